@@ -0,0 +1,150 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	MaxListAmount     = 1000
+	DefaultListAmount = 100
+)
+
+var (
+	ErrInvalidAfter = errors.New("invalid after event ID")
+	ErrInvalidType  = errors.New("invalid event type")
+)
+
+type EventType string
+
+const (
+	EventTypeCommit       EventType = "commit"
+	EventTypeMerge        EventType = "merge"
+	EventTypeCreateBranch EventType = "create_branch"
+	EventTypeDeleteBranch EventType = "delete_branch"
+	EventTypeRenameBranch EventType = "rename_branch"
+	EventTypeRevertBranch EventType = "revert_branch"
+	EventTypeExport       EventType = "export"
+	EventTypePolicy       EventType = "policy"
+)
+
+var eventTypes = map[EventType]struct{}{
+	EventTypeCommit:       {},
+	EventTypeMerge:        {},
+	EventTypeCreateBranch: {},
+	EventTypeDeleteBranch: {},
+	EventTypeRenameBranch: {},
+	EventTypeRevertBranch: {},
+	EventTypeExport:       {},
+	EventTypePolicy:       {},
+}
+
+// Event is an action performed by Actor on a repository
+type Event struct {
+	ID         int64     `db:"id"`
+	Repository string    `db:"repository"`
+	Type       EventType `db:"event_type"`
+	Actor      string    `db:"actor"`
+	// Ref is the branch or commit the event applies to, if any
+	Ref          string    `db:"ref"`
+	Message      string    `db:"message"`
+	CreationDate time.Time `db:"creation_date"`
+}
+
+// ListParams filters and paginates the events listed, empty fields match all events
+type ListParams struct {
+	Types  []EventType
+	Actor  string
+	Ref    string
+	After  string
+	Amount int
+}
+
+type Service interface {
+	// Record adds event to the activity feed of its repository
+	Record(ctx context.Context, event *Event) error
+	// List returns the repository events matching params, newest first
+	List(ctx context.Context, repository string, params ListParams) ([]*Event, bool, error)
+}
+
+type DBService struct {
+	db db.Database
+}
+
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+func NewDBService(db db.Database) *DBService {
+	return &DBService{db: db}
+}
+
+func (s *DBService) Record(ctx context.Context, event *Event) error {
+	if _, ok := eventTypes[event.Type]; !ok {
+		return fmt.Errorf("%s: %w", event.Type, ErrInvalidType)
+	}
+	_, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		return tx.Exec(`INSERT INTO activity_events (repository_id, event_type, actor, ref, message)
+			SELECT id, $2, $3, $4, $5 FROM catalog_repositories WHERE name = $1`,
+			event.Repository, event.Type, event.Actor, event.Ref, event.Message)
+	}, db.WithContext(ctx))
+	return err
+}
+
+func (s *DBService) List(ctx context.Context, repository string, params ListParams) ([]*Event, bool, error) {
+	amount := params.Amount
+	if amount <= 0 || amount > MaxListAmount {
+		amount = DefaultListAmount
+	}
+	q := psql.Select("e.id", "r.name AS repository", "e.event_type", "e.actor", "e.ref", "e.message", "e.creation_date").
+		From("activity_events e").
+		Join("catalog_repositories r ON r.id = e.repository_id").
+		Where(sq.Eq{"r.name": repository}).
+		OrderBy("e.id DESC").
+		Limit(uint64(amount) + 1)
+	if params.After != "" {
+		afterID, err := strconv.ParseInt(params.After, 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", params.After, ErrInvalidAfter)
+		}
+		q = q.Where(sq.Lt{"e.id": afterID})
+	}
+	if len(params.Types) > 0 {
+		for _, t := range params.Types {
+			if _, ok := eventTypes[t]; !ok {
+				return nil, false, fmt.Errorf("%s: %w", t, ErrInvalidType)
+			}
+		}
+		q = q.Where(sq.Eq{"e.event_type": params.Types})
+	}
+	if params.Actor != "" {
+		q = q.Where(sq.Eq{"e.actor": params.Actor})
+	}
+	if params.Ref != "" {
+		q = q.Where(sq.Eq{"e.ref": params.Ref})
+	}
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("build sql: %w", err)
+	}
+	res, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var events []*Event
+		if err := tx.Select(&events, query, args...); err != nil {
+			return nil, err
+		}
+		return events, nil
+	}, db.WithContext(ctx), db.ReadOnly())
+	if err != nil {
+		return nil, false, err
+	}
+	events := res.([]*Event)
+	hasMore := len(events) > amount
+	if hasMore {
+		events = events[:amount]
+	}
+	return events, hasMore, nil
+}
@@ -0,0 +1,178 @@
+package activity_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/treeverse/lakefs/activity"
+	"github.com/treeverse/lakefs/catalog/mvcc"
+	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+var (
+	pool        *dockertest.Pool
+	databaseURI string
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	var closer func()
+	pool, err = dockertest.NewPool("")
+	if err != nil {
+		logging.Default().Fatalf("Could not connect to Docker: %s", err)
+	}
+	databaseURI, closer = testutil.GetDBInstance(pool)
+	code := m.Run()
+	closer() // cleanup
+	os.Exit(code)
+}
+
+func setupService(t *testing.T) *activity.DBService {
+	t.Helper()
+	ctx := context.Background()
+	cdb, _ := testutil.GetDB(t, databaseURI)
+	cataloger := mvcc.NewCataloger(cdb)
+	for _, repo := range []string{"repo1", "repo2"} {
+		_, err := cataloger.CreateRepository(ctx, repo, "s3://"+repo, "master")
+		testutil.MustDo(t, "create repository "+repo, err)
+	}
+	return activity.NewDBService(cdb)
+}
+
+func TestDBService_List(t *testing.T) {
+	ctx := context.Background()
+	s := setupService(t)
+	events := []activity.Event{
+		{Repository: "repo1", Type: activity.EventTypeCreateBranch, Actor: "alice", Ref: "b1"},
+		{Repository: "repo1", Type: activity.EventTypeCommit, Actor: "bob", Ref: "~abc", Message: "add data"},
+		{Repository: "repo2", Type: activity.EventTypeCommit, Actor: "alice", Ref: "~def"},
+		{Repository: "repo1", Type: activity.EventTypeMerge, Actor: "alice", Ref: "master"},
+		{Repository: "repo1", Type: activity.EventTypePolicy, Actor: "bob", Message: "updated retention policy"},
+	}
+	for i := range events {
+		testutil.MustDo(t, "record event", s.Record(ctx, &events[i]))
+	}
+	err := s.Record(ctx, &activity.Event{Repository: "repo1", Type: "unknown", Actor: "alice"})
+	if !errors.Is(err, activity.ErrInvalidType) {
+		t.Fatalf("Record() unknown type err=%v, expected=%v", err, activity.ErrInvalidType)
+	}
+
+	type event struct {
+		Type  activity.EventType
+		Actor string
+	}
+	tests := []struct {
+		name        string
+		repository  string
+		params      activity.ListParams
+		want        []event
+		wantHasMore bool
+		wantErr     error
+	}{
+		{
+			name:       "all",
+			repository: "repo1",
+			want: []event{
+				{Type: activity.EventTypePolicy, Actor: "bob"},
+				{Type: activity.EventTypeMerge, Actor: "alice"},
+				{Type: activity.EventTypeCommit, Actor: "bob"},
+				{Type: activity.EventTypeCreateBranch, Actor: "alice"},
+			},
+		},
+		{
+			name:       "amount",
+			repository: "repo1",
+			params:     activity.ListParams{Amount: 2},
+			want: []event{
+				{Type: activity.EventTypePolicy, Actor: "bob"},
+				{Type: activity.EventTypeMerge, Actor: "alice"},
+			},
+			wantHasMore: true,
+		},
+		{
+			name:       "types",
+			repository: "repo1",
+			params:     activity.ListParams{Types: []activity.EventType{activity.EventTypeCommit, activity.EventTypeMerge}},
+			want: []event{
+				{Type: activity.EventTypeMerge, Actor: "alice"},
+				{Type: activity.EventTypeCommit, Actor: "bob"},
+			},
+		},
+		{
+			name:       "actor",
+			repository: "repo1",
+			params:     activity.ListParams{Actor: "alice"},
+			want: []event{
+				{Type: activity.EventTypeMerge, Actor: "alice"},
+				{Type: activity.EventTypeCreateBranch, Actor: "alice"},
+			},
+		},
+		{
+			name:       "ref",
+			repository: "repo1",
+			params:     activity.ListParams{Ref: "b1"},
+			want: []event{
+				{Type: activity.EventTypeCreateBranch, Actor: "alice"},
+			},
+		},
+		{
+			name:       "no events",
+			repository: "repo3",
+			want:       []event{},
+		},
+		{
+			name:       "invalid after",
+			repository: "repo1",
+			params:     activity.ListParams{After: "last"},
+			wantErr:    activity.ErrInvalidAfter,
+		},
+		{
+			name:       "invalid type",
+			repository: "repo1",
+			params:     activity.ListParams{Types: []activity.EventType{"unknown"}},
+			wantErr:    activity.ErrInvalidType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, hasMore, err := s.List(ctx, tt.repository, tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("List() err=%v, expected=%v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if hasMore != tt.wantHasMore {
+				t.Errorf("List() hasMore=%t, expected=%t", hasMore, tt.wantHasMore)
+			}
+			got := make([]event, len(events))
+			for i, e := range events {
+				got[i] = event{Type: e.Type, Actor: e.Actor}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("List() events=%v, expected=%v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("List() events=%v, expected=%v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	t.Run("after", func(t *testing.T) {
+		first, _, err := s.List(ctx, "repo1", activity.ListParams{Amount: 2})
+		testutil.MustDo(t, "list first page", err)
+		after := strconv.FormatInt(first[len(first)-1].ID, 10)
+		second, hasMore, err := s.List(ctx, "repo1", activity.ListParams{Amount: 2, After: after})
+		testutil.MustDo(t, "list second page", err)
+		if hasMore || len(second) != 2 || second[0].Type != activity.EventTypeCommit || second[1].Type != activity.EventTypeCreateBranch {
+			t.Fatalf("List() second page=%v hasMore=%t, expected commit and create branch", second, hasMore)
+		}
+	})
+}
@@ -3,10 +3,17 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,6 +26,7 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
+	"github.com/treeverse/lakefs/activity"
 	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/api/gen/restapi/operations"
 	authop "github.com/treeverse/lakefs/api/gen/restapi/operations/auth"
@@ -27,8 +35,11 @@ import (
 	configop "github.com/treeverse/lakefs/api/gen/restapi/operations/config"
 	exportop "github.com/treeverse/lakefs/api/gen/restapi/operations/export"
 	hcop "github.com/treeverse/lakefs/api/gen/restapi/operations/health_check"
+	hooksop "github.com/treeverse/lakefs/api/gen/restapi/operations/hooks"
+	jobsop "github.com/treeverse/lakefs/api/gen/restapi/operations/jobs"
 	metadataop "github.com/treeverse/lakefs/api/gen/restapi/operations/metadata"
 	"github.com/treeverse/lakefs/api/gen/restapi/operations/objects"
+	"github.com/treeverse/lakefs/api/gen/restapi/operations/organizations"
 	"github.com/treeverse/lakefs/api/gen/restapi/operations/refs"
 	"github.com/treeverse/lakefs/api/gen/restapi/operations/repositories"
 	retentionop "github.com/treeverse/lakefs/api/gen/restapi/operations/retention"
@@ -37,11 +48,16 @@ import (
 	"github.com/treeverse/lakefs/auth/model"
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/contentmerge"
 	"github.com/treeverse/lakefs/db"
 	"github.com/treeverse/lakefs/dedup"
+	"github.com/treeverse/lakefs/gateway/sig"
+	"github.com/treeverse/lakefs/hooks"
 	"github.com/treeverse/lakefs/httputil"
 	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/notifications"
 	"github.com/treeverse/lakefs/permissions"
+	"github.com/treeverse/lakefs/preview"
 	"github.com/treeverse/lakefs/retention"
 	"github.com/treeverse/lakefs/stats"
 	"github.com/treeverse/lakefs/upload"
@@ -57,35 +73,55 @@ const (
 	UserContextKey        contextKey = "user"
 )
 
+// S3GatewayParams describe how clients reach the S3 gateway served alongside the API
+type S3GatewayParams struct {
+	// Endpoint is the base URL of the gateway
+	Endpoint string
+	Region   string
+}
+
 type Dependencies struct {
-	ctx             context.Context
-	Cataloger       catalog.Cataloger
-	Auth            auth.Service
-	BlockAdapter    block.Adapter
-	Stats           stats.Collector
-	Retention       retention.Service
-	Parade          parade.Parade
-	Dedup           *dedup.Cleaner
-	MetadataManager auth.MetadataManager
-	Migrator        db.Migrator
-	Collector       stats.Collector
-	logger          logging.Logger
+	ctx          context.Context
+	Cataloger    catalog.Cataloger
+	Auth         auth.Service
+	BlockAdapter block.Adapter
+	Stats        stats.Collector
+	Retention    retention.Service
+	Parade       parade.Parade
+	// ExportDestinations writes to export destinations outside the blockstore
+	ExportDestinations *export.Destinations
+	Dedup              *dedup.Cleaner
+	MetadataManager    auth.MetadataManager
+	Migrator           db.Migrator
+	Collector          stats.Collector
+	Activity           activity.Service
+	Notifier           *notifications.Notifier
+	Subscriptions      notifications.SubscriptionService
+	Hooks              *hooks.Service
+	Gateway            S3GatewayParams
+	logger             logging.Logger
 }
 
 func (d *Dependencies) WithContext(ctx context.Context) *Dependencies {
 	return &Dependencies{
-		ctx:             ctx,
-		Cataloger:       d.Cataloger,
-		Auth:            d.Auth,
-		BlockAdapter:    d.BlockAdapter.WithContext(ctx),
-		Stats:           d.Stats,
-		Retention:       d.Retention,
-		Parade:          d.Parade,
-		Dedup:           d.Dedup,
-		MetadataManager: d.MetadataManager,
-		Migrator:        d.Migrator,
-		Collector:       d.Collector,
-		logger:          d.logger.WithContext(ctx),
+		ctx:                ctx,
+		Cataloger:          d.Cataloger,
+		Auth:               d.Auth,
+		BlockAdapter:       d.BlockAdapter.WithContext(ctx),
+		Stats:              d.Stats,
+		Retention:          d.Retention,
+		Parade:             d.Parade,
+		ExportDestinations: d.ExportDestinations,
+		Dedup:              d.Dedup,
+		MetadataManager:    d.MetadataManager,
+		Migrator:           d.Migrator,
+		Collector:          d.Collector,
+		Activity:           d.Activity,
+		Notifier:           d.Notifier,
+		Subscriptions:      d.Subscriptions,
+		Hooks:              d.Hooks,
+		Gateway:            d.Gateway,
+		logger:             d.logger.WithContext(ctx),
 	}
 }
 
@@ -97,25 +133,41 @@ func (d *Dependencies) LogAction(action string) {
 	d.Stats.CollectEvent("api_server", action)
 }
 
+// RecordActivity adds event to the repository activity feed.  The action it describes was
+// already performed, so failing to record it is only logged.
+func (d *Dependencies) RecordActivity(event *activity.Event) {
+	if err := d.Activity.Record(d.ctx, event); err != nil {
+		d.logger.WithError(err).
+			WithFields(logging.Fields{"repository": event.Repository, "event_type": event.Type}).
+			Warn("failed to record activity")
+	}
+}
+
 type Controller struct {
 	deps *Dependencies
 }
 
-func NewController(cataloger catalog.Cataloger, auth auth.Service, blockAdapter block.Adapter, stats stats.Collector, retention retention.Service, parade parade.Parade, dedupCleaner *dedup.Cleaner, metadataManager auth.MetadataManager, migrator db.Migrator, collector stats.Collector, logger logging.Logger) *Controller {
+func NewController(cataloger catalog.Cataloger, auth auth.Service, blockAdapter block.Adapter, stats stats.Collector, retention retention.Service, parade parade.Parade, exportDestinations *export.Destinations, dedupCleaner *dedup.Cleaner, metadataManager auth.MetadataManager, migrator db.Migrator, collector stats.Collector, activityService activity.Service, notifier *notifications.Notifier, subscriptions notifications.SubscriptionService, hooksService *hooks.Service, gateway S3GatewayParams, logger logging.Logger) *Controller {
 	c := &Controller{
 		deps: &Dependencies{
-			ctx:             context.Background(),
-			Cataloger:       cataloger,
-			Auth:            auth,
-			BlockAdapter:    blockAdapter,
-			Stats:           stats,
-			Retention:       retention,
-			Parade:          parade,
-			Dedup:           dedupCleaner,
-			MetadataManager: metadataManager,
-			Migrator:        migrator,
-			Collector:       collector,
-			logger:          logger,
+			ctx:                context.Background(),
+			Cataloger:          cataloger,
+			Auth:               auth,
+			BlockAdapter:       blockAdapter,
+			Stats:              stats,
+			Retention:          retention,
+			Parade:             parade,
+			ExportDestinations: exportDestinations,
+			Dedup:              dedupCleaner,
+			MetadataManager:    metadataManager,
+			Migrator:           migrator,
+			Collector:          collector,
+			Activity:           activityService,
+			Notifier:           notifier,
+			Subscriptions:      subscriptions,
+			Hooks:              hooksService,
+			Gateway:            gateway,
+			logger:             logger,
 		},
 	}
 	return c
@@ -156,6 +208,9 @@ func (c *Controller) Configure(api *operations.LakefsAPI) {
 	api.AuthCreateCredentialsHandler = c.CreateCredentialsHandler()
 	api.AuthDeleteCredentialsHandler = c.DeleteCredentialsHandler()
 	api.AuthGetCredentialsHandler = c.GetCredentialsHandler()
+	api.AuthListUserSubscriptionsHandler = c.ListUserSubscriptionsHandler()
+	api.AuthCreateUserSubscriptionHandler = c.CreateUserSubscriptionHandler()
+	api.AuthDeleteUserSubscriptionHandler = c.DeleteUserSubscriptionHandler()
 	api.AuthListUserGroupsHandler = c.ListUserGroupsHandler()
 	api.AuthListUserPoliciesHandler = c.ListUserPoliciesHandler()
 	api.AuthAttachPolicyToUserHandler = c.AttachPolicyToUserHandler()
@@ -168,27 +223,72 @@ func (c *Controller) Configure(api *operations.LakefsAPI) {
 	api.RepositoriesGetRepositoryHandler = c.GetRepoHandler()
 	api.RepositoriesCreateRepositoryHandler = c.CreateRepositoryHandler()
 	api.RepositoriesDeleteRepositoryHandler = c.DeleteRepositoryHandler()
+	api.RepositoriesGetRepositoryQuotaHandler = c.GetRepositoryQuotaHandler()
+	api.RepositoriesSetRepositoryQuotaHandler = c.SetRepositoryQuotaHandler()
+	api.RepositoriesSetDefaultBranchHandler = c.SetDefaultBranchHandler()
+	api.RepositoriesSetRepositoryReadOnlyHandler = c.SetRepositoryReadOnlyHandler()
+	api.RepositoriesArchiveRepositoryHandler = c.ArchiveRepositoryHandler()
+	api.RepositoriesUnarchiveRepositoryHandler = c.UnarchiveRepositoryHandler()
+	api.RepositoriesGetRepositoryUsageHandler = c.GetRepositoryUsageHandler()
+	api.OrganizationsGetOrganizationQuotaHandler = c.GetOrganizationQuotaHandler()
+	api.OrganizationsSetOrganizationQuotaHandler = c.SetOrganizationQuotaHandler()
+	api.OrganizationsDeleteOrganizationQuotaHandler = c.DeleteOrganizationQuotaHandler()
+	api.OrganizationsGetOrganizationUsageHandler = c.GetOrganizationUsageHandler()
+	api.RepositoriesGetRepositoryCommitLimitsHandler = c.GetRepositoryCommitLimitsHandler()
+	api.RepositoriesSetRepositoryCommitLimitsHandler = c.SetRepositoryCommitLimitsHandler()
+	api.RepositoriesGetMetadataSchemaHandler = c.GetMetadataSchemaHandler()
+	api.RepositoriesSetMetadataSchemaHandler = c.SetMetadataSchemaHandler()
+	api.RepositoriesSearchRepositoryHandler = c.SearchRepositoryHandler()
+	api.RepositoriesListRepositoryActivityHandler = c.ListRepositoryActivityHandler()
+
+	api.HooksListHookRunsHandler = c.ListHookRunsHandler()
+	api.HooksGetHookRunHandler = c.GetHookRunHandler()
+	api.HooksRetryHookRunHandler = c.RetryHookRunHandler()
+	api.HooksDryRunHooksHandler = c.DryRunHooksHandler()
 
 	api.BranchesListBranchesHandler = c.ListBranchesHandler()
 	api.BranchesGetBranchHandler = c.GetBranchHandler()
 	api.BranchesCreateBranchHandler = c.CreateBranchHandler()
 	api.BranchesDeleteBranchHandler = c.DeleteBranchHandler()
+	api.BranchesRenameBranchHandler = c.RenameBranchHandler()
 	api.BranchesRevertBranchHandler = c.RevertBranchHandler()
 
 	api.CommitsCommitHandler = c.CommitHandler()
+	api.CommitsAmendCommitHandler = c.AmendCommitHandler()
 	api.CommitsGetCommitHandler = c.GetCommitHandler()
+	api.CommitsGetCommitGraphHandler = c.GetCommitGraphHandler()
 	api.CommitsGetBranchCommitLogHandler = c.CommitsGetBranchCommitLogHandler()
+	api.CommitsGetBranchChangesHandler = c.CommitsGetBranchChangesHandler()
+	api.CommitsCreateDataLineageHandler = c.CreateDataLineageHandler()
+	api.CommitsWalkDataLineageHandler = c.WalkDataLineageHandler()
+	api.JobsCreateCommitJobHandler = c.CreateCommitJobHandler()
+	api.JobsListJobsHandler = c.ListJobsHandler()
+	api.JobsGetJobHandler = c.GetJobHandler()
+	api.JobsResumeJobHandler = c.ResumeJobHandler()
+	api.JobsAbortJobHandler = c.AbortJobHandler()
 
 	api.RefsDiffRefsHandler = c.RefsDiffRefsHandler()
+	api.RefsDiffRefsSummaryHandler = c.RefsDiffRefsSummaryHandler()
+	api.RefsDiffRefsChecksumsHandler = c.RefsDiffRefsChecksumsHandler()
 	api.BranchesDiffBranchHandler = c.BranchesDiffBranchHandler()
 	api.RefsMergeIntoBranchHandler = c.MergeMergeIntoBranchHandler()
+	api.RefsMergePreviewHandler = c.RefsMergePreviewHandler()
 
 	api.ObjectsStatObjectHandler = c.ObjectsStatObjectHandler()
+	api.ObjectsPresignObjectHandler = c.ObjectsPresignObjectHandler()
+	api.ObjectsStatObjectsHandler = c.ObjectsStatObjectsHandler()
 	api.ObjectsGetUnderlyingPropertiesHandler = c.ObjectsGetUnderlyingPropertiesHandler()
 	api.ObjectsListObjectsHandler = c.ObjectsListObjectsHandler()
+	api.ObjectsGetObjectHistoryHandler = c.ObjectsGetObjectHistoryHandler()
+	api.ObjectsGetPrefixStatsHandler = c.ObjectsGetPrefixStatsHandler()
 	api.ObjectsGetObjectHandler = c.ObjectsGetObjectHandler()
+	api.ObjectsPreviewObjectHandler = c.ObjectsPreviewObjectHandler()
+	api.ObjectsGetObjectSchemaHandler = c.ObjectsGetObjectSchemaHandler()
 	api.ObjectsUploadObjectHandler = c.ObjectsUploadObjectHandler()
 	api.ObjectsDeleteObjectHandler = c.ObjectsDeleteObjectHandler()
+	api.ObjectsDeleteObjectsHandler = c.ObjectsDeleteObjectsHandler()
+	api.ObjectsStageObjectsHandler = c.ObjectsStageObjectsHandler()
+	api.ObjectsCopyObjectHandler = c.ObjectsCopyObjectHandler()
 
 	api.RetentionGetRetentionPolicyHandler = c.RetentionGetRetentionPolicyHandler()
 	api.RetentionUpdateRetentionPolicyHandler = c.RetentionUpdateRetentionPolicyHandler()
@@ -197,19 +297,47 @@ func (c *Controller) Configure(api *operations.LakefsAPI) {
 
 	api.ExportGetContinuousExportHandler = c.ExportGetContinuousExportHandler()
 	api.ExportSetContinuousExportHandler = c.ExportSetContinuousExportHandler()
+	api.ExportListContinuousExportsHandler = c.ExportListContinuousExportsHandler()
+	api.ExportListExportRunsHandler = c.ExportListExportRunsHandler()
 	api.ExportRunHandler = c.ExportRunHandler()
+	api.ExportExportRefHandler = c.ExportExportRefHandler()
+	api.ExportGetRefExportHandler = c.ExportGetRefExportHandler()
 	api.ExportRepairHandler = c.ExportRepairHandler()
+	api.ExportGetExportPlanHandler = c.ExportGetExportPlanHandler()
+	api.ExportGetExportDriftHandler = c.ExportGetExportDriftHandler()
+	api.ExportReconcileExportDriftHandler = c.ExportReconcileExportDriftHandler()
 	api.ConfigGetConfigHandler = c.ConfigGetConfigHandler()
+	api.ConfigGetLoggingConfigHandler = c.ConfigGetLoggingConfigHandler()
+	api.ConfigSetLoggingConfigHandler = c.ConfigSetLoggingConfigHandler()
 }
 
 func (c *Controller) setupRequest(user *models.User, r *http.Request, permissions []permissions.Permission) (*Dependencies, error) {
+	// requests authenticated by a token carry no access key
+	accessKeyID, _, _ := r.BasicAuth()
+	return c.setupContext(r.Context(), user, accessKeyID, permissions)
+}
+
+// setupContext returns the dependencies of a request of user, authenticated with
+// accessKeyID, with context ctx, authorized for permissions
+func (c *Controller) setupContext(ctx context.Context, user *models.User, accessKeyID string, permissions []permissions.Permission) (*Dependencies, error) {
 	// add user to context
-	ctx := logging.AddFields(r.Context(), logging.Fields{"user": user.ID})
+	ctx = logging.AddFields(ctx, logging.Fields{"user": user.ID})
 	ctx = context.WithValue(ctx, UserContextKey, user)
+	ctx = catalog.WithCommitter(ctx, catalog.Committer{Username: user.ID, AccessKeyID: accessKeyID})
 	deps := c.deps.WithContext(ctx)
 	return deps, authorize(deps.Auth, user, permissions)
 }
 
+// committerContext returns ctx attributing commits to the committer authenticated for the
+// request of deps
+func committerContext(ctx context.Context, deps *Dependencies) context.Context {
+	committer, ok := catalog.CommitterFromContext(deps.ctx)
+	if !ok {
+		return ctx
+	}
+	return catalog.WithCommitter(ctx, committer)
+}
+
 func createPaginator(nextToken string, amountResults int) *models.Pagination {
 	return &models.Pagination{
 		HasMore:    swag.Bool(nextToken != ""),
@@ -309,7 +437,9 @@ func (c *Controller) ListRepositoriesHandler() repositories.ListRepositoriesHand
 
 		after, amount := getPaginationParams(params.After, params.Amount)
 
-		repos, hasMore, err := deps.Cataloger.ListRepositories(c.Context(), amount, after)
+		repos, hasMore, err := deps.Cataloger.ListRepositories(c.Context(), amount, after, catalog.ListRepositoriesParams{
+			IncludeArchived: swag.BoolValue(params.IncludeArchived),
+		})
 		if err != nil {
 			return repositories.NewListRepositoriesDefault(http.StatusInternalServerError).
 				WithPayload(responseError("error listing repositories: %s", err))
@@ -323,6 +453,8 @@ func (c *Controller) ListRepositoriesHandler() repositories.ListRepositoriesHand
 				CreationDate:     repo.CreationDate.Unix(),
 				DefaultBranch:    repo.DefaultBranch,
 				ID:               repo.Name,
+				ReadOnly:         repo.ReadOnly,
+				Archived:         repo.Archived,
 			}
 			lastID = repo.Name
 		}
@@ -385,304 +517,1842 @@ func (c *Controller) GetRepoHandler() repositories.GetRepositoryHandler {
 				CreationDate:     repo.CreationDate.Unix(),
 				DefaultBranch:    repo.DefaultBranch,
 				ID:               repo.Name,
+				ReadOnly:         repo.ReadOnly,
+				Archived:         repo.Archived,
 			})
 	})
 }
 
-func (c *Controller) GetCommitHandler() commits.GetCommitHandler {
-	return commits.GetCommitHandlerFunc(func(params commits.GetCommitParams, user *models.User) middleware.Responder {
+func (c *Controller) GetRepositoryQuotaHandler() repositories.GetRepositoryQuotaHandler {
+	return repositories.GetRepositoryQuotaHandlerFunc(func(params repositories.GetRepositoryQuotaParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
 			{
-				Action:   permissions.ReadCommitAction,
+				Action:   permissions.ReadRepositoryAction,
 				Resource: permissions.RepoArn(params.Repository),
 			},
 		})
 		if err != nil {
-			return commits.NewGetCommitUnauthorized().WithPayload(responseErrorFrom(err))
+			return repositories.NewGetRepositoryQuotaUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-		deps.LogAction("get_commit")
-		commit, err := deps.Cataloger.GetCommit(c.Context(), params.Repository, params.CommitID)
+		deps.LogAction("get_repo_quota")
+		quota, err := deps.Cataloger.GetRepositoryQuota(c.Context(), params.Repository)
 		if errors.Is(err, db.ErrNotFound) {
-			return commits.NewGetCommitNotFound().WithPayload(responseError("commit not found"))
+			return repositories.NewGetRepositoryQuotaNotFound().
+				WithPayload(responseError("repository not found"))
 		}
 		if err != nil {
-			return commits.NewGetCommitDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+			return repositories.NewGetRepositoryQuotaDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
 		}
-		return commits.NewGetCommitOK().WithPayload(&models.Commit{
-			Committer:    commit.Committer,
-			CreationDate: commit.CreationDate.Unix(),
-			ID:           params.CommitID,
-			Message:      commit.Message,
-			Metadata:     commit.Metadata,
-			Parents:      commit.Parents,
-		})
+		return repositories.NewGetRepositoryQuotaOK().
+			WithPayload(&models.RepositoryQuota{
+				MaxStorageBytes:         swag.Int64(quota.MaxStorageBytes),
+				MaxObjects:              swag.Int64(quota.MaxObjects),
+				MaxRequestsPerSecond:    swag.Int64(quota.MaxRequestsPerSecond),
+				MaxExportBytesPerSecond: swag.Int64(quota.MaxExportBytesPerSecond),
+				Organization:            quota.Organization,
+			})
 	})
 }
 
-func (c *Controller) CommitHandler() commits.CommitHandler {
-	return commits.CommitHandlerFunc(func(params commits.CommitParams, user *models.User) middleware.Responder {
-		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+func (c *Controller) SetRepositoryQuotaHandler() repositories.SetRepositoryQuotaHandler {
+	return repositories.SetRepositoryQuotaHandlerFunc(func(params repositories.SetRepositoryQuotaParams, user *models.User) middleware.Responder {
+		perms := []permissions.Permission{
 			{
-				Action:   permissions.CreateCommitAction,
-				Resource: permissions.BranchArn(params.Repository, params.Branch),
+				Action:   permissions.SetRepositoryQuotaAction,
+				Resource: permissions.RepoArn(params.Repository),
 			},
-		})
-		if err != nil {
-			return commits.NewCommitUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-		deps.LogAction("create_commit")
-		userModel, err := c.deps.Auth.GetUser(user.ID)
+		if params.Quota.Organization != "" {
+			// the repository shares the quota of the organization
+			perms = append(perms, permissions.Permission{
+				Action:   permissions.SetOrganizationQuotaAction,
+				Resource: permissions.OrganizationArn(params.Quota.Organization),
+			})
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, perms)
 		if err != nil {
-			return commits.NewCommitUnauthorized().WithPayload(responseErrorFrom(err))
+			return repositories.NewSetRepositoryQuotaUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("set_repo_quota")
+		err = deps.Cataloger.SetRepositoryQuota(c.Context(), params.Repository, &catalog.RepositoryQuota{
+			MaxStorageBytes:         swag.Int64Value(params.Quota.MaxStorageBytes),
+			MaxObjects:              swag.Int64Value(params.Quota.MaxObjects),
+			MaxRequestsPerSecond:    swag.Int64Value(params.Quota.MaxRequestsPerSecond),
+			MaxExportBytesPerSecond: swag.Int64Value(params.Quota.MaxExportBytesPerSecond),
+			Organization:            params.Quota.Organization,
+		})
+		if errors.Is(err, catalog.ErrOrganizationNotFound) {
+			return repositories.NewSetRepositoryQuotaBadRequest().
+				WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewSetRepositoryQuotaNotFound().
+				WithPayload(responseError("repository not found"))
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return repositories.NewSetRepositoryQuotaBadRequest().
+				WithPayload(responseErrorFrom(err))
 		}
-		committer := userModel.Username
-		commitMessage := swag.StringValue(params.Commit.Message)
-		commit, err := deps.Cataloger.Commit(c.Context(), params.Repository,
-			params.Branch, commitMessage, committer, params.Commit.Metadata)
 		if err != nil {
-			return commits.NewCommitDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+			return repositories.NewSetRepositoryQuotaDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
 		}
-		return commits.NewCommitCreated().WithPayload(&models.Commit{
-			Committer:    commit.Committer,
-			CreationDate: commit.CreationDate.Unix(),
-			ID:           commit.Reference,
-			Message:      commit.Message,
-			Metadata:     commit.Metadata,
-			Parents:      commit.Parents,
-		})
+		return repositories.NewSetRepositoryQuotaNoContent()
 	})
 }
 
-func (c *Controller) CommitsGetBranchCommitLogHandler() commits.GetBranchCommitLogHandler {
-	return commits.GetBranchCommitLogHandlerFunc(func(params commits.GetBranchCommitLogParams, user *models.User) middleware.Responder {
+func (c *Controller) SetDefaultBranchHandler() repositories.SetDefaultBranchHandler {
+	return repositories.SetDefaultBranchHandlerFunc(func(params repositories.SetDefaultBranchParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
 			{
-				Action:   permissions.ReadBranchAction,
-				Resource: permissions.BranchArn(params.Repository, params.Branch),
+				Action:   permissions.SetDefaultBranchAction,
+				Resource: permissions.RepoArn(params.Repository),
 			},
 		})
 		if err != nil {
-			return commits.NewGetBranchCommitLogUnauthorized().WithPayload(responseErrorFrom(err))
+			return repositories.NewSetDefaultBranchUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-		deps.LogAction("get_branch_commit_log")
-		cataloger := deps.Cataloger
-
-		after, amount := getPaginationParams(params.After, params.Amount)
-		// get commit log
-		commitLog, hasMore, err := cataloger.ListCommits(c.Context(), params.Repository, params.Branch, after, amount)
-		switch {
-		case errors.Is(err, catalog.ErrBranchNotFound):
-			return commits.NewGetBranchCommitLogNotFound().WithPayload(responseError("branch '%s' not found.", params.Branch))
-		case errors.Is(err, catalog.ErrRepositoryNotFound):
-			return commits.NewGetBranchCommitLogNotFound().WithPayload(responseError("repository '%s' not found.", params.Repository))
-		case err != nil:
-			return commits.NewGetBranchCommitLogDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		deps.LogAction("set_default_branch")
+		err = deps.Cataloger.SetDefaultBranch(c.Context(), params.Repository, swag.StringValue(params.Branch.Name))
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewSetDefaultBranchNotFound().
+				WithPayload(responseError("repository or branch not found"))
 		}
-
-		serializedCommits := make([]*models.Commit, len(commitLog))
-		lastID := ""
-		for i, commit := range commitLog {
-			serializedCommits[i] = &models.Commit{
-				Committer:    commit.Committer,
-				CreationDate: commit.CreationDate.Unix(),
-				ID:           commit.Reference,
-				Message:      commit.Message,
-				Metadata:     commit.Metadata,
-				Parents:      commit.Parents,
-			}
-			lastID = commit.Reference
+		if errors.Is(err, catalog.ErrInvalidValue) || errors.Is(err, catalog.ErrOperationNotPermitted) {
+			return repositories.NewSetDefaultBranchBadRequest().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return repositories.NewSetDefaultBranchDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
 		}
+		return repositories.NewSetDefaultBranchNoContent()
+	})
+}
 
-		returnValue := commits.NewGetBranchCommitLogOK().WithPayload(&commits.GetBranchCommitLogOKBody{
-			Pagination: &models.Pagination{
-				HasMore:    swag.Bool(hasMore),
-				Results:    swag.Int64(int64(len(serializedCommits))),
-				MaxPerPage: swag.Int64(MaxResultsPerPage),
+func (c *Controller) SetRepositoryReadOnlyHandler() repositories.SetRepositoryReadOnlyHandler {
+	return repositories.SetRepositoryReadOnlyHandlerFunc(func(params repositories.SetRepositoryReadOnlyParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.SetReadOnlyAction,
+				Resource: permissions.RepoArn(params.Repository),
 			},
-			Results: serializedCommits,
 		})
-		if hasMore {
-			returnValue.Payload.Pagination.NextOffset = lastID
+		if err != nil {
+			return repositories.NewSetRepositoryReadOnlyUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-		return returnValue
+		deps.LogAction("set_repo_read_only")
+		err = deps.Cataloger.SetRepositoryReadOnly(c.Context(), params.Repository, swag.BoolValue(params.ReadOnly.ReadOnly))
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewSetRepositoryReadOnlyNotFound().
+				WithPayload(responseError("repository not found"))
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return repositories.NewSetRepositoryReadOnlyBadRequest().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return repositories.NewSetRepositoryReadOnlyDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return repositories.NewSetRepositoryReadOnlyNoContent()
 	})
 }
 
-func ensureStorageNamespaceRW(adapter block.Adapter, storageNamespace string) error {
-	const (
-		dummyKey  = "dummy"
-		dummyData = "this is dummy data - created by lakeFS in order to check accessibility "
-	)
-
-	err := adapter.Put(block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: dummyKey}, int64(len(dummyData)), bytes.NewReader([]byte(dummyData)), block.PutOpts{})
-	if err != nil {
-		return err
+// archiveParams returns the params archiving or restoring a repository, transitioning its
+// objects when a storage class is set
+func archiveParams(deps *Dependencies, archive *models.RepositoryArchive) catalog.ArchiveRepositoryParams {
+	if archive == nil || archive.StorageClass == "" {
+		return catalog.ArchiveRepositoryParams{}
 	}
-
-	_, err = adapter.Get(block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: dummyKey}, int64(len(dummyData)))
-	if err != nil {
-		return err
+	return catalog.ArchiveRepositoryParams{
+		TransitionObject: func(namespace, address string) error {
+			return upload.TransitionBlob(deps.BlockAdapter, namespace, address, archive.StorageClass)
+		},
 	}
-
-	return nil
 }
 
-func (c *Controller) CreateRepositoryHandler() repositories.CreateRepositoryHandler {
-	return repositories.CreateRepositoryHandlerFunc(func(params repositories.CreateRepositoryParams, user *models.User) middleware.Responder {
+func (c *Controller) ArchiveRepositoryHandler() repositories.ArchiveRepositoryHandler {
+	return repositories.ArchiveRepositoryHandlerFunc(func(params repositories.ArchiveRepositoryParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
 			{
-				Action:   permissions.CreateRepositoryAction,
-				Resource: permissions.RepoArn(swag.StringValue(params.Repository.Name)),
+				Action:   permissions.ArchiveRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
 			},
 		})
 		if err != nil {
-			return repositories.NewCreateRepositoryUnauthorized().WithPayload(responseErrorFrom(err))
+			return repositories.NewArchiveRepositoryUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-		deps.LogAction("create_repo")
-
-		err = ensureStorageNamespaceRW(deps.BlockAdapter, swag.StringValue(params.Repository.StorageNamespace))
-		if err != nil {
-			c.deps.logger.
-				WithError(err).
-				WithField("storage_namespace", swag.StringValue(params.Repository.StorageNamespace)).
-				Warn("Could not access storage namespace")
-			return repositories.NewCreateRepositoryBadRequest().
-				WithPayload(responseError("error creating repository: could not access storage namespace"))
+		deps.LogAction("archive_repo")
+		err = deps.Cataloger.ArchiveRepository(c.Context(), params.Repository, archiveParams(deps, params.Archive))
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewArchiveRepositoryNotFound().
+				WithPayload(responseError("repository not found"))
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return repositories.NewArchiveRepositoryBadRequest().
+				WithPayload(responseErrorFrom(err))
 		}
-		repo, err := deps.Cataloger.CreateRepository(c.Context(),
-			swag.StringValue(params.Repository.Name),
-			swag.StringValue(params.Repository.StorageNamespace),
-			params.Repository.DefaultBranch)
 		if err != nil {
-			return repositories.NewGetRepositoryDefault(http.StatusInternalServerError).
-				WithPayload(responseError(fmt.Sprintf("error creating repository: %s", err)))
+			return repositories.NewArchiveRepositoryDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
 		}
-
-		return repositories.NewCreateRepositoryCreated().WithPayload(&models.Repository{
-			StorageNamespace: repo.StorageNamespace,
-			CreationDate:     repo.CreationDate.Unix(),
-			DefaultBranch:    repo.DefaultBranch,
-			ID:               repo.Name,
-		})
+		return repositories.NewArchiveRepositoryNoContent()
 	})
 }
 
-func (c *Controller) DeleteRepositoryHandler() repositories.DeleteRepositoryHandler {
-	return repositories.DeleteRepositoryHandlerFunc(func(params repositories.DeleteRepositoryParams, user *models.User) middleware.Responder {
+func (c *Controller) UnarchiveRepositoryHandler() repositories.UnarchiveRepositoryHandler {
+	return repositories.UnarchiveRepositoryHandlerFunc(func(params repositories.UnarchiveRepositoryParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
 			{
-				Action:   permissions.DeleteRepositoryAction,
+				Action:   permissions.ArchiveRepositoryAction,
 				Resource: permissions.RepoArn(params.Repository),
 			},
 		})
 		if err != nil {
-			return repositories.NewDeleteRepositoryUnauthorized().WithPayload(responseErrorFrom(err))
+			return repositories.NewUnarchiveRepositoryUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-		deps.LogAction("delete_repo")
-		err = deps.Cataloger.DeleteRepository(c.Context(), params.Repository)
+		deps.LogAction("unarchive_repo")
+		err = deps.Cataloger.UnarchiveRepository(c.Context(), params.Repository, archiveParams(deps, params.Archive))
 		if errors.Is(err, db.ErrNotFound) {
-			return repositories.NewDeleteRepositoryNotFound().WithPayload(responseError("repository not found"))
+			return repositories.NewUnarchiveRepositoryNotFound().
+				WithPayload(responseError("repository not found"))
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return repositories.NewUnarchiveRepositoryBadRequest().
+				WithPayload(responseErrorFrom(err))
 		}
 		if err != nil {
-			return repositories.NewDeleteRepositoryDefault(http.StatusInternalServerError).
-				WithPayload(responseError("error deleting repository"))
+			return repositories.NewUnarchiveRepositoryDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
 		}
-		return repositories.NewDeleteRepositoryNoContent()
+		return repositories.NewUnarchiveRepositoryNoContent()
 	})
 }
 
-func (c *Controller) ListBranchesHandler() branches.ListBranchesHandler {
-	return branches.ListBranchesHandlerFunc(func(params branches.ListBranchesParams, user *models.User) middleware.Responder {
+func (c *Controller) GetRepositoryCommitLimitsHandler() repositories.GetRepositoryCommitLimitsHandler {
+	return repositories.GetRepositoryCommitLimitsHandlerFunc(func(params repositories.GetRepositoryCommitLimitsParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
 			{
-				Action:   permissions.ListBranchesAction,
+				Action:   permissions.ReadRepositoryAction,
 				Resource: permissions.RepoArn(params.Repository),
 			},
 		})
 		if err != nil {
-			return branches.NewListBranchesUnauthorized().WithPayload(responseErrorFrom(err))
-		}
-		deps.LogAction("list_branches")
-		cataloger := deps.Cataloger
-
-		after, amount := getPaginationParams(params.After, params.Amount)
-
-		res, hasMore, err := cataloger.ListBranches(c.Context(), params.Repository, "", amount, after)
-		if err != nil {
-			return branches.NewListBranchesDefault(http.StatusInternalServerError).
-				WithPayload(responseError("could not list branches: %s", err))
+			return repositories.NewGetRepositoryCommitLimitsUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-
-		branchList := make([]string, len(res))
-		var lastID string
-		for i, branch := range res {
-			branchList[i] = branch.Name
-			lastID = branch.Name
+		deps.LogAction("get_repo_commit_limits")
+		limits, err := deps.Cataloger.GetCommitLimits(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewGetRepositoryCommitLimitsNotFound().
+				WithPayload(responseError("repository not found"))
 		}
-		returnValue := branches.NewListBranchesOK().WithPayload(&branches.ListBranchesOKBody{
-			Pagination: &models.Pagination{
-				HasMore:    swag.Bool(hasMore),
-				Results:    swag.Int64(int64(len(branchList))),
-				MaxPerPage: swag.Int64(MaxResultsPerPage),
-			},
-			Results: branchList,
-		})
-
-		if hasMore {
-			returnValue.Payload.Pagination.NextOffset = lastID
+		if err != nil {
+			return repositories.NewGetRepositoryCommitLimitsDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
 		}
-
-		return returnValue
+		return repositories.NewGetRepositoryCommitLimitsOK().
+			WithPayload(&models.RepositoryCommitLimits{
+				MaxChangedObjects: swag.Int64(limits.MaxChangedObjects),
+				MaxAddedBytes:     swag.Int64(limits.MaxAddedBytes),
+			})
 	})
 }
 
-func (c *Controller) GetBranchHandler() branches.GetBranchHandler {
-	return branches.GetBranchHandlerFunc(func(params branches.GetBranchParams, user *models.User) middleware.Responder {
+func (c *Controller) SetRepositoryCommitLimitsHandler() repositories.SetRepositoryCommitLimitsHandler {
+	return repositories.SetRepositoryCommitLimitsHandlerFunc(func(params repositories.SetRepositoryCommitLimitsParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
 			{
-				Action:   permissions.ReadBranchAction,
-				Resource: permissions.BranchArn(params.Repository, params.Branch),
+				Action:   permissions.SetCommitLimitsAction,
+				Resource: permissions.RepoArn(params.Repository),
 			},
 		})
 		if err != nil {
-			return branches.NewGetBranchUnauthorized().WithPayload(responseErrorFrom(err))
+			return repositories.NewSetRepositoryCommitLimitsUnauthorized().WithPayload(responseErrorFrom(err))
 		}
-		deps.LogAction("get_branch")
-		reference, err := deps.Cataloger.GetBranchReference(c.Context(), params.Repository, params.Branch)
-
-		switch {
-		case errors.Is(err, catalog.ErrBranchNotFound):
-			return branches.NewGetBranchNotFound().WithPayload(responseError("branch '%s' not found.", params.Branch))
-		case errors.Is(err, catalog.ErrRepositoryNotFound):
-			return branches.NewGetBranchNotFound().WithPayload(responseError("repository '%s' not found.", params.Repository))
-		case err != nil:
-			return branches.NewGetBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		deps.LogAction("set_repo_commit_limits")
+		err = deps.Cataloger.SetCommitLimits(c.Context(), params.Repository, &catalog.CommitLimits{
+			MaxChangedObjects: swag.Int64Value(params.Limits.MaxChangedObjects),
+			MaxAddedBytes:     swag.Int64Value(params.Limits.MaxAddedBytes),
+		})
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewSetRepositoryCommitLimitsNotFound().
+				WithPayload(responseError("repository not found"))
 		}
-
-		return branches.NewGetBranchOK().WithPayload(reference)
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return repositories.NewSetRepositoryCommitLimitsBadRequest().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return repositories.NewSetRepositoryCommitLimitsDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return repositories.NewSetRepositoryCommitLimitsNoContent()
 	})
 }
 
-func (c *Controller) CreateBranchHandler() branches.CreateBranchHandler {
-	return branches.CreateBranchHandlerFunc(func(params branches.CreateBranchParams, user *models.User) middleware.Responder {
-		repository := params.Repository
-		branch := swag.StringValue(params.Branch.Name)
+func (c *Controller) GetMetadataSchemaHandler() repositories.GetMetadataSchemaHandler {
+	return repositories.GetMetadataSchemaHandlerFunc(func(params repositories.GetMetadataSchemaParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
 			{
-				Action:   permissions.CreateBranchAction,
-				Resource: permissions.BranchArn(repository, branch),
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
 			},
 		})
 		if err != nil {
-			return branches.NewCreateBranchUnauthorized().WithPayload(responseErrorFrom(err))
+			return repositories.NewGetMetadataSchemaUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_metadata_schema")
+		schema, err := deps.Cataloger.GetMetadataSchema(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewGetMetadataSchemaNotFound().
+				WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return repositories.NewGetMetadataSchemaDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		rules := make([]*models.MetadataSchemaRule, len(schema.Rules))
+		for i, rule := range schema.Rules {
+			rules[i] = &models.MetadataSchemaRule{
+				Prefix:   swag.String(rule.Prefix),
+				Required: rule.Required,
+				Allowed:  rule.Allowed,
+				Values:   rule.Values,
+			}
+		}
+		return repositories.NewGetMetadataSchemaOK().WithPayload(&models.MetadataSchema{Rules: rules})
+	})
+}
+
+func (c *Controller) SetMetadataSchemaHandler() repositories.SetMetadataSchemaHandler {
+	return repositories.SetMetadataSchemaHandlerFunc(func(params repositories.SetMetadataSchemaParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.SetMetadataSchemaAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewSetMetadataSchemaUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("set_metadata_schema")
+		schema := &catalog.MetadataSchema{
+			Rules: make([]catalog.MetadataRule, len(params.Schema.Rules)),
+		}
+		for i, rule := range params.Schema.Rules {
+			schema.Rules[i] = catalog.MetadataRule{
+				Prefix:   swag.StringValue(rule.Prefix),
+				Required: rule.Required,
+				Allowed:  rule.Allowed,
+				Values:   rule.Values,
+			}
+		}
+		err = deps.Cataloger.SetMetadataSchema(c.Context(), params.Repository, schema)
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewSetMetadataSchemaNotFound().
+				WithPayload(responseError("repository not found"))
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return repositories.NewSetMetadataSchemaBadRequest().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return repositories.NewSetMetadataSchemaDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return repositories.NewSetMetadataSchemaNoContent()
+	})
+}
+
+func (c *Controller) GetRepositoryUsageHandler() repositories.GetRepositoryUsageHandler {
+	return repositories.GetRepositoryUsageHandlerFunc(func(params repositories.GetRepositoryUsageParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewGetRepositoryUsageUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_repo_usage")
+		usage, err := deps.Cataloger.GetRepositoryUsage(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewGetRepositoryUsageNotFound().
+				WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return repositories.NewGetRepositoryUsageDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return repositories.NewGetRepositoryUsageOK().
+			WithPayload(&models.RepositoryUsage{
+				StorageBytes: usage.StorageBytes,
+				Objects:      usage.Objects,
+			})
+	})
+}
+
+func (c *Controller) GetOrganizationQuotaHandler() organizations.GetOrganizationQuotaHandler {
+	return organizations.GetOrganizationQuotaHandlerFunc(func(params organizations.GetOrganizationQuotaParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadOrganizationAction,
+				Resource: permissions.OrganizationArn(params.Organization),
+			},
+		})
+		if err != nil {
+			return organizations.NewGetOrganizationQuotaUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_organization_quota")
+		quota, err := deps.Cataloger.GetOrganizationQuota(c.Context(), params.Organization)
+		if errors.Is(err, db.ErrNotFound) {
+			return organizations.NewGetOrganizationQuotaNotFound().
+				WithPayload(responseError("organization not found"))
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return organizations.NewGetOrganizationQuotaDefault(http.StatusBadRequest).
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return organizations.NewGetOrganizationQuotaDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return organizations.NewGetOrganizationQuotaOK().
+			WithPayload(&models.OrganizationQuota{
+				MaxStorageBytes:         swag.Int64(quota.MaxStorageBytes),
+				MaxObjects:              swag.Int64(quota.MaxObjects),
+				MaxRequestsPerSecond:    swag.Int64(quota.MaxRequestsPerSecond),
+				MaxExportBytesPerSecond: swag.Int64(quota.MaxExportBytesPerSecond),
+			})
+	})
+}
+
+func (c *Controller) SetOrganizationQuotaHandler() organizations.SetOrganizationQuotaHandler {
+	return organizations.SetOrganizationQuotaHandlerFunc(func(params organizations.SetOrganizationQuotaParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.SetOrganizationQuotaAction,
+				Resource: permissions.OrganizationArn(params.Organization),
+			},
+		})
+		if err != nil {
+			return organizations.NewSetOrganizationQuotaUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("set_organization_quota")
+		err = deps.Cataloger.SetOrganizationQuota(c.Context(), params.Organization, &catalog.OrganizationQuota{
+			MaxStorageBytes:         swag.Int64Value(params.Quota.MaxStorageBytes),
+			MaxObjects:              swag.Int64Value(params.Quota.MaxObjects),
+			MaxRequestsPerSecond:    swag.Int64Value(params.Quota.MaxRequestsPerSecond),
+			MaxExportBytesPerSecond: swag.Int64Value(params.Quota.MaxExportBytesPerSecond),
+		})
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return organizations.NewSetOrganizationQuotaBadRequest().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return organizations.NewSetOrganizationQuotaDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return organizations.NewSetOrganizationQuotaNoContent()
+	})
+}
+
+func (c *Controller) DeleteOrganizationQuotaHandler() organizations.DeleteOrganizationQuotaHandler {
+	return organizations.DeleteOrganizationQuotaHandlerFunc(func(params organizations.DeleteOrganizationQuotaParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.SetOrganizationQuotaAction,
+				Resource: permissions.OrganizationArn(params.Organization),
+			},
+		})
+		if err != nil {
+			return organizations.NewDeleteOrganizationQuotaUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("delete_organization_quota")
+		err = deps.Cataloger.DeleteOrganizationQuota(c.Context(), params.Organization)
+		if errors.Is(err, db.ErrNotFound) {
+			return organizations.NewDeleteOrganizationQuotaNotFound().
+				WithPayload(responseError("organization not found"))
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return organizations.NewDeleteOrganizationQuotaDefault(http.StatusBadRequest).
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return organizations.NewDeleteOrganizationQuotaDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return organizations.NewDeleteOrganizationQuotaNoContent()
+	})
+}
+
+func (c *Controller) GetOrganizationUsageHandler() organizations.GetOrganizationUsageHandler {
+	return organizations.GetOrganizationUsageHandlerFunc(func(params organizations.GetOrganizationUsageParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadOrganizationAction,
+				Resource: permissions.OrganizationArn(params.Organization),
+			},
+		})
+		if err != nil {
+			return organizations.NewGetOrganizationUsageUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_organization_usage")
+		usage, err := deps.Cataloger.GetOrganizationUsage(c.Context(), params.Organization)
+		if errors.Is(err, db.ErrNotFound) {
+			return organizations.NewGetOrganizationUsageNotFound().
+				WithPayload(responseError("organization not found"))
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return organizations.NewGetOrganizationUsageDefault(http.StatusBadRequest).
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return organizations.NewGetOrganizationUsageDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return organizations.NewGetOrganizationUsageOK().
+			WithPayload(&models.RepositoryUsage{
+				StorageBytes: usage.StorageBytes,
+				Objects:      usage.Objects,
+			})
+	})
+}
+
+func (c *Controller) ListRepositoryActivityHandler() repositories.ListRepositoryActivityHandler {
+	return repositories.ListRepositoryActivityHandlerFunc(func(params repositories.ListRepositoryActivityParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewListRepositoryActivityUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("list_repo_activity")
+		_, err = deps.Cataloger.GetRepository(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewListRepositoryActivityNotFound().
+				WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return repositories.NewListRepositoryActivityDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+
+		after, amount := getPaginationParams(params.After, params.Amount)
+		types := make([]activity.EventType, len(params.Type))
+		for i, t := range params.Type {
+			types[i] = activity.EventType(t)
+		}
+		events, hasMore, err := deps.Activity.List(c.Context(), params.Repository, activity.ListParams{
+			Types:  types,
+			Actor:  swag.StringValue(params.Actor),
+			Ref:    swag.StringValue(params.Ref),
+			After:  after,
+			Amount: amount,
+		})
+		if errors.Is(err, activity.ErrInvalidAfter) || errors.Is(err, activity.ErrInvalidType) {
+			return repositories.NewListRepositoryActivityBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return repositories.NewListRepositoryActivityDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+
+		results := make([]*models.ActivityEvent, len(events))
+		var lastID string
+		for i, event := range events {
+			id := strconv.FormatInt(event.ID, 10)
+			results[i] = &models.ActivityEvent{
+				ID:           swag.String(id),
+				Type:         swag.String(string(event.Type)),
+				Actor:        swag.String(event.Actor),
+				Ref:          event.Ref,
+				Message:      event.Message,
+				CreationDate: swag.Int64(event.CreationDate.Unix()),
+			}
+			lastID = id
+		}
+		returnValue := repositories.NewListRepositoryActivityOK().WithPayload(&repositories.ListRepositoryActivityOKBody{
+			Pagination: &models.Pagination{
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(results))),
+				MaxPerPage: swag.Int64(activity.MaxListAmount),
+			},
+			Results: results,
+		})
+		if hasMore {
+			returnValue.Payload.Pagination.NextOffset = lastID
+		}
+		return returnValue
+	})
+}
+
+func newHookRunModel(run *hooks.Run) *models.HookRun {
+	return &models.HookRun{
+		ID:         swag.String(strconv.FormatInt(run.ID, 10)),
+		EventID:    swag.String(run.EventID),
+		EventType:  swag.String(string(run.EventType)),
+		Branch:     run.Branch,
+		CommitID:   run.CommitID,
+		SourceRef:  run.Event.SourceRef,
+		Action:     swag.String(run.Action),
+		HookID:     swag.String(run.HookID),
+		HookType:   run.HookType,
+		Status:     swag.String(string(run.Status)),
+		Output:     run.Output,
+		Attempt:    swag.Int64(int64(run.Attempt)),
+		StartTime:  swag.Int64(run.StartTime.Unix()),
+		DurationMs: run.DurationMs,
+	}
+}
+
+func (c *Controller) ListHookRunsHandler() hooksop.ListHookRunsHandler {
+	return hooksop.ListHookRunsHandlerFunc(func(params hooksop.ListHookRunsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return hooksop.NewListHookRunsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("list_hook_runs")
+		_, err = deps.Cataloger.GetRepository(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return hooksop.NewListHookRunsNotFound().
+				WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return hooksop.NewListHookRunsDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+
+		after, amount := getPaginationParams(params.After, params.Amount)
+		runs, hasMore, err := deps.Hooks.ListRuns(c.Context(), params.Repository, hooks.ListRunsParams{
+			CommitID: swag.StringValue(params.Commit),
+			Branch:   swag.StringValue(params.Branch),
+			Status:   hooks.RunStatus(swag.StringValue(params.Status)),
+			After:    after,
+			Amount:   amount,
+		})
+		if errors.Is(err, hooks.ErrInvalidAfter) || errors.Is(err, hooks.ErrInvalidStatus) {
+			return hooksop.NewListHookRunsBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return hooksop.NewListHookRunsDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+
+		results := make([]*models.HookRun, len(runs))
+		var lastID string
+		for i, run := range runs {
+			results[i] = newHookRunModel(run)
+			lastID = swag.StringValue(results[i].ID)
+		}
+		returnValue := hooksop.NewListHookRunsOK().WithPayload(&hooksop.ListHookRunsOKBody{
+			Pagination: &models.Pagination{
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(results))),
+				MaxPerPage: swag.Int64(hooks.MaxListRunsAmount),
+			},
+			Results: results,
+		})
+		if hasMore {
+			returnValue.Payload.Pagination.NextOffset = lastID
+		}
+		return returnValue
+	})
+}
+
+func (c *Controller) GetHookRunHandler() hooksop.GetHookRunHandler {
+	return hooksop.GetHookRunHandlerFunc(func(params hooksop.GetHookRunParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return hooksop.NewGetHookRunUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_hook_run")
+		run, err := deps.Hooks.GetRun(c.Context(), params.Repository, params.RunID)
+		if errors.Is(err, db.ErrNotFound) {
+			return hooksop.NewGetHookRunNotFound().WithPayload(responseError("hook run not found"))
+		}
+		if err != nil {
+			return hooksop.NewGetHookRunDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return hooksop.NewGetHookRunOK().WithPayload(newHookRunModel(run))
+	})
+}
+
+func (c *Controller) RetryHookRunHandler() hooksop.RetryHookRunHandler {
+	return hooksop.RetryHookRunHandlerFunc(func(params hooksop.RetryHookRunParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.RetryHookRunAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return hooksop.NewRetryHookRunUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("retry_hook_run")
+		run, err := deps.Hooks.Retry(c.Context(), params.Repository, params.RunID)
+		if errors.Is(err, db.ErrNotFound) {
+			return hooksop.NewRetryHookRunNotFound().WithPayload(responseError("hook run not found"))
+		}
+		if errors.Is(err, hooks.ErrRetryNotAllowed) || errors.Is(err, hooks.ErrHookNotFound) || errors.Is(err, hooks.ErrInvalidAction) {
+			return hooksop.NewRetryHookRunPreconditionFailed().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return hooksop.NewRetryHookRunDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return hooksop.NewRetryHookRunCreated().WithPayload(newHookRunModel(run))
+	})
+}
+
+func (c *Controller) DryRunHooksHandler() hooksop.DryRunHooksHandler {
+	return hooksop.DryRunHooksHandlerFunc(func(params hooksop.DryRunHooksParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+			{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, "*"),
+			},
+		})
+		if err != nil {
+			return hooksop.NewDryRunHooksUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("dry_run_hooks")
+		userModel, err := deps.Auth.GetUser(user.ID)
+		if err != nil {
+			return hooksop.NewDryRunHooksUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		event := &hooks.Event{
+			Type:          hooks.EventType(swag.StringValue(params.Event.EventType)),
+			Repository:    params.Repository,
+			Branch:        swag.StringValue(params.Event.Branch),
+			SourceRef:     params.Event.SourceRef,
+			ParentRef:     params.Event.ParentRef,
+			CommitMessage: params.Event.CommitMessage,
+			Committer:     userModel.Username,
+			Metadata:      params.Event.Metadata,
+		}
+		if event.Type == hooks.EventTypePreCommit && event.SourceRef == "" {
+			event.SourceRef = event.Branch
+		}
+		if event.SourceRef == "" {
+			return hooksop.NewDryRunHooksBadRequest().WithPayload(responseError("source_ref is required for %s events", event.Type))
+		}
+		if event.IsPost() && event.ParentRef == "" {
+			return hooksop.NewDryRunHooksBadRequest().WithPayload(responseError("parent_ref is required for %s events", event.Type))
+		}
+		runs, err := deps.Hooks.DryRun(c.Context(), event)
+		if errors.Is(err, db.ErrNotFound) {
+			return hooksop.NewDryRunHooksNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, hooks.ErrInvalidAction) {
+			return hooksop.NewDryRunHooksBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return hooksop.NewDryRunHooksDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		results := make([]*models.HookRun, len(runs))
+		for i, run := range runs {
+			results[i] = newHookRunModel(run)
+		}
+		return hooksop.NewDryRunHooksOK().WithPayload(&hooksop.DryRunHooksOKBody{Results: results})
+	})
+}
+
+func (c *Controller) SearchRepositoryHandler() repositories.SearchRepositoryHandler {
+	return repositories.SearchRepositoryHandlerFunc(func(params repositories.SearchRepositoryParams, user *models.User) middleware.Responder {
+		searchCommits := swag.StringValue(params.Type) == "commits"
+		action := permissions.ListObjectsAction
+		if searchCommits {
+			action = permissions.ReadCommitAction
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   action,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewSearchRepositoryUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("search_repository")
+		cataloger := deps.Cataloger
+
+		after, amount := getPaginationParams(params.After, params.Amount)
+		ref := swag.StringValue(params.Ref)
+		if !searchCommits && ref == "" {
+			// search objects on the default branch
+			repo, err := cataloger.GetRepository(c.Context(), params.Repository)
+			if errors.Is(err, db.ErrNotFound) {
+				return repositories.NewSearchRepositoryNotFound().WithPayload(responseError("repository not found"))
+			}
+			if err != nil {
+				return repositories.NewSearchRepositoryDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+			}
+			ref = repo.DefaultBranch
+		}
+		pagination := &models.Pagination{
+			MaxPerPage: swag.Int64(MaxResultsPerPage),
+		}
+		payload := &repositories.SearchRepositoryOKBody{Pagination: pagination}
+		var hasMore bool
+		if searchCommits {
+			var res []*catalog.CommitLog
+			res, hasMore, err = cataloger.SearchCommits(c.Context(), params.Repository, ref, params.Query, after, amount)
+			payload.Commits = make([]*models.Commit, len(res))
+			for i, commit := range res {
+				payload.Commits[i] = &models.Commit{
+					Committer:    commit.Committer,
+					CreationDate: commit.CreationDate.Unix(),
+					ID:           commit.Reference,
+					Message:      commit.Message,
+					Metadata:     commit.Metadata,
+					Parents:      commit.Parents,
+				}
+			}
+			pagination.Results = swag.Int64(int64(len(res)))
+			if hasMore {
+				pagination.NextOffset = res[len(res)-1].Reference
+			}
+		} else {
+			var res []*catalog.Entry
+			res, hasMore, err = cataloger.SearchEntries(c.Context(), params.Repository, ref, params.Query, after, amount)
+			payload.Objects = make([]*models.ObjectStats, len(res))
+			for i, entry := range res {
+				payload.Objects[i] = &models.ObjectStats{
+					Checksum:  entry.Checksum,
+					Mtime:     entry.CreationDate.Unix(),
+					Path:      entry.Path,
+					PathType:  models.ObjectStatsPathTypeObject,
+					SizeBytes: entry.Size,
+				}
+			}
+			pagination.Results = swag.Int64(int64(len(res)))
+			if hasMore {
+				pagination.NextOffset = res[len(res)-1].Path
+			}
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) || errors.Is(err, catalog.ErrInvalidReference) {
+			return repositories.NewSearchRepositoryBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewSearchRepositoryNotFound().WithPayload(responseError("repository or reference not found"))
+		}
+		if err != nil {
+			return repositories.NewSearchRepositoryDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		pagination.HasMore = swag.Bool(hasMore)
+		return repositories.NewSearchRepositoryOK().WithPayload(payload)
+	})
+}
+
+func (c *Controller) GetCommitHandler() commits.GetCommitHandler {
+	return commits.GetCommitHandlerFunc(func(params commits.GetCommitParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadCommitAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return commits.NewGetCommitUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_commit")
+		commit, err := deps.Cataloger.GetCommit(c.Context(), params.Repository, params.CommitID)
+		if errors.Is(err, db.ErrNotFound) {
+			return commits.NewGetCommitNotFound().WithPayload(responseError("commit not found"))
+		}
+		if err != nil {
+			return commits.NewGetCommitDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return commits.NewGetCommitOK().WithPayload(&models.Commit{
+			Committer:    commit.Committer,
+			CreationDate: commit.CreationDate.Unix(),
+			ID:           params.CommitID,
+			Message:      commit.Message,
+			Metadata:     commit.Metadata,
+			Parents:      commit.Parents,
+		})
+	})
+}
+
+func (c *Controller) GetCommitGraphHandler() commits.GetCommitGraphHandler {
+	return commits.GetCommitGraphHandlerFunc(func(params commits.GetCommitGraphParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadCommitAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return commits.NewGetCommitGraphUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_commit_graph")
+		graph, err := deps.Cataloger.GetCommitGraph(c.Context(), params.Repository, params.Ref, int(swag.Int64Value(params.Depth)))
+		if errors.Is(err, db.ErrNotFound) {
+			return commits.NewGetCommitGraphNotFound().WithPayload(responseError("reference not found"))
+		}
+		if err != nil {
+			return commits.NewGetCommitGraphDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		payload := &models.CommitGraph{
+			Commits: make([]*models.Commit, len(graph.Commits)),
+			Edges:   make([]*models.CommitGraphEdge, len(graph.Edges)),
+		}
+		for i, commit := range graph.Commits {
+			payload.Commits[i] = &models.Commit{
+				Committer:    commit.Committer,
+				CreationDate: commit.CreationDate.Unix(),
+				ID:           commit.Reference,
+				Message:      commit.Message,
+				Metadata:     commit.Metadata,
+				Parents:      commit.Parents,
+			}
+		}
+		for i, edge := range graph.Edges {
+			payload.Edges[i] = &models.CommitGraphEdge{
+				Child:  swag.String(edge.Child),
+				Parent: swag.String(edge.Parent),
+				Merge:  swag.Bool(edge.Merge),
+			}
+		}
+		return commits.NewGetCommitGraphOK().WithPayload(payload)
+	})
+}
+
+func (c *Controller) CommitHandler() commits.CommitHandler {
+	return commits.CommitHandlerFunc(func(params commits.CommitParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.CreateCommitAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return commits.NewCommitUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("create_commit")
+		commit, err := c.commit(deps, user, params.Repository, params.Branch, swag.StringValue(params.Commit.Message), params.Commit.Metadata, catalog.CommitParams{
+			Prefixes: params.Commit.Prefixes,
+		})
+		switch {
+		case errors.Is(err, ErrAuthorization):
+			return commits.NewCommitUnauthorized().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, hooks.ErrHookFailed),
+			errors.Is(err, hooks.ErrInvalidAction),
+			errors.Is(err, catalog.ErrValidationFailed),
+			errors.Is(err, catalog.ErrCommitLimitExceeded),
+			errors.Is(err, catalog.ErrCommitJobInProgress):
+			return commits.NewCommitPreconditionFailed().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrRepositoryReadOnly):
+			return commits.NewCommitDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		case err != nil:
+			return commits.NewCommitDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return commits.NewCommitCreated().WithPayload(&models.Commit{
+			Committer:    commit.Committer,
+			CreationDate: commit.CreationDate.Unix(),
+			ID:           commit.Reference,
+			Message:      commit.Message,
+			Metadata:     commit.Metadata,
+			Parents:      commit.Parents,
+		})
+	})
+}
+
+func (c *Controller) AmendCommitHandler() commits.AmendCommitHandler {
+	return commits.AmendCommitHandlerFunc(func(params commits.AmendCommitParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.CreateCommitAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return commits.NewAmendCommitUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("amend_commit")
+		ctx := c.Context()
+		if authorize(deps.Auth, user, []permissions.Permission{
+			{
+				Action:   permissions.ExemptCommitLimitsAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		}) == nil {
+			ctx = catalog.WithCommitLimitsExempt(ctx)
+		}
+		commit, err := deps.Cataloger.AmendCommit(ctx, params.Repository, params.Branch,
+			swag.StringValue(params.Commit.Message), params.Commit.Metadata, catalog.AmendCommitParams{
+				IncludeChanges: swag.BoolValue(params.Commit.IncludeChanges),
+			})
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			return commits.NewAmendCommitNotFound().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrCommitNotAmendable):
+			return commits.NewAmendCommitConflict().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrCommitLimitExceeded),
+			errors.Is(err, catalog.ErrCommitJobInProgress),
+			errors.Is(err, catalog.ErrExportInProgress):
+			return commits.NewAmendCommitPreconditionFailed().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrInvalidValue):
+			return commits.NewAmendCommitBadRequest().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrRepositoryReadOnly):
+			return commits.NewAmendCommitDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		case err != nil:
+			return commits.NewAmendCommitDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return commits.NewAmendCommitOK().WithPayload(&models.Commit{
+			Committer:    commit.Committer,
+			CreationDate: commit.CreationDate.Unix(),
+			ID:           commit.Reference,
+			Message:      commit.Message,
+			Metadata:     commit.Metadata,
+			Parents:      commit.Parents,
+		})
+	})
+}
+
+// commit commits branch as user, running the commit hooks of the repository around it
+func (c *Controller) commit(deps *Dependencies, user *models.User, repository, branch, message string, metadata map[string]string, params catalog.CommitParams) (*catalog.CommitLog, error) {
+	userModel, err := deps.Auth.GetUser(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAuthorization, err)
+	}
+	committer := userModel.Username
+	event := &hooks.Event{
+		Type:          hooks.EventTypePreCommit,
+		Repository:    repository,
+		Branch:        branch,
+		SourceRef:     branch,
+		CommitMessage: message,
+		Committer:     committer,
+		Metadata:      metadata,
+	}
+	if err := c.runHooks(deps, event); err != nil {
+		return nil, err
+	}
+	ctx := committerContext(c.Context(), deps)
+	exempt := authorize(deps.Auth, user, []permissions.Permission{
+		{
+			Action:   permissions.ExemptCommitLimitsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) == nil
+	if exempt {
+		ctx = catalog.WithCommitLimitsExempt(ctx)
+	}
+	// hooks may add metadata to the commit
+	commit, err := deps.Cataloger.Commit(ctx, repository, branch, message, committer, event.Metadata, params)
+	if err != nil {
+		return nil, err
+	}
+	deps.RecordActivity(&activity.Event{
+		Repository: repository,
+		Type:       activity.EventTypeCommit,
+		Actor:      user.ID,
+		Ref:        commit.Reference,
+		Message:    message,
+	})
+	c.runPostHooks(deps, event, hooks.EventTypePostCommit, commit)
+	return commit, nil
+}
+
+func newJobModel(job *catalog.CommitJob) *models.Job {
+	return &models.Job{
+		ID:               swag.Int64(job.ID),
+		Type:             swag.String(models.JobTypeCommit),
+		Branch:           swag.String(job.Branch),
+		Status:           swag.String(string(job.Status)),
+		Committer:        job.Committer,
+		Message:          job.Message,
+		Metadata:         job.Metadata,
+		CommittedEntries: swag.Int64(job.CommittedEntries),
+		LastPath:         job.After,
+		Error:            job.Error,
+		CommitID:         job.Reference,
+		CreationDate:     swag.Int64(job.CreationDate.Unix()),
+		UpdateDate:       swag.Int64(job.UpdateDate.Unix()),
+	}
+}
+
+func (c *Controller) CreateCommitJobHandler() jobsop.CreateCommitJobHandler {
+	return jobsop.CreateCommitJobHandlerFunc(func(params jobsop.CreateCommitJobParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.CreateCommitAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return jobsop.NewCreateCommitJobUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("create_commit_job")
+		if len(params.Commit.Prefixes) > 0 {
+			return jobsop.NewCreateCommitJobDefault(http.StatusBadRequest).WithPayload(responseErrorFrom(
+				fmt.Errorf("commit job prefixes: %w", catalog.ErrFeatureNotSupported)))
+		}
+		userModel, err := deps.Auth.GetUser(user.ID)
+		if err != nil {
+			return jobsop.NewCreateCommitJobUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		message := swag.StringValue(params.Commit.Message)
+		event := &hooks.Event{
+			Type:          hooks.EventTypePreCommit,
+			Repository:    params.Repository,
+			Branch:        params.Branch,
+			SourceRef:     params.Branch,
+			CommitMessage: message,
+			Committer:     userModel.Username,
+			Metadata:      params.Commit.Metadata,
+		}
+		err = c.runHooks(deps, event)
+		if err == nil {
+			ctx := committerContext(c.Context(), deps)
+			if authorize(deps.Auth, user, []permissions.Permission{
+				{
+					Action:   permissions.ExemptCommitLimitsAction,
+					Resource: permissions.RepoArn(params.Repository),
+				},
+			}) == nil {
+				ctx = catalog.WithCommitLimitsExempt(ctx)
+			}
+			// hooks may add metadata to the commit
+			var job *catalog.CommitJob
+			job, err = deps.Cataloger.CreateCommitJob(ctx, params.Repository, params.Branch, message, userModel.Username, event.Metadata)
+			if err == nil {
+				go c.runCommitJob(user, event, job)
+				return jobsop.NewCreateCommitJobAccepted().WithPayload(newJobModel(job))
+			}
+		}
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			return jobsop.NewCreateCommitJobNotFound().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrCommitJobInProgress):
+			return jobsop.NewCreateCommitJobConflict().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, hooks.ErrHookFailed),
+			errors.Is(err, hooks.ErrInvalidAction),
+			errors.Is(err, catalog.ErrValidationFailed),
+			errors.Is(err, catalog.ErrCommitLimitExceeded),
+			errors.Is(err, catalog.ErrNothingToCommit):
+			return jobsop.NewCreateCommitJobPreconditionFailed().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrRepositoryReadOnly):
+			return jobsop.NewCreateCommitJobDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		default:
+			return jobsop.NewCreateCommitJobDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+	})
+}
+
+// runCommitJob runs job to completion and then performs the rest of a commit of user: records
+// its activity and runs the post-commit hooks for the pre-commit event.  It runs after the
+// request that started the job has returned, so a failure is only logged on the job.
+func (c *Controller) runCommitJob(user *models.User, pre *hooks.Event, job *catalog.CommitJob) {
+	ctx := logging.AddFields(c.Context(), logging.Fields{"user": user.ID, "commit_job": job.ID})
+	deps := c.deps.WithContext(ctx)
+	job, err := deps.Cataloger.RunCommitJob(ctx, pre.Repository, job.ID)
+	if err != nil {
+		deps.logger.WithError(err).WithField("repository", pre.Repository).Warn("commit job failed")
+		return
+	}
+	commit, err := deps.Cataloger.GetCommit(ctx, pre.Repository, job.Reference)
+	if err != nil {
+		deps.logger.WithError(err).WithField("reference", job.Reference).Warn("failed to get commit of commit job")
+		return
+	}
+	deps.RecordActivity(&activity.Event{
+		Repository: pre.Repository,
+		Type:       activity.EventTypeCommit,
+		Actor:      user.ID,
+		Ref:        commit.Reference,
+		Message:    commit.Message,
+	})
+	c.runPostHooks(deps, pre, hooks.EventTypePostCommit, commit)
+}
+
+func (c *Controller) ListJobsHandler() jobsop.ListJobsHandler {
+	return jobsop.ListJobsHandlerFunc(func(params jobsop.ListJobsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return jobsop.NewListJobsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("list_jobs")
+		_, err = deps.Cataloger.GetRepository(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return jobsop.NewListJobsNotFound().WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return jobsop.NewListJobsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		after, amount := getPaginationParams(params.After, params.Amount)
+		var afterID int64
+		if after != "" {
+			afterID, err = strconv.ParseInt(after, 10, 64)
+			if err != nil {
+				return jobsop.NewListJobsBadRequest().WithPayload(responseError("invalid after job ID: %s", after))
+			}
+		}
+		jobs, hasMore, err := deps.Cataloger.ListCommitJobs(c.Context(), params.Repository, catalog.ListCommitJobsParams{
+			Branch: swag.StringValue(params.Branch),
+			Status: catalog.CommitJobStatus(swag.StringValue(params.Status)),
+			After:  afterID,
+			Limit:  amount,
+		})
+		if err != nil {
+			return jobsop.NewListJobsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		results := make([]*models.Job, len(jobs))
+		var lastID string
+		for i, job := range jobs {
+			results[i] = newJobModel(job)
+			lastID = strconv.FormatInt(job.ID, 10)
+		}
+		returnValue := jobsop.NewListJobsOK().WithPayload(&jobsop.ListJobsOKBody{
+			Pagination: &models.Pagination{
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(results))),
+				MaxPerPage: swag.Int64(catalog.MaxListCommitJobsAmount),
+			},
+			Results: results,
+		})
+		if hasMore {
+			returnValue.Payload.Pagination.NextOffset = lastID
+		}
+		return returnValue
+	})
+}
+
+func (c *Controller) GetJobHandler() jobsop.GetJobHandler {
+	return jobsop.GetJobHandlerFunc(func(params jobsop.GetJobParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return jobsop.NewGetJobUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_job")
+		job, err := deps.Cataloger.GetCommitJob(c.Context(), params.Repository, params.JobID)
+		if errors.Is(err, db.ErrNotFound) {
+			return jobsop.NewGetJobNotFound().WithPayload(responseError("job not found"))
+		}
+		if err != nil {
+			return jobsop.NewGetJobDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return jobsop.NewGetJobOK().WithPayload(newJobModel(job))
+	})
+}
+
+func (c *Controller) ResumeJobHandler() jobsop.ResumeJobHandler {
+	return jobsop.ResumeJobHandlerFunc(func(params jobsop.ResumeJobParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return jobsop.NewResumeJobUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("resume_job")
+		job, err := deps.Cataloger.GetCommitJob(c.Context(), params.Repository, params.JobID)
+		if errors.Is(err, db.ErrNotFound) {
+			return jobsop.NewResumeJobNotFound().WithPayload(responseError("job not found"))
+		}
+		if err != nil {
+			return jobsop.NewResumeJobDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		// resuming a commit job commits the rest of its branch
+		err = authorize(deps.Auth, user, []permissions.Permission{
+			{
+				Action:   permissions.CreateCommitAction,
+				Resource: permissions.BranchArn(params.Repository, job.Branch),
+			},
+		})
+		if err != nil {
+			return jobsop.NewResumeJobUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		if job.Status != catalog.CommitJobStatusRunning {
+			return jobsop.NewResumeJobPreconditionFailed().WithPayload(responseError("job %d already %s", job.ID, job.Status))
+		}
+		// the hooks of the pre-commit event ran when the job started
+		pre := &hooks.Event{
+			Type:          hooks.EventTypePreCommit,
+			Repository:    params.Repository,
+			Branch:        job.Branch,
+			SourceRef:     job.Branch,
+			CommitMessage: job.Message,
+			Committer:     job.Committer,
+			Metadata:      job.Metadata,
+		}
+		go c.runCommitJob(user, pre, job)
+		return jobsop.NewResumeJobAccepted().WithPayload(newJobModel(job))
+	})
+}
+
+func (c *Controller) AbortJobHandler() jobsop.AbortJobHandler {
+	return jobsop.AbortJobHandlerFunc(func(params jobsop.AbortJobParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return jobsop.NewAbortJobUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("abort_job")
+		job, err := deps.Cataloger.GetCommitJob(c.Context(), params.Repository, params.JobID)
+		if errors.Is(err, db.ErrNotFound) {
+			return jobsop.NewAbortJobNotFound().WithPayload(responseError("job not found"))
+		}
+		if err != nil {
+			return jobsop.NewAbortJobDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		// aborting a commit job is allowed to whoever could have committed its branch
+		err = authorize(deps.Auth, user, []permissions.Permission{
+			{
+				Action:   permissions.CreateCommitAction,
+				Resource: permissions.BranchArn(params.Repository, job.Branch),
+			},
+		})
+		if err != nil {
+			return jobsop.NewAbortJobUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		job, err = deps.Cataloger.AbortCommitJob(c.Context(), params.Repository, params.JobID)
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			return jobsop.NewAbortJobNotFound().WithPayload(responseError("job not found"))
+		case errors.Is(err, catalog.ErrCommitJobNotRunning):
+			return jobsop.NewAbortJobPreconditionFailed().WithPayload(responseErrorFrom(err))
+		case err != nil:
+			return jobsop.NewAbortJobDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return jobsop.NewAbortJobOK().WithPayload(newJobModel(job))
+	})
+}
+
+// commitsFilterFromParams returns the filter of the commit log requested by params
+func commitsFilterFromParams(params commits.GetBranchCommitLogParams) (catalog.CommitsFilter, error) {
+	filter := catalog.CommitsFilter{
+		MessageContains: swag.StringValue(params.Message),
+		MessageRegexp:   swag.StringValue(params.MessageRegex),
+		Committer:       swag.StringValue(params.Committer),
+	}
+	if len(params.Metadata) > 0 {
+		const keyValueParts = 2
+		filter.Metadata = make(catalog.Metadata, len(params.Metadata))
+		for _, pair := range params.Metadata {
+			parts := strings.SplitN(pair, "=", keyValueParts)
+			if len(parts) != keyValueParts {
+				return filter, fmt.Errorf("metadata %s not key=value: %w", pair, catalog.ErrInvalidValue)
+			}
+			filter.Metadata[parts[0]] = parts[1]
+		}
+	}
+	if params.Since != nil {
+		filter.Since = time.Unix(*params.Since, 0)
+	}
+	if params.Until != nil {
+		filter.Until = time.Unix(*params.Until, 0)
+	}
+	return filter, nil
+}
+
+func (c *Controller) CommitsGetBranchCommitLogHandler() commits.GetBranchCommitLogHandler {
+	return commits.GetBranchCommitLogHandlerFunc(func(params commits.GetBranchCommitLogParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadBranchAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return commits.NewGetBranchCommitLogUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_branch_commit_log")
+		cataloger := deps.Cataloger
+
+		after, amount := getPaginationParams(params.After, params.Amount)
+		filter, err := commitsFilterFromParams(params)
+		if err != nil {
+			return commits.NewGetBranchCommitLogBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		// get commit log
+		commitLog, hasMore, err := cataloger.ListCommits(c.Context(), params.Repository, params.Branch, after, amount, filter)
+		switch {
+		case errors.Is(err, catalog.ErrInvalidValue):
+			return commits.NewGetBranchCommitLogBadRequest().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrBranchNotFound):
+			return commits.NewGetBranchCommitLogNotFound().WithPayload(responseError("branch '%s' not found.", params.Branch))
+		case errors.Is(err, catalog.ErrRepositoryNotFound):
+			return commits.NewGetBranchCommitLogNotFound().WithPayload(responseError("repository '%s' not found.", params.Repository))
+		case err != nil:
+			return commits.NewGetBranchCommitLogDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		serializedCommits := make([]*models.Commit, len(commitLog))
+		lastID := ""
+		for i, commit := range commitLog {
+			serializedCommits[i] = &models.Commit{
+				Committer:    commit.Committer,
+				CreationDate: commit.CreationDate.Unix(),
+				ID:           commit.Reference,
+				Message:      commit.Message,
+				Metadata:     commit.Metadata,
+				Parents:      commit.Parents,
+			}
+			lastID = commit.Reference
+		}
+
+		returnValue := commits.NewGetBranchCommitLogOK().WithPayload(&commits.GetBranchCommitLogOKBody{
+			Pagination: &models.Pagination{
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(serializedCommits))),
+				MaxPerPage: swag.Int64(MaxResultsPerPage),
+			},
+			Results: serializedCommits,
+		})
+		if hasMore {
+			returnValue.Payload.Pagination.NextOffset = lastID
+		}
+		return returnValue
+	})
+}
+
+func (c *Controller) CommitsGetBranchChangesHandler() commits.GetBranchChangesHandler {
+	return commits.GetBranchChangesHandlerFunc(func(params commits.GetBranchChangesParams, user *models.User) middleware.Responder {
+		withDiffSummary := swag.BoolValue(params.DiffSummary)
+		perms := []permissions.Permission{
+			{
+				Action:   permissions.ReadBranchAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		}
+		if withDiffSummary {
+			perms = append(perms, permissions.Permission{
+				Action:   permissions.ListObjectsAction,
+				Resource: permissions.RepoArn(params.Repository),
+			})
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, perms)
+		if err != nil {
+			return commits.NewGetBranchChangesUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_branch_changes")
+		cataloger := deps.Cataloger
+
+		since := swag.StringValue(params.Since)
+		if since == "" {
+			// start tracking the branch from its current commit
+			reference, err := cataloger.GetBranchReference(c.Context(), params.Repository, params.Branch)
+			if errors.Is(err, db.ErrNotFound) {
+				return commits.NewGetBranchChangesNotFound().WithPayload(responseErrorFrom(err))
+			}
+			if err != nil {
+				return commits.NewGetBranchChangesDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+			}
+			return commits.NewGetBranchChangesOK().WithPayload(&models.BranchChanges{
+				Token:   swag.String(reference),
+				HasMore: swag.Bool(false),
+				Results: []*models.BranchChange{},
+			})
+		}
+
+		_, amount := getPaginationParams(nil, params.Amount)
+		if amount <= 0 || amount > MaxResultsPerPage {
+			amount = MaxResultsPerPage
+		}
+		commitLog, hasMore, err := cataloger.ListCommitsSince(c.Context(), params.Repository, params.Branch, since, amount)
+		switch {
+		case errors.Is(err, catalog.ErrCommitNotFound):
+			return commits.NewGetBranchChangesGone().WithPayload(responseError("the commit of token '%s' is no longer on branch '%s'", since, params.Branch))
+		case errors.Is(err, db.ErrNotFound):
+			return commits.NewGetBranchChangesNotFound().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrInvalidReference), errors.Is(err, catalog.ErrInvalidValue):
+			return commits.NewGetBranchChangesBadRequest().WithPayload(responseError("invalid token '%s': %s", since, err))
+		case err != nil:
+			return commits.NewGetBranchChangesDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		results := make([]*models.BranchChange, len(commitLog))
+		token := since
+		for i, commit := range commitLog {
+			change := &models.BranchChange{
+				Commit: &models.Commit{
+					Committer:    commit.Committer,
+					CreationDate: commit.CreationDate.Unix(),
+					ID:           commit.Reference,
+					Message:      commit.Message,
+					Metadata:     commit.Metadata,
+					Parents:      commit.Parents,
+				},
+			}
+			if withDiffSummary && len(commit.Parents) > 0 {
+				// the previous commit of the branch is the last parent
+				parent := commit.Parents[len(commit.Parents)-1]
+				summary, err := cataloger.DiffSummary(c.Context(), params.Repository, commit.Reference, parent)
+				if err != nil {
+					return commits.NewGetBranchChangesDefault(http.StatusInternalServerError).
+						WithPayload(responseError("could not summarize commit %s: %s", commit.Reference, err))
+				}
+				change.DiffSummary = &models.DiffSummary{
+					CommitsAhead:  int64(summary.CommitsAhead),
+					CommitsBehind: int64(summary.CommitsBehind),
+					Added:         int64(summary.Added),
+					Removed:       int64(summary.Removed),
+					Changed:       int64(summary.Changed),
+					Conflicts:     int64(summary.Conflicts),
+					BytesDelta:    summary.BytesDelta,
+				}
+			}
+			results[i] = change
+			token = commit.Reference
+		}
+		return commits.NewGetBranchChangesOK().WithPayload(&models.BranchChanges{
+			Token:   swag.String(token),
+			HasMore: swag.Bool(hasMore),
+			Results: results,
+		})
+	})
+}
+
+func (c *Controller) CreateDataLineageHandler() commits.CreateDataLineageHandler {
+	return commits.CreateDataLineageHandlerFunc(func(params commits.CreateDataLineageParams, user *models.User) middleware.Responder {
+		perms := []permissions.Permission{
+			{
+				Action:   permissions.CreateCommitAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		}
+		sources := make([]catalog.DataLineageSource, len(params.Lineage.Sources))
+		for i, source := range params.Lineage.Sources {
+			sources[i] = catalog.DataLineageSource{
+				Repository: swag.StringValue(source.Repository),
+				Reference:  swag.StringValue(source.Ref),
+			}
+			perms = append(perms, permissions.Permission{
+				Action:   permissions.ReadCommitAction,
+				Resource: permissions.RepoArn(sources[i].Repository),
+			})
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, perms)
+		if err != nil {
+			return commits.NewCreateDataLineageUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("create_data_lineage")
+		err = deps.Cataloger.CreateDataLineage(c.Context(), params.Repository, params.Ref, sources)
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			return commits.NewCreateDataLineageNotFound().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrInvalidValue), errors.Is(err, catalog.ErrInvalidReference):
+			return commits.NewCreateDataLineageBadRequest().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrRepositoryReadOnly):
+			return commits.NewCreateDataLineageDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		case err != nil:
+			return commits.NewCreateDataLineageDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return commits.NewCreateDataLineageNoContent()
+	})
+}
+
+func (c *Controller) WalkDataLineageHandler() commits.WalkDataLineageHandler {
+	return commits.WalkDataLineageHandlerFunc(func(params commits.WalkDataLineageParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadCommitAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return commits.NewWalkDataLineageUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("walk_data_lineage")
+		direction := catalog.DataLineageDirection(swag.StringValue(params.Direction))
+		depth := int(swag.Int64Value(params.Depth))
+		edges, err := deps.Cataloger.WalkDataLineage(c.Context(), params.Repository, params.Ref, direction, depth)
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			return commits.NewWalkDataLineageNotFound().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrInvalidValue), errors.Is(err, catalog.ErrInvalidReference):
+			return commits.NewWalkDataLineageBadRequest().WithPayload(responseErrorFrom(err))
+		case err != nil:
+			return commits.NewWalkDataLineageDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		// the walk only passes through repositories the user may read
+		edges = catalog.FilterDataLineage(edges, direction, depth, func(repository string) bool {
+			return authorize(deps.Auth, user, []permissions.Permission{
+				{
+					Action:   permissions.ReadCommitAction,
+					Resource: permissions.RepoArn(repository),
+				},
+			}) == nil
+		})
+		results := make([]*models.DataLineageEdge, len(edges))
+		for i, edge := range edges {
+			results[i] = &models.DataLineageEdge{
+				Repository:       edge.Repository,
+				Ref:              edge.Reference,
+				SourceRepository: edge.SourceRepository,
+				SourceRef:        edge.SourceReference,
+				CreationDate:     edge.CreationDate.Unix(),
+				Depth:            int64(edge.Depth),
+			}
+		}
+		return commits.NewWalkDataLineageOK().WithPayload(&commits.WalkDataLineageOKBody{
+			Results: results,
+		})
+	})
+}
+
+func ensureStorageNamespaceRW(adapter block.Adapter, storageNamespace string) error {
+	const (
+		dummyKey  = "dummy"
+		dummyData = "this is dummy data - created by lakeFS in order to check accessibility "
+	)
+
+	err := adapter.Put(block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: dummyKey}, int64(len(dummyData)), bytes.NewReader([]byte(dummyData)), block.PutOpts{})
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.Get(block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: dummyKey}, int64(len(dummyData)))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Controller) CreateRepositoryHandler() repositories.CreateRepositoryHandler {
+	return repositories.CreateRepositoryHandlerFunc(func(params repositories.CreateRepositoryParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.CreateRepositoryAction,
+				Resource: permissions.RepoArn(swag.StringValue(params.Repository.Name)),
+			},
+		})
+		if err != nil {
+			return repositories.NewCreateRepositoryUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("create_repo")
+
+		err = ensureStorageNamespaceRW(deps.BlockAdapter, swag.StringValue(params.Repository.StorageNamespace))
+		if err != nil {
+			c.deps.logger.
+				WithError(err).
+				WithField("storage_namespace", swag.StringValue(params.Repository.StorageNamespace)).
+				Warn("Could not access storage namespace")
+			return repositories.NewCreateRepositoryBadRequest().
+				WithPayload(responseError("error creating repository: could not access storage namespace"))
+		}
+		repo, err := deps.Cataloger.CreateRepository(c.Context(),
+			swag.StringValue(params.Repository.Name),
+			swag.StringValue(params.Repository.StorageNamespace),
+			params.Repository.DefaultBranch)
+		if err != nil {
+			return repositories.NewGetRepositoryDefault(http.StatusInternalServerError).
+				WithPayload(responseError(fmt.Sprintf("error creating repository: %s", err)))
+		}
+
+		return repositories.NewCreateRepositoryCreated().WithPayload(&models.Repository{
+			StorageNamespace: repo.StorageNamespace,
+			CreationDate:     repo.CreationDate.Unix(),
+			DefaultBranch:    repo.DefaultBranch,
+			ID:               repo.Name,
+		})
+	})
+}
+
+func (c *Controller) DeleteRepositoryHandler() repositories.DeleteRepositoryHandler {
+	return repositories.DeleteRepositoryHandlerFunc(func(params repositories.DeleteRepositoryParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.DeleteRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return repositories.NewDeleteRepositoryUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("delete_repo")
+		err = deps.Cataloger.DeleteRepository(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return repositories.NewDeleteRepositoryNotFound().WithPayload(responseError("repository not found"))
+		}
+		if err != nil {
+			return repositories.NewDeleteRepositoryDefault(http.StatusInternalServerError).
+				WithPayload(responseError("error deleting repository"))
+		}
+		return repositories.NewDeleteRepositoryNoContent()
+	})
+}
+
+// branchStatusModel serializes the status of branch, listed with its status
+func branchStatusModel(branch *catalog.Branch) *models.BranchStatus {
+	status := &models.BranchStatus{
+		ID: swag.String(branch.Name),
+	}
+	if branch.Status == nil {
+		return status
+	}
+	status.SourceBranch = branch.Status.SourceBranch
+	status.CommitsAhead = int64(branch.Status.CommitsAhead)
+	status.CommitsBehind = int64(branch.Status.CommitsBehind)
+	status.UncommittedEntries = int64(branch.Status.UncommittedEntries)
+	if head := branch.Status.HeadCommit; head != nil {
+		status.Head = &models.Commit{
+			Committer:    head.Committer,
+			CreationDate: head.CreationDate.Unix(),
+			ID:           head.Reference,
+			Message:      head.Message,
+			Metadata:     head.Metadata,
+			Parents:      head.Parents,
+		}
+	}
+	return status
+}
+
+func (c *Controller) ListBranchesHandler() branches.ListBranchesHandler {
+	return branches.ListBranchesHandlerFunc(func(params branches.ListBranchesParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ListBranchesAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return branches.NewListBranchesUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("list_branches")
+		cataloger := deps.Cataloger
+
+		after, amount := getPaginationParams(params.After, params.Amount)
+
+		includeStatus := swag.BoolValue(params.Status)
+		res, hasMore, err := cataloger.ListBranches(c.Context(), params.Repository, "", amount, after, catalog.ListBranchesParams{
+			IncludeStatus: includeStatus,
+			SortBy:        catalog.BranchSortBy(swag.StringValue(params.SortBy)),
+		})
+		if err != nil {
+			return branches.NewListBranchesDefault(http.StatusInternalServerError).
+				WithPayload(responseError("could not list branches: %s", err))
+		}
+
+		branchList := make([]string, len(res))
+		var statuses []*models.BranchStatus
+		if includeStatus {
+			statuses = make([]*models.BranchStatus, len(res))
+		}
+		var lastID string
+		for i, branch := range res {
+			branchList[i] = branch.Name
+			lastID = branch.Name
+			if includeStatus {
+				statuses[i] = branchStatusModel(branch)
+			}
+		}
+		returnValue := branches.NewListBranchesOK().WithPayload(&branches.ListBranchesOKBody{
+			Pagination: &models.Pagination{
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(branchList))),
+				MaxPerPage: swag.Int64(MaxResultsPerPage),
+			},
+			Results:  branchList,
+			Statuses: statuses,
+		})
+
+		if hasMore {
+			returnValue.Payload.Pagination.NextOffset = lastID
+		}
+
+		return returnValue
+	})
+}
+
+func (c *Controller) GetBranchHandler() branches.GetBranchHandler {
+	return branches.GetBranchHandlerFunc(func(params branches.GetBranchParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadBranchAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return branches.NewGetBranchUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_branch")
+		reference, err := deps.Cataloger.GetBranchReference(c.Context(), params.Repository, params.Branch)
+
+		switch {
+		case errors.Is(err, catalog.ErrBranchNotFound):
+			return branches.NewGetBranchNotFound().WithPayload(responseError("branch '%s' not found.", params.Branch))
+		case errors.Is(err, catalog.ErrRepositoryNotFound):
+			return branches.NewGetBranchNotFound().WithPayload(responseError("repository '%s' not found.", params.Repository))
+		case err != nil:
+			return branches.NewGetBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		return branches.NewGetBranchOK().WithPayload(reference)
+	})
+}
+
+func (c *Controller) CreateBranchHandler() branches.CreateBranchHandler {
+	return branches.CreateBranchHandlerFunc(func(params branches.CreateBranchParams, user *models.User) middleware.Responder {
+		repository := params.Repository
+		branch := swag.StringValue(params.Branch.Name)
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.CreateBranchAction,
+				Resource: permissions.BranchArn(repository, branch),
+			},
+		})
+		if err != nil {
+			return branches.NewCreateBranchUnauthorized().WithPayload(responseErrorFrom(err))
 		}
 		deps.LogAction("create_branch")
 		cataloger := deps.Cataloger
 		sourceBranch := swag.StringValue(params.Branch.Source)
 		commitLog, err := cataloger.CreateBranch(c.Context(), repository, branch, sourceBranch)
+		if errors.Is(err, catalog.ErrRepositoryReadOnly) {
+			return branches.NewCreateBranchDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
 		if err != nil {
 			return branches.NewCreateBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
+		deps.RecordActivity(&activity.Event{
+			Repository: repository,
+			Type:       activity.EventTypeCreateBranch,
+			Actor:      user.ID,
+			Ref:        branch,
+			Message:    fmt.Sprintf("created from %s", sourceBranch),
+		})
 		return branches.NewCreateBranchCreated().WithPayload(commitLog.Reference)
 	})
 }
@@ -706,14 +2376,65 @@ func (c *Controller) DeleteBranchHandler() branches.DeleteBranchHandler {
 			return branches.NewDeleteBranchNotFound().WithPayload(responseError("branch '%s' not found.", params.Branch))
 		case errors.Is(err, catalog.ErrRepositoryNotFound):
 			return branches.NewDeleteBranchNotFound().WithPayload(responseError("repository '%s' not found.", params.Repository))
+		case errors.Is(err, catalog.ErrRepositoryReadOnly):
+			return branches.NewDeleteBranchDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
 		case err != nil:
 			return branches.NewDeleteBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
-
+		deps.RecordActivity(&activity.Event{
+			Repository: params.Repository,
+			Type:       activity.EventTypeDeleteBranch,
+			Actor:      user.ID,
+			Ref:        params.Branch,
+		})
 		return branches.NewDeleteBranchNoContent()
 	})
 }
 
+func (c *Controller) RenameBranchHandler() branches.RenameBranchHandler {
+	return branches.RenameBranchHandlerFunc(func(params branches.RenameBranchParams, user *models.User) middleware.Responder {
+		newName := swag.StringValue(params.Rename.Name)
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.RenameBranchAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+			{
+				Action:   permissions.CreateBranchAction,
+				Resource: permissions.BranchArn(params.Repository, newName),
+			},
+		})
+		if err != nil {
+			return branches.NewRenameBranchUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("rename_branch")
+		err = deps.Cataloger.RenameBranch(c.Context(), params.Repository, params.Branch, newName)
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			return branches.NewRenameBranchNotFound().WithPayload(responseError("repository or branch not found"))
+		case errors.Is(err, db.ErrAlreadyExists):
+			return branches.NewRenameBranchConflict().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrCommitJobInProgress),
+			errors.Is(err, catalog.ErrExportInProgress):
+			return branches.NewRenameBranchPreconditionFailed().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrInvalidValue) || errors.Is(err, catalog.ErrOperationNotPermitted):
+			return branches.NewRenameBranchBadRequest().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrRepositoryReadOnly):
+			return branches.NewRenameBranchDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		case err != nil:
+			return branches.NewRenameBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		deps.RecordActivity(&activity.Event{
+			Repository: params.Repository,
+			Type:       activity.EventTypeRenameBranch,
+			Actor:      user.ID,
+			Ref:        newName,
+			Message:    fmt.Sprintf("renamed from %s", params.Branch),
+		})
+		return branches.NewRenameBranchNoContent()
+	})
+}
+
 func (c *Controller) MergeMergeIntoBranchHandler() refs.MergeIntoBranchHandler {
 	return refs.MergeIntoBranchHandlerFunc(func(params refs.MergeIntoBranchParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -726,21 +2447,40 @@ func (c *Controller) MergeMergeIntoBranchHandler() refs.MergeIntoBranchHandler {
 			return refs.NewMergeIntoBranchUnauthorized().WithPayload(responseErrorFrom(err))
 		}
 		deps.LogAction("merge_branches")
-		userModel, err := deps.Auth.GetUser(user.ID)
-		if err != nil {
-			return refs.NewMergeIntoBranchUnauthorized().WithPayload(responseErrorFrom(err))
-		}
 		var message string
 		var metadata map[string]string
+		var mergeParams catalog.MergeParams
 		if params.Merge != nil {
 			message = params.Merge.Message
 			metadata = params.Merge.Metadata
+			mergeParams.Squash = params.Merge.Squash
+			mergeParams.Strategy = catalog.MergeStrategy(params.Merge.Strategy)
+			if params.Merge.ContentMerge {
+				repo, err := deps.Cataloger.GetRepository(c.Context(), params.Repository)
+				if errors.Is(err, db.ErrNotFound) {
+					return refs.NewMergeIntoBranchNotFound().WithPayload(responseErrorFrom(err))
+				}
+				if err != nil {
+					return refs.NewMergeIntoBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+				}
+				mergeParams.Resolve = contentmerge.NewResolveFunc(deps.BlockAdapter, repo.StorageNamespace)
+			}
+		}
+		res, err := c.merge(deps, user, params.Repository, params.SourceRef, params.DestinationRef, message, metadata, mergeParams)
+		if errors.Is(err, ErrAuthorization) {
+			return refs.NewMergeIntoBranchUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, hooks.ErrHookFailed) || errors.Is(err, hooks.ErrInvalidAction) ||
+			errors.Is(err, catalog.ErrValidationFailed) || errors.Is(err, catalog.ErrCommitJobInProgress) ||
+			errors.Is(err, catalog.ErrCommitLimitExceeded) {
+			return refs.NewMergeIntoBranchPreconditionFailed().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrFeatureNotSupported) || errors.Is(err, catalog.ErrInvalidValue) {
+			return refs.NewMergeIntoBranchDefault(http.StatusBadRequest).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrRepositoryReadOnly) || errors.Is(err, catalog.ErrQuotaExceeded) {
+			return refs.NewMergeIntoBranchForbidden().WithPayload(responseErrorFrom(err))
 		}
-		res, err := deps.Cataloger.Merge(c.Context(),
-			params.Repository, params.SourceRef, params.DestinationRef,
-			userModel.Username,
-			message,
-			metadata)
 
 		switch err {
 		case nil:
@@ -761,6 +2501,170 @@ func (c *Controller) MergeMergeIntoBranchHandler() refs.MergeIntoBranchHandler {
 	})
 }
 
+func (c *Controller) RefsMergePreviewHandler() refs.MergePreviewHandler {
+	return refs.MergePreviewHandlerFunc(func(params refs.MergePreviewParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ListObjectsAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return refs.NewMergePreviewUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("merge_preview")
+		preview, err := deps.Cataloger.MergePreview(c.Context(), params.Repository, params.SourceRef, params.DestinationRef)
+		if errors.Is(err, db.ErrNotFound) || errors.Is(err, catalog.ErrBranchNotFound) {
+			return refs.NewMergePreviewNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrCommitJobInProgress) {
+			return refs.NewMergePreviewPreconditionFailed().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return refs.NewMergePreviewDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		var summary models.MergePreviewSummary
+		for k, v := range preview.Summary {
+			val := int64(v)
+			switch k {
+			case catalog.DifferenceTypeAdded:
+				summary.Added = val
+			case catalog.DifferenceTypeChanged:
+				summary.Changed = val
+			case catalog.DifferenceTypeRemoved:
+				summary.Removed = val
+			case catalog.DifferenceTypeConflict:
+				summary.Conflict = val
+			}
+		}
+		conflicts := make([]*models.MergeConflict, len(preview.Conflicts))
+		for i, conflict := range preview.Conflicts {
+			conflicts[i] = &models.MergeConflict{
+				Path:            conflict.Path,
+				SourceType:      transformDifferenceTypeToString(conflict.SourceType),
+				DestinationType: transformDifferenceTypeToString(conflict.DestinationType),
+			}
+		}
+		return refs.NewMergePreviewOK().WithPayload(&models.MergePreview{
+			Summary:   &summary,
+			Conflicts: conflicts,
+		})
+	})
+}
+
+// merge merges sourceRef into destinationBranch as user, running the merge hooks of the
+// repository around it
+func (c *Controller) merge(deps *Dependencies, user *models.User, repository, sourceRef, destinationBranch, message string, metadata map[string]string, params catalog.MergeParams) (*catalog.MergeResult, error) {
+	userModel, err := deps.Auth.GetUser(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAuthorization, err)
+	}
+	event := &hooks.Event{
+		Type:          hooks.EventTypePreMerge,
+		Repository:    repository,
+		Branch:        destinationBranch,
+		SourceRef:     sourceRef,
+		CommitMessage: message,
+		Committer:     userModel.Username,
+		Metadata:      metadata,
+	}
+	if err := c.runHooks(deps, event); err != nil {
+		return nil, err
+	}
+	ctx := committerContext(c.Context(), deps)
+	if authorize(deps.Auth, user, []permissions.Permission{
+		{
+			Action:   permissions.ExemptCommitLimitsAction,
+			Resource: permissions.RepoArn(repository),
+		},
+	}) == nil {
+		ctx = catalog.WithCommitLimitsExempt(ctx)
+	}
+	res, err := deps.Cataloger.Merge(ctx,
+		repository, sourceRef, destinationBranch,
+		userModel.Username,
+		message,
+		event.Metadata,
+		params)
+	if err != nil {
+		return res, err
+	}
+	deps.RecordActivity(&activity.Event{
+		Repository: repository,
+		Type:       activity.EventTypeMerge,
+		Actor:      user.ID,
+		Ref:        destinationBranch,
+		Message:    fmt.Sprintf("merged %s into %s", sourceRef, res.Reference),
+	})
+	c.notifyProtectedBranchMerge(deps, repository, sourceRef, destinationBranch, user.ID, res.Reference)
+	if commit, err := deps.Cataloger.GetCommit(c.Context(), repository, res.Reference); err != nil {
+		deps.logger.WithError(err).WithField("reference", res.Reference).Warn("failed to get merge commit for post-merge hooks")
+	} else {
+		c.runPostHooks(deps, event, hooks.EventTypePostMerge, commit)
+	}
+	return res, nil
+}
+
+// runHooks runs the hooks configured for event and notifies when a hook fails it
+func (c *Controller) runHooks(deps *Dependencies, event *hooks.Event) error {
+	err := deps.Hooks.Run(c.Context(), event)
+	if errors.Is(err, hooks.ErrHookFailed) || errors.Is(err, hooks.ErrInvalidAction) {
+		body := fmt.Sprintf("%s by %s was rejected: %s", event.Type, event.Committer, err)
+		if event.IsPost() {
+			body = fmt.Sprintf("%s of %s by %s failed: %s", event.Type, event.SourceRef, event.Committer, err)
+		}
+		deps.Notifier.Notify(&notifications.Notification{
+			Type:       notifications.EventHookFailed,
+			Repository: event.Repository,
+			Branch:     event.Branch,
+			Subject:    fmt.Sprintf("%s hooks failed on %s@%s", event.Type, event.Repository, event.Branch),
+			Body:       body,
+		})
+	}
+	return err
+}
+
+// runPostHooks associates the runs of the pre event with commit, and runs the hooks of the
+// post event of its operation.  The operation was already performed, so failures are only
+// notified and recorded for a retry.
+func (c *Controller) runPostHooks(deps *Dependencies, pre *hooks.Event, postType hooks.EventType, commit *catalog.CommitLog) {
+	log := deps.logger.WithFields(logging.Fields{"event_type": postType, "reference": commit.Reference})
+	if err := deps.Hooks.SetCommitID(c.Context(), pre, commit.Reference); err != nil {
+		log.WithError(err).Warn("failed to set commit of hook runs")
+	}
+	event := *pre
+	event.ID = ""
+	event.Type = postType
+	event.SourceRef = commit.Reference
+	if len(commit.Parents) > 0 {
+		// the previous commit of the branch is the last parent
+		event.ParentRef = commit.Parents[len(commit.Parents)-1]
+	}
+	if err := c.runHooks(deps, &event); err != nil {
+		log.WithError(err).Warn("post hooks failed")
+	}
+}
+
+// notifyProtectedBranchMerge notifies about a merge into the repository default branch, the branch
+// the repository protects as its main line
+func (c *Controller) notifyProtectedBranchMerge(deps *Dependencies, repository, sourceRef, destinationBranch, actor, reference string) {
+	repo, err := deps.Cataloger.GetRepository(c.Context(), repository)
+	if err != nil {
+		deps.logger.WithError(err).WithField("repository", repository).Warn("failed to get repository for merge notification")
+		return
+	}
+	if repo.DefaultBranch != destinationBranch {
+		return
+	}
+	deps.Notifier.Notify(&notifications.Notification{
+		Type:       notifications.EventProtectedBranchMerge,
+		Repository: repository,
+		Branch:     destinationBranch,
+		Subject:    fmt.Sprintf("%s merged into %s@%s", sourceRef, repository, destinationBranch),
+		Body:       fmt.Sprintf("%s merged %s into %s, creating commit %s.", actor, sourceRef, destinationBranch, reference),
+	})
+}
+
 func newMergeResultFromCatalog(res *catalog.MergeResult) *models.MergeResult {
 	if res == nil {
 		return nil
@@ -785,6 +2689,26 @@ func newMergeResultFromCatalog(res *catalog.MergeResult) *models.MergeResult {
 	}
 }
 
+func newDiffCountsModel(counts *catalog.DiffCounts) *models.DiffCounts {
+	prefixes := make([]*models.DiffPrefixCounts, len(counts.Prefixes))
+	for i, p := range counts.Prefixes {
+		prefixes[i] = &models.DiffPrefixCounts{
+			Prefix:    swag.String(p.Prefix),
+			Added:     swag.Int64(int64(p.Added)),
+			Removed:   swag.Int64(int64(p.Removed)),
+			Changed:   swag.Int64(int64(p.Changed)),
+			Conflicts: swag.Int64(int64(p.Conflicts)),
+		}
+	}
+	return &models.DiffCounts{
+		Added:     swag.Int64(int64(counts.Added)),
+		Removed:   swag.Int64(int64(counts.Removed)),
+		Changed:   swag.Int64(int64(counts.Changed)),
+		Conflicts: swag.Int64(int64(counts.Conflicts)),
+		Prefixes:  prefixes,
+	}
+}
+
 func (c *Controller) BranchesDiffBranchHandler() branches.DiffBranchHandler {
 	return branches.DiffBranchHandlerFunc(func(params branches.DiffBranchParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -798,9 +2722,21 @@ func (c *Controller) BranchesDiffBranchHandler() branches.DiffBranchHandler {
 		}
 		deps.LogAction("diff_workspace")
 		cataloger := deps.Cataloger
+		if swag.BoolValue(params.SummaryOnly) {
+			counts, err := cataloger.DiffUncommittedCounts(c.Context(), params.Repository, params.Branch)
+			if err != nil {
+				return branches.NewDiffBranchDefault(http.StatusInternalServerError).
+					WithPayload(responseError("could not diff branch: %s", err))
+			}
+			return branches.NewDiffBranchOK().WithPayload(&branches.DiffBranchOKBody{
+				Results: []*models.Diff{},
+				Summary: newDiffCountsModel(counts),
+			})
+		}
 		limit := int(swag.Int64Value(params.Amount))
 		after := swag.StringValue(params.After)
-		diff, hasMore, err := cataloger.DiffUncommitted(c.Context(), params.Repository, params.Branch, limit, after)
+		prefix := swag.StringValue(params.Prefix)
+		diff, hasMore, err := cataloger.DiffUncommitted(c.Context(), params.Repository, params.Branch, prefix, limit, after)
 		if err != nil {
 			return branches.NewDiffBranchDefault(http.StatusInternalServerError).
 				WithPayload(responseError("could not diff branch: %s", err))
@@ -839,12 +2775,46 @@ func (c *Controller) RefsDiffRefsHandler() refs.DiffRefsHandler {
 		}
 		deps.LogAction("diff_refs")
 		cataloger := deps.Cataloger
+		uncommitted := swag.BoolValue(params.Uncommitted)
+		if uncommitted && swag.BoolValue(params.SummaryOnly) {
+			return refs.NewDiffRefsBadRequest().
+				WithPayload(responseError("summary only is not supported with uncommitted changes"))
+		}
+		if swag.BoolValue(params.SummaryOnly) {
+			counts, err := cataloger.DiffCounts(c.Context(), params.Repository, params.LeftRef, params.RightRef)
+			if errors.Is(err, catalog.ErrFeatureNotSupported) || errors.Is(err, catalog.ErrNonDirectNotSupported) {
+				return refs.NewDiffRefsDefault(http.StatusNotImplemented).WithPayload(responseError(err.Error()))
+			}
+			if err != nil {
+				return refs.NewDiffRefsDefault(http.StatusInternalServerError).
+					WithPayload(responseError("could not diff references: %s", err))
+			}
+			return refs.NewDiffRefsOK().WithPayload(&refs.DiffRefsOKBody{
+				Results: []*models.Diff{},
+				Summary: newDiffCountsModel(counts),
+			})
+		}
 		limit := int(swag.Int64Value(params.Amount))
 		after := swag.StringValue(params.After)
-		diff, hasMore, err := cataloger.Diff(c.Context(), params.Repository, params.LeftRef, params.RightRef, catalog.DiffParams{
+		diffParams := catalog.DiffParams{
 			Limit: limit,
 			After: after,
-		})
+		}
+		var (
+			diff    catalog.Differences
+			hasMore bool
+		)
+		if uncommitted {
+			diff, hasMore, err = cataloger.DiffBranches(c.Context(), params.Repository, params.LeftRef, params.RightRef, diffParams)
+		} else {
+			diff, hasMore, err = cataloger.Diff(c.Context(), params.Repository, params.LeftRef, params.RightRef, diffParams)
+		}
+		if uncommitted && errors.Is(err, catalog.ErrInvalidValue) {
+			return refs.NewDiffRefsBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if uncommitted && errors.Is(err, db.ErrNotFound) {
+			return refs.NewDiffRefsNotFound().WithPayload(responseErrorFrom(err))
+		}
 		if errors.Is(err, catalog.ErrFeatureNotSupported) {
 			return refs.NewDiffRefsDefault(http.StatusNotImplemented).WithPayload(responseError(err.Error()))
 		}
@@ -873,6 +2843,83 @@ func (c *Controller) RefsDiffRefsHandler() refs.DiffRefsHandler {
 	})
 }
 
+func (c *Controller) RefsDiffRefsSummaryHandler() refs.DiffRefsSummaryHandler {
+	return refs.DiffRefsSummaryHandlerFunc(func(params refs.DiffRefsSummaryParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ListObjectsAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return refs.NewDiffRefsSummaryUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("diff_refs_summary")
+		summary, err := deps.Cataloger.DiffSummary(c.Context(), params.Repository, params.LeftRef, params.RightRef)
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			return refs.NewDiffRefsSummaryNotFound().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrFeatureNotSupported):
+			return refs.NewDiffRefsSummaryDefault(http.StatusNotImplemented).WithPayload(responseError(err.Error()))
+		case err != nil:
+			return refs.NewDiffRefsSummaryDefault(http.StatusInternalServerError).
+				WithPayload(responseError("could not diff references: %s", err))
+		}
+		return refs.NewDiffRefsSummaryOK().WithPayload(&models.DiffSummary{
+			CommitsAhead:  int64(summary.CommitsAhead),
+			CommitsBehind: int64(summary.CommitsBehind),
+			Added:         int64(summary.Added),
+			Removed:       int64(summary.Removed),
+			Changed:       int64(summary.Changed),
+			Conflicts:     int64(summary.Conflicts),
+			BytesDelta:    summary.BytesDelta,
+		})
+	})
+}
+
+func (c *Controller) RefsDiffRefsChecksumsHandler() refs.DiffRefsChecksumsHandler {
+	return refs.DiffRefsChecksumsHandlerFunc(func(params refs.DiffRefsChecksumsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ListObjectsAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return refs.NewDiffRefsChecksumsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("diff_refs_checksums")
+		summary, err := deps.Cataloger.DiffChecksums(c.Context(), params.Repository, params.LeftRef, params.RightRef)
+		switch {
+		case errors.Is(err, catalog.ErrInvalidValue):
+			return refs.NewDiffRefsChecksumsBadRequest().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, db.ErrNotFound):
+			return refs.NewDiffRefsChecksumsNotFound().WithPayload(responseErrorFrom(err))
+		case err != nil:
+			return refs.NewDiffRefsChecksumsDefault(http.StatusInternalServerError).
+				WithPayload(responseError("could not diff references: %s", err))
+		}
+		return refs.NewDiffRefsChecksumsOK().WithPayload(&models.ChecksumDiffSummary{
+			Added:      swag.Int64(int64(summary.Added)),
+			Removed:    swag.Int64(int64(summary.Removed)),
+			Changed:    swag.Int64(int64(summary.Changed)),
+			BytesDelta: swag.Int64(summary.BytesDelta),
+			Identical:  swag.Bool(summary.Identical()),
+		})
+	})
+}
+
+// payloadETag returns a strong entity tag of a response payload: the hash of its JSON
+// encoding, which holds the checksums of the entries it describes
+func payloadETag(payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(data)
+	return httputil.ETag(hex.EncodeToString(h[:])), nil
+}
+
 func (c *Controller) ObjectsStatObjectHandler() objects.StatObjectHandler {
 	return objects.StatObjectHandlerFunc(func(params objects.StatObjectParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -898,17 +2945,146 @@ func (c *Controller) ObjectsStatObjectHandler() objects.StatObjectHandler {
 
 		// serialize entry
 		obj := &models.ObjectStats{
-			Checksum:  entry.Checksum,
-			Mtime:     entry.CreationDate.Unix(),
-			Path:      params.Path,
-			PathType:  models.ObjectStatsPathTypeObject,
-			SizeBytes: entry.Size,
+			Checksum:        entry.Checksum,
+			Mtime:           entry.CreationDate.Unix(),
+			Path:            params.Path,
+			PathType:        models.ObjectStatsPathTypeObject,
+			SizeBytes:       entry.Size,
+			ContentType:     entry.ContentType,
+			ContentEncoding: entry.ContentEncoding,
+			CacheControl:    entry.CacheControl,
 		}
 
 		if entry.Expired {
 			return objects.NewStatObjectGone().WithPayload(obj)
 		}
-		return objects.NewStatObjectOK().WithPayload(obj)
+		etag, err := payloadETag(obj)
+		if err != nil {
+			return objects.NewStatObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		if httputil.ETagMatch(swag.StringValue(params.IfNoneMatch), etag) {
+			return objects.NewStatObjectNotModified().WithETag(etag)
+		}
+		return objects.NewStatObjectOK().WithPayload(obj).WithETag(etag)
+	})
+}
+
+func (c *Controller) ObjectsPresignObjectHandler() objects.PresignObjectHandler {
+	return objects.PresignObjectHandlerFunc(func(params objects.PresignObjectParams, user *models.User) middleware.Responder {
+		method := swag.StringValue(params.Method)
+		action := permissions.ReadObjectAction
+		if method == http.MethodPut {
+			action = permissions.WriteObjectAction
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   action,
+				Resource: permissions.ObjectArn(params.Repository, params.Path),
+			},
+		})
+		if err != nil {
+			return objects.NewPresignObjectUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("presign_object")
+
+		// URLs are signed with the access key of the request, so revoking it revokes them
+		accessKeyID, _, _ := params.HTTPRequest.BasicAuth()
+		if accessKeyID == "" {
+			return objects.NewPresignObjectBadRequest().WithPayload(responseError("pre-signed URLs require authenticating with an access key"))
+		}
+		if deps.Gateway.Endpoint == "" {
+			return objects.NewPresignObjectDefault(http.StatusInternalServerError).WithPayload(responseError("S3 gateway endpoint is not configured"))
+		}
+		if method == http.MethodPut {
+			exists, err := deps.Cataloger.BranchExists(c.Context(), params.Repository, params.Ref)
+			if err != nil {
+				return objects.NewPresignObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+			}
+			if !exists {
+				return objects.NewPresignObjectNotFound().WithPayload(responseError("branch not found"))
+			}
+		}
+		creds, err := deps.Auth.GetCredentials(accessKeyID)
+		if err != nil {
+			return objects.NewPresignObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		objectURL, err := url.Parse(deps.Gateway.Endpoint)
+		if err != nil {
+			return objects.NewPresignObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		// the gateway serves path-style requests on its bare domain
+		objectURL.Path = strings.TrimSuffix(objectURL.Path, "/") + "/" + params.Repository + "/" + params.Ref + "/" + params.Path
+		expires := time.Duration(swag.Int64Value(params.Expires)) * time.Second
+		now := time.Now()
+		presigned, err := sig.V4Presign(creds, method, objectURL, deps.Gateway.Region, expires, now)
+		if err != nil {
+			return objects.NewPresignObjectBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		return objects.NewPresignObjectOK().WithPayload(&models.PresignedURL{
+			URL:       swag.String(presigned.String()),
+			ExpiresAt: swag.Int64(now.Add(expires).Unix()),
+		})
+	})
+}
+
+func (c *Controller) ObjectsStatObjectsHandler() objects.StatObjectsHandler {
+	return objects.StatObjectsHandlerFunc(func(params objects.StatObjectsParams, user *models.User) middleware.Responder {
+		refs := make([]catalog.EntryRef, len(params.Objects.Objects))
+		perms := make([]permissions.Permission, 0, len(refs))
+		permitted := make(map[string]bool)
+		for i, obj := range params.Objects.Objects {
+			refs[i] = catalog.EntryRef{
+				Reference: swag.StringValue(obj.Ref),
+				Path:      swag.StringValue(obj.Path),
+			}
+			if permitted[refs[i].Path] {
+				continue
+			}
+			permitted[refs[i].Path] = true
+			perms = append(perms, permissions.Permission{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, refs[i].Path),
+			})
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, perms)
+		if err != nil {
+			return objects.NewStatObjectsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("stat_objects")
+		cataloger := deps.Cataloger
+
+		entries, err := cataloger.GetEntries(c.Context(), params.Repository, refs, catalog.GetEntryParams{ReturnExpired: true})
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return objects.NewStatObjectsBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewStatObjectsNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewStatObjectsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		results := make([]*models.ObjectStatBatchResult, len(refs))
+		for i, entry := range entries {
+			result := &models.ObjectStatBatchResult{
+				Ref:   swag.String(refs[i].Reference),
+				Path:  swag.String(refs[i].Path),
+				Found: swag.Bool(entry != nil),
+			}
+			if entry != nil {
+				result.Expired = entry.Expired
+				result.Stats = &models.ObjectStats{
+					Checksum:  entry.Checksum,
+					Mtime:     entry.CreationDate.Unix(),
+					Path:      refs[i].Path,
+					PathType:  models.ObjectStatsPathTypeObject,
+					SizeBytes: entry.Size,
+				}
+			}
+			results[i] = result
+		}
+		return objects.NewStatObjectsOK().WithPayload(&models.ObjectStatBatchResultList{Results: results})
 	})
 }
 
@@ -991,10 +3167,17 @@ func (c *Controller) ObjectsGetObjectHandler() objects.GetObjectHandler {
 			return objects.NewGetObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
 		// setup response
+		etag := httputil.ETag(entry.Checksum)
+		if httputil.ETagMatch(swag.StringValue(params.IfNoneMatch), etag) {
+			return objects.NewGetObjectNotModified().WithETag(etag)
+		}
 		res := objects.NewGetObjectOK()
-		res.ETag = httputil.ETag(entry.Checksum)
+		res.ETag = etag
 		res.LastModified = httputil.HeaderTimestamp(entry.CreationDate)
 		res.ContentDisposition = fmt.Sprintf("filename=\"%s\"", filepath.Base(entry.Path))
+		res.ContentType = entry.ContentType
+		res.ContentEncoding = entry.ContentEncoding
+		res.CacheControl = entry.CacheControl
 
 		// build a response as a multi-reader
 		res.ContentLength = entry.Size
@@ -1002,10 +3185,150 @@ func (c *Controller) ObjectsGetObjectHandler() objects.GetObjectHandler {
 		if err != nil {
 			return objects.NewGetObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
-
-		// done
-		res.Payload = reader
-		return res
+
+		// done
+		res.Payload = reader
+		return res
+	})
+}
+
+func (c *Controller) ObjectsPreviewObjectHandler() objects.PreviewObjectHandler {
+	return objects.PreviewObjectHandlerFunc(func(params objects.PreviewObjectParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.Path),
+			},
+		})
+		if err != nil {
+			return objects.NewPreviewObjectUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("preview_object")
+		cataloger := deps.Cataloger
+
+		// read repo
+		repo, err := cataloger.GetRepository(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewPreviewObjectNotFound().WithPayload(responseError("resource not found"))
+		}
+		if err != nil {
+			return objects.NewPreviewObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		// read the FS entry
+		entry, err := cataloger.GetEntry(c.Context(), params.Repository, params.Ref, params.Path, catalog.GetEntryParams{ReturnExpired: true})
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewPreviewObjectNotFound().WithPayload(responseError("resource not found"))
+		}
+		if err != nil {
+			return objects.NewPreviewObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		if entry.Expired {
+			return objects.NewPreviewObjectGone().WithPayload(responseError("resource expired"))
+		}
+
+		// read the head of the object
+		maxBytes := swag.Int64Value(params.MaxBytes)
+		if maxBytes <= 0 || maxBytes > preview.MaxBytesLimit {
+			maxBytes = preview.DefaultMaxBytes
+		}
+		maxRows := int(swag.Int64Value(params.MaxRows))
+		if maxRows <= 0 || maxRows > preview.MaxRowsLimit {
+			maxRows = preview.DefaultMaxRows
+		}
+		var head []byte
+		readBytes := entry.Size
+		if readBytes > maxBytes {
+			readBytes = maxBytes
+		}
+		if readBytes > 0 {
+			reader, err := deps.BlockAdapter.GetRange(block.ObjectPointer{StorageNamespace: repo.StorageNamespace, Identifier: entry.PhysicalAddress}, 0, readBytes-1)
+			if err != nil {
+				return objects.NewPreviewObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+			}
+			head, err = ioutil.ReadAll(io.LimitReader(reader, readBytes))
+			_ = reader.Close()
+			if err != nil {
+				return objects.NewPreviewObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+			}
+		}
+
+		p := preview.Read(entry.Path, head, entry.Size > int64(len(head)), maxRows)
+		return objects.NewPreviewObjectOK().WithPayload(&models.ObjectPreview{
+			Path:        params.Path,
+			SizeBytes:   entry.Size,
+			ContentType: p.ContentType,
+			Format:      string(p.Format),
+			Truncated:   p.Truncated,
+			Text:        p.Text,
+			Rows:        p.Rows,
+			Records:     p.Records,
+		})
+	})
+}
+
+func (c *Controller) ObjectsGetObjectSchemaHandler() objects.GetObjectSchemaHandler {
+	return objects.GetObjectSchemaHandlerFunc(func(params objects.GetObjectSchemaParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.Path),
+			},
+		})
+		if err != nil {
+			return objects.NewGetObjectSchemaUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_object_schema")
+		cataloger := deps.Cataloger
+
+		// read repo
+		repo, err := cataloger.GetRepository(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewGetObjectSchemaNotFound().WithPayload(responseError("resource not found"))
+		}
+		if err != nil {
+			return objects.NewGetObjectSchemaDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		// read the FS entry
+		entry, err := cataloger.GetEntry(c.Context(), params.Repository, params.Ref, params.Path, catalog.GetEntryParams{ReturnExpired: true})
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewGetObjectSchemaNotFound().WithPayload(responseError("resource not found"))
+		}
+		if err != nil {
+			return objects.NewGetObjectSchemaDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		if entry.Expired {
+			return objects.NewGetObjectSchemaGone().WithPayload(responseError("resource expired"))
+		}
+
+		maxRows := int(swag.Int64Value(params.MaxRows))
+		if maxRows < 0 || maxRows > preview.MaxRowsLimit {
+			maxRows = preview.DefaultMaxRows
+		}
+		src := block.NewRangeReaderAt(deps.BlockAdapter, block.ObjectPointer{StorageNamespace: repo.StorageNamespace, Identifier: entry.PhysicalAddress}, entry.Size)
+		table, err := preview.ReadTable(entry.Path, src, maxRows)
+		if errors.Is(err, preview.ErrUnsupportedTableFormat) {
+			return objects.NewGetObjectSchemaBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewGetObjectSchemaDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		columns := make([]*models.TableColumn, len(table.Columns))
+		for i, column := range table.Columns {
+			columns[i] = &models.TableColumn{
+				Name: swag.String(column.Name),
+				Type: swag.String(column.Type),
+			}
+		}
+		return objects.NewGetObjectSchemaOK().WithPayload(&models.ObjectSchema{
+			Path:      params.Path,
+			SizeBytes: entry.Size,
+			Format:    string(table.Format),
+			RowCount:  table.RowCount,
+			Columns:   columns,
+			Sample:    table.Sample,
+		})
 	})
 }
 
@@ -1100,6 +3423,106 @@ func writeSymlinkToS3(params metadataop.CreateSymlinkParams, repo *catalog.Repos
 	return err
 }
 
+func (c *Controller) ObjectsGetObjectHistoryHandler() objects.GetObjectHistoryHandler {
+	return objects.GetObjectHistoryHandlerFunc(func(params objects.GetObjectHistoryParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.Path),
+			},
+		})
+		if err != nil {
+			return objects.NewGetObjectHistoryUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_object_history")
+		cataloger := deps.Cataloger
+
+		after, amount := getPaginationParams(params.After, params.Amount)
+		history, hasMore, err := cataloger.GetEntryHistory(c.Context(), params.Repository, params.Ref, params.Path, after, amount)
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return objects.NewGetObjectHistoryBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewGetObjectHistoryNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewGetObjectHistoryDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		records := make([]*models.ObjectHistoryRecord, len(history))
+		var lastID string
+		for i, record := range history {
+			records[i] = &models.ObjectHistoryRecord{
+				Commit: &models.Commit{
+					Committer:    record.Commit.Committer,
+					CreationDate: record.Commit.CreationDate.Unix(),
+					ID:           record.Commit.Reference,
+					Message:      record.Commit.Message,
+					Metadata:     record.Commit.Metadata,
+					Parents:      record.Commit.Parents,
+				},
+				Type:            swag.String(transformDifferenceTypeToString(record.Type)),
+				PhysicalAddress: record.PhysicalAddress,
+				Checksum:        record.Checksum,
+				SizeBytes:       record.Size,
+			}
+			lastID = record.Commit.Reference
+		}
+		payload := &objects.GetObjectHistoryOKBody{
+			Pagination: &models.Pagination{
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(records))),
+				MaxPerPage: swag.Int64(MaxResultsPerPage),
+			},
+			Results: records,
+		}
+		if hasMore {
+			payload.Pagination.NextOffset = lastID
+		}
+		return objects.NewGetObjectHistoryOK().WithPayload(payload)
+	})
+}
+
+func (c *Controller) ObjectsGetPrefixStatsHandler() objects.GetPrefixStatsHandler {
+	return objects.GetPrefixStatsHandlerFunc(func(params objects.GetPrefixStatsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ListObjectsAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return objects.NewGetPrefixStatsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_prefix_stats")
+		cataloger := deps.Cataloger
+
+		stats, err := cataloger.GetPrefixStats(c.Context(), params.Repository, params.Ref,
+			swag.StringValue(params.Prefix), int(swag.Int64Value(params.Depth)))
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return objects.NewGetPrefixStatsBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewGetPrefixStatsNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewGetPrefixStatsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		results := make([]*models.PrefixStats, len(stats))
+		for i, s := range stats {
+			results[i] = &models.PrefixStats{
+				Prefix:    swag.String(s.Prefix),
+				Objects:   swag.Int64(s.Objects),
+				SizeBytes: swag.Int64(s.SizeBytes),
+			}
+		}
+		return objects.NewGetPrefixStatsOK().WithPayload(&objects.GetPrefixStatsOKBody{
+			Results: results,
+		})
+	})
+}
+
 func (c *Controller) ObjectsListObjectsHandler() objects.ListObjectsHandler {
 	return objects.ListObjectsHandlerFunc(func(params objects.ListObjectsParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -1156,19 +3579,25 @@ func (c *Controller) ObjectsListObjectsHandler() objects.ListObjectsHandler {
 			}
 			lastID = entry.Path
 		}
-		returnValue := objects.NewListObjectsOK().WithPayload(&objects.ListObjectsOKBody{
+		payload := &objects.ListObjectsOKBody{
 			Pagination: &models.Pagination{
 				HasMore:    swag.Bool(hasMore),
 				Results:    swag.Int64(int64(len(objList))),
 				MaxPerPage: swag.Int64(MaxResultsPerPage),
 			},
 			Results: objList,
-		})
-
+		}
 		if hasMore {
-			returnValue.Payload.Pagination.NextOffset = lastID
+			payload.Pagination.NextOffset = lastID
 		}
-		return returnValue
+		etag, err := payloadETag(payload)
+		if err != nil {
+			return objects.NewListObjectsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		if httputil.ETagMatch(swag.StringValue(params.IfNoneMatch), etag) {
+			return objects.NewListObjectsNotModified().WithETag(etag)
+		}
+		return objects.NewListObjectsOK().WithPayload(payload).WithETag(etag)
 	})
 }
 
@@ -1207,6 +3636,14 @@ func (c *Controller) ObjectsUploadObjectHandler() objects.UploadObjectHandler {
 			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseError("failed extracting size from file"))
 		}
 		byteSize := file.Header.Size
+		// fail before storing the content, CreateEntry checks again
+		err = cataloger.CheckRepositoryQuota(c.Context(), repo.Name, 1, byteSize)
+		if errors.Is(err, catalog.ErrQuotaExceeded) {
+			return objects.NewUploadObjectDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
 
 		// read the content
 		blob, err := upload.WriteBlob(deps.BlockAdapter, repo.StorageNamespace, params.Content, byteSize, block.PutOpts{StorageClass: params.StorageClass})
@@ -1222,6 +3659,10 @@ func (c *Controller) ObjectsUploadObjectHandler() objects.UploadObjectHandler {
 			CreationDate:    writeTime,
 			Size:            blob.Size,
 			Checksum:        blob.Checksum,
+			// headers of the uploaded part are stored and served back on reads
+			ContentType:     file.Header.Header.Get("Content-Type"),
+			ContentEncoding: file.Header.Header.Get("Content-Encoding"),
+			CacheControl:    file.Header.Header.Get("Cache-Control"),
 		}
 		err = cataloger.CreateEntry(c.Context(), repo.Name, params.Branch, entry,
 			catalog.CreateEntryParams{
@@ -1233,15 +3674,211 @@ func (c *Controller) ObjectsUploadObjectHandler() objects.UploadObjectHandler {
 		if errors.Is(err, db.ErrNotFound) {
 			return objects.NewUploadObjectNotFound().WithPayload(responseErrorFrom(err))
 		}
+		if errors.Is(err, catalog.ErrQuotaExceeded) {
+			// the content is not referenced by any entry
+			removeErr := deps.BlockAdapter.Remove(block.ObjectPointer{StorageNamespace: repo.StorageNamespace, Identifier: blob.PhysicalAddress})
+			if removeErr != nil {
+				deps.logger.WithError(removeErr).WithField("physical_address", blob.PhysicalAddress).Warn("could not remove uploaded object")
+			}
+		}
+		if errors.Is(err, catalog.ErrQuotaExceeded) || errors.Is(err, catalog.ErrRepositoryReadOnly) {
+			return objects.NewUploadObjectDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrCommitJobInProgress) {
+			return objects.NewUploadObjectDefault(http.StatusConflict).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrInvalidMetadata) {
+			return objects.NewUploadObjectDefault(http.StatusBadRequest).WithPayload(responseErrorFrom(err))
+		}
 		if err != nil {
 			return objects.NewUploadObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
 		return objects.NewUploadObjectCreated().WithPayload(&models.ObjectStats{
-			Checksum:  blob.Checksum,
-			Mtime:     writeTime.Unix(),
-			Path:      params.Path,
-			PathType:  models.ObjectStatsPathTypeObject,
-			SizeBytes: blob.Size,
+			Checksum:        blob.Checksum,
+			Mtime:           writeTime.Unix(),
+			Path:            params.Path,
+			PathType:        models.ObjectStatsPathTypeObject,
+			SizeBytes:       blob.Size,
+			ContentType:     entry.ContentType,
+			ContentEncoding: entry.ContentEncoding,
+			CacheControl:    entry.CacheControl,
+		})
+	})
+}
+
+func (c *Controller) ObjectsDeleteObjectsHandler() objects.DeleteObjectsHandler {
+	return objects.DeleteObjectsHandlerFunc(func(params objects.DeleteObjectsParams, user *models.User) middleware.Responder {
+		paths := params.Paths.Paths
+		perms := make([]permissions.Permission, 0, len(paths))
+		permitted := make(map[string]bool)
+		for _, path := range paths {
+			if permitted[path] {
+				continue
+			}
+			permitted[path] = true
+			perms = append(perms, permissions.Permission{
+				Action:   permissions.DeleteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, path),
+			})
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, perms)
+		if err != nil {
+			return objects.NewDeleteObjectsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("delete_objects")
+		cataloger := deps.Cataloger
+
+		errs, err := cataloger.DeleteEntries(c.Context(), params.Repository, params.Branch, paths)
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return objects.NewDeleteObjectsBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewDeleteObjectsNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrRepositoryReadOnly) {
+			return objects.NewDeleteObjectsDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrCommitJobInProgress) {
+			return objects.NewDeleteObjectsDefault(http.StatusConflict).WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewDeleteObjectsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+
+		results := make([]*models.ObjectDeleteBatchResult, len(paths))
+		for i, path := range paths {
+			result := &models.ObjectDeleteBatchResult{
+				Path:    swag.String(path),
+				Deleted: swag.Bool(errs[i] == nil),
+			}
+			if errs[i] != nil {
+				result.Error = errs[i].Error()
+			}
+			results[i] = result
+		}
+		return objects.NewDeleteObjectsOK().WithPayload(&models.ObjectDeleteBatchResultList{Results: results})
+	})
+}
+
+func (c *Controller) ObjectsStageObjectsHandler() objects.StageObjectsHandler {
+	return objects.StageObjectsHandlerFunc(func(params objects.StageObjectsParams, user *models.User) middleware.Responder {
+		creations := params.Entries.Entries
+		perms := make([]permissions.Permission, 0, len(creations))
+		permitted := make(map[string]bool)
+		for _, creation := range creations {
+			path := swag.StringValue(creation.Path)
+			if permitted[path] {
+				continue
+			}
+			permitted[path] = true
+			perms = append(perms, permissions.Permission{
+				Action:   permissions.WriteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, path),
+			})
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, perms)
+		if err != nil {
+			return objects.NewStageObjectsUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("stage_objects")
+		cataloger := deps.Cataloger
+
+		schema, err := cataloger.GetMetadataSchema(c.Context(), params.Repository)
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewStageObjectsNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewStageObjectsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		writeTime := time.Now()
+		entries := make([]catalog.Entry, len(creations))
+		for i, creation := range creations {
+			entries[i] = catalog.Entry{
+				Path:            swag.StringValue(creation.Path),
+				PhysicalAddress: swag.StringValue(creation.PhysicalAddress),
+				CreationDate:    writeTime,
+				Size:            swag.Int64Value(creation.SizeBytes),
+				Checksum:        swag.StringValue(creation.Checksum),
+				Metadata:        creation.Metadata,
+			}
+			if err := schema.Check(entries[i].Path, entries[i].Metadata); err != nil {
+				return objects.NewStageObjectsBadRequest().WithPayload(responseErrorFrom(err))
+			}
+		}
+
+		err = cataloger.CreateEntries(c.Context(), params.Repository, params.Branch, entries)
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return objects.NewStageObjectsBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewStageObjectsNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrQuotaExceeded) || errors.Is(err, catalog.ErrRepositoryReadOnly) {
+			return objects.NewStageObjectsDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrCommitJobInProgress) {
+			return objects.NewStageObjectsDefault(http.StatusConflict).WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewStageObjectsDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return objects.NewStageObjectsNoContent()
+	})
+}
+
+func (c *Controller) ObjectsCopyObjectHandler() objects.CopyObjectHandler {
+	return objects.CopyObjectHandlerFunc(func(params objects.CopyObjectParams, user *models.User) middleware.Responder {
+		sourceRepository := params.Source.SourceRepository
+		if sourceRepository == "" {
+			sourceRepository = params.Repository
+		}
+		sourcePath := swag.StringValue(params.Source.SourcePath)
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(sourceRepository, sourcePath),
+			},
+			{
+				Action:   permissions.WriteObjectAction,
+				Resource: permissions.ObjectArn(params.Repository, params.Path),
+			},
+		})
+		if err != nil {
+			return objects.NewCopyObjectUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("copy_object")
+		cataloger := deps.Cataloger
+
+		entry, err := cataloger.CopyEntry(c.Context(), sourceRepository, swag.StringValue(params.Source.SourceRef), sourcePath,
+			params.Repository, params.Branch, params.Path, catalog.CopyEntryParams{
+				CopyObject: func(sourceNamespace, sourceAddress, destinationNamespace string) (string, error) {
+					return upload.CopyBlob(deps.BlockAdapter, sourceNamespace, sourceAddress, destinationNamespace)
+				},
+			})
+		if errors.Is(err, db.ErrNotFound) {
+			return objects.NewCopyObjectNotFound().WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrQuotaExceeded) || errors.Is(err, catalog.ErrRepositoryReadOnly) {
+			return objects.NewCopyObjectDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrCommitJobInProgress) {
+			return objects.NewCopyObjectDefault(http.StatusConflict).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrInvalidValue) || errors.Is(err, catalog.ErrInvalidMetadata) || errors.Is(err, catalog.ErrExpired) {
+			return objects.NewCopyObjectBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return objects.NewCopyObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
+		}
+		return objects.NewCopyObjectCreated().WithPayload(&models.ObjectStats{
+			Checksum:        entry.Checksum,
+			Mtime:           entry.CreationDate.Unix(),
+			Path:            entry.Path,
+			PathType:        models.ObjectStatsPathTypeObject,
+			SizeBytes:       entry.Size,
+			ContentType:     entry.ContentType,
+			ContentEncoding: entry.ContentEncoding,
+			CacheControl:    entry.CacheControl,
 		})
 	})
 }
@@ -1264,6 +3901,12 @@ func (c *Controller) ObjectsDeleteObjectHandler() objects.DeleteObjectHandler {
 		if errors.Is(err, db.ErrNotFound) {
 			return objects.NewDeleteObjectNotFound().WithPayload(responseError("resource not found"))
 		}
+		if errors.Is(err, catalog.ErrRepositoryReadOnly) {
+			return objects.NewDeleteObjectDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, catalog.ErrCommitJobInProgress) {
+			return objects.NewDeleteObjectDefault(http.StatusConflict).WithPayload(responseErrorFrom(err))
+		}
 		if err != nil {
 			return objects.NewDeleteObjectDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
@@ -1274,39 +3917,63 @@ func (c *Controller) ObjectsDeleteObjectHandler() objects.DeleteObjectHandler {
 
 func (c *Controller) RevertBranchHandler() branches.RevertBranchHandler {
 	return branches.RevertBranchHandlerFunc(func(params branches.RevertBranchParams, user *models.User) middleware.Responder {
-		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+		perms := []permissions.Permission{
 			{
 				Action:   permissions.RevertBranchAction,
 				Resource: permissions.BranchArn(params.Repository, params.Branch),
 			},
-		})
+		}
+		moveHead := swag.StringValue(params.Revert.Type) == models.RevertCreationTypeCommit
+		if moveHead {
+			// moving the head of a branch removes commits, it is allowed separately
+			perms = append(perms, permissions.Permission{
+				Action:   permissions.ResetBranchAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			})
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, perms)
 		if err != nil {
 			return branches.NewRevertBranchUnauthorized().WithPayload(responseErrorFrom(err))
 		}
 		deps.LogAction("revert_branch")
 		cataloger := deps.Cataloger
 
-		ctx := c.Context()
+		ctx := committerContext(c.Context(), deps)
 		switch swag.StringValue(params.Revert.Type) {
 		case models.RevertCreationTypeCommit:
-			err = cataloger.RollbackCommit(ctx, params.Repository, params.Revert.Commit)
+			err = cataloger.ResetBranch(ctx, params.Repository, params.Branch, params.Revert.Commit, !params.Revert.KeepUncommitted)
 		case models.RevertCreationTypeCommonPrefix:
 			err = cataloger.ResetEntries(ctx, params.Repository, params.Branch, params.Revert.Path)
 		case models.RevertCreationTypeReset:
-			err = cataloger.ResetBranch(ctx, params.Repository, params.Branch)
+			err = cataloger.ResetBranch(ctx, params.Repository, params.Branch, "", true)
 		case models.RevertCreationTypeObject:
 			err = cataloger.ResetEntry(ctx, params.Repository, params.Branch, params.Revert.Path)
 		default:
 			return branches.NewRevertBranchNotFound().
 				WithPayload(responseError("revert type not found"))
 		}
-		if errors.Is(err, db.ErrNotFound) {
+		switch {
+		case errors.Is(err, db.ErrNotFound):
 			return branches.NewRevertBranchNotFound().WithPayload(responseErrorFrom(err))
-		}
-		if err != nil {
+		case errors.Is(err, catalog.ErrOperationNotPermitted),
+			errors.Is(err, catalog.ErrCommitJobInProgress),
+			errors.Is(err, catalog.ErrExportInProgress):
+			return branches.NewRevertBranchPreconditionFailed().WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrUnsupportedRelation),
+			errors.Is(err, catalog.ErrInvalidValue):
+			return branches.NewRevertBranchDefault(http.StatusBadRequest).WithPayload(responseErrorFrom(err))
+		case errors.Is(err, catalog.ErrRepositoryReadOnly):
+			return branches.NewRevertBranchDefault(http.StatusForbidden).WithPayload(responseErrorFrom(err))
+		case err != nil:
 			return branches.NewRevertBranchDefault(http.StatusInternalServerError).WithPayload(responseErrorFrom(err))
 		}
-
+		deps.RecordActivity(&activity.Event{
+			Repository: params.Repository,
+			Type:       activity.EventTypeRevertBranch,
+			Actor:      user.ID,
+			Ref:        params.Branch,
+			Message:    strings.TrimSpace(fmt.Sprintf("revert %s %s%s", swag.StringValue(params.Revert.Type), params.Revert.Commit, params.Revert.Path)),
+		})
 		return branches.NewRevertBranchNoContent()
 	})
 }
@@ -1899,75 +4566,196 @@ func (c *Controller) CreateCredentialsHandler() authop.CreateCredentialsHandler
 		deps.LogAction("create_credentials")
 		credentials, err := deps.Auth.CreateCredentials(params.UserID)
 		if err != nil {
-			return authop.NewCreateCredentialsDefault(http.StatusInternalServerError).
+			return authop.NewCreateCredentialsDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+
+		return authop.NewCreateCredentialsCreated().
+			WithPayload(&models.CredentialsWithSecret{
+				AccessKeyID:     credentials.AccessKeyID,
+				AccessSecretKey: credentials.AccessSecretKey,
+				CreationDate:    credentials.IssuedDate.Unix(),
+			})
+	})
+}
+
+func (c *Controller) DeleteCredentialsHandler() authop.DeleteCredentialsHandler {
+	return authop.DeleteCredentialsHandlerFunc(func(params authop.DeleteCredentialsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.DeleteCredentialsAction,
+				Resource: permissions.UserArn(params.UserID),
+			},
+		})
+		if err != nil {
+			return authop.NewDeleteCredentialsUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+
+		deps.LogAction("delete_credentials")
+		err = deps.Auth.DeleteCredentials(params.UserID, params.AccessKeyID)
+		if errors.Is(err, db.ErrNotFound) {
+			return authop.NewDeleteCredentialsNotFound().
+				WithPayload(responseError("credentials not found"))
+		}
+		if err != nil {
+			return authop.NewDeleteCredentialsDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+
+		return authop.NewDeleteCredentialsNoContent()
+	})
+}
+
+func (c *Controller) GetCredentialsHandler() authop.GetCredentialsHandler {
+	return authop.GetCredentialsHandlerFunc(func(params authop.GetCredentialsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadCredentialsAction,
+				Resource: permissions.UserArn(params.UserID),
+			},
+		})
+		if err != nil {
+			return authop.NewGetCredentialsUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_credentials_for_user")
+		credentials, err := deps.Auth.GetCredentialsForUser(params.UserID, params.AccessKeyID)
+		if errors.Is(err, db.ErrNotFound) {
+			return authop.NewGetCredentialsNotFound().
+				WithPayload(responseError("credentials not found"))
+		}
+		if err != nil {
+			return authop.NewGetCredentialsDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+
+		return authop.NewGetCredentialsOK().
+			WithPayload(&models.Credentials{
+				AccessKeyID:  credentials.AccessKeyID,
+				CreationDate: credentials.IssuedDate.Unix(),
+			})
+	})
+}
+
+func notificationSubscriptionPayload(s *notifications.Subscription) *models.NotificationSubscription {
+	return &models.NotificationSubscription{
+		ID:           swag.Int64(s.ID),
+		Repository:   s.Repository,
+		Branch:       s.Branch,
+		EventType:    swag.String(string(s.EventType)),
+		Channel:      swag.String(s.Channel),
+		Address:      swag.String(s.Address),
+		CreationDate: swag.Int64(s.CreationDate.Unix()),
+	}
+}
+
+func (c *Controller) ListUserSubscriptionsHandler() authop.ListUserSubscriptionsHandler {
+	return authop.ListUserSubscriptionsHandlerFunc(func(params authop.ListUserSubscriptionsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ListSubscriptionsAction,
+				Resource: permissions.UserArn(params.UserID),
+			},
+		})
+		if err != nil {
+			return authop.NewListUserSubscriptionsUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+
+		deps.LogAction("list_user_subscriptions")
+		subscriptions, err := deps.Subscriptions.ListSubscriptions(c.Context(), params.UserID)
+		if err != nil {
+			return authop.NewListUserSubscriptionsDefault(http.StatusInternalServerError).
 				WithPayload(responseErrorFrom(err))
 		}
 
-		return authop.NewCreateCredentialsCreated().
-			WithPayload(&models.CredentialsWithSecret{
-				AccessKeyID:     credentials.AccessKeyID,
-				AccessSecretKey: credentials.AccessSecretKey,
-				CreationDate:    credentials.IssuedDate.Unix(),
+		response := make([]*models.NotificationSubscription, len(subscriptions))
+		for i, s := range subscriptions {
+			response[i] = notificationSubscriptionPayload(s)
+		}
+		return authop.NewListUserSubscriptionsOK().
+			WithPayload(&authop.ListUserSubscriptionsOKBody{
+				Results: response,
 			})
 	})
 }
 
-func (c *Controller) DeleteCredentialsHandler() authop.DeleteCredentialsHandler {
-	return authop.DeleteCredentialsHandlerFunc(func(params authop.DeleteCredentialsParams, user *models.User) middleware.Responder {
-		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+func (c *Controller) CreateUserSubscriptionHandler() authop.CreateUserSubscriptionHandler {
+	return authop.CreateUserSubscriptionHandlerFunc(func(params authop.CreateUserSubscriptionParams, user *models.User) middleware.Responder {
+		perms := []permissions.Permission{
 			{
-				Action:   permissions.DeleteCredentialsAction,
+				Action:   permissions.CreateSubscriptionAction,
 				Resource: permissions.UserArn(params.UserID),
 			},
-		})
+		}
+		// subscribers of a repository may only be notified about repositories they can read
+		if params.Subscription.Repository != "" {
+			perms = append(perms, permissions.Permission{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Subscription.Repository),
+			})
+		}
+		deps, err := c.setupRequest(user, params.HTTPRequest, perms)
 		if err != nil {
-			return authop.NewDeleteCredentialsUnauthorized().
+			return authop.NewCreateUserSubscriptionUnauthorized().
 				WithPayload(responseErrorFrom(err))
 		}
 
-		deps.LogAction("delete_credentials")
-		err = deps.Auth.DeleteCredentials(params.UserID, params.AccessKeyID)
-		if errors.Is(err, db.ErrNotFound) {
-			return authop.NewDeleteCredentialsNotFound().
-				WithPayload(responseError("credentials not found"))
+		deps.LogAction("create_user_subscription")
+		subscription := &notifications.Subscription{
+			Username:   params.UserID,
+			Repository: params.Subscription.Repository,
+			Branch:     params.Subscription.Branch,
+			EventType:  notifications.EventType(swag.StringValue(params.Subscription.EventType)),
+			Channel:    swag.StringValue(params.Subscription.Channel),
+			Address:    swag.StringValue(params.Subscription.Address),
 		}
-		if err != nil {
-			return authop.NewDeleteCredentialsDefault(http.StatusInternalServerError).
+		err = deps.Subscriptions.CreateSubscription(c.Context(), subscription)
+		switch {
+		case errors.Is(err, notifications.ErrUnknownEventType),
+			errors.Is(err, notifications.ErrInvalidChannel),
+			errors.Is(err, notifications.ErrInvalidAddress),
+			errors.Is(err, notifications.ErrInvalidBranch):
+			return authop.NewCreateUserSubscriptionBadRequest().
+				WithPayload(responseErrorFrom(err))
+		case errors.Is(err, db.ErrNotFound):
+			return authop.NewCreateUserSubscriptionNotFound().
+				WithPayload(responseError("user not found"))
+		case err != nil:
+			return authop.NewCreateUserSubscriptionDefault(http.StatusInternalServerError).
 				WithPayload(responseErrorFrom(err))
 		}
-
-		return authop.NewDeleteCredentialsNoContent()
+		return authop.NewCreateUserSubscriptionCreated().
+			WithPayload(notificationSubscriptionPayload(subscription))
 	})
 }
 
-func (c *Controller) GetCredentialsHandler() authop.GetCredentialsHandler {
-	return authop.GetCredentialsHandlerFunc(func(params authop.GetCredentialsParams, user *models.User) middleware.Responder {
+func (c *Controller) DeleteUserSubscriptionHandler() authop.DeleteUserSubscriptionHandler {
+	return authop.DeleteUserSubscriptionHandlerFunc(func(params authop.DeleteUserSubscriptionParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
 			{
-				Action:   permissions.ReadCredentialsAction,
+				Action:   permissions.DeleteSubscriptionAction,
 				Resource: permissions.UserArn(params.UserID),
 			},
 		})
 		if err != nil {
-			return authop.NewGetCredentialsUnauthorized().
+			return authop.NewDeleteUserSubscriptionUnauthorized().
 				WithPayload(responseErrorFrom(err))
 		}
-		deps.LogAction("get_credentials_for_user")
-		credentials, err := deps.Auth.GetCredentialsForUser(params.UserID, params.AccessKeyID)
+
+		deps.LogAction("delete_user_subscription")
+		err = deps.Subscriptions.DeleteSubscription(c.Context(), params.UserID, params.SubscriptionID)
 		if errors.Is(err, db.ErrNotFound) {
-			return authop.NewGetCredentialsNotFound().
-				WithPayload(responseError("credentials not found"))
+			return authop.NewDeleteUserSubscriptionNotFound().
+				WithPayload(responseError("subscription not found"))
 		}
 		if err != nil {
-			return authop.NewGetCredentialsDefault(http.StatusInternalServerError).
+			return authop.NewDeleteUserSubscriptionDefault(http.StatusInternalServerError).
 				WithPayload(responseErrorFrom(err))
 		}
 
-		return authop.NewGetCredentialsOK().
-			WithPayload(&models.Credentials{
-				AccessKeyID:  credentials.AccessKeyID,
-				CreationDate: credentials.IssuedDate.Unix(),
-			})
+		return authop.NewDeleteUserSubscriptionNoContent()
 	})
 }
 
@@ -2203,8 +4991,8 @@ func (c *Controller) ExportGetContinuousExportHandler() exportop.GetContinuousEx
 
 		deps.LogAction("get_continuous_export")
 
-		config, err := deps.Cataloger.GetExportConfigurationForBranch(params.Repository, params.Branch)
-		if errors.Is(err, catalog.ErrRepositoryNotFound) || errors.Is(err, catalog.ErrBranchNotFound) {
+		config, err := deps.Cataloger.GetExportConfigurationForBranch(params.HTTPRequest.Context(), params.Repository, params.Branch, swag.StringValue(params.Prefix))
+		if errors.Is(err, db.ErrNotFound) {
 			return exportop.NewGetContinuousExportNotFound().
 				WithPayload(responseErrorFrom(err))
 		}
@@ -2212,17 +5000,137 @@ func (c *Controller) ExportGetContinuousExportHandler() exportop.GetContinuousEx
 			return exportop.NewGetContinuousExportDefault(http.StatusInternalServerError).
 				WithPayload(responseErrorFrom(err))
 		}
+		return exportop.NewGetContinuousExportOK().WithPayload(exportConfigurationPayload(config))
+	})
+}
+
+func (c *Controller) ExportListContinuousExportsHandler() exportop.ListContinuousExportsHandler {
+	return exportop.ListContinuousExportsHandlerFunc(func(params exportop.ListContinuousExportsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadBranchAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return exportop.NewListContinuousExportsUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+
+		deps.LogAction("list_continuous_exports")
+
+		configs, err := deps.Cataloger.GetExportConfigurationsForBranch(params.HTTPRequest.Context(), params.Repository, params.Branch)
+		if errors.Is(err, db.ErrNotFound) {
+			return exportop.NewListContinuousExportsNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return exportop.NewListContinuousExportsDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		payload := make([]*models.ContinuousExportConfiguration, len(configs))
+		for i, config := range configs {
+			payload[i] = exportConfigurationPayload(config)
+		}
+		return exportop.NewListContinuousExportsOK().WithPayload(payload)
+	})
+}
+
+func (c *Controller) ExportListExportRunsHandler() exportop.ListExportRunsHandler {
+	return exportop.ListExportRunsHandlerFunc(func(params exportop.ListExportRunsParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadBranchAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return exportop.NewListExportRunsUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+
+		deps.LogAction("list_export_runs")
+
+		after, amount := getPaginationParams(params.After, params.Amount)
+		runs, hasMore, err := deps.Cataloger.GetExportRuns(params.HTTPRequest.Context(), params.Repository, params.Branch, amount, after)
+		if errors.Is(err, db.ErrNotFound) {
+			return exportop.NewListExportRunsNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return exportop.NewListExportRunsDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
 
-		payload := models.ContinuousExportConfiguration{
-			ExportPath:             strfmt.URI(config.Path),
-			ExportStatusPath:       strfmt.URI(config.StatusPath),
-			LastKeysInPrefixRegexp: config.LastKeysInPrefixRegexp,
-			IsContinuous:           config.IsContinuous,
+		results := make([]*models.ExportRun, len(runs))
+		var lastID string
+		for i, run := range runs {
+			startTime := strfmt.DateTime(run.StartTime)
+			results[i] = &models.ExportRun{
+				ExportID:       swag.String(run.ExportID),
+				FromRef:        run.FromRef,
+				ToRef:          swag.String(run.ToRef),
+				StartTime:      &startTime,
+				ObjectsCopied:  run.ObjectsCopied,
+				ObjectsDeleted: run.ObjectsDeleted,
+				BytesCopied:    run.BytesCopied,
+				Status:         swag.String(string(run.Status)),
+				ErrorMessage:   swag.StringValue(run.ErrorMessage),
+			}
+			if run.EndTime != nil {
+				results[i].EndTime = strfmt.DateTime(*run.EndTime)
+			}
+			for _, destination := range run.Destinations {
+				result := &models.ExportRunDestination{
+					Destination:  swag.String(destination.Destination),
+					Status:       swag.String(string(destination.Status)),
+					ErrorMessage: swag.StringValue(destination.ErrorMessage),
+				}
+				if destination.EndTime != nil {
+					result.EndTime = strfmt.DateTime(*destination.EndTime)
+				}
+				results[i].Destinations = append(results[i].Destinations, result)
+			}
+			lastID = run.ExportID
 		}
-		return exportop.NewGetContinuousExportOK().WithPayload(&payload)
+		returnValue := exportop.NewListExportRunsOK().WithPayload(&exportop.ListExportRunsOKBody{
+			Pagination: &models.Pagination{
+				HasMore:    swag.Bool(hasMore),
+				Results:    swag.Int64(int64(len(results))),
+				MaxPerPage: swag.Int64(MaxResultsPerPage),
+			},
+			Results: results,
+		})
+		if hasMore {
+			returnValue.Payload.Pagination.NextOffset = lastID
+		}
+		return returnValue
 	})
 }
 
+func exportConfigurationPayload(config catalog.ExportConfiguration) *models.ContinuousExportConfiguration {
+	return &models.ContinuousExportConfiguration{
+		Prefix:                 config.Prefix,
+		ExportPath:             strfmt.URI(config.Path),
+		ExportStatusPath:       strfmt.URI(config.StatusPath),
+		LastKeysInPrefixRegexp: config.LastKeysInPrefixRegexp,
+		IsContinuous:           config.IsContinuous,
+		Parallelism:            swag.Int64(int64(config.Parallelism)),
+		Mode:                   config.Mode,
+		MaxAttempts:            swag.Int64(int64(config.MaxAttempts)),
+		RetryBackoffMs:         swag.Int64(config.RetryBackoff.Milliseconds()),
+		WriteManifest:          config.WriteManifest,
+		Schedule:               config.Schedule,
+		IncludePrefixes:        config.IncludePrefixes,
+		ExcludeGlobs:           config.ExcludeGlobs,
+		Format:                 config.Format,
+		PropagateDeletes:       swag.Bool(config.PropagateDeletes),
+		AdditionalExportPaths:  config.AdditionalPaths,
+		SseAlgorithm:           config.SSEAlgorithm,
+		SseKmsKeyID:            config.SSEKMSKeyID,
+	}
+}
+
 func (c *Controller) ExportRunHandler() exportop.RunHandler {
 	return exportop.RunHandlerFunc(func(params exportop.RunParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -2236,15 +5144,98 @@ func (c *Controller) ExportRunHandler() exportop.RunHandler {
 				WithPayload(responseErrorFrom(err))
 		}
 		deps.LogAction("execute_single_export")
-		exportID, err := export.ExportBranchStart(deps.Parade, deps.Cataloger, params.Repository, params.Branch)
+		exportID, err := export.ExportBranchStart(params.HTTPRequest.Context(), deps.Parade, deps.Cataloger, params.Repository, params.Branch)
 		if err != nil {
 			return exportop.NewRunDefault(http.StatusInternalServerError).
 				WithPayload(responseErrorFrom(err))
 		}
+		deps.RecordActivity(&activity.Event{
+			Repository: params.Repository,
+			Type:       activity.EventTypeExport,
+			Actor:      user.ID,
+			Ref:        params.Branch,
+			Message:    fmt.Sprintf("started export %s", exportID),
+		})
 		return exportop.NewRunCreated().WithPayload(exportID)
 	})
 }
 
+func (c *Controller) ExportExportRefHandler() exportop.ExportRefHandler {
+	return exportop.ExportRefHandlerFunc(func(params exportop.ExportRefParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ExportConfigAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return exportop.NewExportRefUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("export_ref")
+		exportID, err := export.ExportRef(params.HTTPRequest.Context(), deps.Parade, deps.Cataloger, params.Repository, params.Ref, swag.StringValue(params.Export.Destination))
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return exportop.NewExportRefBadRequest().
+				WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return exportop.NewExportRefNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return exportop.NewExportRefDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		deps.RecordActivity(&activity.Event{
+			Repository: params.Repository,
+			Type:       activity.EventTypeExport,
+			Actor:      user.ID,
+			Ref:        params.Ref,
+			Message:    fmt.Sprintf("started export %s", exportID),
+		})
+		return exportop.NewExportRefCreated().WithPayload(exportID)
+	})
+}
+
+func (c *Controller) ExportGetRefExportHandler() exportop.GetRefExportHandler {
+	return exportop.GetRefExportHandlerFunc(func(params exportop.GetRefExportParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(params.Repository),
+			},
+		})
+		if err != nil {
+			return exportop.NewGetRefExportUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_ref_export")
+		refExport, err := deps.Cataloger.GetRefExport(params.HTTPRequest.Context(), params.Repository, params.ExportID)
+		if errors.Is(err, db.ErrNotFound) {
+			return exportop.NewGetRefExportNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return exportop.NewGetRefExportDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		startTime := strfmt.DateTime(refExport.StartTime)
+		payload := &models.RefExport{
+			ExportID:     swag.String(refExport.ExportID),
+			Ref:          swag.String(refExport.Ref),
+			CommitRef:    swag.String(refExport.CommitRef),
+			Destination:  swag.String(refExport.Destination),
+			StartTime:    &startTime,
+			Status:       swag.String(string(refExport.Status)),
+			ErrorMessage: swag.StringValue(refExport.ErrorMessage),
+		}
+		if refExport.EndTime != nil {
+			payload.EndTime = strfmt.DateTime(*refExport.EndTime)
+		}
+		return exportop.NewGetRefExportOK().WithPayload(payload)
+	})
+}
+
 func (c *Controller) ExportRepairHandler() exportop.RepairHandler {
 	return exportop.RepairHandlerFunc(func(params exportop.RepairParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -2259,14 +5250,163 @@ func (c *Controller) ExportRepairHandler() exportop.RepairHandler {
 		}
 		deps.LogAction("repair_export")
 
-		err = export.ExportBranchRepair(deps.Cataloger, params.Repository, params.Branch)
+		exportID, err := export.RepairExport(params.HTTPRequest.Context(), deps.Parade, deps.Cataloger, params.Repository, params.Branch)
+		if errors.Is(err, export.ErrRepairWrongStatus) || errors.Is(err, export.ErrRepairNoFailedRun) {
+			return exportop.NewRepairBadRequest().
+				WithPayload(responseErrorFrom(err))
+		}
+		if errors.Is(err, db.ErrNotFound) {
+			return exportop.NewRepairNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
 		if err != nil {
 			return exportop.NewRepairDefault(http.StatusInternalServerError).
 				WithPayload(responseErrorFrom(err))
 		}
-		return exportop.NewRepairCreated()
+		deps.RecordActivity(&activity.Event{
+			Repository: params.Repository,
+			Type:       activity.EventTypeExport,
+			Actor:      user.ID,
+			Ref:        params.Branch,
+			Message:    fmt.Sprintf("started repair export %s", exportID),
+		})
+		return exportop.NewRepairCreated().WithPayload(exportID)
+	})
+}
+func (c *Controller) ExportGetExportPlanHandler() exportop.GetExportPlanHandler {
+	return exportop.GetExportPlanHandlerFunc(func(params exportop.GetExportPlanParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadBranchAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return exportop.NewGetExportPlanUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_export_plan")
+
+		plan, err := export.ExportDryRun(params.HTTPRequest.Context(), deps.Cataloger, params.Repository, params.Branch,
+			swag.StringValue(params.Ref), int(swag.Int64Value(params.Amount)))
+		if errors.Is(err, db.ErrNotFound) {
+			return exportop.NewGetExportPlanNotFound().
+				WithPayload(responseErrorFrom(err))
+		}
+		if err != nil {
+			return exportop.NewGetExportPlanDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		operations := make([]*models.ExportPlanOperation, len(plan.Operations))
+		for i, op := range plan.Operations {
+			operations[i] = &models.ExportPlanOperation{
+				Action:      swag.String(exportPlanActions[op.Action]),
+				Source:      op.Source,
+				Destination: swag.String(op.Destination),
+			}
+		}
+		return exportop.NewGetExportPlanOK().WithPayload(&models.ExportPlan{
+			FromRef:       plan.FromRef,
+			ToRef:         swag.String(plan.ToRef),
+			Operations:    operations,
+			NumOperations: swag.Int64(int64(plan.NumOperations)),
+		})
+	})
+}
+
+// exportPlanActions maps export task actions to their names in export plans
+var exportPlanActions = map[string]string{
+	export.CopyAction:    "copy",
+	export.DeleteAction:  "delete",
+	export.TouchAction:   "touch",
+	export.SymlinkAction: "symlink",
+}
+
+func (c *Controller) ExportGetExportDriftHandler() exportop.GetExportDriftHandler {
+	return exportop.GetExportDriftHandlerFunc(func(params exportop.GetExportDriftParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadBranchAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return exportop.NewGetExportDriftUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("get_export_drift")
+
+		report, err := export.ExportBranchDrift(params.HTTPRequest.Context(), deps.Parade, deps.BlockAdapter, deps.ExportDestinations, deps.Cataloger, params.Repository, params.Branch, false)
+		switch {
+		case errors.Is(err, export.ErrExportInProgress) || errors.Is(err, export.ErrNotExported):
+			return exportop.NewGetExportDriftConflict().
+				WithPayload(responseErrorFrom(err))
+		case errors.Is(err, db.ErrNotFound):
+			return exportop.NewGetExportDriftNotFound().
+				WithPayload(responseErrorFrom(err))
+		case err != nil:
+			return exportop.NewGetExportDriftDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		return exportop.NewGetExportDriftOK().WithPayload(exportDriftReportPayload(report))
+	})
+}
+
+func (c *Controller) ExportReconcileExportDriftHandler() exportop.ReconcileExportDriftHandler {
+	return exportop.ReconcileExportDriftHandlerFunc(func(params exportop.ReconcileExportDriftParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.CreateCommitAction,
+				Resource: permissions.BranchArn(params.Repository, params.Branch),
+			},
+		})
+		if err != nil {
+			return exportop.NewReconcileExportDriftUnauthorized().
+				WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("reconcile_export_drift")
+
+		report, err := export.ExportBranchDrift(params.HTTPRequest.Context(), deps.Parade, deps.BlockAdapter, deps.ExportDestinations, deps.Cataloger, params.Repository, params.Branch, true)
+		switch {
+		case errors.Is(err, export.ErrExportInProgress) || errors.Is(err, export.ErrNotExported) || errors.Is(err, export.ErrConflictingRefs):
+			return exportop.NewReconcileExportDriftConflict().
+				WithPayload(responseErrorFrom(err))
+		case errors.Is(err, db.ErrNotFound):
+			return exportop.NewReconcileExportDriftNotFound().
+				WithPayload(responseErrorFrom(err))
+		case err != nil:
+			return exportop.NewReconcileExportDriftDefault(http.StatusInternalServerError).
+				WithPayload(responseErrorFrom(err))
+		}
+		if report.ExportID != "" {
+			deps.RecordActivity(&activity.Event{
+				Repository: params.Repository,
+				Type:       activity.EventTypeExport,
+				Actor:      user.ID,
+				Ref:        params.Branch,
+				Message:    fmt.Sprintf("started export %s re-exporting %d drifted objects", report.ExportID, len(report.Drifts)),
+			})
+		}
+		return exportop.NewReconcileExportDriftCreated().WithPayload(exportDriftReportPayload(report))
 	})
 }
+
+func exportDriftReportPayload(report *export.DriftReport) *models.ExportDriftReport {
+	drifts := make([]*models.ExportDrift, len(report.Drifts))
+	for i, drift := range report.Drifts {
+		drifts[i] = &models.ExportDrift{
+			Path:   swag.String(drift.Path),
+			Type:   swag.String(string(drift.Type)),
+			Prefix: drift.Prefix,
+		}
+	}
+	return &models.ExportDriftReport{
+		CommitRef: swag.String(report.CommitRef),
+		Drifts:    drifts,
+		ExportID:  report.ExportID,
+	}
+}
+
 func (c *Controller) ExportSetContinuousExportHandler() exportop.SetContinuousExportHandlerFunc {
 	return exportop.SetContinuousExportHandlerFunc(func(params exportop.SetContinuousExportParams, user *models.User) middleware.Responder {
 		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
@@ -2283,12 +5423,39 @@ func (c *Controller) ExportSetContinuousExportHandler() exportop.SetContinuousEx
 		deps.LogAction("set_continuous_export")
 
 		config := catalog.ExportConfiguration{
+			Prefix:                 params.Config.Prefix,
 			Path:                   params.Config.ExportPath.String(),
 			StatusPath:             params.Config.ExportStatusPath.String(),
 			LastKeysInPrefixRegexp: params.Config.LastKeysInPrefixRegexp,
 			IsContinuous:           params.Config.IsContinuous,
+			Parallelism:            int(swag.Int64Value(params.Config.Parallelism)),
+			Mode:                   params.Config.Mode,
+			MaxAttempts:            int(swag.Int64Value(params.Config.MaxAttempts)),
+			RetryBackoff:           time.Duration(swag.Int64Value(params.Config.RetryBackoffMs)) * time.Millisecond,
+			WriteManifest:          params.Config.WriteManifest,
+			Schedule:               params.Config.Schedule,
+			IncludePrefixes:        params.Config.IncludePrefixes,
+			ExcludeGlobs:           params.Config.ExcludeGlobs,
+			Format:                 params.Config.Format,
+			PropagateDeletes:       params.Config.PropagateDeletes == nil || *params.Config.PropagateDeletes,
+			AdditionalPaths:        params.Config.AdditionalExportPaths,
+			SSEAlgorithm:           params.Config.SseAlgorithm,
+			SSEKMSKeyID:            params.Config.SseKmsKeyID,
+		}
+		for _, path := range append([]string{config.StatusPath}, config.Paths()...) {
+			if path == "" {
+				continue
+			}
+			if err := export.ValidateDestination(path); err != nil {
+				return exportop.NewSetContinuousExportBadRequest().
+					WithPayload(responseErrorFrom(err))
+			}
+		}
+		err = deps.Cataloger.PutExportConfiguration(params.HTTPRequest.Context(), params.Repository, params.Branch, &config)
+		if errors.Is(err, catalog.ErrInvalidValue) {
+			return exportop.NewSetContinuousExportBadRequest().
+				WithPayload(responseErrorFrom(err))
 		}
-		err = deps.Cataloger.PutExportConfiguration(params.Repository, params.Branch, &config)
 		if errors.Is(err, catalog.ErrRepositoryNotFound) || errors.Is(err, catalog.ErrBranchNotFound) {
 			return exportop.NewSetContinuousExportNotFound().
 				WithPayload(responseErrorFrom(err))
@@ -2297,7 +5464,13 @@ func (c *Controller) ExportSetContinuousExportHandler() exportop.SetContinuousEx
 			return exportop.NewSetContinuousExportDefault(http.StatusInternalServerError).
 				WithPayload(responseErrorFrom(err))
 		}
-
+		deps.RecordActivity(&activity.Event{
+			Repository: params.Repository,
+			Type:       activity.EventTypePolicy,
+			Actor:      user.ID,
+			Ref:        params.Branch,
+			Message:    fmt.Sprintf("set export configuration to %s", config.Path),
+		})
 		return exportop.NewSetContinuousExportCreated()
 	})
 }
@@ -2345,6 +5518,12 @@ func (c *Controller) RetentionUpdateRetentionPolicyHandler() retentionop.UpdateR
 			return retentionop.NewUpdateRetentionPolicyDefault(http.StatusInternalServerError).
 				WithPayload(responseErrorFrom(err))
 		}
+		deps.RecordActivity(&activity.Event{
+			Repository: params.Repository,
+			Type:       activity.EventTypePolicy,
+			Actor:      user.ID,
+			Message:    "updated retention policy",
+		})
 		return retentionop.NewUpdateRetentionPolicyCreated()
 	})
 }
@@ -2367,3 +5546,43 @@ func (c *Controller) ConfigGetConfigHandler() configop.GetConfigHandler {
 		})
 	})
 }
+
+func (c *Controller) ConfigGetLoggingConfigHandler() configop.GetLoggingConfigHandler {
+	return configop.GetLoggingConfigHandlerFunc(func(params configop.GetLoggingConfigParams, user *models.User) middleware.Responder {
+		_, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.ReadConfigAction,
+				Resource: permissions.All,
+			},
+		})
+		if err != nil {
+			return configop.NewGetLoggingConfigUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		return configop.NewGetLoggingConfigOK().WithPayload(&models.LoggingConfig{
+			Level: swag.String(logging.Level()),
+		})
+	})
+}
+
+func (c *Controller) ConfigSetLoggingConfigHandler() configop.SetLoggingConfigHandler {
+	return configop.SetLoggingConfigHandlerFunc(func(params configop.SetLoggingConfigParams, user *models.User) middleware.Responder {
+		deps, err := c.setupRequest(user, params.HTTPRequest, []permissions.Permission{
+			{
+				Action:   permissions.WriteConfigAction,
+				Resource: permissions.All,
+			},
+		})
+		if err != nil {
+			return configop.NewSetLoggingConfigUnauthorized().WithPayload(responseErrorFrom(err))
+		}
+		deps.LogAction("set_logging_level")
+		level := swag.StringValue(params.Config.Level)
+		if err := logging.SetLevel(level); err != nil {
+			return configop.NewSetLoggingConfigBadRequest().WithPayload(responseErrorFrom(err))
+		}
+		deps.logger.WithField("level", level).Info("logging level changed")
+		return configop.NewSetLoggingConfigOK().WithPayload(&models.LoggingConfig{
+			Level: swag.String(logging.Level()),
+		})
+	})
+}
@@ -23,14 +23,20 @@ import (
 	"github.com/treeverse/lakefs/api/gen/client/commits"
 	"github.com/treeverse/lakefs/api/gen/client/config"
 	"github.com/treeverse/lakefs/api/gen/client/export"
+	hooksclient "github.com/treeverse/lakefs/api/gen/client/hooks"
 	"github.com/treeverse/lakefs/api/gen/client/objects"
+	"github.com/treeverse/lakefs/api/gen/client/organizations"
+	"github.com/treeverse/lakefs/api/gen/client/refs"
 	"github.com/treeverse/lakefs/api/gen/client/repositories"
 	"github.com/treeverse/lakefs/api/gen/client/retention"
 	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/gateway/sig"
+	"github.com/treeverse/lakefs/hooks"
 	"github.com/treeverse/lakefs/httputil"
+	"github.com/treeverse/lakefs/logging"
 	"github.com/treeverse/lakefs/testutil"
 	"github.com/treeverse/lakefs/upload"
 )
@@ -220,7 +226,7 @@ func TestHandler_CommitsGetBranchCommitLogHandler(t *testing.T) {
 				catalog.Entry{Path: p, PhysicalAddress: "bar" + n + "addr", CreationDate: time.Now(), Size: int64(i) + 1, Checksum: "cksum" + n},
 				catalog.CreateEntryParams{},
 			))
-			if _, err := deps.cataloger.Commit(ctx, "repo2", "master", "commit"+n, "some_user", nil); err != nil {
+			if _, err := deps.cataloger.Commit(ctx, "repo2", "master", "commit"+n, "some_user", nil, catalog.CommitParams{}); err != nil {
 				t.Fatalf("failed to commit '%s': %s", p, err)
 			}
 		}
@@ -236,6 +242,108 @@ func TestHandler_CommitsGetBranchCommitLogHandler(t *testing.T) {
 		if len(commitsLog) != expectedCommits {
 			t.Fatalf("Log %d commits, expected %d", len(commitsLog), expectedCommits)
 		}
+
+		resp, err = clt.Commits.GetBranchCommitLog(&commits.GetBranchCommitLogParams{
+			Branch:     "master",
+			Repository: "repo2",
+			Message:    swag.String("COMMIT2"),
+			Committer:  swag.String("some_user"),
+		}, bauth)
+		testutil.MustDo(t, "get filtered log of commits", err)
+		commitsLog = resp.GetPayload().Results
+		if len(commitsLog) != 1 || commitsLog[0].Message != "commit2" {
+			t.Errorf("filtered log %+v, expected commit2", commitsLog)
+		}
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := clt.Commits.GetBranchCommitLog(&commits.GetBranchCommitLogParams{
+			Branch:     "master",
+			Repository: "repo2",
+			Metadata:   []string{"no-value"},
+		}, bauth)
+		var badRequest *commits.GetBranchCommitLogBadRequest
+		if !errors.As(err, &badRequest) {
+			t.Errorf("filter by invalid metadata err=%v, expected bad request", err)
+		}
+	})
+}
+
+func TestHandler_CommitsGetBranchChangesHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.MustDo(t, "create repository", err)
+
+	resp, err := clt.Commits.GetBranchChanges(&commits.GetBranchChangesParams{
+		Branch:     "master",
+		Repository: "repo1",
+	}, bauth)
+	testutil.MustDo(t, "get initial token", err)
+	token := swag.StringValue(resp.GetPayload().Token)
+	if len(resp.GetPayload().Results) != 0 {
+		t.Fatalf("expected no commits without since, got %d", len(resp.GetPayload().Results))
+	}
+
+	const commitsLen = 3
+	for i := 0; i < commitsLen; i++ {
+		n := strconv.Itoa(i + 1)
+		testutil.MustDo(t, "create entry "+n, deps.cataloger.CreateEntry(ctx, "repo1", "master",
+			catalog.Entry{Path: "foo/bar" + n, PhysicalAddress: "bar" + n + "addr", CreationDate: time.Now(), Size: int64(i) + 1, Checksum: "cksum" + n},
+			catalog.CreateEntryParams{},
+		))
+		_, err := deps.cataloger.Commit(ctx, "repo1", "master", "commit"+n, "some_user", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit "+n, err)
+	}
+
+	t.Run("changes since token", func(t *testing.T) {
+		var messages []string
+		since := token
+		for {
+			resp, err := clt.Commits.GetBranchChanges(&commits.GetBranchChangesParams{
+				Branch:      "master",
+				Repository:  "repo1",
+				Since:       swag.String(since),
+				Amount:      swag.Int64(2),
+				DiffSummary: swag.Bool(true),
+			}, bauth)
+			testutil.MustDo(t, "get branch changes", err)
+			changes := resp.GetPayload()
+			for _, change := range changes.Results {
+				messages = append(messages, change.Commit.Message)
+				if change.DiffSummary == nil || change.DiffSummary.Added != 1 {
+					t.Errorf("commit %s diff summary %+v, expected 1 added object", change.Commit.Message, change.DiffSummary)
+				}
+			}
+			since = swag.StringValue(changes.Token)
+			if !swag.BoolValue(changes.HasMore) {
+				break
+			}
+		}
+		if diff := deep.Equal(messages, []string{"commit1", "commit2", "commit3"}); diff != nil {
+			t.Fatal("unexpected commits", diff)
+		}
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := clt.Commits.GetBranchChanges(&commits.GetBranchChangesParams{
+			Branch:     "master",
+			Repository: "repo1",
+			Since:      swag.String("not a token"),
+		}, bauth)
+		var badRequest *commits.GetBranchChangesBadRequest
+		if !errors.As(err, &badRequest) {
+			t.Fatalf("expected bad request, got %v", err)
+		}
 	})
 }
 
@@ -272,7 +380,7 @@ func TestHandler_GetCommitHandler(t *testing.T) {
 			catalog.Entry{Path: "foo/bar1", PhysicalAddress: "bar1addr", CreationDate: time.Now(), Size: 1, Checksum: "cksum1"},
 			catalog.CreateEntryParams{},
 		))
-		commit1, err := deps.cataloger.Commit(ctx, "foo1", "master", "some message", DefaultUserID, nil)
+		commit1, err := deps.cataloger.Commit(ctx, "foo1", "master", "some message", DefaultUserID, nil, catalog.CommitParams{})
 		testutil.Must(t, err)
 		reference1, err := deps.cataloger.GetBranchReference(ctx, "foo1", "master")
 		if err != nil {
@@ -346,6 +454,228 @@ func TestHandler_CommitHandler(t *testing.T) {
 			t.Fatalf("unexpected error on commit: %s", err)
 		}
 	})
+
+	t.Run("commit failed by hook", func(t *testing.T) {
+		const action = `name: reject
+on:
+  pre-commit:
+hooks:
+  - id: reject_all
+    type: lua
+    properties:
+      script: error("rejected " .. action.branch)
+`
+		_, err := clt.Objects.UploadObject(&objects.UploadObjectParams{
+			Branch:     "master",
+			Content:    runtime.NamedReader("content", bytes.NewBufferString(action)),
+			Path:       hooks.ActionsPrefix + "reject.yaml",
+			Repository: "foo1",
+		}, bauth)
+		testutil.MustDo(t, "upload action", err)
+		_, err = clt.Commits.Commit(&commits.CommitParams{
+			Branch: "master",
+			Commit: &models.CommitCreation{
+				Message: swag.String("some message"),
+			},
+			Repository: "foo1",
+		}, bauth)
+		var preconditionFailed *commits.CommitPreconditionFailed
+		if !errors.As(err, &preconditionFailed) {
+			t.Fatalf("expected precondition failed on commit, got %v", err)
+		}
+		if !strings.Contains(preconditionFailed.Payload.Message, "rejected master") {
+			t.Fatalf("expected hook error message, got %s", preconditionFailed.Payload.Message)
+		}
+	})
+}
+
+func TestHandler_HookRuns(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "s3://repo1", "master")
+	testutil.MustDo(t, "create repo repo1", err)
+	const action = `name: post
+on:
+  post-commit:
+hooks:
+  - id: fail_post
+    type: lua
+    properties:
+      script: error("post commit failed " .. action.source_ref)
+`
+	_, err = clt.Objects.UploadObject(&objects.UploadObjectParams{
+		Branch:     "master",
+		Content:    runtime.NamedReader("content", bytes.NewBufferString(action)),
+		Path:       hooks.ActionsPrefix + "post.yaml",
+		Repository: "repo1",
+	}, bauth)
+	testutil.MustDo(t, "upload action", err)
+	commitResp, err := clt.Commits.Commit(&commits.CommitParams{
+		Branch: "master",
+		Commit: &models.CommitCreation{
+			Message: swag.String("add post commit action"),
+		},
+		Repository: "repo1",
+	}, bauth)
+	testutil.MustDo(t, "commit with failing post hook", err)
+	commitID := commitResp.GetPayload().ID
+
+	var runID int64
+	t.Run("list runs of commit", func(t *testing.T) {
+		resp, err := clt.Hooks.ListHookRuns(&hooksclient.ListHookRunsParams{
+			Repository: "repo1",
+			Commit:     swag.String(commitID),
+		}, bauth)
+		testutil.MustDo(t, "list hook runs", err)
+		runs := resp.GetPayload().Results
+		if len(runs) != 1 {
+			t.Fatalf("expected 1 hook run, got %d", len(runs))
+		}
+		run := runs[0]
+		if swag.StringValue(run.EventType) != string(hooks.EventTypePostCommit) ||
+			swag.StringValue(run.HookID) != "fail_post" ||
+			swag.StringValue(run.Status) != string(hooks.RunStatusFailed) {
+			t.Fatalf("unexpected hook run %+v", run)
+		}
+		if !strings.Contains(run.Output, "post commit failed "+commitID) {
+			t.Fatalf("expected hook error output, got %s", run.Output)
+		}
+		runID, err = strconv.ParseInt(swag.StringValue(run.ID), 10, 64)
+		testutil.MustDo(t, "parse run id", err)
+	})
+
+	t.Run("list completed runs", func(t *testing.T) {
+		resp, err := clt.Hooks.ListHookRuns(&hooksclient.ListHookRunsParams{
+			Repository: "repo1",
+			Status:     swag.String(string(hooks.RunStatusCompleted)),
+		}, bauth)
+		testutil.MustDo(t, "list hook runs", err)
+		if len(resp.GetPayload().Results) != 0 {
+			t.Fatalf("expected no completed runs, got %d", len(resp.GetPayload().Results))
+		}
+	})
+
+	t.Run("retry failed run", func(t *testing.T) {
+		resp, err := clt.Hooks.RetryHookRun(&hooksclient.RetryHookRunParams{
+			Repository: "repo1",
+			RunID:      runID,
+		}, bauth)
+		testutil.MustDo(t, "retry hook run", err)
+		run := resp.GetPayload()
+		if swag.Int64Value(run.Attempt) != 2 || run.CommitID != commitID {
+			t.Fatalf("unexpected retried hook run %+v", run)
+		}
+	})
+
+	t.Run("get missing run", func(t *testing.T) {
+		_, err := clt.Hooks.GetHookRun(&hooksclient.GetHookRunParams{
+			Repository: "repo1",
+			RunID:      runID + 100,
+		}, bauth)
+		var notFound *hooksclient.GetHookRunNotFound
+		if !errors.As(err, &notFound) {
+			t.Fatalf("expected not found, got %v", err)
+		}
+	})
+}
+
+func TestHandler_DryRunHooks(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "s3://repo1", "master")
+	testutil.MustDo(t, "create repo repo1", err)
+	const action = `name: pre
+on:
+  pre-commit:
+hooks:
+  - id: pass
+    type: lua
+    properties:
+      script: print("ok")
+  - id: fail
+    type: lua
+    properties:
+      script: error("blocked " .. action.commit_message)
+  - id: after_fail
+    type: lua
+    properties:
+      script: print("ok")
+`
+	_, err = clt.Objects.UploadObject(&objects.UploadObjectParams{
+		Branch:     "master",
+		Content:    runtime.NamedReader("content", bytes.NewBufferString(action)),
+		Path:       hooks.ActionsPrefix + "pre.yaml",
+		Repository: "repo1",
+	}, bauth)
+	testutil.MustDo(t, "upload action", err)
+
+	t.Run("pre-commit", func(t *testing.T) {
+		resp, err := clt.Hooks.DryRunHooks(&hooksclient.DryRunHooksParams{
+			Repository: "repo1",
+			Event: &models.HookDryRun{
+				EventType:     swag.String(string(hooks.EventTypePreCommit)),
+				Branch:        swag.String("master"),
+				CommitMessage: "test message",
+			},
+		}, bauth)
+		testutil.MustDo(t, "dry run hooks", err)
+		runs := resp.GetPayload().Results
+		expected := []string{string(hooks.RunStatusCompleted), string(hooks.RunStatusFailed), string(hooks.RunStatusCompleted)}
+		if len(runs) != len(expected) {
+			t.Fatalf("expected %d hook runs, got %d", len(expected), len(runs))
+		}
+		for i, run := range runs {
+			if swag.StringValue(run.Status) != expected[i] {
+				t.Errorf("run %d of hook %s status %s, expected %s", i, swag.StringValue(run.HookID), swag.StringValue(run.Status), expected[i])
+			}
+		}
+		if !strings.Contains(runs[1].Output, "blocked test message") {
+			t.Errorf("expected hook error output, got %s", runs[1].Output)
+		}
+	})
+
+	t.Run("runs not recorded", func(t *testing.T) {
+		resp, err := clt.Hooks.ListHookRuns(&hooksclient.ListHookRunsParams{
+			Repository: "repo1",
+		}, bauth)
+		testutil.MustDo(t, "list hook runs", err)
+		if len(resp.GetPayload().Results) != 0 {
+			t.Fatalf("expected no recorded runs, got %d", len(resp.GetPayload().Results))
+		}
+	})
+
+	t.Run("post-commit without parent", func(t *testing.T) {
+		_, err := clt.Hooks.DryRunHooks(&hooksclient.DryRunHooksParams{
+			Repository: "repo1",
+			Event: &models.HookDryRun{
+				EventType: swag.String(string(hooks.EventTypePostCommit)),
+				Branch:    swag.String("master"),
+				SourceRef: "master",
+			},
+		}, bauth)
+		var badRequest *hooksclient.DryRunHooksBadRequest
+		if !errors.As(err, &badRequest) {
+			t.Fatalf("expected bad request, got %v", err)
+		}
+	})
 }
 
 func TestHandler_CreateRepositoryHandler(t *testing.T) {
@@ -707,6 +1037,80 @@ func TestHandler_DeleteBranchHandler(t *testing.T) {
 	})
 }
 
+func TestHandler_ObjectsPresignObjectHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("presign get", func(t *testing.T) {
+		before := time.Now()
+		resp, err := clt.Objects.PresignObject(&objects.PresignObjectParams{
+			Ref:        "master",
+			Path:       "foo/bar",
+			Repository: "repo1",
+			Expires:    swag.Int64(60),
+		}, bauth)
+		if err != nil {
+			t.Fatalf("unexpected error presigning: %s", err)
+		}
+		expiresAt := swag.Int64Value(resp.Payload.ExpiresAt)
+		if expiresAt < before.Add(time.Minute).Unix() || expiresAt > time.Now().Add(time.Minute).Unix() {
+			t.Errorf("expected URL to expire in a minute, got expiry at %d", expiresAt)
+		}
+		const expectedPrefix = testGatewayEndpoint + "/repo1/master/foo/bar?"
+		presignedURL := swag.StringValue(resp.Payload.URL)
+		if !strings.HasPrefix(presignedURL, expectedPrefix) {
+			t.Fatalf("expected URL starting with %s, got %s", expectedPrefix, presignedURL)
+		}
+		req, err := http.NewRequest(http.MethodGet, presignedURL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		authenticator := sig.NewV4Authenticator(req)
+		if _, err := authenticator.Parse(); err != nil {
+			t.Fatalf("parse presigned URL: %s", err)
+		}
+		if err := authenticator.Verify(creds, ""); err != nil {
+			t.Errorf("verify presigned URL: %s", err)
+		}
+	})
+
+	t.Run("presign put on missing branch", func(t *testing.T) {
+		_, err := clt.Objects.PresignObject(&objects.PresignObjectParams{
+			Ref:        "no-such-branch",
+			Path:       "foo/bar",
+			Repository: "repo1",
+			Method:     swag.String(http.MethodPut),
+		}, bauth)
+		var notFound *objects.PresignObjectNotFound
+		if !errors.As(err, &notFound) {
+			t.Errorf("expected not found presigning an upload to a missing branch, got %v", err)
+		}
+	})
+
+	t.Run("presign for too long", func(t *testing.T) {
+		_, err := clt.Objects.PresignObject(&objects.PresignObjectParams{
+			Ref:        "master",
+			Path:       "foo/bar",
+			Repository: "repo1",
+			Expires:    swag.Int64(8 * 24 * 60 * 60),
+		}, bauth)
+		if err == nil {
+			t.Error("expected an error presigning for more than a week")
+		}
+	})
+}
+
 func TestHandler_ObjectsStatObjectHandler(t *testing.T) {
 	handler, deps := getHandler(t, "")
 
@@ -764,6 +1168,43 @@ func TestHandler_ObjectsStatObjectHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("get object stats not modified", func(t *testing.T) {
+		resp, err := clt.Objects.StatObject(&objects.StatObjectParams{
+			Ref:        "master",
+			Path:       "foo/bar",
+			Repository: "repo1",
+		}, bauth)
+		if err != nil {
+			t.Fatalf("did not expect error for stat, got %s", err)
+		}
+		if resp.ETag == "" {
+			t.Fatal("expected stat response ETag")
+		}
+		_, err = clt.Objects.StatObject(&objects.StatObjectParams{
+			Ref:         "master",
+			Path:        "foo/bar",
+			Repository:  "repo1",
+			IfNoneMatch: swag.String(resp.ETag),
+		}, bauth)
+		notModified, ok := err.(*objects.StatObjectNotModified)
+		if !ok {
+			t.Fatalf("expected StatObjectNotModified, got %v", err)
+		}
+		if notModified.ETag != resp.ETag {
+			t.Fatalf("expected ETag %s, got %s", resp.ETag, notModified.ETag)
+		}
+
+		_, err = clt.Objects.StatObject(&objects.StatObjectParams{
+			Ref:         "master",
+			Path:        "foo/bar",
+			Repository:  "repo1",
+			IfNoneMatch: swag.String(`"another_etag"`),
+		}, bauth)
+		if err != nil {
+			t.Fatalf("did not expect error for stat with another ETag, got %s", err)
+		}
+	})
+
 	t.Run("get expired object stats", func(t *testing.T) {
 		testutil.Must(t,
 			deps.cataloger.CreateEntry(ctx, "repo1", "master", catalog.Entry{
@@ -795,6 +1236,47 @@ func TestHandler_ObjectsStatObjectHandler(t *testing.T) {
 			t.Fatalf("expected correct size, got %d", gone.Payload.SizeBytes)
 		}
 	})
+
+	t.Run("get batch object stats", func(t *testing.T) {
+		resp, err := clt.Objects.StatObjects(&objects.StatObjectsParams{
+			Repository: "repo1",
+			Objects: &models.ObjectStatBatchRequest{
+				Objects: []*models.ObjectStatRef{
+					{Ref: swag.String("master"), Path: swag.String("foo/bar")},
+					{Ref: swag.String("master:HEAD"), Path: swag.String("foo/bar")},
+					{Ref: swag.String("master"), Path: swag.String("foo/expired")},
+				},
+			},
+		}, bauth)
+		if err != nil {
+			t.Fatalf("did not expect error for batch stat, got %s", err)
+		}
+		results := resp.Payload.Results
+		if len(results) != 3 {
+			t.Fatalf("expected 3 results, got %d", len(results))
+		}
+		if !swag.BoolValue(results[0].Found) || results[0].Stats.SizeBytes != 666 {
+			t.Fatalf("expected foo/bar stats on master, got %+v", results[0])
+		}
+		if swag.BoolValue(results[1].Found) {
+			t.Fatalf("expected foo/bar not found on master:HEAD, got %+v", results[1])
+		}
+		if !swag.BoolValue(results[2].Found) || !results[2].Expired {
+			t.Fatalf("expected foo/expired to be expired, got %+v", results[2])
+		}
+
+		_, err = clt.Objects.StatObjects(&objects.StatObjectsParams{
+			Repository: "repo1",
+			Objects: &models.ObjectStatBatchRequest{
+				Objects: []*models.ObjectStatRef{
+					{Ref: swag.String("no_such_branch"), Path: swag.String("foo/bar")},
+				},
+			},
+		}, bauth)
+		if _, ok := err.(*objects.StatObjectsNotFound); !ok {
+			t.Fatalf("expected StatObjectsNotFound for a missing branch, got %v", err)
+		}
+	})
 }
 
 func TestHandler_ObjectsListObjectsHandler(t *testing.T) {
@@ -871,6 +1353,41 @@ func TestHandler_ObjectsListObjectsHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("get object list not modified", func(t *testing.T) {
+		resp, err := clt.Objects.ListObjects(&objects.ListObjectsParams{
+			Ref:        "master",
+			Repository: "repo1",
+			Prefix:     swag.String("foo/"),
+		}, basicAuth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.ETag == "" {
+			t.Fatal("expected list response ETag")
+		}
+		_, err = clt.Objects.ListObjects(&objects.ListObjectsParams{
+			Ref:         "master",
+			Repository:  "repo1",
+			Prefix:      swag.String("foo/"),
+			IfNoneMatch: swag.String(resp.ETag),
+		}, basicAuth)
+		if _, ok := err.(*objects.ListObjectsNotModified); !ok {
+			t.Fatalf("expected ListObjectsNotModified, got %v", err)
+		}
+
+		// a different listing has a different ETag
+		_, err = clt.Objects.ListObjects(&objects.ListObjectsParams{
+			Amount:      swag.Int64(2),
+			Ref:         "master",
+			Repository:  "repo1",
+			Prefix:      swag.String("foo/"),
+			IfNoneMatch: swag.String(resp.ETag),
+		}, basicAuth)
+		if err != nil {
+			t.Fatalf("did not expect error for a different listing, got %s", err)
+		}
+	})
+
 	t.Run("get object list paginated", func(t *testing.T) {
 		resp, err := clt.Objects.ListObjects(&objects.ListObjectsParams{
 			Amount:     swag.Int64(2),
@@ -973,6 +1490,26 @@ func TestHandler_ObjectsGetObjectHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("get object not modified", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		_, err := clt.Objects.GetObject(&objects.GetObjectParams{
+			Ref:         "master",
+			Path:        "foo/bar",
+			Repository:  "repo1",
+			IfNoneMatch: swag.String(`W/"3c4838fe975c762ee97cf39fbbe566f1"`),
+		}, bauth, buf)
+		notModified, ok := err.(*objects.GetObjectNotModified)
+		if !ok {
+			t.Fatalf("expected GetObjectNotModified, got %v", err)
+		}
+		if notModified.ETag != `"3c4838fe975c762ee97cf39fbbe566f1"` {
+			t.Fatalf("got unexpected etag: %s", notModified.ETag)
+		}
+		if buf.Len() != 0 {
+			t.Fatalf("expected no body, got %d bytes", buf.Len())
+		}
+	})
+
 	t.Run("get properties", func(t *testing.T) {
 		properties, err := clt.Objects.GetUnderlyingProperties(&objects.GetUnderlyingPropertiesParams{
 			Ref:        "master",
@@ -1002,9 +1539,10 @@ func TestHandler_ObjectsGetObjectHandler(t *testing.T) {
 	})
 }
 
-func TestHandler_ObjectsUploadObjectHandler(t *testing.T) {
+func TestHandler_ObjectsPreviewObjectHandler(t *testing.T) {
 	handler, deps := getHandler(t, "")
 
+	ctx := context.Background()
 	// create user
 	creds := createDefaultAdminUser(deps.auth, t)
 	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
@@ -1012,26 +1550,163 @@ func TestHandler_ObjectsUploadObjectHandler(t *testing.T) {
 	// setup client
 	clt := client.Default
 	clt.SetTransport(&handlerTransport{Handler: handler})
-	ctx := context.Background()
 	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	t.Run("upload object", func(t *testing.T) {
-		buf := new(bytes.Buffer)
-		buf.WriteString("hello world this is my awesome content")
-		resp, err := clt.Objects.UploadObject(&objects.UploadObjectParams{
-			Branch:     "master",
-			Content:    runtime.NamedReader("content", buf),
-			Path:       "foo/bar",
-			Repository: "repo1",
-		}, bauth)
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		if resp.Payload.SizeBytes != 38 {
+	const content = "id,name\n1,one\n2,two\n3,three\n"
+	blob, err := upload.WriteBlob(deps.blocks, "ns1", strings.NewReader(content), int64(len(content)), block.PutOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.Must(t,
+		deps.cataloger.CreateEntry(ctx, "repo1", "master", catalog.Entry{
+			Path:            "data/table.csv",
+			PhysicalAddress: blob.PhysicalAddress,
+			CreationDate:    time.Now(),
+			Size:            blob.Size,
+			Checksum:        blob.Checksum,
+		}, catalog.CreateEntryParams{}))
+
+	t.Run("preview csv rows", func(t *testing.T) {
+		resp, err := clt.Objects.PreviewObject(&objects.PreviewObjectParams{
+			Ref:        "master",
+			Path:       "data/table.csv",
+			Repository: "repo1",
+			MaxRows:    swag.Int64(3),
+		}, bauth)
+		if err != nil {
+			t.Fatalf("did not expect error for preview, got %s", err)
+		}
+		p := resp.GetPayload()
+		if p.Format != "csv" {
+			t.Fatalf("expected csv format, got %s", p.Format)
+		}
+		if len(p.Rows) != 3 || p.Rows[2][1] != "two" {
+			t.Fatalf("unexpected rows %v", p.Rows)
+		}
+		if !p.Truncated {
+			t.Fatal("expected truncated preview")
+		}
+	})
+
+	t.Run("preview missing object", func(t *testing.T) {
+		_, err := clt.Objects.PreviewObject(&objects.PreviewObjectParams{
+			Ref:        "master",
+			Path:       "data/missing.csv",
+			Repository: "repo1",
+		}, bauth)
+		if _, ok := err.(*objects.PreviewObjectNotFound); !ok {
+			t.Fatalf("expected object not found for preview, got %v", err)
+		}
+	})
+}
+
+func TestHandler_ObjectsGetObjectSchemaHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	ctx := context.Background()
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, obj := range []struct {
+		path    string
+		content string
+	}{
+		{path: "data/table.csv", content: "id,name\n1,one\n2,two\n3,three\n"},
+		{path: "data/notes.txt", content: "just some notes"},
+	} {
+		blob, err := upload.WriteBlob(deps.blocks, "ns1", strings.NewReader(obj.content), int64(len(obj.content)), block.PutOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.Must(t,
+			deps.cataloger.CreateEntry(ctx, "repo1", "master", catalog.Entry{
+				Path:            obj.path,
+				PhysicalAddress: blob.PhysicalAddress,
+				CreationDate:    time.Now(),
+				Size:            blob.Size,
+				Checksum:        blob.Checksum,
+			}, catalog.CreateEntryParams{}))
+	}
+
+	t.Run("get csv schema", func(t *testing.T) {
+		resp, err := clt.Objects.GetObjectSchema(&objects.GetObjectSchemaParams{
+			Ref:        "master",
+			Path:       "data/table.csv",
+			Repository: "repo1",
+			MaxRows:    swag.Int64(2),
+		}, bauth)
+		if err != nil {
+			t.Fatalf("did not expect error for get schema, got %s", err)
+		}
+		s := resp.GetPayload()
+		if s.Format != "csv" {
+			t.Fatalf("expected csv format, got %s", s.Format)
+		}
+		if len(s.Columns) != 2 || swag.StringValue(s.Columns[1].Name) != "name" {
+			t.Fatalf("unexpected columns %v", s.Columns)
+		}
+		if swag.Int64Value(s.RowCount) != 3 {
+			t.Fatalf("expected 3 rows, got %d", swag.Int64Value(s.RowCount))
+		}
+		if len(s.Sample) != 2 {
+			t.Fatalf("expected 2 sample rows, got %d", len(s.Sample))
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := clt.Objects.GetObjectSchema(&objects.GetObjectSchemaParams{
+			Ref:        "master",
+			Path:       "data/notes.txt",
+			Repository: "repo1",
+		}, bauth)
+		if _, ok := err.(*objects.GetObjectSchemaBadRequest); !ok {
+			t.Fatalf("expected bad request for unsupported format, got %v", err)
+		}
+	})
+}
+
+func TestHandler_ObjectsUploadObjectHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("upload object", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		buf.WriteString("hello world this is my awesome content")
+		resp, err := clt.Objects.UploadObject(&objects.UploadObjectParams{
+			Branch:     "master",
+			Content:    runtime.NamedReader("content", buf),
+			Path:       "foo/bar",
+			Repository: "repo1",
+		}, bauth)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.Payload.SizeBytes != 38 {
 			t.Fatalf("expected 38 bytes to be written, got back %d", resp.Payload.SizeBytes)
 		}
 
@@ -1102,6 +1777,226 @@ func TestHandler_ObjectsUploadObjectHandler(t *testing.T) {
 	})
 }
 
+func TestHandler_ObjectsGetObjectHistoryHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.MustDo(t, "create repo1", err)
+	for _, checksum := range []string{"checksum1", "checksum2"} {
+		testutil.MustDo(t, "create entry", deps.cataloger.CreateEntry(ctx, "repo1", "master", catalog.Entry{
+			Path:            "foo/bar",
+			PhysicalAddress: "address-" + checksum,
+			Checksum:        checksum,
+		}, catalog.CreateEntryParams{}))
+		_, err := deps.cataloger.Commit(ctx, "repo1", "master", "write "+checksum, "tester", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit", err)
+	}
+
+	resp, err := clt.Objects.GetObjectHistory(&objects.GetObjectHistoryParams{
+		Ref:        "master",
+		Path:       "foo/bar",
+		Repository: "repo1",
+	}, bauth)
+	testutil.MustDo(t, "get object history", err)
+	results := resp.Payload.Results
+	if len(results) != 2 {
+		t.Fatalf("got %d history records, expected 2", len(results))
+	}
+	if swag.StringValue(results[0].Type) != models.ObjectHistoryRecordTypeChanged || results[0].Checksum != "checksum2" {
+		t.Errorf("newest history record %+v, expected change to checksum2", results[0])
+	}
+	if swag.StringValue(results[1].Type) != models.ObjectHistoryRecordTypeAdded || results[1].Checksum != "checksum1" {
+		t.Errorf("oldest history record %+v, expected add of checksum1", results[1])
+	}
+}
+
+func TestHandler_ObjectsDeleteObjectsHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.MustDo(t, "create repo1", err)
+	for _, path := range []string{"foo/one", "foo/two"} {
+		testutil.MustDo(t, "create entry "+path, deps.cataloger.CreateEntry(ctx, "repo1", "master", catalog.Entry{
+			Path:            path,
+			PhysicalAddress: "address-" + path,
+			Checksum:        "checksum",
+		}, catalog.CreateEntryParams{}))
+	}
+
+	resp, err := clt.Objects.DeleteObjects(&objects.DeleteObjectsParams{
+		Branch:     "master",
+		Repository: "repo1",
+		Paths:      &models.ObjectDeleteBatchRequest{Paths: []string{"foo/one", "foo/missing", "foo/two"}},
+	}, bauth)
+	testutil.MustDo(t, "delete objects", err)
+	expected := map[string]bool{"foo/one": true, "foo/missing": false, "foo/two": true}
+	if len(resp.Payload.Results) != len(expected) {
+		t.Fatalf("got %d results, expected %d", len(resp.Payload.Results), len(expected))
+	}
+	for _, result := range resp.Payload.Results {
+		if swag.BoolValue(result.Deleted) != expected[swag.StringValue(result.Path)] {
+			t.Errorf("delete %s deleted=%t, expected %t", swag.StringValue(result.Path), swag.BoolValue(result.Deleted), expected[swag.StringValue(result.Path)])
+		}
+	}
+	entries, _, err := deps.cataloger.ListEntries(ctx, "repo1", "master", "foo/", "", "", -1)
+	testutil.MustDo(t, "list entries", err)
+	if len(entries) != 0 {
+		t.Errorf("listed %d entries after delete, expected none", len(entries))
+	}
+}
+
+func TestHandler_ObjectsStageObjectsHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.MustDo(t, "create repo1", err)
+	testutil.MustDo(t, "set metadata schema", deps.cataloger.SetMetadataSchema(ctx, "repo1", &catalog.MetadataSchema{
+		Rules: []catalog.MetadataRule{{Prefix: "table/", Required: []string{"schema"}}},
+	}))
+	stage := func(path string, metadata map[string]string) *models.ObjectStageCreation {
+		return &models.ObjectStageCreation{
+			Path:            swag.String(path),
+			PhysicalAddress: swag.String("address-" + path),
+			Checksum:        swag.String("checksum-" + path),
+			SizeBytes:       swag.Int64(10),
+			Metadata:        metadata,
+		}
+	}
+
+	t.Run("all", func(t *testing.T) {
+		_, err := clt.Objects.StageObjects(&objects.StageObjectsParams{
+			Branch:     "master",
+			Repository: "repo1",
+			Entries: &models.ObjectStageBatchRequest{Entries: []*models.ObjectStageCreation{
+				stage("table/part-0", map[string]string{"schema": "v1"}),
+				stage("table/_metadata", map[string]string{"schema": "v1"}),
+			}},
+		}, bauth)
+		testutil.MustDo(t, "stage objects", err)
+		entries, _, err := deps.cataloger.ListEntries(ctx, "repo1", "master", "table/", "", "", -1)
+		testutil.MustDo(t, "list entries", err)
+		if len(entries) != 2 {
+			t.Errorf("listed %d entries after stage, expected 2", len(entries))
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		_, err := clt.Objects.StageObjects(&objects.StageObjectsParams{
+			Branch:     "master",
+			Repository: "repo1",
+			Entries: &models.ObjectStageBatchRequest{Entries: []*models.ObjectStageCreation{
+				stage("other/file", nil),
+				stage("table/part-1", nil),
+			}},
+		}, bauth)
+		var badRequest *objects.StageObjectsBadRequest
+		if !errors.As(err, &badRequest) {
+			t.Fatalf("stage objects breaking schema err=%v, expected bad request", err)
+		}
+		_, err = deps.cataloger.GetEntry(ctx, "repo1", "master", "other/file", catalog.GetEntryParams{})
+		if !errors.Is(err, db.ErrNotFound) {
+			t.Errorf("get other/file err=%v, expected %s", err, db.ErrNotFound)
+		}
+	})
+}
+
+func TestHandler_ObjectsCopyObjectHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.MustDo(t, "create repo1", err)
+	_, err = deps.cataloger.CreateRepository(ctx, "repo2", "ns2", "master")
+	testutil.MustDo(t, "create repo2", err)
+
+	const content = "hello world this is my awesome content"
+	_, err = clt.Objects.UploadObject(&objects.UploadObjectParams{
+		Branch:     "master",
+		Content:    runtime.NamedReader("content", strings.NewReader(content)),
+		Path:       "foo/bar",
+		Repository: "repo1",
+	}, bauth)
+	testutil.MustDo(t, "upload object", err)
+
+	for _, repository := range []string{"repo1", "repo2"} {
+		t.Run("copy to "+repository, func(t *testing.T) {
+			resp, err := clt.Objects.CopyObject(&objects.CopyObjectParams{
+				Branch:     "master",
+				Path:       "foo/copied",
+				Repository: repository,
+				Source: &models.ObjectCopyCreation{
+					SourceRepository: "repo1",
+					SourceRef:        swag.String("master"),
+					SourcePath:       swag.String("foo/bar"),
+				},
+			}, bauth)
+			testutil.MustDo(t, "copy object", err)
+			if resp.Payload.SizeBytes != int64(len(content)) {
+				t.Errorf("copied %d bytes, expected %d", resp.Payload.SizeBytes, len(content))
+			}
+
+			buf := new(bytes.Buffer)
+			_, err = clt.Objects.GetObject(&objects.GetObjectParams{
+				Ref:        "master",
+				Path:       "foo/copied",
+				Repository: repository,
+			}, bauth, buf)
+			testutil.MustDo(t, "get copied object", err)
+			if buf.String() != content {
+				t.Errorf("copied object content %q, expected %q", buf.String(), content)
+			}
+		})
+	}
+
+	t.Run("missing source", func(t *testing.T) {
+		_, err := clt.Objects.CopyObject(&objects.CopyObjectParams{
+			Branch:     "master",
+			Path:       "foo/copied",
+			Repository: "repo2",
+			Source: &models.ObjectCopyCreation{
+				SourceRepository: "repo1",
+				SourceRef:        swag.String("master"),
+				SourcePath:       swag.String("foo/no-such-object"),
+			},
+		}, bauth)
+		var notFound *objects.CopyObjectNotFound
+		if !errors.As(err, &notFound) {
+			t.Errorf("copy missing object err=%v, expected not found", err)
+		}
+	})
+}
+
 func TestHandler_ObjectsDeleteObjectHandler(t *testing.T) {
 	handler, deps := getHandler(t, "")
 
@@ -1330,9 +2225,8 @@ func TestHandler_RetentionPolicyHandlers(t *testing.T) {
 	})
 }
 
-func TestHandler_ConfigHandlers(t *testing.T) {
-	const BlockstoreType = "s3"
-	handler, deps := getHandler(t, BlockstoreType)
+func TestHandler_RepositoryQuotaHandlers(t *testing.T) {
+	handler, deps := getHandler(t, "")
 
 	// create user
 	creds := createDefaultAdminUser(deps.auth, t)
@@ -1341,44 +2235,563 @@ func TestHandler_ConfigHandlers(t *testing.T) {
 	// setup client
 	clt := client.Default
 	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.Must(t, err)
 
-	t.Run("Get config (currently only block store type)", func(t *testing.T) {
-		resp, err := clt.Config.GetConfig(&config.GetConfigParams{}, bauth)
-		if err != nil {
-			t.Fatal(err)
+	t.Run("get quota of missing repo", func(t *testing.T) {
+		_, err := clt.Repositories.GetRepositoryQuota(&repositories.GetRepositoryQuotaParams{
+			Repository: "repo2",
+		}, bauth)
+		var notFoundErr *repositories.GetRepositoryQuotaNotFound
+		if !errors.As(err, &notFoundErr) {
+			t.Fatalf("expected not found error getting quota of missing repo, got %v", err)
 		}
+	})
 
-		got := resp.GetPayload()
+	t.Run("set and get quota", func(t *testing.T) {
+		quota := &models.RepositoryQuota{
+			MaxStorageBytes:         swag.Int64(1024),
+			MaxObjects:              swag.Int64(10),
+			MaxRequestsPerSecond:    swag.Int64(0),
+			MaxExportBytesPerSecond: swag.Int64(4096),
+		}
+		_, err := clt.Repositories.SetRepositoryQuota(&repositories.SetRepositoryQuotaParams{
+			Repository: "repo1",
+			Quota:      quota,
+		}, bauth)
+		testutil.Must(t, err)
 
-		if got.BlockstoreType != BlockstoreType {
-			t.Errorf("expected to get %s, got %s", BlockstoreType, got.BlockstoreType)
+		resp, err := clt.Repositories.GetRepositoryQuota(&repositories.GetRepositoryQuotaParams{
+			Repository: "repo1",
+		}, bauth)
+		testutil.Must(t, err)
+		if diff := deep.Equal(quota, resp.GetPayload()); diff != nil {
+			t.Errorf("expected to read back the same quota, got %s", diff)
 		}
 	})
-}
-
-func TestHandler_ContinuousExportHandlers(t *testing.T) {
-	const (
-		repo          = "repo-for-continuous-export-test"
-		branch        = "main"
-		anotherBranch = "notMain"
-	)
-	handler, deps := getHandler(t, "")
 
-	creds := createDefaultAdminUser(deps.auth, t)
-	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+	t.Run("get usage", func(t *testing.T) {
+		testutil.Must(t, deps.cataloger.CreateEntry(ctx, "repo1", "master", catalog.Entry{
+			Path:            "foo/bar",
+			PhysicalAddress: "this_is_bars_address",
+			Checksum:        "this_is_a_checksum",
+			Size:            42,
+		}, catalog.CreateEntryParams{}))
 
-	clt := client.Default
-	clt.SetTransport(&handlerTransport{Handler: handler})
+		resp, err := clt.Repositories.GetRepositoryUsage(&repositories.GetRepositoryUsageParams{
+			Repository: "repo1",
+		}, bauth)
+		testutil.Must(t, err)
+		if diff := deep.Equal(&models.RepositoryUsage{StorageBytes: 42, Objects: 1}, resp.GetPayload()); diff != nil {
+			t.Errorf("unexpected repository usage, got %s", diff)
+		}
+	})
 
-	ctx := context.Background()
-	_, err := deps.cataloger.CreateRepository(ctx, repo, "s3://foo1", branch)
-	testutil.MustDo(t, "create repository", err)
+	t.Run("set quota of missing organization", func(t *testing.T) {
+		_, err := clt.Repositories.SetRepositoryQuota(&repositories.SetRepositoryQuotaParams{
+			Repository: "repo1",
+			Quota:      &models.RepositoryQuota{Organization: "org1"},
+		}, bauth)
+		var badRequestErr *repositories.SetRepositoryQuotaBadRequest
+		if !errors.As(err, &badRequestErr) {
+			t.Fatalf("expected bad request setting quota of missing organization, got %v", err)
+		}
+	})
 
-	config := models.ContinuousExportConfiguration{
-		ExportPath:             strfmt.URI("s3://bucket/export"),
-		ExportStatusPath:       strfmt.URI("s3://bucket/report"),
-		LastKeysInPrefixRegexp: []string{"^_success$", ".*/_success$"},
-	}
+	t.Run("organization quota", func(t *testing.T) {
+		_, err := clt.Organizations.GetOrganizationQuota(&organizations.GetOrganizationQuotaParams{
+			Organization: "org1",
+		}, bauth)
+		var notFoundErr *organizations.GetOrganizationQuotaNotFound
+		if !errors.As(err, &notFoundErr) {
+			t.Fatalf("expected not found error getting quota of missing organization, got %v", err)
+		}
+
+		quota := &models.OrganizationQuota{
+			MaxStorageBytes:         swag.Int64(2048),
+			MaxObjects:              swag.Int64(20),
+			MaxRequestsPerSecond:    swag.Int64(100),
+			MaxExportBytesPerSecond: swag.Int64(0),
+		}
+		_, err = clt.Organizations.SetOrganizationQuota(&organizations.SetOrganizationQuotaParams{
+			Organization: "org1",
+			Quota:        quota,
+		}, bauth)
+		testutil.Must(t, err)
+		resp, err := clt.Organizations.GetOrganizationQuota(&organizations.GetOrganizationQuotaParams{
+			Organization: "org1",
+		}, bauth)
+		testutil.Must(t, err)
+		if diff := deep.Equal(quota, resp.GetPayload()); diff != nil {
+			t.Errorf("expected to read back the same organization quota, got %s", diff)
+		}
+
+		_, err = clt.Repositories.SetRepositoryQuota(&repositories.SetRepositoryQuotaParams{
+			Repository: "repo1",
+			Quota:      &models.RepositoryQuota{Organization: "org1"},
+		}, bauth)
+		testutil.Must(t, err)
+		usage, err := clt.Organizations.GetOrganizationUsage(&organizations.GetOrganizationUsageParams{
+			Organization: "org1",
+		}, bauth)
+		testutil.Must(t, err)
+		if diff := deep.Equal(&models.RepositoryUsage{StorageBytes: 42, Objects: 1}, usage.GetPayload()); diff != nil {
+			t.Errorf("unexpected organization usage, got %s", diff)
+		}
+
+		_, err = clt.Organizations.DeleteOrganizationQuota(&organizations.DeleteOrganizationQuotaParams{
+			Organization: "org1",
+		}, bauth)
+		testutil.Must(t, err)
+		repoQuota, err := clt.Repositories.GetRepositoryQuota(&repositories.GetRepositoryQuotaParams{
+			Repository: "repo1",
+		}, bauth)
+		testutil.Must(t, err)
+		if repoQuota.GetPayload().Organization != "" {
+			t.Errorf("expected repository to leave deleted organization, got %s", repoQuota.GetPayload().Organization)
+		}
+	})
+
+	t.Run("request rate", func(t *testing.T) {
+		_, err := clt.Repositories.SetRepositoryQuota(&repositories.SetRepositoryQuotaParams{
+			Repository: "repo1",
+			Quota:      &models.RepositoryQuota{MaxRequestsPerSecond: swag.Int64(1)},
+		}, bauth)
+		testutil.Must(t, err)
+		_, err = clt.Repositories.GetRepositoryUsage(&repositories.GetRepositoryUsageParams{
+			Repository: "repo1",
+		}, bauth)
+		testutil.Must(t, err)
+		_, err = clt.Repositories.GetRepositoryUsage(&repositories.GetRepositoryUsageParams{
+			Repository: "repo1",
+		}, bauth)
+		var defaultErr *repositories.GetRepositoryUsageDefault
+		if !errors.As(err, &defaultErr) || defaultErr.Code() != http.StatusTooManyRequests {
+			t.Fatalf("expected too many requests over request rate, got %v", err)
+		}
+		// quota settings are not limited
+		_, err = clt.Repositories.GetRepositoryQuota(&repositories.GetRepositoryQuotaParams{
+			Repository: "repo1",
+		}, bauth)
+		testutil.Must(t, err)
+	})
+}
+
+func TestHandler_SetDefaultBranchHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.Must(t, err)
+	_, err = deps.cataloger.CreateBranch(ctx, "repo1", "main", "master")
+	testutil.Must(t, err)
+
+	t.Run("missing branch", func(t *testing.T) {
+		_, err := clt.Repositories.SetDefaultBranch(&repositories.SetDefaultBranchParams{
+			Repository: "repo1",
+			Branch:     &models.RepositoryDefaultBranch{Name: swag.String("no-branch")},
+		}, bauth)
+		var notFoundErr *repositories.SetDefaultBranchNotFound
+		if !errors.As(err, &notFoundErr) {
+			t.Fatalf("expected not found error setting missing default branch, got %v", err)
+		}
+	})
+
+	t.Run("set default branch", func(t *testing.T) {
+		_, err := clt.Repositories.SetDefaultBranch(&repositories.SetDefaultBranchParams{
+			Repository: "repo1",
+			Branch:     &models.RepositoryDefaultBranch{Name: swag.String("main")},
+		}, bauth)
+		testutil.Must(t, err)
+
+		resp, err := clt.Repositories.ListRepositories(&repositories.ListRepositoriesParams{}, bauth)
+		testutil.Must(t, err)
+		results := resp.GetPayload().Results
+		if len(results) != 1 || results[0].DefaultBranch != "main" {
+			t.Fatalf("expected repository default branch main, got %+v", results)
+		}
+	})
+}
+
+func TestHandler_SearchRepositoryHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.Must(t, err)
+	for _, p := range []string{"data/events.parquet", "data/users.csv", "logs/events.log"} {
+		testutil.Must(t, deps.cataloger.CreateEntry(ctx, "repo1", "master", catalog.Entry{
+			Path:            p,
+			PhysicalAddress: "addr_" + p,
+			CreationDate:    time.Now(),
+			Size:            42,
+			Checksum:        "checksum",
+		}, catalog.CreateEntryParams{}))
+	}
+	_, err = deps.cataloger.Commit(ctx, "repo1", "master", "add events data", "tester", nil, catalog.CommitParams{})
+	testutil.Must(t, err)
+
+	t.Run("search objects", func(t *testing.T) {
+		resp, err := clt.Repositories.SearchRepository(&repositories.SearchRepositoryParams{
+			Repository: "repo1",
+			Query:      "events",
+		}, bauth)
+		if err != nil {
+			t.Fatalf("unexpected error searching objects: %s", err)
+		}
+		objs := resp.GetPayload().Objects
+		if len(objs) != 2 || objs[0].Path != "data/events.parquet" || objs[1].Path != "logs/events.log" {
+			t.Fatalf("unexpected search results %v", objs)
+		}
+	})
+
+	t.Run("search commits", func(t *testing.T) {
+		resp, err := clt.Repositories.SearchRepository(&repositories.SearchRepositoryParams{
+			Repository: "repo1",
+			Query:      "events data",
+			Type:       swag.String("commits"),
+			Ref:        swag.String("master"),
+		}, bauth)
+		if err != nil {
+			t.Fatalf("unexpected error searching commits: %s", err)
+		}
+		commits := resp.GetPayload().Commits
+		if len(commits) != 1 || commits[0].Message != "add events data" {
+			t.Fatalf("unexpected search results %v", commits)
+		}
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		_, err := clt.Repositories.SearchRepository(&repositories.SearchRepositoryParams{
+			Repository: "repo1",
+			Query:      " ",
+		}, bauth)
+		if _, ok := err.(*repositories.SearchRepositoryBadRequest); !ok {
+			t.Fatalf("expected bad request for empty query, got %v", err)
+		}
+	})
+}
+
+func TestHandler_DataLineageHandlers(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+
+	ctx := context.Background()
+	for _, repo := range []string{"events", "reports"} {
+		_, err := deps.cataloger.CreateRepository(ctx, repo, "ns_"+repo, "master")
+		testutil.Must(t, err)
+		testutil.Must(t, deps.cataloger.CreateEntry(ctx, repo, "master", catalog.Entry{
+			Path:            "data/" + repo,
+			PhysicalAddress: "addr_" + repo,
+			CreationDate:    time.Now(),
+			Size:            42,
+			Checksum:        "checksum",
+		}, catalog.CreateEntryParams{}))
+		_, err = deps.cataloger.Commit(ctx, repo, "master", "produce "+repo, "tester", nil, catalog.CommitParams{})
+		testutil.Must(t, err)
+	}
+
+	t.Run("create lineage", func(t *testing.T) {
+		_, err := clt.Commits.CreateDataLineage(&commits.CreateDataLineageParams{
+			Repository: "reports",
+			Ref:        "master",
+			Lineage: &models.DataLineageCreation{
+				Sources: []*models.DataLineageSource{
+					{Repository: swag.String("events"), Ref: swag.String("master")},
+				},
+			},
+		}, bauth)
+		if err != nil {
+			t.Fatalf("unexpected error creating lineage: %s", err)
+		}
+	})
+
+	t.Run("create lineage missing source", func(t *testing.T) {
+		_, err := clt.Commits.CreateDataLineage(&commits.CreateDataLineageParams{
+			Repository: "reports",
+			Ref:        "master",
+			Lineage: &models.DataLineageCreation{
+				Sources: []*models.DataLineageSource{
+					{Repository: swag.String("events"), Ref: swag.String("no-such-branch")},
+				},
+			},
+		}, bauth)
+		if _, ok := err.(*commits.CreateDataLineageNotFound); !ok {
+			t.Fatalf("expected not found for missing source, got %v", err)
+		}
+	})
+
+	t.Run("walk upstream", func(t *testing.T) {
+		resp, err := clt.Commits.WalkDataLineage(&commits.WalkDataLineageParams{
+			Repository: "reports",
+			Ref:        "master",
+		}, bauth)
+		if err != nil {
+			t.Fatalf("unexpected error walking lineage: %s", err)
+		}
+		edges := resp.GetPayload().Results
+		if len(edges) != 1 || edges[0].Repository != "reports" || edges[0].SourceRepository != "events" || edges[0].Depth != 1 {
+			t.Fatalf("unexpected lineage edges %v", edges)
+		}
+	})
+
+	t.Run("walk downstream", func(t *testing.T) {
+		resp, err := clt.Commits.WalkDataLineage(&commits.WalkDataLineageParams{
+			Repository: "events",
+			Ref:        "master",
+			Direction:  swag.String("downstream"),
+		}, bauth)
+		if err != nil {
+			t.Fatalf("unexpected error walking lineage: %s", err)
+		}
+		edges := resp.GetPayload().Results
+		if len(edges) != 1 || edges[0].Repository != "reports" {
+			t.Fatalf("unexpected lineage edges %v", edges)
+		}
+	})
+}
+
+func TestHandler_DiffRefsSummaryHandler(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.Must(t, err)
+	_, err = deps.cataloger.CreateBranch(ctx, "repo1", "branch1", "master")
+	testutil.Must(t, err)
+	testutil.Must(t, deps.cataloger.CreateEntry(ctx, "repo1", "branch1", catalog.Entry{
+		Path:            "data/file1",
+		PhysicalAddress: "addr_file1",
+		CreationDate:    time.Now(),
+		Size:            42,
+		Checksum:        "checksum",
+	}, catalog.CreateEntryParams{}))
+	_, err = deps.cataloger.Commit(ctx, "repo1", "branch1", "add file1", "tester", nil, catalog.CommitParams{})
+	testutil.Must(t, err)
+
+	t.Run("summary", func(t *testing.T) {
+		resp, err := clt.Refs.DiffRefsSummary(&refs.DiffRefsSummaryParams{
+			Repository: "repo1",
+			LeftRef:    "branch1",
+			RightRef:   "master",
+		}, bauth)
+		if err != nil {
+			t.Fatalf("unexpected error getting diff summary: %s", err)
+		}
+		summary := resp.GetPayload()
+		if summary.Added != 1 || summary.BytesDelta != 42 || summary.CommitsAhead != 2 || summary.CommitsBehind != 0 {
+			t.Fatalf("unexpected diff summary %+v", summary)
+		}
+	})
+
+	t.Run("summary only", func(t *testing.T) {
+		resp, err := clt.Refs.DiffRefs(&refs.DiffRefsParams{
+			Repository:  "repo1",
+			LeftRef:     "branch1",
+			RightRef:    "master",
+			SummaryOnly: swag.Bool(true),
+		}, bauth)
+		if err != nil {
+			t.Fatalf("unexpected error getting diff counts: %s", err)
+		}
+		payload := resp.GetPayload()
+		counts := payload.Summary
+		if len(payload.Results) != 0 || counts == nil || swag.Int64Value(counts.Added) != 1 || len(counts.Prefixes) != 1 ||
+			swag.StringValue(counts.Prefixes[0].Prefix) != "data/" || swag.Int64Value(counts.Prefixes[0].Added) != 1 {
+			t.Fatalf("unexpected diff counts %+v", payload)
+		}
+	})
+
+	t.Run("missing ref", func(t *testing.T) {
+		_, err := clt.Refs.DiffRefsSummary(&refs.DiffRefsSummaryParams{
+			Repository: "repo1",
+			LeftRef:    "branch2",
+			RightRef:   "master",
+		}, bauth)
+		if _, ok := err.(*refs.DiffRefsSummaryNotFound); !ok {
+			t.Fatalf("expected not found for missing ref, got %v", err)
+		}
+	})
+}
+
+func TestHandler_ConfigHandlers(t *testing.T) {
+	const BlockstoreType = "s3"
+	handler, deps := getHandler(t, BlockstoreType)
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+
+	t.Run("Get config (currently only block store type)", func(t *testing.T) {
+		resp, err := clt.Config.GetConfig(&config.GetConfigParams{}, bauth)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := resp.GetPayload()
+
+		if got.BlockstoreType != BlockstoreType {
+			t.Errorf("expected to get %s, got %s", BlockstoreType, got.BlockstoreType)
+		}
+	})
+
+	t.Run("set logging level", func(t *testing.T) {
+		previous := logging.Level()
+		defer func() {
+			_ = logging.SetLevel(previous)
+		}()
+		resp, err := clt.Config.SetLoggingConfig(&config.SetLoggingConfigParams{
+			Config: &models.LoggingConfig{Level: swag.String("debug")},
+		}, bauth)
+		testutil.Must(t, err)
+		if level := swag.StringValue(resp.GetPayload().Level); level != "debug" {
+			t.Errorf("expected logging level debug, got %s", level)
+		}
+	})
+
+	t.Run("set unknown logging level", func(t *testing.T) {
+		previous := logging.Level()
+		_, err := clt.Config.SetLoggingConfig(&config.SetLoggingConfigParams{
+			Config: &models.LoggingConfig{Level: swag.String("bogus")},
+		}, bauth)
+		if _, ok := err.(*config.SetLoggingConfigBadRequest); !ok {
+			t.Fatalf("expected bad request for unknown logging level, got %v", err)
+		}
+		if level := logging.Level(); level != previous {
+			t.Errorf("expected logging level to remain %s, got %s", previous, level)
+		}
+	})
+}
+
+func TestHandler_UserSubscriptionHandlers(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+
+	var subscriptionID int64
+	t.Run("create", func(t *testing.T) {
+		resp, err := clt.Auth.CreateUserSubscription(&auth.CreateUserSubscriptionParams{
+			UserID: "admin",
+			Subscription: &models.NotificationSubscriptionCreation{
+				Repository: "repo1",
+				EventType:  swag.String("export_failed"),
+				Channel:    swag.String("email"),
+				Address:    swag.String("admin@example.com"),
+			},
+		}, bauth)
+		testutil.MustDo(t, "create subscription", err)
+		subscriptionID = swag.Int64Value(resp.GetPayload().ID)
+	})
+
+	t.Run("create invalid address", func(t *testing.T) {
+		_, err := clt.Auth.CreateUserSubscription(&auth.CreateUserSubscriptionParams{
+			UserID: "admin",
+			Subscription: &models.NotificationSubscriptionCreation{
+				EventType: swag.String("export_failed"),
+				Channel:   swag.String("email"),
+				Address:   swag.String("not an address"),
+			},
+		}, bauth)
+		var badRequest *auth.CreateUserSubscriptionBadRequest
+		if !errors.As(err, &badRequest) {
+			t.Fatalf("CreateUserSubscription() err=%v, expected bad request", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := clt.Auth.ListUserSubscriptions(&auth.ListUserSubscriptionsParams{UserID: "admin"}, bauth)
+		testutil.MustDo(t, "list subscriptions", err)
+		results := resp.GetPayload().Results
+		if len(results) != 1 || swag.Int64Value(results[0].ID) != subscriptionID || results[0].Repository != "repo1" {
+			t.Fatalf("ListUserSubscriptions() %v, expected the created subscription", results)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := clt.Auth.DeleteUserSubscription(&auth.DeleteUserSubscriptionParams{UserID: "admin", SubscriptionID: subscriptionID}, bauth)
+		testutil.MustDo(t, "delete subscription", err)
+		_, err = clt.Auth.DeleteUserSubscription(&auth.DeleteUserSubscriptionParams{UserID: "admin", SubscriptionID: subscriptionID}, bauth)
+		var notFound *auth.DeleteUserSubscriptionNotFound
+		if !errors.As(err, &notFound) {
+			t.Fatalf("DeleteUserSubscription() deleted subscription err=%v, expected not found", err)
+		}
+	})
+}
+
+func TestHandler_ContinuousExportHandlers(t *testing.T) {
+	const (
+		repo          = "repo-for-continuous-export-test"
+		branch        = "main"
+		anotherBranch = "notMain"
+	)
+	handler, deps := getHandler(t, "")
+
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, repo, "s3://foo1", branch)
+	testutil.MustDo(t, "create repository", err)
+
+	config := models.ContinuousExportConfiguration{
+		ExportPath:             strfmt.URI("s3://bucket/export"),
+		ExportStatusPath:       strfmt.URI("s3://bucket/report"),
+		LastKeysInPrefixRegexp: []string{"^_success$", ".*/_success$"},
+		Parallelism:            swag.Int64(16),
+		Mode:                   catalog.ExportModeFull,
+		Format:                 catalog.ExportFormatCopy,
+		PropagateDeletes:       swag.Bool(true),
+		MaxAttempts:            swag.Int64(8),
+		RetryBackoffMs:         swag.Int64(1500),
+		WriteManifest:          true,
+		Schedule:               "0 2 * * *",
+		IncludePrefixes:        []string{"tables/"},
+		ExcludeGlobs:           []string{"*.tmp"},
+	}
 
 	res, err := clt.Export.SetContinuousExport(&export.SetContinuousExportParams{
 		Repository: repo,
@@ -1421,6 +2834,15 @@ func TestHandler_ContinuousExportHandlers(t *testing.T) {
 			ExportPath:             strfmt.URI("s3://better-bucket/export"),
 			ExportStatusPath:       strfmt.URI("s3://better-bucket/report"),
 			LastKeysInPrefixRegexp: nil,
+			Parallelism:            swag.Int64(0),
+			Mode:                   catalog.ExportModeIncremental,
+			Format:                 catalog.ExportFormatCopy,
+			PropagateDeletes:       swag.Bool(true),
+			MaxAttempts:            swag.Int64(0),
+			RetryBackoffMs:         swag.Int64(0),
+			AdditionalExportPaths:  []string{"s3://better-bucket-replica/export"},
+			SseAlgorithm:           catalog.ExportSSEAlgorithmKMS,
+			SseKmsKeyID:            "arn:aws:kms:us-east-1:123456789012:key/export",
 		}
 		_, err := clt.Export.SetContinuousExport(&export.SetContinuousExportParams{
 			Repository: repo,
@@ -1441,6 +2863,108 @@ func TestHandler_ContinuousExportHandlers(t *testing.T) {
 			t.Errorf("got different configuration: %s", diffs)
 		}
 	})
+
+	t.Run("prefixed configuration", func(t *testing.T) {
+		prefixConfig := models.ContinuousExportConfiguration{
+			Prefix:           "tables/",
+			ExportPath:       strfmt.URI("s3://tables-bucket/export"),
+			Parallelism:      swag.Int64(0),
+			Mode:             catalog.ExportModeIncremental,
+			Format:           catalog.ExportFormatSymlink,
+			PropagateDeletes: swag.Bool(false),
+			MaxAttempts:      swag.Int64(0),
+			RetryBackoffMs:   swag.Int64(0),
+		}
+		_, err := clt.Export.SetContinuousExport(&export.SetContinuousExportParams{
+			Repository: repo,
+			Branch:     branch,
+			Config:     &prefixConfig,
+		}, bauth)
+		testutil.MustDo(t, "set prefixed continuous export configuration", err)
+		got, err := clt.Export.GetContinuousExport(&export.GetContinuousExportParams{
+			Repository: repo,
+			Branch:     branch,
+			Prefix:     swag.String(prefixConfig.Prefix),
+		}, bauth)
+		if err != nil {
+			t.Fatalf("expected get to return result but got %s", err)
+		}
+		if diffs := deep.Equal(prefixConfig, *got.GetPayload()); diffs != nil {
+			t.Errorf("got different configuration: %s", diffs)
+		}
+		list, err := clt.Export.ListContinuousExports(&export.ListContinuousExportsParams{
+			Repository: repo,
+			Branch:     branch,
+		}, bauth)
+		if err != nil {
+			t.Fatalf("expected list to return result but got %s", err)
+		}
+		var prefixes []string
+		for _, c := range list.GetPayload() {
+			prefixes = append(prefixes, c.Prefix)
+		}
+		if diffs := deep.Equal([]string{"", prefixConfig.Prefix}, prefixes); diffs != nil {
+			t.Errorf("listed unexpected prefixes: %s", diffs)
+		}
+	})
+
+	t.Run("export plan", func(t *testing.T) {
+		got, err := clt.Export.GetExportPlan(&export.GetExportPlanParams{
+			Repository: repo,
+			Branch:     branch,
+		}, bauth)
+		if err != nil {
+			t.Fatalf("expected get export plan to return result but got %s", err)
+		}
+		plan := got.GetPayload()
+		if plan.FromRef != "" || swag.StringValue(plan.ToRef) == "" {
+			t.Errorf("expected export plan of a never exported branch from its commit, got from %q to %q", plan.FromRef, swag.StringValue(plan.ToRef))
+		}
+		if swag.Int64Value(plan.NumOperations) != 0 || len(plan.Operations) != 0 {
+			t.Errorf("expected no operations exporting an empty branch, got %+v", plan.Operations)
+		}
+
+		_, err = clt.Export.GetExportPlan(&export.GetExportPlanParams{
+			Repository: repo,
+			Branch:     anotherBranch,
+		}, bauth)
+		if _, ok := err.(*export.GetExportPlanNotFound); !ok {
+			t.Errorf("expected export plan of unconfigured branch to return not found but got %T %+v", err, err)
+		}
+	})
+
+	t.Run("export runs", func(t *testing.T) {
+		testutil.MustDo(t, "insert export run", deps.cataloger.InsertExportRun(ctx, repo, branch, &catalog.ExportRun{
+			ExportID: "export-run-1",
+			ToRef:    "commit1",
+		}))
+		testutil.MustDo(t, "end export run", deps.cataloger.EndExportRun(ctx, "export-run-1", catalog.ExportStatusSuccess, nil))
+		testutil.MustDo(t, "end export run destination", deps.cataloger.EndExportRunDestination(ctx, "export-run-1", "s3://better-bucket-replica/export", catalog.ExportStatusSuccess, nil))
+		got, err := clt.Export.ListExportRuns(&export.ListExportRunsParams{
+			Repository: repo,
+			Branch:     branch,
+		}, bauth)
+		if err != nil {
+			t.Fatalf("expected list export runs to return result but got %s", err)
+		}
+		runs := got.GetPayload().Results
+		if len(runs) != 1 || swag.StringValue(runs[0].ExportID) != "export-run-1" ||
+			swag.StringValue(runs[0].Status) != string(catalog.ExportStatusSuccess) {
+			t.Errorf("expected the ended export run, got %+v", runs)
+		}
+		if len(runs) == 1 && (len(runs[0].Destinations) != 1 ||
+			swag.StringValue(runs[0].Destinations[0].Status) != string(catalog.ExportStatusSuccess)) {
+			t.Errorf("expected the ended export run destination, got %+v", runs[0].Destinations)
+		}
+
+		_, err = clt.Export.ListExportRuns(&export.ListExportRunsParams{
+			Repository: repo,
+			Branch:     anotherBranch,
+		}, bauth)
+		if _, ok := err.(*export.ListExportRunsNotFound); !ok {
+			t.Errorf("expected export runs of missing branch to return not found but got %T %+v", err, err)
+		}
+	})
 }
 
 func Test_setupLakeFSHandler(t *testing.T) {
@@ -1570,3 +3094,106 @@ func mustSetup(t *testing.T, reqURI string, contentType string, req []byte) *htt
 	}
 	return res
 }
+
+func TestHandler_RepositoryCommitLimitsHandlers(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.Must(t, err)
+
+	t.Run("missing repository", func(t *testing.T) {
+		_, err := clt.Repositories.GetRepositoryCommitLimits(&repositories.GetRepositoryCommitLimitsParams{
+			Repository: "no-repo",
+		}, bauth)
+		var notFoundErr *repositories.GetRepositoryCommitLimitsNotFound
+		if !errors.As(err, &notFoundErr) {
+			t.Fatalf("expected not found error getting commit limits of missing repository, got %v", err)
+		}
+	})
+
+	t.Run("set and get", func(t *testing.T) {
+		limits := &models.RepositoryCommitLimits{
+			MaxChangedObjects: swag.Int64(1000),
+			MaxAddedBytes:     swag.Int64(0),
+		}
+		_, err := clt.Repositories.SetRepositoryCommitLimits(&repositories.SetRepositoryCommitLimitsParams{
+			Repository: "repo1",
+			Limits:     limits,
+		}, bauth)
+		testutil.Must(t, err)
+
+		resp, err := clt.Repositories.GetRepositoryCommitLimits(&repositories.GetRepositoryCommitLimitsParams{
+			Repository: "repo1",
+		}, bauth)
+		testutil.Must(t, err)
+		if diff := deep.Equal(resp.GetPayload(), limits); diff != nil {
+			t.Fatal("unexpected commit limits", diff)
+		}
+	})
+}
+
+func TestHandler_MetadataSchemaHandlers(t *testing.T) {
+	handler, deps := getHandler(t, "")
+
+	// create user
+	creds := createDefaultAdminUser(deps.auth, t)
+	bauth := httptransport.BasicAuth(creds.AccessKeyID, creds.AccessSecretKey)
+
+	// setup client
+	clt := client.Default
+	clt.SetTransport(&handlerTransport{Handler: handler})
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.Must(t, err)
+
+	t.Run("invalid schema", func(t *testing.T) {
+		_, err := clt.Repositories.SetMetadataSchema(&repositories.SetMetadataSchemaParams{
+			Repository: "repo1",
+			Schema: &models.MetadataSchema{
+				Rules: []*models.MetadataSchemaRule{{Prefix: swag.String(""), Values: map[string]string{"k": "(v"}}},
+			},
+		}, bauth)
+		var badRequestErr *repositories.SetMetadataSchemaBadRequest
+		if !errors.As(err, &badRequestErr) {
+			t.Fatalf("expected bad request setting invalid schema, got %v", err)
+		}
+	})
+
+	t.Run("set and enforce", func(t *testing.T) {
+		schema := &models.MetadataSchema{
+			Rules: []*models.MetadataSchemaRule{{Prefix: swag.String("tables/"), Required: []string{"owner"}}},
+		}
+		_, err := clt.Repositories.SetMetadataSchema(&repositories.SetMetadataSchemaParams{
+			Repository: "repo1",
+			Schema:     schema,
+		}, bauth)
+		testutil.Must(t, err)
+
+		resp, err := clt.Repositories.GetMetadataSchema(&repositories.GetMetadataSchemaParams{
+			Repository: "repo1",
+		}, bauth)
+		testutil.Must(t, err)
+		if diff := deep.Equal(resp.GetPayload(), schema); diff != nil {
+			t.Fatal("unexpected metadata schema", diff)
+		}
+
+		_, err = clt.Objects.UploadObject(&objects.UploadObjectParams{
+			Branch:     "master",
+			Content:    runtime.NamedReader("content", bytes.NewBufferString("data")),
+			Path:       "tables/a",
+			Repository: "repo1",
+		}, bauth)
+		var defaultErr *objects.UploadObjectDefault
+		if !errors.As(err, &defaultErr) || defaultErr.Code() != http.StatusBadRequest {
+			t.Fatalf("expected bad request uploading object without required metadata, got %v", err)
+		}
+	})
+}
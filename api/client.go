@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/url"
 	"path"
+	"time"
 
 	"github.com/treeverse/lakefs/api/gen/client/export"
 
@@ -16,8 +17,11 @@ import (
 	"github.com/treeverse/lakefs/api/gen/client/auth"
 	"github.com/treeverse/lakefs/api/gen/client/branches"
 	"github.com/treeverse/lakefs/api/gen/client/commits"
+	"github.com/treeverse/lakefs/api/gen/client/hooks"
+	"github.com/treeverse/lakefs/api/gen/client/jobs"
 	"github.com/treeverse/lakefs/api/gen/client/metadata"
 	"github.com/treeverse/lakefs/api/gen/client/objects"
+	"github.com/treeverse/lakefs/api/gen/client/organizations"
 	"github.com/treeverse/lakefs/api/gen/client/refs"
 	"github.com/treeverse/lakefs/api/gen/client/repositories"
 	"github.com/treeverse/lakefs/api/gen/client/retention"
@@ -46,6 +50,10 @@ type AuthClient interface {
 	CreateCredentials(ctx context.Context, userID string) (*models.CredentialsWithSecret, error)
 	DeleteCredentials(ctx context.Context, userID, accessKeyID string) error
 	GetCredentials(ctx context.Context, userID, accessKeyID string) (*models.Credentials, error)
+
+	ListUserSubscriptions(ctx context.Context, userID string) ([]*models.NotificationSubscription, error)
+	CreateUserSubscription(ctx context.Context, userID string, subscription *models.NotificationSubscriptionCreation) (*models.NotificationSubscription, error)
+	DeleteUserSubscription(ctx context.Context, userID string, subscriptionID int64) error
 	ListUserGroups(ctx context.Context, userID string, after string, amount int) ([]*models.Group, *models.Pagination, error)
 	ListUserPolicies(ctx context.Context, userID string, effective bool, after string, amount int) ([]*models.Policy, *models.Pagination, error)
 	AttachPolicyToUser(ctx context.Context, userID, policyID string) error
@@ -56,40 +64,114 @@ type AuthClient interface {
 }
 
 type RepositoryClient interface {
-	ListRepositories(ctx context.Context, after string, amount int) ([]*models.Repository, *models.Pagination, error)
+	ListRepositories(ctx context.Context, after string, amount int, includeArchived bool) ([]*models.Repository, *models.Pagination, error)
 	GetRepository(ctx context.Context, repository string) (*models.Repository, error)
 	CreateRepository(ctx context.Context, repository *models.RepositoryCreation) error
 	DeleteRepository(ctx context.Context, repository string) error
-
-	ListBranches(ctx context.Context, repository string, from string, amount int) ([]string, *models.Pagination, error)
+	GetRepositoryQuota(ctx context.Context, repository string) (*models.RepositoryQuota, error)
+	SetRepositoryQuota(ctx context.Context, repository string, quota *models.RepositoryQuota) error
+	SetDefaultBranch(ctx context.Context, repository, branch string) error
+	SetRepositoryReadOnly(ctx context.Context, repository string, readOnly bool) error
+	ArchiveRepository(ctx context.Context, repository, storageClass string) error
+	UnarchiveRepository(ctx context.Context, repository, storageClass string) error
+	GetRepositoryUsage(ctx context.Context, repository string) (*models.RepositoryUsage, error)
+	GetOrganizationQuota(ctx context.Context, organization string) (*models.OrganizationQuota, error)
+	SetOrganizationQuota(ctx context.Context, organization string, quota *models.OrganizationQuota) error
+	// DeleteOrganizationQuota deletes the quota of organization, its repositories no longer
+	// belong to an organization
+	DeleteOrganizationQuota(ctx context.Context, organization string) error
+	GetOrganizationUsage(ctx context.Context, organization string) (*models.RepositoryUsage, error)
+	GetRepositoryCommitLimits(ctx context.Context, repository string) (*models.RepositoryCommitLimits, error)
+	SetRepositoryCommitLimits(ctx context.Context, repository string, limits *models.RepositoryCommitLimits) error
+	GetMetadataSchema(ctx context.Context, repository string) (*models.MetadataSchema, error)
+	SetMetadataSchema(ctx context.Context, repository string, schema *models.MetadataSchema) error
+	ListRepositoryActivity(ctx context.Context, repository string, types []string, actor, ref, after string, amount int) ([]*models.ActivityEvent, *models.Pagination, error)
+	SearchObjects(ctx context.Context, repository, ref, query, after string, amount int) ([]*models.ObjectStats, *models.Pagination, error)
+	SearchCommits(ctx context.Context, repository, ref, query, after string, amount int) ([]*models.Commit, *models.Pagination, error)
+
+	ListBranches(ctx context.Context, repository string, from string, amount int, sortBy string) ([]string, *models.Pagination, error)
+	ListBranchesStatus(ctx context.Context, repository string, from string, amount int, sortBy string) ([]*models.BranchStatus, *models.Pagination, error)
 	GetBranch(ctx context.Context, repository, branchID string) (string, error)
 	CreateBranch(ctx context.Context, repository string, branch *models.BranchCreation) (string, error)
 	DeleteBranch(ctx context.Context, repository, branchID string) error
+	RenameBranch(ctx context.Context, repository, branchID, newName string) error
 	RevertBranch(ctx context.Context, repository, branchID string, revertProps *models.RevertCreation) error
 
-	Commit(ctx context.Context, repository, branchID, message string, metadata map[string]string) (*models.Commit, error)
+	// Commit commits the changes of branchID under prefixes, or all its changes if prefixes is empty
+	Commit(ctx context.Context, repository, branchID, message string, metadata map[string]string, prefixes []string) (*models.Commit, error)
+	// AmendCommit replaces the message and metadata of the last commit of branchID
+	AmendCommit(ctx context.Context, repository, branchID string, amend *models.CommitAmend) (*models.Commit, error)
 	GetCommit(ctx context.Context, repository, commitID string) (*models.Commit, error)
-	GetCommitLog(ctx context.Context, repository, branchID, after string, amount int) ([]*models.Commit, *models.Pagination, error)
+	// GetCommitGraph returns the commits reachable from ref through at most depth parent edges
+	GetCommitGraph(ctx context.Context, repository, ref string, depth int) (*models.CommitGraph, error)
+	// GetCommitLog returns the commits of branchID before after that match filter
+	GetCommitLog(ctx context.Context, repository, branchID, after string, amount int, filter catalog.CommitsFilter) ([]*models.Commit, *models.Pagination, error)
+	GetBranchChanges(ctx context.Context, repository, branchID, since string, amount int, diffSummary bool) (*models.BranchChanges, error)
+	CreateDataLineage(ctx context.Context, repository, ref string, sources []*models.DataLineageSource) error
+	WalkDataLineage(ctx context.Context, repository, ref, direction string, depth int) ([]*models.DataLineageEdge, error)
 
 	StatObject(ctx context.Context, repository, ref, path string) (*models.ObjectStats, error)
+	// GetObjectHistory returns the commits reachable from ref that changed the object at path
+	GetObjectHistory(ctx context.Context, repository, ref, path, after string, amount int) ([]*models.ObjectHistoryRecord, *models.Pagination, error)
+	// GetPrefixStats returns the number and total size of objects under prefix and its
+	// sub-prefixes up to depth levels below it
+	GetPrefixStats(ctx context.Context, repository, ref, prefix string, depth int) ([]*models.PrefixStats, error)
+	// StatObjects returns the metadata of a batch of objects, each read from its own ref
+	StatObjects(ctx context.Context, repository string, objects []*models.ObjectStatRef) ([]*models.ObjectStatBatchResult, error)
+	PreviewObject(ctx context.Context, repository, ref, path string, maxBytes, maxRows int) (*models.ObjectPreview, error)
+	GetObjectSchema(ctx context.Context, repository, ref, path string, maxRows int) (*models.ObjectSchema, error)
 	ListObjects(ctx context.Context, repository, ref, prefix, from string, amount int) ([]*models.ObjectStats, *models.Pagination, error)
 	GetObject(ctx context.Context, repository, ref, path string, w io.Writer) (*objects.GetObjectOK, error)
 	UploadObject(ctx context.Context, repository, branchID, path string, r io.Reader) (*models.ObjectStats, error)
 	DeleteObject(ctx context.Context, repository, branchID, path string) error
+	DeleteObjects(ctx context.Context, repository, branchID string, paths []string) ([]*models.ObjectDeleteBatchResult, error)
+	// StageObjects adds entries whose data is already written to branch, all or none of them
+	StageObjects(ctx context.Context, repository, branchID string, entries []*models.ObjectStageCreation) error
+	CopyObject(ctx context.Context, sourceRepository, sourceRef, sourcePath, repository, branchID, path string) (*models.ObjectStats, error)
+	// PresignObject returns an S3 gateway URL accepting requests with method on the object at
+	// path for the next expires, signed with the access key of the client
+	PresignObject(ctx context.Context, repository, ref, path, method string, expires time.Duration) (*models.PresignedURL, error)
 
 	DiffRefs(ctx context.Context, repository, leftRef, rightRef string, after string, amount int) ([]*models.Diff, *models.Pagination, error)
-	Merge(ctx context.Context, repository, leftRef, rightRef string) (*models.MergeResult, error)
-
-	DiffBranch(ctx context.Context, repository, branch string, after string, amount int) ([]*models.Diff, *models.Pagination, error)
+	// DiffBranches lists the differences between branches including their uncommitted changes
+	DiffBranches(ctx context.Context, repository, leftBranch, rightBranch string, after string, amount int) ([]*models.Diff, *models.Pagination, error)
+	DiffRefsSummary(ctx context.Context, repository, leftRef, rightRef string) (*models.DiffSummary, error)
+	// DiffRefsChecksums counts the objects that differ between references by path and checksum only
+	DiffRefsChecksums(ctx context.Context, repository, leftRef, rightRef string) (*models.ChecksumDiffSummary, error)
+	DiffRefsCounts(ctx context.Context, repository, leftRef, rightRef string) (*models.DiffCounts, error)
+	Merge(ctx context.Context, repository, leftRef, rightRef string, merge *models.Merge) (*models.MergeResult, error)
+	MergePreview(ctx context.Context, repository, sourceRef, destinationRef string) (*models.MergePreview, error)
+
+	// DiffBranch lists the uncommitted changes of branch to paths with prefix
+	DiffBranch(ctx context.Context, repository, branch, prefix string, after string, amount int) ([]*models.Diff, *models.Pagination, error)
+	DiffBranchCounts(ctx context.Context, repository, branch string) (*models.DiffCounts, error)
 
 	GetRetentionPolicy(ctx context.Context, repository string) (*models.RetentionPolicyWithCreationDate, error)
 	UpdateRetentionPolicy(ctx context.Context, repository string, policy *models.RetentionPolicy) error
 	Symlink(ctx context.Context, repoID, ref, path string) (string, error)
 
 	SetContinuousExport(ctx context.Context, repository, branchID string, config *models.ContinuousExportConfiguration) error
-	GetContinuousExport(ctx context.Context, repository, branchID string) (*models.ContinuousExportConfiguration, error)
+	GetContinuousExport(ctx context.Context, repository, branchID, prefix string) (*models.ContinuousExportConfiguration, error)
+	ListContinuousExports(ctx context.Context, repository, branchID string) ([]*models.ContinuousExportConfiguration, error)
+	ListExportRuns(ctx context.Context, repository, branchID, after string, amount int) ([]*models.ExportRun, *models.Pagination, error)
 	RunExport(ctx context.Context, repository, branchID string) (string, error)
-	RepairExport(ctx context.Context, repository, branchID string) error
+	ExportRef(ctx context.Context, repository, ref, destination string) (string, error)
+	GetRefExport(ctx context.Context, repository, exportID string) (*models.RefExport, error)
+	RepairExport(ctx context.Context, repository, branchID string) (string, error)
+	GetExportPlan(ctx context.Context, repository, branchID, ref string, amount int) (*models.ExportPlan, error)
+	GetExportDrift(ctx context.Context, repository, branchID string) (*models.ExportDriftReport, error)
+	ReconcileExportDrift(ctx context.Context, repository, branchID string) (*models.ExportDriftReport, error)
+
+	ListHookRuns(ctx context.Context, repository, commitID, branch, status, after string, amount int) ([]*models.HookRun, *models.Pagination, error)
+	GetHookRun(ctx context.Context, repository string, runID int64) (*models.HookRun, error)
+	RetryHookRun(ctx context.Context, repository string, runID int64) (*models.HookRun, error)
+	DryRunHooks(ctx context.Context, repository string, event *models.HookDryRun) ([]*models.HookRun, error)
+
+	CreateCommitJob(ctx context.Context, repository, branchID, message string, metadata map[string]string) (*models.Job, error)
+	ListJobs(ctx context.Context, repository, branch, status, after string, amount int) ([]*models.Job, *models.Pagination, error)
+	GetJob(ctx context.Context, repository string, jobID int64) (*models.Job, error)
+	ResumeJob(ctx context.Context, repository string, jobID int64) (*models.Job, error)
+	AbortJob(ctx context.Context, repository string, jobID int64) (*models.Job, error)
 }
 
 type Client interface {
@@ -320,6 +402,38 @@ func (c *client) GetCredentials(ctx context.Context, userID, accessKeyID string)
 	return resp.GetPayload(), nil
 }
 
+func (c *client) ListUserSubscriptions(ctx context.Context, userID string) ([]*models.NotificationSubscription, error) {
+	resp, err := c.remote.Auth.ListUserSubscriptions(&auth.ListUserSubscriptionsParams{
+		UserID:  userID,
+		Context: ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload().Results, nil
+}
+
+func (c *client) CreateUserSubscription(ctx context.Context, userID string, subscription *models.NotificationSubscriptionCreation) (*models.NotificationSubscription, error) {
+	resp, err := c.remote.Auth.CreateUserSubscription(&auth.CreateUserSubscriptionParams{
+		UserID:       userID,
+		Subscription: subscription,
+		Context:      ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) DeleteUserSubscription(ctx context.Context, userID string, subscriptionID int64) error {
+	_, err := c.remote.Auth.DeleteUserSubscription(&auth.DeleteUserSubscriptionParams{
+		UserID:         userID,
+		SubscriptionID: subscriptionID,
+		Context:        ctx,
+	}, c.auth)
+	return err
+}
+
 func (c *client) ListUserGroups(ctx context.Context, userID string, after string, amount int) ([]*models.Group, *models.Pagination, error) {
 	resp, err := c.remote.Auth.ListUserGroups(&auth.ListUserGroupsParams{
 		Amount:  swag.Int64(int64(amount)),
@@ -396,11 +510,12 @@ func (c *client) DetachPolicyFromGroup(ctx context.Context, groupID, policyID st
 	return err
 }
 
-func (c *client) ListRepositories(ctx context.Context, after string, amount int) ([]*models.Repository, *models.Pagination, error) {
+func (c *client) ListRepositories(ctx context.Context, after string, amount int, includeArchived bool) ([]*models.Repository, *models.Pagination, error) {
 	resp, err := c.remote.Repositories.ListRepositories(&repositories.ListRepositoriesParams{
-		After:   swag.String(after),
-		Amount:  swag.Int64(int64(amount)),
-		Context: ctx,
+		After:           swag.String(after),
+		Amount:          swag.Int64(int64(amount)),
+		IncludeArchived: swag.Bool(includeArchived),
+		Context:         ctx,
 	}, c.auth)
 	if err != nil {
 		return nil, nil, err
@@ -419,318 +534,1069 @@ func (c *client) GetRepository(ctx context.Context, repository string) (*models.
 	return resp.GetPayload(), nil
 }
 
-func (c *client) ListBranches(ctx context.Context, repository string, after string, amount int) ([]string, *models.Pagination, error) {
-	resp, err := c.remote.Branches.ListBranches(&branches.ListBranchesParams{
-		After:      swag.String(after),
-		Amount:     swag.Int64(int64(amount)),
+func (c *client) GetRepositoryQuota(ctx context.Context, repository string) (*models.RepositoryQuota, error) {
+	resp, err := c.remote.Repositories.GetRepositoryQuota(&repositories.GetRepositoryQuotaParams{
 		Repository: repository,
 		Context:    ctx,
 	}, c.auth)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	return resp.GetPayload().Results, resp.GetPayload().Pagination, nil
+	return resp.GetPayload(), nil
 }
 
-func (c *client) CreateRepository(ctx context.Context, repository *models.RepositoryCreation) error {
-	_, err := c.remote.Repositories.CreateRepository(&repositories.CreateRepositoryParams{
+func (c *client) SetRepositoryQuota(ctx context.Context, repository string, quota *models.RepositoryQuota) error {
+	_, err := c.remote.Repositories.SetRepositoryQuota(&repositories.SetRepositoryQuotaParams{
 		Repository: repository,
+		Quota:      quota,
 		Context:    ctx,
 	}, c.auth)
 	return err
 }
 
-func (c *client) DeleteRepository(ctx context.Context, repository string) error {
-	_, err := c.remote.Repositories.DeleteRepository(&repositories.DeleteRepositoryParams{
+func (c *client) SetDefaultBranch(ctx context.Context, repository, branch string) error {
+	_, err := c.remote.Repositories.SetDefaultBranch(&repositories.SetDefaultBranchParams{
 		Repository: repository,
+		Branch:     &models.RepositoryDefaultBranch{Name: swag.String(branch)},
 		Context:    ctx,
 	}, c.auth)
 	return err
 }
 
-func (c *client) GetBranch(ctx context.Context, repository, branchID string) (string, error) {
-	resp, err := c.remote.Branches.GetBranch(&branches.GetBranchParams{
-		Branch:     branchID,
+func (c *client) SetRepositoryReadOnly(ctx context.Context, repository string, readOnly bool) error {
+	_, err := c.remote.Repositories.SetRepositoryReadOnly(&repositories.SetRepositoryReadOnlyParams{
 		Repository: repository,
+		ReadOnly:   &models.RepositoryReadOnly{ReadOnly: swag.Bool(readOnly)},
 		Context:    ctx,
 	}, c.auth)
-	if err != nil {
-		return "", err
-	}
-	return resp.GetPayload(), nil
+	return err
 }
 
-func (c *client) CreateBranch(ctx context.Context, repository string, branch *models.BranchCreation) (string, error) {
-	resp, err := c.remote.Branches.CreateBranch(&branches.CreateBranchParams{
-		Branch:     branch,
+func (c *client) ArchiveRepository(ctx context.Context, repository, storageClass string) error {
+	_, err := c.remote.Repositories.ArchiveRepository(&repositories.ArchiveRepositoryParams{
 		Repository: repository,
+		Archive:    &models.RepositoryArchive{StorageClass: storageClass},
 		Context:    ctx,
 	}, c.auth)
-	if err != nil {
-		return "", err
-	}
-	return resp.GetPayload(), nil
+	return err
 }
 
-func (c *client) DeleteBranch(ctx context.Context, repository, branchID string) error {
-	_, err := c.remote.Branches.DeleteBranch(&branches.DeleteBranchParams{
-		Branch:     branchID,
+func (c *client) UnarchiveRepository(ctx context.Context, repository, storageClass string) error {
+	_, err := c.remote.Repositories.UnarchiveRepository(&repositories.UnarchiveRepositoryParams{
 		Repository: repository,
+		Archive:    &models.RepositoryArchive{StorageClass: storageClass},
 		Context:    ctx,
 	}, c.auth)
 	return err
 }
 
-func (c *client) RevertBranch(ctx context.Context, repository, branchID string, revertProps *models.RevertCreation) error {
-	_, err := c.remote.Branches.RevertBranch(&branches.RevertBranchParams{
-		Branch:     branchID,
-		Revert:     revertProps,
+func (c *client) GetRepositoryCommitLimits(ctx context.Context, repository string) (*models.RepositoryCommitLimits, error) {
+	resp, err := c.remote.Repositories.GetRepositoryCommitLimits(&repositories.GetRepositoryCommitLimitsParams{
 		Repository: repository,
 		Context:    ctx,
 	}, c.auth)
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
 }
 
-func (c *client) SetContinuousExport(ctx context.Context, repository, branchID string, config *models.ContinuousExportConfiguration) error {
-	_, err := c.remote.Export.SetContinuousExport(&export.SetContinuousExportParams{
-		Branch:     branchID,
-		Config:     config,
+func (c *client) SetRepositoryCommitLimits(ctx context.Context, repository string, limits *models.RepositoryCommitLimits) error {
+	_, err := c.remote.Repositories.SetRepositoryCommitLimits(&repositories.SetRepositoryCommitLimitsParams{
 		Repository: repository,
+		Limits:     limits,
 		Context:    ctx,
-		HTTPClient: nil,
 	}, c.auth)
 	return err
 }
 
-func (c *client) GetContinuousExport(ctx context.Context, repository, branchID string) (*models.ContinuousExportConfiguration, error) {
-	resp, err := c.remote.Export.GetContinuousExport(&export.GetContinuousExportParams{
-		Branch:     branchID,
+func (c *client) GetMetadataSchema(ctx context.Context, repository string) (*models.MetadataSchema, error) {
+	resp, err := c.remote.Repositories.GetMetadataSchema(&repositories.GetMetadataSchemaParams{
 		Repository: repository,
 		Context:    ctx,
-		HTTPClient: nil,
 	}, c.auth)
 	if err != nil {
 		return nil, err
 	}
-	return resp.GetPayload(), err
+	return resp.GetPayload(), nil
 }
 
-func (c *client) RunExport(ctx context.Context, repository, branchID string) (string, error) {
-	resp, err := c.remote.Export.Run(&export.RunParams{
-		Branch:     branchID,
+func (c *client) SetMetadataSchema(ctx context.Context, repository string, schema *models.MetadataSchema) error {
+	_, err := c.remote.Repositories.SetMetadataSchema(&repositories.SetMetadataSchemaParams{
 		Repository: repository,
+		Schema:     schema,
 		Context:    ctx,
-		HTTPClient: nil,
 	}, c.auth)
-	if err != nil {
-		return "", err
-	}
-	return resp.GetPayload(), nil
+	return err
 }
 
-func (c *client) RepairExport(ctx context.Context, repository, branchID string) error {
-	_, err := c.remote.Export.Repair(&export.RepairParams{
-		Branch:     branchID,
+func (c *client) GetRepositoryUsage(ctx context.Context, repository string) (*models.RepositoryUsage, error) {
+	resp, err := c.remote.Repositories.GetRepositoryUsage(&repositories.GetRepositoryUsageParams{
 		Repository: repository,
 		Context:    ctx,
-		HTTPClient: nil,
 	}, c.auth)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return resp.GetPayload(), nil
 }
 
-func (c *client) Commit(ctx context.Context, repository, branchID, message string, metadata map[string]string) (*models.Commit, error) {
-	commit, err := c.remote.Commits.Commit(&commits.CommitParams{
-		Branch: branchID,
-		Commit: &models.CommitCreation{
-			Message:  &message,
-			Metadata: metadata,
-		},
-		Repository: repository,
-		Context:    ctx,
+func (c *client) GetOrganizationQuota(ctx context.Context, organization string) (*models.OrganizationQuota, error) {
+	resp, err := c.remote.Organizations.GetOrganizationQuota(&organizations.GetOrganizationQuotaParams{
+		Organization: organization,
+		Context:      ctx,
 	}, c.auth)
 	if err != nil {
 		return nil, err
 	}
-	return commit.GetPayload(), nil
+	return resp.GetPayload(), nil
 }
 
-func (c *client) GetCommit(ctx context.Context, repository, commitID string) (*models.Commit, error) {
-	commit, err := c.remote.Commits.GetCommit(&commits.GetCommitParams{
-		CommitID:   commitID,
-		Repository: repository,
-		Context:    ctx,
+func (c *client) SetOrganizationQuota(ctx context.Context, organization string, quota *models.OrganizationQuota) error {
+	_, err := c.remote.Organizations.SetOrganizationQuota(&organizations.SetOrganizationQuotaParams{
+		Organization: organization,
+		Quota:        quota,
+		Context:      ctx,
+	}, c.auth)
+	return err
+}
+
+func (c *client) DeleteOrganizationQuota(ctx context.Context, organization string) error {
+	_, err := c.remote.Organizations.DeleteOrganizationQuota(&organizations.DeleteOrganizationQuotaParams{
+		Organization: organization,
+		Context:      ctx,
+	}, c.auth)
+	return err
+}
+
+func (c *client) GetOrganizationUsage(ctx context.Context, organization string) (*models.RepositoryUsage, error) {
+	resp, err := c.remote.Organizations.GetOrganizationUsage(&organizations.GetOrganizationUsageParams{
+		Organization: organization,
+		Context:      ctx,
 	}, c.auth)
 	if err != nil {
 		return nil, err
 	}
-	return commit.GetPayload(), nil
+	return resp.GetPayload(), nil
 }
 
-func (c *client) GetCommitLog(ctx context.Context, repository, branchID, after string, amount int) ([]*models.Commit, *models.Pagination, error) {
-	resp, err := c.remote.Commits.GetBranchCommitLog(&commits.GetBranchCommitLogParams{
-		Amount:     swag.Int64(int64(amount)),
-		After:      swag.String(after),
-		Branch:     branchID,
+func (c *client) ListRepositoryActivity(ctx context.Context, repository string, types []string, actor, ref, after string, amount int) ([]*models.ActivityEvent, *models.Pagination, error) {
+	params := &repositories.ListRepositoryActivityParams{
 		Repository: repository,
+		Type:       types,
+		After:      swag.String(after),
+		Amount:     swag.Int64(int64(amount)),
 		Context:    ctx,
-	}, c.auth)
+	}
+	if actor != "" {
+		params.Actor = swag.String(actor)
+	}
+	if ref != "" {
+		params.Ref = swag.String(ref)
+	}
+	resp, err := c.remote.Repositories.ListRepositoryActivity(params, c.auth)
 	if err != nil {
 		return nil, nil, err
 	}
 	return resp.GetPayload().Results, resp.GetPayload().Pagination, nil
 }
 
-func (c *client) DiffRefs(ctx context.Context, repository, leftRef, rightRef, after string, amount int) ([]*models.Diff, *models.Pagination, error) {
-	diff, err := c.remote.Refs.DiffRefs(&refs.DiffRefsParams{
+func (c *client) SearchObjects(ctx context.Context, repository, ref, query, after string, amount int) ([]*models.ObjectStats, *models.Pagination, error) {
+	resp, err := c.remote.Repositories.SearchRepository(&repositories.SearchRepositoryParams{
+		Repository: repository,
+		Query:      query,
+		Type:       swag.String("objects"),
+		Ref:        swag.String(ref),
 		After:      swag.String(after),
 		Amount:     swag.Int64(int64(amount)),
-		LeftRef:    leftRef,
-		Repository: repository,
-		RightRef:   rightRef,
 		Context:    ctx,
 	}, c.auth)
 	if err != nil {
 		return nil, nil, err
 	}
-	payload := diff.GetPayload()
-	return payload.Results, payload.Pagination, nil
+	return resp.GetPayload().Objects, resp.GetPayload().Pagination, nil
 }
 
-func (c *client) Merge(ctx context.Context, repository, leftRef, rightRef string) (*models.MergeResult, error) {
-	statusOK, err := c.remote.Refs.MergeIntoBranch(&refs.MergeIntoBranchParams{
-		DestinationRef: leftRef,
-		SourceRef:      rightRef,
-		Repository:     repository,
-		Context:        ctx,
+func (c *client) SearchCommits(ctx context.Context, repository, ref, query, after string, amount int) ([]*models.Commit, *models.Pagination, error) {
+	resp, err := c.remote.Repositories.SearchRepository(&repositories.SearchRepositoryParams{
+		Repository: repository,
+		Query:      query,
+		Type:       swag.String("commits"),
+		Ref:        swag.String(ref),
+		After:      swag.String(after),
+		Amount:     swag.Int64(int64(amount)),
+		Context:    ctx,
 	}, c.auth)
-
-	if err == nil {
-		return statusOK.Payload, nil
-	}
-	conflict, ok := err.(*refs.MergeIntoBranchConflict)
-	if ok {
-		return conflict.Payload, catalog.ErrConflictFound
+	if err != nil {
+		return nil, nil, err
 	}
-	return nil, err
+	return resp.GetPayload().Commits, resp.GetPayload().Pagination, nil
 }
 
-func (c *client) DiffBranch(ctx context.Context, repoID, branch string, after string, amount int) ([]*models.Diff, *models.Pagination, error) {
-	diff, err := c.remote.Branches.DiffBranch(&branches.DiffBranchParams{
+func (c *client) ListBranches(ctx context.Context, repository string, after string, amount int, sortBy string) ([]string, *models.Pagination, error) {
+	resp, err := c.remote.Branches.ListBranches(&branches.ListBranchesParams{
 		After:      swag.String(after),
 		Amount:     swag.Int64(int64(amount)),
-		Branch:     branch,
-		Repository: repoID,
+		SortBy:     swag.String(sortBy),
+		Repository: repository,
 		Context:    ctx,
 	}, c.auth)
 	if err != nil {
 		return nil, nil, err
 	}
-	payload := diff.GetPayload()
-	return payload.Results, payload.Pagination, nil
+	return resp.GetPayload().Results, resp.GetPayload().Pagination, nil
 }
 
-func (c *client) Symlink(ctx context.Context, repoID, branch, path string) (string, error) {
-	resp, err := c.remote.Metadata.CreateSymlink(&metadata.CreateSymlinkParams{
-		Location:   swag.String(path),
-		Branch:     branch,
-		Repository: repoID,
+func (c *client) ListBranchesStatus(ctx context.Context, repository string, after string, amount int, sortBy string) ([]*models.BranchStatus, *models.Pagination, error) {
+	resp, err := c.remote.Branches.ListBranches(&branches.ListBranchesParams{
+		After:      swag.String(after),
+		Amount:     swag.Int64(int64(amount)),
+		SortBy:     swag.String(sortBy),
+		Status:     swag.Bool(true),
+		Repository: repository,
 		Context:    ctx,
 	}, c.auth)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
-	return resp.GetPayload(), nil
+	return resp.GetPayload().Statuses, resp.GetPayload().Pagination, nil
 }
 
-func (c *client) GetRetentionPolicy(ctx context.Context, repository string) (*models.RetentionPolicyWithCreationDate, error) {
-	policy, err := c.remote.Retention.GetRetentionPolicy(&retention.GetRetentionPolicyParams{
+func (c *client) CreateRepository(ctx context.Context, repository *models.RepositoryCreation) error {
+	_, err := c.remote.Repositories.CreateRepository(&repositories.CreateRepositoryParams{
 		Repository: repository,
 		Context:    ctx,
 	}, c.auth)
-	if err != nil {
-		return nil, err
-	}
-	return policy.GetPayload(), nil
+	return err
 }
 
-func (c *client) UpdateRetentionPolicy(ctx context.Context, repository string, policy *models.RetentionPolicy) error {
-	_, err := c.remote.Retention.UpdateRetentionPolicy(&retention.UpdateRetentionPolicyParams{
+func (c *client) DeleteRepository(ctx context.Context, repository string) error {
+	_, err := c.remote.Repositories.DeleteRepository(&repositories.DeleteRepositoryParams{
 		Repository: repository,
-		Policy:     policy,
 		Context:    ctx,
 	}, c.auth)
 	return err
 }
 
-func (c *client) StatObject(ctx context.Context, repoID, ref, path string) (*models.ObjectStats, error) {
-	resp, err := c.remote.Objects.StatObject(&objects.StatObjectParams{
-		Ref:        ref,
-		Path:       path,
-		Repository: repoID,
+func (c *client) GetBranch(ctx context.Context, repository, branchID string) (string, error) {
+	resp, err := c.remote.Branches.GetBranch(&branches.GetBranchParams{
+		Branch:     branchID,
+		Repository: repository,
 		Context:    ctx,
 	}, c.auth)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	return resp.GetPayload(), nil
 }
 
-func (c *client) ListObjects(ctx context.Context, repoID, ref, prefix, after string, amount int) ([]*models.ObjectStats, *models.Pagination, error) {
-	resp, err := c.remote.Objects.ListObjects(&objects.ListObjectsParams{
-		After:      swag.String(after),
-		Amount:     swag.Int64(int64(amount)),
-		Ref:        ref,
-		Repository: repoID,
-		Prefix:     swag.String(prefix),
+func (c *client) CreateBranch(ctx context.Context, repository string, branch *models.BranchCreation) (string, error) {
+	resp, err := c.remote.Branches.CreateBranch(&branches.CreateBranchParams{
+		Branch:     branch,
+		Repository: repository,
 		Context:    ctx,
 	}, c.auth)
 	if err != nil {
-		return nil, nil, err
+		return "", err
 	}
-	return resp.GetPayload().Results, resp.GetPayload().Pagination, nil
+	return resp.GetPayload(), nil
 }
 
-func (c *client) GetObject(ctx context.Context, repoID, ref, path string, writer io.Writer) (*objects.GetObjectOK, error) {
-	params := &objects.GetObjectParams{
-		Ref:        ref,
-		Path:       path,
-		Repository: repoID,
+func (c *client) DeleteBranch(ctx context.Context, repository, branchID string) error {
+	_, err := c.remote.Branches.DeleteBranch(&branches.DeleteBranchParams{
+		Branch:     branchID,
+		Repository: repository,
 		Context:    ctx,
-	}
-	resp, err := c.remote.Objects.GetObject(params, c.auth, writer)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
+	}, c.auth)
+	return err
 }
 
-func (c *client) UploadObject(ctx context.Context, repoID, branchID, path string, r io.Reader) (*models.ObjectStats, error) {
-	resp, err := c.remote.Objects.UploadObject(&objects.UploadObjectParams{
+func (c *client) RenameBranch(ctx context.Context, repository, branchID, newName string) error {
+	_, err := c.remote.Branches.RenameBranch(&branches.RenameBranchParams{
 		Branch:     branchID,
-		Content:    runtime.NamedReader("content", r),
-		Path:       path,
-		Repository: repoID,
+		Rename:     &models.BranchRename{Name: swag.String(newName)},
+		Repository: repository,
 		Context:    ctx,
 	}, c.auth)
-	if err != nil {
-		return nil, err
-	}
-	return resp.GetPayload(), nil
+	return err
 }
 
-func (c *client) DeleteObject(ctx context.Context, repository, branchID, path string) error {
-	_, err := c.remote.Objects.DeleteObject(&objects.DeleteObjectParams{
+func (c *client) RevertBranch(ctx context.Context, repository, branchID string, revertProps *models.RevertCreation) error {
+	_, err := c.remote.Branches.RevertBranch(&branches.RevertBranchParams{
 		Branch:     branchID,
-		Path:       path,
+		Revert:     revertProps,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	return err
+}
+
+func (c *client) SetContinuousExport(ctx context.Context, repository, branchID string, config *models.ContinuousExportConfiguration) error {
+	_, err := c.remote.Export.SetContinuousExport(&export.SetContinuousExportParams{
+		Branch:     branchID,
+		Config:     config,
 		Repository: repository,
 		Context:    ctx,
+		HTTPClient: nil,
 	}, c.auth)
 	return err
 }
 
+func (c *client) GetContinuousExport(ctx context.Context, repository, branchID, prefix string) (*models.ContinuousExportConfiguration, error) {
+	resp, err := c.remote.Export.GetContinuousExport(&export.GetContinuousExportParams{
+		Branch:     branchID,
+		Prefix:     swag.String(prefix),
+		Repository: repository,
+		Context:    ctx,
+		HTTPClient: nil,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), err
+}
+
+func (c *client) ListContinuousExports(ctx context.Context, repository, branchID string) ([]*models.ContinuousExportConfiguration, error) {
+	resp, err := c.remote.Export.ListContinuousExports(&export.ListContinuousExportsParams{
+		Branch:     branchID,
+		Repository: repository,
+		Context:    ctx,
+		HTTPClient: nil,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) ListExportRuns(ctx context.Context, repository, branchID, after string, amount int) ([]*models.ExportRun, *models.Pagination, error) {
+	resp, err := c.remote.Export.ListExportRuns(&export.ListExportRunsParams{
+		Branch:     branchID,
+		Repository: repository,
+		After:      swag.String(after),
+		Amount:     swag.Int64(int64(amount)),
+		Context:    ctx,
+		HTTPClient: nil,
+	}, c.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.GetPayload().Results, resp.GetPayload().Pagination, nil
+}
+
+func (c *client) ExportRef(ctx context.Context, repository, ref, destination string) (string, error) {
+	resp, err := c.remote.Export.ExportRef(&export.ExportRefParams{
+		Repository: repository,
+		Ref:        ref,
+		Export:     &models.RefExportCreation{Destination: swag.String(destination)},
+		Context:    ctx,
+		HTTPClient: nil,
+	}, c.auth)
+	if err != nil {
+		return "", err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) GetRefExport(ctx context.Context, repository, exportID string) (*models.RefExport, error) {
+	resp, err := c.remote.Export.GetRefExport(&export.GetRefExportParams{
+		Repository: repository,
+		ExportID:   exportID,
+		Context:    ctx,
+		HTTPClient: nil,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) RunExport(ctx context.Context, repository, branchID string) (string, error) {
+	resp, err := c.remote.Export.Run(&export.RunParams{
+		Branch:     branchID,
+		Repository: repository,
+		Context:    ctx,
+		HTTPClient: nil,
+	}, c.auth)
+	if err != nil {
+		return "", err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) RepairExport(ctx context.Context, repository, branchID string) (string, error) {
+	resp, err := c.remote.Export.Repair(&export.RepairParams{
+		Branch:     branchID,
+		Repository: repository,
+		Context:    ctx,
+		HTTPClient: nil,
+	}, c.auth)
+	if err != nil {
+		return "", err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) GetExportPlan(ctx context.Context, repository, branchID, ref string, amount int) (*models.ExportPlan, error) {
+	params := &export.GetExportPlanParams{
+		Repository: repository,
+		Branch:     branchID,
+		Amount:     swag.Int64(int64(amount)),
+		Context:    ctx,
+	}
+	if ref != "" {
+		params.Ref = swag.String(ref)
+	}
+	resp, err := c.remote.Export.GetExportPlan(params, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) GetExportDrift(ctx context.Context, repository, branchID string) (*models.ExportDriftReport, error) {
+	resp, err := c.remote.Export.GetExportDrift(&export.GetExportDriftParams{
+		Branch:     branchID,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) ReconcileExportDrift(ctx context.Context, repository, branchID string) (*models.ExportDriftReport, error) {
+	resp, err := c.remote.Export.ReconcileExportDrift(&export.ReconcileExportDriftParams{
+		Branch:     branchID,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) ListHookRuns(ctx context.Context, repository, commitID, branch, status, after string, amount int) ([]*models.HookRun, *models.Pagination, error) {
+	params := &hooks.ListHookRunsParams{
+		Repository: repository,
+		After:      swag.String(after),
+		Amount:     swag.Int64(int64(amount)),
+		Context:    ctx,
+	}
+	if commitID != "" {
+		params.Commit = swag.String(commitID)
+	}
+	if branch != "" {
+		params.Branch = swag.String(branch)
+	}
+	if status != "" {
+		params.Status = swag.String(status)
+	}
+	resp, err := c.remote.Hooks.ListHookRuns(params, c.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.GetPayload().Results, resp.GetPayload().Pagination, nil
+}
+
+func (c *client) GetHookRun(ctx context.Context, repository string, runID int64) (*models.HookRun, error) {
+	resp, err := c.remote.Hooks.GetHookRun(&hooks.GetHookRunParams{
+		Repository: repository,
+		RunID:      runID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) RetryHookRun(ctx context.Context, repository string, runID int64) (*models.HookRun, error) {
+	resp, err := c.remote.Hooks.RetryHookRun(&hooks.RetryHookRunParams{
+		Repository: repository,
+		RunID:      runID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) DryRunHooks(ctx context.Context, repository string, event *models.HookDryRun) ([]*models.HookRun, error) {
+	resp, err := c.remote.Hooks.DryRunHooks(&hooks.DryRunHooksParams{
+		Repository: repository,
+		Event:      event,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload().Results, nil
+}
+
+func (c *client) CreateCommitJob(ctx context.Context, repository, branchID, message string, metadata map[string]string) (*models.Job, error) {
+	resp, err := c.remote.Jobs.CreateCommitJob(&jobs.CreateCommitJobParams{
+		Branch: branchID,
+		Commit: &models.CommitCreation{
+			Message:  &message,
+			Metadata: metadata,
+		},
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) ListJobs(ctx context.Context, repository, branch, status, after string, amount int) ([]*models.Job, *models.Pagination, error) {
+	params := &jobs.ListJobsParams{
+		Repository: repository,
+		After:      swag.String(after),
+		Amount:     swag.Int64(int64(amount)),
+		Context:    ctx,
+	}
+	if branch != "" {
+		params.Branch = swag.String(branch)
+	}
+	if status != "" {
+		params.Status = swag.String(status)
+	}
+	resp, err := c.remote.Jobs.ListJobs(params, c.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.GetPayload().Results, resp.GetPayload().Pagination, nil
+}
+
+func (c *client) GetJob(ctx context.Context, repository string, jobID int64) (*models.Job, error) {
+	resp, err := c.remote.Jobs.GetJob(&jobs.GetJobParams{
+		Repository: repository,
+		JobID:      jobID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) ResumeJob(ctx context.Context, repository string, jobID int64) (*models.Job, error) {
+	resp, err := c.remote.Jobs.ResumeJob(&jobs.ResumeJobParams{
+		Repository: repository,
+		JobID:      jobID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) AbortJob(ctx context.Context, repository string, jobID int64) (*models.Job, error) {
+	resp, err := c.remote.Jobs.AbortJob(&jobs.AbortJobParams{
+		Repository: repository,
+		JobID:      jobID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) Commit(ctx context.Context, repository, branchID, message string, metadata map[string]string, prefixes []string) (*models.Commit, error) {
+	commit, err := c.remote.Commits.Commit(&commits.CommitParams{
+		Branch: branchID,
+		Commit: &models.CommitCreation{
+			Message:  &message,
+			Metadata: metadata,
+			Prefixes: prefixes,
+		},
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return commit.GetPayload(), nil
+}
+
+func (c *client) AmendCommit(ctx context.Context, repository, branchID string, amend *models.CommitAmend) (*models.Commit, error) {
+	commit, err := c.remote.Commits.AmendCommit(&commits.AmendCommitParams{
+		Branch:     branchID,
+		Commit:     amend,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return commit.GetPayload(), nil
+}
+
+func (c *client) GetCommitGraph(ctx context.Context, repository, ref string, depth int) (*models.CommitGraph, error) {
+	resp, err := c.remote.Commits.GetCommitGraph(&commits.GetCommitGraphParams{
+		Depth:      swag.Int64(int64(depth)),
+		Ref:        ref,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) GetCommit(ctx context.Context, repository, commitID string) (*models.Commit, error) {
+	commit, err := c.remote.Commits.GetCommit(&commits.GetCommitParams{
+		CommitID:   commitID,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return commit.GetPayload(), nil
+}
+
+func (c *client) GetCommitLog(ctx context.Context, repository, branchID, after string, amount int, filter catalog.CommitsFilter) ([]*models.Commit, *models.Pagination, error) {
+	params := &commits.GetBranchCommitLogParams{
+		Amount:     swag.Int64(int64(amount)),
+		After:      swag.String(after),
+		Branch:     branchID,
+		Repository: repository,
+		Context:    ctx,
+	}
+	if filter.MessageContains != "" {
+		params.Message = swag.String(filter.MessageContains)
+	}
+	if filter.MessageRegexp != "" {
+		params.MessageRegex = swag.String(filter.MessageRegexp)
+	}
+	if filter.Committer != "" {
+		params.Committer = swag.String(filter.Committer)
+	}
+	for key, value := range filter.Metadata {
+		params.Metadata = append(params.Metadata, key+"="+value)
+	}
+	if !filter.Since.IsZero() {
+		params.Since = swag.Int64(filter.Since.Unix())
+	}
+	if !filter.Until.IsZero() {
+		params.Until = swag.Int64(filter.Until.Unix())
+	}
+	resp, err := c.remote.Commits.GetBranchCommitLog(params, c.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.GetPayload().Results, resp.GetPayload().Pagination, nil
+}
+
+func (c *client) GetBranchChanges(ctx context.Context, repository, branchID, since string, amount int, diffSummary bool) (*models.BranchChanges, error) {
+	resp, err := c.remote.Commits.GetBranchChanges(&commits.GetBranchChangesParams{
+		Amount:      swag.Int64(int64(amount)),
+		Since:       swag.String(since),
+		DiffSummary: swag.Bool(diffSummary),
+		Branch:      branchID,
+		Repository:  repository,
+		Context:     ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) CreateDataLineage(ctx context.Context, repository, ref string, sources []*models.DataLineageSource) error {
+	_, err := c.remote.Commits.CreateDataLineage(&commits.CreateDataLineageParams{
+		Lineage: &models.DataLineageCreation{
+			Sources: sources,
+		},
+		Ref:        ref,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	return err
+}
+
+func (c *client) WalkDataLineage(ctx context.Context, repository, ref, direction string, depth int) ([]*models.DataLineageEdge, error) {
+	resp, err := c.remote.Commits.WalkDataLineage(&commits.WalkDataLineageParams{
+		Depth:      swag.Int64(int64(depth)),
+		Direction:  swag.String(direction),
+		Ref:        ref,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload().Results, nil
+}
+
+func (c *client) DiffRefs(ctx context.Context, repository, leftRef, rightRef, after string, amount int) ([]*models.Diff, *models.Pagination, error) {
+	diff, err := c.remote.Refs.DiffRefs(&refs.DiffRefsParams{
+		After:      swag.String(after),
+		Amount:     swag.Int64(int64(amount)),
+		LeftRef:    leftRef,
+		Repository: repository,
+		RightRef:   rightRef,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload := diff.GetPayload()
+	return payload.Results, payload.Pagination, nil
+}
+
+func (c *client) DiffBranches(ctx context.Context, repository, leftBranch, rightBranch, after string, amount int) ([]*models.Diff, *models.Pagination, error) {
+	diff, err := c.remote.Refs.DiffRefs(&refs.DiffRefsParams{
+		After:       swag.String(after),
+		Amount:      swag.Int64(int64(amount)),
+		LeftRef:     leftBranch,
+		Repository:  repository,
+		RightRef:    rightBranch,
+		Uncommitted: swag.Bool(true),
+		Context:     ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload := diff.GetPayload()
+	return payload.Results, payload.Pagination, nil
+}
+
+func (c *client) DiffRefsSummary(ctx context.Context, repository, leftRef, rightRef string) (*models.DiffSummary, error) {
+	resp, err := c.remote.Refs.DiffRefsSummary(&refs.DiffRefsSummaryParams{
+		LeftRef:    leftRef,
+		Repository: repository,
+		RightRef:   rightRef,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) DiffRefsChecksums(ctx context.Context, repository, leftRef, rightRef string) (*models.ChecksumDiffSummary, error) {
+	resp, err := c.remote.Refs.DiffRefsChecksums(&refs.DiffRefsChecksumsParams{
+		LeftRef:    leftRef,
+		Repository: repository,
+		RightRef:   rightRef,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) DiffRefsCounts(ctx context.Context, repository, leftRef, rightRef string) (*models.DiffCounts, error) {
+	diff, err := c.remote.Refs.DiffRefs(&refs.DiffRefsParams{
+		LeftRef:     leftRef,
+		Repository:  repository,
+		RightRef:    rightRef,
+		SummaryOnly: swag.Bool(true),
+		Context:     ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return diff.GetPayload().Summary, nil
+}
+
+func (c *client) Merge(ctx context.Context, repository, leftRef, rightRef string, merge *models.Merge) (*models.MergeResult, error) {
+	statusOK, err := c.remote.Refs.MergeIntoBranch(&refs.MergeIntoBranchParams{
+		Merge:          merge,
+		DestinationRef: leftRef,
+		SourceRef:      rightRef,
+		Repository:     repository,
+		Context:        ctx,
+	}, c.auth)
+
+	if err == nil {
+		return statusOK.Payload, nil
+	}
+	conflict, ok := err.(*refs.MergeIntoBranchConflict)
+	if ok {
+		return conflict.Payload, catalog.ErrConflictFound
+	}
+	return nil, err
+}
+
+func (c *client) MergePreview(ctx context.Context, repository, sourceRef, destinationRef string) (*models.MergePreview, error) {
+	resp, err := c.remote.Refs.MergePreview(&refs.MergePreviewParams{
+		Repository:     repository,
+		SourceRef:      sourceRef,
+		DestinationRef: destinationRef,
+		Context:        ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) DiffBranch(ctx context.Context, repoID, branch, prefix string, after string, amount int) ([]*models.Diff, *models.Pagination, error) {
+	diff, err := c.remote.Branches.DiffBranch(&branches.DiffBranchParams{
+		After:      swag.String(after),
+		Amount:     swag.Int64(int64(amount)),
+		Prefix:     swag.String(prefix),
+		Branch:     branch,
+		Repository: repoID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload := diff.GetPayload()
+	return payload.Results, payload.Pagination, nil
+}
+
+func (c *client) DiffBranchCounts(ctx context.Context, repoID, branch string) (*models.DiffCounts, error) {
+	diff, err := c.remote.Branches.DiffBranch(&branches.DiffBranchParams{
+		Branch:      branch,
+		Repository:  repoID,
+		SummaryOnly: swag.Bool(true),
+		Context:     ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return diff.GetPayload().Summary, nil
+}
+
+func (c *client) Symlink(ctx context.Context, repoID, branch, path string) (string, error) {
+	resp, err := c.remote.Metadata.CreateSymlink(&metadata.CreateSymlinkParams{
+		Location:   swag.String(path),
+		Branch:     branch,
+		Repository: repoID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return "", err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) GetRetentionPolicy(ctx context.Context, repository string) (*models.RetentionPolicyWithCreationDate, error) {
+	policy, err := c.remote.Retention.GetRetentionPolicy(&retention.GetRetentionPolicyParams{
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return policy.GetPayload(), nil
+}
+
+func (c *client) UpdateRetentionPolicy(ctx context.Context, repository string, policy *models.RetentionPolicy) error {
+	_, err := c.remote.Retention.UpdateRetentionPolicy(&retention.UpdateRetentionPolicyParams{
+		Repository: repository,
+		Policy:     policy,
+		Context:    ctx,
+	}, c.auth)
+	return err
+}
+
+func (c *client) StatObject(ctx context.Context, repoID, ref, path string) (*models.ObjectStats, error) {
+	resp, err := c.remote.Objects.StatObject(&objects.StatObjectParams{
+		Ref:        ref,
+		Path:       path,
+		Repository: repoID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) GetObjectHistory(ctx context.Context, repository, ref, path, after string, amount int) ([]*models.ObjectHistoryRecord, *models.Pagination, error) {
+	resp, err := c.remote.Objects.GetObjectHistory(&objects.GetObjectHistoryParams{
+		After:      swag.String(after),
+		Amount:     swag.Int64(int64(amount)),
+		Path:       path,
+		Ref:        ref,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.GetPayload().Results, resp.GetPayload().Pagination, nil
+}
+
+func (c *client) GetPrefixStats(ctx context.Context, repository, ref, prefix string, depth int) ([]*models.PrefixStats, error) {
+	resp, err := c.remote.Objects.GetPrefixStats(&objects.GetPrefixStatsParams{
+		Depth:      swag.Int64(int64(depth)),
+		Prefix:     swag.String(prefix),
+		Ref:        ref,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload().Results, nil
+}
+
+func (c *client) StatObjects(ctx context.Context, repoID string, refs []*models.ObjectStatRef) ([]*models.ObjectStatBatchResult, error) {
+	resp, err := c.remote.Objects.StatObjects(&objects.StatObjectsParams{
+		Objects:    &models.ObjectStatBatchRequest{Objects: refs},
+		Repository: repoID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload().Results, nil
+}
+
+func (c *client) PresignObject(ctx context.Context, repository, ref, path, method string, expires time.Duration) (*models.PresignedURL, error) {
+	resp, err := c.remote.Objects.PresignObject(&objects.PresignObjectParams{
+		Expires:    swag.Int64(int64(expires / time.Second)),
+		Method:     swag.String(method),
+		Path:       path,
+		Ref:        ref,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) PreviewObject(ctx context.Context, repoID, ref, path string, maxBytes, maxRows int) (*models.ObjectPreview, error) {
+	resp, err := c.remote.Objects.PreviewObject(&objects.PreviewObjectParams{
+		Ref:        ref,
+		Path:       path,
+		Repository: repoID,
+		MaxBytes:   swag.Int64(int64(maxBytes)),
+		MaxRows:    swag.Int64(int64(maxRows)),
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) GetObjectSchema(ctx context.Context, repoID, ref, path string, maxRows int) (*models.ObjectSchema, error) {
+	resp, err := c.remote.Objects.GetObjectSchema(&objects.GetObjectSchemaParams{
+		Ref:        ref,
+		Path:       path,
+		Repository: repoID,
+		MaxRows:    swag.Int64(int64(maxRows)),
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) ListObjects(ctx context.Context, repoID, ref, prefix, after string, amount int) ([]*models.ObjectStats, *models.Pagination, error) {
+	resp, err := c.remote.Objects.ListObjects(&objects.ListObjectsParams{
+		After:      swag.String(after),
+		Amount:     swag.Int64(int64(amount)),
+		Ref:        ref,
+		Repository: repoID,
+		Prefix:     swag.String(prefix),
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.GetPayload().Results, resp.GetPayload().Pagination, nil
+}
+
+func (c *client) GetObject(ctx context.Context, repoID, ref, path string, writer io.Writer) (*objects.GetObjectOK, error) {
+	params := &objects.GetObjectParams{
+		Ref:        ref,
+		Path:       path,
+		Repository: repoID,
+		Context:    ctx,
+	}
+	resp, err := c.remote.Objects.GetObject(params, c.auth, writer)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *client) UploadObject(ctx context.Context, repoID, branchID, path string, r io.Reader) (*models.ObjectStats, error) {
+	resp, err := c.remote.Objects.UploadObject(&objects.UploadObjectParams{
+		Branch:     branchID,
+		Content:    runtime.NamedReader("content", r),
+		Path:       path,
+		Repository: repoID,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
+func (c *client) DeleteObject(ctx context.Context, repository, branchID, path string) error {
+	_, err := c.remote.Objects.DeleteObject(&objects.DeleteObjectParams{
+		Branch:     branchID,
+		Path:       path,
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	return err
+}
+
+func (c *client) DeleteObjects(ctx context.Context, repository, branchID string, paths []string) ([]*models.ObjectDeleteBatchResult, error) {
+	resp, err := c.remote.Objects.DeleteObjects(&objects.DeleteObjectsParams{
+		Branch:     branchID,
+		Paths:      &models.ObjectDeleteBatchRequest{Paths: paths},
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload().Results, nil
+}
+
+func (c *client) StageObjects(ctx context.Context, repository, branchID string, entries []*models.ObjectStageCreation) error {
+	_, err := c.remote.Objects.StageObjects(&objects.StageObjectsParams{
+		Branch:     branchID,
+		Entries:    &models.ObjectStageBatchRequest{Entries: entries},
+		Repository: repository,
+		Context:    ctx,
+	}, c.auth)
+	return err
+}
+
+func (c *client) CopyObject(ctx context.Context, sourceRepository, sourceRef, sourcePath, repository, branchID, path string) (*models.ObjectStats, error) {
+	resp, err := c.remote.Objects.CopyObject(&objects.CopyObjectParams{
+		Branch:     branchID,
+		Path:       path,
+		Repository: repository,
+		Source: &models.ObjectCopyCreation{
+			SourceRepository: sourceRepository,
+			SourceRef:        swag.String(sourceRef),
+			SourcePath:       swag.String(sourcePath),
+		},
+		Context: ctx,
+	}, c.auth)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload(), nil
+}
+
 func NewClient(endpointURL, accessKeyID, secretAccessKey string) (Client, error) {
 	parsedURL, err := url.Parse(endpointURL)
 	if err != nil {
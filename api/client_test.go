@@ -23,10 +23,10 @@ func TestNewClient(t *testing.T) {
 
 	client, err := NewClient(server.URL, "key", "secret")
 	testutil.Must(t, err)
-	_, _, _ = client.ListRepositories(context.Background(), "", 0)
+	_, _, _ = client.ListRepositories(context.Background(), "", 0, false)
 
 	client, err = NewClient(server.URL+genclient.DefaultBasePath, "key2", "secret2")
 	testutil.Must(t, err)
-	_, _, _ = client.ListRepositories(context.Background(), "", 0)
+	_, _, _ = client.ListRepositories(context.Background(), "", 0, false)
 
 }
@@ -0,0 +1,305 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/treeverse/lakefs/activity"
+	"github.com/treeverse/lakefs/api/gen/models"
+	"github.com/treeverse/lakefs/api/grpcapi"
+	"github.com/treeverse/lakefs/auth"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/dedup"
+	"github.com/treeverse/lakefs/export"
+	"github.com/treeverse/lakefs/hooks"
+	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/notifications"
+	"github.com/treeverse/lakefs/parade"
+	"github.com/treeverse/lakefs/permissions"
+	"github.com/treeverse/lakefs/retention"
+	"github.com/treeverse/lakefs/stats"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer serves the Catalog service of the gRPC API with the controller of the REST API,
+// so calls are authorized by the same policies and commits and merges run the same hooks
+type grpcServer struct {
+	c            *Controller
+	authenticate func(accessKey, secretKey string) (*models.User, error)
+}
+
+// NewGRPCServer returns the server of the gRPC API, served alongside the REST API
+func NewGRPCServer(cataloger catalog.Cataloger,
+	blockStore block.Adapter,
+	authService auth.Service,
+	metadataManager auth.MetadataManager,
+	stats stats.Collector,
+	retention retention.Service,
+	migrator db.Migrator,
+	parade parade.Parade,
+	exportDestinations *export.Destinations,
+	dedupCleaner *dedup.Cleaner,
+	activityService activity.Service,
+	notifier *notifications.Notifier,
+	subscriptions notifications.SubscriptionService,
+	hooksService *hooks.Service,
+	gateway S3GatewayParams,
+	logger logging.Logger,
+	opts ...grpc.ServerOption,
+) *grpc.Server {
+	logger.Info("initialized gRPC server")
+	c := NewController(cataloger, authService, blockStore, stats, retention, parade, exportDestinations, dedupCleaner, metadataManager, migrator, stats, activityService, notifier, subscriptions, hooksService, gateway, logger)
+	return grpcapi.NewServer(&grpcServer{c: c, authenticate: basicAuth(authService)}, opts...)
+}
+
+// ReadOnlyUnaryInterceptor rejects gRPC API calls that may modify data, like
+// ReadOnlyMiddleware does for the REST API
+func ReadOnlyUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !grpcapi.IsReadOnlyMethod(info.FullMethod) {
+		return nil, status.Error(codes.FailedPrecondition, readOnlyModeMessage)
+	}
+	return handler(ctx, req)
+}
+
+// setup authenticates the user calling with ctx, authorizes it for permissions and counts the
+// call against the request rate quota of repository
+func (s *grpcServer) setup(ctx context.Context, repository string, permissions []permissions.Permission) (*Dependencies, *models.User, error) {
+	accessKey, secretKey, ok := grpcapi.Credentials(ctx)
+	if !ok {
+		return nil, nil, status.Error(codes.Unauthenticated, "missing credentials")
+	}
+	user, err := s.authenticate(accessKey, secretKey)
+	if err != nil {
+		return nil, nil, status.Error(codes.Unauthenticated, ErrAuthenticationFailed.Error())
+	}
+	deps, err := s.c.setupContext(ctx, user, accessKey, permissions)
+	if err != nil {
+		return nil, nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	err = deps.Cataloger.CheckRequestRate(ctx, repository)
+	if errors.Is(err, catalog.ErrRequestRateExceeded) {
+		return nil, nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		deps.logger.WithError(err).WithField("repository", repository).Debug("could not check request rate")
+	}
+	return deps, user, nil
+}
+
+// grpcError returns the gRPC status of err
+func grpcError(err error) error {
+	var code codes.Code
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, ErrAuthorization),
+		errors.Is(err, catalog.ErrRepositoryReadOnly):
+		code = codes.PermissionDenied
+	case errors.Is(err, catalog.ErrQuotaExceeded),
+		errors.Is(err, catalog.ErrRequestRateExceeded):
+		code = codes.ResourceExhausted
+	case errors.Is(err, catalog.ErrInvalidReference),
+		errors.Is(err, catalog.ErrInvalidValue),
+		errors.Is(err, catalog.ErrUnsupportedDelimiter):
+		code = codes.InvalidArgument
+	case errors.Is(err, hooks.ErrHookFailed),
+		errors.Is(err, hooks.ErrInvalidAction),
+		errors.Is(err, catalog.ErrValidationFailed),
+		errors.Is(err, catalog.ErrCommitLimitExceeded),
+		errors.Is(err, catalog.ErrNothingToCommit),
+		errors.Is(err, catalog.ErrNoDifferenceWasFound),
+		errors.Is(err, catalog.ErrUnsupportedRelation):
+		code = codes.FailedPrecondition
+	case errors.Is(err, catalog.ErrConflictFound):
+		code = codes.Aborted
+	case errors.Is(err, catalog.ErrFeatureNotSupported):
+		code = codes.Unimplemented
+	default:
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
+
+func (s *grpcServer) StatObject(ctx context.Context, req *grpcapi.StatObjectRequest) (*grpcapi.ObjectStats, error) {
+	deps, _, err := s.setup(ctx, req.Repository, []permissions.Permission{
+		{
+			Action:   permissions.ReadObjectAction,
+			Resource: permissions.ObjectArn(req.Repository, req.Path),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	deps.LogAction("stat_object")
+	entry, err := deps.Cataloger.GetEntry(ctx, req.Repository, req.Ref, req.Path, catalog.GetEntryParams{ReturnExpired: true})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if entry.Expired {
+		return nil, status.Error(codes.NotFound, catalog.ErrExpired.Error())
+	}
+	return newGRPCObjectStats(entry), nil
+}
+
+func newGRPCObjectStats(entry *catalog.Entry) *grpcapi.ObjectStats {
+	if entry.CommonLevel {
+		return &grpcapi.ObjectStats{Path: entry.Path, CommonPrefix: true}
+	}
+	var mtime int64
+	if !entry.CreationDate.IsZero() {
+		mtime = entry.CreationDate.Unix()
+	}
+	return &grpcapi.ObjectStats{
+		Path:      entry.Path,
+		Checksum:  entry.Checksum,
+		SizeBytes: entry.Size,
+		Mtime:     mtime,
+		Metadata:  entry.Metadata,
+	}
+}
+
+// ListObjects streams the listing in pages of MaxResultsPerPage entries
+func (s *grpcServer) ListObjects(req *grpcapi.ListObjectsRequest, stream grpcapi.Catalog_ListObjectsServer) error {
+	ctx := stream.Context()
+	deps, _, err := s.setup(ctx, req.Repository, []permissions.Permission{
+		{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(req.Repository),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	deps.LogAction("list_objects")
+	after := req.After
+	remaining := req.Amount
+	for {
+		limit := MaxResultsPerPage
+		if remaining > 0 && remaining < int64(limit) {
+			limit = int(remaining)
+		}
+		entries, hasMore, err := deps.Cataloger.ListEntries(ctx, req.Repository, req.Ref, req.Prefix, after, req.Delimiter, limit)
+		if err != nil {
+			return grpcError(err)
+		}
+		for _, entry := range entries {
+			if err := stream.Send(newGRPCObjectStats(entry)); err != nil {
+				return err
+			}
+			after = entry.Path
+		}
+		if remaining > 0 {
+			remaining -= int64(len(entries))
+			if remaining == 0 {
+				return nil
+			}
+		}
+		if !hasMore || len(entries) == 0 {
+			return nil
+		}
+	}
+}
+
+// Diff streams the differences in pages of MaxResultsPerPage differences
+func (s *grpcServer) Diff(req *grpcapi.DiffRequest, stream grpcapi.Catalog_DiffServer) error {
+	ctx := stream.Context()
+	deps, _, err := s.setup(ctx, req.Repository, []permissions.Permission{
+		{
+			Action:   permissions.ListObjectsAction,
+			Resource: permissions.RepoArn(req.Repository),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	deps.LogAction("diff_refs")
+	after := req.After
+	for {
+		differences, hasMore, err := deps.Cataloger.Diff(ctx, req.Repository, req.LeftRef, req.RightRef, catalog.DiffParams{
+			Limit: MaxResultsPerPage,
+			After: after,
+		})
+		if err != nil {
+			return grpcError(err)
+		}
+		for _, d := range differences {
+			err := stream.Send(&grpcapi.Diff{
+				Path:         d.Path,
+				Type:         transformDifferenceTypeToString(d.Type),
+				CommonPrefix: strings.HasSuffix(d.Path, catalog.DefaultPathDelimiter),
+			})
+			if err != nil {
+				return err
+			}
+			after = d.Path
+		}
+		if !hasMore || len(differences) == 0 {
+			return nil
+		}
+	}
+}
+
+func (s *grpcServer) Commit(ctx context.Context, req *grpcapi.CommitRequest) (*grpcapi.Commit, error) {
+	deps, user, err := s.setup(ctx, req.Repository, []permissions.Permission{
+		{
+			Action:   permissions.CreateCommitAction,
+			Resource: permissions.BranchArn(req.Repository, req.Branch),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	deps.LogAction("create_commit")
+	commit, err := s.c.commit(deps, user, req.Repository, req.Branch, req.Message, req.Metadata, catalog.CommitParams{})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &grpcapi.Commit{
+		Id:           commit.Reference,
+		Parents:      commit.Parents,
+		Committer:    commit.Committer,
+		Message:      commit.Message,
+		CreationDate: commit.CreationDate.Unix(),
+		Metadata:     commit.Metadata,
+	}, nil
+}
+
+func (s *grpcServer) Merge(ctx context.Context, req *grpcapi.MergeRequest) (*grpcapi.MergeResult, error) {
+	deps, user, err := s.setup(ctx, req.Repository, []permissions.Permission{
+		{
+			Action:   permissions.CreateCommitAction,
+			Resource: permissions.BranchArn(req.Repository, req.DestinationBranch),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	deps.LogAction("merge_branches")
+	res, err := s.c.merge(deps, user, req.Repository, req.SourceRef, req.DestinationBranch, req.Message, req.Metadata, catalog.MergeParams{})
+	if errors.Is(err, catalog.ErrConflictFound) && res != nil {
+		return nil, status.Error(codes.Aborted, fmt.Sprintf("%s: %d conflicts", err, res.Summary[catalog.DifferenceTypeConflict]))
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	result := &grpcapi.MergeResult{Reference: res.Reference}
+	for k, v := range res.Summary {
+		switch k {
+		case catalog.DifferenceTypeAdded:
+			result.Added = int64(v)
+		case catalog.DifferenceTypeRemoved:
+			result.Removed = int64(v)
+		case catalog.DifferenceTypeChanged:
+			result.Changed = int64(v)
+		case catalog.DifferenceTypeConflict:
+			result.Conflict = int64(v)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,218 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/activity"
+	"github.com/treeverse/lakefs/api"
+	"github.com/treeverse/lakefs/api/grpcapi"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/hooks"
+	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/notifications"
+	"github.com/treeverse/lakefs/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const grpcBufferSize = 1024 * 1024
+
+func getGRPCClient(t *testing.T, deps *dependencies, auth grpcapi.BasicAuth, opts ...grpc.ServerOption) grpcapi.CatalogClient {
+	t.Helper()
+	srv := api.NewGRPCServer(
+		deps.cataloger,
+		deps.blocks,
+		deps.auth,
+		nil,
+		&mockCollector{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		activity.NewDBService(deps.conn),
+		nil,
+		notifications.NewDBSubscriptionService(deps.conn, deps.auth),
+		hooks.NewService(deps.cataloger, deps.blocks, hooks.NewDBRunStore(deps.conn), nil, nil, deps.auth),
+		api.S3GatewayParams{},
+		logging.Default(),
+		opts...,
+	)
+	listener := bufconn.Listen(grpcBufferSize)
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+	auth.Insecure = true
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(auth),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return grpcapi.NewCatalogClient(conn)
+}
+
+func TestGRPCServer(t *testing.T) {
+	_, deps := getHandler(t, "")
+	creds := createDefaultAdminUser(deps.auth, t)
+	clt := getGRPCClient(t, deps, grpcapi.BasicAuth{AccessKeyID: creds.AccessKeyID, SecretAccessKey: creds.AccessSecretKey})
+
+	ctx := context.Background()
+	_, err := deps.cataloger.CreateRepository(ctx, "repo1", "ns1", "master")
+	testutil.Must(t, err)
+	for _, p := range []string{"foo/a", "foo/b", "foo/c/d", "bar"} {
+		testutil.Must(t, deps.cataloger.CreateEntry(ctx, "repo1", "master", catalog.Entry{
+			Path:            p,
+			PhysicalAddress: "address_of_" + p,
+			CreationDate:    time.Now(),
+			Size:            42,
+			Checksum:        "checksum_of_" + p,
+			Metadata:        catalog.Metadata{"key": "value"},
+		}, catalog.CreateEntryParams{}))
+	}
+
+	t.Run("stat object", func(t *testing.T) {
+		stats, err := clt.StatObject(ctx, &grpcapi.StatObjectRequest{Repository: "repo1", Ref: "master", Path: "foo/a"})
+		if err != nil {
+			t.Fatalf("StatObject() unexpected error: %s", err)
+		}
+		if stats.Path != "foo/a" || stats.SizeBytes != 42 || stats.Checksum != "checksum_of_foo/a" || stats.Metadata["key"] != "value" {
+			t.Fatalf("StatObject() got %+v", stats)
+		}
+		_, err = clt.StatObject(ctx, &grpcapi.StatObjectRequest{Repository: "repo1", Ref: "master", Path: "foo/missing"})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("StatObject() of missing object err=%v, expected NotFound", err)
+		}
+	})
+
+	t.Run("list objects", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			req   *grpcapi.ListObjectsRequest
+			paths []string
+		}{
+			{
+				name:  "all",
+				req:   &grpcapi.ListObjectsRequest{Repository: "repo1", Ref: "master"},
+				paths: []string{"bar", "foo/a", "foo/b", "foo/c/d"},
+			},
+			{
+				name:  "delimited",
+				req:   &grpcapi.ListObjectsRequest{Repository: "repo1", Ref: "master", Prefix: "foo/", Delimiter: "/"},
+				paths: []string{"foo/a", "foo/b", "foo/c/"},
+			},
+			{
+				name:  "after with amount",
+				req:   &grpcapi.ListObjectsRequest{Repository: "repo1", Ref: "master", After: "bar", Amount: 2},
+				paths: []string{"foo/a", "foo/b"},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				stream, err := clt.ListObjects(ctx, tt.req)
+				if err != nil {
+					t.Fatalf("ListObjects() unexpected error: %s", err)
+				}
+				var paths []string
+				for {
+					stats, err := stream.Recv()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					if err != nil {
+						t.Fatalf("Recv() unexpected error: %s", err)
+					}
+					paths = append(paths, stats.Path)
+				}
+				if len(paths) != len(tt.paths) {
+					t.Fatalf("ListObjects() got %v, expected %v", paths, tt.paths)
+				}
+				for i := range paths {
+					if paths[i] != tt.paths[i] {
+						t.Fatalf("ListObjects() got %v, expected %v", paths, tt.paths)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("commit and diff", func(t *testing.T) {
+		commit, err := clt.Commit(ctx, &grpcapi.CommitRequest{
+			Repository: "repo1",
+			Branch:     "master",
+			Message:    "first",
+			Metadata:   map[string]string{"source": "grpc"},
+		})
+		if err != nil {
+			t.Fatalf("Commit() unexpected error: %s", err)
+		}
+		if commit.Id == "" || commit.Message != "first" || commit.Committer != "admin" || commit.Metadata["source"] != "grpc" {
+			t.Fatalf("Commit() got %+v", commit)
+		}
+		_, err = deps.cataloger.CreateBranch(ctx, "repo1", "branch1", "master")
+		testutil.Must(t, err)
+		testutil.Must(t, deps.cataloger.CreateEntry(ctx, "repo1", "branch1", catalog.Entry{
+			Path:            "foo/e",
+			PhysicalAddress: "address_of_foo/e",
+			CreationDate:    time.Now(),
+			Size:            1,
+			Checksum:        "checksum_of_foo/e",
+		}, catalog.CreateEntryParams{}))
+		_, err = clt.Commit(ctx, &grpcapi.CommitRequest{Repository: "repo1", Branch: "branch1", Message: "second"})
+		testutil.Must(t, err)
+
+		stream, err := clt.Diff(ctx, &grpcapi.DiffRequest{Repository: "repo1", LeftRef: "branch1", RightRef: "master"})
+		if err != nil {
+			t.Fatalf("Diff() unexpected error: %s", err)
+		}
+		d, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() unexpected error: %s", err)
+		}
+		if d.Path != "foo/e" || d.Type != "added" {
+			t.Fatalf("Diff() got %+v, expected foo/e added", d)
+		}
+		if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+			t.Fatalf("Diff() expected a single difference, got err=%v", err)
+		}
+
+		res, err := clt.Merge(ctx, &grpcapi.MergeRequest{Repository: "repo1", SourceRef: "branch1", DestinationBranch: "master"})
+		if err != nil {
+			t.Fatalf("Merge() unexpected error: %s", err)
+		}
+		if res.Reference == "" || res.Added != 1 {
+			t.Fatalf("Merge() got %+v, expected 1 added", res)
+		}
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		other := getGRPCClient(t, deps, grpcapi.BasicAuth{AccessKeyID: creds.AccessKeyID, SecretAccessKey: "wrong"})
+		_, err := other.StatObject(ctx, &grpcapi.StatObjectRequest{Repository: "repo1", Ref: "master", Path: "foo/a"})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("StatObject() err=%v, expected Unauthenticated", err)
+		}
+	})
+
+	t.Run("read only", func(t *testing.T) {
+		readOnly := getGRPCClient(t, deps,
+			grpcapi.BasicAuth{AccessKeyID: creds.AccessKeyID, SecretAccessKey: creds.AccessSecretKey},
+			grpc.UnaryInterceptor(api.ReadOnlyUnaryInterceptor))
+		if _, err := readOnly.StatObject(ctx, &grpcapi.StatObjectRequest{Repository: "repo1", Ref: "master", Path: "foo/a"}); err != nil {
+			t.Fatalf("StatObject() unexpected error: %s", err)
+		}
+		_, err := readOnly.Commit(ctx, &grpcapi.CommitRequest{Repository: "repo1", Branch: "master", Message: "nothing"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("Commit() err=%v, expected FailedPrecondition", err)
+		}
+	})
+}
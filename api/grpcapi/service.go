@@ -0,0 +1,87 @@
+// Package grpcapi is the gRPC API of lakeFS: the Catalog service of catalog.proto, serving the
+// core catalog operations to high-throughput clients over protobuf.  The messages, server
+// and client in catalog.pb.go are generated from catalog.proto by protoc-gen-go ("make
+// gen-grpc"), so the service is called by clients generated from catalog.proto in any
+// language, and by NewCatalogClient in Go.
+package grpcapi
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	ServiceName = "lakefs.catalog.v1.Catalog"
+
+	StatObjectMethod  = "/" + ServiceName + "/StatObject"
+	ListObjectsMethod = "/" + ServiceName + "/ListObjects"
+	DiffMethod        = "/" + ServiceName + "/Diff"
+	CommitMethod      = "/" + ServiceName + "/Commit"
+	MergeMethod       = "/" + ServiceName + "/Merge"
+
+	authorizationKey   = "authorization"
+	basicAuthPrefix    = "Basic "
+	credentialsFields  = 2
+	credentialsDivider = ":"
+)
+
+// IsReadOnlyMethod returns true for the methods of the Catalog service that don't modify
+// data
+func IsReadOnlyMethod(fullMethod string) bool {
+	switch fullMethod {
+	case StatObjectMethod, ListObjectsMethod, DiffMethod:
+		return true
+	}
+	return false
+}
+
+// NewServer returns a gRPC server serving srv as the Catalog service
+func NewServer(srv CatalogServer, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(opts...)
+	RegisterCatalogServer(s, srv)
+	return s
+}
+
+// Credentials returns the access key and secret sent by the client in the metadata of ctx
+func Credentials(ctx context.Context) (string, string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", "", false
+	}
+	for _, value := range md.Get(authorizationKey) {
+		if !strings.HasPrefix(value, basicAuthPrefix) {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, basicAuthPrefix))
+		if err != nil {
+			return "", "", false
+		}
+		fields := strings.SplitN(string(decoded), credentialsDivider, credentialsFields)
+		if len(fields) != credentialsFields {
+			return "", "", false
+		}
+		return fields[0], fields[1], true
+	}
+	return "", "", false
+}
+
+// BasicAuth sends the access key and secret of a lakeFS user with each call
+type BasicAuth struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// Insecure allows sending the credentials over connections without transport security
+	Insecure bool
+}
+
+func (a BasicAuth) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	credentials := base64.StdEncoding.EncodeToString([]byte(a.AccessKeyID + credentialsDivider + a.SecretAccessKey))
+	return map[string]string{authorizationKey: basicAuthPrefix + credentials}, nil
+}
+
+func (a BasicAuth) RequireTransportSecurity() bool {
+	return !a.Insecure
+}
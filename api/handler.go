@@ -15,6 +15,7 @@ import (
 	"github.com/go-openapi/loads"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/treeverse/lakefs/activity"
 	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/api/gen/restapi"
 	"github.com/treeverse/lakefs/api/gen/restapi/operations"
@@ -23,8 +24,11 @@ import (
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 	"github.com/treeverse/lakefs/dedup"
+	"github.com/treeverse/lakefs/export"
+	"github.com/treeverse/lakefs/hooks"
 	"github.com/treeverse/lakefs/httputil"
 	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/notifications"
 	"github.com/treeverse/lakefs/retention"
 	_ "github.com/treeverse/lakefs/statik"
 	"github.com/treeverse/lakefs/stats"
@@ -43,18 +47,25 @@ var (
 )
 
 type Handler struct {
-	metadataManager auth.MetadataManager
-	cataloger       catalog.Cataloger
-	blockStore      block.Adapter
-	authService     auth.Service
-	stats           stats.Collector
-	retention       retention.Service
-	parade          parade.Parade
-	migrator        db.Migrator
-	apiServer       *restapi.Server
-	handler         *http.ServeMux
-	dedupCleaner    *dedup.Cleaner
-	logger          logging.Logger
+	metadataManager    auth.MetadataManager
+	cataloger          catalog.Cataloger
+	blockStore         block.Adapter
+	authService        auth.Service
+	stats              stats.Collector
+	retention          retention.Service
+	parade             parade.Parade
+	exportDestinations *export.Destinations
+	migrator           db.Migrator
+	apiServer          *restapi.Server
+	handler            *http.ServeMux
+	dedupCleaner       *dedup.Cleaner
+	activity           activity.Service
+	notifier           *notifications.Notifier
+	subscriptions      notifications.SubscriptionService
+	hooks              *hooks.Service
+	gateway            S3GatewayParams
+	readOnly           bool
+	logger             logging.Logger
 }
 
 func NewHandler(cataloger catalog.Cataloger,
@@ -65,21 +76,35 @@ func NewHandler(cataloger catalog.Cataloger,
 	retention retention.Service,
 	migrator db.Migrator,
 	parade parade.Parade,
+	exportDestinations *export.Destinations,
 	dedupCleaner *dedup.Cleaner,
+	activityService activity.Service,
+	notifier *notifications.Notifier,
+	subscriptions notifications.SubscriptionService,
+	hooksService *hooks.Service,
+	gateway S3GatewayParams,
+	readOnly bool,
 	logger logging.Logger,
 ) http.Handler {
 	logger.Info("initialized OpenAPI server")
 	s := &Handler{
-		cataloger:       cataloger,
-		blockStore:      blockStore,
-		authService:     authService,
-		metadataManager: metadataManager,
-		stats:           stats,
-		retention:       retention,
-		parade:          parade,
-		migrator:        migrator,
-		dedupCleaner:    dedupCleaner,
-		logger:          logger,
+		cataloger:          cataloger,
+		blockStore:         blockStore,
+		authService:        authService,
+		metadataManager:    metadataManager,
+		stats:              stats,
+		retention:          retention,
+		parade:             parade,
+		exportDestinations: exportDestinations,
+		migrator:           migrator,
+		dedupCleaner:       dedupCleaner,
+		activity:           activityService,
+		notifier:           notifier,
+		subscriptions:      subscriptions,
+		hooks:              hooksService,
+		gateway:            gateway,
+		readOnly:           readOnly,
+		logger:             logger,
 	}
 	s.buildAPI()
 	return s.handler
@@ -120,9 +145,14 @@ func (s *Handler) JwtTokenAuth() func(string) (*models.User, error) {
 // BasicAuth returns a function that hooks into Swagger's basic Auth provider
 // it uses the Auth.Service provided to ensure credentials are valid
 func (s *Handler) BasicAuth() func(accessKey, secretKey string) (user *models.User, err error) {
+	return basicAuth(s.authService)
+}
+
+// basicAuth returns a function authenticating the users of access keys with authService
+func basicAuth(authService auth.Service) func(accessKey, secretKey string) (user *models.User, err error) {
 	logger := logging.Default().WithField("auth", "basic")
 	return func(accessKey, secretKey string) (user *models.User, err error) {
-		credentials, err := s.authService.GetCredentials(accessKey)
+		credentials, err := authService.GetCredentials(accessKey)
 		if err != nil {
 			logger.WithError(err).WithField("access_key", accessKey).Warn("could not get access key for login")
 			return nil, ErrAuthenticationFailed
@@ -131,7 +161,7 @@ func (s *Handler) BasicAuth() func(accessKey, secretKey string) (user *models.Us
 			logger.WithField("access_key", accessKey).Warn("access key secret does not match")
 			return nil, ErrAuthenticationFailed
 		}
-		userData, err := s.authService.GetUserByID(credentials.UserID)
+		userData, err := authService.GetUserByID(credentials.UserID)
 		if err != nil {
 			logger.WithField("access_key", accessKey).Warn("could not find user for key pair")
 			return nil, ErrAuthenticationFailed
@@ -171,11 +201,16 @@ func (s *Handler) buildAPI() {
 	api.BasicAuthAuth = s.BasicAuth()
 	api.JwtTokenAuth = s.JwtTokenAuth()
 	// bind our handlers to the server
-	NewController(s.cataloger, s.authService, s.blockStore, s.stats, s.retention, s.parade, s.dedupCleaner, s.metadataManager, s.migrator, s.stats, s.logger).Configure(api)
+	NewController(s.cataloger, s.authService, s.blockStore, s.stats, s.retention, s.parade, s.exportDestinations, s.dedupCleaner, s.metadataManager, s.migrator, s.stats, s.activity, s.notifier, s.subscriptions, s.hooks, s.gateway, s.logger).Configure(api)
 
 	// setup host/port
 	s.apiServer = restapi.NewServer(api)
 	s.apiServer.ConfigureAPI()
+	apiHandler := s.apiServer.GetHandler()
+	apiHandler = requestRateMiddleware(api.Context(), s.cataloger, apiHandler)
+	if s.readOnly {
+		apiHandler = readOnlyMiddleware(api.Context(), apiHandler)
+	}
 	s.setupHandler(
 		// api handler
 		httputil.LoggingMiddleware(
@@ -184,7 +219,7 @@ func (s *Handler) buildAPI() {
 			promhttp.InstrumentHandlerCounter(requestCounter,
 				metricsMiddleware(api.Context(),
 					cookieToAPIHeader(
-						s.apiServer.GetHandler(),
+						apiHandler,
 					)),
 			),
 		),
@@ -12,6 +12,7 @@ import (
 	"github.com/go-openapi/runtime"
 	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/ory/dockertest/v3"
+	"github.com/treeverse/lakefs/activity"
 	"github.com/treeverse/lakefs/api"
 	"github.com/treeverse/lakefs/api/gen/client"
 	"github.com/treeverse/lakefs/api/gen/client/repositories"
@@ -25,7 +26,9 @@ import (
 	"github.com/treeverse/lakefs/db"
 	dbparams "github.com/treeverse/lakefs/db/params"
 	"github.com/treeverse/lakefs/dedup"
+	"github.com/treeverse/lakefs/hooks"
 	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/notifications"
 	"github.com/treeverse/lakefs/retention"
 	"github.com/treeverse/lakefs/stats"
 	"github.com/treeverse/lakefs/testutil"
@@ -33,6 +36,9 @@ import (
 
 const (
 	DefaultUserID = "example_user"
+
+	testGatewayEndpoint = "http://s3.local.lakefs.io:8000"
+	testGatewayRegion   = "us-east-1"
 )
 
 var (
@@ -57,6 +63,7 @@ type dependencies struct {
 	blocks    block.Adapter
 	auth      auth.Service
 	cataloger catalog.Cataloger
+	conn      db.Database
 }
 
 func createDefaultAdminUser(authService auth.Service, t *testing.T) *authmodel.Credential {
@@ -107,7 +114,14 @@ func getHandler(t *testing.T, blockstoreType string, opts ...testutil.GetDBOptio
 		retentionService,
 		migrator,
 		nil,
+		nil,
 		dedupCleaner,
+		activity.NewDBService(conn),
+		nil,
+		notifications.NewDBSubscriptionService(conn, authService),
+		hooks.NewService(cataloger, blockAdapter, hooks.NewDBRunStore(conn), nil, nil, authService),
+		api.S3GatewayParams{Endpoint: testGatewayEndpoint, Region: testGatewayRegion},
+		false,
 		logging.Default(),
 	)
 
@@ -115,6 +129,7 @@ func getHandler(t *testing.T, blockstoreType string, opts ...testutil.GetDBOptio
 		blocks:    blockAdapter,
 		auth:      authService,
 		cataloger: cataloger,
+		conn:      conn,
 	}
 }
 
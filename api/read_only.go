@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+const readOnlyModeMessage = "lakeFS is running in read-only mode"
+
+// readOnlyOperations are the IDs of the API operations that don't modify data, served in
+// read-only mode.  Some are POST operations, as their parameters don't fit in a query.
+var readOnlyOperations = map[string]struct{}{
+	"getCurrentUser":            {},
+	"listUsers":                 {},
+	"getUser":                   {},
+	"listGroups":                {},
+	"getGroup":                  {},
+	"listPolicies":              {},
+	"getPolicy":                 {},
+	"listGroupMembers":          {},
+	"listUserCredentials":       {},
+	"getCredentials":            {},
+	"listUserSubscriptions":     {},
+	"listUserGroups":            {},
+	"listUserPolicies":          {},
+	"listGroupPolicies":         {},
+	"listRepositories":          {},
+	"getRepository":             {},
+	"getRepositoryQuota":        {},
+	"getRepositoryCommitLimits": {},
+	"getMetadataSchema":         {},
+	"getRepositoryUsage":        {},
+	"listRepositoryActivity":    {},
+	"listHookRuns":              {},
+	"getHookRun":                {},
+	"dryRunHooks":               {},
+	"listJobs":                  {},
+	"getJob":                    {},
+	"searchRepository":          {},
+	"listBranches":              {},
+	"getBranchCommitLog":        {},
+	"getBranchChanges":          {},
+	"getBranch":                 {},
+	"mergePreview":              {},
+	"diffBranch":                {},
+	"diffRefs":                  {},
+	"diffRefsSummary":           {},
+	"diffRefsChecksums":         {},
+	"getCommit":                 {},
+	"getCommitGraph":            {},
+	"walkDataLineage":           {},
+	"getObject":                 {},
+	"statObject":                {},
+	"statObjects":               {},
+	"presignObject":             {},
+	"previewObject":             {},
+	"getObjectSchema":           {},
+	"getUnderlyingProperties":   {},
+	"getObjectHistory":          {},
+	"listObjects":               {},
+	"getPrefixStats":            {},
+	"getRefExport":              {},
+	"getContinuousExport":       {},
+	"listContinuousExports":     {},
+	"listExportRuns":            {},
+	"getExportPlan":             {},
+	"getExportDrift":            {},
+	"getRetentionPolicy":        {},
+	"healthCheck":               {},
+	"getConfig":                 {},
+	"getLoggingConfig":          {},
+}
+
+// readOnlyMiddleware rejects requests to API operations not in readOnlyOperations, used to
+// serve reads from a read-only replica of the database.  Requests matching no operation pass,
+// to fail as not found.
+func readOnlyMiddleware(ctx *middleware.Context, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, _, ok := ctx.RouteInfo(r)
+		if ok {
+			if _, readOnly := readOnlyOperations[route.Operation.ID]; !readOnly {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				_ = json.NewEncoder(w).Encode(responseError(readOnlyModeMessage))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/treeverse/lakefs/api/gen/restapi"
+	"github.com/treeverse/lakefs/api/gen/restapi/operations"
+)
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lakefsAPI := operations.NewLakefsAPI(swaggerSpec)
+	// serving builds the router of the API context
+	_ = lakefsAPI.Serve(nil)
+	handler := readOnlyMiddleware(lakefsAPI.Context(), http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	cases := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{name: "api read", method: http.MethodGet, path: "/api/v1/repositories", expectedCode: http.StatusOK},
+		{name: "api post read", method: http.MethodPost, path: "/api/v1/repositories/repo1/objects/stat", expectedCode: http.StatusOK},
+		{name: "api dry run", method: http.MethodPost, path: "/api/v1/repositories/repo1/hooks/dry-run", expectedCode: http.StatusOK},
+		{name: "api create", method: http.MethodPost, path: "/api/v1/repositories", expectedCode: http.StatusMethodNotAllowed},
+		{name: "api delete", method: http.MethodDelete, path: "/api/v1/repositories/repo1", expectedCode: http.StatusMethodNotAllowed},
+		{name: "api unknown", method: http.MethodPost, path: "/api/v1/unknown", expectedCode: http.StatusOK},
+		{name: "ui login", method: http.MethodPost, path: "/auth/login", expectedCode: http.StatusOK},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+			if rr.Code != tt.expectedCode {
+				t.Fatalf("%s %s returned %d, expected %d", tt.method, tt.path, rr.Code, tt.expectedCode)
+			}
+		})
+	}
+}
+
+func TestReadOnlyOperations(t *testing.T) {
+	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[string]bool)
+	for method, paths := range swaggerSpec.Analyzer.Operations() {
+		for _, operation := range paths {
+			ids[operation.ID] = true
+			// reads are served unless listed, so every GET operation should be
+			if _, ok := readOnlyOperations[operation.ID]; strings.EqualFold(method, http.MethodGet) && !ok {
+				t.Errorf("GET operation %s is not a read-only operation", operation.ID)
+			}
+		}
+	}
+	for id := range readOnlyOperations {
+		if !ids[id] {
+			t.Errorf("read-only operation %s is not an API operation", id)
+		}
+	}
+}
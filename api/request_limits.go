@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	uploadparams "github.com/treeverse/lakefs/upload/params"
+)
+
+// multipartFormOverhead is allowed on top of the maximum object size for the fields and
+// boundaries of an object uploaded as a multipart form
+const multipartFormOverhead = 1024 * 1024
+
+// RequestSizeLimitMiddleware rejects API requests with bodies larger than limits: objects
+// uploaded as multipart forms are bounded by MaxObjectSize, other requests by
+// MaxRequestBodySize.  Requests outside the API (UI, health, metrics) pass.
+func RequestSizeLimitMiddleware(next http.Handler, limits uploadparams.Limits) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") || r.Body == nil || r.Body == http.NoBody {
+			next.ServeHTTP(w, r)
+			return
+		}
+		kind, limit := "request body", limits.MaxRequestBodySize
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && mediaType == "multipart/form-data" {
+			kind, limit = "object", limits.MaxObjectSize
+			if limit > 0 {
+				limit += multipartFormOverhead
+			}
+		}
+		if limit <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.ContentLength > limit {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			_ = json.NewEncoder(w).Encode(responseError(fmt.Sprintf("%s size %d exceeds the maximum of %d bytes", kind, r.ContentLength, limit)))
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
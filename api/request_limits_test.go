@@ -0,0 +1,52 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	uploadparams "github.com/treeverse/lakefs/upload/params"
+)
+
+func TestRequestSizeLimitMiddleware(t *testing.T) {
+	limits := uploadparams.Limits{MaxObjectSize: 8, MaxRequestBodySize: 4}
+	handler := RequestSizeLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}), limits)
+	cases := []struct {
+		name          string
+		path          string
+		contentType   string
+		body          string
+		unknownLength bool
+		expectedCode  int
+	}{
+		{name: "api small body", path: "/api/v1/repositories", body: "abcd", expectedCode: http.StatusOK},
+		{name: "api large body", path: "/api/v1/repositories", body: "abcde", expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "api large body unknown length", path: "/api/v1/repositories", body: "abcde", unknownLength: true, expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "api upload", path: "/api/v1/repositories/repo1/branches/master/objects", contentType: "multipart/form-data; boundary=b", body: "abcdefgh", expectedCode: http.StatusOK},
+		{name: "ui", path: "/auth/login", body: "abcdefgh", expectedCode: http.StatusOK},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.unknownLength {
+				req.ContentLength = -1
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			if rr.Code != tt.expectedCode {
+				t.Fatalf("POST %s returned %d, expected %d", tt.path, rr.Code, tt.expectedCode)
+			}
+		})
+	}
+}
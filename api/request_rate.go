@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/logging"
+)
+
+// requestRateExemptOperations are the IDs of the API operations not limited by the request
+// rate quota of their repository, so that the quota can always be changed
+var requestRateExemptOperations = map[string]struct{}{
+	"getRepositoryQuota": {},
+	"setRepositoryQuota": {},
+}
+
+// requestRateMiddleware rejects requests to API operations of a repository beyond the request
+// rate quota of the repository or of its organization with 429 Too Many Requests.  Only
+// authenticated requests count: the middleware authenticates them, and passes the
+// authenticated request on so that the API doesn't authenticate it again.
+func requestRateMiddleware(ctx *middleware.Context, cataloger catalog.Cataloger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, routed, ok := ctx.RouteInfo(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r = routed
+		repository := route.Params.Get("repository")
+		if _, exempt := requestRateExemptOperations[route.Operation.ID]; exempt || repository == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		principal, authenticated, err := ctx.Authorize(r, route)
+		if err != nil || principal == nil {
+			// the API fails the request
+			next.ServeHTTP(w, r)
+			return
+		}
+		r = authenticated
+		err = cataloger.CheckRequestRate(r.Context(), repository)
+		if errors.Is(err, catalog.ErrRequestRateExceeded) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(responseErrorFrom(err))
+			return
+		}
+		if err != nil {
+			logging.FromContext(r.Context()).WithError(err).WithField("repository", repository).Debug("could not check request rate")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -85,6 +85,7 @@ func SetupBaseGroups(authService Service, ts time.Time) error {
 						permissions.CreateBranchAction,
 						permissions.DeleteBranchAction,
 						permissions.CreateCommitAction,
+						permissions.RetryHookRunAction,
 					},
 					Resource: permissions.All,
 					Effect:   model.StatementEffectAllow,
@@ -173,6 +174,21 @@ func SetupBaseGroups(authService Service, ts time.Time) error {
 				},
 			},
 		},
+		{
+			CreatedAt:   ts,
+			DisplayName: "AuthManageOwnSubscriptions",
+			Statement: model.Statements{
+				{
+					Action: []string{
+						permissions.ListSubscriptionsAction,
+						permissions.CreateSubscriptionAction,
+						permissions.DeleteSubscriptionAction,
+					},
+					Resource: permissions.UserArn("${user}"),
+					Effect:   model.StatementEffectAllow,
+				},
+			},
+		},
 	})
 	if err != nil {
 		return err
@@ -182,15 +198,15 @@ func SetupBaseGroups(authService Service, ts time.Time) error {
 	if err != nil {
 		return err
 	}
-	err = attachPolicies(authService, "SuperUsers", []string{"FSFullAccess", "AuthManageOwnCredentials", "RepoManagementReadAll"})
+	err = attachPolicies(authService, "SuperUsers", []string{"FSFullAccess", "AuthManageOwnCredentials", "AuthManageOwnSubscriptions", "RepoManagementReadAll"})
 	if err != nil {
 		return err
 	}
-	err = attachPolicies(authService, "Developers", []string{"FSReadWriteAll", "AuthManageOwnCredentials", "RepoManagementReadAll"})
+	err = attachPolicies(authService, "Developers", []string{"FSReadWriteAll", "AuthManageOwnCredentials", "AuthManageOwnSubscriptions", "RepoManagementReadAll"})
 	if err != nil {
 		return err
 	}
-	err = attachPolicies(authService, "Viewers", []string{"FSReadAll", "AuthManageOwnCredentials"})
+	err = attachPolicies(authService, "Viewers", []string{"FSReadAll", "AuthManageOwnCredentials", "AuthManageOwnSubscriptions"})
 	if err != nil {
 		return err
 	}
@@ -2,12 +2,17 @@ package block
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
+// ErrSelectNotSupported is returned by adapters whose storage cannot filter object content by
+// S3 Select requests
+var ErrSelectNotSupported = errors.New("select object content not supported by storage adapter")
+
 type MultipartUploadCompletion struct{ Part []*s3.CompletedPart }
 
 // ObjectPointer is a unique identifier of an object in the object
@@ -26,7 +31,31 @@ type ObjectPointer struct {
 // contents but different option values, the first supplied option
 // value is retained.
 type PutOpts struct {
-	StorageClass *string // S3 storage class
+	StorageClass *string           // S3 storage class
+	ContentType  *string           // Content-Type served with the object
+	Metadata     map[string]string // user metadata, such as S3 x-amz-meta-* headers
+	// ServerSideEncryption (if set) is the S3 server-side encryption of the object,
+	// using the KMS key SSEKMSKeyID (if set) for aws:kms
+	ServerSideEncryption *string
+	SSEKMSKeyID          *string
+}
+
+// CopyOpts contains optional arguments for Copy.  Copied objects keep the
+// content type and user metadata of their source, unless ContentType or
+// Metadata are set: then both replace those of the source.
+type CopyOpts struct {
+	ContentType *string
+	Metadata    map[string]string
+	// ServerSideEncryption and SSEKMSKeyID encrypt the copied object as in PutOpts,
+	// regardless of the encryption of its source
+	ServerSideEncryption *string
+	SSEKMSKeyID          *string
+}
+
+// Replaces returns whether opts replace the content type and user metadata
+// of copied objects
+func (opts CopyOpts) Replaces() bool {
+	return opts.ContentType != nil || opts.Metadata != nil
 }
 
 // CreateMultiPartOpts contains optional arguments for
@@ -45,9 +74,27 @@ type CreateMultiPartUploadOpts struct {
 // Refer to the actual underlying Adapter for which properties are
 // actually reported.
 type Properties struct {
-	StorageClass *string
+	StorageClass         *string
+	ContentType          *string
+	Metadata             map[string]string
+	ServerSideEncryption *string
+	SSEKMSKeyID          *string
+}
+
+// ObjectInfo describes an object listed from the underlying storage
+type ObjectInfo struct {
+	// Key of the object, relative to the walked prefix
+	Key  string
+	Size int64
+	// ETag of the object as reported by the underlying storage, without quotes.  Empty when
+	// the storage does not report one.
+	ETag string
 }
 
+// WalkFunc is called by Walk for each listed object.  Returning an error stops the walk, and
+// the error is returned by Walk.
+type WalkFunc func(info ObjectInfo) error
+
 type Adapter interface {
 	InventoryGenerator
 	WithContext(ctx context.Context) Adapter
@@ -56,7 +103,16 @@ type Adapter interface {
 	GetRange(obj ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error)
 	GetProperties(obj ObjectPointer) (Properties, error)
 	Remove(obj ObjectPointer) error
-	Copy(sourceObj, destinationObj ObjectPointer) error
+	Copy(sourceObj, destinationObj ObjectPointer, opts CopyOpts) error
+	// SetStorageClass transitions an existing object to storageClass, keeping its content
+	// and metadata
+	SetStorageClass(obj ObjectPointer, storageClass string) error
+	// Walk lists all objects whose identifiers start with the prefix identifier
+	Walk(prefix ObjectPointer, walkFn WalkFunc) error
+	// SelectObjectContent filters the content of obj by the S3 Select request input, and
+	// returns the response event stream as encoded by S3.  The bucket and key of input are
+	// ignored.
+	SelectObjectContent(obj ObjectPointer, input *s3.SelectObjectContentInput) (io.ReadCloser, error)
 	CreateMultiPartUpload(obj ObjectPointer, r *http.Request, opts CreateMultiPartUploadOpts) (string, error)
 	UploadPart(obj ObjectPointer, sizeBytes int64, reader io.Reader, uploadID string, partNumber int64) (string, error)
 	AbortMultiPartUpload(obj ObjectPointer, uploadID string) error
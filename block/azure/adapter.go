@@ -0,0 +1,504 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/logging"
+)
+
+const (
+	BlockstoreType = "azure"
+
+	// MaxPutSize is the largest object uploaded by a single Put
+	MaxPutSize = 5000 * 1024 * 1024
+
+	apiVersion       = "2019-12-12"
+	blobDomain       = ".blob.core.windows.net"
+	copyPollInterval = time.Second
+)
+
+var (
+	ErrNotImplemented  = errors.New("not implemented")
+	ErrMissingAccount  = errors.New("missing storage account")
+	ErrInvalidURL      = errors.New("invalid Azure Blob Storage URL")
+	ErrAccountMismatch = errors.New("no credentials for storage account")
+	ErrObjectTooLarge  = errors.New("object too large for a single upload")
+	ErrCopyFailed      = errors.New("copy failed")
+)
+
+// StatusError is a request that Azure Blob Storage answered with an error status
+type StatusError struct {
+	Method     string
+	StatusCode int
+	Code       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: status %d %s", e.Method, e.StatusCode, e.Code)
+}
+
+// Adapter reads and writes Azure Blob Storage of a single storage account through its REST
+// API, authorized by the shared key of the account.  It serves export destinations, so it
+// uploads each object in a single request and does not support multipart uploads.
+type Adapter struct {
+	ctx      context.Context
+	client   *http.Client
+	account  string
+	key      []byte
+	endpoint string
+}
+
+func WithContext(ctx context.Context) func(a *Adapter) {
+	return func(a *Adapter) {
+		a.ctx = ctx
+	}
+}
+
+func WithHTTPClient(client *http.Client) func(a *Adapter) {
+	return func(a *Adapter) {
+		a.client = client
+	}
+}
+
+// WithEndpoint sends requests to endpoint instead of the blob service of the account
+func WithEndpoint(endpoint string) func(a *Adapter) {
+	return func(a *Adapter) {
+		a.endpoint = strings.TrimRight(endpoint, "/")
+	}
+}
+
+// NewAdapter returns an adapter of storage account authorized by its base64 encoded
+// accessKey
+func NewAdapter(account, accessKey string, opts ...func(a *Adapter)) (*Adapter, error) {
+	if account == "" {
+		return nil, ErrMissingAccount
+	}
+	key, err := base64.StdEncoding.DecodeString(accessKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode access key: %w", err)
+	}
+	a := &Adapter{
+		ctx:      context.Background(),
+		client:   http.DefaultClient,
+		account:  account,
+		key:      key,
+		endpoint: "https://" + account + blobDomain,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a, nil
+}
+
+func (a *Adapter) WithContext(ctx context.Context) block.Adapter {
+	return &Adapter{
+		ctx:      ctx,
+		client:   a.client,
+		account:  a.account,
+		key:      a.key,
+		endpoint: a.endpoint,
+	}
+}
+
+// ParseURL returns the storage account, container and blob of an
+// https://<account>.blob.core.windows.net/<container>/<blob> or
+// wasb[s]://<container>@<account>.blob.core.windows.net/<blob> URL
+func ParseURL(u *url.URL) (account, container, blob string, err error) {
+	if !strings.HasSuffix(u.Host, blobDomain) {
+		return "", "", "", fmt.Errorf("%s: %w", u, ErrInvalidURL)
+	}
+	account = strings.TrimSuffix(u.Host, blobDomain)
+	p := strings.TrimPrefix(u.Path, "/")
+	switch u.Scheme {
+	case "https":
+		parts := strings.SplitN(p, "/", 2)
+		container = parts[0]
+		if len(parts) > 1 {
+			blob = parts[1]
+		}
+	case "wasb", "wasbs":
+		if u.User != nil {
+			container = u.User.Username()
+		}
+		blob = p
+	default:
+		return "", "", "", fmt.Errorf("%s: %w", u, ErrInvalidURL)
+	}
+	if account == "" || container == "" {
+		return "", "", "", fmt.Errorf("%s: %w", u, ErrInvalidURL)
+	}
+	return account, container, blob, nil
+}
+
+// blobLocation is the container and blob of an object in the account of the adapter
+type blobLocation struct {
+	container string
+	blob      string
+}
+
+func (a *Adapter) resolve(obj block.ObjectPointer) (blobLocation, error) {
+	u, err := url.Parse(strings.TrimSuffix(obj.StorageNamespace, "/") + "/" + strings.TrimPrefix(obj.Identifier, "/"))
+	if err != nil {
+		return blobLocation{}, err
+	}
+	account, container, blob, err := ParseURL(u)
+	if err != nil {
+		return blobLocation{}, err
+	}
+	if account != a.account {
+		return blobLocation{}, fmt.Errorf("%s: %w", account, ErrAccountMismatch)
+	}
+	return blobLocation{container: container, blob: blob}, nil
+}
+
+func (a *Adapter) url(loc blobLocation, query url.Values) string {
+	p := "/" + loc.container
+	if loc.blob != "" {
+		p += "/" + loc.blob
+	}
+	u := (&url.URL{Path: p}).EscapedPath()
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return a.endpoint + u
+}
+
+// do sends an authorized request, returning a StatusError for error statuses
+func (a *Adapter) do(method string, loc blobLocation, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	if body == nil {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(a.ctx, method, a.url(loc, query), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.ContentLength = size
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", apiVersion)
+	req.Header.Set("Authorization", "SharedKey "+a.account+":"+a.signature(req))
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+		return nil, &StatusError{Method: method, StatusCode: resp.StatusCode, Code: resp.Header.Get("x-ms-error-code")}
+	}
+	return resp, nil
+}
+
+// signature signs req with the shared key of the account
+// (https://docs.microsoft.com/rest/api/storageservices/authorize-with-shared-key)
+func (a *Adapter) signature(req *http.Request) string {
+	var contentLength string
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+	var msHeaders []string
+	for k := range req.Header {
+		if k := strings.ToLower(k); strings.HasPrefix(k, "x-ms-") {
+			msHeaders = append(msHeaders, k)
+		}
+	}
+	sort.Strings(msHeaders)
+	var canonicalized strings.Builder
+	for _, k := range msHeaders {
+		canonicalized.WriteString(k + ":" + strings.TrimSpace(req.Header.Get(k)) + "\n")
+	}
+	canonicalized.WriteString("/" + a.account + req.URL.EscapedPath())
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for k := range query {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	for _, k := range params {
+		values := query[k]
+		sort.Strings(values)
+		canonicalized.WriteString("\n" + strings.ToLower(k) + ":" + strings.Join(values, ","))
+	}
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, x-ms-date is signed instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		canonicalized.String(),
+	}, "\n")
+	mac := hmac.New(sha256.New, a.key)
+	_, _ = mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// addMetadataHeaders adds headers setting the user metadata of a blob to header
+func addMetadataHeaders(header http.Header, metadata map[string]string) {
+	for name, value := range metadata {
+		header.Set("X-Ms-Meta-"+name, value)
+	}
+}
+
+func (a *Adapter) Put(obj block.ObjectPointer, sizeBytes int64, reader io.Reader, opts block.PutOpts) error {
+	loc, err := a.resolve(obj)
+	if err != nil {
+		return err
+	}
+	if sizeBytes < 0 {
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+		sizeBytes = int64(len(data))
+	}
+	if sizeBytes > MaxPutSize {
+		return fmt.Errorf("%d bytes: %w", sizeBytes, ErrObjectTooLarge)
+	}
+	if sizeBytes == 0 {
+		reader = nil
+	}
+	header := http.Header{"X-Ms-Blob-Type": {"BlockBlob"}}
+	if opts.ContentType != nil {
+		header.Set("X-Ms-Blob-Content-Type", *opts.ContentType)
+	}
+	addMetadataHeaders(header, opts.Metadata)
+	resp, err := a.do(http.MethodPut, loc, nil, header, reader, sizeBytes)
+	if err != nil {
+		return fmt.Errorf("put blob: %w", err)
+	}
+	return resp.Body.Close()
+}
+
+func (a *Adapter) Get(obj block.ObjectPointer, _ int64) (io.ReadCloser, error) {
+	loc, err := a.resolve(obj)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.do(http.MethodGet, loc, nil, nil, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("get blob: %w", err)
+	}
+	return resp.Body, nil
+}
+
+func (a *Adapter) GetRange(obj block.ObjectPointer, startPosition int64, endPosition int64) (io.ReadCloser, error) {
+	loc, err := a.resolve(obj)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{"X-Ms-Range": {fmt.Sprintf("bytes=%d-%d", startPosition, endPosition)}}
+	resp, err := a.do(http.MethodGet, loc, nil, header, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("get blob range: %w", err)
+	}
+	return resp.Body, nil
+}
+
+func (a *Adapter) GetProperties(obj block.ObjectPointer) (block.Properties, error) {
+	loc, err := a.resolve(obj)
+	if err != nil {
+		return block.Properties{}, err
+	}
+	resp, err := a.do(http.MethodHead, loc, nil, nil, nil, 0)
+	if err != nil {
+		return block.Properties{}, fmt.Errorf("get blob properties: %w", err)
+	}
+	_ = resp.Body.Close()
+	var props block.Properties
+	if tier := resp.Header.Get("x-ms-access-tier"); tier != "" {
+		props.StorageClass = &tier
+	}
+	return props, nil
+}
+
+// SetStorageClass sets the access tier of a blob, such as Cool or Archive
+func (a *Adapter) SetStorageClass(obj block.ObjectPointer, storageClass string) error {
+	loc, err := a.resolve(obj)
+	if err != nil {
+		return err
+	}
+	resp, err := a.do(http.MethodPut, loc, url.Values{"comp": {"tier"}}, http.Header{"X-Ms-Access-Tier": {storageClass}}, nil, 0)
+	if err != nil {
+		return fmt.Errorf("set blob tier: %w", err)
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+func (a *Adapter) Remove(obj block.ObjectPointer) error {
+	loc, err := a.resolve(obj)
+	if err != nil {
+		return err
+	}
+	resp, err := a.do(http.MethodDelete, loc, nil, nil, nil, 0)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("delete blob: %w", err)
+	}
+	return resp.Body.Close()
+}
+
+// Copy copies a blob within the account, waiting for the copy to complete.  A content type
+// in opts is set once the copy completes, as copies keep the properties of their source.
+func (a *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer, opts block.CopyOpts) error {
+	source, err := a.resolve(sourceObj)
+	if err != nil {
+		return fmt.Errorf("resolve source: %w", err)
+	}
+	destination, err := a.resolve(destinationObj)
+	if err != nil {
+		return fmt.Errorf("resolve destination: %w", err)
+	}
+	header := http.Header{"X-Ms-Copy-Source": {a.url(source, nil)}}
+	if opts.Replaces() {
+		addMetadataHeaders(header, opts.Metadata)
+	}
+	resp, err := a.do(http.MethodPut, destination, nil, header, nil, 0)
+	if err != nil {
+		return fmt.Errorf("copy blob: %w", err)
+	}
+	_ = resp.Body.Close()
+	status := resp.Header.Get("x-ms-copy-status")
+	for status == "pending" {
+		select {
+		case <-a.ctx.Done():
+			return a.ctx.Err()
+		case <-time.After(copyPollInterval):
+		}
+		resp, err = a.do(http.MethodHead, destination, nil, nil, nil, 0)
+		if err != nil {
+			return fmt.Errorf("copy blob status: %w", err)
+		}
+		_ = resp.Body.Close()
+		status = resp.Header.Get("x-ms-copy-status")
+	}
+	if status != "success" {
+		return fmt.Errorf("%w: %s %s", ErrCopyFailed, status, resp.Header.Get("x-ms-copy-status-description"))
+	}
+	if opts.ContentType == nil {
+		return nil
+	}
+	resp, err = a.do(http.MethodPut, destination, url.Values{"comp": {"properties"}}, http.Header{"X-Ms-Blob-Content-Type": {*opts.ContentType}}, nil, 0)
+	if err != nil {
+		return fmt.Errorf("set copied blob content type: %w", err)
+	}
+	return resp.Body.Close()
+}
+
+type listBlobsResult struct {
+	Blobs []struct {
+		Name       string `xml:"Name"`
+		Properties struct {
+			ContentLength int64  `xml:"Content-Length"`
+			ContentMD5    string `xml:"Content-MD5"`
+		} `xml:"Properties"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+func (a *Adapter) SelectObjectContent(_ block.ObjectPointer, _ *s3.SelectObjectContentInput) (io.ReadCloser, error) {
+	return nil, block.ErrSelectNotSupported
+}
+
+func (a *Adapter) Walk(prefix block.ObjectPointer, walkFn block.WalkFunc) error {
+	loc, err := a.resolve(prefix)
+	if err != nil {
+		return err
+	}
+	container := blobLocation{container: loc.container}
+	marker := ""
+	for {
+		query := url.Values{"restype": {"container"}, "comp": {"list"}, "prefix": {loc.blob}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, err := a.do(http.MethodGet, container, query, nil, nil, 0)
+		if err != nil {
+			return fmt.Errorf("list blobs %q: %w", loc.blob, err)
+		}
+		var result listBlobsResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("list blobs %q: %w", loc.blob, err)
+		}
+		for _, b := range result.Blobs {
+			// blobs uploaded in blocks may have no MD5
+			var etag string
+			if md5, err := base64.StdEncoding.DecodeString(b.Properties.ContentMD5); err == nil && len(md5) > 0 {
+				etag = hex.EncodeToString(md5)
+			}
+			err := walkFn(block.ObjectInfo{
+				Key:  strings.TrimPrefix(b.Name, loc.blob),
+				Size: b.Properties.ContentLength,
+				ETag: etag,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if result.NextMarker == "" {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+func (a *Adapter) CreateMultiPartUpload(_ block.ObjectPointer, _ *http.Request, _ block.CreateMultiPartUploadOpts) (string, error) {
+	return "", fmt.Errorf("multipart upload %w", ErrNotImplemented)
+}
+
+func (a *Adapter) UploadPart(_ block.ObjectPointer, _ int64, _ io.Reader, _ string, _ int64) (string, error) {
+	return "", fmt.Errorf("multipart upload %w", ErrNotImplemented)
+}
+
+func (a *Adapter) AbortMultiPartUpload(_ block.ObjectPointer, _ string) error {
+	return fmt.Errorf("multipart upload %w", ErrNotImplemented)
+}
+
+func (a *Adapter) CompleteMultiPartUpload(_ block.ObjectPointer, _ string, _ *block.MultipartUploadCompletion) (*string, int64, error) {
+	return nil, 0, fmt.Errorf("multipart upload %w", ErrNotImplemented)
+}
+
+func (a *Adapter) ValidateConfiguration(_ string) error {
+	return nil
+}
+
+func (a *Adapter) GenerateInventory(_ context.Context, _ logging.Logger, _ string, _ bool) (block.Inventory, error) {
+	return nil, fmt.Errorf("inventory %w", ErrNotImplemented)
+}
+
+func (a *Adapter) BlockstoreType() string {
+	return BlockstoreType
+}
@@ -0,0 +1,241 @@
+package azure_test
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/azure"
+)
+
+const testAccount = "lakefs"
+
+// blobServer is an in-memory blob service of a single account
+type blobServer struct {
+	mu           sync.Mutex
+	blobs        map[string][]byte
+	contentTypes map[string]string
+	metadata     map[string]map[string]string
+}
+
+// headerMetadata returns the user metadata set by x-ms-meta-* headers, nil if none are set
+func headerMetadata(header http.Header) map[string]string {
+	var metadata map[string]string
+	for name := range header {
+		if strings.HasPrefix(name, "X-Ms-Meta-") {
+			if metadata == nil {
+				metadata = make(map[string]string)
+			}
+			metadata[strings.ToLower(strings.TrimPrefix(name, "X-Ms-Meta-"))] = header.Get(name)
+		}
+	}
+	return metadata
+}
+
+func (s *blobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey "+testAccount+":") || r.Header.Get("x-ms-date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.URL.Query().Get("comp") == "list":
+		s.list(w, strings.Trim(r.URL.Path, "/"), r.URL.Query().Get("prefix"))
+	case r.Method == http.MethodPut && r.Header.Get("x-ms-copy-source") != "":
+		source, _ := url.Parse(r.Header.Get("x-ms-copy-source"))
+		data, ok := s.blobs[source.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.blobs[r.URL.Path] = data
+		s.contentTypes[r.URL.Path] = s.contentTypes[source.Path]
+		s.metadata[r.URL.Path] = s.metadata[source.Path]
+		if metadata := headerMetadata(r.Header); metadata != nil {
+			s.metadata[r.URL.Path] = metadata
+		}
+		w.Header().Set("x-ms-copy-status", "success")
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodPut && r.URL.Query().Get("comp") == "properties":
+		if _, ok := s.blobs[r.URL.Path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.contentTypes[r.URL.Path] = r.Header.Get("x-ms-blob-content-type")
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodPut:
+		data, _ := ioutil.ReadAll(r.Body)
+		s.blobs[r.URL.Path] = data
+		s.contentTypes[r.URL.Path] = r.Header.Get("x-ms-blob-content-type")
+		s.metadata[r.URL.Path] = headerMetadata(r.Header)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet:
+		data, ok := s.blobs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == http.MethodDelete:
+		if _, ok := s.blobs[r.URL.Path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(s.blobs, r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *blobServer) list(w http.ResponseWriter, container, prefix string) {
+	type blob struct {
+		Name          string `xml:"Name"`
+		ContentLength int64  `xml:"Properties>Content-Length"`
+	}
+	var result struct {
+		XMLName xml.Name `xml:"EnumerationResults"`
+		Blobs   []blob   `xml:"Blobs>Blob"`
+	}
+	for p, data := range s.blobs {
+		name := strings.TrimPrefix(p, "/"+container+"/")
+		if strings.HasPrefix(name, prefix) {
+			result.Blobs = append(result.Blobs, blob{Name: name, ContentLength: int64(len(data))})
+		}
+	}
+	sort.Slice(result.Blobs, func(i, j int) bool { return result.Blobs[i].Name < result.Blobs[j].Name })
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func TestAdapter(t *testing.T) {
+	blobs := &blobServer{
+		blobs:        make(map[string][]byte),
+		contentTypes: make(map[string]string),
+		metadata:     make(map[string]map[string]string),
+	}
+	server := httptest.NewServer(blobs)
+	defer server.Close()
+	adapter, err := azure.NewAdapter(testAccount, base64.StdEncoding.EncodeToString([]byte("secret")), azure.WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("NewAdapter() unexpected error: %s", err)
+	}
+	obj := block.ObjectPointer{StorageNamespace: "wasb://export@lakefs.blob.core.windows.net/", Identifier: "path/to/a"}
+	if err := adapter.Put(obj, 4, strings.NewReader("data"), block.PutOpts{}); err != nil {
+		t.Fatalf("Put() unexpected error: %s", err)
+	}
+	// the same blob in an https URL
+	copied := block.ObjectPointer{StorageNamespace: "https://lakefs.blob.core.windows.net/", Identifier: "export/path/to/b"}
+	if err := adapter.Copy(obj, copied, block.CopyOpts{}); err != nil {
+		t.Fatalf("Copy() unexpected error: %s", err)
+	}
+	reader, err := adapter.Get(copied, 4)
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %s", err)
+	}
+	data, _ := ioutil.ReadAll(reader)
+	_ = reader.Close()
+	if string(data) != "data" {
+		t.Fatalf("Get() got %q, expected the copied data", data)
+	}
+
+	var keys []string
+	prefix := block.ObjectPointer{StorageNamespace: "https://lakefs.blob.core.windows.net/", Identifier: "export/path/"}
+	err = adapter.Walk(prefix, func(info block.ObjectInfo) error {
+		keys = append(keys, fmt.Sprintf("%s:%d", info.Key, info.Size))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() unexpected error: %s", err)
+	}
+	if strings.Join(keys, ",") != "to/a:4,to/b:4" {
+		t.Fatalf("Walk() got %s", keys)
+	}
+
+	if err := adapter.Remove(obj); err != nil {
+		t.Fatalf("Remove() unexpected error: %s", err)
+	}
+	if err := adapter.Remove(obj); err != nil {
+		t.Fatalf("Remove() of a removed object unexpected error: %s", err)
+	}
+	_, err = adapter.Get(obj, 4)
+	var statusErr *azure.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Get() of a removed object err=%v, expected not found", err)
+	}
+
+	typed := block.ObjectPointer{StorageNamespace: "wasb://export@lakefs.blob.core.windows.net/", Identifier: "path/to/c"}
+	err = adapter.Put(typed, 4, strings.NewReader("data"), block.PutOpts{ContentType: swag.String("text/plain"), Metadata: map[string]string{"color": "red"}})
+	if err != nil {
+		t.Fatalf("Put() with content type unexpected error: %s", err)
+	}
+	if blobs.contentTypes["/export/path/to/c"] != "text/plain" || blobs.metadata["/export/path/to/c"]["color"] != "red" {
+		t.Fatalf("Put() set content type %q and metadata %v", blobs.contentTypes["/export/path/to/c"], blobs.metadata["/export/path/to/c"])
+	}
+	if err := adapter.Copy(typed, copied, block.CopyOpts{}); err != nil {
+		t.Fatalf("Copy() unexpected error: %s", err)
+	}
+	if blobs.contentTypes["/export/path/to/b"] != "text/plain" || blobs.metadata["/export/path/to/b"]["color"] != "red" {
+		t.Fatalf("Copy() kept content type %q and metadata %v, expected those of the source", blobs.contentTypes["/export/path/to/b"], blobs.metadata["/export/path/to/b"])
+	}
+	err = adapter.Copy(typed, copied, block.CopyOpts{ContentType: swag.String("text/csv"), Metadata: map[string]string{"color": "blue"}})
+	if err != nil {
+		t.Fatalf("Copy() replacing content type unexpected error: %s", err)
+	}
+	if blobs.contentTypes["/export/path/to/b"] != "text/csv" || blobs.metadata["/export/path/to/b"]["color"] != "blue" {
+		t.Fatalf("Copy() set content type %q and metadata %v, expected those of opts", blobs.contentTypes["/export/path/to/b"], blobs.metadata["/export/path/to/b"])
+	}
+
+	other := block.ObjectPointer{StorageNamespace: "https://other.blob.core.windows.net/", Identifier: "export/a"}
+	if err := adapter.Put(other, 4, strings.NewReader("data"), block.PutOpts{}); !errors.Is(err, azure.ErrAccountMismatch) {
+		t.Fatalf("Put() to another account err=%v, expected %s", err, azure.ErrAccountMismatch)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		url                      string
+		account, container, blob string
+		wantErr                  bool
+	}{
+		{url: "https://acct.blob.core.windows.net/cont/path/to/obj", account: "acct", container: "cont", blob: "path/to/obj"},
+		{url: "https://acct.blob.core.windows.net/cont", account: "acct", container: "cont"},
+		{url: "wasb://cont@acct.blob.core.windows.net/path/to/obj", account: "acct", container: "cont", blob: "path/to/obj"},
+		{url: "wasbs://cont@acct.blob.core.windows.net/", account: "acct", container: "cont"},
+		{url: "wasb://acct.blob.core.windows.net/path", wantErr: true},
+		{url: "https://example.com/cont/path", wantErr: true},
+		{url: "s3://acct.blob.core.windows.net/cont/path", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			account, container, blob, err := azure.ParseURL(u)
+			if tt.wantErr {
+				if !errors.Is(err, azure.ErrInvalidURL) {
+					t.Fatalf("ParseURL() err=%v, expected %s", err, azure.ErrInvalidURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseURL() unexpected error: %s", err)
+			}
+			if account != tt.account || container != tt.container || blob != tt.blob {
+				t.Fatalf("ParseURL() got %s %s %s, expected %s %s %s", account, container, blob, tt.account, tt.container, tt.blob)
+			}
+		})
+	}
+}
@@ -54,7 +54,7 @@ func BuildBlockAdapter(c *config.Config) (block.Adapter, error) {
 		if err != nil {
 			return nil, err
 		}
-		return buildGSAdapter(p)
+		return BuildGSAdapter(p)
 	default:
 		return nil, fmt.Errorf("%w '%s' please choose one of %s",
 			ErrInvalidBlockStoreType, blockstore, []string{local.BlockstoreType, s3a.BlockstoreType, mem.BlockstoreType, transient.BlockstoreType, gs.BlockstoreType})
@@ -88,7 +88,8 @@ func buildS3Adapter(params params.S3) (*s3a.Adapter, error) {
 	return adapter, nil
 }
 
-func buildGSAdapter(params params.GS) (*gs.Adapter, error) {
+// BuildGSAdapter returns an adapter of Google Cloud Storage authenticated by params
+func BuildGSAdapter(params params.GS) (*gs.Adapter, error) {
 	var opts []option.ClientOption
 	if params.CredentialsFile != "" {
 		opts = append(opts, option.WithCredentialsFile(params.CredentialsFile))
@@ -2,14 +2,17 @@ package gs
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"sort"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/logging"
 	"google.golang.org/api/iterator"
@@ -88,7 +91,7 @@ func resolveNamespace(obj block.ObjectPointer) (block.QualifiedKey, error) {
 	return qualifiedKey, nil
 }
 
-func (a *Adapter) Put(obj block.ObjectPointer, sizeBytes int64, reader io.Reader, _ block.PutOpts) error {
+func (a *Adapter) Put(obj block.ObjectPointer, sizeBytes int64, reader io.Reader, opts block.PutOpts) error {
 	var err error
 	defer reportMetrics("Put", time.Now(), &sizeBytes, &err)
 	qualifiedKey, err := resolveNamespace(obj)
@@ -99,6 +102,10 @@ func (a *Adapter) Put(obj block.ObjectPointer, sizeBytes int64, reader io.Reader
 		Bucket(qualifiedKey.StorageNamespace).
 		Object(qualifiedKey.Key).
 		NewWriter(a.ctx)
+	if opts.ContentType != nil {
+		w.ContentType = *opts.ContentType
+	}
+	w.Metadata = opts.Metadata
 	_, err = io.Copy(w, reader)
 	if err != nil {
 		return fmt.Errorf("io.Copy: %w", err)
@@ -173,7 +180,7 @@ func (a *Adapter) Remove(obj block.ObjectPointer) error {
 	return nil
 }
 
-func (a *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer) error {
+func (a *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer, opts block.CopyOpts) error {
 	var err error
 	defer reportMetrics("Copy", time.Now(), nil, &err)
 	qualifiedDestinationKey, err := resolveNamespace(destinationObj)
@@ -186,12 +193,79 @@ func (a *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer) error {
 	}
 	destinationObjectHandle := a.client.Bucket(qualifiedDestinationKey.StorageNamespace).Object(qualifiedDestinationKey.Key)
 	sourceObjectHandle := a.client.Bucket(qualifiedSourceKey.StorageNamespace).Object(qualifiedSourceKey.Key)
-	_, err = destinationObjectHandle.CopierFrom(sourceObjectHandle).Run(a.ctx)
+	copier := destinationObjectHandle.CopierFrom(sourceObjectHandle)
+	if opts.Replaces() {
+		if opts.ContentType != nil {
+			copier.ContentType = *opts.ContentType
+		}
+		copier.Metadata = opts.Metadata
+	}
+	_, err = copier.Run(a.ctx)
 	if err != nil {
 		return fmt.Errorf("Copy: %w", err)
 	}
 	return nil
 }
+
+func (a *Adapter) SetStorageClass(obj block.ObjectPointer, storageClass string) error {
+	var err error
+	defer reportMetrics("SetStorageClass", time.Now(), nil, &err)
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return err
+	}
+	// objects are rewritten in place to change their storage class
+	handle := a.client.Bucket(qualifiedKey.StorageNamespace).Object(qualifiedKey.Key)
+	copier := handle.CopierFrom(handle)
+	copier.StorageClass = storageClass
+	_, err = copier.Run(a.ctx)
+	if err != nil {
+		return fmt.Errorf("SetStorageClass: %w", err)
+	}
+	return nil
+}
+
+func (a *Adapter) SelectObjectContent(_ block.ObjectPointer, _ *s3.SelectObjectContentInput) (io.ReadCloser, error) {
+	return nil, block.ErrSelectNotSupported
+}
+
+func (a *Adapter) Walk(prefix block.ObjectPointer, walkFn block.WalkFunc) error {
+	var err error
+	defer reportMetrics("Walk", time.Now(), nil, &err)
+	qualifiedPrefix, err := resolveNamespace(prefix)
+	if err != nil {
+		return err
+	}
+	it := a.client.
+		Bucket(qualifiedPrefix.StorageNamespace).
+		Objects(a.ctx, &storage.Query{Prefix: qualifiedPrefix.Key})
+	for {
+		var attrs *storage.ObjectAttrs
+		attrs, err = it.Next()
+		if errors.Is(err, iterator.Done) {
+			err = nil
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("Objects(%q): %w", qualifiedPrefix.Key, err)
+		}
+		// composite objects have no MD5
+		var etag string
+		if len(attrs.MD5) > 0 {
+			etag = hex.EncodeToString(attrs.MD5)
+		}
+		err = walkFn(block.ObjectInfo{
+			Key:  strings.TrimPrefix(attrs.Name, qualifiedPrefix.Key),
+			Size: attrs.Size,
+			ETag: etag,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *Adapter) CreateMultiPartUpload(obj block.ObjectPointer, r *http.Request, opts block.CreateMultiPartUploadOpts) (string, error) {
 	var err error
 	defer reportMetrics("CreateMultiPartUpload", time.Now(), nil, &err)
@@ -31,9 +31,10 @@ type Adapter struct {
 }
 
 var (
-	ErrPathNotValid          = errors.New("path provided is not a valid directory")
-	ErrPathNotWritable       = errors.New("path provided is not writable")
-	ErrInventoryNotSupported = errors.New("inventory feature not implemented for local storage adapter")
+	ErrPathNotValid             = errors.New("path provided is not a valid directory")
+	ErrPathNotWritable          = errors.New("path provided is not writable")
+	ErrInventoryNotSupported    = errors.New("inventory feature not implemented for local storage adapter")
+	ErrStorageClassNotSupported = errors.New("storage classes not supported by local storage adapter")
 )
 
 func (l *Adapter) WithContext(ctx context.Context) block.Adapter {
@@ -126,7 +127,7 @@ func (l *Adapter) Remove(obj block.ObjectPointer) error {
 	return os.Remove(p)
 }
 
-func (l *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer) error {
+func (l *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer, _ block.CopyOpts) error {
 	source, err := l.getPath(sourceObj)
 	if err != nil {
 		return err
@@ -153,6 +154,48 @@ func (l *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer) error {
 	return err
 }
 
+func (l *Adapter) SelectObjectContent(_ block.ObjectPointer, _ *s3.SelectObjectContentInput) (io.ReadCloser, error) {
+	return nil, block.ErrSelectNotSupported
+}
+
+func (l *Adapter) Walk(prefix block.ObjectPointer, walkFn block.WalkFunc) error {
+	qualifiedPrefix, err := resolveNamespace(prefix)
+	if err != nil {
+		return err
+	}
+	namespacePath := path.Join(l.path, qualifiedPrefix.StorageNamespace)
+	// walk the deepest directory containing all keys with the prefix
+	dir := ""
+	if i := strings.LastIndex(qualifiedPrefix.Key, "/"); i >= 0 {
+		dir = qualifiedPrefix.Key[:i]
+	}
+	root := path.Join(namespacePath, dir)
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		// nothing was stored under the prefix
+		return nil
+	}
+	return filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(namespacePath, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, qualifiedPrefix.Key) {
+			return nil
+		}
+		return walkFn(block.ObjectInfo{
+			Key:  strings.TrimPrefix(key, qualifiedPrefix.Key),
+			Size: info.Size(),
+		})
+	})
+}
+
 func (l *Adapter) Get(obj block.ObjectPointer, _ int64) (reader io.ReadCloser, err error) {
 	p, err := l.getPath(obj)
 	if err != nil {
@@ -327,6 +370,10 @@ func (l *Adapter) getPartFiles(uploadID string, obj block.ObjectPointer) ([]stri
 	return names, nil
 }
 
+func (l *Adapter) SetStorageClass(_ block.ObjectPointer, _ string) error {
+	return ErrStorageClassNotSupported
+}
+
 func (l *Adapter) ValidateConfiguration(_ string) error {
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 
@@ -111,7 +112,7 @@ func TestLocalCopy(t *testing.T) {
 
 	testutil.MustDo(t, "Put", a.Put(makePointer("src"), 0, strings.NewReader(contents), block.PutOpts{}))
 
-	testutil.MustDo(t, "Copy", a.Copy(makePointer("src"), makePointer("export/to/dst")))
+	testutil.MustDo(t, "Copy", a.Copy(makePointer("src"), makePointer("export/to/dst"), block.CopyOpts{}))
 	reader, err := a.Get(makePointer("export/to/dst"), 0)
 	testutil.MustDo(t, "Get", err)
 	got, err := ioutil.ReadAll(reader)
@@ -120,3 +121,38 @@ func TestLocalCopy(t *testing.T) {
 		t.Errorf("expected to read \"%s\" as written, got \"%s\"", contents, string(got))
 	}
 }
+
+func TestLocalWalk(t *testing.T) {
+	a, cleanup := makeAdapter(t)
+	defer cleanup()
+
+	for _, p := range []string{"export/a", "export/b/c", "export-other/d", "other/e"} {
+		testutil.MustDo(t, "Put "+p, a.Put(makePointer(p), 0, strings.NewReader(p), block.PutOpts{}))
+	}
+
+	cases := []struct {
+		name   string
+		prefix string
+		keys   []string
+	}{
+		{"directory", "export/", []string{"a", "b/c"}},
+		{"partial", "export", []string{"-other/d", "/a", "/b/c"}},
+		{"missing", "missing/", []string{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			keys := make([]string, 0)
+			testutil.MustDo(t, "Walk", a.Walk(makePointer(c.prefix), func(info block.ObjectInfo) error {
+				keys = append(keys, info.Key)
+				if info.Size != int64(len(c.prefix+info.Key)) {
+					t.Errorf("object %s size=%d, expected %d", info.Key, info.Size, len(c.prefix+info.Key))
+				}
+				return nil
+			}))
+			sort.Strings(keys)
+			if strings.Join(keys, ",") != strings.Join(c.keys, ",") {
+				t.Errorf("walked keys %v, expected %v", keys, c.keys)
+			}
+		})
+	}
+}
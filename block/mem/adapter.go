@@ -3,6 +3,7 @@ package mem
 import (
 	"bytes"
 	"context"
+	"crypto/md5" //nolint:gosec
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
@@ -11,8 +12,10 @@ import (
 	"io/ioutil"
 	"net/http"
 	"sort"
+	"strings"
 	"sync"
 
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/google/uuid"
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/logging"
@@ -148,13 +151,65 @@ func (a *Adapter) Remove(obj block.ObjectPointer) error {
 	return nil
 }
 
-func (a *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer) error {
+func (a *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer, opts block.CopyOpts) error {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 	destinationKey := getKey(destinationObj)
 	sourceKey := getKey(sourceObj)
 	a.data[destinationKey] = a.data[sourceKey]
-	a.properties[destinationKey] = a.properties[sourceKey]
+	props := a.properties[sourceKey]
+	if opts.Replaces() {
+		props.ContentType = opts.ContentType
+		props.Metadata = opts.Metadata
+	}
+	props.ServerSideEncryption = opts.ServerSideEncryption
+	props.SSEKMSKeyID = opts.SSEKMSKeyID
+	a.properties[destinationKey] = props
+	return nil
+}
+
+func (a *Adapter) SetStorageClass(obj block.ObjectPointer, storageClass string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	key := getKey(obj)
+	if _, ok := a.data[key]; !ok {
+		return ErrNoDataForKey
+	}
+	props := a.properties[key]
+	props.StorageClass = &storageClass
+	a.properties[key] = props
+	return nil
+}
+
+func (a *Adapter) SelectObjectContent(_ block.ObjectPointer, _ *s3.SelectObjectContentInput) (io.ReadCloser, error) {
+	return nil, block.ErrSelectNotSupported
+}
+
+func (a *Adapter) Walk(prefix block.ObjectPointer, walkFn block.WalkFunc) error {
+	a.mutex.RLock()
+	prefixKey := getKey(prefix)
+	objects := make([]block.ObjectInfo, 0)
+	for key, data := range a.data {
+		if !strings.HasPrefix(key, prefixKey) {
+			continue
+		}
+		etag := md5.Sum(data) //nolint:gosec
+		objects = append(objects, block.ObjectInfo{
+			Key:  strings.TrimPrefix(key, prefixKey),
+			Size: int64(len(data)),
+			ETag: hex.EncodeToString(etag[:]),
+		})
+	}
+	// walk without holding the lock, walkFn may access the adapter
+	a.mutex.RUnlock()
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	for _, obj := range objects {
+		if err := walkFn(obj); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -22,3 +22,8 @@ type GS struct {
 	CredentialsFile string
 	CredentialsJSON string
 }
+
+type Azure struct {
+	StorageAccount   string
+	StorageAccessKey string
+}
@@ -0,0 +1,54 @@
+package block
+
+import (
+	"io"
+)
+
+// RangeReaderAt reads an object of a known size at arbitrary offsets, fetching each read using
+// the adapter's GetRange.  It is used by readers of file formats that keep their metadata at the
+// end of the object (parquet, orc).
+type RangeReaderAt struct {
+	Adapter    Adapter
+	Pointer    ObjectPointer
+	ObjectSize int64
+}
+
+func NewRangeReaderAt(adapter Adapter, pointer ObjectPointer, size int64) *RangeReaderAt {
+	return &RangeReaderAt{
+		Adapter:    adapter,
+		Pointer:    pointer,
+		ObjectSize: size,
+	}
+}
+
+func (r *RangeReaderAt) Size() int64 {
+	return r.ObjectSize
+}
+
+func (r *RangeReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.ObjectSize {
+		return 0, io.EOF
+	}
+	n := int64(len(p))
+	if n == 0 {
+		return 0, nil
+	}
+	if off+n > r.ObjectSize {
+		n = r.ObjectSize - off
+	}
+	reader, err := r.Adapter.GetRange(r.Pointer, off, off+n-1)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	read, err := io.ReadFull(reader, p[:n])
+	if err != nil {
+		return read, err
+	}
+	if n < int64(len(p)) {
+		return read, io.EOF
+	}
+	return read, nil
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -122,9 +123,13 @@ func (a *Adapter) Put(obj block.ObjectPointer, sizeBytes int64, reader io.Reader
 		return err
 	}
 	putObject := s3.PutObjectInput{
-		Bucket:       aws.String(qualifiedKey.StorageNamespace),
-		Key:          aws.String(qualifiedKey.Key),
-		StorageClass: opts.StorageClass,
+		Bucket:               aws.String(qualifiedKey.StorageNamespace),
+		Key:                  aws.String(qualifiedKey.Key),
+		StorageClass:         opts.StorageClass,
+		ContentType:          opts.ContentType,
+		Metadata:             aws.StringMap(opts.Metadata),
+		ServerSideEncryption: opts.ServerSideEncryption,
+		SSEKMSKeyId:          opts.SSEKMSKeyID,
 	}
 	sdkRequest, _ := a.s3.PutObjectRequest(&putObject)
 	_, err = a.streamToS3(sdkRequest, sizeBytes, reader)
@@ -323,7 +328,7 @@ func (a *Adapter) Remove(obj block.ObjectPointer) error {
 	return err
 }
 
-func (a *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer) error {
+func (a *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer, opts block.CopyOpts) error {
 	var err error
 	defer reportMetrics("Copy", time.Now(), nil, &err)
 
@@ -336,9 +341,16 @@ func (a *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer) error {
 		return err
 	}
 	copyObjectParams := &s3.CopyObjectInput{
-		Bucket:     aws.String(qualifiedDestinationKey.StorageNamespace),
-		Key:        aws.String(qualifiedDestinationKey.Key),
-		CopySource: aws.String(qualifiedSourceKey.StorageNamespace + "/" + qualifiedSourceKey.Key),
+		Bucket:               aws.String(qualifiedDestinationKey.StorageNamespace),
+		Key:                  aws.String(qualifiedDestinationKey.Key),
+		CopySource:           aws.String(qualifiedSourceKey.StorageNamespace + "/" + qualifiedSourceKey.Key),
+		ServerSideEncryption: opts.ServerSideEncryption,
+		SSEKMSKeyId:          opts.SSEKMSKeyID,
+	}
+	if opts.Replaces() {
+		copyObjectParams.MetadataDirective = aws.String(s3.MetadataDirectiveReplace)
+		copyObjectParams.ContentType = opts.ContentType
+		copyObjectParams.Metadata = aws.StringMap(opts.Metadata)
 	}
 	_, err = a.s3.CopyObject(copyObjectParams)
 	if err != nil {
@@ -347,6 +359,81 @@ func (a *Adapter) Copy(sourceObj, destinationObj block.ObjectPointer) error {
 	return err
 }
 
+func (a *Adapter) SetStorageClass(obj block.ObjectPointer, storageClass string) error {
+	var err error
+	defer reportMetrics("SetStorageClass", time.Now(), nil, &err)
+
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return err
+	}
+	// S3 changes the storage class of an object by copying it onto itself
+	_, err = a.s3.CopyObject(&s3.CopyObjectInput{
+		Bucket:            aws.String(qualifiedKey.StorageNamespace),
+		Key:               aws.String(qualifiedKey.Key),
+		CopySource:        aws.String(qualifiedKey.StorageNamespace + "/" + qualifiedKey.Key),
+		MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
+		StorageClass:      aws.String(storageClass),
+	})
+	if err != nil {
+		a.log().WithError(err).Error("failed to set S3 object storage class")
+	}
+	return err
+}
+
+func (a *Adapter) SelectObjectContent(obj block.ObjectPointer, input *s3.SelectObjectContentInput) (io.ReadCloser, error) {
+	var err error
+	defer reportMetrics("SelectObjectContent", time.Now(), nil, &err)
+	qualifiedKey, err := resolveNamespace(obj)
+	if err != nil {
+		return nil, err
+	}
+	selectInput := *input
+	selectInput.Bucket = aws.String(qualifiedKey.StorageNamespace)
+	selectInput.Key = aws.String(qualifiedKey.Key)
+	req, _ := a.s3.SelectObjectContentRequest(&selectInput)
+	// pass the event stream on as encoded by S3, instead of decoding its events
+	req.Handlers.Unmarshal.Clear()
+	err = req.Send()
+	if err != nil {
+		a.log().WithError(err).Error("failed to select S3 object content")
+		return nil, err
+	}
+	return req.HTTPResponse.Body, nil
+}
+
+func (a *Adapter) Walk(prefix block.ObjectPointer, walkFn block.WalkFunc) error {
+	var err error
+	defer reportMetrics("Walk", time.Now(), nil, &err)
+	qualifiedPrefix, err := resolveNamespace(prefix)
+	if err != nil {
+		return err
+	}
+	var walkErr error
+	err = a.s3.ListObjectsV2PagesWithContext(a.ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(qualifiedPrefix.StorageNamespace),
+		Prefix: aws.String(qualifiedPrefix.Key),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			walkErr = walkFn(block.ObjectInfo{
+				Key:  strings.TrimPrefix(aws.StringValue(obj.Key), qualifiedPrefix.Key),
+				Size: aws.Int64Value(obj.Size),
+				ETag: strings.Trim(aws.StringValue(obj.ETag), `"`),
+			})
+			if walkErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		a.log().WithError(err).WithField("operation", "ListObjectsV2").Error("failed to list S3 objects")
+		return err
+	}
+	err = walkErr
+	return err
+}
+
 func (a *Adapter) CreateMultiPartUpload(obj block.ObjectPointer, r *http.Request, opts block.CreateMultiPartUploadOpts) (string, error) {
 	var err error
 	defer reportMetrics("CreateMultiPartUpload", time.Now(), nil, &err)
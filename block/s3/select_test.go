@@ -0,0 +1,57 @@
+package s3_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/treeverse/lakefs/block"
+	s3a "github.com/treeverse/lakefs/block/s3"
+)
+
+func TestAdapter_SelectObjectContent(t *testing.T) {
+	const eventStream = "\x00\x00\x00\x10raw event stream"
+	var selectedPath, selectedQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selectedPath = r.URL.Path
+		selectedQuery = r.URL.RawQuery
+		_, _ = w.Write([]byte(eventStream))
+	}))
+	defer server.Close()
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Endpoint:         aws.String(server.URL),
+		Region:           aws.String("us-east-1"),
+		Credentials:      credentials.NewStaticCredentials("key", "secret", ""),
+		S3ForcePathStyle: aws.Bool(true),
+		MaxRetries:       aws.Int(0),
+	}))
+	adapter := s3a.NewAdapter(s3.New(sess))
+	data, err := adapter.SelectObjectContent(block.ObjectPointer{StorageNamespace: "s3://bucket/ns", Identifier: "object"}, &s3.SelectObjectContentInput{
+		Expression:          aws.String("SELECT * FROM S3Object"),
+		ExpressionType:      aws.String(s3.ExpressionTypeSql),
+		InputSerialization:  &s3.InputSerialization{CSV: &s3.CSVInput{}},
+		OutputSerialization: &s3.OutputSerialization{JSON: &s3.JSONOutput{}},
+	})
+	if err != nil {
+		t.Fatalf("select object content: %s", err)
+	}
+	defer func() {
+		_ = data.Close()
+	}()
+	if selectedPath != "/bucket/ns/object" || selectedQuery != "select=&select-type=2" {
+		t.Errorf("selected %s?%s, expected /bucket/ns/object?select=&select-type=2", selectedPath, selectedQuery)
+	}
+	content, err := ioutil.ReadAll(data)
+	if err != nil {
+		t.Fatalf("read selected content: %s", err)
+	}
+	if string(content) != eventStream {
+		t.Errorf("got event stream %q, expected it passed on as %q", content, eventStream)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/google/uuid"
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/logging"
@@ -61,7 +62,20 @@ func (a *Adapter) Remove(_ block.ObjectPointer) error {
 	return nil
 }
 
-func (a *Adapter) Copy(_, _ block.ObjectPointer) error {
+func (a *Adapter) Copy(_, _ block.ObjectPointer, _ block.CopyOpts) error {
+	return nil
+}
+
+func (a *Adapter) SetStorageClass(_ block.ObjectPointer, _ string) error {
+	return nil
+}
+
+func (a *Adapter) SelectObjectContent(_ block.ObjectPointer, _ *s3.SelectObjectContentInput) (io.ReadCloser, error) {
+	return nil, block.ErrSelectNotSupported
+}
+
+func (a *Adapter) Walk(_ block.ObjectPointer, _ block.WalkFunc) error {
+	// nothing is stored
 	return nil
 }
 
@@ -13,6 +13,8 @@ type SetFn func() (v interface{}, err error)
 
 type Cache interface {
 	GetOrSet(k interface{}, setFn SetFn) (v interface{}, err error)
+	// Remove removes k, so its value is set again on the next GetOrSet
+	Remove(k interface{})
 }
 
 type GetSetCache struct {
@@ -61,6 +63,10 @@ func (c *GetSetCache) GetOrSet(k interface{}, setFn SetFn) (v interface{}, err e
 	return nil, ErrCacheItemNotFound
 }
 
+func (c *GetSetCache) Remove(k interface{}) {
+	c.lru.Remove(k)
+}
+
 func NewJitterFn(jitter time.Duration) JitterFn {
 	return func() time.Duration {
 		n := rand.Intn(int(jitter)) //nolint:gosec
@@ -0,0 +1,26 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/cache"
+)
+
+func TestGetSetCache_Remove(t *testing.T) {
+	c := cache.NewCache(10, time.Hour, cache.NewJitterFn(time.Millisecond))
+	sets := 0
+	setFn := func() (interface{}, error) {
+		sets++
+		return sets, nil
+	}
+	for i := 0; i < 2; i++ {
+		if v, err := c.GetOrSet("k", setFn); err != nil || v != 1 {
+			t.Fatalf("GetOrSet() = %v, %v, expected the first set value 1", v, err)
+		}
+	}
+	c.Remove("k")
+	if v, err := c.GetOrSet("k", setFn); err != nil || v != 2 {
+		t.Fatalf("GetOrSet() after Remove = %v, %v, expected the value set again 2", v, err)
+	}
+}
@@ -29,6 +29,21 @@ type DedupParams struct {
 	StorageNamespace string
 }
 
+// CommitsFilter selects the commits ListCommits returns.  Empty fields match all commits.
+type CommitsFilter struct {
+	// MessageContains matches commits whose message contains it, ignoring case
+	MessageContains string
+	// MessageRegexp matches commits whose message matches it, as a PostgreSQL regular
+	// expression
+	MessageRegexp string
+	Committer     string
+	// Metadata matches commits whose metadata includes all of its pairs
+	Metadata Metadata
+	// Since and Until match commits created at or after Since and before Until
+	Since time.Time
+	Until time.Time
+}
+
 type DiffParams struct {
 	Limit            int
 	After            string
@@ -59,10 +74,86 @@ type GetEntryParams struct {
 	ReturnExpired bool
 }
 
+// EntryRef identifies the entry read from a path on a reference
+type EntryRef struct {
+	Reference string
+	Path      string
+}
+
 type CreateEntryParams struct {
 	Dedup DedupParams
 }
 
+// CreateMultipartUploadParams holds the HTTP headers and user metadata of the entry created when
+// the upload completes
+type CreateMultipartUploadParams struct {
+	ContentType     string
+	ContentEncoding string
+	CacheControl    string
+	Metadata        Metadata
+}
+
+// CommitParams configures what Commit commits
+type CommitParams struct {
+	// Prefixes limits the commit to the uncommitted changes of paths starting with one of
+	// them, leaving other changes uncommitted.  Empty commits all changes.
+	Prefixes []string
+}
+
+// AmendCommitParams configures what AmendCommit changes besides the commit message and metadata
+type AmendCommitParams struct {
+	// IncludeChanges folds the uncommitted changes of the branch into the amended commit
+	IncludeChanges bool
+}
+
+// BranchSortBy is the order of the branches listed by ListBranches
+type BranchSortBy string
+
+const (
+	// BranchSortByName lists branches by name, the default
+	BranchSortByName BranchSortBy = "name"
+	// BranchSortByLastCommit lists the branches committed to most recently first
+	BranchSortByLastCommit BranchSortBy = "last_commit"
+	// BranchSortByCreation lists the branches created most recently first
+	BranchSortByCreation BranchSortBy = "creation"
+)
+
+// ListBranchesParams configures what ListBranches returns for each branch
+type ListBranchesParams struct {
+	// IncludeStatus sets the Status of each listed branch
+	IncludeStatus bool
+	// SortBy orders the listed branches, by name when empty.  In every order the branches
+	// listed after a branch are those after its name.
+	SortBy BranchSortBy
+}
+
+// ListRepositoriesParams configures which repositories ListRepositories lists
+type ListRepositoriesParams struct {
+	// IncludeArchived lists archived repositories too
+	IncludeArchived bool
+}
+
+// TransitionObjectFunc moves the object at address of namespace to another storage class
+type TransitionObjectFunc func(namespace, address string) error
+
+// ArchiveRepositoryParams configures how ArchiveRepository and UnarchiveRepository move the
+// objects of a repository between storage classes
+type ArchiveRepositoryParams struct {
+	// TransitionObject, if set, is called once for each object referenced by the repository
+	TransitionObject TransitionObjectFunc
+}
+
+// CopyObjectFunc copies the object at sourceAddress of sourceNamespace into
+// destinationNamespace, and returns its physical address there
+type CopyObjectFunc func(sourceNamespace, sourceAddress, destinationNamespace string) (string, error)
+
+// CopyEntryParams configures how CopyEntry copies objects between storage namespaces
+type CopyEntryParams struct {
+	// CopyObject copies the object when the repositories have different storage
+	// namespaces.  If nil, such copies fail with ErrFeatureNotSupported.
+	CopyObject CopyObjectFunc
+}
+
 type Cataloger interface {
 	// CreateRepository create a new repository pointing to 'storageNamespace' (ex: s3://bucket1/repo) with default branch name 'branch'
 	CreateRepository(ctx context.Context, repository string, storageNamespace string, branch string) (*Repository, error)
@@ -73,24 +164,198 @@ type Cataloger interface {
 	// DeleteRepository delete a repository
 	DeleteRepository(ctx context.Context, repository string) error
 
+	// GetRepositoryQuota returns the quota set on repository, a zero quota is returned when none was set
+	GetRepositoryQuota(ctx context.Context, repository string) (*RepositoryQuota, error)
+
+	// SetRepositoryQuota sets the quota of repository, and the organization it belongs to.
+	// Writes that exceed the quota fail with ErrQuotaExceeded
+	SetRepositoryQuota(ctx context.Context, repository string, quota *RepositoryQuota) error
+
+	// GetRepositoryUsage returns the storage and number of objects used by repository
+	GetRepositoryUsage(ctx context.Context, repository string) (*RepositoryUsage, error)
+
+	// CheckRepositoryQuota fails with ErrQuotaExceeded if adding objects with a total of size
+	// bytes to repository would exceed its storage quota or the quota of its organization.
+	// Writers call it before storing data, the entries they create are checked again.
+	CheckRepositoryQuota(ctx context.Context, repository string, objects int64, size int64) error
+
+	// CheckRequestRate counts a request to repository, failing with ErrRequestRateExceeded
+	// when requests exceed the rate quota of repository or of its organization
+	CheckRequestRate(ctx context.Context, repository string) error
+
+	// WaitExportBandwidth waits until copying size bytes exported from repository keeps within
+	// the export bandwidth quota of repository and of its organization
+	WaitExportBandwidth(ctx context.Context, repository string, size int64) error
+
+	// GetOrganizationQuota returns the quota of organization
+	GetOrganizationQuota(ctx context.Context, organization string) (*OrganizationQuota, error)
+
+	// SetOrganizationQuota sets the quota of organization, creating the organization if missing
+	SetOrganizationQuota(ctx context.Context, organization string, quota *OrganizationQuota) error
+
+	// DeleteOrganizationQuota deletes organization, its repositories are only limited by their own quotas
+	DeleteOrganizationQuota(ctx context.Context, organization string) error
+
+	// GetOrganizationUsage returns the storage and number of objects used by the repositories of organization
+	GetOrganizationUsage(ctx context.Context, organization string) (*RepositoryUsage, error)
+
+	// GetCommitLimits returns the commit limits set on repository, zero limits are returned when none were set
+	GetCommitLimits(ctx context.Context, repository string) (*CommitLimits, error)
+
+	// SetCommitLimits sets the commit limits of repository.  Commits and merges that exceed the
+	// limits fail with ErrCommitLimitExceeded, unless their context is marked by
+	// WithCommitLimitsExempt
+	SetCommitLimits(ctx context.Context, repository string, limits *CommitLimits) error
+
+	// GetRepositoryTrash returns the trash settings of repository, a disabled trash is returned when none was set
+	GetRepositoryTrash(ctx context.Context, repository string) (*RepositoryTrash, error)
+
+	// SetRepositoryTrash sets the trash settings of repository.  Entries deleted while the trash is
+	// enabled can be restored with RestoreEntry until they expire
+	SetRepositoryTrash(ctx context.Context, repository string, trash *RepositoryTrash) error
+
+	// GetRepositoryLifecycle returns the lifecycle rules of repository, no rules are returned when none were set
+	GetRepositoryLifecycle(ctx context.Context, repository string) (*RepositoryLifecycle, error)
+
+	// SetRepositoryLifecycle sets the lifecycle rules of repository and the branch they apply to.
+	// Setting no rules removes the lifecycle of repository
+	SetRepositoryLifecycle(ctx context.Context, repository string, lifecycle *RepositoryLifecycle) error
+
+	// ListRepositoryLifecycles returns the lifecycles of all repositories that have one
+	ListRepositoryLifecycles(ctx context.Context) ([]*RepositoryLifecycle, error)
+
+	// ExpireLifecycle commits the removal of the entries expired by the lifecycle rules of
+	// repository from its lifecycle branch.  On dryRun the expired entries are only reported
+	ExpireLifecycle(ctx context.Context, repository string, dryRun bool) (*LifecycleReport, error)
+
+	// MigrateStorageNamespace copies the objects of repository to storageNamespace in
+	// batches, updating their physical addresses, and then moves repository to
+	// storageNamespace.  An interrupted migration resumes from its last batch when called
+	// again with the same storageNamespace
+	MigrateStorageNamespace(ctx context.Context, repository, storageNamespace string, params MigrateStorageNamespaceParams) (*StorageNamespaceMigration, error)
+
+	// GetStorageNamespaceMigration returns the progress of the storage namespace migration of
+	// repository, or fails with ErrMigrationNotFound
+	GetStorageNamespaceMigration(ctx context.Context, repository string) (*StorageNamespaceMigration, error)
+
+	// GetMetadataSchema returns the metadata schema of repository, an empty schema is returned when none was set
+	GetMetadataSchema(ctx context.Context, repository string) (*MetadataSchema, error)
+
+	// SetMetadataSchema sets the metadata schema of repository.  Entries created with metadata that
+	// breaks the schema fail with ErrInvalidMetadata
+	SetMetadataSchema(ctx context.Context, repository string, schema *MetadataSchema) error
+
+	// SetRepositoryReadOnly sets whether repository is read-only.  Writes, commits, merges and
+	// branch and tag changes of a read-only repository fail with ErrRepositoryReadOnly, while
+	// reads, exports and repository settings are still allowed
+	SetRepositoryReadOnly(ctx context.Context, repository string, readOnly bool) error
+
+	// ArchiveRepository hides repository from ListRepositories and rejects writes to it with
+	// ErrRepositoryArchived, then transitions its objects if params.TransitionObject is set.
+	// Its metadata is kept, so it can be restored by UnarchiveRepository
+	ArchiveRepository(ctx context.Context, repository string, params ArchiveRepositoryParams) error
+
+	// UnarchiveRepository transitions the objects of repository if params.TransitionObject is
+	// set, then lists it and accepts writes to it again
+	UnarchiveRepository(ctx context.Context, repository string, params ArchiveRepositoryParams) error
+
+	// SetDefaultBranch sets the default branch of repository to an existing branch
+	SetDefaultBranch(ctx context.Context, repository, branch string) error
+
 	// ListRepositories list repositories information, the bool returned is true when more repositories can be listed.
 	// In this case pass the last repository name as 'after' on the next call to ListRepositories
-	ListRepositories(ctx context.Context, limit int, after string) ([]*Repository, bool, error)
+	ListRepositories(ctx context.Context, limit int, after string, params ListRepositoriesParams) ([]*Repository, bool, error)
 
 	CreateBranch(ctx context.Context, repository, branch string, sourceBranch string) (*CommitLog, error)
 	DeleteBranch(ctx context.Context, repository, branch string) error
-	ListBranches(ctx context.Context, repository string, prefix string, limit int, after string) ([]*Branch, bool, error)
+	// RenameBranch renames branch to newName, keeping its commits, entries, exports and
+	// default branch status.  Retention rules filtering on branch are rewritten to newName.
+	// Commit references embed the branch name, so references to its commits change too.
+	RenameBranch(ctx context.Context, repository, branch, newName string) error
+	ListBranches(ctx context.Context, repository string, prefix string, limit int, after string, params ListBranchesParams) ([]*Branch, bool, error)
 	BranchExists(ctx context.Context, repository string, branch string) (bool, error)
 	GetBranchReference(ctx context.Context, repository, branch string) (string, error)
-	ResetBranch(ctx context.Context, repository, branch string) error
+	// RecreateBranch replaces branch with a new branch created from sourceBranch, discarding
+	// its commits and changes, in a single transaction.  It fails like DeleteBranch when
+	// branch cannot be deleted.
+	RecreateBranch(ctx context.Context, repository, branch string, sourceBranch string) (*CommitLog, error)
+	// ResetBranch moves the head of branch back to the commit of reference, removing the
+	// commits after it, or keeps the head when reference is empty.  A commit of another
+	// branch, e.g. of the lineage of branch, removes no commits: its entries are committed to
+	// branch as a new commit.  A hard reset also discards the uncommitted changes of branch.
+	ResetBranch(ctx context.Context, repository, branch, reference string, hard bool) error
+
+	// CreateTag creates tag on the commit of reference, a branch reference is resolved to
+	// the last commit of the branch.  Tags share the namespace of branches.
+	CreateTag(ctx context.Context, repository, tag string, reference string) (*Tag, error)
+	// DeleteTag deletes tag, the commit it references is not affected
+	DeleteTag(ctx context.Context, repository, tag string) error
+	// ListTags lists tags of repository with prefix, the bool returned is true when more
+	// tags can be listed after the last one returned
+	ListTags(ctx context.Context, repository string, prefix string, limit int, after string) ([]*Tag, bool, error)
 
 	// GetEntry returns the current entry for path in repository branch reference.  Returns
 	// the entry with ExpiredError if it has expired from underlying storage.
 	GetEntry(ctx context.Context, repository, reference string, path string, params GetEntryParams) (*Entry, error)
+	// GetEntries returns the entries of a batch of references and paths, reading all of them
+	// in a single query.  The entry at each index is the entry of the EntryRef at that index,
+	// or nil if it is not found.  Expired entries are nil unless params.ReturnExpired is set.
+	GetEntries(ctx context.Context, repository string, refs []EntryRef, params GetEntryParams) ([]*Entry, error)
+	// GetEntryHistory returns the commits reachable from reference that added, changed or
+	// removed the entry at path, newest first.  Pass the commit reference of the last record
+	// as 'after' to read the next page.
+	GetEntryHistory(ctx context.Context, repository, reference string, path string, after string, limit int) ([]*EntryHistoryRecord, bool, error)
 	CreateEntry(ctx context.Context, repository, branch string, entry Entry, params CreateEntryParams) error
+	// CreateEntries creates entries on branch in a single transaction, all or none of them
 	CreateEntries(ctx context.Context, repository, branch string, entries []Entry) error
 	DeleteEntry(ctx context.Context, repository, branch string, path string) error
+	// DeleteEntries deletes paths from branch in a single transaction.  The error at each
+	// index is nil when the path at that index was deleted, or ErrEntryNotFound.
+	DeleteEntries(ctx context.Context, repository, branch string, paths []string) ([]error, error)
+	// AcquireLock locks the paths of branch starting with path for owner until ttl passes, or
+	// extends the lock when owner already holds it.  It fails with ErrLocked when another owner
+	// holds a lock on a prefix of path or on paths starting with it.  Creating and deleting
+	// entries on a context marked by WithLockOwner fails with ErrLocked on paths locked by
+	// other owners.
+	AcquireLock(ctx context.Context, repository, branch, path, owner string, ttl time.Duration) (*BranchLock, error)
+	// ReleaseLock releases the lock owner holds on path of branch, or fails with ErrLockNotFound
+	ReleaseLock(ctx context.Context, repository, branch, path, owner string) error
+	// ListLocks returns the unexpired locks of branch, ordered by path
+	ListLocks(ctx context.Context, repository, branch string) ([]*BranchLock, error)
+	// ListTrash returns the unexpired entries under prefix in the trash of branch, ordered by path
+	ListTrash(ctx context.Context, repository, branch string, prefix, after string, limit int) ([]*TrashEntry, bool, error)
+	// RestoreEntry restores the entry at path from the trash of branch, as an uncommitted entry
+	RestoreEntry(ctx context.Context, repository, branch string, path string) error
+	// MoveEntry moves the entry at sourcePath on branch to destinationPath, as one uncommitted
+	// change that writes the destination and deletes the source.
+	MoveEntry(ctx context.Context, repository, branch string, sourcePath, destinationPath string) error
+	// MoveEntries moves all entries under sourcePrefix on branch to destinationPrefix in a
+	// single transaction, and returns the number of entries moved.
+	MoveEntries(ctx context.Context, repository, branch string, sourcePrefix, destinationPrefix string) (int, error)
+	// CopyEntry copies the entry at sourcePath on sourceReference of sourceRepository to
+	// destinationPath on destinationBranch of destinationRepository, and returns the new entry.
+	// The copy shares the physical address of its source when both repositories share a
+	// storage namespace, otherwise params.CopyObject copies the object.
+	CopyEntry(ctx context.Context, sourceRepository, sourceReference, sourcePath, destinationRepository, destinationBranch, destinationPath string, params CopyEntryParams) (*Entry, error)
 	ListEntries(ctx context.Context, repository, reference string, prefix, after string, delimiter string, limit int) ([]*Entry, bool, error)
+	// ListEntriesIterator returns an iterator over the entries of repository reference under
+	// prefix, in path order.  It reads entries in batches as it advances, so walking all the
+	// entries of a reference holds neither a transaction nor all the entries.
+	ListEntriesIterator(ctx context.Context, repository, reference, prefix string) (EntryIterator, error)
+	// SearchEntries returns entries in repository reference whose path contains all the words
+	// of query, ordered by path.  Pass the last path as 'after' to read the next page.
+	SearchEntries(ctx context.Context, repository, reference string, query string, after string, limit int) ([]*Entry, bool, error)
+	// GetPrefixStats returns the number and total size of objects in repository reference under
+	// prefix and under each of its sub-prefixes up to depth path levels below it, ordered by
+	// prefix.  Depth 0 returns only the totals of prefix.
+	GetPrefixStats(ctx context.Context, repository, reference string, prefix string, depth int) ([]*PrefixStats, error)
+	// GetEntriesByChecksum returns entries in repository reference whose object has checksum,
+	// ordered by path.  Pass the last path as 'after' to read the next page.
+	GetEntriesByChecksum(ctx context.Context, repository, reference string, checksum string, after string, limit int) ([]*Entry, bool, error)
+	// SearchEntriesByMetadata returns entries in repository reference under prefix whose
+	// metadata has key, with value unless value is empty, ordered by path.  Pass the last path
+	// as 'after' to read the next page.
+	SearchEntriesByMetadata(ctx context.Context, repository, reference string, key, value string, prefix, after string, limit int) ([]*Entry, bool, error)
 	ResetEntry(ctx context.Context, repository, branch string, path string) error
 	ResetEntries(ctx context.Context, repository, branch string, prefix string) error
 
@@ -115,29 +380,125 @@ type Cataloger interface {
 
 	DedupReportChannel() chan *DedupReport
 
-	CreateMultipartUpload(ctx context.Context, repository, uploadID, path, physicalAddress string, creationTime time.Time) error
+	CreateMultipartUpload(ctx context.Context, repository, uploadID, path, physicalAddress string, creationTime time.Time, params CreateMultipartUploadParams) error
 	GetMultipartUpload(ctx context.Context, repository, uploadID string) (*MultipartUpload, error)
 	DeleteMultipartUpload(ctx context.Context, repository, uploadID string) error
 
-	Commit(ctx context.Context, repository, branch string, message string, committer string, metadata Metadata) (*CommitLog, error)
+	Commit(ctx context.Context, repository, branch string, message string, committer string, metadata Metadata, params CommitParams) (*CommitLog, error)
+	// AmendCommit replaces the message and metadata of the last commit of branch, which was
+	// not merged, branched or tagged yet, keeping its reference.  Folding in changes also
+	// requires that data lineage does not record the commit, and clears an export state at
+	// the commit.
+	AmendCommit(ctx context.Context, repository, branch string, message string, metadata Metadata, params AmendCommitParams) (*CommitLog, error)
 	GetCommit(ctx context.Context, repository, reference string) (*CommitLog, error)
-	ListCommits(ctx context.Context, repository, branch string, fromReference string, limit int) ([]*CommitLog, bool, error)
+	// GetCommitGraph returns the commits reachable from reference through at most depth parent
+	// edges, nearest first, including merge parents
+	GetCommitGraph(ctx context.Context, repository, reference string, depth int) (*CommitGraph, error)
+	// ListCommits returns the commits of branch before fromReference that match filter,
+	// newest first
+	ListCommits(ctx context.Context, repository, branch string, fromReference string, limit int, filter CommitsFilter) ([]*CommitLog, bool, error)
+	// ListCommitsSince returns the commits created on branch after the commit at sinceReference,
+	// oldest first.  It fails with ErrCommitNotFound when that commit is no longer on branch.
+	ListCommitsSince(ctx context.Context, repository, branch string, sinceReference string, limit int) ([]*CommitLog, bool, error)
+	// SearchCommits returns commits whose message contains all the words of query, newest
+	// first, starting after the commit referenced by after when it is set.  Only commits
+	// reachable from reference are searched, or all commits of the repository if reference
+	// is empty.
+	SearchCommits(ctx context.Context, repository, reference string, query string, after string, limit int) ([]*CommitLog, bool, error)
 	RollbackCommit(ctx context.Context, repository, reference string) error
 
+	// CreateCommitJob starts a commit of the uncommitted entries of branch that is performed in
+	// chunks by RunCommitJob.  While the job runs, writes, commits and merges of branch fail
+	// with ErrCommitJobInProgress.
+	CreateCommitJob(ctx context.Context, repository, branch string, message string, committer string, metadata Metadata) (*CommitJob, error)
+	// RunCommitJob stages the remaining chunks of job id and then commits them all at once.
+	// An interrupted job is resumed from its last staged chunk, a job that failed is rolled
+	// back and fails with ErrCommitJobNotRunning.
+	RunCommitJob(ctx context.Context, repository string, id int64) (*CommitJob, error)
+	// AbortCommitJob rolls back the running job id and marks it failed, releasing its branch
+	AbortCommitJob(ctx context.Context, repository string, id int64) (*CommitJob, error)
+	GetCommitJob(ctx context.Context, repository string, id int64) (*CommitJob, error)
+	// ListCommitJobs returns the commit jobs of repository matching params, newest first
+	ListCommitJobs(ctx context.Context, repository string, params ListCommitJobsParams) ([]*CommitJob, bool, error)
+
+	// CreateDataLineage records that the commit at reference was produced from sources.
+	// Branch references are resolved to their last commit.
+	CreateDataLineage(ctx context.Context, repository, reference string, sources []DataLineageSource) error
+	// WalkDataLineage returns the data lineage edges reachable from the commit at reference
+	// in direction, up to depth edges away, ordered by depth.
+	WalkDataLineage(ctx context.Context, repository, reference string, direction DataLineageDirection, depth int) ([]*DataLineageEdge, error)
+
 	Diff(ctx context.Context, repository, leftReference string, rightReference string, params DiffParams) (Differences, bool, error)
-	DiffUncommitted(ctx context.Context, repository, branch string, limit int, after string) (Differences, bool, error)
+	DiffSummary(ctx context.Context, repository, leftReference string, rightReference string) (*DiffSummary, error)
+	// DiffBranches compares leftBranch to rightBranch including the uncommitted changes of both,
+	// listing paths only on leftBranch as added and paths only on rightBranch as removed.  The
+	// bool returned is true when there are more differences.
+	DiffBranches(ctx context.Context, repository, leftBranch, rightBranch string, params DiffParams) (Differences, bool, error)
+	// DiffUncommitted lists the uncommitted changes of branch to paths with prefix, after the
+	// path after.  The bool returned is true when there are more changes.
+	DiffUncommitted(ctx context.Context, repository, branch, prefix string, limit int, after string) (Differences, bool, error)
+	// DiffCounts counts the differences Diff returns between references, per type and
+	// top-level prefix, without reading the differences themselves
+	DiffCounts(ctx context.Context, repository, leftReference string, rightReference string) (*DiffCounts, error)
+	// DiffChecksums counts the objects of leftReference added, removed or changed compared to
+	// rightReference by path and checksum only, without reading the commits relating them.
+	// Branch references include their uncommitted changes.
+	DiffChecksums(ctx context.Context, repository, leftReference string, rightReference string) (*ChecksumDiffSummary, error)
+	// DiffUncommittedCounts counts the differences DiffUncommitted returns for a branch
+	DiffUncommittedCounts(ctx context.Context, repository, branch string) (*DiffCounts, error)
 
-	Merge(ctx context.Context, repository, leftBranch, rightBranch, committer, message string, metadata Metadata) (*MergeResult, error)
+	Merge(ctx context.Context, repository, leftBranch, rightBranch, committer, message string, metadata Metadata, params MergeParams) (*MergeResult, error)
+	// MergePreview runs the diff of merging leftBranch into rightBranch without writing
+	// anything, and returns the changes the merge would apply and its conflicts
+	MergePreview(ctx context.Context, repository, leftBranch, rightBranch string) (*MergePreview, error)
+	// Cherrypick applies the changes of the commit of reference onto targetBranch as a new
+	// commit.  Like Merge, it fails with ErrConflictFound when targetBranch changed the same
+	// paths differently, and the result summarizes the applied changes and conflicts.
+	Cherrypick(ctx context.Context, repository, targetBranch, reference, committer string) (*MergeResult, error)
+	// Revert commits on branch the changes undoing the commit of reference, or all commits
+	// after sinceReference up to reference when sinceReference is not empty.  It fails with
+	// ErrConflictFound when branch changed the reverted paths since.
+	Revert(ctx context.Context, repository, branch, reference, sinceReference, committer string) (*MergeResult, error)
+	// Rebase recreates branch from ontoReference, a commit of its parent branch, replaying the
+	// commits branch made itself as new commits.  It fails with ErrConflictFound when a replayed
+	// commit changes paths that differ on ontoReference.
+	Rebase(ctx context.Context, repository, branch, ontoReference string) (*MergeResult, error)
 
 	Hooks() *CatalogerHooks
 
-	GetExportConfigurationForBranch(repository string, branch string) (ExportConfiguration, error)
-	GetExportConfigurations() ([]ExportConfigurationForBranch, error)
-	PutExportConfiguration(repository string, branch string, conf *ExportConfiguration) error
+	// GetExportConfigurationForBranch returns the export configuration of branch for prefix
+	GetExportConfigurationForBranch(ctx context.Context, repository string, branch string, prefix string) (ExportConfiguration, error)
+	// GetExportConfigurationsForBranch returns all export configurations of branch, ordered by prefix
+	GetExportConfigurationsForBranch(ctx context.Context, repository string, branch string) ([]ExportConfiguration, error)
+	GetExportConfigurations(ctx context.Context) ([]ExportConfigurationForBranch, error)
+	PutExportConfiguration(ctx context.Context, repository string, branch string, conf *ExportConfiguration) error
 
-	ExportStateSet(repo, branch string, cb ExportStateCallback) error
+	ExportStateSet(ctx context.Context, repo, branch string, cb ExportStateCallback) error
 	// GetExportState returns the current Export state params
-	GetExportState(repo string, branch string) (ExportState, error)
+	GetExportState(ctx context.Context, repo string, branch string) (ExportState, error)
+	// MarkExportScheduledRun records run as the last scheduled export of branch, unless a run
+	// at or after it was already recorded.  It returns whether run was recorded.
+	MarkExportScheduledRun(ctx context.Context, repo, branch string, run time.Time) (bool, error)
+
+	// InsertExportRun records the start of run of an export of branch
+	InsertExportRun(ctx context.Context, repo, branch string, run *ExportRun) error
+	// SetExportRunCounts sets the numbers of objects and bytes planned to export by run exportID
+	SetExportRunCounts(ctx context.Context, exportID string, objectsCopied, objectsDeleted, bytesCopied int64) error
+	// EndExportRun records the end of run exportID with status
+	EndExportRun(ctx context.Context, exportID string, status CatalogBranchExportStatus, errorMessage *string) error
+	// GetExportRuns returns the export runs of branch, latest first, starting after run ID after
+	GetExportRuns(ctx context.Context, repo, branch string, limit int, after string) ([]*ExportRun, bool, error)
+	// InsertExportRunDestinations records that run exportID started exporting to destinations
+	InsertExportRunDestinations(ctx context.Context, exportID string, destinations []string) error
+	// EndExportRunDestination records the end of the export of run exportID to destination
+	EndExportRunDestination(ctx context.Context, exportID, destination string, status CatalogBranchExportStatus, errorMessage *string) error
+
+	// InsertRefExport records the start of a one-off export of a ref of repository
+	InsertRefExport(ctx context.Context, repo string, export *RefExport) error
+	// GetRefExport returns the one-off export exportID of repository
+	GetRefExport(ctx context.Context, repo, exportID string) (*RefExport, error)
+	// EndRefExport records the end of the one-off export exportID with status
+	EndRefExport(ctx context.Context, exportID string, status CatalogBranchExportStatus, errorMessage *string) error
 
 	io.Closer
 }
@@ -150,6 +511,14 @@ type ExportStateCallback func(oldRef string, state CatalogBranchExportStatus) (n
 // back.  Because these transactions are current, the hook can see the effect the operation only
 // on the passed transaction.
 type CatalogerHooks struct {
+	// PreCommit hooks are called at the start of a commit or commit job, before anything is
+	// written.  Return a *ValidationError to reject the commit with the rules it breaks.
+	PreCommit []func(ctx context.Context, tx db.Tx, commit *CommitValidation) error
+
+	// PreMerge hooks are called at the start of a merge, before anything is written.  Return a
+	// *ValidationError to reject the merge with the rules it breaks.
+	PreMerge []func(ctx context.Context, tx db.Tx, merge *MergeValidation) error
+
 	// PostCommit hooks are called at the end of a commit.
 	PostCommit []func(ctx context.Context, tx db.Tx, commitLog *CommitLog) error
 
@@ -157,6 +526,16 @@ type CatalogerHooks struct {
 	PostMerge []func(ctx context.Context, tx db.Tx, mergeResult *MergeResult) error
 }
 
+func (h *CatalogerHooks) AddPreCommit(f func(context.Context, db.Tx, *CommitValidation) error) *CatalogerHooks {
+	h.PreCommit = append(h.PreCommit, f)
+	return h
+}
+
+func (h *CatalogerHooks) AddPreMerge(f func(context.Context, db.Tx, *MergeValidation) error) *CatalogerHooks {
+	h.PreMerge = append(h.PreMerge, f)
+	return h
+}
+
 func (h *CatalogerHooks) AddPostCommit(f func(context.Context, db.Tx, *CommitLog) error) *CatalogerHooks {
 	h.PostCommit = append(h.PostCommit, f)
 	return h
@@ -0,0 +1,50 @@
+package catalog
+
+import "time"
+
+const (
+	MaxListCommitJobsAmount     = 1000
+	DefaultListCommitJobsAmount = 100
+)
+
+type CommitJobStatus string
+
+const (
+	CommitJobStatusRunning   CommitJobStatus = "running"
+	CommitJobStatusFailed    CommitJobStatus = "failed"
+	CommitJobStatusCompleted CommitJobStatus = "completed"
+)
+
+// CommitJob commits the uncommitted entries of a branch in chunks: each chunk stages the paths
+// it commits in its own transaction, so collecting millions of entries does not time out.
+// Staged chunks are not visible, the entries of all of them are committed together with the
+// commit once the last chunk is staged.  An interrupted job is resumed from its last staged
+// chunk, a failed or aborted job is rolled back and final.
+type CommitJob struct {
+	ID         int64           `db:"id"`
+	Repository string          `db:"repository"`
+	Branch     string          `db:"branch"`
+	Committer  string          `db:"committer"`
+	Message    string          `db:"message"`
+	Metadata   Metadata        `db:"metadata"`
+	Status     CommitJobStatus `db:"status"`
+	// After is the last path staged by the job, chunks are staged in path order
+	After            string `db:"after_path"`
+	CommittedEntries int64  `db:"committed_entries"`
+	// Error holds the error of a failed job
+	Error string `db:"error"`
+	// Reference is the commit created by the job, empty until it completes
+	Reference    string    `db:"-"`
+	CreationDate time.Time `db:"creation_date"`
+	UpdateDate   time.Time `db:"update_date"`
+}
+
+// ListCommitJobsParams filters and paginates the commit jobs listed, empty fields match all
+// jobs
+type ListCommitJobsParams struct {
+	Branch string
+	Status CommitJobStatus
+	// After is the ID of the last job of the previous page
+	After int64
+	Limit int
+}
@@ -0,0 +1,41 @@
+package catalog
+
+import "context"
+
+// CommitLimits limits the size of a single commit to a repository.  A zero value for a field
+// means no limit.
+type CommitLimits struct {
+	MaxChangedObjects int64 `db:"max_changed_objects" json:"max_changed_objects"`
+	MaxAddedBytes     int64 `db:"max_added_bytes" json:"max_added_bytes"`
+}
+
+// CommitSize reports the size of the uncommitted changes of a branch
+type CommitSize struct {
+	ChangedObjects int64 `db:"changed_objects" json:"changed_objects"`
+	AddedBytes     int64 `db:"added_bytes" json:"added_bytes"`
+}
+
+// Exceeds returns true if size is over any limit set by limits.
+func (l *CommitLimits) Exceeds(size *CommitSize) bool {
+	if l.MaxChangedObjects > 0 && size.ChangedObjects > l.MaxChangedObjects {
+		return true
+	}
+	if l.MaxAddedBytes > 0 && size.AddedBytes > l.MaxAddedBytes {
+		return true
+	}
+	return false
+}
+
+type commitLimitsExemptKey struct{}
+
+// WithCommitLimitsExempt returns a context for commits that are not checked against the
+// commit limits of their repository
+func WithCommitLimitsExempt(ctx context.Context) context.Context {
+	return context.WithValue(ctx, commitLimitsExemptKey{}, true)
+}
+
+// IsCommitLimitsExempt returns true if commits on ctx are not checked against commit limits
+func IsCommitLimitsExempt(ctx context.Context) bool {
+	exempt, _ := ctx.Value(commitLimitsExemptKey{}).(bool)
+	return exempt
+}
@@ -0,0 +1,32 @@
+package catalog
+
+import "context"
+
+// Metadata keys of the commits attributed to an authenticated Committer
+const (
+	CommitterMetadataKey   = "lakefs_committer"
+	AccessKeyIDMetadataKey = "lakefs_access_key_id"
+)
+
+// Committer is the authenticated user making commits
+type Committer struct {
+	Username string
+	// AccessKeyID is the access key the user authenticated with, empty if they authenticated
+	// otherwise
+	AccessKeyID string
+}
+
+type committerKey struct{}
+
+// WithCommitter returns a context for commits, merges and reverts made by committer.  They
+// are attributed to committer regardless of the committer passed to the cataloger.
+func WithCommitter(ctx context.Context, committer Committer) context.Context {
+	return context.WithValue(ctx, committerKey{}, committer)
+}
+
+// CommitterFromContext returns the committer of commits made on ctx, false if ctx has no
+// authenticated committer
+func CommitterFromContext(ctx context.Context) (Committer, bool) {
+	committer, ok := ctx.Value(committerKey{}).(Committer)
+	return committer, ok
+}
@@ -0,0 +1,113 @@
+package catalog
+
+import (
+	"sort"
+	"time"
+)
+
+type DataLineageDirection string
+
+const (
+	// DataLineageUpstream walks from a commit to the commits it was produced from
+	DataLineageUpstream DataLineageDirection = "upstream"
+	// DataLineageDownstream walks from a commit to the commits produced from it
+	DataLineageDownstream DataLineageDirection = "downstream"
+)
+
+// DataLineageSource identifies a commit some data was produced from.  Reference may be a
+// branch, in which case it refers to the last commit of the branch.
+type DataLineageSource struct {
+	Repository string
+	Reference  string
+}
+
+// DataLineageEdge records that the commit Reference of Repository was produced from the commit
+// SourceReference of SourceRepository.  Depth is the distance of the edge from the commit a walk
+// started at, starting from 1.
+type DataLineageEdge struct {
+	Repository       string
+	Reference        string
+	SourceRepository string
+	SourceReference  string
+	CreationDate     time.Time
+	Depth            int
+}
+
+type dataLineageNode struct {
+	repository string
+	reference  string
+}
+
+// FilterDataLineage returns the edges of a walk in direction that stay reachable from its
+// start commit when the edges touching a repository that readable rejects are dropped, so no
+// commit is reached through a repository the caller may not read.  Depths are recomputed on the
+// remaining edges, and edges deeper than depth are dropped unless depth is not positive.
+func FilterDataLineage(edges []*DataLineageEdge, direction DataLineageDirection, depth int, readable func(repository string) bool) []*DataLineageEdge {
+	ends := func(edge *DataLineageEdge) (dataLineageNode, dataLineageNode) {
+		target := dataLineageNode{repository: edge.Repository, reference: edge.Reference}
+		source := dataLineageNode{repository: edge.SourceRepository, reference: edge.SourceReference}
+		if direction == DataLineageDownstream {
+			return source, target
+		}
+		return target, source
+	}
+	allowed := make(map[string]bool)
+	isReadable := func(repository string) bool {
+		ok, checked := allowed[repository]
+		if !checked {
+			ok = readable(repository)
+			allowed[repository] = ok
+		}
+		return ok
+	}
+
+	// breadth first walk over the readable edges, from the start commit of the depth 1 edges
+	outgoing := make(map[dataLineageNode][]int)
+	nodeDepth := make(map[dataLineageNode]int)
+	for i, edge := range edges {
+		if !isReadable(edge.Repository) || !isReadable(edge.SourceRepository) {
+			continue
+		}
+		from, _ := ends(edge)
+		outgoing[from] = append(outgoing[from], i)
+		if edge.Depth == 1 {
+			nodeDepth[from] = 0
+		}
+	}
+	var queue []dataLineageNode
+	for node := range nodeDepth {
+		queue = append(queue, node)
+	}
+	edgeDepth := make(map[int]int)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		d := nodeDepth[node] + 1
+		if depth > 0 && d > depth {
+			continue
+		}
+		for _, i := range outgoing[node] {
+			edgeDepth[i] = d
+			_, to := ends(edges[i])
+			if _, ok := nodeDepth[to]; !ok {
+				nodeDepth[to] = d
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	result := make([]*DataLineageEdge, 0, len(edgeDepth))
+	for i, edge := range edges {
+		d, ok := edgeDepth[i]
+		if !ok {
+			continue
+		}
+		filtered := *edge
+		filtered.Depth = d
+		result = append(result, &filtered)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Depth < result[j].Depth
+	})
+	return result
+}
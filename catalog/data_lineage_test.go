@@ -0,0 +1,80 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestFilterDataLineage(t *testing.T) {
+	// reports is produced from events and users, events from raw and users from hidden, which
+	// is produced from raw too
+	edges := []*DataLineageEdge{
+		{Repository: "reports", Reference: "~r1", SourceRepository: "events", SourceReference: "~e1", Depth: 1},
+		{Repository: "reports", Reference: "~r1", SourceRepository: "users", SourceReference: "~u1", Depth: 1},
+		{Repository: "events", Reference: "~e1", SourceRepository: "raw", SourceReference: "~w1", Depth: 2},
+		{Repository: "users", Reference: "~u1", SourceRepository: "hidden", SourceReference: "~h1", Depth: 2},
+		{Repository: "hidden", Reference: "~h1", SourceRepository: "raw", SourceReference: "~w2", Depth: 3},
+		{Repository: "raw", Reference: "~w2", SourceRepository: "archive", SourceReference: "~a1", Depth: 4},
+	}
+	readable := func(repository string) bool { return repository != "hidden" }
+	tests := []struct {
+		name      string
+		edges     []*DataLineageEdge
+		direction DataLineageDirection
+		depth     int
+		want      []*DataLineageEdge
+	}{
+		{
+			name:      "upstream",
+			edges:     edges,
+			direction: DataLineageUpstream,
+			want:      edges[:3],
+		},
+		{
+			name:      "upstream depth",
+			edges:     edges,
+			direction: DataLineageUpstream,
+			depth:     1,
+			want:      edges[:2],
+		},
+		{
+			name: "downstream",
+			edges: []*DataLineageEdge{
+				{Repository: "events", Reference: "~e1", SourceRepository: "raw", SourceReference: "~w1", Depth: 1},
+				{Repository: "hidden", Reference: "~h1", SourceRepository: "raw", SourceReference: "~w1", Depth: 1},
+				{Repository: "reports", Reference: "~r1", SourceRepository: "hidden", SourceReference: "~h1", Depth: 2},
+				{Repository: "reports", Reference: "~r1", SourceRepository: "events", SourceReference: "~e1", Depth: 2},
+			},
+			direction: DataLineageDownstream,
+			want: []*DataLineageEdge{
+				{Repository: "events", Reference: "~e1", SourceRepository: "raw", SourceReference: "~w1", Depth: 1},
+				{Repository: "reports", Reference: "~r1", SourceRepository: "events", SourceReference: "~e1", Depth: 2},
+			},
+		},
+		{
+			name: "depth through hidden repository",
+			edges: []*DataLineageEdge{
+				{Repository: "reports", Reference: "~r1", SourceRepository: "hidden", SourceReference: "~h1", Depth: 1},
+				{Repository: "reports", Reference: "~r1", SourceRepository: "events", SourceReference: "~e1", Depth: 1},
+				{Repository: "hidden", Reference: "~h1", SourceRepository: "raw", SourceReference: "~w1", Depth: 2},
+				{Repository: "events", Reference: "~e1", SourceRepository: "users", SourceReference: "~u1", Depth: 2},
+				{Repository: "users", Reference: "~u1", SourceRepository: "raw", SourceReference: "~w1", Depth: 3},
+			},
+			direction: DataLineageUpstream,
+			want: []*DataLineageEdge{
+				{Repository: "reports", Reference: "~r1", SourceRepository: "events", SourceReference: "~e1", Depth: 1},
+				{Repository: "events", Reference: "~e1", SourceRepository: "users", SourceReference: "~u1", Depth: 2},
+				{Repository: "users", Reference: "~u1", SourceRepository: "raw", SourceReference: "~w1", Depth: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterDataLineage(tt.edges, tt.direction, tt.depth, readable)
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Fatalf("FilterDataLineage() %s", diff)
+			}
+		})
+	}
+}
@@ -18,7 +18,9 @@ type Difference struct {
 type DiffResultRecord struct {
 	TargetEntryNotInDirectBranch bool // the entry is reflected via lineage, NOT in the branch itself
 	Difference
-	EntryCtid *string // CTID of the modified/added entry. Do not use outside of catalog diff-by-iterators. https://github.com/treeverse/lakeFS/issues/831
+	EntryCtid        *string // CTID of the modified/added entry. Do not use outside of catalog diff-by-iterators. https://github.com/treeverse/lakeFS/issues/831
+	ResolvedConflict bool    // a conflict the merge strategy resolved to the source change
+	MergedEntry      *Entry  // entry merging the content of a resolved conflict, written instead of the source entry
 }
 
 func (d Difference) String() string {
@@ -38,6 +40,55 @@ func (d Difference) String() string {
 
 type Differences []Difference
 
+// DiffSummary aggregates the differences between two references
+type DiffSummary struct {
+	// CommitsAhead is the number of commits reachable from the left reference and not from the right
+	CommitsAhead int
+	// CommitsBehind is the number of commits reachable from the right reference and not from the left
+	CommitsBehind int
+	Added         int
+	Removed       int
+	Changed       int
+	Conflicts     int
+	// BytesDelta is the change in size of the right reference's entries after applying the differences
+	BytesDelta int64
+}
+
+// DiffCounts counts the differences between two references by type, without listing them
+type DiffCounts struct {
+	Added     int `db:"added"`
+	Removed   int `db:"removed"`
+	Changed   int `db:"changed"`
+	Conflicts int `db:"conflicts"`
+	// Prefixes counts the differences under each top-level prefix, ordered by prefix.
+	// Differences of objects that are not under any prefix are counted under the empty prefix
+	Prefixes []DiffPrefixCounts
+}
+
+// DiffPrefixCounts counts the differences under a top-level prefix, such as "logs/"
+type DiffPrefixCounts struct {
+	Prefix    string `db:"prefix"`
+	Added     int    `db:"added"`
+	Removed   int    `db:"removed"`
+	Changed   int    `db:"changed"`
+	Conflicts int    `db:"conflicts"`
+}
+
+// ChecksumDiffSummary counts the objects of a left reference that differ by checksum from the
+// objects at the same paths of a right reference
+type ChecksumDiffSummary struct {
+	Added   int `db:"added"`
+	Removed int `db:"removed"`
+	Changed int `db:"changed"`
+	// BytesDelta is the change in size of the right reference's objects after applying the differences
+	BytesDelta int64 `db:"bytes_delta"`
+}
+
+// Identical reports whether both references hold the same objects at the same paths
+func (s ChecksumDiffSummary) Identical() bool {
+	return s.Added == 0 && s.Removed == 0 && s.Changed == 0
+}
+
 func (d Differences) Equal(other Differences) bool {
 	if len(d) != len(other) {
 		return false
@@ -0,0 +1,9 @@
+package catalog
+
+// EntryIterator iterates over entries.  Next advances it to the next entry and returns false
+// when there are no more entries or it failed; Err reports the failure.
+type EntryIterator interface {
+	Next() bool
+	Value() *Entry
+	Err() error
+}
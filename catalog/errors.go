@@ -15,6 +15,7 @@ var (
 	ErrFeatureNotSupported         = errors.New("feature not supported")
 	ErrBranchNotFound              = fmt.Errorf("branch %w", db.ErrNotFound)
 	ErrCommitNotFound              = fmt.Errorf("commit %w", db.ErrNotFound)
+	ErrTagNotFound                 = fmt.Errorf("tag %w", db.ErrNotFound)
 	ErrRepositoryNotFound          = fmt.Errorf("repository %w", db.ErrNotFound)
 	ErrMultipartUploadNotFound     = fmt.Errorf("multipart upload %w", db.ErrNotFound)
 	ErrEntryNotFound               = fmt.Errorf("entry %w", db.ErrNotFound)
@@ -33,4 +34,21 @@ var (
 	ErrUnsupportedDelimiter        = errors.New("unsupported delimiter")
 	ErrBadTypeConversion           = errors.New("bad type")
 	ErrExportFailed                = errors.New("export failed")
+	ErrExportInProgress            = errors.New("export in progress")
+	ErrQuotaExceeded               = errors.New("quota exceeded")
+	ErrRequestRateExceeded         = errors.New("request rate exceeded")
+	ErrOrganizationNotFound        = fmt.Errorf("organization %w", db.ErrNotFound)
+	ErrCommitLimitExceeded         = errors.New("commit limit exceeded")
+	ErrInvalidMetadata             = errors.New("invalid metadata")
+	ErrCommitJobInProgress         = errors.New("commit job in progress")
+	ErrCommitJobNotFound           = fmt.Errorf("commit job %w", db.ErrNotFound)
+	ErrCommitJobNotRunning         = errors.New("commit job not running")
+	ErrCommitNotAmendable          = errors.New("commit cannot be amended")
+	ErrValidationFailed            = errors.New("validation failed")
+	ErrLocked                      = errors.New("locked by another owner")
+	ErrLockNotFound                = fmt.Errorf("lock %w", db.ErrNotFound)
+	ErrChecksumMismatch            = errors.New("checksum mismatch")
+	ErrMigrationNotFound           = fmt.Errorf("storage namespace migration %w", db.ErrNotFound)
+	ErrRepositoryReadOnly          = errors.New("repository is read-only")
+	ErrRepositoryArchived          = fmt.Errorf("archived %w", ErrRepositoryReadOnly)
 )
@@ -3,20 +3,129 @@ package catalog
 import (
 	"database/sql/driver"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/lib/pq"
 )
 
 // ExportConfiguration describes the export configuration of a branch, as passed on wire, used
-// internally, and stored in DB.
+// internally, and stored in DB.  A branch may have several export configurations, each
+// exporting the entries under its Prefix.  Its paths may hold placeholders (such as
+// ExportPathCommit), expanded by ExpandPaths when each export starts.
 type ExportConfiguration struct {
+	// Prefix selects the entries exported by this configuration, empty to export all
+	// entries.  Exported entries keep their full path under Path.
+	Prefix                 string         `db:"prefix" json:"prefix"`
 	Path                   string         `db:"export_path" json:"export_path"`
 	StatusPath             string         `db:"export_status_path" json:"export_status_path"`
 	LastKeysInPrefixRegexp pq.StringArray `db:"last_keys_in_prefix_regexp" json:"last_keys_in_prefix_regexp"`
 	IsContinuous           bool           `db:"continuous" json:"is_continuous"`
+	// Parallelism limits the number of objects concurrently exported, 0 for no limit.
+	Parallelism int `db:"parallelism" json:"parallelism"`
+	// Mode is ExportModeIncremental to export only the diff from the last successfully
+	// exported ref, or ExportModeFull to export the entire branch every time.
+	Mode string `db:"mode" json:"mode"`
+	// MaxAttempts is the number of attempts to export each object before failing the
+	// export, 0 for the default.
+	MaxAttempts int `db:"max_attempts" json:"max_attempts"`
+	// RetryBackoff is the delay before retrying to export an object the first time, it
+	// doubles on every further attempt.  0 retries immediately.
+	RetryBackoff time.Duration `db:"retry_backoff" json:"retry_backoff"`
+	// WriteManifest writes a manifest of all exported objects to StatusPath after every
+	// successful export.
+	WriteManifest bool `db:"write_manifest" json:"write_manifest"`
+	// Schedule (if set) is a cron expression of the times (in UTC) to export the branch.
+	Schedule string `db:"schedule" json:"schedule"`
+	// IncludePrefixes (if set) exports only entries under one of these prefixes
+	IncludePrefixes pq.StringArray `db:"include_prefixes" json:"include_prefixes"`
+	// ExcludeGlobs skips exporting entries whose path matches one of these globs, in the
+	// syntax of path.Match
+	ExcludeGlobs pq.StringArray `db:"exclude_globs" json:"exclude_globs"`
+	// Format is ExportFormatCopy to copy exported entries to Path, or ExportFormatSymlink
+	// to write a Hive symlink.txt file to each partition (directory) under Path, listing
+	// the physical addresses of its entries.
+	Format string `db:"format" json:"format"`
+	// PropagateDeletes deletes from Path the entries removed from the branch.  Exports
+	// without it only add and update objects.
+	PropagateDeletes bool `db:"propagate_deletes" json:"propagate_deletes"`
+	// AdditionalPaths are further destinations that every export copies the entries to,
+	// as to Path.  Each destination of a configuration with additional paths records its
+	// own status on export runs.
+	AdditionalPaths pq.StringArray `db:"additional_export_paths" json:"additional_export_paths"`
+	// SSEAlgorithm (if set) is the S3 server-side encryption of copied objects,
+	// ExportSSEAlgorithmS3 or ExportSSEAlgorithmKMS.  SSEKMSKeyID (if set) is the ID or
+	// ARN of the KMS key encrypting them with ExportSSEAlgorithmKMS, otherwise the AWS
+	// managed key of the destination account is used.
+	SSEAlgorithm string `db:"sse_algorithm" json:"sse_algorithm"`
+	SSEKMSKeyID  string `db:"sse_kms_key_id" json:"sse_kms_key_id"`
+}
+
+// Paths returns all destinations of the configuration, Path first
+func (c ExportConfiguration) Paths() []string {
+	return append([]string{c.Path}, c.AdditionalPaths...)
+}
+
+// Placeholders of export paths, expanded when each export starts
+const (
+	ExportPathBranch    = "{branch}"
+	ExportPathCommit    = "{commit}"
+	ExportPathTimestamp = "{timestamp}"
+)
+
+// ExportPathTimestampFormat formats the time (in UTC) an export started for
+// ExportPathTimestamp, so that dated paths sort by time
+const ExportPathTimestampFormat = "20060102T150405Z"
+
+var exportPathPlaceholderRegexp = regexp.MustCompile(`{[^{}]*}`)
+
+// ExportPathPlaceholders returns the placeholders in path, including unknown ones
+func ExportPathPlaceholders(path string) []string {
+	return exportPathPlaceholderRegexp.FindAllString(path, -1)
+}
+
+// IsPerExportPath returns whether path expands differently for every exported commit, so
+// that each export lands under a path of its own
+func IsPerExportPath(path string) bool {
+	return strings.Contains(path, ExportPathCommit) || strings.Contains(path, ExportPathTimestamp)
 }
 
+// ExpandPaths returns the configuration with the placeholders of its paths expanded for
+// exporting commitRef of branch, starting at startTime
+func (c ExportConfiguration) ExpandPaths(branch, commitRef string, startTime time.Time) ExportConfiguration {
+	replacer := strings.NewReplacer(
+		ExportPathBranch, branch,
+		ExportPathCommit, commitRef,
+		ExportPathTimestamp, startTime.UTC().Format(ExportPathTimestampFormat),
+	)
+	c.Path = replacer.Replace(c.Path)
+	c.StatusPath = replacer.Replace(c.StatusPath)
+	if c.AdditionalPaths != nil {
+		additionalPaths := make(pq.StringArray, len(c.AdditionalPaths))
+		for i, p := range c.AdditionalPaths {
+			additionalPaths[i] = replacer.Replace(p)
+		}
+		c.AdditionalPaths = additionalPaths
+	}
+	return c
+}
+
+const (
+	ExportModeIncremental = "incremental"
+	ExportModeFull        = "full"
+)
+
+const (
+	ExportFormatCopy    = "copy"
+	ExportFormatSymlink = "symlink"
+)
+
+const (
+	ExportSSEAlgorithmS3  = "AES256"
+	ExportSSEAlgorithmKMS = "aws:kms"
+)
+
 // ExportConfigurationForBranch describes how to export BranchID.  It is stored in the database.
 // Unfortunately golang sql doesn't know about embedded structs, so you get a useless copy of
 // ExportConfiguration embedded here.
@@ -24,10 +133,24 @@ type ExportConfigurationForBranch struct {
 	Repository string `db:"repository"`
 	Branch     string `db:"branch"`
 
+	Prefix                 string         `db:"prefix"`
 	Path                   string         `db:"export_path"`
 	StatusPath             string         `db:"export_status_path"`
 	LastKeysInPrefixRegexp pq.StringArray `db:"last_keys_in_prefix_regexp"`
 	IsContinuous           bool           `db:"continuous"`
+	Parallelism            int            `db:"parallelism"`
+	Mode                   string         `db:"mode"`
+	MaxAttempts            int            `db:"max_attempts"`
+	RetryBackoff           time.Duration  `db:"retry_backoff"`
+	WriteManifest          bool           `db:"write_manifest"`
+	Schedule               string         `db:"schedule"`
+	IncludePrefixes        pq.StringArray `db:"include_prefixes"`
+	ExcludeGlobs           pq.StringArray `db:"exclude_globs"`
+	Format                 string         `db:"format"`
+	PropagateDeletes       bool           `db:"propagate_deletes"`
+	AdditionalPaths        pq.StringArray `db:"additional_export_paths"`
+	SSEAlgorithm           string         `db:"sse_algorithm"`
+	SSEKMSKeyID            string         `db:"sse_kms_key_id"`
 }
 
 type CatalogBranchExportStatus string
@@ -51,12 +174,67 @@ type ExportState struct {
 	CurrentRef   string
 	State        CatalogBranchExportStatus
 	ErrorMessage *string
+	// LastScheduledRun is the time of the last scheduled export of the branch, nil if it
+	// was never exported on schedule
+	LastScheduledRun *time.Time
+}
+
+// ExportRun records a single export of a branch, for auditing past exports
+type ExportRun struct {
+	ExportID string `db:"export_id" json:"export_id"`
+	// FromRef is the exported ref the export diffed from, empty when it exported all
+	// entries of ToRef
+	FromRef   string     `db:"from_ref" json:"from_ref"`
+	ToRef     string     `db:"to_ref" json:"to_ref"`
+	StartTime time.Time  `db:"start_time" json:"start_time"`
+	EndTime   *time.Time `db:"end_time" json:"end_time"`
+	// ObjectsCopied, ObjectsDeleted and BytesCopied count the objects planned to export,
+	// once the export generated its tasks
+	ObjectsCopied  int64                     `db:"objects_copied" json:"objects_copied"`
+	ObjectsDeleted int64                     `db:"objects_deleted" json:"objects_deleted"`
+	BytesCopied    int64                     `db:"bytes_copied" json:"bytes_copied"`
+	Status         CatalogBranchExportStatus `db:"status" json:"status"`
+	ErrorMessage   *string                   `db:"error_message" json:"error_message"`
+	// PathsTime is the time expanding the placeholders of the export paths of the run,
+	// nil for runs recorded before it was
+	PathsTime *time.Time `db:"paths_time" json:"paths_time,omitempty"`
+	// Destinations are the statuses of the destinations of configurations exporting to
+	// several paths, ordered by destination
+	Destinations []ExportRunDestination `db:"-" json:"destinations"`
+}
+
+// ExportRunDestination records the export of a run to one of the destinations of a
+// configuration exporting to several paths, so that a run failing on some destinations
+// shows which
+type ExportRunDestination struct {
+	Destination  string                    `db:"destination" json:"destination"`
+	EndTime      *time.Time                `db:"end_time" json:"end_time"`
+	Status       CatalogBranchExportStatus `db:"status" json:"status"`
+	ErrorMessage *string                   `db:"error_message" json:"error_message"`
+}
+
+// RefExport tracks a one-off export of a ref, such as a tag or a commit, to a destination.
+// Unlike branch exports it has no export configuration and always exports all entries.
+type RefExport struct {
+	ExportID string `db:"export_id" json:"export_id"`
+	Ref      string `db:"ref" json:"ref"`
+	// CommitRef is the commit that Ref resolved to when the export started
+	CommitRef    string                    `db:"commit_ref" json:"commit_ref"`
+	Destination  string                    `db:"destination" json:"destination"`
+	StartTime    time.Time                 `db:"start_time" json:"start_time"`
+	EndTime      *time.Time                `db:"end_time" json:"end_time"`
+	Status       CatalogBranchExportStatus `db:"status" json:"status"`
+	ErrorMessage *string                   `db:"error_message" json:"error_message"`
 }
 
 // nolint: stylecheck
 func (dst *CatalogBranchExportStatus) Scan(src interface{}) error {
 	var sc CatalogBranchExportStatus
 	switch s := src.(type) {
+	case nil:
+		// state of a branch that was only scheduled to export
+		*dst = ""
+		return nil
 	case string:
 		sc = CatalogBranchExportStatus(strings.ToLower(s))
 	case []byte:
@@ -65,7 +243,7 @@ func (dst *CatalogBranchExportStatus) Scan(src interface{}) error {
 		return fmt.Errorf("cannot convert %T to CatalogBranchExportStatus: %w", src, ErrBadTypeConversion)
 	}
 
-	if !(sc == ExportStatusInProgress || sc == ExportStatusSuccess || sc == ExportStatusFailed) {
+	if !(sc == ExportStatusInProgress || sc == ExportStatusSuccess || sc == ExportStatusFailed || sc == ExportStatusRepaired) {
 		// not a failure, "just" be a newer enum value than known
 		*dst = ExportStatusUnknown
 		return nil
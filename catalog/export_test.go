@@ -0,0 +1,46 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/lib/pq"
+)
+
+func TestExportConfiguration_ExpandPaths(t *testing.T) {
+	config := ExportConfiguration{
+		Prefix:          "tables/",
+		Path:            "s3://bucket/{branch}/{timestamp}",
+		StatusPath:      "s3://bucket/status/{branch}/{commit}",
+		AdditionalPaths: pq.StringArray{"s3://replica/{commit}/export", "s3://replica/latest"},
+	}
+	startTime := time.Date(2020, 11, 3, 14, 5, 9, 0, time.FixedZone("IST", 2*60*60))
+	got := config.ExpandPaths("main", "c0ffee", startTime)
+	expected := ExportConfiguration{
+		Prefix:          "tables/",
+		Path:            "s3://bucket/main/20201103T120509Z",
+		StatusPath:      "s3://bucket/status/main/c0ffee",
+		AdditionalPaths: pq.StringArray{"s3://replica/c0ffee/export", "s3://replica/latest"},
+	}
+	if diffs := deep.Equal(got, expected); diffs != nil {
+		t.Errorf("unexpected expanded configuration: %s", diffs)
+	}
+	if config.AdditionalPaths[0] != "s3://replica/{commit}/export" {
+		t.Errorf("expanding changed additional paths of the configuration to %s", config.AdditionalPaths)
+	}
+}
+
+func TestIsPerExportPath(t *testing.T) {
+	tests := map[string]bool{
+		"s3://bucket/export":              false,
+		"s3://bucket/{branch}/export":     false,
+		"s3://bucket/{commit}/export":     true,
+		"s3://bucket/export/{timestamp}/": true,
+	}
+	for path, expected := range tests {
+		if got := IsPerExportPath(path); got != expected {
+			t.Errorf("IsPerExportPath(%s) = %t, expected %t", path, got, expected)
+		}
+	}
+}
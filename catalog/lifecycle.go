@@ -0,0 +1,62 @@
+package catalog
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// LifecycleCommitter is the committer of the commits expiring entries by lifecycle rules
+const LifecycleCommitter = "lakefs-lifecycle"
+
+// LifecycleRule expires the committed entries under Prefix created more than MaxAgeDays ago,
+// and all but the MaxVersions most recently created of them.  Zero disables either limit.
+type LifecycleRule struct {
+	Prefix      string `json:"prefix"`
+	MaxAgeDays  int    `json:"max_age_days,omitempty"`
+	MaxVersions int    `json:"max_versions,omitempty"`
+}
+
+type LifecycleRules []LifecycleRule
+
+func (r LifecycleRules) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+func (r *LifecycleRules) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	data, ok := src.([]byte)
+	if !ok {
+		return ErrByteSliceTypeAssertion
+	}
+	return json.Unmarshal(data, r)
+}
+
+// RepositoryLifecycle holds the lifecycle rules of a repository, applied to the entries of
+// Branch.  A repository without rules has no lifecycle.
+type RepositoryLifecycle struct {
+	Repository string         `db:"repository"`
+	Branch     string         `db:"branch"`
+	Rules      LifecycleRules `db:"rules"`
+}
+
+// LifecycleExpiration is an entry expired by Rule
+type LifecycleExpiration struct {
+	Path         string
+	CreationDate time.Time
+	Rule         LifecycleRule
+}
+
+// LifecycleReport describes the entries expired by the lifecycle rules of a repository
+type LifecycleReport struct {
+	Repository string
+	Branch     string
+	// Reference is the commit removing the expired entries, empty on a dry run or when no
+	// entry expired
+	Reference string
+	Expired   []LifecycleExpiration
+	// Skipped are expired paths left in place because they have uncommitted changes
+	Skipped []string
+}
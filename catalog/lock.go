@@ -0,0 +1,29 @@
+package catalog
+
+import (
+	"context"
+	"time"
+)
+
+// BranchLock is an advisory lock held by Owner on the paths of a branch starting with Path,
+// until ExpirationDate
+type BranchLock struct {
+	Path           string    `db:"path"`
+	Owner          string    `db:"owner"`
+	AcquiredDate   time.Time `db:"acquired_date"`
+	ExpirationDate time.Time `db:"expiration_date"`
+}
+
+type lockOwnerKey struct{}
+
+// WithLockOwner returns a context for writes made by owner, that fail with ErrLocked on paths
+// locked by other owners.  Writes on contexts without an owner ignore locks.
+func WithLockOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, lockOwnerKey{}, owner)
+}
+
+// LockOwner returns the lock owner of writes on ctx, empty if writes on ctx ignore locks
+func LockOwner(ctx context.Context) string {
+	owner, _ := ctx.Value(lockOwnerKey{}).(string)
+	return owner
+}
@@ -0,0 +1,109 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MetadataSchema constrains the user metadata of objects written to a repository.  Every rule
+// whose prefix matches the path of an object applies to it.
+type MetadataSchema struct {
+	Rules []MetadataRule `json:"rules"`
+
+	compiled bool
+}
+
+type MetadataRule struct {
+	// Prefix selects the paths of the objects the rule applies to, all objects when empty
+	Prefix string `json:"prefix"`
+	// Required keys must be set on objects
+	Required []string `json:"required,omitempty"`
+	// Allowed keys may be set on objects, in addition to the required keys.  Any key is allowed
+	// when both Required and Allowed are empty.
+	Allowed []string `json:"allowed,omitempty"`
+	// Values maps keys to regular expressions their values must match
+	Values map[string]string `json:"values,omitempty"`
+
+	// values holds the compiled expressions of Values
+	values map[string]*regexp.Regexp
+}
+
+// Validate returns ErrInvalidValue when schema has an empty key or an invalid value expression
+func (s *MetadataSchema) Validate() error {
+	for _, rule := range s.Rules {
+		for _, keys := range [][]string{rule.Required, rule.Allowed} {
+			for _, key := range keys {
+				if key == "" {
+					return fmt.Errorf("prefix '%s' empty key: %w", rule.Prefix, ErrInvalidValue)
+				}
+			}
+		}
+	}
+	return s.Compile()
+}
+
+// Compile compiles the value expressions of the rules of schema once, for checking any number
+// of objects.  It returns ErrInvalidValue for an invalid expression.
+func (s *MetadataSchema) Compile() error {
+	if s.compiled {
+		return nil
+	}
+	for i := range s.Rules {
+		rule := &s.Rules[i]
+		rule.values = make(map[string]*regexp.Regexp, len(rule.Values))
+		for key, expr := range rule.Values {
+			// anchored, so an expression matches the whole value
+			re, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return fmt.Errorf("prefix '%s' key '%s' values: %s: %w", rule.Prefix, key, err, ErrInvalidValue)
+			}
+			rule.values[key] = re
+		}
+	}
+	s.compiled = true
+	return nil
+}
+
+// Check returns ErrInvalidMetadata when metadata of the object at path breaks a rule of schema.
+// It compiles the schema unless already compiled.
+func (s *MetadataSchema) Check(path string, metadata Metadata) error {
+	if err := s.Compile(); err != nil {
+		return err
+	}
+	for _, rule := range s.Rules {
+		if !strings.HasPrefix(path, rule.Prefix) {
+			continue
+		}
+		if err := rule.check(metadata); err != nil {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidMetadata, path, err)
+		}
+	}
+	return nil
+}
+
+func (r *MetadataRule) check(metadata Metadata) error {
+	known := make(map[string]struct{}, len(r.Required)+len(r.Allowed))
+	for _, key := range r.Required {
+		if _, ok := metadata[key]; !ok {
+			return fmt.Errorf("missing required key '%s'", key)
+		}
+		known[key] = struct{}{}
+	}
+	for _, key := range r.Allowed {
+		known[key] = struct{}{}
+	}
+	for key, value := range metadata {
+		if _, ok := known[key]; !ok && len(known) > 0 {
+			return fmt.Errorf("key '%s' not allowed", key)
+		}
+		re, ok := r.values[key]
+		if !ok {
+			continue
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("key '%s' value '%s' doesn't match '%s'", key, value, r.Values[key])
+		}
+	}
+	return nil
+}
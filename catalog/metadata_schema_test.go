@@ -0,0 +1,59 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMetadataSchema_Check(t *testing.T) {
+	schema := &MetadataSchema{
+		Rules: []MetadataRule{
+			{Prefix: "tables/", Required: []string{"owner"}, Allowed: []string{"format"}, Values: map[string]string{"format": "parquet|orc"}},
+			{Prefix: "tables/sales/", Required: []string{"region"}},
+			{Prefix: "raw/", Values: map[string]string{"source": "[a-z]+"}},
+		},
+	}
+	tests := []struct {
+		name     string
+		path     string
+		metadata Metadata
+		wantErr  bool
+	}{
+		{name: "no rule", path: "other/a", metadata: Metadata{"any": "value"}},
+		{name: "required", path: "tables/a", metadata: Metadata{"owner": "data"}},
+		{name: "missing required", path: "tables/a", metadata: Metadata{"format": "orc"}, wantErr: true},
+		{name: "allowed", path: "tables/a", metadata: Metadata{"owner": "data", "format": "parquet"}},
+		{name: "not allowed", path: "tables/a", metadata: Metadata{"owner": "data", "color": "blue"}, wantErr: true},
+		{name: "value mismatch", path: "tables/a", metadata: Metadata{"owner": "data", "format": "csv"}, wantErr: true},
+		{name: "partial value match", path: "tables/a", metadata: Metadata{"owner": "data", "format": "parquet2"}, wantErr: true},
+		{name: "all matching rules", path: "tables/sales/a", metadata: Metadata{"owner": "data"}, wantErr: true},
+		{name: "any key", path: "raw/a", metadata: Metadata{"color": "blue"}},
+		{name: "any key value mismatch", path: "raw/a", metadata: Metadata{"source": "S3"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Check(tt.path, tt.metadata)
+			if tt.wantErr && !errors.Is(err, ErrInvalidMetadata) {
+				t.Fatalf("Check() err=%v, expected %v", err, ErrInvalidMetadata)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Check() err=%v", err)
+			}
+		})
+	}
+}
+
+func TestMetadataSchema_Validate(t *testing.T) {
+	valid := &MetadataSchema{Rules: []MetadataRule{{Prefix: "a/", Required: []string{"k"}, Values: map[string]string{"k": "v+"}}}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() err=%v", err)
+	}
+	for _, schema := range []*MetadataSchema{
+		{Rules: []MetadataRule{{Required: []string{""}}}},
+		{Rules: []MetadataRule{{Values: map[string]string{"k": "(v"}}}},
+	} {
+		if err := schema.Validate(); !errors.Is(err, ErrInvalidValue) {
+			t.Fatalf("Validate() %+v err=%v, expected %v", schema, err, ErrInvalidValue)
+		}
+	}
+}
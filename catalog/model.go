@@ -9,6 +9,8 @@ import (
 const (
 	DBEntryFieldChecksum        = "checksum"
 	DBEntryFieldPhysicalAddress = "physical_address"
+	DBEntryFieldSize            = "size"
+	DBEntryFieldMetadata        = "metadata"
 )
 
 type Metadata map[string]string
@@ -18,6 +20,8 @@ type Repository struct {
 	StorageNamespace string    `db:"storage_namespace"`
 	DefaultBranch    string    `db:"default_branch"`
 	CreationDate     time.Time `db:"creation_date"`
+	ReadOnly         bool      `db:"read_only"`
+	Archived         bool      `db:"archived"`
 }
 
 type Entry struct {
@@ -29,6 +33,9 @@ type Entry struct {
 	Checksum        string    `db:"checksum"`
 	Metadata        Metadata  `db:"metadata"`
 	Expired         bool      `db:"is_expired"`
+	ContentType     string    `db:"content_type"`
+	ContentEncoding string    `db:"content_encoding"`
+	CacheControl    string    `db:"cache_control"`
 }
 
 type CommitLog struct {
@@ -40,14 +47,120 @@ type CommitLog struct {
 	Parents      []string
 }
 
+// CommitGraph is the commits reachable from a reference up to some depth, and the parent edges
+// between them
+type CommitGraph struct {
+	Commits []*CommitLog
+	Edges   []CommitGraphEdge
+}
+
+// CommitGraphEdge links the commit of reference Child to its parent commit of reference Parent.
+// Merge is set on edges to a parent on another branch, merged from or branched from.
+type CommitGraphEdge struct {
+	Child  string
+	Parent string
+	Merge  bool
+}
+
+// PrefixStats aggregates the objects under Prefix, including those under its sub-prefixes
+type PrefixStats struct {
+	Prefix    string `db:"prefix"`
+	Objects   int64  `db:"objects"`
+	SizeBytes int64  `db:"size_bytes"`
+}
+
+// EntryHistoryRecord is a change to the entry at a path made by a commit.  The physical
+// address, size and checksum are those of the object the commit set, empty for removals.
+type EntryHistoryRecord struct {
+	Commit          *CommitLog
+	Type            DifferenceType
+	PhysicalAddress string
+	Size            int64
+	Checksum        string
+}
+
+// MergeSquashSourceMetadataKey is the commit metadata key of a squash merge, holding the
+// reference of the merged source commit
+const MergeSquashSourceMetadataKey = "squash_source"
+
+// MergeStrategy decides the result of a merge for paths changed on both branches
+type MergeStrategy string
+
+const (
+	// MergeStrategyFail fails the merge with ErrConflictFound on any conflict
+	MergeStrategyFail MergeStrategy = "fail"
+	// MergeStrategySourceWins takes the source version of conflicting paths, discarding the
+	// destination changes, committed and uncommitted
+	MergeStrategySourceWins MergeStrategy = "source-wins"
+	// MergeStrategyDestWins keeps the destination version of conflicting paths
+	MergeStrategyDestWins MergeStrategy = "dest-wins"
+)
+
+// MergeResolveFunc merges the content of the source and destination entries of a path
+// changed on both branches of a merge, and returns the entry of the merged object.  It
+// returns a nil entry to leave the conflict to the merge strategy.
+type MergeResolveFunc func(source, destination *Entry) (*Entry, error)
+
+// MergeParams are the options of Merge.  A Squash merge commits the changes of the source
+// branch as a single commit that the destination log does not follow into the source commits;
+// the reference of the source commit is kept in its metadata under
+// MergeSquashSourceMetadataKey.  Resolve, when set, is called first on conflicts where both
+// branches hold an object; conflicts it leaves are resolved by Strategy.  An empty Strategy is
+// MergeStrategyFail.
+type MergeParams struct {
+	Squash   bool
+	Strategy MergeStrategy
+	Resolve  MergeResolveFunc
+}
+
 type MergeResult struct {
 	Summary   map[DifferenceType]int
 	Reference string
 }
 
+// MergeConflict is a path changed on both the source and the destination of a merge.
+// SourceType is the change the source would apply to the destination: added, removed or
+// changed.  DestinationType is removed when the path is deleted on the destination, and
+// changed otherwise.
+type MergeConflict struct {
+	Path            string
+	SourceType      DifferenceType
+	DestinationType DifferenceType
+}
+
+// MergePreview is the result a merge would have: the counts of its changes per type and the
+// conflicts failing it
+type MergePreview struct {
+	Summary   map[DifferenceType]int
+	Conflicts []*MergeConflict
+}
+
 type Branch struct {
 	Repository string `db:"repository"`
 	Name       string `db:"name"`
+	// Status is set only when requested from ListBranches
+	Status *BranchStatus `db:"-"`
+}
+
+// BranchStatus describes the head of a branch and how it relates to its source branch
+type BranchStatus struct {
+	HeadCommit *CommitLog
+	// SourceBranch is the branch this branch was created from, empty for the root branch
+	SourceBranch string
+	// CommitsAhead is the number of commits reachable from the branch and not from its source
+	CommitsAhead int
+	// CommitsBehind is the number of commits reachable from the source and not from the branch
+	CommitsBehind int
+	// UncommittedEntries is the number of paths changed on the branch since its last commit
+	UncommittedEntries int
+}
+
+// Tag is an immutable name of a commit, usable anywhere a reference is accepted
+type Tag struct {
+	Repository   string
+	Name         string
+	Reference    string
+	CreationDate time.Time
 }
 
 type MultipartUpload struct {
@@ -56,6 +169,10 @@ type MultipartUpload struct {
 	Path            string    `db:"path"`
 	CreationDate    time.Time `db:"creation_date"`
 	PhysicalAddress string    `db:"physical_address"`
+	ContentType     string    `db:"content_type"`
+	ContentEncoding string    `db:"content_encoding"`
+	CacheControl    string    `db:"cache_control"`
+	Metadata        Metadata  `db:"metadata"`
 }
 
 func (j Metadata) Value() (driver.Value, error) {
@@ -15,6 +15,8 @@ type Cache interface {
 	Repository(repository string, setFn GetRepositoryFn) (*catalog.Repository, error)
 	RepositoryID(repository string, setFn GetRepositoryIDFn) (int, error)
 	BranchID(repository string, branch string, setFn GetBranchIDFn) (int64, error)
+	// InvalidateBranchID removes the cached ID of branch, after it was renamed
+	InvalidateBranchID(repository string, branch string)
 }
 
 type LRUCache struct {
@@ -52,8 +54,12 @@ func (c *LRUCache) RepositoryID(repository string, setFn GetRepositoryIDFn) (int
 	return v.(int), nil
 }
 
+func branchIDKey(repository string, branch string) string {
+	return repository + "/" + branch
+}
+
 func (c *LRUCache) BranchID(repository string, branch string, setFn GetBranchIDFn) (int64, error) {
-	key := repository + "/" + branch
+	key := branchIDKey(repository, branch)
 	v, err := c.branchID.GetOrSet(key, func() (interface{}, error) {
 		return setFn(repository, branch)
 	})
@@ -63,6 +69,10 @@ func (c *LRUCache) BranchID(repository string, branch string, setFn GetBranchIDF
 	return v.(int64), nil
 }
 
+func (c *LRUCache) InvalidateBranchID(repository string, branch string) {
+	c.branchID.Remove(branchIDKey(repository, branch))
+}
+
 type DummyCache struct{}
 
 func (c *DummyCache) Repository(repository string, setFn GetRepositoryFn) (*catalog.Repository, error) {
@@ -77,6 +87,8 @@ func (c *DummyCache) BranchID(repository string, branch string, setFn GetBranchI
 	return setFn(repository, branch)
 }
 
+func (c *DummyCache) InvalidateBranchID(_ string, _ string) {}
+
 type CacheConfig struct {
 	Enabled bool
 	Size    int
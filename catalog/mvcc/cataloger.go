@@ -31,6 +31,7 @@ type cataloger struct {
 	dedupReportCh        chan *catalog.DedupReport
 	readEntryRequestChan chan *readRequest
 	hooks                catalog.CatalogerHooks
+	rateLimiters         *rateLimiters
 }
 
 const (
@@ -44,6 +45,9 @@ const (
 	defaultCatalogerCacheJitter = 5 * time.Second
 	MaxReadQueue                = 10
 
+	defaultListingCacheSize   = 10000
+	defaultListingCacheExpiry = time.Minute
+
 	defaultBatchReadEntryMaxWait  = 15 * time.Second
 	defaultBatchScanTimeout       = 500 * time.Microsecond
 	defaultBatchDelay             = 1000 * time.Microsecond
@@ -51,6 +55,8 @@ const (
 	defaultBatchReaders           = 8
 
 	defaultBatchWriteEntriesInsertSize = 10
+
+	defaultCommitJobChunkSize = 10000
 )
 
 type CatalogerOption func(*cataloger)
@@ -97,6 +103,18 @@ func WithParams(p params.Catalog) CatalogerOption {
 			c.Cache.Jitter = p.Cache.Jitter
 		}
 		c.Cache.Enabled = p.Cache.Enabled
+		if p.ListingCache.Size != 0 {
+			c.ListingCache.Size = p.ListingCache.Size
+		}
+		if p.ListingCache.Expiry != 0 {
+			c.ListingCache.Expiry = p.ListingCache.Expiry
+		}
+		c.ListingCache.Redis = p.ListingCache.Redis
+		c.ListingCache.Enabled = p.ListingCache.Enabled
+		if p.CommitJob.ChunkSize != 0 {
+			c.CommitJob.ChunkSize = p.CommitJob.ChunkSize
+		}
+		c.Committer.RecordAccessKeyID = p.Committer.RecordAccessKeyID
 	}
 }
 
@@ -106,6 +124,7 @@ func NewCataloger(db db.Database, options ...CatalogerOption) catalog.Cataloger
 		db:                 db,
 		dedupCh:            make(chan *dedupRequest, dedupChannelSize),
 		dedupReportEnabled: true,
+		rateLimiters:       newRateLimiters(),
 		Catalog: params.Catalog{
 			BatchRead: params.BatchRead{
 				EntryMaxWait:  defaultBatchReadEntryMaxWait,
@@ -123,6 +142,14 @@ func NewCataloger(db db.Database, options ...CatalogerOption) catalog.Cataloger
 				Expiry:  defaultCatalogerCacheExpiry,
 				Jitter:  defaultCatalogerCacheJitter,
 			},
+			ListingCache: params.ListingCache{
+				Enabled: false,
+				Size:    defaultListingCacheSize,
+				Expiry:  defaultListingCacheExpiry,
+			},
+			CommitJob: params.CommitJob{
+				ChunkSize: defaultCommitJobChunkSize,
+			},
 		},
 	}
 	for _, opt := range options {
@@ -138,6 +165,15 @@ func NewCataloger(db db.Database, options ...CatalogerOption) catalog.Cataloger
 	}
 	c.processDedupBatches()
 	c.startReadOrchestrator()
+	if c.ListingCache.Enabled {
+		var store ListingCacheStore
+		if c.ListingCache.Redis.Address != "" {
+			store = NewRedisListingCacheStore(c.ListingCache.Redis)
+		} else {
+			store = NewMemoryListingCacheStore(c.ListingCache.Size)
+		}
+		return newListingCacheCataloger(c, store, c.ListingCache.Expiry)
+	}
 	return c
 }
 
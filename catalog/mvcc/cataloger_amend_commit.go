@@ -0,0 +1,144 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) AmendCommit(ctx context.Context, repository, branch string, message string, metadata catalog.Metadata, params catalog.AmendCommitParams) (*catalog.CommitLog, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "message", IsValid: ValidateCommitMessage(message)},
+	}); err != nil {
+		return nil, err
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("get branch id: %w", err)
+		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+		lastCommitID, err := getLastCommitIDByBranchID(tx, branchID)
+		if err != nil {
+			return nil, fmt.Errorf("last commit id: %w", err)
+		}
+		previousCommitID, err := checkCommitAmendable(tx, branchID, lastCommitID)
+		if err != nil {
+			return nil, err
+		}
+
+		if params.IncludeChanges {
+			if !catalog.IsCommitLimitsExempt(ctx) {
+				repoID, err := c.getRepositoryIDCache(tx, repository)
+				if err != nil {
+					return nil, err
+				}
+				if err := checkCommitLimits(tx, repoID, branchID); err != nil {
+					return nil, err
+				}
+			}
+			if err := checkCommitContentUnreferenced(tx, branchID, lastCommitID); err != nil {
+				return nil, err
+			}
+			if err := releaseExportedCommits(tx, branchID, branch, lastCommitID-1, lastCommitID+1); err != nil {
+				return nil, err
+			}
+			if err := amendCommitEntries(tx, branchID, lastCommitID, previousCommitID); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := tx.Exec(`UPDATE catalog_commits SET message = $3, metadata = $4 WHERE branch_id = $1 AND commit_id = $2`,
+			branchID, lastCommitID, message, metadata); err != nil {
+			return nil, fmt.Errorf("update commit: %w", err)
+		}
+		var rawCommit commitLogRaw
+		if err := tx.Get(&rawCommit, `SELECT b.name as branch_name,c.commit_id,c.previous_commit_id,c.committer,c.message,c.creation_date,c.metadata
+			FROM catalog_commits c JOIN catalog_branches b ON b.id = c.branch_id
+			WHERE c.branch_id = $1 AND c.commit_id = $2`, branchID, lastCommitID); err != nil {
+			return nil, fmt.Errorf("get commit: %w", err)
+		}
+		return convertRawCommit(rawCommit), nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.CommitLog), nil
+}
+
+// checkCommitAmendable returns the previous commit of commitID on branchID, or an error if
+// commitID is not a regular commit or other refs already point to it
+func checkCommitAmendable(tx db.Tx, branchID int64, commitID CommitID) (CommitID, error) {
+	var commit struct {
+		MergeType        RelationType `db:"merge_type"`
+		PreviousCommitID CommitID     `db:"previous_commit_id"`
+		Referenced       bool         `db:"referenced"`
+		Tagged           bool         `db:"tagged"`
+	}
+	err := tx.Get(&commit, `SELECT merge_type, previous_commit_id,
+			EXISTS (SELECT 1 FROM catalog_commits WHERE merge_source_branch = $1 AND merge_source_commit >= $2) AS referenced,
+			EXISTS (SELECT 1 FROM catalog_tags WHERE branch_id = $1 AND commit_id = $2) AS tagged
+		FROM catalog_commits WHERE branch_id = $1 AND commit_id = $2`,
+		branchID, commitID)
+	if err != nil {
+		return 0, fmt.Errorf("get commit: %w", err)
+	}
+	switch {
+	case commit.MergeType != RelationTypeNone:
+		return 0, fmt.Errorf("%w: not a regular commit", catalog.ErrCommitNotAmendable)
+	case commit.Referenced:
+		return 0, fmt.Errorf("%w: already merged or branched", catalog.ErrCommitNotAmendable)
+	case commit.Tagged:
+		return 0, fmt.Errorf("%w: tagged", catalog.ErrCommitNotAmendable)
+	}
+	return commit.PreviousCommitID, nil
+}
+
+// checkCommitContentUnreferenced returns an error if data lineage records commitID of branchID,
+// whose entries an amend changes, as produced from or as a source of other commits
+func checkCommitContentUnreferenced(tx db.Tx, branchID int64, commitID CommitID) error {
+	var referenced bool
+	err := tx.GetPrimitive(&referenced, `SELECT EXISTS (SELECT 1 FROM catalog_data_lineage
+			WHERE (branch_id = $1 AND commit_id = $2) OR (source_branch_id = $1 AND source_commit_id = $2))`,
+		branchID, commitID)
+	if err != nil {
+		return fmt.Errorf("check data lineage: %w", err)
+	}
+	if referenced {
+		return fmt.Errorf("%w: recorded in data lineage", catalog.ErrCommitNotAmendable)
+	}
+	return nil
+}
+
+// amendCommitEntries commits the uncommitted entries of branchID into its last commit
+// commitID, as if they were committed with it after previousCommitID
+func amendCommitEntries(tx db.Tx, branchID int64, commitID, previousCommitID CommitID) error {
+	// entries and tombstones of the amended commit that uncommitted entries replace were never
+	// visible elsewhere
+	_, err := tx.Exec(`DELETE FROM catalog_entries_v WHERE branch_id = $1 AND min_commit = $2 AND max_commit IN ($3, $4)
+			AND path IN (SELECT path FROM catalog_entries_v WHERE branch_id = $1 AND NOT is_committed)`,
+		branchID, commitID, MaxCommitID, TombstoneCommitID)
+	if err != nil {
+		return fmt.Errorf("delete replaced entries: %w", err)
+	}
+	if _, err := commitUpdateCommittedEntriesWithMaxCommit(tx, branchID, previousCommitID, nil); err != nil {
+		return fmt.Errorf("update commit entries: %w", err)
+	}
+	if _, err := commitDeleteUncommittedTombstones(tx, branchID, previousCommitID, nil); err != nil {
+		return fmt.Errorf("delete uncommitted tombstones: %w", err)
+	}
+	if _, err := commitEntries(tx, branchID, commitID, nil); err != nil {
+		return fmt.Errorf("commit entries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_AmendCommit(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	commit, err := c.Commit(ctx, repository, "master", "typo", "tester", catalog.Metadata{"k": "v"}, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+
+	amended, err := c.AmendCommit(ctx, repository, "master", "fixed", catalog.Metadata{"k": "fixed"}, catalog.AmendCommitParams{})
+	testutil.MustDo(t, "amend message", err)
+	if amended.Reference != commit.Reference || amended.Message != "fixed" || amended.Metadata["k"] != "fixed" {
+		t.Errorf("amended commit %+v, expected message and metadata fixed on %s", amended, commit.Reference)
+	}
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "changed")
+	testutil.MustDo(t, "delete file2", c.DeleteEntry(ctx, repository, "master", "file2"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "")
+	_, err = c.AmendCommit(ctx, repository, "master", "fixed with changes", nil, catalog.AmendCommitParams{IncludeChanges: true})
+	testutil.MustDo(t, "amend with changes", err)
+	changes, _, err := c.DiffUncommitted(ctx, repository, "master", "", -1, "")
+	testutil.MustDo(t, "diff uncommitted", err)
+	if len(changes) != 0 {
+		t.Errorf("uncommitted changes after amend %v, expected none", changes)
+	}
+	file1, err := c.GetEntry(ctx, repository, commit.Reference, "file1", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get file1", err)
+	if expected := testCreateEntryCalcChecksum("file1", t.Name(), "changed"); file1.Checksum != expected {
+		t.Errorf("amended file1 checksum %s, expected %s", file1.Checksum, expected)
+	}
+	if _, err := c.GetEntry(ctx, repository, commit.Reference, "file2", catalog.GetEntryParams{}); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("get amended file2 err=%v, expected %s", err, db.ErrNotFound)
+	}
+	if _, err := c.GetEntry(ctx, repository, commit.Reference, "file3", catalog.GetEntryParams{}); err != nil {
+		t.Errorf("get amended file3: %s", err)
+	}
+
+	_, err = c.CreateBranch(ctx, repository, "branch1", "master")
+	testutil.MustDo(t, "create branch", err)
+	_, err = c.AmendCommit(ctx, repository, "master", "too late", nil, catalog.AmendCommitParams{})
+	if !errors.Is(err, catalog.ErrCommitNotAmendable) {
+		t.Errorf("amend branched commit err=%v, expected %s", err, catalog.ErrCommitNotAmendable)
+	}
+}
+
+func TestCataloger_AmendCommit_RecreatedEntry(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "add file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+
+	// deleting an entry of the lineage commits a tombstone
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testutil.MustDo(t, "delete file1", c.DeleteEntry(ctx, repository, "branch1", "file1"))
+	commit, err := c.Commit(ctx, repository, "branch1", "delete file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "recreated")
+	_, err = c.AmendCommit(ctx, repository, "branch1", "recreate file1", nil, catalog.AmendCommitParams{IncludeChanges: true})
+	testutil.MustDo(t, "amend with recreated entry", err)
+	file1, err := c.GetEntry(ctx, repository, commit.Reference, "file1", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get file1", err)
+	if expected := testCreateEntryCalcChecksum("file1", t.Name(), "recreated"); file1.Checksum != expected {
+		t.Errorf("amended file1 checksum %s, expected %s", file1.Checksum, expected)
+	}
+}
+
+func TestCataloger_AmendCommit_Referenced(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	commit, err := c.Commit(ctx, repository, "master", "add file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+
+	testutil.MustDo(t, "set export state", c.ExportStateSet(ctx, repository, "master",
+		func(string, catalog.CatalogBranchExportStatus) (string, catalog.CatalogBranchExportStatus, *string, error) {
+			return commit.Reference, catalog.ExportStatusSuccess, nil, nil
+		}))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	_, err = c.AmendCommit(ctx, repository, "master", "add files", nil, catalog.AmendCommitParams{IncludeChanges: true})
+	testutil.MustDo(t, "amend exported commit", err)
+	state, err := c.GetExportState(ctx, repository, "master")
+	testutil.MustDo(t, "get export state", err)
+	if state.CurrentRef != "" {
+		t.Errorf("export state ref %s after amending its changes, expected none", state.CurrentRef)
+	}
+
+	source := testCatalogerRepo(t, ctx, c, "source", "master")
+	testutil.MustDo(t, "create data lineage", c.CreateDataLineage(ctx, repository, commit.Reference,
+		[]catalog.DataLineageSource{{Repository: source, Reference: "master"}}))
+	_, err = c.AmendCommit(ctx, repository, "master", "message only", nil, catalog.AmendCommitParams{})
+	testutil.MustDo(t, "amend message of lineage commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "")
+	_, err = c.AmendCommit(ctx, repository, "master", "add more files", nil, catalog.AmendCommitParams{IncludeChanges: true})
+	if !errors.Is(err, catalog.ErrCommitNotAmendable) {
+		t.Errorf("amend changes of lineage commit err=%v, expected %s", err, catalog.ErrCommitNotAmendable)
+	}
+}
@@ -0,0 +1,114 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const ArchiveRepositoryBatchSize = 1000
+
+// sqlRepositoryObjects selects the objects referenced by the entries and the trash of
+// repository $1 after physical address $2
+const sqlRepositoryObjects = `SELECT a.physical_address
+	FROM (SELECT e.physical_address
+			FROM catalog_entries e JOIN catalog_branches b ON b.id = e.branch_id
+			WHERE b.repository_id = $1
+		UNION ALL
+		SELECT t.physical_address
+			FROM catalog_trash t JOIN catalog_branches b ON b.id = t.branch_id
+			WHERE b.repository_id = $1) a
+	WHERE a.physical_address COLLATE "C" > $2
+	GROUP BY a.physical_address
+	ORDER BY a.physical_address COLLATE "C"
+	LIMIT $3`
+
+func (c *cataloger) ArchiveRepository(ctx context.Context, repository string, params catalog.ArchiveRepositoryParams) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	// writes are rejected first, so no object is added while objects are transitioned
+	if err := c.setRepositoryArchived(ctx, repository, true); err != nil {
+		return err
+	}
+	return c.transitionRepositoryObjects(ctx, repository, params.TransitionObject)
+}
+
+func (c *cataloger) UnarchiveRepository(ctx context.Context, repository string, params catalog.ArchiveRepositoryParams) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	// the repository stays archived until all of its objects are transitioned back, so a
+	// failed restore can be run again
+	if err := c.transitionRepositoryObjects(ctx, repository, params.TransitionObject); err != nil {
+		return err
+	}
+	return c.setRepositoryArchived(ctx, repository, false)
+}
+
+func (c *cataloger) setRepositoryArchived(ctx context.Context, repository string, archived bool) error {
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		res, err := tx.Exec(`UPDATE catalog_repositories SET archived = $2 WHERE name = $1`, repository, archived)
+		if err != nil {
+			return nil, fmt.Errorf("set repository archived: %w", err)
+		}
+		if res.RowsAffected() == 0 {
+			return nil, catalog.ErrRepositoryNotFound
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+// transitionRepositoryObjects calls transition for each object referenced by repository, in
+// batches ordered by physical address
+func (c *cataloger) transitionRepositoryObjects(ctx context.Context, repository string, transition catalog.TransitionObjectFunc) error {
+	if transition == nil {
+		return nil
+	}
+	var (
+		storageNamespace string
+		repoID           int
+	)
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repo, err := c.getRepositoryCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		storageNamespace = repo.StorageNamespace
+		repoID, err = c.getRepositoryIDCache(tx, repository)
+		return nil, err
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return err
+	}
+	after := ""
+	for {
+		res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+			var addresses []string
+			if err := tx.Select(&addresses, sqlRepositoryObjects, repoID, after, ArchiveRepositoryBatchSize); err != nil {
+				return nil, fmt.Errorf("select objects: %w", err)
+			}
+			return addresses, nil
+		}, c.txOpts(ctx, db.ReadOnly())...)
+		if err != nil {
+			return err
+		}
+		addresses := res.([]string)
+		for _, address := range addresses {
+			if err := transition(storageNamespace, address); err != nil {
+				return fmt.Errorf("transition %s: %w", address, err)
+			}
+		}
+		if len(addresses) < ArchiveRepositoryBatchSize {
+			return nil
+		}
+		after = addresses[len(addresses)-1]
+	}
+}
@@ -0,0 +1,101 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ArchiveRepository(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	for _, path := range []string{"file1", "file2"} {
+		testutil.MustDo(t, "create entry "+path, c.CreateEntry(ctx, repository, "master", catalog.Entry{
+			Path:            path,
+			Checksum:        "ff",
+			PhysicalAddress: "addr_" + path,
+		}, catalog.CreateEntryParams{}))
+	}
+	_, err := c.Commit(ctx, repository, "master", "add files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	// the same object referenced twice is transitioned once
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testutil.MustDo(t, "create entry file3", c.CreateEntry(ctx, repository, "branch1", catalog.Entry{
+		Path:            "file3",
+		Checksum:        "ff",
+		PhysicalAddress: "addr_file1",
+	}, catalog.CreateEntryParams{}))
+
+	isListed := func(includeArchived bool) bool {
+		repos, _, err := c.ListRepositories(ctx, -1, "", catalog.ListRepositoriesParams{IncludeArchived: includeArchived})
+		testutil.MustDo(t, "list repositories", err)
+		for _, repo := range repos {
+			if repo.Name == repository {
+				return true
+			}
+		}
+		return false
+	}
+
+	var archived []string
+	testutil.MustDo(t, "archive", c.ArchiveRepository(ctx, repository, catalog.ArchiveRepositoryParams{
+		TransitionObject: func(namespace, address string) error {
+			if namespace != "s3://bucket" {
+				t.Errorf("transitioned %s of namespace %s, expected s3://bucket", address, namespace)
+			}
+			archived = append(archived, address)
+			return nil
+		},
+	}))
+	sort.Strings(archived)
+	if diff := deep.Equal(archived, []string{"addr_file1", "addr_file2"}); diff != nil {
+		t.Error("ArchiveRepository() transitioned objects diff", diff)
+	}
+	if isListed(false) {
+		t.Error("ListRepositories() lists archived repository")
+	}
+	if !isListed(true) {
+		t.Error("ListRepositories() with IncludeArchived does not list archived repository")
+	}
+	repo, err := c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if !repo.Archived {
+		t.Error("GetRepository() expected archived repository")
+	}
+	err = c.CreateEntry(ctx, repository, "master", catalog.Entry{Path: "file4", Checksum: "ff", PhysicalAddress: "addr_file4"}, catalog.CreateEntryParams{})
+	if !errors.Is(err, catalog.ErrRepositoryArchived) || !errors.Is(err, catalog.ErrRepositoryReadOnly) {
+		t.Errorf("CreateEntry() on archived repository err=%v, expected %s", err, catalog.ErrRepositoryArchived)
+	}
+	if _, err := c.GetEntry(ctx, repository, "master", "file1", catalog.GetEntryParams{}); err != nil {
+		t.Errorf("GetEntry() on archived repository err=%s, expected no error", err)
+	}
+
+	restored := 0
+	testutil.MustDo(t, "unarchive", c.UnarchiveRepository(ctx, repository, catalog.ArchiveRepositoryParams{
+		TransitionObject: func(_, _ string) error {
+			restored++
+			return nil
+		},
+	}))
+	if restored != len(archived) {
+		t.Errorf("UnarchiveRepository() transitioned %d objects, expected %d", restored, len(archived))
+	}
+	if !isListed(false) {
+		t.Error("ListRepositories() does not list restored repository")
+	}
+	testutil.MustDo(t, "create entry on restored repository", c.CreateEntry(ctx, repository, "master", catalog.Entry{
+		Path:            "file4",
+		Checksum:        "ff",
+		PhysicalAddress: "addr_file4",
+	}, catalog.CreateEntryParams{}))
+
+	if err := c.ArchiveRepository(ctx, "no-repo", catalog.ArchiveRepositoryParams{}); !errors.Is(err, catalog.ErrRepositoryNotFound) {
+		t.Errorf("ArchiveRepository() on missing repository err=%v, expected %s", err, catalog.ErrRepositoryNotFound)
+	}
+}
@@ -0,0 +1,111 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const cherrypickCommitMessageFormat = "%s\n\n(cherry picked from commit %s)"
+
+// Cherrypick applies the changes made by the commit of reference onto targetBranch, as a new
+// commit.  A path changed by the commit conflicts when targetBranch changed it differently
+// since the parent of the commit, or has uncommitted changes to it; on conflicts nothing is
+// applied and ErrConflictFound is returned.  Paths that targetBranch already changed the same
+// way are skipped.  ErrNoDifferenceWasFound is returned when no path remains to apply.
+func (c *cataloger) Cherrypick(ctx context.Context, repository, targetBranch, reference, committer string) (*catalog.MergeResult, error) {
+	committer, _ = c.commitAttribution(ctx, committer, nil)
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "targetBranch", IsValid: ValidateBranchName(targetBranch)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "committer", IsValid: ValidateCommitter(committer)},
+	}); err != nil {
+		return nil, err
+	}
+
+	mergeResult := &catalog.MergeResult{
+		Summary: make(map[catalog.DifferenceType]int),
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		targetID, err := getBranchID(tx, repository, targetBranch, LockTypeUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("target branch: %w", err)
+		}
+		if err := checkNoCommitJob(tx, targetID); err != nil {
+			return nil, fmt.Errorf("target branch: %w", err)
+		}
+		sourceID, commitID, err := c.resolveCommit(tx, repository, reference)
+		if err != nil {
+			return nil, fmt.Errorf("reference: %w", err)
+		}
+		var commit struct {
+			BranchName       string           `db:"branch_name"`
+			PreviousCommitID CommitID         `db:"previous_commit_id"`
+			Message          string           `db:"message"`
+			Metadata         catalog.Metadata `db:"metadata"`
+		}
+		err = tx.Get(&commit, `SELECT b.name AS branch_name, c.previous_commit_id, c.message, c.metadata
+			FROM catalog_commits c JOIN catalog_branches b ON b.id = c.branch_id
+			WHERE c.branch_id = $1 AND c.commit_id = $2`, sourceID, commitID)
+		if err != nil {
+			return nil, fmt.Errorf("get commit: %w", err)
+		}
+		if commit.PreviousCommitID <= 0 {
+			// the first commit of a branch has nothing to diff against
+			return nil, fmt.Errorf("cherry-pick commit without parent: %w", catalog.ErrOperationNotPermitted)
+		}
+
+		changes, err := commitChanges(tx, repository, sourceID, commit.PreviousCommitID, commitID)
+		if err != nil {
+			return nil, err
+		}
+		changes, err = checkChangesOnBranch(tx, targetID, changes, mergeResult)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 {
+			return nil, catalog.ErrNoDifferenceWasFound
+		}
+
+		previousMaxCommitID, err := getLastCommitIDByBranchID(tx, targetID)
+		if err != nil {
+			return nil, fmt.Errorf("last commit id: %w", err)
+		}
+		nextCommitID, err := getNextCommitID(tx)
+		if err != nil {
+			return nil, fmt.Errorf("next commit id: %w", err)
+		}
+		for i := 0; i < len(changes); i += MergeBatchSize {
+			end := i + MergeBatchSize
+			if end > len(changes) {
+				end = len(changes)
+			}
+			if err := applyChangesToBranch(tx, targetID, previousMaxCommitID, nextCommitID, changes[i:end]); err != nil {
+				return nil, err
+			}
+		}
+
+		sourceReference := MakeReference(commit.BranchName, commitID)
+		message := fmt.Sprintf(cherrypickCommitMessageFormat, commit.Message, sourceReference)
+		_, metadata := c.commitAttribution(ctx, committer, commit.Metadata)
+		var creationDate time.Time
+		if err := tx.GetPrimitive(&creationDate,
+			`INSERT INTO catalog_commits (branch_id,commit_id,committer,message,creation_date,metadata,merge_type,previous_commit_id)
+			VALUES ($1,$2,$3,$4,transaction_timestamp(),$5,$6,$7)
+			RETURNING creation_date`,
+			targetID, nextCommitID, committer, message, metadata, RelationTypeNone, previousMaxCommitID,
+		); err != nil {
+			return nil, fmt.Errorf("insert commit: %w", err)
+		}
+		mergeResult.Reference = MakeReference(targetBranch, nextCommitID)
+		return nil, nil
+	}, c.txOpts(ctx, db.ReadCommitted())...)
+	return mergeResult, err
+}
@@ -0,0 +1,88 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_Cherrypick(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	for i := 0; i < 4; i++ {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "file"+strconv.Itoa(i), nil, "")
+	}
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	// a commit on branch1 adding, removing and changing files
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file5", nil, "")
+	testutil.MustDo(t, "delete file1", c.DeleteEntry(ctx, repository, "branch1", "file1"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "seed1")
+	pickedLog, err := c.Commit(ctx, repository, "branch1", "change files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	// a later commit on branch1 that is not picked
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file6", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "add file6", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "second commit to branch1", err)
+
+	res, err := c.Cherrypick(ctx, repository, "master", pickedLog.Reference, "picker")
+	testutil.MustDo(t, "cherry-pick onto master", err)
+	expectedSummary := map[catalog.DifferenceType]int{
+		catalog.DifferenceTypeAdded:   1,
+		catalog.DifferenceTypeRemoved: 1,
+		catalog.DifferenceTypeChanged: 1,
+	}
+	for diffType, count := range expectedSummary {
+		if res.Summary[diffType] != count {
+			t.Errorf("cherry-pick summary %v, expected %v", res.Summary, expectedSummary)
+			break
+		}
+	}
+	commitLog, err := c.GetCommit(ctx, repository, res.Reference)
+	testutil.MustDo(t, "get cherry-pick commit", err)
+	if commitLog.Committer != "picker" {
+		t.Errorf("cherry-pick committed by %s, expected picker", commitLog.Committer)
+	}
+
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file5", true)
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file1", false)
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file6", false)
+	masterFile2, err := c.GetEntry(ctx, repository, "master:HEAD", "file2", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get file2 on master", err)
+	branchFile2, err := c.GetEntry(ctx, repository, pickedLog.Reference, "file2", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get picked file2", err)
+	if masterFile2.Checksum != branchFile2.Checksum {
+		t.Errorf("file2 on master has checksum %s, expected picked checksum %s", masterFile2.Checksum, branchFile2.Checksum)
+	}
+
+	t.Run("already applied", func(t *testing.T) {
+		_, err := c.Cherrypick(ctx, repository, "master", pickedLog.Reference, "picker")
+		if !errors.Is(err, catalog.ErrNoDifferenceWasFound) {
+			t.Errorf("cherry-pick again err=%v, expected %s", err, catalog.ErrNoDifferenceWasFound)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file3", nil, "seed2")
+		conflictLog, err := c.Commit(ctx, repository, "branch1", "change file3", "tester", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit file3 to branch1", err)
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "seed3")
+		_, err = c.Commit(ctx, repository, "master", "change file3", "tester", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit file3 to master", err)
+
+		res, err := c.Cherrypick(ctx, repository, "master", conflictLog.Reference, "picker")
+		if !errors.Is(err, catalog.ErrConflictFound) {
+			t.Fatalf("cherry-pick conflict err=%v, expected %s", err, catalog.ErrConflictFound)
+		}
+		if res.Summary[catalog.DifferenceTypeConflict] != 1 {
+			t.Errorf("cherry-pick conflict summary %v, expected a conflict", res.Summary)
+		}
+	})
+}
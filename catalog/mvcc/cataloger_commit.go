@@ -9,7 +9,8 @@ import (
 	"github.com/treeverse/lakefs/db"
 )
 
-func (c *cataloger) Commit(ctx context.Context, repository, branch string, message string, committer string, metadata catalog.Metadata) (*catalog.CommitLog, error) {
+func (c *cataloger) Commit(ctx context.Context, repository, branch string, message string, committer string, metadata catalog.Metadata, params catalog.CommitParams) (*catalog.CommitLog, error) {
+	committer, metadata = c.commitAttribution(ctx, committer, metadata)
 	if err := Validate(ValidateFields{
 		{Name: "branch", IsValid: ValidateBranchName(branch)},
 		{Name: "message", IsValid: ValidateCommitMessage(message)},
@@ -19,22 +20,48 @@ func (c *cataloger) Commit(ctx context.Context, repository, branch string, messa
 	}
 
 	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
 		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
 		if err != nil {
 			return nil, fmt.Errorf("get branch id: %w", err)
 		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+
+		if !catalog.IsCommitLimitsExempt(ctx) {
+			repoID, err := c.getRepositoryIDCache(tx, repository)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkCommitLimits(tx, repoID, branchID); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.validateCommit(ctx, tx, branchID, &catalog.CommitValidation{
+			Repository: repository,
+			Branch:     branch,
+			Committer:  committer,
+			Message:    message,
+			Metadata:   metadata,
+			Params:     params,
+		}); err != nil {
+			return nil, err
+		}
 
 		lastCommitID, err := getLastCommitIDByBranchID(tx, branchID)
 		if err != nil {
 			return nil, fmt.Errorf("last commit id: %w", err)
 		}
 
-		committedAffected, err := commitUpdateCommittedEntriesWithMaxCommit(tx, branchID, lastCommitID)
+		committedAffected, err := commitUpdateCommittedEntriesWithMaxCommit(tx, branchID, lastCommitID, params.Prefixes)
 		if err != nil {
 			return nil, fmt.Errorf("update commit entries: %w", err)
 		}
 
-		_, err = commitDeleteUncommittedTombstones(tx, branchID, lastCommitID)
+		_, err = commitDeleteUncommittedTombstones(tx, branchID, lastCommitID, params.Prefixes)
 		if err != nil {
 			return nil, fmt.Errorf("delete uncommitted tombstones: %w", err)
 		}
@@ -46,7 +73,7 @@ func (c *cataloger) Commit(ctx context.Context, repository, branch string, messa
 		}
 
 		// commit entries (include the tombstones)
-		affectedNew, err := commitEntries(tx, branchID, commitID)
+		affectedNew, err := commitEntries(tx, branchID, commitID, params.Prefixes)
 		if err != nil {
 			return nil, fmt.Errorf("commit entries: %w", err)
 		}
@@ -92,33 +119,51 @@ func (c *cataloger) Commit(ctx context.Context, repository, branch string, messa
 	return res.(*catalog.CommitLog), nil
 }
 
-func commitUpdateCommittedEntriesWithMaxCommit(tx db.Tx, branchID int64, commitID CommitID) (int64, error) {
+func commitUpdateCommittedEntriesWithMaxCommit(tx db.Tx, branchID int64, commitID CommitID, prefixes []string) (int64, error) {
+	prefixCondition, args := commitPrefixCondition(prefixes, []interface{}{branchID, commitID, MaxCommitID})
 	res, err := tx.Exec(`UPDATE catalog_entries_v SET max_commit = $2
 			WHERE branch_id = $1 AND is_committed
 				AND max_commit = $3
-				AND path in (SELECT path FROM catalog_entries_v WHERE branch_id = $1 AND NOT is_committed)`,
-		branchID, commitID, MaxCommitID)
+				AND path in (SELECT path FROM catalog_entries_v WHERE branch_id = $1 AND NOT is_committed`+prefixCondition+`)`,
+		args...)
 	if err != nil {
 		return 0, err
 	}
 	return res.RowsAffected(), nil
 }
 
-func commitDeleteUncommittedTombstones(tx db.Tx, branchID int64, commitID CommitID) (int64, error) {
-	res, err := tx.Exec(`DELETE FROM catalog_entries_v WHERE branch_id = $1 AND NOT is_committed AND is_tombstone AND path IN (
+func commitDeleteUncommittedTombstones(tx db.Tx, branchID int64, commitID CommitID, prefixes []string) (int64, error) {
+	prefixCondition, args := commitPrefixCondition(prefixes, []interface{}{branchID, commitID})
+	res, err := tx.Exec(`DELETE FROM catalog_entries_v WHERE branch_id = $1 AND NOT is_committed AND is_tombstone`+prefixCondition+` AND path IN (
 		SELECT path FROM catalog_entries_v WHERE branch_id = $1 AND is_committed AND max_commit = $2)`,
-		branchID, commitID)
+		args...)
 	if err != nil {
 		return 0, err
 	}
 	return res.RowsAffected(), nil
 }
 
-func commitEntries(tx db.Tx, branchID int64, commitID CommitID) (int64, error) {
-	res, err := tx.Exec(`UPDATE catalog_entries_v SET min_commit = $2 WHERE branch_id = $1 AND NOT is_committed`,
-		branchID, commitID)
+func commitEntries(tx db.Tx, branchID int64, commitID CommitID, prefixes []string) (int64, error) {
+	prefixCondition, args := commitPrefixCondition(prefixes, []interface{}{branchID, commitID})
+	res, err := tx.Exec(`UPDATE catalog_entries_v SET min_commit = $2 WHERE branch_id = $1 AND NOT is_committed`+prefixCondition,
+		args...)
 	if err != nil {
 		return 0, err
 	}
 	return res.RowsAffected(), nil
 }
+
+// commitPrefixCondition returns a condition limiting the paths of uncommitted entries to
+// prefixes, to add to a WHERE clause with args, and args with its value appended.  No
+// prefixes does not limit the paths.
+func commitPrefixCondition(prefixes []string, args []interface{}) (string, []interface{}) {
+	if len(prefixes) == 0 {
+		return "", args
+	}
+	patterns := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		patterns[i] = db.Prefix(prefix)
+	}
+	args = append(args, patterns)
+	return fmt.Sprintf(" AND path LIKE ANY($%d::text[])", len(args)), args
+}
@@ -0,0 +1,408 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// commitJob is a commit job with the commit it creates
+type commitJob struct {
+	catalog.CommitJob
+	BranchID         int64    `db:"branch_id"`
+	CommitID         CommitID `db:"commit_id"`
+	PreviousCommitID CommitID `db:"previous_commit_id"`
+}
+
+func (j *commitJob) job() *catalog.CommitJob {
+	job := j.CommitJob
+	if job.Status == catalog.CommitJobStatusCompleted {
+		job.Reference = MakeReference(job.Branch, j.CommitID)
+	}
+	return &job
+}
+
+func selectCommitJobs() sq.SelectBuilder {
+	return psql.Select("j.id", "r.name AS repository", "b.name AS branch", "j.committer", "j.message", "j.metadata",
+		"j.status", "j.after_path", "j.committed_entries", "j.error", "j.creation_date", "j.update_date",
+		"j.branch_id", "j.commit_id", "j.previous_commit_id").
+		From("catalog_commit_jobs j").
+		Join("catalog_branches b ON b.id = j.branch_id").
+		Join("catalog_repositories r ON r.id = b.repository_id")
+}
+
+func getCommitJob(tx db.Tx, repository string, id int64, lock bool) (*commitJob, error) {
+	q := selectCommitJobs().Where(sq.Eq{"r.name": repository, "j.id": id})
+	if lock {
+		q = q.Suffix("FOR UPDATE OF j")
+	}
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql: %w", err)
+	}
+	var job commitJob
+	err = tx.Get(&job, query, args...)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, catalog.ErrCommitJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+var errCommitJobAborted = errors.New("aborted")
+
+// checkNoCommitJob fails with ErrCommitJobInProgress when branchID has a running commit job
+func checkNoCommitJob(tx db.Tx, branchID int64) error {
+	var inProgress bool
+	err := tx.GetPrimitive(&inProgress, `SELECT EXISTS (SELECT 1 FROM catalog_commit_jobs WHERE branch_id = $1 AND status = $2)`,
+		branchID, catalog.CommitJobStatusRunning)
+	if err != nil {
+		return fmt.Errorf("check commit jobs: %w", err)
+	}
+	if inProgress {
+		return catalog.ErrCommitJobInProgress
+	}
+	return nil
+}
+
+func (c *cataloger) CreateCommitJob(ctx context.Context, repository, branch string, message string, committer string, metadata catalog.Metadata) (*catalog.CommitJob, error) {
+	committer, metadata = c.commitAttribution(ctx, committer, metadata)
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "message", IsValid: ValidateCommitMessage(message)},
+		{Name: "committer", IsValid: ValidateCommitter(committer)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("get branch id: %w", err)
+		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+		if !catalog.IsCommitLimitsExempt(ctx) {
+			repoID, err := c.getRepositoryIDCache(tx, repository)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkCommitLimits(tx, repoID, branchID); err != nil {
+				return nil, err
+			}
+		}
+		var hasUncommitted bool
+		err = tx.GetPrimitive(&hasUncommitted, `SELECT EXISTS (SELECT 1 FROM catalog_entries_v WHERE branch_id = $1 AND NOT is_committed)`, branchID)
+		if err != nil {
+			return nil, fmt.Errorf("uncommitted entries: %w", err)
+		}
+		if !hasUncommitted {
+			return nil, catalog.ErrNothingToCommit
+		}
+		if err := c.validateCommit(ctx, tx, branchID, &catalog.CommitValidation{
+			Repository: repository,
+			Branch:     branch,
+			Committer:  committer,
+			Message:    message,
+			Metadata:   metadata,
+		}); err != nil {
+			return nil, err
+		}
+		lastCommitID, err := getLastCommitIDByBranchID(tx, branchID)
+		if err != nil {
+			return nil, fmt.Errorf("last commit id: %w", err)
+		}
+		// the commit ID is reserved now, so the entries of every chunk are committed with it
+		commitID, err := getNextCommitID(tx)
+		if err != nil {
+			return nil, fmt.Errorf("next commit id: %w", err)
+		}
+		var id int64
+		err = tx.GetPrimitive(&id, `INSERT INTO catalog_commit_jobs (branch_id, commit_id, previous_commit_id, committer, message, metadata, status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id`,
+			branchID, commitID, lastCommitID, committer, message, metadata, catalog.CommitJobStatusRunning)
+		if err != nil {
+			return nil, fmt.Errorf("insert commit job: %w", err)
+		}
+		return getCommitJob(tx, repository, id, false)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*commitJob).job(), nil
+}
+
+func (c *cataloger) RunCommitJob(ctx context.Context, repository string, id int64) (*catalog.CommitJob, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	for {
+		res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+			if err := checkRepositoryWritable(tx, repository); err != nil {
+				return nil, err
+			}
+			return c.commitJobChunk(ctx, tx, repository, id)
+		}, c.txOpts(ctx)...)
+		if err != nil {
+			if errors.Is(err, catalog.ErrCommitJobNotFound) || errors.Is(err, catalog.ErrCommitJobNotRunning) {
+				return nil, err
+			}
+			return c.failCommitJob(ctx, repository, id, err)
+		}
+		job := res.(*commitJob)
+		if job.Status == catalog.CommitJobStatusCompleted {
+			return job.job(), nil
+		}
+	}
+}
+
+func (c *cataloger) AbortCommitJob(ctx context.Context, repository string, id int64) (*catalog.CommitJob, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		job, err := getCommitJob(tx, repository, id, true)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status != catalog.CommitJobStatusRunning {
+			return nil, catalog.ErrCommitJobNotRunning
+		}
+		if err := rollbackCommitJob(tx, id, errCommitJobAborted); err != nil {
+			return nil, err
+		}
+		return getCommitJob(tx, repository, id, false)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*commitJob).job(), nil
+}
+
+// failCommitJob rolls back job id with jobErr as its error, and returns the job with jobErr
+func (c *cataloger) failCommitJob(ctx context.Context, repository string, id int64, jobErr error) (*catalog.CommitJob, error) {
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := rollbackCommitJob(tx, id, jobErr); err != nil {
+			return nil, err
+		}
+		return getCommitJob(tx, repository, id, false)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		c.log.WithContext(ctx).WithError(err).WithField("commit_job", id).Warn("failed to record commit job failure")
+		return nil, jobErr
+	}
+	return res.(*commitJob).job(), jobErr
+}
+
+// rollbackCommitJob drops the staged chunks of the running job id and marks it failed with
+// jobErr.  Staged chunks are not visible, so the branch is left as it was before the job.
+func rollbackCommitJob(tx db.Tx, id int64, jobErr error) error {
+	res, err := tx.Exec(`UPDATE catalog_commit_jobs SET status = $2, error = $3, update_date = now()
+			WHERE id = $1 AND status = $4`,
+		id, catalog.CommitJobStatusFailed, jobErr.Error(), catalog.CommitJobStatusRunning)
+	if err != nil {
+		return fmt.Errorf("update commit job: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return nil
+	}
+	_, err = tx.Exec(`DELETE FROM catalog_commit_job_entries WHERE job_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete staged entries: %w", err)
+	}
+	return nil
+}
+
+// commitJobChunk stages the next chunk of uncommitted entries of job id, or completes the job
+// when no uncommitted entries are left after the job's last path.
+func (c *cataloger) commitJobChunk(ctx context.Context, tx db.Tx, repository string, id int64) (*commitJob, error) {
+	job, err := getCommitJob(tx, repository, id, true)
+	if err != nil {
+		return nil, err
+	}
+	switch job.Status {
+	case catalog.CommitJobStatusCompleted:
+		return job, nil
+	case catalog.CommitJobStatusRunning:
+	default:
+		return nil, catalog.ErrCommitJobNotRunning
+	}
+	var chunk struct {
+		Staged int64  `db:"staged"`
+		UpTo   string `db:"up_to"`
+	}
+	err = tx.Get(&chunk, `WITH staged AS (
+			INSERT INTO catalog_commit_job_entries (job_id, path)
+			SELECT $1, path FROM catalog_entries_v WHERE branch_id = $2 AND NOT is_committed AND path > $3
+			ORDER BY path LIMIT $4
+			RETURNING path)
+		SELECT COUNT(*) AS staged, COALESCE(MAX(path), '') AS up_to FROM staged`,
+		job.ID, job.BranchID, job.After, c.CommitJob.ChunkSize)
+	if err != nil {
+		return nil, fmt.Errorf("stage chunk: %w", err)
+	}
+	if chunk.Staged == 0 {
+		return c.completeCommitJob(ctx, tx, job)
+	}
+	job.After = chunk.UpTo
+	job.CommittedEntries += chunk.Staged
+	_, err = tx.Exec(`UPDATE catalog_commit_jobs SET after_path = $2, committed_entries = $3, update_date = now()
+			WHERE id = $1`,
+		job.ID, job.After, job.CommittedEntries)
+	if err != nil {
+		return nil, fmt.Errorf("update commit job: %w", err)
+	}
+	return job, nil
+}
+
+// completeCommitJob commits the staged entries of job and creates its commit, all in one
+// transaction.  The entries are committed the same way Commit commits all the entries of a
+// branch.
+func (c *cataloger) completeCommitJob(ctx context.Context, tx db.Tx, job *commitJob) (*commitJob, error) {
+	if job.CommittedEntries == 0 {
+		return nil, catalog.ErrNothingToCommit
+	}
+	lastCommitID, err := getLastCommitIDByBranchID(tx, job.BranchID)
+	if err != nil {
+		return nil, fmt.Errorf("last commit id: %w", err)
+	}
+	if lastCommitID != job.PreviousCommitID {
+		return nil, fmt.Errorf("%w: branch was committed during the commit job", catalog.ErrUnexpected)
+	}
+	// only the staged paths are committed, writes to the branch were rejected while the job ran
+	_, err = tx.Exec(`UPDATE catalog_entries_v SET max_commit = $2
+			WHERE branch_id = $1 AND is_committed AND max_commit = $3
+				AND path IN (SELECT path FROM catalog_commit_job_entries WHERE job_id = $4)`,
+		job.BranchID, job.PreviousCommitID, MaxCommitID, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("update commit entries: %w", err)
+	}
+	_, err = tx.Exec(`DELETE FROM catalog_entries_v WHERE branch_id = $1 AND NOT is_committed AND is_tombstone
+			AND path IN (SELECT path FROM catalog_entries_v WHERE branch_id = $1 AND is_committed AND max_commit = $2)
+			AND path IN (SELECT path FROM catalog_commit_job_entries WHERE job_id = $3)`,
+		job.BranchID, job.PreviousCommitID, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("delete uncommitted tombstones: %w", err)
+	}
+	_, err = tx.Exec(`UPDATE catalog_entries_v SET min_commit = $2
+			WHERE branch_id = $1 AND NOT is_committed
+				AND path IN (SELECT path FROM catalog_commit_job_entries WHERE job_id = $3)`,
+		job.BranchID, job.CommitID, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("commit entries: %w", err)
+	}
+	var creationDate time.Time
+	if err = tx.GetPrimitive(&creationDate,
+		`INSERT INTO catalog_commits (branch_id,commit_id,committer,message,creation_date,metadata,merge_type,previous_commit_id)
+			VALUES ($1,$2,$3,$4,transaction_timestamp(),$5,$6,$7)
+			RETURNING creation_date`,
+		job.BranchID, job.CommitID, job.Committer, job.Message, job.Metadata, RelationTypeNone, job.PreviousCommitID,
+	); err != nil {
+		return nil, err
+	}
+	commitLog := &catalog.CommitLog{
+		Committer:    job.Committer,
+		Message:      job.Message,
+		CreationDate: creationDate,
+		Metadata:     job.Metadata,
+		Reference:    MakeReference(job.Branch, job.CommitID),
+		Parents:      []string{MakeReference(job.Branch, job.PreviousCommitID)},
+	}
+	for _, hook := range c.hooks.PostCommit {
+		if err := hook(ctx, tx, commitLog); err != nil {
+			return nil, err
+		}
+	}
+	job.Status = catalog.CommitJobStatusCompleted
+	job.Error = ""
+	_, err = tx.Exec(`UPDATE catalog_commit_jobs SET status = $2, error = '', update_date = now() WHERE id = $1`,
+		job.ID, job.Status)
+	if err != nil {
+		return nil, fmt.Errorf("update commit job: %w", err)
+	}
+	_, err = tx.Exec(`DELETE FROM catalog_commit_job_entries WHERE job_id = $1`, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("delete staged entries: %w", err)
+	}
+	return job, nil
+}
+
+func (c *cataloger) GetCommitJob(ctx context.Context, repository string, id int64) (*catalog.CommitJob, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		return getCommitJob(tx, repository, id, false)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*commitJob).job(), nil
+}
+
+func (c *cataloger) ListCommitJobs(ctx context.Context, repository string, params catalog.ListCommitJobsParams) ([]*catalog.CommitJob, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, false, err
+	}
+	limit := params.Limit
+	if limit <= 0 || limit > catalog.MaxListCommitJobsAmount {
+		limit = catalog.DefaultListCommitJobsAmount
+	}
+	q := selectCommitJobs().
+		Where(sq.Eq{"r.name": repository}).
+		OrderBy("j.id DESC").
+		Limit(uint64(limit) + 1)
+	if params.After > 0 {
+		q = q.Where(sq.Lt{"j.id": params.After})
+	}
+	if params.Branch != "" {
+		q = q.Where(sq.Eq{"b.name": params.Branch})
+	}
+	if params.Status != "" {
+		q = q.Where(sq.Eq{"j.status": params.Status})
+	}
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("build sql: %w", err)
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var jobs []*commitJob
+		if err := tx.Select(&jobs, query, args...); err != nil {
+			return nil, err
+		}
+		return jobs, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	jobs := res.([]*commitJob)
+	hasMore := len(jobs) > limit
+	if hasMore {
+		jobs = jobs[:limit]
+	}
+	result := make([]*catalog.CommitJob, len(jobs))
+	for i, job := range jobs {
+		result[i] = job.job()
+	}
+	return result, hasMore, nil
+}
@@ -0,0 +1,161 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/catalog/mvcc/params"
+	"github.com/treeverse/lakefs/db"
+)
+
+var errTestPostCommit = errors.New("post commit failed")
+
+func TestCataloger_CommitJob(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t, WithParams(params.Catalog{CommitJob: params.CommitJob{ChunkSize: 2}}))
+	failPostCommit := false
+	c.Hooks().AddPostCommit(func(_ context.Context, _ db.Tx, _ *catalog.CommitLog) error {
+		if failPostCommit {
+			return errTestPostCommit
+		}
+		return nil
+	})
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	for _, p := range []string{"a", "b", "c"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", p, nil, "")
+	}
+	firstCommit, err := c.Commit(ctx, repository, "master", "first", "tester", nil, catalog.CommitParams{})
+	if err != nil {
+		t.Fatal("Commit()", err)
+	}
+
+	_, err = c.CreateCommitJob(ctx, repository, "master", "nothing", "tester", nil)
+	if !errors.Is(err, catalog.ErrNothingToCommit) {
+		t.Fatalf("CreateCommitJob() without changes err=%v, expected=%s", err, catalog.ErrNothingToCommit)
+	}
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a", nil, "v2")
+	if err := c.DeleteEntry(ctx, repository, "master", "b"); err != nil {
+		t.Fatal("DeleteEntry()", err)
+	}
+	for _, p := range []string{"d", "e", "f"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", p, nil, "")
+	}
+	job, err := c.CreateCommitJob(ctx, repository, "master", "chunked", "tester", catalog.Metadata{"k": "v"})
+	if err != nil {
+		t.Fatal("CreateCommitJob()", err)
+	}
+	if job.Status != catalog.CommitJobStatusRunning || job.Branch != "master" || job.Reference != "" {
+		t.Fatalf("CreateCommitJob() got %+v", job)
+	}
+	_, err = c.CreateCommitJob(ctx, repository, "master", "again", "tester", nil)
+	if !errors.Is(err, catalog.ErrCommitJobInProgress) {
+		t.Fatalf("CreateCommitJob() with a job in progress err=%v, expected=%s", err, catalog.ErrCommitJobInProgress)
+	}
+	_, err = c.Commit(ctx, repository, "master", "commit", "tester", nil, catalog.CommitParams{})
+	if !errors.Is(err, catalog.ErrCommitJobInProgress) {
+		t.Fatalf("Commit() with a job in progress err=%v, expected=%s", err, catalog.ErrCommitJobInProgress)
+	}
+
+	err = c.CreateEntry(ctx, repository, "master", catalog.Entry{Path: "g", PhysicalAddress: "g", Checksum: "g"}, catalog.CreateEntryParams{})
+	if !errors.Is(err, catalog.ErrCommitJobInProgress) {
+		t.Fatalf("CreateEntry() with a job in progress err=%v, expected=%s", err, catalog.ErrCommitJobInProgress)
+	}
+
+	// a staged chunk is not visible, not even to committed reads of the branch
+	mvccCataloger := c.Cataloger.(*cataloger)
+	staged, err := mvccCataloger.db.Transact(func(tx db.Tx) (interface{}, error) {
+		return mvccCataloger.commitJobChunk(ctx, tx, repository, job.ID)
+	})
+	if err != nil {
+		t.Fatal("commitJobChunk()", err)
+	}
+	if stagedJob := staged.(*commitJob); stagedJob.CommittedEntries != 2 || stagedJob.After != "b" {
+		t.Fatalf("commitJobChunk() got %+v", stagedJob.CommitJob)
+	}
+	testVerifyEntries(t, ctx, c, repository, "master:HEAD", []testEntryInfo{
+		{Path: "a"}, {Path: "b"}, {Path: "c"}, {Path: "d", Deleted: true},
+	})
+
+	// all chunks are staged, creating the commit fails and the job is rolled back
+	failPostCommit = true
+	failed, err := c.RunCommitJob(ctx, repository, job.ID)
+	if !errors.Is(err, errTestPostCommit) {
+		t.Fatalf("RunCommitJob() err=%v, expected=%s", err, errTestPostCommit)
+	}
+	if failed.Status != catalog.CommitJobStatusFailed || failed.CommittedEntries != 5 || failed.After != "f" || failed.Error == "" {
+		t.Fatalf("RunCommitJob() failed job got %+v", failed)
+	}
+	// the branch and its last commit are unchanged by the job
+	testVerifyEntries(t, ctx, c, repository, "master", []testEntryInfo{
+		{Path: "a", Seed: "v2"}, {Path: "b", Deleted: true}, {Path: "c"}, {Path: "d"}, {Path: "e"}, {Path: "f"},
+	})
+	testVerifyEntries(t, ctx, c, repository, "master:HEAD", []testEntryInfo{
+		{Path: "a"}, {Path: "b"}, {Path: "c"}, {Path: "d", Deleted: true},
+	})
+	testVerifyEntries(t, ctx, c, repository, firstCommit.Reference, []testEntryInfo{
+		{Path: "a"}, {Path: "b"}, {Path: "c"}, {Path: "d", Deleted: true},
+	})
+	_, err = c.RunCommitJob(ctx, repository, job.ID)
+	if !errors.Is(err, catalog.ErrCommitJobNotRunning) {
+		t.Fatalf("RunCommitJob() of a failed job err=%v, expected=%s", err, catalog.ErrCommitJobNotRunning)
+	}
+
+	// a failed job releases its branch
+	failPostCommit = false
+	aborted, err := c.CreateCommitJob(ctx, repository, "master", "aborted", "tester", nil)
+	if err != nil {
+		t.Fatal("CreateCommitJob() after a failed job", err)
+	}
+	aborted, err = c.AbortCommitJob(ctx, repository, aborted.ID)
+	if err != nil {
+		t.Fatal("AbortCommitJob()", err)
+	}
+	if aborted.Status != catalog.CommitJobStatusFailed || aborted.Error == "" {
+		t.Fatalf("AbortCommitJob() got %+v", aborted)
+	}
+	_, err = c.AbortCommitJob(ctx, repository, aborted.ID)
+	if !errors.Is(err, catalog.ErrCommitJobNotRunning) {
+		t.Fatalf("AbortCommitJob() of an aborted job err=%v, expected=%s", err, catalog.ErrCommitJobNotRunning)
+	}
+
+	job, err = c.CreateCommitJob(ctx, repository, "master", "chunked", "tester", catalog.Metadata{"k": "v"})
+	if err != nil {
+		t.Fatal("CreateCommitJob() after an aborted job", err)
+	}
+	completed, err := c.RunCommitJob(ctx, repository, job.ID)
+	if err != nil {
+		t.Fatal("RunCommitJob()", err)
+	}
+	if completed.Status != catalog.CommitJobStatusCompleted || completed.Reference == "" || completed.Error != "" || completed.CommittedEntries != 5 {
+		t.Fatalf("RunCommitJob() completed job got %+v", completed)
+	}
+	commit, err := c.GetCommit(ctx, repository, completed.Reference)
+	if err != nil {
+		t.Fatal("GetCommit()", err)
+	}
+	if commit.Message != "chunked" || commit.Metadata["k"] != "v" || len(commit.Parents) != 1 || commit.Parents[0] != firstCommit.Reference {
+		t.Fatalf("GetCommit() of job got %+v", commit)
+	}
+	testVerifyEntries(t, ctx, c, repository, completed.Reference, []testEntryInfo{
+		{Path: "a", Seed: "v2"}, {Path: "b", Deleted: true}, {Path: "c"}, {Path: "d"}, {Path: "e"}, {Path: "f"},
+	})
+	_, err = c.Commit(ctx, repository, "master", "after job", "tester", nil, catalog.CommitParams{})
+	if !errors.Is(err, catalog.ErrNothingToCommit) {
+		t.Fatalf("Commit() after job err=%v, expected=%s", err, catalog.ErrNothingToCommit)
+	}
+
+	jobs, hasMore, err := c.ListCommitJobs(ctx, repository, catalog.ListCommitJobsParams{Status: catalog.CommitJobStatusCompleted})
+	if err != nil {
+		t.Fatal("ListCommitJobs()", err)
+	}
+	if hasMore || len(jobs) != 1 || jobs[0].ID != job.ID || jobs[0].Reference != completed.Reference {
+		t.Fatalf("ListCommitJobs() got %+v (has more %t)", jobs, hasMore)
+	}
+	_, err = c.GetCommitJob(ctx, repository, job.ID+1)
+	if !errors.Is(err, catalog.ErrCommitJobNotFound) {
+		t.Fatalf("GetCommitJob() unknown job err=%v, expected=%s", err, catalog.ErrCommitJobNotFound)
+	}
+}
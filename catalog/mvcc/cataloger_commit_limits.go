@@ -0,0 +1,122 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) GetCommitLimits(ctx context.Context, repository string) (*catalog.CommitLimits, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		return getCommitLimits(tx, repoID)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.CommitLimits), nil
+}
+
+func (c *cataloger) SetCommitLimits(ctx context.Context, repository string, limits *catalog.CommitLimits) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	if limits == nil || limits.MaxChangedObjects < 0 || limits.MaxAddedBytes < 0 {
+		return fmt.Errorf("commit limits: %w", catalog.ErrInvalidValue)
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`INSERT INTO catalog_repositories_commit_limits (repository_id, max_changed_objects, max_added_bytes)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (repository_id)
+			DO UPDATE SET (max_changed_objects, max_added_bytes) = (EXCLUDED.max_changed_objects, EXCLUDED.max_added_bytes)`,
+			repoID, limits.MaxChangedObjects, limits.MaxAddedBytes)
+		if err != nil {
+			return nil, fmt.Errorf("set commit limits: %w", err)
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func getCommitLimits(tx db.Tx, repositoryID int) (*catalog.CommitLimits, error) {
+	var limits catalog.CommitLimits
+	err := tx.Get(&limits, `SELECT max_changed_objects, max_added_bytes FROM catalog_repositories_commit_limits WHERE repository_id=$1`, repositoryID)
+	if errors.Is(err, db.ErrNotFound) {
+		return &limits, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get commit limits: %w", err)
+	}
+	return &limits, nil
+}
+
+func getUncommittedSize(tx db.Tx, branchID int64) (*catalog.CommitSize, error) {
+	var size catalog.CommitSize
+	err := tx.Get(&size, `SELECT COUNT(*) AS changed_objects,
+			COALESCE(SUM(CASE WHEN is_tombstone THEN 0 ELSE size END),0) AS added_bytes
+		FROM catalog_entries_v WHERE branch_id = $1 AND NOT is_committed`, branchID)
+	if err != nil {
+		return nil, fmt.Errorf("get uncommitted size: %w", err)
+	}
+	return &size, nil
+}
+
+// checkCommitLimits verifies that committing the uncommitted changes of branch keeps within the
+// commit limits of repository.  Branches of repositories without limits are not scanned.
+func checkCommitLimits(tx db.Tx, repositoryID int, branchID int64) error {
+	return checkCommitSize(tx, repositoryID, func() (*catalog.CommitSize, error) {
+		return getUncommittedSize(tx, branchID)
+	})
+}
+
+// checkMergeCommitLimits verifies that the changedObjects differences merged into branch by the
+// merge commit commitID keep within the commit limits of repository
+func checkMergeCommitLimits(tx db.Tx, repositoryID int, branchID int64, commitID CommitID, changedObjects int) error {
+	return checkCommitSize(tx, repositoryID, func() (*catalog.CommitSize, error) {
+		size := catalog.CommitSize{ChangedObjects: int64(changedObjects)}
+		err := tx.GetPrimitive(&size.AddedBytes, `SELECT COALESCE(SUM(size),0) FROM catalog_entries
+			WHERE branch_id = $1 AND min_commit = $2`, branchID, commitID)
+		if err != nil {
+			return nil, fmt.Errorf("get merged size: %w", err)
+		}
+		return &size, nil
+	})
+}
+
+// checkCommitSize verifies that the commit size returned by getSize keeps within the commit
+// limits of repository, getSize is not called for repositories without limits
+func checkCommitSize(tx db.Tx, repositoryID int, getSize func() (*catalog.CommitSize, error)) error {
+	limits, err := getCommitLimits(tx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if limits.MaxChangedObjects == 0 && limits.MaxAddedBytes == 0 {
+		return nil
+	}
+	size, err := getSize()
+	if err != nil {
+		return err
+	}
+	if limits.Exceeds(size) {
+		return fmt.Errorf("%w: %d changed objects and %d added bytes, limits are %d objects and %d bytes",
+			catalog.ErrCommitLimitExceeded, size.ChangedObjects, size.AddedBytes, limits.MaxChangedObjects, limits.MaxAddedBytes)
+	}
+	return nil
+}
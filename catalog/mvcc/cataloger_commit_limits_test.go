@@ -0,0 +1,105 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+)
+
+func TestCataloger_CommitLimits(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	limits, err := c.GetCommitLimits(ctx, repository)
+	if err != nil {
+		t.Fatal("GetCommitLimits() on new repository", err)
+	}
+	if diff := deep.Equal(limits, &catalog.CommitLimits{}); diff != nil {
+		t.Fatal("GetCommitLimits() expected no limits", diff)
+	}
+
+	err = c.SetCommitLimits(ctx, repository, &catalog.CommitLimits{MaxAddedBytes: -1})
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Fatalf("SetCommitLimits() negative value err=%s, expected=%s", err, catalog.ErrInvalidValue)
+	}
+
+	expected := &catalog.CommitLimits{MaxChangedObjects: 2, MaxAddedBytes: 100}
+	if err := c.SetCommitLimits(ctx, repository, expected); err != nil {
+		t.Fatal("SetCommitLimits()", err)
+	}
+	limits, err = c.GetCommitLimits(ctx, repository)
+	if err != nil {
+		t.Fatal("GetCommitLimits()", err)
+	}
+	if diff := deep.Equal(limits, expected); diff != nil {
+		t.Fatal("GetCommitLimits() unexpected limits", diff)
+	}
+
+	_, err = c.GetCommitLimits(ctx, "no-repository")
+	if !errors.Is(err, catalog.ErrRepositoryNotFound) {
+		t.Fatalf("GetCommitLimits() unknown repository err=%s, expected=%s", err, catalog.ErrRepositoryNotFound)
+	}
+}
+
+func TestCataloger_CommitOverLimits(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	if err := c.SetCommitLimits(ctx, repository, &catalog.CommitLimits{MaxChangedObjects: 2}); err != nil {
+		t.Fatal("SetCommitLimits()", err)
+	}
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "b", nil, "")
+	if _, err := c.Commit(ctx, repository, "master", "within limits", "tester", nil, catalog.CommitParams{}); err != nil {
+		t.Fatal("Commit() within limits", err)
+	}
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "c", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "d", nil, "")
+	if err := c.DeleteEntry(ctx, repository, "master", "a"); err != nil {
+		t.Fatal("DeleteEntry()", err)
+	}
+	_, err := c.Commit(ctx, repository, "master", "over limits", "tester", nil, catalog.CommitParams{})
+	if !errors.Is(err, catalog.ErrCommitLimitExceeded) {
+		t.Fatalf("Commit() over limits err=%s, expected=%s", err, catalog.ErrCommitLimitExceeded)
+	}
+
+	if _, err := c.Commit(catalog.WithCommitLimitsExempt(ctx), repository, "master", "exempt", "tester", nil, catalog.CommitParams{}); err != nil {
+		t.Fatal("Commit() exempt from limits", err)
+	}
+}
+
+func TestCataloger_MergeOverLimits(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	for _, p := range []string{"a", "b", "c"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "branch1", p, nil, "")
+	}
+	if _, err := c.Commit(ctx, repository, "branch1", "three objects", "tester", nil, catalog.CommitParams{}); err != nil {
+		t.Fatal("Commit()", err)
+	}
+	if err := c.SetCommitLimits(ctx, repository, &catalog.CommitLimits{MaxChangedObjects: 2}); err != nil {
+		t.Fatal("SetCommitLimits()", err)
+	}
+
+	_, err := c.Merge(ctx, repository, "branch1", "master", "tester", "over limits", nil, catalog.MergeParams{})
+	if !errors.Is(err, catalog.ErrCommitLimitExceeded) {
+		t.Fatalf("Merge() over limits err=%s, expected=%s", err, catalog.ErrCommitLimitExceeded)
+	}
+
+	// the rejected merge was rolled back, so the exempt merge applies all the changes
+	res, err := c.Merge(catalog.WithCommitLimitsExempt(ctx), repository, "branch1", "master", "tester", "exempt", nil, catalog.MergeParams{})
+	if err != nil {
+		t.Fatal("Merge() exempt from limits", err)
+	}
+	if added := res.Summary[catalog.DifferenceTypeAdded]; added != 3 {
+		t.Fatalf("Merge() exempt from limits added %d objects, expected 3", added)
+	}
+}
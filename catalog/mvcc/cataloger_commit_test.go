@@ -99,7 +99,7 @@ func TestCataloger_Commit(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			now := time.Now()
-			got, err := c.Commit(ctx, tt.args.repository, tt.args.branch, tt.args.message, tt.args.committer, tt.args.metadata)
+			got, err := c.Commit(ctx, tt.args.repository, tt.args.branch, tt.args.message, tt.args.committer, tt.args.metadata, catalog.CommitParams{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Commit() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -125,7 +125,7 @@ func TestCataloger_Commit_Scenario(t *testing.T) {
 
 	t.Run("nothing", func(t *testing.T) {
 		repository := testCatalogerRepo(t, ctx, c, "repository", "master")
-		_, err := c.Commit(ctx, repository, "master", "in a bottle", "tester1", nil)
+		_, err := c.Commit(ctx, repository, "master", "in a bottle", "tester1", nil, catalog.CommitParams{})
 		if !errors.Is(err, catalog.ErrNothingToCommit) {
 			t.Fatal("Expect nothing to commit error, got", err)
 		}
@@ -144,7 +144,7 @@ func TestCataloger_Commit_Scenario(t *testing.T) {
 				t.Error("create entry for commit twice", err)
 				return
 			}
-			commitLog, err := c.Commit(ctx, repository, "master", "commit"+strconv.Itoa(i+1), "tester", nil)
+			commitLog, err := c.Commit(ctx, repository, "master", "commit"+strconv.Itoa(i+1), "tester", nil, catalog.CommitParams{})
 			if err != nil {
 				t.Errorf("Commit got error on iteration %d: %s", i+1, err)
 				return
@@ -182,7 +182,7 @@ func TestCataloger_Commit_Scenario(t *testing.T) {
 				t.Error("create entry for file per commit", err)
 				return
 			}
-			commitLog, err := c.Commit(ctx, repository, "master", "commit"+strconv.Itoa(i+1), "tester", nil)
+			commitLog, err := c.Commit(ctx, repository, "master", "commit"+strconv.Itoa(i+1), "tester", nil, catalog.CommitParams{})
 			if err != nil {
 				t.Errorf("Commit got error on iteration %d: %s", i+1, err)
 				return
@@ -217,7 +217,7 @@ func TestCataloger_Commit_Scenario(t *testing.T) {
 			t.Fatal("create entry for file per commit", err)
 			return
 		}
-		_, err := c.Commit(ctx, repository, "master", "commit one file", "tester", nil)
+		_, err := c.Commit(ctx, repository, "master", "commit one file", "tester", nil, catalog.CommitParams{})
 		if err != nil {
 			t.Fatal("Commit expected to succeed error:", err)
 		}
@@ -244,7 +244,7 @@ func TestCataloger_Commit_Scenario(t *testing.T) {
 		if len(entries) != 1 {
 			t.Fatalf("List should find 1 file, got %d", len(entries))
 		}
-		_, err = c.Commit(ctx, repository, "master", "delete one file", "tester", nil)
+		_, err = c.Commit(ctx, repository, "master", "delete one file", "tester", nil, catalog.CommitParams{})
 		if err != nil {
 			t.Fatal("Commit expected to succeed error:", err)
 		}
@@ -265,7 +265,7 @@ func TestCataloger_CommitTombstoneShouldNotChangeHistory(t *testing.T) {
 
 	// create file
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file42", nil, "")
-	_, err := c.Commit(ctx, repository, "master", "commit new file", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "commit new file", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit new file", err)
 
 	// create branch
@@ -277,7 +277,7 @@ func TestCataloger_CommitTombstoneShouldNotChangeHistory(t *testing.T) {
 	testutil.MustDo(t, "delete entry", err)
 
 	// commit the delete - should create tombstone
-	_, err = c.Commit(ctx, repository, "branch1", "commit delete file", "tester", nil)
+	_, err = c.Commit(ctx, repository, "branch1", "commit delete file", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit delete file", err)
 
 	// verify that the file is deleted
@@ -290,6 +290,45 @@ func TestCataloger_CommitTombstoneShouldNotChangeHistory(t *testing.T) {
 	}
 }
 
+func TestCataloger_CommitPrefixes(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	defer func() { _ = c.Close() }()
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/deleted", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "b/deleted", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit files", err)
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/file", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "b/file", nil, "")
+	testutil.MustDo(t, "delete a/deleted", c.DeleteEntry(ctx, repository, "master", "a/deleted"))
+	testutil.MustDo(t, "delete b/deleted", c.DeleteEntry(ctx, repository, "master", "b/deleted"))
+	_, err = c.Commit(ctx, repository, "master", "commit a", "tester", nil, catalog.CommitParams{Prefixes: []string{"a/"}})
+	testutil.MustDo(t, "commit prefix a/", err)
+
+	changes, _, err := c.DiffUncommitted(ctx, repository, "master", "", -1, "")
+	testutil.MustDo(t, "diff uncommitted", err)
+	var paths []string
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	if len(paths) != 2 || paths[0] != "b/deleted" || paths[1] != "b/file" {
+		t.Errorf("uncommitted paths %v, expected [b/deleted b/file]", paths)
+	}
+	if _, err := c.GetEntry(ctx, repository, "master:HEAD", "a/file", catalog.GetEntryParams{}); err != nil {
+		t.Errorf("get committed a/file: %s", err)
+	}
+	if _, err := c.GetEntry(ctx, repository, "master:HEAD", "a/deleted", catalog.GetEntryParams{}); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("get committed a/deleted err=%v, expected %s", err, db.ErrNotFound)
+	}
+
+	_, err = c.Commit(ctx, repository, "master", "commit c", "tester", nil, catalog.CommitParams{Prefixes: []string{"c/"}})
+	if !errors.Is(err, catalog.ErrNothingToCommit) {
+		t.Errorf("commit prefix without changes err=%v, expected %s", err, catalog.ErrNothingToCommit)
+	}
+}
+
 // CommitHookLogger - commit hook that will return an error if set by Err.
 // When no Err is set it will log commit log into Logs.
 type CommitHookLogger struct {
@@ -339,7 +378,7 @@ func TestCataloger_CommitHooks(t *testing.T) {
 			repository := testCatalogerRepo(t, ctx, c, "repository", "master")
 			_ = testCatalogerCreateEntry(t, ctx, c, repository, catalog.DefaultBranchName, "/file1", nil, "")
 
-			commitLog, err := c.Commit(ctx, repository, "master", "commit "+t.Name(), "tester", catalog.Metadata{"foo": "bar"})
+			commitLog, err := c.Commit(ctx, repository, "master", "commit "+t.Name(), "tester", catalog.Metadata{"foo": "bar"}, catalog.CommitParams{})
 			// check that hook err is the commit error
 			if !errors.Is(tt.hookErr, err) {
 				t.Fatalf("Commit err=%s, expected=%s", err, tt.hookErr)
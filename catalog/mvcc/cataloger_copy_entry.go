@@ -0,0 +1,66 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+)
+
+// CopyEntry copies the entry at sourcePath on sourceReference of sourceRepository to
+// destinationPath on destinationBranch of destinationRepository.  Repositories sharing a
+// storage namespace share the object, otherwise params.CopyObject copies it before the entry
+// is created.
+func (c *cataloger) CopyEntry(ctx context.Context, sourceRepository, sourceReference, sourcePath, destinationRepository, destinationBranch, destinationPath string, params catalog.CopyEntryParams) (*catalog.Entry, error) {
+	if err := Validate(ValidateFields{
+		{Name: "sourceRepository", IsValid: ValidateRepositoryName(sourceRepository)},
+		{Name: "sourceReference", IsValid: ValidateReference(sourceReference)},
+		{Name: "sourcePath", IsValid: ValidatePath(sourcePath)},
+		{Name: "destinationRepository", IsValid: ValidateRepositoryName(destinationRepository)},
+		{Name: "destinationBranch", IsValid: ValidateBranchName(destinationBranch)},
+		{Name: "destinationPath", IsValid: ValidatePath(destinationPath)},
+	}); err != nil {
+		return nil, err
+	}
+	entry, err := c.GetEntry(ctx, sourceRepository, sourceReference, sourcePath, catalog.GetEntryParams{})
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	sourceRepo, err := c.GetRepository(ctx, sourceRepository)
+	if err != nil {
+		return nil, fmt.Errorf("source repository: %w", err)
+	}
+	destinationRepo, err := c.GetRepository(ctx, destinationRepository)
+	if err != nil {
+		return nil, fmt.Errorf("destination repository: %w", err)
+	}
+	// fail before copying the object, CreateEntry checks again
+	if destinationRepo.Archived {
+		return nil, fmt.Errorf("%s: %w", destinationRepository, catalog.ErrRepositoryArchived)
+	}
+	if destinationRepo.ReadOnly {
+		return nil, fmt.Errorf("%s: %w", destinationRepository, catalog.ErrRepositoryReadOnly)
+	}
+	if err := c.CheckRepositoryQuota(ctx, destinationRepository, 1, entry.Size); err != nil {
+		return nil, fmt.Errorf("%s: %w", destinationRepository, err)
+	}
+
+	// physical addresses are relative to the storage namespace of their repository
+	if sourceRepo.StorageNamespace != destinationRepo.StorageNamespace {
+		if params.CopyObject == nil {
+			return nil, fmt.Errorf("copy between storage namespaces: %w", catalog.ErrFeatureNotSupported)
+		}
+		address, err := params.CopyObject(sourceRepo.StorageNamespace, entry.PhysicalAddress, destinationRepo.StorageNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("copy object: %w", err)
+		}
+		entry.PhysicalAddress = address
+	}
+	entry.Path = destinationPath
+	entry.CreationDate = time.Now()
+	if err := c.CreateEntry(ctx, destinationRepository, destinationBranch, *entry, catalog.CreateEntryParams{}); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,69 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_CopyEntry(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	sourceRepository := testCatalogerRepo(t, ctx, c, "source", "master")
+	testCatalogerCreateEntry(t, ctx, c, sourceRepository, "master", "file1", catalog.Metadata{"k": "v"}, "")
+	source, err := c.GetEntry(ctx, sourceRepository, "master", "file1", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get source", err)
+
+	t.Run("same namespace", func(t *testing.T) {
+		destinationRepository := testCatalogerRepo(t, ctx, c, "destination", "master")
+		copied, err := c.CopyEntry(ctx, sourceRepository, "master", "file1", destinationRepository, "master", "copied", catalog.CopyEntryParams{})
+		testutil.MustDo(t, "copy entry", err)
+		entry, err := c.GetEntry(ctx, destinationRepository, "master", "copied", catalog.GetEntryParams{})
+		testutil.MustDo(t, "get copied entry", err)
+		if entry.PhysicalAddress != source.PhysicalAddress || entry.Checksum != source.Checksum || entry.Metadata["k"] != "v" {
+			t.Errorf("copied entry %+v, expected object of %+v", entry, source)
+		}
+		if copied.Path != "copied" {
+			t.Errorf("copy returned entry of %s, expected copied", copied.Path)
+		}
+	})
+
+	t.Run("other namespace", func(t *testing.T) {
+		destinationRepository := "destination-" + testCatalogerUniqueID()
+		_, err := c.CreateRepository(ctx, destinationRepository, "s3://other-bucket", "master")
+		testutil.MustDo(t, "create repository", err)
+
+		_, err = c.CopyEntry(ctx, sourceRepository, "master", "file1", destinationRepository, "master", "copied", catalog.CopyEntryParams{})
+		if !errors.Is(err, catalog.ErrFeatureNotSupported) {
+			t.Errorf("copy without CopyObject err=%v, expected %s", err, catalog.ErrFeatureNotSupported)
+		}
+
+		var copiedFrom string
+		_, err = c.CopyEntry(ctx, sourceRepository, "master", "file1", destinationRepository, "master", "copied", catalog.CopyEntryParams{
+			CopyObject: func(sourceNamespace, sourceAddress, destinationNamespace string) (string, error) {
+				copiedFrom = sourceNamespace + "/" + sourceAddress
+				return "copied-address", nil
+			},
+		})
+		testutil.MustDo(t, "copy entry", err)
+		if copiedFrom != "s3://bucket/"+source.PhysicalAddress {
+			t.Errorf("copied object from %s, expected address of source", copiedFrom)
+		}
+		entry, err := c.GetEntry(ctx, destinationRepository, "master", "copied", catalog.GetEntryParams{})
+		testutil.MustDo(t, "get copied entry", err)
+		if entry.PhysicalAddress != "copied-address" || entry.Checksum != source.Checksum {
+			t.Errorf("copied entry %+v, expected copied-address with source checksum", entry)
+		}
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		_, err := c.CopyEntry(ctx, sourceRepository, "master", "no-such-file", sourceRepository, "master", "copied", catalog.CopyEntryParams{})
+		if !errors.Is(err, db.ErrNotFound) {
+			t.Errorf("copy missing entry err=%v, expected %s", err, db.ErrNotFound)
+		}
+	})
+}
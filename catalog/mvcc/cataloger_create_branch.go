@@ -23,69 +23,87 @@ func (c *cataloger) CreateBranch(ctx context.Context, repository, branch string,
 	}
 
 	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
-		_, err := tx.Exec("LOCK TABLE catalog_branches IN SHARE UPDATE EXCLUSIVE MODE")
-		if err != nil {
-			return nil, fmt.Errorf("lock branches for update: %w", err)
-		}
-
-		repoID, err := c.getRepositoryIDCache(tx, repository)
-		if err != nil {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
 			return nil, err
 		}
+		return c.createBranch(tx, repository, branch, sourceBranch)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	commitLog := res.(*catalog.CommitLog)
+	return commitLog, nil
+}
 
-		// get source branch id and
-		var sourceBranchID int
-		if err := tx.GetPrimitive(&sourceBranchID, `SELECT id FROM catalog_branches WHERE repository_id=$1 AND name=$2`,
-			repoID, sourceBranch); err != nil {
-			return nil, fmt.Errorf("source branch id: %w", err)
-		}
+// createBranch creates branch from the last commit of sourceBranch
+func (c *cataloger) createBranch(tx db.Tx, repository, branch string, sourceBranch string) (*catalog.CommitLog, error) {
+	_, err := tx.Exec("LOCK TABLE catalog_branches IN SHARE UPDATE EXCLUSIVE MODE")
+	if err != nil {
+		return nil, fmt.Errorf("lock branches for update: %w", err)
+	}
 
-		// next id for branch
-		var branchID int64
-		if err := tx.GetPrimitive(&branchID, `SELECT nextval('catalog_branches_id_seq')`); err != nil {
-			return nil, fmt.Errorf("next branch id: %w", err)
-		}
+	repoID, err := c.getRepositoryIDCache(tx, repository)
+	if err != nil {
+		return nil, err
+	}
 
-		// insert new branch
-		if _, err := tx.Exec(`INSERT INTO catalog_branches (repository_id, id, name, lineage)
-			VALUES($1,$2,$3,(SELECT $4::bigint||lineage FROM catalog_branches WHERE id=$4))`,
-			repoID, branchID, branch, sourceBranchID); err != nil {
-			return nil, fmt.Errorf("insert branch: %w", err)
-		}
+	// tags and branches share a namespace
+	var isTag bool
+	if err := tx.GetPrimitive(&isTag, `SELECT EXISTS (SELECT 1 FROM catalog_tags WHERE repository_id=$1 AND name=$2)`,
+		repoID, branch); err != nil {
+		return nil, fmt.Errorf("tag check: %w", err)
+	}
+	if isTag {
+		return nil, fmt.Errorf("branch %s is a tag: %w", branch, db.ErrAlreadyExists)
+	}
 
-		insertReturns := struct {
-			CommitID             CommitID  `db:"commit_id"`
-			MergeSourceCommit    CommitID  `db:"merge_source_commit"`
-			TransactionTimestamp time.Time `db:"transaction_timestamp"`
-		}{}
-		commitMsg := fmt.Sprintf(createBranchCommitMessageFormat, branch, sourceBranch)
-		err = tx.Get(&insertReturns, `INSERT INTO catalog_commits (branch_id,commit_id,previous_commit_id,committer,message,
-			creation_date,merge_source_branch,merge_type,lineage_commits,merge_source_commit)
-			VALUES ($1,nextval('catalog_commit_id_seq'),0,$2,$3,transaction_timestamp(),$4,'from_parent',
-				(select (select max(commit_id) from catalog_commits where branch_id=$4) ||
-					(select distinct on (branch_id) lineage_commits from catalog_commits
-						where branch_id=$4 and merge_type='from_parent' order by branch_id,commit_id desc))
-						,(select max(commit_id) from catalog_commits where branch_id=$4 ))
-			RETURNING commit_id,merge_source_commit,transaction_timestamp()`,
-			branchID, catalog.DefaultCommitter, commitMsg, sourceBranchID)
-		if err != nil {
-			return nil, fmt.Errorf("insert commit: %w", err)
-		}
-		reference := MakeReference(branch, insertReturns.CommitID)
-		parentReference := MakeReference(sourceBranch, insertReturns.MergeSourceCommit)
+	// get source branch id and
+	var sourceBranchID int
+	if err := tx.GetPrimitive(&sourceBranchID, `SELECT id FROM catalog_branches WHERE repository_id=$1 AND name=$2`,
+		repoID, sourceBranch); err != nil {
+		return nil, fmt.Errorf("source branch id: %w", err)
+	}
 
-		commitLog := &catalog.CommitLog{
-			Committer:    catalog.DefaultCommitter,
-			Message:      commitMsg,
-			CreationDate: insertReturns.TransactionTimestamp,
-			Reference:    reference,
-			Parents:      []string{parentReference},
-		}
-		return commitLog, nil
-	}, c.txOpts(ctx)...)
+	// next id for branch
+	var branchID int64
+	if err := tx.GetPrimitive(&branchID, `SELECT nextval('catalog_branches_id_seq')`); err != nil {
+		return nil, fmt.Errorf("next branch id: %w", err)
+	}
+
+	// insert new branch
+	if _, err := tx.Exec(`INSERT INTO catalog_branches (repository_id, id, name, lineage)
+		VALUES($1,$2,$3,(SELECT $4::bigint||lineage FROM catalog_branches WHERE id=$4))`,
+		repoID, branchID, branch, sourceBranchID); err != nil {
+		return nil, fmt.Errorf("insert branch: %w", err)
+	}
+
+	insertReturns := struct {
+		CommitID             CommitID  `db:"commit_id"`
+		MergeSourceCommit    CommitID  `db:"merge_source_commit"`
+		TransactionTimestamp time.Time `db:"transaction_timestamp"`
+	}{}
+	commitMsg := fmt.Sprintf(createBranchCommitMessageFormat, branch, sourceBranch)
+	err = tx.Get(&insertReturns, `INSERT INTO catalog_commits (branch_id,commit_id,previous_commit_id,committer,message,
+		creation_date,merge_source_branch,merge_type,lineage_commits,merge_source_commit)
+		VALUES ($1,nextval('catalog_commit_id_seq'),0,$2,$3,transaction_timestamp(),$4,'from_parent',
+			(select (select max(commit_id) from catalog_commits where branch_id=$4) ||
+				(select distinct on (branch_id) lineage_commits from catalog_commits
+					where branch_id=$4 and merge_type='from_parent' order by branch_id,commit_id desc))
+					,(select max(commit_id) from catalog_commits where branch_id=$4 ))
+		RETURNING commit_id,merge_source_commit,transaction_timestamp()`,
+		branchID, catalog.DefaultCommitter, commitMsg, sourceBranchID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("insert commit: %w", err)
+	}
+	reference := MakeReference(branch, insertReturns.CommitID)
+	parentReference := MakeReference(sourceBranch, insertReturns.MergeSourceCommit)
+
+	commitLog := &catalog.CommitLog{
+		Committer:    catalog.DefaultCommitter,
+		Message:      commitMsg,
+		CreationDate: insertReturns.TransactionTimestamp,
+		Reference:    reference,
+		Parents:      []string{parentReference},
 	}
-	commitLog := res.(*catalog.CommitLog)
 	return commitLog, nil
 }
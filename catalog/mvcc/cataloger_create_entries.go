@@ -11,6 +11,7 @@ import (
 )
 
 // CreateEntries add multiple entries into the catalog, this process doesn't pass through de-dup mechanism.
+//   All entries are written in a single transaction: either all of them are added or none is.
 //   It is mainly used by import mass entries into the catalog.
 func (c *cataloger) CreateEntries(ctx context.Context, repository, branch string, entries []catalog.Entry) error {
 	if err := Validate(ValidateFields{
@@ -32,6 +33,9 @@ func (c *cataloger) CreateEntries(ctx context.Context, repository, branch string
 		if !IsNonEmptyString(p) {
 			return fmt.Errorf("entry at pos %d, path: %w", i, catalog.ErrInvalidValue)
 		}
+		if !IsNonEmptyString(entries[i].PhysicalAddress) {
+			return fmt.Errorf("entry at pos %d, physical address: %w", i, catalog.ErrInvalidValue)
+		}
 		entriesMap[p] = &entries[i]
 	}
 
@@ -46,15 +50,40 @@ func (c *cataloger) CreateEntries(ctx context.Context, repository, branch string
 
 	// create entries
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
 		branchID, err := c.getBranchIDCache(tx, repository, branch)
 		if err != nil {
 			return nil, err
 		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var size int64
+		paths := make([]string, len(entriesToInsert))
+		for i, entry := range entriesToInsert {
+			size += entry.Size
+			paths[i] = entry.Path
+		}
+		err = checkLocks(ctx, tx, branchID, paths)
+		if err != nil {
+			return nil, err
+		}
+		err = checkRepositoryQuota(tx, repoID, int64(len(entriesToInsert)), size)
+		if err != nil {
+			return nil, err
+		}
 		// single insert per batch
 		entriesInsertSize := c.BatchWrite.EntriesInsertSize
 		for i := 0; i < len(entriesToInsert); i += entriesInsertSize {
 			sqInsert := psql.Insert("catalog_entries").
-				Columns("branch_id", "path", "physical_address", "checksum", "size", "metadata", "creation_date", "is_expired", "min_commit")
+				Columns("branch_id", "path", "physical_address", "checksum", "size", "metadata", "creation_date", "is_expired", "min_commit",
+					"content_type", "content_encoding", "cache_control")
 			j := i + entriesInsertSize
 			if j > len(entriesToInsert) {
 				j = len(entriesToInsert)
@@ -66,10 +95,11 @@ func (c *cataloger) CreateEntries(ctx context.Context, repository, branch string
 					dbTime.Valid = true
 				}
 				sqInsert = sqInsert.Values(branchID, entry.Path, entry.PhysicalAddress, entry.Checksum, entry.Size, entry.Metadata,
-					sq.Expr("COALESCE(?,NOW())", dbTime), entry.Expired, MaxCommitID)
+					sq.Expr("COALESCE(?,NOW())", dbTime), entry.Expired, MaxCommitID, entry.ContentType, entry.ContentEncoding, entry.CacheControl)
 			}
 			query, args, err := sqInsert.Suffix(`ON CONFLICT (branch_id,path,min_commit)
-DO UPDATE SET physical_address=EXCLUDED.physical_address, checksum=EXCLUDED.checksum, size=EXCLUDED.size, metadata=EXCLUDED.metadata, creation_date=EXCLUDED.creation_date, is_expired=EXCLUDED.is_expired, min_commit=EXCLUDED.min_commit, max_commit=?`, MaxCommitID).
+DO UPDATE SET physical_address=EXCLUDED.physical_address, checksum=EXCLUDED.checksum, size=EXCLUDED.size, metadata=EXCLUDED.metadata, creation_date=EXCLUDED.creation_date, is_expired=EXCLUDED.is_expired, min_commit=EXCLUDED.min_commit, max_commit=?,
+	content_type=EXCLUDED.content_type, content_encoding=EXCLUDED.content_encoding, cache_control=EXCLUDED.cache_control`, MaxCommitID).
 				ToSql()
 			if err != nil {
 				return nil, fmt.Errorf("build query: %w", err)
@@ -102,6 +102,27 @@ func TestCataloger_CreateEntries(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "missing physical address",
+			args: args{
+				repository: repo,
+				branch:     "master",
+				entries: []catalog.Entry{
+					{
+						Path:            "/aaa/bbb/cccX",
+						Checksum:        "1239",
+						PhysicalAddress: "5679",
+						Size:            999,
+					},
+					{
+						Path:     "/aaa/bbb/cccY",
+						Checksum: "1239",
+						Size:     999,
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing branch",
 			args: args{
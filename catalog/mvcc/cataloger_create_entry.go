@@ -19,10 +19,36 @@ func (c *cataloger) CreateEntry(ctx context.Context, repository, branch string,
 	}
 
 	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
 		branchID, err := c.getBranchIDCache(tx, repository, branch)
 		if err != nil {
 			return nil, err
 		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		err = checkLocks(ctx, tx, branchID, []string{entry.Path})
+		if err != nil {
+			return nil, err
+		}
+		err = checkRepositoryQuota(tx, repoID, 1, entry.Size)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := getMetadataSchema(tx, repoID)
+		if err != nil {
+			return nil, err
+		}
+		err = schema.Check(entry.Path, entry.Metadata)
+		if err != nil {
+			return nil, err
+		}
 		return insertEntry(tx, branchID, &entry)
 	}, c.txOpts(ctx)...)
 	if err != nil {
@@ -53,12 +79,14 @@ func insertEntry(tx db.Tx, branchID int64, entry *catalog.Entry) (string, error)
 		dbTime.Time = entry.CreationDate
 		dbTime.Valid = true
 	}
-	err := tx.GetPrimitive(&ctid, `INSERT INTO catalog_entries (branch_id,path,physical_address,checksum,size,metadata,creation_date,is_expired,min_commit)
-                        VALUES ($1,$2,$3,$4,$5,$6,COALESCE($7,NOW()),$8,$9)
+	err := tx.GetPrimitive(&ctid, `INSERT INTO catalog_entries (branch_id,path,physical_address,checksum,size,metadata,creation_date,is_expired,min_commit,content_type,content_encoding,cache_control)
+                        VALUES ($1,$2,$3,$4,$5,$6,COALESCE($7,NOW()),$8,$9,$10,$11,$12)
 			ON CONFLICT (branch_id,path,min_commit)
-			DO UPDATE SET physical_address=EXCLUDED.physical_address, checksum=EXCLUDED.checksum, size=EXCLUDED.size, metadata=EXCLUDED.metadata, creation_date=EXCLUDED.creation_date, is_expired=EXCLUDED.is_expired, min_commit=EXCLUDED.min_commit, max_commit=$9
+			DO UPDATE SET physical_address=EXCLUDED.physical_address, checksum=EXCLUDED.checksum, size=EXCLUDED.size, metadata=EXCLUDED.metadata, creation_date=EXCLUDED.creation_date, is_expired=EXCLUDED.is_expired, min_commit=EXCLUDED.min_commit, max_commit=$9,
+				content_type=EXCLUDED.content_type, content_encoding=EXCLUDED.content_encoding, cache_control=EXCLUDED.cache_control
 			RETURNING ctid`,
-		branchID, entry.Path, entry.PhysicalAddress, entry.Checksum, entry.Size, entry.Metadata, dbTime, entry.Expired, MaxCommitID)
+		branchID, entry.Path, entry.PhysicalAddress, entry.Checksum, entry.Size, entry.Metadata, dbTime, entry.Expired, MaxCommitID,
+		entry.ContentType, entry.ContentEncoding, entry.CacheControl)
 	if err != nil {
 		return "", fmt.Errorf("insert entry: %w", err)
 	}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 )
 
-func (c *cataloger) CreateMultipartUpload(ctx context.Context, repository string, uploadID, path, physicalAddress string, creationTime time.Time) error {
+func (c *cataloger) CreateMultipartUpload(ctx context.Context, repository string, uploadID, path, physicalAddress string, creationTime time.Time, params catalog.CreateMultipartUploadParams) error {
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "uploadID", IsValid: ValidateUploadID(uploadID)},
@@ -18,13 +19,29 @@ func (c *cataloger) CreateMultipartUpload(ctx context.Context, repository string
 	}
 
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
 		repoID, err := c.getRepositoryIDCache(tx, repository)
 		if err != nil {
 			return nil, err
 		}
-		_, err = tx.Exec(`INSERT INTO catalog_multipart_uploads (repository_id,upload_id,path,creation_date,physical_address)
-			VALUES ($1, $2, $3, $4, $5)`,
-			repoID, uploadID, path, creationTime, physicalAddress)
+		// reject metadata early, the entry created on completion is checked again
+		schema, err := getMetadataSchema(tx, repoID)
+		if err != nil {
+			return nil, err
+		}
+		if err := schema.Check(path, params.Metadata); err != nil {
+			return nil, err
+		}
+		// uploads without metadata store none, rather than empty metadata
+		var metadata interface{}
+		if len(params.Metadata) > 0 {
+			metadata = params.Metadata
+		}
+		_, err = tx.Exec(`INSERT INTO catalog_multipart_uploads (repository_id,upload_id,path,creation_date,physical_address,content_type,content_encoding,cache_control,metadata)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			repoID, uploadID, path, creationTime, physicalAddress, params.ContentType, params.ContentEncoding, params.CacheControl, metadata)
 		return nil, err
 	}, c.txOpts(ctx)...)
 	return err
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/testutil"
 )
 
@@ -16,7 +17,7 @@ func TestCataloger_CreateMultipartUpload(t *testing.T) {
 	if _, err := c.CreateRepository(ctx, "repo1", "s3://bucket1", "master"); err != nil {
 		t.Fatal("create repository for testing", err)
 	}
-	if err := c.CreateMultipartUpload(ctx, "repo1", "uploadX", "/pathX", "/fileX", time.Now()); err != nil {
+	if err := c.CreateMultipartUpload(ctx, "repo1", "uploadX", "/pathX", "/fileX", time.Now(), catalog.CreateMultipartUploadParams{}); err != nil {
 		t.Fatal("create multipart upload for testing", err)
 	}
 
@@ -60,7 +61,7 @@ func TestCataloger_CreateMultipartUpload(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := c.CreateMultipartUpload(ctx, tt.args.repository, tt.args.uploadID, tt.args.path, tt.args.physicalAddress, tt.args.creationTime)
+			err := c.CreateMultipartUpload(ctx, tt.args.repository, tt.args.uploadID, tt.args.path, tt.args.physicalAddress, tt.args.creationTime, catalog.CreateMultipartUploadParams{})
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("CreateMultipartUpload() error = %v, wantErr %v", err, tt.wantErr)
 			}
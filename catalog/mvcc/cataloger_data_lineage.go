@@ -0,0 +1,172 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	DataLineageMaxDepth   = 100
+	DataLineageMaxSources = 100
+	DataLineageMaxEdges   = 10000
+)
+
+type dataLineageEdgeRaw struct {
+	Repository       string    `db:"repository"`
+	BranchName       string    `db:"branch_name"`
+	CommitID         CommitID  `db:"commit_id"`
+	SourceRepository string    `db:"source_repository"`
+	SourceBranchName string    `db:"source_branch_name"`
+	SourceCommitID   CommitID  `db:"source_commit_id"`
+	CreationDate     time.Time `db:"creation_date"`
+	Depth            int       `db:"depth"`
+}
+
+// resolveCommit returns the branch and commit referenced by reference, a branch reference is
+// resolved to the last commit of the branch
+func (c *cataloger) resolveCommit(tx db.Tx, repository, reference string) (int64, CommitID, error) {
+	ref, err := c.resolveRef(tx, repository, reference)
+	if err != nil {
+		return 0, 0, err
+	}
+	branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+	if err != nil {
+		return 0, 0, err
+	}
+	commitID := ref.CommitID
+	if commitID == UncommittedID || commitID == CommittedID {
+		commitID, err = getLastCommitIDByBranchID(tx, branchID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("get last commit id: %w", err)
+		}
+		return branchID, commitID, nil
+	}
+	var exists bool
+	err = tx.GetPrimitive(&exists, `SELECT EXISTS (SELECT 1 FROM catalog_commits WHERE branch_id=$1 AND commit_id=$2)`,
+		branchID, commitID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !exists {
+		return 0, 0, catalog.ErrCommitNotFound
+	}
+	return branchID, commitID, nil
+}
+
+func (c *cataloger) CreateDataLineage(ctx context.Context, repository, reference string, sources []catalog.DataLineageSource) error {
+	fields := ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "sources", IsValid: func() bool { return len(sources) > 0 && len(sources) <= DataLineageMaxSources }},
+	}
+	for i, source := range sources {
+		fields = append(fields,
+			ValidateField{Name: fmt.Sprintf("sources[%d].repository", i), IsValid: ValidateRepositoryName(source.Repository)},
+			ValidateField{Name: fmt.Sprintf("sources[%d].reference", i), IsValid: ValidateReference(source.Reference)})
+	}
+	if err := Validate(fields); err != nil {
+		return err
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		branchID, commitID, err := c.resolveCommit(tx, repository, reference)
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range sources {
+			sourceBranchID, sourceCommitID, err := c.resolveCommit(tx, source.Repository, source.Reference)
+			if err != nil {
+				return nil, fmt.Errorf("source %s@%s: %w", source.Repository, source.Reference, err)
+			}
+			if sourceBranchID == branchID && sourceCommitID == commitID {
+				return nil, fmt.Errorf("commit produced from itself: %w", catalog.ErrInvalidValue)
+			}
+			_, err = tx.Exec(`INSERT INTO catalog_data_lineage (branch_id, commit_id, source_branch_id, source_commit_id)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT DO NOTHING`,
+				branchID, commitID, sourceBranchID, sourceCommitID)
+			if err != nil {
+				return nil, fmt.Errorf("insert data lineage: %w", err)
+			}
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func (c *cataloger) WalkDataLineage(ctx context.Context, repository, reference string, direction catalog.DataLineageDirection, depth int) ([]*catalog.DataLineageEdge, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+	}); err != nil {
+		return nil, err
+	}
+	// walk from the commit columns to the source columns (upstream), or back (downstream)
+	var fromColumns, toColumns string
+	switch direction {
+	case catalog.DataLineageUpstream:
+		fromColumns, toColumns = "branch_id, commit_id", "source_branch_id, source_commit_id"
+	case catalog.DataLineageDownstream:
+		fromColumns, toColumns = "source_branch_id, source_commit_id", "branch_id, commit_id"
+	default:
+		return nil, fmt.Errorf("direction: %w", catalog.ErrInvalidValue)
+	}
+	if depth <= 0 || depth > DataLineageMaxDepth {
+		depth = DataLineageMaxDepth
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, commitID, err := c.resolveCommit(tx, repository, reference)
+		if err != nil {
+			return nil, err
+		}
+		// UNION drops edges repeated at the same depth, DISTINCT ON keeps each edge at its
+		// minimal depth
+		query := `WITH RECURSIVE walk AS (
+				SELECT branch_id, commit_id, source_branch_id, source_commit_id, creation_date, 1 AS depth
+				FROM catalog_data_lineage
+				WHERE (` + fromColumns + `) = ($1, $2)
+			UNION
+				SELECT l.branch_id, l.commit_id, l.source_branch_id, l.source_commit_id, l.creation_date, w.depth + 1
+				FROM catalog_data_lineage l JOIN walk w ON (l.` + fromColumns + `) = (w.` + toColumns + `)
+				WHERE w.depth < $3
+			)
+			SELECT r.name AS repository, b.name AS branch_name, e.commit_id,
+				sr.name AS source_repository, sb.name AS source_branch_name, e.source_commit_id,
+				e.creation_date, e.depth
+			FROM (SELECT DISTINCT ON (branch_id, commit_id, source_branch_id, source_commit_id) *
+					FROM walk
+					ORDER BY branch_id, commit_id, source_branch_id, source_commit_id, depth) e
+				JOIN catalog_branches b ON b.id = e.branch_id
+				JOIN catalog_repositories r ON r.id = b.repository_id
+				JOIN catalog_branches sb ON sb.id = e.source_branch_id
+				JOIN catalog_repositories sr ON sr.id = sb.repository_id
+			ORDER BY e.depth, e.creation_date, e.commit_id, e.source_commit_id
+			LIMIT $4`
+		var rawEdges []dataLineageEdgeRaw
+		if err := tx.Select(&rawEdges, query, branchID, commitID, depth, DataLineageMaxEdges); err != nil {
+			return nil, err
+		}
+		edges := make([]*catalog.DataLineageEdge, len(rawEdges))
+		for i, raw := range rawEdges {
+			edges[i] = &catalog.DataLineageEdge{
+				Repository:       raw.Repository,
+				Reference:        MakeReference(raw.BranchName, raw.CommitID),
+				SourceRepository: raw.SourceRepository,
+				SourceReference:  MakeReference(raw.SourceBranchName, raw.SourceCommitID),
+				CreationDate:     raw.CreationDate,
+				Depth:            raw.Depth,
+			}
+		}
+		return edges, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.([]*catalog.DataLineageEdge), nil
+}
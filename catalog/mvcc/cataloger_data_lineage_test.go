@@ -0,0 +1,129 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_DataLineage(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	// raw -> clean -> report, where report also reads from raw
+	commitRefs := make(map[string]string)
+	for _, name := range []string{"raw", "clean", "report"} {
+		repository := testCatalogerRepo(t, ctx, c, name, "master")
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "data/"+name, nil, "")
+		commitLog, err := c.Commit(ctx, repository, "master", "produce "+name, "tester", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit "+name, err)
+		commitRefs[name] = commitLog.Reference
+	}
+	testutil.MustDo(t, "lineage clean", c.CreateDataLineage(ctx, "clean", commitRefs["clean"], []catalog.DataLineageSource{
+		{Repository: "raw", Reference: "master"},
+	}))
+	testutil.MustDo(t, "lineage report", c.CreateDataLineage(ctx, "report", "master", []catalog.DataLineageSource{
+		{Repository: "clean", Reference: commitRefs["clean"]},
+		{Repository: "raw", Reference: commitRefs["raw"]},
+	}))
+	// recording the same edge again is a no-op
+	testutil.MustDo(t, "lineage report again", c.CreateDataLineage(ctx, "report", "master", []catalog.DataLineageSource{
+		{Repository: "raw", Reference: commitRefs["raw"]},
+	}))
+
+	t.Run("invalid", func(t *testing.T) {
+		err := c.CreateDataLineage(ctx, "raw", "master", []catalog.DataLineageSource{
+			{Repository: "raw", Reference: commitRefs["raw"]},
+		})
+		if !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Fatalf("CreateDataLineage() from itself err=%v, expected=%v", err, catalog.ErrInvalidValue)
+		}
+		err = c.CreateDataLineage(ctx, "clean", "master", nil)
+		if !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Fatalf("CreateDataLineage() no sources err=%v, expected=%v", err, catalog.ErrInvalidValue)
+		}
+	})
+
+	type edge struct {
+		repository       string
+		sourceRepository string
+		depth            int
+	}
+	tests := []struct {
+		name       string
+		repository string
+		direction  catalog.DataLineageDirection
+		depth      int
+		want       []edge
+		wantErr    error
+	}{
+		{
+			name:       "upstream",
+			repository: "report",
+			direction:  catalog.DataLineageUpstream,
+			want: []edge{
+				{repository: "report", sourceRepository: "raw", depth: 1},
+				{repository: "report", sourceRepository: "clean", depth: 1},
+				{repository: "clean", sourceRepository: "raw", depth: 2},
+			},
+		},
+		{
+			name:       "upstream depth",
+			repository: "report",
+			direction:  catalog.DataLineageUpstream,
+			depth:      1,
+			want: []edge{
+				{repository: "report", sourceRepository: "raw", depth: 1},
+				{repository: "report", sourceRepository: "clean", depth: 1},
+			},
+		},
+		{
+			name:       "downstream",
+			repository: "raw",
+			direction:  catalog.DataLineageDownstream,
+			want: []edge{
+				{repository: "clean", sourceRepository: "raw", depth: 1},
+				{repository: "report", sourceRepository: "raw", depth: 1},
+				{repository: "report", sourceRepository: "clean", depth: 2},
+			},
+		},
+		{
+			name:       "no lineage",
+			repository: "raw",
+			direction:  catalog.DataLineageUpstream,
+			want:       []edge{},
+		},
+		{
+			name:       "invalid direction",
+			repository: "raw",
+			direction:  "sideways",
+			wantErr:    catalog.ErrInvalidValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edges, err := c.WalkDataLineage(ctx, tt.repository, commitRefs[tt.repository], tt.direction, tt.depth)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WalkDataLineage() err=%v, expected=%v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := make([]edge, len(edges))
+			for i, e := range edges {
+				got[i] = edge{repository: e.Repository, sourceRepository: e.SourceRepository, depth: e.Depth}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("WalkDataLineage() edges=%v, expected=%v", got, tt.want)
+			}
+			// edges of the same depth are ordered by creation, then by commit
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("WalkDataLineage() edges=%v, expected=%v", got, tt.want)
+				}
+			}
+		})
+	}
+}
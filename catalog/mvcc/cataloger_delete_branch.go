@@ -18,49 +18,83 @@ func (c *cataloger) DeleteBranch(ctx context.Context, repository, branch string)
 	}
 
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
-		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
-		if err != nil {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
 			return nil, err
 		}
+		return nil, deleteBranch(tx, repository, branch)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return err
+	}
+	// the name may be reused by a new branch
+	c.cache.InvalidateBranchID(repository, branch)
+	return nil
+}
 
-		// default branch doesn't have parents
-		var legacyCount int
-		err = tx.GetPrimitive(&legacyCount, `SELECT array_length(lineage,1) FROM catalog_branches WHERE id=$1`, branchID)
-		if err != nil {
-			return nil, err
-		}
-		if legacyCount == 0 {
-			return nil, fmt.Errorf("delete default branch: %w", catalog.ErrOperationNotPermitted)
-		}
+// deleteBranch deletes branch and its entries, unless it is the default branch or other
+// branches or tags depend on it
+func deleteBranch(tx db.Tx, repository, branch string) error {
+	branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
+	if err != nil {
+		return err
+	}
 
-		// check we don't have branch depends on us by count lineage records we are part of
-		var childBranches int
-		err = tx.GetPrimitive(&childBranches, `SELECT count(*) FROM catalog_branches b 
-			JOIN catalog_branches b2 ON b.repository_id = b2.repository_id AND b2.id=$1
-			WHERE $1=ANY(b.lineage)`, branchID)
-		if err != nil {
-			return nil, fmt.Errorf("dependent check: %w", err)
-		}
-		if childBranches > 0 {
-			return nil, fmt.Errorf("branch has dependent branch: %w", catalog.ErrOperationNotPermitted)
-		}
+	// the default branch may have been changed to a branch with parents
+	var isDefault bool
+	err = tx.GetPrimitive(&isDefault, `SELECT EXISTS (SELECT 1 FROM catalog_repositories WHERE default_branch=$1)`, branchID)
+	if err != nil {
+		return fmt.Errorf("default branch check: %w", err)
+	}
+	if isDefault {
+		return fmt.Errorf("delete default branch: %w", catalog.ErrOperationNotPermitted)
+	}
 
-		// delete branch entries
-		_, err = tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1`, branchID)
-		if err != nil {
-			return nil, fmt.Errorf("delete entries: %w", err)
-		}
+	// default branch doesn't have parents
+	var legacyCount int
+	err = tx.GetPrimitive(&legacyCount, `SELECT array_length(lineage,1) FROM catalog_branches WHERE id=$1`, branchID)
+	if err != nil {
+		return err
+	}
+	if legacyCount == 0 {
+		return fmt.Errorf("delete default branch: %w", catalog.ErrOperationNotPermitted)
+	}
 
-		// delete branch
-		res, err := tx.Exec(`DELETE FROM catalog_branches WHERE id=$1`, branchID)
-		if err != nil {
-			return nil, fmt.Errorf("delete branch: %w", err)
-		}
-		affected := res.RowsAffected()
-		if affected != 1 {
-			return nil, catalog.ErrBranchNotFound
-		}
-		return nil, nil
-	}, c.txOpts(ctx)...)
-	return err
+	// check we don't have branch depends on us by count lineage records we are part of
+	var childBranches int
+	err = tx.GetPrimitive(&childBranches, `SELECT count(*) FROM catalog_branches b 
+		JOIN catalog_branches b2 ON b.repository_id = b2.repository_id AND b2.id=$1
+		WHERE $1=ANY(b.lineage)`, branchID)
+	if err != nil {
+		return fmt.Errorf("dependent check: %w", err)
+	}
+	if childBranches > 0 {
+		return fmt.Errorf("branch has dependent branch: %w", catalog.ErrOperationNotPermitted)
+	}
+
+	// tags are immutable, they keep the commits of their branch
+	var tagged bool
+	err = tx.GetPrimitive(&tagged, `SELECT EXISTS (SELECT 1 FROM catalog_tags WHERE branch_id=$1)`, branchID)
+	if err != nil {
+		return fmt.Errorf("tags check: %w", err)
+	}
+	if tagged {
+		return fmt.Errorf("branch has tagged commits: %w", catalog.ErrOperationNotPermitted)
+	}
+
+	// delete branch entries
+	_, err = tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1`, branchID)
+	if err != nil {
+		return fmt.Errorf("delete entries: %w", err)
+	}
+
+	// delete branch
+	res, err := tx.Exec(`DELETE FROM catalog_branches WHERE id=$1`, branchID)
+	if err != nil {
+		return fmt.Errorf("delete branch: %w", err)
+	}
+	affected := res.RowsAffected()
+	if affected != 1 {
+		return catalog.ErrBranchNotFound
+	}
+	return nil
 }
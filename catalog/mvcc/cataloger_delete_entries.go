@@ -0,0 +1,109 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const DeleteEntriesMaxPaths = 10000
+
+// DeleteEntries deletes paths from branch in a single transaction.  The error at each index is
+// the result of deleting the path at that index: nil when deleted, or ErrEntryNotFound.  Any
+// other failure deletes none of the paths.
+func (c *cataloger) DeleteEntries(ctx context.Context, repository, branch string, paths []string) ([]error, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+	}); err != nil {
+		return nil, err
+	}
+	if len(paths) > DeleteEntriesMaxPaths {
+		return nil, fmt.Errorf("delete %d paths, at most %d allowed: %w", len(paths), DeleteEntriesMaxPaths, catalog.ErrInvalidValue)
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkLocks(ctx, tx, branchID, paths); err != nil {
+			return nil, err
+		}
+		if err := trashEntries(tx, repoID, branchID, paths); err != nil {
+			return nil, err
+		}
+		return deleteEntries(tx, branchID, paths)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	deleted := res.(map[string]bool)
+	errs := make([]error, len(paths))
+	for i, path := range paths {
+		if !deleted[path] {
+			errs[i] = catalog.ErrEntryNotFound
+		}
+	}
+	return errs, nil
+}
+
+// deleteEntries deletes paths from the uncommitted state of branchID as deleteEntry does, with
+// a fixed number of queries.  Returns the set of deleted paths.
+func deleteEntries(tx db.Tx, branchID int64, paths []string) (map[string]bool, error) {
+	var uncommittedPaths []string
+	err := tx.Select(&uncommittedPaths, `DELETE FROM catalog_entries
+		WHERE branch_id=$1 AND path=ANY($2::text[]) AND min_commit=$3 AND max_commit=$4
+		RETURNING path`,
+		branchID, paths, MinCommitUncommittedIndicator, MaxCommitID)
+	if err != nil {
+		return nil, fmt.Errorf("uncommitted: %w", err)
+	}
+
+	lineage, err := getLineage(tx, branchID, UncommittedID)
+	if err != nil {
+		return nil, fmt.Errorf("get lineage: %w", err)
+	}
+	sql, args, err := psql.
+		Select("path").
+		FromSelect(sqEntriesLineage(branchID, UncommittedID, lineage), "entries").
+		// Expired objects *can* be successfully deleted!
+		Where(sq.Eq{"path": paths, "is_deleted": false, "is_committed": true}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql: %w", err)
+	}
+	var committedPaths []string
+	if err := tx.Select(&committedPaths, sql, args...); err != nil {
+		return nil, fmt.Errorf("committed: %w", err)
+	}
+	if len(committedPaths) > 0 {
+		_, err = tx.Exec(`INSERT INTO catalog_entries (branch_id,path,physical_address,checksum,size,metadata,min_commit,max_commit)
+			SELECT $1,path,'','',0,'{}',$2,0 FROM unnest($3::text[]) AS path`,
+			branchID, MaxCommitID, committedPaths)
+		if err != nil {
+			return nil, fmt.Errorf("tombstones: %w", err)
+		}
+	}
+
+	deleted := make(map[string]bool, len(uncommittedPaths)+len(committedPaths))
+	for _, path := range uncommittedPaths {
+		deleted[path] = true
+	}
+	for _, path := range committedPaths {
+		deleted[path] = true
+	}
+	return deleted, nil
+}
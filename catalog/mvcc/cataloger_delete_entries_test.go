@@ -0,0 +1,55 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_DeleteEntries(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "committed", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "changed", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "kept", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "changed", nil, "seed1")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "uncommitted", nil, "")
+
+	paths := []string{"committed", "changed", "uncommitted", "no-such-path"}
+	errs, err := c.DeleteEntries(ctx, repository, "master", paths)
+	testutil.MustDo(t, "delete entries", err)
+	if len(errs) != len(paths) {
+		t.Fatalf("got %d results, expected %d", len(errs), len(paths))
+	}
+	for i, path := range paths[:3] {
+		if errs[i] != nil {
+			t.Errorf("delete %s err=%v, expected deleted", path, errs[i])
+		}
+		testCatalogerGetEntry(t, ctx, c, repository, "master", path, false)
+	}
+	if !errors.Is(errs[3], catalog.ErrEntryNotFound) {
+		t.Errorf("delete missing path err=%v, expected %s", errs[3], catalog.ErrEntryNotFound)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "kept", true)
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "committed", true)
+
+	// deleted paths are not found again
+	errs, err = c.DeleteEntries(ctx, repository, "master", []string{"committed", "uncommitted"})
+	testutil.MustDo(t, "delete entries again", err)
+	for i, err := range errs {
+		if !errors.Is(err, catalog.ErrEntryNotFound) {
+			t.Errorf("delete deleted path %d err=%v, expected %s", i, err, catalog.ErrEntryNotFound)
+		}
+	}
+
+	_, err = c.DeleteEntries(ctx, repository, "master", make([]string, DeleteEntriesMaxPaths+1))
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("delete too many paths err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+}
@@ -21,56 +21,77 @@ func (c *cataloger) DeleteEntry(ctx context.Context, repository, branch string,
 		return db.ErrNotFound
 	}
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
-		branchID, err := c.getBranchIDCache(tx, repository, branch)
-		if err != nil {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
 			return nil, err
 		}
-
-		// delete uncommitted entry, if found first
-		res, err := tx.Exec("DELETE FROM catalog_entries WHERE branch_id=$1 AND path=$2 AND min_commit=$3 AND max_commit=$4",
-			branchID, path, MinCommitUncommittedIndicator, MaxCommitID)
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
 		if err != nil {
-			return nil, fmt.Errorf("uncommitted: %w", err)
+			return nil, err
 		}
-		deletedUncommittedCount := res.RowsAffected()
-
-		// get uncommitted entry based on path
-		lineage, err := getLineage(tx, branchID, UncommittedID)
-		if err != nil {
-			return nil, fmt.Errorf("get lineage: %w", err)
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
 		}
-		sql, args, err := psql.
-			Select("is_committed").
-			FromSelect(sqEntriesLineage(branchID, UncommittedID, lineage), "entries").
-			// Expired objects *can* be successfully deleted!
-			Where(sq.Eq{"path": path, "is_deleted": false}).
-			ToSql()
+		repoID, err := c.getRepositoryIDCache(tx, repository)
 		if err != nil {
-			return nil, fmt.Errorf("build sql: %w", err)
-		}
-		var isCommitted bool
-		err = tx.GetPrimitive(&isCommitted, sql, args...)
-		committedNotFound := errors.Is(err, db.ErrNotFound)
-		if err != nil && !committedNotFound {
 			return nil, err
 		}
-		// 1. found committed record - add tombstone and return success
-		// 2. not found committed record:
-		//    - if we deleted uncommitted - return success
-		//    - if we didn't delete uncommitted - return not found
-		if isCommitted {
-			_, err = tx.Exec(`INSERT INTO catalog_entries (branch_id,path,physical_address,checksum,size,metadata,min_commit,max_commit)
-					VALUES ($1,$2,'','',0,'{}',$3,0)`,
-				branchID, path, MaxCommitID)
-			if err != nil {
-				return nil, fmt.Errorf("tombstone: %w", err)
-			}
-			return nil, nil
+		if err := checkLocks(ctx, tx, branchID, []string{path}); err != nil {
+			return nil, err
 		}
-		if deletedUncommittedCount == 0 {
-			return nil, catalog.ErrEntryNotFound
+		if err := trashEntries(tx, repoID, branchID, []string{path}); err != nil {
+			return nil, err
 		}
-		return nil, nil
+		return nil, deleteEntry(tx, branchID, path)
 	}, c.txOpts(ctx)...)
 	return err
 }
+
+// deleteEntry deletes path from the uncommitted state of branchID: an uncommitted entry is
+// removed, and a committed entry is hidden by a tombstone.
+func deleteEntry(tx db.Tx, branchID int64, path string) error {
+	// delete uncommitted entry, if found first
+	res, err := tx.Exec("DELETE FROM catalog_entries WHERE branch_id=$1 AND path=$2 AND min_commit=$3 AND max_commit=$4",
+		branchID, path, MinCommitUncommittedIndicator, MaxCommitID)
+	if err != nil {
+		return fmt.Errorf("uncommitted: %w", err)
+	}
+	deletedUncommittedCount := res.RowsAffected()
+
+	// get uncommitted entry based on path
+	lineage, err := getLineage(tx, branchID, UncommittedID)
+	if err != nil {
+		return fmt.Errorf("get lineage: %w", err)
+	}
+	sql, args, err := psql.
+		Select("is_committed").
+		FromSelect(sqEntriesLineage(branchID, UncommittedID, lineage), "entries").
+		// Expired objects *can* be successfully deleted!
+		Where(sq.Eq{"path": path, "is_deleted": false}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build sql: %w", err)
+	}
+	var isCommitted bool
+	err = tx.GetPrimitive(&isCommitted, sql, args...)
+	committedNotFound := errors.Is(err, db.ErrNotFound)
+	if err != nil && !committedNotFound {
+		return err
+	}
+	// 1. found committed record - add tombstone and return success
+	// 2. not found committed record:
+	//    - if we deleted uncommitted - return success
+	//    - if we didn't delete uncommitted - return not found
+	if isCommitted {
+		_, err = tx.Exec(`INSERT INTO catalog_entries (branch_id,path,physical_address,checksum,size,metadata,min_commit,max_commit)
+				VALUES ($1,$2,'','',0,'{}',$3,0)`,
+			branchID, path, MaxCommitID)
+		if err != nil {
+			return fmt.Errorf("tombstone: %w", err)
+		}
+		return nil
+	}
+	if deletedUncommittedCount == 0 {
+		return catalog.ErrEntryNotFound
+	}
+	return nil
+}
@@ -41,7 +41,7 @@ func TestCataloger_DeleteEntry(t *testing.T) {
 		testDeleteEntryExpectNotFound(t, ctx, c, repository, "master", "/file2")
 
 		// if we try to commit we should fail - there was no change
-		_, err = c.Commit(ctx, repository, "master", "commit nothing", "tester", nil)
+		_, err = c.Commit(ctx, repository, "master", "commit nothing", "tester", nil, catalog.CommitParams{})
 		if !errors.Is(err, catalog.ErrNothingToCommit) {
 			t.Fatalf("Commit returned err=%s, expected=%s", err, catalog.ErrNothingToCommit)
 		}
@@ -57,7 +57,7 @@ func TestCataloger_DeleteEntry(t *testing.T) {
 		}, catalog.CreateEntryParams{}); err != nil {
 			t.Fatal("create entry for delete entry test:", err)
 		}
-		if _, err := c.Commit(ctx, repository, "master", "commit file3", "tester", nil); err != nil {
+		if _, err := c.Commit(ctx, repository, "master", "commit file3", "tester", nil, catalog.CommitParams{}); err != nil {
 			t.Fatal("Commit entry for delete entry test:", err)
 		}
 		err := c.DeleteEntry(ctx, repository, "master", "/file3")
@@ -79,7 +79,7 @@ func TestCataloger_DeleteEntry(t *testing.T) {
 		}, catalog.CreateEntryParams{}); err != nil {
 			t.Fatal("create entry for delete entry test:", err)
 		}
-		if _, err := c.Commit(ctx, repository, "master", "commit file4", "tester", nil); err != nil {
+		if _, err := c.Commit(ctx, repository, "master", "commit file4", "tester", nil, catalog.CommitParams{}); err != nil {
 			t.Fatal("Commit entry for delete entry test:", err)
 		}
 		if _, err := c.CreateBranch(ctx, repository, "b1", "master"); err != nil {
@@ -110,7 +110,7 @@ func testDeleteEntryExpectNotFound(t *testing.T, ctx context.Context, c catalog.
 }
 
 func testDeleteEntryCommitAndExpectNotFound(t *testing.T, ctx context.Context, c catalog.Cataloger, repository, branch string, path string) {
-	_, err := c.Commit(ctx, repository, branch, "commit before expect not found "+path, "tester", nil)
+	_, err := c.Commit(ctx, repository, branch, "commit before expect not found "+path, "tester", nil, catalog.CommitParams{})
 	if err != nil {
 		t.Fatal("Failed to commit before expect not found:", err)
 	}
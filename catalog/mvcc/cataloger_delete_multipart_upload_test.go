@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/treeverse/lakefs/catalog"
 )
 
 func TestCataloger_DeleteMultipartUpload(t *testing.T) {
@@ -14,7 +16,7 @@ func TestCataloger_DeleteMultipartUpload(t *testing.T) {
 	if _, err := c.CreateRepository(ctx, "repo1", "s3://bucket1", "master"); err != nil {
 		t.Fatal("create repository for testing", err)
 	}
-	if err := c.CreateMultipartUpload(ctx, "repo1", "uploadX", "/pathX", "/fileX", time.Now()); err != nil {
+	if err := c.CreateMultipartUpload(ctx, "repo1", "uploadX", "/pathX", "/fileX", time.Now(), catalog.CreateMultipartUploadParams{}); err != nil {
 		t.Fatal("create multipart upload for testing", err)
 	}
 
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 	"github.com/treeverse/lakefs/testutil"
 )
@@ -27,7 +28,7 @@ func TestCataloger_DeleteRepository(t *testing.T) {
 			p := "ent" + strconv.Itoa(j)
 			testCatalogerCreateEntry(t, ctx, c, repoName, branchName, p, nil, branchName)
 		}
-		_, err := c.Commit(ctx, repoName, branchName, "commit changes", "tester", nil)
+		_, err := c.Commit(ctx, repoName, branchName, "commit changes", "tester", nil, catalog.CommitParams{})
 		testutil.MustDo(t, "commit changes", err)
 	}
 	tests := []struct {
@@ -21,11 +21,11 @@ func (c *cataloger) Diff(ctx context.Context, repository string, leftReference s
 	}
 
 	// parse references
-	leftRef, err := ParseRef(leftReference)
+	leftRef, err := c.resolveRef(c.db.WithContext(ctx), repository, leftReference)
 	if err != nil {
 		return nil, false, fmt.Errorf("left reference: %w", err)
 	}
-	rightRef, err := ParseRef(rightReference)
+	rightRef, err := c.resolveRef(c.db.WithContext(ctx), repository, rightReference)
 	if err != nil {
 		return nil, false, fmt.Errorf("right reference: %w", err)
 	}
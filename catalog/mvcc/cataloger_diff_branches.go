@@ -0,0 +1,86 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// DiffBranches compares the entries of leftBranch to those of rightBranch, both including their
+// uncommitted changes.  An entry is added when found only on leftBranch, removed when found
+// only on rightBranch and changed when found on both with different content.
+func (c *cataloger) DiffBranches(ctx context.Context, repository, leftBranch, rightBranch string, params catalog.DiffParams) (catalog.Differences, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "leftBranch", IsValid: ValidateBranchName(leftBranch)},
+		{Name: "rightBranch", IsValid: ValidateBranchName(rightBranch)},
+	}); err != nil {
+		return nil, false, err
+	}
+	if params.Limit < 0 || params.Limit > DiffMaxLimit {
+		params.Limit = DiffMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		leftBranchID, err := c.getBranchIDCache(tx, repository, leftBranch)
+		if err != nil {
+			return nil, fmt.Errorf("left branch: %w", err)
+		}
+		rightBranchID, err := c.getBranchIDCache(tx, repository, rightBranch)
+		if err != nil {
+			return nil, fmt.Errorf("right branch: %w", err)
+		}
+		scannerOpts := DBLineageScannerOptions{
+			DBScannerOptions: DBScannerOptions{
+				After:            params.After,
+				AdditionalFields: params.AdditionalFields,
+			},
+		}
+		leftScanner := NewDBLineageScanner(tx, leftBranchID, UncommittedID, scannerOpts)
+		rightScanner := NewDBLineageScanner(tx, rightBranchID, UncommittedID, scannerOpts)
+		// we read an additional difference (without returning it) for pagination (hasMore)
+		differences := make(catalog.Differences, 0, params.Limit+1)
+		left := nextExistingEntry(leftScanner)
+		right := nextExistingEntry(rightScanner)
+		for (left != nil || right != nil) && len(differences) <= params.Limit {
+			switch {
+			case right == nil || (left != nil && left.Path < right.Path):
+				differences = append(differences, catalog.Difference{Type: catalog.DifferenceTypeAdded, Entry: left.Entry})
+				left = nextExistingEntry(leftScanner)
+			case left == nil || right.Path < left.Path:
+				differences = append(differences, catalog.Difference{Type: catalog.DifferenceTypeRemoved, Entry: right.Entry})
+				right = nextExistingEntry(rightScanner)
+			default:
+				if left.Checksum != right.Checksum {
+					differences = append(differences, catalog.Difference{Type: catalog.DifferenceTypeChanged, Entry: left.Entry})
+				}
+				left = nextExistingEntry(leftScanner)
+				right = nextExistingEntry(rightScanner)
+			}
+		}
+		if err := leftScanner.Err(); err != nil {
+			return nil, fmt.Errorf("scan left branch: %w", err)
+		}
+		if err := rightScanner.Err(); err != nil {
+			return nil, fmt.Errorf("scan right branch: %w", err)
+		}
+		return differences, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	differences := res.(catalog.Differences)
+	hasMore := paginateSlice(&differences, params.Limit)
+	return differences, hasMore, nil
+}
+
+// nextExistingEntry returns the next entry of scanner that was not deleted, nil when scanner ends
+func nextExistingEntry(scanner DBScanner) *DBScannerEntry {
+	for scanner.Next() {
+		if entry := scanner.Value(); !entry.IsDeleted() {
+			return entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_DiffBranches(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	for _, path := range []string{"same", "changed", "removed", "deleted-on-branch"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", path, nil, "")
+	}
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	_, err = c.CreateBranch(ctx, repository, "branch1", "master")
+	testutil.MustDo(t, "create branch", err)
+
+	// committed and uncommitted changes on both branches
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "added-committed", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "commit to branch1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "added-uncommitted", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "changed", nil, "branch1")
+	testutil.MustDo(t, "delete on branch1", c.DeleteEntry(ctx, repository, "branch1", "deleted-on-branch"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "master-only", nil, "")
+
+	expected := catalog.Differences{
+		{Type: catalog.DifferenceTypeAdded, Entry: catalog.Entry{Path: "added-committed"}},
+		{Type: catalog.DifferenceTypeAdded, Entry: catalog.Entry{Path: "added-uncommitted"}},
+		{Type: catalog.DifferenceTypeChanged, Entry: catalog.Entry{Path: "changed"}},
+		{Type: catalog.DifferenceTypeRemoved, Entry: catalog.Entry{Path: "deleted-on-branch"}},
+		{Type: catalog.DifferenceTypeRemoved, Entry: catalog.Entry{Path: "master-only"}},
+	}
+	differences, hasMore, err := c.DiffBranches(ctx, repository, "branch1", "master", catalog.DiffParams{Limit: -1})
+	testutil.MustDo(t, "diff branches", err)
+	if hasMore {
+		t.Error("DiffBranches() has more should be false")
+	}
+	paths := func(differences catalog.Differences) catalog.Differences {
+		res := make(catalog.Differences, len(differences))
+		for i, d := range differences {
+			res[i] = catalog.Difference{Type: d.Type, Entry: catalog.Entry{Path: d.Path}}
+		}
+		return res
+	}
+	if diff := deep.Equal(paths(differences), expected); diff != nil {
+		t.Error("DiffBranches", diff)
+	}
+
+	// paginate
+	var paged catalog.Differences
+	after := ""
+	for {
+		res, hasMore, err := c.DiffBranches(ctx, repository, "branch1", "master", catalog.DiffParams{Limit: 2, After: after})
+		testutil.MustDo(t, "diff branches page", err)
+		paged = append(paged, res...)
+		if !hasMore {
+			break
+		}
+		after = res[len(res)-1].Path
+	}
+	if diff := deep.Equal(paths(paged), expected); diff != nil {
+		t.Error("DiffBranches paginated", diff)
+	}
+
+	if _, _, err := c.DiffBranches(ctx, repository, "branch1", "missing", catalog.DiffParams{}); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("DiffBranches() with missing branch err=%v, expected %s", err, db.ErrNotFound)
+	}
+}
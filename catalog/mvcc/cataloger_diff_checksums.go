@@ -0,0 +1,76 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) DiffChecksums(ctx context.Context, repository, leftReference string, rightReference string) (*catalog.ChecksumDiffSummary, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "leftReference", IsValid: ValidateReference(leftReference)},
+		{Name: "rightReference", IsValid: ValidateReference(rightReference)},
+	}); err != nil {
+		return nil, err
+	}
+	leftRef, err := c.resolveRef(c.db.WithContext(ctx), repository, leftReference)
+	if err != nil {
+		return nil, fmt.Errorf("left reference: %w", err)
+	}
+	rightRef, err := c.resolveRef(c.db.WithContext(ctx), repository, rightReference)
+	if err != nil {
+		return nil, fmt.Errorf("right reference: %w", err)
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		left, err := c.sqChecksumsLineage(tx, repository, leftRef)
+		if err != nil {
+			return nil, fmt.Errorf("left reference: %w", err)
+		}
+		right, err := c.sqChecksumsLineage(tx, repository, rightRef)
+		if err != nil {
+			return nil, fmt.Errorf("right reference: %w", err)
+		}
+		// only paths and checksums are compared, the entries themselves are never returned
+		diffSQL, args, err := psql.
+			Select("COUNT(*) FILTER (WHERE r.path IS NULL) AS added",
+				"COUNT(*) FILTER (WHERE l.path IS NULL) AS removed",
+				"COUNT(*) FILTER (WHERE l.path IS NOT NULL AND r.path IS NOT NULL) AS changed",
+				"COALESCE(SUM(COALESCE(l.size, 0) - COALESCE(r.size, 0)), 0)::bigint AS bytes_delta").
+			FromSelect(left, "l").
+			JoinClause(right.Prefix("FULL OUTER JOIN (").Suffix(") AS r ON l.path = r.path")).
+			Where("l.path IS NULL OR r.path IS NULL OR l.checksum <> r.checksum").
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var summary catalog.ChecksumDiffSummary
+		if err := tx.Get(&summary, diffSQL, args...); err != nil {
+			return nil, fmt.Errorf("count differences: %w", err)
+		}
+		return &summary, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.ChecksumDiffSummary), nil
+}
+
+// sqChecksumsLineage selects the path, checksum and size of the entries read from ref
+func (c *cataloger) sqChecksumsLineage(tx db.Tx, repository string, ref *Ref) (sq.SelectBuilder, error) {
+	branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+	if err != nil {
+		return sq.SelectBuilder{}, err
+	}
+	lineage, err := getLineage(tx, branchID, ref.CommitID)
+	if err != nil {
+		return sq.SelectBuilder{}, fmt.Errorf("get lineage: %w", err)
+	}
+	return sq.Select("path", "checksum", "size").
+		FromSelect(sqEntriesLineage(branchID, ref.CommitID, lineage), "entries").
+		Where(sq.Eq{"is_deleted": false}), nil
+}
@@ -0,0 +1,95 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_DiffChecksums(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	createEntry := func(branch, path, checksum string, size int64) {
+		testutil.MustDo(t, "create entry "+path, c.CreateEntry(ctx, repository, branch, catalog.Entry{
+			Path:            path,
+			Checksum:        checksum,
+			PhysicalAddress: branch + "_" + path,
+			Size:            size,
+		}, catalog.CreateEntryParams{}))
+	}
+	createEntry("master", "file1", "c1", 1)
+	createEntry("master", "file2", "c2", 2)
+	createEntry("master", "file3", "c3", 4)
+	_, err := c.Commit(ctx, repository, "master", "add files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	// same content at a new physical address is not a change
+	createEntry("branch1", "file1", "c1", 1)
+	createEntry("branch1", "file2", "c2-changed", 8)
+	testutil.MustDo(t, "delete file3", c.DeleteEntry(ctx, repository, "branch1", "file3"))
+	_, err = c.Commit(ctx, repository, "branch1", "change files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	createEntry("branch1", "file4", "c4", 16)
+
+	tests := []struct {
+		name    string
+		left    string
+		right   string
+		want    catalog.ChecksumDiffSummary
+		wantErr error
+	}{
+		{
+			name:  "same",
+			left:  "master",
+			right: "master",
+			want:  catalog.ChecksumDiffSummary{},
+		},
+		{
+			name:  "uncommitted",
+			left:  "branch1",
+			right: "master",
+			want:  catalog.ChecksumDiffSummary{Added: 1, Removed: 1, Changed: 1, BytesDelta: 16 + 8 - 2 - 4},
+		},
+		{
+			name:  "committed",
+			left:  "branch1:HEAD",
+			right: "master",
+			want:  catalog.ChecksumDiffSummary{Removed: 1, Changed: 1, BytesDelta: 8 - 2 - 4},
+		},
+		{
+			name:  "reversed",
+			left:  "master",
+			right: "branch1:HEAD",
+			want:  catalog.ChecksumDiffSummary{Added: 1, Changed: 1, BytesDelta: 2 + 4 - 8},
+		},
+		{
+			name:    "missing reference",
+			left:    "master",
+			right:   "no-branch",
+			wantErr: db.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.DiffChecksums(ctx, repository, tt.left, tt.right)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DiffChecksums() err=%v, expected %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if *got != tt.want {
+				t.Errorf("DiffChecksums() got %+v, expected %+v", got, tt.want)
+			}
+			if got.Identical() != (tt.want == catalog.ChecksumDiffSummary{}) {
+				t.Errorf("DiffChecksums() identical %t for %+v", got.Identical(), got)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// diffCountsBranch is a branch read by a diff counted in SQL, the counterpart of a
+// DBBranchScanner reading the branch at CommitID changes after MinCommitID
+type diffCountsBranch struct {
+	BranchID    int64
+	CommitID    CommitID
+	MinCommitID CommitID
+}
+
+// diffCountsLineage returns the branches a DBLineageScanner of branchID at commitID reads, in
+// precedence order.  minCommits holds the commit of each branch to read changes after, nil
+// reads all of them.
+func diffCountsLineage(branchID int64, commitID CommitID, lineage []lineageCommit, minCommits []CommitID) ([]diffCountsBranch, error) {
+	if minCommits != nil && len(minCommits) != len(lineage)+1 {
+		return nil, ErrMinCommitsMismatch
+	}
+	minCommitAt := func(i int) CommitID {
+		if minCommits == nil {
+			return 1
+		}
+		return minCommits[i]
+	}
+	branches := make([]diffCountsBranch, 0, len(lineage)+1)
+	branches = append(branches, diffCountsBranch{BranchID: branchID, CommitID: commitID, MinCommitID: minCommitAt(0)})
+	for i, l := range lineage {
+		branches = append(branches, diffCountsBranch{BranchID: l.BranchID, CommitID: l.CommitID, MinCommitID: minCommitAt(i + 1)})
+	}
+	return branches, nil
+}
+
+// diffCountsEntriesSQL selects for each path the entry a lineage scanner of branches returns:
+// the last entry of the first branch holding the path that changed in the commits read from
+// it.  Entries deleted after a specific commit are read as not deleted, like the scanners do.
+func diffCountsEntriesSQL(branches []diffCountsBranch) string {
+	values := make([]string, len(branches))
+	for i, b := range branches {
+		// entries of an uncommitted branch are all read, committed branches read entries
+		// created or deleted by their commits after the min commit
+		minLow, maxLow, high, align := CommitID(0), CommitID(0), MaxCommitID, CommitID(0)
+		switch b.CommitID {
+		case UncommittedID:
+		case CommittedID:
+			minLow, maxLow, high = b.MinCommitID+1, b.MinCommitID, MaxCommitID-1
+		default:
+			minLow, maxLow, high, align = b.MinCommitID+1, b.MinCommitID, b.CommitID, b.CommitID
+		}
+		values[i] = fmt.Sprintf("(%d,%d::bigint,%d::bigint,%d::bigint,%d::bigint,%d::bigint)", i, b.BranchID, minLow, maxLow, high, align)
+	}
+	maxCommit := strconv.FormatInt(int64(MaxCommitID), 10)
+	return `SELECT DISTINCT ON (e.path) e.path, e.branch_id, e.min_commit, e.checksum, e.size,
+			CASE WHEN s.align > 0 AND e.max_commit >= s.align THEN ` + maxCommit + ` ELSE e.max_commit END AS max_commit
+		FROM catalog_entries e
+		JOIN (VALUES ` + strings.Join(values, ",") + `) AS s(precedence,branch_id,min_low,max_low,high,align)
+			ON e.branch_id = s.branch_id
+		WHERE e.min_commit BETWEEN s.min_low AND s.high OR e.max_commit BETWEEN s.max_low AND s.high
+		ORDER BY e.path, s.precedence, e.min_commit DESC`
+}
+
+// diffCountsTypeSQL returns the SQL evaluating the difference type of left entry l and its
+// matching right entry r, if any, NULL when they do not differ.  conflict is the condition of
+// a conflict between the entries, empty for diffs that have no conflicts.
+func diffCountsTypeSQL(conflict string) string {
+	maxCommit := strconv.FormatInt(int64(MaxCommitID), 10)
+	leftDeleted := "l.max_commit <> " + maxCommit
+	rightDeleted := "r.max_commit <> " + maxCommit
+	typeSQL := `CASE WHEN ` + leftDeleted + ` AND (r.path IS NULL OR ` + rightDeleted + `) THEN NULL
+		WHEN r.path IS NOT NULL AND (` + leftDeleted + `) = (` + rightDeleted + `) AND l.checksum = r.checksum THEN NULL`
+	if conflict != "" {
+		typeSQL += `
+		WHEN ` + conflict + ` THEN ` + strconv.Itoa(int(catalog.DifferenceTypeConflict))
+	}
+	return typeSQL + `
+		WHEN ` + leftDeleted + ` THEN ` + strconv.Itoa(int(catalog.DifferenceTypeRemoved)) + `
+		WHEN r.path IS NULL OR ` + rightDeleted + ` THEN ` + strconv.Itoa(int(catalog.DifferenceTypeAdded)) + `
+		ELSE ` + strconv.Itoa(int(catalog.DifferenceTypeChanged)) + ` END`
+}
+
+// diffCountsSQL returns a query of the differences between the entries of left and right
+// branches, with path, diff_type, left_size and right_size columns and a NULL diff_type for
+// paths without differences.  right_size is 0 when the path has no right entry.
+func diffCountsSQL(left, right []diffCountsBranch, conflict string) string {
+	maxCommit := strconv.FormatInt(int64(MaxCommitID), 10)
+	return `WITH l AS (` + diffCountsEntriesSQL(left) + `),
+			r AS (` + diffCountsEntriesSQL(right) + `)
+		SELECT l.path, ` + diffCountsTypeSQL(conflict) + ` AS diff_type,
+			l.size AS left_size,
+			CASE WHEN r.path IS NOT NULL AND r.max_commit = ` + maxCommit + ` THEN r.size ELSE 0 END AS right_size
+		FROM l LEFT JOIN r ON l.path = r.path`
+}
+
+// diffCountsBranches returns the branches read and the conflict condition of the diff of
+// params, as NewDiffScanner does for the relation of its references
+func diffCountsBranches(tx db.Tx, params doDiffParams) ([]diffCountsBranch, []diffCountsBranch, string, error) {
+	relation, err := getRefsRelationType(tx, params)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	switch relation {
+	case RelationTypeSame:
+		left, right, err := diffCountsSameBranch(tx, params)
+		return left, right, "", err
+	case RelationTypeFromParent:
+		return diffCountsFromParent(tx, params)
+	case RelationTypeFromChild:
+		return diffCountsFromChild(tx, params)
+	case RelationTypeNotDirect:
+		return nil, nil, "", catalog.ErrNonDirectNotSupported
+	default:
+		return nil, nil, "", catalog.ErrFeatureNotSupported
+	}
+}
+
+// selectDiffCounts counts the differences selected by diffSQL, a query of path and diff_type
+// columns with a NULL diff_type for paths without differences
+func selectDiffCounts(tx db.Tx, diffSQL string, args ...interface{}) (*catalog.DiffCounts, error) {
+	query := `SELECT CASE WHEN strpos(d.path, '/') > 0 THEN split_part(d.path, '/', 1) || '/' ELSE '' END AS prefix,
+			COUNT(*) FILTER (WHERE d.diff_type = ` + strconv.Itoa(int(catalog.DifferenceTypeAdded)) + `) AS added,
+			COUNT(*) FILTER (WHERE d.diff_type = ` + strconv.Itoa(int(catalog.DifferenceTypeRemoved)) + `) AS removed,
+			COUNT(*) FILTER (WHERE d.diff_type = ` + strconv.Itoa(int(catalog.DifferenceTypeChanged)) + `) AS changed,
+			COUNT(*) FILTER (WHERE d.diff_type = ` + strconv.Itoa(int(catalog.DifferenceTypeConflict)) + `) AS conflicts
+		FROM (` + diffSQL + `) d
+		WHERE d.diff_type IS NOT NULL
+		GROUP BY 1
+		ORDER BY 1`
+	var prefixes []catalog.DiffPrefixCounts
+	if err := tx.Select(&prefixes, query, args...); err != nil {
+		return nil, fmt.Errorf("count differences: %w", err)
+	}
+	counts := &catalog.DiffCounts{Prefixes: prefixes}
+	for _, p := range prefixes {
+		counts.Added += p.Added
+		counts.Removed += p.Removed
+		counts.Changed += p.Changed
+		counts.Conflicts += p.Conflicts
+	}
+	return counts, nil
+}
+
+func (c *cataloger) DiffCounts(ctx context.Context, repository, leftReference string, rightReference string) (*catalog.DiffCounts, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "leftReference", IsValid: ValidateReference(leftReference)},
+		{Name: "rightReference", IsValid: ValidateReference(rightReference)},
+	}); err != nil {
+		return nil, err
+	}
+	leftRef, err := c.resolveRef(c.db.WithContext(ctx), repository, leftReference)
+	if err != nil {
+		return nil, fmt.Errorf("left reference: %w", err)
+	}
+	rightRef, err := c.resolveRef(c.db.WithContext(ctx), repository, rightReference)
+	if err != nil {
+		return nil, fmt.Errorf("right reference: %w", err)
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		leftBranchID, err := c.getBranchIDCache(tx, repository, leftRef.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("left ref branch: %w", err)
+		}
+		rightBranchID, err := c.getBranchIDCache(tx, repository, rightRef.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("right ref branch: %w", err)
+		}
+		params := doDiffParams{
+			Repository:    repository,
+			LeftCommitID:  leftRef.CommitID,
+			LeftBranchID:  leftBranchID,
+			RightCommitID: rightRef.CommitID,
+			RightBranchID: rightBranchID,
+		}
+		left, right, conflict, err := diffCountsBranches(tx, params)
+		if err != nil {
+			return nil, err
+		}
+		return selectDiffCounts(tx, diffCountsSQL(left, right, conflict))
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.DiffCounts), nil
+}
+
+// diffCountsSameBranch returns the branches read for a diff between references of the same
+// branch, as DiffScanner.diffSameBranch does
+func diffCountsSameBranch(tx db.Tx, params doDiffParams) ([]diffCountsBranch, []diffCountsBranch, error) {
+	leftLineage, err := getLineage(tx, params.LeftBranchID, params.LeftCommitID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get left branch lineage: %w", err)
+	}
+	rightLineage, err := getLineage(tx, params.RightBranchID, params.RightCommitID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get right branch lineage: %w", err)
+	}
+	left, err := diffCountsLineage(params.LeftBranchID, params.LeftCommitID, leftLineage, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	right, err := diffCountsLineage(params.RightBranchID, params.RightCommitID, rightLineage, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return left, right, nil
+}
+
+// diffCountsFromParent returns the branches read and the conflict condition of a diff from a
+// parent to its child, as DiffScanner.diffFromParent does
+func diffCountsFromParent(tx db.Tx, params doDiffParams) ([]diffCountsBranch, []diffCountsBranch, string, error) {
+	var childLastFromParentCommitID CommitID
+	err := tx.Get(&childLastFromParentCommitID,
+		`SELECT MAX(commit_id) as max_child_commit FROM catalog_commits WHERE branch_id = $1 AND merge_type = 'from_parent'`,
+		params.RightBranchID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("get child last commit: %w", err)
+	}
+	rightLineage, err := getLineage(tx, params.RightBranchID, UncommittedID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("get right branch lineage: %w", err)
+	}
+	leftLineage, err := getLineage(tx, params.LeftBranchID, CommittedID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("get left branch lineage: %w", err)
+	}
+	if len(rightLineage)-len(leftLineage) != 1 || len(rightLineage) == 0 {
+		return nil, nil, "", catalog.ErrLineageCorrupted
+	}
+	minMinCommit := []CommitID{rightLineage[0].CommitID}
+	for i := range leftLineage {
+		if leftLineage[i].CommitID == rightLineage[i+1].CommitID {
+			leftLineage = leftLineage[:i]
+			break
+		}
+		minMinCommit = append(minMinCommit, rightLineage[i+1].CommitID)
+	}
+	left, err := diffCountsLineage(params.LeftBranchID, CommittedID, leftLineage, minMinCommit)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	right, err := diffCountsLineage(params.RightBranchID, UncommittedID, rightLineage, nil)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	// the child entry is uncommitted or changed after the child last merged from its parent
+	conflict := fmt.Sprintf("r.branch_id = %d AND (r.min_commit = %d OR r.min_commit > %d OR (r.max_commit <> %d AND r.max_commit >= %d))",
+		params.RightBranchID, MinCommitUncommittedIndicator, childLastFromParentCommitID, MaxCommitID, childLastFromParentCommitID)
+	return left, right, conflict, nil
+}
+
+// diffCountsFromChild returns the branches read and the conflict condition of a diff from a
+// child to its parent, as DiffScanner.diffFromChild does
+func diffCountsFromChild(tx db.Tx, params doDiffParams) ([]diffCountsBranch, []diffCountsBranch, string, error) {
+	effectiveCommits, err := selectChildEffectiveCommits(tx, params.LeftBranchID, params.RightBranchID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	rightLineage, err := getLineage(tx, params.RightBranchID, UncommittedID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("get right branch lineage: %w", err)
+	}
+	left := []diffCountsBranch{{BranchID: params.LeftBranchID, CommitID: CommittedID, MinCommitID: 1}}
+	right, err := diffCountsLineage(params.RightBranchID, UncommittedID, rightLineage, nil)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	// the parent entry changed after the parent effective commit of its branch
+	effectiveCommit := "CASE r.branch_id"
+	for _, l := range effectiveCommits.ParentEffectiveLineage {
+		effectiveCommit += fmt.Sprintf(" WHEN %d THEN %d", l.BranchID, l.CommitID)
+	}
+	effectiveCommit += fmt.Sprintf(" ELSE %d END", effectiveCommits.ParentEffectiveCommit)
+	conflict := "r.path IS NOT NULL AND " + effectiveCommit + " > 0 AND r.min_commit > " + effectiveCommit
+	return left, right, conflict, nil
+}
+
+func (c *cataloger) DiffUncommittedCounts(ctx context.Context, repository, branch string) (*catalog.DiffCounts, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		lineage, err := getLineage(tx, branchID, CommittedID)
+		if err != nil {
+			return nil, fmt.Errorf("get lineage: %w", err)
+		}
+		diffSQL, args, err := sqDiffUncommitted(branchID, lineage).ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		return selectDiffCounts(tx, diffSQL, args...)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.DiffCounts), nil
+}
@@ -0,0 +1,148 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+// testDiffCountsOf counts differences the way DiffCounts does
+func testDiffCountsOf(differences catalog.Differences) *catalog.DiffCounts {
+	counts := &catalog.DiffCounts{}
+	var prefix *catalog.DiffPrefixCounts
+	for _, d := range differences {
+		var p string
+		if i := strings.Index(d.Path, "/"); i >= 0 {
+			p = d.Path[:i+1]
+		}
+		if prefix == nil || prefix.Prefix != p {
+			counts.Prefixes = append(counts.Prefixes, catalog.DiffPrefixCounts{Prefix: p})
+			prefix = &counts.Prefixes[len(counts.Prefixes)-1]
+		}
+		switch d.Type {
+		case catalog.DifferenceTypeAdded:
+			counts.Added++
+			prefix.Added++
+		case catalog.DifferenceTypeRemoved:
+			counts.Removed++
+			prefix.Removed++
+		case catalog.DifferenceTypeChanged:
+			counts.Changed++
+			prefix.Changed++
+		case catalog.DifferenceTypeConflict:
+			counts.Conflicts++
+			prefix.Conflicts++
+		}
+	}
+	return counts
+}
+
+func TestCataloger_DiffCounts(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	createEntry := func(branch, path, seed string) {
+		testutil.MustDo(t, "create entry "+path, c.CreateEntry(ctx, repository, branch, catalog.Entry{
+			Path:            path,
+			Checksum:        seed + path,
+			PhysicalAddress: seed + path,
+			Size:            1,
+		}, catalog.CreateEntryParams{}))
+	}
+	for _, p := range []string{"a/1", "a/2", "b/1", "b/2", "c"} {
+		createEntry("master", p, "master")
+	}
+	_, err := c.Commit(ctx, repository, "master", "add files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	masterCommit, err := c.GetBranchReference(ctx, repository, "master")
+	testutil.MustDo(t, "get master reference", err)
+
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	createEntry("branch1", "a/1", "branch1")
+	createEntry("branch1", "a/3", "branch1")
+	testutil.MustDo(t, "delete b/1", c.DeleteEntry(ctx, repository, "branch1", "b/1"))
+	createEntry("branch1", "b/2", "branch1")
+	createEntry("branch1", "d", "branch1")
+	_, err = c.Commit(ctx, repository, "branch1", "change files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+
+	// conflicting change and uncommitted changes on master
+	createEntry("master", "b/2", "master2")
+	_, err = c.Commit(ctx, repository, "master", "change b/2", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	createEntry("master", "a/4", "master")
+	createEntry("master", "c", "master2")
+	testutil.MustDo(t, "delete a/2", c.DeleteEntry(ctx, repository, "master", "a/2"))
+
+	t.Run("matches diff", func(t *testing.T) {
+		refs := []struct{ left, right string }{
+			{"branch1", "master"},
+			{"master", "branch1"},
+			{"master:HEAD", "branch1"},
+			{"master", "master:HEAD"},
+			{"master:HEAD", masterCommit},
+			{"master", masterCommit},
+		}
+		for _, ref := range refs {
+			differences, _, err := c.Diff(ctx, repository, ref.left, ref.right, catalog.DiffParams{Limit: -1})
+			testutil.MustDo(t, "diff "+ref.left+" "+ref.right, err)
+			counts, err := c.DiffCounts(ctx, repository, ref.left, ref.right)
+			if err != nil {
+				t.Fatalf("DiffCounts(%s, %s) unexpected error: %s", ref.left, ref.right, err)
+			}
+			if want := testDiffCountsOf(differences); !reflect.DeepEqual(counts, want) {
+				t.Fatalf("DiffCounts(%s, %s) got %+v, expected %+v from differences %s", ref.left, ref.right, counts, want, differences)
+			}
+		}
+	})
+
+	t.Run("child to parent", func(t *testing.T) {
+		counts, err := c.DiffCounts(ctx, repository, "branch1", "master")
+		testutil.MustDo(t, "diff counts", err)
+		want := &catalog.DiffCounts{
+			Added:     2,
+			Removed:   1,
+			Changed:   1,
+			Conflicts: 1,
+			Prefixes: []catalog.DiffPrefixCounts{
+				{Prefix: "", Added: 1},
+				{Prefix: "a/", Added: 1, Changed: 1},
+				{Prefix: "b/", Removed: 1, Conflicts: 1},
+			},
+		}
+		if !reflect.DeepEqual(counts, want) {
+			t.Fatalf("DiffCounts() got %+v, expected %+v", counts, want)
+		}
+	})
+
+	t.Run("uncommitted", func(t *testing.T) {
+		differences, _, err := c.DiffUncommitted(ctx, repository, "master", "", -1, "")
+		testutil.MustDo(t, "diff uncommitted", err)
+		counts, err := c.DiffUncommittedCounts(ctx, repository, "master")
+		testutil.MustDo(t, "diff uncommitted counts", err)
+		want := &catalog.DiffCounts{
+			Added:   1,
+			Removed: 1,
+			Changed: 1,
+			Prefixes: []catalog.DiffPrefixCounts{
+				{Prefix: "", Changed: 1},
+				{Prefix: "a/", Added: 1, Removed: 1},
+			},
+		}
+		if !reflect.DeepEqual(counts, want) || !reflect.DeepEqual(testDiffCountsOf(differences), want) {
+			t.Fatalf("DiffUncommittedCounts() got %+v, expected %+v", counts, want)
+		}
+	})
+
+	t.Run("missing branch", func(t *testing.T) {
+		_, err := c.DiffCounts(ctx, repository, "branch2", "master")
+		if !errors.Is(err, catalog.ErrBranchNotFound) {
+			t.Fatalf("DiffCounts() err=%v, expected=%s", err, catalog.ErrBranchNotFound)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) DiffSummary(ctx context.Context, repository, leftReference string, rightReference string) (*catalog.DiffSummary, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "leftReference", IsValid: ValidateReference(leftReference)},
+		{Name: "rightReference", IsValid: ValidateReference(rightReference)},
+	}); err != nil {
+		return nil, err
+	}
+	leftRef, err := c.resolveRef(c.db.WithContext(ctx), repository, leftReference)
+	if err != nil {
+		return nil, fmt.Errorf("left reference: %w", err)
+	}
+	rightRef, err := c.resolveRef(c.db.WithContext(ctx), repository, rightReference)
+	if err != nil {
+		return nil, fmt.Errorf("right reference: %w", err)
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		leftBranchID, err := c.getBranchIDCache(tx, repository, leftRef.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("left ref branch: %w", err)
+		}
+		rightBranchID, err := c.getBranchIDCache(tx, repository, rightRef.Branch)
+		if err != nil {
+			return nil, fmt.Errorf("right ref branch: %w", err)
+		}
+		left, right, conflict, err := diffCountsBranches(tx, doDiffParams{
+			Repository:    repository,
+			LeftCommitID:  leftRef.CommitID,
+			LeftBranchID:  leftBranchID,
+			RightCommitID: rightRef.CommitID,
+			RightBranchID: rightBranchID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		leftGraph, err := lineageGraph(tx, "left_graph", leftBranchID, commitsFromCommitID(leftRef.CommitID))
+		if err != nil {
+			return nil, fmt.Errorf("left lineage: %w", err)
+		}
+		rightGraph, err := lineageGraph(tx, "right_graph", rightBranchID, commitsFromCommitID(rightRef.CommitID))
+		if err != nil {
+			return nil, fmt.Errorf("right lineage: %w", err)
+		}
+		return selectDiffSummary(tx, leftGraph, rightGraph, diffCountsSQL(left, right, conflict))
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.DiffSummary), nil
+}
+
+// selectDiffSummary sums the differences selected by diffSQL, a query of diffCountsSQL, and
+// counts the commits of each of the lineage graphs leftGraph and rightGraph not reachable from
+// the other, in a single statement
+func selectDiffSummary(tx db.Tx, leftGraph, rightGraph, diffSQL string) (*catalog.DiffSummary, error) {
+	added := strconv.Itoa(int(catalog.DifferenceTypeAdded))
+	removed := strconv.Itoa(int(catalog.DifferenceTypeRemoved))
+	changed := strconv.Itoa(int(catalog.DifferenceTypeChanged))
+	conflict := strconv.Itoa(int(catalog.DifferenceTypeConflict))
+	query := `WITH RECURSIVE ` + diffCommitsCTEs(leftGraph, rightGraph) + `
+		SELECT ` + diffCommitsColumns + `,
+			COUNT(*) FILTER (WHERE d.diff_type = ` + added + `) AS added,
+			COUNT(*) FILTER (WHERE d.diff_type = ` + removed + `) AS removed,
+			COUNT(*) FILTER (WHERE d.diff_type = ` + changed + `) AS changed,
+			COUNT(*) FILTER (WHERE d.diff_type = ` + conflict + `) AS conflicts,
+			COALESCE(SUM(CASE WHEN d.diff_type IN (` + added + `, ` + changed + `) THEN d.left_size - d.right_size
+				WHEN d.diff_type = ` + removed + ` THEN -d.right_size
+				ELSE 0 END), 0) AS bytes_delta
+		FROM (` + diffSQL + `) d
+		WHERE d.diff_type IS NOT NULL`
+	var summary struct {
+		CommitsAhead  int   `db:"commits_ahead"`
+		CommitsBehind int   `db:"commits_behind"`
+		Added         int   `db:"added"`
+		Removed       int   `db:"removed"`
+		Changed       int   `db:"changed"`
+		Conflicts     int   `db:"conflicts"`
+		BytesDelta    int64 `db:"bytes_delta"`
+	}
+	if err := tx.Get(&summary, query); err != nil {
+		return nil, fmt.Errorf("summarize differences: %w", err)
+	}
+	return &catalog.DiffSummary{
+		CommitsAhead:  summary.CommitsAhead,
+		CommitsBehind: summary.CommitsBehind,
+		Added:         summary.Added,
+		Removed:       summary.Removed,
+		Changed:       summary.Changed,
+		Conflicts:     summary.Conflicts,
+		BytesDelta:    summary.BytesDelta,
+	}, nil
+}
+
+// diffSummaryCommits counts the commits reachable from each reference and not from the other
+func diffSummaryCommits(tx db.Tx, leftBranchID int64, leftCommitID CommitID, rightBranchID int64, rightCommitID CommitID) (*catalog.DiffSummary, error) {
+	leftGraph, err := lineageGraph(tx, "left_graph", leftBranchID, commitsFromCommitID(leftCommitID))
+	if err != nil {
+		return nil, fmt.Errorf("left lineage: %w", err)
+	}
+	rightGraph, err := lineageGraph(tx, "right_graph", rightBranchID, commitsFromCommitID(rightCommitID))
+	if err != nil {
+		return nil, fmt.Errorf("right lineage: %w", err)
+	}
+	query := `WITH RECURSIVE ` + diffCommitsCTEs(leftGraph, rightGraph) + `
+		SELECT ` + diffCommitsColumns
+	var counts struct {
+		CommitsAhead  int `db:"commits_ahead"`
+		CommitsBehind int `db:"commits_behind"`
+	}
+	if err := tx.Get(&counts, query); err != nil {
+		return nil, fmt.Errorf("count commits: %w", err)
+	}
+	return &catalog.DiffSummary{
+		CommitsAhead:  counts.CommitsAhead,
+		CommitsBehind: counts.CommitsBehind,
+	}, nil
+}
+
+// diffCommitsColumns selects the commits_ahead and commits_behind counts of the commits of
+// diffCommitsCTEs
+const diffCommitsColumns = `(SELECT COUNT(*) FROM (SELECT * FROM left_commits EXCEPT SELECT * FROM right_commits) a) AS commits_ahead,
+			(SELECT COUNT(*) FROM (SELECT * FROM right_commits EXCEPT SELECT * FROM left_commits) b) AS commits_behind`
+
+// diffCommitsCTEs returns the recursive lineage graphs leftGraph and rightGraph named
+// left_graph and right_graph, and the left_commits and right_commits reachable from each
+func diffCommitsCTEs(leftGraph, rightGraph string) string {
+	return leftGraph + `, ` + rightGraph + `,
+		left_commits AS (SELECT c.branch_id, c.commit_id FROM catalog_commits c
+			JOIN left_graph l ON c.branch_id = l.branch_id AND c.commit_id <= l.commit_id),
+		right_commits AS (SELECT c.branch_id, c.commit_id FROM catalog_commits c
+			JOIN right_graph r ON c.branch_id = r.branch_id AND c.commit_id <= r.commit_id)`
+}
+
+// commitsFromCommitID returns the commit to walk a reference's commits from, a branch
+// reference walks from the branch head
+func commitsFromCommitID(commitID CommitID) CommitID {
+	if commitID > 0 {
+		return commitID
+	}
+	return MaxCommitID
+}
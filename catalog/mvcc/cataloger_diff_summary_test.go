@@ -0,0 +1,95 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_DiffSummary(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	createEntry := func(branch, path string, size int64) {
+		testutil.MustDo(t, "create entry "+path, c.CreateEntry(ctx, repository, branch, catalog.Entry{
+			Path:            path,
+			Checksum:        branch + path,
+			PhysicalAddress: branch + path,
+			Size:            size,
+		}, catalog.CreateEntryParams{}))
+	}
+	createEntry("master", "file1", 10)
+	createEntry("master", "file2", 20)
+	_, err := c.Commit(ctx, repository, "master", "add files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	createEntry("branch1", "file1", 15)
+	createEntry("branch1", "file3", 5)
+	testutil.MustDo(t, "delete file2", c.DeleteEntry(ctx, repository, "branch1", "file2"))
+	_, err = c.Commit(ctx, repository, "branch1", "change files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	// uncommitted change on master
+	createEntry("master", "file4", 7)
+
+	tests := []struct {
+		name    string
+		left    string
+		right   string
+		want    catalog.DiffSummary
+		wantErr error
+	}{
+		{
+			name:  "child to parent",
+			left:  "branch1",
+			right: "master",
+			want: catalog.DiffSummary{
+				CommitsAhead: 2, // branch creation and change files
+				Added:        1,
+				Removed:      1,
+				Changed:      1,
+				BytesDelta:   15 - 10 + 5 - 20,
+			},
+		},
+		{
+			name:  "parent to child",
+			left:  "master:HEAD",
+			right: "branch1",
+			want: catalog.DiffSummary{
+				CommitsBehind: 2,
+			},
+		},
+		{
+			name:  "same branch",
+			left:  "master",
+			right: "master:HEAD",
+			want: catalog.DiffSummary{
+				Added:      1,
+				BytesDelta: 7,
+			},
+		},
+		{
+			name:    "missing branch",
+			left:    "branch2",
+			right:   "master",
+			wantErr: catalog.ErrBranchNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := c.DiffSummary(ctx, repository, tt.left, tt.right)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DiffSummary() err=%v, expected=%v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if *summary != tt.want {
+				t.Fatalf("DiffSummary() summary=%+v, expected=%+v", *summary, tt.want)
+			}
+		})
+	}
+}
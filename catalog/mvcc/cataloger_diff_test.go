@@ -21,7 +21,7 @@ func TestCataloger_Diff(t *testing.T) {
 		for i := 0; i < n; i++ {
 			testCatalogerCreateEntry(t, ctx, c, repository, branch, "/file"+strconv.Itoa(i), nil, branch)
 		}
-		_, err := c.Commit(ctx, repository, branch, msg, "tester", nil)
+		_, err := c.Commit(ctx, repository, branch, msg, "tester", nil, catalog.CommitParams{})
 		testutil.MustDo(t, msg, err)
 	}
 	commitChanges(10, "Changes on master", "master")
@@ -32,7 +32,7 @@ func TestCataloger_Diff(t *testing.T) {
 		testutil.MustDo(t, "delete file from branch",
 			c.DeleteEntry(ctx, repository, "branch1", "/file"+strconv.Itoa(i)))
 	}
-	_, err := c.Commit(ctx, repository, "branch1", "delete some files", "tester", nil)
+	_, err := c.Commit(ctx, repository, "branch1", "delete some files", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "delete some files from branch1", err)
 
 	const limit = 3
@@ -107,7 +107,7 @@ func TestCataloger_Diff_FromChild(t *testing.T) {
 		p := fmt.Sprintf("file%d", i)
 		testCatalogerCreateEntry(t, ctx, c, repository, catalog.DefaultBranchName, p, nil, catalog.DefaultBranchName)
 	}
-	_, err := c.Commit(ctx, repository, catalog.DefaultBranchName, "initial commit", "tester", nil)
+	_, err := c.Commit(ctx, repository, catalog.DefaultBranchName, "initial commit", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "initial commit", err)
 
 	// branch changes into child branch called "branch1"
@@ -124,7 +124,7 @@ func TestCataloger_Diff_FromChild(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "fileX", nil, "branch1")
 
 	// commit change on "branch1"
-	_, err = c.Commit(ctx, repository, "branch1", "commit changes", "tester", nil)
+	_, err = c.Commit(ctx, repository, "branch1", "commit changes", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit changes", err)
 
 	// diff changes between "branch1" and "master" (from child)
@@ -154,7 +154,7 @@ func TestCataloger_Diff_SameBranch(t *testing.T) {
 		p := fmt.Sprintf("file%d-%s", j, catalog.DefaultBranchName)
 		testCatalogerCreateEntry(t, ctx, c, repository, catalog.DefaultBranchName, p, nil, catalog.DefaultBranchName)
 	}
-	firstCommit, err := c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes to "+catalog.DefaultBranchName, "tester", nil)
+	firstCommit, err := c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes to "+catalog.DefaultBranchName, "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "initial branch commit", err)
 
 	// delete
@@ -168,7 +168,7 @@ func TestCataloger_Diff_SameBranch(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, catalog.DefaultBranchName, "fileX-"+catalog.DefaultBranchName, nil, catalog.DefaultBranchName)
 
 	// commit changes
-	secondCommit, err := c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes", "tester", nil)
+	secondCommit, err := c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit branch changes", err)
 
 	// diff changes between second and first commit
@@ -214,9 +214,9 @@ func TestCataloger_Diff_SameBranchDiffMergedChanges(t *testing.T) {
 		testCatalogerCreateEntry(t, ctx, c, repository, catalog.DefaultBranchName, p, nil, catalog.DefaultBranchName)
 	}
 	// commit and merge changes
-	_, err := c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes to "+catalog.DefaultBranchName, "tester", nil)
+	_, err := c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes to "+catalog.DefaultBranchName, "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "initial branch commit", err)
-	firstCommit, err := c.Merge(ctx, repository, catalog.DefaultBranchName, "branch1", "tester", "merge changes from master to branch1", nil)
+	firstCommit, err := c.Merge(ctx, repository, catalog.DefaultBranchName, "branch1", "tester", "merge changes from master to branch1", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge changes from master to branch1", err)
 
 	// delete
@@ -230,9 +230,9 @@ func TestCataloger_Diff_SameBranchDiffMergedChanges(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, catalog.DefaultBranchName, "fileX-"+catalog.DefaultBranchName, nil, catalog.DefaultBranchName)
 
 	// commit and merge changes
-	_, err = c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes", "tester", nil)
+	_, err = c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit branch changes", err)
-	secondCommit, err := c.Merge(ctx, repository, catalog.DefaultBranchName, "branch1", "tester", "merge more changes from master to branch1", nil)
+	secondCommit, err := c.Merge(ctx, repository, catalog.DefaultBranchName, "branch1", "tester", "merge more changes from master to branch1", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge more changes from master to branch1", err)
 
 	// diff changes between second and first commit
@@ -266,7 +266,7 @@ func TestCataloger_Diff_SameBranchDiffMergedChanges(t *testing.T) {
 
 	// rewrite a file with different content and expect to find a change in diff
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2-"+catalog.DefaultBranchName, nil, catalog.DefaultBranchName+"mod2")
-	rewriteCommit, err := c.Commit(ctx, repository, "branch1", "rewrite file2", "tester", nil)
+	rewriteCommit, err := c.Commit(ctx, repository, "branch1", "rewrite file2", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "rewrite file2", err)
 
 	res, more, err = c.Diff(ctx, repository, rewriteCommit.Reference, secondCommit.Reference, catalog.DiffParams{Limit: -1})
@@ -293,7 +293,7 @@ func TestCataloger_Diff_FromChildThreeBranches(t *testing.T) {
 		for i := 0; i < items; i++ {
 			testCatalogerCreateEntry(t, ctx, c, repository, branch, "/file"+strconv.Itoa(i+items*offset), nil, "")
 		}
-		_, err := c.Commit(ctx, repository, branch, msg, "tester", nil)
+		_, err := c.Commit(ctx, repository, branch, msg, "tester", nil, catalog.CommitParams{})
 		testutil.MustDo(t, msg, err)
 	}
 	// create 3 files and commit on master
@@ -315,14 +315,14 @@ func TestCataloger_Diff_FromChildThreeBranches(t *testing.T) {
 		c.DeleteEntry(ctx, repository, "branch2", delFilename))
 	const overFilename = "/file2"
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch2", overFilename, nil, "seed1")
-	_, err := c.Commit(ctx, repository, "branch2", "second commit to branch2", "tester", nil)
+	_, err := c.Commit(ctx, repository, "branch2", "second commit to branch2", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "second commit to branch2", err)
 
 	// merge the above up to master (from branch2)
-	_, err = c.Merge(ctx, repository, "branch2", "branch1", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "branch2", "branch1", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "Merge changes from branch2 to branch1", err)
 	// merge the changes from branch1 to master
-	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "Merge changes from branch1 to master", err)
 
 	if !IsValidReference(res.Reference) {
@@ -383,7 +383,7 @@ func TestCataloger_Diff_FromParentThreeBranches(t *testing.T) {
 		p := fmt.Sprintf("file%d-%s", j, catalog.DefaultBranchName)
 		testCatalogerCreateEntry(t, ctx, c, repository, catalog.DefaultBranchName, p, nil, catalog.DefaultBranchName)
 	}
-	_, err := c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes to "+catalog.DefaultBranchName, "tester", nil)
+	_, err := c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes to "+catalog.DefaultBranchName, "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "initial branch commit", err)
 
 	// create 3 branches, create 3 files and commit. each branch branches from previous branch
@@ -395,7 +395,7 @@ func TestCataloger_Diff_FromParentThreeBranches(t *testing.T) {
 			p := fmt.Sprintf("file%d-%s", j, branchName)
 			testCatalogerCreateEntry(t, ctx, c, repository, branchName, p, nil, branchName)
 		}
-		_, err := c.Commit(ctx, repository, branchName, "commit changes to "+branchName, "tester", nil)
+		_, err := c.Commit(ctx, repository, branchName, "commit changes to "+branchName, "tester", nil, catalog.CommitParams{})
 		testutil.MustDo(t, "initial branch commit", err)
 		prevBranch = branchName
 	}
@@ -413,7 +413,7 @@ func TestCataloger_Diff_FromParentThreeBranches(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, catalog.DefaultBranchName, "fileX-"+catalog.DefaultBranchName, nil, catalog.DefaultBranchName)
 
 	// commit changes
-	_, err = c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes", "tester", nil)
+	_, err = c.Commit(ctx, repository, catalog.DefaultBranchName, "commit changes", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit branch changes", err)
 
 	// diff changes between master and branch0
@@ -449,7 +449,7 @@ func TestCataloger_Diff_AdditionalFields(t *testing.T) {
 	for i := 0; i < numOfEntries; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repository, "master", fmt.Sprintf("file%d", i), nil, "")
 	}
-	_, err := c.Commit(ctx, repository, "master", "checking changes on master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "checking changes on master", "tester", nil, catalog.CommitParams{})
 	testutil.Must(t, err)
 
 	res, hasMore, err := c.Diff(ctx, repository, "master", "branch1", catalog.DiffParams{Limit: numOfEntries})
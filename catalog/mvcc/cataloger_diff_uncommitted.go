@@ -10,7 +10,7 @@ import (
 	"github.com/treeverse/lakefs/db"
 )
 
-func (c *cataloger) DiffUncommitted(ctx context.Context, repository, branch string, limit int, after string) (catalog.Differences, bool, error) {
+func (c *cataloger) DiffUncommitted(ctx context.Context, repository, branch, prefix string, limit int, after string) (catalog.Differences, bool, error) {
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "branch", IsValid: ValidateBranchName(branch)},
@@ -32,17 +32,13 @@ func (c *cataloger) DiffUncommitted(ctx context.Context, repository, branch stri
 			return nil, fmt.Errorf("get lineage: %w", err)
 		}
 
-		q := psql.Select("CASE WHEN e.max_commit=0 THEN 1 WHEN v.path IS NOT NULL THEN 2 ELSE 0 END AS diff_type", "e.path").
-			FromSelect(sqEntriesV(UncommittedID), "e").
-			JoinClause(
-				sqEntriesLineageV(branchID, CommittedID, lineage).
-					Prefix("LEFT JOIN (").Suffix(") AS v ON v.path=e.path")).
-			Where(sq.And{
-				sq.Eq{"e.branch_id": branchID, "e.is_committed": false},
-				sq.Gt{"e.path": after},
-			}).
+		q := sqDiffUncommitted(branchID, lineage).
+			Where(sq.Gt{"e.path": after}).
 			Limit(uint64(limit + 1)).
 			OrderBy("path")
+		if prefix != "" {
+			q = q.Where(sq.Like{"e.path": db.Prefix(prefix)})
+		}
 		sql, args, err := q.ToSql()
 		if err != nil {
 			return nil, fmt.Errorf("build sql: %w", err)
@@ -61,3 +57,14 @@ func (c *cataloger) DiffUncommitted(ctx context.Context, repository, branch stri
 	hasMore := paginateSlice(&differences, limit)
 	return differences, hasMore, nil
 }
+
+// sqDiffUncommitted selects the path and difference type of the uncommitted entries of a
+// branch
+func sqDiffUncommitted(branchID int64, lineage []lineageCommit) sq.SelectBuilder {
+	return psql.Select("CASE WHEN e.max_commit=0 THEN 1 WHEN v.path IS NOT NULL THEN 2 ELSE 0 END AS diff_type", "e.path").
+		FromSelect(sqEntriesV(UncommittedID), "e").
+		JoinClause(
+			sqEntriesLineageV(branchID, CommittedID, lineage).
+				Prefix("LEFT JOIN (").Suffix(") AS v ON v.path=e.path")).
+		Where(sq.Eq{"e.branch_id": branchID, "e.is_committed": false})
+}
@@ -26,7 +26,7 @@ func TestCataloger_DiffUncommitted_Pagination(t *testing.T) {
 	var differences catalog.Differences
 	var after string
 	for {
-		res, hasMore, err := c.DiffUncommitted(ctx, repository, "master", changesPerPage, after)
+		res, hasMore, err := c.DiffUncommitted(ctx, repository, "master", "", changesPerPage, after)
 		testutil.MustDo(t, "diff uncommitted changes", err)
 		if err != nil {
 			t.Fatalf("DiffUncommitted err=%s, expected none", err)
@@ -45,7 +45,7 @@ func TestCataloger_DiffUncommitted_Pagination(t *testing.T) {
 	}
 
 	// check the case where we ask for 0 amount
-	res, hasMore, err := c.DiffUncommitted(ctx, repository, "master", 0, "")
+	res, hasMore, err := c.DiffUncommitted(ctx, repository, "master", "", 0, "")
 	testutil.MustDo(t, "diff uncommitted with 0 limit", err)
 	if !hasMore {
 		t.Error("DiffUncommitted() has more should be true")
@@ -65,7 +65,7 @@ func TestCataloger_DiffUncommitted_Changes(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file"+strconv.Itoa(i), nil, "")
 	}
-	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to master", err)
 
 	// delete, create and change
@@ -78,7 +78,7 @@ func TestCataloger_DiffUncommitted_Changes(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", overFilename, nil, "seed1")
 
 	// verify that diff uncommitted show the above change
-	differences, _, err := c.DiffUncommitted(ctx, repository, "master", -1, "")
+	differences, _, err := c.DiffUncommitted(ctx, repository, "master", "", -1, "")
 	if err != nil {
 		t.Fatalf("DiffUncommitted err = %s, expected none", err)
 	}
@@ -93,6 +93,29 @@ func TestCataloger_DiffUncommitted_Changes(t *testing.T) {
 	}
 }
 
+func TestCataloger_DiffUncommitted_Prefix(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+
+	var expectedDifferences catalog.Differences
+	for i := 0; i < 3; i++ {
+		p := "a/file" + strconv.Itoa(i)
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", p, nil, "")
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "b/file"+strconv.Itoa(i), nil, "")
+		expectedDifferences = append(expectedDifferences, catalog.Difference{Type: catalog.DifferenceTypeAdded, Entry: catalog.Entry{Path: p}})
+	}
+
+	differences, hasMore, err := c.DiffUncommitted(ctx, repository, "master", "a/", -1, "")
+	testutil.MustDo(t, "diff uncommitted under prefix", err)
+	if hasMore {
+		t.Error("DiffUncommitted() has more should be false")
+	}
+	if diff := deep.Equal(differences, expectedDifferences); diff != nil {
+		t.Fatal("DiffUncommitted", diff)
+	}
+}
+
 func TestCataloger_DiffUncommitted_NoChance(t *testing.T) {
 	ctx := context.Background()
 	c := testCataloger(t)
@@ -102,11 +125,11 @@ func TestCataloger_DiffUncommitted_NoChance(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file"+strconv.Itoa(i), nil, "")
 	}
-	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to master", err)
 
 	// verify that diff uncommitted show the above change
-	differences, hasMore, err := c.DiffUncommitted(ctx, repository, "master", -1, "")
+	differences, hasMore, err := c.DiffUncommitted(ctx, repository, "master", "", -1, "")
 	if err != nil {
 		t.Fatalf("DiffUncommitted err = %s, expected none", err)
 	}
@@ -1,41 +1,78 @@
 package mvcc
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"path"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/georgysavva/scany/pgxscan"
 	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/cron"
 	"github.com/treeverse/lakefs/db"
 )
 
-func (c *cataloger) GetExportConfigurationForBranch(repository string, branch string) (catalog.ExportConfiguration, error) {
+// exportConfigurationColumns are the columns of catalog_branches_export scanned into an
+// ExportConfiguration
+const exportConfigurationColumns = `prefix, export_path, export_status_path, last_keys_in_prefix_regexp, continuous,
+    parallelism, mode, max_attempts, retry_backoff, write_manifest, schedule, include_prefixes, exclude_globs, format,
+    propagate_deletes, additional_export_paths, sse_algorithm, sse_kms_key_id`
+
+func (c *cataloger) GetExportConfigurationForBranch(ctx context.Context, repository string, branch string, prefix string) (catalog.ExportConfiguration, error) {
 	ret, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
 		branchID, err := c.getBranchIDCache(tx, repository, branch)
 		var ret catalog.ExportConfiguration
 		if err != nil {
 			return nil, err
 		}
-		err = c.db.Get(&ret,
-			`SELECT export_path, export_status_path, last_keys_in_prefix_regexp, continuous
+		err = tx.Get(&ret,
+			`SELECT `+exportConfigurationColumns+`
                          FROM catalog_branches_export
-                         WHERE branch_id = $1`, branchID)
+                         WHERE branch_id = $1 AND prefix = $2`, branchID, prefix)
 		return &ret, err
-	})
+	}, c.txOpts(ctx)...)
 	if ret == nil {
 		return catalog.ExportConfiguration{}, err
 	}
 	return *ret.(*catalog.ExportConfiguration), err
 }
 
-func (c *cataloger) GetExportConfigurations() ([]catalog.ExportConfigurationForBranch, error) {
+func (c *cataloger) GetExportConfigurationsForBranch(ctx context.Context, repository string, branch string) ([]catalog.ExportConfiguration, error) {
+	ret, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		ret := make([]catalog.ExportConfiguration, 0)
+		err = tx.Select(&ret,
+			`SELECT `+exportConfigurationColumns+`
+                         FROM catalog_branches_export
+                         WHERE branch_id = $1
+                         ORDER BY prefix`, branchID)
+		return ret, err
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	return ret.([]catalog.ExportConfiguration), nil
+}
+
+func (c *cataloger) GetExportConfigurations(ctx context.Context) ([]catalog.ExportConfigurationForBranch, error) {
 	ret := make([]catalog.ExportConfigurationForBranch, 0)
-	rows, err := c.db.Query(
-		`SELECT r.name repository, b.name branch,
+	rows, err := c.db.WithContext(ctx).Query(
+		`SELECT r.name repository, b.name branch, e.prefix prefix,
                      e.export_path export_path, e.export_status_path export_status_path,
                      e.last_keys_in_prefix_regexp last_keys_in_prefix_regexp,
-                     e.continuous continuous
+                     e.continuous continuous, e.parallelism parallelism, e.mode mode,
+                     e.max_attempts max_attempts, e.retry_backoff retry_backoff,
+                     e.write_manifest write_manifest, e.schedule schedule,
+                     e.include_prefixes include_prefixes, e.exclude_globs exclude_globs,
+                     e.format format, e.propagate_deletes propagate_deletes,
+                     e.additional_export_paths additional_export_paths,
+                     e.sse_algorithm sse_algorithm, e.sse_kms_key_id sse_kms_key_id
                  FROM catalog_branches_export e JOIN catalog_branches b ON e.branch_id = b.id
                     JOIN catalog_repositories r ON b.repository_id = r.id`)
 	if err != nil {
@@ -45,32 +82,122 @@ func (c *cataloger) GetExportConfigurations() ([]catalog.ExportConfigurationForB
 	return ret, err
 }
 
-func (c *cataloger) PutExportConfiguration(repository string, branch string, conf *catalog.ExportConfiguration) error {
+func (c *cataloger) PutExportConfiguration(ctx context.Context, repository string, branch string, conf *catalog.ExportConfiguration) error {
 	// Validate all fields could be compiled as regexps.
 	for i, r := range conf.LastKeysInPrefixRegexp {
 		if _, err := regexp.Compile(r); err != nil {
 			return fmt.Errorf("invalid regexp /%s/ at position %d in LastKeysInPrefixRegexp: %w", r, i, err)
 		}
 	}
+	for _, glob := range conf.ExcludeGlobs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("exclude glob %s: %s: %w", glob, err, catalog.ErrInvalidValue)
+		}
+	}
+	if conf.Parallelism < 0 {
+		return fmt.Errorf("parallelism %d: %w", conf.Parallelism, catalog.ErrInvalidValue)
+	}
+	if conf.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts %d: %w", conf.MaxAttempts, catalog.ErrInvalidValue)
+	}
+	if conf.RetryBackoff < 0 {
+		return fmt.Errorf("retry backoff %s: %w", conf.RetryBackoff, catalog.ErrInvalidValue)
+	}
+	if conf.WriteManifest && conf.StatusPath == "" {
+		return fmt.Errorf("write manifest with no status path: %w", catalog.ErrInvalidValue)
+	}
+	if conf.Schedule != "" {
+		if _, err := cron.Parse(conf.Schedule); err != nil {
+			return fmt.Errorf("schedule: %s: %w", err, catalog.ErrInvalidValue)
+		}
+	}
+	paths := make(map[string]struct{}, 1+len(conf.AdditionalPaths))
+	for i, exportPath := range conf.Paths() {
+		if i > 0 && exportPath == "" {
+			return fmt.Errorf("empty additional export path: %w", catalog.ErrInvalidValue)
+		}
+		if _, ok := paths[exportPath]; ok {
+			return fmt.Errorf("export path %s repeated: %w", exportPath, catalog.ErrInvalidValue)
+		}
+		paths[exportPath] = struct{}{}
+	}
+	switch conf.Mode {
+	case "":
+		conf.Mode = catalog.ExportModeIncremental
+	case catalog.ExportModeIncremental, catalog.ExportModeFull:
+	default:
+		return fmt.Errorf("mode %s: %w", conf.Mode, catalog.ErrInvalidValue)
+	}
+	for _, exportPath := range append(conf.Paths(), conf.StatusPath) {
+		for _, placeholder := range catalog.ExportPathPlaceholders(exportPath) {
+			switch placeholder {
+			case catalog.ExportPathBranch, catalog.ExportPathCommit, catalog.ExportPathTimestamp:
+			default:
+				return fmt.Errorf("export path %s placeholder %s: %w", exportPath, placeholder, catalog.ErrInvalidValue)
+			}
+		}
+	}
+	for _, exportPath := range conf.Paths() {
+		// incremental exports to a new path each time would leave it with only the diff
+		if conf.Mode != catalog.ExportModeFull && catalog.IsPerExportPath(exportPath) {
+			return fmt.Errorf("%s export to per-export path %s: %w", conf.Mode, exportPath, catalog.ErrInvalidValue)
+		}
+	}
+	switch conf.Format {
+	case "":
+		conf.Format = catalog.ExportFormatCopy
+	case catalog.ExportFormatCopy:
+	case catalog.ExportFormatSymlink:
+		if conf.WriteManifest {
+			return fmt.Errorf("write manifest of symlink export: %w", catalog.ErrInvalidValue)
+		}
+	default:
+		return fmt.Errorf("format %s: %w", conf.Format, catalog.ErrInvalidValue)
+	}
+	switch conf.SSEAlgorithm {
+	case "":
+		if conf.SSEKMSKeyID != "" {
+			return fmt.Errorf("KMS key with no server-side encryption: %w", catalog.ErrInvalidValue)
+		}
+	case catalog.ExportSSEAlgorithmS3:
+		if conf.SSEKMSKeyID != "" {
+			return fmt.Errorf("KMS key with server-side encryption %s: %w", conf.SSEAlgorithm, catalog.ErrInvalidValue)
+		}
+	case catalog.ExportSSEAlgorithmKMS:
+	default:
+		return fmt.Errorf("server-side encryption %s: %w", conf.SSEAlgorithm, catalog.ErrInvalidValue)
+	}
+	if conf.SSEAlgorithm != "" {
+		for _, exportPath := range conf.Paths() {
+			if !strings.HasPrefix(exportPath, "s3://") {
+				return fmt.Errorf("server-side encryption of non-S3 export path %s: %w", exportPath, catalog.ErrInvalidValue)
+			}
+		}
+	}
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
 		branchID, err := c.getBranchIDCache(tx, repository, branch)
 		if err != nil {
 			return nil, err
 		}
-		_, err = c.db.Exec(
-			`INSERT INTO catalog_branches_export (
-                             branch_id, export_path, export_status_path, last_keys_in_prefix_regexp, continuous)
-                         VALUES ($1, $2, $3, $4, $5)
-                         ON CONFLICT (branch_id)
-                         DO UPDATE SET (branch_id, export_path, export_status_path, last_keys_in_prefix_regexp, continuous) =
-                             (EXCLUDED.branch_id, EXCLUDED.export_path, EXCLUDED.export_status_path, EXCLUDED.last_keys_in_prefix_regexp, EXCLUDED.continuous)`,
-			branchID, conf.Path, conf.StatusPath, conf.LastKeysInPrefixRegexp, conf.IsContinuous)
+		_, err = tx.Exec(
+			`INSERT INTO catalog_branches_export (branch_id, `+exportConfigurationColumns+`)
+                         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+                         ON CONFLICT (branch_id, prefix)
+                         DO UPDATE SET (`+exportConfigurationColumns+`) =
+                             (EXCLUDED.prefix, EXCLUDED.export_path, EXCLUDED.export_status_path, EXCLUDED.last_keys_in_prefix_regexp, EXCLUDED.continuous,
+                              EXCLUDED.parallelism, EXCLUDED.mode, EXCLUDED.max_attempts, EXCLUDED.retry_backoff, EXCLUDED.write_manifest, EXCLUDED.schedule,
+                              EXCLUDED.include_prefixes, EXCLUDED.exclude_globs, EXCLUDED.format,
+                              EXCLUDED.propagate_deletes, EXCLUDED.additional_export_paths, EXCLUDED.sse_algorithm, EXCLUDED.sse_kms_key_id)`,
+			branchID, conf.Prefix, conf.Path, conf.StatusPath, conf.LastKeysInPrefixRegexp, conf.IsContinuous,
+			conf.Parallelism, conf.Mode, conf.MaxAttempts, conf.RetryBackoff, conf.WriteManifest, conf.Schedule,
+			conf.IncludePrefixes, conf.ExcludeGlobs, conf.Format, conf.PropagateDeletes, conf.AdditionalPaths,
+			conf.SSEAlgorithm, conf.SSEKMSKeyID)
 		return nil, err
-	})
+	}, c.txOpts(ctx)...)
 	return err
 }
 
-func (c *cataloger) GetExportState(repo string, branch string) (catalog.ExportState, error) {
+func (c *cataloger) GetExportState(ctx context.Context, repo string, branch string) (catalog.ExportState, error) {
 	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
 		var res catalog.ExportState
 
@@ -80,16 +207,19 @@ func (c *cataloger) GetExportState(repo string, branch string) (catalog.ExportSt
 		}
 		// get current state
 		err = tx.Get(&res, `
-		SELECT current_ref, state, error_message
+		SELECT COALESCE(current_ref, '') current_ref, state, error_message, last_scheduled_run
 		FROM catalog_branches_export_state
 		WHERE branch_id=$1`,
 			branchID)
 		return res, err
-	})
-	return res.(catalog.ExportState), err
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return catalog.ExportState{}, err
+	}
+	return res.(catalog.ExportState), nil
 }
 
-func (c *cataloger) ExportStateSet(repo, branch string, cb catalog.ExportStateCallback) error {
+func (c *cataloger) ExportStateSet(ctx context.Context, repo, branch string, cb catalog.ExportStateCallback) error {
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
 		var res struct {
 			CurrentRef   string
@@ -103,7 +233,7 @@ func (c *cataloger) ExportStateSet(repo, branch string, cb catalog.ExportStateCa
 		}
 		// get current state
 		err = tx.Get(&res, `
-		SELECT current_ref, state, error_message
+		SELECT COALESCE(current_ref, '') current_ref, state, error_message
 		FROM catalog_branches_export_state
 		WHERE branch_id=$1 FOR UPDATE`,
 			branchID)
@@ -141,6 +271,31 @@ func (c *cataloger) ExportStateSet(repo, branch string, cb catalog.ExportStateCa
 			return nil, fmt.Errorf("ExportStateSet: could not update single row %s: %w", tag, catalog.ErrExportFailed)
 		}
 		return nil, err
-	})
+	}, c.txOpts(ctx)...)
 	return err
 }
+
+func (c *cataloger) MarkExportScheduledRun(ctx context.Context, repo, branch string, run time.Time) (bool, error) {
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repo, branch)
+		if err != nil {
+			return false, err
+		}
+		// a branch that was never exported has no state yet, record only the run
+		tag, err := tx.Exec(`
+		INSERT INTO catalog_branches_export_state (branch_id, last_scheduled_run)
+		VALUES ($1, $2)
+		ON CONFLICT (branch_id) DO UPDATE SET last_scheduled_run = EXCLUDED.last_scheduled_run
+		WHERE catalog_branches_export_state.last_scheduled_run IS NULL
+		    OR catalog_branches_export_state.last_scheduled_run < EXCLUDED.last_scheduled_run`,
+			branchID, run)
+		if err != nil {
+			return false, fmt.Errorf("MarkExportScheduledRun: %w", err)
+		}
+		return tag.RowsAffected() == 1, nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return false, err
+	}
+	return res.(bool), nil
+}
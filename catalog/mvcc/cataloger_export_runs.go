@@ -0,0 +1,166 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const ListExportRunsMaxLimit = 1000
+
+func (c *cataloger) InsertExportRun(ctx context.Context, repo, branch string, run *catalog.ExportRun) error {
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repo, branch)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO catalog_branches_export_runs (export_id, branch_id, from_ref, to_ref, status, paths_time)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			run.ExportID, branchID, run.FromRef, run.ToRef, catalog.ExportStatusInProgress, run.PathsTime)
+		if err != nil {
+			return nil, fmt.Errorf("insert export run %s: %w", run.ExportID, err)
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func (c *cataloger) SetExportRunCounts(ctx context.Context, exportID string, objectsCopied, objectsDeleted, bytesCopied int64) error {
+	res, err := c.db.WithContext(ctx).Exec(`
+		UPDATE catalog_branches_export_runs
+		SET objects_copied=$2, objects_deleted=$3, bytes_copied=$4
+		WHERE export_id=$1`,
+		exportID, objectsCopied, objectsDeleted, bytesCopied)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() != 1 {
+		return fmt.Errorf("export run %s: %w", exportID, db.ErrNotFound)
+	}
+	return nil
+}
+
+func (c *cataloger) EndExportRun(ctx context.Context, exportID string, status catalog.CatalogBranchExportStatus, errorMessage *string) error {
+	res, err := c.db.WithContext(ctx).Exec(`
+		UPDATE catalog_branches_export_runs
+		SET end_time=NOW(), status=$2, error_message=$3
+		WHERE export_id=$1`,
+		exportID, status, errorMessage)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() != 1 {
+		return fmt.Errorf("export run %s: %w", exportID, db.ErrNotFound)
+	}
+	return nil
+}
+
+func (c *cataloger) GetExportRuns(ctx context.Context, repo, branch string, limit int, after string) ([]*catalog.ExportRun, bool, error) {
+	if limit < 0 || limit > ListExportRunsMaxLimit {
+		limit = ListExportRunsMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repo, branch)
+		if err != nil {
+			return nil, err
+		}
+		var runs []*catalog.ExportRun
+		if after == "" {
+			err = tx.Select(&runs, `
+				SELECT export_id, from_ref, to_ref, start_time, end_time, objects_copied, objects_deleted,
+				    bytes_copied, status, error_message, paths_time
+				FROM catalog_branches_export_runs
+				WHERE branch_id=$1
+				ORDER BY start_time DESC, export_id DESC
+				LIMIT $2`,
+				branchID, limit+1)
+		} else {
+			err = tx.Select(&runs, `
+				SELECT r.export_id, r.from_ref, r.to_ref, r.start_time, r.end_time, r.objects_copied,
+				    r.objects_deleted, r.bytes_copied, r.status, r.error_message, r.paths_time
+				FROM catalog_branches_export_runs r, catalog_branches_export_runs a
+				WHERE r.branch_id=$1 AND a.export_id=$2
+				    AND (r.start_time, r.export_id) < (a.start_time, a.export_id)
+				ORDER BY r.start_time DESC, r.export_id DESC
+				LIMIT $3`,
+				branchID, after, limit+1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return runs, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	runs := res.([]*catalog.ExportRun)
+	hasMore := paginateSlice(&runs, limit)
+	if err := c.loadExportRunDestinations(ctx, runs); err != nil {
+		return nil, false, err
+	}
+	return runs, hasMore, nil
+}
+
+// loadExportRunDestinations sets the destinations of runs
+func (c *cataloger) loadExportRunDestinations(ctx context.Context, runs []*catalog.ExportRun) error {
+	if len(runs) == 0 {
+		return nil
+	}
+	exportIDs := make([]string, len(runs))
+	runByID := make(map[string]*catalog.ExportRun, len(runs))
+	for i, run := range runs {
+		exportIDs[i] = run.ExportID
+		runByID[run.ExportID] = run
+	}
+	var destinations []struct {
+		ExportID string `db:"export_id"`
+		catalog.ExportRunDestination
+	}
+	err := c.db.WithContext(ctx).Select(&destinations, `
+		SELECT export_id, destination, end_time, status, error_message
+		FROM catalog_branches_export_run_destinations
+		WHERE export_id = ANY($1)
+		ORDER BY export_id, destination`,
+		exportIDs)
+	if err != nil {
+		return fmt.Errorf("get export run destinations: %w", err)
+	}
+	for _, destination := range destinations {
+		run := runByID[destination.ExportID]
+		run.Destinations = append(run.Destinations, destination.ExportRunDestination)
+	}
+	return nil
+}
+
+func (c *cataloger) InsertExportRunDestinations(ctx context.Context, exportID string, destinations []string) error {
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		for _, destination := range destinations {
+			_, err := tx.Exec(`
+				INSERT INTO catalog_branches_export_run_destinations (export_id, destination, status)
+				VALUES ($1, $2, $3)
+				ON CONFLICT DO NOTHING`,
+				exportID, destination, catalog.ExportStatusInProgress)
+			if err != nil {
+				return nil, fmt.Errorf("insert export run %s destination %s: %w", exportID, destination, err)
+			}
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func (c *cataloger) EndExportRunDestination(ctx context.Context, exportID, destination string, status catalog.CatalogBranchExportStatus, errorMessage *string) error {
+	_, err := c.db.WithContext(ctx).Exec(`
+		INSERT INTO catalog_branches_export_run_destinations (export_id, destination, end_time, status, error_message)
+		VALUES ($1, $2, NOW(), $3, $4)
+		ON CONFLICT (export_id, destination)
+		DO UPDATE SET (end_time, status, error_message) = (EXCLUDED.end_time, EXCLUDED.status, EXCLUDED.error_message)`,
+		exportID, destination, status, errorMessage)
+	if err != nil {
+		return fmt.Errorf("end export run %s destination %s: %w", exportID, destination, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestExportRuns(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repo := testCatalogerRepo(t, ctx, c, prefix, defaultBranch)
+
+	runIDs := []string{"export-1", "export-2", "export-3"}
+	for _, exportID := range runIDs {
+		testutil.Must(t, c.InsertExportRun(ctx, repo, defaultBranch, &catalog.ExportRun{
+			ExportID: exportID,
+			FromRef:  "from-" + exportID,
+			ToRef:    "to-" + exportID,
+		}))
+	}
+	testutil.Must(t, c.SetExportRunCounts(ctx, "export-2", 3, 1, 42))
+	errorMessage := "copy failed"
+	testutil.Must(t, c.EndExportRun(ctx, "export-2", catalog.ExportStatusFailed, &errorMessage))
+	testutil.Must(t, c.InsertExportRunDestinations(ctx, "export-2", []string{"s3://west/export", "s3://east/export"}))
+	testutil.Must(t, c.EndExportRunDestination(ctx, "export-2", "s3://west/export", catalog.ExportStatusSuccess, nil))
+	testutil.Must(t, c.EndExportRunDestination(ctx, "export-2", "s3://east/export", catalog.ExportStatusFailed, &errorMessage))
+
+	t.Run("newest first", func(t *testing.T) {
+		runs, hasMore, err := c.GetExportRuns(ctx, repo, defaultBranch, -1, "")
+		testutil.Must(t, err)
+		if hasMore {
+			t.Error("got more runs, expected all runs")
+		}
+		var gotIDs []string
+		for _, run := range runs {
+			gotIDs = append(gotIDs, run.ExportID)
+		}
+		expectedIDs := []string{"export-3", "export-2", "export-1"}
+		if len(gotIDs) != len(expectedIDs) {
+			t.Fatalf("got runs %v, expected %v", gotIDs, expectedIDs)
+		}
+		for i := range expectedIDs {
+			if gotIDs[i] != expectedIDs[i] {
+				t.Fatalf("got runs %v, expected %v", gotIDs, expectedIDs)
+			}
+		}
+		if runs[0].Status != catalog.ExportStatusInProgress || runs[0].EndTime != nil {
+			t.Errorf("got run %+v, expected an unfinished run in progress", runs[0])
+		}
+		ended := runs[1]
+		if ended.FromRef != "from-export-2" || ended.ToRef != "to-export-2" {
+			t.Errorf("got run refs %s..%s, expected from-export-2..to-export-2", ended.FromRef, ended.ToRef)
+		}
+		if ended.ObjectsCopied != 3 || ended.ObjectsDeleted != 1 || ended.BytesCopied != 42 {
+			t.Errorf("got run counts %d/%d/%d, expected 3/1/42", ended.ObjectsCopied, ended.ObjectsDeleted, ended.BytesCopied)
+		}
+		if ended.Status != catalog.ExportStatusFailed || ended.EndTime == nil ||
+			ended.ErrorMessage == nil || *ended.ErrorMessage != errorMessage {
+			t.Errorf("got run %+v, expected a failed run ended with %q", ended, errorMessage)
+		}
+		if len(runs[0].Destinations) != 0 {
+			t.Errorf("got destinations %+v of a run exporting to single paths, expected none", runs[0].Destinations)
+		}
+		if len(ended.Destinations) != 2 {
+			t.Fatalf("got destinations %+v, expected 2 destinations", ended.Destinations)
+		}
+		east, west := ended.Destinations[0], ended.Destinations[1]
+		if east.Destination != "s3://east/export" || east.Status != catalog.ExportStatusFailed || east.EndTime == nil ||
+			east.ErrorMessage == nil || *east.ErrorMessage != errorMessage {
+			t.Errorf("got destination %+v, expected s3://east/export failed with %q", east, errorMessage)
+		}
+		if west.Destination != "s3://west/export" || west.Status != catalog.ExportStatusSuccess || west.EndTime == nil {
+			t.Errorf("got destination %+v, expected s3://west/export exported successfully", west)
+		}
+	})
+
+	t.Run("paginate", func(t *testing.T) {
+		runs, hasMore, err := c.GetExportRuns(ctx, repo, defaultBranch, 2, "")
+		testutil.Must(t, err)
+		if len(runs) != 2 || !hasMore {
+			t.Fatalf("got %d runs (more: %t), expected 2 runs and more", len(runs), hasMore)
+		}
+		runs, hasMore, err = c.GetExportRuns(ctx, repo, defaultBranch, 2, runs[1].ExportID)
+		testutil.Must(t, err)
+		if len(runs) != 1 || hasMore || runs[0].ExportID != "export-1" {
+			t.Errorf("got runs %+v (more: %t), expected only export-1", runs, hasMore)
+		}
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		err := c.EndExportRun(ctx, "no-such-export", catalog.ExportStatusSuccess, nil)
+		if !errors.Is(err, db.ErrNotFound) {
+			t.Errorf("end unknown run: expected ErrNotFound but got %v", err)
+		}
+	})
+}
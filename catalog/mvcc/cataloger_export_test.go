@@ -7,10 +7,13 @@ import (
 	"regexp/syntax"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 	"github.com/lib/pq"
 	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
 )
 
 const (
@@ -49,19 +52,19 @@ func TestExportConfiguration(t *testing.T) {
 		LastKeysInPrefixRegexp: pq.StringArray{"xyz+y"},
 	}
 
-	if err := c.PutExportConfiguration(repo, defaultBranch, &cfg); err != nil {
+	if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &cfg); err != nil {
 		t.Fatal(err)
 	}
 
 	t.Run("unconfigured branch", func(t *testing.T) {
-		gotCfg, err := c.GetExportConfigurationForBranch(repo, anotherBranch)
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, anotherBranch, "")
 		if !errors.Is(err, catalog.ErrBranchNotFound) {
 			t.Errorf("get configuration for unconfigured branch failed: expected ErrBranchNotFound but got %s (and %+v)", err, gotCfg)
 		}
 	})
 
 	t.Run("configured branch", func(t *testing.T) {
-		gotCfg, err := c.GetExportConfigurationForBranch(repo, defaultBranch)
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
 		if err != nil {
 			t.Errorf("get configuration for configured branch failed: %s", err)
 		}
@@ -76,10 +79,10 @@ func TestExportConfiguration(t *testing.T) {
 			StatusPath:             "/better/for/status",
 			LastKeysInPrefixRegexp: pq.StringArray{"abc", "def", "xyz"},
 		}
-		if err := c.PutExportConfiguration(repo, defaultBranch, &newCfg); err != nil {
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
 			t.Fatalf("update configuration with %+v: %s", newCfg, err)
 		}
-		gotCfg, err := c.GetExportConfigurationForBranch(repo, defaultBranch)
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
 		if err != nil {
 			t.Errorf("get updated configuration for configured branch failed: %s", err)
 		}
@@ -95,10 +98,10 @@ func TestExportConfiguration(t *testing.T) {
 			LastKeysInPrefixRegexp: pq.StringArray{"abc", "def", "xyz"},
 			IsContinuous:           true,
 		}
-		if err := c.PutExportConfiguration(repo, defaultBranch, &newCfg); err != nil {
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
 			t.Fatalf("update configuration with %+v: %s", newCfg, err)
 		}
-		gotCfg, err := c.GetExportConfigurationForBranch(repo, defaultBranch)
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
 		if err != nil {
 			t.Errorf("get updated configuration for configured branch failed: %s", err)
 		}
@@ -107,13 +110,295 @@ func TestExportConfiguration(t *testing.T) {
 		}
 	})
 
+	t.Run("parallelism", func(t *testing.T) {
+		newCfg := catalog.ExportConfiguration{
+			Path:        "/better/to/export",
+			StatusPath:  "/better/for/status",
+			Parallelism: 32,
+		}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
+			t.Fatalf("update configuration with %+v: %s", newCfg, err)
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
+		if err != nil {
+			t.Errorf("get updated configuration for configured branch failed: %s", err)
+		}
+		if diffs := deep.Equal(newCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+
+		badCfg := newCfg
+		badCfg.Parallelism = -1
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update configuration with negative parallelism err=%v, expected %s", err, catalog.ErrInvalidValue)
+		}
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		newCfg := catalog.ExportConfiguration{
+			Path:         "/better/to/export",
+			StatusPath:   "/better/for/status",
+			MaxAttempts:  8,
+			RetryBackoff: 1500 * time.Millisecond,
+		}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
+			t.Fatalf("update configuration with %+v: %s", newCfg, err)
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
+		if err != nil {
+			t.Errorf("get updated configuration for configured branch failed: %s", err)
+		}
+		if diffs := deep.Equal(newCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+
+		badCfg := newCfg
+		badCfg.RetryBackoff = -time.Second
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update configuration with negative retry backoff err=%v, expected %s", err, catalog.ErrInvalidValue)
+		}
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		newCfg := catalog.ExportConfiguration{
+			Path:          "/better/to/export",
+			StatusPath:    "/better/for/status",
+			WriteManifest: true,
+		}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
+			t.Fatalf("update configuration with %+v: %s", newCfg, err)
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
+		if err != nil {
+			t.Errorf("get updated configuration for configured branch failed: %s", err)
+		}
+		if diffs := deep.Equal(newCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+
+		badCfg := newCfg
+		badCfg.StatusPath = ""
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update configuration writing a manifest without a status path err=%v, expected %s", err, catalog.ErrInvalidValue)
+		}
+	})
+
+	t.Run("mode", func(t *testing.T) {
+		newCfg := catalog.ExportConfiguration{
+			Path:       "/better/to/export",
+			StatusPath: "/better/for/status",
+			Mode:       catalog.ExportModeFull,
+		}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
+			t.Fatalf("update configuration with %+v: %s", newCfg, err)
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
+		if err != nil {
+			t.Errorf("get updated configuration for configured branch failed: %s", err)
+		}
+		if diffs := deep.Equal(newCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+
+		badCfg := newCfg
+		badCfg.Mode = "sometimes"
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update configuration with mode %s err=%v, expected %s", badCfg.Mode, err, catalog.ErrInvalidValue)
+		}
+	})
+
+	t.Run("schedule", func(t *testing.T) {
+		newCfg := catalog.ExportConfiguration{
+			Path:       "/better/to/export",
+			StatusPath: "/better/for/status",
+			Mode:       catalog.ExportModeIncremental,
+			Schedule:   "30 2 * * 1-5",
+		}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
+			t.Fatalf("update configuration with %+v: %s", newCfg, err)
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
+		if err != nil {
+			t.Errorf("get updated configuration for configured branch failed: %s", err)
+		}
+		if diffs := deep.Equal(newCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+
+		badCfg := newCfg
+		badCfg.Schedule = "every day"
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update configuration with schedule %s err=%v, expected %s", badCfg.Schedule, err, catalog.ErrInvalidValue)
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		newCfg := catalog.ExportConfiguration{
+			Path:             "/better/to/export",
+			StatusPath:       "/better/for/status",
+			Mode:             catalog.ExportModeIncremental,
+			IncludePrefixes:  pq.StringArray{"tables/", "views/"},
+			ExcludeGlobs:     pq.StringArray{"*.tmp"},
+			PropagateDeletes: true,
+		}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
+			t.Fatalf("update configuration with %+v: %s", newCfg, err)
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
+		if err != nil {
+			t.Errorf("get updated configuration for configured branch failed: %s", err)
+		}
+		if diffs := deep.Equal(newCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+
+		badCfg := newCfg
+		badCfg.ExcludeGlobs = pq.StringArray{"[unclosed"}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update configuration with exclude globs %s err=%v, expected %s", badCfg.ExcludeGlobs, err, catalog.ErrInvalidValue)
+		}
+	})
+
+	t.Run("format", func(t *testing.T) {
+		newCfg := catalog.ExportConfiguration{
+			Path:       "/better/to/export",
+			StatusPath: "/better/for/status",
+			Mode:       catalog.ExportModeIncremental,
+			Format:     catalog.ExportFormatSymlink,
+		}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
+			t.Fatalf("update configuration with %+v: %s", newCfg, err)
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
+		if err != nil {
+			t.Errorf("get updated configuration for configured branch failed: %s", err)
+		}
+		if diffs := deep.Equal(newCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+
+		badCfg := newCfg
+		badCfg.Format = "parquet"
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update configuration with format %s err=%v, expected %s", badCfg.Format, err, catalog.ErrInvalidValue)
+		}
+		badCfg = newCfg
+		badCfg.WriteManifest = true
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update symlink configuration writing a manifest err=%v, expected %s", err, catalog.ErrInvalidValue)
+		}
+	})
+
+	t.Run("additional paths", func(t *testing.T) {
+		newCfg := catalog.ExportConfiguration{
+			Path:             "/better/to/export",
+			StatusPath:       "/better/for/status",
+			Mode:             catalog.ExportModeIncremental,
+			Format:           catalog.ExportFormatCopy,
+			PropagateDeletes: true,
+			AdditionalPaths:  pq.StringArray{"/other/region/export", "/third/region/export"},
+		}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
+			t.Fatalf("update configuration with %+v: %s", newCfg, err)
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
+		if err != nil {
+			t.Errorf("get updated configuration for configured branch failed: %s", err)
+		}
+		if diffs := deep.Equal(newCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+
+		badCfg := newCfg
+		badCfg.AdditionalPaths = pq.StringArray{"/other/region/export", newCfg.Path}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update configuration with repeated path err=%v, expected %s", err, catalog.ErrInvalidValue)
+		}
+		badCfg.AdditionalPaths = pq.StringArray{""}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update configuration with empty additional path err=%v, expected %s", err, catalog.ErrInvalidValue)
+		}
+	})
+
+	t.Run("path placeholders", func(t *testing.T) {
+		newCfg := catalog.ExportConfiguration{
+			Path:             "/better/to/export/{branch}/{timestamp}",
+			StatusPath:       "/better/for/status/{commit}",
+			Mode:             catalog.ExportModeFull,
+			Format:           catalog.ExportFormatCopy,
+			PropagateDeletes: true,
+		}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
+			t.Fatalf("update configuration with %+v: %s", newCfg, err)
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
+		if err != nil {
+			t.Errorf("get updated configuration for configured branch failed: %s", err)
+		}
+		if diffs := deep.Equal(newCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+
+		badCfg := newCfg
+		badCfg.StatusPath = "/better/for/status/{date}"
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update configuration with unknown placeholder err=%v, expected %s", err, catalog.ErrInvalidValue)
+		}
+		badCfg = newCfg
+		badCfg.Mode = catalog.ExportModeIncremental
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("update incremental configuration exporting to per-export path err=%v, expected %s", err, catalog.ErrInvalidValue)
+		}
+	})
+
+	t.Run("server-side encryption", func(t *testing.T) {
+		newCfg := catalog.ExportConfiguration{
+			Path:             "s3://better/to/export",
+			StatusPath:       "s3://better/for/status",
+			Mode:             catalog.ExportModeIncremental,
+			Format:           catalog.ExportFormatCopy,
+			PropagateDeletes: true,
+			AdditionalPaths:  pq.StringArray{"s3://other/region/export"},
+			SSEAlgorithm:     catalog.ExportSSEAlgorithmKMS,
+			SSEKMSKeyID:      "arn:aws:kms:us-east-1:123456789012:key/export",
+		}
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &newCfg); err != nil {
+			t.Fatalf("update configuration with %+v: %s", newCfg, err)
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, defaultBranch, "")
+		if err != nil {
+			t.Errorf("get updated configuration for configured branch failed: %s", err)
+		}
+		if diffs := deep.Equal(newCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+
+		badCfgs := map[string]func(cfg *catalog.ExportConfiguration){
+			"unknown algorithm":      func(cfg *catalog.ExportConfiguration) { cfg.SSEAlgorithm = "rot13" },
+			"KMS key with SSE-S3":    func(cfg *catalog.ExportConfiguration) { cfg.SSEAlgorithm = catalog.ExportSSEAlgorithmS3 },
+			"KMS key with no SSE":    func(cfg *catalog.ExportConfiguration) { cfg.SSEAlgorithm = "" },
+			"non-S3 additional path": func(cfg *catalog.ExportConfiguration) { cfg.AdditionalPaths = pq.StringArray{"gs://other/export"} },
+			"non-S3 export path":     func(cfg *catalog.ExportConfiguration) { cfg.Path = "/better/to/export" },
+			"SSE-S3 of non-S3 export": func(cfg *catalog.ExportConfiguration) {
+				cfg.SSEAlgorithm, cfg.SSEKMSKeyID, cfg.Path = catalog.ExportSSEAlgorithmS3, "", "gs://export"
+			},
+		}
+		for name, makeBad := range badCfgs {
+			badCfg := newCfg
+			makeBad(&badCfg)
+			if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg); !errors.Is(err, catalog.ErrInvalidValue) {
+				t.Errorf("update configuration with %s err=%v, expected %s", name, err, catalog.ErrInvalidValue)
+			}
+		}
+	})
+
 	t.Run("invalid regexp", func(t *testing.T) {
 		badCfg := catalog.ExportConfiguration{
 			Path:                   "/better/to/export",
 			StatusPath:             "/better/for/status",
 			LastKeysInPrefixRegexp: pq.StringArray{"(unclosed"},
 		}
-		err := c.PutExportConfiguration(repo, defaultBranch, &badCfg)
+		err := c.PutExportConfiguration(ctx, repo, defaultBranch, &badCfg)
 		var regexpErr *syntax.Error
 		if !errors.As(err, &regexpErr) {
 			t.Fatalf("update configuration with bad %+v did not give a regexp error: %s", badCfg, err)
@@ -139,22 +424,26 @@ func TestExportConfiguration(t *testing.T) {
 				Path:                   cfg.Path,
 				StatusPath:             cfg.StatusPath,
 				LastKeysInPrefixRegexp: cfg.LastKeysInPrefixRegexp,
+				Mode:                   catalog.ExportModeIncremental,
+				Format:                 catalog.ExportFormatCopy,
 			}, {
 				Repository:             repo,
 				Branch:                 moreBranch,
 				Path:                   moreCfg.Path,
 				StatusPath:             moreCfg.StatusPath,
 				LastKeysInPrefixRegexp: moreCfg.LastKeysInPrefixRegexp,
+				Mode:                   catalog.ExportModeIncremental,
+				Format:                 catalog.ExportFormatCopy,
 			},
 		}
 
-		if err := c.PutExportConfiguration(repo, defaultBranch, &cfg); err != nil {
+		if err := c.PutExportConfiguration(ctx, repo, defaultBranch, &cfg); err != nil {
 			t.Fatalf("add configuration with %+v failed: %s", cfg, err)
 		}
-		if err := c.PutExportConfiguration(repo, moreBranch, &moreCfg); err != nil {
+		if err := c.PutExportConfiguration(ctx, repo, moreBranch, &moreCfg); err != nil {
 			t.Fatalf("add configuration with %+v failed: %s", moreCfg, err)
 		}
-		got, err := c.GetExportConfigurations()
+		got, err := c.GetExportConfigurations(ctx)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -164,6 +453,51 @@ func TestExportConfiguration(t *testing.T) {
 			t.Errorf("did not read expected configurations: %s", diffs)
 		}
 	})
+
+	t.Run("prefixes", func(t *testing.T) {
+		prefixedBranch := "prefixed"
+		if _, err := c.CreateBranch(ctx, repo, prefixedBranch, defaultBranch); err != nil {
+			t.Fatalf("create prefixed branch: %s", err)
+		}
+		tablesCfg := catalog.ExportConfiguration{
+			Prefix:     "tables/",
+			Path:       "/tables/to/export",
+			StatusPath: "/tables/for/status",
+		}
+		logsCfg := catalog.ExportConfiguration{
+			Prefix: "logs/",
+			Path:   "/logs/to/export",
+			Mode:   catalog.ExportModeFull,
+		}
+		for _, conf := range []*catalog.ExportConfiguration{&tablesCfg, &logsCfg} {
+			if err := c.PutExportConfiguration(ctx, repo, prefixedBranch, conf); err != nil {
+				t.Fatalf("add configuration with %+v failed: %s", conf, err)
+			}
+		}
+		gotCfg, err := c.GetExportConfigurationForBranch(ctx, repo, prefixedBranch, tablesCfg.Prefix)
+		if err != nil {
+			t.Fatalf("get configuration for prefix %s: %s", tablesCfg.Prefix, err)
+		}
+		if diffs := deep.Equal(tablesCfg, gotCfg); diffs != nil {
+			t.Errorf("got other configuration than expected: %s", diffs)
+		}
+		_, err = c.GetExportConfigurationForBranch(ctx, repo, prefixedBranch, "")
+		if !errors.Is(err, db.ErrNotFound) {
+			t.Errorf("get configuration of unconfigured prefix err=%v, expected %s", err, db.ErrNotFound)
+		}
+
+		logsCfg.Path = "/logs/better/to/export"
+		if err := c.PutExportConfiguration(ctx, repo, prefixedBranch, &logsCfg); err != nil {
+			t.Fatalf("update configuration with %+v failed: %s", logsCfg, err)
+		}
+		got, err := c.GetExportConfigurationsForBranch(ctx, repo, prefixedBranch)
+		if err != nil {
+			t.Fatalf("get configurations of prefixed branch: %s", err)
+		}
+		if diffs := deep.Equal([]catalog.ExportConfiguration{logsCfg, tablesCfg}, got); diffs != nil {
+			t.Errorf("got other configurations than expected: %s", diffs)
+		}
+	})
 }
 
 func TestExportState(t *testing.T) {
@@ -179,7 +513,7 @@ func TestExportState(t *testing.T) {
 		return ref1, catalog.ExportStatusInProgress, nil, nil
 	}
 
-	if err := c.ExportStateSet(repo, defaultBranch, insertStart); err != nil {
+	if err := c.ExportStateSet(ctx, repo, defaultBranch, insertStart); err != nil {
 		t.Fatal(err)
 	}
 
@@ -194,11 +528,11 @@ func TestExportState(t *testing.T) {
 		return ref2, catalog.ExportStatusSuccess, nil, nil
 	}
 
-	if err := c.ExportStateSet(repo, defaultBranch, inProgressToSuccess); err != nil {
+	if err := c.ExportStateSet(ctx, repo, defaultBranch, inProgressToSuccess); err != nil {
 		t.Fatal(err)
 	}
 
-	state, err := c.GetExportState(repo, defaultBranch)
+	state, err := c.GetExportState(ctx, repo, defaultBranch)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -209,3 +543,43 @@ func TestExportState(t *testing.T) {
 		t.Errorf("expected previous state %s but got %s", catalog.ExportStatusSuccess, state.State)
 	}
 }
+
+func TestMarkExportScheduledRun(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repo := testCatalogerRepo(t, ctx, c, prefix, defaultBranch)
+	run1 := time.Date(2020, time.November, 3, 2, 30, 0, 0, time.UTC)
+	run2 := run1.Add(24 * time.Hour)
+
+	// a branch that was never exported
+	recorded, err := c.MarkExportScheduledRun(ctx, repo, defaultBranch, run1)
+	testutil.MustDo(t, "mark first run", err)
+	if !recorded {
+		t.Error("first run not recorded")
+	}
+	state, err := c.GetExportState(ctx, repo, defaultBranch)
+	testutil.MustDo(t, "get state after first run", err)
+	if state.State != "" || state.CurrentRef != "" || state.LastScheduledRun == nil || !state.LastScheduledRun.Equal(run1) {
+		t.Errorf("got state %+v after first run, expected only last scheduled run %s", state, run1)
+	}
+	recorded, err = c.MarkExportScheduledRun(ctx, repo, defaultBranch, run1)
+	testutil.MustDo(t, "mark first run again", err)
+	if recorded {
+		t.Error("first run recorded twice")
+	}
+
+	insertStart := func(oldRef string, state catalog.CatalogBranchExportStatus) (newRef string, newState catalog.CatalogBranchExportStatus, newMessage *string, err error) {
+		return "this commit", catalog.ExportStatusInProgress, nil, nil
+	}
+	testutil.MustDo(t, "start export", c.ExportStateSet(ctx, repo, defaultBranch, insertStart))
+	recorded, err = c.MarkExportScheduledRun(ctx, repo, defaultBranch, run2)
+	testutil.MustDo(t, "mark second run", err)
+	if !recorded {
+		t.Error("second run not recorded")
+	}
+	state, err = c.GetExportState(ctx, repo, defaultBranch)
+	testutil.MustDo(t, "get state after second run", err)
+	if state.State != catalog.ExportStatusInProgress || state.LastScheduledRun == nil || !state.LastScheduledRun.Equal(run2) {
+		t.Errorf("got state %+v after second run, expected in progress with last scheduled run %s", state, run2)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/testutil"
 )
 
@@ -17,7 +18,7 @@ func TestCataloger_GetBranch(t *testing.T) {
 	_, err := c.CreateBranch(ctx, repo, "branch1", "master")
 	testutil.MustDo(t, "create branch1", err)
 	testCatalogerCreateEntry(t, ctx, c, repo, "master", "a/file", nil, "")
-	_, err = c.Commit(ctx, repo, "master", "commit a file", "tester", nil)
+	_, err = c.Commit(ctx, repo, "master", "commit a file", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit a file", err)
 
 	type args struct {
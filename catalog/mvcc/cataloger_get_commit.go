@@ -15,7 +15,7 @@ func (c *cataloger) GetCommit(ctx context.Context, repository, reference string)
 	}); err != nil {
 		return nil, err
 	}
-	ref, err := ParseRef(reference)
+	ref, err := c.resolveRef(c.db.WithContext(ctx), repository, reference)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +38,7 @@ func (c *cataloger) GetCommit(ctx context.Context, repository, reference string)
 		query := `SELECT b.name as branch_name,c.commit_id,c.previous_commit_id,c.committer,c.message,c.creation_date,c.metadata,
 			COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit
 			FROM catalog_commits c JOIN catalog_branches b ON b.id = c.branch_id 
-				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch
+				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch AND NOT c.squash
 			WHERE b.id=$1 AND c.commit_id=$2`
 		var rawCommit commitLogRaw
 		if err := tx.Get(&rawCommit, query, branchID, ref.CommitID); err != nil {
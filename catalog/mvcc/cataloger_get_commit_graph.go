@@ -0,0 +1,105 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const CommitGraphMaxDepth = 1000
+
+type commitGraphRaw struct {
+	commitLogRaw
+	Depth int `db:"depth"`
+}
+
+func (c *cataloger) GetCommitGraph(ctx context.Context, repository, reference string, depth int) (*catalog.CommitGraph, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+	}); err != nil {
+		return nil, err
+	}
+	if depth <= 0 || depth > CommitGraphMaxDepth {
+		depth = CommitGraphMaxDepth
+	}
+	ref, err := c.resolveRef(c.db.WithContext(ctx), repository, reference)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		commitID := ref.CommitID
+		if commitID == UncommittedID || commitID == CommittedID {
+			commitID, err = getLastCommitIDByBranchID(tx, branchID)
+			if err != nil {
+				return nil, fmt.Errorf("get last commit id: %w", err)
+			}
+		}
+		// walk parents on the same branch and merge sources up to depth, keeping the nearest
+		// depth of each commit reached by several paths
+		var rawCommits []commitGraphRaw
+		err = tx.Select(&rawCommits, `WITH RECURSIVE graph (branch_id, commit_id, depth) AS (
+				SELECT $1::bigint, $2::bigint, 0
+			UNION
+				SELECT p.branch_id, p.commit_id, g.depth + 1
+				FROM graph g
+					JOIN catalog_commits c ON c.branch_id = g.branch_id AND c.commit_id = g.commit_id
+					CROSS JOIN LATERAL (VALUES (c.branch_id::bigint, c.previous_commit_id::bigint),
+						(CASE WHEN c.squash THEN NULL ELSE c.merge_source_branch::bigint END, c.merge_source_commit::bigint))
+						AS p (branch_id, commit_id)
+				WHERE g.depth < $3 AND p.branch_id IS NOT NULL AND p.commit_id > 0
+			)
+			SELECT b.name AS branch_name, c.commit_id, c.previous_commit_id, c.committer, c.message, c.creation_date, c.metadata,
+				COALESCE(bb.name,'') AS merge_source_branch_name, COALESCE(c.merge_source_commit,0) AS merge_source_commit, g.depth
+			FROM (SELECT branch_id, commit_id, min(depth) AS depth FROM graph GROUP BY branch_id, commit_id) g
+				JOIN catalog_commits c ON c.branch_id = g.branch_id AND c.commit_id = g.commit_id
+				JOIN catalog_branches b ON b.id = c.branch_id
+				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch AND NOT c.squash
+			ORDER BY g.depth, c.commit_id DESC`,
+			branchID, commitID, depth)
+		if err != nil {
+			return nil, fmt.Errorf("select commit graph: %w", err)
+		}
+		if len(rawCommits) == 0 {
+			return nil, db.ErrNotFound
+		}
+		return newCommitGraph(rawCommits), nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.CommitGraph), nil
+}
+
+// newCommitGraph returns the graph of rawCommits, with the edges to parents found in rawCommits
+func newCommitGraph(rawCommits []commitGraphRaw) *catalog.CommitGraph {
+	graph := &catalog.CommitGraph{
+		Commits: make([]*catalog.CommitLog, len(rawCommits)),
+	}
+	found := make(map[string]struct{}, len(rawCommits))
+	for i, raw := range rawCommits {
+		graph.Commits[i] = convertRawCommit(raw.commitLogRaw)
+		found[graph.Commits[i].Reference] = struct{}{}
+	}
+	addEdge := func(child, parent string, merge bool) {
+		if _, ok := found[parent]; ok {
+			graph.Edges = append(graph.Edges, catalog.CommitGraphEdge{Child: child, Parent: parent, Merge: merge})
+		}
+	}
+	for i, raw := range rawCommits {
+		child := graph.Commits[i].Reference
+		if raw.PreviousCommitID > 0 {
+			addEdge(child, MakeReference(raw.BranchName, raw.PreviousCommitID), false)
+		}
+		if raw.MergeSourceBranchName != "" && raw.MergeSourceCommit > 0 {
+			addEdge(child, MakeReference(raw.MergeSourceBranchName, raw.MergeSourceCommit), true)
+		}
+	}
+	return graph
+}
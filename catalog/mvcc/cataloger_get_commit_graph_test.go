@@ -0,0 +1,66 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_GetCommitGraph(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	masterCommit, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	branchCommit, err := c.CreateBranch(ctx, repository, "branch1", "master")
+	testutil.MustDo(t, "create branch", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "")
+	changeCommit, err := c.Commit(ctx, repository, "branch1", "commit to branch1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	merge, err := c.Merge(ctx, repository, "branch1", "master", "tester", "merge branch1", nil, catalog.MergeParams{})
+	testutil.MustDo(t, "merge branch1 to master", err)
+
+	graph, err := c.GetCommitGraph(ctx, repository, "master", 2)
+	testutil.MustDo(t, "get commit graph", err)
+	if len(graph.Commits) == 0 || graph.Commits[0].Reference != merge.Reference {
+		t.Fatalf("commit graph %+v, expected to start at merge %s", graph.Commits, merge.Reference)
+	}
+	commits := make(map[string]struct{})
+	for _, commit := range graph.Commits {
+		commits[commit.Reference] = struct{}{}
+	}
+	for _, reference := range []string{masterCommit.Reference, branchCommit.Reference, changeCommit.Reference} {
+		if _, ok := commits[reference]; !ok {
+			t.Errorf("commit graph missing commit %s", reference)
+		}
+	}
+	edges := make(map[catalog.CommitGraphEdge]struct{})
+	for _, edge := range graph.Edges {
+		edges[edge] = struct{}{}
+	}
+	for _, edge := range []catalog.CommitGraphEdge{
+		{Child: merge.Reference, Parent: masterCommit.Reference},
+		{Child: merge.Reference, Parent: changeCommit.Reference, Merge: true},
+		{Child: changeCommit.Reference, Parent: branchCommit.Reference},
+		{Child: branchCommit.Reference, Parent: masterCommit.Reference, Merge: true},
+	} {
+		if _, ok := edges[edge]; !ok {
+			t.Errorf("commit graph missing edge %+v", edge)
+		}
+	}
+
+	graph, err = c.GetCommitGraph(ctx, repository, merge.Reference, 1)
+	testutil.MustDo(t, "get commit graph of depth 1", err)
+	if len(graph.Commits) != 3 || len(graph.Edges) != 2 {
+		t.Errorf("commit graph of depth 1 has %d commits and %d edges, expected 3 and 2", len(graph.Commits), len(graph.Edges))
+	}
+
+	if _, err := c.GetCommitGraph(ctx, repository, "missing", 1); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("get commit graph of missing branch err=%v, expected %s", err, db.ErrNotFound)
+	}
+}
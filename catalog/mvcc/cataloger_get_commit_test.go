@@ -30,7 +30,7 @@ func TestCataloger_GetCommit(t *testing.T) {
 		msg := "Commit" + n
 		committer := "tester" + n
 		testCatalogerCreateEntry(t, ctx, c, repository, testBranch, testPath, meta, "")
-		commitLog, err := c.Commit(ctx, repository, testBranch, msg, committer, meta)
+		commitLog, err := c.Commit(ctx, repository, testBranch, msg, committer, meta, catalog.CommitParams{})
 		testutil.MustDo(t, "commit "+msg, err)
 		refs[i] = commitLog.Reference
 	}
@@ -140,7 +140,7 @@ func TestCataloger_GetMergeCommit(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repo, "master", "/file"+strconv.Itoa(i), nil, "master")
 	}
-	_, err := c.Commit(ctx, repo, "master", "commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repo, "master", "commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to master", err)
 
 	// prepare data on b1
@@ -149,11 +149,11 @@ func TestCataloger_GetMergeCommit(t *testing.T) {
 	for i := 2; i < 6; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repo, "b1", "/file"+strconv.Itoa(i), nil, "b1")
 	}
-	_, err = c.Commit(ctx, repo, "b1", "commit to branch", "tester", nil)
+	_, err = c.Commit(ctx, repo, "b1", "commit to branch", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to b1", err)
 
 	// merge b1 to master
-	res, err := c.Merge(ctx, repo, "b1", "master", "tester", "merge b1 to master", nil)
+	res, err := c.Merge(ctx, repo, "b1", "master", "tester", "merge b1 to master", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge b1 to master", err)
 
 	// test commit on master got two parents
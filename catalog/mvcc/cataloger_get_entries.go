@@ -0,0 +1,108 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// getEntriesRow is an entry read by GetEntries with the index of the reference it was read from
+type getEntriesRow struct {
+	RefIndex int `db:"ref_index"`
+	catalog.Entry
+}
+
+// getEntriesRef is a reference read by GetEntries with its paths
+type getEntriesRef struct {
+	ref   *Ref
+	paths []string
+	// index of each path in the requested EntryRefs
+	indexes map[string][]int
+}
+
+func (c *cataloger) GetEntries(ctx context.Context, repository string, refs []catalog.EntryRef, params catalog.GetEntryParams) ([]*catalog.Entry, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	// group the paths by reference, keeping the order references first appear in
+	var references []*getEntriesRef
+	byReference := make(map[string]*getEntriesRef)
+	for i, entryRef := range refs {
+		if err := Validate(ValidateFields{
+			{Name: "reference", IsValid: ValidateReference(entryRef.Reference)},
+		}); err != nil {
+			return nil, err
+		}
+		if entryRef.Path == "" {
+			continue
+		}
+		r, ok := byReference[entryRef.Reference]
+		if !ok {
+			ref, err := c.resolveRef(c.db.WithContext(ctx), repository, entryRef.Reference)
+			if err != nil {
+				return nil, err
+			}
+			r = &getEntriesRef{ref: ref, indexes: make(map[string][]int)}
+			byReference[entryRef.Reference] = r
+			references = append(references, r)
+		}
+		if _, ok := r.indexes[entryRef.Path]; !ok {
+			r.paths = append(r.paths, entryRef.Path)
+		}
+		r.indexes[entryRef.Path] = append(r.indexes[entryRef.Path], i)
+	}
+	entries := make([]*catalog.Entry, len(refs))
+	if len(references) == 0 {
+		return entries, nil
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		// union the lineage select of each reference into a single query
+		var query sq.SelectBuilder
+		for i, r := range references {
+			branchID, err := c.getBranchIDCache(tx, repository, r.ref.Branch)
+			if err != nil {
+				return nil, err
+			}
+			readExpr, err := sqEntryLineageSelect(tx, branchID, r.ref.CommitID, true, r.paths)
+			if err != nil {
+				return nil, fmt.Errorf("lineage select: %w", err)
+			}
+			readExpr = readExpr.Column("?::int AS ref_index", i)
+			if i == 0 {
+				query = readExpr.Prefix("(").Suffix(")")
+			} else {
+				query = query.SuffixExpr(sq.ConcatExpr(" UNION ALL (", readExpr, ")"))
+			}
+		}
+		sql, args, err := query.PlaceholderFormat(sq.Dollar).ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var rows []*getEntriesRow
+		if err := tx.Select(&rows, sql, args...); err != nil {
+			return nil, fmt.Errorf("select entries: %w", err)
+		}
+		return rows, nil
+	}, c.txOpts(ctx, db.ReadOnly(), db.WithIsolationLevel(pgx.ReadCommitted))...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range res.([]*getEntriesRow) {
+		if row.Expired && !params.ReturnExpired {
+			continue
+		}
+		for _, i := range references[row.RefIndex].indexes[row.Path] {
+			entry := row.Entry
+			entries[i] = &entry
+		}
+	}
+	return entries, nil
+}
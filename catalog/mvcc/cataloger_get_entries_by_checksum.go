@@ -0,0 +1,43 @@
+package mvcc
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const GetEntriesByChecksumMaxLimit = 1000
+
+func (c *cataloger) GetEntriesByChecksum(ctx context.Context, repository, reference string, checksum string, after string, limit int) ([]*catalog.Entry, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "checksum", IsValid: func() bool { return IsNonEmptyString(checksum) }},
+	}); err != nil {
+		return nil, false, err
+	}
+	ref, err := c.resolveRef(c.db.WithContext(ctx), repository, reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > GetEntriesByChecksumMaxLimit {
+		limit = GetEntriesByChecksumMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		return findEntries(tx, branchID, ref.CommitID, "checksum = $1", []interface{}{checksum}, after, limit,
+			func(entry *catalog.Entry) bool {
+				return entry.Checksum == checksum
+			})
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	entries := res.([]*catalog.Entry)
+	hasMore := paginateSlice(&entries, limit)
+	return entries, hasMore, nil
+}
@@ -0,0 +1,65 @@
+package mvcc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_GetEntriesByChecksum(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	const checksum = "c0ffee"
+	createEntry := func(branch, path, checksum string) {
+		testutil.MustDo(t, "create entry "+path, c.CreateEntry(ctx, repository, branch, catalog.Entry{
+			Path:            path,
+			Checksum:        checksum,
+			PhysicalAddress: "address-" + checksum,
+			Size:            7,
+		}, catalog.CreateEntryParams{}))
+	}
+	createEntry("master", "data/a", checksum)
+	createEntry("master", "data/b", checksum)
+	createEntry("master", "other/c", "another")
+	createEntry("master", "copies/d", checksum)
+	_, err := c.Commit(ctx, repository, "master", "add entries", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+
+	// the branch reads copies of the parent, and changes some of them
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	createEntry("branch1", "data/b", "changed")
+	testutil.MustDo(t, "delete copies/d", c.DeleteEntry(ctx, repository, "branch1", "copies/d"))
+	createEntry("branch1", "new/e", checksum)
+
+	tests := []struct {
+		name      string
+		reference string
+		after     string
+		limit     int
+		expected  []string
+		more      bool
+	}{
+		{name: "committed", reference: "master", limit: -1, expected: []string{"copies/d", "data/a", "data/b"}},
+		{name: "first page", reference: "master", limit: 2, expected: []string{"copies/d", "data/a"}, more: true},
+		{name: "next page", reference: "master", after: "data/a", limit: 2, expected: []string{"data/b"}},
+		{name: "branch", reference: "branch1", limit: -1, expected: []string{"data/a", "new/e"}},
+		{name: "branch committed", reference: "branch1:HEAD", limit: -1, expected: []string{"copies/d", "data/a", "data/b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, more, err := c.GetEntriesByChecksum(ctx, repository, tt.reference, checksum, tt.after, tt.limit)
+			testutil.MustDo(t, "get entries by checksum", err)
+			paths := make([]string, len(entries))
+			for i, entry := range entries {
+				paths[i] = entry.Path
+			}
+			if diff := deep.Equal(paths, tt.expected); diff != nil || more != tt.more {
+				t.Errorf("got %v more=%t, expected %v more=%t: %s", paths, more, tt.expected, tt.more, diff)
+			}
+		})
+	}
+}
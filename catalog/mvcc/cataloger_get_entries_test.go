@@ -0,0 +1,67 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_GetEntries(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := setupReadEntryData(t, ctx, c)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testutil.MustDo(t, "create entry on branch1", c.CreateEntry(ctx, repository, "branch1", catalog.Entry{
+		Path:            "/file1",
+		Checksum:        "bb",
+		PhysicalAddress: "/addr1b",
+		Size:            4,
+	}, catalog.CreateEntryParams{}))
+
+	refs := []catalog.EntryRef{
+		{Reference: "master", Path: "/file3"},
+		{Reference: "master:HEAD", Path: "/file2"},
+		{Reference: "master:HEAD", Path: "/file3"},
+		{Reference: "master", Path: "/fileX"},
+		{Reference: "branch1", Path: "/file1"},
+		{Reference: "branch1", Path: "/file2"},
+		{Reference: "master", Path: "/file1"},
+		{Reference: "master", Path: "/file3"},
+	}
+	wantAddresses := []string{"/addr3", "/addr2", "", "", "/addr1b", "/addr2", "/addr1", "/addr3"}
+
+	entries, err := c.GetEntries(ctx, repository, refs, catalog.GetEntryParams{})
+	testutil.MustDo(t, "get entries", err)
+	if len(entries) != len(refs) {
+		t.Fatalf("GetEntries() got %d entries, expected %d", len(entries), len(refs))
+	}
+	for i, want := range wantAddresses {
+		got := entries[i]
+		switch {
+		case want == "" && got != nil:
+			t.Errorf("GetEntries() entry %d %+v got %+v, expected none", i, refs[i], got)
+		case want == "":
+		case got == nil:
+			t.Errorf("GetEntries() entry %d %+v not found, expected %s", i, refs[i], want)
+		case got.Path != refs[i].Path || got.PhysicalAddress != want:
+			t.Errorf("GetEntries() entry %d %+v got %s %s, expected %s", i, refs[i], got.Path, got.PhysicalAddress, want)
+		}
+	}
+
+	t.Run("unknown branch", func(t *testing.T) {
+		_, err := c.GetEntries(ctx, repository, []catalog.EntryRef{{Reference: "branch2", Path: "/file1"}}, catalog.GetEntryParams{})
+		if !errors.Is(err, catalog.ErrBranchNotFound) {
+			t.Fatalf("GetEntries() err=%v, expected=%s", err, catalog.ErrBranchNotFound)
+		}
+	})
+
+	t.Run("empty reference", func(t *testing.T) {
+		_, err := c.GetEntries(ctx, repository, []catalog.EntryRef{{Reference: "", Path: "/file1"}}, catalog.GetEntryParams{})
+		if !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Fatalf("GetEntries() err=%v, expected=%s", err, catalog.ErrInvalidValue)
+		}
+	})
+}
@@ -22,7 +22,7 @@ func (c *cataloger) GetEntry(ctx context.Context, repository, reference string,
 	if path == "" {
 		return nil, db.ErrNotFound
 	}
-	ref, err := ParseRef(reference)
+	ref, err := c.resolveRef(c.db.WithContext(ctx), repository, reference)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +76,8 @@ func (c *cataloger) getEntryMaybeExpired(ctx context.Context, repository string,
 		}
 
 		sql, args, err := psql.
-			Select("path", "physical_address", "creation_date", "size", "checksum", "metadata", "is_expired").
+			Select("path", "physical_address", "creation_date", "size", "checksum", "metadata", "is_expired",
+				"content_type", "content_encoding", "cache_control").
 			FromSelect(sqEntriesLineage(branchID, ref.CommitID, lineage), "entries").
 			Where(sq.Eq{"path": path, "is_deleted": false}).
 			ToSql()
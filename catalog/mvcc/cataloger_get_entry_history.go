@@ -0,0 +1,113 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const GetEntryHistoryMaxLimit = 1000
+
+// entryHistoryRaw is a commit changing an entry, read by GetEntryHistory
+type entryHistoryRaw struct {
+	commitLogRaw
+	BranchID        int64  `db:"branch_id"`
+	IsRemoved       bool   `db:"is_removed"`
+	PhysicalAddress string `db:"physical_address"`
+	Size            int64  `db:"size"`
+	Checksum        string `db:"checksum"`
+}
+
+func (c *cataloger) GetEntryHistory(ctx context.Context, repository, reference string, path string, after string, limit int) ([]*catalog.EntryHistoryRecord, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "path", IsValid: ValidatePath(path)},
+		{Name: "after", IsValid: ValidateOptionalString(after, IsValidReference)},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > GetEntryHistoryMaxLimit {
+		limit = GetEntryHistoryMaxLimit
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, commitID, err := c.resolveCommit(tx, repository, reference)
+		if err != nil {
+			return nil, err
+		}
+		afterCommitID := MaxCommitID
+		if after != "" {
+			_, afterCommitID, err = c.resolveCommit(tx, repository, after)
+			if err != nil {
+				return nil, fmt.Errorf("after: %w", err)
+			}
+		}
+		cte, err := commitsLineageCTE(tx, branchID, commitID)
+		if err != nil {
+			return nil, err
+		}
+		// a commit changes the entry by adding a version of it, a committed tombstone when
+		// removing an entry of another branch, or by ending the version it follows
+		query := cte + `, versions AS (
+				SELECT branch_id, min_commit, max_commit, physical_address, size, checksum
+				FROM catalog_entries
+				WHERE path = $1 AND min_commit < $2 AND branch_id IN (SELECT branch_id FROM lineage_graph)
+			), changes AS (
+				SELECT branch_id, min_commit AS commit_id, max_commit = 0 AS is_removed, physical_address, size, checksum
+				FROM versions
+				UNION ALL
+				SELECT v.branch_id, c.commit_id, true AS is_removed, '', 0, ''
+				FROM versions v JOIN catalog_commits c ON c.branch_id = v.branch_id AND c.previous_commit_id = v.max_commit
+				WHERE v.max_commit > 0 AND v.max_commit < $2
+					AND NOT EXISTS (SELECT 1 FROM versions w WHERE w.branch_id = v.branch_id AND w.min_commit = c.commit_id)
+			)
+			SELECT b.name as branch_name,c.commit_id,c.previous_commit_id,c.committer,c.message,c.creation_date,c.metadata,
+				COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit,
+				ch.branch_id,ch.is_removed,ch.physical_address,ch.size,ch.checksum
+			FROM changes ch JOIN catalog_commits c ON c.branch_id = ch.branch_id AND c.commit_id = ch.commit_id
+				JOIN catalog_branches b ON b.id = c.branch_id
+				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch AND NOT c.squash
+			WHERE c.commit_id < $3
+				AND EXISTS (SELECT 1 FROM lineage_graph l WHERE l.branch_id = c.branch_id AND c.commit_id <= l.commit_id)
+			ORDER BY c.commit_id DESC
+			LIMIT $4`
+		var rawHistory []entryHistoryRaw
+		if err := tx.Select(&rawHistory, query, path, MaxCommitID, afterCommitID, limit+1); err != nil {
+			return nil, err
+		}
+
+		history := make([]*catalog.EntryHistoryRecord, len(rawHistory))
+		for i, raw := range rawHistory {
+			record := &catalog.EntryHistoryRecord{
+				Commit: convertRawCommit(raw.commitLogRaw),
+				Type:   catalog.DifferenceTypeRemoved,
+			}
+			if !raw.IsRemoved {
+				record.Type = catalog.DifferenceTypeAdded
+				record.PhysicalAddress = raw.PhysicalAddress
+				record.Size = raw.Size
+				record.Checksum = raw.Checksum
+				if raw.PreviousCommitID > 0 {
+					previous, err := readEntriesAt(tx, raw.BranchID, raw.PreviousCommitID, []string{path})
+					if err != nil {
+						return nil, err
+					}
+					if previous[path] != nil {
+						record.Type = catalog.DifferenceTypeChanged
+					}
+				}
+			}
+			history[i] = record
+		}
+		return history, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	history := res.([]*catalog.EntryHistoryRecord)
+	hasMore := paginateSlice(&history, limit)
+	return history, hasMore, nil
+}
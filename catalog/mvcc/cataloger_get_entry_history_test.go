@@ -0,0 +1,97 @@
+package mvcc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_GetEntryHistory(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	addLog, err := c.Commit(ctx, repository, "master", "add file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit add", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "seed1")
+	changeLog, err := c.Commit(ctx, repository, "master", "change file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit change", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testutil.MustDo(t, "delete file1 on master", c.DeleteEntry(ctx, repository, "master", "file1"))
+	removeLog, err := c.Commit(ctx, repository, "master", "remove file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit remove", err)
+	testutil.MustDo(t, "delete file1 on branch1", c.DeleteEntry(ctx, repository, "branch1", "file1"))
+	branchRemoveLog, err := c.Commit(ctx, repository, "branch1", "remove file1 on branch1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit remove on branch1", err)
+
+	type historyRecord struct {
+		reference string
+		diffType  catalog.DifferenceType
+	}
+	tests := []struct {
+		name      string
+		reference string
+		expected  []historyRecord
+	}{
+		{
+			name:      "master",
+			reference: "master",
+			expected: []historyRecord{
+				{removeLog.Reference, catalog.DifferenceTypeRemoved},
+				{changeLog.Reference, catalog.DifferenceTypeChanged},
+				{addLog.Reference, catalog.DifferenceTypeAdded},
+			},
+		},
+		{
+			name:      "commit",
+			reference: changeLog.Reference,
+			expected: []historyRecord{
+				{changeLog.Reference, catalog.DifferenceTypeChanged},
+				{addLog.Reference, catalog.DifferenceTypeAdded},
+			},
+		},
+		{
+			name:      "child branch",
+			reference: "branch1",
+			expected: []historyRecord{
+				{branchRemoveLog.Reference, catalog.DifferenceTypeRemoved},
+				{changeLog.Reference, catalog.DifferenceTypeChanged},
+				{addLog.Reference, catalog.DifferenceTypeAdded},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history, hasMore, err := c.GetEntryHistory(ctx, repository, tt.reference, "file1", "", -1)
+			testutil.MustDo(t, "get entry history", err)
+			if hasMore || len(history) != len(tt.expected) {
+				t.Fatalf("got %d history records more=%t, expected %d", len(history), hasMore, len(tt.expected))
+			}
+			for i, record := range history {
+				if record.Commit.Reference != tt.expected[i].reference || record.Type != tt.expected[i].diffType {
+					t.Errorf("history record %d of %s type %d, expected %s type %d",
+						i, record.Commit.Reference, record.Type, tt.expected[i].reference, tt.expected[i].diffType)
+				}
+			}
+		})
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		history, hasMore, err := c.GetEntryHistory(ctx, repository, "master", "file1", "", 1)
+		testutil.MustDo(t, "get first page", err)
+		if !hasMore || len(history) != 1 || history[0].Commit.Reference != removeLog.Reference {
+			t.Fatalf("first page %+v more=%t, expected removal with more", history, hasMore)
+		}
+		history, _, err = c.GetEntryHistory(ctx, repository, "master", "file1", history[0].Commit.Reference, 1)
+		testutil.MustDo(t, "get second page", err)
+		if len(history) != 1 || history[0].Commit.Reference != changeLog.Reference {
+			t.Errorf("second page %+v, expected change", history)
+		}
+		if history[0].Checksum == "" || history[0].PhysicalAddress == "" {
+			t.Errorf("changed record %+v, expected the object it set", history[0])
+		}
+	})
+}
@@ -129,7 +129,7 @@ func setupReadEntryData(t *testing.T, ctx context.Context, c catalog.Cataloger)
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("failed to create entry", err)
 	}
-	if _, err := c.Commit(ctx, repository, "master", "commit file1 and 2", "tester", nil); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "commit file1 and 2", "tester", nil, catalog.CommitParams{}); err != nil {
 		t.Fatal("failed to commit for get entry:", err)
 	}
 	if err := c.CreateEntry(ctx, repository, "master", catalog.Entry{
@@ -152,3 +152,56 @@ func setupReadEntryData(t *testing.T, ctx context.Context, c catalog.Cataloger)
 	}
 	return repository
 }
+
+func TestCataloger_GetEntry_Headers(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	if err := c.CreateEntry(ctx, repository, "master", catalog.Entry{
+		Path:            "/file1.json.gz",
+		Checksum:        "ff",
+		PhysicalAddress: "/addr1",
+		Size:            42,
+		ContentType:     "application/json",
+		ContentEncoding: "gzip",
+		CacheControl:    "max-age=3600",
+	}, catalog.CreateEntryParams{}); err != nil {
+		t.Fatal("failed to create entry", err)
+	}
+	if err := c.CreateEntry(ctx, repository, "master", catalog.Entry{
+		Path:            "/file2",
+		Checksum:        "ee",
+		PhysicalAddress: "/addr2",
+		Size:            24,
+	}, catalog.CreateEntryParams{}); err != nil {
+		t.Fatal("failed to create entry", err)
+	}
+	if _, err := c.Commit(ctx, repository, "master", "commit files", "tester", nil, catalog.CommitParams{}); err != nil {
+		t.Fatal("failed to commit for get entry:", err)
+	}
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	tests := []struct {
+		reference string
+		path      string
+		want      catalog.Entry
+	}{
+		{reference: "master", path: "/file1.json.gz", want: catalog.Entry{ContentType: "application/json", ContentEncoding: "gzip", CacheControl: "max-age=3600"}},
+		{reference: "master:HEAD", path: "/file1.json.gz", want: catalog.Entry{ContentType: "application/json", ContentEncoding: "gzip", CacheControl: "max-age=3600"}},
+		{reference: "branch1", path: "/file1.json.gz", want: catalog.Entry{ContentType: "application/json", ContentEncoding: "gzip", CacheControl: "max-age=3600"}},
+		{reference: "master", path: "/file2", want: catalog.Entry{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference+tt.path, func(t *testing.T) {
+			got, err := c.GetEntry(ctx, repository, tt.reference, tt.path, catalog.GetEntryParams{})
+			if err != nil {
+				t.Fatalf("GetEntry() error = %s", err)
+			}
+			if got.ContentType != tt.want.ContentType || got.ContentEncoding != tt.want.ContentEncoding || got.CacheControl != tt.want.CacheControl {
+				t.Errorf("GetEntry() got headers (%q, %q, %q), want (%q, %q, %q)",
+					got.ContentType, got.ContentEncoding, got.CacheControl,
+					tt.want.ContentType, tt.want.ContentEncoding, tt.want.CacheControl)
+			}
+		})
+	}
+}
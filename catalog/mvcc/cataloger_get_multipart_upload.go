@@ -22,7 +22,8 @@ func (c *cataloger) GetMultipartUpload(ctx context.Context, repository string, u
 		}
 		var m catalog.MultipartUpload
 		if err := tx.Get(&m, `
-			SELECT r.name as repository, m.upload_id, m.path, m.creation_date, m.physical_address 
+			SELECT r.name as repository, m.upload_id, m.path, m.creation_date, m.physical_address,
+				m.content_type, m.content_encoding, m.cache_control, m.metadata
 			FROM catalog_multipart_uploads m, catalog_repositories r
 			WHERE r.id = m.repository_id AND m.repository_id = $1 AND m.upload_id = $2`,
 			repoID, uploadID); err != nil {
@@ -18,7 +18,9 @@ func TestCataloger_GetMultipartUpload(t *testing.T) {
 	if _, err := c.CreateRepository(ctx, "repo1", "s3://bucket1", "master"); err != nil {
 		t.Fatal("create repository for testing failed", err)
 	}
-	if err := c.CreateMultipartUpload(ctx, "repo1", "upload1", "/path1", "/file1", creationTime); err != nil {
+	if err := c.CreateMultipartUpload(ctx, "repo1", "upload1", "/path1", "/file1", creationTime, catalog.CreateMultipartUploadParams{
+		Metadata: catalog.Metadata{"owner": "data"},
+	}); err != nil {
 		t.Fatal("create multipart upload for testing", err)
 	}
 
@@ -41,6 +43,7 @@ func TestCataloger_GetMultipartUpload(t *testing.T) {
 				Path:            "/path1",
 				CreationDate:    creationTime,
 				PhysicalAddress: "/file1",
+				Metadata:        catalog.Metadata{"owner": "data"},
 			},
 			wantErr: false,
 		},
@@ -0,0 +1,79 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	PrefixStatsMaxDepth   = 10
+	PrefixStatsMaxResults = 10000
+)
+
+func (c *cataloger) GetPrefixStats(ctx context.Context, repository, reference string, prefix string, depth int) ([]*catalog.PrefixStats, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "depth", IsValid: func() bool { return depth >= 0 }},
+	}); err != nil {
+		return nil, err
+	}
+	ref, err := c.resolveRef(c.db.WithContext(ctx), repository, reference)
+	if err != nil {
+		return nil, err
+	}
+	if depth > PrefixStatsMaxDepth {
+		depth = PrefixStatsMaxDepth
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		lineage, err := getLineage(tx, branchID, ref.CommitID)
+		if err != nil {
+			return nil, fmt.Errorf("get lineage: %w", err)
+		}
+		// each object counts once at every level from prefix down to its own directory, so the
+		// stats of a prefix include those of its sub-prefixes
+		entriesQ := psql.Select().
+			Column(sq.Expr("substr(path, ?) AS rest", utf8.RuneCountInString(prefix)+1)).
+			Column("size").
+			FromSelect(sqEntriesLineage(branchID, ref.CommitID, lineage), "entries").
+			Where(sq.And{sq.Like{"path": db.Prefix(prefix)}, sq.Eq{"is_deleted": false}})
+		statsSQL, args, err := psql.Select().
+			Column(sq.Expr(`? || CASE WHEN level = 0 THEN '' ELSE array_to_string((string_to_array(rest, '/'))[1:level], '/') || '/' END AS prefix`, prefix)).
+			Column("count(*) AS objects").
+			Column("sum(size)::bigint AS size_bytes").
+			FromSelect(entriesQ, "e").
+			JoinClause(sq.Expr(`CROSS JOIN LATERAL generate_series(0, LEAST(?, length(rest) - length(replace(rest, '/', '')))) AS level`, depth)).
+			GroupBy("1").
+			Limit(PrefixStatsMaxResults + 1).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var stats []*catalog.PrefixStats
+		if err := tx.Select(&stats, statsSQL, args...); err != nil {
+			return nil, err
+		}
+		return stats, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	stats := res.([]*catalog.PrefixStats)
+	if len(stats) > PrefixStatsMaxResults {
+		return nil, fmt.Errorf("more than %d prefixes, use a smaller depth: %w", PrefixStatsMaxResults, catalog.ErrInvalidValue)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Prefix < stats[j].Prefix
+	})
+	return stats, nil
+}
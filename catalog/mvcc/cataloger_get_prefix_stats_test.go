@@ -0,0 +1,109 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_GetPrefixStats(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	for _, entry := range []catalog.Entry{
+		{Path: "a/file1", Size: 1},
+		{Path: "a/b/file2", Size: 2},
+		{Path: "a/b/c/file3", Size: 4},
+		{Path: "a/d/file4", Size: 8},
+		{Path: "e/file5", Size: 16},
+	} {
+		entry.PhysicalAddress = "addr_" + entry.Path
+		entry.Checksum = "ff"
+		testutil.MustDo(t, "create entry "+entry.Path, c.CreateEntry(ctx, repository, "master", entry, catalog.CreateEntryParams{}))
+	}
+	_, err := c.Commit(ctx, repository, "master", "commit", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	// uncommitted removal is seen on the branch but not on the commit
+	testutil.MustDo(t, "delete entry", c.DeleteEntry(ctx, repository, "master", "a/d/file4"))
+
+	tests := []struct {
+		name      string
+		reference string
+		prefix    string
+		depth     int
+		want      []*catalog.PrefixStats
+		wantErr   error
+	}{
+		{
+			name:      "total",
+			reference: "master",
+			want:      []*catalog.PrefixStats{{Prefix: "", Objects: 4, SizeBytes: 23}},
+		},
+		{
+			name:      "prefix",
+			reference: "master",
+			prefix:    "a/",
+			depth:     1,
+			want: []*catalog.PrefixStats{
+				{Prefix: "a/", Objects: 3, SizeBytes: 7},
+				{Prefix: "a/b/", Objects: 2, SizeBytes: 6},
+			},
+		},
+		{
+			name:      "depth",
+			reference: "master",
+			prefix:    "a/",
+			depth:     5,
+			want: []*catalog.PrefixStats{
+				{Prefix: "a/", Objects: 3, SizeBytes: 7},
+				{Prefix: "a/b/", Objects: 2, SizeBytes: 6},
+				{Prefix: "a/b/c/", Objects: 1, SizeBytes: 4},
+			},
+		},
+		{
+			name:      "commit",
+			reference: "master:HEAD",
+			prefix:    "a/",
+			depth:     1,
+			want: []*catalog.PrefixStats{
+				{Prefix: "a/", Objects: 4, SizeBytes: 15},
+				{Prefix: "a/b/", Objects: 2, SizeBytes: 6},
+				{Prefix: "a/d/", Objects: 1, SizeBytes: 8},
+			},
+		},
+		{
+			name:      "no objects",
+			reference: "master",
+			prefix:    "z/",
+			depth:     1,
+			want:      []*catalog.PrefixStats{},
+		},
+		{
+			name:      "negative depth",
+			reference: "master",
+			depth:     -1,
+			wantErr:   catalog.ErrInvalidValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetPrefixStats(ctx, repository, tt.reference, tt.prefix, tt.depth)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetPrefixStats() err=%v, expected %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got == nil {
+				got = []*catalog.PrefixStats{}
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Error("GetPrefixStats() found diff", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const lifecycleCommitMessage = "Expire entries by lifecycle rules"
+
+type repositoryLifecycle struct {
+	catalog.RepositoryLifecycle
+	BranchID int64 `db:"branch_id"`
+}
+
+func (c *cataloger) GetRepositoryLifecycle(ctx context.Context, repository string) (*catalog.RepositoryLifecycle, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		return getRepositoryLifecycle(tx, repoID)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	lifecycle := res.(*repositoryLifecycle).RepositoryLifecycle
+	lifecycle.Repository = repository
+	return &lifecycle, nil
+}
+
+func (c *cataloger) SetRepositoryLifecycle(ctx context.Context, repository string, lifecycle *catalog.RepositoryLifecycle) error {
+	if lifecycle == nil {
+		return fmt.Errorf("lifecycle: %w", catalog.ErrInvalidValue)
+	}
+	fields := ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}
+	if len(lifecycle.Rules) > 0 {
+		fields = append(fields, ValidateField{Name: "branch", IsValid: ValidateBranchName(lifecycle.Branch)})
+	}
+	if err := Validate(fields); err != nil {
+		return err
+	}
+	for i, rule := range lifecycle.Rules {
+		if rule.MaxAgeDays < 0 || rule.MaxVersions < 0 || (rule.MaxAgeDays == 0 && rule.MaxVersions == 0) {
+			return fmt.Errorf("lifecycle rule %d: %w", i, catalog.ErrInvalidValue)
+		}
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		if len(lifecycle.Rules) == 0 {
+			_, err := tx.Exec(`DELETE FROM catalog_repositories_lifecycle WHERE repository_id = $1`, repoID)
+			if err != nil {
+				return nil, fmt.Errorf("delete repository lifecycle: %w", err)
+			}
+			return nil, nil
+		}
+		if lifecycle.Branch == catalog.DefaultImportBranchName {
+			return nil, fmt.Errorf("lifecycle of import branch: %w", catalog.ErrOperationNotPermitted)
+		}
+		branchID, err := getBranchID(tx, repository, lifecycle.Branch, LockTypeNone)
+		if err != nil {
+			return nil, fmt.Errorf("branch: %w", err)
+		}
+		_, err = tx.Exec(`INSERT INTO catalog_repositories_lifecycle (repository_id, branch_id, rules)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (repository_id)
+			DO UPDATE SET (branch_id, rules) = (EXCLUDED.branch_id, EXCLUDED.rules)`,
+			repoID, branchID, lifecycle.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("set repository lifecycle: %w", err)
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func (c *cataloger) ListRepositoryLifecycles(ctx context.Context) ([]*catalog.RepositoryLifecycle, error) {
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var lifecycles []*catalog.RepositoryLifecycle
+		err := tx.Select(&lifecycles, `SELECT r.name AS repository, b.name AS branch, l.rules
+			FROM catalog_repositories_lifecycle l
+				JOIN catalog_repositories r ON r.id = l.repository_id
+				JOIN catalog_branches b ON b.id = l.branch_id
+			ORDER BY r.name`)
+		if err != nil {
+			return nil, fmt.Errorf("list repository lifecycles: %w", err)
+		}
+		return lifecycles, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.([]*catalog.RepositoryLifecycle), nil
+}
+
+// ExpireLifecycle removes the committed entries expired by the lifecycle rules of repository
+// from its lifecycle branch in a single commit.  Expired paths with uncommitted changes are
+// skipped.  On dryRun the expired entries are only reported.
+func (c *cataloger) ExpireLifecycle(ctx context.Context, repository string, dryRun bool) (*catalog.LifecycleReport, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	txOpts := c.txOpts(ctx, db.ReadCommitted())
+	lockType := LockTypeUpdate
+	if dryRun {
+		txOpts = c.txOpts(ctx, db.ReadOnly())
+		lockType = LockTypeNone
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if !dryRun {
+			if err := checkRepositoryWritable(tx, repository); err != nil {
+				return nil, err
+			}
+		}
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		lifecycle, err := getRepositoryLifecycle(tx, repoID)
+		if err != nil {
+			return nil, err
+		}
+		report := &catalog.LifecycleReport{Repository: repository, Branch: lifecycle.Branch}
+		if len(lifecycle.Rules) == 0 {
+			return report, nil
+		}
+		branchID, err := getBranchID(tx, repository, lifecycle.Branch, lockType)
+		if err != nil {
+			return nil, fmt.Errorf("branch: %w", err)
+		}
+		expired, err := expiredLifecycleEntries(tx, branchID, lifecycle.Rules)
+		if err != nil {
+			return nil, err
+		}
+		if len(expired) == 0 {
+			return report, nil
+		}
+		paths := make([]string, len(expired))
+		for i, expiration := range expired {
+			paths[i] = expiration.Path
+		}
+		var uncommittedPaths []string
+		err = tx.Select(&uncommittedPaths, `SELECT DISTINCT path FROM catalog_entries_v
+			WHERE branch_id = $1 AND NOT is_committed AND path = ANY($2::text[])
+			ORDER BY path`, branchID, paths)
+		if err != nil {
+			return nil, fmt.Errorf("uncommitted paths: %w", err)
+		}
+		uncommitted := make(map[string]struct{}, len(uncommittedPaths))
+		for _, path := range uncommittedPaths {
+			uncommitted[path] = struct{}{}
+		}
+		report.Skipped = uncommittedPaths
+		changes := make([]*commitChange, 0, len(expired))
+		for _, expiration := range expired {
+			if _, ok := uncommitted[expiration.Path]; ok {
+				continue
+			}
+			report.Expired = append(report.Expired, expiration)
+			changes = append(changes, &commitChange{
+				Difference: catalog.Difference{Type: catalog.DifferenceTypeRemoved, Entry: catalog.Entry{Path: expiration.Path}},
+			})
+		}
+		if dryRun || len(changes) == 0 {
+			return report, nil
+		}
+
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+		if err := trashEntries(tx, repoID, branchID, changePaths(changes)); err != nil {
+			return nil, err
+		}
+		previousMaxCommitID, err := getLastCommitIDByBranchID(tx, branchID)
+		if err != nil {
+			return nil, fmt.Errorf("last commit id: %w", err)
+		}
+		nextCommitID, err := getNextCommitID(tx)
+		if err != nil {
+			return nil, fmt.Errorf("next commit id: %w", err)
+		}
+		for i := 0; i < len(changes); i += MergeBatchSize {
+			end := i + MergeBatchSize
+			if end > len(changes) {
+				end = len(changes)
+			}
+			if err := applyChangesToBranch(tx, branchID, previousMaxCommitID, nextCommitID, changes[i:end]); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := tx.Exec(`INSERT INTO catalog_commits (branch_id,commit_id,committer,message,creation_date,merge_type,previous_commit_id)
+			VALUES ($1,$2,$3,$4,transaction_timestamp(),$5,$6)`,
+			branchID, nextCommitID, catalog.LifecycleCommitter, lifecycleCommitMessage, RelationTypeNone, previousMaxCommitID,
+		); err != nil {
+			return nil, fmt.Errorf("insert commit: %w", err)
+		}
+		report.Reference = MakeReference(lifecycle.Branch, nextCommitID)
+		return report, nil
+	}, txOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.LifecycleReport), nil
+}
+
+func getRepositoryLifecycle(tx db.Tx, repositoryID int) (*repositoryLifecycle, error) {
+	var lifecycle repositoryLifecycle
+	err := tx.Get(&lifecycle, `SELECT b.name AS branch, l.branch_id, l.rules
+		FROM catalog_repositories_lifecycle l JOIN catalog_branches b ON b.id = l.branch_id
+		WHERE l.repository_id = $1`, repositoryID)
+	if errors.Is(err, db.ErrNotFound) {
+		return &lifecycle, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get repository lifecycle: %w", err)
+	}
+	return &lifecycle, nil
+}
+
+// expiredLifecycleEntries returns the committed entries of branchID expired by rules, ordered
+// by path.  An entry matched by several rules is reported with the first of them.
+func expiredLifecycleEntries(tx db.Tx, branchID int64, rules catalog.LifecycleRules) ([]catalog.LifecycleExpiration, error) {
+	lineage, err := getLineage(tx, branchID, CommittedID)
+	if err != nil {
+		return nil, fmt.Errorf("get lineage: %w", err)
+	}
+	expiredByPath := make(map[string]catalog.LifecycleExpiration)
+	for _, rule := range rules {
+		ranked := psql.
+			Select("path", "creation_date").
+			Column("row_number() OVER (ORDER BY creation_date DESC, path DESC) AS version").
+			FromSelect(sqEntriesLineageV(branchID, CommittedID, lineage), "entries").
+			Where(sq.And{sq.Eq{"is_deleted": false}, sq.Like{"path": db.Prefix(rule.Prefix)}})
+		var expiredExprs sq.Or
+		if rule.MaxAgeDays > 0 {
+			expiredExprs = append(expiredExprs, sq.Expr("creation_date < transaction_timestamp() - make_interval(days => ?::integer)", rule.MaxAgeDays))
+		}
+		if rule.MaxVersions > 0 {
+			expiredExprs = append(expiredExprs, sq.Gt{"version": rule.MaxVersions})
+		}
+		sql, args, err := psql.Select("path", "creation_date").
+			FromSelect(ranked, "ranked").
+			Where(expiredExprs).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var entries []struct {
+			Path         string    `db:"path"`
+			CreationDate time.Time `db:"creation_date"`
+		}
+		if err := tx.Select(&entries, sql, args...); err != nil {
+			return nil, fmt.Errorf("select expired entries: %w", err)
+		}
+		for _, entry := range entries {
+			if _, ok := expiredByPath[entry.Path]; ok {
+				continue
+			}
+			expiredByPath[entry.Path] = catalog.LifecycleExpiration{Path: entry.Path, CreationDate: entry.CreationDate, Rule: rule}
+		}
+	}
+	expired := make([]catalog.LifecycleExpiration, 0, len(expiredByPath))
+	for _, expiration := range expiredByPath {
+		expired = append(expired, expiration)
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].Path < expired[j].Path })
+	return expired, nil
+}
@@ -0,0 +1,127 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_RepositoryLifecycle(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+
+	lifecycle, err := c.GetRepositoryLifecycle(ctx, repository)
+	testutil.MustDo(t, "get default lifecycle", err)
+	if len(lifecycle.Rules) != 0 {
+		t.Errorf("default lifecycle rules %v, expected none", lifecycle.Rules)
+	}
+	expected := &catalog.RepositoryLifecycle{
+		Repository: repository,
+		Branch:     "master",
+		Rules:      catalog.LifecycleRules{{Prefix: "logs/", MaxAgeDays: 30}, {Prefix: "reports/", MaxVersions: 3}},
+	}
+	testutil.MustDo(t, "set lifecycle", c.SetRepositoryLifecycle(ctx, repository, expected))
+	lifecycle, err = c.GetRepositoryLifecycle(ctx, repository)
+	testutil.MustDo(t, "get lifecycle", err)
+	if diff := deep.Equal(lifecycle, expected); diff != nil {
+		t.Error("GetRepositoryLifecycle", diff)
+	}
+	lifecycles, err := c.ListRepositoryLifecycles(ctx)
+	testutil.MustDo(t, "list lifecycles", err)
+	if diff := deep.Equal(lifecycles, []*catalog.RepositoryLifecycle{expected}); diff != nil {
+		t.Error("ListRepositoryLifecycles", diff)
+	}
+
+	err = c.SetRepositoryLifecycle(ctx, repository, &catalog.RepositoryLifecycle{Branch: "master", Rules: catalog.LifecycleRules{{Prefix: "logs/"}}})
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("set rule without limits err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+	err = c.SetRepositoryLifecycle(ctx, repository, &catalog.RepositoryLifecycle{Branch: "missing", Rules: expected.Rules})
+	if !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("set lifecycle of missing branch err=%v, expected %s", err, db.ErrNotFound)
+	}
+
+	testutil.MustDo(t, "remove lifecycle", c.SetRepositoryLifecycle(ctx, repository, &catalog.RepositoryLifecycle{}))
+	lifecycles, err = c.ListRepositoryLifecycles(ctx)
+	testutil.MustDo(t, "list lifecycles after remove", err)
+	if len(lifecycles) != 0 {
+		t.Errorf("lifecycles after remove %v, expected none", lifecycles)
+	}
+}
+
+func TestCataloger_ExpireLifecycle(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+
+	old := time.Now().AddDate(0, 0, -10)
+	for _, path := range []string{"logs/old", "data/old"} {
+		testutil.MustDo(t, "create old entry", c.CreateEntry(ctx, repository, "master", catalog.Entry{
+			Path:            path,
+			Checksum:        "ff",
+			PhysicalAddress: path,
+			CreationDate:    old,
+		}, catalog.CreateEntryParams{}))
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "logs/new", nil, "")
+	for i, path := range []string{"reports/1", "reports/2", "reports/3"} {
+		testutil.MustDo(t, "create report", c.CreateEntry(ctx, repository, "master", catalog.Entry{
+			Path:            path,
+			Checksum:        "ff",
+			PhysicalAddress: path,
+			CreationDate:    old.Add(time.Duration(i) * time.Hour),
+		}, catalog.CreateEntryParams{}))
+	}
+	_, err := c.Commit(ctx, repository, "master", "commit entries", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	// an uncommitted change keeps reports/2 in place
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "reports/2", nil, "changed")
+
+	testutil.MustDo(t, "set lifecycle", c.SetRepositoryLifecycle(ctx, repository, &catalog.RepositoryLifecycle{
+		Branch: "master",
+		Rules:  catalog.LifecycleRules{{Prefix: "logs/", MaxAgeDays: 7}, {Prefix: "reports/", MaxVersions: 1}},
+	}))
+
+	report, err := c.ExpireLifecycle(ctx, repository, true)
+	testutil.MustDo(t, "dry run", err)
+	expiredPaths := func(report *catalog.LifecycleReport) []string {
+		var paths []string
+		for _, expiration := range report.Expired {
+			paths = append(paths, expiration.Path)
+		}
+		return paths
+	}
+	if diff := deep.Equal(expiredPaths(report), []string{"logs/old", "reports/1"}); diff != nil {
+		t.Error("dry run expired", diff)
+	}
+	if diff := deep.Equal(report.Skipped, []string{"reports/2"}); diff != nil {
+		t.Error("dry run skipped", diff)
+	}
+	if report.Reference != "" {
+		t.Errorf("dry run reference %s, expected none", report.Reference)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "logs/old", true)
+
+	report, err = c.ExpireLifecycle(ctx, repository, false)
+	testutil.MustDo(t, "expire", err)
+	if diff := deep.Equal(expiredPaths(report), []string{"logs/old", "reports/1"}); diff != nil {
+		t.Error("expired", diff)
+	}
+	commit, err := c.GetCommit(ctx, repository, report.Reference)
+	testutil.MustDo(t, "get expiration commit", err)
+	if commit.Committer != catalog.LifecycleCommitter {
+		t.Errorf("expiration committer %s, expected %s", commit.Committer, catalog.LifecycleCommitter)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "logs/old", false)
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "reports/1", false)
+	for _, path := range []string{"data/old", "logs/new", "reports/2", "reports/3"} {
+		testCatalogerGetEntry(t, ctx, c, repository, "master", path, true)
+	}
+}
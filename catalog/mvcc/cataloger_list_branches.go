@@ -2,6 +2,7 @@ package mvcc
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
@@ -9,9 +10,24 @@ import (
 
 const ListBranchesMaxLimit = 10000
 
-func (c *cataloger) ListBranches(ctx context.Context, repository string, prefix string, limit int, after string) ([]*catalog.Branch, bool, error) {
+// branchSortTimes are the aggregates of the creation dates of the commits of a branch
+// ordering branches by time, most recent first.  Every branch has a commit from its creation.
+var branchSortTimes = map[catalog.BranchSortBy]string{
+	catalog.BranchSortByLastCommit: "MAX",
+	catalog.BranchSortByCreation:   "MIN",
+}
+
+func ValidateBranchSortBy(sortBy catalog.BranchSortBy) ValidateFunc {
+	return func() bool {
+		_, ok := branchSortTimes[sortBy]
+		return ok || sortBy == "" || sortBy == catalog.BranchSortByName
+	}
+}
+
+func (c *cataloger) ListBranches(ctx context.Context, repository string, prefix string, limit int, after string, params catalog.ListBranchesParams) ([]*catalog.Branch, bool, error) {
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "sortBy", IsValid: ValidateBranchSortBy(params.SortBy)},
 	}); err != nil {
 		return nil, false, err
 	}
@@ -25,15 +41,25 @@ func (c *cataloger) ListBranches(ctx context.Context, repository string, prefix
 			return nil, err
 		}
 
-		query := `SELECT $2 AS repository, name
-			FROM catalog_branches
-			WHERE repository_id = $1 AND name like $3 AND name > $4
-			ORDER BY name
-			LIMIT $5`
 		var branches []*catalog.Branch
-		if err := tx.Select(&branches, query, repoID, repository, prefixCond, after, limit+1); err != nil {
+		if sortTime, ok := branchSortTimes[params.SortBy]; ok {
+			branches, err = listBranchesByTime(tx, repoID, repository, prefixCond, after, limit+1, sortTime)
+		} else {
+			query := `SELECT $2 AS repository, name
+				FROM catalog_branches
+				WHERE repository_id = $1 AND name like $3 AND name > $4
+				ORDER BY name
+				LIMIT $5`
+			err = tx.Select(&branches, query, repoID, repository, prefixCond, after, limit+1)
+		}
+		if err != nil {
 			return nil, err
 		}
+		if params.IncludeStatus {
+			if err := setBranchesStatus(tx, repoID, branches); err != nil {
+				return nil, fmt.Errorf("branches status: %w", err)
+			}
+		}
 		return branches, nil
 	}, c.txOpts(ctx, db.ReadOnly())...)
 
@@ -44,3 +70,90 @@ func (c *cataloger) ListBranches(ctx context.Context, repository string, prefix
 	hasMore := paginateSlice(&branches, limit)
 	return branches, hasMore, nil
 }
+
+// listBranchesByTime selects up to limit branches ordered by the sortTime aggregate of their
+// commit dates, most recent first, then by name.  Branches are listed after the position of
+// branch after in that order.
+func listBranchesByTime(tx db.Tx, repoID int, repository, prefixCond, after string, limit int, sortTime string) ([]*catalog.Branch, error) {
+	query := fmt.Sprintf(`WITH t AS (
+			SELECT b.name, %s(c.creation_date) AS sort_time
+			FROM catalog_branches b JOIN catalog_commits c ON c.branch_id = b.id
+			WHERE b.repository_id = $1
+			GROUP BY b.name)
+		SELECT $2 AS repository, t.name
+		FROM t
+		WHERE t.name like $3 AND ($4 = ''
+			OR t.sort_time < (SELECT sort_time FROM t WHERE name = $4)
+			OR (t.sort_time = (SELECT sort_time FROM t WHERE name = $4) AND t.name > $4))
+		ORDER BY t.sort_time DESC, t.name
+		LIMIT $5`, sortTime)
+	var branches []*catalog.Branch
+	if err := tx.Select(&branches, query, repoID, repository, prefixCond, after, limit); err != nil {
+		return nil, err
+	}
+	if after != "" && len(branches) == 0 {
+		// tell a missing after branch from the end of the listing
+		var exists bool
+		if err := tx.GetPrimitive(&exists, `SELECT EXISTS (SELECT 1 FROM catalog_branches WHERE repository_id = $1 AND name = $2)`, repoID, after); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("after %s: %w", after, catalog.ErrBranchNotFound)
+		}
+	}
+	return branches, nil
+}
+
+// setBranchesStatus reads the head commit, source branch and uncommitted entries count of all
+// branches in one query, then counts the commits each branch is ahead and behind its source
+func setBranchesStatus(tx db.Tx, repoID int, branches []*catalog.Branch) error {
+	if len(branches) == 0 {
+		return nil
+	}
+	names := make([]string, len(branches))
+	for i, branch := range branches {
+		names[i] = branch.Name
+	}
+	var statuses []struct {
+		commitLogRaw
+		BranchID           int64  `db:"branch_id"`
+		SourceBranchID     int64  `db:"source_branch_id"`
+		SourceBranch       string `db:"source_branch"`
+		UncommittedEntries int    `db:"uncommitted_entries"`
+	}
+	err := tx.Select(&statuses, `SELECT b.id AS branch_id, b.name AS branch_name,
+			COALESCE(b.lineage[1], 0) AS source_branch_id, COALESCE(s.name, '') AS source_branch,
+			(SELECT COUNT(*) FROM catalog_entries e WHERE e.branch_id = b.id AND e.min_commit = $3) AS uncommitted_entries,
+			h.commit_id, h.previous_commit_id, h.committer, h.message, h.creation_date, h.metadata,
+			COALESCE(m.name, '') AS merge_source_branch_name, COALESCE(h.merge_source_commit, 0) AS merge_source_commit
+		FROM catalog_branches b
+			LEFT JOIN catalog_branches s ON s.id = b.lineage[1]
+			JOIN LATERAL (SELECT * FROM catalog_commits c WHERE c.branch_id = b.id ORDER BY c.commit_id DESC LIMIT 1) h ON true
+			LEFT JOIN catalog_branches m ON m.id = h.merge_source_branch AND NOT h.squash
+		WHERE b.repository_id = $1 AND b.name = ANY($2::text[])`,
+		repoID, names, MaxCommitID)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*catalog.BranchStatus, len(statuses))
+	for _, s := range statuses {
+		status := &catalog.BranchStatus{
+			HeadCommit:         convertRawCommit(s.commitLogRaw),
+			SourceBranch:       s.SourceBranch,
+			UncommittedEntries: s.UncommittedEntries,
+		}
+		if s.SourceBranchID != 0 {
+			counts, err := diffSummaryCommits(tx, s.BranchID, CommittedID, s.SourceBranchID, CommittedID)
+			if err != nil {
+				return fmt.Errorf("branch %s: %w", s.BranchName, err)
+			}
+			status.CommitsAhead = counts.CommitsAhead
+			status.CommitsBehind = counts.CommitsBehind
+		}
+		byName[s.BranchName] = status
+	}
+	for _, branch := range branches {
+		branch.Status = byName[branch.Name]
+	}
+	return nil
+}
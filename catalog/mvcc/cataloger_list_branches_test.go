@@ -2,11 +2,14 @@ package mvcc
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"strconv"
 	"testing"
 
+	"github.com/go-test/deep"
 	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
 )
 
 func TestCataloger_ListBranches(t *testing.T) {
@@ -86,7 +89,7 @@ func TestCataloger_ListBranches(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotMore, err := c.ListBranches(ctx, tt.args.repository, tt.args.prefix, tt.args.limit, tt.args.after)
+			got, gotMore, err := c.ListBranches(ctx, tt.args.repository, tt.args.prefix, tt.args.limit, tt.args.after, catalog.ListBranchesParams{})
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("ListBranches() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -106,3 +109,95 @@ func TestCataloger_ListBranches(t *testing.T) {
 		})
 	}
 }
+
+func TestCataloger_ListBranches_Status(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "")
+	branchLog, err := c.Commit(ctx, repository, "branch1", "commit to branch1", "tester1", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+
+	branches, _, err := c.ListBranches(ctx, repository, "", -1, "", catalog.ListBranchesParams{IncludeStatus: true})
+	testutil.MustDo(t, "list branches with status", err)
+	if len(branches) != 2 || branches[0].Name != "branch1" || branches[1].Name != "master" {
+		t.Fatalf("ListBranches() got %v, expected branch1 and master", branches)
+	}
+	status := branches[0].Status
+	if status == nil {
+		t.Fatal("ListBranches() branch1 has no status")
+	}
+	if status.HeadCommit == nil || status.HeadCommit.Reference != branchLog.Reference || status.HeadCommit.Committer != "tester1" {
+		t.Errorf("branch1 head commit %+v, expected %s", status.HeadCommit, branchLog.Reference)
+	}
+	expected := catalog.BranchStatus{
+		HeadCommit:         status.HeadCommit,
+		SourceBranch:       "master",
+		CommitsAhead:       2, // branch creation and commit
+		CommitsBehind:      1,
+		UncommittedEntries: 1,
+	}
+	if *status != expected {
+		t.Errorf("branch1 status %+v, expected %+v", *status, expected)
+	}
+	if master := branches[1].Status; master == nil || master.SourceBranch != "" || master.CommitsAhead != 0 || master.CommitsBehind != 0 {
+		t.Errorf("master status %+v, expected no source branch", master)
+	}
+}
+
+func TestCataloger_ListBranches_SortBy(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch2", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "")
+	_, err := c.Commit(ctx, repository, "branch1", "commit to branch1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+
+	tests := []struct {
+		sortBy catalog.BranchSortBy
+		want   []string
+	}{
+		{sortBy: "", want: []string{"branch1", "branch2", "master"}},
+		{sortBy: catalog.BranchSortByName, want: []string{"branch1", "branch2", "master"}},
+		{sortBy: catalog.BranchSortByLastCommit, want: []string{"branch1", "branch2", "master"}},
+		{sortBy: catalog.BranchSortByCreation, want: []string{"branch2", "branch1", "master"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.sortBy), func(t *testing.T) {
+			// list one branch at a time to paginate after each
+			var got []string
+			after := ""
+			for {
+				branches, hasMore, err := c.ListBranches(ctx, repository, "", 1, after, catalog.ListBranchesParams{SortBy: tt.sortBy})
+				testutil.MustDo(t, "list branches", err)
+				for _, branch := range branches {
+					got = append(got, branch.Name)
+					after = branch.Name
+				}
+				if !hasMore {
+					break
+				}
+			}
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Error("ListBranches() diff", diff)
+			}
+		})
+	}
+
+	_, _, err = c.ListBranches(ctx, repository, "", -1, "no-branch", catalog.ListBranchesParams{SortBy: catalog.BranchSortByCreation})
+	if !errors.Is(err, catalog.ErrBranchNotFound) {
+		t.Errorf("ListBranches() after missing branch err=%v, expected %s", err, catalog.ErrBranchNotFound)
+	}
+	_, _, err = c.ListBranches(ctx, repository, "", -1, "", catalog.ListBranchesParams{SortBy: "size"})
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("ListBranches() sort by size err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+}
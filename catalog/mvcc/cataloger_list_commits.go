@@ -3,6 +3,7 @@ package mvcc
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
@@ -10,15 +11,16 @@ import (
 
 const ListCommitsMaxLimit = 10000
 
-func (c *cataloger) ListCommits(ctx context.Context, repository, branch string, fromReference string, limit int) ([]*catalog.CommitLog, bool, error) {
+func (c *cataloger) ListCommits(ctx context.Context, repository, branch string, fromReference string, limit int, filter catalog.CommitsFilter) ([]*catalog.CommitLog, bool, error) {
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "branch", IsValid: ValidateBranchName(branch)},
 		{Name: "fromReference", IsValid: ValidateOptionalString(fromReference, IsValidReference)},
+		{Name: "filter.messageRegexp", IsValid: ValidateOptionalString(filter.MessageRegexp, IsValidRegexp)},
 	}); err != nil {
 		return nil, false, err
 	}
-	ref, err := ParseRef(fromReference)
+	ref, err := c.resolveRef(c.db.WithContext(ctx), repository, fromReference)
 	if err != nil {
 		return nil, false, err
 	}
@@ -35,29 +37,22 @@ func (c *cataloger) ListCommits(ctx context.Context, repository, branch string,
 		if err != nil {
 			return nil, err
 		}
-		lineage, err := getLineage(tx, branchID, fromCommitID)
+		cte, err := commitsLineageCTE(tx, branchID, fromCommitID)
 		if err != nil {
-			return nil, fmt.Errorf("get lineage: %w", err)
+			return nil, err
 		}
-		lineageAsValuesTable := getLineageAsValues(lineage, branchID, fromCommitID)
-		cte := `WITH RECURSIVE lineage_graph AS (
-    select branch_id,commit_id from ` + lineageAsValuesTable + `
-	union all
-	select * from (Select distinct on (c.branch_id,c.merge_source_branch) merge_source_branch,merge_source_commit from catalog_commits c
-	join lineage_graph l on l.branch_id = c.branch_id and c.merge_type='from_child'  and c.merge_source_commit < l.commit_id
-	order by c.branch_id,c.merge_source_branch,c.commit_id desc )t)
-`
+		filterConditions, args := commitsFilterConditions(filter, []interface{}{fromCommitID, limit + 1})
 		query := cte + `SELECT b_name.name as branch_name,c.commit_id,c.previous_commit_id,c.committer,c.message,c.creation_date,c.metadata,
 				COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit
 			FROM catalog_commits c JOIN lineage_graph l  ON  c.branch_id = l.branch_id and c.commit_id <= l.commit_id
 				JOIN catalog_branches b_name ON c.branch_id = b_name.id
-				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch
-			WHERE c.commit_id < $1
+				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch AND NOT c.squash
+			WHERE c.commit_id < $1` + filterConditions + `
 			ORDER BY c.commit_id DESC
 			LIMIT $2`
 
 		var rawCommits []commitLogRaw
-		if err := tx.Select(&rawCommits, query, fromCommitID, limit+1); err != nil {
+		if err := tx.Select(&rawCommits, query, args...); err != nil {
 			return nil, err
 		}
 		commits := convertRawCommits(rawCommits)
@@ -72,6 +67,62 @@ func (c *cataloger) ListCommits(ctx context.Context, repository, branch string,
 	return commits, hasMore, err
 }
 
+// commitsFilterConditions returns the conditions on commits c of filter, to add to a WHERE
+// clause with args, and args with their values appended
+func commitsFilterConditions(filter catalog.CommitsFilter, args []interface{}) (string, []interface{}) {
+	var conditions strings.Builder
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions.WriteString(" AND " + fmt.Sprintf(condition, len(args)))
+	}
+	if filter.MessageContains != "" {
+		addCondition("c.message ILIKE $%d", db.Contains(filter.MessageContains))
+	}
+	if filter.MessageRegexp != "" {
+		addCondition("c.message ~ $%d", filter.MessageRegexp)
+	}
+	if filter.Committer != "" {
+		addCondition("c.committer = $%d", filter.Committer)
+	}
+	if len(filter.Metadata) > 0 {
+		addCondition("c.metadata @> $%d", filter.Metadata)
+	}
+	if !filter.Since.IsZero() {
+		addCondition("c.creation_date >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		addCondition("c.creation_date < $%d", filter.Until)
+	}
+	return conditions.String(), args
+}
+
+// commitsLineageCTE returns a recursive CTE named lineage_graph, holding the branches and
+// the last commit on each branch that are reachable from branchID at fromCommitID.  Squash
+// merges do not reach the commits of their source branch.
+func commitsLineageCTE(tx db.Tx, branchID int64, fromCommitID CommitID) (string, error) {
+	graph, err := lineageGraph(tx, "lineage_graph", branchID, fromCommitID)
+	if err != nil {
+		return "", err
+	}
+	return "WITH RECURSIVE " + graph + "\n", nil
+}
+
+// lineageGraph returns the named recursive query of commitsLineageCTE, for use as one of
+// several queries of a WITH RECURSIVE clause
+func lineageGraph(tx db.Tx, name string, branchID int64, fromCommitID CommitID) (string, error) {
+	lineage, err := getLineage(tx, branchID, fromCommitID)
+	if err != nil {
+		return "", fmt.Errorf("get lineage: %w", err)
+	}
+	lineageAsValuesTable := getLineageAsValues(lineage, branchID, fromCommitID)
+	return name + ` AS (
+    select branch_id,commit_id from ` + lineageAsValuesTable + `
+	union all
+	select * from (Select distinct on (c.branch_id,c.merge_source_branch) merge_source_branch,merge_source_commit from catalog_commits c
+	join ` + name + ` l on l.branch_id = c.branch_id and c.merge_type='from_child' and NOT c.squash and c.merge_source_commit < l.commit_id
+	order by c.branch_id,c.merge_source_branch,c.commit_id desc )t)`, nil
+}
+
 func convertRawCommits(rawCommits []commitLogRaw) []*catalog.CommitLog {
 	commits := make([]*catalog.CommitLog, len(rawCommits))
 	for i, commit := range rawCommits {
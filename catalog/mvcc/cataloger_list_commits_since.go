@@ -0,0 +1,65 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) ListCommitsSince(ctx context.Context, repository, branch string, sinceReference string, limit int) ([]*catalog.CommitLog, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "sinceReference", IsValid: ValidateReference(sinceReference)},
+	}); err != nil {
+		return nil, false, err
+	}
+	ref, err := c.resolveRef(c.db.WithContext(ctx), repository, sinceReference)
+	if err != nil {
+		return nil, false, err
+	}
+	if ref.Branch != branch || ref.CommitID <= 0 {
+		return nil, false, fmt.Errorf("%w: not a commit of branch %s", catalog.ErrInvalidReference, branch)
+	}
+	if limit < 0 || limit > ListCommitsMaxLimit {
+		limit = ListCommitsMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		// not cached: a branch recreated since must not match the commits of the deleted branch
+		branchID, err := getBranchID(tx, repository, branch, LockTypeNone)
+		if err != nil {
+			return nil, err
+		}
+		// the commit is missing when the branch was recreated since
+		var exists bool
+		if err := tx.GetPrimitive(&exists, `SELECT EXISTS (SELECT 1 FROM catalog_commits WHERE branch_id = $1 AND commit_id = $2)`,
+			branchID, ref.CommitID); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, catalog.ErrCommitNotFound
+		}
+		// commits of a branch are created holding a lock on the branch, so their ids grow in
+		// the order they become visible
+		query := `SELECT b.name as branch_name,c.commit_id,c.previous_commit_id,c.committer,c.message,c.creation_date,c.metadata,
+				COALESCE(bb.name,'') as merge_source_branch_name,COALESCE(c.merge_source_commit,0) as merge_source_commit
+			FROM catalog_commits c JOIN catalog_branches b ON c.branch_id = b.id
+				LEFT JOIN catalog_branches bb ON bb.id = c.merge_source_branch AND NOT c.squash
+			WHERE c.branch_id = $1 AND c.commit_id > $2
+			ORDER BY c.commit_id
+			LIMIT $3`
+		var rawCommits []commitLogRaw
+		if err := tx.Select(&rawCommits, query, branchID, ref.CommitID, limit+1); err != nil {
+			return nil, err
+		}
+		return convertRawCommits(rawCommits), nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	commits := res.([]*catalog.CommitLog)
+	hasMore := paginateSlice(&commits, limit)
+	return commits, hasMore, nil
+}
@@ -0,0 +1,76 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ListCommitsSince(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	initialReference, err := c.GetBranchReference(ctx, repository, "master")
+	testutil.MustDo(t, "get master branch reference", err)
+	commits := setupListCommitsByBranchData(t, ctx, c, repository, "master")
+
+	t.Run("all", func(t *testing.T) {
+		got, hasMore, err := c.ListCommitsSince(ctx, repository, "master", initialReference, -1)
+		testutil.MustDo(t, "list commits since", err)
+		if hasMore {
+			t.Error("expected no more commits")
+		}
+		if len(got) != len(commits) {
+			t.Fatalf("got %d commits, expected %d", len(got), len(commits))
+		}
+		for i, commit := range got {
+			if commit.Reference != commits[i].Reference {
+				t.Errorf("commit %d is %s, expected %s", i, commit.Reference, commits[i].Reference)
+			}
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		got, hasMore, err := c.ListCommitsSince(ctx, repository, "master", commits[0].Reference, 1)
+		testutil.MustDo(t, "list commits since", err)
+		if !hasMore {
+			t.Error("expected more commits")
+		}
+		if len(got) != 1 || got[0].Reference != commits[1].Reference {
+			t.Fatalf("got %+v, expected commit %s", got, commits[1].Reference)
+		}
+	})
+
+	t.Run("latest", func(t *testing.T) {
+		got, hasMore, err := c.ListCommitsSince(ctx, repository, "master", commits[len(commits)-1].Reference, -1)
+		testutil.MustDo(t, "list commits since", err)
+		if hasMore || len(got) != 0 {
+			t.Fatalf("expected no commits, got %d", len(got))
+		}
+	})
+
+	t.Run("other branch", func(t *testing.T) {
+		testCatalogerBranch(t, ctx, c, repository, "br1", "master")
+		_, _, err := c.ListCommitsSince(ctx, repository, "br1", commits[0].Reference, -1)
+		if !errors.Is(err, catalog.ErrInvalidReference) {
+			t.Fatalf("expected invalid reference, got %v", err)
+		}
+	})
+
+	t.Run("recreated branch", func(t *testing.T) {
+		testCatalogerBranch(t, ctx, c, repository, "br2", "master")
+		testCatalogerCreateEntry(t, ctx, c, repository, "br2", "/br2file", nil, "")
+		commit, err := c.Commit(ctx, repository, "br2", "commit on br2", "tester", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit on br2", err)
+		testutil.MustDo(t, "delete br2", c.DeleteBranch(ctx, repository, "br2"))
+		testCatalogerBranch(t, ctx, c, repository, "br2", "master")
+		_, _, err = c.ListCommitsSince(ctx, repository, "br2", commit.Reference, -1)
+		if !errors.Is(err, catalog.ErrCommitNotFound) {
+			t.Fatalf("expected commit not found, got %v", err)
+		}
+	})
+}
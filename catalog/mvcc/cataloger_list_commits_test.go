@@ -2,6 +2,7 @@ package mvcc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -141,7 +142,7 @@ func TestCataloger_ListCommits(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotMore, err := c.ListCommits(ctx, tt.args.repository, tt.args.branch, tt.args.fromReference, tt.args.limit)
+			got, gotMore, err := c.ListCommits(ctx, tt.args.repository, tt.args.branch, tt.args.fromReference, tt.args.limit, catalog.CommitsFilter{})
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("ListCommits() error = %s, wantErr %t", err, tt.wantErr)
 			}
@@ -161,14 +162,14 @@ func TestCataloger_ListCommits(t *testing.T) {
 	}
 	testCatalogerBranch(t, ctx, c, repository, "br_1", "master")
 	testCatalogerBranch(t, ctx, c, repository, "br_2", "br_1")
-	masterCommits, _, err := c.ListCommits(ctx, repository, "master", "", 100)
+	masterCommits, _, err := c.ListCommits(ctx, repository, "master", "", 100, catalog.CommitsFilter{})
 	testutil.Must(t, err)
-	br1Commits, _, err := c.ListCommits(ctx, repository, "br_1", "", 100)
+	br1Commits, _, err := c.ListCommits(ctx, repository, "br_1", "", 100, catalog.CommitsFilter{})
 	testutil.Must(t, err)
 	if diff := deep.Equal(masterCommits, br1Commits[1:]); diff != nil {
 		t.Error("br_1 did not inherit commits correctly", diff)
 	}
-	br2Commits, _, err := c.ListCommits(ctx, repository, "br_2", "", 100)
+	br2Commits, _, err := c.ListCommits(ctx, repository, "br_2", "", 100, catalog.CommitsFilter{})
 	if err != nil {
 		t.Fatalf("ListCommits() error = %s", err)
 	}
@@ -184,16 +185,16 @@ func TestCataloger_ListCommits(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("Write entry for list repository commits failed", err)
 	}
-	commitLog, err := c.Commit(ctx, repository, "master", "commit master", "tester", nil)
+	commitLog, err := c.Commit(ctx, repository, "master", "commit master", "tester", nil, catalog.CommitParams{})
 	_ = commitLog
 	if err != nil {
 		t.Fatalf("Commit for list repository commits failed '%s': %s", "master commit failed", err)
 	}
-	_, err = c.Merge(ctx, repository, "master", "br_1", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "master", "br_1", "tester", "", nil, catalog.MergeParams{})
 	testutil.Must(t, err)
-	_, _, err = c.ListCommits(ctx, repository, "br_2", "", 100)
+	_, _, err = c.ListCommits(ctx, repository, "br_2", "", 100, catalog.CommitsFilter{})
 	testutil.Must(t, err)
-	_, _, err = c.ListCommits(ctx, repository, "br_1", "", 100)
+	_, _, err = c.ListCommits(ctx, repository, "br_1", "", 100, catalog.CommitsFilter{})
 	testutil.Must(t, err)
 }
 
@@ -211,7 +212,7 @@ func setupListCommitsByBranchData(t *testing.T, ctx context.Context, c catalog.C
 			t.Fatal("Write entry for list repository commits failed", err)
 		}
 		message := "commit" + strconv.Itoa(i+1) + " on branch " + branch
-		commitLog, err := c.Commit(ctx, repository, branch, message, "tester", nil)
+		commitLog, err := c.Commit(ctx, repository, branch, message, "tester", nil, catalog.CommitParams{})
 		if err != nil {
 			t.Fatalf("Commit for list repository commits failed '%s': %s", message, err)
 		}
@@ -229,10 +230,10 @@ func TestCataloger_ListCommits_Lineage(t *testing.T) {
 
 	testCatalogerBranch(t, ctx, c, repository, "br_1", "master")
 	testCatalogerBranch(t, ctx, c, repository, "br_2", "br_1")
-	masterCommits, _, err := c.ListCommits(ctx, repository, "master", "", 100)
+	masterCommits, _, err := c.ListCommits(ctx, repository, "master", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list master commits", err)
 
-	br1Commits, _, err := c.ListCommits(ctx, repository, "br_1", "", 100)
+	br1Commits, _, err := c.ListCommits(ctx, repository, "br_1", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_1 commits", err)
 
 	// get all commits without the first one
@@ -240,7 +241,7 @@ func TestCataloger_ListCommits_Lineage(t *testing.T) {
 		t.Error("br_1 did not inherit commits correctly", diff)
 	}
 
-	b2Commits, _, err := c.ListCommits(ctx, repository, "br_2", "", 100)
+	b2Commits, _, err := c.ListCommits(ctx, repository, "br_2", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_2 commits", err)
 
 	if diff := deep.Equal(br1Commits, b2Commits[1:]); diff != nil {
@@ -255,22 +256,22 @@ func TestCataloger_ListCommits_Lineage(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("Write entry for list repository commits failed", err)
 	}
-	_, err = c.Commit(ctx, repository, "master", "commit master-file", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "commit master-file", "tester", nil, catalog.CommitParams{})
 	if err != nil {
 		t.Fatalf("Commit for list repository commits failed '%s': %s", "master commit failed", err)
 	}
-	_, err = c.Merge(ctx, repository, "master", "br_1", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "master", "br_1", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge master  into br_1", err)
 
-	got, _, err := c.ListCommits(ctx, repository, "br_2", "", 100)
+	got, _, err := c.ListCommits(ctx, repository, "br_2", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_2 commits", err)
 	if diff := deep.Equal(got, b2Commits); diff != nil {
 		t.Error("br_2 changed although not merged", diff)
 	}
-	masterCommits, _, err = c.ListCommits(ctx, repository, "master", "", 100)
+	masterCommits, _, err = c.ListCommits(ctx, repository, "master", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list master commits", err)
 
-	got, _, err = c.ListCommits(ctx, repository, "br_1", "", 100)
+	got, _, err = c.ListCommits(ctx, repository, "br_1", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_1 commits", err)
 	if diff := deep.Equal(masterCommits[0], got[1]); diff != nil {
 		t.Error("br_1 did not inherit commits correctly", diff)
@@ -286,10 +287,10 @@ func TestCataloger_ListCommits_LineageFromChild(t *testing.T) {
 
 	testCatalogerBranch(t, ctx, c, repository, "br_1_1", "master")
 	testCatalogerBranch(t, ctx, c, repository, "br_1_2", "br_1_1")
-	masterCommits, _, err := c.ListCommits(ctx, repository, "master", "", 100)
+	masterCommits, _, err := c.ListCommits(ctx, repository, "master", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list master commits", err)
 
-	br1Commits, _, err := c.ListCommits(ctx, repository, "br_1_1", "", 100)
+	br1Commits, _, err := c.ListCommits(ctx, repository, "br_1_1", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_1_1 commits", err)
 
 	// get all commits without the first one
@@ -297,7 +298,7 @@ func TestCataloger_ListCommits_LineageFromChild(t *testing.T) {
 		t.Error("br_1_1 did not inherit commits correctly", diff)
 	}
 
-	b2Commits, _, err := c.ListCommits(ctx, repository, "br_1_2", "", 100)
+	b2Commits, _, err := c.ListCommits(ctx, repository, "br_1_2", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_1_2 commits", err)
 
 	if diff := deep.Equal(br1Commits, b2Commits[1:]); diff != nil {
@@ -312,22 +313,22 @@ func TestCataloger_ListCommits_LineageFromChild(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("Write entry for list repository commits failed", err)
 	}
-	_, err = c.Commit(ctx, repository, "master", "commit master-file on master", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "commit master-file on master", "tester", nil, catalog.CommitParams{})
 	if err != nil {
 		t.Fatalf("Commit for list repository commits failed '%s': %s", "master commit failed", err)
 	}
-	_, err = c.Merge(ctx, repository, "master", "br_1_1", "tester", "merge master to br_1_1", nil)
+	_, err = c.Merge(ctx, repository, "master", "br_1_1", "tester", "merge master to br_1_1", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge master  into br_1_1", err)
 
-	got, _, err := c.ListCommits(ctx, repository, "br_1_2", "", 100)
+	got, _, err := c.ListCommits(ctx, repository, "br_1_2", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_1_2 commits", err)
 	if diff := deep.Equal(got, b2Commits); diff != nil {
 		t.Error("br_1_2 changed although not merged", diff)
 	}
-	masterCommits, _, err = c.ListCommits(ctx, repository, "master", "", 100)
+	masterCommits, _, err = c.ListCommits(ctx, repository, "master", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list master commits", err)
 
-	br11BaseList, _, err := c.ListCommits(ctx, repository, "br_1_1", "", 100)
+	br11BaseList, _, err := c.ListCommits(ctx, repository, "br_1_1", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_1_1 commits", err)
 	if diff := deep.Equal(masterCommits[0], br11BaseList[1]); diff != nil {
 		t.Error("br_1_1 did not inherit commits correctly", diff)
@@ -343,24 +344,24 @@ func TestCataloger_ListCommits_LineageFromChild(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("Write entry to br_2_2 failed", err)
 	}
-	_, err = c.Commit(ctx, repository, "br_2_2", "commit master-file to br_2_2", "tester", nil)
+	_, err = c.Commit(ctx, repository, "br_2_2", "commit master-file to br_2_2", "tester", nil, catalog.CommitParams{})
 	if err != nil {
 		t.Fatalf("Commit for list repository commits failed '%s': %s", "br_2_2  commit failed", err)
 	}
-	br22List, _, err := c.ListCommits(ctx, repository, "br_2_2", "", 100)
+	br22List, _, err := c.ListCommits(ctx, repository, "br_2_2", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_2_2  commits", err)
 	_ = br22List
-	_, err = c.Merge(ctx, repository, "br_2_2", "br_2_1", "tester", "merge br_2_2 to br_2_1", nil)
+	_, err = c.Merge(ctx, repository, "br_2_2", "br_2_1", "tester", "merge br_2_2 to br_2_1", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge br_2_2  into br_2_1", err)
-	br21List, _, err := c.ListCommits(ctx, repository, "br_2_1", "", 100)
+	br21List, _, err := c.ListCommits(ctx, repository, "br_2_1", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_2_1  commits", err)
 	_ = br21List
-	masterList, _, err := c.ListCommits(ctx, repository, "master", "", 100)
+	masterList, _, err := c.ListCommits(ctx, repository, "master", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list master commits", err)
 	if diff := deep.Equal(masterCommits, masterList); diff != nil {
 		t.Error("master commits changed before merge", diff)
 	}
-	merge2, err := c.Merge(ctx, repository, "br_2_1", "master", "tester", "merge br_2_1 to master", nil)
+	merge2, err := c.Merge(ctx, repository, "br_2_1", "master", "tester", "merge br_2_1 to master", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge br_2_1  into master", err)
 	commitLog, err := c.GetCommit(ctx, repository, merge2.Reference)
 	testutil.MustDo(t, "get merge commit reference", err)
@@ -380,20 +381,20 @@ func TestCataloger_ListCommits_LineageFromChild(t *testing.T) {
 	//	t.Error("merge br_2_1 into master with unexpected results", differences[0])
 	//}
 
-	masterList, _, err = c.ListCommits(ctx, repository, "master", "", 100)
+	masterList, _, err = c.ListCommits(ctx, repository, "master", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list master commits", err)
 	if diff := deep.Equal(br21List, masterList[1:]); diff != nil {
 		t.Error("master commits list mismatch with br_2_1_list", diff)
 	}
 
-	br11List, _, err := c.ListCommits(ctx, repository, "br_1_1", "", 100)
+	br11List, _, err := c.ListCommits(ctx, repository, "br_1_1", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_1_1 commits", err)
 	if diff := deep.Equal(br11BaseList, br11List); diff != nil {
 		t.Error("br_1_1 commits changed before merge", diff)
 	}
-	_, err = c.Merge(ctx, repository, "master", "br_1_1", "tester", "merge master to br_1_1", nil)
+	_, err = c.Merge(ctx, repository, "master", "br_1_1", "tester", "merge master to br_1_1", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge master  into br_1_1", err)
-	br11List, _, err = c.ListCommits(ctx, repository, "br_1_1", "", 100)
+	br11List, _, err = c.ListCommits(ctx, repository, "br_1_1", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "list br_1_1 commits", err)
 	if diff := deep.Equal(masterList[:5], br11List[1:6]); diff != nil {
 		t.Error("master 5 first different from br_1_1 [1:6]", diff)
@@ -410,18 +411,18 @@ func TestCataloger_ListCommits_LineageFromChild(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("Write no-propagate-file to br_2_2 failed", err)
 	}
-	_, err = c.Commit(ctx, repository, "br_2_2", "commit master-file to br_2_2", "tester", nil)
+	_, err = c.Commit(ctx, repository, "br_2_2", "commit master-file to br_2_2", "tester", nil, catalog.CommitParams{})
 	if err != nil {
 		t.Fatalf("no-propagate-Commit for list repository commits failed '%s': %s", "br_2_2  commit failed", err)
 	}
-	_, err = c.Merge(ctx, repository, "br_2_2", "br_2_1", "tester", "merge br_2_2 to br_2_1", nil)
+	_, err = c.Merge(ctx, repository, "br_2_2", "br_2_1", "tester", "merge br_2_2 to br_2_1", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "second merge br_2_2  into br_2_1", err)
-	newBr21List, _, err := c.ListCommits(ctx, repository, "br_2_1", "", 100)
+	newBr21List, _, err := c.ListCommits(ctx, repository, "br_2_1", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "second list br_2_1 commits", err)
 	if diff := deep.Equal(br21List, newBr21List); diff == nil {
 		t.Error("br_2_1 commits did not changed after merge", diff)
 	}
-	newMasterList, _, err := c.ListCommits(ctx, repository, "master", "", 100)
+	newMasterList, _, err := c.ListCommits(ctx, repository, "master", "", 100, catalog.CommitsFilter{})
 	testutil.MustDo(t, "third list master commits", err)
 	if diff := deep.Equal(newMasterList, masterList); diff != nil {
 		t.Error("master commits  changed without merge", diff)
@@ -442,12 +443,12 @@ func TestCataloger_ListCommits_Order(t *testing.T) {
 
 			testCatalogerCreateEntry(t, ctx, c, repository, "master", "files/first", nil, "")
 			const commit1Msg = "first"
-			_, err := c.Commit(ctx, repository, "master", commit1Msg, "barak.amar", nil)
+			_, err := c.Commit(ctx, repository, "master", commit1Msg, "barak.amar", nil, catalog.CommitParams{})
 			testutil.MustDo(t, commit1Msg, err)
 
 			testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
 
-			commitsLog, _, err := c.ListCommits(ctx, repository, "branch1", "", 300)
+			commitsLog, _, err := c.ListCommits(ctx, repository, "branch1", "", 300, catalog.CommitsFilter{})
 			testutil.MustDo(t, "list branch1 commits", err)
 
 			// get all commits without the first one
@@ -469,3 +470,59 @@ func TestCataloger_ListCommits_Order(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestCataloger_ListCommits_Filter(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	commits := []struct {
+		message   string
+		committer string
+		metadata  catalog.Metadata
+	}{
+		{message: "Add raw data", committer: "alice", metadata: catalog.Metadata{"job": "ingest"}},
+		{message: "clean data", committer: "bob", metadata: catalog.Metadata{"job": "clean", "env": "prod"}},
+		{message: "Fix report #12", committer: "alice", metadata: catalog.Metadata{"job": "report", "env": "prod"}},
+	}
+	var middle time.Time
+	for i, commit := range commits {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "file"+strconv.Itoa(i), nil, "")
+		commitLog, err := c.Commit(ctx, repository, "master", commit.message, commit.committer, commit.metadata, catalog.CommitParams{})
+		testutil.MustDo(t, "commit "+commit.message, err)
+		if i == 1 {
+			middle = commitLog.CreationDate
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filter   catalog.CommitsFilter
+		expected []string
+	}{
+		{name: "message contains", filter: catalog.CommitsFilter{MessageContains: "DATA"}, expected: []string{"clean data", "Add raw data"}},
+		{name: "message regexp", filter: catalog.CommitsFilter{MessageRegexp: "#[0-9]+$"}, expected: []string{"Fix report #12"}},
+		{name: "committer", filter: catalog.CommitsFilter{Committer: "alice"}, expected: []string{"Fix report #12", "Add raw data"}},
+		{name: "metadata", filter: catalog.CommitsFilter{Metadata: catalog.Metadata{"env": "prod"}}, expected: []string{"Fix report #12", "clean data"}},
+		{name: "combined", filter: catalog.CommitsFilter{Committer: "alice", Metadata: catalog.Metadata{"env": "prod"}}, expected: []string{"Fix report #12"}},
+		{name: "since", filter: catalog.CommitsFilter{Since: middle, Committer: "bob"}, expected: []string{"clean data"}},
+		{name: "until", filter: catalog.CommitsFilter{Until: middle, Committer: "alice"}, expected: []string{"Add raw data"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := c.ListCommits(ctx, repository, "master", "", -1, tt.filter)
+			testutil.MustDo(t, "list commits", err)
+			messages := make([]string, len(got))
+			for i, commit := range got {
+				messages[i] = commit.Message
+			}
+			if diff := deep.Equal(messages, tt.expected); diff != nil {
+				t.Errorf("listed commits %v, expected %v: %s", messages, tt.expected, diff)
+			}
+		})
+	}
+
+	_, _, err := c.ListCommits(ctx, repository, "master", "", -1, catalog.CommitsFilter{MessageRegexp: "("})
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("list commits with invalid regexp err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+}
@@ -24,7 +24,7 @@ func (c *cataloger) ListEntries(ctx context.Context, repository, reference strin
 		return nil, false, err
 	}
 
-	ref, err := ParseRef(reference)
+	ref, err := c.resolveRef(c.db.WithContext(ctx), repository, reference)
 	if err != nil {
 		return nil, false, err
 	}
@@ -63,7 +63,8 @@ func (c *cataloger) listEntries(ctx context.Context, repository string, ref *Ref
 			return nil, fmt.Errorf("get lineage: %w", err)
 		}
 		entriesSQL, args, err := psql.
-			Select("path", "physical_address", "creation_date", "size", "checksum", "metadata").
+			Select("path", "physical_address", "creation_date", "size", "checksum", "metadata",
+				"content_type", "content_encoding", "cache_control").
 			FromSelect(sqEntriesLineage(branchID, ref.CommitID, lineage), "entries").
 			// Listing also shows expired objects!
 			Where(sq.And{sq.Like{"path": likePath}, sq.Eq{"is_deleted": false}, sq.Gt{"path": after}}).
@@ -352,7 +353,8 @@ func loadEntriesIntoMarkerList(markerList []string, tx db.Tx, branchID int64, co
 	entriesReader := sqEntriesLineageV(branchID, commitID, lineage)
 	for _, r := range entryRuns {
 		entriesSQL, args, err := sq.
-			Select("path", "physical_address", "creation_date", "size", "checksum", "metadata").
+			Select("path", "physical_address", "creation_date", "size", "checksum", "metadata",
+				"content_type", "content_encoding", "cache_control").
 			Where("NOT is_deleted AND path between ? and ?", prefix+r.startEntryRun, prefix+r.endEntryRun).
 			FromSelect(entriesReader, "e").
 			PlaceholderFormat(sq.Dollar).
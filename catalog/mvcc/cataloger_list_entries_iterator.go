@@ -0,0 +1,116 @@
+package mvcc
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const ListEntriesIteratorBatchSize = 1000
+
+// entryIterator reads the entries of a reference in batches, each in its own transaction,
+// continuing after the path of the last entry it read
+type entryIterator struct {
+	ctx        context.Context
+	c          *cataloger
+	repository string
+	ref        *Ref
+	prefix     string
+	batchSize  int
+	batch      []*catalog.Entry
+	hasMore    bool
+	value      *catalog.Entry
+	err        error
+}
+
+// ListEntriesIterator iterates over the entries of reference.  A committed branch reference
+// is pinned to the last commit of the branch, so the iterator reads the same commit from
+// start to end.  An uncommitted branch reference reads each batch as it is.
+func (c *cataloger) ListEntriesIterator(ctx context.Context, repository, reference, prefix string) (catalog.EntryIterator, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		ref, err := c.resolveRef(tx, repository, reference)
+		if err != nil {
+			return nil, err
+		}
+		if ref.CommitID != CommittedID {
+			return ref, nil
+		}
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		ref.CommitID, err = getLastCommitIDByBranchID(tx, branchID)
+		return ref, err
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return newEntryIterator(ctx, c, repository, res.(*Ref), prefix, ListEntriesIteratorBatchSize), nil
+}
+
+func newEntryIterator(ctx context.Context, c *cataloger, repository string, ref *Ref, prefix string, batchSize int) *entryIterator {
+	return &entryIterator{
+		ctx:        ctx,
+		c:          c,
+		repository: repository,
+		ref:        ref,
+		prefix:     prefix,
+		batchSize:  batchSize,
+		hasMore:    true,
+	}
+}
+
+func (it *entryIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.batch) == 0 {
+		if !it.hasMore {
+			it.value = nil
+			return false
+		}
+		if !it.readBatch() {
+			return false
+		}
+	}
+	it.value = it.batch[0]
+	it.batch = it.batch[1:]
+	return true
+}
+
+// readBatch reads the entries after the current one, and reports whether it read any
+func (it *entryIterator) readBatch() bool {
+	after := ""
+	if it.value != nil {
+		after = it.value.Path
+	}
+	res, err := it.c.listEntries(it.ctx, it.repository, it.ref, it.prefix, after, it.batchSize)
+	if err != nil {
+		it.err = err
+		it.value = nil
+		return false
+	}
+	// listEntries reads one entry past the limit to tell whether there are more
+	it.batch = res.([]*catalog.Entry)
+	it.hasMore = len(it.batch) > it.batchSize
+	if len(it.batch) == 0 {
+		it.value = nil
+		return false
+	}
+	return true
+}
+
+func (it *entryIterator) Value() *catalog.Entry {
+	return it.value
+}
+
+func (it *entryIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,59 @@
+package mvcc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ListEntriesIterator(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	for _, path := range []string{"a/1", "a/2", "a/3", "a/4", "a/5", "b/1"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", path, nil, "")
+	}
+	_, err := c.Commit(ctx, repository, "master", "add files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+
+	readPaths := func(it catalog.EntryIterator) []string {
+		var paths []string
+		for it.Next() {
+			paths = append(paths, it.Value().Path)
+		}
+		testutil.MustDo(t, "iterate entries", it.Err())
+		if it.Next() {
+			t.Error("Next() after the last entry returned true")
+		}
+		return paths
+	}
+
+	committed := MakeReference("master", CommittedID)
+	it, err := c.ListEntriesIterator(ctx, repository, committed, "a/")
+	testutil.MustDo(t, "list entries iterator", err)
+	// entries committed after the iterator was created are not read
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a/6", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "add a/6", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit a/6", err)
+	if diff := deep.Equal(readPaths(it), []string{"a/1", "a/2", "a/3", "a/4", "a/5"}); diff != nil {
+		t.Error("ListEntriesIterator() paths diff", diff)
+	}
+
+	// read in batches smaller than the listing
+	ref, err := ParseRef(committed)
+	testutil.MustDo(t, "parse reference", err)
+	for _, batchSize := range []int{1, 2, 6, 10} {
+		got := readPaths(newEntryIterator(ctx, c.Cataloger.(*cataloger), repository, ref, "", batchSize))
+		want := []string{"a/1", "a/2", "a/3", "a/4", "a/5", "a/6", "b/1"}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("entry iterator batch size %d paths diff %s", batchSize, diff)
+		}
+	}
+
+	if _, err := c.ListEntriesIterator(ctx, repository, "no-branch:HEAD", ""); err == nil {
+		t.Error("ListEntriesIterator() on missing branch expected error")
+	}
+}
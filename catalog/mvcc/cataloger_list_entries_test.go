@@ -37,7 +37,7 @@ func TestCataloger_ListEntries(t *testing.T) {
 				Metadata:        nil,
 			}, catalog.CreateEntryParams{}))
 		if i == 2 {
-			_, err := c.Commit(ctx, "repo1", "master", "commit test files", "tester", nil)
+			_, err := c.Commit(ctx, "repo1", "master", "commit test files", "tester", nil, catalog.CommitParams{})
 			testutil.MustDo(t, "commit test files", err)
 		}
 	}
@@ -180,7 +180,7 @@ func TestCataloger_ListEntries_ByLevel(t *testing.T) {
 			}, catalog.CreateEntryParams{}))
 
 		if i == 3 {
-			_, err := c.Commit(ctx, repo, "master", "commit test files", "tester", nil)
+			_, err := c.Commit(ctx, repo, "master", "commit test files", "tester", nil, catalog.CommitParams{})
 			testutil.MustDo(t, "commit test files", err)
 		}
 	}
@@ -369,7 +369,7 @@ func TestCataloger_ListEntries_ByLevel_Deleted(t *testing.T) {
 	repo := testCatalogerRepo(t, ctx, c, "repo", "master")
 	testCatalogerCreateEntry(t, ctx, c, repo, "master", "place/to/go.1", nil, "")
 	testCatalogerCreateEntry(t, ctx, c, repo, "master", "place/to/go.2", nil, "")
-	_, err := c.Commit(ctx, repo, "master", "commit two files", "tester", nil)
+	_, err := c.Commit(ctx, repo, "master", "commit two files", "tester", nil, catalog.CommitParams{})
 
 	testutil.MustDo(t, "commit 2 files", err)
 	testutil.MustDo(t, "delete file 1",
@@ -378,7 +378,7 @@ func TestCataloger_ListEntries_ByLevel_Deleted(t *testing.T) {
 		c.DeleteEntry(ctx, repo, "master", "place/to/go.2"))
 	testCatalogerCreateEntry(t, ctx, c, repo, "master", "place/to/go.0", nil, "")
 	testCatalogerCreateEntry(t, ctx, c, repo, "master", "place/to/go.3", nil, "")
-	_, err = c.Commit(ctx, repo, "master", "commit two more files", "tester", nil)
+	_, err = c.Commit(ctx, repo, "master", "commit two more files", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit 2 files", err)
 
 	entries, hasMore, err := c.ListEntries(ctx, repo, "master", "place/to/", "", catalog.DefaultPathDelimiter, -1)
@@ -399,7 +399,7 @@ func TestCataloger_ListByLevel_Delete(t *testing.T) {
 	for i := 0; i < 150; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repo, "master", "xxx/entry"+pathExt(i), nil, strconv.Itoa(i*10000))
 	}
-	_, err := c.Commit(ctx, repo, "master", "message", "committer1", nil)
+	_, err := c.Commit(ctx, repo, "master", "message", "committer1", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to master", err)
 	testCatalogerBranch(t, ctx, c, repo, "br_1", "master")
 
@@ -411,7 +411,7 @@ func TestCataloger_ListByLevel_Delete(t *testing.T) {
 		testCatalogerCreateEntry(t, ctx, c, repo, "br_1", "xxx/entry"+pathExt(i), nil, strconv.Itoa(i*10000))
 	}
 
-	_, err = c.Commit(ctx, repo, "br_1", "message", "committer1", nil)
+	_, err = c.Commit(ctx, repo, "br_1", "message", "committer1", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to br_1", err)
 
 	for i := 0; i < 100; i++ {
@@ -433,7 +433,7 @@ func TestCataloger_ListByLevel_DirectoriesAndTombstones(t *testing.T) {
 			testCatalogerCreateEntry(t, ctx, c, repo, "master", "xxx"+pathExt(i)+"/entry"+pathExt(j), nil, strconv.Itoa(i*10000))
 		}
 	}
-	_, err := c.Commit(ctx, repo, "master", "message", "committer1", nil)
+	_, err := c.Commit(ctx, repo, "master", "message", "committer1", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to master", err)
 	testCatalogerBranch(t, ctx, c, repo, "br_1", "master")
 	for i := 0; i < 10; i += 2 {
@@ -441,7 +441,7 @@ func TestCataloger_ListByLevel_DirectoriesAndTombstones(t *testing.T) {
 			testCatalogerCreateEntry(t, ctx, c, repo, "br_1", "xxx"+pathExt(i)+"/entry"+pathExt(j), nil, strconv.Itoa(i*10000))
 		}
 	}
-	_, err = c.Commit(ctx, repo, "br_1", "message", "committer1", nil)
+	_, err = c.Commit(ctx, repo, "br_1", "message", "committer1", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to br_1", err)
 	testCatalogerBranch(t, ctx, c, repo, "br_2", "br_1")
 	for i := 0; i < 10; i += 3 {
@@ -449,7 +449,7 @@ func TestCataloger_ListByLevel_DirectoriesAndTombstones(t *testing.T) {
 			testCatalogerCreateEntry(t, ctx, c, repo, "br_2", "xxx"+pathExt(i)+"/entry"+pathExt(j), nil, strconv.Itoa(i*10000))
 		}
 	}
-	_, err = c.Commit(ctx, repo, "br_2", "message", "committer1", nil)
+	_, err = c.Commit(ctx, repo, "br_2", "message", "committer1", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to br_2", err)
 	for i := 0; i < 10; i += 3 {
 		for j := 0; j < 10; j++ {
@@ -607,7 +607,7 @@ func TestCataloger_ListEntries_Uncommitted(t *testing.T) {
 	c := testCataloger(t)
 	repo := testCatalogerRepo(t, ctx, c, "repo", "master")
 	testCatalogerCreateEntry(t, ctx, c, repo, "master", "my_entry", nil, "abcd")
-	_, err := c.Commit(ctx, repo, "master", "commit test files", "tester", nil)
+	_, err := c.Commit(ctx, repo, "master", "commit test files", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit my_entry", err)
 	testutil.MustDo(t, "delete the first committed file",
 		c.DeleteEntry(ctx, repo, "master", "my_entry"))
@@ -627,11 +627,11 @@ func TestCataloger_ListEntries_Uncommitted(t *testing.T) {
 		t.Fatal("ListEntries", diff)
 	}
 
-	_, err = c.Commit(ctx, repo, "master", "commit test files", "tester", nil)
+	_, err = c.Commit(ctx, repo, "master", "commit test files", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit my_entry", err)
 	testutil.MustDo(t, "delete the first committed file",
 		c.DeleteEntry(ctx, repo, "master", "my_entry"))
-	_, err = c.Commit(ctx, repo, "master", "commit test files", "tester", nil)
+	_, err = c.Commit(ctx, repo, "master", "commit test files", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit my_entry deletion", err)
 	// deleted entry will not be displayed
 	got, _, err = c.ListEntries(ctx, repo, "master", "", "", catalog.DefaultPathDelimiter, -1)
@@ -658,7 +658,7 @@ func TestCataloger_ListEntries_ReadingUncommittedFromLineage(t *testing.T) {
 		path := "my_entry" + z
 		testCatalogerCreateEntry(t, ctx, c, repo, "master", path, nil, "abcd"+z)
 	}
-	_, err := c.Commit(ctx, repo, "master", "commit first 10 in master", "tester", nil)
+	_, err := c.Commit(ctx, repo, "master", "commit first 10 in master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit first 10 in master", err)
 
 	// deletion that will not be seen by br_1, because it is not committed for br_1. so br_1 still sees this
@@ -678,7 +678,7 @@ func TestCataloger_ListEntries_ReadingUncommittedFromLineage(t *testing.T) {
 		path := "my_entry" + z
 		testCatalogerCreateEntry(t, ctx, c, repo, "master", path, nil, "abcd"+z)
 	}
-	_, err = c.Commit(ctx, repo, "master", "commit 20-50 in master", "tester", nil)
+	_, err = c.Commit(ctx, repo, "master", "commit 20-50 in master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit 20-50 in master", err)
 
 	// uncommitted
@@ -734,7 +734,7 @@ func TestCataloger_ListEntries_MultipleDelete(t *testing.T) {
 		}
 	}
 	// check it can identify committed tombstones
-	_, err = c.Commit(ctx, repo, "br_1", "commit  br_1 after delete ", "tester", nil)
+	_, err = c.Commit(ctx, repo, "br_1", "commit  br_1 after delete ", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit br_1 after delete ", err)
 	got, _, err = c.ListEntries(ctx, repo, "br_1", "", "", catalog.DefaultPathDelimiter, -1)
 	testutil.Must(t, err)
@@ -757,7 +757,7 @@ func TestCataloger_ListEntries_MultipleDelete(t *testing.T) {
 		t.Fatalf("expected 0 entries, read %d", len(got))
 	}
 	// check after commit
-	_, err = c.Commit(ctx, repo, "br_1", "commit br_1 after delete ", "tester", nil)
+	_, err = c.Commit(ctx, repo, "br_1", "commit br_1 after delete ", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit br_1 after delete ", err)
 	got, _, err = c.ListEntries(ctx, repo, "br_1", "", "", catalog.DefaultPathDelimiter, -1)
 	testutil.Must(t, err)
@@ -793,7 +793,7 @@ func TestCataloger_ListEntries_IgnoreDeleteByLineage(t *testing.T) {
 	if len(got) != 0 {
 		t.Fatalf("expected 0 entries on master, read %d", len(got))
 	}
-	_, err = c.Commit(ctx, repo, "master", "commit master", "tester", nil)
+	_, err = c.Commit(ctx, repo, "master", "commit master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit master ", err)
 	// br_1 ignores deleted committed entries on master
 	got, _, err = c.ListEntries(ctx, repo, "br_1", "", "", catalog.DefaultPathDelimiter, -1)
@@ -802,7 +802,7 @@ func TestCataloger_ListEntries_IgnoreDeleteByLineage(t *testing.T) {
 		t.Fatalf("expected 100 entries on br_1, read %d", len(got))
 	}
 	// now merge master to br_1
-	_, err = c.Merge(ctx, repo, "master", "br_1", "tester", "merge deletions", nil)
+	_, err = c.Merge(ctx, repo, "master", "br_1", "tester", "merge deletions", nil, catalog.MergeParams{})
 	testutil.Must(t, err)
 	got, _, err = c.ListEntries(ctx, repo, "br_1", "", "", catalog.DefaultPathDelimiter, -1)
 	testutil.Must(t, err)
@@ -824,7 +824,7 @@ func testListEntriesCreateEntries(t *testing.T, ctx context.Context, c catalog.C
 			}
 		}
 		msg := fmt.Sprintf("commit %s cycle %d", branch, i)
-		_, err := c.Commit(ctx, repo, branch, msg, "tester", nil)
+		_, err := c.Commit(ctx, repo, branch, msg, "tester", nil, catalog.CommitParams{})
 		testutil.MustDo(t, msg, err)
 	}
 }
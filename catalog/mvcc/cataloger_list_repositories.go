@@ -9,18 +9,18 @@ import (
 
 const ListRepositoriesMaxLimit = 10000
 
-func (c *cataloger) ListRepositories(ctx context.Context, limit int, after string) ([]*catalog.Repository, bool, error) {
+func (c *cataloger) ListRepositories(ctx context.Context, limit int, after string, params catalog.ListRepositoriesParams) ([]*catalog.Repository, bool, error) {
 	if limit < 0 || limit > ListRepositoriesMaxLimit {
 		limit = ListRepositoriesMaxLimit
 	}
 	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
-		query := `SELECT r.name, r.storage_namespace, b.name as default_branch, r.creation_date
+		query := `SELECT r.name, r.storage_namespace, b.name as default_branch, r.creation_date, r.read_only, r.archived
 			FROM catalog_repositories r JOIN catalog_branches b ON r.default_branch = b.id 
-			WHERE r.name > $1
+			WHERE r.name > $1 AND ($3 OR NOT r.archived)
 			ORDER BY r.name
 			LIMIT $2`
 		var repos []*catalog.Repository
-		if err := tx.Select(&repos, query, after, limit+1); err != nil {
+		if err := tx.Select(&repos, query, after, limit+1, params.IncludeArchived); err != nil {
 			return nil, err
 		}
 		return repos, nil
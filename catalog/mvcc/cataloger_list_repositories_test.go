@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/testutil"
 )
 
@@ -63,7 +64,7 @@ func TestCataloger_ListRepositories(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotMore, err := c.ListRepositories(ctx, tt.args.limit, tt.args.after)
+			got, gotMore, err := c.ListRepositories(ctx, tt.args.limit, tt.args.after, catalog.ListRepositoriesParams{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ListRepositories() error = %v, wantErr %v", err, tt.wantErr)
 				return
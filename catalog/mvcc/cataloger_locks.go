@@ -0,0 +1,138 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) AcquireLock(ctx context.Context, repository, branch, path, owner string, ttl time.Duration) (*catalog.BranchLock, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "owner", IsValid: ValidateLockOwner(owner)},
+	}); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("ttl: %w", catalog.ErrInvalidValue)
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		// locking the branch serializes acquiring overlapping locks
+		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("branch: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM catalog_branch_locks WHERE branch_id = $1 AND expiration_date <= transaction_timestamp()`,
+			branchID); err != nil {
+			return nil, fmt.Errorf("expire locks: %w", err)
+		}
+		var held []*catalog.BranchLock
+		err = tx.Select(&held, `SELECT path, owner, acquired_date, expiration_date FROM catalog_branch_locks
+			WHERE branch_id = $1 AND owner <> $3
+				AND (left($2, length(path)) = path OR left(path, length($2)) = $2)
+			ORDER BY path
+			LIMIT 1`,
+			branchID, path, owner)
+		if err != nil {
+			return nil, fmt.Errorf("overlapping locks: %w", err)
+		}
+		if len(held) > 0 {
+			return nil, fmt.Errorf("%w: '%s' held by %s", catalog.ErrLocked, held[0].Path, held[0].Owner)
+		}
+		var lock catalog.BranchLock
+		err = tx.Get(&lock, `INSERT INTO catalog_branch_locks (branch_id, path, owner, expiration_date)
+			VALUES ($1, $2, $3, transaction_timestamp() + make_interval(secs => $4))
+			ON CONFLICT (branch_id, path)
+			DO UPDATE SET expiration_date = EXCLUDED.expiration_date
+			RETURNING path, owner, acquired_date, expiration_date`,
+			branchID, path, owner, ttl.Seconds())
+		if err != nil {
+			return nil, fmt.Errorf("acquire lock: %w", err)
+		}
+		return &lock, nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.BranchLock), nil
+}
+
+func (c *cataloger) ReleaseLock(ctx context.Context, repository, branch, path, owner string) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "owner", IsValid: ValidateLockOwner(owner)},
+	}); err != nil {
+		return err
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		res, err := tx.Exec(`DELETE FROM catalog_branch_locks
+			WHERE branch_id = $1 AND path = $2 AND owner = $3 AND expiration_date > transaction_timestamp()`,
+			branchID, path, owner)
+		if err != nil {
+			return nil, fmt.Errorf("release lock: %w", err)
+		}
+		if res.RowsAffected() == 0 {
+			return nil, catalog.ErrLockNotFound
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func (c *cataloger) ListLocks(ctx context.Context, repository, branch string) ([]*catalog.BranchLock, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		var locks []*catalog.BranchLock
+		err = tx.Select(&locks, `SELECT path, owner, acquired_date, expiration_date FROM catalog_branch_locks
+			WHERE branch_id = $1 AND expiration_date > transaction_timestamp()
+			ORDER BY path`, branchID)
+		if err != nil {
+			return nil, fmt.Errorf("list locks: %w", err)
+		}
+		return locks, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.([]*catalog.BranchLock), nil
+}
+
+// checkLocks fails with ErrLocked when one of paths of branchID is locked by an owner other
+// than the lock owner of ctx.  Writes on contexts without a lock owner are not checked.
+func checkLocks(ctx context.Context, tx db.Tx, branchID int64, paths []string) error {
+	owner := catalog.LockOwner(ctx)
+	if owner == "" {
+		return nil
+	}
+	var held []*catalog.BranchLock
+	err := tx.Select(&held, `SELECT l.path, l.owner, l.acquired_date, l.expiration_date
+		FROM catalog_branch_locks l JOIN unnest($2::text[]) AS p (path) ON left(p.path, length(l.path)) = l.path
+		WHERE l.branch_id = $1 AND l.owner <> $3 AND l.expiration_date > transaction_timestamp()
+		LIMIT 1`,
+		branchID, paths, owner)
+	if err != nil {
+		return fmt.Errorf("check locks: %w", err)
+	}
+	if len(held) > 0 {
+		return fmt.Errorf("%w: '%s' held by %s", catalog.ErrLocked, held[0].Path, held[0].Owner)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_AcquireLock(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+
+	lock, err := c.AcquireLock(ctx, repository, "master", "tables/events/", "compaction", time.Minute)
+	testutil.MustDo(t, "acquire lock", err)
+	if lock.Path != "tables/events/" || lock.Owner != "compaction" || !lock.ExpirationDate.After(lock.AcquiredDate) {
+		t.Errorf("acquired lock %+v, expected on tables/events/ by compaction", lock)
+	}
+	renewed, err := c.AcquireLock(ctx, repository, "master", "tables/events/", "compaction", time.Hour)
+	testutil.MustDo(t, "renew lock", err)
+	if !renewed.ExpirationDate.After(lock.ExpirationDate) {
+		t.Errorf("renewed lock expires %s, expected after %s", renewed.ExpirationDate, lock.ExpirationDate)
+	}
+
+	for _, path := range []string{"tables/events/", "tables/", "tables/events/day=1/"} {
+		if _, err := c.AcquireLock(ctx, repository, "master", path, "writer", time.Minute); !errors.Is(err, catalog.ErrLocked) {
+			t.Errorf("acquire overlapping lock on %s err=%v, expected %s", path, err, catalog.ErrLocked)
+		}
+	}
+	_, err = c.AcquireLock(ctx, repository, "master", "tables/users/", "writer", time.Minute)
+	testutil.MustDo(t, "acquire disjoint lock", err)
+	locks, err := c.ListLocks(ctx, repository, "master")
+	testutil.MustDo(t, "list locks", err)
+	if len(locks) != 2 || locks[0].Path != "tables/events/" || locks[1].Path != "tables/users/" {
+		t.Errorf("listed locks %+v, expected tables/events/ and tables/users/", locks)
+	}
+
+	if err := c.ReleaseLock(ctx, repository, "master", "tables/events/", "writer"); !errors.Is(err, catalog.ErrLockNotFound) {
+		t.Errorf("release lock of another owner err=%v, expected %s", err, catalog.ErrLockNotFound)
+	}
+	testutil.MustDo(t, "release lock", c.ReleaseLock(ctx, repository, "master", "tables/events/", "compaction"))
+	_, err = c.AcquireLock(ctx, repository, "master", "tables/", "writer", time.Minute)
+	testutil.MustDo(t, "acquire lock after release", err)
+
+	if _, err := c.AcquireLock(ctx, repository, "master", "other/", "writer", 0); !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("acquire lock without ttl err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+}
+
+func TestCataloger_LockedWrites(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "tables/events/part-0", nil, "")
+	_, err := c.AcquireLock(ctx, repository, "master", "tables/events/", "compaction", time.Minute)
+	testutil.MustDo(t, "acquire lock", err)
+
+	// writes that do not take part in locking ignore locks
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "tables/events/part-1", nil, "")
+
+	writerCtx := catalog.WithLockOwner(ctx, "writer")
+	entry := catalog.Entry{Path: "tables/events/part-2", Checksum: "ff", PhysicalAddress: "part-2"}
+	if err := c.CreateEntry(writerCtx, repository, "master", entry, catalog.CreateEntryParams{}); !errors.Is(err, catalog.ErrLocked) {
+		t.Errorf("create locked entry err=%v, expected %s", err, catalog.ErrLocked)
+	}
+	if err := c.CreateEntries(writerCtx, repository, "master", []catalog.Entry{entry}); !errors.Is(err, catalog.ErrLocked) {
+		t.Errorf("create locked entries err=%v, expected %s", err, catalog.ErrLocked)
+	}
+	if err := c.DeleteEntry(writerCtx, repository, "master", "tables/events/part-0"); !errors.Is(err, catalog.ErrLocked) {
+		t.Errorf("delete locked entry err=%v, expected %s", err, catalog.ErrLocked)
+	}
+	entry.Path = "tables/users/part-0"
+	testutil.MustDo(t, "create unlocked entry", c.CreateEntry(writerCtx, repository, "master", entry, catalog.CreateEntryParams{}))
+
+	ownerCtx := catalog.WithLockOwner(ctx, "compaction")
+	testutil.MustDo(t, "delete entry by lock owner", c.DeleteEntry(ownerCtx, repository, "master", "tables/events/part-0"))
+}
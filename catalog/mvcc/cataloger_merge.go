@@ -21,7 +21,8 @@ type mergeBatchRecords []*catalog.DiffResultRecord
 // It uses the cataloger diff internal API to produce a temporary table that we delete at the end of a successful merge
 // the table holds entry ctid to reference entries in case of changed/added and source branch in case of delete.
 // That information is used to address cases where we need to create new entry or tombstone as part of the merge
-func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBranch, committer, message string, metadata catalog.Metadata) (*catalog.MergeResult, error) {
+func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBranch, committer, message string, metadata catalog.Metadata, params catalog.MergeParams) (*catalog.MergeResult, error) {
+	committer, metadata = c.commitAttribution(ctx, committer, metadata)
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "leftBranch", IsValid: ValidateBranchName(leftBranch)},
@@ -30,11 +31,19 @@ func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBran
 	}); err != nil {
 		return nil, err
 	}
+	switch params.Strategy {
+	case "", catalog.MergeStrategyFail, catalog.MergeStrategySourceWins, catalog.MergeStrategyDestWins:
+	default:
+		return nil, fmt.Errorf("merge strategy %s: %w", params.Strategy, catalog.ErrInvalidValue)
+	}
 
 	mergeResult := &catalog.MergeResult{
 		Summary: make(map[catalog.DifferenceType]int),
 	}
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
 		leftID, err := getBranchID(tx, repository, leftBranch, LockTypeUpdate)
 		if err != nil {
 			return nil, fmt.Errorf("left branch: %w", err)
@@ -43,7 +52,14 @@ func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBran
 		if err != nil {
 			return nil, fmt.Errorf("right branch: %w", err)
 		}
-		params := doDiffParams{
+		// the committed entries of a branch with a commit job include the job's chunks
+		if err := checkNoCommitJob(tx, leftID); err != nil {
+			return nil, fmt.Errorf("left branch: %w", err)
+		}
+		if err := checkNoCommitJob(tx, rightID); err != nil {
+			return nil, fmt.Errorf("right branch: %w", err)
+		}
+		diffParams := doDiffParams{
 			Repository:    repository,
 			LeftCommitID:  CommittedID,
 			LeftBranchID:  leftID,
@@ -52,14 +68,38 @@ func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBran
 			DiffParams: catalog.DiffParams{
 				Limit: -1,
 			},
+			MergeStrategy: params.Strategy,
+			MergeResolve:  params.Resolve,
+		}
+		if params.Resolve != nil {
+			// the resolver reads the objects of both entries
+			diffParams.AdditionalFields = []string{catalog.DBEntryFieldPhysicalAddress, catalog.DBEntryFieldSize, catalog.DBEntryFieldMetadata}
 		}
-		relation, err := getRefsRelationType(tx, params)
+		relation, err := getRefsRelationType(tx, diffParams)
 		if err != nil {
 			return nil, err
 		}
 		if relation == RelationTypeSame {
 			return nil, catalog.ErrSameBranchMergeNotSupported
 		}
+		// the lineage of a child branch includes the commits of its parent, squashed or not
+		if params.Squash && relation == RelationTypeFromParent {
+			return nil, fmt.Errorf("squash merge from parent branch: %w", catalog.ErrFeatureNotSupported)
+		}
+		if message == "" {
+			message = fmt.Sprintf("Merge '%s' into '%s'", leftBranch, rightBranch)
+		}
+		if err := c.validateMerge(ctx, tx, diffParams, &catalog.MergeValidation{
+			Repository:        repository,
+			SourceBranch:      leftBranch,
+			DestinationBranch: rightBranch,
+			Committer:         committer,
+			Message:           message,
+			Metadata:          metadata,
+			Params:            params,
+		}); err != nil {
+			return nil, err
+		}
 		nextCommitID, err := getNextCommitID(tx)
 		if err != nil {
 			return nil, err
@@ -68,13 +108,11 @@ func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBran
 		if err != nil {
 			return nil, err
 		}
-		rowsCounter, err := c.doMerge(ctx, tx, params, mergeResult, previousMaxCommitID, nextCommitID, relation)
+		var merged catalog.RepositoryUsage
+		rowsCounter, err := c.doMerge(ctx, tx, diffParams, mergeResult, previousMaxCommitID, nextCommitID, relation, &merged)
 		if err != nil {
 			return nil, err
 		}
-		if message == "" {
-			message = fmt.Sprintf("Merge '%s' into '%s'", leftBranch, rightBranch)
-		}
 		if rowsCounter == 0 {
 			commitDifferences, err := hasCommitDifferences(tx, leftID, rightID)
 			if err != nil {
@@ -84,7 +122,21 @@ func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBran
 				return nil, catalog.ErrNoDifferenceWasFound
 			}
 		}
-		err = insertMergeCommit(tx, relation, leftID, rightID, nextCommitID, previousMaxCommitID, committer, message, metadata)
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		// merged entries share the objects of the source branch, only objects merged by
+		// content are new.  A repository over its quota accepts no merges.
+		if err := checkRepositoryQuota(tx, repoID, merged.Objects, merged.StorageBytes); err != nil {
+			return nil, err
+		}
+		if !catalog.IsCommitLimitsExempt(ctx) {
+			if err := checkMergeCommitLimits(tx, repoID, rightID, nextCommitID, rowsCounter); err != nil {
+				return nil, err
+			}
+		}
+		err = insertMergeCommit(tx, relation, leftID, rightID, nextCommitID, previousMaxCommitID, committer, message, metadata, params.Squash)
 		if err != nil {
 			return nil, err
 		}
@@ -101,7 +153,9 @@ func (c *cataloger) Merge(ctx context.Context, repository, leftBranch, rightBran
 	return mergeResult, err
 }
 
-func (c *cataloger) doMerge(ctx context.Context, tx db.Tx, params doDiffParams, mergeResult *catalog.MergeResult, previousMaxCommitID CommitID, nextCommitID CommitID, relation RelationType) (int, error) {
+// doMerge applies the diff of params to its right branch, adding the objects merged by content
+// to merged
+func (c *cataloger) doMerge(ctx context.Context, tx db.Tx, params doDiffParams, mergeResult *catalog.MergeResult, previousMaxCommitID CommitID, nextCommitID CommitID, relation RelationType, merged *catalog.RepositoryUsage) (int, error) {
 	mergeCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	mergeBatchChan, errChan := c.initDiffWorker(mergeCtx, params)
@@ -118,6 +172,10 @@ func (c *cataloger) doMerge(ctx context.Context, tx db.Tx, params doDiffParams,
 					if d.Type == catalog.DifferenceTypeConflict {
 						return rowsCounter, catalog.ErrConflictFound
 					}
+					if d.MergedEntry != nil {
+						merged.Objects++
+						merged.StorageBytes += d.MergedEntry.Size
+					}
 				}
 				err := applyDiffChangesToRightBranch(tx, buf, previousMaxCommitID, nextCommitID, params.RightBranchID, relation)
 				if err != nil {
@@ -202,7 +260,15 @@ func applyDiffChangesToRightBranch(tx db.Tx, mergeBatch mergeBatchRecords, previ
 	paths := make([]string, 0, MergeBatchSize)
 	ctidArray := make([]string, 0, MergeBatchSize)
 	var tombstonePaths []string
+	var resolvedPaths []string
+	var mergedEntries []*catalog.Entry
 	for _, diffRec := range mergeBatch {
+		if diffRec.ResolvedConflict {
+			resolvedPaths = append(resolvedPaths, diffRec.Entry.Path)
+		}
+		if diffRec.MergedEntry != nil {
+			mergedEntries = append(mergedEntries, diffRec.MergedEntry)
+		}
 		if diffRec.Type == catalog.DifferenceTypeRemoved || diffRec.Type == catalog.DifferenceTypeChanged {
 			paths = append(paths, diffRec.Entry.Path)
 		}
@@ -217,6 +283,14 @@ func applyDiffChangesToRightBranch(tx db.Tx, mergeBatch mergeBatchRecords, previ
 		}
 	}
 	// apply changes
+	if len(resolvedPaths) > 0 {
+		// the source wins conflicts over uncommitted changes too
+		_, err := tx.Exec(`DELETE FROM catalog_entries WHERE branch_id = $1 AND path = ANY($2::text[]) AND min_commit = $3`,
+			rightID, resolvedPaths, MinCommitUncommittedIndicator)
+		if err != nil {
+			return err
+		}
+	}
 	if len(paths) > 0 {
 		// set entries that exist in the right branch as deleted by entries that were removed or changed
 		setMaxCommit := sq.Update("catalog_entries").
@@ -238,10 +312,12 @@ func applyDiffChangesToRightBranch(tx db.Tx, mergeBatch mergeBatchRecords, previ
 			Column("?", rightID).
 			Columns("path", "physical_address", "creation_date", "size", "checksum", "metadata").
 			Column("?", nextCommitID).
+			Columns("content_type", "content_encoding", "cache_control").
 			From("catalog_entries").
 			Where(sq.Eq{"ctid": ctidArray})
 		copyEntries := sq.Insert("catalog_entries").
-			Columns("branch_id", "path", "physical_address", "creation_date", "size", "checksum", "metadata", "min_commit").
+			Columns("branch_id", "path", "physical_address", "creation_date", "size", "checksum", "metadata", "min_commit",
+				"content_type", "content_encoding", "cache_control").
 			Select(internalSelect)
 		sql, args, err := copyEntries.PlaceholderFormat(sq.Dollar).ToSql()
 		if err != nil {
@@ -252,6 +328,16 @@ func applyDiffChangesToRightBranch(tx db.Tx, mergeBatch mergeBatchRecords, previ
 			return err
 		}
 	}
+	// write the objects merged by content
+	for _, entry := range mergedEntries {
+		_, err := tx.Exec(`INSERT INTO catalog_entries (branch_id,path,physical_address,creation_date,size,checksum,metadata,min_commit,content_type,content_encoding,cache_control)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+			rightID, entry.Path, entry.PhysicalAddress, entry.CreationDate, entry.Size, entry.Checksum, entry.Metadata, nextCommitID,
+			entry.ContentType, entry.ContentEncoding, entry.CacheControl)
+		if err != nil {
+			return err
+		}
+	}
 	// insert tombstones into parent branch that has a removed entry in its lineage
 	if len(tombstonePaths) > 0 {
 		sql := `INSERT INTO catalog_entries (branch_id,path,physical_address,size,checksum,metadata,min_commit,max_commit)
@@ -263,12 +349,27 @@ func applyDiffChangesToRightBranch(tx db.Tx, mergeBatch mergeBatchRecords, previ
 	}
 	return nil
 }
-func insertMergeCommit(tx db.Tx, relation RelationType, leftID int64, rightID int64, nextCommitID CommitID, previousMaxCommitID CommitID, committer string, msg string, metadata catalog.Metadata) error {
+
+// insertMergeCommit commits a merge from leftID into rightID.  A squash merge commit references
+// the merged commit of leftID in its metadata.
+func insertMergeCommit(tx db.Tx, relation RelationType, leftID int64, rightID int64, nextCommitID CommitID, previousMaxCommitID CommitID, committer string, msg string, metadata catalog.Metadata, squash bool) error {
 	var childNewLineage []int64
 	leftLastCommitID, err := getLastCommitIDByBranchID(tx, leftID)
 	if err != nil {
 		return err
 	}
+	if squash {
+		var leftName string
+		if err := tx.GetPrimitive(&leftName, `SELECT name FROM catalog_branches WHERE id = $1`, leftID); err != nil {
+			return fmt.Errorf("source branch name: %w", err)
+		}
+		squashMetadata := make(catalog.Metadata, len(metadata)+1)
+		for k, v := range metadata {
+			squashMetadata[k] = v
+		}
+		squashMetadata[catalog.MergeSquashSourceMetadataKey] = MakeReference(leftName, leftLastCommitID)
+		metadata = squashMetadata
+	}
 	if relation == RelationTypeFromParent {
 		var parentLastLineage []int64
 		err = tx.Get(&parentLastLineage, `SELECT DISTINCT ON (branch_id) lineage_commits FROM catalog_commits
@@ -279,8 +380,8 @@ func insertMergeCommit(tx db.Tx, relation RelationType, leftID int64, rightID in
 		childNewLineage = append([]int64{int64(leftLastCommitID)}, parentLastLineage...)
 	}
 	_, err = tx.Exec(`INSERT INTO catalog_commits (branch_id, commit_id, previous_commit_id,committer, message, creation_date, metadata, merge_source_branch, merge_source_commit,
-                     lineage_commits, merge_type)
-		VALUES ($1,$2,$3,$4,$5,transaction_timestamp(),$6,$7,$8,$9,$10)`,
-		rightID, nextCommitID, previousMaxCommitID, committer, msg, metadata, leftID, leftLastCommitID, childNewLineage, relation)
+                     lineage_commits, merge_type, squash)
+		VALUES ($1,$2,$3,$4,$5,transaction_timestamp(),$6,$7,$8,$9,$10,$11)`,
+		rightID, nextCommitID, previousMaxCommitID, committer, msg, metadata, leftID, leftLastCommitID, childNewLineage, relation, squash)
 	return err
 }
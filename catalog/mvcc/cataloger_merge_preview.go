@@ -0,0 +1,86 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// MergePreview scans the differences Merge would apply from leftBranch to rightBranch, in a
+// read-only transaction.  Unlike Merge, it does not stop at the first conflict.
+func (c *cataloger) MergePreview(ctx context.Context, repository, leftBranch, rightBranch string) (*catalog.MergePreview, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "leftBranch", IsValid: ValidateBranchName(leftBranch)},
+		{Name: "rightBranch", IsValid: ValidateBranchName(rightBranch)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		leftID, err := c.getBranchIDCache(tx, repository, leftBranch)
+		if err != nil {
+			return nil, fmt.Errorf("left branch: %w", err)
+		}
+		rightID, err := c.getBranchIDCache(tx, repository, rightBranch)
+		if err != nil {
+			return nil, fmt.Errorf("right branch: %w", err)
+		}
+		if err := checkNoCommitJob(tx, leftID); err != nil {
+			return nil, fmt.Errorf("left branch: %w", err)
+		}
+		if err := checkNoCommitJob(tx, rightID); err != nil {
+			return nil, fmt.Errorf("right branch: %w", err)
+		}
+		params := doDiffParams{
+			Repository:    repository,
+			LeftCommitID:  CommittedID,
+			LeftBranchID:  leftID,
+			RightCommitID: UncommittedID,
+			RightBranchID: rightID,
+			DiffParams: catalog.DiffParams{
+				Limit: -1,
+			},
+		}
+		relation, err := getRefsRelationType(tx, params)
+		if err != nil {
+			return nil, err
+		}
+		if relation == RelationTypeSame {
+			return nil, catalog.ErrSameBranchMergeNotSupported
+		}
+		scanner, err := NewDiffScanner(tx, params)
+		if err != nil {
+			return nil, err
+		}
+		preview := &catalog.MergePreview{
+			Summary: make(map[catalog.DifferenceType]int),
+		}
+		for scanner.Next() {
+			v := scanner.Value()
+			preview.Summary[v.Type]++
+			if v.Type != catalog.DifferenceTypeConflict {
+				continue
+			}
+			right := scanner.MatchedRight()
+			conflict := &catalog.MergeConflict{
+				Path:            v.Path,
+				SourceType:      evaluateSourceChange(scanner.Left(), right),
+				DestinationType: catalog.DifferenceTypeChanged,
+			}
+			if right == nil || right.IsDeleted() {
+				conflict.DestinationType = catalog.DifferenceTypeRemoved
+			}
+			preview.Conflicts = append(preview.Conflicts, conflict)
+		}
+		if err := scanner.Error(); err != nil {
+			return nil, err
+		}
+		return preview, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.MergePreview), nil
+}
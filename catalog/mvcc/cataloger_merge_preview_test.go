@@ -0,0 +1,56 @@
+package mvcc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_MergePreview(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	for _, path := range []string{"/file0", "/file1", "/file2"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", path, nil, "")
+	}
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	// change /file0 on both branches, remove /file1 on branch1 and change it on master, add
+	// /file3 on branch1
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file0", nil, "source")
+	testutil.MustDo(t, "delete on branch1", c.DeleteEntry(ctx, repository, "branch1", "/file1"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file3", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "commit to branch1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file0", nil, "dest")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "dest")
+	_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "second commit to master", err)
+
+	preview, err := c.MergePreview(ctx, repository, "branch1", "master")
+	testutil.MustDo(t, "merge preview", err)
+	if diff := deep.Equal(preview, &catalog.MergePreview{
+		Summary: map[catalog.DifferenceType]int{
+			catalog.DifferenceTypeAdded:    1,
+			catalog.DifferenceTypeConflict: 2,
+		},
+		Conflicts: []*catalog.MergeConflict{
+			{Path: "/file0", SourceType: catalog.DifferenceTypeChanged, DestinationType: catalog.DifferenceTypeChanged},
+			{Path: "/file1", SourceType: catalog.DifferenceTypeRemoved, DestinationType: catalog.DifferenceTypeChanged},
+		},
+	}); diff != nil {
+		t.Error("merge preview", diff)
+	}
+
+	// nothing was merged
+	testVerifyEntries(t, ctx, c, repository, "master", []testEntryInfo{
+		{Path: "/file0", Seed: "dest"},
+		{Path: "/file1", Seed: "dest"},
+		{Path: "/file3", Deleted: true},
+	})
+}
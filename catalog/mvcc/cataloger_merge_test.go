@@ -21,7 +21,7 @@ func TestCataloger_Merge_FromParentNoChangesInChild(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file"+strconv.Itoa(i), nil, "")
 	}
-	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to master", err)
 
 	// create branch based on master
@@ -41,7 +41,7 @@ func TestCataloger_Merge_FromParentNoChangesInChild(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", overFilename, nil, "seed1")
 
 	// commit, merge and verify
-	_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "second commit to master", err)
 
 	// before the merge - make sure we see the deleted file
@@ -51,7 +51,7 @@ func TestCataloger_Merge_FromParentNoChangesInChild(t *testing.T) {
 	}
 
 	// merge master to branch1
-	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatal("Merge from master to branch1 failed:", err)
 	}
@@ -78,7 +78,7 @@ func TestCataloger_Merge_FromParentNoChangesInChild(t *testing.T) {
 		t.Fatal("Merge Summary", diff)
 	}
 	// merge again - nothing should happen
-	res, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	res, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{})
 	if err != catalog.ErrNoDifferenceWasFound {
 		t.Fatal("Merge() expected ErrNoDifferenceWasFound, got:", err)
 	}
@@ -103,7 +103,7 @@ func TestCataloger_Merge_FromParentConflicts(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file"+strconv.Itoa(i), nil, "")
 	}
-	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to master", err)
 
 	// create branch based on master
@@ -123,7 +123,7 @@ func TestCataloger_Merge_FromParentConflicts(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", overFilename, nil, "seed1")
 
 	// commit changes on master
-	_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "second commit to master", err)
 
 	// make other changes to the same files
@@ -133,7 +133,7 @@ func TestCataloger_Merge_FromParentConflicts(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", overFilename, nil, "seed2")
 
 	// merge should identify conflicts on pending changes
-	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{})
 
 	// expected to find 2 conflicts on the files we update/created with the same path
 	if !errors.Is(err, catalog.ErrConflictFound) {
@@ -149,7 +149,7 @@ func TestCataloger_Merge_FromParentNoChangesInParent(t *testing.T) {
 	c := testCataloger(t)
 	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
 	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
-	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{})
 	expectedErr := catalog.ErrNoDifferenceWasFound
 	if !errors.Is(err, expectedErr) {
 		t.Errorf("Merge err = %s, expected %s", err, expectedErr)
@@ -179,7 +179,7 @@ func TestCataloger_Merge_FromParentChangesInBoth(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file"+strconv.Itoa(i), nil, "")
 	}
-	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to master", err)
 
 	// create branch based on master
@@ -199,18 +199,18 @@ func TestCataloger_Merge_FromParentChangesInBoth(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", overFilename, nil, "seed1")
 
 	// commit changes on master
-	_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "second commit to master", err)
 
 	// make other changes
 	for i := 0; i < 3; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/b2/file"+strconv.Itoa(i), nil, "seed2")
 	}
-	_, err = c.Commit(ctx, repository, "branch1", "first commit to branch1", "tester", nil)
+	_, err = c.Commit(ctx, repository, "branch1", "first commit to branch1", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "first commit on branch1", err)
 
 	// merge should work and grab all the changes from master
-	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatal("Merge from master to branch1 failed:", err)
 	}
@@ -263,7 +263,7 @@ func TestCataloger_Merge_FromParentThreeBranches(t *testing.T) {
 		for i := 0; i < items; i++ {
 			testCatalogerCreateEntry(t, ctx, c, repository, branch, "/file"+strconv.Itoa(i+items*offset), nil, "")
 		}
-		_, err := c.Commit(ctx, repository, branch, msg, "tester", nil)
+		_, err := c.Commit(ctx, repository, branch, msg, "tester", nil, catalog.CommitParams{})
 		testutil.MustDo(t, msg, err)
 	}
 	// create 3 files and commit on master
@@ -285,14 +285,14 @@ func TestCataloger_Merge_FromParentThreeBranches(t *testing.T) {
 		c.DeleteEntry(ctx, repository, "master", delFilename))
 	const overFilename = "/file2"
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", overFilename, nil, "seed1")
-	_, err := c.Commit(ctx, repository, "master", "second commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "second commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "second commit to master", err)
 
 	// merge the above down (from master) to branch1
-	_, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "Merge changes from master to branch1", err)
 	// merge the changes from branch1 to branch2
-	res, err := c.Merge(ctx, repository, "branch1", "branch2", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "branch1", "branch2", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "Merge changes from master to branch1", err)
 
 	// verify valid commit id
@@ -340,14 +340,14 @@ func TestCataloger_Merge_FromChildNoChanges(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file"+strconv.Itoa(i), nil, "")
 	}
-	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to master", err)
 
 	// create branch based on master
 	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
 
 	// merge empty branch into master
-	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
 	expectedErr := catalog.ErrNoDifferenceWasFound
 	if !errors.Is(err, expectedErr) {
 		t.Fatalf("Merge from branch1 to master err=%s, expected=%s", err, expectedErr)
@@ -368,7 +368,7 @@ func TestCataloger_Merge_FromChildChangesOnChild(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file"+strconv.Itoa(i), nil, "")
 	}
-	_, err := c.Commit(ctx, repository, "master", "First commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "First commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "First commit to master", err)
 
 	// create branch based on master
@@ -388,11 +388,11 @@ func TestCataloger_Merge_FromChildChangesOnChild(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", overFilename, nil, "seed1")
 
 	// commit changes on child and merge
-	_, err = c.Commit(ctx, repository, "branch1", "First commit to branch1", "tester", nil)
+	_, err = c.Commit(ctx, repository, "branch1", "First commit to branch1", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "First commit to branch1", err)
 
 	// merge empty branch into master
-	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatalf("Merge from branch1 to master err=%s, expected none", err)
 	}
@@ -441,7 +441,7 @@ func TestCataloger_Merge_FromChildThreeBranches(t *testing.T) {
 		for i := 0; i < items; i++ {
 			testCatalogerCreateEntry(t, ctx, c, repository, branch, "/file"+strconv.Itoa(i+items*offset), nil, "")
 		}
-		_, err := c.Commit(ctx, repository, branch, msg, "tester", nil)
+		_, err := c.Commit(ctx, repository, branch, msg, "tester", nil, catalog.CommitParams{})
 		testutil.MustDo(t, msg, err)
 	}
 	// create 3 files and commit on master
@@ -463,11 +463,11 @@ func TestCataloger_Merge_FromChildThreeBranches(t *testing.T) {
 		c.DeleteEntry(ctx, repository, "branch2", delFilename))
 	const overFilename = "/file2"
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch2", overFilename, nil, "seed1")
-	_, err := c.Commit(ctx, repository, "branch2", "second commit to branch2", "tester", nil)
+	_, err := c.Commit(ctx, repository, "branch2", "second commit to branch2", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "second commit to branch2", err)
 
 	// merge the above up to master (from branch2)
-	res, err := c.Merge(ctx, repository, "branch2", "branch1", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "branch2", "branch1", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "Merge changes from branch2 to branch1", err)
 
 	if !IsValidReference(res.Reference) {
@@ -510,7 +510,7 @@ func TestCataloger_Merge_FromChildThreeBranches(t *testing.T) {
 	})
 
 	// merge the changes from branch1 to master
-	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "Merge changes from branch1 to master", err)
 
 	// verify valid commit id
@@ -562,11 +562,11 @@ func TestCataloger_Merge_FromChildNewDelSameEntry(t *testing.T) {
 
 	// create new file and commit to branch
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file0", nil, "")
-	_, err := c.Commit(ctx, repository, "branch1", "Add new file", "tester", nil)
+	_, err := c.Commit(ctx, repository, "branch1", "Add new file", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "add new file to branch", err)
 
 	// merge branch to master
-	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatalf("Merge from branch1 to master err=%s, expected none", err)
 	}
@@ -597,11 +597,11 @@ func TestCataloger_Merge_FromChildNewDelSameEntry(t *testing.T) {
 	// delete file on branch and commit
 	testutil.MustDo(t, "Delete file0 from branch",
 		c.DeleteEntry(ctx, repository, "branch1", "/file0"))
-	_, err = c.Commit(ctx, repository, "branch1", "Delete the file", "tester", nil)
+	_, err = c.Commit(ctx, repository, "branch1", "Delete the file", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "Commit with deleted file", err)
 
 	// merge branch to master
-	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatalf("Merge from branch1 to master err=%s, expected none", err)
 	}
@@ -641,11 +641,11 @@ func TestCataloger_Merge_FromChildNewEntrySameEntry(t *testing.T) {
 
 	// create new file and commit to branch
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file0", nil, "")
-	_, err := c.Commit(ctx, repository, "branch1", "Add new file", "tester", nil)
+	_, err := c.Commit(ctx, repository, "branch1", "Add new file", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "add new file to branch", err)
 
 	// merge branch to master
-	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatalf("Merge from branch1 to master err=%s, expected none", err)
 	}
@@ -676,11 +676,11 @@ func TestCataloger_Merge_FromChildNewEntrySameEntry(t *testing.T) {
 
 	// create same file and commit to branch
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file0", nil, "")
-	_, err = c.Commit(ctx, repository, "branch1", "Add same file", "tester", nil)
+	_, err = c.Commit(ctx, repository, "branch1", "Add same file", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "add same file to branch", err)
 
 	// merge branch to master
-	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatalf("Merge from branch1 to master err=%s, expected none", err)
 	}
@@ -715,7 +715,7 @@ func TestCataloger_Merge_FromChildDelModifyGrandparentFiles(t *testing.T) {
 	// create new file and commit to branch
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file0", nil, "")
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
-	_, err := c.Commit(ctx, repository, "master", "Add new files", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "Add new files", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "add new files to master", err)
 
 	// create branches
@@ -726,11 +726,11 @@ func TestCataloger_Merge_FromChildDelModifyGrandparentFiles(t *testing.T) {
 	testutil.MustDo(t, "Delete /file0 from master on branch2",
 		c.DeleteEntry(ctx, repository, "branch2", "/file0"))
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch2", "/file1", nil, "seed1")
-	_, err = c.Commit(ctx, repository, "branch2", "Delete the file", "tester", nil)
+	_, err = c.Commit(ctx, repository, "branch2", "Delete the file", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "Commit with deleted file", err)
 
 	// merge changes from branch2 to branch1
-	res, err := c.Merge(ctx, repository, "branch2", "branch1", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "branch2", "branch1", "tester", "", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatalf("Merge from branch2 to branch1 err=%s, expected none", err)
 	}
@@ -772,22 +772,22 @@ func TestCataloger_Merge_FromChildConflicts(t *testing.T) {
 
 	// create new file and commit to branch
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file0", nil, "seed0")
-	_, err := c.Commit(ctx, repository, "master", "Add new files", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "Add new files", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "add new files to master", err)
 
 	// branch and modify the file
 	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file0", nil, "seed1")
-	_, err = c.Commit(ctx, repository, "branch1", "Modify the file", "tester", nil)
+	_, err = c.Commit(ctx, repository, "branch1", "Modify the file", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "modify /file0 on branch1", err)
 
 	// modify the file on master
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file0", nil, "seed3")
-	_, err = c.Commit(ctx, repository, "master", "Modify the file (master)", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "Modify the file (master)", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "modify /file0 on master", err)
 
 	// merge changes from branch to master should find the conflict
-	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
 	if !errors.Is(err, catalog.ErrConflictFound) {
 		t.Fatalf("Merge from branch1 to master err=%s, expected conflict", err)
 	}
@@ -823,7 +823,7 @@ func TestCataloger_Merge_FromParentThreeBranchesExtended1(t *testing.T) {
 		for i := 0; i < items; i++ {
 			testCatalogerCreateEntry(t, ctx, c, repository, branch, "/file"+strconv.Itoa(i+items*offset), nil, "")
 		}
-		_, err := c.Commit(ctx, repository, branch, msg, "tester", nil)
+		_, err := c.Commit(ctx, repository, branch, msg, "tester", nil, catalog.CommitParams{})
 		testutil.MustDo(t, msg, err)
 	}
 	// create 3 files and commit on master
@@ -845,14 +845,14 @@ func TestCataloger_Merge_FromParentThreeBranchesExtended1(t *testing.T) {
 		c.DeleteEntry(ctx, repository, "master", delFilename))
 	const overFilename = "/file2"
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", overFilename, nil, "seed1")
-	_, err := c.Commit(ctx, repository, "master", "second commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "second commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "second commit to master", err)
 
 	// merge the above down (from master) to branch1
-	_, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "Merge changes from master to branch1", err)
 	// merge the changes from branch1 to branch2
-	res, err := c.Merge(ctx, repository, "branch1", "branch2", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "branch1", "branch2", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "Merge changes from master to branch1", err)
 
 	// verify valid commit id
@@ -891,21 +891,21 @@ func TestCataloger_Merge_FromParentThreeBranchesExtended1(t *testing.T) {
 	// test that an object deleted at master becomes deleted at branch2 only after merges from both parents
 	testutil.MustDo(t, "delete committed file on master",
 		c.DeleteEntry(ctx, repository, "master", "/file0"))
-	_, err = c.Commit(ctx, repository, "master", "commit file0 deletion", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "commit file0 deletion", "tester", nil, catalog.CommitParams{})
 	testutil.Must(t, err)
 
 	testCatalogerGetEntry(t, ctx, c, repository, "branch2", "/file0", true)
 	testCatalogerGetEntry(t, ctx, c, repository, "branch1", "/file0", true)
 	testCatalogerGetEntry(t, ctx, c, repository, "master", "/file0", false)
 
-	_, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge to master to branch1", err)
 
 	testCatalogerGetEntry(t, ctx, c, repository, "branch2", "/file0", true)
 	testCatalogerGetEntry(t, ctx, c, repository, "branch1", "/file0", false)
 	testCatalogerGetEntry(t, ctx, c, repository, "master", "/file0", false)
 
-	_, err = c.Merge(ctx, repository, "branch1", "branch2", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "branch1", "branch2", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge branch1 to branch2", err)
 
 	testCatalogerGetEntry(t, ctx, c, repository, "branch2", "/file0", false)
@@ -915,10 +915,10 @@ func TestCataloger_Merge_FromParentThreeBranchesExtended1(t *testing.T) {
 	// test that the same object with the same name does not create a conflict in child to parent , and is not a change
 
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch2", "/file0", nil, "seed1")
-	_, _ = c.Commit(ctx, repository, "branch2", "commit file0 creation", "tester", nil)
+	_, _ = c.Commit(ctx, repository, "branch2", "commit file0 creation", "tester", nil, catalog.CommitParams{})
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file0", nil, "seed1")
-	_, _ = c.Commit(ctx, repository, "master", "commit file0 creation", "tester", nil)
-	res, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	_, _ = c.Commit(ctx, repository, "master", "commit file0 creation", "tester", nil, catalog.CommitParams{})
+	res, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge master to branch1", err)
 	if res.Reference == "" {
 		t.Fatal("No merge reference")
@@ -942,7 +942,7 @@ func TestCataloger_Merge_FromParentThreeBranchesExtended1(t *testing.T) {
 	//if !differences.Equal(expectedDifferences) {
 	//	t.Errorf("Merge differences = %s, expected %s", spew.Sdump(differences), spew.Sdump(expectedDifferences))
 	//}
-	res, err = c.Merge(ctx, repository, "branch1", "branch2", "tester", "", nil)
+	res, err = c.Merge(ctx, repository, "branch1", "branch2", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge branch1 to branch2", err)
 	if res.Reference == "" {
 		t.Fatal("No merge results")
@@ -965,15 +965,15 @@ func TestCataloger_Merge_FromParentThreeBranchesExtended1(t *testing.T) {
 	// deletion in master will force  physically delete in grandchild
 	testutil.MustDo(t, "delete committed file on master",
 		c.DeleteEntry(ctx, repository, "master", "/file0"))
-	_, err = c.Commit(ctx, repository, "master", "commit file0 deletion", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "commit file0 deletion", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit file0 delete", err)
-	res, err = c.Merge(ctx, repository, "master", "branch1", "tester", "bubling /file0 deletion up", nil)
+	res, err = c.Merge(ctx, repository, "master", "branch1", "tester", "bubling /file0 deletion up", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge master to branch1", err)
 	if res.Reference == "" {
 		t.Fatal("No merge reference")
 	}
 
-	res, err = c.Merge(ctx, repository, "branch1", "branch2", "tester", "forcing file0 on branch2 to delete", nil)
+	res, err = c.Merge(ctx, repository, "branch1", "branch2", "tester", "forcing file0 on branch2 to delete", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge master to branch1", err)
 	if res.Reference == "" {
 		t.Fatal("No merge reference")
@@ -995,21 +995,21 @@ func TestCataloger_Merge_FromParentThreeBranchesExtended1(t *testing.T) {
 	//}
 
 	//identical entries created in child and grandparent do not create conflict - even when grandparent is uncommitted
-	_, err = c.Merge(ctx, repository, "branch2", "branch1", "tester", "empty updates", nil)
+	_, err = c.Merge(ctx, repository, "branch2", "branch1", "tester", "empty updates", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge branch2 to branch1", err)
 
-	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "empty updates", nil)
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "empty updates", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge branch1 to master", err)
 
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch2", "/file111", nil, "seed1")
-	_, err = c.Commit(ctx, repository, "branch2", "commit file0 creation", "tester", nil)
+	_, err = c.Commit(ctx, repository, "branch2", "commit file0 creation", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit file0 creation to branch2", err)
 
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file111", nil, "seed2")
-	_, err = c.Merge(ctx, repository, "branch2", "branch1", "tester", "pushing /file111 down", nil)
+	_, err = c.Merge(ctx, repository, "branch2", "branch1", "tester", "pushing /file111 down", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge branch2 to branch1", err)
 
-	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "pushing /file111 down", nil)
+	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "pushing /file111 down", nil, catalog.MergeParams{})
 	if !errors.Is(err, catalog.ErrConflictFound) {
 		t.Fatalf("Merge err=%s, expected conflict", err)
 	}
@@ -1024,15 +1024,15 @@ func TestCataloger_Merge_FromParentThreeBranchesExtended1(t *testing.T) {
 		c.DeleteEntry(ctx, repository, "master", "/file111"))
 
 	// push file111 delete
-	_, err = c.Merge(ctx, repository, "branch1", "branch2", "tester", "delete /file111 up", nil)
+	_, err = c.Merge(ctx, repository, "branch1", "branch2", "tester", "delete /file111 up", nil, catalog.MergeParams{})
 	testutil.Must(t, err)
 
 	testutil.MustDo(t, "delete committed file on branch1",
 		c.DeleteEntry(ctx, repository, "branch1", "/file111"))
-	_, err = c.Commit(ctx, repository, "branch1", "commit file111 deletion", "tester", nil)
+	_, err = c.Commit(ctx, repository, "branch1", "commit file111 deletion", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit file111 to branch1", err)
 
-	res, err = c.Merge(ctx, repository, "branch1", "branch2", "tester", "delete /file111 up", nil)
+	res, err = c.Merge(ctx, repository, "branch1", "branch2", "tester", "delete /file111 up", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge branch1 to branch2", err)
 	if res.Reference == "" {
 		t.Fatal("No merge results")
@@ -1064,7 +1064,7 @@ func TestCataloger_MergeOverDeletedEntries(t *testing.T) {
 	// setup a report with 'master' with a single file, and branch 'b1' that started after the file was committed
 	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "fileX", nil, "master")
-	_, err := c.Commit(ctx, repository, "master", "fileX", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "fileX", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit file first time on master", err)
 	_, err = c.CreateBranch(ctx, repository, "b1", "master")
 	testutil.MustDo(t, "create branch b1", err)
@@ -1072,19 +1072,19 @@ func TestCataloger_MergeOverDeletedEntries(t *testing.T) {
 	// delete file on 'b1', commit and check that we don't get the file on 'b1' branch
 	err = c.DeleteEntry(ctx, repository, "b1", "fileX")
 	testutil.MustDo(t, "delete file on branch b1", err)
-	_, err = c.Commit(ctx, repository, "b1", "fileX", "tester", nil)
+	_, err = c.Commit(ctx, repository, "b1", "fileX", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit file delete on b1", err)
 	_, err = c.GetEntry(ctx, repository, "b1", "fileX", catalog.GetEntryParams{})
 	if !errors.Is(err, catalog.ErrEntryNotFound) {
 		t.Fatal("expected entry not found, got", err)
 	}
-	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge changes from master to b1 part 2", nil)
+	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge changes from master to b1 part 2", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge master to b1 part 2", err)
 
 	// create and commit the same file, different content, on 'master', merge to 'b1' and check that we get the file on 'b1'
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "fileX", nil, "master2")
-	_, err = c.Commit(ctx, repository, "master", "fileX", "tester", nil)
-	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge changes from master to b1", nil)
+	_, err = c.Commit(ctx, repository, "master", "fileX", "tester", nil, catalog.CommitParams{})
+	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge changes from master to b1", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge master to b1", err)
 	ent, err := c.GetEntry(ctx, repository, "b1", "fileX", catalog.GetEntryParams{})
 	testutil.MustDo(t, "get entry again from b1", err)
@@ -1100,26 +1100,26 @@ func TestCataloger_MergeWithoutDiff(t *testing.T) {
 	// setup a report with 'master' with a single file, and branch 'b1' that started after the file was committed
 	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "fileX", nil, "master")
-	_, err := c.Commit(ctx, repository, "master", "fileX", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "fileX", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit file first time on master", err)
 	_, err = c.CreateBranch(ctx, repository, "b1", "master")
 	testutil.MustDo(t, "create branch b1", err)
-	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge nothing from master to b1", nil)
+	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge nothing from master to b1", nil, catalog.MergeParams{})
 	if !errors.Is(err, catalog.ErrNoDifferenceWasFound) {
 		t.Fatalf("Merge expected err=%s, expected=%s", err, catalog.ErrNoDifferenceWasFound)
 	}
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file_dummy", nil, "master1")
-	_, err = c.Commit(ctx, repository, "master", "file_dummy", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "file_dummy", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit dummy file  master", err)
 	err = c.DeleteEntry(ctx, repository, "master", "file_dummy")
 	testutil.MustDo(t, "delete dummy_file on master", err)
-	_, err = c.Commit(ctx, repository, "master", "file_dummy delete", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "file_dummy delete", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit dummy file  deletion", err)
-	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge nothing from master to b1", nil)
+	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge nothing from master to b1", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatalf("error on merge with no changes:%+v", err)
 	}
-	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge nothing from master to b1", nil)
+	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge nothing from master to b1", nil, catalog.MergeParams{})
 	if !errors.Is(err, catalog.ErrNoDifferenceWasFound) {
 		t.Fatalf("Merge expected err=%s, expected=%s", err, catalog.ErrNoDifferenceWasFound)
 	}
@@ -1133,11 +1133,11 @@ func TestCataloger_MergeFromChildAfterMergeFromParent(t *testing.T) {
 	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
 	// first entry creation
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "fileX", nil, "master")
-	_, err := c.Commit(ctx, repository, "master", "fileX", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "fileX", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit file first time on master", err)
 	_, err = c.CreateBranch(ctx, repository, "b1", "master")
 	testutil.MustDo(t, "create branch b1", err)
-	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge nothing from master to b1", nil)
+	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge nothing from master to b1", nil, catalog.MergeParams{})
 	if !errors.Is(err, catalog.ErrNoDifferenceWasFound) {
 		t.Fatalf("merge err=%s, expected ErrNoDifferenceWasFound", err)
 	}
@@ -1146,18 +1146,18 @@ func TestCataloger_MergeFromChildAfterMergeFromParent(t *testing.T) {
 	// two entries on master
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "fileY", nil, "master1")
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "fileZ", nil, "master1")
-	_, err = c.Commit(ctx, repository, "master", "fileY and fileZ", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "fileY and fileZ", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit fileY  master", err)
 	// merge them into child
-	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge fileY from master to b1", nil)
+	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge fileY from master to b1", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge into branch b1", err)
 	// delete one of those files in b1
 	err = c.DeleteEntry(ctx, repository, "b1", "fileY")
 	testutil.MustDo(t, "delete fileY on b1", err)
 	testCatalogerCreateEntry(t, ctx, c, repository, "b1", "fileZ", nil, "master1")
-	_, err = c.Commit(ctx, repository, "b1", "fileY and fileZ", "tester", nil)
+	_, err = c.Commit(ctx, repository, "b1", "fileY and fileZ", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit fileY b1", err)
-	_, err = c.Merge(ctx, repository, "b1", "master", "tester", "merge nothing from master to b1", nil)
+	_, err = c.Merge(ctx, repository, "b1", "master", "tester", "merge nothing from master to b1", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatalf("Merge err=%s, expected none", err)
 	}
@@ -1166,18 +1166,18 @@ func TestCataloger_MergeFromChildAfterMergeFromParent(t *testing.T) {
 	// two entries on master
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "fileYY", nil, "master1")
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", "fileZZ", nil, "master1")
-	_, err = c.Commit(ctx, repository, "master", "fileYY and fileZZ", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "fileYY and fileZZ", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit fileYY  master", err)
 	// merge them into child
-	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge fileYY from master to b1", nil)
+	_, err = c.Merge(ctx, repository, "master", "b1", "tester", "merge fileYY from master to b1", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge into branch b1", err)
 	// delete one of those files in b1
 	err = c.DeleteEntry(ctx, repository, "b1", "fileYY")
 	testutil.MustDo(t, "delete fileYY on b1", err)
 	testCatalogerCreateEntry(t, ctx, c, repository, "b1", "fileZZ", nil, "master1")
-	_, err = c.Commit(ctx, repository, "b1", "fileYY and fileZZ", "tester", nil)
+	_, err = c.Commit(ctx, repository, "b1", "fileYY and fileZZ", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit fileYY b1", err)
-	_, err = c.Merge(ctx, repository, "b1", "master", "tester", "merge nothing from master to b1", nil)
+	_, err = c.Merge(ctx, repository, "b1", "master", "tester", "merge nothing from master to b1", nil, catalog.MergeParams{})
 	if err != nil {
 		t.Fatalf("Merge err=%s, expected none", err)
 	}
@@ -1225,10 +1225,10 @@ func TestCataloger_Merge_Hooks(t *testing.T) {
 
 			// create file to merge
 			testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file1", nil, "")
-			_, err := c.Commit(ctx, repository, "branch1", "commit to master", "tester", nil)
+			_, err := c.Commit(ctx, repository, "branch1", "commit to master", "tester", nil, catalog.CommitParams{})
 			testutil.MustDo(t, "commit to branch1", err)
 
-			res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil)
+			res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
 
 			if !errors.Is(err, tt.err) {
 				t.Error("hook did not fail merge: ", err)
@@ -1261,7 +1261,7 @@ func TestCataloger_Merge_FromParentConflicts_WithSkip(t *testing.T) {
 	for i := 1000; i < 4000; i += 1000 {
 		testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file"+strconv.Itoa(i), nil, "")
 	}
-	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to master", err)
 
 	// create branch based on master
@@ -1281,7 +1281,7 @@ func TestCataloger_Merge_FromParentConflicts_WithSkip(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, "master", overFilename, nil, "seed1")
 
 	// commit changes on master
-	_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil)
+	_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "second commit to master", err)
 	for i := 1000; i < 4000; i++ {
 		testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file"+strconv.Itoa(i), nil, "seed1")
@@ -1293,7 +1293,7 @@ func TestCataloger_Merge_FromParentConflicts_WithSkip(t *testing.T) {
 	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", overFilename, nil, "seed2")
 
 	// merge should identify conflicts on pending changes
-	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil)
+	res, err := c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{})
 
 	// expected to find 2 conflicts on the files we update/created with the same path
 	if !errors.Is(err, catalog.ErrConflictFound) {
@@ -1303,3 +1303,166 @@ func TestCataloger_Merge_FromParentConflicts_WithSkip(t *testing.T) {
 		t.Errorf("Merge reference = %s, expected to be empty", res.Reference)
 	}
 }
+
+func TestCataloger_Merge_Squash(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file0", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	// two commits on branch1 to squash
+	for i := 1; i < 3; i++ {
+		testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file"+strconv.Itoa(i), nil, "")
+		_, err := c.Commit(ctx, repository, "branch1", "commit to branch1", "tester", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit to branch1", err)
+	}
+	branchHead, err := c.GetBranchReference(ctx, repository, "branch1")
+	testutil.MustDo(t, "get branch1 reference", err)
+
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "", catalog.Metadata{"key": "value"}, catalog.MergeParams{Squash: true})
+	testutil.MustDo(t, "squash merge branch1 to master", err)
+	testVerifyEntries(t, ctx, c, repository, "master", []testEntryInfo{
+		{Path: "/file0"},
+		{Path: "/file1"},
+		{Path: "/file2"},
+	})
+
+	commitLog, err := c.GetCommit(ctx, repository, res.Reference)
+	testutil.MustDo(t, "get squash commit", err)
+	if len(commitLog.Parents) != 1 {
+		t.Errorf("squash commit parents %v, expected only the previous commit", commitLog.Parents)
+	}
+	if diff := deep.Equal(commitLog.Metadata, catalog.Metadata{
+		"key":                                "value",
+		catalog.MergeSquashSourceMetadataKey: branchHead,
+	}); diff != nil {
+		t.Error("squash commit metadata", diff)
+	}
+	commits, _, err := c.ListCommits(ctx, repository, "master", "", -1, catalog.CommitsFilter{})
+	testutil.MustDo(t, "list master commits", err)
+	for _, commit := range commits {
+		if commit.Message == "commit to branch1" {
+			t.Errorf("master log includes squashed commit %s", commit.Reference)
+		}
+	}
+
+	// changes made after the squash merge are merged again
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file3", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "another commit to branch1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	res, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{Squash: true})
+	testutil.MustDo(t, "squash merge branch1 to master again", err)
+	if diff := deep.Equal(res.Summary, map[catalog.DifferenceType]int{
+		catalog.DifferenceTypeAdded: 1,
+	}); diff != nil {
+		t.Error("second squash merge summary", diff)
+	}
+
+	// a child branch lineage always includes its parent commits
+	_, err = c.Merge(ctx, repository, "master", "branch1", "tester", "", nil, catalog.MergeParams{Squash: true})
+	if !errors.Is(err, catalog.ErrFeatureNotSupported) {
+		t.Errorf("squash merge from parent err=%v, expected %s", err, catalog.ErrFeatureNotSupported)
+	}
+}
+
+func TestCataloger_Merge_Strategy(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		strategy catalog.MergeStrategy
+		wantErr  error
+		wantSeed string
+	}{
+		{strategy: "", wantErr: catalog.ErrConflictFound},
+		{strategy: catalog.MergeStrategyFail, wantErr: catalog.ErrConflictFound},
+		{strategy: catalog.MergeStrategySourceWins, wantSeed: "source"},
+		{strategy: catalog.MergeStrategyDestWins, wantSeed: "dest"},
+		{strategy: "no-such-strategy", wantErr: catalog.ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			c := testCataloger(t)
+			repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+			testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file0", nil, "")
+			_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+			testutil.MustDo(t, "commit to master", err)
+			testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+			// change the same path on both branches, and another path on the source
+			testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file0", nil, "source")
+			testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file1", nil, "")
+			_, err = c.Commit(ctx, repository, "branch1", "commit to branch1", "tester", nil, catalog.CommitParams{})
+			testutil.MustDo(t, "commit to branch1", err)
+			testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file0", nil, "dest")
+			_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil, catalog.CommitParams{})
+			testutil.MustDo(t, "second commit to master", err)
+
+			_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{Strategy: tt.strategy})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Merge err = %v, expected %s", err, tt.wantErr)
+				}
+				return
+			}
+			testutil.MustDo(t, "merge branch1 to master", err)
+			testVerifyEntries(t, ctx, c, repository, "master", []testEntryInfo{
+				{Path: "/file0", Seed: tt.wantSeed},
+				{Path: "/file1"},
+			})
+		})
+	}
+}
+
+func TestCataloger_Merge_Resolve(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file0", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/file1", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	for _, path := range []string{"/file0", "/file1"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "branch1", path, nil, "source")
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", path, nil, "dest")
+	}
+	_, err = c.Commit(ctx, repository, "branch1", "commit to branch1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	_, err = c.Commit(ctx, repository, "master", "second commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "second commit to master", err)
+
+	// merge the content of /file0 only, and keep the destination of what is left
+	var resolved []string
+	resolve := func(source, destination *catalog.Entry) (*catalog.Entry, error) {
+		resolved = append(resolved, source.Path)
+		if source.PhysicalAddress == "" || destination.PhysicalAddress == "" {
+			t.Errorf("resolve %s without physical addresses", source.Path)
+		}
+		if source.Path != "/file0" {
+			return nil, nil
+		}
+		return &catalog.Entry{
+			PhysicalAddress: "merged-address",
+			Checksum:        "merged",
+			Size:            source.Size + destination.Size,
+		}, nil
+	}
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{
+		Strategy: catalog.MergeStrategyDestWins,
+		Resolve:  resolve,
+	})
+	testutil.MustDo(t, "merge branch1 to master", err)
+	if diff := deep.Equal(resolved, []string{"/file0", "/file1"}); diff != nil {
+		t.Error("resolved paths", diff)
+	}
+	merged, err := c.GetEntry(ctx, repository, "master", "/file0", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get merged entry", err)
+	if merged.Checksum != "merged" || merged.PhysicalAddress != "merged-address" {
+		t.Errorf("merged entry %+v, expected the resolved object", merged)
+	}
+	testVerifyEntries(t, ctx, c, repository, "master", []testEntryInfo{
+		{Path: "/file1", Seed: "dest"},
+	})
+}
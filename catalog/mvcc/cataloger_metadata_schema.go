@@ -0,0 +1,83 @@
+package mvcc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) GetMetadataSchema(ctx context.Context, repository string) (*catalog.MetadataSchema, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		return getMetadataSchema(tx, repoID)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.MetadataSchema), nil
+}
+
+func (c *cataloger) SetMetadataSchema(ctx context.Context, repository string, schema *catalog.MetadataSchema) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	if schema == nil {
+		return fmt.Errorf("metadata schema: %w", catalog.ErrInvalidValue)
+	}
+	if err := schema.Validate(); err != nil {
+		return fmt.Errorf("metadata schema: %w", err)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("marshal metadata schema: %w", err)
+	}
+	_, err = c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`INSERT INTO catalog_repositories_metadata_schema (repository_id, schema)
+			VALUES ($1, $2)
+			ON CONFLICT (repository_id)
+			DO UPDATE SET schema = EXCLUDED.schema`,
+			repoID, data)
+		if err != nil {
+			return nil, fmt.Errorf("set metadata schema: %w", err)
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func getMetadataSchema(tx db.Tx, repositoryID int) (*catalog.MetadataSchema, error) {
+	var data []byte
+	err := tx.GetPrimitive(&data, `SELECT schema FROM catalog_repositories_metadata_schema WHERE repository_id=$1`, repositoryID)
+	if errors.Is(err, db.ErrNotFound) {
+		return &catalog.MetadataSchema{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get metadata schema: %w", err)
+	}
+	var schema catalog.MetadataSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("unmarshal metadata schema: %w", err)
+	}
+	if err := schema.Compile(); err != nil {
+		return nil, fmt.Errorf("compile metadata schema: %w", err)
+	}
+	return &schema, nil
+}
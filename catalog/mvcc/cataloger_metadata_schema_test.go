@@ -0,0 +1,68 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+)
+
+func TestCataloger_MetadataSchema(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	schema, err := c.GetMetadataSchema(ctx, repository)
+	if err != nil {
+		t.Fatal("GetMetadataSchema() on new repository", err)
+	}
+	if diff := deep.Equal(schema, &catalog.MetadataSchema{}); diff != nil {
+		t.Fatal("GetMetadataSchema() expected empty schema", diff)
+	}
+
+	err = c.SetMetadataSchema(ctx, repository, &catalog.MetadataSchema{
+		Rules: []catalog.MetadataRule{{Values: map[string]string{"k": "(v"}}},
+	})
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Fatalf("SetMetadataSchema() invalid expression err=%s, expected=%s", err, catalog.ErrInvalidValue)
+	}
+
+	expected := &catalog.MetadataSchema{
+		Rules: []catalog.MetadataRule{{Prefix: "tables/", Required: []string{"owner"}}},
+	}
+	if err := c.SetMetadataSchema(ctx, repository, expected); err != nil {
+		t.Fatal("SetMetadataSchema()", err)
+	}
+	schema, err = c.GetMetadataSchema(ctx, repository)
+	if err != nil {
+		t.Fatal("GetMetadataSchema()", err)
+	}
+	if diff := deep.Equal(schema, expected); diff != nil {
+		t.Fatal("GetMetadataSchema() unexpected schema", diff)
+	}
+
+	err = c.CreateEntry(ctx, repository, "master", catalog.Entry{Path: "tables/a", PhysicalAddress: "a", Checksum: "a"}, catalog.CreateEntryParams{})
+	if !errors.Is(err, catalog.ErrInvalidMetadata) {
+		t.Fatalf("CreateEntry() without required key err=%s, expected=%s", err, catalog.ErrInvalidMetadata)
+	}
+	err = c.CreateEntry(ctx, repository, "master", catalog.Entry{Path: "tables/a", PhysicalAddress: "a", Checksum: "a", Metadata: catalog.Metadata{"owner": "data"}}, catalog.CreateEntryParams{})
+	if err != nil {
+		t.Fatal("CreateEntry() with required key", err)
+	}
+	err = c.CreateEntry(ctx, repository, "master", catalog.Entry{Path: "other/a", PhysicalAddress: "b", Checksum: "b"}, catalog.CreateEntryParams{})
+	if err != nil {
+		t.Fatal("CreateEntry() outside schema prefixes", err)
+	}
+
+	err = c.CreateMultipartUpload(ctx, repository, "upload1", "tables/b", "b", time.Now(), catalog.CreateMultipartUploadParams{})
+	if !errors.Is(err, catalog.ErrInvalidMetadata) {
+		t.Fatalf("CreateMultipartUpload() without required key err=%s, expected=%s", err, catalog.ErrInvalidMetadata)
+	}
+	err = c.CreateMultipartUpload(ctx, repository, "upload2", "tables/b", "b", time.Now(), catalog.CreateMultipartUploadParams{Metadata: catalog.Metadata{"owner": "data"}})
+	if err != nil {
+		t.Fatal("CreateMultipartUpload() with required key", err)
+	}
+}
@@ -0,0 +1,119 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// MoveEntry moves the entry at sourcePath on branch to destinationPath, replacing any entry
+// found there.  The move is a single uncommitted change: an entry is written at the
+// destination and the source is deleted, in the same transaction.
+func (c *cataloger) MoveEntry(ctx context.Context, repository, branch string, sourcePath, destinationPath string) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "sourcePath", IsValid: ValidatePath(sourcePath)},
+		{Name: "destinationPath", IsValid: ValidatePath(destinationPath)},
+	}); err != nil {
+		return err
+	}
+	if sourcePath == destinationPath {
+		return fmt.Errorf("move entry onto itself: %w", catalog.ErrInvalidValue)
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		return c.moveEntries(tx, repository, branch, sq.Eq{"path": sourcePath}, sourcePath, destinationPath)
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+// MoveEntries moves all entries under sourcePrefix on branch to the same relative paths under
+// destinationPrefix, in a single transaction.  Returns the number of entries moved.
+func (c *cataloger) MoveEntries(ctx context.Context, repository, branch string, sourcePrefix, destinationPrefix string) (int, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "sourcePrefix", IsValid: ValidatePath(sourcePrefix)},
+		{Name: "destinationPrefix", IsValid: ValidatePath(destinationPrefix)},
+	}); err != nil {
+		return 0, err
+	}
+	// moved entries must not land under the source prefix, or be overwritten by later moves
+	if strings.HasPrefix(destinationPrefix, sourcePrefix) || strings.HasPrefix(sourcePrefix, destinationPrefix) {
+		return 0, fmt.Errorf("overlapping source and destination prefixes: %w", catalog.ErrInvalidValue)
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		return c.moveEntries(tx, repository, branch, sq.Like{"path": db.Prefix(sourcePrefix)}, sourcePrefix, destinationPrefix)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return 0, err
+	}
+	return res.(int), nil
+}
+
+// moveEntries moves the entries of branch matching pathCond, replacing the sourcePrefix of
+// their path with destinationPrefix.  Returns catalog.ErrEntryNotFound if no entry matched.
+func (c *cataloger) moveEntries(tx db.Tx, repository, branch string, pathCond sq.Sqlizer, sourcePrefix, destinationPrefix string) (int, error) {
+	branchID, err := c.getBranchIDCache(tx, repository, branch)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkNoCommitJob(tx, branchID); err != nil {
+		return 0, err
+	}
+	repoID, err := c.getRepositoryIDCache(tx, repository)
+	if err != nil {
+		return 0, err
+	}
+	lineage, err := getLineage(tx, branchID, UncommittedID)
+	if err != nil {
+		return 0, fmt.Errorf("get lineage: %w", err)
+	}
+	sql, args, err := psql.
+		Select("path", "physical_address", "creation_date", "size", "checksum", "metadata", "is_expired",
+			"content_type", "content_encoding", "cache_control").
+		FromSelect(sqEntriesLineage(branchID, UncommittedID, lineage), "entries").
+		Where(pathCond).
+		Where(sq.Eq{"is_deleted": false}).
+		OrderBy("path").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build sql: %w", err)
+	}
+	var entries []*catalog.Entry
+	if err := tx.Select(&entries, sql, args...); err != nil {
+		return 0, fmt.Errorf("select entries: %w", err)
+	}
+	if len(entries) == 0 {
+		return 0, catalog.ErrEntryNotFound
+	}
+
+	schema, err := getMetadataSchema(tx, repoID)
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		sourcePath := entry.Path
+		entry.Path = destinationPrefix + strings.TrimPrefix(sourcePath, sourcePrefix)
+		if err := schema.Check(entry.Path, entry.Metadata); err != nil {
+			return 0, err
+		}
+		if _, err := insertEntry(tx, branchID, entry); err != nil {
+			return 0, err
+		}
+		if err := deleteEntry(tx, branchID, sourcePath); err != nil {
+			return 0, fmt.Errorf("delete %s: %w", sourcePath, err)
+		}
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,73 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_MoveEntry(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "committed", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit file", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "uncommitted", nil, "")
+
+	for _, path := range []string{"committed", "uncommitted"} {
+		t.Run(path, func(t *testing.T) {
+			source, err := c.GetEntry(ctx, repository, "master", path, catalog.GetEntryParams{})
+			testutil.MustDo(t, "get source", err)
+			testutil.MustDo(t, "move", c.MoveEntry(ctx, repository, "master", path, "moved/"+path))
+			testCatalogerGetEntry(t, ctx, c, repository, "master", path, false)
+			moved, err := c.GetEntry(ctx, repository, "master", "moved/"+path, catalog.GetEntryParams{})
+			testutil.MustDo(t, "get moved", err)
+			if moved.Checksum != source.Checksum || moved.PhysicalAddress != source.PhysicalAddress {
+				t.Errorf("moved entry %+v, expected object of %+v", moved, source)
+			}
+		})
+	}
+	// the committed entry is still found on the commit
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "committed", true)
+
+	if err := c.MoveEntry(ctx, repository, "master", "no-such-path", "moved/no-such-path"); !errors.Is(err, catalog.ErrEntryNotFound) {
+		t.Errorf("move missing entry err=%v, expected %s", err, catalog.ErrEntryNotFound)
+	}
+	if err := c.MoveEntry(ctx, repository, "master", "moved/committed", "moved/committed"); !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("move entry onto itself err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+}
+
+func TestCataloger_MoveEntries(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "src/file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "src/dir/file2", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "srcfile", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "src/file3", nil, "")
+
+	count, err := c.MoveEntries(ctx, repository, "master", "src/", "dst/")
+	testutil.MustDo(t, "move prefix", err)
+	if count != 3 {
+		t.Errorf("moved %d entries, expected 3", count)
+	}
+	for _, path := range []string{"file1", "dir/file2", "file3"} {
+		testCatalogerGetEntry(t, ctx, c, repository, "master", "src/"+path, false)
+		testCatalogerGetEntry(t, ctx, c, repository, "master", "dst/"+path, true)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "srcfile", true)
+
+	if _, err := c.MoveEntries(ctx, repository, "master", "src/", "other/"); !errors.Is(err, catalog.ErrEntryNotFound) {
+		t.Errorf("move empty prefix err=%v, expected %s", err, catalog.ErrEntryNotFound)
+	}
+	if _, err := c.MoveEntries(ctx, repository, "master", "dst/", "dst/sub/"); !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("move into source prefix err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+}
@@ -0,0 +1,165 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) GetOrganizationQuota(ctx context.Context, organization string) (*catalog.OrganizationQuota, error) {
+	if err := Validate(ValidateFields{
+		{Name: "organization", IsValid: ValidateOrganizationName(organization)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var quota catalog.OrganizationQuota
+		err := tx.Get(&quota, `SELECT max_storage_bytes, max_objects, max_requests_per_second, max_export_bytes_per_second
+			FROM catalog_organizations_quota WHERE name=$1`, organization)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, catalog.ErrOrganizationNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get organization quota: %w", err)
+		}
+		return &quota, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.OrganizationQuota), nil
+}
+
+// SetOrganizationQuota creates or updates the quota of organization.  Usage bounds are not kept
+// without limits, so the next write rescans the usage of the organization.
+func (c *cataloger) SetOrganizationQuota(ctx context.Context, organization string, quota *catalog.OrganizationQuota) error {
+	if err := Validate(ValidateFields{
+		{Name: "organization", IsValid: ValidateOrganizationName(organization)},
+	}); err != nil {
+		return err
+	}
+	if quota == nil || quota.MaxStorageBytes < 0 || quota.MaxObjects < 0 ||
+		quota.MaxRequestsPerSecond < 0 || quota.MaxExportBytesPerSecond < 0 {
+		return fmt.Errorf("quota: %w", catalog.ErrInvalidValue)
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		_, err := tx.Exec(`INSERT INTO catalog_organizations_quota
+				(name, max_storage_bytes, max_objects, max_requests_per_second, max_export_bytes_per_second)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (name)
+			DO UPDATE SET (max_storage_bytes, max_objects, max_requests_per_second, max_export_bytes_per_second,
+					used_storage_bytes, used_objects) =
+				(EXCLUDED.max_storage_bytes, EXCLUDED.max_objects, EXCLUDED.max_requests_per_second, EXCLUDED.max_export_bytes_per_second,
+					NULL, NULL)`,
+			organization, quota.MaxStorageBytes, quota.MaxObjects, quota.MaxRequestsPerSecond, quota.MaxExportBytesPerSecond)
+		if err != nil {
+			return nil, fmt.Errorf("set organization quota: %w", err)
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func (c *cataloger) DeleteOrganizationQuota(ctx context.Context, organization string) error {
+	if err := Validate(ValidateFields{
+		{Name: "organization", IsValid: ValidateOrganizationName(organization)},
+	}); err != nil {
+		return err
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var organizationID int
+		err := tx.GetPrimitive(&organizationID, `SELECT id FROM catalog_organizations_quota WHERE name=$1`, organization)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, catalog.ErrOrganizationNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get organization: %w", err)
+		}
+		// release the repositories first, quota rows of repositories are locked before those
+		// of their organizations
+		_, err = tx.Exec(`UPDATE catalog_repositories_quota SET organization_id = NULL WHERE organization_id=$1`, organizationID)
+		if err != nil {
+			return nil, fmt.Errorf("release repositories: %w", err)
+		}
+		_, err = tx.Exec(`DELETE FROM catalog_organizations_quota WHERE id=$1`, organizationID)
+		if err != nil {
+			return nil, fmt.Errorf("delete organization quota: %w", err)
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func (c *cataloger) GetOrganizationUsage(ctx context.Context, organization string) (*catalog.RepositoryUsage, error) {
+	if err := Validate(ValidateFields{
+		{Name: "organization", IsValid: ValidateOrganizationName(organization)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var organizationID int
+		err := tx.GetPrimitive(&organizationID, `SELECT id FROM catalog_organizations_quota WHERE name=$1`, organization)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, catalog.ErrOrganizationNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get organization: %w", err)
+		}
+		return getOrganizationUsage(tx, organizationID)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.RepositoryUsage), nil
+}
+
+// getOrganizationUsage returns the usage of the repositories of organizationID.  Physical
+// addresses are relative to the storage namespace of their repository, so objects are counted
+// once per repository and physical address.
+func getOrganizationUsage(tx db.Tx, organizationID int) (*catalog.RepositoryUsage, error) {
+	var usage catalog.RepositoryUsage
+	err := tx.Get(&usage, `SELECT COUNT(*) AS objects, COALESCE(SUM(size),0) AS storage_bytes
+		FROM (SELECT DISTINCT ON (b.repository_id, e.physical_address) e.physical_address, e.size
+			FROM catalog_entries e JOIN catalog_branches b ON e.branch_id = b.id
+				JOIN catalog_repositories_quota q ON q.repository_id = b.repository_id
+			WHERE q.organization_id = $1 AND NOT e.is_expired) o`, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("get organization usage: %w", err)
+	}
+	return &usage, nil
+}
+
+// checkOrganizationQuota verifies that adding objects with a total of size bytes to a
+// repository of organizationID keeps the organization within its quota, adding them to its
+// usage bound if reserve is set.  Writers of all repositories of an organization with a quota
+// are serialized on its quota row.
+func checkOrganizationQuota(tx db.Tx, organizationID int, objects int64, size int64, reserve bool) error {
+	var bound quotaBound
+	err := tx.Get(&bound, `SELECT max_storage_bytes, max_objects, used_storage_bytes, used_objects
+		FROM catalog_organizations_quota WHERE id=$1
+		FOR UPDATE`, organizationID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get organization quota: %w", err)
+	}
+	usage, err := bound.check(objects, size, reserve, func() (*catalog.RepositoryUsage, error) {
+		return getOrganizationUsage(tx, organizationID)
+	})
+	if err != nil {
+		return fmt.Errorf("organization: %w", err)
+	}
+	if usage == nil {
+		return nil
+	}
+	_, err = tx.Exec(`UPDATE catalog_organizations_quota SET (used_storage_bytes, used_objects) = ($2, $3)
+		WHERE id=$1`, organizationID, usage.StorageBytes, usage.Objects)
+	if err != nil {
+		return fmt.Errorf("update organization usage: %w", err)
+	}
+	return nil
+}
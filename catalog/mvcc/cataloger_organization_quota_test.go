@@ -0,0 +1,93 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+)
+
+func TestCataloger_OrganizationQuota(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	organization := "org-" + testCatalogerUniqueID()
+	repository1 := testCatalogerRepo(t, ctx, c, "repository", "master")
+	repository2 := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	_, err := c.GetOrganizationQuota(ctx, organization)
+	if !errors.Is(err, catalog.ErrOrganizationNotFound) {
+		t.Fatalf("GetOrganizationQuota() unknown organization err=%s, expected=%s", err, catalog.ErrOrganizationNotFound)
+	}
+	err = c.SetRepositoryQuota(ctx, repository1, &catalog.RepositoryQuota{Organization: organization})
+	if !errors.Is(err, catalog.ErrOrganizationNotFound) {
+		t.Fatalf("SetRepositoryQuota() unknown organization err=%s, expected=%s", err, catalog.ErrOrganizationNotFound)
+	}
+	err = c.SetOrganizationQuota(ctx, organization, &catalog.OrganizationQuota{MaxObjects: -1})
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Fatalf("SetOrganizationQuota() negative value err=%s, expected=%s", err, catalog.ErrInvalidValue)
+	}
+
+	expected := &catalog.OrganizationQuota{MaxObjects: 3, MaxRequestsPerSecond: 10}
+	if err := c.SetOrganizationQuota(ctx, organization, expected); err != nil {
+		t.Fatal("SetOrganizationQuota()", err)
+	}
+	quota, err := c.GetOrganizationQuota(ctx, organization)
+	if err != nil {
+		t.Fatal("GetOrganizationQuota()", err)
+	}
+	if diff := deep.Equal(quota, expected); diff != nil {
+		t.Fatal("GetOrganizationQuota() unexpected quota", diff)
+	}
+
+	// the first repository stores objects before joining the organization
+	testCatalogerCreateEntry(t, ctx, c, repository1, "master", "a", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository1, "master", "b", nil, "")
+	for _, repository := range []string{repository1, repository2} {
+		if err := c.SetRepositoryQuota(ctx, repository, &catalog.RepositoryQuota{Organization: organization}); err != nil {
+			t.Fatalf("SetRepositoryQuota() %s: %s", repository, err)
+		}
+	}
+	repoQuota, err := c.GetRepositoryQuota(ctx, repository2)
+	if err != nil {
+		t.Fatal("GetRepositoryQuota()", err)
+	}
+	if repoQuota.Organization != organization {
+		t.Fatalf("GetRepositoryQuota() organization %s, expected %s", repoQuota.Organization, organization)
+	}
+
+	testCatalogerCreateEntry(t, ctx, c, repository2, "master", "c", nil, "")
+	err = c.CreateEntry(ctx, repository2, "master", catalog.Entry{Path: "d", PhysicalAddress: "addr4", Checksum: "addr4", Size: 5}, catalog.CreateEntryParams{})
+	if !errors.Is(err, catalog.ErrQuotaExceeded) {
+		t.Fatalf("CreateEntry() over organization quota err=%s, expected=%s", err, catalog.ErrQuotaExceeded)
+	}
+	err = c.CheckRepositoryQuota(ctx, repository1, 1, 0)
+	if !errors.Is(err, catalog.ErrQuotaExceeded) {
+		t.Fatalf("CheckRepositoryQuota() over organization quota err=%s, expected=%s", err, catalog.ErrQuotaExceeded)
+	}
+	usage, err := c.GetOrganizationUsage(ctx, organization)
+	if err != nil {
+		t.Fatal("GetOrganizationUsage()", err)
+	}
+	if usage.Objects != 3 {
+		t.Fatalf("GetOrganizationUsage() %d objects, expected 3", usage.Objects)
+	}
+
+	// deleting the organization releases its repositories
+	if err := c.DeleteOrganizationQuota(ctx, organization); err != nil {
+		t.Fatal("DeleteOrganizationQuota()", err)
+	}
+	repoQuota, err = c.GetRepositoryQuota(ctx, repository2)
+	if err != nil {
+		t.Fatal("GetRepositoryQuota()", err)
+	}
+	if repoQuota.Organization != "" {
+		t.Fatalf("GetRepositoryQuota() organization %s after delete, expected none", repoQuota.Organization)
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository2, "master", "d", nil, "")
+	_, err = c.GetOrganizationUsage(ctx, organization)
+	if !errors.Is(err, catalog.ErrOrganizationNotFound) {
+		t.Fatalf("GetOrganizationUsage() deleted organization err=%s, expected=%s", err, catalog.ErrOrganizationNotFound)
+	}
+}
@@ -0,0 +1,171 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/cache"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimitsCacheExpiry bounds the delay until a server applies changed rate quotas
+	rateLimitsCacheExpiry = 10 * time.Second
+	rateLimitsCacheJitter = time.Second
+)
+
+// rateLimits are the rate quotas applying to a repository, its own and those of its organization
+type rateLimits struct {
+	MaxRequestsPerSecond                int64  `db:"max_requests_per_second"`
+	MaxExportBytesPerSecond             int64  `db:"max_export_bytes_per_second"`
+	Organization                        string `db:"organization"`
+	OrganizationMaxRequestsPerSecond    int64  `db:"organization_max_requests_per_second"`
+	OrganizationMaxExportBytesPerSecond int64  `db:"organization_max_export_bytes_per_second"`
+}
+
+// rateLimiters limits request rates and export bandwidth with token buckets refilled at the
+// rate of their quota, holding up to a second of it.  Buckets are kept in memory, so each
+// server enforces the rate quotas on the requests it serves and the copies it performs.
+type rateLimiters struct {
+	limits  *cache.GetSetCache
+	mu      sync.Mutex
+	buckets map[string]*rate.Limiter
+}
+
+func newRateLimiters() *rateLimiters {
+	return &rateLimiters{
+		limits:  cache.NewCache(defaultCatalogerCacheSize, rateLimitsCacheExpiry, cache.NewJitterFn(rateLimitsCacheJitter)),
+		buckets: make(map[string]*rate.Limiter),
+	}
+}
+
+// invalidate drops the cached rate quotas of repository, after they were set
+func (r *rateLimiters) invalidate(repository string) {
+	r.limits.Remove(repository)
+}
+
+// bucket returns the bucket named key refilled at limit tokens per second, nil for no limit
+func (r *rateLimiters) bucket(key string, limit int64) *rate.Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limit <= 0 {
+		delete(r.buckets, key)
+		return nil
+	}
+	b, ok := r.buckets[key]
+	if !ok {
+		b = rate.NewLimiter(rate.Limit(limit), int(limit))
+		r.buckets[key] = b
+	} else if b.Limit() != rate.Limit(limit) {
+		b.SetLimit(rate.Limit(limit))
+		b.SetBurst(int(limit))
+	}
+	return b
+}
+
+// bucketsOf returns the buckets of repository and of its organization for kind, with the
+// limits set for each
+func (r *rateLimiters) bucketsOf(kind, repository string, limit int64, organization string, organizationLimit int64) []*rate.Limiter {
+	var buckets []*rate.Limiter
+	if b := r.bucket(kind+":repository:"+repository, limit); b != nil {
+		buckets = append(buckets, b)
+	}
+	if organization != "" {
+		if b := r.bucket(kind+":organization:"+organization, organizationLimit); b != nil {
+			buckets = append(buckets, b)
+		}
+	}
+	return buckets
+}
+
+func (c *cataloger) getRateLimits(ctx context.Context, repository string) (*rateLimits, error) {
+	v, err := c.rateLimiters.limits.GetOrSet(repository, func() (interface{}, error) {
+		return c.db.Transact(func(tx db.Tx) (interface{}, error) {
+			var limits rateLimits
+			err := tx.Get(&limits, `SELECT q.max_requests_per_second, q.max_export_bytes_per_second,
+					COALESCE(o.name, '') AS organization,
+					COALESCE(o.max_requests_per_second, 0) AS organization_max_requests_per_second,
+					COALESCE(o.max_export_bytes_per_second, 0) AS organization_max_export_bytes_per_second
+				FROM catalog_repositories r
+					JOIN catalog_repositories_quota q ON q.repository_id = r.id
+					LEFT JOIN catalog_organizations_quota o ON o.id = q.organization_id
+				WHERE r.name = $1`, repository)
+			if errors.Is(err, db.ErrNotFound) {
+				return &limits, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("get rate limits: %w", err)
+			}
+			return &limits, nil
+		}, c.txOpts(ctx, db.ReadOnly())...)
+	})
+	if errors.Is(err, cache.ErrCacheItemNotFound) {
+		// a concurrent read of the limits failed, don't limit
+		return &rateLimits{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.(*rateLimits), nil
+}
+
+func (c *cataloger) CheckRequestRate(ctx context.Context, repository string) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	limits, err := c.getRateLimits(ctx, repository)
+	if err != nil {
+		return err
+	}
+	buckets := c.rateLimiters.bucketsOf("requests", repository, limits.MaxRequestsPerSecond,
+		limits.Organization, limits.OrganizationMaxRequestsPerSecond)
+	now := time.Now()
+	for _, b := range buckets {
+		if !b.AllowN(now, 1) {
+			return catalog.ErrRequestRateExceeded
+		}
+	}
+	return nil
+}
+
+func (c *cataloger) WaitExportBandwidth(ctx context.Context, repository string, size int64) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	limits, err := c.getRateLimits(ctx, repository)
+	if err != nil {
+		return err
+	}
+	buckets := c.rateLimiters.bucketsOf("export", repository, limits.MaxExportBytesPerSecond,
+		limits.Organization, limits.OrganizationMaxExportBytesPerSecond)
+	for _, b := range buckets {
+		if err := waitBytes(ctx, b, size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitBytes waits until b holds size tokens, taking them a burst at a time
+func waitBytes(ctx context.Context, b *rate.Limiter, size int64) error {
+	for size > 0 {
+		n := int64(b.Burst())
+		if n > size {
+			n = size
+		}
+		if err := b.WaitN(ctx, int(n)); err != nil {
+			return err
+		}
+		size -= n
+	}
+	return nil
+}
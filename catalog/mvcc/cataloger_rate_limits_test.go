@@ -0,0 +1,102 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+)
+
+func TestRateLimiters_Bucket(t *testing.T) {
+	r := newRateLimiters()
+	if b := r.bucket("requests:repository:repo", 0); b != nil {
+		t.Fatal("bucket() without limit returned a bucket")
+	}
+	b := r.bucket("requests:repository:repo", 2)
+	if b == nil || b.Burst() != 2 {
+		t.Fatalf("bucket() expected a bucket holding 2 tokens, got %v", b)
+	}
+	if again := r.bucket("requests:repository:repo", 2); again != b {
+		t.Fatal("bucket() with the same limit returned another bucket")
+	}
+	if changed := r.bucket("requests:repository:repo", 5); changed != b || b.Burst() != 5 {
+		t.Fatal("bucket() with a changed limit expected to update the bucket")
+	}
+	r.bucket("requests:repository:repo", 0)
+	if _, ok := r.buckets["requests:repository:repo"]; ok {
+		t.Fatal("bucket() without limit expected to remove the bucket")
+	}
+
+	buckets := r.bucketsOf("export", "repo", 10, "org", 20)
+	if len(buckets) != 2 {
+		t.Fatalf("bucketsOf() returned %d buckets, expected 2", len(buckets))
+	}
+	if buckets := r.bucketsOf("export", "repo", 0, "", 20); len(buckets) != 0 {
+		t.Fatalf("bucketsOf() without limits returned %d buckets, expected none", len(buckets))
+	}
+}
+
+func TestCataloger_CheckRequestRate(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	organization := "org-" + testCatalogerUniqueID()
+	repository1 := testCatalogerRepo(t, ctx, c, "repository", "master")
+	repository2 := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	for i := 0; i < 10; i++ {
+		if err := c.CheckRequestRate(ctx, repository1); err != nil {
+			t.Fatal("CheckRequestRate() without quota", err)
+		}
+	}
+
+	if err := c.SetOrganizationQuota(ctx, organization, &catalog.OrganizationQuota{MaxRequestsPerSecond: 3}); err != nil {
+		t.Fatal("SetOrganizationQuota()", err)
+	}
+	if err := c.SetRepositoryQuota(ctx, repository1, &catalog.RepositoryQuota{MaxRequestsPerSecond: 2, Organization: organization}); err != nil {
+		t.Fatal("SetRepositoryQuota()", err)
+	}
+	if err := c.SetRepositoryQuota(ctx, repository2, &catalog.RepositoryQuota{Organization: organization}); err != nil {
+		t.Fatal("SetRepositoryQuota()", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.CheckRequestRate(ctx, repository1); err != nil {
+			t.Fatalf("CheckRequestRate() request %d within rate: %s", i, err)
+		}
+	}
+	err := c.CheckRequestRate(ctx, repository1)
+	if !errors.Is(err, catalog.ErrRequestRateExceeded) {
+		t.Fatalf("CheckRequestRate() over repository rate err=%s, expected=%s", err, catalog.ErrRequestRateExceeded)
+	}
+	// the rejected request took no token of the organization
+	if err := c.CheckRequestRate(ctx, repository2); err != nil {
+		t.Fatal("CheckRequestRate() within organization rate", err)
+	}
+	err = c.CheckRequestRate(ctx, repository2)
+	if !errors.Is(err, catalog.ErrRequestRateExceeded) {
+		t.Fatalf("CheckRequestRate() over organization rate err=%s, expected=%s", err, catalog.ErrRequestRateExceeded)
+	}
+}
+
+func TestCataloger_WaitExportBandwidth(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	if err := c.WaitExportBandwidth(ctx, repository, 1<<30); err != nil {
+		t.Fatal("WaitExportBandwidth() without quota", err)
+	}
+
+	if err := c.SetRepositoryQuota(ctx, repository, &catalog.RepositoryQuota{MaxExportBytesPerSecond: 100}); err != nil {
+		t.Fatal("SetRepositoryQuota()", err)
+	}
+	if err := c.WaitExportBandwidth(ctx, repository, 100); err != nil {
+		t.Fatal("WaitExportBandwidth() a second of bandwidth", err)
+	}
+	// copying 10 more seconds of bandwidth waits past the deadline
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if err := c.WaitExportBandwidth(waitCtx, repository, 1000); err == nil {
+		t.Fatal("WaitExportBandwidth() over bandwidth expected to wait past the deadline")
+	}
+}
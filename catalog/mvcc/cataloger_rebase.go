@@ -0,0 +1,172 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// Rebase recreates branch from ontoReference, a commit of its parent branch, and replays on
+// it the commits branch made itself as new commits.  Merges from the parent are not replayed,
+// and replayed commits that change nothing are dropped.  A replayed commit conflicts when a
+// path it changes differs from what the commit found; on conflicts nothing is rebased and
+// ErrConflictFound is returned.  The replaced commits are removed, so branch must have no
+// uncommitted changes, no other ref may point to its commits and branch may not be exporting;
+// their data lineage moves to the new commits, and an export state at one of them is cleared.
+func (c *cataloger) Rebase(ctx context.Context, repository, branch, ontoReference string) (*catalog.MergeResult, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "ontoReference", IsValid: ValidateReference(ontoReference)},
+	}); err != nil {
+		return nil, err
+	}
+
+	mergeResult := &catalog.MergeResult{
+		Summary: make(map[catalog.DifferenceType]int),
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("branch: %w", err)
+		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+		parentID, err := checkBranchRebaseable(tx, branchID)
+		if err != nil {
+			return nil, err
+		}
+		ontoBranchID, ontoCommitID, err := c.resolveCommit(tx, repository, ontoReference)
+		if err != nil {
+			return nil, fmt.Errorf("onto reference: %w", err)
+		}
+		if ontoBranchID != parentID {
+			return nil, fmt.Errorf("%w: rebase onto a commit of the parent branch", catalog.ErrUnsupportedRelation)
+		}
+
+		// collect the changes of the commits to replay before removing them
+		var commits []struct {
+			CommitID  CommitID         `db:"commit_id"`
+			Committer string           `db:"committer"`
+			Message   string           `db:"message"`
+			Metadata  catalog.Metadata `db:"metadata"`
+			MergeType RelationType     `db:"merge_type"`
+		}
+		if err := tx.Select(&commits, `SELECT commit_id, committer, message, metadata, merge_type
+			FROM catalog_commits WHERE branch_id = $1 ORDER BY commit_id`, branchID); err != nil {
+			return nil, fmt.Errorf("branch commits: %w", err)
+		}
+		if len(commits) == 0 {
+			return nil, catalog.ErrCommitNotFound
+		}
+		replays := make(map[int][]*commitChange)
+		for i := 1; i < len(commits); i++ {
+			if commits[i].MergeType == RelationTypeFromParent {
+				continue
+			}
+			changes, err := commitChanges(tx, repository, branchID, commits[i-1].CommitID, commits[i].CommitID)
+			if err != nil {
+				return nil, fmt.Errorf("changes of %s: %w", MakeReference(branch, commits[i].CommitID), err)
+			}
+			replays[i] = changes
+		}
+		if err := releaseExportedCommits(tx, branchID, branch, 0, commits[len(commits)-1].CommitID+1); err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM catalog_entries WHERE branch_id = $1`, branchID); err != nil {
+			return nil, fmt.Errorf("delete entries: %w", err)
+		}
+		creation := commits[0]
+		var baseCommitID CommitID
+		err = tx.GetPrimitive(&baseCommitID, `INSERT INTO catalog_commits (branch_id,commit_id,previous_commit_id,committer,message,
+			creation_date,merge_source_branch,merge_type,lineage_commits,merge_source_commit)
+			VALUES ($1,nextval('catalog_commit_id_seq'),0,$2,$3,transaction_timestamp(),$4,'from_parent',
+				$5::bigint || (SELECT lineage_commits FROM catalog_commits
+					WHERE branch_id=$4 AND merge_type='from_parent' AND commit_id <= $5 ORDER BY commit_id DESC LIMIT 1),
+				$5)
+			RETURNING commit_id`,
+			branchID, creation.Committer, creation.Message, parentID, ontoCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("insert base commit: %w", err)
+		}
+
+		// the data lineage of each replaced commit moves to the commit holding its entries: its
+		// replay, or the commit before it when it was not replayed or replayed no changes
+		previousCommitID := baseCommitID
+		for i, commit := range commits {
+			oldReference := MakeReference(branch, commit.CommitID)
+			changes, err := checkChangesOnBranch(tx, branchID, replays[i], mergeResult)
+			if err != nil {
+				return nil, fmt.Errorf("replay %s: %w", oldReference, err)
+			}
+			if len(changes) > 0 {
+				nextCommitID, err := getNextCommitID(tx)
+				if err != nil {
+					return nil, fmt.Errorf("next commit id: %w", err)
+				}
+				for j := 0; j < len(changes); j += MergeBatchSize {
+					end := j + MergeBatchSize
+					if end > len(changes) {
+						end = len(changes)
+					}
+					if err := applyChangesToBranch(tx, branchID, previousCommitID, nextCommitID, changes[j:end]); err != nil {
+						return nil, fmt.Errorf("replay %s: %w", oldReference, err)
+					}
+				}
+				if _, err := tx.Exec(`INSERT INTO catalog_commits (branch_id,commit_id,committer,message,creation_date,metadata,merge_type,previous_commit_id)
+					VALUES ($1,$2,$3,$4,transaction_timestamp(),$5,$6,$7)`,
+					branchID, nextCommitID, commit.Committer, commit.Message, commit.Metadata, RelationTypeNone, previousCommitID); err != nil {
+					return nil, fmt.Errorf("insert commit: %w", err)
+				}
+				previousCommitID = nextCommitID
+			}
+			if _, err := tx.Exec(`INSERT INTO catalog_data_lineage (branch_id, commit_id, source_branch_id, source_commit_id)
+				SELECT branch_id, $3, source_branch_id, source_commit_id FROM catalog_data_lineage
+				WHERE branch_id = $1 AND commit_id = $2
+				ON CONFLICT DO NOTHING`,
+				branchID, commit.CommitID, previousCommitID); err != nil {
+				return nil, fmt.Errorf("move data lineage: %w", err)
+			}
+		}
+
+		// deleting the replaced commits deletes their data lineage
+		if _, err := tx.Exec(`DELETE FROM catalog_commits WHERE branch_id = $1 AND commit_id < $2`, branchID, baseCommitID); err != nil {
+			return nil, fmt.Errorf("delete replaced commits: %w", err)
+		}
+		mergeResult.Reference = MakeReference(branch, previousCommitID)
+		return nil, nil
+	}, c.txOpts(ctx, db.ReadCommitted())...)
+	return mergeResult, err
+}
+
+// checkBranchRebaseable returns the parent branch of branchID, or an error if branchID has
+// uncommitted changes or other refs point to its commits
+func checkBranchRebaseable(tx db.Tx, branchID int64) (int64, error) {
+	var branch struct {
+		ParentID    int64 `db:"parent_id"`
+		Uncommitted bool  `db:"uncommitted"`
+	}
+	err := tx.Get(&branch, `SELECT COALESCE(b.lineage[1], 0) AS parent_id,
+			EXISTS (SELECT 1 FROM catalog_entries_v WHERE branch_id = $1 AND NOT is_committed) AS uncommitted
+		FROM catalog_branches b WHERE b.id = $1`, branchID)
+	if err != nil {
+		return 0, fmt.Errorf("get branch: %w", err)
+	}
+	switch {
+	case branch.ParentID == 0:
+		return 0, fmt.Errorf("%w: branch has no parent to rebase onto", catalog.ErrOperationNotPermitted)
+	case branch.Uncommitted:
+		return 0, fmt.Errorf("%w: branch has uncommitted changes", catalog.ErrOperationNotPermitted)
+	}
+	if err := checkCommitsUnreferenced(tx, branchID, 0); err != nil {
+		return 0, err
+	}
+	return branch.ParentID, nil
+}
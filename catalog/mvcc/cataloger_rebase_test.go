@@ -0,0 +1,163 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_Rebase(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "add file2", "tester1", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "first commit to branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "seed1")
+	testutil.MustDo(t, "delete file1", c.DeleteEntry(ctx, repository, "branch1", "file1"))
+	_, err = c.Commit(ctx, repository, "branch1", "change file2", "tester2", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "second commit to branch1", err)
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "")
+	masterLog, err := c.Commit(ctx, repository, "master", "add file3", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "second commit to master", err)
+
+	res, err := c.Rebase(ctx, repository, "branch1", "master")
+	testutil.MustDo(t, "rebase branch1", err)
+	testCatalogerGetEntry(t, ctx, c, repository, res.Reference, "file0", true)
+	testCatalogerGetEntry(t, ctx, c, repository, res.Reference, "file1", false)
+	testCatalogerGetEntry(t, ctx, c, repository, res.Reference, "file3", true)
+	file2, err := c.GetEntry(ctx, repository, res.Reference, "file2", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get rebased file2", err)
+	if expected := testCreateEntryCalcChecksum("file2", t.Name(), "seed1"); file2.Checksum != expected {
+		t.Errorf("rebased file2 checksum %s, expected %s", file2.Checksum, expected)
+	}
+
+	commits, _, err := c.ListCommits(ctx, repository, "branch1", "", -1, catalog.CommitsFilter{})
+	testutil.MustDo(t, "list branch1 commits", err)
+	var messages []string
+	for _, commit := range commits {
+		messages = append(messages, commit.Message)
+	}
+	if len(commits) < 4 || messages[0] != "change file2" || messages[1] != "add file2" || commits[3].Reference != masterLog.Reference {
+		t.Errorf("rebased branch1 log %v, expected replayed commits on the branch creation and %s", messages, masterLog.Reference)
+	}
+	if commits[0].Committer != "tester2" || commits[1].Committer != "tester1" {
+		t.Errorf("replayed committers %s and %s, expected tester2 and tester1", commits[0].Committer, commits[1].Committer)
+	}
+}
+
+func TestCataloger_Rebase_Conflict(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "branch")
+	branchLog, err := c.Commit(ctx, repository, "branch1", "change file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "master")
+	_, err = c.Commit(ctx, repository, "master", "change file1 on master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "second commit to master", err)
+
+	res, err := c.Rebase(ctx, repository, "branch1", "master")
+	if !errors.Is(err, catalog.ErrConflictFound) {
+		t.Fatalf("rebase err=%v, expected %s", err, catalog.ErrConflictFound)
+	}
+	if res.Summary[catalog.DifferenceTypeConflict] != 1 {
+		t.Errorf("rebase summary %v, expected 1 conflict", res.Summary)
+	}
+	// nothing was rebased
+	if _, err := c.GetCommit(ctx, repository, branchLog.Reference); err != nil {
+		t.Errorf("get commit after failed rebase: %s", err)
+	}
+
+	testCatalogerBranch(t, ctx, c, repository, "branch2", "branch1")
+	if _, err := c.Rebase(ctx, repository, "branch1", "master"); !errors.Is(err, catalog.ErrOperationNotPermitted) {
+		t.Errorf("rebase branched branch err=%v, expected %s", err, catalog.ErrOperationNotPermitted)
+	}
+}
+
+func TestCataloger_Rebase_LineageAndExports(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	source := testCatalogerRepo(t, ctx, c, "source", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	creation, err := c.CreateBranch(ctx, repository, "branch1", "master")
+	testutil.MustDo(t, "create branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "")
+	replayed, err := c.Commit(ctx, repository, "branch1", "add file2", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "first commit to branch1", err)
+	// master makes the same change, so it is not replayed
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file3", nil, "")
+	dropped, err := c.Commit(ctx, repository, "branch1", "add file3", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "second commit to branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "add file3 on master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "second commit to master", err)
+
+	sources := map[string]string{}
+	for i, ref := range []string{creation.Reference, replayed.Reference, dropped.Reference} {
+		testCatalogerCreateEntry(t, ctx, c, source, "master", "source"+strconv.Itoa(i), nil, "")
+		sourceLog, err := c.Commit(ctx, source, "master", "source of "+ref, "tester", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit to source", err)
+		testutil.MustDo(t, "create data lineage", c.CreateDataLineage(ctx, repository, ref,
+			[]catalog.DataLineageSource{{Repository: source, Reference: sourceLog.Reference}}))
+		sources[ref] = sourceLog.Reference
+	}
+	testutil.MustDo(t, "set export state", c.ExportStateSet(ctx, repository, "branch1",
+		func(string, catalog.CatalogBranchExportStatus) (string, catalog.CatalogBranchExportStatus, *string, error) {
+			return replayed.Reference, catalog.ExportStatusSuccess, nil, nil
+		}))
+
+	res, err := c.Rebase(ctx, repository, "branch1", "master")
+	testutil.MustDo(t, "rebase branch1", err)
+	commits, _, err := c.ListCommits(ctx, repository, "branch1", "", 2, catalog.CommitsFilter{})
+	testutil.MustDo(t, "list branch1 commits", err)
+	if len(commits) != 2 || commits[0].Reference != res.Reference {
+		t.Fatalf("rebased branch1 log %+v, expected replay of one commit on %s", commits, res.Reference)
+	}
+
+	expectSources := func(ref string, expected ...string) {
+		t.Helper()
+		edges, err := c.WalkDataLineage(ctx, repository, ref, catalog.DataLineageUpstream, 1)
+		testutil.MustDo(t, "walk data lineage", err)
+		got := map[string]bool{}
+		for _, edge := range edges {
+			got[edge.SourceReference] = true
+		}
+		if len(got) != len(expected) {
+			t.Errorf("data lineage sources of %s %v, expected %v", ref, got, expected)
+		}
+		for _, sourceRef := range expected {
+			if !got[sourceRef] {
+				t.Errorf("data lineage sources of %s %v, expected %v", ref, got, expected)
+			}
+		}
+	}
+	// the dropped commit was replayed as no changes, its lineage moves to the commit before it
+	expectSources(commits[0].Reference, sources[replayed.Reference], sources[dropped.Reference])
+	expectSources(commits[1].Reference, sources[creation.Reference])
+
+	state, err := c.GetExportState(ctx, repository, "branch1")
+	testutil.MustDo(t, "get export state", err)
+	if state.CurrentRef != "" {
+		t.Errorf("export state ref %s after rebase removed it, expected none", state.CurrentRef)
+	}
+}
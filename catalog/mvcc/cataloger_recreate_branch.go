@@ -0,0 +1,34 @@
+package mvcc
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) RecreateBranch(ctx context.Context, repository, branch string, sourceBranch string) (*catalog.CommitLog, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "sourceBranch", IsValid: ValidateBranchName(sourceBranch)},
+	}); err != nil {
+		return nil, err
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if err := deleteBranch(tx, repository, branch); err != nil {
+			return nil, err
+		}
+		return c.createBranch(tx, repository, branch, sourceBranch)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	// the branch has a new ID
+	c.cache.InvalidateBranchID(repository, branch)
+	return res.(*catalog.CommitLog), nil
+}
@@ -0,0 +1,52 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+)
+
+func TestCataloger_RecreateBranch(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/master_file", nil, "")
+	if _, err := c.Commit(ctx, repository, "master", "master file", "tester", nil, catalog.CommitParams{}); err != nil {
+		t.Fatal("commit master:", err)
+	}
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/committed_file", nil, "")
+	if _, err := c.Commit(ctx, repository, "branch1", "branch file", "tester", nil, catalog.CommitParams{}); err != nil {
+		t.Fatal("commit branch1:", err)
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/uncommitted_file", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "/new_master_file", nil, "")
+	if _, err := c.Commit(ctx, repository, "master", "new master file", "tester", nil, catalog.CommitParams{}); err != nil {
+		t.Fatal("commit master:", err)
+	}
+
+	commitLog, err := c.RecreateBranch(ctx, repository, "branch1", "master")
+	if err != nil {
+		t.Fatal("RecreateBranch:", err)
+	}
+	if commitLog == nil {
+		t.Fatal("RecreateBranch expected a commit log")
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "branch1", "/master_file", true)
+	testCatalogerGetEntry(t, ctx, c, repository, "branch1", "/new_master_file", true)
+	testCatalogerGetEntry(t, ctx, c, repository, "branch1", "/committed_file", false)
+	testCatalogerGetEntry(t, ctx, c, repository, "branch1", "/uncommitted_file", false)
+
+	// writes go to the new branch
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "/file_after_recreate", nil, "")
+	testCatalogerGetEntry(t, ctx, c, repository, "branch1", "/file_after_recreate", true)
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "/file_after_recreate", false)
+
+	_, err = c.RecreateBranch(ctx, repository, "master", "branch1")
+	if !errors.Is(err, catalog.ErrOperationNotPermitted) {
+		t.Fatalf("RecreateBranch of the default branch err=%s, expected %s", err, catalog.ErrOperationNotPermitted)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "/master_file", true)
+}
@@ -0,0 +1,65 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) InsertRefExport(ctx context.Context, repo string, export *catalog.RefExport) error {
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repo)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO catalog_ref_exports (export_id, repository_id, ref, commit_ref, destination, status)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			export.ExportID, repoID, export.Ref, export.CommitRef, export.Destination, catalog.ExportStatusInProgress)
+		if err != nil {
+			return nil, fmt.Errorf("insert ref export %s: %w", export.ExportID, err)
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func (c *cataloger) GetRefExport(ctx context.Context, repo, exportID string) (*catalog.RefExport, error) {
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repo)
+		if err != nil {
+			return nil, err
+		}
+		var export catalog.RefExport
+		err = tx.Get(&export, `
+			SELECT export_id, ref, commit_ref, destination, start_time, end_time, status, error_message
+			FROM catalog_ref_exports
+			WHERE repository_id=$1 AND export_id=$2`,
+			repoID, exportID)
+		if err != nil {
+			return nil, err
+		}
+		return &export, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.RefExport), nil
+}
+
+func (c *cataloger) EndRefExport(ctx context.Context, exportID string, status catalog.CatalogBranchExportStatus, errorMessage *string) error {
+	res, err := c.db.WithContext(ctx).Exec(`
+		UPDATE catalog_ref_exports
+		SET end_time=NOW(), status=$2, error_message=$3
+		WHERE export_id=$1`,
+		exportID, status, errorMessage)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() != 1 {
+		return fmt.Errorf("ref export %s: %w", exportID, db.ErrNotFound)
+	}
+	return nil
+}
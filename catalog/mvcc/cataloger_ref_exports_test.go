@@ -0,0 +1,55 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestRefExports(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repo := testCatalogerRepo(t, ctx, c, prefix, defaultBranch)
+
+	testutil.Must(t, c.InsertRefExport(ctx, repo, &catalog.RefExport{
+		ExportID:    "ref-export-1",
+		Ref:         "v1.0",
+		CommitRef:   "commit1",
+		Destination: "s3://export/v1.0",
+	}))
+
+	t.Run("in progress", func(t *testing.T) {
+		got, err := c.GetRefExport(ctx, repo, "ref-export-1")
+		testutil.Must(t, err)
+		if got.Ref != "v1.0" || got.CommitRef != "commit1" || got.Destination != "s3://export/v1.0" {
+			t.Errorf("got ref export %+v, expected the inserted export", got)
+		}
+		if got.Status != catalog.ExportStatusInProgress || got.EndTime != nil {
+			t.Errorf("got ref export %+v, expected an unfinished export in progress", got)
+		}
+	})
+
+	t.Run("ended", func(t *testing.T) {
+		errorMessage := "2 tasks failed"
+		testutil.Must(t, c.EndRefExport(ctx, "ref-export-1", catalog.ExportStatusFailed, &errorMessage))
+		got, err := c.GetRefExport(ctx, repo, "ref-export-1")
+		testutil.Must(t, err)
+		if got.Status != catalog.ExportStatusFailed || got.EndTime == nil ||
+			got.ErrorMessage == nil || *got.ErrorMessage != errorMessage {
+			t.Errorf("got ref export %+v, expected a failed export ended with %q", got, errorMessage)
+		}
+	})
+
+	t.Run("unknown export", func(t *testing.T) {
+		if _, err := c.GetRefExport(ctx, repo, "no-such-export"); !errors.Is(err, db.ErrNotFound) {
+			t.Errorf("get unknown ref export: expected ErrNotFound but got %v", err)
+		}
+		if err := c.EndRefExport(ctx, "no-such-export", catalog.ExportStatusSuccess, nil); !errors.Is(err, db.ErrNotFound) {
+			t.Errorf("end unknown ref export: expected ErrNotFound but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) RenameBranch(ctx context.Context, repository, branch, newName string) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "newName", IsValid: ValidateBranchName(newName)},
+	}); err != nil {
+		return err
+	}
+	if branch == catalog.DefaultImportBranchName || newName == catalog.DefaultImportBranchName {
+		return fmt.Errorf("rename import branch: %w", catalog.ErrOperationNotPermitted)
+	}
+	if branch == newName {
+		return nil
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec("LOCK TABLE catalog_branches IN SHARE UPDATE EXCLUSIVE MODE"); err != nil {
+			return nil, fmt.Errorf("lock branches for update: %w", err)
+		}
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("branch: %w", err)
+		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+
+		// tags and branches share a namespace
+		var isTag bool
+		if err := tx.GetPrimitive(&isTag, `SELECT EXISTS (SELECT 1 FROM catalog_tags WHERE repository_id=$1 AND name=$2)`,
+			repoID, newName); err != nil {
+			return nil, fmt.Errorf("tag check: %w", err)
+		}
+		if isTag {
+			return nil, fmt.Errorf("branch %s is a tag: %w", newName, db.ErrAlreadyExists)
+		}
+		_, err = tx.Exec(`UPDATE catalog_branches SET name = $2 WHERE id = $1`, branchID, newName)
+		if db.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("branch %s: %w", newName, db.ErrAlreadyExists)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rename branch: %w", err)
+		}
+		if err := renameRetentionRulesBranch(tx, repoID, branch, newName); err != nil {
+			return nil, fmt.Errorf("retention policy: %w", err)
+		}
+		if err := renameExportRefs(tx, repoID, branchID, branch, newName); err != nil {
+			return nil, fmt.Errorf("exports: %w", err)
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return err
+	}
+	// the old name no longer resolves to the branch
+	c.cache.InvalidateBranchID(repository, branch)
+	return nil
+}
+
+// renameRetentionRulesBranch rewrites the retention rules of repoID whose filter starts with
+// branch to start with newName instead
+func renameRetentionRulesBranch(tx db.Tx, repoID int, branch, newName string) error {
+	var rules catalog.Rules
+	err := tx.GetPrimitive(&rules, `SELECT (value::json)->'Rules' FROM catalog_repositories_config
+		WHERE repository_id = $1 AND key = $2 FOR UPDATE`, repoID, catalog.RetentionPolicyConfigKey)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	renamed := false
+	for i, rule := range rules {
+		parts := strings.SplitN(rule.FilterPrefix, "/", 2)
+		if parts[0] != branch {
+			continue
+		}
+		parts[0] = newName
+		rules[i].FilterPrefix = strings.Join(parts, "/")
+		renamed = true
+	}
+	if !renamed {
+		return nil
+	}
+	_, err = tx.Exec(`UPDATE catalog_repositories_config SET value = $3 WHERE repository_id = $1 AND key = $2`,
+		repoID, catalog.RetentionPolicyConfigKey, &catalog.RulesHolder{Rules: rules})
+	return err
+}
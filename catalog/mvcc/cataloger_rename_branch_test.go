@@ -0,0 +1,125 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_RenameBranch(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	_, err = c.CreateTag(ctx, repository, "tag1", "master")
+	testutil.MustDo(t, "create tag", err)
+
+	testutil.MustDo(t, "rename master", c.RenameBranch(ctx, repository, "master", "main"))
+	repo, err := c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if repo.DefaultBranch != "main" {
+		t.Errorf("default branch %s, expected main", repo.DefaultBranch)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "main:HEAD", "file1", true)
+	testCatalogerGetEntry(t, ctx, c, repository, "main", "file2", true)
+	if exists, err := c.BranchExists(ctx, repository, "master"); err != nil || exists {
+		t.Errorf("master exists=%t err=%v after rename, expected it gone", exists, err)
+	}
+
+	if err := c.RenameBranch(ctx, repository, "main", "branch1"); !errors.Is(err, db.ErrAlreadyExists) {
+		t.Errorf("rename to existing branch err=%v, expected %s", err, db.ErrAlreadyExists)
+	}
+	if err := c.RenameBranch(ctx, repository, "main", "tag1"); !errors.Is(err, db.ErrAlreadyExists) {
+		t.Errorf("rename to tag err=%v, expected %s", err, db.ErrAlreadyExists)
+	}
+	if err := c.RenameBranch(ctx, repository, "master", "other"); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("rename missing branch err=%v, expected %s", err, db.ErrNotFound)
+	}
+}
+
+func TestCataloger_RenameBranchCached(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t, WithCacheEnabled(true))
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	// creating an entry caches the branch ID of master
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+
+	testutil.MustDo(t, "rename master", c.RenameBranch(ctx, repository, "master", "main"))
+	err := c.CreateEntry(ctx, repository, "master", catalog.Entry{Path: "file2", Checksum: "ff", PhysicalAddress: "file2"}, catalog.CreateEntryParams{})
+	if !errors.Is(err, catalog.ErrBranchNotFound) {
+		t.Errorf("create entry on master after rename err=%v, expected %s", err, catalog.ErrBranchNotFound)
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository, "main", "file2", nil, "")
+}
+
+func TestCataloger_RenameBranchExports(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	exported, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+
+	setState := func(state catalog.CatalogBranchExportStatus) {
+		t.Helper()
+		testutil.MustDo(t, "set export state", c.ExportStateSet(ctx, repository, "master",
+			func(string, catalog.CatalogBranchExportStatus) (string, catalog.CatalogBranchExportStatus, *string, error) {
+				return exported.Reference, state, nil, nil
+			}))
+	}
+	setState(catalog.ExportStatusInProgress)
+	testutil.MustDo(t, "insert export run", c.InsertExportRun(ctx, repository, "master",
+		&catalog.ExportRun{ExportID: "run", ToRef: exported.Reference}))
+	if err := c.RenameBranch(ctx, repository, "master", "main"); !errors.Is(err, catalog.ErrExportInProgress) {
+		t.Fatalf("rename while exporting err=%v, expected %s", err, catalog.ErrExportInProgress)
+	}
+	testutil.MustDo(t, "end export run", c.EndExportRun(ctx, "run", catalog.ExportStatusSuccess, nil))
+	setState(catalog.ExportStatusSuccess)
+	testutil.MustDo(t, "insert ref export", c.InsertRefExport(ctx, repository, &catalog.RefExport{
+		ExportID:    "ref-export",
+		Ref:         exported.Reference,
+		CommitRef:   exported.Reference,
+		Destination: "s3://bucket/export",
+	}))
+	testutil.MustDo(t, "end ref export", c.EndRefExport(ctx, "ref-export", catalog.ExportStatusSuccess, nil))
+
+	testutil.MustDo(t, "rename master", c.RenameBranch(ctx, repository, "master", "main"))
+	state, err := c.GetExportState(ctx, repository, "main")
+	testutil.MustDo(t, "get export state", err)
+	if _, err := c.GetCommit(ctx, repository, state.CurrentRef); err != nil {
+		t.Fatalf("get exported commit %s after rename: %s", state.CurrentRef, err)
+	}
+	runs, _, err := c.GetExportRuns(ctx, repository, "main", -1, "")
+	testutil.MustDo(t, "get export runs", err)
+	if len(runs) != 1 || runs[0].ToRef != state.CurrentRef {
+		t.Errorf("export runs %+v after rename, expected one run to %s", runs, state.CurrentRef)
+	}
+	refExport, err := c.GetRefExport(ctx, repository, "ref-export")
+	testutil.MustDo(t, "get ref export", err)
+	if refExport.Ref != state.CurrentRef || refExport.CommitRef != state.CurrentRef {
+		t.Errorf("ref export %+v after rename, expected ref %s", refExport, state.CurrentRef)
+	}
+
+	// the next export diffs the branch from the exported commit
+	testCatalogerCreateEntry(t, ctx, c, repository, "main", "file2", nil, "")
+	head, err := c.Commit(ctx, repository, "main", "commit to main", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to main", err)
+	commits, _, err := c.ListCommitsSince(ctx, repository, "main", state.CurrentRef, -1)
+	testutil.MustDo(t, "list commits since export", err)
+	if len(commits) != 1 || commits[0].Reference != head.Reference {
+		t.Errorf("commits since export %+v, expected %s", commits, head.Reference)
+	}
+	diffs, _, err := c.Diff(ctx, repository, head.Reference, state.CurrentRef, catalog.DiffParams{Limit: -1})
+	testutil.MustDo(t, "diff since export", err)
+	if len(diffs) != 1 || diffs[0].Path != "file2" || diffs[0].Type != catalog.DifferenceTypeAdded {
+		t.Errorf("diff since export %+v, expected file2 added", diffs)
+	}
+}
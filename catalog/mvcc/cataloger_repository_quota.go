@@ -0,0 +1,241 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) GetRepositoryQuota(ctx context.Context, repository string) (*catalog.RepositoryQuota, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		return getRepositoryQuota(tx, repoID)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.RepositoryQuota), nil
+}
+
+func (c *cataloger) SetRepositoryQuota(ctx context.Context, repository string, quota *catalog.RepositoryQuota) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	if quota == nil || quota.MaxStorageBytes < 0 || quota.MaxObjects < 0 ||
+		quota.MaxRequestsPerSecond < 0 || quota.MaxExportBytesPerSecond < 0 {
+		return fmt.Errorf("quota: %w", catalog.ErrInvalidValue)
+	}
+	if quota.Organization != "" && !IsValidOrganizationName(quota.Organization) {
+		return fmt.Errorf("organization: %w", catalog.ErrInvalidValue)
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var organizationID *int
+		if quota.Organization != "" {
+			organizationID = new(int)
+			err := tx.GetPrimitive(organizationID, `SELECT id FROM catalog_organizations_quota WHERE name=$1`, quota.Organization)
+			if errors.Is(err, db.ErrNotFound) {
+				return nil, catalog.ErrOrganizationNotFound
+			}
+			if err != nil {
+				return nil, fmt.Errorf("get organization: %w", err)
+			}
+		}
+		_, err = tx.Exec(`INSERT INTO catalog_repositories_quota
+				(repository_id, max_storage_bytes, max_objects, max_requests_per_second, max_export_bytes_per_second, organization_id)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (repository_id)
+			DO UPDATE SET (max_storage_bytes, max_objects, max_requests_per_second, max_export_bytes_per_second, organization_id,
+					used_storage_bytes, used_objects) =
+				(EXCLUDED.max_storage_bytes, EXCLUDED.max_objects, EXCLUDED.max_requests_per_second, EXCLUDED.max_export_bytes_per_second, EXCLUDED.organization_id,
+					NULL, NULL)`,
+			repoID, quota.MaxStorageBytes, quota.MaxObjects, quota.MaxRequestsPerSecond, quota.MaxExportBytesPerSecond, organizationID)
+		if err != nil {
+			return nil, fmt.Errorf("set repository quota: %w", err)
+		}
+		// usage bounds are not kept without limits, and the objects of the repository join
+		// those of its organization: rescan on the next write
+		if organizationID != nil {
+			_, err = tx.Exec(`UPDATE catalog_organizations_quota SET (used_storage_bytes, used_objects) = (NULL, NULL)
+				WHERE id=$1`, *organizationID)
+			if err != nil {
+				return nil, fmt.Errorf("reset organization usage: %w", err)
+			}
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return err
+	}
+	c.rateLimiters.invalidate(repository)
+	return nil
+}
+
+func (c *cataloger) GetRepositoryUsage(ctx context.Context, repository string) (*catalog.RepositoryUsage, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		return getRepositoryUsage(tx, repoID)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.RepositoryUsage), nil
+}
+
+func (c *cataloger) CheckRepositoryQuota(ctx context.Context, repository string, objects int64, size int64) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		return nil, checkQuota(tx, repoID, objects, size, false)
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func getRepositoryQuota(tx db.Tx, repositoryID int) (*catalog.RepositoryQuota, error) {
+	var quota catalog.RepositoryQuota
+	err := tx.Get(&quota, `SELECT q.max_storage_bytes, q.max_objects, q.max_requests_per_second, q.max_export_bytes_per_second,
+			COALESCE(o.name, '') AS organization
+		FROM catalog_repositories_quota q LEFT JOIN catalog_organizations_quota o ON o.id = q.organization_id
+		WHERE q.repository_id=$1`, repositoryID)
+	if errors.Is(err, db.ErrNotFound) {
+		return &quota, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get repository quota: %w", err)
+	}
+	return &quota, nil
+}
+
+func getRepositoryUsage(tx db.Tx, repositoryID int) (*catalog.RepositoryUsage, error) {
+	var usage catalog.RepositoryUsage
+	err := tx.Get(&usage, `SELECT COUNT(*) AS objects, COALESCE(SUM(size),0) AS storage_bytes
+		FROM (SELECT DISTINCT ON (e.physical_address) e.physical_address, e.size
+			FROM catalog_entries e JOIN catalog_branches b ON e.branch_id = b.id
+			WHERE b.repository_id = $1 AND NOT e.is_expired) o`, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("get repository usage: %w", err)
+	}
+	return &usage, nil
+}
+
+// quotaBound holds the storage limits of a quota and an upper bound of the usage they limit.
+// Writers of a repository or organization with a quota are serialized on its quota row, which
+// keeps the bound: writes add to it, deletes don't subtract from it.
+type quotaBound struct {
+	MaxStorageBytes  int64  `db:"max_storage_bytes"`
+	MaxObjects       int64  `db:"max_objects"`
+	UsedStorageBytes *int64 `db:"used_storage_bytes"`
+	UsedObjects      *int64 `db:"used_objects"`
+}
+
+// check verifies that adding objects with a total of size bytes keeps within the limits of b.
+// The usage is scanned by getUsage only when the bound is unknown or would exceed the limits.
+// It returns the bound to store, nil to keep the current one.  The added objects are part of
+// the returned bound only if reserve is set, when the caller writes them.
+func (b *quotaBound) check(objects int64, size int64, reserve bool, getUsage func() (*catalog.RepositoryUsage, error)) (*catalog.RepositoryUsage, error) {
+	quota := &catalog.RepositoryQuota{MaxStorageBytes: b.MaxStorageBytes, MaxObjects: b.MaxObjects}
+	if quota.MaxStorageBytes == 0 && quota.MaxObjects == 0 {
+		return nil, nil
+	}
+	if b.UsedStorageBytes != nil && b.UsedObjects != nil {
+		usage := &catalog.RepositoryUsage{
+			StorageBytes: *b.UsedStorageBytes + size,
+			Objects:      *b.UsedObjects + objects,
+		}
+		if !quota.Exceeds(usage) {
+			if !reserve {
+				return nil, nil
+			}
+			return usage, nil
+		}
+	}
+	scanned, err := getUsage()
+	if err != nil {
+		return nil, err
+	}
+	usage := &catalog.RepositoryUsage{
+		StorageBytes: scanned.StorageBytes + size,
+		Objects:      scanned.Objects + objects,
+	}
+	if quota.Exceeds(usage) {
+		return nil, catalog.ErrQuotaExceeded
+	}
+	if !reserve {
+		return scanned, nil
+	}
+	return usage, nil
+}
+
+// checkRepositoryQuota verifies that adding objects with a total of size bytes to repository
+// keeps it and its organization within their quotas, adding them to the usage bounds.
+// Repositories without a quota are not scanned for usage.
+func checkRepositoryQuota(tx db.Tx, repositoryID int, objects int64, size int64) error {
+	return checkQuota(tx, repositoryID, objects, size, true)
+}
+
+// checkQuota verifies that adding objects with a total of size bytes to repository keeps it
+// and its organization within their quotas, adding them to the usage bounds if reserve is set.
+// The quota row of the repository is locked before that of its organization.
+func checkQuota(tx db.Tx, repositoryID int, objects int64, size int64, reserve bool) error {
+	var row struct {
+		quotaBound
+		OrganizationID *int `db:"organization_id"`
+	}
+	err := tx.Get(&row, `SELECT max_storage_bytes, max_objects, used_storage_bytes, used_objects, organization_id
+		FROM catalog_repositories_quota WHERE repository_id=$1
+		FOR UPDATE`, repositoryID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get repository quota: %w", err)
+	}
+	usage, err := row.check(objects, size, reserve, func() (*catalog.RepositoryUsage, error) {
+		return getRepositoryUsage(tx, repositoryID)
+	})
+	if err != nil {
+		return err
+	}
+	if usage != nil {
+		_, err = tx.Exec(`UPDATE catalog_repositories_quota SET (used_storage_bytes, used_objects) = ($2, $3)
+			WHERE repository_id=$1`, repositoryID, usage.StorageBytes, usage.Objects)
+		if err != nil {
+			return fmt.Errorf("update repository usage: %w", err)
+		}
+	}
+	if row.OrganizationID == nil {
+		return nil
+	}
+	return checkOrganizationQuota(tx, *row.OrganizationID, objects, size, reserve)
+}
@@ -0,0 +1,210 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+)
+
+func TestCataloger_RepositoryQuota(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	quota, err := c.GetRepositoryQuota(ctx, repository)
+	if err != nil {
+		t.Fatal("GetRepositoryQuota() on new repository", err)
+	}
+	if diff := deep.Equal(quota, &catalog.RepositoryQuota{}); diff != nil {
+		t.Fatal("GetRepositoryQuota() expected no quota", diff)
+	}
+
+	err = c.SetRepositoryQuota(ctx, repository, &catalog.RepositoryQuota{MaxStorageBytes: -1})
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Fatalf("SetRepositoryQuota() negative value err=%s, expected=%s", err, catalog.ErrInvalidValue)
+	}
+
+	expected := &catalog.RepositoryQuota{MaxStorageBytes: 100, MaxObjects: 2}
+	if err := c.SetRepositoryQuota(ctx, repository, expected); err != nil {
+		t.Fatal("SetRepositoryQuota()", err)
+	}
+	quota, err = c.GetRepositoryQuota(ctx, repository)
+	if err != nil {
+		t.Fatal("GetRepositoryQuota()", err)
+	}
+	if diff := deep.Equal(quota, expected); diff != nil {
+		t.Fatal("GetRepositoryQuota() unexpected quota", diff)
+	}
+
+	_, err = c.GetRepositoryQuota(ctx, "no-repository")
+	if !errors.Is(err, catalog.ErrRepositoryNotFound) {
+		t.Fatalf("GetRepositoryQuota() unknown repository err=%s, expected=%s", err, catalog.ErrRepositoryNotFound)
+	}
+}
+
+func TestCataloger_RepositoryUsage(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+
+	entries := []struct {
+		branch  string
+		path    string
+		address string
+		size    int64
+	}{
+		{branch: "master", path: "a", address: "addr1", size: 10},
+		{branch: "master", path: "b", address: "addr2", size: 20},
+		// same physical address on another branch is counted once
+		{branch: "branch1", path: "c", address: "addr1", size: 10},
+	}
+	for _, ent := range entries {
+		err := c.CreateEntry(ctx, repository, ent.branch, catalog.Entry{
+			Path:            ent.path,
+			PhysicalAddress: ent.address,
+			Checksum:        ent.address,
+			Size:            ent.size,
+		}, catalog.CreateEntryParams{})
+		if err != nil {
+			t.Fatalf("CreateEntry() %s on %s: %s", ent.path, ent.branch, err)
+		}
+	}
+
+	usage, err := c.GetRepositoryUsage(ctx, repository)
+	if err != nil {
+		t.Fatal("GetRepositoryUsage()", err)
+	}
+	if diff := deep.Equal(usage, &catalog.RepositoryUsage{StorageBytes: 30, Objects: 2}); diff != nil {
+		t.Fatal("GetRepositoryUsage() unexpected usage", diff)
+	}
+
+	if err := c.SetRepositoryQuota(ctx, repository, &catalog.RepositoryQuota{MaxObjects: 3}); err != nil {
+		t.Fatal("SetRepositoryQuota()", err)
+	}
+	err = c.CreateEntry(ctx, repository, "master", catalog.Entry{Path: "d", PhysicalAddress: "addr3", Checksum: "addr3", Size: 5}, catalog.CreateEntryParams{})
+	if err != nil {
+		t.Fatal("CreateEntry() within quota", err)
+	}
+	err = c.CreateEntry(ctx, repository, "master", catalog.Entry{Path: "e", PhysicalAddress: "addr4", Checksum: "addr4", Size: 5}, catalog.CreateEntryParams{})
+	if !errors.Is(err, catalog.ErrQuotaExceeded) {
+		t.Fatalf("CreateEntry() over object quota err=%s, expected=%s", err, catalog.ErrQuotaExceeded)
+	}
+
+	if err := c.SetRepositoryQuota(ctx, repository, &catalog.RepositoryQuota{MaxStorageBytes: 40}); err != nil {
+		t.Fatal("SetRepositoryQuota()", err)
+	}
+	err = c.CreateEntries(ctx, repository, "master", []catalog.Entry{
+		{Path: "f", PhysicalAddress: "addr5", Checksum: "addr5", Size: 3},
+		{Path: "g", PhysicalAddress: "addr6", Checksum: "addr6", Size: 3},
+	})
+	if !errors.Is(err, catalog.ErrQuotaExceeded) {
+		t.Fatalf("CreateEntries() over storage quota err=%s, expected=%s", err, catalog.ErrQuotaExceeded)
+	}
+}
+
+func TestCataloger_RepositoryQuotaUsageBound(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	if err := c.SetRepositoryQuota(ctx, repository, &catalog.RepositoryQuota{MaxObjects: 3}); err != nil {
+		t.Fatal("SetRepositoryQuota()", err)
+	}
+	mvccCataloger := c.Cataloger.(*cataloger)
+	usedObjects := func() int64 {
+		t.Helper()
+		var used int64
+		err := mvccCataloger.db.GetPrimitive(&used, `SELECT q.used_objects FROM catalog_repositories_quota q
+			JOIN catalog_repositories r ON r.id = q.repository_id WHERE r.name = $1`, repository)
+		if err != nil {
+			t.Fatal("get used objects", err)
+		}
+		return used
+	}
+
+	for _, p := range []string{"a", "b"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", p, nil, "")
+	}
+	if used := usedObjects(); used != 2 {
+		t.Fatalf("used objects %d, expected 2", used)
+	}
+	// deletes keep the bound
+	if err := c.DeleteEntry(ctx, repository, "master", "b"); err != nil {
+		t.Fatal("DeleteEntry()", err)
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "c", nil, "")
+	if used := usedObjects(); used != 3 {
+		t.Fatalf("used objects %d, expected 3", used)
+	}
+	// the bound exceeds the quota, the usage is scanned and the bound reset
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "d", nil, "")
+	if used := usedObjects(); used != 3 {
+		t.Fatalf("used objects %d after scan, expected 3", used)
+	}
+	err := c.CreateEntry(ctx, repository, "master", catalog.Entry{Path: "e", PhysicalAddress: "addr5", Checksum: "addr5", Size: 5}, catalog.CreateEntryParams{})
+	if !errors.Is(err, catalog.ErrQuotaExceeded) {
+		t.Fatalf("CreateEntry() over object quota err=%s, expected=%s", err, catalog.ErrQuotaExceeded)
+	}
+}
+
+func TestCataloger_CheckRepositoryQuota(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+
+	if err := c.CheckRepositoryQuota(ctx, repository, 100, 100); err != nil {
+		t.Fatal("CheckRepositoryQuota() without quota", err)
+	}
+	if err := c.SetRepositoryQuota(ctx, repository, &catalog.RepositoryQuota{MaxObjects: 2}); err != nil {
+		t.Fatal("SetRepositoryQuota()", err)
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "a", nil, "")
+	if err := c.CheckRepositoryQuota(ctx, repository, 1, 0); err != nil {
+		t.Fatal("CheckRepositoryQuota() within quota", err)
+	}
+	// checks don't reserve, the object fits after any number of checks
+	if err := c.CheckRepositoryQuota(ctx, repository, 1, 0); err != nil {
+		t.Fatal("CheckRepositoryQuota() again within quota", err)
+	}
+	err := c.CheckRepositoryQuota(ctx, repository, 2, 0)
+	if !errors.Is(err, catalog.ErrQuotaExceeded) {
+		t.Fatalf("CheckRepositoryQuota() over quota err=%s, expected=%s", err, catalog.ErrQuotaExceeded)
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "b", nil, "")
+}
+
+func TestCataloger_MergeOverQuota(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	for _, p := range []string{"a", "b", "c"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "branch1", p, nil, "")
+	}
+	if _, err := c.Commit(ctx, repository, "branch1", "three objects", "tester", nil, catalog.CommitParams{}); err != nil {
+		t.Fatal("Commit()", err)
+	}
+	if err := c.SetRepositoryQuota(ctx, repository, &catalog.RepositoryQuota{MaxObjects: 2}); err != nil {
+		t.Fatal("SetRepositoryQuota()", err)
+	}
+
+	_, err := c.Merge(ctx, repository, "branch1", "master", "tester", "over quota", nil, catalog.MergeParams{})
+	if !errors.Is(err, catalog.ErrQuotaExceeded) {
+		t.Fatalf("Merge() over quota err=%s, expected=%s", err, catalog.ErrQuotaExceeded)
+	}
+
+	if err := c.SetRepositoryQuota(ctx, repository, &catalog.RepositoryQuota{MaxObjects: 3}); err != nil {
+		t.Fatal("SetRepositoryQuota()", err)
+	}
+	// merged entries share the objects of the source branch
+	res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "within quota", nil, catalog.MergeParams{})
+	if err != nil {
+		t.Fatal("Merge() within quota", err)
+	}
+	if added := res.Summary[catalog.DifferenceTypeAdded]; added != 3 {
+		t.Fatalf("Merge() within quota added %d objects, expected 3", added)
+	}
+}
@@ -0,0 +1,53 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) SetRepositoryReadOnly(ctx context.Context, repository string, readOnly bool) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		res, err := tx.Exec(`UPDATE catalog_repositories SET read_only = $2 WHERE name = $1`, repository, readOnly)
+		if err != nil {
+			return nil, fmt.Errorf("set repository read-only: %w", err)
+		}
+		if res.RowsAffected() == 0 {
+			return nil, catalog.ErrRepositoryNotFound
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+// checkRepositoryWritable fails with ErrRepositoryReadOnly when repository is read-only, or
+// with ErrRepositoryArchived when it is archived.  The flags are read on every write rather
+// than cached, so setting them takes effect immediately.
+func checkRepositoryWritable(tx db.Tx, repository string) error {
+	var flags struct {
+		ReadOnly bool `db:"read_only"`
+		Archived bool `db:"archived"`
+	}
+	err := tx.Get(&flags, `SELECT read_only, archived FROM catalog_repositories WHERE name = $1`, repository)
+	if errors.Is(err, db.ErrNotFound) {
+		return catalog.ErrRepositoryNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get repository read-only: %w", err)
+	}
+	if flags.Archived {
+		return fmt.Errorf("%s: %w", repository, catalog.ErrRepositoryArchived)
+	}
+	if flags.ReadOnly {
+		return fmt.Errorf("%s: %w", repository, catalog.ErrRepositoryReadOnly)
+	}
+	return nil
+}
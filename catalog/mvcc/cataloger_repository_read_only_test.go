@@ -0,0 +1,58 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_SetRepositoryReadOnly(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit file1", err)
+
+	testutil.MustDo(t, "set read-only", c.SetRepositoryReadOnly(ctx, repository, true))
+	repo, err := c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if !repo.ReadOnly {
+		t.Fatal("GetRepository() expected read-only repository")
+	}
+
+	writes := map[string]func() error{
+		"create entry": func() error {
+			return c.CreateEntry(ctx, repository, "master", catalog.Entry{Path: "file2", Checksum: "ff", PhysicalAddress: "file2"}, catalog.CreateEntryParams{})
+		},
+		"delete entry": func() error {
+			return c.DeleteEntry(ctx, repository, "master", "file1")
+		},
+		"commit": func() error {
+			_, err := c.Commit(ctx, repository, "master", "commit", "tester", nil, catalog.CommitParams{})
+			return err
+		},
+		"create branch": func() error {
+			_, err := c.CreateBranch(ctx, repository, "branch1", "master")
+			return err
+		},
+	}
+	for name, write := range writes {
+		if err := write(); !errors.Is(err, catalog.ErrRepositoryReadOnly) {
+			t.Errorf("%s on read-only repository err=%v, expected %s", name, err, catalog.ErrRepositoryReadOnly)
+		}
+	}
+	if _, err := c.GetEntry(ctx, repository, "master", "file1", catalog.GetEntryParams{}); err != nil {
+		t.Errorf("GetEntry() on read-only repository err=%s, expected no error", err)
+	}
+
+	testutil.MustDo(t, "unset read-only", c.SetRepositoryReadOnly(ctx, repository, false))
+	testutil.MustDo(t, "delete entry", c.DeleteEntry(ctx, repository, "master", "file1"))
+
+	if err := c.SetRepositoryReadOnly(ctx, "no-repo", true); !errors.Is(err, catalog.ErrRepositoryNotFound) {
+		t.Errorf("SetRepositoryReadOnly() on missing repository err=%v, expected %s", err, catalog.ErrRepositoryNotFound)
+	}
+}
@@ -2,24 +2,141 @@ package mvcc
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 )
 
-func (c *cataloger) ResetBranch(ctx context.Context, repository, branch string) error {
+const resetCommitMessageFormat = "Reset to %s"
+
+func (c *cataloger) ResetBranch(ctx context.Context, repository, branch, reference string, hard bool) error {
 	if err := Validate(ValidateFields{
 		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
 		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "reference", IsValid: ValidateOptionalString(reference, IsValidReference)},
 	}); err != nil {
 		return err
 	}
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
-		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		if reference == "" {
+			if !hard {
+				return nil, nil
+			}
+			branchID, err := c.getBranchIDCache(tx, repository, branch)
+			if err != nil {
+				return nil, err
+			}
+			_, err = tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND min_commit=$2`, branchID, MinCommitUncommittedIndicator)
+			return nil, err
+		}
+
+		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
 		if err != nil {
 			return nil, err
 		}
-		_, err = tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND min_commit=$2`, branchID, MinCommitUncommittedIndicator)
-		return nil, err
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+		refBranchID, commitID, err := c.resolveCommit(tx, repository, reference)
+		if err != nil {
+			return nil, fmt.Errorf("reference: %w", err)
+		}
+		if refBranchID != branchID {
+			return nil, c.resetBranchToRef(ctx, tx, branchID, refBranchID, commitID, hard)
+		}
+		if err := checkCommitsUnreferenced(tx, branchID, commitID); err != nil {
+			return nil, err
+		}
+		if err := releaseExportedCommits(tx, branchID, branch, commitID, MaxCommitID); err != nil {
+			return nil, err
+		}
+		if hard {
+			if _, err := tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND min_commit=$2`, branchID, MinCommitUncommittedIndicator); err != nil {
+				return nil, fmt.Errorf("delete uncommitted entries: %w", err)
+			}
+		}
+		// drop the entries committed after commitID, and restore the ones deleted after it
+		if _, err := tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND min_commit>$2 AND min_commit<$3`,
+			branchID, commitID, MaxCommitID); err != nil {
+			return nil, fmt.Errorf("delete committed entries: %w", err)
+		}
+		if _, err := tx.Exec(`UPDATE catalog_entries SET max_commit=$3 WHERE branch_id=$1 AND max_commit>=$2 AND max_commit<$3`,
+			branchID, commitID, MaxCommitID); err != nil {
+			return nil, fmt.Errorf("restore deleted entries: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM catalog_commits WHERE branch_id=$1 AND commit_id>$2`, branchID, commitID); err != nil {
+			return nil, fmt.Errorf("delete commits: %w", err)
+		}
+		return nil, nil
 	}, c.txOpts(ctx)...)
 	return err
 }
+
+// resetBranchToRef resets branchID to commitID of refBranchID, another branch.  The commits of
+// branchID are kept: the entries of the commit are committed to branchID as a new commit.
+func (c *cataloger) resetBranchToRef(ctx context.Context, tx db.Tx, branchID int64, refBranchID int64, commitID CommitID, hard bool) error {
+	if hard {
+		if _, err := tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND min_commit=$2`, branchID, MinCommitUncommittedIndicator); err != nil {
+			return fmt.Errorf("delete uncommitted entries: %w", err)
+		}
+	}
+	changes, err := refChanges(tx, branchID, refBranchID, commitID)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	var refBranch string
+	if err := tx.GetPrimitive(&refBranch, `SELECT name FROM catalog_branches WHERE id=$1`, refBranchID); err != nil {
+		return fmt.Errorf("reference branch: %w", err)
+	}
+	previousMaxCommitID, err := getLastCommitIDByBranchID(tx, branchID)
+	if err != nil {
+		return fmt.Errorf("last commit id: %w", err)
+	}
+	nextCommitID, err := getNextCommitID(tx)
+	if err != nil {
+		return fmt.Errorf("next commit id: %w", err)
+	}
+	for i := 0; i < len(changes); i += MergeBatchSize {
+		end := i + MergeBatchSize
+		if end > len(changes) {
+			end = len(changes)
+		}
+		if err := applyChangesToBranch(tx, branchID, previousMaxCommitID, nextCommitID, changes[i:end]); err != nil {
+			return err
+		}
+	}
+	committer, metadata := c.commitAttribution(ctx, catalog.DefaultCommitter, nil)
+	message := fmt.Sprintf(resetCommitMessageFormat, MakeReference(refBranch, commitID))
+	_, err = tx.Exec(`INSERT INTO catalog_commits (branch_id,commit_id,committer,message,creation_date,metadata,merge_type,previous_commit_id)
+		VALUES ($1,$2,$3,$4,transaction_timestamp(),$5,$6,$7)`,
+		branchID, nextCommitID, committer, message, metadata, RelationTypeNone, previousMaxCommitID)
+	if err != nil {
+		return fmt.Errorf("insert commit: %w", err)
+	}
+	return nil
+}
+
+// checkCommitsUnreferenced returns an error if other refs point to the commits of branchID
+// after commitID
+func checkCommitsUnreferenced(tx db.Tx, branchID int64, commitID CommitID) error {
+	var referenced bool
+	err := tx.GetPrimitive(&referenced, `SELECT
+			EXISTS (SELECT 1 FROM catalog_commits WHERE merge_source_branch = $1 AND merge_source_commit > $2)
+			OR EXISTS (SELECT 1 FROM catalog_tags WHERE branch_id = $1 AND commit_id > $2)
+			OR EXISTS (SELECT 1 FROM catalog_data_lineage WHERE source_branch_id = $1 AND source_commit_id > $2)`,
+		branchID, commitID)
+	if err != nil {
+		return fmt.Errorf("check commit references: %w", err)
+	}
+	if referenced {
+		return fmt.Errorf("%w: later commits are merged, branched, tagged or lineage sources", catalog.ErrOperationNotPermitted)
+	}
+	return nil
+}
@@ -2,18 +2,20 @@ package mvcc
 
 import (
 	"context"
+	"errors"
 	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
 )
 
 func TestCataloger_ResetBranch_NoChanges(t *testing.T) {
 	ctx := context.Background()
 	c := testCataloger(t)
 	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
-	err := c.ResetBranch(ctx, repository, "master")
+	err := c.ResetBranch(ctx, repository, "master", "", true)
 	if err != nil {
 		t.Fatal("Reset branch should work on empty branch")
 	}
@@ -35,7 +37,7 @@ func TestCataloger_ResetBranch_ChangesOnBranch(t *testing.T) {
 			t.Fatal("create entry for ResetBranch:", err)
 		}
 	}
-	_, err := c.Commit(ctx, repository, "master", "commit three files", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "commit three files", "tester", nil, catalog.CommitParams{})
 	if err != nil {
 		t.Fatal("Commit for ResetBranch:", err)
 	}
@@ -55,7 +57,7 @@ func TestCataloger_ResetBranch_ChangesOnBranch(t *testing.T) {
 		}
 	}
 
-	if err := c.ResetBranch(ctx, repository, "master"); err != nil {
+	if err := c.ResetBranch(ctx, repository, "master", "", true); err != nil {
 		t.Fatal("Reset branch should work on empty branch")
 	}
 	reference := MakeReference("master", UncommittedID)
@@ -85,7 +87,7 @@ func TestCataloger_ResetBranch_ChangesOnParent(t *testing.T) {
 			t.Fatal("create entry for ResetBranch:", err)
 		}
 	}
-	_, err := c.Commit(ctx, repository, "master", "commit three files", "tester", nil)
+	_, err := c.Commit(ctx, repository, "master", "commit three files", "tester", nil, catalog.CommitParams{})
 	if err != nil {
 		t.Fatal("Commit for ResetBranch:", err)
 	}
@@ -111,7 +113,7 @@ func TestCataloger_ResetBranch_ChangesOnParent(t *testing.T) {
 		}
 	}
 
-	if err := c.ResetBranch(ctx, repository, "b1"); err != nil {
+	if err := c.ResetBranch(ctx, repository, "b1", "", true); err != nil {
 		t.Fatal("Reset branch should work on empty branch")
 	}
 	entries, _, err := c.ListEntries(ctx, repository, MakeReference("b1", UncommittedID), "", "", "", -1)
@@ -123,3 +125,135 @@ func TestCataloger_ResetBranch_ChangesOnParent(t *testing.T) {
 		t.Fatalf("ListEntries for ResetBranch should return %d items, got %d", expectedEntriesLen, len(entries))
 	}
 }
+
+func TestCataloger_ResetBranch_ToCommit(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	resetLog, err := c.Commit(ctx, repository, "master", "commit two files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "first commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "changed")
+	testutil.MustDo(t, "delete file2", c.DeleteEntry(ctx, repository, "master", "file2"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "change files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "second commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "uncommitted", nil, "")
+
+	testutil.MustDo(t, "soft reset", c.ResetBranch(ctx, repository, "master", resetLog.Reference, false))
+	headRef, err := c.GetBranchReference(ctx, repository, "master")
+	testutil.MustDo(t, "get branch reference", err)
+	if headRef != resetLog.Reference {
+		t.Errorf("branch head %s after reset, expected %s", headRef, resetLog.Reference)
+	}
+	file1, err := c.GetEntry(ctx, repository, "master:HEAD", "file1", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get file1", err)
+	if expected := testCreateEntryCalcChecksum("file1", t.Name(), ""); file1.Checksum != expected {
+		t.Errorf("file1 checksum %s after reset, expected %s", file1.Checksum, expected)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file2", true)
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file3", false)
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "uncommitted", true)
+
+	testutil.MustDo(t, "hard reset", c.ResetBranch(ctx, repository, "master", resetLog.Reference, true))
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "uncommitted", false)
+
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file4", nil, "")
+	branchLog, err := c.Commit(ctx, repository, "branch1", "add file4", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	testutil.MustDo(t, "reset to commit of another branch", c.ResetBranch(ctx, repository, "master", branchLog.Reference, true))
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file4", true)
+	headLog, err := c.GetCommit(ctx, repository, "master")
+	testutil.MustDo(t, "get master commit", err)
+	if len(headLog.Parents) != 1 || headLog.Parents[0] != resetLog.Reference {
+		t.Errorf("reset to commit of another branch committed on %v, expected parent %s", headLog.Parents, resetLog.Reference)
+	}
+}
+
+func TestCataloger_ResetBranch_ToParentCommit(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	parentLog, err := c.Commit(ctx, repository, "master", "add file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "add file2", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file1", nil, "changed")
+	testutil.MustDo(t, "delete file2", c.DeleteEntry(ctx, repository, "branch1", "file2"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file3", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "change files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "uncommitted", nil, "")
+
+	// a commit of the parent branch, before branch1 was created
+	testutil.MustDo(t, "soft reset", c.ResetBranch(ctx, repository, "branch1", parentLog.Reference, false))
+	file1, err := c.GetEntry(ctx, repository, "branch1:HEAD", "file1", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get file1", err)
+	if expected := testCreateEntryCalcChecksum("file1", t.Name(), ""); file1.Checksum != expected {
+		t.Errorf("file1 checksum %s after reset, expected %s", file1.Checksum, expected)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "branch1:HEAD", "file2", false)
+	testCatalogerGetEntry(t, ctx, c, repository, "branch1:HEAD", "file3", false)
+	testCatalogerGetEntry(t, ctx, c, repository, "branch1", "uncommitted", true)
+
+	headRef, err := c.GetBranchReference(ctx, repository, "branch1")
+	testutil.MustDo(t, "get branch reference", err)
+	testutil.MustDo(t, "reset to the same content", c.ResetBranch(ctx, repository, "branch1", parentLog.Reference, true))
+	testCatalogerGetEntry(t, ctx, c, repository, "branch1", "uncommitted", false)
+	if ref, err := c.GetBranchReference(ctx, repository, "branch1"); err != nil || ref != headRef {
+		t.Errorf("reset to the same content moved head to %s (err=%v), expected %s", ref, err, headRef)
+	}
+}
+
+func TestCataloger_ResetBranch_ExportState(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	resetLog, err := c.Commit(ctx, repository, "master", "add file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "first commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	exportedLog, err := c.Commit(ctx, repository, "master", "add file2", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "second commit", err)
+
+	setState := func(ref string, state catalog.CatalogBranchExportStatus) {
+		t.Helper()
+		testutil.MustDo(t, "set export state", c.ExportStateSet(ctx, repository, "master",
+			func(string, catalog.CatalogBranchExportStatus) (string, catalog.CatalogBranchExportStatus, *string, error) {
+				return ref, state, nil, nil
+			}))
+	}
+	setState(exportedLog.Reference, catalog.ExportStatusInProgress)
+	if err := c.ResetBranch(ctx, repository, "master", resetLog.Reference, true); !errors.Is(err, catalog.ErrExportInProgress) {
+		t.Fatalf("reset while exporting err=%v, expected %s", err, catalog.ErrExportInProgress)
+	}
+
+	setState(exportedLog.Reference, catalog.ExportStatusSuccess)
+	exportID := "ref-export"
+	testutil.MustDo(t, "insert ref export", c.InsertRefExport(ctx, repository, &catalog.RefExport{
+		ExportID:    exportID,
+		Ref:         exportedLog.Reference,
+		CommitRef:   exportedLog.Reference,
+		Destination: "s3://bucket/export",
+	}))
+	if err := c.ResetBranch(ctx, repository, "master", resetLog.Reference, true); !errors.Is(err, catalog.ErrExportInProgress) {
+		t.Fatalf("reset while exporting ref err=%v, expected %s", err, catalog.ErrExportInProgress)
+	}
+	testutil.MustDo(t, "end ref export", c.EndRefExport(ctx, exportID, catalog.ExportStatusSuccess, nil))
+
+	testutil.MustDo(t, "reset", c.ResetBranch(ctx, repository, "master", resetLog.Reference, true))
+	state, err := c.GetExportState(ctx, repository, "master")
+	testutil.MustDo(t, "get export state", err)
+	if state.CurrentRef != "" {
+		t.Errorf("export state ref %s after reset removed it, expected none", state.CurrentRef)
+	}
+	if state.State != catalog.ExportStatusSuccess {
+		t.Errorf("export state %s after reset, expected %s", state.State, catalog.ExportStatusSuccess)
+	}
+}
@@ -14,10 +14,16 @@ func (c *cataloger) ResetEntries(ctx context.Context, repository, branch string,
 		return err
 	}
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
 		branchID, err := c.getBranchIDCache(tx, repository, branch)
 		if err != nil {
 			return nil, err
 		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
 		prefixCond := db.Prefix(prefix)
 		_, err = tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND path LIKE $2 AND min_commit=$3`, branchID, prefixCond, MinCommitUncommittedIndicator)
 		return nil, err
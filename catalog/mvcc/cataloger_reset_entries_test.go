@@ -25,7 +25,7 @@ func TestCataloger_ResetEntries_Basics(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("create entry for reset entry test:", err)
 	}
-	if _, err := c.Commit(ctx, repository, branch, "commit file1", "tester", nil); err != nil {
+	if _, err := c.Commit(ctx, repository, branch, "commit file1", "tester", nil, catalog.CommitParams{}); err != nil {
 		t.Fatal("Commit for reset entry test:", err)
 	}
 	if err := c.CreateEntry(ctx, repository, "master", catalog.Entry{
@@ -120,7 +120,7 @@ func TestCataloger_ResetEntries(t *testing.T) {
 			Size:            int64(i) + 1,
 		}, catalog.CreateEntryParams{}))
 	}
-	if _, err := c.Commit(ctx, repository, "master", "commit changes on master", "tester", nil); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "commit changes on master", "tester", nil, catalog.CommitParams{}); err != nil {
 		t.Fatal("Commit for reset entry test:", err)
 	}
 
@@ -135,7 +135,7 @@ func TestCataloger_ResetEntries(t *testing.T) {
 			Size:            int64(i) + 1,
 		}, catalog.CreateEntryParams{}))
 	}
-	if _, err := c.Commit(ctx, repository, "b1", "commit changes on b1", "tester", nil); err != nil {
+	if _, err := c.Commit(ctx, repository, "b1", "commit changes on b1", "tester", nil, catalog.CommitParams{}); err != nil {
 		t.Fatal("Commit for reset entry test:", err)
 	}
 	testutil.Must(t, c.CreateEntry(ctx, repository, "master", catalog.Entry{
@@ -18,10 +18,16 @@ func (c *cataloger) ResetEntry(ctx context.Context, repository, branch string, p
 		return db.ErrNotFound
 	}
 	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
 		branchID, err := c.getBranchIDCache(tx, repository, branch)
 		if err != nil {
 			return nil, err
 		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
 		res, err := tx.Exec(`DELETE FROM catalog_entries WHERE branch_id=$1 AND path=$2 AND min_commit=$3`, branchID, path, MinCommitUncommittedIndicator)
 		if err != nil {
 			return nil, err
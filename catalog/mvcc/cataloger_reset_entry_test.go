@@ -24,7 +24,7 @@ func TestCataloger_ResetEntry(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("create entry for reset entry test:", err)
 	}
-	if _, err := c.Commit(ctx, repository, branch, "commit file1", "tester", nil); err != nil {
+	if _, err := c.Commit(ctx, repository, branch, "commit file1", "tester", nil, catalog.CommitParams{}); err != nil {
 		t.Fatal("Commit for reset entry test:", err)
 	}
 	if err := c.CreateEntry(ctx, repository, "master", catalog.Entry{
@@ -149,7 +149,7 @@ func TestCataloger_ResetEntry_NewToPrevious(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("create entry for reset entry test:", err)
 	}
-	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil, catalog.CommitParams{}); err != nil {
 		t.Fatal("Commit for reset entry test:", err)
 	}
 	const newChecksum = "eeee"
@@ -189,7 +189,7 @@ func TestCataloger_ResetEntry_Committed(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("create entry for reset entry test:", err)
 	}
-	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil, catalog.CommitParams{}); err != nil {
 		t.Fatal("Commit for reset entry test:", err)
 	}
 	err := c.ResetEntry(ctx, repository, "master", "/file1")
@@ -213,7 +213,7 @@ func TestCataloger_ResetEntry_CommittedParentBranch(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("create entry for reset entry test:", err)
 	}
-	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil, catalog.CommitParams{}); err != nil {
 		t.Fatal("Commit for reset entry test:", err)
 	}
 	_, err := c.CreateBranch(ctx, repository, "b1", "master")
@@ -241,7 +241,7 @@ func TestCataloger_ResetEntry_UncommittedDeleteSameBranch(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("create entry for reset entry test:", err)
 	}
-	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil, catalog.CommitParams{}); err != nil {
 		t.Fatal("Commit for reset entry test:", err)
 	}
 	err := c.DeleteEntry(ctx, repository, "master", "/file1")
@@ -275,7 +275,7 @@ func TestCataloger_ResetEntry_UncommittedDeleteParentBranch(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("create entry for reset entry test:", err)
 	}
-	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil, catalog.CommitParams{}); err != nil {
 		t.Fatal("Commit for reset entry test:", err)
 	}
 	if _, err := c.CreateBranch(ctx, repository, "b1", "master"); err != nil {
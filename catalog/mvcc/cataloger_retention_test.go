@@ -124,7 +124,7 @@ func TestCataloger_ScanExpired(t *testing.T) {
 		t.Fatal("Failed to create 0/committed on master", err)
 	}
 
-	if _, err := c.Commit(ctx, repository, "master", "first commit", "tester", catalog.Metadata{}); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "first commit", "tester", catalog.Metadata{}, catalog.CommitParams{}); err != nil {
 		t.Fatal("Failed to commit first commit to master", err)
 	}
 
@@ -136,7 +136,7 @@ func TestCataloger_ScanExpired(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("Failed to update 0/historical on master", err)
 	}
-	if _, err := c.Commit(ctx, repository, "master", "second commit", "tester", catalog.Metadata{}); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "second commit", "tester", catalog.Metadata{}, catalog.CommitParams{}); err != nil {
 		t.Fatal("Failed to commit second commit to master", err)
 	}
 
@@ -149,7 +149,7 @@ func TestCataloger_ScanExpired(t *testing.T) {
 		t.Fatal("Failed to create 0/committed on slow", err)
 	}
 
-	if _, err := c.Commit(ctx, repository, "slow", "first slow commit", "tester", catalog.Metadata{}); err != nil {
+	if _, err := c.Commit(ctx, repository, "slow", "first slow commit", "tester", catalog.Metadata{}, catalog.CommitParams{}); err != nil {
 		t.Fatal("Failed to commit to slow", err)
 	}
 
@@ -162,7 +162,7 @@ func TestCataloger_ScanExpired(t *testing.T) {
 		t.Fatal("Failed to update 0/historical on fast", err)
 	}
 
-	if _, err := c.Commit(ctx, repository, "fast", "first fast commit", "tester", catalog.Metadata{}); err != nil {
+	if _, err := c.Commit(ctx, repository, "fast", "first fast commit", "tester", catalog.Metadata{}, catalog.CommitParams{}); err != nil {
 		t.Fatal("Failed to commit first fast commit", err)
 	}
 	if err := c.CreateEntry(ctx, repository, "fast", catalog.Entry{
@@ -174,7 +174,7 @@ func TestCataloger_ScanExpired(t *testing.T) {
 		t.Fatal("Failed to update 0/historical again on fast", err)
 	}
 
-	if _, err := c.Commit(ctx, repository, "fast", "second fast commit", "tester", catalog.Metadata{}); err != nil {
+	if _, err := c.Commit(ctx, repository, "fast", "second fast commit", "tester", catalog.Metadata{}, catalog.CommitParams{}); err != nil {
 		t.Fatal("Failed to commit second fast commit", err)
 	}
 
@@ -410,7 +410,7 @@ func TestCataloger_ScanExpiredWithDupes(t *testing.T) {
 	}, catalog.CreateEntryParams{}); err != nil {
 		t.Fatal("Failed to create 0/committed on master", err)
 	}
-	if _, err := c.Commit(ctx, repository, "master", "first commit", "tester", catalog.Metadata{}); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "first commit", "tester", catalog.Metadata{}, catalog.CommitParams{}); err != nil {
 		t.Fatal("Failed to commit first commit to master", err)
 	}
 
@@ -520,7 +520,7 @@ func TestCataloger_MarkEntriesExpired(t *testing.T) {
 			}
 		}
 	}
-	if _, err := c.Commit(ctx, repository, "master", "commit ALL the files to expire", "tester", catalog.Metadata{}); err != nil {
+	if _, err := c.Commit(ctx, repository, "master", "commit ALL the files to expire", "tester", catalog.Metadata{}, catalog.CommitParams{}); err != nil {
 		t.Fatalf("failed to commit: %s", err)
 	}
 
@@ -0,0 +1,134 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	revertCommitMessageFormat      = "Revert \"%s\"\n\nThis reverts commit %s."
+	revertRangeCommitMessageFormat = "Revert commits %s..%s"
+)
+
+// Revert commits on branch the changes undoing the commit of reference, or all commits of its
+// branch after sinceReference up to reference when sinceReference is set.  A path conflicts
+// when branch changed it after the reverted commits, or has uncommitted changes to it; on
+// conflicts nothing is reverted and ErrConflictFound is returned.
+func (c *cataloger) Revert(ctx context.Context, repository, branch, reference, sinceReference, committer string) (*catalog.MergeResult, error) {
+	committer, metadata := c.commitAttribution(ctx, committer, nil)
+	fields := ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "committer", IsValid: ValidateCommitter(committer)},
+	}
+	if sinceReference != "" {
+		fields = append(fields, ValidateField{Name: "sinceReference", IsValid: ValidateReference(sinceReference)})
+	}
+	if err := Validate(fields); err != nil {
+		return nil, err
+	}
+
+	mergeResult := &catalog.MergeResult{
+		Summary: make(map[catalog.DifferenceType]int),
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		branchID, err := getBranchID(tx, repository, branch, LockTypeUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("branch: %w", err)
+		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+		sourceID, commitID, err := c.resolveCommit(tx, repository, reference)
+		if err != nil {
+			return nil, fmt.Errorf("reference: %w", err)
+		}
+		var commit struct {
+			BranchName       string   `db:"branch_name"`
+			PreviousCommitID CommitID `db:"previous_commit_id"`
+			Message          string   `db:"message"`
+		}
+		err = tx.Get(&commit, `SELECT b.name AS branch_name, c.previous_commit_id, c.message
+			FROM catalog_commits c JOIN catalog_branches b ON b.id = c.branch_id
+			WHERE c.branch_id = $1 AND c.commit_id = $2`, sourceID, commitID)
+		if err != nil {
+			return nil, fmt.Errorf("get commit: %w", err)
+		}
+		revertedReference := MakeReference(commit.BranchName, commitID)
+
+		var sinceCommitID CommitID
+		var message string
+		if sinceReference == "" {
+			if commit.PreviousCommitID <= 0 {
+				// the first commit of a branch has nothing to diff against
+				return nil, fmt.Errorf("revert commit without parent: %w", catalog.ErrOperationNotPermitted)
+			}
+			sinceCommitID = commit.PreviousCommitID
+			message = fmt.Sprintf(revertCommitMessageFormat, commit.Message, revertedReference)
+		} else {
+			var sinceBranchID int64
+			sinceBranchID, sinceCommitID, err = c.resolveCommit(tx, repository, sinceReference)
+			if err != nil {
+				return nil, fmt.Errorf("since reference: %w", err)
+			}
+			if sinceBranchID != sourceID || sinceCommitID >= commitID {
+				return nil, fmt.Errorf("since reference must be an earlier commit of %s: %w", commit.BranchName, catalog.ErrInvalidValue)
+			}
+			message = fmt.Sprintf(revertRangeCommitMessageFormat, MakeReference(commit.BranchName, sinceCommitID), revertedReference)
+		}
+
+		changes, err := commitChanges(tx, repository, sourceID, sinceCommitID, commitID)
+		if err != nil {
+			return nil, err
+		}
+		for i, change := range changes {
+			changes[i] = change.inverse()
+		}
+		changes, err = checkChangesOnBranch(tx, branchID, changes, mergeResult)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 {
+			return nil, catalog.ErrNoDifferenceWasFound
+		}
+
+		previousMaxCommitID, err := getLastCommitIDByBranchID(tx, branchID)
+		if err != nil {
+			return nil, fmt.Errorf("last commit id: %w", err)
+		}
+		nextCommitID, err := getNextCommitID(tx)
+		if err != nil {
+			return nil, fmt.Errorf("next commit id: %w", err)
+		}
+		for i := 0; i < len(changes); i += MergeBatchSize {
+			end := i + MergeBatchSize
+			if end > len(changes) {
+				end = len(changes)
+			}
+			if err := applyChangesToBranch(tx, branchID, previousMaxCommitID, nextCommitID, changes[i:end]); err != nil {
+				return nil, err
+			}
+		}
+
+		var creationDate time.Time
+		if err := tx.GetPrimitive(&creationDate,
+			`INSERT INTO catalog_commits (branch_id,commit_id,committer,message,creation_date,metadata,merge_type,previous_commit_id)
+			VALUES ($1,$2,$3,$4,transaction_timestamp(),$5,$6,$7)
+			RETURNING creation_date`,
+			branchID, nextCommitID, committer, message, metadata, RelationTypeNone, previousMaxCommitID,
+		); err != nil {
+			return nil, fmt.Errorf("insert commit: %w", err)
+		}
+		mergeResult.Reference = MakeReference(branch, nextCommitID)
+		return nil, nil
+	}, c.txOpts(ctx, db.ReadCommitted())...)
+	return mergeResult, err
+}
@@ -0,0 +1,85 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_Revert(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	for i := 0; i < 4; i++ {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "file"+strconv.Itoa(i), nil, "")
+	}
+	baseLog, err := c.Commit(ctx, repository, "master", "base", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit base", err)
+	baseFile2, err := c.GetEntry(ctx, repository, baseLog.Reference, "file2", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get base file2", err)
+
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file5", nil, "")
+	testutil.MustDo(t, "delete file1", c.DeleteEntry(ctx, repository, "master", "file1"))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "seed1")
+	revertedLog, err := c.Commit(ctx, repository, "master", "change files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit changes", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file6", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "add file6", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit file6", err)
+
+	res, err := c.Revert(ctx, repository, "master", revertedLog.Reference, "", "reverter")
+	testutil.MustDo(t, "revert", err)
+	if res.Summary[catalog.DifferenceTypeAdded] != 1 || res.Summary[catalog.DifferenceTypeRemoved] != 1 || res.Summary[catalog.DifferenceTypeChanged] != 1 {
+		t.Errorf("revert summary %v, expected 1 added, removed and changed", res.Summary)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file5", false)
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file1", true)
+	testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file6", true)
+	file2, err := c.GetEntry(ctx, repository, "master:HEAD", "file2", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get reverted file2", err)
+	if file2.Checksum != baseFile2.Checksum {
+		t.Errorf("reverted file2 has checksum %s, expected base checksum %s", file2.Checksum, baseFile2.Checksum)
+	}
+
+	t.Run("already reverted", func(t *testing.T) {
+		_, err := c.Revert(ctx, repository, "master", revertedLog.Reference, "", "reverter")
+		if !errors.Is(err, catalog.ErrNoDifferenceWasFound) {
+			t.Errorf("revert again err=%v, expected %s", err, catalog.ErrNoDifferenceWasFound)
+		}
+	})
+
+	t.Run("modified later", func(t *testing.T) {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "seed2")
+		changeLog, err := c.Commit(ctx, repository, "master", "change file3", "tester", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit file3", err)
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", "file3", nil, "seed3")
+		_, err = c.Commit(ctx, repository, "master", "change file3 again", "tester", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit file3 again", err)
+
+		res, err := c.Revert(ctx, repository, "master", changeLog.Reference, "", "reverter")
+		if !errors.Is(err, catalog.ErrConflictFound) {
+			t.Fatalf("revert of modified file err=%v, expected %s", err, catalog.ErrConflictFound)
+		}
+		if res.Summary[catalog.DifferenceTypeConflict] != 1 {
+			t.Errorf("revert conflict summary %v, expected a conflict", res.Summary)
+		}
+	})
+
+	t.Run("range", func(t *testing.T) {
+		_, err := c.Revert(ctx, repository, "master", "master", baseLog.Reference, "reverter")
+		testutil.MustDo(t, "revert range", err)
+		for i := 0; i < 4; i++ {
+			testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file"+strconv.Itoa(i), true)
+		}
+		testCatalogerGetEntry(t, ctx, c, repository, "master:HEAD", "file6", false)
+
+		_, err = c.Revert(ctx, repository, "master", baseLog.Reference, "master", "reverter")
+		if !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Errorf("revert backwards range err=%v, expected %s", err, catalog.ErrInvalidValue)
+		}
+	})
+}
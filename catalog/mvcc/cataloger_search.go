@@ -0,0 +1,198 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	SearchCommitsMaxLimit = 1000
+	SearchEntriesMaxLimit = 1000
+)
+
+// searchTerms splits a free-text query into the words that must all be matched
+func searchTerms(query string) []string {
+	return strings.Fields(query)
+}
+
+// sqContainsAll matches rows where column contains all terms, ignoring case
+func sqContainsAll(column string, terms []string) sq.And {
+	conds := make(sq.And, len(terms))
+	for i, term := range terms {
+		conds[i] = sq.ILike{column: db.Contains(term)}
+	}
+	return conds
+}
+
+func (c *cataloger) SearchCommits(ctx context.Context, repository, reference string, query string, after string, limit int) ([]*catalog.CommitLog, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateOptionalString(reference, IsValidReference)},
+		{Name: "query", IsValid: ValidateSearchQuery(query)},
+		{Name: "after", IsValid: ValidateOptionalString(after, IsValidCommitReference)},
+	}); err != nil {
+		return nil, false, err
+	}
+	// commit IDs are allocated in order across branches, so commits older than after have
+	// lower IDs
+	afterCommitID := MaxCommitID
+	if after != "" {
+		afterRef, err := ParseRef(after)
+		if err != nil {
+			return nil, false, err
+		}
+		afterCommitID = afterRef.CommitID
+	}
+	if limit < 0 || limit > SearchCommitsMaxLimit {
+		limit = SearchCommitsMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		commitsQ := psql.Select("b_name.name as branch_name", "c.commit_id", "c.previous_commit_id", "c.committer",
+			"c.message", "c.creation_date", "c.metadata",
+			"COALESCE(bb.name,'') as merge_source_branch_name", "COALESCE(c.merge_source_commit,0) as merge_source_commit").
+			From("catalog_commits c").
+			Join("catalog_branches b_name ON c.branch_id = b_name.id").
+			LeftJoin("catalog_branches bb ON bb.id = c.merge_source_branch AND NOT c.squash").
+			Where(sq.Lt{"c.commit_id": afterCommitID}).
+			Where(sqContainsAll("c.message", searchTerms(query))).
+			OrderBy("c.commit_id DESC").
+			Limit(uint64(limit) + 1)
+		if reference == "" {
+			repoID, err := c.getRepositoryIDCache(tx, repository)
+			if err != nil {
+				return nil, err
+			}
+			commitsQ = commitsQ.Where(sq.Eq{"b_name.repository_id": repoID})
+		} else {
+			ref, err := c.resolveRef(tx, repository, reference)
+			if err != nil {
+				return nil, err
+			}
+			branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+			if err != nil {
+				return nil, err
+			}
+			// search from the referenced commit, or from the branch head
+			fromCommitID := MaxCommitID
+			if ref.CommitID > 0 {
+				fromCommitID = ref.CommitID
+			}
+			cte, err := commitsLineageCTE(tx, branchID, fromCommitID)
+			if err != nil {
+				return nil, err
+			}
+			commitsQ = commitsQ.Prefix(cte).
+				Join("lineage_graph l ON c.branch_id = l.branch_id AND c.commit_id <= l.commit_id")
+		}
+		commitsSQL, args, err := commitsQ.ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var rawCommits []commitLogRaw
+		if err := tx.Select(&rawCommits, commitsSQL, args...); err != nil {
+			return nil, err
+		}
+		return convertRawCommits(rawCommits), nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	commits := res.([]*catalog.CommitLog)
+	hasMore := paginateSlice(&commits, limit)
+	return commits, hasMore, nil
+}
+
+func (c *cataloger) SearchEntries(ctx context.Context, repository, reference string, query string, after string, limit int) ([]*catalog.Entry, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "query", IsValid: ValidateSearchQuery(query)},
+	}); err != nil {
+		return nil, false, err
+	}
+	ref, err := c.resolveRef(c.db.WithContext(ctx), repository, reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > SearchEntriesMaxLimit {
+		limit = SearchEntriesMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		lineage, err := getLineage(tx, branchID, ref.CommitID)
+		if err != nil {
+			return nil, fmt.Errorf("get lineage: %w", err)
+		}
+		entriesSQL, args, err := psql.
+			Select("path", "physical_address", "creation_date", "size", "checksum", "metadata",
+				"content_type", "content_encoding", "cache_control").
+			FromSelect(sqEntriesLineage(branchID, ref.CommitID, lineage), "entries").
+			Where(sq.And{sqContainsAll("path", searchTerms(query)), sq.Eq{"is_deleted": false}, sq.Gt{"path": after}}).
+			OrderBy("path").
+			Limit(uint64(limit) + 1).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build sql: %w", err)
+		}
+		var entries []*catalog.Entry
+		if err := tx.Select(&entries, entriesSQL, args...); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	entries := res.([]*catalog.Entry)
+	hasMore := paginateSlice(&entries, limit)
+	return entries, hasMore, nil
+}
+
+func (c *cataloger) SearchEntriesByMetadata(ctx context.Context, repository, reference string, key, value string, prefix, after string, limit int) ([]*catalog.Entry, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+		{Name: "key", IsValid: func() bool { return IsNonEmptyString(key) }},
+	}); err != nil {
+		return nil, false, err
+	}
+	ref, err := c.resolveRef(c.db.WithContext(ctx), repository, reference)
+	if err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > SearchEntriesMaxLimit {
+		limit = SearchEntriesMaxLimit
+	}
+	// both conditions use the metadata index
+	condition := "metadata ? $1 AND path LIKE $2"
+	var conditionValue interface{} = key
+	if value != "" {
+		condition = "metadata @> $1::jsonb AND path LIKE $2"
+		conditionValue = catalog.Metadata{key: value}
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		return findEntries(tx, branchID, ref.CommitID, condition, []interface{}{conditionValue, db.Prefix(prefix)}, after, limit,
+			func(entry *catalog.Entry) bool {
+				entryValue, ok := entry.Metadata[key]
+				return ok && (value == "" || entryValue == value) && strings.HasPrefix(entry.Path, prefix)
+			})
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	entries := res.([]*catalog.Entry)
+	hasMore := paginateSlice(&entries, limit)
+	return entries, hasMore, nil
+}
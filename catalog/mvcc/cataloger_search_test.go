@@ -0,0 +1,270 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_SearchCommits(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	setupListCommitsByBranchData(t, ctx, c, repository, "master")
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	setupListCommitsByBranchData(t, ctx, c, repository, "branch1")
+
+	tests := []struct {
+		name        string
+		reference   string
+		query       string
+		limit       int
+		wantMessage []string
+		wantMore    bool
+		wantErr     error
+	}{
+		{
+			name:        "repository",
+			query:       "COMMIT2",
+			limit:       -1,
+			wantMessage: []string{"commit2 on branch branch1", "commit2 on branch master"},
+		},
+		{
+			name:        "all words",
+			query:       "commit2 branch1",
+			limit:       -1,
+			wantMessage: []string{"commit2 on branch branch1"},
+		},
+		{
+			name:        "branch",
+			reference:   "master",
+			query:       "commit2",
+			limit:       -1,
+			wantMessage: []string{"commit2 on branch master"},
+		},
+		{
+			name:        "limit",
+			query:       "commit",
+			limit:       2,
+			wantMessage: []string{"commit3 on branch branch1", "commit2 on branch branch1"},
+			wantMore:    true,
+		},
+		{
+			name:        "no match",
+			query:       "nothing",
+			limit:       -1,
+			wantMessage: []string{},
+		},
+		{
+			name:    "empty query",
+			query:   " ",
+			limit:   -1,
+			wantErr: catalog.ErrInvalidValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits, more, err := c.SearchCommits(ctx, repository, tt.reference, tt.query, "", tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SearchCommits() err=%v, expected=%v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			messages := make([]string, len(commits))
+			for i, commit := range commits {
+				messages[i] = commit.Message
+			}
+			if len(messages) != len(tt.wantMessage) {
+				t.Fatalf("SearchCommits() messages=%v, expected=%v", messages, tt.wantMessage)
+			}
+			for i := range messages {
+				if messages[i] != tt.wantMessage[i] {
+					t.Fatalf("SearchCommits() messages=%v, expected=%v", messages, tt.wantMessage)
+				}
+			}
+			if more != tt.wantMore {
+				t.Fatalf("SearchCommits() more=%t, expected=%t", more, tt.wantMore)
+			}
+		})
+	}
+
+	t.Run("after", func(t *testing.T) {
+		var messages []string
+		after := ""
+		for {
+			commits, more, err := c.SearchCommits(ctx, repository, "", "commit", after, 2)
+			testutil.MustDo(t, "search commits", err)
+			for _, commit := range commits {
+				messages = append(messages, commit.Message)
+			}
+			if !more {
+				break
+			}
+			after = commits[len(commits)-1].Reference
+		}
+		all, _, err := c.SearchCommits(ctx, repository, "", "commit", "", -1)
+		testutil.MustDo(t, "search all commits", err)
+		if len(messages) != len(all) {
+			t.Fatalf("SearchCommits() paged messages=%v, expected %d commits", messages, len(all))
+		}
+		for i, commit := range all {
+			if messages[i] != commit.Message {
+				t.Fatalf("SearchCommits() paged messages=%v, expected message %d to be %s", messages, i, commit.Message)
+			}
+		}
+	})
+
+	t.Run("after branch", func(t *testing.T) {
+		_, _, err := c.SearchCommits(ctx, repository, "", "commit", "master", -1)
+		if !errors.Is(err, catalog.ErrInvalidValue) {
+			t.Fatalf("SearchCommits() err=%v, expected=%v", err, catalog.ErrInvalidValue)
+		}
+	})
+}
+
+func TestCataloger_SearchEntries(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	for _, p := range []string{"data/2020/events.parquet", "data/2020/users.csv", "logs/2020/events.log"} {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", p, nil, "")
+	}
+	_, err := c.Commit(ctx, repository, "master", "add data", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	testutil.MustDo(t, "delete entry", c.DeleteEntry(ctx, repository, "master", "logs/2020/events.log"))
+
+	tests := []struct {
+		name      string
+		reference string
+		query     string
+		after     string
+		limit     int
+		wantPaths []string
+		wantMore  bool
+	}{
+		{
+			name:      "uncommitted",
+			reference: "master",
+			query:     "EVENTS",
+			limit:     -1,
+			wantPaths: []string{"data/2020/events.parquet"},
+		},
+		{
+			name:      "committed",
+			reference: "master:HEAD",
+			query:     "events",
+			limit:     -1,
+			wantPaths: []string{"data/2020/events.parquet", "logs/2020/events.log"},
+		},
+		{
+			name:      "all words",
+			reference: "master",
+			query:     "2020 users",
+			limit:     -1,
+			wantPaths: []string{"data/2020/users.csv"},
+		},
+		{
+			name:      "paginate",
+			reference: "master",
+			query:     "data",
+			limit:     1,
+			wantPaths: []string{"data/2020/events.parquet"},
+			wantMore:  true,
+		},
+		{
+			name:      "after",
+			reference: "master",
+			query:     "data",
+			after:     "data/2020/events.parquet",
+			limit:     1,
+			wantPaths: []string{"data/2020/users.csv"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, more, err := c.SearchEntries(ctx, repository, tt.reference, tt.query, tt.after, tt.limit)
+			testutil.MustDo(t, "search entries", err)
+			paths := make([]string, len(entries))
+			for i, entry := range entries {
+				paths[i] = entry.Path
+			}
+			if len(paths) != len(tt.wantPaths) {
+				t.Fatalf("SearchEntries() paths=%v, expected=%v", paths, tt.wantPaths)
+			}
+			for i := range paths {
+				if paths[i] != tt.wantPaths[i] {
+					t.Fatalf("SearchEntries() paths=%v, expected=%v", paths, tt.wantPaths)
+				}
+			}
+			if more != tt.wantMore {
+				t.Fatalf("SearchEntries() more=%t, expected=%t", more, tt.wantMore)
+			}
+		})
+	}
+}
+
+func TestCataloger_SearchEntriesByMetadata(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	entries := map[string]catalog.Metadata{
+		"clicks/1.parquet": {"dataset": "clicks", "pii": "false"},
+		"clicks/2.parquet": {"dataset": "clicks", "pii": "true"},
+		"users/1.csv":      {"dataset": "users", "pii": "true"},
+		"users/2.csv":      nil,
+	}
+	for p, metadata := range entries {
+		testCatalogerCreateEntry(t, ctx, c, repository, "master", p, metadata, "")
+	}
+	_, err := c.Commit(ctx, repository, "master", "add data", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	// uncommitted metadata changes replace the committed metadata
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "clicks/2.parquet", catalog.Metadata{"dataset": "clicks", "pii": "false"}, "")
+
+	tests := []struct {
+		name      string
+		reference string
+		key       string
+		value     string
+		prefix    string
+		after     string
+		limit     int
+		wantPaths []string
+		wantMore  bool
+	}{
+		{name: "key and value", reference: "master", key: "dataset", value: "clicks", limit: -1, wantPaths: []string{"clicks/1.parquet", "clicks/2.parquet"}},
+		{name: "key only", reference: "master", key: "pii", limit: -1, wantPaths: []string{"clicks/1.parquet", "clicks/2.parquet", "users/1.csv"}},
+		{name: "uncommitted", reference: "master", key: "pii", value: "true", limit: -1, wantPaths: []string{"users/1.csv"}},
+		{name: "committed", reference: "master:HEAD", key: "pii", value: "true", limit: -1, wantPaths: []string{"clicks/2.parquet", "users/1.csv"}},
+		{name: "prefix", reference: "master", key: "pii", prefix: "users/", limit: -1, wantPaths: []string{"users/1.csv"}},
+		{name: "paginate", reference: "master", key: "pii", limit: 2, wantPaths: []string{"clicks/1.parquet", "clicks/2.parquet"}, wantMore: true},
+		{name: "after", reference: "master", key: "pii", after: "clicks/2.parquet", limit: 2, wantPaths: []string{"users/1.csv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, more, err := c.SearchEntriesByMetadata(ctx, repository, tt.reference, tt.key, tt.value, tt.prefix, tt.after, tt.limit)
+			testutil.MustDo(t, "search entries by metadata", err)
+			paths := make([]string, len(entries))
+			for i, entry := range entries {
+				paths[i] = entry.Path
+			}
+			if len(paths) != len(tt.wantPaths) || more != tt.wantMore {
+				t.Fatalf("SearchEntriesByMetadata() paths=%v more=%t, expected=%v more=%t", paths, more, tt.wantPaths, tt.wantMore)
+			}
+			for i := range paths {
+				if paths[i] != tt.wantPaths[i] {
+					t.Fatalf("SearchEntriesByMetadata() paths=%v, expected=%v", paths, tt.wantPaths)
+				}
+			}
+		})
+	}
+
+	_, _, err = c.SearchEntriesByMetadata(ctx, repository, "master", "", "clicks", "", "", -1)
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("search without key err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+}
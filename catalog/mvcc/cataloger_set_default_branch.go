@@ -0,0 +1,33 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func (c *cataloger) SetDefaultBranch(ctx context.Context, repository, branch string) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+	}); err != nil {
+		return err
+	}
+	if branch == catalog.DefaultImportBranchName {
+		return fmt.Errorf("set import branch as default: %w", catalog.ErrOperationNotPermitted)
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := getBranchID(tx, repository, branch, LockTypeShare)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`UPDATE catalog_repositories SET default_branch = $2 WHERE name = $1`, repository, branchID)
+		if err != nil {
+			return nil, fmt.Errorf("set default branch: %w", err)
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
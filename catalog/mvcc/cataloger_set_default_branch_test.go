@@ -0,0 +1,49 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+func TestCataloger_SetDefaultBranch(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t, WithCacheEnabled(false))
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerBranch(t, ctx, c, repository, "main", "master")
+
+	tests := []struct {
+		name       string
+		repository string
+		branch     string
+		wantErr    error
+	}{
+		{name: "unknown repository", repository: "no-repository", branch: "main", wantErr: db.ErrNotFound},
+		{name: "unknown branch", repository: repository, branch: "no-branch", wantErr: db.ErrNotFound},
+		{name: "import branch", repository: repository, branch: catalog.DefaultImportBranchName, wantErr: catalog.ErrOperationNotPermitted},
+		{name: "branch", repository: repository, branch: "main"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.SetDefaultBranch(ctx, tt.repository, tt.branch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetDefaultBranch() err=%v, expected=%v", err, tt.wantErr)
+			}
+		})
+	}
+
+	repo, err := c.GetRepository(ctx, repository)
+	if err != nil {
+		t.Fatal("GetRepository()", err)
+	}
+	if repo.DefaultBranch != "main" {
+		t.Fatalf("GetRepository() default branch=%s, expected main", repo.DefaultBranch)
+	}
+	err = c.DeleteBranch(ctx, repository, "main")
+	if !errors.Is(err, catalog.ErrOperationNotPermitted) {
+		t.Fatalf("DeleteBranch() default branch err=%v, expected=%v", err, catalog.ErrOperationNotPermitted)
+	}
+}
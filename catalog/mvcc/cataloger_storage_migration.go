@@ -0,0 +1,329 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	StorageNamespaceMigrationBatchSize    = 1000
+	StorageNamespaceMigrationMaxBatchSize = 10000
+)
+
+type storageNamespaceMigration struct {
+	catalog.StorageNamespaceMigration
+	RepositoryID    int    `db:"repository_id"`
+	PhysicalAddress string `db:"physical_address"`
+}
+
+// migrationObject is an object referenced by the entries of a repository being migrated
+type migrationObject struct {
+	PhysicalAddress string `db:"physical_address"`
+	Checksum        string `db:"checksum"`
+	Size            int64  `db:"size"`
+	MigratedAddress string
+}
+
+// sqlMigrationObjects selects the objects referenced by the entries and the trash of repository
+// $1 after physical address $2 that were not migrated yet
+const sqlMigrationObjects = `SELECT a.physical_address, min(a.checksum) AS checksum, max(a.size) AS size
+	FROM (SELECT e.physical_address, e.checksum, e.size
+			FROM catalog_entries e JOIN catalog_branches b ON b.id = e.branch_id
+			WHERE b.repository_id = $1
+		UNION ALL
+		SELECT t.physical_address, t.checksum, t.size
+			FROM catalog_trash t JOIN catalog_branches b ON b.id = t.branch_id
+			WHERE b.repository_id = $1) a
+	WHERE a.physical_address COLLATE "C" > $2
+		AND NOT EXISTS (SELECT 1 FROM catalog_storage_namespace_migration_objects m
+			WHERE m.repository_id = $1 AND (m.physical_address = a.physical_address OR m.migrated_address = a.physical_address))
+	GROUP BY a.physical_address
+	ORDER BY a.physical_address COLLATE "C"`
+
+// MigrateStorageNamespace copies the objects of repository to storageNamespace.  Each batch of
+// objects is copied and verified, then their physical addresses are updated together with the
+// progress of the migration.  Objects written during the migration are migrated by a final
+// pass over the entries of repository, which should not be written while it completes.
+func (c *cataloger) MigrateStorageNamespace(ctx context.Context, repository, storageNamespace string, params catalog.MigrateStorageNamespaceParams) (*catalog.StorageNamespaceMigration, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "storageNamespace", IsValid: ValidateStorageNamespace(storageNamespace)},
+	}); err != nil {
+		return nil, err
+	}
+	if params.CopyObject == nil || params.ChecksumObject == nil {
+		return nil, fmt.Errorf("migrate objects: %w", catalog.ErrFeatureNotSupported)
+	}
+	batchSize := params.BatchSize
+	if batchSize <= 0 || batchSize > StorageNamespaceMigrationMaxBatchSize {
+		batchSize = StorageNamespaceMigrationBatchSize
+	}
+	migration, err := c.startStorageNamespaceMigration(ctx, repository, storageNamespace)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		objects, err := c.nextMigrationObjects(ctx, migration, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(objects) == 0 {
+			completed, err := c.completeStorageNamespaceMigration(ctx, migration)
+			if err != nil {
+				return nil, err
+			}
+			if completed {
+				migration.Completed = true
+				return &migration.StorageNamespaceMigration, nil
+			}
+			// objects were written before the last physical address, start another pass
+			continue
+		}
+		for _, object := range objects {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			object.MigratedAddress, err = params.CopyObject(migration.SourceNamespace, object.PhysicalAddress, migration.StorageNamespace)
+			if err != nil {
+				return nil, fmt.Errorf("copy object %s: %w", object.PhysicalAddress, err)
+			}
+			if err := verifyMigrationObject(params.ChecksumObject, migration, object); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.updateMigrationObjects(ctx, migration, objects); err != nil {
+			return nil, err
+		}
+		if params.Progress != nil {
+			params.Progress(&migration.StorageNamespaceMigration)
+		}
+	}
+}
+
+func (c *cataloger) GetStorageNamespaceMigration(ctx context.Context, repository string) (*catalog.StorageNamespaceMigration, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		return getStorageNamespaceMigration(tx, repoID, LockTypeNone)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	migration := res.(*storageNamespaceMigration).StorageNamespaceMigration
+	migration.Repository = repository
+	return &migration, nil
+}
+
+// startStorageNamespaceMigration returns the migration of repository to storageNamespace,
+// starting it unless it is in progress
+func (c *cataloger) startStorageNamespaceMigration(ctx context.Context, repository, storageNamespace string) (*storageNamespaceMigration, error) {
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		migration, err := getStorageNamespaceMigration(tx, repoID, LockTypeUpdate)
+		if err == nil {
+			if migration.StorageNamespace != storageNamespace {
+				return nil, fmt.Errorf("migration to %s in progress: %w", migration.StorageNamespace, catalog.ErrOperationNotPermitted)
+			}
+			return migration, nil
+		}
+		if !errors.Is(err, catalog.ErrMigrationNotFound) {
+			return nil, err
+		}
+		// the repository is read without the cache, its storage namespace may have just changed
+		repo, err := getRepository(tx, repository)
+		if err != nil {
+			return nil, fmt.Errorf("get repository: %w", err)
+		}
+		if repo.StorageNamespace == storageNamespace {
+			return nil, fmt.Errorf("storage namespace unchanged: %w", catalog.ErrInvalidValue)
+		}
+		_, err = tx.Exec(`INSERT INTO catalog_storage_namespace_migrations (repository_id, source_namespace, storage_namespace)
+			VALUES ($1, $2, $3)`, repoID, repo.StorageNamespace, storageNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("start migration: %w", err)
+		}
+		return getStorageNamespaceMigration(tx, repoID, LockTypeNone)
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	migration := res.(*storageNamespaceMigration)
+	migration.Repository = repository
+	return migration, nil
+}
+
+func (c *cataloger) nextMigrationObjects(ctx context.Context, migration *storageNamespaceMigration, limit int) ([]*migrationObject, error) {
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var objects []*migrationObject
+		err := tx.Select(&objects, sqlMigrationObjects+` LIMIT $3`,
+			migration.RepositoryID, migration.PhysicalAddress, limit)
+		if err != nil {
+			return nil, fmt.Errorf("select objects: %w", err)
+		}
+		return objects, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.([]*migrationObject), nil
+}
+
+// updateMigrationObjects points the entries of migrated objects at their migrated addresses and
+// records the progress of migration
+func (c *cataloger) updateMigrationObjects(ctx context.Context, migration *storageNamespaceMigration, objects []*migrationObject) error {
+	addresses := make([]string, len(objects))
+	migratedAddresses := make([]string, len(objects))
+	for i, object := range objects {
+		addresses[i] = object.PhysicalAddress
+		migratedAddresses[i] = object.MigratedAddress
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		current, err := getStorageNamespaceMigration(tx, migration.RepositoryID, LockTypeUpdate)
+		if err != nil {
+			return nil, err
+		}
+		if current.PhysicalAddress != migration.PhysicalAddress {
+			return nil, fmt.Errorf("migration progressed concurrently: %w", catalog.ErrConflictFound)
+		}
+		_, err = tx.Exec(`INSERT INTO catalog_storage_namespace_migration_objects (repository_id, physical_address, migrated_address)
+			SELECT $1, a.physical_address, a.migrated_address FROM unnest($2::text[], $3::text[]) AS a (physical_address, migrated_address)
+			ON CONFLICT DO NOTHING`,
+			migration.RepositoryID, addresses, migratedAddresses)
+		if err != nil {
+			return nil, fmt.Errorf("insert migrated objects: %w", err)
+		}
+		for _, table := range []string{"catalog_entries", "catalog_trash"} {
+			_, err = tx.Exec(`UPDATE `+table+` t SET physical_address = m.migrated_address
+				FROM catalog_storage_namespace_migration_objects m, catalog_branches b
+				WHERE m.repository_id = $1 AND m.physical_address = ANY($2::text[]) AND m.physical_address <> m.migrated_address
+					AND b.repository_id = $1 AND t.branch_id = b.id AND t.physical_address = m.physical_address`,
+				migration.RepositoryID, addresses)
+			if err != nil {
+				return nil, fmt.Errorf("update %s: %w", table, err)
+			}
+		}
+		_, err = tx.Exec(`UPDATE catalog_object_dedup d SET physical_address = m.migrated_address
+			FROM catalog_storage_namespace_migration_objects m
+			WHERE m.repository_id = $1 AND m.physical_address = ANY($2::text[]) AND m.physical_address <> m.migrated_address
+				AND d.repository_id = $1 AND d.physical_address = m.physical_address`,
+			migration.RepositoryID, addresses)
+		if err != nil {
+			return nil, fmt.Errorf("update dedup: %w", err)
+		}
+		_, err = tx.Exec(`UPDATE catalog_storage_namespace_migrations
+			SET physical_address = $2, migrated_objects = migrated_objects + $3, updated_date = now()
+			WHERE repository_id = $1`,
+			migration.RepositoryID, addresses[len(addresses)-1], len(objects))
+		if err != nil {
+			return nil, fmt.Errorf("update migration: %w", err)
+		}
+		return getStorageNamespaceMigration(tx, migration.RepositoryID, LockTypeNone)
+	}, c.txOpts(ctx, db.ReadCommitted())...)
+	if err != nil {
+		return err
+	}
+	repository := migration.Repository
+	*migration = *res.(*storageNamespaceMigration)
+	migration.Repository = repository
+	return nil
+}
+
+// completeStorageNamespaceMigration moves the repository of migration to its storage namespace
+// when all its objects were migrated.  Otherwise it restarts migration from the first physical
+// address and returns false.
+func (c *cataloger) completeStorageNamespaceMigration(ctx context.Context, migration *storageNamespaceMigration) (bool, error) {
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		current, err := getStorageNamespaceMigration(tx, migration.RepositoryID, LockTypeUpdate)
+		if err != nil {
+			return nil, err
+		}
+		if current.PhysicalAddress != migration.PhysicalAddress {
+			return nil, fmt.Errorf("migration progressed concurrently: %w", catalog.ErrConflictFound)
+		}
+		var remaining bool
+		err = tx.GetPrimitive(&remaining, `SELECT EXISTS (`+sqlMigrationObjects+`)`, migration.RepositoryID, "")
+		if err != nil {
+			return nil, fmt.Errorf("remaining objects: %w", err)
+		}
+		if remaining {
+			_, err := tx.Exec(`UPDATE catalog_storage_namespace_migrations SET physical_address = '', updated_date = now()
+				WHERE repository_id = $1`, migration.RepositoryID)
+			if err != nil {
+				return nil, fmt.Errorf("restart migration: %w", err)
+			}
+			return false, nil
+		}
+		_, err = tx.Exec(`UPDATE catalog_repositories SET storage_namespace = $2 WHERE id = $1`,
+			migration.RepositoryID, migration.StorageNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("update storage namespace: %w", err)
+		}
+		_, err = tx.Exec(`DELETE FROM catalog_storage_namespace_migrations WHERE repository_id = $1`, migration.RepositoryID)
+		if err != nil {
+			return nil, fmt.Errorf("delete migration: %w", err)
+		}
+		return true, nil
+	}, c.txOpts(ctx, db.ReadCommitted())...)
+	if err != nil {
+		return false, err
+	}
+	completed := res.(bool)
+	if !completed {
+		migration.PhysicalAddress = ""
+	}
+	return completed, nil
+}
+
+// verifyMigrationObject compares the checksum of the migrated copy of object with the checksum of
+// its entries, and with the checksum of the source object when they differ: entries of multipart
+// uploads and imports are not checksummed by content
+func verifyMigrationObject(checksumObject catalog.ChecksumObjectFunc, migration *storageNamespaceMigration, object *migrationObject) error {
+	checksum, err := checksumObject(migration.StorageNamespace, object.MigratedAddress, object.Size)
+	if err != nil {
+		return fmt.Errorf("checksum object %s: %w", object.MigratedAddress, err)
+	}
+	if checksum == object.Checksum {
+		return nil
+	}
+	sourceChecksum, err := checksumObject(migration.SourceNamespace, object.PhysicalAddress, object.Size)
+	if err != nil {
+		return fmt.Errorf("checksum object %s: %w", object.PhysicalAddress, err)
+	}
+	if checksum != sourceChecksum {
+		return fmt.Errorf("object %s: %w", object.PhysicalAddress, catalog.ErrChecksumMismatch)
+	}
+	return nil
+}
+
+func getStorageNamespaceMigration(tx db.Tx, repositoryID int, lockType LockType) (*storageNamespaceMigration, error) {
+	query, err := formatSQLWithLockType(`SELECT repository_id, source_namespace, storage_namespace, physical_address,
+			migrated_objects, started_date, updated_date
+		FROM catalog_storage_namespace_migrations
+		WHERE repository_id = $1`, lockType)
+	if err != nil {
+		return nil, err
+	}
+	var migration storageNamespaceMigration
+	err = tx.Get(&migration, query, repositoryID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, catalog.ErrMigrationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get migration: %w", err)
+	}
+	return &migration, nil
+}
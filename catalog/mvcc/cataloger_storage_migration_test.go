@@ -0,0 +1,136 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+// testMigrationObjects holds the checksums of objects by namespace and address, copying objects
+// at fully qualified addresses to new addresses
+type testMigrationObjects map[string]string
+
+func (o testMigrationObjects) params(batchSize int) catalog.MigrateStorageNamespaceParams {
+	return catalog.MigrateStorageNamespaceParams{
+		CopyObject: func(sourceNamespace, sourceAddress, destinationNamespace string) (string, error) {
+			address := sourceAddress
+			if strings.Contains(address, "://") {
+				address = "migrated-" + address[strings.LastIndex(address, "/")+1:]
+			}
+			o[destinationNamespace+"|"+address] = o[sourceNamespace+"|"+sourceAddress]
+			return address, nil
+		},
+		ChecksumObject: func(namespace, address string, _ int64) (string, error) {
+			return o[namespace+"|"+address], nil
+		},
+		BatchSize: batchSize,
+	}
+}
+
+func TestCataloger_MigrateStorageNamespace(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	entries := []catalog.Entry{
+		{Path: "file1", PhysicalAddress: "addr1", Checksum: "c1", Size: 1},
+		{Path: "file2", PhysicalAddress: "addr2", Checksum: "c2", Size: 2},
+		{Path: "imported", PhysicalAddress: "s3://imported-bucket/path/obj3", Checksum: "c3", Size: 3},
+		// multipart uploads are not checksummed by content
+		{Path: "multipart", PhysicalAddress: "addr4", Checksum: "c4-2", Size: 4},
+	}
+	objects := testMigrationObjects{
+		"s3://bucket|addr1":                          "c1",
+		"s3://bucket|addr2":                          "c2",
+		"s3://bucket|s3://imported-bucket/path/obj3": "c3",
+		"s3://bucket|addr4":                          "c4",
+	}
+	for _, entry := range entries[:2] {
+		testutil.MustDo(t, "create entry", c.CreateEntry(ctx, repository, "master", entry, catalog.CreateEntryParams{}))
+	}
+	_, err := c.Commit(ctx, repository, "master", "commit", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	for _, entry := range entries[2:] {
+		testutil.MustDo(t, "create entry", c.CreateEntry(ctx, repository, "master", entry, catalog.CreateEntryParams{}))
+	}
+
+	_, err = c.MigrateStorageNamespace(ctx, repository, "s3://bucket", objects.params(0))
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Fatalf("migrate to the same namespace err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+
+	migration, err := c.MigrateStorageNamespace(ctx, repository, "s3://new-bucket", objects.params(1))
+	testutil.MustDo(t, "migrate", err)
+	if !migration.Completed || migration.MigratedObjects != int64(len(entries)) {
+		t.Errorf("migration %+v, expected %d migrated objects and completed", migration, len(entries))
+	}
+	repo, err := c.GetRepository(ctx, repository)
+	testutil.MustDo(t, "get repository", err)
+	if repo.StorageNamespace != "s3://new-bucket" {
+		t.Errorf("repository storage namespace %s, expected s3://new-bucket", repo.StorageNamespace)
+	}
+	expectedAddresses := map[string]string{
+		"file1":     "addr1",
+		"file2":     "addr2",
+		"imported":  "migrated-obj3",
+		"multipart": "addr4",
+	}
+	for path, expected := range expectedAddresses {
+		entry, err := c.GetEntry(ctx, repository, "master", path, catalog.GetEntryParams{})
+		testutil.MustDo(t, "get entry "+path, err)
+		if entry.PhysicalAddress != expected {
+			t.Errorf("entry %s physical address %s, expected %s", path, entry.PhysicalAddress, expected)
+		}
+	}
+	_, err = c.GetStorageNamespaceMigration(ctx, repository)
+	if !errors.Is(err, catalog.ErrMigrationNotFound) {
+		t.Errorf("get completed migration err=%v, expected %s", err, catalog.ErrMigrationNotFound)
+	}
+}
+
+func TestCataloger_MigrateStorageNamespace_Resume(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	objects := testMigrationObjects{}
+	for _, entry := range []catalog.Entry{
+		{Path: "file1", PhysicalAddress: "addr1", Checksum: "c1"},
+		{Path: "file2", PhysicalAddress: "addr2", Checksum: "c2"},
+	} {
+		testutil.MustDo(t, "create entry", c.CreateEntry(ctx, repository, "master", entry, catalog.CreateEntryParams{}))
+		objects["s3://bucket|"+entry.PhysicalAddress] = entry.Checksum
+	}
+
+	// the copy of addr2 is corrupted
+	params := objects.params(1)
+	copyObject := params.CopyObject
+	params.CopyObject = func(sourceNamespace, sourceAddress, destinationNamespace string) (string, error) {
+		address, err := copyObject(sourceNamespace, sourceAddress, destinationNamespace)
+		if sourceAddress == "addr2" {
+			objects[destinationNamespace+"|"+address] = "corrupted"
+		}
+		return address, err
+	}
+	_, err := c.MigrateStorageNamespace(ctx, repository, "s3://new-bucket", params)
+	if !errors.Is(err, catalog.ErrChecksumMismatch) {
+		t.Fatalf("migrate with corrupted copy err=%v, expected %s", err, catalog.ErrChecksumMismatch)
+	}
+	migration, err := c.GetStorageNamespaceMigration(ctx, repository)
+	testutil.MustDo(t, "get migration", err)
+	if migration.MigratedObjects != 1 || migration.StorageNamespace != "s3://new-bucket" || migration.SourceNamespace != "s3://bucket" {
+		t.Errorf("interrupted migration %+v, expected 1 migrated object from s3://bucket to s3://new-bucket", migration)
+	}
+	_, err = c.MigrateStorageNamespace(ctx, repository, "s3://other-bucket", objects.params(1))
+	if !errors.Is(err, catalog.ErrOperationNotPermitted) {
+		t.Errorf("migrate to another namespace err=%v, expected %s", err, catalog.ErrOperationNotPermitted)
+	}
+
+	migration, err = c.MigrateStorageNamespace(ctx, repository, "s3://new-bucket", objects.params(1))
+	testutil.MustDo(t, "resume migration", err)
+	if !migration.Completed || migration.MigratedObjects != 2 {
+		t.Errorf("resumed migration %+v, expected 2 migrated objects and completed", migration)
+	}
+}
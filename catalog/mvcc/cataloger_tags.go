@@ -0,0 +1,211 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const ListTagsMaxLimit = 10000
+
+// resolveRef parses reference in repository.  A reference naming a tag instead of a branch
+// resolves to the commit of the tag, and a branch at a time to the last commit of the branch at
+// that time.  Ancestor and parent operators resolve to the commit they walk to.
+func (c *cataloger) resolveRef(tx db.Tx, repository, reference string) (*Ref, error) {
+	ref, err := ParseRef(reference)
+	if err != nil {
+		return nil, err
+	}
+	parents := ref.Parents
+	ref.Parents = ""
+	if ref.At != "" {
+		ref, err = c.resolveRefTime(tx, repository, ref)
+	} else {
+		ref, err = c.resolveBaseRef(tx, repository, ref)
+	}
+	if err != nil || parents == "" {
+		return ref, err
+	}
+	walk, err := ParseRefParents(parents)
+	if err != nil {
+		return nil, err
+	}
+	return c.resolveRefParents(tx, repository, ref, walk)
+}
+
+// resolveBaseRef resolves a reference without parents, that may name a tag
+func (c *cataloger) resolveBaseRef(tx db.Tx, repository string, ref *Ref) (*Ref, error) {
+	if ref.CommitID != UncommittedID {
+		return ref, nil
+	}
+	_, err := c.getBranchIDCache(tx, repository, ref.Branch)
+	if !errors.Is(err, catalog.ErrBranchNotFound) {
+		// an existing branch, or a repository error the caller reports
+		return ref, nil
+	}
+	var tagRef struct {
+		Branch   string   `db:"branch"`
+		CommitID CommitID `db:"commit_id"`
+	}
+	err = tx.Get(&tagRef, `SELECT b.name AS branch, t.commit_id
+		FROM catalog_tags t
+			JOIN catalog_repositories r ON r.id = t.repository_id
+			JOIN catalog_branches b ON b.id = t.branch_id
+		WHERE r.name = $1 AND t.name = $2`, repository, ref.Branch)
+	if errors.Is(err, db.ErrNotFound) {
+		// not a tag either, report on the branch
+		return ref, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get tag: %w", err)
+	}
+	return &Ref{Branch: tagRef.Branch, CommitID: tagRef.CommitID}, nil
+}
+
+func (c *cataloger) CreateTag(ctx context.Context, repository, tag string, reference string) (*catalog.Tag, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "tag", IsValid: ValidateBranchName(tag)},
+		{Name: "reference", IsValid: ValidateReference(reference)},
+	}); err != nil {
+		return nil, err
+	}
+
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		// tags and branches share a namespace, lock branches as CreateBranch does
+		_, err := tx.Exec("LOCK TABLE catalog_branches IN SHARE UPDATE EXCLUSIVE MODE")
+		if err != nil {
+			return nil, fmt.Errorf("lock branches for update: %w", err)
+		}
+
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+
+		var isBranch bool
+		err = tx.GetPrimitive(&isBranch, `SELECT EXISTS (SELECT 1 FROM catalog_branches WHERE repository_id=$1 AND name=$2)`,
+			repoID, tag)
+		if err != nil {
+			return nil, fmt.Errorf("branch check: %w", err)
+		}
+		if isBranch {
+			return nil, fmt.Errorf("tag %s is a branch: %w", tag, db.ErrAlreadyExists)
+		}
+
+		branchID, commitID, err := c.resolveCommit(tx, repository, reference)
+		if err != nil {
+			return nil, err
+		}
+		var branch string
+		if err := tx.GetPrimitive(&branch, `SELECT name FROM catalog_branches WHERE id=$1`, branchID); err != nil {
+			return nil, fmt.Errorf("branch name: %w", err)
+		}
+
+		var creationDate time.Time
+		err = tx.GetPrimitive(&creationDate, `INSERT INTO catalog_tags (repository_id, name, branch_id, commit_id, creation_date)
+			VALUES ($1, $2, $3, $4, transaction_timestamp())
+			RETURNING creation_date`,
+			repoID, tag, branchID, commitID)
+		if db.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("tag %s: %w", tag, db.ErrAlreadyExists)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("insert tag: %w", err)
+		}
+		return &catalog.Tag{
+			Repository:   repository,
+			Name:         tag,
+			Reference:    MakeReference(branch, commitID),
+			CreationDate: creationDate,
+		}, nil
+	}, c.txOpts(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.Tag), nil
+}
+
+func (c *cataloger) DeleteTag(ctx context.Context, repository, tag string) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "tag", IsValid: ValidateBranchName(tag)},
+	}); err != nil {
+		return err
+	}
+
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		res, err := tx.Exec(`DELETE FROM catalog_tags WHERE repository_id=$1 AND name=$2`, repoID, tag)
+		if err != nil {
+			return nil, fmt.Errorf("delete tag: %w", err)
+		}
+		if res.RowsAffected() != 1 {
+			return nil, catalog.ErrTagNotFound
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func (c *cataloger) ListTags(ctx context.Context, repository string, prefix string, limit int, after string) ([]*catalog.Tag, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListTagsMaxLimit {
+		limit = ListTagsMaxLimit
+	}
+	prefixCond := db.Prefix(prefix)
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+
+		query := `SELECT t.name, b.name AS branch, t.commit_id, t.creation_date
+			FROM catalog_tags t JOIN catalog_branches b ON b.id = t.branch_id
+			WHERE t.repository_id = $1 AND t.name like $2 AND t.name > $3
+			ORDER BY t.name
+			LIMIT $4`
+		var rows []struct {
+			Name         string    `db:"name"`
+			Branch       string    `db:"branch"`
+			CommitID     CommitID  `db:"commit_id"`
+			CreationDate time.Time `db:"creation_date"`
+		}
+		if err := tx.Select(&rows, query, repoID, prefixCond, after, limit+1); err != nil {
+			return nil, err
+		}
+		tags := make([]*catalog.Tag, len(rows))
+		for i, row := range rows {
+			tags[i] = &catalog.Tag{
+				Repository:   repository,
+				Name:         row.Name,
+				Reference:    MakeReference(row.Branch, row.CommitID),
+				CreationDate: row.CreationDate,
+			}
+		}
+		return tags, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	tags := res.([]*catalog.Tag)
+	hasMore := paginateSlice(&tags, limit)
+	return tags, hasMore, nil
+}
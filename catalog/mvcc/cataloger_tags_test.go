@@ -0,0 +1,103 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_Tags(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repository", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	commitLog, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit file1", err)
+
+	tag, err := c.CreateTag(ctx, repository, "v1", "master")
+	testutil.MustDo(t, "create tag v1", err)
+	if tag.Name != "v1" || tag.Reference != commitLog.Reference {
+		t.Errorf("created tag %+v, expected v1 of %s", tag, commitLog.Reference)
+	}
+	_, err = c.CreateTag(ctx, repository, "v0", commitLog.Parents[0])
+	testutil.MustDo(t, "create tag v0", err)
+
+	// tags are immutable, later commits to the branch don't move them
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	_, err = c.Commit(ctx, repository, "master", "commit file2", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit file2", err)
+
+	t.Run("resolve", func(t *testing.T) {
+		testCatalogerGetEntry(t, ctx, c, repository, "v1", "file1", true)
+		testCatalogerGetEntry(t, ctx, c, repository, "v1", "file2", false)
+		testCatalogerGetEntry(t, ctx, c, repository, "v0", "file1", false)
+
+		commit, err := c.GetCommit(ctx, repository, "v1")
+		testutil.MustDo(t, "get commit of v1", err)
+		if commit.Reference != commitLog.Reference {
+			t.Errorf("commit of v1 %s, expected %s", commit.Reference, commitLog.Reference)
+		}
+
+		differences, _, err := c.Diff(ctx, repository, "master", "v1", catalog.DiffParams{Limit: -1})
+		testutil.MustDo(t, "diff master and v1", err)
+		if len(differences) != 1 || differences[0].Path != "file2" {
+			t.Errorf("diff of master and v1 %+v, expected file2", differences)
+		}
+	})
+
+	t.Run("name clash", func(t *testing.T) {
+		if _, err := c.CreateTag(ctx, repository, "v1", "master"); !errors.Is(err, db.ErrAlreadyExists) {
+			t.Errorf("create existing tag err=%v, expected %s", err, db.ErrAlreadyExists)
+		}
+		if _, err := c.CreateTag(ctx, repository, "master", "v1"); !errors.Is(err, db.ErrAlreadyExists) {
+			t.Errorf("create tag of branch name err=%v, expected %s", err, db.ErrAlreadyExists)
+		}
+		if _, err := c.CreateBranch(ctx, repository, "v1", "master"); !errors.Is(err, db.ErrAlreadyExists) {
+			t.Errorf("create branch of tag name err=%v, expected %s", err, db.ErrAlreadyExists)
+		}
+		if _, err := c.CreateTag(ctx, repository, "v2", "no-such-branch"); !errors.Is(err, catalog.ErrBranchNotFound) {
+			t.Errorf("create tag of missing branch err=%v, expected %s", err, catalog.ErrBranchNotFound)
+		}
+	})
+
+	t.Run("delete tagged branch", func(t *testing.T) {
+		testCatalogerBranch(t, ctx, c, repository, "feature", "master")
+		_, err := c.CreateTag(ctx, repository, "feature-start", "feature")
+		testutil.MustDo(t, "create tag of feature", err)
+		if err := c.DeleteBranch(ctx, repository, "feature"); !errors.Is(err, catalog.ErrOperationNotPermitted) {
+			t.Errorf("delete tagged branch err=%v, expected %s", err, catalog.ErrOperationNotPermitted)
+		}
+		testutil.MustDo(t, "delete tag of feature", c.DeleteTag(ctx, repository, "feature-start"))
+		testutil.MustDo(t, "delete untagged branch", c.DeleteBranch(ctx, repository, "feature"))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		tags, hasMore, err := c.ListTags(ctx, repository, "", -1, "")
+		testutil.MustDo(t, "list tags", err)
+		if hasMore || len(tags) != 2 || tags[0].Name != "v0" || tags[1].Name != "v1" {
+			t.Fatalf("listed tags %+v more=%t, expected v0 and v1", tags, hasMore)
+		}
+		if tags[1].Reference != commitLog.Reference {
+			t.Errorf("listed v1 of %s, expected %s", tags[1].Reference, commitLog.Reference)
+		}
+		tags, hasMore, err = c.ListTags(ctx, repository, "", 1, "v0")
+		testutil.MustDo(t, "list tags after v0", err)
+		if hasMore || len(tags) != 1 || tags[0].Name != "v1" {
+			t.Errorf("listed tags after v0 %+v more=%t, expected v1", tags, hasMore)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		testutil.MustDo(t, "delete tag v0", c.DeleteTag(ctx, repository, "v0"))
+		if err := c.DeleteTag(ctx, repository, "v0"); !errors.Is(err, catalog.ErrTagNotFound) {
+			t.Errorf("delete deleted tag err=%v, expected %s", err, catalog.ErrTagNotFound)
+		}
+		if _, err := c.GetCommit(ctx, repository, "v0"); !errors.Is(err, catalog.ErrBranchNotFound) {
+			t.Errorf("get commit of deleted tag err=%v, expected %s", err, catalog.ErrBranchNotFound)
+		}
+	})
+}
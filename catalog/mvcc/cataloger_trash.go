@@ -0,0 +1,203 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const ListTrashMaxLimit = 1000
+
+func (c *cataloger) GetRepositoryTrash(ctx context.Context, repository string) (*catalog.RepositoryTrash, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return nil, err
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		return getRepositoryTrash(tx, repoID)
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*catalog.RepositoryTrash), nil
+}
+
+func (c *cataloger) SetRepositoryTrash(ctx context.Context, repository string, trash *catalog.RepositoryTrash) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+	}); err != nil {
+		return err
+	}
+	if trash == nil || trash.RetentionDays < 0 {
+		return fmt.Errorf("trash: %w", catalog.ErrInvalidValue)
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`INSERT INTO catalog_repositories_trash (repository_id, retention_days)
+			VALUES ($1, $2)
+			ON CONFLICT (repository_id)
+			DO UPDATE SET retention_days = EXCLUDED.retention_days`,
+			repoID, trash.RetentionDays)
+		if err != nil {
+			return nil, fmt.Errorf("set repository trash: %w", err)
+		}
+		return nil, nil
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func (c *cataloger) ListTrash(ctx context.Context, repository, branch string, prefix, after string, limit int) ([]*catalog.TrashEntry, bool, error) {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+	}); err != nil {
+		return nil, false, err
+	}
+	if limit < 0 || limit > ListTrashMaxLimit {
+		limit = ListTrashMaxLimit
+	}
+	res, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		var entries []*catalog.TrashEntry
+		err = tx.Select(&entries, `SELECT path, physical_address, creation_date, size, checksum, metadata, is_expired,
+				content_type, content_encoding, cache_control, deletion_date, expiration_date
+			FROM catalog_trash
+			WHERE branch_id = $1 AND path LIKE $2 AND path > $3 AND expiration_date > transaction_timestamp()
+			ORDER BY path
+			LIMIT $4`,
+			branchID, db.Prefix(prefix), after, limit+1)
+		if err != nil {
+			return nil, fmt.Errorf("list trash: %w", err)
+		}
+		return entries, nil
+	}, c.txOpts(ctx, db.ReadOnly())...)
+	if err != nil {
+		return nil, false, err
+	}
+	entries := res.([]*catalog.TrashEntry)
+	hasMore := paginateSlice(&entries, limit)
+	return entries, hasMore, nil
+}
+
+func (c *cataloger) RestoreEntry(ctx context.Context, repository, branch string, path string) error {
+	if err := Validate(ValidateFields{
+		{Name: "repository", IsValid: ValidateRepositoryName(repository)},
+		{Name: "branch", IsValid: ValidateBranchName(branch)},
+		{Name: "path", IsValid: ValidatePath(path)},
+	}); err != nil {
+		return err
+	}
+	_, err := c.db.Transact(func(tx db.Tx) (interface{}, error) {
+		if err := checkRepositoryWritable(tx, repository); err != nil {
+			return nil, err
+		}
+		branchID, err := c.getBranchIDCache(tx, repository, branch)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkNoCommitJob(tx, branchID); err != nil {
+			return nil, err
+		}
+		repoID, err := c.getRepositoryIDCache(tx, repository)
+		if err != nil {
+			return nil, err
+		}
+		var entry catalog.Entry
+		err = tx.Get(&entry, `DELETE FROM catalog_trash
+			WHERE branch_id = $1 AND path = $2 AND expiration_date > transaction_timestamp()
+			RETURNING path, physical_address, creation_date, size, checksum, metadata, is_expired,
+				content_type, content_encoding, cache_control`,
+			branchID, path)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, catalog.ErrEntryNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("remove from trash: %w", err)
+		}
+		// restoring never replaces an entry written since the delete
+		current, err := readEntriesAt(tx, branchID, UncommittedID, []string{path})
+		if err != nil {
+			return nil, err
+		}
+		if current[path] != nil {
+			return nil, fmt.Errorf("restore over existing entry: %w", db.ErrAlreadyExists)
+		}
+		if err := checkRepositoryQuota(tx, repoID, 1, entry.Size); err != nil {
+			return nil, err
+		}
+		return insertEntry(tx, branchID, &entry)
+	}, c.txOpts(ctx)...)
+	return err
+}
+
+func getRepositoryTrash(tx db.Tx, repositoryID int) (*catalog.RepositoryTrash, error) {
+	var trash catalog.RepositoryTrash
+	err := tx.Get(&trash, `SELECT retention_days FROM catalog_repositories_trash WHERE repository_id=$1`, repositoryID)
+	if errors.Is(err, db.ErrNotFound) {
+		return &trash, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get repository trash: %w", err)
+	}
+	return &trash, nil
+}
+
+// trashEntries keeps the current entries at paths of branchID in the trash, when the trash of
+// repositoryID is enabled.  Call it before deleting the entries.
+func trashEntries(tx db.Tx, repositoryID int, branchID int64, paths []string) error {
+	trash, err := getRepositoryTrash(tx, repositoryID)
+	if err != nil {
+		return err
+	}
+	if trash.RetentionDays == 0 {
+		return nil
+	}
+	// drop expired entries of the branch while here
+	if _, err := tx.Exec(`DELETE FROM catalog_trash WHERE branch_id = $1 AND expiration_date <= transaction_timestamp()`, branchID); err != nil {
+		return fmt.Errorf("expire trash: %w", err)
+	}
+	lineage, err := getLineage(tx, branchID, UncommittedID)
+	if err != nil {
+		return fmt.Errorf("get lineage: %w", err)
+	}
+	sql, args, err := psql.
+		Select().
+		Column("?::bigint", branchID).
+		Columns("path", "physical_address", "creation_date", "size", "checksum", "metadata", "is_expired",
+			"content_type", "content_encoding", "cache_control").
+		Column("transaction_timestamp() + make_interval(days => ?::integer)", trash.RetentionDays).
+		FromSelect(sqEntriesLineage(branchID, UncommittedID, lineage), "entries").
+		Where(sq.Eq{"path": paths, "is_deleted": false}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build sql: %w", err)
+	}
+	_, err = tx.Exec(`INSERT INTO catalog_trash (branch_id, path, physical_address, creation_date, size, checksum, metadata, is_expired,
+			content_type, content_encoding, cache_control, expiration_date)
+		`+sql+`
+		ON CONFLICT (branch_id, path)
+		DO UPDATE SET (physical_address, creation_date, size, checksum, metadata, is_expired,
+			content_type, content_encoding, cache_control, deletion_date, expiration_date) =
+			(EXCLUDED.physical_address, EXCLUDED.creation_date, EXCLUDED.size, EXCLUDED.checksum, EXCLUDED.metadata,
+			EXCLUDED.is_expired, EXCLUDED.content_type, EXCLUDED.content_encoding, EXCLUDED.cache_control,
+			EXCLUDED.deletion_date, EXCLUDED.expiration_date)`, args...)
+	if err != nil {
+		return fmt.Errorf("trash entries: %w", err)
+	}
+	return nil
+}
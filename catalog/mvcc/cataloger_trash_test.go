@@ -0,0 +1,108 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_RepositoryTrash(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+
+	trash, err := c.GetRepositoryTrash(ctx, repository)
+	testutil.MustDo(t, "get default trash", err)
+	if trash.RetentionDays != 0 {
+		t.Errorf("default trash retention %d days, expected disabled", trash.RetentionDays)
+	}
+	testutil.MustDo(t, "set trash", c.SetRepositoryTrash(ctx, repository, &catalog.RepositoryTrash{RetentionDays: 7}))
+	trash, err = c.GetRepositoryTrash(ctx, repository)
+	testutil.MustDo(t, "get trash", err)
+	if trash.RetentionDays != 7 {
+		t.Errorf("trash retention %d days, expected 7", trash.RetentionDays)
+	}
+	if err := c.SetRepositoryTrash(ctx, repository, &catalog.RepositoryTrash{RetentionDays: -1}); !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("set negative retention err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+}
+
+func TestCataloger_ListTrash(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "disabled", nil, "")
+	testutil.MustDo(t, "delete with trash disabled", c.DeleteEntry(ctx, repository, "master", "disabled"))
+
+	testutil.MustDo(t, "set trash", c.SetRepositoryTrash(ctx, repository, &catalog.RepositoryTrash{RetentionDays: 1}))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "committed", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit file", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "uncommitted", nil, "")
+	testutil.MustDo(t, "delete committed", c.DeleteEntry(ctx, repository, "master", "committed"))
+	testutil.MustDo(t, "delete uncommitted", c.DeleteEntry(ctx, repository, "master", "uncommitted"))
+
+	entries, hasMore, err := c.ListTrash(ctx, repository, "master", "", "", -1)
+	testutil.MustDo(t, "list trash", err)
+	if hasMore {
+		t.Error("list trash has more, expected all entries")
+	}
+	var paths []string
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+		if !entry.ExpirationDate.After(entry.DeletionDate) {
+			t.Errorf("trash entry %s expires %s, before deletion %s", entry.Path, entry.ExpirationDate, entry.DeletionDate)
+		}
+	}
+	if len(paths) != 2 || paths[0] != "committed" || paths[1] != "uncommitted" {
+		t.Errorf("trash paths %v, expected [committed uncommitted]", paths)
+	}
+
+	entries, hasMore, err = c.ListTrash(ctx, repository, "master", "", "", 1)
+	testutil.MustDo(t, "list trash page", err)
+	if len(entries) != 1 || !hasMore {
+		t.Errorf("list trash page got %d entries, has more %t, expected 1 and more", len(entries), hasMore)
+	}
+}
+
+func TestCataloger_RestoreEntry(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testutil.MustDo(t, "set trash", c.SetRepositoryTrash(ctx, repository, &catalog.RepositoryTrash{RetentionDays: 1}))
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit files", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit", err)
+	deleted, err := c.GetEntry(ctx, repository, "master", "file1", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get entry", err)
+
+	errs, err := c.DeleteEntries(ctx, repository, "master", []string{"file1", "file2"})
+	testutil.MustDo(t, "delete entries", err)
+	for _, err := range errs {
+		testutil.MustDo(t, "delete entry", err)
+	}
+	testutil.MustDo(t, "restore", c.RestoreEntry(ctx, repository, "master", "file1"))
+	restored, err := c.GetEntry(ctx, repository, "master", "file1", catalog.GetEntryParams{})
+	testutil.MustDo(t, "get restored", err)
+	if restored.PhysicalAddress != deleted.PhysicalAddress || restored.Checksum != deleted.Checksum {
+		t.Errorf("restored entry %+v, expected object of %+v", restored, deleted)
+	}
+	entries, _, err := c.ListTrash(ctx, repository, "master", "", "", -1)
+	testutil.MustDo(t, "list trash", err)
+	if len(entries) != 1 || entries[0].Path != "file2" {
+		t.Errorf("trash after restore %+v, expected only file2", entries)
+	}
+
+	if err := c.RestoreEntry(ctx, repository, "master", "file1"); !errors.Is(err, catalog.ErrEntryNotFound) {
+		t.Errorf("restore entry twice err=%v, expected %s", err, catalog.ErrEntryNotFound)
+	}
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	if err := c.RestoreEntry(ctx, repository, "master", "file2"); !errors.Is(err, db.ErrAlreadyExists) {
+		t.Errorf("restore over existing entry err=%v, expected %s", err, db.ErrAlreadyExists)
+	}
+}
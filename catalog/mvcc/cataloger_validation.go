@@ -0,0 +1,97 @@
+package mvcc
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// validateCommit calls the PreCommit hooks on commit of the uncommitted changes of branchID
+func (c *cataloger) validateCommit(ctx context.Context, tx db.Tx, branchID int64, commit *catalog.CommitValidation) error {
+	if len(c.hooks.PreCommit) == 0 {
+		return nil
+	}
+	commit.Changes = cachedChanges(func() (catalog.Differences, error) {
+		return uncommittedChanges(tx, branchID, commit.Params.Prefixes)
+	})
+	for _, hook := range c.hooks.PreCommit {
+		if err := hook(ctx, tx, commit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMerge calls the PreMerge hooks on merge of the differences of params.  Conflicts a
+// merge resolver could merge by content are listed as conflicts, the resolver is not called.
+func (c *cataloger) validateMerge(ctx context.Context, tx db.Tx, params doDiffParams, merge *catalog.MergeValidation) error {
+	if len(c.hooks.PreMerge) == 0 {
+		return nil
+	}
+	params.MergeResolve = nil
+	merge.Changes = cachedChanges(func() (catalog.Differences, error) {
+		scanner, err := NewDiffScanner(tx, params)
+		if err != nil {
+			return nil, err
+		}
+		var differences catalog.Differences
+		for scanner.Next() {
+			differences = append(differences, scanner.Value().Difference)
+		}
+		if err := scanner.Error(); err != nil {
+			return nil, err
+		}
+		return differences, nil
+	})
+	for _, hook := range c.hooks.PreMerge {
+		if err := hook(ctx, tx, merge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cachedChanges returns a function reading the changes once, for all hooks to share
+func cachedChanges(read func() (catalog.Differences, error)) func() (catalog.Differences, error) {
+	var (
+		differences catalog.Differences
+		err         error
+		done        bool
+	)
+	return func() (catalog.Differences, error) {
+		if !done {
+			differences, err = read()
+			done = true
+		}
+		return differences, err
+	}
+}
+
+// uncommittedChanges returns the uncommitted changes of branchID under prefixes, all changes
+// when prefixes is empty
+func uncommittedChanges(tx db.Tx, branchID int64, prefixes []string) (catalog.Differences, error) {
+	lineage, err := getLineage(tx, branchID, CommittedID)
+	if err != nil {
+		return nil, fmt.Errorf("get lineage: %w", err)
+	}
+	q := sqDiffUncommitted(branchID, lineage).OrderBy("path")
+	if len(prefixes) > 0 {
+		prefixCond := make(sq.Or, len(prefixes))
+		for i, prefix := range prefixes {
+			prefixCond[i] = sq.Like{"e.path": db.Prefix(prefix)}
+		}
+		q = q.Where(prefixCond)
+	}
+	sql, args, err := q.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql: %w", err)
+	}
+	var differences catalog.Differences
+	if err := tx.Select(&differences, sql, args...); err != nil {
+		return nil, err
+	}
+	return differences, nil
+}
@@ -0,0 +1,89 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+// rejectUppercasePaths is a validation rejecting changes to paths with uppercase letters
+func rejectUppercasePaths(changes func() (catalog.Differences, error)) error {
+	differences, err := changes()
+	if err != nil {
+		return err
+	}
+	var violations []catalog.ValidationViolation
+	for _, d := range differences {
+		if strings.ToLower(d.Path) != d.Path {
+			violations = append(violations, catalog.ValidationViolation{Path: d.Path, Message: "uppercase path"})
+		}
+	}
+	if len(violations) > 0 {
+		return &catalog.ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func TestCataloger_CommitValidation(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	var validated []*catalog.CommitValidation
+	c.Hooks().AddPreCommit(func(_ context.Context, _ db.Tx, commit *catalog.CommitValidation) error {
+		validated = append(validated, commit)
+		return rejectUppercasePaths(commit.Changes)
+	})
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "good/file1", nil, "")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "bad/File2", nil, "")
+
+	_, err := c.Commit(ctx, repository, "master", "commit all", "tester", nil, catalog.CommitParams{})
+	var validationErr *catalog.ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, catalog.ErrValidationFailed) {
+		t.Fatalf("Commit err=%v, expected %s", err, catalog.ErrValidationFailed)
+	}
+	if len(validationErr.Violations) != 1 || validationErr.Violations[0].Path != "bad/File2" {
+		t.Errorf("Commit violations %+v, expected bad/File2", validationErr.Violations)
+	}
+	changes, _, err := c.DiffUncommitted(ctx, repository, "master", "", -1, "")
+	testutil.MustDo(t, "diff uncommitted", err)
+	if len(changes) != 2 {
+		t.Errorf("uncommitted changes %v after rejected commit, expected both files", changes)
+	}
+
+	_, err = c.Commit(ctx, repository, "master", "commit good", "tester", nil, catalog.CommitParams{Prefixes: []string{"good/"}})
+	testutil.MustDo(t, "commit good prefix", err)
+	last := validated[len(validated)-1]
+	if last.Repository != repository || last.Branch != "master" || last.Message != "commit good" || last.Committer != "tester" {
+		t.Errorf("validated commit %+v, expected commit good to master", last)
+	}
+}
+
+func TestCataloger_MergeValidation(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	c.Hooks().AddPreMerge(func(_ context.Context, _ db.Tx, merge *catalog.MergeValidation) error {
+		if merge.SourceBranch != "branch1" || merge.DestinationBranch != "master" {
+			t.Errorf("validated merge %s into %s, expected branch1 into master", merge.SourceBranch, merge.DestinationBranch)
+		}
+		return rejectUppercasePaths(merge.Changes)
+	})
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file0", nil, "")
+	_, err := c.Commit(ctx, repository, "master", "commit to master", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to master", err)
+	testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "File1", nil, "")
+	_, err = c.Commit(ctx, repository, "branch1", "commit to branch1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit to branch1", err)
+
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "", nil, catalog.MergeParams{})
+	if !errors.Is(err, catalog.ErrValidationFailed) {
+		t.Fatalf("Merge err=%v, expected %s", err, catalog.ErrValidationFailed)
+	}
+	testCatalogerGetEntry(t, ctx, c, repository, "master", "File1", false)
+}
@@ -0,0 +1,26 @@
+package mvcc
+
+import (
+	"context"
+
+	"github.com/treeverse/lakefs/catalog"
+)
+
+// commitAttribution returns the committer and metadata of a commit made on ctx.  When ctx
+// carries an authenticated catalog.Committer the commit is attributed to it, ignoring
+// committer, and its identity is recorded in a copy of metadata.
+func (c *cataloger) commitAttribution(ctx context.Context, committer string, metadata catalog.Metadata) (string, catalog.Metadata) {
+	authenticated, ok := catalog.CommitterFromContext(ctx)
+	if !ok || authenticated.Username == "" {
+		return committer, metadata
+	}
+	attributed := make(catalog.Metadata, len(metadata)+2)
+	for k, v := range metadata {
+		attributed[k] = v
+	}
+	attributed[catalog.CommitterMetadataKey] = authenticated.Username
+	if c.Committer.RecordAccessKeyID && authenticated.AccessKeyID != "" {
+		attributed[catalog.AccessKeyIDMetadataKey] = authenticated.AccessKeyID
+	}
+	return authenticated.Username, attributed
+}
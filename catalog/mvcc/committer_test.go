@@ -0,0 +1,80 @@
+package mvcc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/catalog/mvcc/params"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_CommitAttribution(t *testing.T) {
+	authenticated := catalog.Committer{Username: "user1", AccessKeyID: "AKIAEXAMPLE"}
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		recordAccessKeyID bool
+		wantCommitter     string
+		wantMetadata      catalog.Metadata
+	}{
+		{
+			name:          "no committer",
+			ctx:           context.Background(),
+			wantCommitter: "tester",
+			wantMetadata:  catalog.Metadata{"key": "value"},
+		},
+		{
+			name:          "committer",
+			ctx:           catalog.WithCommitter(context.Background(), authenticated),
+			wantCommitter: "user1",
+			wantMetadata:  catalog.Metadata{"key": "value", catalog.CommitterMetadataKey: "user1"},
+		},
+		{
+			name:              "committer with access key",
+			ctx:               catalog.WithCommitter(context.Background(), authenticated),
+			recordAccessKeyID: true,
+			wantCommitter:     "user1",
+			wantMetadata: catalog.Metadata{
+				"key":                          "value",
+				catalog.CommitterMetadataKey:   "user1",
+				catalog.AccessKeyIDMetadataKey: "AKIAEXAMPLE",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			c := testCataloger(t, WithParams(params.Catalog{
+				Committer: params.Committer{RecordAccessKeyID: tt.recordAccessKeyID},
+			}))
+			repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+			testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+			metadata := catalog.Metadata{"key": "value"}
+			commit, err := c.Commit(ctx, repository, "master", "commit file1", "tester", metadata, catalog.CommitParams{})
+			testutil.MustDo(t, "commit file1", err)
+			if commit.Committer != tt.wantCommitter {
+				t.Errorf("Commit() committer %s, expected %s", commit.Committer, tt.wantCommitter)
+			}
+			if diff := deep.Equal(commit.Metadata, tt.wantMetadata); diff != nil {
+				t.Error("Commit() metadata diff", diff)
+			}
+			if len(metadata) != 1 {
+				t.Errorf("Commit() modified passed metadata %v", metadata)
+			}
+
+			testCatalogerBranch(t, ctx, c, repository, "branch1", "master")
+			testCatalogerCreateEntry(t, ctx, c, repository, "branch1", "file2", nil, "")
+			_, err = c.Commit(ctx, repository, "branch1", "commit file2", "tester", nil, catalog.CommitParams{})
+			testutil.MustDo(t, "commit file2", err)
+			res, err := c.Merge(ctx, repository, "branch1", "master", "tester", "merge", nil, catalog.MergeParams{})
+			testutil.MustDo(t, "merge", err)
+			merge, err := c.GetCommit(ctx, repository, res.Reference)
+			testutil.MustDo(t, "get merge commit", err)
+			if merge.Committer != tt.wantCommitter {
+				t.Errorf("Merge() committer %s, expected %s", merge.Committer, tt.wantCommitter)
+			}
+		})
+	}
+}
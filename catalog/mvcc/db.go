@@ -69,7 +69,7 @@ func getNextCommitID(tx db.Tx) (CommitID, error) {
 
 func getRepository(tx db.Tx, repository string) (*catalog.Repository, error) {
 	var r catalog.Repository
-	err := tx.Get(&r, `SELECT r.name, r.storage_namespace, b.name as default_branch, r.creation_date
+	err := tx.Get(&r, `SELECT r.name, r.storage_namespace, b.name as default_branch, r.creation_date, r.read_only, r.archived
 			FROM catalog_repositories r, catalog_branches b
 			WHERE r.id = b.repository_id AND r.default_branch = b.id AND r.name = $1`,
 		repository)
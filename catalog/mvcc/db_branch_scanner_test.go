@@ -9,6 +9,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 	"github.com/treeverse/lakefs/testutil"
 )
@@ -139,7 +140,7 @@ func testSetupDBScannerData(t *testing.T, ctx context.Context, c TestCataloger,
 		for i := 0; i < numberOfObjects; i += skipCount {
 			testCatalogerCreateEntry(t, ctx, c, repository, branchName, fmt.Sprintf("Obj-%04d", i), nil, "")
 		}
-		_, err := c.Commit(ctx, repository, branchName, "commit to "+branchName, "tester", nil)
+		_, err := c.Commit(ctx, repository, branchName, "commit to "+branchName, "tester", nil, catalog.CommitParams{})
 		testutil.MustDo(t, "commit to "+branchName, err)
 		baseBranchName = branchName
 	}
@@ -0,0 +1,272 @@
+package mvcc
+
+import (
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// commitChange is a path changed between two commits of a branch, with its entry before and
+// after the change.  A nil entry is a missing or deleted entry.
+type commitChange struct {
+	catalog.Difference
+	before *catalog.Entry
+	after  *catalog.Entry
+}
+
+// inverse returns the change undoing change
+func (change *commitChange) inverse() *commitChange {
+	inverse := &commitChange{Difference: change.Difference, before: change.after, after: change.before}
+	switch change.Type {
+	case catalog.DifferenceTypeAdded:
+		inverse.Type = catalog.DifferenceTypeRemoved
+	case catalog.DifferenceTypeRemoved:
+		inverse.Type = catalog.DifferenceTypeAdded
+	}
+	return inverse
+}
+
+// commitChanges returns the paths of branchID changed by its commits after previousCommitID
+// up to commitID
+func commitChanges(tx db.Tx, repository string, branchID int64, previousCommitID, commitID CommitID) ([]*commitChange, error) {
+	scanner, err := NewDiffScanner(tx, doDiffParams{
+		Repository:    repository,
+		LeftBranchID:  branchID,
+		LeftCommitID:  commitID,
+		RightBranchID: branchID,
+		RightCommitID: previousCommitID,
+		DiffParams:    catalog.DiffParams{Limit: -1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var changes []*commitChange
+	for scanner.Next() {
+		changes = append(changes, &commitChange{Difference: scanner.Value().Difference})
+	}
+	if err := scanner.Error(); err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(changes); i += MergeBatchSize {
+		end := i + MergeBatchSize
+		if end > len(changes) {
+			end = len(changes)
+		}
+		batch := changes[i:end]
+		paths := changePaths(batch)
+		before, err := readEntriesAt(tx, branchID, previousCommitID, paths)
+		if err != nil {
+			return nil, err
+		}
+		after, err := readEntriesAt(tx, branchID, commitID, paths)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range batch {
+			change.before = before[change.Path]
+			change.after = after[change.Path]
+		}
+	}
+	return changes, nil
+}
+
+// refChanges returns the paths changed between the committed entries of branchID and the
+// entries of commitID of refBranchID, which may be on any branch
+func refChanges(tx db.Tx, branchID int64, refBranchID int64, commitID CommitID) ([]*commitChange, error) {
+	lineage, err := getLineage(tx, branchID, CommittedID)
+	if err != nil {
+		return nil, fmt.Errorf("get lineage: %w", err)
+	}
+	refLineage, err := getLineage(tx, refBranchID, commitID)
+	if err != nil {
+		return nil, fmt.Errorf("get ref lineage: %w", err)
+	}
+	branchEntries := sq.Select("path", "checksum").
+		FromSelect(sqEntriesLineage(branchID, CommittedID, lineage), "entries").
+		Where("NOT is_deleted")
+	refEntries := sq.Select("path", "checksum").
+		FromSelect(sqEntriesLineage(refBranchID, commitID, refLineage), "entries").
+		Where("NOT is_deleted")
+	sql, args, err := sq.Select("COALESCE(b.path, r.path) AS path",
+		fmt.Sprintf(`CASE WHEN b.path IS NULL THEN %d WHEN r.path IS NULL THEN %d ELSE %d END AS diff_type`,
+			catalog.DifferenceTypeAdded, catalog.DifferenceTypeRemoved, catalog.DifferenceTypeChanged)).
+		FromSelect(branchEntries, "b").
+		JoinClause(refEntries.Prefix("FULL OUTER JOIN (").Suffix(") r ON b.path = r.path")).
+		Where("b.path IS NULL OR r.path IS NULL OR b.checksum <> r.checksum").
+		OrderBy("path").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql: %w", err)
+	}
+	var differences []catalog.Difference
+	if err := tx.Select(&differences, sql, args...); err != nil {
+		return nil, fmt.Errorf("select changes: %w", err)
+	}
+	changes := make([]*commitChange, 0, len(differences))
+	for i := 0; i < len(differences); i += MergeBatchSize {
+		end := i + MergeBatchSize
+		if end > len(differences) {
+			end = len(differences)
+		}
+		batch := make([]*commitChange, end-i)
+		for j := range batch {
+			batch[j] = &commitChange{Difference: differences[i+j]}
+		}
+		paths := changePaths(batch)
+		before, err := readEntriesAt(tx, branchID, CommittedID, paths)
+		if err != nil {
+			return nil, err
+		}
+		after, err := readEntriesAt(tx, refBranchID, commitID, paths)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range batch {
+			change.before = before[change.Path]
+			change.after = after[change.Path]
+			if !sameEntry(change.before, change.after) {
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes, nil
+}
+
+// checkChangesOnBranch returns the changes that apply to the committed entries of targetID,
+// counting them in mergeResult.  A change applies when targetID holds its entry before the
+// change, and is skipped when targetID already holds its entry after the change.  It fails
+// with ErrConflictFound if any change conflicts.
+func checkChangesOnBranch(tx db.Tx, targetID int64, changes []*commitChange, mergeResult *catalog.MergeResult) ([]*commitChange, error) {
+	var conflicts bool
+	apply := make([]*commitChange, 0, len(changes))
+	for i := 0; i < len(changes); i += MergeBatchSize {
+		end := i + MergeBatchSize
+		if end > len(changes) {
+			end = len(changes)
+		}
+		batch := changes[i:end]
+		paths := changePaths(batch)
+		current, err := readEntriesAt(tx, targetID, CommittedID, paths)
+		if err != nil {
+			return nil, err
+		}
+		var uncommittedPaths []string
+		err = tx.Select(&uncommittedPaths, `SELECT DISTINCT path FROM catalog_entries_v
+			WHERE branch_id = $1 AND NOT is_committed AND path = ANY($2::text[])`, targetID, paths)
+		if err != nil {
+			return nil, fmt.Errorf("uncommitted paths: %w", err)
+		}
+		uncommitted := make(map[string]struct{}, len(uncommittedPaths))
+		for _, path := range uncommittedPaths {
+			uncommitted[path] = struct{}{}
+		}
+		for _, change := range batch {
+			target := current[change.Path]
+			_, hasUncommitted := uncommitted[change.Path]
+			switch {
+			case !hasUncommitted && sameEntry(target, change.after):
+				// already changed the same way
+			case !hasUncommitted && sameEntry(target, change.before):
+				mergeResult.Summary[change.Type]++
+				apply = append(apply, change)
+			default:
+				mergeResult.Summary[catalog.DifferenceTypeConflict]++
+				conflicts = true
+			}
+		}
+	}
+	if conflicts {
+		return nil, catalog.ErrConflictFound
+	}
+	return apply, nil
+}
+
+// applyChangesToBranch commits changes to targetID as commit nextCommitID, after its last
+// commit previousMaxCommitID
+func applyChangesToBranch(tx db.Tx, targetID int64, previousMaxCommitID, nextCommitID CommitID, changes []*commitChange) error {
+	paths := changePaths(changes)
+	// end the committed entries of the changed paths on the target branch itself
+	var endedPaths []string
+	err := tx.Select(&endedPaths, `UPDATE catalog_entries SET max_commit = $2
+		WHERE branch_id = $1 AND max_commit = $3 AND min_commit < $3 AND path = ANY($4::text[])
+		RETURNING path`,
+		targetID, previousMaxCommitID, MaxCommitID, paths)
+	if err != nil {
+		return fmt.Errorf("end entries: %w", err)
+	}
+	ended := make(map[string]struct{}, len(endedPaths))
+	for _, path := range endedPaths {
+		ended[path] = struct{}{}
+	}
+
+	var rows int
+	insert := sq.Insert("catalog_entries").
+		Columns("branch_id", "path", "physical_address", "creation_date", "size", "checksum", "metadata", "min_commit", "max_commit",
+			"content_type", "content_encoding", "cache_control")
+	for _, change := range changes {
+		if change.after != nil {
+			insert = insert.Values(targetID, change.Path, change.after.PhysicalAddress, change.after.CreationDate,
+				change.after.Size, change.after.Checksum, change.after.Metadata, nextCommitID, MaxCommitID,
+				change.after.ContentType, change.after.ContentEncoding, change.after.CacheControl)
+			rows++
+			continue
+		}
+		if _, ok := ended[change.Path]; !ok {
+			// the removed entry is read from the lineage of the target branch, hide it
+			insert = insert.Values(targetID, change.Path, "", time.Now(), 0, "", catalog.Metadata{}, nextCommitID, TombstoneCommitID, "", "", "")
+			rows++
+		}
+	}
+	if rows == 0 {
+		return nil
+	}
+	sql, args, err := insert.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return fmt.Errorf("build sql: %w", err)
+	}
+	if _, err := tx.Exec(sql, args...); err != nil {
+		return fmt.Errorf("insert entries: %w", err)
+	}
+	return nil
+}
+
+// readEntriesAt returns the entries of paths in commitID of branchID that were not deleted
+func readEntriesAt(tx db.Tx, branchID int64, commitID CommitID, paths []string) (map[string]*catalog.Entry, error) {
+	readExpr, err := sqEntryLineageSelect(tx, branchID, commitID, true, paths)
+	if err != nil {
+		return nil, fmt.Errorf("lineage select: %w", err)
+	}
+	sql, args, err := readExpr.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql: %w", err)
+	}
+	var entries []*catalog.Entry
+	if err := tx.Select(&entries, sql, args...); err != nil {
+		return nil, fmt.Errorf("select entries: %w", err)
+	}
+	byPath := make(map[string]*catalog.Entry, len(entries))
+	for _, entry := range entries {
+		byPath[entry.Path] = entry
+	}
+	return byPath, nil
+}
+
+func changePaths(changes []*commitChange) []string {
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i] = change.Path
+	}
+	return paths
+}
+
+// sameEntry reports whether two entries, possibly nil, hold the same object
+func sameEntry(a, b *catalog.Entry) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Checksum == b.Checksum
+}
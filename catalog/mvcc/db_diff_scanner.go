@@ -16,6 +16,10 @@ type doDiffParams struct {
 	LeftBranchID  int64
 	RightCommitID CommitID
 	RightBranchID int64
+	// MergeStrategy resolves the conflicts found by a merge diff, empty to report them
+	MergeStrategy catalog.MergeStrategy
+	// MergeResolve merges the content of conflicts before MergeStrategy resolves them
+	MergeResolve catalog.MergeResolveFunc
 }
 
 type diffEvaluator func(leftEntry *DBScannerEntry, rightEntry *DBScannerEntry) catalog.DifferenceType
@@ -27,6 +31,8 @@ type DiffScanner struct {
 	rightScanner                DBScanner
 	err                         error
 	value                       *catalog.DiffResultRecord
+	matchedRight                *DBScannerEntry
+	left                        *DBScannerEntry
 	evaluator                   diffEvaluator
 	childLineage                []lineageCommit       // used by diff from parent to child
 	childLastFromParentCommitID CommitID              // used by diff from parent to child
@@ -161,21 +167,35 @@ func (s *DiffScanner) Next() bool {
 			matchedRight = rightEnt
 		}
 		diffType := s.evaluator(leftEnt, matchedRight)
+		resolvedConflict := false
+		var mergedEntry *catalog.Entry
+		if diffType == catalog.DifferenceTypeConflict {
+			diffType, mergedEntry, err = s.resolveConflict(leftEnt, matchedRight)
+			if err != nil {
+				s.err = fmt.Errorf("resolve conflict %s: %w", leftEnt.Path, err)
+				return false
+			}
+			resolvedConflict = diffType != catalog.DifferenceTypeConflict
+		}
 		if diffType == catalog.DifferenceTypeNone {
 			continue
 		}
+		s.left = leftEnt
+		s.matchedRight = matchedRight
 		s.value = &catalog.DiffResultRecord{
 			Difference: catalog.Difference{
 				Type:  diffType,
 				Entry: leftEnt.Entry,
 			},
+			ResolvedConflict: resolvedConflict,
+			MergedEntry:      mergedEntry,
 		}
 
 		// store ctid for copying in the merge step, under the following conditions:
 		// 1. the entry exists in the child branch
 		// 2. difference type is either changed or added.
 		// Then the entry has to appear in the child branch, but an older version exists in the child, so merge will copy it, and the ctid is needed for that
-		if (diffType == catalog.DifferenceTypeAdded || diffType == catalog.DifferenceTypeChanged) &&
+		if (diffType == catalog.DifferenceTypeAdded || diffType == catalog.DifferenceTypeChanged) && mergedEntry == nil &&
 			((matchedRight != nil && matchedRight.BranchID == s.params.RightBranchID) || s.Relation == RelationTypeFromChild) {
 			s.value.EntryCtid = &leftEnt.RowCtid
 		}
@@ -198,6 +218,22 @@ func (s *DiffScanner) Value() *catalog.DiffResultRecord {
 	return s.value
 }
 
+// MatchedRight returns the right entry with the same path as the current value, nil if none
+func (s *DiffScanner) MatchedRight() *DBScannerEntry {
+	if s.err != nil {
+		return nil
+	}
+	return s.matchedRight
+}
+
+// Left returns the left entry of the current value
+func (s *DiffScanner) Left() *DBScannerEntry {
+	if s.err != nil {
+		return nil
+	}
+	return s.left
+}
+
 func (s *DiffScanner) Error() error {
 	return s.err
 }
@@ -221,18 +257,7 @@ func (s *DiffScanner) evaluateParentToChild(leftEntry, rightEntry *DBScannerEntr
 		return catalog.DifferenceTypeConflict
 	}
 
-	// if source was deleted (target exists) - removed
-	if leftEntry.IsDeleted() {
-		return catalog.DifferenceTypeRemoved
-	}
-
-	// if target deleted (source exists) - add
-	if rightEntry == nil || rightEntry.IsDeleted() {
-		return catalog.DifferenceTypeAdded
-	}
-
-	// if target exists - change
-	return catalog.DifferenceTypeChanged
+	return evaluateSourceChange(leftEntry, rightEntry)
 }
 
 func (s *DiffScanner) evaluateChildToParent(leftEntry *DBScannerEntry, rightEntry *DBScannerEntry) catalog.DifferenceType {
@@ -247,6 +272,11 @@ func (s *DiffScanner) evaluateChildToParent(leftEntry *DBScannerEntry, rightEntr
 		}
 	}
 
+	return evaluateSourceChange(leftEntry, rightEntry)
+}
+
+// evaluateSourceChange returns the change of the source entry, applied on the target entry
+func evaluateSourceChange(leftEntry *DBScannerEntry, rightEntry *DBScannerEntry) catalog.DifferenceType {
 	// source deleted - removed
 	if leftEntry.IsDeleted() {
 		return catalog.DifferenceTypeRemoved
@@ -261,6 +291,32 @@ func (s *DiffScanner) evaluateChildToParent(leftEntry *DBScannerEntry, rightEntr
 	return catalog.DifferenceTypeChanged
 }
 
+// resolveConflict returns the difference of a conflicting entry under the merge resolver and
+// strategy of the diff: a change to the merged entry the resolver returned, the source change,
+// none to keep the target, or still a conflict
+func (s *DiffScanner) resolveConflict(leftEntry *DBScannerEntry, rightEntry *DBScannerEntry) (catalog.DifferenceType, *catalog.Entry, error) {
+	if s.params.MergeResolve != nil && !leftEntry.IsDeleted() && rightEntry != nil && !rightEntry.IsDeleted() {
+		source := leftEntry.Entry
+		destination := rightEntry.Entry
+		merged, err := s.params.MergeResolve(&source, &destination)
+		if err != nil {
+			return catalog.DifferenceTypeConflict, nil, err
+		}
+		if merged != nil {
+			merged.Path = leftEntry.Path
+			return catalog.DifferenceTypeChanged, merged, nil
+		}
+	}
+	switch s.params.MergeStrategy {
+	case catalog.MergeStrategySourceWins:
+		return evaluateSourceChange(leftEntry, rightEntry), nil, nil
+	case catalog.MergeStrategyDestWins:
+		return catalog.DifferenceTypeNone, nil, nil
+	default:
+		return catalog.DifferenceTypeConflict, nil, nil
+	}
+}
+
 func evaluateSameBranch(leftEntry *DBScannerEntry, rightEntry *DBScannerEntry) catalog.DifferenceType {
 	if isNoneDiff(leftEntry, rightEntry) {
 		return catalog.DifferenceTypeNone
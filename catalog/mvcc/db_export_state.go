@@ -0,0 +1,149 @@
+package mvcc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// releaseExportedCommits prepares the exports of branchID for removing or rewriting its
+// commits after afterCommitID and before beforeCommitID.  It fails with ErrExportInProgress
+// while the branch exports, or while a ref export of one of these commits is in progress.  An export state at one of these commits is cleared, so the next
+// export of the branch exports all of its entries rather than a diff from a missing commit.
+func releaseExportedCommits(tx db.Tx, branchID int64, branch string, afterCommitID, beforeCommitID CommitID) error {
+	var state struct {
+		CurrentRef string                            `db:"current_ref"`
+		State      catalog.CatalogBranchExportStatus `db:"state"`
+	}
+	err := tx.Get(&state, `SELECT COALESCE(current_ref, '') current_ref, state
+		FROM catalog_branches_export_state
+		WHERE branch_id=$1 FOR UPDATE`, branchID)
+	missing := errors.Is(err, db.ErrNotFound)
+	if err != nil && !missing {
+		return fmt.Errorf("get export state: %w", err)
+	}
+	if !missing {
+		if state.State == catalog.ExportStatusInProgress {
+			return fmt.Errorf("branch: %w", catalog.ErrExportInProgress)
+		}
+		if isCommitRefInRange(state.CurrentRef, branch, afterCommitID, beforeCommitID) {
+			_, err := tx.Exec(`UPDATE catalog_branches_export_state SET current_ref = NULL WHERE branch_id=$1`, branchID)
+			if err != nil {
+				return fmt.Errorf("clear export state: %w", err)
+			}
+		}
+	}
+
+	var commitRefs []string
+	err = tx.Select(&commitRefs, `SELECT commit_ref FROM catalog_ref_exports
+		WHERE repository_id = (SELECT repository_id FROM catalog_branches WHERE id=$1) AND status=$2`,
+		branchID, catalog.ExportStatusInProgress)
+	if err != nil {
+		return fmt.Errorf("get ref exports: %w", err)
+	}
+	for _, commitRef := range commitRefs {
+		if isCommitRefInRange(commitRef, branch, afterCommitID, beforeCommitID) {
+			return fmt.Errorf("%s: %w", commitRef, catalog.ErrExportInProgress)
+		}
+	}
+	return nil
+}
+
+// isCommitRefInRange reports whether reference is a commit reference of branch after
+// afterCommitID and before beforeCommitID
+func isCommitRefInRange(reference, branch string, afterCommitID, beforeCommitID CommitID) bool {
+	ref, err := ParseRef(reference)
+	if err != nil || ref.Branch != branch || ref.Parents != "" {
+		return false
+	}
+	return ref.CommitID > afterCommitID && ref.CommitID < beforeCommitID
+}
+
+// renameExportRefs rewrites the references to branchID stored by its exports and by the ref
+// exports of repoID to reference the branch by newName.  It fails with ErrExportInProgress
+// while the branch or a ref export of it is in progress, as running exports still use the
+// old references.
+func renameExportRefs(tx db.Tx, repoID int, branchID int64, branch, newName string) error {
+	var state struct {
+		CurrentRef string                            `db:"current_ref"`
+		State      catalog.CatalogBranchExportStatus `db:"state"`
+	}
+	err := tx.Get(&state, `SELECT COALESCE(current_ref, '') current_ref, state
+		FROM catalog_branches_export_state
+		WHERE branch_id=$1 FOR UPDATE`, branchID)
+	missing := errors.Is(err, db.ErrNotFound)
+	if err != nil && !missing {
+		return fmt.Errorf("get export state: %w", err)
+	}
+	if !missing {
+		if state.State == catalog.ExportStatusInProgress {
+			return fmt.Errorf("branch: %w", catalog.ErrExportInProgress)
+		}
+		if renamed := renameRef(state.CurrentRef, branch, newName); renamed != state.CurrentRef {
+			_, err := tx.Exec(`UPDATE catalog_branches_export_state SET current_ref = $2 WHERE branch_id=$1`, branchID, renamed)
+			if err != nil {
+				return fmt.Errorf("rename export state: %w", err)
+			}
+		}
+	}
+
+	var runs []struct {
+		ExportID string `db:"export_id"`
+		FromRef  string `db:"from_ref"`
+		ToRef    string `db:"to_ref"`
+	}
+	if err := tx.Select(&runs, `SELECT export_id, from_ref, to_ref FROM catalog_branches_export_runs WHERE branch_id=$1`, branchID); err != nil {
+		return fmt.Errorf("get export runs: %w", err)
+	}
+	for _, run := range runs {
+		fromRef := renameRef(run.FromRef, branch, newName)
+		toRef := renameRef(run.ToRef, branch, newName)
+		if fromRef == run.FromRef && toRef == run.ToRef {
+			continue
+		}
+		_, err := tx.Exec(`UPDATE catalog_branches_export_runs SET from_ref = $2, to_ref = $3 WHERE export_id=$1`,
+			run.ExportID, fromRef, toRef)
+		if err != nil {
+			return fmt.Errorf("rename export run %s: %w", run.ExportID, err)
+		}
+	}
+
+	var refExports []struct {
+		ExportID  string                            `db:"export_id"`
+		Ref       string                            `db:"ref"`
+		CommitRef string                            `db:"commit_ref"`
+		Status    catalog.CatalogBranchExportStatus `db:"status"`
+	}
+	if err := tx.Select(&refExports, `SELECT export_id, ref, commit_ref, status FROM catalog_ref_exports WHERE repository_id=$1`, repoID); err != nil {
+		return fmt.Errorf("get ref exports: %w", err)
+	}
+	for _, export := range refExports {
+		ref := renameRef(export.Ref, branch, newName)
+		commitRef := renameRef(export.CommitRef, branch, newName)
+		if ref == export.Ref && commitRef == export.CommitRef {
+			continue
+		}
+		if export.Status == catalog.ExportStatusInProgress {
+			return fmt.Errorf("%s: %w", export.CommitRef, catalog.ErrExportInProgress)
+		}
+		_, err := tx.Exec(`UPDATE catalog_ref_exports SET ref = $2, commit_ref = $3 WHERE export_id=$1`,
+			export.ExportID, ref, commitRef)
+		if err != nil {
+			return fmt.Errorf("rename ref export %s: %w", export.ExportID, err)
+		}
+	}
+	return nil
+}
+
+// renameRef returns reference with its branch renamed to newName if it references branch,
+// otherwise reference itself
+func renameRef(reference, branch, newName string) string {
+	ref, err := ParseRef(reference)
+	if err != nil || ref.Branch != branch {
+		return reference
+	}
+	ref.Branch = newName
+	return ref.String()
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 	"github.com/treeverse/lakefs/testutil"
 )
@@ -91,7 +92,7 @@ func TestDBLineageScanner(t *testing.T) {
 		return nil, nil
 	})
 
-	_, err := c.Commit(ctx, repository, "b1", "commit to b1", "tester", nil)
+	_, err := c.Commit(ctx, repository, "b1", "commit to b1", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to b1", err)
 	_, _ = conn.Transact(func(tx db.Tx) (interface{}, error) {
 		lineageScannerB1U := NewDBLineageScanner(tx, b1BranchID, UncommittedID, scannerOpts)
@@ -105,7 +106,7 @@ func TestDBLineageScanner(t *testing.T) {
 		return nil, nil
 	})
 
-	_, err = c.Merge(ctx, repository, "b1", "b2", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "b1", "b2", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge b1 into b2", err)
 	_, _ = conn.Transact(func(tx db.Tx) (interface{}, error) {
 		lineageScannerB2U := NewDBLineageScanner(tx, b2BranchID, UncommittedID, scannerOpts)
@@ -129,7 +130,7 @@ func TestDBLineageScanner(t *testing.T) {
 		return nil, nil
 	})
 
-	_, err = c.Commit(ctx, repository, "b1", "commit to b1", "tester", nil)
+	_, err = c.Commit(ctx, repository, "b1", "commit to b1", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to b1", err)
 	_, _ = conn.Transact(func(tx db.Tx) (interface{}, error) {
 		lineageScannerB1U := NewDBLineageScanner(tx, b1BranchID, UncommittedID, scannerOpts)
@@ -143,7 +144,7 @@ func TestDBLineageScanner(t *testing.T) {
 		return nil, nil
 	})
 
-	_, err = c.Merge(ctx, repository, "b1", "b2", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "b1", "b2", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge b1 into b2", err)
 	_, _ = conn.Transact(func(tx db.Tx) (interface{}, error) {
 		lineageScannerB2U := NewDBLineageScanner(tx, b2BranchID, UncommittedID, scannerOpts)
@@ -154,9 +155,9 @@ func TestDBLineageScanner(t *testing.T) {
 	})
 
 	testCatalogerCreateEntry(t, ctx, c, repository, "b1", "Obj-0004", nil, "sd2")
-	_, err = c.Commit(ctx, repository, "b1", "commit to b1", "tester", nil)
+	_, err = c.Commit(ctx, repository, "b1", "commit to b1", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to b1", err)
-	_, err = c.Merge(ctx, repository, "b1", "b2", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "b1", "b2", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge b1 into b2", err)
 	testutil.MustDo(t, "delete committed file on b2",
 		c.DeleteEntry(ctx, repository, "b2", "Obj-0004"))
@@ -168,7 +169,7 @@ func TestDBLineageScanner(t *testing.T) {
 		return nil, nil
 	})
 
-	_, err = c.Commit(ctx, repository, "b2", "commit to b2", "tester", nil)
+	_, err = c.Commit(ctx, repository, "b2", "commit to b2", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to b1", err)
 	_, _ = conn.Transact(func(tx db.Tx) (interface{}, error) {
 		lineageScannerB2U := NewDBLineageScanner(tx, b2BranchID, UncommittedID, scannerOpts)
@@ -179,19 +180,19 @@ func TestDBLineageScanner(t *testing.T) {
 	})
 
 	testCatalogerCreateEntry(t, ctx, c, repository, "b0", "Obj-00041", nil, "sd4")
-	_, err = c.Commit(ctx, repository, "b0", "commit to b0", "tester", nil)
+	_, err = c.Commit(ctx, repository, "b0", "commit to b0", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to b0", err)
-	_, err = c.Merge(ctx, repository, "b0", "b1", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "b0", "b1", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge b0 into b1", err)
-	_, err = c.Merge(ctx, repository, "b1", "b2", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "b1", "b2", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge b1 into b2", err)
 
 	testCatalogerCreateEntry(t, ctx, c, repository, "b0", "Obj-0004", nil, "sd3")
-	_, err = c.Commit(ctx, repository, "b0", "commit to b0", "tester", nil)
+	_, err = c.Commit(ctx, repository, "b0", "commit to b0", "tester", nil, catalog.CommitParams{})
 	testutil.MustDo(t, "commit to b0", err)
-	_, err = c.Merge(ctx, repository, "b0", "b1", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "b0", "b1", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge b0 into b1", err)
-	_, err = c.Merge(ctx, repository, "b1", "b2", "tester", "", nil)
+	_, err = c.Merge(ctx, repository, "b1", "b2", "tester", "", nil, catalog.MergeParams{})
 	testutil.MustDo(t, "merge b1 into b2", err)
 	_, _ = conn.Transact(func(tx db.Tx) (interface{}, error) {
 		lineageScannerB2C := NewDBLineageScanner(tx, b2BranchID, CommittedID, scannerOpts)
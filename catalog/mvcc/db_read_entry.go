@@ -1,9 +1,11 @@
 package mvcc
 
 import (
+	"fmt"
 	"strconv"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 )
 
@@ -28,7 +30,8 @@ func sqEntryLineageSelect(tx db.Tx, branchID int64, commitID CommitID, filterDel
 		FromSelect(unionSelect, "c").
 		Distinct().Options("ON (path)").
 		OrderBy("path", "lineage_order")
-	finalSelect := sq.Select("path", "physical_address", "creation_date", "size", "checksum", "metadata", "is_expired").
+	finalSelect := sq.Select("path", "physical_address", "creation_date", "size", "checksum", "metadata", "is_expired",
+		"content_type", "content_encoding", "cache_control").
 		FromSelect(distinctSelect, "t")
 	if filterDeleted {
 		finalSelect = finalSelect.Where("max_commit = ?", MaxCommitID)
@@ -41,7 +44,8 @@ func sqEntryLineageSelect(tx db.Tx, branchID int64, commitID CommitID, filterDel
 // 2. If a path has multiple versions in various commits - Return the row with highest min commit
 // 3. If the version was deleted after the requested commit - the row max-commit will be set to uncommitted
 func sqEntryBranchSelect(branchID int64, commitID CommitID, paths []string) sq.SelectBuilder {
-	rawSelect := sq.Select("path", "physical_address", "creation_date", "size", "checksum", "metadata", "is_expired").
+	rawSelect := sq.Select("path", "physical_address", "creation_date", "size", "checksum", "metadata", "is_expired",
+		"content_type", "content_encoding", "cache_control").
 		Distinct().Options("ON (branch_id,path)").
 		From("catalog_entries").
 		Where("branch_id = ?", branchID).
@@ -67,3 +71,51 @@ func sqEntryBranchSelect(branchID int64, commitID CommitID, paths []string) sq.S
 	}
 	return rawSelect
 }
+
+// findEntries returns up to limit+1 entries after path after in commitID of branchID, that
+// match, ordered by path.  Only paths of entries on the branches of the lineage that satisfy
+// condition, an indexed SQL condition on catalog_entries with conditionArgs as its first
+// placeholders, are read.
+func findEntries(tx db.Tx, branchID int64, commitID CommitID, condition string, conditionArgs []interface{}, after string, limit int, match func(*catalog.Entry) bool) ([]*catalog.Entry, error) {
+	lineage, err := getLineage(tx, branchID, commitID)
+	if err != nil {
+		return nil, fmt.Errorf("get lineage: %w", err)
+	}
+	branchIDs := []int64{branchID}
+	for _, lc := range lineage {
+		branchIDs = append(branchIDs, lc.BranchID)
+	}
+	n := len(conditionArgs)
+	query := fmt.Sprintf(`SELECT DISTINCT path FROM catalog_entries
+		WHERE %s AND branch_id = ANY($%d::bigint[]) AND path > $%d
+		ORDER BY path
+		LIMIT $%d`, condition, n+1, n+2, n+3)
+
+	// the condition finds the paths of entries on some branch of the lineage, reading
+	// those paths at commitID keeps the entries that still match
+	var entries []*catalog.Entry
+	for len(entries) <= limit {
+		var paths []string
+		args := append(append([]interface{}{}, conditionArgs...), branchIDs, after, limit+1)
+		if err := tx.Select(&paths, query, args...); err != nil {
+			return nil, fmt.Errorf("select paths: %w", err)
+		}
+		if len(paths) == 0 {
+			break
+		}
+		byPath, err := readEntriesAt(tx, branchID, commitID, paths)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			if entry, ok := byPath[path]; ok && match(entry) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(paths) <= limit {
+			break
+		}
+		after = paths[len(paths)-1]
+	}
+	return entries, nil
+}
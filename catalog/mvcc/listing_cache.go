@@ -0,0 +1,431 @@
+package mvcc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	lru "github.com/hnlq715/golang-lru"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/logging"
+)
+
+const listingCacheKeyPrefix = "lakefs:listing:"
+
+// ListingCacheStore stores the results cached by the listing cache.  Results are cached under
+// the generations of their repository and branch, a write changes the generation of the
+// branch so results cached before it are no longer read.
+type ListingCacheStore interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, expiry time.Duration) error
+	// Generation returns the current generation of key
+	Generation(key string) (string, error)
+	// NextGeneration moves key to a new generation
+	NextGeneration(key string) error
+	Close() error
+}
+
+// newGeneration returns a generation that differs from all generations returned before it,
+// also after a store lost its generations
+func newGeneration() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// MemoryListingCacheStore is a ListingCacheStore keeping results in memory, for a single
+// lakeFS instance
+type MemoryListingCacheStore struct {
+	values *lru.Cache
+
+	mu          sync.Mutex
+	generations map[string]string
+}
+
+func NewMemoryListingCacheStore(size int) *MemoryListingCacheStore {
+	values, _ := lru.New(size)
+	return &MemoryListingCacheStore{
+		values:      values,
+		generations: make(map[string]string),
+	}
+}
+
+func (s *MemoryListingCacheStore) Get(key string) ([]byte, bool, error) {
+	v, ok := s.values.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return v.([]byte), true, nil
+}
+
+func (s *MemoryListingCacheStore) Set(key string, value []byte, expiry time.Duration) error {
+	s.values.AddEx(key, value, expiry)
+	return nil
+}
+
+func (s *MemoryListingCacheStore) Generation(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	generation, ok := s.generations[key]
+	if !ok {
+		generation = newGeneration()
+		s.generations[key] = generation
+	}
+	return generation, nil
+}
+
+func (s *MemoryListingCacheStore) NextGeneration(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generations[key] = newGeneration()
+	return nil
+}
+
+func (s *MemoryListingCacheStore) Close() error {
+	return nil
+}
+
+// listingCacheCataloger caches the entries listed and read by cataloger.  Results of commit
+// references are cached until they expire or an operation rewrites or removes commits of
+// the repository, results of branches are cached until a write to the branch.  Store
+// failures only skip the cache.
+type listingCacheCataloger struct {
+	catalog.Cataloger
+	store  ListingCacheStore
+	expiry time.Duration
+	log    logging.Logger
+}
+
+func newListingCacheCataloger(c catalog.Cataloger, store ListingCacheStore, expiry time.Duration) *listingCacheCataloger {
+	return &listingCacheCataloger{
+		Cataloger: c,
+		store:     store,
+		expiry:    expiry,
+		log:       logging.Default().WithField("service_name", "listing_cache"),
+	}
+}
+
+type listEntriesResult struct {
+	Entries []*catalog.Entry `json:"entries"`
+	HasMore bool             `json:"has_more"`
+}
+
+func (c *listingCacheCataloger) ListEntries(ctx context.Context, repository, reference string, prefix, after string, delimiter string, limit int) ([]*catalog.Entry, bool, error) {
+	key := c.key(repository, reference, "list", prefix, after, delimiter, strconv.Itoa(limit))
+	var result listEntriesResult
+	if key != "" && c.get(key, &result) {
+		return result.Entries, result.HasMore, nil
+	}
+	entries, hasMore, err := c.Cataloger.ListEntries(ctx, repository, reference, prefix, after, delimiter, limit)
+	if err != nil || key == "" {
+		return entries, hasMore, err
+	}
+	c.set(key, &listEntriesResult{Entries: entries, HasMore: hasMore})
+	return entries, hasMore, nil
+}
+
+func (c *listingCacheCataloger) GetEntry(ctx context.Context, repository, reference string, path string, params catalog.GetEntryParams) (*catalog.Entry, error) {
+	key := c.key(repository, reference, "get", path)
+	var entry *catalog.Entry
+	if key == "" || !c.get(key, &entry) {
+		var err error
+		// cache expired entries too, the expired error depends on params
+		entry, err = c.Cataloger.GetEntry(ctx, repository, reference, path, catalog.GetEntryParams{ReturnExpired: true})
+		if err != nil {
+			return entry, err
+		}
+		if key != "" {
+			c.set(key, entry)
+		}
+	}
+	if !params.ReturnExpired && entry != nil && entry.Expired {
+		return entry, catalog.ErrExpired
+	}
+	return entry, nil
+}
+
+// key returns the cache key of an operation on reference, or an empty key when the operation
+// can't be cached
+func (c *listingCacheCataloger) key(repository, reference string, parts ...string) string {
+	ref, err := ParseRef(reference)
+	if err != nil || ref.At != "" {
+		// a reference at a relative time moves without writes to its branch
+		return ""
+	}
+	repositoryGeneration, err := c.store.Generation(repositoryGenerationKey(repository))
+	if err != nil {
+		c.log.WithError(err).Warn("listing cache generation")
+		return ""
+	}
+	branchGeneration := ""
+	if ref.CommitID <= 0 {
+		// the uncommitted and committed references of a branch change on writes
+		branchGeneration, err = c.store.Generation(branchGenerationKey(repository, ref.Branch))
+		if err != nil {
+			c.log.WithError(err).Warn("listing cache generation")
+			return ""
+		}
+	}
+	h := sha256.New()
+	for _, part := range append([]string{repository, repositoryGeneration, branchGeneration, reference}, parts...) {
+		_, _ = h.Write([]byte(part))
+		_, _ = h.Write([]byte{0})
+	}
+	return listingCacheKeyPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *listingCacheCataloger) get(key string, v interface{}) bool {
+	data, ok, err := c.store.Get(key)
+	if err != nil {
+		c.log.WithError(err).Warn("listing cache get")
+		return false
+	}
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+func (c *listingCacheCataloger) set(key string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	if err := c.store.Set(key, data, c.expiry); err != nil {
+		c.log.WithError(err).Warn("listing cache set")
+	}
+}
+
+func repositoryGenerationKey(repository string) string {
+	return listingCacheKeyPrefix + "generation:" + repository
+}
+
+func branchGenerationKey(repository, branch string) string {
+	return listingCacheKeyPrefix + "generation:" + repository + "/" + branch
+}
+
+// invalidate moves the branches of repository to a new generation after they were written,
+// all branches when none are passed
+func (c *listingCacheCataloger) invalidate(repository string, branches ...string) {
+	keys := []string{repositoryGenerationKey(repository)}
+	if len(branches) > 0 {
+		keys = make([]string, len(branches))
+		for i, branch := range branches {
+			keys[i] = branchGenerationKey(repository, branch)
+		}
+	}
+	for _, key := range keys {
+		if err := c.store.NextGeneration(key); err != nil {
+			c.log.WithError(err).WithField("key", key).Error("listing cache invalidate")
+		}
+	}
+}
+
+func (c *listingCacheCataloger) CreateRepository(ctx context.Context, repository string, storageNamespace string, branch string) (*catalog.Repository, error) {
+	repo, err := c.Cataloger.CreateRepository(ctx, repository, storageNamespace, branch)
+	c.invalidate(repository)
+	return repo, err
+}
+
+func (c *listingCacheCataloger) DeleteRepository(ctx context.Context, repository string) error {
+	err := c.Cataloger.DeleteRepository(ctx, repository)
+	c.invalidate(repository)
+	return err
+}
+
+func (c *listingCacheCataloger) CreateBranch(ctx context.Context, repository, branch string, sourceBranch string) (*catalog.CommitLog, error) {
+	commitLog, err := c.Cataloger.CreateBranch(ctx, repository, branch, sourceBranch)
+	c.invalidate(repository, branch)
+	return commitLog, err
+}
+
+// DeleteBranch invalidates all branches of repository, commit references of branch no longer
+// resolve
+func (c *listingCacheCataloger) DeleteBranch(ctx context.Context, repository, branch string) error {
+	err := c.Cataloger.DeleteBranch(ctx, repository, branch)
+	c.invalidate(repository)
+	return err
+}
+
+// RenameBranch invalidates all branches of repository, commit references of branch no longer
+// resolve
+func (c *listingCacheCataloger) RenameBranch(ctx context.Context, repository, branch, newName string) error {
+	err := c.Cataloger.RenameBranch(ctx, repository, branch, newName)
+	c.invalidate(repository)
+	return err
+}
+
+// CreateTag invalidates the name of tag, it may have been cached as a missing branch
+func (c *listingCacheCataloger) CreateTag(ctx context.Context, repository, tag string, reference string) (*catalog.Tag, error) {
+	t, err := c.Cataloger.CreateTag(ctx, repository, tag, reference)
+	c.invalidate(repository, tag)
+	return t, err
+}
+
+func (c *listingCacheCataloger) DeleteTag(ctx context.Context, repository, tag string) error {
+	err := c.Cataloger.DeleteTag(ctx, repository, tag)
+	c.invalidate(repository, tag)
+	return err
+}
+
+// RecreateBranch invalidates all branches of repository, the commits of branch are removed
+func (c *listingCacheCataloger) RecreateBranch(ctx context.Context, repository, branch string, sourceBranch string) (*catalog.CommitLog, error) {
+	commitLog, err := c.Cataloger.RecreateBranch(ctx, repository, branch, sourceBranch)
+	c.invalidate(repository)
+	return commitLog, err
+}
+
+// ResetBranch invalidates all branches of repository, the commits after reference are removed
+func (c *listingCacheCataloger) ResetBranch(ctx context.Context, repository, branch, reference string, hard bool) error {
+	err := c.Cataloger.ResetBranch(ctx, repository, branch, reference, hard)
+	c.invalidate(repository)
+	return err
+}
+
+func (c *listingCacheCataloger) CreateEntry(ctx context.Context, repository, branch string, entry catalog.Entry, params catalog.CreateEntryParams) error {
+	err := c.Cataloger.CreateEntry(ctx, repository, branch, entry, params)
+	c.invalidate(repository, branch)
+	return err
+}
+
+func (c *listingCacheCataloger) CreateEntries(ctx context.Context, repository, branch string, entries []catalog.Entry) error {
+	err := c.Cataloger.CreateEntries(ctx, repository, branch, entries)
+	c.invalidate(repository, branch)
+	return err
+}
+
+func (c *listingCacheCataloger) DeleteEntry(ctx context.Context, repository, branch string, path string) error {
+	err := c.Cataloger.DeleteEntry(ctx, repository, branch, path)
+	c.invalidate(repository, branch)
+	return err
+}
+
+func (c *listingCacheCataloger) DeleteEntries(ctx context.Context, repository, branch string, paths []string) ([]error, error) {
+	errs, err := c.Cataloger.DeleteEntries(ctx, repository, branch, paths)
+	c.invalidate(repository, branch)
+	return errs, err
+}
+
+func (c *listingCacheCataloger) MoveEntry(ctx context.Context, repository, branch string, sourcePath, destinationPath string) error {
+	err := c.Cataloger.MoveEntry(ctx, repository, branch, sourcePath, destinationPath)
+	c.invalidate(repository, branch)
+	return err
+}
+
+func (c *listingCacheCataloger) MoveEntries(ctx context.Context, repository, branch string, sourcePrefix, destinationPrefix string) (int, error) {
+	count, err := c.Cataloger.MoveEntries(ctx, repository, branch, sourcePrefix, destinationPrefix)
+	c.invalidate(repository, branch)
+	return count, err
+}
+
+func (c *listingCacheCataloger) RestoreEntry(ctx context.Context, repository, branch string, path string) error {
+	err := c.Cataloger.RestoreEntry(ctx, repository, branch, path)
+	c.invalidate(repository, branch)
+	return err
+}
+
+func (c *listingCacheCataloger) ExpireLifecycle(ctx context.Context, repository string, dryRun bool) (*catalog.LifecycleReport, error) {
+	report, err := c.Cataloger.ExpireLifecycle(ctx, repository, dryRun)
+	if report != nil && report.Reference != "" {
+		c.invalidate(repository, report.Branch)
+	}
+	return report, err
+}
+
+// MigrateStorageNamespace invalidates all branches of repository, physical addresses of all of
+// them may have changed
+func (c *listingCacheCataloger) MigrateStorageNamespace(ctx context.Context, repository, storageNamespace string, params catalog.MigrateStorageNamespaceParams) (*catalog.StorageNamespaceMigration, error) {
+	migration, err := c.Cataloger.MigrateStorageNamespace(ctx, repository, storageNamespace, params)
+	c.invalidate(repository)
+	return migration, err
+}
+
+func (c *listingCacheCataloger) CopyEntry(ctx context.Context, sourceRepository, sourceReference, sourcePath, destinationRepository, destinationBranch, destinationPath string, params catalog.CopyEntryParams) (*catalog.Entry, error) {
+	entry, err := c.Cataloger.CopyEntry(ctx, sourceRepository, sourceReference, sourcePath, destinationRepository, destinationBranch, destinationPath, params)
+	c.invalidate(destinationRepository, destinationBranch)
+	return entry, err
+}
+
+func (c *listingCacheCataloger) ResetEntry(ctx context.Context, repository, branch string, path string) error {
+	err := c.Cataloger.ResetEntry(ctx, repository, branch, path)
+	c.invalidate(repository, branch)
+	return err
+}
+
+func (c *listingCacheCataloger) ResetEntries(ctx context.Context, repository, branch string, prefix string) error {
+	err := c.Cataloger.ResetEntries(ctx, repository, branch, prefix)
+	c.invalidate(repository, branch)
+	return err
+}
+
+func (c *listingCacheCataloger) MarkEntriesExpired(ctx context.Context, repositoryName string, expireResults []*catalog.ExpireResult) error {
+	err := c.Cataloger.MarkEntriesExpired(ctx, repositoryName, expireResults)
+	// entries are expired on commits too
+	c.invalidate(repositoryName)
+	return err
+}
+
+func (c *listingCacheCataloger) Commit(ctx context.Context, repository, branch string, message string, committer string, metadata catalog.Metadata, params catalog.CommitParams) (*catalog.CommitLog, error) {
+	commitLog, err := c.Cataloger.Commit(ctx, repository, branch, message, committer, metadata, params)
+	c.invalidate(repository, branch)
+	return commitLog, err
+}
+
+// AmendCommit invalidates all branches of repository, the amended commit keeps its reference
+// while its entries change
+func (c *listingCacheCataloger) AmendCommit(ctx context.Context, repository, branch string, message string, metadata catalog.Metadata, params catalog.AmendCommitParams) (*catalog.CommitLog, error) {
+	commitLog, err := c.Cataloger.AmendCommit(ctx, repository, branch, message, metadata, params)
+	c.invalidate(repository)
+	return commitLog, err
+}
+
+func (c *listingCacheCataloger) RunCommitJob(ctx context.Context, repository string, id int64) (*catalog.CommitJob, error) {
+	job, err := c.Cataloger.RunCommitJob(ctx, repository, id)
+	if job != nil {
+		c.invalidate(repository, job.Branch)
+	} else {
+		c.invalidate(repository)
+	}
+	return job, err
+}
+
+func (c *listingCacheCataloger) RollbackCommit(ctx context.Context, repository, reference string) error {
+	err := c.Cataloger.RollbackCommit(ctx, repository, reference)
+	c.invalidate(repository)
+	return err
+}
+
+func (c *listingCacheCataloger) Merge(ctx context.Context, repository, leftBranch, rightBranch, committer, message string, metadata catalog.Metadata, params catalog.MergeParams) (*catalog.MergeResult, error) {
+	result, err := c.Cataloger.Merge(ctx, repository, leftBranch, rightBranch, committer, message, metadata, params)
+	c.invalidate(repository, rightBranch)
+	return result, err
+}
+
+func (c *listingCacheCataloger) Cherrypick(ctx context.Context, repository, targetBranch, reference, committer string) (*catalog.MergeResult, error) {
+	result, err := c.Cataloger.Cherrypick(ctx, repository, targetBranch, reference, committer)
+	c.invalidate(repository, targetBranch)
+	return result, err
+}
+
+func (c *listingCacheCataloger) Revert(ctx context.Context, repository, branch, reference, sinceReference, committer string) (*catalog.MergeResult, error) {
+	result, err := c.Cataloger.Revert(ctx, repository, branch, reference, sinceReference, committer)
+	c.invalidate(repository, branch)
+	return result, err
+}
+
+// Rebase invalidates all branches of repository, the replayed commits of branch are removed
+func (c *listingCacheCataloger) Rebase(ctx context.Context, repository, branch, ontoReference string) (*catalog.MergeResult, error) {
+	result, err := c.Cataloger.Rebase(ctx, repository, branch, ontoReference)
+	c.invalidate(repository)
+	return result, err
+}
+
+func (c *listingCacheCataloger) Close() error {
+	err := c.Cataloger.Close()
+	if storeErr := c.store.Close(); err == nil {
+		err = storeErr
+	}
+	return err
+}
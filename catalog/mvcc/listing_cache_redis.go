@@ -0,0 +1,236 @@
+package mvcc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog/mvcc/params"
+)
+
+const (
+	redisDialTimeout = 2 * time.Second
+	redisIOTimeout   = 2 * time.Second
+	redisMaxIdle     = 16
+)
+
+var (
+	ErrRedisClosed   = errors.New("redis listing cache closed")
+	ErrRedisResponse = errors.New("unexpected redis response")
+)
+
+// RedisListingCacheStore is a ListingCacheStore keeping results in Redis, shared by all lakeFS
+// instances using it.  It speaks the subset of the Redis protocol it uses.
+type RedisListingCacheStore struct {
+	params params.ListingCacheRedis
+
+	mu     sync.Mutex
+	idle   []*redisConn
+	closed bool
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func NewRedisListingCacheStore(p params.ListingCacheRedis) *RedisListingCacheStore {
+	return &RedisListingCacheStore{params: p}
+}
+
+func (s *RedisListingCacheStore) Get(key string) ([]byte, bool, error) {
+	v, err := s.do("GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	if v == nil {
+		return nil, false, nil
+	}
+	data, ok := v.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("%w: GET returned %T", ErrRedisResponse, v)
+	}
+	return data, true, nil
+}
+
+func (s *RedisListingCacheStore) Set(key string, value []byte, expiry time.Duration) error {
+	_, err := s.do("SET", key, string(value), "PX", strconv.FormatInt(expiry.Milliseconds(), 10))
+	return err
+}
+
+func (s *RedisListingCacheStore) Generation(key string) (string, error) {
+	v, err := s.do("GET", key)
+	if err != nil {
+		return "", err
+	}
+	if v == nil {
+		// another instance may set the generation first, the stored generation is read back
+		if _, err := s.do("SET", key, newGeneration(), "NX"); err != nil {
+			return "", err
+		}
+		if v, err = s.do("GET", key); err != nil {
+			return "", err
+		}
+	}
+	data, ok := v.([]byte)
+	if !ok {
+		return "", fmt.Errorf("%w: GET returned %T", ErrRedisResponse, v)
+	}
+	return string(data), nil
+}
+
+func (s *RedisListingCacheStore) NextGeneration(key string) error {
+	_, err := s.do("SET", key, newGeneration())
+	return err
+}
+
+func (s *RedisListingCacheStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, c := range s.idle {
+		_ = c.conn.Close()
+	}
+	s.idle = nil
+	return nil
+}
+
+// do runs a command on an idle connection, or on a new connection when none is idle
+func (s *RedisListingCacheStore) do(args ...string) (interface{}, error) {
+	c, err := s.conn()
+	if err != nil {
+		return nil, err
+	}
+	v, err := c.do(args...)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		// the connection state is unknown after a network error
+		_ = c.conn.Close()
+		return nil, err
+	}
+	s.release(c)
+	return v, err
+}
+
+func (s *RedisListingCacheStore) conn() (*redisConn, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrRedisClosed
+	}
+	if n := len(s.idle); n > 0 {
+		c := s.idle[n-1]
+		s.idle = s.idle[:n-1]
+		s.mu.Unlock()
+		return c, nil
+	}
+	s.mu.Unlock()
+
+	conn, err := net.DialTimeout("tcp", s.params.Address, redisDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+	if s.params.Password != "" {
+		if _, err := c.do("AUTH", s.params.Password); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis auth: %w", err)
+		}
+	}
+	if s.params.DB != 0 {
+		if _, err := c.do("SELECT", strconv.Itoa(s.params.DB)); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis select: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (s *RedisListingCacheStore) release(c *redisConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || len(s.idle) >= redisMaxIdle {
+		_ = c.conn.Close()
+		return
+	}
+	s.idle = append(s.idle, c)
+}
+
+// redisError is an error reply of the Redis server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func (c *redisConn) do(args ...string) (interface{}, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(redisIOTimeout)); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply reads a reply: nil for a null reply, []byte for bulk strings, string for simple
+// strings, int64 for integers and []interface{} for arrays
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("%w: empty line", ErrRedisResponse)
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrRedisResponse, line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrRedisResponse, line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrRedisResponse, line)
+	}
+}
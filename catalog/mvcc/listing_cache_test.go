@@ -0,0 +1,226 @@
+package mvcc
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/catalog/mvcc/params"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+// countingCataloger counts the entries listed and read from it
+type countingCataloger struct {
+	catalog.Cataloger
+	lists int
+	gets  int
+}
+
+func (c *countingCataloger) ListEntries(_ context.Context, _, reference string, prefix, _ string, _ string, _ int) ([]*catalog.Entry, bool, error) {
+	c.lists++
+	return []*catalog.Entry{{Path: prefix + reference, Size: int64(c.lists)}}, false, nil
+}
+
+func (c *countingCataloger) GetEntry(_ context.Context, _, _ string, path string, params catalog.GetEntryParams) (*catalog.Entry, error) {
+	c.gets++
+	entry := &catalog.Entry{Path: path, Expired: strings.HasPrefix(path, "expired/")}
+	if entry.Expired && !params.ReturnExpired {
+		return entry, catalog.ErrExpired
+	}
+	return entry, nil
+}
+
+func (c *countingCataloger) CreateEntry(context.Context, string, string, catalog.Entry, catalog.CreateEntryParams) error {
+	return nil
+}
+
+func (c *countingCataloger) AmendCommit(context.Context, string, string, string, catalog.Metadata, catalog.AmendCommitParams) (*catalog.CommitLog, error) {
+	return &catalog.CommitLog{}, nil
+}
+
+func (c *countingCataloger) Close() error {
+	return nil
+}
+
+func TestListingCacheCataloger_ListEntries(t *testing.T) {
+	ctx := context.Background()
+	counting := &countingCataloger{}
+	c := newListingCacheCataloger(counting, NewMemoryListingCacheStore(100), time.Minute)
+	commitRef := MakeReference("master", 5)
+
+	list := func(reference, prefix string) {
+		t.Helper()
+		entries, _, err := c.ListEntries(ctx, "repo", reference, prefix, "", "/", 100)
+		testutil.MustDo(t, "list entries", err)
+		if len(entries) != 1 || entries[0].Path != prefix+reference {
+			t.Fatalf("unexpected entries %+v", entries)
+		}
+	}
+	expectLists := func(expected int) {
+		t.Helper()
+		if counting.lists != expected {
+			t.Fatalf("listed %d times, expected %d", counting.lists, expected)
+		}
+	}
+
+	list("master", "a/")
+	list("master", "a/")
+	expectLists(1)
+	list("master", "b/")
+	list("master:HEAD", "a/")
+	list(commitRef, "a/")
+	list(commitRef, "a/")
+	expectLists(4)
+
+	// a write to the branch invalidates its references, not its commits
+	testutil.MustDo(t, "create entry", c.CreateEntry(ctx, "repo", "master", catalog.Entry{Path: "a/1"}, catalog.CreateEntryParams{}))
+	list("master", "a/")
+	list("master:HEAD", "a/")
+	list(commitRef, "a/")
+	expectLists(6)
+
+	// a write to another branch doesn't
+	testutil.MustDo(t, "create entry", c.CreateEntry(ctx, "repo", "branch1", catalog.Entry{Path: "a/1"}, catalog.CreateEntryParams{}))
+	list("master", "a/")
+	expectLists(6)
+
+	// amending a commit changes the entries of its reference
+	_, err := c.AmendCommit(ctx, "repo", "master", "amended", nil, catalog.AmendCommitParams{IncludeChanges: true})
+	testutil.MustDo(t, "amend commit", err)
+	list(commitRef, "a/")
+	list(commitRef, "a/")
+	expectLists(7)
+}
+
+func TestListingCacheCataloger_GetEntry(t *testing.T) {
+	ctx := context.Background()
+	counting := &countingCataloger{}
+	c := newListingCacheCataloger(counting, NewMemoryListingCacheStore(100), time.Minute)
+
+	for i := 0; i < 2; i++ {
+		entry, err := c.GetEntry(ctx, "repo", "master", "expired/file", catalog.GetEntryParams{})
+		if !errors.Is(err, catalog.ErrExpired) || entry == nil {
+			t.Fatalf("expected expired entry, got %+v, %v", entry, err)
+		}
+		entry, err = c.GetEntry(ctx, "repo", "master", "expired/file", catalog.GetEntryParams{ReturnExpired: true})
+		if err != nil || entry == nil || !entry.Expired {
+			t.Fatalf("expected expired entry, got %+v, %v", entry, err)
+		}
+	}
+	if counting.gets != 1 {
+		t.Fatalf("read %d times, expected 1", counting.gets)
+	}
+}
+
+// fakeRedis serves the Redis commands used by RedisListingCacheStore
+type fakeRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, n)
+		for i := range args {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+			data := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, data); err != nil {
+				return
+			}
+			args[i] = string(data[:size])
+		}
+		_, _ = io.WriteString(conn, f.do(args))
+	}
+}
+
+func (f *fakeRedis) do(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "GET":
+		v, ok := f.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return "$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"
+	case "SET":
+		if len(args) > 3 && args[3] == "NX" {
+			if _, ok := f.values[args[1]]; ok {
+				return "$-1\r\n"
+			}
+		}
+		f.values[args[1]] = args[2]
+		return "+OK\r\n"
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+func TestRedisListingCacheStore(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.MustDo(t, "listen", err)
+	defer func() { _ = listener.Close() }()
+	server := &fakeRedis{values: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	store := NewRedisListingCacheStore(params.ListingCacheRedis{Address: listener.Addr().String()})
+	defer func() { _ = store.Close() }()
+
+	if _, ok, err := store.Get("missing"); err != nil || ok {
+		t.Fatalf("expected missing key, got %t, %v", ok, err)
+	}
+	testutil.MustDo(t, "set", store.Set("key", []byte("value\r\nwith newline"), time.Minute))
+	value, ok, err := store.Get("key")
+	if err != nil || !ok || string(value) != "value\r\nwith newline" {
+		t.Fatalf("got %q, %t, %v", value, ok, err)
+	}
+
+	generation, err := store.Generation("generation")
+	testutil.MustDo(t, "generation", err)
+	again, err := store.Generation("generation")
+	testutil.MustDo(t, "generation", err)
+	if generation != again {
+		t.Fatalf("generation changed from %s to %s", generation, again)
+	}
+	testutil.MustDo(t, "next generation", store.NextGeneration("generation"))
+	next, err := store.Generation("generation")
+	testutil.MustDo(t, "generation", err)
+	if next == generation {
+		t.Fatalf("generation %s didn't change", generation)
+	}
+
+	if _, err := store.do("UNKNOWN"); err == nil {
+		t.Fatal("expected error of unknown command")
+	}
+	// the connection is reused after an error reply
+	if _, _, err := store.Get("key"); err != nil {
+		t.Fatalf("get after error reply: %s", err)
+	}
+}
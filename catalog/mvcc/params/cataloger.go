@@ -9,6 +9,22 @@ type Cache struct {
 	Jitter  time.Duration
 }
 
+// ListingCache configures caching the results of listing and reading entries
+type ListingCache struct {
+	Enabled bool
+	Size    int
+	Expiry  time.Duration
+	// Redis shares the cache between lakeFS instances, the cache is kept in memory when its
+	// address is empty
+	Redis ListingCacheRedis
+}
+
+type ListingCacheRedis struct {
+	Address  string
+	Password string
+	DB       int
+}
+
 type BatchRead struct {
 	EntryMaxWait  time.Duration
 	ScanTimeout   time.Duration
@@ -21,8 +37,23 @@ type BatchWrite struct {
 	EntriesInsertSize int
 }
 
+// CommitJob configures commit jobs, that commit large changesets in chunks
+type CommitJob struct {
+	// ChunkSize is the number of entries committed in each transaction of a commit job
+	ChunkSize int
+}
+
+// Committer configures how commits are attributed to authenticated committers
+type Committer struct {
+	// RecordAccessKeyID records the access key of the committer in the metadata of commits
+	RecordAccessKeyID bool
+}
+
 type Catalog struct {
-	BatchRead  BatchRead
-	BatchWrite BatchWrite
-	Cache      Cache
+	BatchRead    BatchRead
+	BatchWrite   BatchWrite
+	Cache        Cache
+	ListingCache ListingCache
+	CommitJob    CommitJob
+	Committer    Committer
 }
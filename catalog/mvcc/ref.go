@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/mr-tron/base58"
@@ -22,6 +23,19 @@ const (
 	CommittedSuffix = ":HEAD"
 	CommitPrefix    = "~"
 
+	// AncestorOperator suffixed to a reference with a number N references the Nth first-parent
+	// ancestor of its commit, and ParentOperator with a number N its Nth parent.  The number
+	// defaults to 1.
+	AncestorOperator = '~'
+	ParentOperator   = '^'
+	// MaxRefParents is the maximal number of parents a reference walks
+	MaxRefParents = 1000
+
+	// TimePrefix and TimeSuffix enclose a time suffixed to a branch, referencing the last
+	// commit of the branch at that time
+	TimePrefix = "@{"
+	TimeSuffix = "}"
+
 	InternalObjectRefSeparator = "$"
 	InternalObjectRefFormat    = "int:pbm:%s"
 	InternalObjectRefParts     = 3
@@ -30,18 +44,27 @@ const (
 type Ref struct {
 	Branch   string
 	CommitID CommitID
+	// Parents are the ancestor and parent operators walking from the commit of the reference
+	// to the referenced commit
+	Parents string
+	// At is the time expression of a committed branch reference to the last commit of the
+	// branch at that time
+	At string
 }
 
 func (r Ref) String() string {
 	switch r.CommitID {
 	case CommittedID:
-		return r.Branch + CommittedSuffix
+		if r.At != "" {
+			return r.Branch + TimePrefix + r.At + TimeSuffix + r.Parents
+		}
+		return r.Branch + CommittedSuffix + r.Parents
 	case UncommittedID:
-		return r.Branch
+		return r.Branch + r.Parents
 	default:
 		ref := r.Branch + ":" + strconv.Itoa(int(r.CommitID))
 		encRef := base58.Encode([]byte(ref))
-		return CommitPrefix + encRef
+		return CommitPrefix + encRef + r.Parents
 	}
 }
 
@@ -49,7 +72,88 @@ func MakeReference(branch string, commitID CommitID) string {
 	return Ref{Branch: branch, CommitID: commitID}.String()
 }
 
+// ParseRef parses ref, a branch, committed branch, branch at a time or commit reference
+// followed by any number of ancestor and parent operators
 func ParseRef(ref string) (*Ref, error) {
+	base, parents := splitRefParents(ref)
+	if _, err := ParseRefParents(parents); err != nil {
+		return nil, err
+	}
+	r, err := parseBaseRef(base)
+	if err != nil {
+		return nil, err
+	}
+	r.Parents = parents
+	return r, nil
+}
+
+// splitRefParents splits ref to its base reference and its ancestor and parent operators.  A
+// commit reference starts with the ancestor operator, so operators are looked for after the
+// first character.
+func splitRefParents(ref string) (string, string) {
+	if ref == "" {
+		return ref, ""
+	}
+	i := strings.IndexAny(ref[1:], string([]rune{AncestorOperator, ParentOperator}))
+	if i < 0 {
+		return ref, ""
+	}
+	return ref[:i+1], ref[i+1:]
+}
+
+// ParseRefParents returns the parents walked by the ancestor and parent operators of
+// parents, in order.  Parent 1 is the previous commit of a branch, or the source commit of a
+// branch creation commit, and parent 2 is the commit merged by a merge commit.
+func ParseRefParents(parents string) ([]int, error) {
+	var walk []int
+	for len(parents) > 0 {
+		op := rune(parents[0])
+		digits := 1
+		for digits < len(parents) && parents[digits] >= '0' && parents[digits] <= '9' {
+			digits++
+		}
+		n := 1
+		if digits > 1 {
+			var err error
+			n, err = strconv.Atoi(parents[1:digits])
+			if err != nil || n > MaxRefParents {
+				return nil, fmt.Errorf("%w: invalid number %s", catalog.ErrInvalidReference, parents[1:digits])
+			}
+		}
+		parents = parents[digits:]
+		switch op {
+		case AncestorOperator:
+			for i := 0; i < n; i++ {
+				walk = append(walk, 1)
+			}
+		case ParentOperator:
+			// parent 0 is the commit itself
+			if n > 0 {
+				walk = append(walk, n)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unexpected %c", catalog.ErrInvalidReference, op)
+		}
+		if len(walk) > MaxRefParents {
+			return nil, fmt.Errorf("%w: more than %d parents", catalog.ErrInvalidReference, MaxRefParents)
+		}
+	}
+	return walk, nil
+}
+
+func parseBaseRef(ref string) (*Ref, error) {
+	// branch at a time
+	if i := strings.Index(ref, TimePrefix); i > 0 && strings.HasSuffix(ref, TimeSuffix) {
+		at := ref[i+len(TimePrefix) : len(ref)-len(TimeSuffix)]
+		if _, err := ParseRefTime(at, time.Now()); err != nil {
+			return nil, err
+		}
+		return &Ref{
+			Branch:   ref[:i],
+			CommitID: CommittedID,
+			At:       at,
+		}, nil
+	}
 	// committed branch
 	if strings.HasSuffix(ref, CommittedSuffix) {
 		return &Ref{
@@ -0,0 +1,61 @@
+package mvcc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+// resolveRefParents walks parents from the commit of ref, the last commit of a branch
+// reference, and returns a reference to the commit it reaches
+func (c *cataloger) resolveRefParents(tx db.Tx, repository string, ref *Ref, parents []int) (*Ref, error) {
+	branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+	if err != nil {
+		return nil, err
+	}
+	commitID := ref.CommitID
+	if commitID == UncommittedID || commitID == CommittedID {
+		commitID, err = getLastCommitIDByBranchID(tx, branchID)
+		if err != nil {
+			return nil, fmt.Errorf("get last commit id: %w", err)
+		}
+	}
+	for _, parent := range parents {
+		var commit struct {
+			PreviousCommitID  CommitID `db:"previous_commit_id"`
+			MergeSourceBranch int64    `db:"merge_source_branch"`
+			MergeSourceCommit CommitID `db:"merge_source_commit"`
+		}
+		// a squash merge commit has no merged parent
+		err := tx.Get(&commit, `SELECT previous_commit_id,
+				CASE WHEN squash THEN 0 ELSE COALESCE(merge_source_branch, 0) END AS merge_source_branch,
+				CASE WHEN squash THEN 0 ELSE COALESCE(merge_source_commit, 0) END AS merge_source_commit
+			FROM catalog_commits
+			WHERE branch_id = $1 AND commit_id = $2`, branchID, commitID)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, catalog.ErrCommitNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get commit: %w", err)
+		}
+		hasMergeSource := commit.MergeSourceBranch != 0 && commit.MergeSourceCommit > 0
+		switch {
+		case parent == 1 && commit.PreviousCommitID > 0:
+			commitID = commit.PreviousCommitID
+		case parent == 1 && hasMergeSource:
+			// the creation commit of a branch continues its source branch
+			branchID, commitID = commit.MergeSourceBranch, commit.MergeSourceCommit
+		case parent == 2 && commit.PreviousCommitID > 0 && hasMergeSource:
+			branchID, commitID = commit.MergeSourceBranch, commit.MergeSourceCommit
+		default:
+			return nil, fmt.Errorf("parent %d of commit %d: %w", parent, commitID, catalog.ErrCommitNotFound)
+		}
+	}
+	var branch string
+	if err := tx.GetPrimitive(&branch, `SELECT name FROM catalog_branches WHERE id = $1`, branchID); err != nil {
+		return nil, fmt.Errorf("get branch: %w", err)
+	}
+	return &Ref{Branch: branch, CommitID: commitID}, nil
+}
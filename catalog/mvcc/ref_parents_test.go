@@ -0,0 +1,57 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ResolveRefParents(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	commit := func(branch, path string) string {
+		testCatalogerCreateEntry(t, ctx, c, repository, branch, path, nil, "")
+		commitLog, err := c.Commit(ctx, repository, branch, "commit "+path, "tester", nil, catalog.CommitParams{})
+		testutil.MustDo(t, "commit "+path, err)
+		return commitLog.Reference
+	}
+	commit1 := commit("master", "file1")
+	commit2 := commit("master", "file2")
+	created, err := c.CreateBranch(ctx, repository, "branch1", "master")
+	testutil.MustDo(t, "create branch1", err)
+	commit3 := commit("branch1", "file3")
+	_, err = c.Merge(ctx, repository, "branch1", "master", "tester", "merge branch1", nil, catalog.MergeParams{})
+	testutil.MustDo(t, "merge branch1", err)
+
+	tests := []struct {
+		reference string
+		want      string
+		wantErr   error
+	}{
+		{reference: "master~1", want: commit2},
+		{reference: "master:HEAD~2", want: commit1},
+		{reference: "master^", want: commit2},
+		{reference: "master^2", want: commit3},
+		{reference: "master^2~1", want: created.Reference},
+		{reference: "master^2~2", want: commit2},
+		{reference: "branch1^0", want: commit3},
+		{reference: commit2 + "~1", want: commit1},
+		{reference: "master^3", wantErr: catalog.ErrCommitNotFound},
+		{reference: "master~100", wantErr: catalog.ErrCommitNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			got, err := c.GetCommit(ctx, repository, tt.reference)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetCommit() err=%v, expected %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.Reference != tt.want {
+				t.Errorf("GetCommit() reference %s, expected %s", got.Reference, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRef_String(t *testing.T) {
@@ -90,6 +91,48 @@ func TestParseRef(t *testing.T) {
 			want:    &Ref{},
 			wantErr: false,
 		},
+		{
+			name:    "branch ancestor",
+			args:    args{ref: "main~2"},
+			want:    &Ref{Branch: "main", CommitID: UncommittedID, Parents: "~2"},
+			wantErr: false,
+		},
+		{
+			name:    "committed parents",
+			args:    args{ref: "main:HEAD^2~"},
+			want:    &Ref{Branch: "main", CommitID: CommittedID, Parents: "^2~"},
+			wantErr: false,
+		},
+		{
+			name:    "commit parent",
+			args:    args{ref: "~6kfQBz477AZCUw^"},
+			want:    &Ref{Branch: "feature", CommitID: 10, Parents: "^"},
+			wantErr: false,
+		},
+		{
+			name:    "branch at time",
+			args:    args{ref: "main@{2020-10-01}~1"},
+			want:    &Ref{Branch: "main", CommitID: CommittedID, At: "2020-10-01", Parents: "~1"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid time",
+			args:    args{ref: "main@{tomorrow}"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "invalid parent",
+			args:    args{ref: "main~x"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "too many parents",
+			args:    args{ref: "main~1001"},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -105,6 +148,65 @@ func TestParseRef(t *testing.T) {
 	}
 }
 
+func TestParseRefParents(t *testing.T) {
+	tests := []struct {
+		parents string
+		want    []int
+		wantErr bool
+	}{
+		{parents: "", want: nil},
+		{parents: "~", want: []int{1}},
+		{parents: "~3", want: []int{1, 1, 1}},
+		{parents: "^", want: []int{1}},
+		{parents: "^2", want: []int{2}},
+		{parents: "^0", want: nil},
+		{parents: "^2~2^", want: []int{2, 1, 1, 1}},
+		{parents: "~-1", wantErr: true},
+		{parents: "~1000^2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.parents, func(t *testing.T) {
+			got, err := ParseRefParents(tt.parents)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRefParents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRefParents() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRefTime(t *testing.T) {
+	now := time.Date(2020, 10, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{expr: "now", want: now},
+		{expr: "yesterday", want: now.Add(-24 * time.Hour)},
+		{expr: "2020-10-01", want: time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "2020-10-01T10:30:00+02:00", want: time.Date(2020, 10, 1, 8, 30, 0, 0, time.UTC)},
+		{expr: "3.hours.ago", want: now.Add(-3 * time.Hour)},
+		{expr: "1 week ago", want: now.Add(-7 * 24 * time.Hour)},
+		{expr: "2.fortnights.ago", wantErr: true},
+		{expr: "-1.days.ago", wantErr: true},
+		{expr: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseRefTime(tt.expr, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRefTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseRefTime() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseInternalObjectRef(t *testing.T) {
 	// Internal representation is _not_ user-visible, test just round-trip encode and parse.
 	tests := []struct {
@@ -0,0 +1,73 @@
+package mvcc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+)
+
+const refDateFormat = "2006-01-02"
+
+var refTimeUnits = map[string]time.Duration{
+	"second": time.Second,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+}
+
+// ParseRefTime returns the time of expr relative to now.  expr is an RFC 3339 time, a date
+// (its midnight in UTC), "now", "yesterday" (24 hours ago) or an age of the form
+// "<n>.<unit>.ago" or "<n> <unit> ago", with units of seconds, minutes, hours, days or weeks.
+func ParseRefTime(expr string, now time.Time) (time.Time, error) {
+	switch expr {
+	case "now":
+		return now, nil
+	case "yesterday":
+		return now.Add(-24 * time.Hour), nil
+	}
+	if t, err := time.Parse(time.RFC3339, expr); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(refDateFormat, expr); err == nil {
+		return t, nil
+	}
+	fields := strings.FieldsFunc(expr, func(r rune) bool { return r == '.' || r == ' ' })
+	const ageFields = 3
+	if len(fields) == ageFields && fields[2] == "ago" {
+		n, err := strconv.Atoi(fields[0])
+		unit, ok := refTimeUnits[strings.TrimSuffix(fields[1], "s")]
+		if err == nil && ok && n >= 0 && int64(n) <= math.MaxInt64/int64(unit) {
+			return now.Add(-time.Duration(n) * unit), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid time %s", catalog.ErrInvalidReference, expr)
+}
+
+// resolveRefTime resolves a branch reference at a time to the last commit of the branch
+// created at or before that time
+func (c *cataloger) resolveRefTime(tx db.Tx, repository string, ref *Ref) (*Ref, error) {
+	at, err := ParseRefTime(ref.At, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	branchID, err := c.getBranchIDCache(tx, repository, ref.Branch)
+	if err != nil {
+		return nil, err
+	}
+	var commitID CommitID
+	err = tx.GetPrimitive(&commitID, `SELECT COALESCE(MAX(commit_id), 0) FROM catalog_commits
+		WHERE branch_id = $1 AND creation_date <= $2`, branchID, at)
+	if err != nil {
+		return nil, fmt.Errorf("get commit at %s: %w", at, err)
+	}
+	if commitID == 0 {
+		return nil, fmt.Errorf("%s at %s: %w", ref.Branch, at.Format(time.RFC3339), catalog.ErrCommitNotFound)
+	}
+	return &Ref{Branch: ref.Branch, CommitID: commitID}, nil
+}
@@ -0,0 +1,52 @@
+package mvcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestCataloger_ResolveRefTime(t *testing.T) {
+	ctx := context.Background()
+	c := testCataloger(t)
+	repository := testCatalogerRepo(t, ctx, c, "repo", "master")
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file1", nil, "")
+	commit1, err := c.Commit(ctx, repository, "master", "commit file1", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit file1", err)
+	testCatalogerCreateEntry(t, ctx, c, repository, "master", "file2", nil, "")
+	commit2, err := c.Commit(ctx, repository, "master", "commit file2", "tester", nil, catalog.CommitParams{})
+	testutil.MustDo(t, "commit file2", err)
+
+	tests := []struct {
+		reference string
+		want      string
+		wantErr   error
+	}{
+		{reference: "master@{now}", want: commit2.Reference},
+		{reference: "master@{" + commit1.CreationDate.Format(time.RFC3339Nano) + "}", want: commit1.Reference},
+		{reference: "master@{now}~1", want: commit1.Reference},
+		{reference: "master@{2000-01-01}", wantErr: catalog.ErrCommitNotFound},
+		{reference: "no-branch@{now}", wantErr: catalog.ErrBranchNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			got, err := c.GetCommit(ctx, repository, tt.reference)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetCommit() err=%v, expected %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.Reference != tt.want {
+				t.Errorf("GetCommit() reference %s, expected %s", got.Reference, tt.want)
+			}
+		})
+	}
+
+	// a branch at a time is read-only
+	err = c.CreateEntry(ctx, repository, "master@{now}", catalog.Entry{Path: "file3", Checksum: "ff", PhysicalAddress: "file3"}, catalog.CreateEntryParams{})
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("CreateEntry() on branch at time err=%v, expected %s", err, catalog.ErrInvalidValue)
+	}
+}
@@ -54,6 +54,18 @@ func IsValidRepositoryName(repository string) bool {
 	return validRepositoryNameRegexp.MatchString(repository)
 }
 
+func ValidateOrganizationName(organization string) ValidateFunc {
+	return func() bool {
+		return IsValidOrganizationName(organization)
+	}
+}
+
+// IsValidOrganizationName returns true if organization is a valid organization name, named
+// like repositories
+func IsValidOrganizationName(organization string) bool {
+	return validRepositoryNameRegexp.MatchString(organization)
+}
+
 func ValidateReference(reference string) ValidateFunc {
 	return func() bool {
 		return IsValidReference(reference)
@@ -74,6 +86,13 @@ func IsValidReference(reference string) bool {
 	return true
 }
 
+// IsValidCommitReference returns true if reference is a reference to a commit, without
+// ancestor or parent operators
+func IsValidCommitReference(reference string) bool {
+	ref, err := ParseRef(reference)
+	return err == nil && ref.CommitID > UncommittedID && ref.Parents == ""
+}
+
 func ValidateUploadID(uploadID string) ValidateFunc {
 	return func() bool {
 		return IsNonEmptyString(uploadID)
@@ -104,6 +123,12 @@ func ValidateCommitter(name string) ValidateFunc {
 	}
 }
 
+func ValidateLockOwner(owner string) ValidateFunc {
+	return func() bool {
+		return IsNonEmptyString(owner)
+	}
+}
+
 func ValidateStorageNamespace(storageNamespace string) ValidateFunc {
 	return func() bool {
 		return IsNonEmptyString(storageNamespace)
@@ -118,3 +143,15 @@ func ValidateOptionalString(s string, validator func(string) bool) ValidateFunc
 		return validator(s)
 	}
 }
+
+// IsValidRegexp reports whether pattern is a valid regular expression
+func IsValidRegexp(pattern string) bool {
+	_, err := regexp.Compile(pattern)
+	return err == nil
+}
+
+func ValidateSearchQuery(query string) ValidateFunc {
+	return func() bool {
+		return len(searchTerms(query)) > 0
+	}
+}
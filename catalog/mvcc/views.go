@@ -55,7 +55,8 @@ func sqEntriesLineage(branchID int64, requestedCommit CommitID, lineage []lineag
 			"e.path", "e.branch_id AS source_branch",
 			"e.min_commit", "e.physical_address",
 			"e.creation_date", "e.size", "e.checksum", "e.metadata",
-			"e.is_committed", "e.is_tombstone", "e.entry_ctid", "e.is_expired").
+			"e.is_committed", "e.is_tombstone", "e.entry_ctid", "e.is_expired",
+			"e.content_type", "e.content_encoding", "e.cache_control").
 		Column(maxCommitAlias).Column(isDeletedAlias)
 	return baseSelect
 }
@@ -92,7 +93,8 @@ func sqEntriesLineageV(branchID int64, requestedCommit CommitID, lineage []linea
 		Columns("e.path", "e.branch_id AS source_branch",
 			"e.min_commit", "e.physical_address",
 			"e.creation_date", "e.size", "e.checksum", "e.metadata",
-			"e.is_committed", "e.is_tombstone", "e.entry_ctid", "e.is_expired").
+			"e.is_committed", "e.is_tombstone", "e.entry_ctid", "e.is_expired",
+			"e.content_type", "e.content_encoding", "e.cache_control").
 		Column(maxCommitAlias).Column(isDeletedAlias)
 	return baseSelect
 }
@@ -0,0 +1,54 @@
+package catalog
+
+// RepositoryQuota limits the resources a repository may consume.  A zero value for a field means
+// no limit.  A repository of an organization is also limited by the quota of the organization,
+// shared by all of its repositories.
+type RepositoryQuota struct {
+	MaxStorageBytes int64 `db:"max_storage_bytes" json:"max_storage_bytes"`
+	MaxObjects      int64 `db:"max_objects" json:"max_objects"`
+	// MaxRequestsPerSecond limits the API and S3 gateway requests to the repository, each
+	// lakeFS server enforces it on the requests it serves
+	MaxRequestsPerSecond int64 `db:"max_requests_per_second" json:"max_requests_per_second"`
+	// MaxExportBytesPerSecond limits the bytes copied by exports of the repository, each
+	// lakeFS server enforces it on the copies it performs
+	MaxExportBytesPerSecond int64 `db:"max_export_bytes_per_second" json:"max_export_bytes_per_second"`
+	// Organization names the organization the repository belongs to, empty for none
+	Organization string `db:"organization" json:"organization,omitempty"`
+}
+
+// OrganizationQuota limits the resources consumed together by the repositories of an
+// organization.  A zero value for a field means no limit.
+type OrganizationQuota struct {
+	MaxStorageBytes         int64 `db:"max_storage_bytes" json:"max_storage_bytes"`
+	MaxObjects              int64 `db:"max_objects" json:"max_objects"`
+	MaxRequestsPerSecond    int64 `db:"max_requests_per_second" json:"max_requests_per_second"`
+	MaxExportBytesPerSecond int64 `db:"max_export_bytes_per_second" json:"max_export_bytes_per_second"`
+}
+
+// RepositoryUsage reports the resources currently used by a repository, or by the repositories
+// of an organization.  Objects are counted once per physical address of a repository, even
+// when referenced by entries on several branches or commits.
+type RepositoryUsage struct {
+	StorageBytes int64 `db:"storage_bytes" json:"storage_bytes"`
+	Objects      int64 `db:"objects" json:"objects"`
+}
+
+// Exceeds returns true if usage is over any storage limit set by quota.
+func (q *RepositoryQuota) Exceeds(usage *RepositoryUsage) bool {
+	return exceedsStorage(q.MaxStorageBytes, q.MaxObjects, usage)
+}
+
+// Exceeds returns true if usage is over any storage limit set by quota.
+func (q *OrganizationQuota) Exceeds(usage *RepositoryUsage) bool {
+	return exceedsStorage(q.MaxStorageBytes, q.MaxObjects, usage)
+}
+
+func exceedsStorage(maxStorageBytes, maxObjects int64, usage *RepositoryUsage) bool {
+	if maxStorageBytes > 0 && usage.StorageBytes > maxStorageBytes {
+		return true
+	}
+	if maxObjects > 0 && usage.Objects > maxObjects {
+		return true
+	}
+	return false
+}
@@ -6,6 +6,9 @@ import (
 	"time"
 )
 
+// RetentionPolicyConfigKey is the repository configuration key holding the retention policy
+const RetentionPolicyConfigKey = "retentionPolicy"
+
 // Avoid rounding by keeping whole hours (not Durations)
 type TimePeriodHours int
 
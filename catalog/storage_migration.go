@@ -0,0 +1,39 @@
+package catalog
+
+import "time"
+
+// ChecksumObjectFunc returns the checksum of the object of size at address of namespace,
+// computed as it is on upload
+type ChecksumObjectFunc func(namespace, address string, size int64) (string, error)
+
+// StorageNamespaceMigrationProgressFunc is called with the progress of a storage namespace
+// migration after each migrated batch
+type StorageNamespaceMigrationProgressFunc func(migration *StorageNamespaceMigration)
+
+// MigrateStorageNamespaceParams configures how MigrateStorageNamespace copies and verifies
+// the objects of a repository
+type MigrateStorageNamespaceParams struct {
+	// CopyObject copies an object to the new storage namespace.  Relative physical
+	// addresses should be kept, so the object is found while the repository is cached
+	// with its previous storage namespace.
+	CopyObject CopyObjectFunc
+	// ChecksumObject computes the checksum of copied objects to verify them
+	ChecksumObject ChecksumObjectFunc
+	// BatchSize is the number of objects copied before their physical addresses are
+	// updated, zero uses the default
+	BatchSize int
+	// Progress, if set, is called after each batch
+	Progress StorageNamespaceMigrationProgressFunc
+}
+
+// StorageNamespaceMigration is the progress of copying the objects of a repository from
+// SourceNamespace to StorageNamespace
+type StorageNamespaceMigration struct {
+	Repository       string    `db:"repository"`
+	SourceNamespace  string    `db:"source_namespace"`
+	StorageNamespace string    `db:"storage_namespace"`
+	MigratedObjects  int64     `db:"migrated_objects"`
+	StartedDate      time.Time `db:"started_date"`
+	UpdatedDate      time.Time `db:"updated_date"`
+	Completed        bool
+}
@@ -0,0 +1,17 @@
+package catalog
+
+import "time"
+
+// RepositoryTrash configures keeping entries deleted from the branches of a repository in its
+// trash, from where they can be restored for RetentionDays.  Zero RetentionDays disables the
+// trash.
+type RepositoryTrash struct {
+	RetentionDays int `db:"retention_days" json:"retention_days"`
+}
+
+// TrashEntry is an entry deleted from a branch, that can be restored until ExpirationDate
+type TrashEntry struct {
+	Entry
+	DeletionDate   time.Time `db:"deletion_date"`
+	ExpirationDate time.Time `db:"expiration_date"`
+}
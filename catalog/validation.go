@@ -0,0 +1,66 @@
+package catalog
+
+import (
+	"strings"
+)
+
+// CommitValidation describes a commit before anything is written, as passed to PreCommit hooks
+type CommitValidation struct {
+	Repository string
+	Branch     string
+	Committer  string
+	Message    string
+	Metadata   Metadata
+	Params     CommitParams
+	// Changes returns the uncommitted changes the commit includes, ordered by path
+	Changes func() (Differences, error)
+}
+
+// MergeValidation describes a merge before anything is written, as passed to PreMerge hooks
+type MergeValidation struct {
+	Repository        string
+	SourceBranch      string
+	DestinationBranch string
+	Committer         string
+	Message           string
+	Metadata          Metadata
+	Params            MergeParams
+	// Changes returns the differences the merge applies to the destination branch, ordered
+	// by path and including conflicts
+	Changes func() (Differences, error)
+}
+
+// ValidationViolation is a rule a commit or merge breaks, of Path or of the whole operation
+// when Path is empty
+type ValidationViolation struct {
+	Path    string
+	Message string
+}
+
+// ValidationError rejects a commit or merge with the violations a validation hook found.  It
+// matches ErrValidationFailed.
+type ValidationError struct {
+	Violations []ValidationViolation
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString(ErrValidationFailed.Error())
+	for i, v := range e.Violations {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		if v.Path != "" {
+			b.WriteString(v.Path)
+			b.WriteString(": ")
+		}
+		b.WriteString(v.Message)
+	}
+	return b.String()
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
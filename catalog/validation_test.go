@@ -0,0 +1,23 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	err := fmt.Errorf("commit: %w", &ValidationError{
+		Violations: []ValidationViolation{
+			{Path: "tables/a", Message: "missing owner"},
+			{Message: "message must reference a ticket"},
+		},
+	})
+	if !errors.Is(err, ErrValidationFailed) {
+		t.Errorf("errors.Is(%s, ErrValidationFailed) = false", err)
+	}
+	const expected = "commit: validation failed: tables/a: missing owner; message must reference a ticket"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, expected %q", err.Error(), expected)
+	}
+}
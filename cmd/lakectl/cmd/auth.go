@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/api/gen/models"
 )
@@ -29,6 +30,11 @@ var credentialsCreatedTemplate = `{{ "Credentials created successfully." | green
 {{ "Keep these somewhere safe since you will not be able to see the secret key again" | yellow }}
 `
 
+var subscriptionCreatedTemplate = `{{ "Subscription created successfully." | green }}
+ID: {{ .ID | bold }}
+Creation Date: {{  .CreationDate | date }}
+`
+
 var policyDetailsTemplate = `
 ID: {{ .ID | bold }}
 Creation Date: {{  .CreationDate | date }}
@@ -292,6 +298,104 @@ var authUsersCredentialsList = &cobra.Command{
 	},
 }
 
+var authUsersSubscriptions = &cobra.Command{
+	Use:   "subscriptions",
+	Short: "manage user notification subscriptions",
+}
+
+var authUsersSubscriptionsList = &cobra.Command{
+	Use:   "list",
+	Short: "list user notification subscriptions",
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := cmd.Flags().GetString("id")
+
+		clt := getClient()
+		if id == "" {
+			user, err := clt.GetCurrentUser(context.Background())
+			if err != nil {
+				DieErr(err)
+			}
+			id = user.ID
+		}
+
+		subscriptions, err := clt.ListUserSubscriptions(context.Background(), id)
+		if err != nil {
+			DieErr(err)
+		}
+
+		rows := make([][]interface{}, len(subscriptions))
+		for i, s := range subscriptions {
+			ts := time.Unix(swag.Int64Value(s.CreationDate), 0).String()
+			rows[i] = []interface{}{swag.Int64Value(s.ID), s.Repository, s.Branch, swag.StringValue(s.EventType), swag.StringValue(s.Channel), swag.StringValue(s.Address), ts}
+		}
+
+		PrintTable(rows, []interface{}{"ID", "Repository", "Branch", "Event Type", "Channel", "Address", "Creation Date"}, nil, 0)
+	},
+}
+
+var authUsersSubscriptionsCreate = &cobra.Command{
+	Use:   "create",
+	Short: "subscribe user to notifications",
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := cmd.Flags().GetString("id")
+		repository, _ := cmd.Flags().GetString("repository")
+		branch, _ := cmd.Flags().GetString("branch")
+		eventType, _ := cmd.Flags().GetString("event-type")
+		channel, _ := cmd.Flags().GetString("channel")
+		address, _ := cmd.Flags().GetString("address")
+
+		clt := getClient()
+		if id == "" {
+			user, err := clt.GetCurrentUser(context.Background())
+			if err != nil {
+				DieErr(err)
+			}
+			id = user.ID
+		}
+
+		subscription, err := clt.CreateUserSubscription(context.Background(), id, &models.NotificationSubscriptionCreation{
+			Repository: repository,
+			Branch:     branch,
+			EventType:  swag.String(eventType),
+			Channel:    swag.String(channel),
+			Address:    swag.String(address),
+		})
+		if err != nil {
+			DieErr(err)
+		}
+
+		Write(subscriptionCreatedTemplate, struct {
+			ID           int64
+			CreationDate int64
+		}{swag.Int64Value(subscription.ID), swag.Int64Value(subscription.CreationDate)})
+	},
+}
+
+var authUsersSubscriptionsDelete = &cobra.Command{
+	Use:   "delete",
+	Short: "delete user notification subscription",
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := cmd.Flags().GetString("id")
+		subscriptionID, _ := cmd.Flags().GetInt64("subscription-id")
+
+		clt := getClient()
+		if id == "" {
+			user, err := clt.GetCurrentUser(context.Background())
+			if err != nil {
+				DieErr(err)
+			}
+			id = user.ID
+		}
+
+		err := clt.DeleteUserSubscription(context.Background(), id, subscriptionID)
+		if err != nil {
+			DieErr(err)
+		}
+
+		Fmt("Subscription deleted successfully\n")
+	},
+}
+
 // groups
 var authGroups = &cobra.Command{
 	Use:   "groups",
@@ -654,12 +758,32 @@ func init() {
 	authUsersCredentials.AddCommand(authUsersCredentialsCreate)
 	authUsersCredentials.AddCommand(authUsersCredentialsDelete)
 
+	authUsersSubscriptionsList.Flags().String("id", "", "user identifier (default: current user)")
+
+	authUsersSubscriptionsCreate.Flags().String("id", "", "user identifier (default: current user)")
+	authUsersSubscriptionsCreate.Flags().String("repository", "", "repository to notify about (default: all repositories the user may read)")
+	authUsersSubscriptionsCreate.Flags().String("branch", "", "branch of repository to notify about (default: all branches)")
+	authUsersSubscriptionsCreate.Flags().String("event-type", "", "event type: export_failed, hook_failed or protected_branch_merge")
+	authUsersSubscriptionsCreate.Flags().String("channel", "email", "channel to deliver notifications through")
+	authUsersSubscriptionsCreate.Flags().String("address", "", "address to deliver notifications to")
+	_ = authUsersSubscriptionsCreate.MarkFlagRequired("event-type")
+	_ = authUsersSubscriptionsCreate.MarkFlagRequired("address")
+
+	authUsersSubscriptionsDelete.Flags().String("id", "", "user identifier (default: current user)")
+	authUsersSubscriptionsDelete.Flags().Int64("subscription-id", 0, "subscription ID to delete")
+	_ = authUsersSubscriptionsDelete.MarkFlagRequired("subscription-id")
+
+	authUsersSubscriptions.AddCommand(authUsersSubscriptionsList)
+	authUsersSubscriptions.AddCommand(authUsersSubscriptionsCreate)
+	authUsersSubscriptions.AddCommand(authUsersSubscriptionsDelete)
+
 	authUsers.AddCommand(authUsersCreate)
 	authUsers.AddCommand(authUsersDelete)
 	authUsers.AddCommand(authUsersList)
 	authUsers.AddCommand(authUsersPolicies)
 	authUsers.AddCommand(authUsersGroups)
 	authUsers.AddCommand(authUsersCredentials)
+	authUsers.AddCommand(authUsersSubscriptions)
 
 	authCmd.AddCommand(authUsers)
 
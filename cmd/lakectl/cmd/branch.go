@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
@@ -33,17 +34,44 @@ var branchListCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		amount, _ := cmd.Flags().GetInt("amount")
 		after, _ := cmd.Flags().GetString("after")
+		withStatus, _ := cmd.Flags().GetBool("status")
+		sortBy, _ := cmd.Flags().GetString("sort-by")
 
 		u := uri.Must(uri.Parse(args[0]))
 		client := getClient()
-		response, pagination, err := client.ListBranches(context.Background(), u.Repository, after, amount)
-		if err != nil {
-			DieErr(err)
-		}
-
-		rows := make([][]interface{}, len(response))
-		for i, row := range response {
-			rows[i] = []interface{}{row}
+		var rows [][]interface{}
+		var pagination *models.Pagination
+		headers := []interface{}{"Branch"}
+		if withStatus {
+			var statuses []*models.BranchStatus
+			var err error
+			statuses, pagination, err = client.ListBranchesStatus(context.Background(), u.Repository, after, amount, sortBy)
+			if err != nil {
+				DieErr(err)
+			}
+			headers = []interface{}{"Branch", "Source", "Ahead", "Behind", "Uncommitted", "Last Commit", "Last Committer", "Last Commit Date"}
+			rows = make([][]interface{}, len(statuses))
+			for i, status := range statuses {
+				var headID, headCommitter, headDate string
+				if status.Head != nil {
+					headID = status.Head.ID
+					headCommitter = status.Head.Committer
+					headDate = time.Unix(status.Head.CreationDate, 0).String()
+				}
+				rows[i] = []interface{}{swag.StringValue(status.ID), status.SourceBranch, status.CommitsAhead, status.CommitsBehind,
+					status.UncommittedEntries, headID, headCommitter, headDate}
+			}
+		} else {
+			var response []string
+			var err error
+			response, pagination, err = client.ListBranches(context.Background(), u.Repository, after, amount, sortBy)
+			if err != nil {
+				DieErr(err)
+			}
+			rows = make([][]interface{}, len(response))
+			for i, row := range response {
+				rows[i] = []interface{}{row}
+			}
 		}
 
 		ctx := struct {
@@ -51,7 +79,7 @@ var branchListCmd = &cobra.Command{
 			Pagination  *Pagination
 		}{
 			BranchTable: &Table{
-				Headers: []interface{}{"Branch"},
+				Headers: headers,
 				Rows:    rows,
 			},
 		}
@@ -119,12 +147,31 @@ var branchDeleteCmd = &cobra.Command{
 	},
 }
 
+var branchRenameCmd = &cobra.Command{
+	Use:     "rename <branch uri> <new name>",
+	Short:   "rename a branch, keeping its commits, objects, exports and default branch status",
+	Example: "lakectl branch rename lakefs://myrepo@master main",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(2),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		u := uri.Must(uri.Parse(args[0]))
+		err := client.RenameBranch(context.Background(), u.Repository, u.Ref, args[1])
+		if err != nil {
+			DieErr(err)
+		}
+		fmt.Printf("branch %s renamed to %s\n", u.Ref, args[1])
+	},
+}
+
 // lakectl branch revert lakefs://myrepo@master --commit commitId --prefix path --object path
 var branchRevertCmd = &cobra.Command{
 	Use:   "revert <branch uri> [flags]",
 	Short: "revert changes to specified commit, or revert uncommitted changes - all changes, or by path",
 	Long: `revert changes.  There are four different ways to revert changes:
-  1. revert to previous commit, set HEAD of branch to given commit - revert lakefs://myrepo@master --commit commitId
+  1. revert to previous commit, set HEAD of branch to given commit - revert lakefs://myrepo@master --commit commitId [--soft]
   2. revert all uncommitted changes (reset) - revert lakefs://myrepo@master 
   3. revert uncommitted changes under specific path -	revert lakefs://myrepo@master --prefix path
   4. revert uncommitted changes for specific object - revert lakefs://myrepo@master --object path`,
@@ -148,6 +195,7 @@ var branchRevertCmd = &cobra.Command{
 		if err != nil {
 			DieErr(err)
 		}
+		soft, _ := cmd.Flags().GetBool("soft")
 
 		var revert models.RevertCreation
 		var confirmationMsg string
@@ -155,8 +203,9 @@ var branchRevertCmd = &cobra.Command{
 		case len(commitID) > 0:
 			confirmationMsg = fmt.Sprintf("Are you sure you want to revert all changes to commit: %s", commitID)
 			revert = models.RevertCreation{
-				Commit: commitID,
-				Type:   swag.String(models.RevertCreationTypeCommit),
+				Commit:          commitID,
+				KeepUncommitted: soft,
+				Type:            swag.String(models.RevertCreationTypeCommit),
 			}
 		case len(prefix) > 0:
 			confirmationMsg = fmt.Sprintf("Are you sure you want to revert all changes from path: %s to last commit", prefix)
@@ -207,22 +256,74 @@ var branchShowCmd = &cobra.Command{
 	},
 }
 
+const branchChangesTemplate = `{{ range $val := .Results }}
+ID: {{ $val.Commit.ID|yellow }}{{if $val.Commit.Committer }}
+Author: {{ $val.Commit.Committer }}{{end}}
+Date: {{ $val.Commit.CreationDate|date }}
+	{{ $val.Commit.Message }}
+{{ with $val.DiffSummary }}	+ added: {{ .Added }} changed: {{ .Changed }} removed: {{ .Removed }} bytes: {{ .BytesDelta }}
+{{ end -}}
+{{ end }}
+Token: {{ .Token|bold }}
+{{ if .HasMore }}More commits were created, call again with --since {{ .Token }}
+{{ end }}`
+
+var branchChangesCmd = &cobra.Command{
+	Use:   "changes <branch uri>",
+	Short: "list the commits created on a branch since a token returned by a previous call",
+	Long: `list the commits created on a branch since a token returned by a previous call, oldest first.
+Without --since only a token of the current branch commit is returned, to start tracking the branch from.`,
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		since, _ := cmd.Flags().GetString("since")
+		amount, _ := cmd.Flags().GetInt("amount")
+		diffSummary, _ := cmd.Flags().GetBool("diff-summary")
+		client := getClient()
+		u := uri.Must(uri.Parse(args[0]))
+		changes, err := client.GetBranchChanges(context.Background(), u.Repository, u.Ref, since, amount, diffSummary)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(branchChangesTemplate, struct {
+			Results []*models.BranchChange
+			Token   string
+			HasMore bool
+		}{
+			Results: changes.Results,
+			Token:   swag.StringValue(changes.Token),
+			HasMore: swag.BoolValue(changes.HasMore),
+		})
+	},
+}
+
 //nolint:gochecknoinits
 func init() {
 	rootCmd.AddCommand(branchCmd)
 	branchCmd.AddCommand(branchCreateCmd)
 	branchCmd.AddCommand(branchDeleteCmd)
+	branchCmd.AddCommand(branchRenameCmd)
 	branchCmd.AddCommand(branchListCmd)
 	branchCmd.AddCommand(branchShowCmd)
 	branchCmd.AddCommand(branchRevertCmd)
+	branchCmd.AddCommand(branchChangesCmd)
 
 	branchListCmd.Flags().Int("amount", -1, "how many results to return, or-1 for all results (used for pagination)")
 	branchListCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	branchListCmd.Flags().Bool("status", false, "show the head commit, commits ahead and behind the source branch and uncommitted changes of each branch")
+	branchListCmd.Flags().String("sort-by", "name", "order branches by name, or most recently committed to (last_commit) or created (creation) first")
 
 	branchCreateCmd.Flags().StringP("source", "s", "", "source branch uri")
 	_ = branchCreateCmd.MarkFlagRequired("source")
 
 	branchRevertCmd.Flags().String("commit", "", "commit ID to revert branch to")
+	branchRevertCmd.Flags().Bool("soft", false, "with --commit, keep the uncommitted changes of the branch")
 	branchRevertCmd.Flags().String("prefix", "", "prefix of the objects to be reverted")
 	branchRevertCmd.Flags().String("object", "", "path to object to be reverted")
+
+	branchChangesCmd.Flags().String("since", "", "token returned by a previous call")
+	branchChangesCmd.Flags().Int("amount", -1, "how many commits to return, or -1 for the maximum")
+	branchChangesCmd.Flags().Bool("diff-summary", false, "show the summary of the changes of each commit")
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/cmdutils"
@@ -40,11 +41,56 @@ var commitCmd = &cobra.Command{
 		if err != nil {
 			DieErr(err)
 		}
+		chunked, _ := cmd.Flags().GetBool("chunked")
+		prefixes, err := cmd.Flags().GetStringArray("prefix")
+		if err != nil {
+			DieErr(err)
+		}
+		if chunked && len(prefixes) > 0 {
+			DieFmt("cannot commit selected prefixes in chunks")
+		}
+		amend, _ := cmd.Flags().GetBool("amend")
+		includeChanges, _ := cmd.Flags().GetBool("include-changes")
+		if includeChanges && !amend {
+			DieFmt("--include-changes requires --amend")
+		}
+		if amend && (chunked || len(prefixes) > 0) {
+			DieFmt("cannot amend a commit in chunks or with selected prefixes")
+		}
 		branchURI := uri.Must(uri.Parse(args[0]))
 
 		// do commit
 		client := getClient()
-		commit, err := client.Commit(context.Background(), branchURI.Repository, branchURI.Ref, message, kvPairs)
+		if chunked {
+			job, err := client.CreateCommitJob(context.Background(), branchURI.Repository, branchURI.Ref, message, kvPairs)
+			if err != nil {
+				DieErr(err)
+			}
+			job = waitForJob(client, branchURI.Repository, job)
+			if swag.StringValue(job.Status) != models.JobStatusCompleted {
+				Write(jobTemplate, newJobOutput(job))
+				DieFmt("commit job %d failed and was rolled back, the branch is unchanged", swag.Int64Value(job.ID))
+			}
+			commit, err := client.GetCommit(context.Background(), branchURI.Repository, job.CommitID)
+			if err != nil {
+				DieErr(err)
+			}
+			Write(commitCreateTemplate, struct {
+				Branch *uri.URI
+				Commit *models.Commit
+			}{branchURI, commit})
+			return
+		}
+		var commit *models.Commit
+		if amend {
+			commit, err = client.AmendCommit(context.Background(), branchURI.Repository, branchURI.Ref, &models.CommitAmend{
+				Message:        swag.String(message),
+				Metadata:       kvPairs,
+				IncludeChanges: swag.Bool(includeChanges),
+			})
+		} else {
+			commit, err = client.Commit(context.Background(), branchURI.Repository, branchURI.Ref, message, kvPairs, prefixes)
+		}
 		if err != nil {
 			DieErr(err)
 		}
@@ -81,4 +127,8 @@ func init() {
 	_ = commitCmd.MarkFlagRequired("message")
 
 	commitCmd.Flags().StringSlice("meta", []string{}, "key value pair in the form of key=value")
+	commitCmd.Flags().Bool("chunked", false, "commit in chunks using a commit job, for very large changes")
+	commitCmd.Flags().StringArray("prefix", []string{}, "commit only the changes under this path prefix, leaving other changes uncommitted (repeatable)")
+	commitCmd.Flags().Bool("amend", false, "replace the message and metadata of the last commit, if it was not merged, branched or tagged yet")
+	commitCmd.Flags().Bool("include-changes", false, "with --amend, also fold the uncommitted changes into the amended commit")
 }
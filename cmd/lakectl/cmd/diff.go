@@ -19,6 +19,29 @@ const (
 	diffPageSize   = 100
 )
 
+const diffSummaryTemplate = `Commits ahead:  {{.CommitsAhead}}
+Commits behind: {{.CommitsBehind}}
+Added:          {{.Added}}
+Removed:        {{.Removed}}
+Changed:        {{.Changed}}
+Conflicts:      {{.Conflicts}}
+Bytes delta:    {{.BytesDelta}}
+`
+
+const diffChecksumsTemplate = `Added:       {{.Added}}
+Removed:     {{.Removed}}
+Changed:     {{.Changed}}
+Bytes delta: {{.BytesDelta}}
+Identical:   {{.Identical}}
+`
+
+const diffCountsTemplate = `Added:     {{.Added}}
+Removed:   {{.Removed}}
+Changed:   {{.Changed}}
+Conflicts: {{.Conflicts}}
+{{.PrefixesTable | table -}}
+`
+
 var diffCmd = &cobra.Command{
 	Use:   "diff <ref uri> [other ref uri]",
 	Short: "diff between commits/hashes",
@@ -28,9 +51,38 @@ var diffCmd = &cobra.Command{
 		cmdutils.FuncValidator(0, uri.ValidateRefURI),
 	),
 	Run: func(cmd *cobra.Command, args []string) {
+		summary, err := cmd.Flags().GetBool("summary")
+		if err != nil {
+			DieErr(err)
+		}
+		summaryOnly, err := cmd.Flags().GetBool("summary-only")
+		if err != nil {
+			DieErr(err)
+		}
+		prefix, err := cmd.Flags().GetString("prefix")
+		if err != nil {
+			DieErr(err)
+		}
+		uncommitted, err := cmd.Flags().GetBool("uncommitted")
+		if err != nil {
+			DieErr(err)
+		}
+		checksums, err := cmd.Flags().GetBool("checksums")
+		if err != nil {
+			DieErr(err)
+		}
 		client := getClient()
 
 		const diffWithOtherArgsCount = 2
+		if prefix != "" && (len(args) == diffWithOtherArgsCount || summaryOnly) {
+			Die("prefix applies only to listing the changes of a single branch", 1)
+		}
+		if uncommitted && (len(args) != diffWithOtherArgsCount || summary || summaryOnly) {
+			Die("uncommitted applies only to listing the differences between two branches", 1)
+		}
+		if checksums && (len(args) != diffWithOtherArgsCount || summary || summaryOnly || uncommitted) {
+			Die("checksums applies only to counting the differences between two references", 1)
+		}
 		if len(args) == diffWithOtherArgsCount {
 			if err := uri.ValidateRefURI(args[1]); err != nil {
 				DieErr(err)
@@ -40,18 +92,45 @@ var diffCmd = &cobra.Command{
 			if leftRefURI.Repository != rightRefURI.Repository {
 				Die("both references must belong to the same repository", 1)
 			}
-			printDiffRefs(client, leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref)
+			if checksums {
+				printDiffRefsChecksums(client, leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref)
+				return
+			}
+			if summary {
+				printDiffRefsSummary(client, leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref)
+				return
+			}
+			if summaryOnly {
+				counts, err := client.DiffRefsCounts(context.Background(), leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref)
+				if err != nil {
+					DieErr(err)
+				}
+				printDiffCounts(counts)
+				return
+			}
+			printDiffRefs(client, leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref, uncommitted)
 		} else {
+			if summary {
+				Die("summary requires two references", 1)
+			}
 			branchURI := uri.Must(uri.Parse(args[0]))
-			printDiffBranch(client, branchURI.Repository, branchURI.Ref)
+			if summaryOnly {
+				counts, err := client.DiffBranchCounts(context.Background(), branchURI.Repository, branchURI.Ref)
+				if err != nil {
+					DieErr(err)
+				}
+				printDiffCounts(counts)
+				return
+			}
+			printDiffBranch(client, branchURI.Repository, branchURI.Ref, prefix)
 		}
 	},
 }
 
-func printDiffBranch(client api.Client, repository string, branch string) {
+func printDiffBranch(client api.Client, repository string, branch string, prefix string) {
 	var after string
 	for {
-		diff, pagination, err := client.DiffBranch(context.Background(), repository, branch, after, diffPageSize)
+		diff, pagination, err := client.DiffBranch(context.Background(), repository, branch, prefix, after, diffPageSize)
 		if err != nil {
 			DieErr(err)
 		}
@@ -65,11 +144,21 @@ func printDiffBranch(client api.Client, repository string, branch string) {
 	}
 }
 
-func printDiffRefs(client api.Client, repository string, leftRef string, rightRef string) {
+func printDiffRefs(client api.Client, repository string, leftRef string, rightRef string, uncommitted bool) {
 	var after string
 	for {
-		diff, pagination, err := client.DiffRefs(context.Background(), repository, leftRef, rightRef,
-			after, diffPageSize)
+		var (
+			diff       []*models.Diff
+			pagination *models.Pagination
+			err        error
+		)
+		if uncommitted {
+			diff, pagination, err = client.DiffBranches(context.Background(), repository, leftRef, rightRef,
+				after, diffPageSize)
+		} else {
+			diff, pagination, err = client.DiffRefs(context.Background(), repository, leftRef, rightRef,
+				after, diffPageSize)
+		}
 		if err != nil {
 			DieErr(err)
 		}
@@ -83,6 +172,48 @@ func printDiffRefs(client api.Client, repository string, leftRef string, rightRe
 	}
 }
 
+func printDiffRefsSummary(client api.Client, repository string, leftRef string, rightRef string) {
+	summary, err := client.DiffRefsSummary(context.Background(), repository, leftRef, rightRef)
+	if err != nil {
+		DieErr(err)
+	}
+	Write(diffSummaryTemplate, summary)
+}
+
+func printDiffRefsChecksums(client api.Client, repository string, leftRef string, rightRef string) {
+	summary, err := client.DiffRefsChecksums(context.Background(), repository, leftRef, rightRef)
+	if err != nil {
+		DieErr(err)
+	}
+	Write(diffChecksumsTemplate, summary)
+}
+
+func printDiffCounts(counts *models.DiffCounts) {
+	rows := make([][]interface{}, len(counts.Prefixes))
+	for i, p := range counts.Prefixes {
+		rows[i] = []interface{}{
+			swag.StringValue(p.Prefix), swag.Int64Value(p.Added), swag.Int64Value(p.Removed),
+			swag.Int64Value(p.Changed), swag.Int64Value(p.Conflicts),
+		}
+	}
+	Write(diffCountsTemplate, struct {
+		Added         int64
+		Removed       int64
+		Changed       int64
+		Conflicts     int64
+		PrefixesTable *Table
+	}{
+		Added:     swag.Int64Value(counts.Added),
+		Removed:   swag.Int64Value(counts.Removed),
+		Changed:   swag.Int64Value(counts.Changed),
+		Conflicts: swag.Int64Value(counts.Conflicts),
+		PrefixesTable: &Table{
+			Headers: []interface{}{"Prefix", "Added", "Removed", "Changed", "Conflicts"},
+			Rows:    rows,
+		},
+	})
+}
+
 func FmtDiff(diff *models.Diff, withDirection bool) {
 	var color text.Color
 	var action string
@@ -118,4 +249,9 @@ func FmtDiff(diff *models.Diff, withDirection bool) {
 //nolint:gochecknoinits
 func init() {
 	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().Bool("summary", false, "show only the number of commits and differences between the two references")
+	diffCmd.Flags().Bool("summary-only", false, "show only the number of differences per type and top-level prefix, without listing them")
+	diffCmd.Flags().String("prefix", "", "list only the uncommitted changes of a branch to paths with this prefix")
+	diffCmd.Flags().Bool("uncommitted", false, "compare two branches including their uncommitted changes, instead of their last commits")
+	diffCmd.Flags().Bool("checksums", false, "show only the number of objects that differ by checksum between the two references, branches include their uncommitted changes")
 }
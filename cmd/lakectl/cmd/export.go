@@ -3,11 +3,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
 	"github.com/treeverse/lakefs/api/gen/models"
 
 	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/cmdutils"
 	"github.com/treeverse/lakefs/uri"
 )
 
@@ -20,8 +25,13 @@ var exportCmd = &cobra.Command{
 var exportSetCmd = &cobra.Command{
 	Use:   "set <branch uri>",
 	Short: "set continuous export configuration for branch",
-	Long: `Set the entire continuous export configuration for branch.
-Overrides all fields of any previous configuration.`,
+	Long: `Set the entire continuous export configuration for branch and prefix.
+Overrides all fields of any previous configuration for the same prefix.  Configurations for
+different prefixes export the objects under them to different destinations.
+
+Export and status paths may hold the placeholders {branch}, {commit} and {timestamp} (the
+export start time in UTC), expanded when each export starts.  Exports to paths with {commit}
+or {timestamp} land in a new path every time, they need --mode full.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
@@ -42,11 +52,81 @@ Overrides all fields of any previous configuration.`,
 		if err != nil {
 			DieErr(err)
 		}
+		parallelism, err := cmd.Flags().GetInt64("parallelism")
+		if err != nil {
+			DieErr(err)
+		}
+		mode, err := cmd.Flags().GetString("mode")
+		if err != nil {
+			DieErr(err)
+		}
+		maxAttempts, err := cmd.Flags().GetInt64("max-attempts")
+		if err != nil {
+			DieErr(err)
+		}
+		retryBackoff, err := cmd.Flags().GetDuration("retry-backoff")
+		if err != nil {
+			DieErr(err)
+		}
+		writeManifest, err := cmd.Flags().GetBool("write-manifest")
+		if err != nil {
+			DieErr(err)
+		}
+		prefix, err := cmd.Flags().GetString("prefix")
+		if err != nil {
+			DieErr(err)
+		}
+		schedule, err := cmd.Flags().GetString("schedule")
+		if err != nil {
+			DieErr(err)
+		}
+		includePrefixes, err := cmd.Flags().GetStringArray("include-prefix")
+		if err != nil {
+			DieErr(err)
+		}
+		excludeGlobs, err := cmd.Flags().GetStringArray("exclude-glob")
+		if err != nil {
+			DieErr(err)
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			DieErr(err)
+		}
+		propagateDeletes, err := cmd.Flags().GetBool("propagate-deletes")
+		if err != nil {
+			DieErr(err)
+		}
+		additionalPaths, err := cmd.Flags().GetStringArray("additional-path")
+		if err != nil {
+			DieErr(err)
+		}
+		sseAlgorithm, err := cmd.Flags().GetString("sse")
+		if err != nil {
+			DieErr(err)
+		}
+		sseKMSKeyID, err := cmd.Flags().GetString("sse-kms-key-id")
+		if err != nil {
+			DieErr(err)
+		}
 		config := &models.ContinuousExportConfiguration{
+			Prefix:                 prefix,
 			ExportPath:             strfmt.URI(exportPath),
 			ExportStatusPath:       strfmt.URI(exportStatusPath),
 			LastKeysInPrefixRegexp: prefixRegex,
 			IsContinuous:           isContinuous,
+			Parallelism:            swag.Int64(parallelism),
+			Mode:                   mode,
+			MaxAttempts:            swag.Int64(maxAttempts),
+			RetryBackoffMs:         swag.Int64(retryBackoff.Milliseconds()),
+			WriteManifest:          writeManifest,
+			Schedule:               schedule,
+			IncludePrefixes:        includePrefixes,
+			ExcludeGlobs:           excludeGlobs,
+			Format:                 format,
+			PropagateDeletes:       swag.Bool(propagateDeletes),
+			AdditionalExportPaths:  additionalPaths,
+			SseAlgorithm:           sseAlgorithm,
+			SseKmsKeyID:            sseKMSKeyID,
 		}
 		err = client.SetContinuousExport(context.Background(), branchURI.Repository, branchURI.Ref, config)
 		if err != nil {
@@ -57,10 +137,24 @@ Overrides all fields of any previous configuration.`,
 
 var exportConfigurationTemplate = `export configuration for branch "{{.Branch.Ref}}" completed.
 
-Export Path: {{.Configuration.ExportPath|yellow}}
-Export status path: {{.Configuration.ExportStatusPath}}
+{{ if .Configuration.Prefix }}Prefix: {{.Configuration.Prefix}}
+{{ end }}Export Path: {{.Configuration.ExportPath|yellow}}
+{{ if .Configuration.AdditionalExportPaths }}Additional export paths: {{.Configuration.AdditionalExportPaths}}
+{{ end }}Export status path: {{.Configuration.ExportStatusPath}}
 Last Keys In Prefix Regexp: {{.Configuration.LastKeysInPrefixRegexp}}
-{{.ContinuousMarker}}
+Parallelism: {{.Configuration.Parallelism}}
+Mode: {{.Configuration.Mode}}
+Format: {{.Configuration.Format}}
+Propagate deletes: {{.Configuration.PropagateDeletes}}
+Max attempts: {{.Configuration.MaxAttempts}}
+Retry backoff (ms): {{.Configuration.RetryBackoffMs}}
+Write manifest: {{.Configuration.WriteManifest}}
+{{ if .Configuration.Schedule }}Schedule: {{.Configuration.Schedule}}
+{{ end }}{{ if .Configuration.IncludePrefixes }}Include prefixes: {{.Configuration.IncludePrefixes}}
+{{ end }}{{ if .Configuration.ExcludeGlobs }}Exclude globs: {{.Configuration.ExcludeGlobs}}
+{{ end }}{{ if .Configuration.SseAlgorithm }}Server-side encryption: {{.Configuration.SseAlgorithm}}
+{{ end }}{{ if .Configuration.SseKmsKeyID }}SSE KMS key ID: {{.Configuration.SseKmsKeyID}}
+{{ end }}{{.ContinuousMarker}}
 `
 
 var exportGetCmd = &cobra.Command{
@@ -68,9 +162,13 @@ var exportGetCmd = &cobra.Command{
 	Short: "get continuous export configuration for branch",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		prefix, err := cmd.Flags().GetString("prefix")
+		if err != nil {
+			DieErr(err)
+		}
 		client := getClient()
 		branchURI := uri.Must(uri.Parse(args[0]))
-		configuration, err := client.GetContinuousExport(context.Background(), branchURI.Repository, branchURI.Ref)
+		configuration, err := client.GetContinuousExport(context.Background(), branchURI.Repository, branchURI.Ref, prefix)
 
 		if err != nil {
 			DieErr(err)
@@ -87,6 +185,74 @@ var exportGetCmd = &cobra.Command{
 	},
 }
 
+var exportListCmd = &cobra.Command{
+	Use:   "list <branch uri>",
+	Short: "list all continuous export configurations of branch",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		branchURI := uri.Must(uri.Parse(args[0]))
+		configurations, err := client.ListContinuousExports(context.Background(), branchURI.Repository, branchURI.Ref)
+		if err != nil {
+			DieErr(err)
+		}
+		rows := make([][]interface{}, len(configurations))
+		for i, c := range configurations {
+			rows[i] = []interface{}{c.Prefix, c.ExportPath, c.ExportStatusPath, c.Mode, c.IsContinuous, c.Schedule}
+		}
+		PrintTable(rows, []interface{}{"Prefix", "Export Path", "Export Status Path", "Mode", "Continuous", "Schedule"}, nil, 0)
+	},
+}
+
+var exportRunsCmd = &cobra.Command{
+	Use:   "runs <branch uri>",
+	Short: "list past and running exports of branch, newest first",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		amount, _ := cmd.Flags().GetInt("amount")
+		after, _ := cmd.Flags().GetString("after")
+		client := getClient()
+		branchURI := uri.Must(uri.Parse(args[0]))
+		runs, pagination, err := client.ListExportRuns(context.Background(), branchURI.Repository, branchURI.Ref, after, amount)
+		if err != nil {
+			DieErr(err)
+		}
+		rows := make([][]interface{}, len(runs))
+		for i, r := range runs {
+			endTime := ""
+			if !time.Time(r.EndTime).IsZero() {
+				endTime = r.EndTime.String()
+			}
+			rows[i] = []interface{}{
+				swag.StringValue(r.ExportID), r.FromRef, swag.StringValue(r.ToRef), r.StartTime.String(), endTime,
+				r.ObjectsCopied, r.ObjectsDeleted, r.BytesCopied, swag.StringValue(r.Status), r.ErrorMessage,
+				failedDestinations(r.Destinations),
+			}
+		}
+		PrintTable(rows, []interface{}{
+			"Export ID", "From Ref", "To Ref", "Start Time", "End Time",
+			"Copied", "Deleted", "Bytes Copied", "Status", "Error", "Failed Destinations",
+		}, pagination, amount)
+	},
+}
+
+// failedDestinations returns the failed destinations of an export run, separated by commas
+func failedDestinations(destinations []*models.ExportRunDestination) string {
+	var failed []string
+	for _, d := range destinations {
+		if swag.StringValue(d.Status) == string(catalog.ExportStatusFailed) {
+			failed = append(failed, swag.StringValue(d.Destination))
+		}
+	}
+	return strings.Join(failed, ", ")
+}
+
 var exportExecuteCmd = &cobra.Command{
 	Use:   "run",
 	Short: "export requested branch now",
@@ -101,16 +267,169 @@ var exportExecuteCmd = &cobra.Command{
 	},
 }
 
+var exportRefCmd = &cobra.Command{
+	Use:   "ref <ref uri>",
+	Short: "export all objects of a ref, such as a tag or a commit, once",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		destination, _ := cmd.Flags().GetString("path")
+		client := getClient()
+		refURI := uri.Must(uri.Parse(args[0]))
+		exportID, err := client.ExportRef(context.Background(), refURI.Repository, refURI.Ref, destination)
+		if err != nil {
+			DieErr(err)
+		}
+		fmt.Printf("Export-ID:%s\n", exportID)
+	},
+}
+
+const refExportTemplate = `Export-ID: {{ .ExportID | yellow }}
+Ref: {{ .Ref }} ({{ .CommitRef }})
+Destination: {{ .Destination }}
+Started: {{ .StartTime }}
+Ended: {{ .EndTime }}
+Status: {{ .Status }}
+{{ if .ErrorMessage }}Error: {{ .ErrorMessage }}
+{{ end }}`
+
+var exportRefStatusCmd = &cobra.Command{
+	Use:   "ref-status <repository uri> <export id>",
+	Short: "show the state of a one-off export of a ref",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(2),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		repoURI := uri.Must(uri.Parse(args[0]))
+		refExport, err := client.GetRefExport(context.Background(), repoURI.Repository, args[1])
+		if err != nil {
+			DieErr(err)
+		}
+		endTime := ""
+		if !time.Time(refExport.EndTime).IsZero() {
+			endTime = refExport.EndTime.String()
+		}
+		Write(refExportTemplate, struct {
+			ExportID, Ref, CommitRef, Destination, StartTime, EndTime, Status, ErrorMessage string
+		}{
+			ExportID:     swag.StringValue(refExport.ExportID),
+			Ref:          swag.StringValue(refExport.Ref),
+			CommitRef:    swag.StringValue(refExport.CommitRef),
+			Destination:  swag.StringValue(refExport.Destination),
+			StartTime:    refExport.StartTime.String(),
+			EndTime:      endTime,
+			Status:       swag.StringValue(refExport.Status),
+			ErrorMessage: refExport.ErrorMessage,
+		})
+	},
+}
+
 var exportRepairCmd = &cobra.Command{
-	Use:   "repair",
-	Short: "mark failed export as repaired",
+	Use:   "repair <branch uri>",
+	Short: "retry the failed objects of a failed export",
+	Long: `Export again only the objects whose export failed in the last export of branch.  The
+export state of branch changes to repaired once they all export successfully.`,
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
 	Run: func(cmd *cobra.Command, args []string) {
 		client := getClient()
 		branchURI := uri.Must(uri.Parse(args[0]))
-		err := client.RepairExport(context.Background(), branchURI.Repository, branchURI.Ref)
+		exportID, err := client.RepairExport(context.Background(), branchURI.Repository, branchURI.Ref)
 		if err != nil {
 			DieErr(err)
 		}
+		fmt.Printf("Export-ID:%s\n", exportID)
+	},
+}
+
+var exportDriftTemplate = `{{ if .ExportID }}Export-ID: {{ .ExportID }}
+{{ end }}{{ len .Drifts }} objects drifted from exported commit {{ .CommitRef|yellow }}
+{{ range $drift := .Drifts }}  {{ index $drift 0|red }}  {{ index $drift 1 }}
+{{ end }}`
+
+var exportDriftCmd = &cobra.Command{
+	Use:   "drift <branch uri>",
+	Short: "show objects modified or deleted on the export destination out-of-band",
+	Long: `Compare the export destination of branch with the last commit exported to it, and
+show the objects modified or deleted there out-of-band.  With --reconcile also re-export only
+the drifted objects.`,
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		reconcile, err := cmd.Flags().GetBool("reconcile")
+		if err != nil {
+			DieErr(err)
+		}
+		client := getClient()
+		branchURI := uri.Must(uri.Parse(args[0]))
+		var report *models.ExportDriftReport
+		if reconcile {
+			report, err = client.ReconcileExportDrift(context.Background(), branchURI.Repository, branchURI.Ref)
+		} else {
+			report, err = client.GetExportDrift(context.Background(), branchURI.Repository, branchURI.Ref)
+		}
+		if err != nil {
+			DieErr(err)
+		}
+		drifts := make([][]string, len(report.Drifts))
+		for i, drift := range report.Drifts {
+			drifts[i] = []string{swag.StringValue(drift.Type), swag.StringValue(drift.Path)}
+		}
+		Write(exportDriftTemplate, struct {
+			CommitRef string
+			Drifts    [][]string
+			ExportID  string
+		}{swag.StringValue(report.CommitRef), drifts, report.ExportID})
+	},
+}
+
+var exportPlanTemplate = `{{ if .FromRef }}Export diff from {{ .FromRef|yellow }} to {{ .ToRef|yellow }}{{ else }}Export all objects of {{ .ToRef|yellow }}{{ end }}: {{ .NumOperations }} operations
+{{ range $op := .Operations }}  {{ index $op 0|bold }}  {{ if index $op 1 }}{{ index $op 1 }} -> {{ end }}{{ index $op 2 }}
+{{ end }}`
+
+var exportPlanCmd = &cobra.Command{
+	Use:   "plan <branch uri>",
+	Short: "show the operations that exporting branch would perform, without performing them",
+	Long: `Show the objects that exporting branch would copy, delete and mark successful on the
+export destination, using its current export configuration.  Use it to validate the export
+path and prefix regexps before setting continuous export.`,
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		ref, err := cmd.Flags().GetString("ref")
+		if err != nil {
+			DieErr(err)
+		}
+		amount, err := cmd.Flags().GetInt("amount")
+		if err != nil {
+			DieErr(err)
+		}
+		client := getClient()
+		branchURI := uri.Must(uri.Parse(args[0]))
+		plan, err := client.GetExportPlan(context.Background(), branchURI.Repository, branchURI.Ref, ref, amount)
+		if err != nil {
+			DieErr(err)
+		}
+		operations := make([][]string, len(plan.Operations))
+		for i, op := range plan.Operations {
+			operations[i] = []string{swag.StringValue(op.Action), op.Source, swag.StringValue(op.Destination)}
+		}
+		Write(exportPlanTemplate, struct {
+			FromRef       string
+			ToRef         string
+			NumOperations int64
+			Operations    [][]string
+		}{plan.FromRef, swag.StringValue(plan.ToRef), swag.Int64Value(plan.NumOperations), operations})
 	},
 }
 
@@ -119,13 +438,45 @@ func init() {
 	rootCmd.AddCommand(exportCmd)
 	exportCmd.AddCommand(exportGetCmd)
 	exportCmd.AddCommand(exportSetCmd)
+	exportCmd.AddCommand(exportListCmd)
+	exportCmd.AddCommand(exportRunsCmd)
 	exportCmd.AddCommand(exportExecuteCmd)
+	exportCmd.AddCommand(exportRefCmd)
+	exportCmd.AddCommand(exportRefStatusCmd)
 	exportCmd.AddCommand(exportRepairCmd)
+	exportCmd.AddCommand(exportDriftCmd)
+	exportCmd.AddCommand(exportPlanCmd)
+
+	exportGetCmd.Flags().String("prefix", "", "prefix of the export configuration")
+
+	addPaginationFlags(exportRunsCmd)
 
-	exportSetCmd.Flags().String("path", "", "export objects to this path")
+	exportRefCmd.Flags().String("path", "", "export objects to this path, on S3 (s3://), Google Cloud Storage (gs://) or Azure Blob Storage (https:// or wasb://)")
+	_ = exportRefCmd.MarkFlagRequired("path")
+
+	exportSetCmd.Flags().String("prefix", "", "export only objects under this prefix (default is all objects)")
+	exportSetCmd.Flags().String("path", "", "export objects to this path, on S3 (s3://), Google Cloud Storage (gs://) or Azure Blob Storage (https:// or wasb://)")
+	exportSetCmd.Flags().StringArray("additional-path", nil, "also export objects to this path, recording its status on export runs (repeat for several paths)")
 	exportSetCmd.Flags().String("status-path", "", "write export status object to this path")
 	exportSetCmd.Flags().StringArray("prefix-regex", nil, "list of regexps of keys to exported last in each prefix (for signalling)")
+	exportSetCmd.Flags().Int64("max-attempts", 0, "number of attempts to export each object before failing the export (0 for the default)")
+	exportSetCmd.Flags().Duration("retry-backoff", 0, "delay before retrying to export an object, doubled on every further attempt")
+	exportSetCmd.Flags().String("mode", "incremental", "export only the diff from the last exported commit (incremental) or the entire branch (full)")
+	exportSetCmd.Flags().String("format", "copy", "copy exported objects (copy) or write a Hive symlink.txt file listing the objects of each directory (symlink)")
+	exportSetCmd.Flags().Int64("parallelism", 0, "maximal number of objects exported concurrently (0 for no limit)")
+	exportSetCmd.Flags().Bool("propagate-deletes", true, "delete objects deleted from branch from the export path (...=false to only add and update objects)")
+	exportSetCmd.Flags().Bool("write-manifest", false, "write a CSV manifest of all exported objects to the status path after every successful export")
+	exportSetCmd.Flags().StringArray("include-prefix", nil, "export only objects under one of these prefixes (default is all objects)")
+	exportSetCmd.Flags().StringArray("exclude-glob", nil, "skip exporting objects matching one of these globs, globs with no \"/\" match the last element of the object path")
+	exportSetCmd.Flags().String("sse", "", "server-side encryption of objects exported to S3: AES256 (SSE-S3) or aws:kms (SSE-KMS) (default is no encryption)")
+	exportSetCmd.Flags().String("sse-kms-key-id", "", "ID or ARN of the KMS key encrypting objects exported with --sse aws:kms (default is the AWS managed key)")
+	exportSetCmd.Flags().String("schedule", "", "cron expression of the times (in UTC) to export branch, e.g. \"0 2 * * *\" (default is no scheduled exports)")
 	exportSetCmd.Flags().Bool("continuous", false, "export branch after every commit or merge (...=false to disable)")
 	_ = exportSetCmd.MarkFlagRequired("path")
 	_ = exportSetCmd.MarkFlagRequired("continuous")
+
+	exportDriftCmd.Flags().Bool("reconcile", false, "re-export the drifted objects")
+
+	exportPlanCmd.Flags().String("ref", "", "ref to export (default is the branch)")
+	exportPlanCmd.Flags().Int("amount", 1000, "maximal number of operations to show")
 }
@@ -2,13 +2,18 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"io"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/cmdutils"
+	"github.com/treeverse/lakefs/preview"
 	"github.com/treeverse/lakefs/uri"
 )
 
@@ -19,22 +24,55 @@ Human Size: {{ .SizeBytes|human_bytes }}
 Checksum: {{.Checksum}}
 `
 
+const fsStatNotFoundTemplate = `Path: {{ . | yellow }}
+Not found
+`
+
 var fsStatCmd = &cobra.Command{
-	Use:   "stat <path uri>",
+	Use:   "stat <path uri> [<path uri>...]",
 	Short: "view object metadata",
+	Long:  "view object metadata, of several objects of the same repository in a single request",
 	Args: cmdutils.ValidationChain(
-		cobra.ExactArgs(1),
+		cobra.MinimumNArgs(1),
 		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
 	),
 	Run: func(cmd *cobra.Command, args []string) {
 		pathURI := uri.Must(uri.Parse(args[0]))
 		client := getClient()
-		stat, err := client.StatObject(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path)
+		if len(args) == 1 {
+			stat, err := client.StatObject(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path)
+			if err != nil {
+				DieErr(err)
+			}
+			Write(fsStatTemplate, stat)
+			return
+		}
+
+		refs := make([]*models.ObjectStatRef, len(args))
+		for i, arg := range args {
+			if err := uri.ValidatePathURI(arg); err != nil {
+				DieFmt("argument at position %d: %s", i, err)
+			}
+			u := uri.Must(uri.Parse(arg))
+			if u.Repository != pathURI.Repository {
+				DieFmt("%s is not in repository %s", arg, pathURI.Repository)
+			}
+			refs[i] = &models.ObjectStatRef{Ref: swag.String(u.Ref), Path: swag.String(u.Path)}
+		}
+		results, err := client.StatObjects(context.Background(), pathURI.Repository, refs)
 		if err != nil {
 			DieErr(err)
 		}
-
-		Write(fsStatTemplate, stat)
+		for i, result := range results {
+			if i > 0 {
+				Fmt("\n")
+			}
+			if !swag.BoolValue(result.Found) {
+				Write(fsStatNotFoundTemplate, args[i])
+				continue
+			}
+			Write(fsStatTemplate, result.Stats)
+		}
 	},
 }
 
@@ -90,6 +128,103 @@ var fsCatCmd = &cobra.Command{
 	},
 }
 
+var fsPreviewCmd = &cobra.Command{
+	Use:   "preview <path uri>",
+	Short: "show the head of an object, decoding rows of csv and json lines objects",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidatePathURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		maxBytes, _ := cmd.Flags().GetInt("max-bytes")
+		maxRows, _ := cmd.Flags().GetInt("max-rows")
+		client := getClient()
+		pathURI := uri.Must(uri.Parse(args[0]))
+		p, err := client.PreviewObject(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path, maxBytes, maxRows)
+		if err != nil {
+			DieErr(err)
+		}
+		switch p.Format {
+		case "csv":
+			rows := make([][]interface{}, 0, len(p.Rows))
+			for _, row := range p.Rows {
+				r := make([]interface{}, len(row))
+				for i := range row {
+					r[i] = row[i]
+				}
+				rows = append(rows, r)
+			}
+			var headers []interface{}
+			if len(rows) > 0 {
+				headers, rows = rows[0], rows[1:]
+			}
+			PrintTable(rows, headers, nil, 0)
+		case "jsonl":
+			for _, record := range p.Records {
+				line, err := json.Marshal(record)
+				if err != nil {
+					DieErr(err)
+				}
+				Fmt("%s\n", line)
+			}
+		case "binary":
+			Fmt("binary content (%s), %d bytes\n", p.ContentType, p.SizeBytes)
+		default:
+			Fmt("%s\n", p.Text)
+		}
+		if p.Truncated {
+			Fmt("...\n")
+		}
+	},
+}
+
+const fsSchemaTemplate = `Path: {{.Path | yellow }}
+Format: {{.Format}}
+{{ if .RowCount }}Rows: {{.RowCount}}
+{{ end }}Columns:
+{{ range $col := .Columns -}}
+  {{ $col.Name|ljust 30 }} {{ $col.Type }}
+{{ end -}}
+`
+
+var fsSchemaCmd = &cobra.Command{
+	Use:   "schema <path uri>",
+	Short: "show the schema, row count and sample rows of a parquet, orc or csv object",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidatePathURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		maxRows, _ := cmd.Flags().GetInt("max-rows")
+		client := getClient()
+		pathURI := uri.Must(uri.Parse(args[0]))
+		schema, err := client.GetObjectSchema(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path, maxRows)
+		if err != nil {
+			DieErr(err)
+		}
+		type column struct {
+			Name string
+			Type string
+		}
+		columns := make([]column, len(schema.Columns))
+		headers := make([]interface{}, len(schema.Columns))
+		for i, col := range schema.Columns {
+			columns[i] = column{Name: swag.StringValue(col.Name), Type: swag.StringValue(col.Type)}
+			headers[i] = columns[i].Name
+		}
+		Write(fsSchemaTemplate, struct {
+			Path     string
+			Format   string
+			RowCount *int64
+			Columns  []column
+		}{schema.Path, schema.Format, schema.RowCount, columns})
+		if len(schema.Sample) == 0 {
+			return
+		}
+		PrintTable(schema.Sample, headers, nil, 0)
+	},
+}
+
 var fsUploadCmd = &cobra.Command{
 	Use:   "upload <path uri>",
 	Short: "upload a local file to the specified URI",
@@ -125,20 +260,192 @@ var fsUploadCmd = &cobra.Command{
 	},
 }
 
+const fsRmNotDeletedTemplate = `{{ range . }}{{ .Path | yellow }}: {{ .Error }}
+{{ end }}`
+
 var fsRmCmd = &cobra.Command{
-	Use:   "rm <path uri>",
-	Short: "delete object",
+	Use:   "rm <path uri> [<path uri>...]",
+	Short: "delete objects",
+	Long:  "delete objects, all on the same branch, in a single transaction",
+	Args: cmdutils.ValidationChain(
+		cobra.MinimumNArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidatePathURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		pathURI := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		if len(args) == 1 {
+			err := client.DeleteObject(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path)
+			if err != nil {
+				DieErr(err)
+			}
+			return
+		}
+		paths := make([]string, len(args))
+		for i, arg := range args {
+			if err := uri.ValidatePathURI(arg); err != nil {
+				DieFmt("argument at position %d: %s", i, err)
+			}
+			u := uri.Must(uri.Parse(arg))
+			if u.Repository != pathURI.Repository || u.Ref != pathURI.Ref {
+				DieFmt("%s is not on branch %s of repository %s", arg, pathURI.Ref, pathURI.Repository)
+			}
+			paths[i] = u.Path
+		}
+		results, err := client.DeleteObjects(context.Background(), pathURI.Repository, pathURI.Ref, paths)
+		if err != nil {
+			DieErr(err)
+		}
+		type notDeletedPath struct {
+			Path  string
+			Error string
+		}
+		var notDeleted []notDeletedPath
+		for _, result := range results {
+			if !swag.BoolValue(result.Deleted) {
+				notDeleted = append(notDeleted, notDeletedPath{Path: swag.StringValue(result.Path), Error: result.Error})
+			}
+		}
+		Write(fsRmNotDeletedTemplate, notDeleted)
+	},
+}
+
+var fsCpCmd = &cobra.Command{
+	Use:   "cp <source path uri> <destination path uri>",
+	Short: "copy an object, possibly from another repository, without moving its data when possible",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(2),
+		cmdutils.FuncValidator(0, uri.ValidatePathURI),
+		cmdutils.FuncValidator(1, uri.ValidatePathURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		sourceURI := uri.Must(uri.Parse(args[0]))
+		destinationURI := uri.Must(uri.Parse(args[1]))
+		client := getClient()
+		stat, err := client.CopyObject(context.Background(), sourceURI.Repository, sourceURI.Ref, sourceURI.Path,
+			destinationURI.Repository, destinationURI.Ref, destinationURI.Path)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(fsStatTemplate, stat)
+	},
+}
+
+var fsPresignCmd = &cobra.Command{
+	Use:   "presign <path uri>",
+	Short: "print a time-limited S3 gateway URL to download or upload an object without credentials",
 	Args: cmdutils.ValidationChain(
 		cobra.ExactArgs(1),
 		cmdutils.FuncValidator(0, uri.ValidatePathURI),
 	),
 	Run: func(cmd *cobra.Command, args []string) {
+		expires, err := cmd.Flags().GetDuration("expires")
+		if err != nil {
+			DieErr(err)
+		}
+		upload, err := cmd.Flags().GetBool("upload")
+		if err != nil {
+			DieErr(err)
+		}
+		method := http.MethodGet
+		if upload {
+			method = http.MethodPut
+		}
 		pathURI := uri.Must(uri.Parse(args[0]))
 		client := getClient()
-		err := client.DeleteObject(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path)
+		presigned, err := client.PresignObject(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path, method, expires)
 		if err != nil {
 			DieErr(err)
 		}
+		Fmt("%s\n", swag.StringValue(presigned.URL))
+	},
+}
+
+const fsHistoryTemplate = `{{ range $val := .Records }}
+{{ $val.Type|ljust 8 }}{{ $val.Commit.ID|yellow }}{{ if $val.SizeBytes }}    {{ $val.SizeBytes|human_bytes }}    {{ $val.Checksum }}{{ end }}
+Author: {{ $val.Commit.Committer }}
+Date: {{ $val.Commit.CreationDate|date }}
+	{{ $val.Commit.Message }}
+{{ end }}
+{{.Pagination | paginate }}
+`
+
+var fsHistoryCmd = &cobra.Command{
+	Use:   "history <path uri>",
+	Short: "show the commits that added, changed or removed an object",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidatePathURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		amount, err := cmd.Flags().GetInt("amount")
+		if err != nil {
+			DieErr(err)
+		}
+		after, err := cmd.Flags().GetString("after")
+		if err != nil {
+			DieErr(err)
+		}
+		pathURI := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		records, pagination, err := client.GetObjectHistory(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path, after, amount)
+		if err != nil {
+			DieErr(err)
+		}
+		ctx := struct {
+			Records    []*models.ObjectHistoryRecord
+			Pagination *Pagination
+		}{
+			Records: records,
+		}
+		if pagination != nil && swag.BoolValue(pagination.HasMore) {
+			ctx.Pagination = &Pagination{
+				Amount:  amount,
+				HasNext: true,
+				After:   pagination.NextOffset,
+			}
+		}
+		Write(fsHistoryTemplate, ctx)
+	},
+}
+
+const fsDuTemplate = `{{ range $val := . -}}
+{{ $val.SizeBytes|human_bytes|ljust 12 }}    {{ printf "%-10d" $val.Objects }}    {{ $val.Prefix|yellow }}
+{{ end -}}
+`
+
+var fsDuCmd = &cobra.Command{
+	Use:   "du <path uri>",
+	Short: "show the number and total size of objects under a prefix and its sub-prefixes",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.Or(
+			cmdutils.FuncValidator(0, uri.ValidatePathURI),
+			cmdutils.FuncValidator(0, uri.ValidateRefURI),
+		),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		depth, err := cmd.Flags().GetInt("depth")
+		if err != nil {
+			DieErr(err)
+		}
+		pathURI := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		stats, err := client.GetPrefixStats(context.Background(), pathURI.Repository, pathURI.Ref, pathURI.Path, depth)
+		if err != nil {
+			DieErr(err)
+		}
+		rows := make([]struct {
+			Prefix    string
+			Objects   int64
+			SizeBytes int64
+		}, len(stats))
+		for i, s := range stats {
+			rows[i].Prefix = swag.StringValue(s.Prefix)
+			rows[i].Objects = swag.Int64Value(s.Objects)
+			rows[i].SizeBytes = swag.Int64Value(s.SizeBytes)
+		}
+		Write(fsDuTemplate, rows)
 	},
 }
 
@@ -154,9 +461,23 @@ func init() {
 	fsCmd.AddCommand(fsStatCmd)
 	fsCmd.AddCommand(fsListCmd)
 	fsCmd.AddCommand(fsCatCmd)
+	fsCmd.AddCommand(fsPreviewCmd)
+	fsCmd.AddCommand(fsSchemaCmd)
 	fsCmd.AddCommand(fsUploadCmd)
 	fsCmd.AddCommand(fsRmCmd)
+	fsCmd.AddCommand(fsCpCmd)
+	fsCmd.AddCommand(fsHistoryCmd)
+	fsCmd.AddCommand(fsDuCmd)
+	fsCmd.AddCommand(fsPresignCmd)
 
+	fsPreviewCmd.Flags().Int("max-bytes", preview.DefaultMaxBytes, "maximal number of bytes to read from the head of the object")
+	fsPreviewCmd.Flags().Int("max-rows", preview.DefaultMaxRows, "maximal number of rows to show for csv and json lines objects")
+	fsSchemaCmd.Flags().Int("max-rows", preview.DefaultMaxRows, "maximal number of sample rows to show")
 	fsUploadCmd.Flags().StringP("source", "s", "", "local file to upload, or \"-\" for stdin")
 	_ = fsUploadCmd.MarkFlagRequired("source")
+	fsHistoryCmd.Flags().Int("amount", -1, "how many results to return, or-1 for all results (used for pagination)")
+	fsHistoryCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	fsPresignCmd.Flags().Duration("expires", 15*time.Minute, "how long the URL remains valid, up to a week")
+	fsPresignCmd.Flags().Bool("upload", false, "sign a PUT URL uploading the object to a branch, instead of a GET URL")
+	fsDuCmd.Flags().Int("depth", 0, "number of path levels below the prefix to show sub-prefixes for")
 }
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/api/gen/models"
+	"github.com/treeverse/lakefs/cmdutils"
+	"github.com/treeverse/lakefs/uri"
+)
+
+const hookRunArgs = 2
+
+var hookRunsTemplate = `{{.RunsTable | table -}}
+{{.Pagination | paginate }}
+`
+
+var hookDryRunTemplate = `{{.RunsTable | table -}}
+{{ range .Failed }}{{.Action}} {{.HookID}}: {{.Output|red}}
+{{ end }}`
+
+var hookRunTemplate = `ID: {{.ID|yellow}}
+Event: {{.EventType}} {{.EventID}}
+Branch: {{.Branch}}
+Commit: {{.CommitID}}
+Action: {{.Action}}
+Hook: {{.HookID}} ({{.HookType}})
+Status: {{.Status}}
+Attempt: {{.Attempt}}
+Start Time: {{.StartTime}}
+Duration: {{.Duration}}
+{{ if .Output }}Output: {{.Output}}
+{{ end }}`
+
+// hooksCmd represents the hooks command
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "inspect hook runs, retry failed post-commit and post-merge hooks and test hooks with dry runs",
+}
+
+var hookRunsCmd = &cobra.Command{
+	Use:   "runs <repository uri>",
+	Short: "list hook runs, newest first",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		amount, _ := cmd.Flags().GetInt("amount")
+		after, _ := cmd.Flags().GetString("after")
+		commitID, _ := cmd.Flags().GetString("commit")
+		branch, _ := cmd.Flags().GetString("branch")
+		status, _ := cmd.Flags().GetString("status")
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		runs, pagination, err := client.ListHookRuns(context.Background(), u.Repository, commitID, branch, status, after, amount)
+		if err != nil {
+			DieErr(err)
+		}
+
+		rows := make([][]interface{}, len(runs))
+		for i, run := range runs {
+			ts := time.Unix(swag.Int64Value(run.StartTime), 0).String()
+			rows[i] = []interface{}{
+				swag.StringValue(run.ID), ts, swag.StringValue(run.EventType), run.CommitID,
+				swag.StringValue(run.Action), swag.StringValue(run.HookID), swag.StringValue(run.Status), swag.Int64Value(run.Attempt),
+			}
+		}
+		ctx := struct {
+			RunsTable  *Table
+			Pagination *Pagination
+		}{
+			RunsTable: &Table{
+				Headers: []interface{}{"ID", "Start Time", "Event", "Commit", "Action", "Hook", "Status", "Attempt"},
+				Rows:    rows,
+			},
+		}
+		if pagination != nil && swag.BoolValue(pagination.HasMore) {
+			ctx.Pagination = &Pagination{
+				Amount:  amount,
+				HasNext: true,
+				After:   pagination.NextOffset,
+			}
+		}
+		Write(hookRunsTemplate, ctx)
+	},
+}
+
+var hookRunShowCmd = &cobra.Command{
+	Use:   "show <repository uri> <run id>",
+	Short: "show a hook run, with the error of a failed run",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(hookRunArgs),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		runID := mustParseRunID(args[1])
+		client := getClient()
+		run, err := client.GetHookRun(context.Background(), u.Repository, runID)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(hookRunTemplate, newHookRunOutput(run))
+	},
+}
+
+var hookRunRetryCmd = &cobra.Command{
+	Use:   "retry <repository uri> <run id>",
+	Short: "run the hook of a failed post-commit or post-merge run again",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(hookRunArgs),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		runID := mustParseRunID(args[1])
+		client := getClient()
+		run, err := client.RetryHookRun(context.Background(), u.Repository, runID)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(hookRunTemplate, newHookRunOutput(run))
+	},
+}
+
+var hookDryRunCmd = &cobra.Command{
+	Use:   "dry-run <branch uri>",
+	Short: "run the hooks of an event on a branch without performing its operation",
+	Long: `run the hooks of an event on a branch without performing its operation, and print the result of each hook.
+Pre-commit hooks run on the uncommitted changes of the branch, pre-merge hooks on the changes of the source
+reference, and post hooks on the changes between the source and the parent references.`,
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		eventType, _ := cmd.Flags().GetString("event")
+		sourceRef, _ := cmd.Flags().GetString("source")
+		parentRef, _ := cmd.Flags().GetString("parent")
+		message, _ := cmd.Flags().GetString("message")
+		kvPairs, err := getKV(cmd, "meta")
+		if err != nil {
+			DieErr(err)
+		}
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		runs, err := client.DryRunHooks(context.Background(), u.Repository, &models.HookDryRun{
+			EventType:     swag.String(eventType),
+			Branch:        swag.String(u.Ref),
+			SourceRef:     sourceRef,
+			ParentRef:     parentRef,
+			CommitMessage: message,
+			Metadata:      kvPairs,
+		})
+		if err != nil {
+			DieErr(err)
+		}
+
+		rows := make([][]interface{}, len(runs))
+		var failed []*hookRunOutput
+		for i, run := range runs {
+			rows[i] = []interface{}{
+				swag.StringValue(run.Action), swag.StringValue(run.HookID), run.HookType, swag.StringValue(run.Status),
+				time.Duration(run.DurationMs) * time.Millisecond,
+			}
+			if run.Output != "" {
+				failed = append(failed, newHookRunOutput(run))
+			}
+		}
+		Write(hookDryRunTemplate, struct {
+			RunsTable *Table
+			Failed    []*hookRunOutput
+		}{
+			RunsTable: &Table{
+				Headers: []interface{}{"Action", "Hook", "Type", "Status", "Duration"},
+				Rows:    rows,
+			},
+			Failed: failed,
+		})
+	},
+}
+
+func mustParseRunID(s string) int64 {
+	runID, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		DieFmt("invalid run id '%s'", s)
+	}
+	return runID
+}
+
+type hookRunOutput struct {
+	ID        string
+	EventType string
+	EventID   string
+	Branch    string
+	CommitID  string
+	Action    string
+	HookID    string
+	HookType  string
+	Status    string
+	Attempt   int64
+	StartTime string
+	Duration  time.Duration
+	Output    string
+}
+
+func newHookRunOutput(run *models.HookRun) *hookRunOutput {
+	return &hookRunOutput{
+		ID:        swag.StringValue(run.ID),
+		EventType: swag.StringValue(run.EventType),
+		EventID:   swag.StringValue(run.EventID),
+		Branch:    run.Branch,
+		CommitID:  run.CommitID,
+		Action:    swag.StringValue(run.Action),
+		HookID:    swag.StringValue(run.HookID),
+		HookType:  run.HookType,
+		Status:    swag.StringValue(run.Status),
+		Attempt:   swag.Int64Value(run.Attempt),
+		StartTime: time.Unix(swag.Int64Value(run.StartTime), 0).String(),
+		Duration:  time.Duration(run.DurationMs) * time.Millisecond,
+		Output:    run.Output,
+	}
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hookRunsCmd)
+	hooksCmd.AddCommand(hookRunShowCmd)
+	hooksCmd.AddCommand(hookRunRetryCmd)
+	hooksCmd.AddCommand(hookDryRunCmd)
+
+	hookRunsCmd.Flags().Int("amount", -1, "how many results to return, or-1 for all results (used for pagination)")
+	hookRunsCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	hookRunsCmd.Flags().String("commit", "", "show only runs of the operation that created this commit")
+	hookRunsCmd.Flags().String("branch", "", "show only runs of operations on this branch")
+	hookRunsCmd.Flags().String("status", "", "show only runs with this status: completed or failed")
+
+	hookDryRunCmd.Flags().String("event", "pre-commit", "event to run the hooks of: pre-commit, pre-merge, post-commit or post-merge")
+	hookDryRunCmd.Flags().String("source", "", "reference holding the changes, defaults to the branch for pre-commit events")
+	hookDryRunCmd.Flags().String("parent", "", "reference the changes of post events are compared to")
+	hookDryRunCmd.Flags().StringP("message", "m", "", "commit message of the event")
+	hookDryRunCmd.Flags().StringSlice("meta", []string{}, "key value pair in the form of key=value")
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/api"
+	"github.com/treeverse/lakefs/api/gen/models"
+	"github.com/treeverse/lakefs/cmdutils"
+	"github.com/treeverse/lakefs/uri"
+)
+
+const (
+	jobArgs         = 2
+	jobPollInterval = 2 * time.Second
+)
+
+var jobsTemplate = `{{.JobsTable | table -}}
+{{.Pagination | paginate }}
+`
+
+var jobTemplate = `ID: {{.ID|yellow}}
+Type: {{.Type}}
+Branch: {{.Branch}}
+Status: {{.Status}}
+Message: {{.Message}}
+Committed Entries: {{.CommittedEntries}}
+Last Path: {{.LastPath}}
+Created: {{.CreationDate}}
+Updated: {{.UpdateDate}}
+{{ if .CommitID }}Commit: {{.CommitID}}
+{{ end }}{{ if .Error }}Error: {{.Error|red}}
+{{ end }}`
+
+// jobsCmd represents the jobs command
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "inspect, resume and abort long running jobs, such as chunked commits",
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:   "list <repository uri>",
+	Short: "list jobs, newest first",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		amount, _ := cmd.Flags().GetInt("amount")
+		after, _ := cmd.Flags().GetString("after")
+		branch, _ := cmd.Flags().GetString("branch")
+		status, _ := cmd.Flags().GetString("status")
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		jobs, pagination, err := client.ListJobs(context.Background(), u.Repository, branch, status, after, amount)
+		if err != nil {
+			DieErr(err)
+		}
+
+		rows := make([][]interface{}, len(jobs))
+		for i, job := range jobs {
+			rows[i] = []interface{}{
+				swag.Int64Value(job.ID), swag.StringValue(job.Type), swag.StringValue(job.Branch), swag.StringValue(job.Status),
+				swag.Int64Value(job.CommittedEntries), time.Unix(swag.Int64Value(job.CreationDate), 0).String(), job.CommitID,
+			}
+		}
+		ctx := struct {
+			JobsTable  *Table
+			Pagination *Pagination
+		}{
+			JobsTable: &Table{
+				Headers: []interface{}{"ID", "Type", "Branch", "Status", "Committed Entries", "Created", "Commit"},
+				Rows:    rows,
+			},
+		}
+		if pagination != nil && swag.BoolValue(pagination.HasMore) {
+			ctx.Pagination = &Pagination{
+				Amount:  amount,
+				HasNext: true,
+				After:   pagination.NextOffset,
+			}
+		}
+		Write(jobsTemplate, ctx)
+	},
+}
+
+var jobShowCmd = &cobra.Command{
+	Use:   "show <repository uri> <job id>",
+	Short: "show a job, with the error of a failed job",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(jobArgs),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		jobID := mustParseJobID(args[1])
+		client := getClient()
+		job, err := client.GetJob(context.Background(), u.Repository, jobID)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(jobTemplate, newJobOutput(job))
+	},
+}
+
+var jobResumeCmd = &cobra.Command{
+	Use:   "resume <repository uri> <job id>",
+	Short: "resume an interrupted job from where it stopped",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(jobArgs),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		wait, _ := cmd.Flags().GetBool("wait")
+		u := uri.Must(uri.Parse(args[0]))
+		jobID := mustParseJobID(args[1])
+		client := getClient()
+		job, err := client.ResumeJob(context.Background(), u.Repository, jobID)
+		if err != nil {
+			DieErr(err)
+		}
+		if wait {
+			job = waitForJob(client, u.Repository, job)
+		}
+		Write(jobTemplate, newJobOutput(job))
+	},
+}
+
+var jobAbortCmd = &cobra.Command{
+	Use:   "abort <repository uri> <job id>",
+	Short: "roll back a running job, leaving its branch as it was before the job",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(jobArgs),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		jobID := mustParseJobID(args[1])
+		client := getClient()
+		job, err := client.AbortJob(context.Background(), u.Repository, jobID)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(jobTemplate, newJobOutput(job))
+	},
+}
+
+// waitForJob polls job until it is no longer running and returns its last state
+func waitForJob(client api.Client, repository string, job *models.Job) *models.Job {
+	for swag.StringValue(job.Status) == models.JobStatusRunning {
+		time.Sleep(jobPollInterval)
+		var err error
+		job, err = client.GetJob(context.Background(), repository, swag.Int64Value(job.ID))
+		if err != nil {
+			DieErr(err)
+		}
+	}
+	return job
+}
+
+func mustParseJobID(s string) int64 {
+	jobID, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		DieFmt("invalid job id '%s'", s)
+	}
+	return jobID
+}
+
+type jobOutput struct {
+	ID               int64
+	Type             string
+	Branch           string
+	Status           string
+	Message          string
+	CommittedEntries int64
+	LastPath         string
+	CreationDate     string
+	UpdateDate       string
+	CommitID         string
+	Error            string
+}
+
+func newJobOutput(job *models.Job) *jobOutput {
+	return &jobOutput{
+		ID:               swag.Int64Value(job.ID),
+		Type:             swag.StringValue(job.Type),
+		Branch:           swag.StringValue(job.Branch),
+		Status:           swag.StringValue(job.Status),
+		Message:          job.Message,
+		CommittedEntries: swag.Int64Value(job.CommittedEntries),
+		LastPath:         job.LastPath,
+		CreationDate:     time.Unix(swag.Int64Value(job.CreationDate), 0).String(),
+		UpdateDate:       time.Unix(swag.Int64Value(job.UpdateDate), 0).String(),
+		CommitID:         job.CommitID,
+		Error:            job.Error,
+	}
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(jobsCmd)
+	jobsCmd.AddCommand(jobsListCmd)
+	jobsCmd.AddCommand(jobShowCmd)
+	jobsCmd.AddCommand(jobResumeCmd)
+	jobsCmd.AddCommand(jobAbortCmd)
+
+	jobsListCmd.Flags().Int("amount", -1, "how many results to return, or-1 for all results (used for pagination)")
+	jobsListCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	jobsListCmd.Flags().String("branch", "", "show only jobs on this branch")
+	jobsListCmd.Flags().String("status", "", "show only jobs with this status: running, failed or completed")
+
+	jobResumeCmd.Flags().Bool("wait", false, "wait for the job to complete or fail")
+}
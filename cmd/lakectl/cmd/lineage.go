@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/api/gen/models"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/cmdutils"
+	"github.com/treeverse/lakefs/uri"
+)
+
+// lineageCmd represents the lineage command
+var lineageCmd = &cobra.Command{
+	Use:   "lineage",
+	Short: "record and show the refs commits were produced from",
+	Long: `Record the refs, on any repository, a commit was produced from, and walk the lineage
+upstream (what a commit was produced from) or downstream (what was produced from a commit).`,
+}
+
+var lineageAddCmd = &cobra.Command{
+	Use:     "add <ref uri>",
+	Short:   "record the refs a commit was produced from",
+	Example: "lakectl lineage add lakefs://reports@master --source lakefs://events@<commit id> --source lakefs://users@master",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		sourceURIs, err := cmd.Flags().GetStringArray("source")
+		if err != nil {
+			DieErr(err)
+		}
+		sources := make([]*models.DataLineageSource, len(sourceURIs))
+		for i, sourceURI := range sourceURIs {
+			if err := uri.ValidateRefURI(sourceURI); err != nil {
+				DieFmt("invalid source '%s': %s", sourceURI, err)
+			}
+			u := uri.Must(uri.Parse(sourceURI))
+			sources[i] = &models.DataLineageSource{
+				Repository: swag.String(u.Repository),
+				Ref:        swag.String(u.Ref),
+			}
+		}
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		err = client.CreateDataLineage(context.Background(), u.Repository, u.Ref, sources)
+		if err != nil {
+			DieErr(err)
+		}
+		Fmt("recorded %d sources of '%s'\n", len(sources), u.String())
+	},
+}
+
+var lineageShowCmd = &cobra.Command{
+	Use:   "show <ref uri>",
+	Short: "show the lineage edges upstream, or downstream, of a commit",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		downstream, err := cmd.Flags().GetBool("downstream")
+		if err != nil {
+			DieErr(err)
+		}
+		depth, err := cmd.Flags().GetInt("depth")
+		if err != nil {
+			DieErr(err)
+		}
+		direction := catalog.DataLineageUpstream
+		if downstream {
+			direction = catalog.DataLineageDownstream
+		}
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		edges, err := client.WalkDataLineage(context.Background(), u.Repository, u.Ref, string(direction), depth)
+		if err != nil {
+			DieErr(err)
+		}
+		rows := make([][]interface{}, len(edges))
+		for i, edge := range edges {
+			commitURI := &uri.URI{Protocol: uri.LakeFSProtocol, Repository: edge.Repository, Ref: edge.Ref}
+			sourceURI := &uri.URI{Protocol: uri.LakeFSProtocol, Repository: edge.SourceRepository, Ref: edge.SourceRef}
+			rows[i] = []interface{}{
+				edge.Depth,
+				commitURI.String(),
+				sourceURI.String(),
+				time.Unix(edge.CreationDate, 0).String(),
+			}
+		}
+		PrintTable(rows, []interface{}{"Depth", "Commit", "Produced From", "Recorded"}, nil, 0)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(lineageCmd)
+	lineageCmd.AddCommand(lineageAddCmd)
+	lineageCmd.AddCommand(lineageShowCmd)
+
+	lineageAddCmd.Flags().StringArrayP("source", "s", nil, "ref uri the commit was produced from (can be repeated)")
+	_ = lineageAddCmd.MarkFlagRequired("source")
+
+	lineageShowCmd.Flags().Bool("downstream", false, "show commits produced from the commit, instead of the commits it was produced from")
+	lineageShowCmd.Flags().Int("depth", 0, "maximal number of edges to walk from the commit, 0 for the maximum")
+}
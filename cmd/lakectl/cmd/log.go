@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-openapi/swag"
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/api/gen/models"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/cmdutils"
 	"github.com/treeverse/lakefs/uri"
 )
@@ -44,9 +47,13 @@ var logCmd = &cobra.Command{
 		if err != nil {
 			DieErr(err)
 		}
+		filter, err := getCommitsFilter(cmd)
+		if err != nil {
+			DieErr(err)
+		}
 		client := getClient()
 		branchURI := uri.Must(uri.Parse(args[0]))
-		commits, pagination, err := client.GetCommitLog(context.Background(), branchURI.Repository, branchURI.Ref, after, amount)
+		commits, pagination, err := client.GetCommitLog(context.Background(), branchURI.Repository, branchURI.Ref, after, amount, filter)
 		ctx := struct {
 			Commits    []*models.Commit
 			Pagination *Pagination
@@ -67,9 +74,46 @@ var logCmd = &cobra.Command{
 	},
 }
 
+// getCommitsFilter returns the commits filter of the log command flags
+func getCommitsFilter(cmd *cobra.Command) (catalog.CommitsFilter, error) {
+	var filter catalog.CommitsFilter
+	var err error
+	if filter.MessageContains, err = cmd.Flags().GetString("message"); err != nil {
+		return filter, err
+	}
+	if filter.MessageRegexp, err = cmd.Flags().GetString("message-regex"); err != nil {
+		return filter, err
+	}
+	if filter.Committer, err = cmd.Flags().GetString("committer"); err != nil {
+		return filter, err
+	}
+	if filter.Metadata, err = getKV(cmd, "meta"); err != nil {
+		return filter, err
+	}
+	for name, t := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value, err := cmd.Flags().GetString(name)
+		if err != nil {
+			return filter, err
+		}
+		if value == "" {
+			continue
+		}
+		if *t, err = time.Parse(time.RFC3339, value); err != nil {
+			return filter, fmt.Errorf("--%s: %w", name, err)
+		}
+	}
+	return filter, nil
+}
+
 //nolint:gochecknoinits
 func init() {
 	rootCmd.AddCommand(logCmd)
 	logCmd.Flags().Int("amount", -1, "how many results to return, or-1 for all results (used for pagination)")
 	logCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	logCmd.Flags().String("message", "", "show only commits whose message contains this string, ignoring case")
+	logCmd.Flags().String("message-regex", "", "show only commits whose message matches this regular expression")
+	logCmd.Flags().String("committer", "", "show only commits by this committer")
+	logCmd.Flags().StringSlice("meta", []string{}, "show only commits with this metadata, key value pairs in the form of key=value")
+	logCmd.Flags().String("since", "", "show only commits created at or after this time (RFC3339)")
+	logCmd.Flags().String("until", "", "show only commits created before this time (RFC3339)")
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/api/gen/models"
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/cmdutils"
 	"github.com/treeverse/lakefs/uri"
@@ -35,7 +36,29 @@ var mergeCmd = &cobra.Command{
 			Die("both references must belong to the same repository", 1)
 		}
 
-		result, err := client.Merge(context.Background(), leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref)
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			preview, err := client.MergePreview(context.Background(), leftRefURI.Repository, rightRefURI.Ref, leftRefURI.Ref)
+			if err != nil {
+				DieErr(err)
+			}
+			_, _ = fmt.Printf("new: %d modified: %d removed: %d conflicts: %d\n",
+				preview.Summary.Added, preview.Summary.Changed, preview.Summary.Removed, preview.Summary.Conflict)
+			for _, conflict := range preview.Conflicts {
+				_, _ = fmt.Printf("  %s (source: %s, destination: %s)\n", conflict.Path, conflict.SourceType, conflict.DestinationType)
+			}
+			if len(preview.Conflicts) > 0 {
+				Die("merge has conflicts", 1)
+			}
+			return
+		}
+		squash, _ := cmd.Flags().GetBool("squash")
+		strategy, _ := cmd.Flags().GetString("strategy")
+		contentMerge, _ := cmd.Flags().GetBool("content-merge")
+		result, err := client.Merge(context.Background(), leftRefURI.Repository, leftRefURI.Ref, rightRefURI.Ref, &models.Merge{
+			Squash:       squash,
+			Strategy:     strategy,
+			ContentMerge: contentMerge,
+		})
 		if errors.Is(err, catalog.ErrConflictFound) {
 			_, _ = fmt.Printf("Conflicts: %d\n", result.Summary.Conflict)
 			return
@@ -50,4 +73,8 @@ var mergeCmd = &cobra.Command{
 //nolint:gochecknoinits
 func init() {
 	rootCmd.AddCommand(mergeCmd)
+	mergeCmd.Flags().Bool("content-merge", false, "merge the content of conflicting objects of mergeable formats, such as JSON lines and CSV")
+	mergeCmd.Flags().Bool("dry-run", false, "print the changes and conflicts of the merge without merging, exit with an error on conflicts")
+	mergeCmd.Flags().Bool("squash", false, "commit the source changes as a single commit, without the source commits in the destination log")
+	mergeCmd.Flags().String("strategy", "", "resolution of paths changed on both branches: fail, source-wins or dest-wins (default fail)")
 }
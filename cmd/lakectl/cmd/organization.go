@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/api/gen/models"
+)
+
+var organizationCmd = &cobra.Command{
+	Use:   "organization",
+	Short: "manage quotas shared by the repositories of an organization",
+}
+
+var organizationQuotaCmd = &cobra.Command{
+	Use:   "quota [sub-command]",
+	Short: "manage organization quota",
+}
+
+var organizationQuotaTemplate = `Max storage bytes: {{.MaxStorageBytes}}
+Max objects: {{.MaxObjects}}
+Max requests per second: {{.MaxRequestsPerSecond}}
+Max export bytes per second: {{.MaxExportBytesPerSecond}}
+`
+
+var organizationGetQuotaCmd = &cobra.Command{
+	Use:   "get <organization>",
+	Short: "show organization quota (0 means no limit)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		quota, err := client.GetOrganizationQuota(context.Background(), args[0])
+		if err != nil {
+			DieErr(err)
+		}
+		Write(organizationQuotaTemplate, struct {
+			MaxStorageBytes         int64
+			MaxObjects              int64
+			MaxRequestsPerSecond    int64
+			MaxExportBytesPerSecond int64
+		}{
+			swag.Int64Value(quota.MaxStorageBytes),
+			swag.Int64Value(quota.MaxObjects),
+			swag.Int64Value(quota.MaxRequestsPerSecond),
+			swag.Int64Value(quota.MaxExportBytesPerSecond),
+		})
+	},
+}
+
+var organizationSetQuotaCmd = &cobra.Command{
+	Use:   "set <organization>",
+	Short: "create or set organization quota",
+	Long:  "create or set organization quota, overrides all fields of any previous quota. Use 0 for no limit. The quota is shared by the repositories whose quota names the organization",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		maxStorageBytes, err := cmd.Flags().GetInt64("max-storage-bytes")
+		if err != nil {
+			DieErr(err)
+		}
+		maxObjects, err := cmd.Flags().GetInt64("max-objects")
+		if err != nil {
+			DieErr(err)
+		}
+		maxRequestsPerSecond, err := cmd.Flags().GetInt64("max-requests-per-second")
+		if err != nil {
+			DieErr(err)
+		}
+		maxExportBytesPerSecond, err := cmd.Flags().GetInt64("max-export-bytes-per-second")
+		if err != nil {
+			DieErr(err)
+		}
+		client := getClient()
+		err = client.SetOrganizationQuota(context.Background(), args[0], &models.OrganizationQuota{
+			MaxStorageBytes:         swag.Int64(maxStorageBytes),
+			MaxObjects:              swag.Int64(maxObjects),
+			MaxRequestsPerSecond:    swag.Int64(maxRequestsPerSecond),
+			MaxExportBytesPerSecond: swag.Int64(maxExportBytesPerSecond),
+		})
+		if err != nil {
+			DieErr(err)
+		}
+	},
+}
+
+var organizationDeleteQuotaCmd = &cobra.Command{
+	Use:   "delete <organization>",
+	Short: "delete organization quota, its repositories no longer belong to an organization",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		err := client.DeleteOrganizationQuota(context.Background(), args[0])
+		if err != nil {
+			DieErr(err)
+		}
+	},
+}
+
+var organizationUsageCmd = &cobra.Command{
+	Use:   "usage <organization>",
+	Short: "show storage and objects used by the repositories of organization",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := getClient()
+		usage, err := client.GetOrganizationUsage(context.Background(), args[0])
+		if err != nil {
+			DieErr(err)
+		}
+		Write(repoUsageTemplate, usage)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	organizationQuotaCmd.AddCommand(organizationGetQuotaCmd)
+	organizationQuotaCmd.AddCommand(organizationSetQuotaCmd)
+	organizationQuotaCmd.AddCommand(organizationDeleteQuotaCmd)
+
+	rootCmd.AddCommand(organizationCmd)
+	organizationCmd.AddCommand(organizationQuotaCmd)
+	organizationCmd.AddCommand(organizationUsageCmd)
+
+	organizationSetQuotaCmd.Flags().Int64("max-storage-bytes", 0, "maximal number of bytes stored by the repositories of the organization (0 for no limit)")
+	organizationSetQuotaCmd.Flags().Int64("max-objects", 0, "maximal number of objects stored by the repositories of the organization (0 for no limit)")
+	organizationSetQuotaCmd.Flags().Int64("max-requests-per-second", 0, "maximal number of requests per second to the repositories of the organization served by each lakeFS server (0 for no limit)")
+	organizationSetQuotaCmd.Flags().Int64("max-export-bytes-per-second", 0, "maximal number of bytes per second copied by exports of the repositories of the organization on each lakeFS server (0 for no limit)")
+}
@@ -35,18 +35,26 @@ var repoListCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		amount, _ := cmd.Flags().GetInt("amount")
 		after, _ := cmd.Flags().GetString("after")
+		includeArchived, _ := cmd.Flags().GetBool("include-archived")
 
 		clt := getClient()
 
-		repos, pagination, err := clt.ListRepositories(context.Background(), after, amount)
+		repos, pagination, err := clt.ListRepositories(context.Background(), after, amount, includeArchived)
 		if err != nil {
 			DieErr(err)
 		}
 
+		headers := []interface{}{"Repository", "Creation Date", "Default Ref Name", "Storage Namespace"}
+		if includeArchived {
+			headers = append(headers, "Archived")
+		}
 		rows := make([][]interface{}, len(repos))
 		for i, repo := range repos {
 			ts := time.Unix(repo.CreationDate, 0).String()
 			rows[i] = []interface{}{repo.ID, ts, repo.DefaultBranch, repo.StorageNamespace}
+			if includeArchived {
+				rows[i] = append(rows[i], repo.Archived)
+			}
 		}
 
 		ctx := struct {
@@ -54,7 +62,7 @@ var repoListCmd = &cobra.Command{
 			Pagination *Pagination
 		}{
 			RepoTable: &Table{
-				Headers: []interface{}{"Repository", "Creation Date", "Default Ref Name", "Storage Namespace"},
+				Headers: headers,
 				Rows:    rows,
 			},
 		}
@@ -179,8 +187,364 @@ var setPolicyCmd = &cobra.Command{
 	},
 }
 
+var quotaCmd = &cobra.Command{
+	Use:   "quota [sub-command]",
+	Short: "manage repository quota",
+}
+
+var repoQuotaTemplate = `Max storage bytes: {{.MaxStorageBytes}}
+Max objects: {{.MaxObjects}}
+Max requests per second: {{.MaxRequestsPerSecond}}
+Max export bytes per second: {{.MaxExportBytesPerSecond}}
+{{ if .Organization }}Organization: {{.Organization}}
+{{ end }}`
+
+var getQuotaCmd = &cobra.Command{
+	Use:   "get <repository uri>",
+	Short: "show repository quota (0 means no limit)",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		quota, err := client.GetRepositoryQuota(context.Background(), u.Repository)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(repoQuotaTemplate, struct {
+			MaxStorageBytes         int64
+			MaxObjects              int64
+			MaxRequestsPerSecond    int64
+			MaxExportBytesPerSecond int64
+			Organization            string
+		}{
+			swag.Int64Value(quota.MaxStorageBytes),
+			swag.Int64Value(quota.MaxObjects),
+			swag.Int64Value(quota.MaxRequestsPerSecond),
+			swag.Int64Value(quota.MaxExportBytesPerSecond),
+			quota.Organization,
+		})
+	},
+}
+
+var setQuotaCmd = &cobra.Command{
+	Use:   "set <repository uri>",
+	Short: "set repository quota",
+	Long:  "set repository quota, overrides all fields of any previous quota. Use 0 for no limit. Request rate and export bandwidth are limited on each lakeFS server. A repository of an organization is also limited by the quota of the organization",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		maxStorageBytes, err := cmd.Flags().GetInt64("max-storage-bytes")
+		if err != nil {
+			DieErr(err)
+		}
+		maxObjects, err := cmd.Flags().GetInt64("max-objects")
+		if err != nil {
+			DieErr(err)
+		}
+		maxRequestsPerSecond, err := cmd.Flags().GetInt64("max-requests-per-second")
+		if err != nil {
+			DieErr(err)
+		}
+		maxExportBytesPerSecond, err := cmd.Flags().GetInt64("max-export-bytes-per-second")
+		if err != nil {
+			DieErr(err)
+		}
+		organization, err := cmd.Flags().GetString("organization")
+		if err != nil {
+			DieErr(err)
+		}
+		client := getClient()
+		err = client.SetRepositoryQuota(context.Background(), u.Repository, &models.RepositoryQuota{
+			MaxStorageBytes:         swag.Int64(maxStorageBytes),
+			MaxObjects:              swag.Int64(maxObjects),
+			MaxRequestsPerSecond:    swag.Int64(maxRequestsPerSecond),
+			MaxExportBytesPerSecond: swag.Int64(maxExportBytesPerSecond),
+			Organization:            organization,
+		})
+		if err != nil {
+			DieErr(err)
+		}
+	},
+}
+
+var repoSetDefaultBranchCmd = &cobra.Command{
+	Use:   "set-default-branch <branch uri>",
+	Short: "set the default branch of repository to an existing branch",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRefURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		err := client.SetDefaultBranch(context.Background(), u.Repository, u.Ref)
+		if err != nil {
+			DieErr(err)
+		}
+		Fmt("Default branch of repository '%s' set to '%s'\n", u.Repository, u.Ref)
+	},
+}
+
+var repoSetReadOnlyCmd = &cobra.Command{
+	Use:   "set-read-only <repository uri>",
+	Short: "reject all writes, commits and merges to repository, while still allowing reads",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		disable, _ := cmd.Flags().GetBool("disable")
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		err := client.SetRepositoryReadOnly(context.Background(), u.Repository, !disable)
+		if err != nil {
+			DieErr(err)
+		}
+		if disable {
+			Fmt("Repository '%s' is writable\n", u.Repository)
+		} else {
+			Fmt("Repository '%s' is read-only\n", u.Repository)
+		}
+	},
+}
+
+var repoArchiveCmd = &cobra.Command{
+	Use:   "archive <repository uri>",
+	Short: "archive a repository, hiding it from listings and rejecting writes to it",
+	Long: `Archive a repository: it is no longer listed and rejects all writes, commits and merges, while
+its objects can still be read.  With --storage-class its objects are also moved to a cheaper
+storage class.  Its metadata is kept, so "lakectl repo unarchive" restores it.`,
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		storageClass, _ := cmd.Flags().GetString("storage-class")
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		err := client.ArchiveRepository(context.Background(), u.Repository, storageClass)
+		if err != nil {
+			DieErr(err)
+		}
+		Fmt("Repository '%s' archived\n", u.Repository)
+	},
+}
+
+var repoUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive <repository uri>",
+	Short: "restore an archived repository, listing it and accepting writes to it again",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		storageClass, _ := cmd.Flags().GetString("storage-class")
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		err := client.UnarchiveRepository(context.Background(), u.Repository, storageClass)
+		if err != nil {
+			DieErr(err)
+		}
+		Fmt("Repository '%s' restored\n", u.Repository)
+	},
+}
+
+var commitLimitsCmd = &cobra.Command{
+	Use:   "commit-limits [sub-command]",
+	Short: "manage repository limits on the size of a single commit",
+}
+
+var repoCommitLimitsTemplate = `Max changed objects: {{.MaxChangedObjects}}
+Max added bytes: {{.MaxAddedBytes}}
+`
+
+var getCommitLimitsCmd = &cobra.Command{
+	Use:   "get <repository uri>",
+	Short: "show repository commit limits (0 means no limit)",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		limits, err := client.GetRepositoryCommitLimits(context.Background(), u.Repository)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(repoCommitLimitsTemplate, struct {
+			MaxChangedObjects int64
+			MaxAddedBytes     int64
+		}{swag.Int64Value(limits.MaxChangedObjects), swag.Int64Value(limits.MaxAddedBytes)})
+	},
+}
+
+var setCommitLimitsCmd = &cobra.Command{
+	Use:   "set <repository uri>",
+	Short: "set repository commit limits",
+	Long:  "set repository commit limits, overrides all fields of any previous limits. Use 0 for no limit. Users allowed fs:ExemptCommitLimits on the repository are not limited",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		maxChangedObjects, err := cmd.Flags().GetInt64("max-changed-objects")
+		if err != nil {
+			DieErr(err)
+		}
+		maxAddedBytes, err := cmd.Flags().GetInt64("max-added-bytes")
+		if err != nil {
+			DieErr(err)
+		}
+		client := getClient()
+		err = client.SetRepositoryCommitLimits(context.Background(), u.Repository, &models.RepositoryCommitLimits{
+			MaxChangedObjects: swag.Int64(maxChangedObjects),
+			MaxAddedBytes:     swag.Int64(maxAddedBytes),
+		})
+		if err != nil {
+			DieErr(err)
+		}
+	},
+}
+
+var metadataSchemaCmd = &cobra.Command{
+	Use:   "metadata-schema [sub-command]",
+	Short: "manage the schema of object user metadata, enforced on uploads and copies",
+}
+
+var getMetadataSchemaCmd = &cobra.Command{
+	Use:   "get <repository uri>",
+	Short: "show metadata schema",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		schema, err := client.GetMetadataSchema(context.Background(), u.Repository)
+		if err != nil {
+			DieErr(err)
+		}
+		out, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			DieFmt("Could not JSON-encode response: %v", err)
+		}
+		fmt.Printf("%s\n", string(out))
+	},
+}
+
+var setMetadataSchemaCmd = &cobra.Command{
+	Use:   "set <repository uri> </path/to/schema.json | ->",
+	Short: "set metadata schema",
+	Long:  "set metadata schema from file, or stdin if \"-\" specified",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(setPolicyCmdArgs),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+
+		var schema models.MetadataSchema
+		ParseDocument(&schema, args[1], "metadata schema")
+
+		client := getClient()
+		err := client.SetMetadataSchema(context.Background(), u.Repository, &schema)
+		if err != nil {
+			DieErr(err)
+		}
+	},
+}
+
+var repoUsageTemplate = `Storage bytes: {{.StorageBytes}}
+Objects: {{.Objects}}
+`
+
+var repoUsageCmd = &cobra.Command{
+	Use:   "usage <repository uri>",
+	Short: "show storage and objects used by repository",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		usage, err := client.GetRepositoryUsage(context.Background(), u.Repository)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(repoUsageTemplate, usage)
+	},
+}
+
+var repoActivityTemplate = `{{.ActivityTable | table -}}
+{{.Pagination | paginate }}
+`
+
+var repoActivityCmd = &cobra.Command{
+	Use:   "activity <repository uri>",
+	Short: "list repository activity, newest first",
+	Args: cmdutils.ValidationChain(
+		cobra.ExactArgs(1),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		amount, _ := cmd.Flags().GetInt("amount")
+		after, _ := cmd.Flags().GetString("after")
+		types, _ := cmd.Flags().GetStringArray("type")
+		actor, _ := cmd.Flags().GetString("actor")
+		ref, _ := cmd.Flags().GetString("ref")
+		u := uri.Must(uri.Parse(args[0]))
+		client := getClient()
+		events, pagination, err := client.ListRepositoryActivity(context.Background(), u.Repository, types, actor, ref, after, amount)
+		if err != nil {
+			DieErr(err)
+		}
+
+		rows := make([][]interface{}, len(events))
+		for i, event := range events {
+			ts := time.Unix(swag.Int64Value(event.CreationDate), 0).String()
+			rows[i] = []interface{}{ts, swag.StringValue(event.Actor), swag.StringValue(event.Type), event.Ref, event.Message}
+		}
+		ctx := struct {
+			ActivityTable *Table
+			Pagination    *Pagination
+		}{
+			ActivityTable: &Table{
+				Headers: []interface{}{"Date", "Actor", "Type", "Ref", "Message"},
+				Rows:    rows,
+			},
+		}
+		if pagination != nil && swag.BoolValue(pagination.HasMore) {
+			ctx.Pagination = &Pagination{
+				Amount:  amount,
+				HasNext: true,
+				After:   pagination.NextOffset,
+			}
+		}
+		Write(repoActivityTemplate, ctx)
+	},
+}
+
 //nolint:gochecknoinits
 func init() {
+	quotaCmd.AddCommand(getQuotaCmd)
+	quotaCmd.AddCommand(setQuotaCmd)
+
+	commitLimitsCmd.AddCommand(getCommitLimitsCmd)
+	commitLimitsCmd.AddCommand(setCommitLimitsCmd)
+
+	metadataSchemaCmd.AddCommand(getMetadataSchemaCmd)
+	metadataSchemaCmd.AddCommand(setMetadataSchemaCmd)
+
 	retentionCmd.AddCommand(setPolicyCmd)
 	retentionCmd.AddCommand(getPolicyCmd)
 
@@ -189,9 +553,38 @@ func init() {
 	repoCmd.AddCommand(repoCreateCmd)
 	repoCmd.AddCommand(repoDeleteCmd)
 	repoCmd.AddCommand(retentionCmd)
+	repoCmd.AddCommand(quotaCmd)
+	repoCmd.AddCommand(commitLimitsCmd)
+	repoCmd.AddCommand(metadataSchemaCmd)
+	repoCmd.AddCommand(repoUsageCmd)
+	repoCmd.AddCommand(repoSetDefaultBranchCmd)
+	repoCmd.AddCommand(repoSetReadOnlyCmd)
+	repoCmd.AddCommand(repoArchiveCmd)
+	repoCmd.AddCommand(repoUnarchiveCmd)
+	repoCmd.AddCommand(repoActivityCmd)
 
 	repoListCmd.Flags().Int("amount", -1, "how many results to return, or-1 for all results (used for pagination)")
 	repoListCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	repoListCmd.Flags().Bool("include-archived", false, "list archived repositories too")
+
+	repoActivityCmd.Flags().Int("amount", -1, "how many results to return, or-1 for all results (used for pagination)")
+	repoActivityCmd.Flags().String("after", "", "show results after this value (used for pagination)")
+	repoActivityCmd.Flags().StringArray("type", nil, "show only events of this type: commit, merge, create_branch, delete_branch, rename_branch, revert_branch, export or policy (can be repeated)")
+	repoActivityCmd.Flags().String("actor", "", "show only events performed by this user")
+	repoActivityCmd.Flags().String("ref", "", "show only events applied to this branch or commit")
+
+	repoSetReadOnlyCmd.Flags().Bool("disable", false, "allow writes to the repository again")
+	repoArchiveCmd.Flags().String("storage-class", "", "storage class to move the objects of the repository to, such as GLACIER on S3")
+	repoUnarchiveCmd.Flags().String("storage-class", "", "storage class to move the objects of the repository back to, such as STANDARD on S3")
 
 	repoCreateCmd.Flags().StringP("default-branch", "d", DefaultBranch, "the default branch of this repository")
+
+	setQuotaCmd.Flags().Int64("max-storage-bytes", 0, "maximal number of bytes stored by the repository (0 for no limit)")
+	setQuotaCmd.Flags().Int64("max-objects", 0, "maximal number of objects stored by the repository (0 for no limit)")
+	setQuotaCmd.Flags().Int64("max-requests-per-second", 0, "maximal number of requests per second to the repository served by each lakeFS server (0 for no limit)")
+	setQuotaCmd.Flags().Int64("max-export-bytes-per-second", 0, "maximal number of bytes per second copied by exports of the repository on each lakeFS server (0 for no limit)")
+	setQuotaCmd.Flags().String("organization", "", "organization the repository belongs to, sharing its quota (empty for none)")
+
+	setCommitLimitsCmd.Flags().Int64("max-changed-objects", 0, "maximal number of objects changed by a single commit (0 for no limit)")
+	setCommitLimitsCmd.Flags().Int64("max-added-bytes", 0, "maximal number of bytes added by a single commit (0 for no limit)")
 }
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-openapi/swag"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/api/gen/models"
+	"github.com/treeverse/lakefs/cmdutils"
+	"github.com/treeverse/lakefs/uri"
+)
+
+const searchObjectsTemplate = `{{ range $val := .Objects -}}
+{{ $val.Mtime|date|ljust 29 }}    {{ $val.SizeBytes|human_bytes|ljust 12 }}    {{ $val.Path|yellow }}
+{{ end -}}
+{{.Pagination | paginate }}
+`
+
+const searchCmdMinArgs = 2
+
+var searchCmd = &cobra.Command{
+	Use:   "search <repository uri|ref uri> <query>",
+	Short: "search object paths, or commit messages, that contain all words of query",
+	Long: `Search object paths on ref (or the repository default branch), or search commit messages
+(with --commits) of commits reachable from ref (or all commits of the repository).`,
+	Args: cmdutils.ValidationChain(
+		cobra.MinimumNArgs(searchCmdMinArgs),
+		cmdutils.FuncValidator(0, func(s string) error {
+			if err := uri.ValidateRepoURI(s); err == nil {
+				return nil
+			}
+			return uri.ValidateRefURI(s)
+		}),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		amount, err := cmd.Flags().GetInt("amount")
+		if err != nil {
+			DieErr(err)
+		}
+		after, err := cmd.Flags().GetString("after")
+		if err != nil {
+			DieErr(err)
+		}
+		commits, err := cmd.Flags().GetBool("commits")
+		if err != nil {
+			DieErr(err)
+		}
+		client := getClient()
+		refURI := uri.Must(uri.Parse(args[0]))
+		query := strings.Join(args[1:], " ")
+
+		var pagination *models.Pagination
+		if commits {
+			var results []*models.Commit
+			results, pagination, err = client.SearchCommits(context.Background(), refURI.Repository, refURI.Ref, query, after, amount)
+			if err != nil {
+				DieErr(err)
+			}
+			Write(commitsTemplate, struct {
+				Commits    []*models.Commit
+				Pagination *Pagination
+			}{
+				Commits:    results,
+				Pagination: searchPagination(pagination, amount),
+			})
+			return
+		}
+		var results []*models.ObjectStats
+		results, pagination, err = client.SearchObjects(context.Background(), refURI.Repository, refURI.Ref, query, after, amount)
+		if err != nil {
+			DieErr(err)
+		}
+		Write(searchObjectsTemplate, struct {
+			Objects    []*models.ObjectStats
+			Pagination *Pagination
+		}{
+			Objects:    results,
+			Pagination: searchPagination(pagination, amount),
+		})
+	},
+}
+
+func searchPagination(pagination *models.Pagination, amount int) *Pagination {
+	if pagination == nil || !swag.BoolValue(pagination.HasMore) {
+		return nil
+	}
+	return &Pagination{
+		Amount:  amount,
+		HasNext: true,
+		After:   pagination.NextOffset,
+	}
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().Bool("commits", false, "search commit messages instead of object paths")
+	searchCmd.Flags().Int("amount", -1, "how many results to return, or -1 for the default (used for pagination)")
+	searchCmd.Flags().String("after", "", "show results after this object path, or commits older than this commit reference (used for pagination)")
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/treeverse/lakefs/block/factory"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/catalog/mvcc"
 	"github.com/treeverse/lakefs/config"
 	"github.com/treeverse/lakefs/db"
@@ -28,7 +29,7 @@ var diagnoseCmd = &cobra.Command{
 		cataloger := mvcc.NewCataloger(dbPool, mvcc.WithParams(conf.GetMvccCatalogerCatalogParams()))
 
 		numFailures := 0
-		repos, _, err := cataloger.ListRepositories(ctx, -1, "")
+		repos, _, err := cataloger.ListRepositories(ctx, -1, "", catalog.ListRepositoriesParams{IncludeArchived: true})
 		if err != nil {
 			// Cannot advance last so fail everything
 			logger.WithField("error", err).Fatal("Failed to list repositories")
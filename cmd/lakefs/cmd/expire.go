@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/catalog/mvcc"
 
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -31,7 +32,8 @@ var expireCmd = &cobra.Command{
 
 		awsRetentionConfig := config.NewConfig().GetAwsS3RetentionConfig()
 
-		repos, _, err := cataloger.ListRepositories(ctx, -1, "")
+		// archived repositories keep their objects until restored
+		repos, _, err := cataloger.ListRepositories(ctx, -1, "", catalog.ListRepositoriesParams{})
 		if err != nil {
 			logger.WithError(err).Fatal("cannot list repositories")
 		}
@@ -133,7 +133,8 @@ var importCmd = &cobra.Command{
 		if withMerge {
 			fmt.Printf("Merging import changes into lakefs://%s@%s/\n", repoName, repo.DefaultBranch)
 			msg := fmt.Sprintf(onboard.CommitMsgTemplate, stats.CommitRef)
-			commitLog, err := cataloger.Merge(ctx, repoName, catalog.DefaultImportBranchName, repo.DefaultBranch, CommitterName, msg, nil)
+			// like its commit, the merge of an import is not limited like other merges
+			commitLog, err := cataloger.Merge(catalog.WithCommitLimitsExempt(ctx), repoName, catalog.DefaultImportBranchName, repo.DefaultBranch, CommitterName, msg, nil, catalog.MergeParams{})
 			if err != nil {
 				fmt.Printf("Merge failed: %s\n", err)
 				os.Exit(1)
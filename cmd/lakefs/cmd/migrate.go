@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/db/params"
 
 	"github.com/spf13/cobra"
 )
@@ -77,6 +79,51 @@ var gotoCmd = &cobra.Command{
 	},
 }
 
+var copyCmd = &cobra.Command{
+	Use:   "copy",
+	Short: "Copy metadata to another database",
+	Long: `Copy metadata to another database, migrated to the latest schema version.
+Only tables (or branches) that changed since the previous copy are copied, so
+it can run while lakeFS keeps serving and run again to copy the delta.
+To cut over, run lakeFS in read-only mode, run a final copy and switch lakeFS
+to the new database.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		to, _ := cmd.Flags().GetString("to")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		ctx := context.Background()
+
+		sourceParams := cfg.GetDatabaseParams()
+		targetParams := params.Database{ConnectionString: to}
+		if !dryRun {
+			if err := db.MigrateUp(targetParams); err != nil {
+				fmt.Printf("Failed to migrate target DB: %s\n", err)
+				os.Exit(1)
+			}
+		}
+		source, err := db.ConnectDBPool(sourceParams)
+		if err != nil {
+			fmt.Printf("Failed to connect source DB: %s\n", err)
+			os.Exit(1)
+		}
+		defer source.Close()
+		target, err := db.ConnectDBPool(targetParams)
+		if err != nil {
+			fmt.Printf("Failed to connect target DB: %s\n", err)
+			os.Exit(1)
+		}
+		defer target.Close()
+
+		results, err := db.CopyDatabase(ctx, source, target, db.CopyOptions{DryRun: dryRun})
+		for _, res := range results {
+			fmt.Printf("%s: %d of %d partitions changed\n", res.Table, res.Changed, res.Partitions)
+		}
+		if err != nil {
+			fmt.Printf("Failed to copy DB: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
 //nolint:gochecknoinits
 func init() {
 	rootCmd.AddCommand(migrateCmd)
@@ -84,7 +131,11 @@ func init() {
 	migrateCmd.AddCommand(upCmd)
 	migrateCmd.AddCommand(downCmd)
 	migrateCmd.AddCommand(gotoCmd)
+	migrateCmd.AddCommand(copyCmd)
 	_ = gotoCmd.Flags().Uint("version", 0, "version number")
 	_ = gotoCmd.MarkFlagRequired("version")
 	_ = gotoCmd.Flags().Bool("force", false, "force migrate")
+	_ = copyCmd.Flags().String("to", "", "target database connection string")
+	_ = copyCmd.MarkFlagRequired("to")
+	_ = copyCmd.Flags().Bool("dry-run", false, "only report tables that changed, without copying")
 }
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/text"
+	"github.com/spf13/cobra"
+	"github.com/treeverse/lakefs/block/factory"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/catalog/mvcc"
+	"github.com/treeverse/lakefs/cmdutils"
+	"github.com/treeverse/lakefs/config"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/upload"
+	"github.com/treeverse/lakefs/uri"
+)
+
+const (
+	MigrateNamespaceCmdMinArgs = 1
+	MigrateNamespaceCmdMaxArgs = 2
+	BatchSizeFlagName          = "batch-size"
+	StatusFlagName             = "status"
+)
+
+var migrateNamespaceCmd = &cobra.Command{
+	Use:   "migrate-namespace <repository uri> [<storage namespace>]",
+	Short: "Copy the objects of a repository to a new storage namespace",
+	Long: `Copy all objects referenced by a repository to a new storage namespace, verify their checksums
+and update their physical addresses in batches, then move the repository to the new storage
+namespace.  An interrupted migration resumes from its last batch when run again.
+With --status only the repository uri is required.`,
+	Args: cmdutils.ValidationChain(
+		cobra.RangeArgs(MigrateNamespaceCmdMinArgs, MigrateNamespaceCmdMaxArgs),
+		cmdutils.FuncValidator(0, uri.ValidateRepoURI),
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		batchSize, _ := flags.GetInt(BatchSizeFlagName)
+		statusOnly, _ := flags.GetBool(StatusFlagName)
+
+		if !statusOnly && len(args) < MigrateNamespaceCmdMaxArgs {
+			fmt.Println("Missing storage namespace to migrate to")
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		conf := config.NewConfig()
+		err := db.ValidateSchemaUpToDate(conf.GetDatabaseParams())
+		if errors.Is(err, db.ErrSchemaNotCompatible) {
+			fmt.Println("Migration version mismatch, for more information see https://docs.lakefs.io/deploying/upgrade.html")
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		dbPool := db.BuildDatabaseConnection(cfg.GetDatabaseParams())
+		defer dbPool.Close()
+
+		cataloger := mvcc.NewCataloger(dbPool, mvcc.WithParams(conf.GetMvccCatalogerCatalogParams()))
+		defer func() { _ = cataloger.Close() }()
+
+		repoName := uri.Must(uri.Parse(args[0])).Repository
+		if statusOnly {
+			migration, err := cataloger.GetStorageNamespaceMigration(ctx, repoName)
+			if errors.Is(err, catalog.ErrMigrationNotFound) {
+				fmt.Printf("No storage namespace migration in progress for %s\n", repoName)
+				return
+			}
+			if err != nil {
+				fmt.Printf("Failed to get migration: %s\n", err)
+				os.Exit(1)
+			}
+			printStorageNamespaceMigration(migration)
+			return
+		}
+
+		blockStore, err := factory.BuildBlockAdapter(cfg)
+		if err != nil {
+			fmt.Printf("Failed to create block adapter: %s\n", err)
+			os.Exit(1)
+		}
+		migration, err := cataloger.MigrateStorageNamespace(ctx, repoName, args[1], catalog.MigrateStorageNamespaceParams{
+			CopyObject: func(sourceNamespace, sourceAddress, destinationNamespace string) (string, error) {
+				return upload.MigrateBlob(blockStore, sourceNamespace, sourceAddress, destinationNamespace)
+			},
+			ChecksumObject: func(namespace, address string, size int64) (string, error) {
+				return upload.ChecksumBlob(blockStore, namespace, address, size)
+			},
+			BatchSize: batchSize,
+			Progress: func(migration *catalog.StorageNamespaceMigration) {
+				fmt.Printf("Migrated %d objects\n", migration.MigratedObjects)
+			},
+		})
+		if err != nil {
+			fmt.Printf("Migration failed: %s\n", err)
+			fmt.Println("Run the same command again to resume the migration.")
+			os.Exit(1)
+		}
+		printStorageNamespaceMigration(migration)
+		fmt.Printf("Repository %s moved to %s\n", repoName, migration.StorageNamespace)
+	},
+}
+
+func printStorageNamespaceMigration(migration *catalog.StorageNamespaceMigration) {
+	fmt.Println(text.FgYellow.Sprint("Source namespace:"), migration.SourceNamespace)
+	fmt.Println(text.FgYellow.Sprint("Storage namespace:"), migration.StorageNamespace)
+	fmt.Println(text.FgYellow.Sprint("Migrated objects:"), migration.MigratedObjects)
+	fmt.Println(text.FgYellow.Sprint("Started:"), migration.StartedDate)
+	fmt.Println(text.FgYellow.Sprint("Updated:"), migration.UpdatedDate)
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.AddCommand(migrateNamespaceCmd)
+	migrateNamespaceCmd.Flags().Int(BatchSizeFlagName, mvcc.StorageNamespaceMigrationBatchSize, "Number of objects copied before their physical addresses are updated")
+	migrateNamespaceCmd.Flags().Bool(StatusFlagName, false, "Only print the progress of the migration in progress")
+}
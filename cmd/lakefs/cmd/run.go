@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,21 +18,31 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/treeverse/lakefs/activity"
 	"github.com/treeverse/lakefs/api"
 	"github.com/treeverse/lakefs/auth"
 	"github.com/treeverse/lakefs/auth/crypt"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/azure"
 	"github.com/treeverse/lakefs/block/factory"
+	"github.com/treeverse/lakefs/block/gs"
 	"github.com/treeverse/lakefs/config"
 	"github.com/treeverse/lakefs/db"
 	"github.com/treeverse/lakefs/dedup"
 	"github.com/treeverse/lakefs/export"
 	"github.com/treeverse/lakefs/gateway"
 	"github.com/treeverse/lakefs/gateway/simulator"
+	"github.com/treeverse/lakefs/hooks"
+	"github.com/treeverse/lakefs/hooks/plugin"
 	"github.com/treeverse/lakefs/httputil"
+	"github.com/treeverse/lakefs/lifecycle"
 	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/notifications"
 	"github.com/treeverse/lakefs/parade"
 	"github.com/treeverse/lakefs/retention"
 	"github.com/treeverse/lakefs/stats"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -45,6 +56,26 @@ type Shutter interface {
 	Shutdown(context.Context) error
 }
 
+// grpcShutter shuts down a gRPC server, stopping it when ctx is done before its calls end
+type grpcShutter struct {
+	server *grpc.Server
+}
+
+func (s grpcShutter) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
 // runCmd represents the run command
 var runCmd = &cobra.Command{
 	Use:   "run",
@@ -56,6 +87,7 @@ var runCmd = &cobra.Command{
 
 		// validate service names and turn on the right flags
 		dbParams := cfg.GetDatabaseParams()
+		readOnly := cfg.GetReadOnly()
 
 		if err := db.ValidateSchemaUpToDate(dbParams); errors.Is(err, db.ErrSchemaNotCompatible) {
 			logger.WithError(err).Fatal("Migration version mismatch, for more information see https://docs.lakefs.io/deploying/upgrade.html")
@@ -68,6 +100,7 @@ var runCmd = &cobra.Command{
 		defer dbPool.Close()
 		registerPrometheusCollector(dbPool)
 		retention := retention.NewService(dbPool)
+		activityService := activity.NewDBService(dbPool)
 		migrator := db.NewDatabaseMigrator(dbParams)
 
 		// init catalog
@@ -93,18 +126,69 @@ var runCmd = &cobra.Command{
 
 		dedupCleaner := dedup.NewCleaner(blockStore, cataloger.DedupReportChannel())
 
+		// notifications
+		subscriptionService := notifications.NewDBSubscriptionService(dbPool, authService)
+		var notificationChannels []notifications.Channel
+		notificationSubscribers := []notifications.Subscribers{subscriptionService}
+		if emailParams, ok := cfg.GetNotificationsEmailParams(); ok {
+			emailChannel, err := notifications.NewEmailChannel(emailParams)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to create email notifications channel")
+			}
+			notificationChannels = append(notificationChannels, emailChannel)
+			emailSubscribers, err := notifications.NewEmailStaticSubscribers(emailParams)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to create email notifications recipients")
+			}
+			notificationSubscribers = append(notificationSubscribers, emailSubscribers)
+		}
+		notifier := notifications.NewNotifier(logger.WithField("service", "notifications"), notificationChannels, notificationSubscribers...)
+
+		// hooks
+		hooksPluginsParams, err := cfg.GetHooksPluginsParams()
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to read hook plugins configuration")
+		}
+		hooksPlugins, err := plugin.NewManager(hooksPluginsParams, logger.WithField("service", "hooks"))
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create hook plugins")
+		}
+		hooksContentScanners, err := cfg.GetHooksContentScannersParams()
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to read hook content scanners configuration")
+		}
+
 		// parade
 		paradeDB := parade.NewParadeDB(dbPool.Pool())
-		// export handler
-		exportHandler := export.NewHandler(blockStore, cataloger, paradeDB)
-		exportActionManager := parade.NewActionManager(exportHandler, paradeDB, nil)
+		exportDestinations := buildExportDestinations(cfg, blockStore)
+		// export handler - exports update the catalog, skip them when serving reads only
+		var exportActionManager *parade.ActionManager
+		if readOnly {
+			logger.Info("running in read-only mode")
+		} else {
+			exportHandler := export.NewHandler(blockStore, exportDestinations, cataloger, paradeDB, notifier)
+			exportActionManager = parade.NewActionManager(exportHandler, paradeDB, &parade.ManagerProperties{
+				Workers: cfg.GetExportWorkers(),
+			})
+		}
 		defer func() {
 			// order is important - close cataloger channel before dedup
 			_ = cataloger.Close()
 			_ = dedupCleaner.Close()
-			exportActionManager.Close()
+			if exportActionManager != nil {
+				exportActionManager.Close()
+			}
+			notifier.Close()
+			hooksPlugins.Close()
 		}()
 
+		hooksService := hooks.NewService(cataloger, blockStore, hooks.NewDBRunStore(dbPool), hooksPlugins, hooksContentScanners, authService)
+
+		gatewayParams := api.S3GatewayParams{
+			Endpoint: cfg.GetS3GatewayEndpoint(),
+			Region:   cfg.GetS3GatewayRegion(),
+		}
+
 		// start API server
 		done := make(chan bool, 1)
 		quit := make(chan os.Signal, 1)
@@ -119,11 +203,19 @@ var runCmd = &cobra.Command{
 			retention,
 			migrator,
 			paradeDB,
+			exportDestinations,
 			dedupCleaner,
+			activityService,
+			notifier,
+			subscriptionService,
+			hooksService,
+			gatewayParams,
+			readOnly,
 			logger.WithField("service", "api_gateway"),
 		)
 
 		// init gateway server
+		limits := cfg.GetLimitsParams()
 		s3gatewayHandler := gateway.NewHandler(
 			cfg.GetS3GatewayRegion(),
 			cataloger,
@@ -132,10 +224,18 @@ var runCmd = &cobra.Command{
 			cfg.GetS3GatewayDomainName(),
 			bufferedCollector,
 			dedupCleaner,
+			limits,
+			readOnly,
 		)
+		apiHandler = api.RequestSizeLimitMiddleware(apiHandler, limits)
 
 		ctx, cancelFn := context.WithCancel(context.Background())
 		go bufferedCollector.Run(ctx)
+		if !readOnly {
+			go export.NewScheduler(paradeDB, cataloger, logger.WithField("service", "export_scheduler")).Run(ctx)
+			go lifecycle.NewRunner(cataloger, cfg.GetLifecycleInterval(), cfg.GetLifecycleDryRun(),
+				logger.WithField("service", "lifecycle")).Run(ctx)
+		}
 
 		bufferedCollector.CollectEvent("global", "run")
 
@@ -157,7 +257,47 @@ var runCmd = &cobra.Command{
 			}
 		}()
 
-		go gracefulShutdown(quit, done, server)
+		servers := []Shutter{server}
+		if grpcAddress := cfg.GetGRPCListenAddress(); grpcAddress != "" {
+			var opts []grpc.ServerOption
+			if readOnly {
+				opts = append(opts, grpc.UnaryInterceptor(api.ReadOnlyUnaryInterceptor))
+			}
+			grpcServer := api.NewGRPCServer(
+				cataloger,
+				blockStore,
+				authService,
+				authMetadataManager,
+				bufferedCollector,
+				retention,
+				migrator,
+				paradeDB,
+				exportDestinations,
+				dedupCleaner,
+				activityService,
+				notifier,
+				subscriptionService,
+				hooksService,
+				gatewayParams,
+				logger.WithField("service", "grpc_api"),
+				opts...,
+			)
+			listener, err := net.Listen("tcp", grpcAddress)
+			if err != nil {
+				fmt.Printf("gRPC server failed to listen on %s: %v\n", grpcAddress, err)
+				os.Exit(1)
+			}
+			logging.Default().WithField("listen_address", grpcAddress).Info("starting gRPC server")
+			go func() {
+				if err := grpcServer.Serve(listener); err != nil {
+					fmt.Printf("gRPC server failed on %s: %v\n", grpcAddress, err)
+					os.Exit(1)
+				}
+			}()
+			servers = append(servers, grpcShutter{grpcServer})
+		}
+
+		go gracefulShutdown(quit, done, servers...)
 
 		<-done
 		cancelFn()
@@ -190,6 +330,23 @@ func printWelcome(w io.Writer) {
 	_, _ = fmt.Fprint(w, runBanner)
 }
 
+// buildExportDestinations returns the adapters exporting to storage other than the blockstore
+func buildExportDestinations(cfg *config.Config, blockStore block.Adapter) *export.Destinations {
+	destinations := export.NewDestinations()
+	if blockStore.BlockstoreType() != gs.BlockstoreType {
+		destinations.Register(export.SchemeGS, func() (block.Adapter, error) {
+			return factory.BuildGSAdapter(cfg.GetExportGSParams())
+		})
+	}
+	for _, scheme := range export.SchemesAzure {
+		destinations.Register(scheme, func() (block.Adapter, error) {
+			p := cfg.GetExportAzureParams()
+			return azure.NewAdapter(p.StorageAccount, p.StorageAccessKey)
+		})
+	}
+	return destinations
+}
+
 func registerPrometheusCollector(db sqlstats.StatsGetter) {
 	collector := sqlstats.NewStatsCollector("lakefs", db)
 	err := prometheus.Register(collector)
@@ -223,4 +380,6 @@ func gracefulShutdown(quit <-chan os.Signal, done chan<- bool, servers ...Shutte
 func init() {
 	rootCmd.AddCommand(runCmd)
 	runCmd.Flags().StringArrayP("service", "s", []string{serviceS3Gateway, serviceAPIServer}, "lakeFS services to run")
+	runCmd.Flags().Bool("read-only", false, "serve only read operations, e.g. when using a read-only database replica")
+	_ = viper.BindPFlag("read_only", runCmd.Flags().Lookup("read-only"))
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"strings"
 	"time"
@@ -19,6 +20,9 @@ import (
 	blockparams "github.com/treeverse/lakefs/block/params"
 	catalogparams "github.com/treeverse/lakefs/catalog/mvcc/params"
 	dbparams "github.com/treeverse/lakefs/db/params"
+	hooksparams "github.com/treeverse/lakefs/hooks/params"
+	notificationsparams "github.com/treeverse/lakefs/notifications/params"
+	uploadparams "github.com/treeverse/lakefs/upload/params"
 )
 
 const (
@@ -44,13 +48,28 @@ const (
 	DefaultStatsAddr          = "https://stats.treeverse.io"
 	DefaultStatsFlushInterval = time.Second * 30
 
+	DefaultNotificationsEmailSMTPPort = 587
+	DefaultNotificationsEmailTimeout  = 30 * time.Second
+
+	DefaultExportWorkers = 5
+
+	DefaultLifecycleInterval = time.Hour
+
+	// upload limits are unbounded by default, set them to the limits of S3 to enforce those
+	DefaultLimitsMaxObjectSize      = 0
+	DefaultLimitsMaxParts           = 0
+	DefaultLimitsMinPartSize        = 0
+	DefaultLimitsMaxPartSize        = 0
+	DefaultLimitsMaxRequestBodySize = 0
+
 	MetaStoreType          = "metastore.type"
 	MetaStoreHiveURI       = "metastore.hive.uri"
 	MetastoreGlueCatalogID = "metastore.glue.catalog_id"
 )
 
 var (
-	ErrMissingSecretKey = errors.New("auth.encrypt.secret_key cannot be empty")
+	ErrMissingSecretKey      = errors.New("auth.encrypt.secret_key cannot be empty")
+	ErrInvalidContentScanner = errors.New("invalid content scanner configuration")
 )
 
 type LogrusAWSAdapter struct {
@@ -96,6 +115,20 @@ func setDefaults() {
 	viper.SetDefault("stats.enabled", DefaultStatsEnabled)
 	viper.SetDefault("stats.address", DefaultStatsAddr)
 	viper.SetDefault("stats.flush_interval", DefaultStatsFlushInterval)
+
+	viper.SetDefault("limits.max_object_size", DefaultLimitsMaxObjectSize)
+	viper.SetDefault("limits.max_parts", DefaultLimitsMaxParts)
+	viper.SetDefault("limits.min_part_size", DefaultLimitsMinPartSize)
+	viper.SetDefault("limits.max_part_size", DefaultLimitsMaxPartSize)
+	viper.SetDefault("limits.max_request_body_size", DefaultLimitsMaxRequestBodySize)
+
+	viper.SetDefault("export.workers", DefaultExportWorkers)
+
+	viper.SetDefault("lifecycle.interval", DefaultLifecycleInterval)
+
+	viper.SetDefault("notifications.email.smtp_port", DefaultNotificationsEmailSMTPPort)
+	viper.SetDefault("notifications.email.timeout", DefaultNotificationsEmailTimeout)
+	viper.SetDefault("notifications.email.events", []string{"export_failed", "hook_failed", "protected_branch_merge"})
 }
 
 func (c *Config) GetDatabaseParams() dbparams.Database {
@@ -125,6 +158,22 @@ func (c *Config) GetMvccCatalogerCatalogParams() catalogparams.Catalog {
 			Expiry:  viper.GetDuration("cataloger.cache.expiry"),
 			Jitter:  viper.GetDuration("cataloger.cache.jitter"),
 		},
+		ListingCache: catalogparams.ListingCache{
+			Enabled: viper.GetBool("cataloger.listing_cache.enabled"),
+			Size:    viper.GetInt("cataloger.listing_cache.size"),
+			Expiry:  viper.GetDuration("cataloger.listing_cache.expiry"),
+			Redis: catalogparams.ListingCacheRedis{
+				Address:  viper.GetString("cataloger.listing_cache.redis.address"),
+				Password: viper.GetString("cataloger.listing_cache.redis.password"),
+				DB:       viper.GetInt("cataloger.listing_cache.redis.db"),
+			},
+		},
+		CommitJob: catalogparams.CommitJob{
+			ChunkSize: viper.GetInt("cataloger.commit_job.chunk_size"),
+		},
+		Committer: catalogparams.Committer{
+			RecordAccessKeyID: viper.GetBool("cataloger.committer.record_access_key_id"),
+		},
 	}
 }
 
@@ -249,6 +298,40 @@ func (c *Config) GetBlockAdapterGSParams() (blockparams.GS, error) {
 	}, nil
 }
 
+// GetExportWorkers returns the number of export tasks this lakeFS performs concurrently, across
+// all exported branches
+func (c *Config) GetExportWorkers() int {
+	return viper.GetInt("export.workers")
+}
+
+// GetLifecycleInterval returns the interval between expirations of entries by the lifecycle
+// rules of repositories
+func (c *Config) GetLifecycleInterval() time.Duration {
+	return viper.GetDuration("lifecycle.interval")
+}
+
+// GetLifecycleDryRun returns whether lifecycle expirations only report the expired entries
+func (c *Config) GetLifecycleDryRun() bool {
+	return viper.GetBool("lifecycle.dry_run")
+}
+
+// GetExportGSParams returns the credentials of exports to Google Cloud Storage, used when the
+// blockstore is not on Google Cloud Storage
+func (c *Config) GetExportGSParams() blockparams.GS {
+	return blockparams.GS{
+		CredentialsFile: viper.GetString("export.gs.credentials_file"),
+		CredentialsJSON: viper.GetString("export.gs.credentials_json"),
+	}
+}
+
+// GetExportAzureParams returns the storage account and key of exports to Azure Blob Storage
+func (c *Config) GetExportAzureParams() blockparams.Azure {
+	return blockparams.Azure{
+		StorageAccount:   viper.GetString("export.azure.storage_account"),
+		StorageAccessKey: viper.GetString("export.azure.storage_access_key"),
+	}
+}
+
 func (c *Config) GetAuthCacheConfig() authparams.ServiceCache {
 	return authparams.ServiceCache{
 		Enabled:        viper.GetBool("auth.cache.enabled"),
@@ -274,10 +357,35 @@ func (c *Config) GetS3GatewayDomainName() string {
 	return viper.GetString("gateways.s3.domain_name")
 }
 
+// GetS3GatewayEndpoint returns the URL through which clients reach the S3 gateway, used as
+// the base of pre-signed URLs.  It defaults to plain HTTP to the gateway domain name, on the
+// port of the listen address.
+func (c *Config) GetS3GatewayEndpoint() string {
+	if endpoint := viper.GetString("gateways.s3.endpoint"); endpoint != "" {
+		return endpoint
+	}
+	host := c.GetS3GatewayDomainName()
+	if _, port, err := net.SplitHostPort(c.GetListenAddress()); err == nil && port != "" {
+		host = net.JoinHostPort(host, port)
+	}
+	return (&url.URL{Scheme: "http", Host: host}).String()
+}
+
 func (c *Config) GetListenAddress() string {
 	return viper.GetString("listen_address")
 }
 
+// GetGRPCListenAddress returns the address of the gRPC API, it isn't served when empty
+func (c *Config) GetGRPCListenAddress() string {
+	return viper.GetString("grpc.listen_address")
+}
+
+// GetReadOnly returns true when lakeFS serves only read operations, e.g. when running against a
+// read-only replica of the database.
+func (c *Config) GetReadOnly() bool {
+	return viper.GetBool("read_only")
+}
+
 func (c *Config) GetStatsEnabled() bool {
 	return viper.GetBool("stats.enabled")
 }
@@ -290,6 +398,63 @@ func (c *Config) GetStatsFlushInterval() time.Duration {
 	return viper.GetDuration("stats.flush_interval")
 }
 
+// GetNotificationsEmailParams returns the email notifications channel configuration, and
+// whether email notifications are enabled by configuring an SMTP host.
+func (c *Config) GetNotificationsEmailParams() (notificationsparams.Email, bool) {
+	p := notificationsparams.Email{
+		SMTPHost:   viper.GetString("notifications.email.smtp_host"),
+		SMTPPort:   viper.GetInt("notifications.email.smtp_port"),
+		Timeout:    viper.GetDuration("notifications.email.timeout"),
+		Username:   viper.GetString("notifications.email.username"),
+		Password:   viper.GetString("notifications.email.password"),
+		Sender:     viper.GetString("notifications.email.sender"),
+		Recipients: viper.GetStringSlice("notifications.email.recipients"),
+		Events:     viper.GetStringSlice("notifications.email.events"),
+	}
+	return p, p.SMTPHost != ""
+}
+
+// GetLimitsParams returns the limits of uploads and API requests
+func (c *Config) GetLimitsParams() uploadparams.Limits {
+	return uploadparams.Limits{
+		MaxObjectSize:      viper.GetInt64("limits.max_object_size"),
+		MaxParts:           viper.GetInt64("limits.max_parts"),
+		MinPartSize:        viper.GetInt64("limits.min_part_size"),
+		MaxPartSize:        viper.GetInt64("limits.max_part_size"),
+		MaxRequestBodySize: viper.GetInt64("limits.max_request_body_size"),
+	}
+}
+
+// GetHooksPluginsParams returns the hook plugins lakeFS runs hooks on
+func (c *Config) GetHooksPluginsParams() ([]hooksparams.Plugin, error) {
+	var plugins []hooksparams.Plugin
+	if err := viper.UnmarshalKey("hooks.plugins", &plugins); err != nil {
+		return nil, err
+	}
+	return plugins, nil
+}
+
+// GetHooksContentScannersParams returns the scanning endpoints content scan hooks send objects
+// to.  Endpoints are configured only here, so actions cannot send objects to other hosts.
+func (c *Config) GetHooksContentScannersParams() ([]hooksparams.ContentScanner, error) {
+	var scanners []hooksparams.ContentScanner
+	if err := viper.UnmarshalKey("hooks.content_scanners", &scanners); err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{}, len(scanners))
+	for _, scanner := range scanners {
+		u, err := url.Parse(scanner.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%w: %s endpoint should be an http or https URL", ErrInvalidContentScanner, scanner.Name)
+		}
+		if _, ok := names[scanner.Name]; ok || scanner.Name == "" {
+			return nil, fmt.Errorf("%w: names should be unique and not empty", ErrInvalidContentScanner)
+		}
+		names[scanner.Name] = struct{}{}
+	}
+	return scanners, nil
+}
+
 func GetMetastoreAwsConfig() *aws.Config {
 	cfg := &aws.Config{
 		Region: aws.String(viper.GetString("metastore.glue.region")),
@@ -13,6 +13,7 @@ import (
 	"github.com/treeverse/lakefs/block/local"
 	s3a "github.com/treeverse/lakefs/block/s3"
 	"github.com/treeverse/lakefs/config"
+	"github.com/treeverse/lakefs/logging"
 	"github.com/treeverse/lakefs/testutil"
 )
 
@@ -137,3 +138,10 @@ func TestConfig_JSONLogger(t *testing.T) {
 	}
 
 }
+
+func TestConfig_UnknownLoggingLevel(t *testing.T) {
+	_ = newConfigFromFile("testdata/unknown_logging_level_config.yaml")
+	if level := logging.Level(); level != log.InfoLevel.String() {
+		t.Fatalf("expected default logging level %s, got %s", log.InfoLevel, level)
+	}
+}
@@ -2,7 +2,6 @@ package config
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
 	"os/signal"
 	"runtime"
@@ -11,6 +10,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"github.com/treeverse/lakefs/logging"
 )
 
 const (
@@ -39,64 +39,68 @@ func setupLogger() {
 		return
 	}
 
-	// set output format
-	if strings.EqualFold(viper.GetString("logging.format"), "text") {
-		log.SetFormatter(&log.TextFormatter{
-			FullTimestamp:          true,
-			DisableLevelTruncation: true,
-			PadLevelText:           true,
-			QuoteEmptyFields:       true,
-			CallerPrettyfier:       logPrettyfier,
-		})
-	} else if strings.EqualFold(viper.GetString("logging.format"), "json") {
-		log.SetFormatter(&log.JSONFormatter{
-			CallerPrettyfier: logPrettyfier,
-			PrettyPrint:      false,
-		})
+	// set level
+	level := strings.ToLower(viper.GetString("logging.level"))
+	if level == "null" || level == "none" {
+		logging.SetSinks()
+		return
+	}
+	// an unknown level keeps the default level, reported once the sinks are set
+	levelErr := logging.SetLevel(level)
+	if levelErr != nil {
+		_ = logging.SetLevel(DefaultLoggingLevel)
 	}
 
-	// set output
-	if strings.EqualFold(viper.GetString("logging.output"), "-") {
-		log.SetOutput(os.Stdout)
-	} else {
-		filename := viper.GetString("logging.output")
-		handle, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY, 0755)
+	// each sink is configured by logging.sinks, without sinks logs are written to
+	// logging.output in logging.format
+	var sinksParams []logging.SinkParams
+	if err := viper.UnmarshalKey("logging.sinks", &sinksParams); err != nil {
+		panic(fmt.Errorf("could not parse logging sinks: %w", err))
+	}
+	if len(sinksParams) == 0 {
+		params := logging.SinkParams{
+			Type:   logging.SinkStdout,
+			Format: viper.GetString("logging.format"),
+		}
+		if output := viper.GetString("logging.output"); output != "-" {
+			params.Type = logging.SinkFile
+			params.Path = output
+		}
+		sinksParams = append(sinksParams, params)
+	}
+	sinks := make([]*logging.Sink, 0, len(sinksParams))
+	var hasFiles bool
+	for _, params := range sinksParams {
+		hasFiles = hasFiles || strings.EqualFold(params.Type, logging.SinkFile)
+		params.CallerPrettyfier = logPrettyfier
+		sink, err := logging.NewSink(params)
 		if err != nil {
-			panic(fmt.Errorf("could not open log file: %w", err))
+			panic(fmt.Errorf("could not open log sink %s: %w", params.Type, err))
 		}
-		log.SetOutput(handle)
-		// setup signal handler to reopen logfile on SIGHUP
-		sigChannel := make(chan os.Signal, 1)
-		signal.Notify(sigChannel, syscall.SIGHUP)
-		go func() {
-			for {
-				<-sigChannel
-				log.Info("SIGHUP received, rotating log file")
-				log.SetOutput(ioutil.Discard)
-				_ = handle.Close()
-				handle, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY, 0755)
-				if err != nil {
-					panic(fmt.Errorf("could not open log file: %w", err))
-				}
-				log.SetOutput(handle)
-				log.Info("log file was rotated successfully")
-			}
-		}()
+		sinks = append(sinks, sink)
 	}
 
-	// set level
-	switch strings.ToLower(viper.GetString("logging.level")) {
-	case "trace":
-		log.SetLevel(log.TraceLevel)
-	case "debug":
-		log.SetLevel(log.DebugLevel)
-	case "info":
-		log.SetLevel(log.InfoLevel)
-	case "warn", "warning":
-		log.SetLevel(log.WarnLevel)
-	case "error":
-		log.SetLevel(log.ErrorLevel)
-	case "null", "none":
-		log.SetOutput(ioutil.Discard)
+	logging.SetSinks(sinks...)
+	if levelErr != nil {
+		logging.Default().WithError(levelErr).Warnf("unknown logging.level, logging at level %s", DefaultLoggingLevel)
+	}
+	if !hasFiles {
+		return
 	}
+
+	// setup signal handler to reopen log files on SIGHUP
+	sigChannel := make(chan os.Signal, 1)
+	signal.Notify(sigChannel, syscall.SIGHUP)
+	go func() {
+		for {
+			<-sigChannel
+			log.Info("SIGHUP received, rotating log files")
+			for _, sink := range sinks {
+				if err := sink.Reopen(); err != nil {
+					panic(err)
+				}
+			}
+			log.Info("log files were rotated successfully")
+		}
+	}()
 }
@@ -0,0 +1,192 @@
+// Package contentmerge resolves merge conflicts of objects whose formats can be merged, by
+// merging the content of both versions into a new object.
+package contentmerge
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/upload"
+)
+
+// maxLineSize is the longest line the line based resolvers read
+const maxLineSize = 16 * 1024 * 1024
+
+// ErrNotMergeable is returned by a Resolver that cannot merge the two versions, leaving the
+// conflict to the merge strategy
+var ErrNotMergeable = errors.New("content not mergeable")
+
+// Resolver writes to w the content merging the source and destination versions of an object
+type Resolver interface {
+	Resolve(w io.Writer, source, destination io.Reader) error
+}
+
+// ResolverFunc is a function Resolver
+type ResolverFunc func(w io.Writer, source, destination io.Reader) error
+
+func (f ResolverFunc) Resolve(w io.Writer, source, destination io.Reader) error {
+	return f(w, source, destination)
+}
+
+var (
+	resolversLock sync.RWMutex
+	resolvers     = map[string]Resolver{
+		"*.jsonl":  ResolverFunc(LinesUnion),
+		"*.ndjson": ResolverFunc(LinesUnion),
+		"*.csv":    ResolverFunc(CSVUnion),
+	}
+)
+
+// Register sets resolver for the objects whose base name matches pattern, as matched by
+// path.Match.  A nil resolver removes the pattern.
+func Register(pattern string, resolver Resolver) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("pattern %s: %w", pattern, err)
+	}
+	resolversLock.Lock()
+	defer resolversLock.Unlock()
+	if resolver == nil {
+		delete(resolvers, pattern)
+	} else {
+		resolvers[pattern] = resolver
+	}
+	return nil
+}
+
+// Lookup returns the resolver of the object at p, nil if there is none.  When several patterns
+// match, the first in lexical order is used.
+func Lookup(p string) Resolver {
+	resolversLock.RLock()
+	defer resolversLock.RUnlock()
+	patterns := make([]string, 0, len(resolvers))
+	for pattern := range resolvers {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	base := path.Base(p)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, base); ok {
+			return resolvers[pattern]
+		}
+	}
+	return nil
+}
+
+// NewResolveFunc returns a merge resolver of the objects in storageNamespace: it reads both
+// versions of a conflicting object with a registered resolver, and writes the merged object.
+// The merged entry keeps the metadata of the destination.
+func NewResolveFunc(adapter block.Adapter, storageNamespace string) catalog.MergeResolveFunc {
+	return func(source, destination *catalog.Entry) (*catalog.Entry, error) {
+		resolver := Lookup(source.Path)
+		if resolver == nil {
+			return nil, nil
+		}
+		sourceReader, err := adapter.Get(block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: source.PhysicalAddress}, source.Size)
+		if err != nil {
+			return nil, fmt.Errorf("read source: %w", err)
+		}
+		defer func() { _ = sourceReader.Close() }()
+		destinationReader, err := adapter.Get(block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: destination.PhysicalAddress}, destination.Size)
+		if err != nil {
+			return nil, fmt.Errorf("read destination: %w", err)
+		}
+		defer func() { _ = destinationReader.Close() }()
+
+		var merged bytes.Buffer
+		err = resolver.Resolve(&merged, sourceReader, destinationReader)
+		if errors.Is(err, ErrNotMergeable) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		size := int64(merged.Len())
+		blob, err := upload.WriteBlob(adapter, storageNamespace, &merged, size, block.PutOpts{})
+		if err != nil {
+			return nil, fmt.Errorf("write merged object: %w", err)
+		}
+		return &catalog.Entry{
+			Path:            source.Path,
+			PhysicalAddress: blob.PhysicalAddress,
+			CreationDate:    time.Now(),
+			Size:            blob.Size,
+			Checksum:        blob.Checksum,
+			Metadata:        destination.Metadata,
+		}, nil
+	}
+}
+
+// LinesUnion merges line based content, such as JSON lines appended to on both branches: the
+// destination lines followed by the source lines the destination does not have.  Lines
+// repeated in the source are kept as many times as they exceed the destination count.
+func LinesUnion(w io.Writer, source, destination io.Reader) error {
+	out := bufio.NewWriter(w)
+	counts := make(map[string]int)
+	err := scanLines(destination, func(line string) error {
+		counts[line]++
+		_, err := out.WriteString(line + "\n")
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+	err = scanLines(source, func(line string) error {
+		if counts[line] > 0 {
+			counts[line]--
+			return nil
+		}
+		_, err := out.WriteString(line + "\n")
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	return out.Flush()
+}
+
+// CSVUnion merges CSV files with the same header line as LinesUnion merges their rows.  Rows
+// are lines, quoted fields holding line breaks are not supported.
+func CSVUnion(w io.Writer, source, destination io.Reader) error {
+	sourceReader := bufio.NewReader(source)
+	destinationReader := bufio.NewReader(destination)
+	sourceHeader, err := sourceReader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("source header: %w", err)
+	}
+	destinationHeader, err := destinationReader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("destination header: %w", err)
+	}
+	if trimLine(sourceHeader) != trimLine(destinationHeader) {
+		return fmt.Errorf("different headers: %w", ErrNotMergeable)
+	}
+	if _, err := io.WriteString(w, trimLine(destinationHeader)+"\n"); err != nil {
+		return err
+	}
+	return LinesUnion(w, sourceReader, destinationReader)
+}
+
+func scanLines(r io.Reader, f func(line string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		if err := f(trimLine(scanner.Text())); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func trimLine(line string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+}
@@ -0,0 +1,126 @@
+package contentmerge_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/mem"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/contentmerge"
+	"github.com/treeverse/lakefs/upload"
+)
+
+func TestLinesUnion(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		destination string
+		want        string
+	}{
+		{name: "appends", source: "a\nb\nc\n", destination: "a\nb\nd\n", want: "a\nb\nd\nc\n"},
+		{name: "no final newline", source: "a\nc", destination: "a\nd", want: "a\nd\nc\n"},
+		{name: "repeated lines", source: "a\na\na\n", destination: "a\n", want: "a\na\na\n"},
+		{name: "empty source", source: "", destination: "a\n", want: "a\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := contentmerge.LinesUnion(&out, strings.NewReader(tt.source), strings.NewReader(tt.destination)); err != nil {
+				t.Fatalf("LinesUnion: %s", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("LinesUnion = %q, expected %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVUnion(t *testing.T) {
+	var out bytes.Buffer
+	err := contentmerge.CSVUnion(&out, strings.NewReader("id,name\r\n1,a\r\n3,c\r\n"), strings.NewReader("id,name\n1,a\n2,b\n"))
+	if err != nil {
+		t.Fatalf("CSVUnion: %s", err)
+	}
+	if want := "id,name\n1,a\n2,b\n3,c\n"; out.String() != want {
+		t.Errorf("CSVUnion = %q, expected %q", out.String(), want)
+	}
+
+	err = contentmerge.CSVUnion(&out, strings.NewReader("id,name\n1,a\n"), strings.NewReader("id,title\n1,a\n"))
+	if !errors.Is(err, contentmerge.ErrNotMergeable) {
+		t.Errorf("CSVUnion of different headers err=%v, expected %s", err, contentmerge.ErrNotMergeable)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	if contentmerge.Lookup("data/events.jsonl") == nil {
+		t.Error("no resolver of JSON lines")
+	}
+	if contentmerge.Lookup("data/table.parquet") != nil {
+		t.Error("resolver of parquet, expected none")
+	}
+	resolver := contentmerge.ResolverFunc(contentmerge.LinesUnion)
+	if err := contentmerge.Register("MANIFEST*", resolver); err != nil {
+		t.Fatalf("register: %s", err)
+	}
+	defer func() { _ = contentmerge.Register("MANIFEST*", nil) }()
+	if contentmerge.Lookup("table/MANIFEST-000001") == nil {
+		t.Error("no resolver of registered pattern")
+	}
+	if err := contentmerge.Register("[", resolver); err == nil {
+		t.Error("registered bad pattern")
+	}
+}
+
+func TestNewResolveFunc(t *testing.T) {
+	const namespace = "mem://merge"
+	adapter := mem.New()
+	write := func(path, content string) *catalog.Entry {
+		blob, err := upload.WriteBlob(adapter, namespace, strings.NewReader(content), int64(len(content)), block.PutOpts{})
+		if err != nil {
+			t.Fatalf("write %s: %s", path, err)
+		}
+		return &catalog.Entry{
+			Path:            path,
+			PhysicalAddress: blob.PhysicalAddress,
+			Size:            blob.Size,
+			Checksum:        blob.Checksum,
+			Metadata:        catalog.Metadata{"version": path},
+		}
+	}
+	resolve := contentmerge.NewResolveFunc(adapter, namespace)
+
+	merged, err := resolve(write("events.jsonl", "{\"a\":1}\n{\"c\":3}\n"), write("events.jsonl", "{\"a\":1}\n{\"b\":2}\n"))
+	if err != nil {
+		t.Fatalf("resolve: %s", err)
+	}
+	if merged == nil {
+		t.Fatal("resolve left the conflict, expected merged entry")
+	}
+	reader, err := adapter.Get(block.ObjectPointer{StorageNamespace: namespace, Identifier: merged.PhysicalAddress}, merged.Size)
+	if err != nil {
+		t.Fatalf("read merged: %s", err)
+	}
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read merged: %s", err)
+	}
+	if want := "{\"a\":1}\n{\"b\":2}\n{\"c\":3}\n"; string(content) != want {
+		t.Errorf("merged content %q, expected %q", content, want)
+	}
+	if merged.Size != int64(len(content)) || merged.Path != "events.jsonl" {
+		t.Errorf("merged entry %+v, expected path events.jsonl and size %d", merged, len(content))
+	}
+
+	merged, err = resolve(write("table.parquet", "source"), write("table.parquet", "destination"))
+	if err != nil || merged != nil {
+		t.Errorf("resolve without resolver = %+v, %v, expected no entry", merged, err)
+	}
+	merged, err = resolve(write("table.csv", "id,name\n"), write("table.csv", "id,title\n"))
+	if err != nil || merged != nil {
+		t.Errorf("resolve not mergeable = %+v, %v, expected no entry", merged, err)
+	}
+}
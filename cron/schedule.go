@@ -0,0 +1,186 @@
+// Package cron parses cron expressions and computes their schedules.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExpression = errors.New("invalid cron expression")
+
+// maxSearchYears bounds the search for the next time of a schedule that may never match,
+// such as February 30th
+const maxSearchYears = 5
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field is a set of the values matched by a single field of an expression
+type field uint64
+
+func (f field) has(v int) bool {
+	return f&(1<<uint(v)) != 0
+}
+
+type bounds struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteBounds = bounds{name: "minute", min: 0, max: 59}
+	hourBounds   = bounds{name: "hour", min: 0, max: 23}
+	domBounds    = bounds{name: "day of month", min: 1, max: 31}
+	monthBounds  = bounds{name: "month", min: 1, max: 12}
+	// 7 is also Sunday
+	dowBounds = bounds{name: "day of week", min: 0, max: 7}
+)
+
+// Schedule is a parsed cron expression.  Its times are in UTC.
+type Schedule struct {
+	minute, hour, dom, month, dow field
+	// domStar and dowStar are set when the day of month and day of week fields are "*": a
+	// day matches when both fields match it if one of them is "*", otherwise when either
+	// matches it
+	domStar, dowStar bool
+}
+
+// Parse parses a standard 5 field cron expression "minute hour day-of-month month
+// day-of-week", or one of the macros @yearly, @monthly, @weekly, @daily and @hourly.  Fields
+// are "*", numbers, ranges "a-b" and lists of them separated by commas, optionally followed
+// by a step "/n".
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[expr]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	const numFields = 5
+	if len(fields) != numFields {
+		return nil, fmt.Errorf("%w: %q has %d fields, expected %d", ErrInvalidExpression, expr, len(fields), numFields)
+	}
+	var s Schedule
+	var err error
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+	if s.dow.has(7) {
+		s.dow |= 1
+	}
+	s.domStar = fields[2] == "*"
+	s.dowStar = fields[4] == "*"
+	return &s, nil
+}
+
+func parseField(expr string, b bounds) (field, error) {
+	var f field
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangeExpr = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("%w: bad step in %s %q", ErrInvalidExpression, b.name, part)
+			}
+		}
+		low, high := b.min, b.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			i := strings.Index(rangeExpr, "-")
+			var err error
+			if low, err = parseValue(rangeExpr[:i], b); err != nil {
+				return 0, err
+			}
+			if high, err = parseValue(rangeExpr[i+1:], b); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("%w: empty range in %s %q", ErrInvalidExpression, b.name, part)
+			}
+		default:
+			var err error
+			if low, err = parseValue(rangeExpr, b); err != nil {
+				return 0, err
+			}
+			if step == 1 {
+				high = low
+			}
+		}
+		for v := low; v <= high; v += step {
+			f |= 1 << uint(v)
+		}
+	}
+	return f, nil
+}
+
+func parseValue(expr string, b bounds) (int, error) {
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < b.min || v > b.max {
+		return 0, fmt.Errorf("%w: %s %q not in %d-%d", ErrInvalidExpression, b.name, expr, b.min, b.max)
+	}
+	return v, nil
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom, dow := s.dom.has(t.Day()), s.dow.has(int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first time of the schedule after t, or the zero time if the schedule
+// never matches.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !s.month.has(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !s.hour.has(t.Hour()):
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !s.minute.has(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Prev returns the last time of the schedule at or before t, but after since.  It returns the
+// zero time if there is no such time.
+func (s *Schedule) Prev(since, t time.Time) time.Time {
+	var prev time.Time
+	for next := s.Next(since); !next.IsZero() && !next.After(t); next = s.Next(next) {
+		prev = next
+	}
+	return prev
+}
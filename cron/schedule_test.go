@@ -0,0 +1,65 @@
+package cron_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/cron"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	// a Tuesday
+	from := time.Date(2020, time.November, 3, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2020, time.November, 3, 10, 31, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2020, time.November, 3, 10, 45, 0, 0, time.UTC)},
+		{expr: "0 * * * *", want: time.Date(2020, time.November, 3, 11, 0, 0, 0, time.UTC)},
+		{expr: "@daily", want: time.Date(2020, time.November, 4, 0, 0, 0, 0, time.UTC)},
+		{expr: "30 2 * * 1-5", want: time.Date(2020, time.November, 4, 2, 30, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", want: time.Date(2020, time.November, 8, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1,15 * *", want: time.Date(2020, time.November, 15, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * 2", want: time.Date(2020, time.November, 10, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 12 29 2 *", want: time.Date(2024, time.February, 29, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := cron.Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %s", err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() got %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_Prev(t *testing.T) {
+	s, err := cron.Parse("0 * * * *")
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %s", err)
+	}
+	since := time.Date(2020, time.November, 3, 10, 30, 0, 0, time.UTC)
+	got := s.Prev(since, time.Date(2020, time.November, 3, 13, 15, 0, 0, time.UTC))
+	if want := time.Date(2020, time.November, 3, 13, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Prev() got %s, expected %s", got, want)
+	}
+	if got := s.Prev(since, since.Add(10*time.Minute)); !got.IsZero() {
+		t.Errorf("Prev() got %s, expected no time", got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@sometimes"} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := cron.Parse(expr); !errors.Is(err, cron.ErrInvalidExpression) {
+				t.Errorf("Parse() err=%v, expected %s", err, cron.ErrInvalidExpression)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/treeverse/lakefs/logging"
+)
+
+// copyPartitionColumn is used to split large tables into partitions that are compared and
+// copied separately, so only branches that changed since the last copy are copied again.
+const copyPartitionColumn = "branch_id"
+
+// CopyOptions configures CopyDatabase.
+type CopyOptions struct {
+	// DryRun only compares source and target, without writing to target.
+	DryRun bool
+}
+
+// CopyTableResult describes the differences found (and copied) between the source and target
+// of a single table.
+type CopyTableResult struct {
+	Table string
+	// Partitions is the number of partitions found in source or target
+	Partitions int
+	// Changed is the number of partitions that differ between source and target
+	Changed int
+}
+
+// CopyDatabase copies all tables of the current schema from source to target.  Tables are
+// compared by checksum of their content (partitioned by branch when the table has a branch
+// column) and only partitions that differ are replaced on target.  It can be called while
+// source keeps serving requests, and again to copy the delta that accumulated since the
+// previous call.  Cut over by running the final call while lakeFS serves source in read-only
+// mode.
+//
+// Only the columns found on both databases are copied, allowing target to be migrated to a
+// newer schema version before the copy.  Foreign keys are not checked on target while copying
+// (session_replication_role is set to "replica"), which requires a privileged database user.
+func CopyDatabase(ctx context.Context, source, target *pgxpool.Pool, opts CopyOptions) ([]CopyTableResult, error) {
+	tables, err := listTables(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("list source tables: %w", err)
+	}
+	results := make([]CopyTableResult, 0, len(tables))
+	for _, table := range tables {
+		res, err := copyTable(ctx, source, target, table, opts)
+		if err != nil {
+			return results, fmt.Errorf("copy table %s: %w", table, err)
+		}
+		results = append(results, *res)
+	}
+	if !opts.DryRun {
+		if err := copySequences(ctx, source, target); err != nil {
+			return results, fmt.Errorf("copy sequences: %w", err)
+		}
+	}
+	return results, nil
+}
+
+func listTables(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	var tables []string
+	err := pgxscan.Select(ctx, pool, &tables, `SELECT table_name FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' AND table_name <> 'schema_migrations'
+		ORDER BY table_name`)
+	return tables, err
+}
+
+func listColumns(ctx context.Context, pool *pgxpool.Pool, table string) ([]string, error) {
+	var columns []string
+	err := pgxscan.Select(ctx, pool, &columns, `SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1
+		ORDER BY ordinal_position`, table)
+	return columns, err
+}
+
+// commonColumns returns the columns of source which are also found on target, in source order
+func commonColumns(ctx context.Context, source, target *pgxpool.Pool, table string) ([]string, error) {
+	sourceColumns, err := listColumns(ctx, source, table)
+	if err != nil {
+		return nil, err
+	}
+	targetColumns, err := listColumns(ctx, target, table)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]struct{}, len(targetColumns))
+	for _, c := range targetColumns {
+		found[c] = struct{}{}
+	}
+	columns := make([]string, 0, len(sourceColumns))
+	for _, c := range sourceColumns {
+		if _, ok := found[c]; ok {
+			columns = append(columns, c)
+		}
+	}
+	return columns, nil
+}
+
+// tableChecksums returns a checksum of the content of each partition of table
+func tableChecksums(ctx context.Context, pool *pgxpool.Pool, table string, columns []string, partition string) (map[string]string, error) {
+	partitionExpr := "''"
+	if partition != "" {
+		partitionExpr = pgx.Identifier{partition}.Sanitize() + "::text"
+	}
+	rowExpr := "md5(ROW(" + strings.Join(quoteIdentifiers(columns), ",") + ")::text)"
+	rows, err := pool.Query(ctx, `SELECT `+partitionExpr+` AS key, md5(string_agg(`+rowExpr+`, '' ORDER BY `+rowExpr+`)) AS checksum
+		FROM `+pgx.Identifier{table}.Sanitize()+` GROUP BY 1`)
+	if err != nil {
+		return nil, err
+	}
+	var checksums []struct {
+		Key      string
+		Checksum string
+	}
+	if err := pgxscan.ScanAll(&checksums, rows); err != nil {
+		return nil, err
+	}
+	res := make(map[string]string, len(checksums))
+	for _, c := range checksums {
+		res[c.Key] = c.Checksum
+	}
+	return res, nil
+}
+
+func copyTable(ctx context.Context, source, target *pgxpool.Pool, table string, opts CopyOptions) (*CopyTableResult, error) {
+	log := logging.FromContext(ctx).WithField("table", table)
+	columns, err := commonColumns(ctx, source, target, table)
+	if err != nil {
+		return nil, fmt.Errorf("list columns: %w", err)
+	}
+	if len(columns) == 0 {
+		log.Warn("table not found on target, skipped")
+		return &CopyTableResult{Table: table}, nil
+	}
+	partition := ""
+	for _, c := range columns {
+		if c == copyPartitionColumn {
+			partition = c
+			break
+		}
+	}
+	sourceChecksums, err := tableChecksums(ctx, source, table, columns, partition)
+	if err != nil {
+		return nil, fmt.Errorf("source checksum: %w", err)
+	}
+	targetChecksums, err := tableChecksums(ctx, target, table, columns, partition)
+	if err != nil {
+		return nil, fmt.Errorf("target checksum: %w", err)
+	}
+
+	// partitions that differ, including partitions found only on one of the databases
+	var changed []string
+	for key, checksum := range sourceChecksums {
+		if targetChecksums[key] != checksum {
+			changed = append(changed, key)
+		}
+	}
+	for key := range targetChecksums {
+		if _, ok := sourceChecksums[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	partitions := len(sourceChecksums)
+	for key := range targetChecksums {
+		if _, ok := sourceChecksums[key]; !ok {
+			partitions++
+		}
+	}
+	res := &CopyTableResult{
+		Table:      table,
+		Partitions: partitions,
+		Changed:    len(changed),
+	}
+	if opts.DryRun {
+		return res, nil
+	}
+	for _, key := range changed {
+		if err := copyTablePartition(ctx, source, target, table, columns, partition, key); err != nil {
+			return nil, err
+		}
+	}
+	log.WithFields(logging.Fields{
+		"partitions": res.Partitions,
+		"changed":    res.Changed,
+	}).Info("table copied")
+	return res, nil
+}
+
+// copyTablePartition replaces the rows of a single partition of table on target with the rows
+// found on source
+func copyTablePartition(ctx context.Context, source, target *pgxpool.Pool, table string, columns []string, partition, key string) error {
+	where := ""
+	if partition != "" {
+		where = " WHERE " + pgx.Identifier{partition}.Sanitize() + "::text = " + quoteLiteral(key)
+	}
+	tableName := pgx.Identifier{table}.Sanitize()
+	columnsList := strings.Join(quoteIdentifiers(columns), ",")
+
+	sourceConn, err := source.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire source connection: %w", err)
+	}
+	defer sourceConn.Release()
+
+	tx, err := target.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin target: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	if _, err := tx.Exec(ctx, `SET LOCAL session_replication_role = replica`); err != nil {
+		return fmt.Errorf("disable target triggers: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM `+tableName+where); err != nil {
+		return fmt.Errorf("delete target rows: %w", err)
+	}
+
+	// stream rows from source COPY TO into target COPY FROM
+	reader, writer := io.Pipe()
+	copyToErr := make(chan error, 1)
+	go func() {
+		_, err := sourceConn.Conn().PgConn().CopyTo(ctx, writer, `COPY (SELECT `+columnsList+` FROM `+tableName+where+`) TO STDOUT`)
+		_ = writer.CloseWithError(err)
+		copyToErr <- err
+	}()
+	_, err = tx.Conn().PgConn().CopyFrom(ctx, reader, `COPY `+tableName+` (`+columnsList+`) FROM STDIN`)
+	_ = reader.Close()
+	if sourceErr := <-copyToErr; sourceErr != nil {
+		return fmt.Errorf("read source rows: %w", sourceErr)
+	}
+	if err != nil {
+		return fmt.Errorf("write target rows: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// copySequences sets the sequences on target to their current values on source
+func copySequences(ctx context.Context, source, target *pgxpool.Pool) error {
+	var sequences []string
+	err := pgxscan.Select(ctx, source, &sequences, `SELECT sequence_name FROM information_schema.sequences
+		WHERE sequence_schema = current_schema()`)
+	if err != nil {
+		return err
+	}
+	for _, sequence := range sequences {
+		var (
+			lastValue int64
+			isCalled  bool
+		)
+		name := pgx.Identifier{sequence}.Sanitize()
+		err := source.QueryRow(ctx, `SELECT last_value, is_called FROM `+name).Scan(&lastValue, &isCalled)
+		if err != nil {
+			return fmt.Errorf("read sequence %s: %w", sequence, err)
+		}
+		_, err = target.Exec(ctx, `SELECT setval($1, $2, $3)`, sequence, lastValue, isCalled)
+		if err != nil {
+			return fmt.Errorf("set sequence %s: %w", sequence, err)
+		}
+	}
+	return nil
+}
+
+func quoteIdentifiers(names []string) []string {
+	res := make([]string, len(names))
+	for i, name := range names {
+		res[i] = pgx.Identifier{name}.Sanitize()
+	}
+	return res
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,62 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func copyResultForTable(t *testing.T, results []db.CopyTableResult, table string) db.CopyTableResult {
+	t.Helper()
+	for _, res := range results {
+		if res.Table == table {
+			return res
+		}
+	}
+	t.Fatalf("missing copy result for table %s", table)
+	return db.CopyTableResult{}
+}
+
+func TestCopyDatabase(t *testing.T) {
+	ctx := context.Background()
+	source, _ := testutil.GetDB(t, databaseURI)
+	target, _ := testutil.GetDB(t, databaseURI)
+
+	_, err := source.Exec(`INSERT INTO auth_users (created_at, display_name) VALUES (NOW(), 'user1'), (NOW(), 'user2')`)
+	testutil.MustDo(t, "insert source users", err)
+
+	results, err := db.CopyDatabase(ctx, source.Pool(), target.Pool(), db.CopyOptions{})
+	testutil.MustDo(t, "copy database", err)
+	if res := copyResultForTable(t, results, "auth_users"); res.Changed != 1 {
+		t.Errorf("first copy auth_users changed %d partitions, expected 1", res.Changed)
+	}
+	var count int
+	testutil.MustDo(t, "count target users", target.GetPrimitive(&count, `SELECT COUNT(*) FROM auth_users`))
+	if count != 2 {
+		t.Fatalf("target has %d users after copy, expected 2", count)
+	}
+
+	// nothing changed - nothing to copy
+	results, err = db.CopyDatabase(ctx, source.Pool(), target.Pool(), db.CopyOptions{})
+	testutil.MustDo(t, "copy unchanged database", err)
+	if res := copyResultForTable(t, results, "auth_users"); res.Changed != 0 {
+		t.Errorf("unchanged copy auth_users changed %d partitions, expected 0", res.Changed)
+	}
+
+	// delta is found by dry run and copied by the next copy
+	_, err = source.Exec(`DELETE FROM auth_users WHERE display_name = 'user1'`)
+	testutil.MustDo(t, "delete source user", err)
+	results, err = db.CopyDatabase(ctx, source.Pool(), target.Pool(), db.CopyOptions{DryRun: true})
+	testutil.MustDo(t, "dry run copy", err)
+	if res := copyResultForTable(t, results, "auth_users"); res.Changed != 1 {
+		t.Errorf("dry run auth_users changed %d partitions, expected 1", res.Changed)
+	}
+	_, err = db.CopyDatabase(ctx, source.Pool(), target.Pool(), db.CopyOptions{})
+	testutil.MustDo(t, "copy delta", err)
+	testutil.MustDo(t, "count target users", target.GetPrimitive(&count, `SELECT COUNT(*) FROM auth_users`))
+	if count != 1 {
+		t.Fatalf("target has %d users after delta copy, expected 1", count)
+	}
+}
@@ -4,8 +4,16 @@ import (
 	"strings"
 )
 
+func escapeLike(s string) string {
+	v := strings.ReplaceAll(s, "%", "\\%")
+	return strings.ReplaceAll(v, "_", "\\_")
+}
+
 func Prefix(prefix string) string {
-	v := strings.ReplaceAll(prefix, "%", "\\%")
-	v = strings.ReplaceAll(v, "_", "\\_")
-	return v + "%"
+	return escapeLike(prefix) + "%"
+}
+
+// Contains returns a LIKE pattern matching values that contain s
+func Contains(s string) string {
+	return "%" + escapeLike(s) + "%"
 }
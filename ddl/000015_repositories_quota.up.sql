@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS catalog_repositories_quota (
+    repository_id integer PRIMARY KEY,
+    max_storage_bytes bigint NOT NULL DEFAULT 0,
+    max_objects bigint NOT NULL DEFAULT 0
+);
+
+ALTER TABLE catalog_repositories_quota
+    ADD CONSTRAINT repositories_quota_repositories_fk
+        FOREIGN KEY (repository_id) REFERENCES catalog_repositories(id)
+	ON DELETE CASCADE;
+END;
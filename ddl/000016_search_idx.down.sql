@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX IF EXISTS catalog_entries_path_trgm_idx;
+DROP INDEX IF EXISTS catalog_commits_message_trgm_idx;
+COMMIT;
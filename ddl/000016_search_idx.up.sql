@@ -0,0 +1,25 @@
+-- trigram indexes for searching commit messages and entry paths.  Creating the pg_trgm
+-- extension may require a privileged user: when the lakeFS user may not create it the
+-- indexes are skipped and search scans without them, see docs/deploying/db.md.
+BEGIN;
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS pg_trgm SCHEMA public;
+EXCEPTION WHEN insufficient_privilege OR undefined_file OR feature_not_supported THEN
+    RAISE NOTICE 'pg_trgm extension not created (%), skipping search indexes', SQLERRM;
+END
+$$;
+DO $$
+DECLARE
+    trgm_schema TEXT;
+BEGIN
+    SELECT n.nspname INTO trgm_schema
+    FROM pg_extension e JOIN pg_namespace n ON n.oid = e.extnamespace
+    WHERE e.extname = 'pg_trgm';
+    IF FOUND THEN
+        EXECUTE format('CREATE INDEX IF NOT EXISTS catalog_commits_message_trgm_idx ON catalog_commits USING gin (message %I.gin_trgm_ops)', trgm_schema);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS catalog_entries_path_trgm_idx ON catalog_entries USING gin (path %I.gin_trgm_ops)', trgm_schema);
+    END IF;
+END
+$$;
+COMMIT;
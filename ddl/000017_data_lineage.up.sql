@@ -0,0 +1,18 @@
+-- data lineage: commits produced from (source) commits, possibly of other repositories
+BEGIN;
+CREATE TABLE IF NOT EXISTS catalog_data_lineage (
+    branch_id bigint NOT NULL,
+    commit_id bigint NOT NULL,
+    source_branch_id bigint NOT NULL,
+    source_commit_id bigint NOT NULL,
+    creation_date timestamptz NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (branch_id, commit_id, source_branch_id, source_commit_id),
+    CONSTRAINT catalog_data_lineage_commit_fk FOREIGN KEY (branch_id, commit_id)
+        REFERENCES catalog_commits (branch_id, commit_id) ON DELETE CASCADE,
+    CONSTRAINT catalog_data_lineage_source_commit_fk FOREIGN KEY (source_branch_id, source_commit_id)
+        REFERENCES catalog_commits (branch_id, commit_id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS catalog_data_lineage_source_idx
+    ON catalog_data_lineage (source_branch_id, source_commit_id);
+COMMIT;
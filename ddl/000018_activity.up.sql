@@ -0,0 +1,17 @@
+-- repository activity feed: user actions on repositories, newest first by id
+BEGIN;
+CREATE TABLE IF NOT EXISTS activity_events (
+    id bigserial PRIMARY KEY,
+    repository_id integer NOT NULL,
+    event_type varchar NOT NULL,
+    actor varchar NOT NULL,
+    ref varchar NOT NULL DEFAULT '',
+    message varchar NOT NULL DEFAULT '',
+    creation_date timestamptz NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT activity_events_repository_fk FOREIGN KEY (repository_id)
+        REFERENCES catalog_repositories (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS activity_events_repository_idx
+    ON activity_events (repository_id, id DESC);
+COMMIT;
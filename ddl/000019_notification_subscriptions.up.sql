@@ -0,0 +1,18 @@
+-- per-user notification subscriptions: events on a repository (or all) and branch (or all)
+-- delivered through a channel to an address
+BEGIN;
+CREATE TABLE IF NOT EXISTS notification_subscriptions (
+    id bigserial PRIMARY KEY,
+    user_id integer REFERENCES auth_users (id) ON DELETE CASCADE NOT NULL,
+    repository varchar NOT NULL DEFAULT '',
+    branch varchar NOT NULL DEFAULT '',
+    event_type varchar NOT NULL,
+    channel varchar NOT NULL,
+    address varchar NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notification_subscriptions_unique UNIQUE (user_id, repository, branch, event_type, channel, address)
+);
+CREATE INDEX IF NOT EXISTS notification_subscriptions_event_idx
+    ON notification_subscriptions (event_type, channel);
+COMMIT;
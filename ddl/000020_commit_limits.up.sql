@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS catalog_repositories_commit_limits (
+    repository_id integer PRIMARY KEY,
+    max_changed_objects bigint NOT NULL DEFAULT 0,
+    max_added_bytes bigint NOT NULL DEFAULT 0
+);
+
+ALTER TABLE catalog_repositories_commit_limits
+    ADD CONSTRAINT repositories_commit_limits_repositories_fk
+        FOREIGN KEY (repository_id) REFERENCES catalog_repositories(id)
+	ON DELETE CASCADE;
+END;
@@ -0,0 +1,12 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS catalog_repositories_metadata_schema (
+    repository_id integer PRIMARY KEY,
+    schema jsonb NOT NULL
+);
+
+ALTER TABLE catalog_repositories_metadata_schema
+    ADD CONSTRAINT repositories_metadata_schema_repositories_fk
+        FOREIGN KEY (repository_id) REFERENCES catalog_repositories(id)
+	ON DELETE CASCADE;
+END;
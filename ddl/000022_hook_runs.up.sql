@@ -0,0 +1,27 @@
+-- hook runs: every hook invocation, its input event and outcome
+BEGIN;
+CREATE TABLE IF NOT EXISTS hook_runs (
+    id bigserial PRIMARY KEY,
+    repository_id integer NOT NULL,
+    event_id varchar NOT NULL,
+    event_type varchar NOT NULL,
+    branch varchar NOT NULL,
+    commit_id varchar NOT NULL DEFAULT '',
+    action varchar NOT NULL,
+    hook_id varchar NOT NULL,
+    hook_type varchar NOT NULL,
+    event jsonb NOT NULL,
+    status varchar NOT NULL,
+    output varchar NOT NULL DEFAULT '',
+    attempt integer NOT NULL DEFAULT 1,
+    start_time timestamptz NOT NULL,
+    duration_ms bigint NOT NULL,
+
+    CONSTRAINT hook_runs_repository_fk FOREIGN KEY (repository_id)
+        REFERENCES catalog_repositories (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS hook_runs_repository_idx
+    ON hook_runs (repository_id, id DESC);
+CREATE INDEX IF NOT EXISTS hook_runs_event_idx
+    ON hook_runs (event_id);
+COMMIT;
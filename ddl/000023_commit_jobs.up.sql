@@ -0,0 +1,26 @@
+-- commit jobs: commits of large changesets performed in chunks, each in its own transaction
+BEGIN;
+CREATE TABLE IF NOT EXISTS catalog_commit_jobs (
+    id bigserial PRIMARY KEY,
+    branch_id integer NOT NULL,
+    commit_id bigint NOT NULL,
+    previous_commit_id bigint NOT NULL,
+    committer varchar NOT NULL,
+    message varchar NOT NULL,
+    metadata jsonb,
+    status varchar NOT NULL,
+    after_path varchar COLLATE "C" NOT NULL DEFAULT '',
+    committed_entries bigint NOT NULL DEFAULT 0,
+    error varchar NOT NULL DEFAULT '',
+    creation_date timestamptz NOT NULL DEFAULT now(),
+    update_date timestamptz NOT NULL DEFAULT now(),
+
+    CONSTRAINT catalog_commit_jobs_branches_fk FOREIGN KEY (branch_id)
+        REFERENCES catalog_branches (id) ON DELETE CASCADE
+);
+-- a branch has at most one unfinished commit job
+CREATE UNIQUE INDEX IF NOT EXISTS catalog_commit_jobs_unfinished_uindex
+    ON catalog_commit_jobs (branch_id) WHERE status <> 'completed';
+CREATE INDEX IF NOT EXISTS catalog_commit_jobs_branch_idx
+    ON catalog_commit_jobs (branch_id, id DESC);
+COMMIT;
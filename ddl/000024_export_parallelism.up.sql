@@ -0,0 +1 @@
+ALTER TABLE catalog_branches_export ADD COLUMN IF NOT EXISTS parallelism INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE catalog_branches_export DROP COLUMN IF EXISTS mode;
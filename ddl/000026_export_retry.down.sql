@@ -0,0 +1,91 @@
+BEGIN;
+
+-- Marks up to `max_tasks' on one of `actions' as in-progress and
+-- belonging to `actor_id' and returns their ids and a "performance
+-- token".  Both must be returned to complete the task successfully.
+CREATE OR REPLACE FUNCTION own_tasks(
+    max_tasks INTEGER, actions VARCHAR ARRAY, owner_id VARCHAR, max_duration INTERVAL
+)
+RETURNS TABLE(task_id VARCHAR, token UUID, num_failures INTEGER, action VARCHAR, body TEXT)
+LANGUAGE sql VOLATILE AS $$
+    UPDATE tasks
+    SET actor_id = owner_id,
+        status_code = 'in-progress',
+        num_tries = num_tries + 1,
+        performance_token = public.gen_random_uuid(),
+        action_deadline = NOW() + max_duration -- NULL if max_duration IS NULL
+    WHERE id IN (
+        SELECT id
+        FROM tasks
+        WHERE can_allocate_task(id, status_code, action_deadline, num_signals, total_dependencies) AND
+            action = ANY(actions) AND
+            (max_tries IS NULL OR num_tries < max_tries)
+        -- maybe: AND not_before <= NOW()
+        -- maybe: ORDER BY priority (eventually)
+        ORDER BY random()
+        FOR UPDATE SKIP LOCKED
+        LIMIT max_tasks)
+    RETURNING id, performance_token, num_failures, action, body
+$$;
+
+CREATE OR REPLACE FUNCTION return_task(
+    task_id VARCHAR, token UUID, result_status TEXT, result_status_code task_status_code_value
+) RETURNS INTEGER
+LANGUAGE plpgsql AS $$
+DECLARE
+    num_updated INTEGER;
+    channel VARCHAR;
+    to_signal VARCHAR ARRAY;
+
+
+
+BEGIN
+    CASE result_status_code
+    WHEN 'aborted', 'completed' THEN
+        UPDATE tasks INTO channel, to_signal
+        SET status = result_status,
+            status_code = result_status_code,
+            actor_id = NULL,
+            performance_token = NULL
+        WHERE id = task_id AND performance_token = token
+        RETURNING notify_channel_after, to_signal_after;
+    WHEN 'pending' THEN
+        UPDATE tasks INTO channel, to_signal
+	SET status = result_status,
+	    status_code = (CASE WHEN no_more_tries(tasks) THEN 'aborted' ELSE 'pending' END)::task_status_code_value,
+	    actor_id = NULL,
+	    performance_token = NULL
+	WHERE id = task_id AND performance_token = token
+	RETURNING (CASE WHEN no_more_tries(tasks) THEN notify_channel_after ELSE NULL END),
+	          (CASE WHEN no_more_tries(tasks) THEN to_signal_after ELSE NULL END);
+    ELSE
+        RAISE EXCEPTION 'cannot return task to status %', result_status;
+    END CASE;
+
+    GET DIAGNOSTICS num_updated := ROW_COUNT;
+
+    UPDATE tasks
+    SET num_signals = num_signals+1,
+        num_failures = num_failures + CASE WHEN result_status_code = 'aborted'::task_status_code_value THEN 1 ELSE 0 END
+    WHERE id = ANY(to_signal);
+
+    IF channel IS NOT NULL THEN
+        PERFORM pg_notify(channel, NULL);
+    END IF;
+
+    RETURN num_updated;
+END;
+$$;
+
+DROP FUNCTION IF EXISTS retry_delay;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS not_before,
+    DROP COLUMN IF EXISTS retry_max_delay,
+    DROP COLUMN IF EXISTS retry_base_delay;
+
+ALTER TABLE catalog_branches_export
+    DROP COLUMN IF EXISTS retry_backoff,
+    DROP COLUMN IF EXISTS max_attempts;
+
+END;
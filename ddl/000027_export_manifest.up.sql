@@ -0,0 +1 @@
+ALTER TABLE catalog_branches_export ADD COLUMN IF NOT EXISTS write_manifest BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,6 @@
+BEGIN;
+DELETE FROM catalog_branches_export WHERE prefix <> '';
+ALTER TABLE catalog_branches_export DROP CONSTRAINT IF EXISTS catalog_branches_export_pkey;
+ALTER TABLE catalog_branches_export ADD PRIMARY KEY (branch_id);
+ALTER TABLE catalog_branches_export DROP COLUMN IF EXISTS prefix;
+COMMIT;
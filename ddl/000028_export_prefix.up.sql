@@ -0,0 +1,5 @@
+BEGIN;
+ALTER TABLE catalog_branches_export ADD COLUMN IF NOT EXISTS prefix VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE catalog_branches_export DROP CONSTRAINT IF EXISTS catalog_branches_export_pkey;
+ALTER TABLE catalog_branches_export ADD PRIMARY KEY (branch_id, prefix);
+COMMIT;
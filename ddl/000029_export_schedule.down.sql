@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE catalog_branches_export_state DROP COLUMN IF EXISTS last_scheduled_run;
+ALTER TABLE catalog_branches_export DROP COLUMN IF EXISTS schedule;
+COMMIT;
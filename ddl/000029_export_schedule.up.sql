@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE catalog_branches_export ADD COLUMN IF NOT EXISTS schedule VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE catalog_branches_export_state ADD COLUMN IF NOT EXISTS last_scheduled_run TIMESTAMPTZ;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE catalog_branches_export DROP COLUMN IF EXISTS exclude_globs;
+ALTER TABLE catalog_branches_export DROP COLUMN IF EXISTS include_prefixes;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE catalog_branches_export ADD COLUMN IF NOT EXISTS include_prefixes VARCHAR ARRAY;
+ALTER TABLE catalog_branches_export ADD COLUMN IF NOT EXISTS exclude_globs VARCHAR ARRAY;
+COMMIT;
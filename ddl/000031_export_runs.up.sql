@@ -0,0 +1,25 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS catalog_branches_export_runs (
+    export_id VARCHAR PRIMARY KEY,
+    branch_id INTEGER NOT NULL,
+    from_ref VARCHAR NOT NULL DEFAULT '',  -- empty when exporting all entries of to_ref
+    to_ref VARCHAR NOT NULL,
+    start_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    end_time TIMESTAMPTZ,                  -- NULL while in progress
+    objects_copied BIGINT NOT NULL DEFAULT 0,
+    objects_deleted BIGINT NOT NULL DEFAULT 0,
+    bytes_copied BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR NOT NULL,
+    error_message TEXT
+);
+
+ALTER TABLE catalog_branches_export_runs
+    ADD CONSTRAINT branches_export_runs_branches_fk
+    FOREIGN KEY (branch_id) REFERENCES catalog_branches(id)
+    ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS catalog_branches_export_runs_branch_start_idx
+    ON catalog_branches_export_runs (branch_id, start_time DESC, export_id DESC);
+
+COMMIT;
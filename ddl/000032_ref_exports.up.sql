@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS catalog_ref_exports (
+    export_id VARCHAR PRIMARY KEY,
+    repository_id INTEGER NOT NULL,
+    ref VARCHAR NOT NULL,                  -- exported ref as requested, e.g. a tag or a commit
+    commit_ref VARCHAR NOT NULL,           -- commit ref resolved from ref
+    destination VARCHAR NOT NULL,
+    start_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    end_time TIMESTAMPTZ,                  -- NULL while in progress
+    status VARCHAR NOT NULL,
+    error_message TEXT
+);
+
+ALTER TABLE catalog_ref_exports
+    ADD CONSTRAINT ref_exports_repositories_fk
+    FOREIGN KEY (repository_id) REFERENCES catalog_repositories(id)
+    ON DELETE CASCADE;
+
+COMMIT;
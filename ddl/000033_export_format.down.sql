@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_branches_export DROP COLUMN IF EXISTS format;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_branches_export ADD COLUMN IF NOT EXISTS propagate_deletes BOOLEAN NOT NULL DEFAULT TRUE;
+COMMIT;
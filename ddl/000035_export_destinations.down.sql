@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS catalog_branches_export_run_destinations;
+ALTER TABLE catalog_branches_export DROP COLUMN IF EXISTS additional_export_paths;
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE catalog_branches_export ADD COLUMN IF NOT EXISTS additional_export_paths VARCHAR ARRAY;
+
+CREATE TABLE IF NOT EXISTS catalog_branches_export_run_destinations (
+    export_id VARCHAR NOT NULL,
+    destination VARCHAR NOT NULL,          -- export path of a configuration exporting to several paths
+    end_time TIMESTAMPTZ,                  -- NULL while in progress
+    status VARCHAR NOT NULL,
+    error_message TEXT,
+    PRIMARY KEY (export_id, destination)
+);
+
+ALTER TABLE catalog_branches_export_run_destinations
+    ADD CONSTRAINT branches_export_run_destinations_runs_fk
+    FOREIGN KEY (export_id) REFERENCES catalog_branches_export_runs(export_id)
+    ON DELETE CASCADE;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+ALTER TABLE catalog_branches_export
+    DROP COLUMN IF EXISTS sse_algorithm,
+    DROP COLUMN IF EXISTS sse_kms_key_id;
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE catalog_branches_export
+    ADD COLUMN IF NOT EXISTS sse_algorithm VARCHAR NOT NULL DEFAULT '',    -- empty for no server-side encryption
+    ADD COLUMN IF NOT EXISTS sse_kms_key_id VARCHAR NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE catalog_branches_export_runs DROP COLUMN IF EXISTS paths_time;
+COMMIT;
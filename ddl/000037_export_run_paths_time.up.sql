@@ -0,0 +1,6 @@
+BEGIN;
+
+-- time expanding the placeholders of the export paths of the run, NULL for older runs
+ALTER TABLE catalog_branches_export_runs ADD COLUMN IF NOT EXISTS paths_time TIMESTAMPTZ;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+-- tags are immutable names of commits, sharing the namespace of the repository branches
+CREATE TABLE IF NOT EXISTS catalog_tags (
+    repository_id integer NOT NULL,
+    name character varying(64) NOT NULL,
+    branch_id bigint NOT NULL,
+    commit_id bigint NOT NULL,
+    creation_date timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (repository_id, name),
+    FOREIGN KEY (repository_id) REFERENCES catalog_repositories(id) ON DELETE CASCADE,
+    FOREIGN KEY (branch_id, commit_id) REFERENCES catalog_commits(branch_id, commit_id)
+);
+
+CREATE INDEX IF NOT EXISTS catalog_tags_branch_id_idx ON catalog_tags (branch_id);
+
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+DROP INDEX IF EXISTS catalog_entries_checksum_idx;
+COMMIT;
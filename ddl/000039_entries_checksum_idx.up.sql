@@ -0,0 +1,5 @@
+-- index for finding the entries of an object by its checksum
+BEGIN;
+CREATE INDEX IF NOT EXISTS catalog_entries_checksum_idx
+    ON catalog_entries (checksum, branch_id, path);
+COMMIT;
@@ -0,0 +1,5 @@
+-- index for searching entries by their user metadata
+BEGIN;
+CREATE INDEX IF NOT EXISTS catalog_entries_metadata_idx
+    ON catalog_entries USING gin (metadata);
+COMMIT;
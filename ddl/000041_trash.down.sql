@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS catalog_trash;
+DROP TABLE IF EXISTS catalog_repositories_trash;
+COMMIT;
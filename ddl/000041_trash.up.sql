@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS catalog_repositories_trash (
+    repository_id integer PRIMARY KEY,
+    retention_days integer NOT NULL DEFAULT 0,
+    FOREIGN KEY (repository_id) REFERENCES catalog_repositories(id) ON DELETE CASCADE
+);
+
+-- entries deleted from branches of repositories with a trash, restorable until they expire.
+-- only the last deleted entry of each path is kept.
+CREATE TABLE IF NOT EXISTS catalog_trash (
+    branch_id bigint NOT NULL,
+    path character varying COLLATE "C" NOT NULL,
+    physical_address character varying,
+    creation_date timestamp with time zone NOT NULL,
+    size bigint NOT NULL,
+    checksum character varying(64) NOT NULL,
+    metadata jsonb,
+    is_expired boolean DEFAULT false NOT NULL,
+    deletion_date timestamp with time zone DEFAULT now() NOT NULL,
+    expiration_date timestamp with time zone NOT NULL,
+    PRIMARY KEY (branch_id, path),
+    FOREIGN KEY (branch_id) REFERENCES catalog_branches(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS catalog_trash_expiration_date_idx ON catalog_trash (expiration_date);
+
+COMMIT;
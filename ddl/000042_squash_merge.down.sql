@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE catalog_commits DROP COLUMN IF EXISTS squash;
+
+COMMIT;
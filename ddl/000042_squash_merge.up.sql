@@ -0,0 +1,6 @@
+BEGIN;
+
+-- squash merges keep their source for diffs, but the log does not follow into it
+ALTER TABLE catalog_commits ADD COLUMN IF NOT EXISTS squash BOOLEAN NOT NULL DEFAULT false;
+
+COMMIT;
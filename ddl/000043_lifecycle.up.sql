@@ -0,0 +1,13 @@
+BEGIN;
+
+-- expiration rules of repositories, applied to the committed entries of a single branch.
+-- the rules are a JSON array of {"prefix", "max_age_days", "max_versions"} objects.
+CREATE TABLE IF NOT EXISTS catalog_repositories_lifecycle (
+    repository_id integer PRIMARY KEY,
+    branch_id bigint NOT NULL,
+    rules jsonb NOT NULL,
+    FOREIGN KEY (repository_id) REFERENCES catalog_repositories(id) ON DELETE CASCADE,
+    FOREIGN KEY (branch_id) REFERENCES catalog_branches(id) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- advisory locks on path prefixes of branches, held by an owner until they expire
+CREATE TABLE IF NOT EXISTS catalog_branch_locks (
+    branch_id bigint NOT NULL,
+    path character varying COLLATE "C" NOT NULL,
+    owner character varying NOT NULL,
+    acquired_date timestamp with time zone DEFAULT now() NOT NULL,
+    expiration_date timestamp with time zone NOT NULL,
+    PRIMARY KEY (branch_id, path),
+    FOREIGN KEY (branch_id) REFERENCES catalog_branches(id) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+ALTER TABLE catalog_multipart_uploads
+    DROP COLUMN IF EXISTS content_type,
+    DROP COLUMN IF EXISTS content_encoding,
+    DROP COLUMN IF EXISTS cache_control;
+
+ALTER TABLE catalog_trash
+    DROP COLUMN IF EXISTS content_type,
+    DROP COLUMN IF EXISTS content_encoding,
+    DROP COLUMN IF EXISTS cache_control;
+
+ALTER TABLE catalog_entries
+    DROP COLUMN IF EXISTS content_type,
+    DROP COLUMN IF EXISTS content_encoding,
+    DROP COLUMN IF EXISTS cache_control;
+
+COMMIT;
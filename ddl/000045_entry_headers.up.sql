@@ -0,0 +1,20 @@
+BEGIN;
+
+-- HTTP headers stored on upload and served back when reading an entry
+ALTER TABLE catalog_entries
+    ADD COLUMN IF NOT EXISTS content_type character varying DEFAULT '' NOT NULL,
+    ADD COLUMN IF NOT EXISTS content_encoding character varying DEFAULT '' NOT NULL,
+    ADD COLUMN IF NOT EXISTS cache_control character varying DEFAULT '' NOT NULL;
+
+ALTER TABLE catalog_trash
+    ADD COLUMN IF NOT EXISTS content_type character varying DEFAULT '' NOT NULL,
+    ADD COLUMN IF NOT EXISTS content_encoding character varying DEFAULT '' NOT NULL,
+    ADD COLUMN IF NOT EXISTS cache_control character varying DEFAULT '' NOT NULL;
+
+-- headers of the entry created when the multipart upload completes
+ALTER TABLE catalog_multipart_uploads
+    ADD COLUMN IF NOT EXISTS content_type character varying DEFAULT '' NOT NULL,
+    ADD COLUMN IF NOT EXISTS content_encoding character varying DEFAULT '' NOT NULL,
+    ADD COLUMN IF NOT EXISTS cache_control character varying DEFAULT '' NOT NULL;
+
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS catalog_storage_namespace_migration_objects;
+DROP TABLE IF EXISTS catalog_storage_namespace_migrations;
+COMMIT;
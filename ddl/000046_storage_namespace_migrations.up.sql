@@ -0,0 +1,28 @@
+BEGIN;
+
+-- progress of copying the objects of repositories to a new storage namespace.  physical_address
+-- is the last physical address migrated in the current pass over the entries of the repository.
+CREATE TABLE IF NOT EXISTS catalog_storage_namespace_migrations (
+    repository_id integer PRIMARY KEY,
+    source_namespace character varying NOT NULL,
+    storage_namespace character varying NOT NULL,
+    physical_address character varying COLLATE "C" DEFAULT '' NOT NULL,
+    migrated_objects bigint DEFAULT 0 NOT NULL,
+    started_date timestamp with time zone DEFAULT now() NOT NULL,
+    updated_date timestamp with time zone DEFAULT now() NOT NULL,
+    FOREIGN KEY (repository_id) REFERENCES catalog_repositories(id) ON DELETE CASCADE
+);
+
+-- physical addresses already migrated, and the addresses their objects were copied to
+CREATE TABLE IF NOT EXISTS catalog_storage_namespace_migration_objects (
+    repository_id integer NOT NULL,
+    physical_address character varying NOT NULL,
+    migrated_address character varying NOT NULL,
+    PRIMARY KEY (repository_id, physical_address),
+    FOREIGN KEY (repository_id) REFERENCES catalog_storage_namespace_migrations(repository_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS catalog_storage_namespace_migration_objects_migrated_idx
+    ON catalog_storage_namespace_migration_objects (repository_id, migrated_address);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE catalog_repositories
+    DROP COLUMN IF EXISTS read_only;
+
+COMMIT;
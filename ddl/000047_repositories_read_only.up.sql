@@ -0,0 +1,7 @@
+BEGIN;
+
+-- read-only repositories reject writes, commits and merges
+ALTER TABLE catalog_repositories
+    ADD COLUMN IF NOT EXISTS read_only boolean DEFAULT false NOT NULL;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- archived repositories are hidden from listings and reject writes, commits and merges
+ALTER TABLE catalog_repositories
+    ADD COLUMN IF NOT EXISTS archived boolean DEFAULT false NOT NULL;
+
+COMMIT;
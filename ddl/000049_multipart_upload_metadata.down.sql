@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE catalog_multipart_uploads
+    DROP COLUMN IF EXISTS metadata;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- user metadata of the entry created when the multipart upload completes
+ALTER TABLE catalog_multipart_uploads
+    ADD COLUMN IF NOT EXISTS metadata jsonb;
+
+COMMIT;
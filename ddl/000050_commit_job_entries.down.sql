@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS catalog_commit_jobs_running_uindex;
+-- a branch may have several failed jobs, which the previous index does not allow
+DELETE FROM catalog_commit_jobs WHERE status = 'failed';
+CREATE UNIQUE INDEX IF NOT EXISTS catalog_commit_jobs_unfinished_uindex
+    ON catalog_commit_jobs (branch_id) WHERE status <> 'completed';
+DROP TABLE IF EXISTS catalog_commit_job_entries;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- the paths staged by the chunks of a commit job, committed together when the job completes
+CREATE TABLE IF NOT EXISTS catalog_commit_job_entries (
+    job_id bigint NOT NULL,
+    path varchar COLLATE "C" NOT NULL,
+
+    PRIMARY KEY (job_id, path),
+    CONSTRAINT catalog_commit_job_entries_jobs_fk FOREIGN KEY (job_id)
+        REFERENCES catalog_commit_jobs (id) ON DELETE CASCADE
+);
+
+-- failed jobs are rolled back and no longer hold their branch, jobs that failed before
+-- chunks were staged may have committed some of their entries, so they are left to resume
+UPDATE catalog_commit_jobs SET status = 'running' WHERE status = 'failed';
+DROP INDEX IF EXISTS catalog_commit_jobs_unfinished_uindex;
+CREATE UNIQUE INDEX IF NOT EXISTS catalog_commit_jobs_running_uindex
+    ON catalog_commit_jobs (branch_id) WHERE status = 'running';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE catalog_repositories_quota
+    DROP COLUMN IF EXISTS used_storage_bytes,
+    DROP COLUMN IF EXISTS used_objects;
+
+COMMIT;
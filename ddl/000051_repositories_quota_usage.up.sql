@@ -0,0 +1,8 @@
+BEGIN;
+
+-- upper bound of the usage of a repository with a quota, NULL until first computed
+ALTER TABLE catalog_repositories_quota
+    ADD COLUMN IF NOT EXISTS used_storage_bytes bigint,
+    ADD COLUMN IF NOT EXISTS used_objects bigint;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE catalog_repositories_quota
+    DROP COLUMN IF EXISTS max_requests_per_second,
+    DROP COLUMN IF EXISTS max_export_bytes_per_second,
+    DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS catalog_organizations_quota;
+
+COMMIT;
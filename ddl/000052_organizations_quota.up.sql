@@ -0,0 +1,22 @@
+BEGIN;
+
+-- quota shared by the repositories of an organization
+CREATE TABLE IF NOT EXISTS catalog_organizations_quota (
+    id serial NOT NULL PRIMARY KEY,
+    name varchar(64) NOT NULL UNIQUE,
+    max_storage_bytes bigint NOT NULL DEFAULT 0,
+    max_objects bigint NOT NULL DEFAULT 0,
+    max_requests_per_second bigint NOT NULL DEFAULT 0,
+    max_export_bytes_per_second bigint NOT NULL DEFAULT 0,
+    -- upper bound of the usage of the organization, NULL until first computed
+    used_storage_bytes bigint,
+    used_objects bigint
+);
+
+ALTER TABLE catalog_repositories_quota
+    ADD COLUMN IF NOT EXISTS max_requests_per_second bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS max_export_bytes_per_second bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS organization_id integer
+        REFERENCES catalog_organizations_quota(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/azure"
+)
+
+const SchemeGS = "gs"
+
+// SchemesAzure are the schemes of Azure Blob Storage URLs
+var SchemesAzure = []string{"https", "wasb", "wasbs"}
+
+var ErrUnsupportedDestination = errors.New("unsupported export destination")
+
+// DestinationBuilder builds the adapter that writes to an export destination
+type DestinationBuilder func() (block.Adapter, error)
+
+// Destinations holds the adapters that write to export destinations outside the lakeFS
+// blockstore, by URL scheme.  Adapters are built on first use, so destinations that are never
+// exported to need no credentials.
+type Destinations struct {
+	mu       sync.Mutex
+	builders map[string]DestinationBuilder
+	adapters map[string]block.Adapter
+}
+
+func NewDestinations() *Destinations {
+	return &Destinations{
+		builders: make(map[string]DestinationBuilder),
+		adapters: make(map[string]block.Adapter),
+	}
+}
+
+// Register sets the builder of the adapter writing to destinations with scheme
+func (d *Destinations) Register(scheme string, builder DestinationBuilder) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.builders[scheme] = builder
+	delete(d.adapters, scheme)
+}
+
+// Adapter returns the adapter writing to destinations with scheme, or nil if the lakeFS
+// blockstore adapter writes to them
+func (d *Destinations) Adapter(scheme string) (block.Adapter, error) {
+	if d == nil {
+		return nil, nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if adapter, ok := d.adapters[scheme]; ok {
+		return adapter, nil
+	}
+	builder, ok := d.builders[scheme]
+	if !ok {
+		return nil, nil
+	}
+	adapter, err := builder()
+	if err != nil {
+		return nil, fmt.Errorf("build %s export destination: %w", scheme, err)
+	}
+	d.adapters[scheme] = adapter
+	return adapter, nil
+}
+
+// resolve returns the adapter writing to path and its pointer, adapter writes paths that have
+// no destination adapter
+func (d *Destinations) resolve(adapter block.Adapter, path string) (block.Adapter, block.ObjectPointer, error) {
+	pointer, err := PathToPointer(path)
+	if err != nil {
+		return nil, block.ObjectPointer{}, err
+	}
+	u, err := url.Parse(pointer.StorageNamespace)
+	if err != nil {
+		return nil, block.ObjectPointer{}, err
+	}
+	destinationAdapter, err := d.Adapter(u.Scheme)
+	if err != nil {
+		return nil, block.ObjectPointer{}, err
+	}
+	if destinationAdapter != nil {
+		adapter = destinationAdapter
+	}
+	return adapter, pointer, nil
+}
+
+// ValidateDestination verifies that path is a URL on storage that lakeFS can export to
+func ValidateDestination(path string) error {
+	u, err := url.Parse(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, scheme := range SchemesAzure {
+		if u.Scheme != scheme {
+			continue
+		}
+		if _, _, _, err := azure.ParseURL(u); err != nil {
+			return fmt.Errorf("%w: %s", ErrUnsupportedDestination, err)
+		}
+		return nil
+	}
+	if _, err := block.GetStorageType(u); err != nil {
+		return fmt.Errorf("%s: %w", path, ErrUnsupportedDestination)
+	}
+	return nil
+}
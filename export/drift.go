@@ -0,0 +1,207 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/parade"
+)
+
+type DriftType string
+
+const (
+	DriftTypeModified DriftType = "modified"
+	DriftTypeDeleted  DriftType = "deleted"
+)
+
+// Drift is an exported object that was changed on the destination out-of-band
+type Drift struct {
+	Path string
+	Type DriftType
+	// Prefix of the export configuration whose destination drifted
+	Prefix string
+}
+
+// DriftReport describes the drift of an export destination from the last exported commit
+type DriftReport struct {
+	CommitRef string
+	Drifts    []Drift
+	// ExportID identifies the export re-exporting the drifted objects, empty if none started
+	ExportID string
+}
+
+var ErrNotExported = errors.New("branch not exported successfully")
+
+// ExportBranchDrift compares the export destinations of branch with the last commit exported
+// to them.  If reconcile is set, it re-exports only the drifted objects, setting branch export
+// state to in progress until they are copied.  The destination is listed with its adapter in
+// destinations, or with the blockstore adapter if it has none.  Only the first path of
+// configurations with additional paths is compared, drifted objects are re-exported to all
+// their paths.  Configurations exporting to a path of its own every time (see
+// catalog.IsPerExportPath) have no destination to compare.
+func ExportBranchDrift(ctx context.Context, paradeDB parade.Parade, adapter block.Adapter, destinations *Destinations, cataloger catalog.Cataloger, repo, branch string, reconcile bool) (*DriftReport, error) {
+	exportState, err := cataloger.GetExportState(ctx, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	if exportState.State == catalog.ExportStatusInProgress {
+		return nil, ErrExportInProgress
+	}
+	if exportState.State != catalog.ExportStatusSuccess || exportState.CurrentRef == "" {
+		return nil, fmt.Errorf("%s state %s: %w", branch, exportState.State, ErrNotExported)
+	}
+	configs, err := getExportConfigurations(ctx, cataloger, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	startTime := time.Now()
+	expandedConfigs := expandPaths(configs, branch, exportState.CurrentRef, startTime)
+
+	report := &DriftReport{
+		CommitRef: exportState.CurrentRef,
+		Drifts:    make([]Drift, 0),
+	}
+	// drifted holds the drifted entries of each configuration
+	drifted := make([]catalog.Differences, len(configs))
+	numDrifted := 0
+	for i, config := range expandedConfigs {
+		if config.Format == catalog.ExportFormatSymlink {
+			// symlink destinations hold no copies of entries to drift
+			continue
+		}
+		if catalog.IsPerExportPath(configs[i].Path) {
+			// every export wrote its own snapshot, none is the destination to compare
+			continue
+		}
+		filter := newPathFilter(config)
+		objects, err := listDestination(adapter, destinations, config.Path)
+		if err != nil {
+			return nil, fmt.Errorf("list export destination %s: %w", config.Path, err)
+		}
+		it, err := cataloger.ListEntriesIterator(ctx, repo, exportState.CurrentRef, config.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() {
+			entry := it.Value()
+			if !filter.matches(entry.Path) {
+				continue
+			}
+			driftType, ok := entryDrift(entry, objects)
+			if !ok {
+				continue
+			}
+			report.Drifts = append(report.Drifts, Drift{Path: entry.Path, Type: driftType, Prefix: config.Prefix})
+			drifted[i] = append(drifted[i], catalog.Difference{Entry: *entry, Type: catalog.DifferenceTypeChanged})
+			numDrifted++
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if !reconcile || numDrifted == 0 {
+		return report, nil
+	}
+
+	exportID, err := getExportID(repo, branch, exportState.CurrentRef)
+	if err != nil {
+		return nil, err
+	}
+	repository, err := cataloger.GetRepository(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	err = cataloger.ExportStateSet(ctx, repo, branch, func(oldRef string, state catalog.CatalogBranchExportStatus) (newRef string, newState catalog.CatalogBranchExportStatus, newMessage *string, err error) {
+		if state == catalog.ExportStatusInProgress {
+			return oldRef, state, nil, ErrExportInProgress
+		}
+		if oldRef != report.CommitRef {
+			return "", "", nil, fmt.Errorf("reconcile export: currentRef:%s, comparedRef:%s: %w", oldRef, report.CommitRef, ErrConflictingRefs)
+		}
+		finishBodyStr, err := getFinishBodyString(exportID, repo, branch, oldRef, expandedConfigs)
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		tasksGenerator := NewMultiTasksGenerator(exportID, repo, oldRef, oldRef, expandedConfigs, &finishBodyStr, repository.StorageNamespace)
+		var tasks []parade.TaskData
+		for i, diffs := range drifted {
+			configTasks, err := tasksGenerator.addTo(i, diffs)
+			if err != nil {
+				return oldRef, "", nil, err
+			}
+			tasks = append(tasks, configTasks...)
+		}
+		finishTasks, err := tasksGenerator.Finish()
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		err = paradeDB.InsertTasks(ctx, append(tasks, finishTasks...))
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		err = cataloger.InsertExportRun(ctx, repo, branch, &catalog.ExportRun{ExportID: exportID, FromRef: oldRef, ToRef: oldRef, PathsTime: &startTime})
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		if destinations := fanOutDestinations(expandedConfigs); len(destinations) > 0 {
+			err = cataloger.InsertExportRunDestinations(ctx, exportID, destinations)
+			if err != nil {
+				return oldRef, "", nil, err
+			}
+		}
+		counts := tasksGenerator.counts()
+		err = cataloger.SetExportRunCounts(ctx, exportID, counts.objectsCopied, counts.objectsDeleted, counts.bytesCopied)
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		return oldRef, catalog.ExportStatusInProgress, nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.ExportID = exportID
+	return report, nil
+}
+
+// listDestination returns the objects under the export path, keyed by their path relative to it
+func listDestination(adapter block.Adapter, destinations *Destinations, exportPath string) (map[string]block.ObjectInfo, error) {
+	adapter, prefix, err := destinations.resolve(adapter, strings.TrimRight(exportPath, "/")+"/")
+	if err != nil {
+		return nil, err
+	}
+	objects := make(map[string]block.ObjectInfo)
+	err = adapter.Walk(prefix, func(info block.ObjectInfo) error {
+		objects[info.Key] = info
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// entryDrift returns how the destination object of entry drifted from it.  Content is compared
+// only when both the entry checksum and the object ETag are plain MD5 digests, as multipart
+// uploads and some stores report other values.
+func entryDrift(entry *catalog.Entry, objects map[string]block.ObjectInfo) (DriftType, bool) {
+	obj, ok := objects[entry.Path]
+	if !ok {
+		return DriftTypeDeleted, true
+	}
+	if obj.Size != entry.Size {
+		return DriftTypeModified, true
+	}
+	if isPlainDigest(obj.ETag) && isPlainDigest(entry.Checksum) && !strings.EqualFold(obj.ETag, entry.Checksum) {
+		return DriftTypeModified, true
+	}
+	return "", false
+}
+
+func isPlainDigest(checksum string) bool {
+	return checksum != "" && !strings.Contains(checksum, "-")
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/mem"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestEntryDrift(t *testing.T) {
+	adapter := testutil.NewBlockAdapterByType(t, &block.NoOpTranslator{}, mem.BlockstoreType)
+	const exportPath = "mem://external-bucket/export"
+	put := func(path, data string) {
+		pointer, err := PathToPointer(path)
+		testutil.MustDo(t, "path to pointer", err)
+		reader := strings.NewReader(data)
+		testutil.MustDo(t, "put "+path, adapter.Put(pointer, reader.Size(), reader, block.PutOpts{}))
+	}
+	checksum := func(data string) string {
+		sum := md5.Sum([]byte(data)) //nolint:gosec
+		return hex.EncodeToString(sum[:])
+	}
+	put(exportPath+"/a/unchanged", "unchanged")
+	put(exportPath+"/a/resized", "resized out-of-band")
+	put(exportPath+"/b/rewritten", "REWRITTEN")
+	put(exportPath+"/b/multipart", "multipart")
+	// outside the export path
+	put("mem://external-bucket/other/deleted", "deleted")
+
+	objects, err := listDestination(adapter, nil, exportPath+"/")
+	testutil.MustDo(t, "list destination", err)
+	if len(objects) != 4 {
+		t.Fatalf("listDestination() objects=%v, expected 4", objects)
+	}
+
+	tests := []struct {
+		name      string
+		entry     catalog.Entry
+		wantDrift bool
+		wantType  DriftType
+	}{
+		{
+			name:  "unchanged",
+			entry: catalog.Entry{Path: "a/unchanged", Size: 9, Checksum: checksum("unchanged")},
+		},
+		{
+			name:      "resized",
+			entry:     catalog.Entry{Path: "a/resized", Size: 7, Checksum: checksum("resized")},
+			wantDrift: true,
+			wantType:  DriftTypeModified,
+		},
+		{
+			name:      "rewritten",
+			entry:     catalog.Entry{Path: "b/rewritten", Size: 9, Checksum: checksum("rewritten")},
+			wantDrift: true,
+			wantType:  DriftTypeModified,
+		},
+		{
+			name:  "multipart checksum",
+			entry: catalog.Entry{Path: "b/multipart", Size: 9, Checksum: "0123456789abcdef-2"},
+		},
+		{
+			name:      "deleted",
+			entry:     catalog.Entry{Path: "other/deleted", Size: 7, Checksum: checksum("deleted")},
+			wantDrift: true,
+			wantType:  DriftTypeDeleted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driftType, ok := entryDrift(&tt.entry, objects)
+			if ok != tt.wantDrift {
+				t.Fatalf("entryDrift() drift=%t, expected=%t", ok, tt.wantDrift)
+			}
+			if driftType != tt.wantType {
+				t.Fatalf("entryDrift() type=%s, expected=%s", driftType, tt.wantType)
+			}
+		})
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	nanoid "github.com/matoous/go-nanoid"
 
 	"github.com/treeverse/lakefs/parade"
 
 	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
 )
 
 func getExportID(repo, branch, commitRef string) (string, error) {
@@ -24,8 +26,8 @@ var ErrExportInProgress = errors.New("export currently in progress")
 
 // ExportBranchStart inserts a start task on branch, sets branch export state to pending.
 // It returns an error if an export is already in progress.
-func ExportBranchStart(paradeDB parade.Parade, cataloger catalog.Cataloger, repo, branch string) (string, error) {
-	commit, err := cataloger.GetCommit(context.Background(), repo, branch)
+func ExportBranchStart(ctx context.Context, paradeDB parade.Parade, cataloger catalog.Cataloger, repo, branch string) (string, error) {
+	commit, err := cataloger.GetCommit(ctx, repo, branch)
 	if err != nil {
 		return "", err
 	}
@@ -34,23 +36,37 @@ func ExportBranchStart(paradeDB parade.Parade, cataloger catalog.Cataloger, repo
 	if err != nil {
 		return "", err
 	}
-	err = cataloger.ExportStateSet(repo, branch, func(oldRef string, state catalog.CatalogBranchExportStatus) (newRef string, newState catalog.CatalogBranchExportStatus, newMessage *string, err error) {
+	err = cataloger.ExportStateSet(ctx, repo, branch, func(oldRef string, state catalog.CatalogBranchExportStatus) (newRef string, newState catalog.CatalogBranchExportStatus, newMessage *string, err error) {
 		if state == catalog.ExportStatusInProgress {
 			return oldRef, state, nil, ErrExportInProgress
 		}
 		if state == catalog.ExportStatusFailed {
 			return oldRef, state, nil, catalog.ErrExportFailed
 		}
-		config, err := cataloger.GetExportConfigurationForBranch(repo, branch)
+		configs, err := getExportConfigurations(ctx, cataloger, repo, branch)
 		if err != nil {
 			return oldRef, "", nil, err
 		}
-		tasks, err := GetStartTasks(repo, branch, oldRef, commitRef, exportID, config)
+		fromRef := lastExportedRef(oldRef, state)
+		startTime := time.Now()
+		tasks, err := getStartTasks(StartData{
+			Repo:          repo,
+			Branch:        branch,
+			FromCommitRef: fromRef,
+			ToCommitRef:   commitRef,
+			ExportID:      exportID,
+			ExportConfigs: expandPaths(configs, branch, commitRef, startTime),
+			StartTime:     startTime,
+		})
 		if err != nil {
 			return oldRef, "", nil, err
 		}
 
-		err = paradeDB.InsertTasks(context.Background(), tasks)
+		err = paradeDB.InsertTasks(ctx, tasks)
+		if err != nil {
+			return "", "", nil, err
+		}
+		err = cataloger.InsertExportRun(ctx, repo, branch, &catalog.ExportRun{ExportID: exportID, FromRef: fromRef, ToRef: commitRef, PathsTime: &startTime})
 		if err != nil {
 			return "", "", nil, err
 		}
@@ -59,11 +75,93 @@ func ExportBranchStart(paradeDB parade.Parade, cataloger catalog.Cataloger, repo
 	return exportID, err
 }
 
+// ExportRef inserts a start task exporting all entries of ref to destination, for a one-off
+// export of a tag or a commit.  The export is tracked by its own ref export state, apart from
+// the export state of branches, so it may run while branches export.
+func ExportRef(ctx context.Context, paradeDB parade.Parade, cataloger catalog.Cataloger, repo, ref, destination string) (string, error) {
+	if destination == "" {
+		return "", fmt.Errorf("export destination: %w", catalog.ErrInvalidValue)
+	}
+	commit, err := cataloger.GetCommit(ctx, repo, ref)
+	if err != nil {
+		return "", err
+	}
+	commitRef := commit.Reference
+	exportID, err := getExportID(repo, ref, commitRef)
+	if err != nil {
+		return "", err
+	}
+	tasks, err := getStartTasks(StartData{
+		Repo:          repo,
+		Branch:        ref,
+		ToCommitRef:   commitRef,
+		ExportID:      exportID,
+		ExportConfigs: []catalog.ExportConfiguration{{Path: destination, Mode: catalog.ExportModeFull}},
+		RefExport:     true,
+		StartTime:     time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+	// record the export before starting it, so that its end is always recorded
+	err = cataloger.InsertRefExport(ctx, repo, &catalog.RefExport{
+		ExportID:    exportID,
+		Ref:         ref,
+		CommitRef:   commitRef,
+		Destination: destination,
+	})
+	if err != nil {
+		return "", err
+	}
+	err = paradeDB.InsertTasks(ctx, tasks)
+	if err != nil {
+		msg := err.Error()
+		// record the failure even when ctx was cancelled
+		_ = cataloger.EndRefExport(context.Background(), exportID, catalog.ExportStatusFailed, &msg)
+		return "", err
+	}
+	return exportID, nil
+}
+
+var ErrNoExportConfiguration = fmt.Errorf("no export configuration: %w", db.ErrNotFound)
+
+// getExportConfigurations returns the export configurations of branch, failing if it has none
+func getExportConfigurations(ctx context.Context, cataloger catalog.Cataloger, repo, branch string) ([]catalog.ExportConfiguration, error) {
+	configs, err := cataloger.GetExportConfigurationsForBranch(ctx, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("%s: %w", branch, ErrNoExportConfiguration)
+	}
+	return configs, nil
+}
+
+// expandPaths returns configs with the placeholders of their paths expanded for an export
+// of commitRef on branch starting at startTime
+func expandPaths(configs []catalog.ExportConfiguration, branch, commitRef string, startTime time.Time) []catalog.ExportConfiguration {
+	ret := make([]catalog.ExportConfiguration, len(configs))
+	for i, config := range configs {
+		ret[i] = config.ExpandPaths(branch, commitRef, startTime)
+	}
+	return ret
+}
+
+// lastExportedRef returns the ref from which incremental export configurations export the
+// diff, given the current export state of the branch.  It returns an empty ref to export the
+// entire branch when the destinations were not successfully exported at oldRef.
+func lastExportedRef(oldRef string, state catalog.CatalogBranchExportStatus) string {
+	if state != catalog.ExportStatusSuccess && state != catalog.ExportStatusRepaired {
+		return ""
+	}
+	return oldRef
+}
+
 var ErrConflictingRefs = errors.New("conflicting references")
 
 // ExportBranchDone ends the export branch process by changing the status
-func ExportBranchDone(cataloger catalog.Cataloger, status catalog.CatalogBranchExportStatus, statusMsg *string, repo, branch, commitRef string) error {
-	err := cataloger.ExportStateSet(repo, branch, func(oldRef string, state catalog.CatalogBranchExportStatus) (newRef string, newState catalog.CatalogBranchExportStatus, newMessage *string, err error) {
+func ExportBranchDone(ctx context.Context, cataloger catalog.Cataloger, status catalog.CatalogBranchExportStatus, statusMsg *string, repo, branch, commitRef string) error {
+	err := cataloger.ExportStateSet(ctx, repo, branch, func(oldRef string, state catalog.CatalogBranchExportStatus) (newRef string, newState catalog.CatalogBranchExportStatus, newMessage *string, err error) {
 		if commitRef != oldRef {
 			return "", "", nil, fmt.Errorf("ExportBranchDone: currentRef:%s, newRef:%s: %w", oldRef, commitRef, ErrConflictingRefs)
 		}
@@ -71,16 +169,3 @@ func ExportBranchDone(cataloger catalog.Cataloger, status catalog.CatalogBranchE
 	})
 	return err
 }
-
-var ErrRepairWrongStatus = errors.New("incorrect status")
-
-// ExportBranchRepair changes state from Failed To Repair and starts a new export.
-// It fails if the current state is not ExportStatusFailed.
-func ExportBranchRepair(cataloger catalog.Cataloger, repo, branch string) error {
-	return cataloger.ExportStateSet(repo, branch, func(oldRef string, state catalog.CatalogBranchExportStatus) (newRef string, newState catalog.CatalogBranchExportStatus, newMessage *string, err error) {
-		if state != catalog.ExportStatusFailed {
-			return oldRef, "", nil, ErrRepairWrongStatus
-		}
-		return oldRef, catalog.ExportStatusRepaired, nil, nil
-	})
-}
@@ -1,34 +1,55 @@
 package export
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/treeverse/lakefs/catalog"
 
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/notifications"
 	"github.com/treeverse/lakefs/parade"
 )
 
 const actorName parade.ActorID = "EXPORT"
 
+// statBatchSize is the number of entries the planner reads in a single query
+const statBatchSize = 1000
+
+var ErrMissingEntry = errors.New("entry missing on exported commit")
+
+// manifestSuffix is appended to the status object name to name the manifest of an export
+const manifestSuffix = ".manifest.csv"
+
+var manifestHeader = []string{"key", "size", "checksum"}
+
 type Handler struct {
-	adapter   block.Adapter
-	cataloger catalog.Cataloger
-	parade    parade.Parade
+	adapter      block.Adapter
+	destinations *Destinations
+	cataloger    catalog.Cataloger
+	parade       parade.Parade
+	notifier     *notifications.Notifier
 }
 
-func NewHandler(adapter block.Adapter, cataloger catalog.Cataloger, parade parade.Parade) *Handler {
+// NewHandler returns a handler that reads exported objects with adapter and writes them to
+// destinations, nil destinations write everything with adapter
+func NewHandler(adapter block.Adapter, destinations *Destinations, cataloger catalog.Cataloger, parade parade.Parade, notifier *notifications.Notifier) *Handler {
 	return &Handler{
-		adapter:   adapter,
-		cataloger: cataloger,
-		parade:    parade,
+		adapter:      adapter,
+		destinations: destinations,
+		cataloger:    cataloger,
+		parade:       parade,
+		notifier:     notifier,
 	}
 }
 
@@ -44,76 +65,119 @@ func PathToPointer(path string) (block.ObjectPointer, error) {
 	if err != nil {
 		return block.ObjectPointer{}, err
 	}
+	host := u.Host
+	if u.User != nil {
+		// the container of wasb:// URLs
+		host = u.User.String() + "@" + host
+	}
 	return block.ObjectPointer{
-		StorageNamespace: fmt.Sprintf("%s://%s/", u.Scheme, u.Host),
+		StorageNamespace: fmt.Sprintf("%s://%s/", u.Scheme, host),
 		Identifier:       strings.TrimPrefix(u.Path, "/"),
 	}, err
 }
 
-func (h *Handler) start(body *string) error {
+func (h *Handler) start(ctx context.Context, body *string) error {
 	var startData StartData
 	err := json.Unmarshal([]byte(*body), &startData)
 	if err != nil {
 		return err
 	}
 
-	finishBodyStr, err := getFinishBodyString(startData.Repo, startData.Branch, startData.ToCommitRef, startData.ExportConfig.StatusPath)
+	finishData := newFinishData(startData.ExportID, startData.Repo, startData.Branch, startData.ToCommitRef, startData.ExportConfigs)
+	finishData.RefExport = startData.RefExport
+	finishData.StartTime = startData.StartTime
+	finishBody, err := json.Marshal(finishData)
+	if err != nil {
+		return err
+	}
+	finishBodyStr := string(finishBody)
+	repo, err := h.cataloger.GetRepository(ctx, startData.Repo)
 	if err != nil {
 		return err
 	}
-	repo, err := h.cataloger.GetRepository(context.Background(), startData.Repo)
+	if destinations := fanOutDestinations(startData.ExportConfigs); !startData.RefExport && len(destinations) > 0 {
+		// recorded before generating the tasks that end them
+		err = h.cataloger.InsertExportRunDestinations(ctx, startData.ExportID, destinations)
+		if err != nil {
+			logging.Default().WithError(err).WithField("export_id", startData.ExportID).Warn("failed to record export run destinations")
+		}
+	}
+	counts, err := h.generateTasks(ctx, startData, &finishBodyStr, repo.StorageNamespace)
 	if err != nil {
 		return err
 	}
-	return h.generateTasks(startData, startData.ExportConfig, &finishBodyStr, repo.StorageNamespace)
+	if startData.RefExport {
+		// ref exports have no run history
+		return nil
+	}
+	err = h.cataloger.SetExportRunCounts(ctx, startData.ExportID, counts.objectsCopied, counts.objectsDeleted, counts.bytesCopied)
+	if err != nil {
+		// run history is informational, it never fails an export
+		logging.Default().WithError(err).WithField("export_id", startData.ExportID).Warn("failed to record export run counts")
+	}
+	return nil
 }
 
-func (h *Handler) generateTasks(startData StartData, config catalog.ExportConfiguration, finishBodyStr *string, storageNamespace string) error {
-	tasksGenerator := NewTasksGenerator(startData.ExportID, config.Path, getGenerateSuccess(config.LastKeysInPrefixRegexp), finishBodyStr, storageNamespace)
+// generateTasks inserts all tasks of the export of startData, returning their counts
+func (h *Handler) generateTasks(ctx context.Context, startData StartData, finishBodyStr *string, storageNamespace string) (exportCounts, error) {
+	tasksGenerator := NewMultiTasksGenerator(startData.ExportID, startData.Repo, startData.FromCommitRef, startData.ToCommitRef, startData.ExportConfigs, finishBodyStr, storageNamespace)
+	for _, fromRef := range tasksGenerator.FromRefs() {
+		err := forEachExportDiff(ctx, h.cataloger, startData.Repo, fromRef, startData.ToCommitRef, func(diffs catalog.Differences) error {
+			taskData, err := tasksGenerator.AddDiffFrom(fromRef, diffs)
+			if err != nil {
+				return err
+			}
+			// add taskData tasks
+			return h.parade.InsertTasks(ctx, taskData)
+		})
+		if err != nil {
+			return exportCounts{}, err
+		}
+	}
+
+	taskData, err := tasksGenerator.Finish()
+	if err != nil {
+		return exportCounts{}, err
+	}
+	return tasksGenerator.counts(), h.parade.InsertTasks(ctx, taskData)
+}
+
+// forEachExportDiff calls cb with batches of the differences to export toRef, which was
+// previously exported at fromRef.  All entries of toRef are differences if fromRef is empty.
+func forEachExportDiff(ctx context.Context, cataloger catalog.Cataloger, repo, fromRef, toRef string, cb func(diffs catalog.Differences) error) error {
 	var diffs catalog.Differences
 	var err error
 	var hasMore bool
 	after := ""
 	limit := -1
-	diffFromBase := startData.FromCommitRef == ""
+	diffFromBase := fromRef == ""
 	for {
 		if diffFromBase {
-			diffs, hasMore, err = getDiffFromBase(context.Background(), startData.Repo, startData.ToCommitRef, after, limit, h.cataloger)
+			diffs, hasMore, err = getDiffFromBase(ctx, repo, toRef, after, limit, cataloger)
 		} else {
 			// Todo(guys) change this to work with diff iterator once it is available outside of cataloger
-			diffs, hasMore, err = h.cataloger.Diff(context.Background(), startData.Repo, startData.ToCommitRef, startData.FromCommitRef, catalog.DiffParams{
-				Limit:            limit,
-				After:            after,
-				AdditionalFields: []string{"physical_address"},
+			diffs, hasMore, err = cataloger.Diff(ctx, repo, toRef, fromRef, catalog.DiffParams{
+				Limit: limit,
+				After: after,
 			})
+			if err == nil {
+				err = resolveDiffEntries(ctx, cataloger, repo, toRef, diffs)
+			}
 		}
 		if err != nil {
 			return err
 		}
 		if len(diffs) == 0 {
-			break
+			return nil
 		}
-		taskData, err := tasksGenerator.Add(diffs)
-		if err != nil {
+		if err := cb(diffs); err != nil {
 			return err
 		}
-		// add taskData tasks
-		err = h.parade.InsertTasks(context.Background(), taskData)
-		if err != nil {
-			return err
-		}
-
 		if !hasMore {
-			break
+			return nil
 		}
 		after = diffs[len(diffs)-1].Path
 	}
-
-	taskData, err := tasksGenerator.Finish()
-	if err != nil {
-		return err
-	}
-	return h.parade.InsertTasks(context.Background(), taskData)
 }
 
 // getDiffFromBase returns all the entries on the ref as diffs
@@ -125,6 +189,44 @@ func getDiffFromBase(ctx context.Context, repo, ref, after string, limit int, ca
 	return entriesToDiff(entries), hasMore, nil
 }
 
+// resolveDiffEntries reads the entries of the added and changed differences on ref, in
+// batches of statBatchSize, as differences hold only the fields scanned by the diff
+func resolveDiffEntries(ctx context.Context, cataloger catalog.Cataloger, repo, ref string, diffs catalog.Differences) error {
+	indexes := make([]int, 0, statBatchSize)
+	refs := make([]catalog.EntryRef, 0, statBatchSize)
+	resolve := func() error {
+		entries, err := cataloger.GetEntries(ctx, repo, refs, catalog.GetEntryParams{ReturnExpired: true})
+		if err != nil {
+			return err
+		}
+		for i, entry := range entries {
+			if entry == nil {
+				return fmt.Errorf("%s: %w", refs[i].Path, ErrMissingEntry)
+			}
+			diffs[indexes[i]].Entry = *entry
+		}
+		indexes = indexes[:0]
+		refs = refs[:0]
+		return nil
+	}
+	for i, diff := range diffs {
+		if diff.Type != catalog.DifferenceTypeAdded && diff.Type != catalog.DifferenceTypeChanged {
+			continue
+		}
+		indexes = append(indexes, i)
+		refs = append(refs, catalog.EntryRef{Reference: ref, Path: diff.Path})
+		if len(refs) == statBatchSize {
+			if err := resolve(); err != nil {
+				return err
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+	return resolve()
+}
+
 func entriesToDiff(entries []*catalog.Entry) []catalog.Difference {
 	res := make([]catalog.Difference, len(entries))
 	for i, entry := range entries {
@@ -155,13 +257,10 @@ func getGenerateSuccess(lastKeysInPrefixRegexp []string) func(path string) bool
 	}
 }
 
-func getFinishBodyString(repo, branch, commitRef, statusPath string) (string, error) {
-	finishData := FinishData{
-		Repo:       repo,
-		Branch:     branch,
-		CommitRef:  commitRef,
-		StatusPath: statusPath,
-	}
+// getFinishBodyString returns the body of the finish task of an export starting now
+func getFinishBodyString(exportID, repo, branch, commitRef string, configs []catalog.ExportConfiguration) (string, error) {
+	finishData := newFinishData(exportID, repo, branch, commitRef, configs)
+	finishData.StartTime = time.Now()
 	finisBody, err := json.Marshal(finishData)
 	if err != nil {
 		return "", err
@@ -169,6 +268,29 @@ func getFinishBodyString(repo, branch, commitRef, statusPath string) (string, er
 	return string(finisBody), nil
 }
 
+func newFinishData(exportID, repo, branch, commitRef string, configs []catalog.ExportConfiguration) FinishData {
+	finishData := FinishData{
+		ExportID:  exportID,
+		Repo:      repo,
+		Branch:    branch,
+		CommitRef: commitRef,
+		Statuses:  make([]FinishStatus, 0, len(configs)),
+	}
+	for _, config := range configs {
+		status := FinishStatus{
+			StatusPath:      config.StatusPath,
+			Prefix:          config.Prefix,
+			IncludePrefixes: config.IncludePrefixes,
+			ExcludeGlobs:    config.ExcludeGlobs,
+		}
+		if config.WriteManifest {
+			status.ManifestExportPath = config.Path
+		}
+		finishData.Statuses = append(finishData.Statuses, status)
+	}
+	return finishData
+}
+
 func (h *Handler) copy(body *string) error {
 	var copyData CopyData
 	err := json.Unmarshal([]byte(*body), &copyData)
@@ -179,11 +301,43 @@ func (h *Handler) copy(body *string) error {
 	if err != nil {
 		return err
 	}
-	to, err := PathToPointer(copyData.To)
+	adapter, to, err := h.destinations.resolve(h.adapter, copyData.To)
+	if err != nil {
+		return err
+	}
+	if copyData.Repository != "" {
+		if err := h.cataloger.WaitExportBandwidth(context.Background(), copyData.Repository, copyData.Size); err != nil {
+			return fmt.Errorf("wait for export bandwidth: %w", err)
+		}
+	}
+	opts := copyData.copyOpts()
+	if adapter == h.adapter {
+		err = h.adapter.Copy(from, to, opts)
+	} else {
+		err = copyBetween(h.adapter, from, adapter, to, copyData.Size, opts)
+	}
+	if err != nil {
+		return err
+	}
+	objectsCopiedCounter.Inc()
+	bytesCopiedCounter.Add(float64(copyData.Size))
+	return nil
+}
+
+// copyBetween copies an object of size bytes between storages through lakeFS, setting the
+// content type and metadata of opts
+func copyBetween(fromAdapter block.Adapter, from block.ObjectPointer, toAdapter block.Adapter, to block.ObjectPointer, size int64, opts block.CopyOpts) error {
+	reader, err := fromAdapter.Get(from, size)
 	if err != nil {
 		return err
 	}
-	return h.adapter.Copy(from, to)
+	defer func() { _ = reader.Close() }()
+	return toAdapter.Put(to, size, reader, block.PutOpts{
+		ContentType:          opts.ContentType,
+		Metadata:             opts.Metadata,
+		ServerSideEncryption: opts.ServerSideEncryption,
+		SSEKMSKeyID:          opts.SSEKMSKeyID,
+	})
 }
 
 func (h *Handler) remove(body *string) error {
@@ -192,11 +346,15 @@ func (h *Handler) remove(body *string) error {
 	if err != nil {
 		return err
 	}
-	path, err := PathToPointer(deleteData.File)
+	adapter, path, err := h.destinations.resolve(h.adapter, deleteData.File)
 	if err != nil {
 		return err
 	}
-	return h.adapter.Remove(path)
+	if err := adapter.Remove(path); err != nil {
+		return err
+	}
+	objectsDeletedCounter.Inc()
+	return nil
 }
 
 func (h *Handler) touch(body *string) error {
@@ -205,11 +363,11 @@ func (h *Handler) touch(body *string) error {
 	if err != nil {
 		return err
 	}
-	path, err := PathToPointer(successData.File)
+	adapter, path, err := h.destinations.resolve(h.adapter, successData.File)
 	if err != nil {
 		return err
 	}
-	return h.adapter.Put(path, 0, strings.NewReader(""), block.PutOpts{})
+	return adapter.Put(path, 0, strings.NewReader(""), block.PutOpts{})
 }
 
 func getStatus(signalledErrors int) (catalog.CatalogBranchExportStatus, *string) {
@@ -220,54 +378,147 @@ func getStatus(signalledErrors int) (catalog.CatalogBranchExportStatus, *string)
 	return catalog.ExportStatusSuccess, nil
 }
 
-func (h *Handler) updateStatus(finishData FinishData, status catalog.CatalogBranchExportStatus, signalledErrors int) error {
-	if finishData.StatusPath == "" {
+func (h *Handler) updateStatus(finishData FinishData, finishStatus FinishStatus, status catalog.CatalogBranchExportStatus, signalledErrors int) error {
+	if finishStatus.StatusPath == "" {
 		return nil
 	}
 	fileName := fmt.Sprintf("%s-%s-%s", finishData.Repo, finishData.Branch, finishData.CommitRef)
-	path, err := PathToPointer(fmt.Sprintf("%s/%s", finishData.StatusPath, fileName))
+	adapter, path, err := h.destinations.resolve(h.adapter, fmt.Sprintf("%s/%s", finishStatus.StatusPath, fileName))
 	if err != nil {
 		return err
 	}
 	data := fmt.Sprintf("status: %s, signalled_errors: %d\n", status, signalledErrors)
 	reader := strings.NewReader(data)
-	return h.adapter.Put(path, reader.Size(), reader, block.PutOpts{})
+	return adapter.Put(path, reader.Size(), reader, block.PutOpts{})
+}
+
+// writeManifest writes a CSV manifest of the objects exported by the configuration of
+// finishStatus at the commit of finishData next to its status object, with the key, size and
+// checksum of each object
+func (h *Handler) writeManifest(ctx context.Context, finishData FinishData, finishStatus FinishStatus) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(manifestHeader); err != nil {
+		return err
+	}
+	exportPath := strings.TrimRight(finishStatus.ManifestExportPath, "/")
+	filter := pathFilter{
+		prefix:          finishStatus.Prefix,
+		includePrefixes: finishStatus.IncludePrefixes,
+		excludeGlobs:    finishStatus.ExcludeGlobs,
+	}
+	it, err := h.cataloger.ListEntriesIterator(ctx, finishData.Repo, finishData.CommitRef, finishStatus.Prefix)
+	if err != nil {
+		return err
+	}
+	for it.Next() {
+		entry := it.Value()
+		if !filter.matches(entry.Path) {
+			continue
+		}
+		record := []string{exportPath + "/" + entry.Path, strconv.FormatInt(entry.Size, 10), entry.Checksum}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	fileName := fmt.Sprintf("%s-%s-%s%s", finishData.Repo, finishData.Branch, finishData.CommitRef, manifestSuffix)
+	adapter, path, err := h.destinations.resolve(h.adapter, fmt.Sprintf("%s/%s", finishStatus.StatusPath, fileName))
+	if err != nil {
+		return err
+	}
+	return adapter.Put(path, int64(buf.Len()), &buf, block.PutOpts{})
 }
 
-func (h *Handler) done(body *string, signalledErrors int) error {
+func (h *Handler) done(ctx context.Context, body *string, signalledErrors int) error {
 	var finishData FinishData
 	err := json.Unmarshal([]byte(*body), &finishData)
 	if err != nil {
 		return err
 	}
 	status, msg := getStatus(signalledErrors)
-	err = h.updateStatus(finishData, status, signalledErrors)
+	for _, finishStatus := range finishData.Statuses {
+		if status != catalog.ExportStatusSuccess || finishStatus.ManifestExportPath == "" || finishStatus.StatusPath == "" {
+			continue
+		}
+		// manifests are written before the statuses, so readers of a successful status
+		// find its manifest.  Without a manifest the export fails.
+		if err := h.writeManifest(ctx, finishData, finishStatus); err != nil {
+			status = catalog.ExportStatusFailed
+			failure := fmt.Sprintf("write export manifest: %s\n", err)
+			msg = &failure
+		}
+	}
+	for _, finishStatus := range finishData.Statuses {
+		err = h.updateStatus(finishData, finishStatus, status, signalledErrors)
+		if err != nil {
+			return err
+		}
+	}
+	if !finishData.StartTime.IsZero() {
+		exportDurationHistograms.WithLabelValues(string(status)).Observe(time.Since(finishData.StartTime).Seconds())
+	}
+	if status == catalog.ExportStatusFailed {
+		h.notifier.Notify(&notifications.Notification{
+			Type:       notifications.EventExportFailed,
+			Repository: finishData.Repo,
+			Branch:     finishData.Branch,
+			Subject:    fmt.Sprintf("export of %s@%s failed", finishData.Repo, finishData.Branch),
+			Body:       fmt.Sprintf("Export of commit %s failed: %s", finishData.CommitRef, *msg),
+		})
+	}
+	if finishData.RefExport {
+		return h.cataloger.EndRefExport(ctx, finishData.ExportID, status, msg)
+	}
+	if finishData.Repair && status == catalog.ExportStatusSuccess {
+		// status objects still report success, only the export state records the repair
+		status = catalog.ExportStatusRepaired
+	}
+	err = ExportBranchDone(ctx, h.cataloger, status, msg, finishData.Repo, finishData.Branch, finishData.CommitRef)
 	if err != nil {
 		return err
 	}
-	return ExportBranchDone(h.cataloger, status, msg, finishData.Repo, finishData.Branch, finishData.CommitRef)
+	if finishData.ExportID != "" {
+		if err := h.cataloger.EndExportRun(ctx, finishData.ExportID, status, msg); err != nil {
+			logging.Default().WithError(err).WithField("export_id", finishData.ExportID).Warn("failed to record export run end")
+		}
+	}
+	return nil
 }
 
 var errUnknownAction = errors.New("unknown action")
 
 func (h *Handler) Handle(action string, body *string, signalledErrors int) parade.ActorResult {
+	// parade passes no context to actors, tasks run until they end
+	ctx := context.Background()
 	var err error
 	switch action {
 	case StartAction:
-		err = h.start(body)
+		err = h.start(ctx, body)
 	case CopyAction:
 		err = h.copy(body)
 	case DeleteAction:
 		err = h.remove(body)
 	case TouchAction:
 		err = h.touch(body)
+	case SymlinkAction:
+		err = h.symlink(ctx, body)
+	case DestinationDoneAction:
+		err = h.destinationDone(ctx, body, signalledErrors)
 	case DoneAction:
-		err = h.done(body, signalledErrors)
+		err = h.done(ctx, body, signalledErrors)
 	default:
 		err = errUnknownAction
 	}
 
 	if err != nil {
+		taskFailuresCounter.WithLabelValues(action).Inc()
 		logging.Default().WithFields(logging.Fields{
 			"actor":  actorName,
 			"action": action,
@@ -285,7 +536,7 @@ func (h *Handler) Handle(action string, body *string, signalledErrors int) parad
 }
 
 func (h *Handler) Actions() []string {
-	return []string{StartAction, CopyAction, DeleteAction, TouchAction, DoneAction}
+	return []string{StartAction, CopyAction, DeleteAction, TouchAction, SymlinkAction, DestinationDoneAction, DoneAction}
 }
 
 func (h *Handler) ActorID() parade.ActorID {
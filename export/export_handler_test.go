@@ -1,14 +1,19 @@
 package export
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/go-openapi/swag"
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/block/mem"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/parade"
 	"github.com/treeverse/lakefs/testutil"
 )
@@ -33,10 +38,14 @@ func TestCopy(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	h := NewHandler(adapter, nil, nil)
+	h := NewHandler(adapter, nil, nil, nil, nil)
 	taskBody, err := json.Marshal(&CopyData{
-		From: from,
-		To:   to,
+		From:         from,
+		To:           to,
+		ContentType:  "text/csv",
+		Metadata:     map[string]string{"owner": "etl"},
+		SSEAlgorithm: catalog.ExportSSEAlgorithmKMS,
+		SSEKMSKeyID:  "key",
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -64,6 +73,88 @@ func TestCopy(t *testing.T) {
 	if string(val) != expect {
 		t.Errorf("expected %s, got %s\n", testData, string(val))
 	}
+	props, err := adapter.GetProperties(destinationPointer)
+	testutil.Must(t, err)
+	if props.ContentType == nil || *props.ContentType != "text/csv" || !reflect.DeepEqual(props.Metadata, map[string]string{"owner": "etl"}) {
+		t.Errorf("copied object content type %v and metadata %v, expected those of the entry", props.ContentType, props.Metadata)
+	}
+	if swag.StringValue(props.ServerSideEncryption) != catalog.ExportSSEAlgorithmKMS || swag.StringValue(props.SSEKMSKeyID) != "key" {
+		t.Errorf("copied object encrypted with %v key %v, expected %s key key", props.ServerSideEncryption, props.SSEKMSKeyID, catalog.ExportSSEAlgorithmKMS)
+	}
+}
+
+func TestCopyToDestination(t *testing.T) {
+	adapter := testutil.NewBlockAdapterByType(t, &block.NoOpTranslator{}, mem.BlockstoreType)
+	destinationAdapter := mem.New()
+	destinations := NewDestinations()
+	destinations.Register(SchemeGS, func() (block.Adapter, error) { return destinationAdapter, nil })
+
+	sourcePointer := block.ObjectPointer{
+		StorageNamespace: "mem://lakeFS-bucket/",
+		Identifier:       "one/two",
+	}
+	destinationPointer := block.ObjectPointer{
+		StorageNamespace: "gs://external-bucket/",
+		Identifier:       "one/two",
+	}
+	testData := "this is the test Data"
+	testReader := strings.NewReader(testData)
+	testutil.Must(t, adapter.Put(sourcePointer, testReader.Size(), testReader, block.PutOpts{}))
+
+	h := NewHandler(adapter, destinations, nil, nil, nil)
+	taskBody, err := json.Marshal(&CopyData{
+		From:        sourcePointer.StorageNamespace + sourcePointer.Identifier,
+		To:          destinationPointer.StorageNamespace + destinationPointer.Identifier,
+		Size:        testReader.Size(),
+		ContentType: "text/csv",
+	})
+	testutil.Must(t, err)
+	taskBodyStr := string(taskBody)
+	if res := h.Handle(CopyAction, &taskBodyStr, 0); res.StatusCode != parade.TaskCompleted {
+		t.Fatalf("expected status code: %s, got: %s (%s)", parade.TaskCompleted, res.StatusCode, res.Status)
+	}
+	if _, err := adapter.Get(destinationPointer, testReader.Size()); err == nil {
+		t.Error("expected the blockstore adapter not to hold the destination object")
+	}
+	reader, err := destinationAdapter.Get(destinationPointer, testReader.Size())
+	testutil.Must(t, err)
+	val, err := ioutil.ReadAll(reader)
+	testutil.Must(t, err)
+	if string(val) != testData {
+		t.Errorf("expected %s, got %s", testData, string(val))
+	}
+	props, err := destinationAdapter.GetProperties(destinationPointer)
+	testutil.Must(t, err)
+	if props.ContentType == nil || *props.ContentType != "text/csv" {
+		t.Errorf("copied object content type %v, expected text/csv", props.ContentType)
+	}
+}
+
+func TestValidateDestination(t *testing.T) {
+	for _, path := range []string{
+		"s3://bucket/export",
+		"gs://bucket/export",
+		"https://account.blob.core.windows.net/container/export",
+		"wasbs://container@account.blob.core.windows.net/export",
+	} {
+		if err := ValidateDestination(path); err != nil {
+			t.Errorf("ValidateDestination(%s) unexpected error: %s", path, err)
+		}
+	}
+	for _, path := range []string{"ftp://host/export", "bucket/export", "https://example.com/export", "wasb://account.blob.core.windows.net/export"} {
+		if err := ValidateDestination(path); !errors.Is(err, ErrUnsupportedDestination) {
+			t.Errorf("ValidateDestination(%s) err=%v, expected %s", path, err, ErrUnsupportedDestination)
+		}
+	}
+}
+
+func TestPathToPointer(t *testing.T) {
+	pointer, err := PathToPointer("wasb://container@account.blob.core.windows.net/path/to/obj")
+	testutil.Must(t, err)
+	want := block.ObjectPointer{StorageNamespace: "wasb://container@account.blob.core.windows.net/", Identifier: "path/to/obj"}
+	if pointer != want {
+		t.Fatalf("PathToPointer() got %+v, expected %+v", pointer, want)
+	}
 }
 
 func TestDelete(t *testing.T) {
@@ -82,7 +173,7 @@ func TestDelete(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	h := NewHandler(adapter, nil, nil)
+	h := NewHandler(adapter, nil, nil, nil, nil)
 	taskBody, err := json.Marshal(&DeleteData{
 		File: path,
 	})
@@ -120,7 +211,7 @@ func TestTouch(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	h := NewHandler(adapter, nil, nil)
+	h := NewHandler(adapter, nil, nil, nil, nil)
 	taskBody, err := json.Marshal(&SuccessData{
 		File: path,
 	})
@@ -193,3 +284,160 @@ func Test_getGenerateSuccess(t *testing.T) {
 		})
 	}
 }
+
+// entriesCataloger is a cataloger holding entries of a single ref, that counts GetEntries calls
+type entriesCataloger struct {
+	catalog.Cataloger
+	entries map[string]catalog.Entry
+	calls   int
+}
+
+func (c *entriesCataloger) GetEntries(_ context.Context, _ string, refs []catalog.EntryRef, _ catalog.GetEntryParams) ([]*catalog.Entry, error) {
+	c.calls++
+	entries := make([]*catalog.Entry, len(refs))
+	for i, ref := range refs {
+		if entry, ok := c.entries[ref.Path]; ok {
+			entries[i] = &entry
+		}
+	}
+	return entries, nil
+}
+
+func Test_resolveDiffEntries(t *testing.T) {
+	c := &entriesCataloger{entries: make(map[string]catalog.Entry)}
+	var diffs catalog.Differences
+	const diffsCount = statBatchSize + 10
+	for i := 0; i < diffsCount; i++ {
+		path := fmt.Sprintf("path/%05d", i)
+		diffType := catalog.DifferenceTypeChanged
+		if i%2 == 0 {
+			diffType = catalog.DifferenceTypeRemoved
+		} else {
+			c.entries[path] = catalog.Entry{Path: path, PhysicalAddress: "addr/" + path, Size: int64(i)}
+		}
+		diffs = append(diffs, catalog.Difference{Entry: catalog.Entry{Path: path}, Type: diffType})
+	}
+	testutil.Must(t, resolveDiffEntries(context.Background(), c, "repo", "commit", diffs))
+	if c.calls != 1 {
+		t.Fatalf("resolved changed entries with %d calls, expected a single batch", c.calls)
+	}
+	for i, diff := range diffs {
+		wantAddress := ""
+		if diff.Type != catalog.DifferenceTypeRemoved {
+			wantAddress = "addr/" + diff.Path
+		}
+		if diff.PhysicalAddress != wantAddress {
+			t.Errorf("diff %d %s physical address %s, expected %s", i, diff.Path, diff.PhysicalAddress, wantAddress)
+		}
+	}
+
+	delete(c.entries, diffs[1].Path)
+	err := resolveDiffEntries(context.Background(), c, "repo", "commit", diffs)
+	if !errors.Is(err, ErrMissingEntry) {
+		t.Fatalf("resolveDiffEntries() err=%v, expected %s", err, ErrMissingEntry)
+	}
+}
+
+// doneCataloger is a cataloger of a single commit, that records export state updates and
+// ended export runs
+type doneCataloger struct {
+	catalog.Cataloger
+	entries  []*catalog.Entry
+	state    catalog.CatalogBranchExportStatus
+	runState map[string]catalog.CatalogBranchExportStatus
+}
+
+func (c *doneCataloger) ListEntriesIterator(_ context.Context, _, _, _ string) (catalog.EntryIterator, error) {
+	return &sliceEntryIterator{entries: c.entries}, nil
+}
+
+// sliceEntryIterator iterates over entries
+type sliceEntryIterator struct {
+	entries []*catalog.Entry
+	value   *catalog.Entry
+}
+
+func (it *sliceEntryIterator) Next() bool {
+	if len(it.entries) == 0 {
+		it.value = nil
+		return false
+	}
+	it.value, it.entries = it.entries[0], it.entries[1:]
+	return true
+}
+
+func (it *sliceEntryIterator) Value() *catalog.Entry {
+	return it.value
+}
+
+func (it *sliceEntryIterator) Err() error {
+	return nil
+}
+
+func (c *doneCataloger) ExportStateSet(_ context.Context, _, _ string, cb catalog.ExportStateCallback) error {
+	_, state, _, err := cb("commit1", catalog.ExportStatusInProgress)
+	c.state = state
+	return err
+}
+
+func (c *doneCataloger) EndExportRun(_ context.Context, exportID string, status catalog.CatalogBranchExportStatus, _ *string) error {
+	c.runState[exportID] = status
+	return nil
+}
+
+func TestDoneWritesManifest(t *testing.T) {
+	adapter := testutil.NewBlockAdapterByType(t, &block.NoOpTranslator{}, mem.BlockstoreType)
+	c := &doneCataloger{entries: []*catalog.Entry{
+		{Path: "a/one", Size: 3, Checksum: "c1"},
+		{Path: "b,two", Size: 5, Checksum: "c2"},
+	}, runState: make(map[string]catalog.CatalogBranchExportStatus)}
+	h := NewHandler(adapter, nil, c, nil, nil)
+	finishBody, err := getFinishBodyString("export1", "repo", "master", "commit1", []catalog.ExportConfiguration{{
+		Path:          "mem://external-bucket/export/",
+		StatusPath:    "mem://external-bucket/status",
+		WriteManifest: true,
+	}})
+	testutil.Must(t, err)
+	if res := h.Handle(DoneAction, &finishBody, 0); res.StatusCode != parade.TaskCompleted {
+		t.Fatalf("expected status code: %s, got: %s (%s)", parade.TaskCompleted, res.StatusCode, res.Status)
+	}
+	if c.state != catalog.ExportStatusSuccess {
+		t.Errorf("export state %s, expected %s", c.state, catalog.ExportStatusSuccess)
+	}
+	if c.runState["export1"] != catalog.ExportStatusSuccess {
+		t.Errorf("export run state %s, expected %s", c.runState["export1"], catalog.ExportStatusSuccess)
+	}
+
+	reader, err := adapter.Get(block.ObjectPointer{
+		StorageNamespace: "mem://external-bucket/",
+		Identifier:       "status/repo-master-commit1" + manifestSuffix,
+	}, 0)
+	testutil.Must(t, err)
+	manifest, err := ioutil.ReadAll(reader)
+	testutil.Must(t, err)
+	expected := `key,size,checksum
+mem://external-bucket/export/a/one,3,c1
+"mem://external-bucket/export/b,two",5,c2
+`
+	if string(manifest) != expected {
+		t.Errorf("got manifest:\n%s\nexpected:\n%s", manifest, expected)
+	}
+}
+
+func TestMultiTasksGenerator_Counts(t *testing.T) {
+	configs := []catalog.ExportConfiguration{
+		{Path: "mem://export/all", PropagateDeletes: true},
+		{Prefix: "a/", Path: "mem://export/a", PropagateDeletes: true},
+	}
+	gen := NewMultiTasksGenerator("counts", "repo", "commit1", "commit2", configs, nil, "mem://storage")
+	_, err := gen.Add(catalog.Differences{
+		{Type: catalog.DifferenceTypeAdded, Entry: catalog.Entry{Path: "a/1", PhysicalAddress: "a1", Size: 3}},
+		{Type: catalog.DifferenceTypeChanged, Entry: catalog.Entry{Path: "b/1", PhysicalAddress: "b1", Size: 5}},
+		{Type: catalog.DifferenceTypeRemoved, Entry: catalog.Entry{Path: "a/2"}},
+	})
+	testutil.Must(t, err)
+	expected := exportCounts{objectsCopied: 3, objectsDeleted: 2, bytesCopied: 11}
+	if counts := gen.counts(); counts != expected {
+		t.Errorf("got counts %+v, expected %+v", counts, expected)
+	}
+}
@@ -0,0 +1,185 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/parade"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestExportFromRef(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   string
+		oldRef string
+		state  catalog.CatalogBranchExportStatus
+		want   string
+	}{
+		{name: "first export", mode: catalog.ExportModeIncremental},
+		{name: "incremental after success", mode: catalog.ExportModeIncremental, oldRef: "commit1", state: catalog.ExportStatusSuccess, want: "commit1"},
+		{name: "incremental after repair", mode: catalog.ExportModeIncremental, oldRef: "commit1", state: catalog.ExportStatusRepaired, want: "commit1"},
+		{name: "incremental after unknown state", mode: catalog.ExportModeIncremental, oldRef: "commit1", state: catalog.ExportStatusUnknown},
+		{name: "default mode after success", oldRef: "commit1", state: catalog.ExportStatusSuccess, want: "commit1"},
+		{name: "full after success", mode: catalog.ExportModeFull, oldRef: "commit1", state: catalog.ExportStatusSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs := []catalog.ExportConfiguration{{Path: "s3://export/path", Mode: tt.mode}}
+			generator := NewMultiTasksGenerator("export", "repo", lastExportedRef(tt.oldRef, tt.state), "commit2", configs, nil, "s3://storage")
+			if got := generator.FromRefs(); len(got) != 1 || got[0] != tt.want {
+				t.Errorf("FromRefs() got %q, expected [%q]", got, tt.want)
+			}
+		})
+	}
+}
+
+// refExportCataloger is a cataloger of a single commit, that records ref exports
+type refExportCataloger struct {
+	catalog.Cataloger
+	exports map[string]*catalog.RefExport
+}
+
+func (c *refExportCataloger) GetCommit(_ context.Context, _, _ string) (*catalog.CommitLog, error) {
+	return &catalog.CommitLog{Reference: "commit1"}, nil
+}
+
+func (c *refExportCataloger) InsertRefExport(_ context.Context, _ string, export *catalog.RefExport) error {
+	export.Status = catalog.ExportStatusInProgress
+	c.exports[export.ExportID] = export
+	return nil
+}
+
+func (c *refExportCataloger) EndRefExport(_ context.Context, exportID string, status catalog.CatalogBranchExportStatus, _ *string) error {
+	c.exports[exportID].Status = status
+	return nil
+}
+
+// taskParade records inserted tasks
+type taskParade struct {
+	parade.Parade
+	tasks []parade.TaskData
+}
+
+func (p *taskParade) InsertTasks(_ context.Context, tasks []parade.TaskData) error {
+	p.tasks = append(p.tasks, tasks...)
+	return nil
+}
+
+func TestExportRef(t *testing.T) {
+	ctx := context.Background()
+	c := &refExportCataloger{exports: make(map[string]*catalog.RefExport)}
+	p := &taskParade{}
+	exportID, err := ExportRef(ctx, p, c, "repo", "v1.0", "s3://export/v1.0")
+	testutil.Must(t, err)
+	refExport, ok := c.exports[exportID]
+	if !ok {
+		t.Fatalf("ref export %s not recorded", exportID)
+	}
+	if refExport.Ref != "v1.0" || refExport.CommitRef != "commit1" || refExport.Destination != "s3://export/v1.0" {
+		t.Errorf("recorded unexpected ref export %+v", refExport)
+	}
+	if len(p.tasks) != 1 || p.tasks[0].Action != StartAction {
+		t.Fatalf("got tasks %+v, expected a single start task", p.tasks)
+	}
+	var startData StartData
+	testutil.Must(t, json.Unmarshal([]byte(*p.tasks[0].Body), &startData))
+	if !startData.RefExport || startData.FromCommitRef != "" || startData.ToCommitRef != "commit1" ||
+		len(startData.ExportConfigs) != 1 || startData.ExportConfigs[0].Path != "s3://export/v1.0" {
+		t.Errorf("got start data %+v, expected a full export of commit1 to the destination", startData)
+	}
+
+	h := NewHandler(nil, nil, c, nil, nil)
+	finishBody, err := json.Marshal(FinishData{ExportID: exportID, RefExport: true, Repo: "repo", Branch: "v1.0", CommitRef: "commit1"})
+	testutil.Must(t, err)
+	finishBodyStr := string(finishBody)
+	if res := h.Handle(DoneAction, &finishBodyStr, 0); res.StatusCode != parade.TaskCompleted {
+		t.Fatalf("expected status code: %s, got: %s (%s)", parade.TaskCompleted, res.StatusCode, res.Status)
+	}
+	if refExport.Status != catalog.ExportStatusSuccess {
+		t.Errorf("ref export status %s, expected %s", refExport.Status, catalog.ExportStatusSuccess)
+	}
+
+	_, err = ExportRef(ctx, p, c, "repo", "v1.0", "")
+	if !errors.Is(err, catalog.ErrInvalidValue) {
+		t.Errorf("ExportRef() without destination err=%v, expected ErrInvalidValue", err)
+	}
+}
+
+// startCataloger is a cataloger of a branch exporting to templated paths, that records
+// inserted export runs
+type startCataloger struct {
+	refExportCataloger
+	runs []*catalog.ExportRun
+}
+
+func (c *startCataloger) ExportStateSet(_ context.Context, _, _ string, cb catalog.ExportStateCallback) error {
+	_, _, _, err := cb("", catalog.ExportStatusUnknown)
+	return err
+}
+
+func (c *startCataloger) GetExportConfigurationsForBranch(_ context.Context, _, _ string) ([]catalog.ExportConfiguration, error) {
+	return []catalog.ExportConfiguration{{
+		Path:       "s3://export/{branch}/{commit}",
+		StatusPath: "s3://export/status/{timestamp}",
+		Mode:       catalog.ExportModeFull,
+	}}, nil
+}
+
+func (c *startCataloger) InsertExportRun(_ context.Context, _, _ string, run *catalog.ExportRun) error {
+	c.runs = append(c.runs, run)
+	return nil
+}
+
+func TestExportBranchStart_ExpandPaths(t *testing.T) {
+	ctx := context.Background()
+	c := &startCataloger{}
+	p := &taskParade{}
+	_, err := ExportBranchStart(ctx, p, c, "repo", "main")
+	testutil.Must(t, err)
+	if len(p.tasks) != 1 || p.tasks[0].Action != StartAction {
+		t.Fatalf("got tasks %+v, expected a single start task", p.tasks)
+	}
+	var startData StartData
+	testutil.Must(t, json.Unmarshal([]byte(*p.tasks[0].Body), &startData))
+	if len(startData.ExportConfigs) != 1 {
+		t.Fatalf("got start data %+v, expected a single export configuration", startData)
+	}
+	config := startData.ExportConfigs[0]
+	expectedStatusPath := "s3://export/status/" + startData.StartTime.UTC().Format(catalog.ExportPathTimestampFormat)
+	if config.Path != "s3://export/main/commit1" || config.StatusPath != expectedStatusPath {
+		t.Errorf("export to %s with status path %s, expected s3://export/main/commit1 and %s", config.Path, config.StatusPath, expectedStatusPath)
+	}
+	if len(c.runs) != 1 || c.runs[0].PathsTime == nil || !c.runs[0].PathsTime.Equal(startData.StartTime) {
+		t.Errorf("recorded runs %+v, expected a run expanding paths at the start time %s", c.runs, startData.StartTime)
+	}
+}
+
+func TestMultiTasksGenerator_PropagateDeletes(t *testing.T) {
+	diffs := catalog.Differences{
+		{Type: catalog.DifferenceTypeAdded, Entry: catalog.Entry{Path: "a/1", PhysicalAddress: "a1"}},
+		{Type: catalog.DifferenceTypeRemoved, Entry: catalog.Entry{Path: "a/2"}},
+	}
+	for _, propagateDeletes := range []bool{true, false} {
+		configs := []catalog.ExportConfiguration{{Path: "s3://export/path", PropagateDeletes: propagateDeletes}}
+		gen := NewMultiTasksGenerator("export", "repo", "commit1", "commit2", configs, nil, "s3://storage")
+		tasks, err := gen.Add(diffs)
+		testutil.Must(t, err)
+		deletes := 0
+		for _, task := range tasks {
+			if task.Action == DeleteAction {
+				deletes++
+			}
+		}
+		expected := 0
+		if propagateDeletes {
+			expected = 1
+		}
+		if deletes != expected {
+			t.Errorf("propagate deletes %t: got %d delete tasks, expected %d", propagateDeletes, deletes, expected)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/parade"
+)
+
+const DestinationDoneAction = "export:destination-done"
+
+var ErrDestinationFailed = errors.New("export to destination failed")
+
+// DestinationDoneData describes a task ending the export to one of the destinations of a
+// configuration exporting to several paths
+type DestinationDoneData struct {
+	ExportID    string `json:"export_id"`
+	Destination string `json:"destination"`
+}
+
+func (exportID TaskIDGenerator) destinationDoneTaskID(destination int) parade.TaskID {
+	return parade.TaskID(fmt.Sprintf("%s:destination-done:%d", exportID, destination))
+}
+
+// fanOutTasksGenerator generates tasks exporting diffs to every path of a configuration with
+// additional paths.  The tasks of each destination end in a destination done task, which
+// records the status of the destination and fails if any of its tasks failed, so the finish
+// task sees a failure for every failed destination.
+type fanOutTasksGenerator struct {
+	exportID           string
+	destinations       []string
+	generators         []configTasksGenerator
+	destinationTasks   []*parade.TaskData
+	finishedTask       *parade.TaskData
+	sharesFinishedTask bool
+}
+
+// newFanOutTasksGenerator returns a generator exporting to all paths of config.  It generates
+// the tasks of each destination with a generator returned by newGenerator for a distinct
+// generator ID and the destination path.
+func newFanOutTasksGenerator(exportID, generatorID string, config catalog.ExportConfiguration, finishBody *string, newGenerator func(generatorID, path string) configTasksGenerator) *fanOutTasksGenerator {
+	idGen := TaskIDGenerator(generatorID)
+	zero, one := 0, 1
+	f := &fanOutTasksGenerator{
+		exportID:     exportID,
+		destinations: config.Paths(),
+		finishedTask: &parade.TaskData{
+			ID:                idGen.finishedTaskID(),
+			Action:            DoneAction,
+			Body:              finishBody,
+			StatusCode:        parade.TaskPending,
+			MaxTries:          &one,
+			TotalDependencies: &zero,
+		},
+	}
+	for i, path := range f.destinations {
+		destinationID := generatorID
+		if i > 0 {
+			destinationID = fmt.Sprintf("%s:destination:%d", generatorID, i)
+		}
+		dependencies := 0
+		task := &parade.TaskData{
+			ID:                idGen.destinationDoneTaskID(i),
+			Action:            DestinationDoneAction,
+			StatusCode:        parade.TaskPending,
+			MaxTries:          &one,
+			TotalDependencies: &dependencies,
+			ToSignalAfter:     []parade.TaskID{f.finishedTask.ID},
+		}
+		(*f.finishedTask.TotalDependencies)++
+		generator := newGenerator(destinationID, path)
+		generator.shareFinishTask(task)
+		f.generators = append(f.generators, generator)
+		f.destinationTasks = append(f.destinationTasks, task)
+	}
+	return f
+}
+
+// Add translates diffs into tasks of all destinations
+func (f *fanOutTasksGenerator) Add(diffs catalog.Differences) ([]parade.TaskData, error) {
+	var ret []parade.TaskData
+	for _, generator := range f.generators {
+		tasks, err := generator.Add(diffs)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, tasks...)
+	}
+	return ret, nil
+}
+
+// Finish ends tasks generation of all destinations, returning their remaining tasks, their
+// destination done tasks and the finish task unless another generator owns it
+func (f *fanOutTasksGenerator) Finish() ([]parade.TaskData, error) {
+	var ret []parade.TaskData
+	for _, generator := range f.generators {
+		tasks, err := generator.Finish()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, tasks...)
+	}
+	for i, task := range f.destinationTasks {
+		data := DestinationDoneData{ExportID: f.exportID, Destination: f.destinations[i]}
+		body, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize %+v: %w", data, err)
+		}
+		bodyStr := string(body)
+		task.Body = &bodyStr
+		ret = append(ret, *task)
+	}
+	if !f.sharesFinishedTask {
+		ret = append(ret, *f.finishedTask)
+	}
+	return ret, nil
+}
+
+func (f *fanOutTasksGenerator) generatedCounts() exportCounts {
+	var ret exportCounts
+	for _, generator := range f.generators {
+		counts := generator.generatedCounts()
+		ret.objectsCopied += counts.objectsCopied
+		ret.objectsDeleted += counts.objectsDeleted
+		ret.bytesCopied += counts.bytesCopied
+	}
+	return ret
+}
+
+func (f *fanOutTasksGenerator) finishTask() *parade.TaskData {
+	return f.finishedTask
+}
+
+func (f *fanOutTasksGenerator) shareFinishTask(task *parade.TaskData) {
+	for _, destinationTask := range f.destinationTasks {
+		destinationTask.ToSignalAfter = []parade.TaskID{task.ID}
+		(*task.TotalDependencies)++
+	}
+	f.finishedTask = task
+	f.sharesFinishedTask = true
+}
+
+// fanOutDestinations returns the destinations of all configurations exporting to several
+// paths, whose statuses are recorded on export runs
+func fanOutDestinations(configs []catalog.ExportConfiguration) []string {
+	var ret []string
+	for _, config := range configs {
+		if len(config.AdditionalPaths) > 0 {
+			ret = append(ret, config.Paths()...)
+		}
+	}
+	return ret
+}
+
+// destinationDone records the status of the export to a destination, failing if any of its
+// tasks failed
+func (h *Handler) destinationDone(ctx context.Context, body *string, signalledErrors int) error {
+	var data DestinationDoneData
+	err := json.Unmarshal([]byte(*body), &data)
+	if err != nil {
+		return err
+	}
+	status, msg := getStatus(signalledErrors)
+	err = h.cataloger.EndExportRunDestination(ctx, data.ExportID, data.Destination, status, msg)
+	if err != nil {
+		// run history is informational, the finish task still sees the failure below
+		logging.Default().WithError(err).WithFields(logging.Fields{
+			"export_id":   data.ExportID,
+			"destination": data.Destination,
+		}).Warn("failed to record export destination end")
+	}
+	if status == catalog.ExportStatusFailed {
+		return fmt.Errorf("%s: %d tasks failed: %w", data.Destination, signalledErrors, ErrDestinationFailed)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/parade"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestMultiTasksGenerator_FanOut(t *testing.T) {
+	fanOut := catalog.ExportConfiguration{
+		Prefix:           "a/",
+		Path:             "s3://west/export",
+		AdditionalPaths:  pq.StringArray{"s3://east/export"},
+		PropagateDeletes: true,
+	}
+	single := catalog.ExportConfiguration{Prefix: "b/", Path: "s3://single/export", PropagateDeletes: true}
+	diffs := catalog.Differences{
+		{Type: catalog.DifferenceTypeAdded, Entry: catalog.Entry{Path: "a/1", PhysicalAddress: "a1"}},
+		{Type: catalog.DifferenceTypeRemoved, Entry: catalog.Entry{Path: "a/2"}},
+		{Type: catalog.DifferenceTypeAdded, Entry: catalog.Entry{Path: "b/1", PhysicalAddress: "b1"}},
+	}
+	orders := map[string][]catalog.ExportConfiguration{
+		"fan out first":  {fanOut, single},
+		"fan out second": {single, fanOut},
+	}
+	for name, configs := range orders {
+		t.Run(name, func(t *testing.T) {
+			finishBody := "finish"
+			gen := NewMultiTasksGenerator("export", "repo", "commit1", "commit2", configs, &finishBody, "s3://storage")
+			tasks, err := gen.Add(diffs)
+			testutil.Must(t, err)
+			finishTasks, err := gen.Finish()
+			testutil.Must(t, err)
+			tasks = append(tasks, finishTasks...)
+
+			byID := make(map[parade.TaskID]parade.TaskData)
+			var finish parade.TaskData
+			destinationTasks := make(map[string]parade.TaskData)
+			for _, task := range tasks {
+				if _, ok := byID[task.ID]; ok {
+					t.Fatalf("task ID %s generated twice", task.ID)
+				}
+				byID[task.ID] = task
+				switch task.Action {
+				case DoneAction:
+					finish = task
+				case DestinationDoneAction:
+					var data DestinationDoneData
+					testutil.Must(t, json.Unmarshal([]byte(*task.Body), &data))
+					if data.ExportID != "export" {
+						t.Errorf("destination done task %s of export %s, expected export", task.ID, data.ExportID)
+					}
+					destinationTasks[data.Destination] = task
+				}
+			}
+			if finish.ID == "" {
+				t.Fatal("no finish task generated")
+			}
+			if len(destinationTasks) != 2 {
+				t.Fatalf("got destination done tasks %v, expected one for each path of the fan out configuration", destinationTasks)
+			}
+			for destination, task := range destinationTasks {
+				if len(task.ToSignalAfter) != 1 || task.ToSignalAfter[0] != finish.ID {
+					t.Errorf("destination done task of %s signals %v, expected the finish task", destination, task.ToSignalAfter)
+				}
+				if *task.TotalDependencies != 2 {
+					t.Errorf("destination done task of %s has %d dependencies, expected 2", destination, *task.TotalDependencies)
+				}
+			}
+			// 2 destination done tasks and the copy of b/1
+			if *finish.TotalDependencies != 3 {
+				t.Errorf("finish task has %d dependencies, expected 3", *finish.TotalDependencies)
+			}
+
+			for _, task := range tasks {
+				if task.Action != CopyAction && task.Action != DeleteAction {
+					continue
+				}
+				var destination string
+				if task.Action == CopyAction {
+					var data CopyData
+					testutil.Must(t, json.Unmarshal([]byte(*task.Body), &data))
+					destination = data.To
+				} else {
+					var data DeleteData
+					testutil.Must(t, json.Unmarshal([]byte(*task.Body), &data))
+					destination = data.File
+				}
+				expectedSignal := finish.ID
+				for path, destinationTask := range destinationTasks {
+					if strings.HasPrefix(destination, path+"/") {
+						expectedSignal = destinationTask.ID
+					}
+				}
+				if len(task.ToSignalAfter) != 1 || task.ToSignalAfter[0] != expectedSignal {
+					t.Errorf("task %s to %s signals %v, expected %s", task.ID, destination, task.ToSignalAfter, expectedSignal)
+				}
+			}
+			counts := gen.counts()
+			if counts.objectsCopied != 3 || counts.objectsDeleted != 2 {
+				t.Errorf("got %d copied and %d deleted objects, expected 3 and 2", counts.objectsCopied, counts.objectsDeleted)
+			}
+		})
+	}
+}
+
+// destinationCataloger records the ends of export run destinations
+type destinationCataloger struct {
+	catalog.Cataloger
+	statuses map[string]catalog.CatalogBranchExportStatus
+}
+
+func (c *destinationCataloger) EndExportRunDestination(_ context.Context, _, destination string, status catalog.CatalogBranchExportStatus, _ *string) error {
+	c.statuses[destination] = status
+	return nil
+}
+
+func TestHandler_DestinationDone(t *testing.T) {
+	c := &destinationCataloger{statuses: make(map[string]catalog.CatalogBranchExportStatus)}
+	h := NewHandler(nil, nil, c, nil, nil)
+	cases := []struct {
+		destination        string
+		signalledErrors    int
+		expectedStatusCode parade.TaskStatusCodeValue
+		expectedStatus     catalog.CatalogBranchExportStatus
+	}{
+		{"s3://west/export", 0, parade.TaskCompleted, catalog.ExportStatusSuccess},
+		{"s3://east/export", 2, parade.TaskAborted, catalog.ExportStatusFailed},
+	}
+	for _, tc := range cases {
+		body, err := json.Marshal(DestinationDoneData{ExportID: "export", Destination: tc.destination})
+		testutil.Must(t, err)
+		bodyStr := string(body)
+		res := h.Handle(DestinationDoneAction, &bodyStr, tc.signalledErrors)
+		if res.StatusCode != tc.expectedStatusCode {
+			t.Errorf("%s with %d failures: got status code %s, expected %s", tc.destination, tc.signalledErrors, res.StatusCode, tc.expectedStatusCode)
+		}
+		if c.statuses[tc.destination] != tc.expectedStatus {
+			t.Errorf("%s with %d failures: recorded status %s, expected %s", tc.destination, tc.signalledErrors, c.statuses[tc.destination], tc.expectedStatus)
+		}
+	}
+}
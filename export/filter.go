@@ -0,0 +1,59 @@
+package export
+
+import (
+	"path"
+	"strings"
+
+	"github.com/treeverse/lakefs/catalog"
+)
+
+// pathFilter selects the entries exported by an export configuration
+type pathFilter struct {
+	prefix          string
+	includePrefixes []string
+	excludeGlobs    []string
+}
+
+func newPathFilter(config catalog.ExportConfiguration) pathFilter {
+	return pathFilter{
+		prefix:          config.Prefix,
+		includePrefixes: config.IncludePrefixes,
+		excludeGlobs:    config.ExcludeGlobs,
+	}
+}
+
+// matches returns whether the entry at p is exported: it is under the prefix and under one of
+// the included prefixes (if any), and matches no excluded glob.  Globs with no "/" match the
+// last element of p, other globs match all of p.
+func (f pathFilter) matches(p string) bool {
+	if !strings.HasPrefix(p, f.prefix) {
+		return false
+	}
+	if len(f.includePrefixes) > 0 {
+		included := false
+		for _, prefix := range f.includePrefixes {
+			if strings.HasPrefix(p, prefix) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	for _, glob := range f.excludeGlobs {
+		name := p
+		if !strings.Contains(glob, "/") {
+			name = path.Base(p)
+		}
+		if matched, _ := path.Match(glob, name); matched {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmpty returns whether the filter matches all entries
+func (f pathFilter) isEmpty() bool {
+	return f.prefix == "" && len(f.includePrefixes) == 0 && len(f.excludeGlobs) == 0
+}
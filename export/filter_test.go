@@ -0,0 +1,36 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/treeverse/lakefs/catalog"
+)
+
+func TestPathFilter(t *testing.T) {
+	config := catalog.ExportConfiguration{
+		Prefix:          "data/",
+		IncludePrefixes: []string{"data/tables/", "data/views/"},
+		ExcludeGlobs:    []string{"*.tmp", "data/tables/staging/*"},
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "data/tables/t1/part-0", want: true},
+		{path: "data/views/v1", want: true},
+		{path: "data/other/o1"},
+		{path: "logs/tables/t1"},
+		{path: "data/tables/t1/part-0.tmp"},
+		{path: "data/tables/staging/s1"},
+		{path: "data/tables/staging/s1/part-0", want: true},
+	}
+	filter := newPathFilter(config)
+	for _, tt := range tests {
+		if got := filter.matches(tt.path); got != tt.want {
+			t.Errorf("matches(%s) got %t, expected %t", tt.path, got, tt.want)
+		}
+	}
+	if !newPathFilter(catalog.ExportConfiguration{}).matches("any/path") {
+		t.Error("empty filter did not match")
+	}
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/parade"
+)
+
+const dryRunExportID = "dry-run"
+
+// PlanOperation is a single file operation that an export performs on its destination
+type PlanOperation struct {
+	// Action is one of CopyAction, DeleteAction, TouchAction or SymlinkAction
+	Action      string
+	Source      string // copied object, set only for CopyAction
+	Destination string
+}
+
+// Plan describes the operations that exporting a ref would perform
+type Plan struct {
+	// FromRef is the exported ref that export configurations in incremental mode would diff
+	// from, empty to export all entries of ToRef
+	FromRef    string
+	ToRef      string
+	Operations []PlanOperation
+	// NumOperations counts all operations, it exceeds len(Operations) when the plan was
+	// truncated
+	NumOperations int
+}
+
+// ExportDryRun returns the operations that exporting ref on branch would perform now, without
+// performing them.  It plans using the export configuration and export state of branch, as a
+// real export would, expanding export paths as an export starting now.  Only the first limit
+// operations are returned, unless limit is negative.
+func ExportDryRun(ctx context.Context, cataloger catalog.Cataloger, repo, branch, ref string, limit int) (*Plan, error) {
+	configs, err := getExportConfigurations(ctx, cataloger, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	if ref == "" {
+		ref = branch
+	}
+	commit, err := cataloger.GetCommit(ctx, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	exportState, err := cataloger.GetExportState(ctx, repo, branch)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return nil, err
+	}
+	repository, err := cataloger.GetRepository(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		FromRef:    lastExportedRef(exportState.CurrentRef, exportState.State),
+		ToRef:      commit.Reference,
+		Operations: make([]PlanOperation, 0),
+	}
+	addTasks := func(tasks []parade.TaskData) error {
+		for _, task := range tasks {
+			if task.Action != CopyAction && task.Action != DeleteAction && task.Action != TouchAction && task.Action != SymlinkAction {
+				continue
+			}
+			plan.NumOperations++
+			if limit >= 0 && len(plan.Operations) >= limit {
+				continue
+			}
+			op, err := taskPlanOperation(task)
+			if err != nil {
+				return err
+			}
+			plan.Operations = append(plan.Operations, op)
+		}
+		return nil
+	}
+	tasksGenerator := NewMultiTasksGenerator(dryRunExportID, repo, plan.FromRef, plan.ToRef, expandPaths(configs, branch, plan.ToRef, time.Now()), nil, repository.StorageNamespace)
+	for _, fromRef := range tasksGenerator.FromRefs() {
+		err = forEachExportDiff(ctx, cataloger, repo, fromRef, plan.ToRef, func(diffs catalog.Differences) error {
+			tasks, err := tasksGenerator.AddDiffFrom(fromRef, diffs)
+			if err != nil {
+				return err
+			}
+			return addTasks(tasks)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	tasks, err := tasksGenerator.Finish()
+	if err != nil {
+		return nil, err
+	}
+	if err := addTasks(tasks); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// taskPlanOperation returns the file operation performed by a copy, delete or touch task
+func taskPlanOperation(task parade.TaskData) (PlanOperation, error) {
+	op := PlanOperation{Action: task.Action}
+	var err error
+	switch task.Action {
+	case CopyAction:
+		var data CopyData
+		err = json.Unmarshal([]byte(*task.Body), &data)
+		op.Source, op.Destination = data.From, data.To
+	case DeleteAction:
+		var data DeleteData
+		err = json.Unmarshal([]byte(*task.Body), &data)
+		op.Destination = data.File
+	case TouchAction:
+		var data SuccessData
+		err = json.Unmarshal([]byte(*task.Body), &data)
+		op.Destination = data.File
+	case SymlinkAction:
+		var data SymlinkData
+		err = json.Unmarshal([]byte(*task.Body), &data)
+		op.Destination = data.File
+	}
+	if err != nil {
+		return op, fmt.Errorf("task %s body: %w", task.ID, err)
+	}
+	return op, nil
+}
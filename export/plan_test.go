@@ -0,0 +1,99 @@
+package export
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+// planCataloger is a cataloger of a single never exported branch at a single commit
+type planCataloger struct {
+	catalog.Cataloger
+	configs []catalog.ExportConfiguration
+	entries []*catalog.Entry
+}
+
+func (c *planCataloger) GetExportConfigurationsForBranch(_ context.Context, _, _ string) ([]catalog.ExportConfiguration, error) {
+	return c.configs, nil
+}
+
+func (c *planCataloger) GetExportState(_ context.Context, _, _ string) (catalog.ExportState, error) {
+	return catalog.ExportState{}, db.ErrNotFound
+}
+
+func (c *planCataloger) GetCommit(_ context.Context, _, _ string) (*catalog.CommitLog, error) {
+	return &catalog.CommitLog{Reference: "commit1"}, nil
+}
+
+func (c *planCataloger) GetRepository(_ context.Context, repository string) (*catalog.Repository, error) {
+	return &catalog.Repository{Name: repository, StorageNamespace: "s3://storage/"}, nil
+}
+
+func (c *planCataloger) ListEntries(_ context.Context, _, _, _, _, _ string, _ int) ([]*catalog.Entry, bool, error) {
+	return c.entries, false, nil
+}
+
+func TestExportDryRun(t *testing.T) {
+	c := &planCataloger{
+		configs: []catalog.ExportConfiguration{{
+			Path:                   "s3://export/path",
+			LastKeysInPrefixRegexp: []string{"^tables/[^/]*$"},
+		}},
+		entries: []*catalog.Entry{
+			{Path: "readme", PhysicalAddress: "addr1"},
+			{Path: "tables/t1/part-0", PhysicalAddress: "addr2"},
+		},
+	}
+	plan, err := ExportDryRun(context.Background(), c, "repo", "master", "", -1)
+	testutil.Must(t, err)
+	want := &Plan{
+		ToRef: "commit1",
+		Operations: []PlanOperation{
+			{Action: CopyAction, Source: "s3://storage/addr1", Destination: "s3://export/path/readme"},
+			{Action: CopyAction, Source: "s3://storage/addr2", Destination: "s3://export/path/tables/t1/part-0"},
+			{Action: TouchAction, Destination: "s3://export/path/tables/t1/" + successFilename},
+		},
+		NumOperations: 3,
+	}
+	if diffs := deep.Equal(want, plan); diffs != nil {
+		t.Errorf("ExportDryRun() unexpected plan: %s", diffs)
+	}
+
+	plan, err = ExportDryRun(context.Background(), c, "repo", "master", "", 1)
+	testutil.Must(t, err)
+	if len(plan.Operations) != 1 || plan.NumOperations != 3 {
+		t.Errorf("ExportDryRun() with limit got %d of %d operations, expected 1 of 3", len(plan.Operations), plan.NumOperations)
+	}
+}
+
+func TestExportDryRun_Prefixes(t *testing.T) {
+	c := &planCataloger{
+		configs: []catalog.ExportConfiguration{
+			{Path: "s3://export/all"},
+			{Prefix: "tables/", Path: "s3://export/tables"},
+			{Prefix: "logs/", Path: "s3://export/logs"},
+		},
+		entries: []*catalog.Entry{
+			{Path: "readme", PhysicalAddress: "addr1"},
+			{Path: "tables/t1", PhysicalAddress: "addr2"},
+		},
+	}
+	plan, err := ExportDryRun(context.Background(), c, "repo", "master", "", -1)
+	testutil.Must(t, err)
+	want := &Plan{
+		ToRef: "commit1",
+		Operations: []PlanOperation{
+			{Action: CopyAction, Source: "s3://storage/addr1", Destination: "s3://export/all/readme"},
+			{Action: CopyAction, Source: "s3://storage/addr2", Destination: "s3://export/all/tables/t1"},
+			{Action: CopyAction, Source: "s3://storage/addr2", Destination: "s3://export/tables/tables/t1"},
+		},
+		NumOperations: 3,
+	}
+	if diffs := deep.Equal(want, plan); diffs != nil {
+		t.Errorf("ExportDryRun() unexpected plan: %s", diffs)
+	}
+}
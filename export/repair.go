@@ -0,0 +1,170 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/parade"
+)
+
+var (
+	ErrRepairWrongStatus = errors.New("incorrect status")
+	ErrRepairNoFailedRun = errors.New("no failed export run to repair")
+)
+
+// RepairExport retries the failed tasks of the last export of branch, which must have
+// failed.  Only the keys whose tasks failed are exported again, by a new export run of the
+// same commit.  When all retried tasks succeed the branch export state becomes
+// ExportStatusRepaired, and later exports continue incrementally from the repaired commit.
+// Export paths expand as in the failed export.  It returns the ID of the new export run.
+func RepairExport(ctx context.Context, paradeDB parade.Parade, cataloger catalog.Cataloger, repo, branch string) (string, error) {
+	var exportID string
+	err := cataloger.ExportStateSet(ctx, repo, branch, func(oldRef string, state catalog.CatalogBranchExportStatus) (newRef string, newState catalog.CatalogBranchExportStatus, newMessage *string, err error) {
+		if state != catalog.ExportStatusFailed {
+			return oldRef, "", nil, fmt.Errorf("%s export is %s: %w", branch, state, ErrRepairWrongStatus)
+		}
+		runs, _, err := cataloger.GetExportRuns(ctx, repo, branch, 1, "")
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		if len(runs) == 0 || runs[0].ToRef != oldRef || runs[0].Status != catalog.ExportStatusFailed {
+			return oldRef, "", nil, fmt.Errorf("%s: %w", branch, ErrRepairNoFailedRun)
+		}
+		failedRun := runs[0]
+		configs, err := getExportConfigurations(ctx, cataloger, repo, branch)
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		// retried tasks export to the paths of the failed run, statuses are written there
+		pathsTime := failedRun.StartTime
+		if failedRun.PathsTime != nil {
+			pathsTime = *failedRun.PathsTime
+		}
+		configs = expandPaths(configs, branch, oldRef, pathsTime)
+		failedTasks, err := paradeDB.ListAbortedTasks(ctx, failedRun.ExportID+":")
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		exportID, err = getExportID(repo, branch, oldRef)
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		finishData := newFinishData(exportID, repo, branch, oldRef, configs)
+		finishData.Repair = true
+		finishData.StartTime = time.Now()
+		tasks, counts, err := getRepairTasks(exportID, failedRun.ExportID, finishData, failedTasks)
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+
+		// record the run before its tasks, which may end it immediately
+		err = cataloger.InsertExportRun(ctx, repo, branch, &catalog.ExportRun{ExportID: exportID, FromRef: failedRun.FromRef, ToRef: oldRef, PathsTime: &pathsTime})
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		err = paradeDB.InsertTasks(ctx, tasks)
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		err = cataloger.SetExportRunCounts(ctx, exportID, counts.objectsCopied, counts.objectsDeleted, counts.bytesCopied)
+		if err != nil {
+			return oldRef, "", nil, err
+		}
+		return oldRef, catalog.ExportStatusInProgress, nil, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return exportID, nil
+}
+
+// getRepairTasks returns tasks of export exportID retrying failedTasks of export
+// failedExportID, and a finish task ending it with finishData.  Retried success files are
+// written after the retried tasks of their directories, as in the failed export.
+func getRepairTasks(exportID, failedExportID string, finishData FinishData, failedTasks []parade.TaskData) ([]parade.TaskData, exportCounts, error) {
+	var counts exportCounts
+	finishBody, err := json.Marshal(finishData)
+	if err != nil {
+		return nil, counts, fmt.Errorf("failed to serialize %+v: %w", finishData, err)
+	}
+	finishBodyStr := string(finishBody)
+	zero, one := 0, 1
+	finishTask := parade.TaskData{
+		ID:                TaskIDGenerator(exportID).finishedTaskID(),
+		Action:            DoneAction,
+		Body:              &finishBodyStr,
+		StatusCode:        parade.TaskPending,
+		MaxTries:          &one,
+		TotalDependencies: &zero,
+	}
+
+	tasks := make([]parade.TaskData, 0, len(failedTasks)+1)
+	destinations := make([]string, 0, len(failedTasks))
+	successDirs := make(map[string]int) // directory of retried success file -> index in tasks
+	for _, failedTask := range failedTasks {
+		if failedTask.Action != CopyAction && failedTask.Action != DeleteAction && failedTask.Action != TouchAction && failedTask.Action != SymlinkAction {
+			// start, destination done and finish tasks fail only because other tasks failed
+			continue
+		}
+		op, err := taskPlanOperation(failedTask)
+		if err != nil {
+			return nil, counts, err
+		}
+		dependencies := 0
+		task := parade.TaskData{
+			ID:                parade.TaskID(fmt.Sprintf("%s:retry:%s", exportID, strings.TrimPrefix(string(failedTask.ID), failedExportID+":"))),
+			Action:            failedTask.Action,
+			Body:              failedTask.Body,
+			StatusCode:        parade.TaskPending,
+			MaxTries:          failedTask.MaxTries,
+			RetryBaseDelay:    failedTask.RetryBaseDelay,
+			RetryMaxDelay:     failedTask.RetryMaxDelay,
+			TotalDependencies: &dependencies,
+		}
+		switch task.Action {
+		case CopyAction:
+			var data CopyData
+			if err := json.Unmarshal([]byte(*task.Body), &data); err != nil {
+				return nil, counts, fmt.Errorf("task %s body: %w", failedTask.ID, err)
+			}
+			counts.objectsCopied++
+			counts.bytesCopied += data.Size
+		case DeleteAction:
+			counts.objectsDeleted++
+		case TouchAction:
+			successDirs[dirname(op.Destination)] = len(tasks)
+		}
+		tasks = append(tasks, task)
+		destinations = append(destinations, op.Destination)
+	}
+
+	// enclosingSuccess returns the index of the retried success task of the innermost
+	// directory containing destination
+	enclosingSuccess := func(destination string) (int, bool) {
+		for dir := dirname(destination); dir != ""; dir = dirname(dir) {
+			if i, ok := successDirs[dir]; ok {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+	for i := range tasks {
+		destination := destinations[i]
+		if tasks[i].Action == TouchAction {
+			destination = dirname(destination)
+		}
+		signalled := &finishTask
+		if j, ok := enclosingSuccess(destination); ok {
+			signalled = &tasks[j]
+		}
+		tasks[i].ToSignalAfter = []parade.TaskID{signalled.ID}
+		(*signalled.TotalDependencies)++
+	}
+	tasks = append(tasks, finishTask)
+	return tasks, counts, nil
+}
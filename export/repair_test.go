@@ -0,0 +1,178 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/parade"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+// repairCataloger is a cataloger of a branch whose export of commit1 ended in state, that
+// records inserted export runs
+type repairCataloger struct {
+	catalog.Cataloger
+	state    catalog.CatalogBranchExportStatus
+	lastRun  *catalog.ExportRun
+	runs     []*catalog.ExportRun
+	newState catalog.CatalogBranchExportStatus
+}
+
+func (c *repairCataloger) ExportStateSet(_ context.Context, _, _ string, cb catalog.ExportStateCallback) error {
+	_, state, _, err := cb("commit1", c.state)
+	if err == nil {
+		c.newState = state
+	}
+	return err
+}
+
+func (c *repairCataloger) GetExportRuns(_ context.Context, _, _ string, _ int, _ string) ([]*catalog.ExportRun, bool, error) {
+	return []*catalog.ExportRun{c.lastRun}, false, nil
+}
+
+func (c *repairCataloger) GetExportConfigurationsForBranch(_ context.Context, _, _ string) ([]catalog.ExportConfiguration, error) {
+	return []catalog.ExportConfiguration{{Path: "s3://export/path", StatusPath: "s3://export/status/{timestamp}"}}, nil
+}
+
+func (c *repairCataloger) InsertExportRun(_ context.Context, _, _ string, run *catalog.ExportRun) error {
+	c.runs = append(c.runs, run)
+	return nil
+}
+
+func (c *repairCataloger) SetExportRunCounts(_ context.Context, _ string, _, _, _ int64) error {
+	return nil
+}
+
+// abortedParade holds the aborted tasks of an export and records inserted tasks
+type abortedParade struct {
+	taskParade
+	aborted []parade.TaskData
+}
+
+func (p *abortedParade) ListAbortedTasks(_ context.Context, idPrefix string) ([]parade.TaskData, error) {
+	var ret []parade.TaskData
+	for _, task := range p.aborted {
+		if strings.HasPrefix(string(task.ID), idPrefix) {
+			ret = append(ret, task)
+		}
+	}
+	return ret, nil
+}
+
+func abortedTask(t *testing.T, id parade.TaskID, action string, data interface{}) parade.TaskData {
+	t.Helper()
+	body, err := json.Marshal(data)
+	testutil.Must(t, err)
+	bodyStr := string(body)
+	tries := 5
+	return parade.TaskData{ID: id, Action: action, Body: &bodyStr, StatusCode: parade.TaskAborted, MaxTries: &tries}
+}
+
+func TestRepairExport(t *testing.T) {
+	ctx := context.Background()
+	pathsTime := time.Date(2020, 11, 3, 12, 5, 9, 0, time.UTC)
+	failedRun := &catalog.ExportRun{ExportID: "failed", FromRef: "commit0", ToRef: "commit1", Status: catalog.ExportStatusFailed, PathsTime: &pathsTime}
+	p := &abortedParade{aborted: []parade.TaskData{
+		abortedTask(t, "failed:copy:a1", CopyAction, CopyData{From: "s3://storage/a1", To: "s3://export/path/a/1", Size: 7}),
+		abortedTask(t, "failed:make-success:a", TouchAction, SuccessData{File: "s3://export/path/a/_lakefs_success"}),
+		abortedTask(t, "failed:delete:b2", DeleteAction, DeleteData{File: "s3://export/path/b/2"}),
+		abortedTask(t, "failed:finish", DoneAction, FinishData{ExportID: "failed"}),
+		abortedTask(t, "other:copy:c1", CopyAction, CopyData{From: "s3://storage/c1", To: "s3://export/path/c/1"}),
+	}}
+
+	t.Run("export did not fail", func(t *testing.T) {
+		c := &repairCataloger{state: catalog.ExportStatusSuccess, lastRun: failedRun}
+		_, err := RepairExport(ctx, p, c, "repo", "master")
+		if !errors.Is(err, ErrRepairWrongStatus) {
+			t.Errorf("RepairExport() err=%v, expected %s", err, ErrRepairWrongStatus)
+		}
+	})
+
+	t.Run("failed run of another commit", func(t *testing.T) {
+		c := &repairCataloger{state: catalog.ExportStatusFailed, lastRun: &catalog.ExportRun{ExportID: "old", ToRef: "commit0", Status: catalog.ExportStatusFailed}}
+		_, err := RepairExport(ctx, p, c, "repo", "master")
+		if !errors.Is(err, ErrRepairNoFailedRun) {
+			t.Errorf("RepairExport() err=%v, expected %s", err, ErrRepairNoFailedRun)
+		}
+	})
+
+	t.Run("retry failed tasks", func(t *testing.T) {
+		c := &repairCataloger{state: catalog.ExportStatusFailed, lastRun: failedRun}
+		exportID, err := RepairExport(ctx, p, c, "repo", "master")
+		testutil.Must(t, err)
+		if c.newState != catalog.ExportStatusInProgress {
+			t.Errorf("export state %s, expected %s", c.newState, catalog.ExportStatusInProgress)
+		}
+		if len(c.runs) != 1 || c.runs[0].ExportID != exportID || c.runs[0].FromRef != "commit0" || c.runs[0].ToRef != "commit1" {
+			t.Errorf("inserted runs %+v, expected run %s from commit0 to commit1", c.runs, exportID)
+		}
+
+		byID := make(map[parade.TaskID]parade.TaskData)
+		for _, task := range p.tasks {
+			byID[task.ID] = task
+		}
+		copyTask, ok := byID[parade.TaskID(exportID+":retry:copy:a1")]
+		if !ok {
+			t.Fatalf("copy of a/1 not retried in %+v", p.tasks)
+		}
+		touchTask, ok := byID[parade.TaskID(exportID+":retry:make-success:a")]
+		if !ok {
+			t.Fatalf("success file of a not retried in %+v", p.tasks)
+		}
+		deleteTask, ok := byID[parade.TaskID(exportID+":retry:delete:b2")]
+		if !ok {
+			t.Fatalf("delete of b/2 not retried in %+v", p.tasks)
+		}
+		finishTask, ok := byID[TaskIDGenerator(exportID).finishedTaskID()]
+		if !ok {
+			t.Fatalf("no finish task in %+v", p.tasks)
+		}
+		if len(p.tasks) != 4 {
+			t.Errorf("got %d tasks, expected 3 retried tasks and a finish task", len(p.tasks))
+		}
+		if *copyTask.MaxTries != 5 || *copyTask.Body != *p.aborted[0].Body {
+			t.Errorf("retried copy %+v differs from failed copy", copyTask)
+		}
+
+		signals := map[*parade.TaskData]parade.TaskData{&copyTask: touchTask, &touchTask: finishTask, &deleteTask: finishTask}
+		for task, signalled := range signals {
+			if len(task.ToSignalAfter) != 1 || task.ToSignalAfter[0] != signalled.ID {
+				t.Errorf("task %s signals %v, expected %s", task.ID, task.ToSignalAfter, signalled.ID)
+			}
+		}
+		if *touchTask.TotalDependencies != 1 || *finishTask.TotalDependencies != 2 {
+			t.Errorf("success task has %d and finish task %d dependencies, expected 1 and 2", *touchTask.TotalDependencies, *finishTask.TotalDependencies)
+		}
+
+		var finishData FinishData
+		testutil.Must(t, json.Unmarshal([]byte(*finishTask.Body), &finishData))
+		if !finishData.Repair || finishData.ExportID != exportID || finishData.CommitRef != "commit1" {
+			t.Errorf("got finish data %+v, expected repair of commit1 by %s", finishData, exportID)
+		}
+		if len(finishData.Statuses) != 1 || finishData.Statuses[0].StatusPath != "s3://export/status/20201103T120509Z" {
+			t.Errorf("got finish statuses %+v, expected status path of the failed run", finishData.Statuses)
+		}
+		if c.runs[0].PathsTime == nil || !c.runs[0].PathsTime.Equal(pathsTime) {
+			t.Errorf("repair run expands paths at %v, expected %s", c.runs[0].PathsTime, pathsTime)
+		}
+	})
+}
+
+func TestDoneRepair(t *testing.T) {
+	c := &doneCataloger{runState: make(map[string]catalog.CatalogBranchExportStatus)}
+	h := NewHandler(nil, nil, c, nil, nil)
+	finishBody, err := json.Marshal(FinishData{ExportID: "repair", Repo: "repo", Branch: "master", CommitRef: "commit1", Repair: true})
+	testutil.Must(t, err)
+	finishBodyStr := string(finishBody)
+	if res := h.Handle(DoneAction, &finishBodyStr, 0); res.StatusCode != parade.TaskCompleted {
+		t.Fatalf("expected status code: %s, got: %s (%s)", parade.TaskCompleted, res.StatusCode, res.Status)
+	}
+	if c.state != catalog.ExportStatusRepaired || c.runState["repair"] != catalog.ExportStatusRepaired {
+		t.Errorf("export state %s and run state %s, expected %s", c.state, c.runState["repair"], catalog.ExportStatusRepaired)
+	}
+}
@@ -0,0 +1,185 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/cron"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/parade"
+)
+
+// schedulerInterval is the interval between checks for scheduled exports, the resolution of
+// cron schedules
+const schedulerInterval = time.Minute
+
+// Scheduler starts exports of branches at the times of the schedules of their export
+// configurations.  A branch with several scheduled configurations is exported at the times of
+// each schedule.  Runs are recorded in the export state of the branch, so that each scheduled
+// time starts at most a single export even with several lakeFS instances.
+type Scheduler struct {
+	paradeDB  parade.Parade
+	cataloger catalog.Cataloger
+	log       logging.Logger
+	// started is used instead of the last run of branches that never ran on schedule
+	started time.Time
+}
+
+func NewScheduler(paradeDB parade.Parade, cataloger catalog.Cataloger, log logging.Logger) *Scheduler {
+	return &Scheduler{
+		paradeDB:  paradeDB,
+		cataloger: cataloger,
+		log:       log,
+		started:   time.Now(),
+	}
+}
+
+// Run starts scheduled exports and reports the export lag of branches until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.RunDue(ctx, now); err != nil {
+				s.log.WithError(err).Error("scheduled exports failed")
+			}
+			if err := s.ReportLag(ctx); err != nil {
+				s.log.WithError(err).Error("report export lag failed")
+			}
+		}
+	}
+}
+
+type scheduledBranch struct {
+	repo, branch string
+}
+
+// RunDue starts exports of all branches scheduled to export since their last scheduled run
+// and until now.  Failing to export a branch does not stop exporting other branches.
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) error {
+	configs, err := s.cataloger.GetExportConfigurations(ctx)
+	if err != nil {
+		return err
+	}
+	schedules := make(map[scheduledBranch][]*cron.Schedule)
+	var branches []scheduledBranch
+	for _, config := range configs {
+		if config.Schedule == "" {
+			continue
+		}
+		schedule, err := cron.Parse(config.Schedule)
+		if err != nil {
+			s.log.WithError(err).WithFields(logging.Fields{
+				"repository": config.Repository,
+				"branch":     config.Branch,
+				"prefix":     config.Prefix,
+			}).Warn("skip export configuration with invalid schedule")
+			continue
+		}
+		key := scheduledBranch{repo: config.Repository, branch: config.Branch}
+		if _, ok := schedules[key]; !ok {
+			branches = append(branches, key)
+		}
+		schedules[key] = append(schedules[key], schedule)
+	}
+	for _, key := range branches {
+		log := s.log.WithFields(logging.Fields{"repository": key.repo, "branch": key.branch})
+		exportID, err := s.runBranch(ctx, key.repo, key.branch, schedules[key], now)
+		if err != nil {
+			log.WithError(err).Warn("scheduled export not started")
+			continue
+		}
+		if exportID != "" {
+			log.WithField("export_id", exportID).Info("scheduled export started")
+		}
+	}
+	return nil
+}
+
+// runBranch starts an export of branch if one of schedules is due at now, returning its
+// export ID or an empty ID if no export is due
+func (s *Scheduler) runBranch(ctx context.Context, repo, branch string, schedules []*cron.Schedule, now time.Time) (string, error) {
+	state, err := s.cataloger.GetExportState(ctx, repo, branch)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return "", err
+	}
+	since := s.started
+	if state.LastScheduledRun != nil {
+		since = *state.LastScheduledRun
+	}
+	var run time.Time
+	for _, schedule := range schedules {
+		if prev := schedule.Prev(since, now); prev.After(run) {
+			run = prev
+		}
+	}
+	if run.IsZero() {
+		return "", nil
+	}
+	recorded, err := s.cataloger.MarkExportScheduledRun(ctx, repo, branch, run)
+	if err != nil {
+		return "", err
+	}
+	if !recorded {
+		// started by another scheduler
+		return "", nil
+	}
+	return ExportBranchStart(ctx, s.paradeDB, s.cataloger, repo, branch)
+}
+
+// ReportLag sets the export lag metric of every exported branch to the number of commits on it
+// after its last successfully exported commit, up to the maximal number of listed commits.
+// Branches with no successful export keep their last reported lag.
+func (s *Scheduler) ReportLag(ctx context.Context) error {
+	configs, err := s.cataloger.GetExportConfigurations(ctx)
+	if err != nil {
+		return err
+	}
+	reported := make(map[scheduledBranch]struct{})
+	for _, config := range configs {
+		key := scheduledBranch{repo: config.Repository, branch: config.Branch}
+		if _, ok := reported[key]; ok {
+			continue
+		}
+		reported[key] = struct{}{}
+		lag, ok, err := s.branchLag(ctx, key.repo, key.branch)
+		if err != nil {
+			s.log.WithError(err).WithFields(logging.Fields{
+				"repository": key.repo,
+				"branch":     key.branch,
+			}).Warn("skip export lag of branch")
+			continue
+		}
+		if ok {
+			lagGauges.WithLabelValues(key.repo, key.branch).Set(float64(lag))
+		}
+	}
+	return nil
+}
+
+// branchLag returns the number of commits on branch after its last successfully exported
+// commit, and whether branch was successfully exported
+func (s *Scheduler) branchLag(ctx context.Context, repo, branch string) (int, bool, error) {
+	state, err := s.cataloger.GetExportState(ctx, repo, branch)
+	if errors.Is(err, db.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	exportedRef := lastExportedRef(state.CurrentRef, state.State)
+	if exportedRef == "" {
+		return 0, false, nil
+	}
+	commits, _, err := s.cataloger.ListCommitsSince(ctx, repo, branch, exportedRef, -1)
+	if err != nil {
+		return 0, false, err
+	}
+	return len(commits), true, nil
+}
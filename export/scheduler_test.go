@@ -0,0 +1,168 @@
+package export
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/parade"
+)
+
+// scheduleCataloger is a cataloger of branches at a single commit, that records scheduled runs
+type scheduleCataloger struct {
+	catalog.Cataloger
+	configs []catalog.ExportConfigurationForBranch
+	runs    map[string]time.Time
+}
+
+func (c *scheduleCataloger) GetExportConfigurations(_ context.Context) ([]catalog.ExportConfigurationForBranch, error) {
+	return c.configs, nil
+}
+
+func (c *scheduleCataloger) GetExportConfigurationsForBranch(_ context.Context, _, _ string) ([]catalog.ExportConfiguration, error) {
+	return []catalog.ExportConfiguration{{Path: "s3://export/path"}}, nil
+}
+
+func (c *scheduleCataloger) GetExportState(_ context.Context, _, branch string) (catalog.ExportState, error) {
+	run, ok := c.runs[branch]
+	if !ok {
+		return catalog.ExportState{}, db.ErrNotFound
+	}
+	return catalog.ExportState{LastScheduledRun: &run}, nil
+}
+
+func (c *scheduleCataloger) MarkExportScheduledRun(_ context.Context, _, branch string, run time.Time) (bool, error) {
+	if last, ok := c.runs[branch]; ok && !last.Before(run) {
+		return false, nil
+	}
+	c.runs[branch] = run
+	return true, nil
+}
+
+func (c *scheduleCataloger) ExportStateSet(_ context.Context, _, _ string, cb catalog.ExportStateCallback) error {
+	_, _, _, err := cb("", "")
+	return err
+}
+
+func (c *scheduleCataloger) InsertExportRun(_ context.Context, _, _ string, _ *catalog.ExportRun) error {
+	return nil
+}
+
+func (c *scheduleCataloger) GetCommit(_ context.Context, _, _ string) (*catalog.CommitLog, error) {
+	return &catalog.CommitLog{Reference: "commit1"}, nil
+}
+
+// startParade records the branches of inserted start tasks
+type startParade struct {
+	parade.Parade
+	started []string
+}
+
+func (p *startParade) InsertTasks(_ context.Context, tasks []parade.TaskData) error {
+	for _, task := range tasks {
+		if task.Action == StartAction {
+			p.started = append(p.started, string(task.ID))
+		}
+	}
+	return nil
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	ctx := context.Background()
+	c := &scheduleCataloger{
+		configs: []catalog.ExportConfigurationForBranch{
+			{Repository: "repo", Branch: "hourly", Schedule: "0 * * * *"},
+			{Repository: "repo", Branch: "daily", Schedule: "@daily"},
+			{Repository: "repo", Branch: "manual"},
+			{Repository: "repo", Branch: "invalid", Schedule: "every day"},
+		},
+		runs: make(map[string]time.Time),
+	}
+	p := &startParade{}
+	s := NewScheduler(p, c, logging.Default())
+	s.started = time.Date(2020, time.November, 3, 10, 30, 0, 0, time.UTC)
+
+	if err := s.RunDue(ctx, s.started.Add(10*time.Minute)); err != nil {
+		t.Fatalf("RunDue() unexpected error: %s", err)
+	}
+	if len(p.started) != 0 {
+		t.Fatalf("started exports %v before their schedules", p.started)
+	}
+
+	now := time.Date(2020, time.November, 3, 13, 5, 0, 0, time.UTC)
+	if err := s.RunDue(ctx, now); err != nil {
+		t.Fatalf("RunDue() unexpected error: %s", err)
+	}
+	if len(p.started) != 1 {
+		t.Fatalf("started exports %v, expected a single hourly export", p.started)
+	}
+	if want := time.Date(2020, time.November, 3, 13, 0, 0, 0, time.UTC); !c.runs["hourly"].Equal(want) {
+		t.Errorf("recorded hourly run at %s, expected %s", c.runs["hourly"], want)
+	}
+
+	// a second scheduler does not run the same scheduled time again
+	other := NewScheduler(p, c, logging.Default())
+	other.started = s.started
+	if err := other.RunDue(ctx, now.Add(time.Minute)); err != nil {
+		t.Fatalf("RunDue() unexpected error: %s", err)
+	}
+	if len(p.started) != 1 {
+		t.Errorf("started exports %v, expected no more exports", p.started)
+	}
+}
+
+// lagCataloger is a cataloger of branches with export states and commits after them
+type lagCataloger struct {
+	catalog.Cataloger
+	configs []catalog.ExportConfigurationForBranch
+	states  map[string]catalog.ExportState
+	commits map[string]int
+}
+
+func (c *lagCataloger) GetExportConfigurations(_ context.Context) ([]catalog.ExportConfigurationForBranch, error) {
+	return c.configs, nil
+}
+
+func (c *lagCataloger) GetExportState(_ context.Context, _, branch string) (catalog.ExportState, error) {
+	state, ok := c.states[branch]
+	if !ok {
+		return catalog.ExportState{}, db.ErrNotFound
+	}
+	return state, nil
+}
+
+func (c *lagCataloger) ListCommitsSince(_ context.Context, _, branch string, _ string, _ int) ([]*catalog.CommitLog, bool, error) {
+	return make([]*catalog.CommitLog, c.commits[branch]), false, nil
+}
+
+func TestScheduler_ReportLag(t *testing.T) {
+	c := &lagCataloger{
+		configs: []catalog.ExportConfigurationForBranch{
+			{Repository: "lag-repo", Branch: "exported"},
+			{Repository: "lag-repo", Branch: "exported", Prefix: "tables/"},
+			{Repository: "lag-repo", Branch: "failed"},
+			{Repository: "lag-repo", Branch: "never"},
+		},
+		states: map[string]catalog.ExportState{
+			"exported": {CurrentRef: "~commit1", State: catalog.ExportStatusSuccess},
+			"failed":   {CurrentRef: "~commit1", State: catalog.ExportStatusFailed},
+		},
+		commits: map[string]int{"exported": 3, "failed": 5, "never": 7},
+	}
+	s := NewScheduler(nil, c, logging.Default())
+	if err := s.ReportLag(context.Background()); err != nil {
+		t.Fatalf("ReportLag() unexpected error: %s", err)
+	}
+	if lag := promtestutil.ToFloat64(lagGauges.WithLabelValues("lag-repo", "exported")); lag != 3 {
+		t.Errorf("got lag %g of exported branch, expected 3", lag)
+	}
+	for _, branch := range []string{"failed", "never"} {
+		if lag := promtestutil.ToFloat64(lagGauges.WithLabelValues("lag-repo", branch)); lag != 0 {
+			t.Errorf("got lag %g of branch %s with no successful export, expected none", lag, branch)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	objectsCopiedCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "export_objects_copied_total",
+			Help: "Objects copied to export destinations",
+		},
+	)
+
+	bytesCopiedCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "export_bytes_copied_total",
+			Help: "Bytes copied to export destinations",
+		},
+	)
+
+	objectsDeletedCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "export_objects_deleted_total",
+			Help: "Objects deleted from export destinations",
+		},
+	)
+
+	taskFailuresCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "export_task_failures_total",
+			Help: "Failed export task attempts, including attempts that are retried",
+		},
+		[]string{"action"},
+	)
+
+	exportDurationHistograms = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "export_duration_seconds",
+			Help:    "Durations of exports from starting until ending in status",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+		},
+		[]string{"status"},
+	)
+
+	lagGauges = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "export_lag_commits",
+			Help: "Commits on exported branches after their last successfully exported commit",
+		},
+		[]string{"repository", "branch"},
+	)
+)
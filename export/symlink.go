@@ -0,0 +1,222 @@
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/parade"
+)
+
+const SymlinkAction = "export:symlink"
+
+// symlinkFilename is the name of the file listing the objects of a partition, read by the
+// SymlinkTextInputFormat of Hive, Athena and Presto
+const symlinkFilename = "symlink.txt"
+
+// SymlinkData describes a task writing the symlink file of a single partition
+type SymlinkData struct {
+	Repo string `json:"repo"`
+	Ref  string `json:"ref"`
+	// Partition is the directory of the listed entries, empty for entries at the root
+	Partition        string `json:"partition"`
+	File             string `json:"file"`
+	StorageNamespace string `json:"storage_namespace"`
+	// Prefix, IncludePrefixes and ExcludeGlobs select the listed entries
+	Prefix          string   `json:"prefix,omitempty"`
+	IncludePrefixes []string `json:"include_prefixes,omitempty"`
+	ExcludeGlobs    []string `json:"exclude_globs,omitempty"`
+}
+
+func (exportID TaskIDGenerator) SymlinkTaskID(partition string) parade.TaskID {
+	shaHash := sha256.Sum256([]byte(partition))
+	shaHashInString := hex.EncodeToString(shaHash[:])
+	return parade.TaskID(fmt.Sprintf("%s:symlink:%s", exportID, shaHashInString))
+}
+
+// SymlinkTasksGenerator generates tasks exporting diffs in symlink format: instead of copying
+// objects it writes a symlink file to every partition touched by the diffs, listing the
+// physical addresses of all entries of the partition on Ref.  Partitions are the directories
+// of entries.
+type SymlinkTasksGenerator struct {
+	ExportID  string
+	Repo      string
+	Ref       string
+	DstPrefix string
+	NumTries  int
+	// RetryBackoff (if positive) delays retrying a failed symlink task, doubling the delay
+	// on every try up to maxRetryDelay.
+	RetryBackoff time.Duration
+
+	storageNamespace   string
+	filter             pathFilter
+	idGen              TaskIDGenerator
+	partitions         map[string]struct{}
+	finishedTask       *parade.TaskData
+	sharesFinishedTask bool
+}
+
+// NewSymlinkTasksGenerator returns a generator that exports diffs of ref as symlink files under
+// dstPrefix
+func NewSymlinkTasksGenerator(exportID, repo, ref, dstPrefix string, finishBody *string, storageNamespace string) *SymlinkTasksGenerator {
+	const numTries = 5
+	idGen := TaskIDGenerator(exportID)
+	zero, one := 0, 1
+	return &SymlinkTasksGenerator{
+		ExportID:         exportID,
+		Repo:             repo,
+		Ref:              ref,
+		DstPrefix:        strings.TrimRight(dstPrefix, "/"),
+		NumTries:         numTries,
+		storageNamespace: storageNamespace,
+		idGen:            idGen,
+		partitions:       make(map[string]struct{}),
+		finishedTask: &parade.TaskData{
+			ID:                idGen.finishedTaskID(),
+			Action:            DoneAction,
+			Body:              finishBody,
+			StatusCode:        parade.TaskPending,
+			MaxTries:          &one,
+			TotalDependencies: &zero,
+		},
+	}
+}
+
+// configure sets the listed entries and retry policy of generated tasks from config.  Symlink
+// tasks are not limited by the parallelism of config.
+func (s *SymlinkTasksGenerator) configure(config catalog.ExportConfiguration) {
+	s.filter = newPathFilter(config)
+	s.RetryBackoff = config.RetryBackoff
+	if config.MaxAttempts > 0 {
+		s.NumTries = config.MaxAttempts
+	}
+}
+
+// Add returns a task for each partition of diffs that has no task yet
+func (s *SymlinkTasksGenerator) Add(diffs catalog.Differences) ([]parade.TaskData, error) {
+	var ret []parade.TaskData
+	zero := 0
+	for _, diff := range diffs {
+		if diff.Path == "" {
+			return nil, fmt.Errorf("no \"Path\" in %+v: %w", diff, ErrMissingColumns)
+		}
+		if diff.Type == catalog.DifferenceTypeConflict {
+			return nil, fmt.Errorf("%+v: %w", diff, ErrConflict)
+		}
+		partition := dirname(diff.Path)
+		if _, ok := s.partitions[partition]; ok {
+			continue
+		}
+		s.partitions[partition] = struct{}{}
+
+		file := symlinkFilename
+		if partition != "" {
+			file = partition + "/" + symlinkFilename
+		}
+		data := SymlinkData{
+			Repo:             s.Repo,
+			Ref:              s.Ref,
+			Partition:        partition,
+			File:             fmt.Sprintf("%s/%s", s.DstPrefix, file),
+			StorageNamespace: s.storageNamespace,
+			Prefix:           s.filter.prefix,
+			IncludePrefixes:  s.filter.includePrefixes,
+			ExcludeGlobs:     s.filter.excludeGlobs,
+		}
+		body, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize %+v: %w", data, err)
+		}
+		bodyStr := string(body)
+		task := parade.TaskData{
+			ID:                s.idGen.SymlinkTaskID(partition),
+			Action:            SymlinkAction,
+			Body:              &bodyStr,
+			StatusCode:        parade.TaskPending,
+			MaxTries:          &s.NumTries,
+			TotalDependencies: &zero, // Depends only on a start task
+			ToSignalAfter:     []parade.TaskID{s.finishedTask.ID},
+		}
+		if s.RetryBackoff > 0 {
+			task.RetryBaseDelay = &s.RetryBackoff
+			task.RetryMaxDelay = &maxRetryDelay
+		}
+		(*s.finishedTask.TotalDependencies)++
+		ret = append(ret, task)
+	}
+	return ret, nil
+}
+
+// Finish ends tasks generation, returning the finish task unless another generator owns it
+func (s *SymlinkTasksGenerator) Finish() ([]parade.TaskData, error) {
+	if s.sharesFinishedTask {
+		return nil, nil
+	}
+	return []parade.TaskData{*s.finishedTask}, nil
+}
+
+// generatedCounts returns no counts, as symlink exports copy and delete no objects
+func (s *SymlinkTasksGenerator) generatedCounts() exportCounts {
+	return exportCounts{}
+}
+
+func (s *SymlinkTasksGenerator) finishTask() *parade.TaskData {
+	return s.finishedTask
+}
+
+func (s *SymlinkTasksGenerator) shareFinishTask(task *parade.TaskData) {
+	s.finishedTask = task
+	s.sharesFinishedTask = true
+}
+
+// symlink writes the symlink file of a partition, or removes it if the partition has no
+// entries left
+func (h *Handler) symlink(ctx context.Context, body *string) error {
+	var data SymlinkData
+	err := json.Unmarshal([]byte(*body), &data)
+	if err != nil {
+		return err
+	}
+	filter := pathFilter{
+		prefix:          data.Prefix,
+		includePrefixes: data.IncludePrefixes,
+		excludeGlobs:    data.ExcludeGlobs,
+	}
+	storageNamespace := strings.TrimRight(data.StorageNamespace, "/")
+	listPrefix := ""
+	if data.Partition != "" {
+		listPrefix = data.Partition + "/"
+	}
+	var content strings.Builder
+	after := ""
+	for {
+		entries, hasMore, err := h.cataloger.ListEntries(ctx, data.Repo, data.Ref, listPrefix, after, "/", -1)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.CommonLevel || !filter.matches(entry.Path) {
+				continue
+			}
+			content.WriteString(storageNamespace + "/" + entry.PhysicalAddress + "\n")
+		}
+		if !hasMore || len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1].Path
+	}
+	adapter, path, err := h.destinations.resolve(h.adapter, data.File)
+	if err != nil {
+		return err
+	}
+	if content.Len() == 0 {
+		return adapter.Remove(path)
+	}
+	return adapter.Put(path, int64(content.Len()), strings.NewReader(content.String()), block.PutOpts{})
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/mem"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/parade"
+	"github.com/treeverse/lakefs/testutil"
+)
+
+func TestSymlinkTasksGenerator(t *testing.T) {
+	finishBody := "finish"
+	gen := NewSymlinkTasksGenerator("symlink", "repo", "commit1", "s3://export/table/", &finishBody, "s3://storage")
+	tasks, err := gen.Add(catalog.Differences{
+		{Type: catalog.DifferenceTypeAdded, Entry: catalog.Entry{Path: "year=2020/part-0"}},
+		{Type: catalog.DifferenceTypeChanged, Entry: catalog.Entry{Path: "year=2020/part-1"}},
+		{Type: catalog.DifferenceTypeRemoved, Entry: catalog.Entry{Path: "year=2021/part-0"}},
+		{Type: catalog.DifferenceTypeAdded, Entry: catalog.Entry{Path: "readme"}},
+	})
+	testutil.Must(t, err)
+	more, err := gen.Add(catalog.Differences{
+		{Type: catalog.DifferenceTypeAdded, Entry: catalog.Entry{Path: "year=2021/part-1"}},
+	})
+	testutil.Must(t, err)
+	if len(more) != 0 {
+		t.Errorf("got %d tasks for a partition with a task, expected none", len(more))
+	}
+	finishTasks, err := gen.Finish()
+	testutil.Must(t, err)
+	if len(finishTasks) != 1 || finishTasks[0].Action != DoneAction {
+		t.Fatalf("got finish tasks %+v, expected a single finish task", finishTasks)
+	}
+
+	files := make(map[string]string)
+	for _, task := range tasks {
+		if task.Action != SymlinkAction {
+			t.Fatalf("got task action %s, expected %s", task.Action, SymlinkAction)
+		}
+		if len(task.ToSignalAfter) != 1 || task.ToSignalAfter[0] != finishTasks[0].ID {
+			t.Errorf("task %s signals %v, expected the finish task", task.ID, task.ToSignalAfter)
+		}
+		var data SymlinkData
+		testutil.Must(t, json.Unmarshal([]byte(*task.Body), &data))
+		if data.Repo != "repo" || data.Ref != "commit1" {
+			t.Errorf("task %s lists %s@%s, expected repo@commit1", task.ID, data.Repo, data.Ref)
+		}
+		files[data.Partition] = data.File
+	}
+	expected := map[string]string{
+		"year=2020": "s3://export/table/year=2020/symlink.txt",
+		"year=2021": "s3://export/table/year=2021/symlink.txt",
+		"":          "s3://export/table/symlink.txt",
+	}
+	if len(files) != len(expected) {
+		t.Errorf("got symlink files %v, expected %v", files, expected)
+	}
+	for partition, file := range expected {
+		if files[partition] != file {
+			t.Errorf("got symlink file %q for partition %q, expected %q", files[partition], partition, file)
+		}
+	}
+	if *finishTasks[0].TotalDependencies != len(expected) {
+		t.Errorf("finish task has %d dependencies, expected %d", *finishTasks[0].TotalDependencies, len(expected))
+	}
+}
+
+// partitionCataloger is a cataloger of entries of a single ref that lists them by directory
+type partitionCataloger struct {
+	catalog.Cataloger
+	entries []*catalog.Entry
+}
+
+func (c *partitionCataloger) ListEntries(_ context.Context, _, _, prefix, after, delimiter string, _ int) ([]*catalog.Entry, bool, error) {
+	var ret []*catalog.Entry
+	for _, entry := range c.entries {
+		if !strings.HasPrefix(entry.Path, prefix) || entry.Path <= after {
+			continue
+		}
+		if delimiter != "" && strings.Contains(entry.Path[len(prefix):], delimiter) {
+			continue
+		}
+		ret = append(ret, entry)
+	}
+	return ret, false, nil
+}
+
+func TestHandler_Symlink(t *testing.T) {
+	adapter := mem.New()
+	c := &partitionCataloger{entries: []*catalog.Entry{
+		{Path: "year=2020/part-0", PhysicalAddress: "addr0"},
+		{Path: "year=2020/part-1.tmp", PhysicalAddress: "addr1"},
+		{Path: "year=2020/part-2", PhysicalAddress: "addr2"},
+		{Path: "year=2020/month=1/part-0", PhysicalAddress: "addr3"},
+	}}
+	h := NewHandler(adapter, nil, c, nil, nil)
+	symlink := func(partition string) {
+		body, err := json.Marshal(SymlinkData{
+			Repo:             "repo",
+			Ref:              "commit1",
+			Partition:        partition,
+			File:             "mem://export/table/" + partition + "/" + symlinkFilename,
+			StorageNamespace: "s3://storage/",
+			ExcludeGlobs:     []string{"*.tmp"},
+		})
+		testutil.Must(t, err)
+		bodyStr := string(body)
+		if res := h.Handle(SymlinkAction, &bodyStr, 0); res.StatusCode != parade.TaskCompleted {
+			t.Fatalf("expected status code: %s, got: %s (%s)", parade.TaskCompleted, res.StatusCode, res.Status)
+		}
+	}
+	pointer := block.ObjectPointer{StorageNamespace: "mem://export/", Identifier: "table/year=2020/" + symlinkFilename}
+
+	symlink("year=2020")
+	reader, err := adapter.Get(pointer, 0)
+	testutil.Must(t, err)
+	content, err := ioutil.ReadAll(reader)
+	testutil.Must(t, err)
+	expected := "s3://storage/addr0\ns3://storage/addr2\n"
+	if string(content) != expected {
+		t.Errorf("got symlink file:\n%s\nexpected:\n%s", content, expected)
+	}
+
+	// emptied partitions lose their symlink file
+	c.entries = c.entries[3:]
+	symlink("year=2020")
+	if _, err := adapter.Get(pointer, 0); err == nil {
+		t.Error("symlink file of an empty partition still exists")
+	}
+}
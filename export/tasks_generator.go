@@ -6,8 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
+	"path"
 	"strings"
+	"time"
 
+	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/parade"
 )
@@ -21,6 +25,12 @@ var (
 
 const successFilename = "_lakefs_success"
 
+// defaultContentType is the Content-Type of exported objects with no known type
+const defaultContentType = "application/octet-stream"
+
+// maxRetryDelay bounds the exponential backoff between tries of a file operation task
+var maxRetryDelay = 15 * time.Minute
+
 const (
 	StartAction  = "export:start"
 	CopyAction   = "export:copy"
@@ -35,13 +45,64 @@ type StartData struct {
 	FromCommitRef string `json:"from"`
 	ToCommitRef   string `json:"to"`
 	ExportID      string `json:"export_id"`
-	ExportConfig  catalog.ExportConfiguration
+	// ExportConfigs are the export configurations of the branch, FromCommitRef applies
+	// only to those in incremental mode
+	ExportConfigs []catalog.ExportConfiguration
+	// RefExport is set for one-off exports of a ref, which Branch then holds, that track
+	// their state apart from the export state of branches
+	RefExport bool `json:"ref_export,omitempty"`
+	// StartTime is the time the export was requested, zero for exports requested before it
+	// was recorded
+	StartTime time.Time `json:"start_time,omitempty"`
 }
 
 type CopyData struct {
 	From string `json:"from"`
 	To   string `json:"to"`
 	ETag string `json:"etag"` // Empty for now :-(
+	// Repository exporting the object, its copies are limited by its export bandwidth quota
+	Repository string `json:"repository,omitempty"`
+	// Size of the copied object, used when copying between storages
+	Size int64 `json:"size,omitempty"`
+	// ContentType and Metadata of the entry are set on the copied object, which keeps
+	// those of its source in tasks generated before they were recorded
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	// SSEAlgorithm (if set) encrypts the copied object on S3, with the KMS key
+	// SSEKMSKeyID (if set)
+	SSEAlgorithm string `json:"sse_algorithm,omitempty"`
+	SSEKMSKeyID  string `json:"sse_kms_key_id,omitempty"`
+}
+
+// copyOpts returns options of the copy setting the content type and metadata of the entry
+// and encrypting the copied object
+func (d CopyData) copyOpts() block.CopyOpts {
+	var opts block.CopyOpts
+	if d.ContentType != "" {
+		opts.ContentType = &d.ContentType
+		opts.Metadata = d.Metadata
+	}
+	if d.SSEAlgorithm != "" {
+		opts.ServerSideEncryption = &d.SSEAlgorithm
+	}
+	if d.SSEKMSKeyID != "" {
+		opts.SSEKMSKeyID = &d.SSEKMSKeyID
+	}
+	return opts
+}
+
+// serverSideEncryption describes the S3 server-side encryption of copied objects
+type serverSideEncryption struct {
+	algorithm string
+	kmsKeyID  string
+}
+
+// entryContentType returns the Content-Type of entry exported objects are served with
+func entryContentType(entry catalog.Entry) string {
+	if contentType := mime.TypeByExtension(path.Ext(entry.Path)); contentType != "" {
+		return contentType
+	}
+	return defaultContentType
 }
 
 type DeleteData struct {
@@ -53,10 +114,33 @@ type SuccessData struct {
 }
 
 type FinishData struct {
-	Repo       string `json:"repo"`
-	Branch     string `json:"branch"`
-	CommitRef  string `json:"commitRef"`
+	// ExportID identifies the export run to end, empty for exports started before runs
+	// were recorded
+	ExportID  string `json:"export_id,omitempty"`
+	RefExport bool   `json:"ref_export,omitempty"`
+	Repo      string `json:"repo"`
+	Branch    string `json:"branch"`
+	CommitRef string `json:"commitRef"`
+	// StartTime is the time the export was requested, for measuring its duration
+	StartTime time.Time `json:"start_time,omitempty"`
+	// Repair is set for exports retrying the failed tasks of a failed export, which end
+	// in ExportStatusRepaired when successful
+	Repair bool `json:"repair,omitempty"`
+	// Statuses are written for each export configuration of the branch
+	Statuses []FinishStatus `json:"statuses"`
+}
+
+// FinishStatus describes the status written for a single export configuration
+type FinishStatus struct {
 	StatusPath string `json:"status_path"`
+	// Prefix, IncludePrefixes and ExcludeGlobs select the entries exported by the
+	// configuration
+	Prefix          string   `json:"prefix,omitempty"`
+	IncludePrefixes []string `json:"include_prefixes,omitempty"`
+	ExcludeGlobs    []string `json:"exclude_globs,omitempty"`
+	// ManifestExportPath (if set) is the export path of the objects listed in a manifest
+	// written to StatusPath after a successful export
+	ManifestExportPath string `json:"manifest_export_path,omitempty"`
 }
 
 // Returns the "dirname" of path: everything up to the last "/" (excluding that slash).  If
@@ -145,7 +229,9 @@ type SuccessTasksTreeGenerator struct {
 	idGen                   TaskIDGenerator
 	successDirectoriesCache *DirMatchCache
 	makeDestination         func(string) string
-	finishedTask            parade.TaskData
+	finishedTask            *parade.TaskData
+	// sharesFinishedTask is set when finishedTask is generated by another generator
+	sharesFinishedTask      bool
 	successTaskForDirectory map[string]parade.TaskData
 }
 
@@ -156,7 +242,7 @@ func NewSuccessTasksTreeGenerator(exportID string, generateSuccessFor func(path
 		idGen:                   idGen,
 		successDirectoriesCache: NewDirMatchCache(generateSuccessFor),
 		makeDestination:         makeDestination,
-		finishedTask: parade.TaskData{
+		finishedTask: &parade.TaskData{
 			ID:                idGen.finishedTaskID(),
 			Action:            DoneAction,
 			Body:              finishBody,
@@ -216,19 +302,44 @@ func (s *SuccessTasksTreeGenerator) GenerateTasksTo(tasks []parade.TaskData) []p
 	for _, task := range s.successTaskForDirectory {
 		tasks = append(tasks, task)
 	}
-	tasks = append(tasks, s.finishedTask)
+	if !s.sharesFinishedTask {
+		tasks = append(tasks, *s.finishedTask)
+	}
 	return tasks
 }
 
+// exportCounts counts the objects and bytes exported by generated tasks
+type exportCounts struct {
+	objectsCopied  int64
+	objectsDeleted int64
+	bytesCopied    int64
+}
+
+func (c *exportCounts) add(diff catalog.Difference) {
+	switch diff.Type {
+	case catalog.DifferenceTypeAdded, catalog.DifferenceTypeChanged:
+		c.objectsCopied++
+		c.bytesCopied += diff.Size
+	case catalog.DifferenceTypeRemoved:
+		c.objectsDeleted++
+	}
+}
+
 // makeDiffTaskBody fills TaskData *out with id, action and a body to make it a task to
-// perform diff.
-func makeDiffTaskBody(out *parade.TaskData, idGen TaskIDGenerator, diff catalog.Difference, makeDestination func(string) string, makeSource func(string) string) error {
+// perform diff, encrypting copied objects with sse.
+func makeDiffTaskBody(out *parade.TaskData, idGen TaskIDGenerator, repository string, diff catalog.Difference, makeDestination func(string) string, makeSource func(string) string, sse serverSideEncryption) error {
 	var data interface{}
 	switch diff.Type {
 	case catalog.DifferenceTypeAdded, catalog.DifferenceTypeChanged:
 		data = CopyData{
-			From: makeSource(diff.PhysicalAddress),
-			To:   makeDestination(diff.Path),
+			From:         makeSource(diff.PhysicalAddress),
+			To:           makeDestination(diff.Path),
+			Repository:   repository,
+			Size:         diff.Size,
+			ContentType:  entryContentType(diff.Entry),
+			Metadata:     diff.Metadata,
+			SSEAlgorithm: sse.algorithm,
+			SSEKMSKeyID:  sse.kmsKeyID,
 		}
 		out.ID = idGen.CopyTaskID(diff.Path)
 		out.Action = CopyAction
@@ -252,33 +363,58 @@ func makeDiffTaskBody(out *parade.TaskData, idGen TaskIDGenerator, diff catalog.
 
 // TasksGenerator generates tasks from diffs iteratively.
 type TasksGenerator struct {
-	ExportID              string
-	DstPrefix             string
-	GenerateSuccessFor    func(path string) bool
-	NumTries              int
+	ExportID           string
+	DstPrefix          string
+	GenerateSuccessFor func(path string) bool
+	NumTries           int
+	// RetryBackoff (if positive) delays retrying a failed file operation task, doubling
+	// the delay on every try up to maxRetryDelay.
+	RetryBackoff time.Duration
+	// Parallelism limits the number of file operation tasks that parade can perform
+	// concurrently, 0 for no limit.  Tasks are chained into Parallelism lanes, each task
+	// signalling the next task in its lane.
+	Parallelism int
+	// Repository (if set) is the exported repository, limiting copies by its export
+	// bandwidth quota
+	Repository string
+
+	// lanes holds the last task generated in each lane, until the next task in the lane
+	// is generated and added to its signals
+	lanes                 []*parade.TaskData
+	counts                exportCounts
+	numFileTasks          int
+	sse                   serverSideEncryption
 	makeSource            func(string) string
 	makeDestination       func(string) string
 	idGen                 TaskIDGenerator
 	successTasksGenerator SuccessTasksTreeGenerator
 }
 
-func GetStartTasks(repo, branch, fromCommitRef, toCommitRef, exportID string, config catalog.ExportConfiguration) ([]parade.TaskData, error) {
-	one, zero := 1, 0
-	data := StartData{
+// GetStartTasks returns the start task of an export starting now, expanding the paths of
+// configs
+func GetStartTasks(repo, branch, fromCommitRef, toCommitRef, exportID string, configs []catalog.ExportConfiguration) ([]parade.TaskData, error) {
+	startTime := time.Now()
+	return getStartTasks(StartData{
 		Repo:          repo,
 		Branch:        branch,
 		FromCommitRef: fromCommitRef,
 		ToCommitRef:   toCommitRef,
 		ExportID:      exportID,
-		ExportConfig:  config,
-	}
+		ExportConfigs: expandPaths(configs, branch, toCommitRef, startTime),
+		StartTime:     startTime,
+	})
+}
+
+// getStartTasks returns the start task of the export of data
+func getStartTasks(data StartData) ([]parade.TaskData, error) {
+	one, zero := 1, 0
 	body, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize %+v: %w", data, err)
 	}
 
 	bodyStr := string(body)
-	idGen := TaskIDGenerator(exportID)
+	idGen := TaskIDGenerator(data.ExportID)
 	tasks := make([]parade.TaskData, 1)
 	tasks[0] = parade.TaskData{
 		ID:                idGen.startedTaskID(),
@@ -317,14 +453,27 @@ func NewTasksGenerator(exportID string, dstPrefix string, generateSuccessFor fun
 	}
 }
 
+// configure sets the parallelism, retry policy and encryption of generated tasks from config
+func (e *TasksGenerator) configure(config catalog.ExportConfiguration) {
+	e.Parallelism = config.Parallelism
+	e.RetryBackoff = config.RetryBackoff
+	e.sse = serverSideEncryption{algorithm: config.SSEAlgorithm, kmsKeyID: config.SSEKMSKeyID}
+	if config.MaxAttempts > 0 {
+		e.NumTries = config.MaxAttempts
+	}
+}
+
 // Add translates diffs into many tasks and remembers "generate success" tasks for Finish.  It
 // returns some tasks that can already be added.
 func (e *TasksGenerator) Add(diffs catalog.Differences) ([]parade.TaskData, error) {
 	const initialSize = 1_000
 
-	zero := 0
+	zero, one := 0, 1
 
 	ret := make([]parade.TaskData, 0, initialSize)
+	if e.Parallelism > 0 && e.lanes == nil {
+		e.lanes = make([]*parade.TaskData, e.Parallelism)
+	}
 
 	// Create file operation tasks to return
 	for _, diff := range diffs {
@@ -337,26 +486,233 @@ func (e *TasksGenerator) Add(diffs catalog.Differences) ([]parade.TaskData, erro
 			MaxTries:          &e.NumTries,
 			TotalDependencies: &zero, // Depends only on a start task
 		}
-		err := makeDiffTaskBody(&task, e.idGen, diff, e.makeDestination, e.makeSource)
+		if e.RetryBackoff > 0 {
+			task.RetryBaseDelay = &e.RetryBackoff
+			task.RetryMaxDelay = &maxRetryDelay
+		}
+		err := makeDiffTaskBody(&task, e.idGen, e.Repository, diff, e.makeDestination, e.makeSource, e.sse)
 		if err != nil {
 			return ret, err
 		}
+		e.counts.add(diff)
 		id, err := e.successTasksGenerator.AddFor(diff.Path)
 		if err != nil {
 			return ret, fmt.Errorf("generate tasks after %+v: %w", diff, err)
 		}
 		task.ToSignalAfter = []parade.TaskID{id}
 
-		ret = append(ret, task)
+		if e.lanes == nil {
+			ret = append(ret, task)
+			continue
+		}
+		lane := e.numFileTasks % len(e.lanes)
+		e.numFileTasks++
+		if prev := e.lanes[lane]; prev != nil {
+			prev.ToSignalAfter = append(prev.ToSignalAfter, task.ID)
+			task.TotalDependencies = &one // Runs after the previous task in its lane
+			ret = append(ret, *prev)
+		}
+		e.lanes[lane] = &task
 	}
 
 	return ret, nil
 }
 
-// Finish ends tasks generation, releasing any tasks for success and finish.
+// Finish ends tasks generation, releasing the last task of each lane and any tasks for
+// success and finish.
 func (e *TasksGenerator) Finish() ([]parade.TaskData, error) {
-	ret := make([]parade.TaskData, 0)
+	ret := make([]parade.TaskData, 0, len(e.lanes))
+	for _, task := range e.lanes {
+		if task != nil {
+			ret = append(ret, *task)
+		}
+	}
+	e.lanes = nil
 	ret = e.successTasksGenerator.GenerateTasksTo(ret)
 
 	return ret, nil
 }
+
+func (e *TasksGenerator) generatedCounts() exportCounts {
+	return e.counts
+}
+
+func (e *TasksGenerator) finishTask() *parade.TaskData {
+	return e.successTasksGenerator.finishedTask
+}
+
+func (e *TasksGenerator) shareFinishTask(task *parade.TaskData) {
+	e.successTasksGenerator.finishedTask = task
+	e.successTasksGenerator.sharesFinishedTask = true
+}
+
+// configTasksGenerator generates the tasks of a single export configuration
+type configTasksGenerator interface {
+	Add(diffs catalog.Differences) ([]parade.TaskData, error)
+	Finish() ([]parade.TaskData, error)
+	// generatedCounts returns the objects and bytes exported by the generated tasks
+	generatedCounts() exportCounts
+	// finishTask returns the task that generated tasks end in
+	finishTask() *parade.TaskData
+	// shareFinishTask makes generated tasks end in task, which another generator generates
+	shareFinishTask(task *parade.TaskData)
+}
+
+// MultiTasksGenerator generates tasks exporting diffs to every export configuration of a
+// branch whose prefix and filters they match, and to every path of configurations with
+// additional paths.  All generated tasks end in a single finish task.
+type MultiTasksGenerator struct {
+	filters []pathFilter
+	// propagateDeletes holds whether each configuration exports removed entries
+	propagateDeletes []bool
+	fromRefs         []string
+	generators       []configTasksGenerator
+}
+
+// NewMultiTasksGenerator returns a generator of tasks exporting toRef of repo to configs.
+// Configurations in incremental mode export the diff from lastExportedRef, other
+// configurations export all entries.
+func NewMultiTasksGenerator(exportID, repo, lastExportedRef, toRef string, configs []catalog.ExportConfiguration, finishBody *string, storageNamespace string) *MultiTasksGenerator {
+	m := &MultiTasksGenerator{
+		filters:          make([]pathFilter, len(configs)),
+		propagateDeletes: make([]bool, len(configs)),
+		fromRefs:         make([]string, len(configs)),
+		generators:       make([]configTasksGenerator, len(configs)),
+	}
+	for i, config := range configs {
+		generatorID := exportID
+		if i > 0 {
+			// tasks of each configuration need their own IDs, as the same path may be
+			// exported to several destinations
+			generatorID = fmt.Sprintf("%s:%d", exportID, i)
+		}
+		config := config
+		newGenerator := func(generatorID, path string) configTasksGenerator {
+			return newConfigTasksGenerator(generatorID, repo, toRef, path, config, finishBody, storageNamespace)
+		}
+		var generator configTasksGenerator
+		if len(config.AdditionalPaths) > 0 {
+			generator = newFanOutTasksGenerator(exportID, generatorID, config, finishBody, newGenerator)
+		} else {
+			generator = newGenerator(generatorID, config.Path)
+		}
+		if i > 0 {
+			generator.shareFinishTask(m.generators[0].finishTask())
+		}
+		m.generators[i] = generator
+		m.filters[i] = newPathFilter(config)
+		m.propagateDeletes[i] = config.PropagateDeletes
+		if config.Mode != catalog.ExportModeFull {
+			m.fromRefs[i] = lastExportedRef
+		}
+	}
+	return m
+}
+
+// newConfigTasksGenerator returns a generator of tasks exporting toRef of repo to path in the
+// format of config
+func newConfigTasksGenerator(generatorID, repo, toRef, path string, config catalog.ExportConfiguration, finishBody *string, storageNamespace string) configTasksGenerator {
+	if config.Format == catalog.ExportFormatSymlink {
+		symlinkGenerator := NewSymlinkTasksGenerator(generatorID, repo, toRef, path, finishBody, storageNamespace)
+		symlinkGenerator.configure(config)
+		return symlinkGenerator
+	}
+	copyGenerator := NewTasksGenerator(generatorID, path, getGenerateSuccess(config.LastKeysInPrefixRegexp), finishBody, storageNamespace)
+	copyGenerator.configure(config)
+	copyGenerator.Repository = repo
+	return copyGenerator
+}
+
+// FromRefs returns the distinct refs from which diffs should be generated and passed to
+// AddDiffFrom.  An empty ref stands for all entries of the exported ref.
+func (m *MultiTasksGenerator) FromRefs() []string {
+	ret := make([]string, 0, len(m.fromRefs))
+	for _, fromRef := range m.fromRefs {
+		found := false
+		for _, r := range ret {
+			if r == fromRef {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, fromRef)
+		}
+	}
+	return ret
+}
+
+// AddDiffFrom translates diffs from fromRef into tasks of the configurations exporting from
+// fromRef.  It returns some tasks that can already be added.
+func (m *MultiTasksGenerator) AddDiffFrom(fromRef string, diffs catalog.Differences) ([]parade.TaskData, error) {
+	return m.add(diffs, func(i int) bool { return m.fromRefs[i] == fromRef })
+}
+
+// Add translates diffs into tasks of all configurations.  It returns some tasks that can
+// already be added.
+func (m *MultiTasksGenerator) Add(diffs catalog.Differences) ([]parade.TaskData, error) {
+	return m.add(diffs, func(int) bool { return true })
+}
+
+func (m *MultiTasksGenerator) add(diffs catalog.Differences, selected func(i int) bool) ([]parade.TaskData, error) {
+	var ret []parade.TaskData
+	for i := range m.generators {
+		if !selected(i) {
+			continue
+		}
+		tasks, err := m.addTo(i, diffs)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, tasks...)
+	}
+	return ret, nil
+}
+
+// addTo translates the diffs exported by the i'th configuration into its tasks
+func (m *MultiTasksGenerator) addTo(i int, diffs catalog.Differences) ([]parade.TaskData, error) {
+	filter := m.filters[i]
+	propagateDeletes := m.propagateDeletes[i]
+	matching := diffs
+	if !filter.isEmpty() || !propagateDeletes {
+		matching = make(catalog.Differences, 0, len(diffs))
+		for _, diff := range diffs {
+			if !propagateDeletes && diff.Type == catalog.DifferenceTypeRemoved {
+				continue
+			}
+			if filter.matches(diff.Path) {
+				matching = append(matching, diff)
+			}
+		}
+	}
+	if len(matching) == 0 {
+		return nil, nil
+	}
+	return m.generators[i].Add(matching)
+}
+
+// counts returns the objects and bytes exported by the tasks generated for all configurations
+func (m *MultiTasksGenerator) counts() exportCounts {
+	var ret exportCounts
+	for _, generator := range m.generators {
+		counts := generator.generatedCounts()
+		ret.objectsCopied += counts.objectsCopied
+		ret.objectsDeleted += counts.objectsDeleted
+		ret.bytesCopied += counts.bytesCopied
+	}
+	return ret
+}
+
+// Finish ends tasks generation of all configurations, returning the remaining tasks and the
+// finish task.
+func (m *MultiTasksGenerator) Finish() ([]parade.TaskData, error) {
+	var ret []parade.TaskData
+	for _, generator := range m.generators {
+		tasks, err := generator.Finish()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, tasks...)
+	}
+	return ret, nil
+}
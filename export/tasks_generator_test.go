@@ -2,8 +2,11 @@ package export_test
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 	"github.com/treeverse/lakefs/catalog"
@@ -255,7 +258,7 @@ func TestTasksGenerator_Simple(t *testing.T) {
 		Entry: catalog.Entry{Path: "add1", PhysicalAddress: "add1"},
 	}, {
 		Type:  catalog.DifferenceTypeChanged,
-		Entry: catalog.Entry{Path: "change1", PhysicalAddress: "change1"},
+		Entry: catalog.Entry{Path: "change1", PhysicalAddress: "change1", Metadata: catalog.Metadata{"owner": "etl"}},
 	}, {
 		Type:  catalog.DifferenceTypeRemoved,
 		Entry: catalog.Entry{Path: "remove1", PhysicalAddress: "remove1"},
@@ -279,8 +282,9 @@ func TestTasksGenerator_Simple(t *testing.T) {
 			ID:     idGen.CopyTaskID("add1"),
 			Action: export.CopyAction,
 			Body: toJSON(t, export.CopyData{
-				From: "testsrc://prefix/add1",
-				To:   "testfs://prefix/add1",
+				From:        "testsrc://prefix/add1",
+				To:          "testfs://prefix/add1",
+				ContentType: "application/octet-stream",
 			}),
 			StatusCode:        parade.TaskPending,
 			TotalDependencies: &zero,
@@ -290,8 +294,10 @@ func TestTasksGenerator_Simple(t *testing.T) {
 			ID:     idGen.CopyTaskID("change1"),
 			Action: export.CopyAction,
 			Body: toJSON(t, export.CopyData{
-				From: "testsrc://prefix/change1",
-				To:   "testfs://prefix/change1",
+				From:        "testsrc://prefix/change1",
+				To:          "testfs://prefix/change1",
+				ContentType: "application/octet-stream",
+				Metadata:    map[string]string{"owner": "etl"},
 			}),
 			StatusCode:        parade.TaskPending,
 			TotalDependencies: &zero,
@@ -431,3 +437,217 @@ func TestTasksGenerator_SuccessFiles(t *testing.T) {
 		}
 	}
 }
+
+func TestTasksGenerator_Parallelism(t *testing.T) {
+	const (
+		numDiffs    = 7
+		parallelism = 3
+	)
+	var catalogDiffs catalog.Differences
+	for i := 0; i < numDiffs; i++ {
+		path := fmt.Sprintf("file%d", i)
+		catalogDiffs = append(catalogDiffs, catalog.Difference{
+			Type:  catalog.DifferenceTypeAdded,
+			Entry: catalog.Entry{Path: path, PhysicalAddress: path},
+		})
+	}
+	gen := export.NewTasksGenerator("par", "testfs://prefix/", func(_ string) bool { return false }, nil, "")
+	gen.Parallelism = parallelism
+
+	var tasks []parade.TaskData
+	for o := 0; o < numDiffs; o += 2 {
+		end := o + 2
+		if end > numDiffs {
+			end = numDiffs
+		}
+		moreTasks, err := gen.Add(catalogDiffs[o:end])
+		if err != nil {
+			t.Fatalf("failed to add tasks %d..%d: %s", o, end, err)
+		}
+		tasks = append(tasks, moreTasks...)
+	}
+	moreTasks, err := gen.Finish()
+	if err != nil {
+		t.Fatalf("failed to finish generating tasks: %s", err)
+	}
+	tasks = append(tasks, moreTasks...)
+
+	copyTasks := make(map[parade.TaskID]parade.TaskData)
+	for _, task := range tasks {
+		if task.Action == export.CopyAction {
+			copyTasks[task.ID] = task
+		}
+	}
+	if len(copyTasks) != numDiffs {
+		t.Fatalf("got %d copy tasks, expected %d", len(copyTasks), numDiffs)
+	}
+	idGen := export.TaskIDGenerator("par")
+	for i := 0; i < numDiffs; i++ {
+		task := copyTasks[idGen.CopyTaskID(catalogDiffs[i].Path)]
+		expectedDeps := 1
+		if i < parallelism {
+			expectedDeps = 0
+		}
+		if *task.TotalDependencies != expectedDeps {
+			t.Errorf("task %d has %d dependencies, expected %d", i, *task.TotalDependencies, expectedDeps)
+		}
+		expectedSignals := []parade.TaskID{"par:finish"}
+		if i+parallelism < numDiffs {
+			expectedSignals = append(expectedSignals, idGen.CopyTaskID(catalogDiffs[i+parallelism].Path))
+		}
+		if diffs := deep.Equal(expectedSignals, task.ToSignalAfter); diffs != nil {
+			t.Errorf("task %d unexpected signals: %s", i, diffs)
+		}
+	}
+}
+
+func TestTasksGenerator_RetryBackoff(t *testing.T) {
+	catalogDiffs := catalog.Differences{{
+		Type:  catalog.DifferenceTypeAdded,
+		Entry: catalog.Entry{Path: "add1", PhysicalAddress: "add1"},
+	}, {
+		Type:  catalog.DifferenceTypeRemoved,
+		Entry: catalog.Entry{Path: "remove1", PhysicalAddress: "remove1"},
+	}}
+	gen := export.NewTasksGenerator("retry", "testfs://prefix/", func(_ string) bool { return false }, nil, "")
+	gen.NumTries = 8
+	gen.RetryBackoff = 10 * time.Second
+	tasks, err := gen.Add(catalogDiffs)
+	if err != nil {
+		t.Fatalf("failed to add tasks: %s", err)
+	}
+	if len(tasks) != len(catalogDiffs) {
+		t.Fatalf("got %d tasks, expected %d", len(tasks), len(catalogDiffs))
+	}
+	for _, task := range tasks {
+		if task.MaxTries == nil || *task.MaxTries != 8 {
+			t.Errorf("task %s max tries %v, expected 8", task.ID, task.MaxTries)
+		}
+		if task.RetryBaseDelay == nil || *task.RetryBaseDelay != 10*time.Second {
+			t.Errorf("task %s retry base delay %v, expected 10s", task.ID, task.RetryBaseDelay)
+		}
+		if task.RetryMaxDelay == nil || *task.RetryMaxDelay < *task.RetryBaseDelay {
+			t.Errorf("task %s retry max delay %v, expected at least the base delay", task.ID, task.RetryMaxDelay)
+		}
+	}
+}
+
+func TestMultiTasksGenerator(t *testing.T) {
+	catalogDiffs := catalog.Differences{{
+		Type:  catalog.DifferenceTypeAdded,
+		Entry: catalog.Entry{Path: "a/1", PhysicalAddress: "a1"},
+	}, {
+		Type:  catalog.DifferenceTypeAdded,
+		Entry: catalog.Entry{Path: "b/1", PhysicalAddress: "b1"},
+	}, {
+		Type:  catalog.DifferenceTypeRemoved,
+		Entry: catalog.Entry{Path: "c/1"},
+	}}
+	configs := []catalog.ExportConfiguration{
+		{Path: "testfs://all", PropagateDeletes: true},
+		{Prefix: "a/", Path: "testfs://a", Mode: catalog.ExportModeFull},
+		{Prefix: "b/", Path: "testfs://b"},
+	}
+	gen := export.NewMultiTasksGenerator("multi", "repo", "commit1", "commit2", configs, nil, "testfs://storage")
+	if diffs := deep.Equal(gen.FromRefs(), []string{"commit1", ""}); diffs != nil {
+		t.Fatalf("unexpected from refs: %s", diffs)
+	}
+	tasks, err := gen.AddDiffFrom("commit1", catalogDiffs)
+	if err != nil {
+		t.Fatalf("failed to add tasks: %s", err)
+	}
+	fullTasks, err := gen.AddDiffFrom("", catalogDiffs)
+	if err != nil {
+		t.Fatalf("failed to add tasks: %s", err)
+	}
+	tasks = append(tasks, fullTasks...)
+	finishTasks, err := gen.Finish()
+	if err != nil {
+		t.Fatalf("failed to finish: %s", err)
+	}
+	tasks = append(tasks, finishTasks...)
+
+	ids := make(map[parade.TaskID]struct{})
+	destinations := make([]string, 0)
+	var finishTask *parade.TaskData
+	for i, task := range tasks {
+		if _, ok := ids[task.ID]; ok {
+			t.Errorf("duplicate task ID %s", task.ID)
+		}
+		ids[task.ID] = struct{}{}
+		switch task.Action {
+		case export.CopyAction:
+			var data export.CopyData
+			if err := json.Unmarshal([]byte(*task.Body), &data); err != nil {
+				t.Fatal(err)
+			}
+			destinations = append(destinations, data.To)
+		case export.DeleteAction:
+			var data export.DeleteData
+			if err := json.Unmarshal([]byte(*task.Body), &data); err != nil {
+				t.Fatal(err)
+			}
+			destinations = append(destinations, data.File)
+		case export.DoneAction:
+			if finishTask != nil {
+				t.Errorf("got second finish task %s", task.ID)
+			}
+			finishTask = &tasks[i]
+		}
+	}
+	sort.Strings(destinations)
+	expected := []string{"testfs://a/a/1", "testfs://all/a/1", "testfs://all/b/1", "testfs://all/c/1", "testfs://b/b/1"}
+	if diffs := deep.Equal(destinations, expected); diffs != nil {
+		t.Errorf("unexpected destinations: %s", diffs)
+	}
+	if finishTask == nil {
+		t.Fatal("no finish task")
+	}
+	if *finishTask.TotalDependencies != len(expected) {
+		t.Errorf("finish task has %d dependencies, expected %d", *finishTask.TotalDependencies, len(expected))
+	}
+}
+
+func TestMultiTasksGenerator_ServerSideEncryption(t *testing.T) {
+	catalogDiffs := catalog.Differences{{
+		Type:  catalog.DifferenceTypeAdded,
+		Entry: catalog.Entry{Path: "a/1", PhysicalAddress: "a1"},
+	}, {
+		Type:  catalog.DifferenceTypeAdded,
+		Entry: catalog.Entry{Path: "b/1", PhysicalAddress: "b1"},
+	}}
+	configs := []catalog.ExportConfiguration{
+		{Prefix: "a/", Path: "s3://a", SSEAlgorithm: catalog.ExportSSEAlgorithmKMS, SSEKMSKeyID: "key"},
+		{Prefix: "b/", Path: "s3://b"},
+	}
+	gen := export.NewMultiTasksGenerator("sse", "repo", "commit1", "commit2", configs, nil, "s3://storage")
+	tasks, err := gen.AddDiffFrom("commit1", catalogDiffs)
+	if err != nil {
+		t.Fatalf("failed to add tasks: %s", err)
+	}
+	expected := map[string]export.CopyData{
+		"s3://a/a/1": {SSEAlgorithm: catalog.ExportSSEAlgorithmKMS, SSEKMSKeyID: "key"},
+		"s3://b/b/1": {},
+	}
+	for _, task := range tasks {
+		if task.Action != export.CopyAction {
+			continue
+		}
+		var data export.CopyData
+		if err := json.Unmarshal([]byte(*task.Body), &data); err != nil {
+			t.Fatal(err)
+		}
+		e, ok := expected[data.To]
+		if !ok {
+			t.Errorf("unexpected copy to %s", data.To)
+			continue
+		}
+		delete(expected, data.To)
+		if data.SSEAlgorithm != e.SSEAlgorithm || data.SSEKMSKeyID != e.SSEKMSKeyID {
+			t.Errorf("copy to %s encrypted with %q key %q, expected %q key %q", data.To, data.SSEAlgorithm, data.SSEKMSKeyID, e.SSEAlgorithm, e.SSEKMSKeyID)
+		}
+	}
+	if len(expected) > 0 {
+		t.Errorf("no copies to %v", expected)
+	}
+}
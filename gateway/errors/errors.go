@@ -67,6 +67,7 @@ const (
 	ErrInvalidMaxUploads
 	ErrInvalidMaxParts
 	ErrInvalidPartNumberMarker
+	ErrInvalidPartNumber
 	ErrInvalidRequestBody
 	ErrInvalidCopySource
 	ErrInvalidMetadataDirective
@@ -169,6 +170,13 @@ const (
 
 	// Lakefs errors
 	ERRLakeFSNotSupported
+	ErrQuotaExceeded
+	ErrReadOnlyMode
+	ErrInvalidMetadata
+	ErrRepositoryReadOnly
+	ErrInvalidTag
+	ErrInvalidTaggingDirective
+	ErrCommitJobInProgress
 )
 
 type errorCodeMap map[APIErrorCode]APIError
@@ -236,6 +244,11 @@ var Codes = errorCodeMap{
 		Description:    "Argument partNumberMarker must be an integer.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrInvalidPartNumber: {
+		Code:           "InvalidArgument",
+		Description:    "Part number must be an integer between 1 and the maximum number of parts.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrInvalidPolicyDocument: {
 		Code:           "InvalidPolicyDocument",
 		Description:    "The content of the form does not meet the conditions specified in the policy document.",
@@ -745,4 +758,39 @@ var Codes = errorCodeMap{
 		Description:    "This operation is not supported in LakeFS",
 		HTTPStatusCode: http.StatusMethodNotAllowed,
 	},
+	ErrQuotaExceeded: {
+		Code:           "QuotaExceeded",
+		Description:    "Your proposed upload exceeds the repository quota.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrReadOnlyMode: {
+		Code:           "MethodNotAllowed",
+		Description:    "lakeFS is running in read-only mode.",
+		HTTPStatusCode: http.StatusMethodNotAllowed,
+	},
+	ErrInvalidMetadata: {
+		Code:           "InvalidArgument",
+		Description:    "Your metadata does not match the repository metadata schema.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrRepositoryReadOnly: {
+		Code:           "AccessDenied",
+		Description:    "The repository is read-only.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	ErrInvalidTag: {
+		Code:           "InvalidTag",
+		Description:    "The tag provided was not a valid tag.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTaggingDirective: {
+		Code:           "InvalidArgument",
+		Description:    "Unknown tagging directive.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrCommitJobInProgress: {
+		Code:           "OperationAborted",
+		Description:    "The branch is being committed by a commit job. Try again.",
+		HTTPStatusCode: http.StatusConflict,
+	},
 }
@@ -25,12 +25,14 @@ import (
 	"github.com/treeverse/lakefs/logging"
 	"github.com/treeverse/lakefs/permissions"
 	"github.com/treeverse/lakefs/stats"
+	uploadparams "github.com/treeverse/lakefs/upload/params"
 )
 
 type handler struct {
 	BareDomain         string
 	sc                 *ServerContext
 	operationID        string
+	readOnly           bool
 	NotFoundHandler    http.Handler
 	ServerErrorHandler http.Handler
 }
@@ -44,6 +46,7 @@ type ServerContext struct {
 	authService  simulator.GatewayAuthService
 	stats        stats.Collector
 	dedupCleaner *dedup.Cleaner
+	limits       uploadparams.Limits
 }
 
 const operationIDNotFound = "not_found_operation"
@@ -58,6 +61,7 @@ func (c *ServerContext) WithContext(ctx context.Context) *ServerContext {
 		authService:  c.authService,
 		stats:        c.stats,
 		dedupCleaner: c.dedupCleaner,
+		limits:       c.limits,
 	}
 }
 
@@ -69,6 +73,8 @@ func NewHandler(
 	bareDomain string,
 	stats stats.Collector,
 	dedupCleaner *dedup.Cleaner,
+	limits uploadparams.Limits,
+	readOnly bool,
 ) http.Handler {
 	sc := &ServerContext{
 		ctx:          context.Background(),
@@ -79,6 +85,7 @@ func NewHandler(
 		authService:  authService,
 		stats:        stats,
 		dedupCleaner: dedupCleaner,
+		limits:       limits,
 	}
 
 	// setup routes
@@ -86,6 +93,7 @@ func NewHandler(
 	h = &handler{
 		BareDomain:         bareDomain,
 		sc:                 sc,
+		readOnly:           readOnly,
 		NotFoundHandler:    http.HandlerFunc(notFound),
 		ServerErrorHandler: nil,
 	}
@@ -127,6 +135,7 @@ func authenticateOperation(s *ServerContext, writer http.ResponseWriter, request
 			s.stats.CollectEvent("s3_gateway", action)
 		},
 		DedupCleaner: s.dedupCleaner,
+		Limits:       s.limits,
 	}
 
 	// authenticate
@@ -222,6 +231,7 @@ func operation(sc *ServerContext, writer http.ResponseWriter, request *http.Requ
 			sc.stats.CollectEvent("s3_gateway", action)
 		},
 		DedupCleaner: sc.dedupCleaner,
+		Limits:       sc.limits,
 	}
 }
 
@@ -268,6 +278,9 @@ func RepoOperationHandler(sc *ServerContext, repoID string, handler operations.R
 			authOp.EncodeError(gatewayerrors.ErrInternalError.ToAPIErr())
 			return
 		}
+		if !allowRequestRate(authOp, repoID) {
+			return
+		}
 		// run callback
 		repoOperation := &operations.RepoOperation{
 			AuthenticatedOperation: authOp,
@@ -305,6 +318,9 @@ func PathOperationHandler(sc *ServerContext, repoID, refID, path string, handler
 			authOp.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
 			return
 		}
+		if !allowRequestRate(authOp, repoID) {
+			return
+		}
 
 		// run callback
 		operation := &operations.PathOperation{
@@ -396,7 +412,8 @@ func (h *handler) servePathBased(r *http.Request) http.Handler {
 	// no repository given
 	if r.Method == http.MethodGet {
 		h.operationID = "list_buckets"
-		return OperationHandler(h.sc, &operations.ListBuckets{})
+		handler := &operations.ListBuckets{}
+		return h.readOnlyGuard(handler, OperationHandler(h.sc, handler))
 	}
 	h.operationID = operationIDNotFound
 	return h.NotFoundHandler
@@ -456,7 +473,7 @@ func (h *handler) pathBasedHandler(method, repository, ref, path string) http.Ha
 		return h.NotFoundHandler
 	}
 	h.operationID = reflect.TypeOf(handler).Elem().Name()
-	return PathOperationHandler(h.sc, repository, ref, path, handler)
+	return h.readOnlyGuard(handler, PathOperationHandler(h.sc, repository, ref, path, handler))
 }
 
 func (h *handler) repositoryBasedHandlerIfValid(method, repository string) http.Handler {
@@ -485,7 +502,7 @@ func (h *handler) repositoryBasedHandler(method, repository string) http.Handler
 	}
 	h.operationID = reflect.TypeOf(handler).Elem().Name()
 
-	return RepoOperationHandler(h.sc, repository, handler)
+	return h.readOnlyGuard(handler, RepoOperationHandler(h.sc, repository, handler))
 }
 
 func SplitFirst(pth string, parts int) ([]string, bool) {
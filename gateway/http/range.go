@@ -1,13 +1,15 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 )
 
 var (
-	ErrBadRange = fmt.Errorf("unsatisfiable range")
+	ErrBadRange           = fmt.Errorf("unsatisfiable range")
+	ErrRangeOutsideSource = fmt.Errorf("range outside of source object")
 )
 
 // Range represents an RFC 2616 HTTP Range
@@ -17,18 +19,32 @@ type Range struct {
 }
 
 func (r Range) String() string {
-	return fmt.Sprintf("start=%d, end=%d (total=%d)", r.StartOffset, r.EndOffset, r.EndOffset-r.StartOffset+1)
+	return fmt.Sprintf("start=%d, end=%d (total=%d)", r.StartOffset, r.EndOffset, r.Length())
 }
 
-// ParseRange parses an HTTP RFC 2616 Range header value and returns an Range object for the given object length
-func ParseRange(spec string, length int64) (Range, error) {
-	// Amazon S3 doesn't support retrieving multiple ranges of data per GET request.
+// Length returns the number of bytes in r, whose offsets are both inclusive
+func (r Range) Length() int64 {
+	return r.EndOffset - r.StartOffset + 1
+}
+
+// ContentRange returns the Content-Range header value serving r out of an object of the given
+// length
+func (r Range) ContentRange(length int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.StartOffset, r.EndOffset, length)
+}
+
+// MaxRanges is the maximal number of ranges served by a single request
+const MaxRanges = 100
+
+// errRangeNotSatisfiable is returned by parseByteRange for a range that starts after the end of
+// the object.  Other ranges of the same request may still be served.
+var errRangeNotSatisfiable = fmt.Errorf("%w: outside of object", ErrBadRange)
+
+// parseByteRange parses a single range of an HTTP RFC 7233 Range header value for an object of
+// the given length
+func parseByteRange(spec string, length int64) (Range, error) {
 	var r Range
-	if !strings.HasPrefix(spec, "bytes=") {
-		return r, ErrBadRange
-	}
-	spec = strings.TrimPrefix(spec, "bytes=")
-	parts := strings.Split(spec, "-")
+	parts := strings.Split(strings.TrimSpace(spec), "-")
 	const rangeParts = 2
 	if len(parts) != rangeParts {
 		return r, ErrBadRange
@@ -39,42 +55,121 @@ func ParseRange(spec string, length int64) (Range, error) {
 	if len(fromString) == 0 && len(toString) == 0 {
 		return r, ErrBadRange
 	}
-	// negative only
+	// negative only: the last bytes of the object, or all of it when it is shorter
 	if len(fromString) == 0 {
-		endOffset, err := strconv.ParseInt(toString, 10, 64)
-		if err != nil || endOffset > length {
+		suffixLength, err := strconv.ParseInt(toString, 10, 64)
+		if err != nil || suffixLength < 0 {
 			return r, ErrBadRange
 		}
-		r.StartOffset = length - endOffset
+		if suffixLength == 0 || length == 0 {
+			return r, errRangeNotSatisfiable
+		}
+		if suffixLength > length {
+			suffixLength = length
+		}
+		r.StartOffset = length - suffixLength
 		r.EndOffset = length - 1
 		return r, nil
 	}
+	beginOffset, err := strconv.ParseInt(fromString, 10, 64)
+	if err != nil || beginOffset < 0 {
+		return r, ErrBadRange
+	}
 	// positive only
 	if len(toString) == 0 {
-		beginOffset, err := strconv.ParseInt(fromString, 10, 64)
-		if err != nil || beginOffset > length-1 {
-			return r, ErrBadRange
+		if beginOffset > length-1 {
+			return r, errRangeNotSatisfiable
 		}
 		r.StartOffset = beginOffset
 		r.EndOffset = length - 1
 		return r, nil
 	}
 	// both set
-	beginOffset, err := strconv.ParseInt(fromString, 10, 64)
-	if err != nil {
-		return r, ErrBadRange
-	}
 	endOffset, err := strconv.ParseInt(toString, 10, 64)
-	if err != nil {
+	if err != nil || endOffset < beginOffset {
 		return r, ErrBadRange
 	}
+	if beginOffset > length-1 {
+		return r, errRangeNotSatisfiable
+	}
 	// if endOffset exceeds length return length : this is how it works in s3 (presto for example uses range with a huge endOffset regardless to the file size)
 	if endOffset > length-1 {
 		endOffset = length - 1
 	}
-	if beginOffset > length-1 || endOffset > length-1 {
+	r.StartOffset = beginOffset
+	r.EndOffset = endOffset
+	return r, nil
+}
+
+// ParseRanges parses an HTTP RFC 7233 Range header value and returns its ranges for the given
+// object length, in the order requested.  Ranges that start after the end of the object are
+// dropped.  It returns ErrBadRange if the value is invalid or no range is left, and also if
+// the ranges add up to more than the object, when serving it whole is cheaper.
+func ParseRanges(spec string, length int64) ([]Range, error) {
+	if !strings.HasPrefix(spec, "bytes=") {
+		return nil, ErrBadRange
+	}
+	specs := strings.Split(strings.TrimPrefix(spec, "bytes="), ",")
+	if len(specs) > MaxRanges {
+		return nil, ErrBadRange
+	}
+	ranges := make([]Range, 0, len(specs))
+	var total int64
+	for _, rangeSpec := range specs {
+		r, err := parseByteRange(rangeSpec, length)
+		if errors.Is(err, errRangeNotSatisfiable) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		total += r.Length()
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == 0 || total > length {
+		return nil, ErrBadRange
+	}
+	return ranges, nil
+}
+
+// ParseRange parses an HTTP RFC 7233 Range header value of a single range and returns a Range
+// object for the given object length
+func ParseRange(spec string, length int64) (Range, error) {
+	ranges, err := ParseRanges(spec, length)
+	if err != nil {
+		return Range{}, err
+	}
+	if len(ranges) != 1 {
+		return Range{}, ErrBadRange
+	}
+	return ranges[0], nil
+}
+
+// ParseCopySourceRange parses an x-amz-copy-source-range header value for a source object of
+// the given length.  Unlike ParseRange both offsets are required and the range is not clamped
+// to the object: a syntactically invalid spec returns ErrBadRange, and a range outside the
+// object returns ErrRangeOutsideSource.
+func ParseCopySourceRange(spec string, length int64) (Range, error) {
+	var r Range
+	if !strings.HasPrefix(spec, "bytes=") {
+		return r, ErrBadRange
+	}
+	parts := strings.Split(strings.TrimPrefix(spec, "bytes="), "-")
+	const rangeParts = 2
+	if len(parts) != rangeParts {
 		return r, ErrBadRange
 	}
+	beginOffset, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || beginOffset < 0 {
+		return r, ErrBadRange
+	}
+	endOffset, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || endOffset < beginOffset {
+		return r, ErrBadRange
+	}
+	if endOffset > length-1 {
+		return r, ErrRangeOutsideSource
+	}
 	r.StartOffset = beginOffset
 	r.EndOffset = endOffset
 	return r, nil
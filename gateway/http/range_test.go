@@ -1,9 +1,12 @@
 package http_test
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/go-test/deep"
 	"github.com/treeverse/lakefs/gateway/http"
 )
 
@@ -19,7 +22,11 @@ func TestParseRange(t *testing.T) {
 		{"bytes=0-20", 10, false, 0, 9},
 		{"bytes=-20", 50, false, 30, 49},
 		{"bytes=20-", 50, false, 20, 49},
-		{"bytes=-20", 10, true, 0, 0},
+		{"bytes=-20", 10, false, 0, 9},
+		{"bytes=-0", 10, true, 0, 0},
+		{"bytes=-1", 0, true, 0, 0},
+		{"bytes=5-3", 10, true, 0, 0},
+		{"bytes=0-1,5-6", 10, true, 0, 0},
 		{"bytes=0-20", 20, false, 0, 19},
 		{"bytes=0-19", 20, false, 0, 19},
 		{"bytes=-0-19", 20, true, 0, 0},
@@ -49,3 +56,73 @@ func TestParseRange(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRanges(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Spec           string
+		Length         int64
+		ExpectedError  error
+		ExpectedRanges []http.Range
+	}{
+		{Name: "single", Spec: "bytes=0-9", Length: 100, ExpectedRanges: []http.Range{{StartOffset: 0, EndOffset: 9}}},
+		{Name: "footer", Spec: "bytes=-8", Length: 100, ExpectedRanges: []http.Range{{StartOffset: 92, EndOffset: 99}}},
+		{Name: "multiple", Spec: "bytes=0-9, 50-59,-5", Length: 100, ExpectedRanges: []http.Range{
+			{StartOffset: 0, EndOffset: 9}, {StartOffset: 50, EndOffset: 59}, {StartOffset: 95, EndOffset: 99},
+		}},
+		{Name: "unsatisfiable dropped", Spec: "bytes=200-300,0-9", Length: 100, ExpectedRanges: []http.Range{{StartOffset: 0, EndOffset: 9}}},
+		{Name: "all unsatisfiable", Spec: "bytes=200-300,100-", Length: 100, ExpectedError: http.ErrBadRange},
+		{Name: "invalid range", Spec: "bytes=0-9,x-1", Length: 100, ExpectedError: http.ErrBadRange},
+		{Name: "empty range", Spec: "bytes=0-9,", Length: 100, ExpectedError: http.ErrBadRange},
+		{Name: "larger than object", Spec: "bytes=0-59,40-99", Length: 100, ExpectedError: http.ErrBadRange},
+		{Name: "too many", Spec: "bytes=0-0" + strings.Repeat(",0-0", http.MaxRanges), Length: 1000, ExpectedError: http.ErrBadRange},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			ranges, err := http.ParseRanges(c.Spec, c.Length)
+			if !errors.Is(err, c.ExpectedError) {
+				t.Fatalf("got err=%v, expected %v", err, c.ExpectedError)
+			}
+			if diff := deep.Equal(ranges, c.ExpectedRanges); diff != nil {
+				t.Errorf("unexpected ranges: %s", diff)
+			}
+		})
+	}
+}
+
+func TestParseCopySourceRange(t *testing.T) {
+	cases := []struct {
+		Spec          string
+		Length        int
+		ExpectedError error
+		ExpectedStart int
+		ExpectedEnd   int
+	}{
+		{"bytes=0-20", 50, nil, 0, 20},
+		{"bytes=0-19", 20, nil, 0, 19},
+		{"bytes=5-5", 20, nil, 5, 5},
+		{"bytes=0-20", 20, http.ErrRangeOutsideSource, 0, 0},
+		{"bytes=20-20", 20, http.ErrRangeOutsideSource, 0, 0},
+		{"bytes=-20", 50, http.ErrBadRange, 0, 0},
+		{"bytes=20-", 50, http.ErrBadRange, 0, 0},
+		{"bytes=10-5", 50, http.ErrBadRange, 0, 0},
+		{"bytess=0-19", 20, http.ErrBadRange, 0, 0},
+		{"0-19", 20, http.ErrBadRange, 0, 0},
+		{"bytes=0-foo", 20, http.ErrBadRange, 0, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s_length_%d", c.Spec, c.Length), func(t *testing.T) {
+			r, err := http.ParseCopySourceRange(c.Spec, int64(c.Length))
+			if err != c.ExpectedError {
+				t.Fatalf("got err=%v, expected err=%v", err, c.ExpectedError)
+			}
+			if err != nil {
+				return
+			}
+			if r.StartOffset != int64(c.ExpectedStart) || r.EndOffset != int64(c.ExpectedEnd) {
+				t.Fatalf("expected range %d-%d, got %s", c.ExpectedStart, c.ExpectedEnd, r)
+			}
+		})
+	}
+}
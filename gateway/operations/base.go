@@ -18,6 +18,7 @@ import (
 	"github.com/treeverse/lakefs/httputil"
 	"github.com/treeverse/lakefs/logging"
 	"github.com/treeverse/lakefs/permissions"
+	uploadparams "github.com/treeverse/lakefs/upload/params"
 )
 
 const StorageClassHeader = "x-amz-storage-class"
@@ -34,6 +35,7 @@ type Operation struct {
 	Auth           simulator.GatewayAuthService
 	Incr           ActionIncr
 	DedupCleaner   *dedup.Cleaner
+	Limits         uploadparams.Limits
 }
 
 func (o *Operation) RequestID() string {
@@ -137,6 +139,13 @@ func (o *Operation) EncodeError(e errors.APIError) {
 	}
 }
 
+// EncodeErrorDescription encodes the error of errCode with a description of its cause
+func (o *Operation) EncodeErrorDescription(errCode errors.APIErrorCode, format string, args ...interface{}) {
+	apiErr := errCode.ToAPIErr()
+	apiErr.Description = fmt.Sprintf(format, args...)
+	o.EncodeError(apiErr)
+}
+
 func generateHostID() string {
 	const generatedHostIDLength = 8
 	return auth.HexStringGenerator(generatedHostIDLength)
@@ -7,6 +7,7 @@ import (
 	"github.com/treeverse/lakefs/logging"
 
 	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 	gatewayerrors "github.com/treeverse/lakefs/gateway/errors"
 	"github.com/treeverse/lakefs/permissions"
@@ -14,7 +15,16 @@ import (
 
 type DeleteObject struct{}
 
-func (controller *DeleteObject) RequiredPermissions(_ *http.Request, repoID, _, path string) ([]permissions.Permission, error) {
+func (controller *DeleteObject) RequiredPermissions(request *http.Request, repoID, _, path string) ([]permissions.Permission, error) {
+	if _, hasTagging := request.URL.Query()[QueryParamTagging]; hasTagging {
+		// removing tags keeps the object
+		return []permissions.Permission{
+			{
+				Action:   permissions.WriteObjectAction,
+				Resource: permissions.ObjectArn(repoID, path),
+			},
+		}, nil
+	}
 	return []permissions.Permission{
 		{
 			Action:   permissions.DeleteObjectAction,
@@ -46,6 +56,10 @@ func (controller *DeleteObject) Handle(o *PathOperation) {
 		controller.HandleAbortMultipartUpload(o)
 		return
 	}
+	if _, hasTagging := query[QueryParamTagging]; hasTagging {
+		handleDeleteObjectTagging(o)
+		return
+	}
 
 	o.Incr("delete_object")
 	lg := o.Log().WithField("key", o.Path)
@@ -53,6 +67,12 @@ func (controller *DeleteObject) Handle(o *PathOperation) {
 	switch {
 	case errors.Is(err, db.ErrNotFound):
 		lg.WithError(err).Debug("could not delete object, it doesn't exist")
+	case errors.Is(err, catalog.ErrRepositoryReadOnly):
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrRepositoryReadOnly))
+		return
+	case errors.Is(err, catalog.ErrCommitJobInProgress):
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrCommitJobInProgress))
+		return
 	case err != nil:
 		lg.WithError(err).Error("could not delete object")
 		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
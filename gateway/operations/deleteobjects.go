@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/treeverse/lakefs/auth"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/db"
 	gerrors "github.com/treeverse/lakefs/gateway/errors"
 	"github.com/treeverse/lakefs/gateway/path"
@@ -62,6 +63,20 @@ func (controller *DeleteObjects) Handle(o *RepoOperation) {
 		switch {
 		case errors.Is(err, db.ErrNotFound):
 			lg.Debug("tried to delete a non-existent object")
+		case errors.Is(err, catalog.ErrRepositoryReadOnly):
+			errs = append(errs, serde.DeleteError{
+				Code:    "AccessDenied",
+				Key:     obj.Key,
+				Message: "The repository is read-only.",
+			})
+			continue
+		case errors.Is(err, catalog.ErrCommitJobInProgress):
+			errs = append(errs, serde.DeleteError{
+				Code:    "OperationAborted",
+				Key:     obj.Key,
+				Message: "The branch is being committed by a commit job.",
+			})
+			continue
 		case err != nil:
 			lg.WithError(err).Error("failed deleting object")
 			errs = append(errs, serde.DeleteError{
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"time"
 
 	"github.com/treeverse/lakefs/block"
@@ -36,13 +38,13 @@ func (controller *GetObject) Handle(o *PathOperation) {
 		return
 	}
 
-	if _, exists := query["tagging"]; exists {
-		o.EncodeResponse(serde.Tagging{}, http.StatusOK)
+	if _, exists := query[QueryParamTagging]; exists {
+		handleGetObjectTagging(o)
 		return
 	}
 
 	beforeMeta := time.Now()
-	entry, err := o.Cataloger.GetEntry(o.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
+	entry, err := o.Cataloger.GetEntry(o.Context(), o.Repository.Name, o.versionReference(), o.Path, catalog.GetEntryParams{})
 	metaTook := time.Since(beforeMeta)
 	o.Log().
 		WithField("took", metaTook).
@@ -65,28 +67,32 @@ func (controller *GetObject) Handle(o *PathOperation) {
 	o.SetHeader("Last-Modified", httputil.HeaderTimestamp(entry.CreationDate))
 	o.SetHeader("ETag", httputil.ETag(entry.Checksum))
 	o.SetHeader("Accept-Ranges", "bytes")
+	o.setEntryHeaders(entry)
 	// TODO: the rest of https://docs.aws.amazon.com/en_pv/AmazonS3/latest/API/API_GetObject.html
 
 	// range query
-	var expected int64
-	var data io.ReadCloser
-	var rng ghttp.Range
-	rng.StartOffset = -1
-	// range query
+	var ranges []ghttp.Range
 	rangeSpec := o.Request.Header.Get("Range")
 	if len(rangeSpec) > 0 {
-		rng, err = ghttp.ParseRange(rangeSpec, entry.Size)
+		ranges, err = ghttp.ParseRanges(rangeSpec, entry.Size)
 		if err != nil {
 			o.Log().WithError(err).WithField("range", rangeSpec).Debug("invalid range spec")
 		}
 	}
-	if rangeSpec == "" || err != nil {
+	if len(ranges) > 1 {
+		writeRanges(o, entry, ranges)
+		return
+	}
+
+	var expected int64
+	var data io.ReadCloser
+	if len(ranges) == 0 {
 		// assemble a response body (range-less query)
 		expected = entry.Size
 		data, err = o.BlockStore.Get(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: entry.PhysicalAddress}, entry.Size)
 	} else {
-		expected = rng.EndOffset - rng.StartOffset + 1 // both range ends are inclusive
-		data, err = o.BlockStore.GetRange(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: entry.PhysicalAddress}, rng.StartOffset, rng.EndOffset)
+		expected = ranges[0].Length()
+		data, err = o.BlockStore.GetRange(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: entry.PhysicalAddress}, ranges[0].StartOffset, ranges[0].EndOffset)
 	}
 	if err != nil {
 		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
@@ -96,11 +102,76 @@ func (controller *GetObject) Handle(o *PathOperation) {
 		_ = data.Close()
 	}()
 	o.SetHeader("Content-Length", fmt.Sprintf("%d", expected))
-	if rng.StartOffset != -1 {
-		o.SetHeader("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rng.StartOffset, rng.EndOffset, entry.Size))
+	if len(ranges) == 1 {
+		o.SetHeader("Content-Range", ranges[0].ContentRange(entry.Size))
+		o.ResponseWriter.WriteHeader(http.StatusPartialContent)
 	}
 	_, err = io.Copy(o.ResponseWriter, data)
 	if err != nil {
 		o.Log().WithError(err).Error("could not write response body for object")
 	}
 }
+
+// rangePartHeader returns the header of the part serving rng in a multipart/byteranges response
+func rangePartHeader(rng ghttp.Range, contentType string, size int64) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Type":  {contentType},
+		"Content-Range": {rng.ContentRange(size)},
+	}
+}
+
+// writeRanges serves ranges of entry as a multipart/byteranges response
+func writeRanges(o *PathOperation, entry *catalog.Entry, ranges []ghttp.Range) {
+	contentType := o.ResponseWriter.Header().Get("Content-Type")
+
+	// the length of the response is that of the ranges and of their part headers and boundaries
+	var encoded countingWriter
+	mw := multipart.NewWriter(&encoded)
+	contentLength := int64(0)
+	for _, rng := range ranges {
+		_, _ = mw.CreatePart(rangePartHeader(rng, contentType, entry.Size))
+		contentLength += rng.Length()
+	}
+	_ = mw.Close()
+	contentLength += int64(encoded)
+	boundary := mw.Boundary()
+
+	obj := block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: entry.PhysicalAddress}
+	for i, rng := range ranges {
+		data, err := o.BlockStore.GetRange(obj, rng.StartOffset, rng.EndOffset)
+		if err != nil {
+			o.Log().WithError(err).WithField("range", rng).Error("could not read object range")
+			if i == 0 {
+				o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+			}
+			return
+		}
+		if i == 0 {
+			o.SetHeader("Content-Type", "multipart/byteranges; boundary="+boundary)
+			o.SetHeader("Content-Length", fmt.Sprintf("%d", contentLength))
+			o.ResponseWriter.WriteHeader(http.StatusPartialContent)
+			mw = multipart.NewWriter(o.ResponseWriter)
+			_ = mw.SetBoundary(boundary)
+		}
+		part, err := mw.CreatePart(rangePartHeader(rng, contentType, entry.Size))
+		if err == nil {
+			_, err = io.Copy(part, data)
+		}
+		_ = data.Close()
+		if err != nil {
+			o.Log().WithError(err).Error("could not write response body for object ranges")
+			return
+		}
+	}
+	if err := mw.Close(); err != nil {
+		o.Log().WithError(err).Error("could not write response body for object ranges")
+	}
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,80 @@
+package operations
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/block/mem"
+	"github.com/treeverse/lakefs/catalog"
+	ghttp "github.com/treeverse/lakefs/gateway/http"
+)
+
+func TestWriteRanges(t *testing.T) {
+	const storageNamespace = "mem://ranges"
+	content := []byte("0123456789abcdefghij")
+	blockStore := mem.New()
+	obj := block.ObjectPointer{StorageNamespace: storageNamespace, Identifier: "object"}
+	if err := blockStore.Put(obj, int64(len(content)), bytes.NewReader(content), block.PutOpts{}); err != nil {
+		t.Fatalf("put object: %s", err)
+	}
+	entry := &catalog.Entry{PhysicalAddress: "object", Size: int64(len(content))}
+	ranges, err := ghttp.ParseRanges("bytes=0-3,10-11,-4", entry.Size)
+	if err != nil {
+		t.Fatalf("parse ranges: %s", err)
+	}
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "text/plain")
+	o := &PathOperation{RefOperation: &RefOperation{RepoOperation: &RepoOperation{
+		AuthenticatedOperation: &AuthenticatedOperation{Operation: &Operation{
+			Request:        httptest.NewRequest(http.MethodGet, "/repo/master/object", nil),
+			ResponseWriter: rec,
+			BlockStore:     blockStore,
+		}},
+		Repository: &catalog.Repository{Name: "repo", StorageNamespace: storageNamespace},
+	}}}
+	writeRanges(o, entry, ranges)
+
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("got status %d, expected %d", rec.Code, http.StatusPartialContent)
+	}
+	if contentLength := rec.Header().Get("Content-Length"); contentLength != strconv.Itoa(rec.Body.Len()) {
+		t.Errorf("got Content-Length %s for a body of %d bytes", contentLength, rec.Body.Len())
+	}
+	mediaType, params, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	if err != nil || mediaType != "multipart/byteranges" {
+		t.Fatalf("got Content-Type %s, expected multipart/byteranges", rec.Header().Get("Content-Type"))
+	}
+	expected := []struct {
+		ContentRange string
+		Body         string
+	}{
+		{"bytes 0-3/20", "0123"},
+		{"bytes 10-11/20", "ab"},
+		{"bytes 16-19/20", "ghij"},
+	}
+	mr := multipart.NewReader(rec.Body, params["boundary"])
+	for _, e := range expected {
+		part, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("read part %s: %s", e.ContentRange, err)
+		}
+		if part.Header.Get("Content-Range") != e.ContentRange || part.Header.Get("Content-Type") != "text/plain" {
+			t.Errorf("got part headers %v, expected range %s of text/plain", part.Header, e.ContentRange)
+		}
+		body, err := ioutil.ReadAll(part)
+		if err != nil || string(body) != e.Body {
+			t.Errorf("got part %s body %q (err=%v), expected %q", e.ContentRange, body, err, e.Body)
+		}
+	}
+	if _, err := mr.NextPart(); err == nil {
+		t.Error("expected no more parts")
+	}
+}
@@ -25,7 +25,7 @@ func (controller *HeadObject) RequiredPermissions(request *http.Request, repoID,
 
 func (controller *HeadObject) Handle(o *PathOperation) {
 	o.Incr("stat_object")
-	entry, err := o.Cataloger.GetEntry(o.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{ReturnExpired: true})
+	entry, err := o.Cataloger.GetEntry(o.Context(), o.Repository.Name, o.versionReference(), o.Path, catalog.GetEntryParams{ReturnExpired: true})
 	if errors.Is(err, db.ErrNotFound) {
 		// TODO: create distinction between missing repo & missing key
 		o.Log().Debug("path not found")
@@ -41,6 +41,7 @@ func (controller *HeadObject) Handle(o *PathOperation) {
 	o.SetHeader("Last-Modified", httputil.HeaderTimestamp(entry.CreationDate))
 	o.SetHeader("ETag", httputil.ETag(entry.Checksum))
 	o.SetHeader("Content-Length", fmt.Sprintf("%d", entry.Size))
+	o.setEntryHeaders(entry)
 	if entry.Expired {
 		o.Log().WithError(err).Info("querying expired object")
 		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchVersion))
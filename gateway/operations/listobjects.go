@@ -137,7 +137,7 @@ func (controller *ListObjects) ListV2(o *RepoOperation) {
 		// list branches then.
 		branchPrefix := prefix.Ref // TODO: same prefix logic also in V1!!!!!
 		o.Log().WithField("prefix", branchPrefix).Debug("listing branches with prefix")
-		branches, hasMore, err := o.Cataloger.ListBranches(o.Context(), o.Repository.Name, branchPrefix, maxKeys, fromStr)
+		branches, hasMore, err := o.Cataloger.ListBranches(o.Context(), o.Repository.Name, branchPrefix, maxKeys, fromStr, catalog.ListBranchesParams{})
 		if err != nil {
 			o.Log().WithError(err).Error("could not list branches")
 			o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
@@ -266,7 +266,7 @@ func (controller *ListObjects) ListV1(o *RepoOperation) {
 
 	if !prefix.WithPath {
 		// list branches then.
-		branches, hasMore, err := o.Cataloger.ListBranches(o.Context(), o.Repository.Name, prefix.Ref, maxKeys, params.Get("marker"))
+		branches, hasMore, err := o.Cataloger.ListBranches(o.Context(), o.Repository.Name, prefix.Ref, maxKeys, params.Get("marker"), catalog.ListBranchesParams{})
 		if err != nil {
 			// TODO incorrect error type
 			o.Log().WithError(err).Error("could not list branches")
@@ -375,6 +375,10 @@ func (controller *ListObjects) Handle(o *RepoOperation) {
 		o.EncodeXMLBytes([]byte(serde.VersioningResponse), http.StatusOK)
 		return
 	}
+	if _, found := query[QueryParamVersions]; found {
+		controller.ListVersions(o)
+		return
+	}
 
 	// handle ListObjects versions
 	listType := query.Get("list-type")
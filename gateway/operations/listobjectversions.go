@@ -0,0 +1,244 @@
+package operations
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	gatewayerrors "github.com/treeverse/lakefs/gateway/errors"
+	"github.com/treeverse/lakefs/gateway/path"
+	"github.com/treeverse/lakefs/gateway/serde"
+	"github.com/treeverse/lakefs/httputil"
+	"github.com/treeverse/lakefs/logging"
+)
+
+const (
+	QueryParamVersions  = "versions"
+	QueryParamVersionID = "versionId"
+
+	// NullVersionID is the version ID S3 reports for objects of unversioned buckets
+	NullVersionID = "null"
+)
+
+// versionsPage accumulates a page of ListObjectVersions, up to maxKeys versions, delete
+// markers and common prefixes
+type versionsPage struct {
+	ref     string
+	maxKeys int
+	result  *serde.ListVersionsResult
+	count   int
+}
+
+func (p *versionsPage) full() bool {
+	return p.count >= p.maxKeys
+}
+
+// addRecord adds the version of key created by a commit that changed it, or the delete marker
+// of a commit that removed it
+func (p *versionsPage) addRecord(key string, record *catalog.EntryHistoryRecord, isLatest bool) {
+	p.count++
+	p.result.NextKeyMarker = path.WithRef(key, p.ref)
+	p.result.NextVersionIDMarker = record.Commit.Reference
+	if record.Type == catalog.DifferenceTypeRemoved {
+		p.result.DeleteMarkers = append(p.result.DeleteMarkers, serde.DeleteMarker{
+			Key:          path.WithRef(key, p.ref),
+			VersionID:    record.Commit.Reference,
+			IsLatest:     isLatest,
+			LastModified: serde.Timestamp(record.Commit.CreationDate),
+		})
+		return
+	}
+	p.result.Versions = append(p.result.Versions, serde.Version{
+		Key:          path.WithRef(key, p.ref),
+		VersionID:    record.Commit.Reference,
+		IsLatest:     isLatest,
+		LastModified: serde.Timestamp(record.Commit.CreationDate),
+		ETag:         httputil.ETag(record.Checksum),
+		Size:         record.Size,
+		StorageClass: "STANDARD",
+	})
+}
+
+func (p *versionsPage) addCommonPrefix(prefix string) {
+	p.count++
+	p.result.NextKeyMarker = path.WithRef(prefix, p.ref)
+	p.result.NextVersionIDMarker = ""
+	p.result.CommonPrefixes = append(p.result.CommonPrefixes, serde.CommonPrefixes{Prefix: path.WithRef(prefix, p.ref)})
+}
+
+// addVersions adds the versions of key after the version afterVersionID, until the page is
+// full.  It returns whether versions of key remain.
+func (controller *ListObjects) addVersions(o *RepoOperation, page *versionsPage, key, afterVersionID string) (bool, error) {
+	isLatest := afterVersionID == ""
+	for !page.full() {
+		records, hasMore, err := o.Cataloger.GetEntryHistory(o.Context(), o.Repository.Name, page.ref, key, afterVersionID, page.maxKeys-page.count)
+		if err != nil {
+			return false, err
+		}
+		for _, record := range records {
+			page.addRecord(key, record, isLatest)
+			isLatest = false
+			afterVersionID = record.Commit.Reference
+		}
+		if !hasMore {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ListVersions lists the versions of the objects under a reference, as the commits that
+// changed them.  The version ID of each version is the reference of its commit, and commits
+// that removed an object show as delete markers.  Only objects found on the reference are
+// listed.
+func (controller *ListObjects) ListVersions(o *RepoOperation) {
+	params := o.Request.URL.Query()
+	delimiter := params.Get("delimiter")
+	keyMarker := params.Get("key-marker")
+	versionIDMarker := params.Get("version-id-marker")
+	if delimiter != "" && delimiter != path.Separator {
+		// we only support "/" as a delimiter
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
+		return
+	}
+	maxKeys := controller.getMaxKeys(o)
+	result := &serde.ListVersionsResult{
+		Name:            o.Repository.Name,
+		Prefix:          params.Get("prefix"),
+		Delimiter:       delimiter,
+		KeyMarker:       keyMarker,
+		VersionIDMarker: versionIDMarker,
+		MaxKeys:         maxKeys,
+		Versions:        make([]serde.Version, 0),
+		DeleteMarkers:   make([]serde.DeleteMarker, 0),
+		CommonPrefixes:  make([]serde.CommonPrefixes, 0),
+	}
+
+	prefix, err := path.ResolvePath(params.Get("prefix"))
+	if err != nil {
+		o.Log().WithError(err).WithField("path", params.Get("prefix")).Error("could not resolve path for prefix")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
+		return
+	}
+	if !prefix.WithPath {
+		// versions are listed under a reference, list branches to choose one from
+		branches, hasMore, err := o.Cataloger.ListBranches(o.Context(), o.Repository.Name, prefix.Ref, maxKeys, keyMarker, catalog.ListBranchesParams{})
+		if err != nil {
+			o.Log().WithError(err).Error("could not list branches")
+			o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+			return
+		}
+		dirs, lastKey := controller.serializeBranches(branches)
+		result.CommonPrefixes = dirs
+		if hasMore {
+			result.IsTruncated = true
+			result.NextKeyMarker = lastKey
+		}
+		o.EncodeResponse(result, http.StatusOK)
+		return
+	}
+
+	var marker path.ResolvedPath
+	if keyMarker != "" {
+		marker, err = path.ResolvePath(keyMarker)
+		if err != nil || !strings.EqualFold(marker.Ref, prefix.Ref) {
+			o.Log().WithError(err).WithFields(logging.Fields{
+				"ref":        prefix.Ref,
+				"path":       prefix.Path,
+				"key_marker": keyMarker,
+			}).Error("invalid key marker - doesnt start with reference")
+			o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrBadRequest))
+			return
+		}
+	}
+
+	page := &versionsPage{ref: prefix.Ref, maxKeys: maxKeys, result: result}
+	err = controller.listVersions(o, page, prefix.Path, marker.Path, versionIDMarker, delimiter)
+	if errors.Is(err, db.ErrNotFound) {
+		// no versions found
+		o.Log().WithError(err).WithField("ref", prefix.Ref).Debug("could not list versions")
+		result.Versions = make([]serde.Version, 0)
+		result.DeleteMarkers = make([]serde.DeleteMarker, 0)
+		result.CommonPrefixes = make([]serde.CommonPrefixes, 0)
+		result.IsTruncated = false
+	} else if err != nil {
+		o.Log().WithError(err).WithFields(logging.Fields{
+			"ref":  prefix.Ref,
+			"path": prefix.Path,
+		}).Error("could not list versions")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return
+	}
+	if !result.IsTruncated {
+		result.NextKeyMarker = ""
+		result.NextVersionIDMarker = ""
+	}
+	o.EncodeResponse(result, http.StatusOK)
+}
+
+// listVersions fills page with versions of the objects under prefix, starting after the
+// version versionIDMarker of keyMarker, or after all versions of keyMarker when
+// versionIDMarker is empty
+func (controller *ListObjects) listVersions(o *RepoOperation, page *versionsPage, prefix, keyMarker, versionIDMarker, delimiter string) error {
+	if page.maxKeys <= 0 {
+		return nil
+	}
+	if keyMarker != "" && versionIDMarker != "" {
+		more, err := controller.addVersions(o, page, keyMarker, versionIDMarker)
+		if err != nil {
+			return err
+		}
+		if more {
+			page.result.IsTruncated = true
+			return nil
+		}
+	}
+	after := keyMarker
+	for {
+		if page.full() {
+			// the next page may turn out empty, but S3 clients only stop on a page that
+			// is not truncated
+			page.result.IsTruncated = true
+			return nil
+		}
+		entries, hasMore, err := o.Cataloger.ListEntries(o.Context(), o.Repository.Name, page.ref, prefix, after, delimiter, page.maxKeys-page.count)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if page.full() {
+				page.result.IsTruncated = true
+				return nil
+			}
+			after = entry.Path
+			if entry.CommonLevel {
+				page.addCommonPrefix(entry.Path)
+				continue
+			}
+			more, err := controller.addVersions(o, page, entry.Path, "")
+			if err != nil {
+				return err
+			}
+			if more {
+				page.result.IsTruncated = true
+				return nil
+			}
+		}
+		if !hasMore {
+			return nil
+		}
+	}
+}
+
+// versionReference returns the reference to read the object of o from: the commit of the
+// requested version ID, as listed by ListObjectVersions, or the reference of the path
+func (o *PathOperation) versionReference() string {
+	versionID := o.Request.URL.Query().Get(QueryParamVersionID)
+	if versionID == "" || versionID == NullVersionID {
+		return o.Reference
+	}
+	o.SetHeader("x-amz-version-id", versionID)
+	return versionID
+}
@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/gateway/serde"
+)
+
+func TestVersionsPage(t *testing.T) {
+	page := &versionsPage{ref: "master", maxKeys: 3, result: &serde.ListVersionsResult{}}
+	created := &catalog.EntryHistoryRecord{
+		Commit:   &catalog.CommitLog{Reference: "c1", CreationDate: time.Unix(1, 0)},
+		Type:     catalog.DifferenceTypeAdded,
+		Size:     5,
+		Checksum: "abc",
+	}
+	removed := &catalog.EntryHistoryRecord{
+		Commit: &catalog.CommitLog{Reference: "c2", CreationDate: time.Unix(2, 0)},
+		Type:   catalog.DifferenceTypeRemoved,
+	}
+	page.addRecord("a/file", removed, true)
+	page.addRecord("a/file", created, false)
+	if page.full() {
+		t.Fatal("expected page with 2 of 3 keys not to be full")
+	}
+	page.addCommonPrefix("b/")
+	if !page.full() {
+		t.Fatal("expected page with 3 of 3 keys to be full")
+	}
+
+	result := page.result
+	if len(result.DeleteMarkers) != 1 || result.DeleteMarkers[0].VersionID != "c2" || !result.DeleteMarkers[0].IsLatest {
+		t.Errorf("unexpected delete markers %+v", result.DeleteMarkers)
+	}
+	if len(result.Versions) != 1 || result.Versions[0].VersionID != "c1" || result.Versions[0].IsLatest || result.Versions[0].Key != "master/a/file" {
+		t.Errorf("unexpected versions %+v", result.Versions)
+	}
+	if len(result.CommonPrefixes) != 1 || result.CommonPrefixes[0].Prefix != "master/b/" {
+		t.Errorf("unexpected common prefixes %+v", result.CommonPrefixes)
+	}
+	if result.NextKeyMarker != "master/b/" || result.NextVersionIDMarker != "" {
+		t.Errorf("unexpected next markers %s, %s", result.NextKeyMarker, result.NextVersionIDMarker)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/logging"
 )
@@ -58,9 +59,18 @@ func (a *mockAdapter) GetProperties(_ block.ObjectPointer) (block.Properties, er
 func (a *mockAdapter) Remove(_ block.ObjectPointer) error {
 	return errors.New("remove method not implemented in mock adapter")
 }
-func (a *mockAdapter) Copy(_, _ block.ObjectPointer) error {
+func (a *mockAdapter) Copy(_, _ block.ObjectPointer, _ block.CopyOpts) error {
 	return errors.New("copy method not implemented in mock adapter")
 }
+func (a *mockAdapter) SetStorageClass(_ block.ObjectPointer, _ string) error {
+	return errors.New("setStorageClass method not implemented in mock adapter")
+}
+func (a *mockAdapter) SelectObjectContent(_ block.ObjectPointer, _ *s3.SelectObjectContentInput) (io.ReadCloser, error) {
+	return nil, errors.New("selectObjectContent method not implemented in mock adapter")
+}
+func (a *mockAdapter) Walk(_ block.ObjectPointer, _ block.WalkFunc) error {
+	return errors.New("walk method not implemented in mock adapter")
+}
 func (a *mockAdapter) CreateMultiPartUpload(_ block.ObjectPointer, r *http.Request, _ block.CreateMultiPartUploadOpts) (string, error) {
 	panic("try to create multipart in mock adaptor")
 }
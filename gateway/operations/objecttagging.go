@@ -0,0 +1,182 @@
+package operations
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	gatewayerrors "github.com/treeverse/lakefs/gateway/errors"
+	"github.com/treeverse/lakefs/gateway/serde"
+)
+
+const (
+	QueryParamTagging      = "tagging"
+	TaggingHeader          = "x-amz-tagging"
+	TaggingDirectiveHeader = "x-amz-tagging-directive"
+
+	// TagMetadataPrefix prefixes the keys of entry metadata that store the S3 tags of the entry
+	TagMetadataPrefix = "lakefs_tag_"
+
+	// limits on tags imposed by S3
+	maxTagsPerObject  = 10
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+var ErrInvalidTag = errors.New("invalid tag")
+
+// taggingRequest is the body of PutObjectTagging.  Unlike serde.Tagging it accepts a Tagging
+// element in any XML namespace.
+type taggingRequest struct {
+	TagSet serde.TagSet `xml:"TagSet"`
+}
+
+// tagsFromMetadata returns the tags stored in metadata, sorted by key
+func tagsFromMetadata(metadata catalog.Metadata) []serde.Tag {
+	tags := make([]serde.Tag, 0)
+	for key, value := range metadata {
+		if strings.HasPrefix(key, TagMetadataPrefix) {
+			tags = append(tags, serde.Tag{Key: strings.TrimPrefix(key, TagMetadataPrefix), Value: value})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Key < tags[j].Key })
+	return tags
+}
+
+// withTags returns a copy of metadata storing tags instead of its current tags
+func withTags(metadata catalog.Metadata, tags []serde.Tag) catalog.Metadata {
+	result := make(catalog.Metadata, len(metadata)+len(tags))
+	for key, value := range metadata {
+		if !strings.HasPrefix(key, TagMetadataPrefix) {
+			result[key] = value
+		}
+	}
+	for _, tag := range tags {
+		result[TagMetadataPrefix+tag.Key] = tag.Value
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// validateTags returns ErrInvalidTag unless tags are within the limits of S3 and have unique keys
+func validateTags(tags []serde.Tag) error {
+	if len(tags) > maxTagsPerObject {
+		return ErrInvalidTag
+	}
+	keys := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		if tag.Key == "" || utf8.RuneCountInString(tag.Key) > maxTagKeyLength || utf8.RuneCountInString(tag.Value) > maxTagValueLength {
+			return ErrInvalidTag
+		}
+		if _, ok := keys[tag.Key]; ok {
+			return ErrInvalidTag
+		}
+		keys[tag.Key] = struct{}{}
+	}
+	return nil
+}
+
+// tagsFromHeader returns the tags set by the x-amz-tagging header, encoded as URL query
+// parameters
+func tagsFromHeader(header http.Header) ([]serde.Tag, error) {
+	value := header.Get(TaggingHeader)
+	if value == "" {
+		return nil, nil
+	}
+	query, err := url.ParseQuery(value)
+	if err != nil {
+		return nil, ErrInvalidTag
+	}
+	tags := make([]serde.Tag, 0, len(query))
+	for key, values := range query {
+		if len(values) != 1 {
+			return nil, ErrInvalidTag
+		}
+		tags = append(tags, serde.Tag{Key: key, Value: values[0]})
+	}
+	if err := validateTags(tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// getTaggedEntry returns the entry whose tags are requested by o.  On failure it encodes the
+// error response and returns nil.
+func getTaggedEntry(o *PathOperation) *catalog.Entry {
+	entry, err := o.Cataloger.GetEntry(o.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
+	if errors.Is(err, db.ErrNotFound) {
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchKey))
+		return nil
+	}
+	if err != nil {
+		o.Log().WithError(err).Error("could not read tagged object")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return nil
+	}
+	return entry
+}
+
+// setEntryTags replaces the tags of entry with tags.  On failure it encodes the error response
+// and returns false.
+func setEntryTags(o *PathOperation, entry *catalog.Entry, tags []serde.Tag) bool {
+	entry.Metadata = withTags(entry.Metadata, tags)
+	err := o.Cataloger.CreateEntry(o.Context(), o.Repository.Name, o.Reference, *entry, catalog.CreateEntryParams{})
+	if errors.Is(err, db.ErrNotFound) {
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchBucket))
+		return false
+	}
+	if err != nil {
+		o.Log().WithError(err).Error("could not update object tags")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(uploadErrorCode(err)))
+		return false
+	}
+	return true
+}
+
+func handleGetObjectTagging(o *PathOperation) {
+	o.Incr("get_object_tagging")
+	entry := getTaggedEntry(o)
+	if entry == nil {
+		return
+	}
+	o.EncodeResponse(serde.Tagging{TagSet: serde.TagSet{Tag: tagsFromMetadata(entry.Metadata)}}, http.StatusOK)
+}
+
+func handlePutObjectTagging(o *PathOperation) {
+	o.Incr("put_object_tagging")
+	var req taggingRequest
+	if err := DecodeXMLBody(o.Request.Body, &req); err != nil {
+		o.Log().WithError(err).Debug("could not parse tagging request")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrMalformedXML))
+		return
+	}
+	if err := validateTags(req.TagSet.Tag); err != nil {
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInvalidTag))
+		return
+	}
+	entry := getTaggedEntry(o)
+	if entry == nil {
+		return
+	}
+	if setEntryTags(o, entry, req.TagSet.Tag) {
+		o.ResponseWriter.WriteHeader(http.StatusOK)
+	}
+}
+
+func handleDeleteObjectTagging(o *PathOperation) {
+	o.Incr("delete_object_tagging")
+	entry := getTaggedEntry(o)
+	if entry == nil {
+		return
+	}
+	if setEntryTags(o, entry, nil) {
+		o.ResponseWriter.WriteHeader(http.StatusNoContent)
+	}
+}
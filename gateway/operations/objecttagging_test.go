@@ -0,0 +1,79 @@
+package operations
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/gateway/serde"
+)
+
+func TestTagsFromHeader(t *testing.T) {
+	cases := []struct {
+		Name          string
+		Value         string
+		ExpectedTags  []serde.Tag
+		ExpectedError error
+	}{
+		{Name: "none", Value: ""},
+		{Name: "tags", Value: "project=x&team=data%20eng", ExpectedTags: []serde.Tag{{Key: "project", Value: "x"}, {Key: "team", Value: "data eng"}}},
+		{Name: "empty value", Value: "done=", ExpectedTags: []serde.Tag{{Key: "done", Value: ""}}},
+		{Name: "duplicate key", Value: "a=1&a=2", ExpectedError: ErrInvalidTag},
+		{Name: "empty key", Value: "=1", ExpectedError: ErrInvalidTag},
+		{Name: "too many", Value: "a=1&b=2&c=3&d=4&e=5&f=6&g=7&h=8&i=9&j=10&k=11", ExpectedError: ErrInvalidTag},
+		{Name: "long key", Value: strings.Repeat("k", maxTagKeyLength+1) + "=1", ExpectedError: ErrInvalidTag},
+		{Name: "long value", Value: "k=" + strings.Repeat("v", maxTagValueLength+1), ExpectedError: ErrInvalidTag},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set(TaggingHeader, tc.Value)
+			tags, err := tagsFromHeader(header)
+			if !errors.Is(err, tc.ExpectedError) {
+				t.Fatalf("expected error %v, got %v", tc.ExpectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			// tags are stored, and so returned, sorted by key
+			tags = tagsFromMetadata(withTags(nil, tags))
+			if len(tc.ExpectedTags) == 0 && len(tags) == 0 {
+				return
+			}
+			if diff := deep.Equal(tags, tc.ExpectedTags); diff != nil {
+				t.Errorf("unexpected tags: %s", diff)
+			}
+		})
+	}
+}
+
+func TestWithTags(t *testing.T) {
+	metadata := catalog.Metadata{"owner": "data", TagMetadataPrefix + "old": "1"}
+	tagged := withTags(metadata, []serde.Tag{{Key: "new", Value: "2"}})
+	expected := catalog.Metadata{"owner": "data", TagMetadataPrefix + "new": "2"}
+	if diff := deep.Equal(tagged, expected); diff != nil {
+		t.Errorf("unexpected tagged metadata: %s", diff)
+	}
+	if metadata[TagMetadataPrefix+"old"] != "1" {
+		t.Error("expected tagging to keep the original metadata")
+	}
+	if untagged := withTags(catalog.Metadata{TagMetadataPrefix + "old": "1"}, nil); untagged != nil {
+		t.Errorf("expected no metadata after removing all tags, got %v", untagged)
+	}
+}
+
+func TestDecodeTaggingRequest(t *testing.T) {
+	for _, xmlns := range []string{"", ` xmlns="http://s3.amazonaws.com/doc/2006-03-01/"`} {
+		body := `<Tagging` + xmlns + `><TagSet><Tag><Key>a</Key><Value>1</Value></Tag><Tag><Key>b</Key><Value>2</Value></Tag></TagSet></Tagging>`
+		var req taggingRequest
+		if err := DecodeXMLBody(strings.NewReader(body), &req); err != nil {
+			t.Fatalf("decode %s: %s", body, err)
+		}
+		if diff := deep.Equal(req.TagSet.Tag, []serde.Tag{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}); diff != nil {
+			t.Errorf("unexpected tags decoding %s: %s", body, diff)
+		}
+	}
+}
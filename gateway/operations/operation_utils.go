@@ -1,23 +1,88 @@
 package operations
 
 import (
+	"errors"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/catalog"
+	gatewayerrors "github.com/treeverse/lakefs/gateway/errors"
 	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/upload"
 )
 
-func (o *PathOperation) finishUpload(storageNamespace, checksum, physicalAddress string, size int64) error {
+const (
+	amzMetaHeaderPrefix = "X-Amz-Meta-"
+
+	// DefaultContentType is served for objects uploaded without a Content-Type
+	DefaultContentType = "application/octet-stream"
+)
+
+// entryHeaders are the HTTP headers stored with an entry on upload and served back on reads
+type entryHeaders struct {
+	ContentType     string
+	ContentEncoding string
+	CacheControl    string
+}
+
+func entryHeadersFromHeader(header http.Header) entryHeaders {
+	return entryHeaders{
+		ContentType:     header.Get("Content-Type"),
+		ContentEncoding: header.Get("Content-Encoding"),
+		CacheControl:    header.Get("Cache-Control"),
+	}
+}
+
+func (h entryHeaders) setOn(entry *catalog.Entry) {
+	entry.ContentType = h.ContentType
+	entry.ContentEncoding = h.ContentEncoding
+	entry.CacheControl = h.CacheControl
+}
+
+// setEntryHeaders sets the stored HTTP headers of entry on the response
+func (o *Operation) setEntryHeaders(entry *catalog.Entry) {
+	contentType := entry.ContentType
+	if contentType == "" {
+		contentType = DefaultContentType
+	}
+	o.SetHeader("Content-Type", contentType)
+	if entry.ContentEncoding != "" {
+		o.SetHeader("Content-Encoding", entry.ContentEncoding)
+	}
+	if entry.CacheControl != "" {
+		o.SetHeader("Cache-Control", entry.CacheControl)
+	}
+}
+
+// amzMetaFromHeader returns the user metadata set by x-amz-meta-* headers, keyed by lower case names
+func amzMetaFromHeader(header http.Header) catalog.Metadata {
+	var metadata catalog.Metadata
+	for name, values := range header {
+		if !strings.HasPrefix(name, amzMetaHeaderPrefix) || len(values) == 0 {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(catalog.Metadata)
+		}
+		metadata[strings.ToLower(name[len(amzMetaHeaderPrefix):])] = values[0]
+	}
+	return metadata
+}
+
+func (o *PathOperation) finishUpload(storageNamespace, checksum, physicalAddress string, size int64, metadata catalog.Metadata, headers entryHeaders) error {
 	// write metadata
 	writeTime := time.Now()
 	entry := catalog.Entry{
 		Path:            o.Path,
 		PhysicalAddress: physicalAddress,
 		Checksum:        checksum,
-		Metadata:        nil, // TODO: Read whatever metadata came from the request headers/params and add here
+		Metadata:        metadata,
 		Size:            size,
 		CreationDate:    writeTime,
 	}
+	headers.setOn(&entry)
 
 	err := o.Cataloger.CreateEntry(o.Context(), o.Repository.Name, o.Reference, entry,
 		catalog.CreateEntryParams{
@@ -36,3 +101,57 @@ func (o *PathOperation) finishUpload(storageNamespace, checksum, physicalAddress
 	}).Debug("metadata update complete")
 	return nil
 }
+
+// checkRepositoryQuota returns true if storing size bytes of an object keeps the repository
+// of o within its quota.  Otherwise it encodes the error and returns false, before the data
+// is stored.
+func (o *PathOperation) checkRepositoryQuota(size int64) bool {
+	if size < 0 {
+		// unknown size, checked when the entry is created
+		size = 0
+	}
+	err := o.Cataloger.CheckRepositoryQuota(o.Context(), o.Repository.Name, 1, size)
+	if err != nil {
+		o.Log().WithError(err).Debug("repository quota check failed")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(uploadErrorCode(err)))
+		return false
+	}
+	return true
+}
+
+// removeUploadedObject removes an object stored at physicalAddress for an entry that was not
+// created
+func (o *PathOperation) removeUploadedObject(physicalAddress string) {
+	err := o.BlockStore.Remove(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: physicalAddress})
+	if err != nil {
+		o.Log().WithError(err).WithField("physical_address", physicalAddress).Warn("could not remove uploaded object")
+	}
+}
+
+// uploadErrorCode returns the gateway error code matching an error returned by finishUpload
+func uploadErrorCode(err error) gatewayerrors.APIErrorCode {
+	if errors.Is(err, catalog.ErrQuotaExceeded) {
+		return gatewayerrors.ErrQuotaExceeded
+	}
+	if errors.Is(err, catalog.ErrInvalidMetadata) {
+		return gatewayerrors.ErrInvalidMetadata
+	}
+	if errors.Is(err, catalog.ErrRepositoryReadOnly) {
+		return gatewayerrors.ErrRepositoryReadOnly
+	}
+	if errors.Is(err, catalog.ErrCommitJobInProgress) {
+		return gatewayerrors.ErrCommitJobInProgress
+	}
+	return gatewayerrors.ErrInternalError
+}
+
+// encodeEntityTooLarge encodes EntityTooLarge for an upload exceeding the maximum size of its
+// kind, an object or a part
+func (o *Operation) encodeEntityTooLarge(kind string, maxSize int64) {
+	o.EncodeErrorDescription(gatewayerrors.ErrEntityTooLarge,
+		"Your proposed upload exceeds the maximum allowed %s size of %d bytes.", kind, maxSize)
+}
+
+func isEntityTooLarge(err error) bool {
+	return errors.Is(err, upload.ErrEntityTooLarge)
+}
@@ -3,6 +3,7 @@ package operations
 import (
 	"encoding/hex"
 	"encoding/xml"
+	stderrors "errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/gateway/errors"
 	"github.com/treeverse/lakefs/gateway/path"
 	"github.com/treeverse/lakefs/gateway/serde"
@@ -26,7 +28,16 @@ const (
 
 type PostObject struct{}
 
-func (controller *PostObject) RequiredPermissions(_ *http.Request, repoID, _, path string) ([]permissions.Permission, error) {
+func (controller *PostObject) RequiredPermissions(request *http.Request, repoID, _, path string) ([]permissions.Permission, error) {
+	if _, isSelect := request.URL.Query()[QueryParamSelect]; isSelect {
+		// selecting object content only reads the object
+		return []permissions.Permission{
+			{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(repoID, path),
+			},
+		}, nil
+	}
 	return []permissions.Permission{
 		{
 			Action:   permissions.WriteObjectAction,
@@ -47,10 +58,20 @@ func (controller *PostObject) HandleCreateMultipartUpload(o *PathOperation) {
 		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
 		return
 	}
-	err = o.Cataloger.CreateMultipartUpload(o.Context(), o.Repository.Name, uploadID, o.Path, objName, time.Now())
+	headers := entryHeadersFromHeader(o.Request.Header)
+	err = o.Cataloger.CreateMultipartUpload(o.Context(), o.Repository.Name, uploadID, o.Path, objName, time.Now(),
+		catalog.CreateMultipartUploadParams{
+			ContentType:     headers.ContentType,
+			ContentEncoding: headers.ContentEncoding,
+			CacheControl:    headers.CacheControl,
+			Metadata:        amzMetaFromHeader(o.Request.Header),
+		})
 	if err != nil {
 		o.Log().WithError(err).Error("could not write multipart upload to DB")
-		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
+		if abortErr := o.BlockStore.AbortMultiPartUpload(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: objName}, uploadID); abortErr != nil {
+			o.Log().WithError(abortErr).Warn("could not abort multipart upload")
+		}
+		o.EncodeError(errors.Codes.ToAPIErr(uploadErrorCode(err)))
 		return
 	}
 	o.EncodeResponse(&serde.InitiateMultipartUploadResult{
@@ -91,17 +112,38 @@ func (controller *PostObject) HandleCompleteMultipartUpload(o *PathOperation) {
 		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
 		return
 	}
+	// parts were checked against the quota when uploaded, fail early if the repository
+	// reached it since
+	if !o.checkRepositoryQuota(0) {
+		return
+	}
 	etag, size, err = o.BlockStore.CompleteMultiPartUpload(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: objName}, uploadID, &MultipartList)
 	if err != nil {
 		o.Log().WithError(err).Error("could not complete multipart upload")
 		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
 		return
 	}
+	// only the size of the whole object is known: it is too small when its parts can't all be
+	// at least the minimum part size, except for the last part
+	if parts := int64(len(MultipartList.Part)); o.Limits.MinPartSize > 0 && parts > 1 && size < (parts-1)*o.Limits.MinPartSize {
+		o.discardCompletedUpload(objName, uploadID)
+		o.EncodeErrorDescription(errors.ErrEntityTooSmall,
+			"Your proposed upload is smaller than the minimum allowed size of %d bytes for each part but the last.", o.Limits.MinPartSize)
+		return
+	}
 	ch := trimQuotes(*etag)
 	checksum := strings.Split(ch, "-")[0]
-	err = o.finishUpload(o.Repository.StorageNamespace, checksum, objName, size)
+	err = o.finishUpload(o.Repository.StorageNamespace, checksum, objName, size, multiPart.Metadata, entryHeaders{
+		ContentType:     multiPart.ContentType,
+		ContentEncoding: multiPart.ContentEncoding,
+		CacheControl:    multiPart.CacheControl,
+	})
+	if stderrors.Is(err, catalog.ErrQuotaExceeded) {
+		// the completed upload can't be completed again
+		o.discardCompletedUpload(objName, uploadID)
+	}
 	if err != nil {
-		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
+		o.EncodeError(errors.Codes.ToAPIErr(uploadErrorCode(err)))
 		return
 	}
 	err = o.Cataloger.DeleteMultipartUpload(o.Context(), o.Repository.Name, uploadID)
@@ -110,7 +152,7 @@ func (controller *PostObject) HandleCompleteMultipartUpload(o *PathOperation) {
 	}
 
 	scheme := httputil.RequestScheme(o.Request)
-	location := fmt.Sprintf("%s://%s.%s/%s/%s", scheme, o.Repository, o.FQDN, o.Reference, o.Path)
+	location := fmt.Sprintf("%s://%s.%s/%s/%s", scheme, o.Repository.Name, o.FQDN, o.Reference, o.Path)
 	o.EncodeResponse(&serde.CompleteMultipartUploadResult{
 		Location: location,
 		Bucket:   o.Repository.Name,
@@ -119,10 +161,25 @@ func (controller *PostObject) HandleCompleteMultipartUpload(o *PathOperation) {
 	}, http.StatusOK)
 }
 
+// discardCompletedUpload removes the object of a completed multipart upload that is not added
+// to the repository, and the record of the upload
+func (o *PathOperation) discardCompletedUpload(objName, uploadID string) {
+	o.removeUploadedObject(objName)
+	if err := o.Cataloger.DeleteMultipartUpload(o.Context(), o.Repository.Name, uploadID); err != nil {
+		o.Log().WithError(err).Warn("could not delete multipart record")
+	}
+}
+
 func (controller *PostObject) Handle(o *PathOperation) {
-	// POST is only supported for CreateMultipartUpload/CompleteMultipartUpload
+	// POST is only supported for CreateMultipartUpload/CompleteMultipartUpload and SelectObjectContent
 	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_CreateMultipartUpload.html
 	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_CompleteMultipartUpload.html
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_SelectObjectContent.html
+	if _, isSelect := o.Request.URL.Query()[QueryParamSelect]; isSelect {
+		controller.HandleSelectObjectContent(o)
+		return
+	}
+
 	_, mpuCreateParamExist := o.Request.URL.Query()[CreateMultipartUploadQueryParam]
 	if mpuCreateParamExist {
 		controller.HandleCreateMultipartUpload(o)
@@ -1,7 +1,9 @@
 package operations
 
 import (
+	stderrors "errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -11,6 +13,7 @@ import (
 	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/gateway/errors"
+	ghttp "github.com/treeverse/lakefs/gateway/http"
 	"github.com/treeverse/lakefs/gateway/path"
 	"github.com/treeverse/lakefs/gateway/serde"
 	"github.com/treeverse/lakefs/httputil"
@@ -20,50 +23,105 @@ import (
 )
 
 const (
-	CopySourceHeader     = "x-amz-copy-source"
-	QueryParamUploadID   = "uploadId"
-	QueryParamPartNumber = "partNumber"
+	CopySourceHeader        = "x-amz-copy-source"
+	CopySourceRangeHeader   = "x-amz-copy-source-range"
+	MetadataDirectiveHeader = "x-amz-metadata-directive"
+	QueryParamUploadID      = "uploadId"
+	QueryParamPartNumber    = "partNumber"
 )
 
 type PutObject struct{}
 
-func (controller *PutObject) RequiredPermissions(_ *http.Request, repoID, _, path string) ([]permissions.Permission, error) {
-	return []permissions.Permission{
+func (controller *PutObject) RequiredPermissions(request *http.Request, repoID, _, objectPath string) ([]permissions.Permission, error) {
+	perms := []permissions.Permission{
 		{
 			Action:   permissions.WriteObjectAction,
-			Resource: permissions.ObjectArn(repoID, path),
+			Resource: permissions.ObjectArn(repoID, objectPath),
 		},
-	}, nil
+	}
+	// copies (CopyObject and UploadPartCopy) also read their source.  An invalid source is
+	// rejected by the handler before it is read.
+	if copySource := request.Header.Get(CopySourceHeader); copySource != "" {
+		if p, err := resolveCopySource(copySource); err == nil {
+			perms = append(perms, permissions.Permission{
+				Action:   permissions.ReadObjectAction,
+				Resource: permissions.ObjectArn(p.Repo, p.Path),
+			})
+		}
+	}
+	return perms, nil
 }
 
-func (controller *PutObject) HandleCopy(o *PathOperation, copySource string) {
-	o.Incr("copy_object")
-	// resolve source branch and source path
+// resolveCopySource resolves the repository, reference and path of an x-amz-copy-source
+// header value
+func resolveCopySource(copySource string) (path.ResolvedAbsolutePath, error) {
 	copySourceDecoded, err := url.QueryUnescape(copySource)
 	if err != nil {
 		copySourceDecoded = copySource
 	}
-	p, err := path.ResolveAbsolutePath(copySourceDecoded)
+	return path.ResolveAbsolutePath(copySourceDecoded)
+}
+
+// getCopySourceEntry resolves the entry named by an x-amz-copy-source header value.  On failure
+// it encodes the error response and returns nil.
+func getCopySourceEntry(o *PathOperation, copySource string) *catalog.Entry {
+	// resolve source branch and source path
+	p, err := resolveCopySource(copySource)
 	if err != nil {
 		o.Log().WithError(err).Error("could not parse copy source path")
 		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidCopySource))
-		return
+		return nil
 	}
 
 	// validate src and dst are in the same repository
 	if !strings.EqualFold(o.Repository.Name, p.Repo) {
 		o.Log().WithError(err).Error("cannot copy objects across repos")
 		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidCopySource))
-		return
+		return nil
 	}
 
-	// update metadata to refer to the source hash in the destination workspace
 	ent, err := o.Cataloger.GetEntry(o.Context(), o.Repository.Name, p.Reference, p.Path, catalog.GetEntryParams{})
 	if err != nil {
 		o.Log().WithError(err).Error("could not read copy source")
 		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidCopySource))
+		return nil
+	}
+	return ent
+}
+
+func (controller *PutObject) HandleCopy(o *PathOperation, copySource string) {
+	o.Incr("copy_object")
+	// update metadata to refer to the source hash in the destination workspace
+	ent := getCopySourceEntry(o, copySource)
+	if ent == nil {
 		return
 	}
+	// keep the source metadata unless asked to replace it
+	tags := tagsFromMetadata(ent.Metadata)
+	switch o.Request.Header.Get(MetadataDirectiveHeader) {
+	case "", "COPY":
+	case "REPLACE":
+		ent.Metadata = amzMetaFromHeader(o.Request.Header)
+		entryHeadersFromHeader(o.Request.Header).setOn(ent)
+	default:
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidMetadataDirective))
+		return
+	}
+	// keep the source tags unless asked to replace them, regardless of the metadata
+	var err error
+	switch o.Request.Header.Get(TaggingDirectiveHeader) {
+	case "", "COPY":
+	case "REPLACE":
+		tags, err = tagsFromHeader(o.Request.Header)
+		if err != nil {
+			o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidTag))
+			return
+		}
+	default:
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidTaggingDirective))
+		return
+	}
+	ent.Metadata = withTags(ent.Metadata, tags)
 	// write this object to workspace
 	// TODO: move this logic into the Index impl.
 	ent.CreationDate = time.Now()
@@ -71,7 +129,11 @@ func (controller *PutObject) HandleCopy(o *PathOperation, copySource string) {
 	err = o.Cataloger.CreateEntry(o.Context(), o.Repository.Name, o.Reference, *ent, catalog.CreateEntryParams{})
 	if err != nil {
 		o.Log().WithError(err).Error("could not write copy destination")
-		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidCopyDest))
+		errCode := uploadErrorCode(err)
+		if errCode == errors.ErrInternalError {
+			errCode = errors.ErrInvalidCopyDest
+		}
+		o.EncodeError(errors.Codes.ToAPIErr(errCode))
 		return
 	}
 
@@ -81,16 +143,125 @@ func (controller *PutObject) HandleCopy(o *PathOperation, copySource string) {
 	}, http.StatusOK)
 }
 
+// parsePartNumber returns the part number of an upload part request, checked against the
+// configured limits.  On failure it encodes the error response and returns false.
+func parsePartNumber(o *PathOperation) (int64, bool) {
+	partNumber, err := strconv.ParseInt(o.Request.URL.Query().Get(QueryParamPartNumber), 10, 64)
+	if err != nil {
+		o.Log().WithError(err).Error("invalid part number")
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidPartNumberMarker))
+		return 0, false
+	}
+	if partNumber < 1 {
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidPartNumber))
+		return 0, false
+	}
+	if o.Limits.MaxParts > 0 && partNumber > o.Limits.MaxParts {
+		o.EncodeErrorDescription(errors.ErrInvalidPartNumber,
+			"Part number must be an integer between 1 and %d.", o.Limits.MaxParts)
+		return 0, false
+	}
+	return partNumber, true
+}
+
+// HandleUploadPartCopy uploads a part of a multipart upload from (a range of) an existing
+// object, as in https://docs.aws.amazon.com/AmazonS3/latest/API/API_UploadPartCopy.html.  The
+// source is streamed from the block adapter, so clients need not download it.
+func (controller *PutObject) HandleUploadPartCopy(o *PathOperation, copySource string) {
+	o.Incr("copy_mpu_part")
+	uploadID := o.Request.URL.Query().Get(QueryParamUploadID)
+	partNumber, ok := parsePartNumber(o)
+	if !ok {
+		return
+	}
+	o.AddLogFields(logging.Fields{
+		"part_number": partNumber,
+		"upload_id":   uploadID,
+		"copy_source": copySource,
+	})
+
+	ent := getCopySourceEntry(o, copySource)
+	if ent == nil {
+		return
+	}
+	sourceObj := block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: ent.PhysicalAddress}
+	var (
+		size   int64
+		reader io.ReadCloser
+		err    error
+	)
+	if rangeSpec := o.Request.Header.Get(CopySourceRangeHeader); rangeSpec != "" {
+		var rng ghttp.Range
+		rng, err = ghttp.ParseCopySourceRange(rangeSpec, ent.Size)
+		if err != nil {
+			o.Log().WithError(err).WithField("range", rangeSpec).Debug("invalid copy source range")
+			errCode := errors.ErrInvalidCopyPartRange
+			if stderrors.Is(err, ghttp.ErrRangeOutsideSource) {
+				errCode = errors.ErrInvalidCopyPartRangeSource
+			}
+			o.EncodeError(errors.Codes.ToAPIErr(errCode))
+			return
+		}
+		size = rng.EndOffset - rng.StartOffset + 1 // both range ends are inclusive
+		if o.Limits.MaxPartSize > 0 && size > o.Limits.MaxPartSize {
+			o.encodeEntityTooLarge("part", o.Limits.MaxPartSize)
+			return
+		}
+		if !o.checkRepositoryQuota(size) {
+			return
+		}
+		reader, err = o.BlockStore.GetRange(sourceObj, rng.StartOffset, rng.EndOffset)
+	} else {
+		size = ent.Size
+		if o.Limits.MaxPartSize > 0 && size > o.Limits.MaxPartSize {
+			o.encodeEntityTooLarge("part", o.Limits.MaxPartSize)
+			return
+		}
+		if !o.checkRepositoryQuota(size) {
+			return
+		}
+		reader, err = o.BlockStore.Get(sourceObj, ent.Size)
+	}
+	if err != nil {
+		o.Log().WithError(err).Error("could not read copy source data")
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	multiPart, err := o.Cataloger.GetMultipartUpload(o.Context(), o.Repository.Name, uploadID)
+	if err != nil {
+		o.Log().WithError(err).Error("could not read  multipart record")
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
+		return
+	}
+	etag, err := o.BlockStore.UploadPart(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: multiPart.PhysicalAddress},
+		size, reader, uploadID, partNumber)
+	if err != nil {
+		o.Log().WithError(err).Error("part copy failed")
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
+		return
+	}
+	o.EncodeResponse(&serde.CopyPartResult{
+		LastModified: serde.Timestamp(time.Now()),
+		ETag:         etag,
+	}, http.StatusOK)
+}
+
 func (controller *PutObject) HandleUploadPart(o *PathOperation) {
 	o.Incr("put_mpu_part")
 	query := o.Request.URL.Query()
 	uploadID := query.Get(QueryParamUploadID)
 	partNumberStr := query.Get(QueryParamPartNumber)
 
-	partNumber, err := strconv.ParseInt(partNumberStr, 10, 64)
-	if err != nil {
-		o.Log().WithError(err).Error("invalid part number")
-		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidPartNumberMarker))
+	partNumber, ok := parsePartNumber(o)
+	if !ok {
+		return
+	}
+	if o.Limits.MaxPartSize > 0 && o.Request.ContentLength > o.Limits.MaxPartSize {
+		o.encodeEntityTooLarge("part", o.Limits.MaxPartSize)
 		return
 	}
 
@@ -98,6 +269,9 @@ func (controller *PutObject) HandleUploadPart(o *PathOperation) {
 		"part_number": partNumber,
 		"upload_id":   uploadID,
 	})
+	if !o.checkRepositoryQuota(o.Request.ContentLength) {
+		return
+	}
 
 	// handle the upload itself
 	multiPart, err := o.Cataloger.GetMultipartUpload(o.Context(), o.Repository.Name, uploadID)
@@ -108,7 +282,11 @@ func (controller *PutObject) HandleUploadPart(o *PathOperation) {
 	}
 	byteSize := o.Request.ContentLength
 	etag, err := o.BlockStore.UploadPart(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: multiPart.PhysicalAddress},
-		byteSize, o.Request.Body, uploadID, partNumber)
+		byteSize, upload.LimitReader(o.Request.Body, o.Limits.MaxPartSize), uploadID, partNumber)
+	if isEntityTooLarge(err) {
+		o.encodeEntityTooLarge("part", o.Limits.MaxPartSize)
+		return
+	}
 	if err != nil {
 		o.Log().WithError(err).Error("part " + partNumberStr + " upload failed")
 		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
@@ -137,7 +315,14 @@ func (controller *PutObject) Handle(o *PathOperation) {
 	storageClass := StorageClassFromHeader(o.Request.Header)
 	opts := block.PutOpts{StorageClass: storageClass}
 
+	query := o.Request.URL.Query()
+	_, hasUploadID := query[QueryParamUploadID]
+
 	copySource := o.Request.Header.Get(CopySourceHeader)
+	if len(copySource) > 0 && hasUploadID {
+		controller.HandleUploadPartCopy(o, copySource)
+		return
+	}
 	if len(copySource) > 0 {
 		// The *first* PUT operation sets PutOpts such as
 		// storage class, subsequent PUT operations of the
@@ -148,18 +333,38 @@ func (controller *PutObject) Handle(o *PathOperation) {
 		return
 	}
 
-	query := o.Request.URL.Query()
-
 	// check if this is a multipart upload creation call
-	_, hasUploadID := query[QueryParamUploadID]
 	if hasUploadID {
 		controller.HandleUploadPart(o)
 		return
 	}
 
+	if _, hasTagging := query[QueryParamTagging]; hasTagging {
+		handlePutObjectTagging(o)
+		return
+	}
+
+	tags, err := tagsFromHeader(o.Request.Header)
+	if err != nil {
+		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInvalidTag))
+		return
+	}
+
 	o.Incr("put_object")
+	if o.Limits.MaxObjectSize > 0 && o.Request.ContentLength > o.Limits.MaxObjectSize {
+		o.encodeEntityTooLarge("object", o.Limits.MaxObjectSize)
+		return
+	}
+	if !o.checkRepositoryQuota(o.Request.ContentLength) {
+		return
+	}
 	// handle the upload itself
-	blob, err := upload.WriteBlob(o.BlockStore, o.Repository.StorageNamespace, o.Request.Body, o.Request.ContentLength, opts)
+	body := upload.LimitReader(o.Request.Body, o.Limits.MaxObjectSize)
+	blob, err := upload.WriteBlob(o.BlockStore, o.Repository.StorageNamespace, body, o.Request.ContentLength, opts)
+	if isEntityTooLarge(err) {
+		o.encodeEntityTooLarge("object", o.Limits.MaxObjectSize)
+		return
+	}
 	if err != nil {
 		o.Log().WithError(err).Error("could not write request body to block adapter")
 		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
@@ -167,9 +372,13 @@ func (controller *PutObject) Handle(o *PathOperation) {
 	}
 
 	// write metadata
-	err = o.finishUpload(o.Repository.StorageNamespace, blob.Checksum, blob.PhysicalAddress, blob.Size)
+	err = o.finishUpload(o.Repository.StorageNamespace, blob.Checksum, blob.PhysicalAddress, blob.Size,
+		withTags(amzMetaFromHeader(o.Request.Header), tags), entryHeadersFromHeader(o.Request.Header))
+	if stderrors.Is(err, catalog.ErrQuotaExceeded) {
+		o.removeUploadedObject(blob.PhysicalAddress)
+	}
 	if err != nil {
-		o.EncodeError(errors.Codes.ToAPIErr(errors.ErrInternalError))
+		o.EncodeError(errors.Codes.ToAPIErr(uploadErrorCode(err)))
 		return
 	}
 	o.SetHeader("ETag", httputil.ETag(blob.Checksum))
@@ -5,9 +5,14 @@ import (
 	"crypto/md5" //nolint:gosec
 	"crypto/rand"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/go-test/deep"
 	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/gateway/operations"
+	"github.com/treeverse/lakefs/permissions"
 	"github.com/treeverse/lakefs/upload"
 )
 
@@ -77,3 +82,38 @@ func TestReadBlob(t *testing.T) {
 		})
 	}
 }
+
+func TestPutObjectRequiredPermissions(t *testing.T) {
+	write := permissions.Permission{Action: permissions.WriteObjectAction, Resource: permissions.ObjectArn("repo", "dst/file")}
+	cases := []struct {
+		Name       string
+		CopySource string
+		Expected   []permissions.Permission
+	}{
+		{Name: "put", Expected: []permissions.Permission{write}},
+		{Name: "copy", CopySource: "/repo/master/src/file", Expected: []permissions.Permission{
+			write,
+			{Action: permissions.ReadObjectAction, Resource: permissions.ObjectArn("repo", "src/file")},
+		}},
+		{Name: "escaped copy", CopySource: "repo/master/src%2Fmy%20file", Expected: []permissions.Permission{
+			write,
+			{Action: permissions.ReadObjectAction, Resource: permissions.ObjectArn("repo", "src/my file")},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			// UploadPartCopy is a PUT with both a copy source and a part number
+			req := httptest.NewRequest(http.MethodPut, "/repo/master/dst/file?uploadId=1&partNumber=1", nil)
+			if tc.CopySource != "" {
+				req.Header.Set(operations.CopySourceHeader, tc.CopySource)
+			}
+			perms, err := (&operations.PutObject{}).RequiredPermissions(req, "repo", "master", "dst/file")
+			if err != nil {
+				t.Fatalf("required permissions: %s", err)
+			}
+			if diff := deep.Equal(perms, tc.Expected); diff != nil {
+				t.Errorf("unexpected permissions: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package operations
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/db"
+	gatewayerrors "github.com/treeverse/lakefs/gateway/errors"
+)
+
+const QueryParamSelect = "select"
+
+var ErrInvalidSelectRequest = errors.New("invalid select request")
+
+// decodeSelectRequest decodes the body of SelectObjectContent.  The fields of the SDK input are
+// named after the elements of the request, so the body decodes into it directly.
+func decodeSelectRequest(reader io.Reader) (*s3.SelectObjectContentInput, error) {
+	var input s3.SelectObjectContentInput
+	if err := DecodeXMLBody(reader, &input); err != nil {
+		return nil, err
+	}
+	if aws.StringValue(input.Expression) == "" || aws.StringValue(input.ExpressionType) == "" ||
+		input.InputSerialization == nil || input.OutputSerialization == nil {
+		return nil, ErrInvalidSelectRequest
+	}
+	return &input, nil
+}
+
+// HandleSelectObjectContent pushes S3 Select down to the storage of the object, and passes its
+// response event stream on to the client
+func (controller *PostObject) HandleSelectObjectContent(o *PathOperation) {
+	o.Incr("select_object_content")
+	input, err := decodeSelectRequest(o.Request.Body)
+	if err != nil {
+		o.Log().WithError(err).Debug("could not parse select request")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrMalformedXML))
+		return
+	}
+	entry, err := o.Cataloger.GetEntry(o.Context(), o.Repository.Name, o.Reference, o.Path, catalog.GetEntryParams{})
+	if errors.Is(err, db.ErrNotFound) {
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchKey))
+		return
+	}
+	if errors.Is(err, catalog.ErrExpired) {
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNoSuchVersion))
+		return
+	}
+	if err != nil {
+		o.Log().WithError(err).Error("could not read selected object")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return
+	}
+
+	data, err := o.BlockStore.SelectObjectContent(block.ObjectPointer{StorageNamespace: o.Repository.StorageNamespace, Identifier: entry.PhysicalAddress}, input)
+	var requestFailure awserr.RequestFailure
+	switch {
+	case errors.Is(err, block.ErrSelectNotSupported):
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrNotImplemented))
+		return
+	case errors.As(err, &requestFailure):
+		// errors in the query or in the object content are reported by S3
+		o.Log().WithError(err).Debug("S3 rejected select request")
+		o.EncodeError(gatewayerrors.APIError{
+			Code:           requestFailure.Code(),
+			Description:    requestFailure.Message(),
+			HTTPStatusCode: requestFailure.StatusCode(),
+		})
+		return
+	case err != nil:
+		o.Log().WithError(err).Error("could not select object content")
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrInternalError))
+		return
+	}
+	defer func() {
+		_ = data.Close()
+	}()
+	o.ResponseWriter.WriteHeader(http.StatusOK)
+	_, err = io.Copy(o.ResponseWriter, data)
+	if err != nil {
+		o.Log().WithError(err).Error("could not write response body for select")
+	}
+}
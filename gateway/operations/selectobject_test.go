@@ -0,0 +1,45 @@
+package operations
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestDecodeSelectRequest(t *testing.T) {
+	body := `<SelectObjectContentRequest xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Expression>SELECT s.name FROM S3Object s</Expression>
+  <ExpressionType>SQL</ExpressionType>
+  <InputSerialization>
+    <CompressionType>GZIP</CompressionType>
+    <CSV><FileHeaderInfo>USE</FileHeaderInfo><FieldDelimiter>;</FieldDelimiter></CSV>
+  </InputSerialization>
+  <OutputSerialization><JSON><RecordDelimiter>,</RecordDelimiter></JSON></OutputSerialization>
+  <ScanRange><Start>10</Start><End>100</End></ScanRange>
+</SelectObjectContentRequest>`
+	input, err := decodeSelectRequest(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("decode select request: %s", err)
+	}
+	if aws.StringValue(input.Expression) != "SELECT s.name FROM S3Object s" || aws.StringValue(input.ExpressionType) != "SQL" {
+		t.Errorf("unexpected expression %s of type %s", aws.StringValue(input.Expression), aws.StringValue(input.ExpressionType))
+	}
+	if aws.StringValue(input.InputSerialization.CompressionType) != "GZIP" ||
+		aws.StringValue(input.InputSerialization.CSV.FileHeaderInfo) != "USE" ||
+		aws.StringValue(input.InputSerialization.CSV.FieldDelimiter) != ";" {
+		t.Errorf("unexpected input serialization %s", input.InputSerialization)
+	}
+	if aws.StringValue(input.OutputSerialization.JSON.RecordDelimiter) != "," {
+		t.Errorf("unexpected output serialization %s", input.OutputSerialization)
+	}
+	if aws.Int64Value(input.ScanRange.Start) != 10 || aws.Int64Value(input.ScanRange.End) != 100 {
+		t.Errorf("unexpected scan range %s", input.ScanRange)
+	}
+
+	_, err = decodeSelectRequest(strings.NewReader(`<SelectObjectContentRequest><ExpressionType>SQL</ExpressionType></SelectObjectContentRequest>`))
+	if !errors.Is(err, ErrInvalidSelectRequest) {
+		t.Errorf("expected %s decoding a request without expression, got %v", ErrInvalidSelectRequest, err)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/treeverse/lakefs/logging"
 	"github.com/treeverse/lakefs/stats"
 	"github.com/treeverse/lakefs/testutil"
+	uploadparams "github.com/treeverse/lakefs/upload/params"
 )
 
 type dependencies struct {
@@ -140,6 +141,8 @@ func getBasicHandler(t *testing.T, authService *simulator.PlayBackMockConf) (htt
 		authService.BareDomain,
 		&mockCollector{},
 		dedupCleaner,
+		uploadparams.Limits{},
+		false,
 	)
 
 	return handler, &dependencies{
@@ -0,0 +1,41 @@
+package gateway
+
+import (
+	"net/http"
+
+	gatewayerrors "github.com/treeverse/lakefs/gateway/errors"
+	"github.com/treeverse/lakefs/gateway/operations"
+)
+
+// isReadOnlyOperation returns true for the operations that don't modify data, served in
+// read-only mode.  Selecting object content is a POST that only reads the object.
+func isReadOnlyOperation(operation interface{}, request *http.Request) bool {
+	switch operation.(type) {
+	case *operations.ListBuckets, *operations.HeadBucket, *operations.ListObjects,
+		*operations.GetObject, *operations.HeadObject:
+		return true
+	case *operations.PostObject:
+		_, isSelect := request.URL.Query()[operations.QueryParamSelect]
+		return isSelect
+	}
+	return false
+}
+
+// readOnlyGuard returns next, rejecting the requests of operation that may modify data when
+// serving reads from a read-only replica of the database
+func (h *handler) readOnlyGuard(operation interface{}, next http.Handler) http.Handler {
+	if !h.readOnly {
+		return next
+	}
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !isReadOnlyOperation(operation, request) {
+			o := &operations.Operation{
+				Request:        request,
+				ResponseWriter: writer,
+			}
+			o.EncodeError(gatewayerrors.ErrReadOnlyMode.ToAPIErr())
+			return
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
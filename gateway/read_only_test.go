@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/treeverse/lakefs/gateway/operations"
+)
+
+func TestReadOnlyGuard(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := &handler{readOnly: true}
+	cases := []struct {
+		name      string
+		operation interface{}
+		method    string
+		target    string
+		allowed   bool
+	}{
+		{name: "list buckets", operation: &operations.ListBuckets{}, method: http.MethodGet, target: "/", allowed: true},
+		{name: "list objects", operation: &operations.ListObjects{}, method: http.MethodGet, target: "/repo", allowed: true},
+		{name: "get object", operation: &operations.GetObject{}, method: http.MethodGet, target: "/repo/master/a", allowed: true},
+		{name: "head object", operation: &operations.HeadObject{}, method: http.MethodHead, target: "/repo/master/a", allowed: true},
+		{name: "select object", operation: &operations.PostObject{}, method: http.MethodPost, target: "/repo/master/a?select&select-type=2", allowed: true},
+		{name: "create multipart upload", operation: &operations.PostObject{}, method: http.MethodPost, target: "/repo/master/a?uploads"},
+		{name: "put object", operation: &operations.PutObject{}, method: http.MethodPut, target: "/repo/master/a"},
+		{name: "delete object", operation: &operations.DeleteObject{}, method: http.MethodDelete, target: "/repo/master/a"},
+		{name: "delete objects", operation: &operations.DeleteObjects{}, method: http.MethodPost, target: "/repo?delete"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			h.readOnlyGuard(tt.operation, next).ServeHTTP(rr, httptest.NewRequest(tt.method, tt.target, nil))
+			if allowed := rr.Code == http.StatusOK; allowed != tt.allowed {
+				t.Fatalf("%s %s returned %d, expected allowed=%t", tt.method, tt.target, rr.Code, tt.allowed)
+			}
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	(&handler{}).readOnlyGuard(&operations.PutObject{}, next).ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/repo/master/a", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("put object without read-only mode returned %d", rr.Code)
+	}
+}
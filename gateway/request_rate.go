@@ -0,0 +1,24 @@
+package gateway
+
+import (
+	"errors"
+
+	"github.com/treeverse/lakefs/catalog"
+	gatewayerrors "github.com/treeverse/lakefs/gateway/errors"
+	"github.com/treeverse/lakefs/gateway/operations"
+)
+
+// allowRequestRate returns true if the request of o to repository keeps within the request
+// rate quota of the repository and of its organization.  Otherwise it encodes SlowDown, as
+// S3 throttles requests.  Only authenticated requests count.
+func allowRequestRate(o *operations.AuthenticatedOperation, repository string) bool {
+	err := o.Cataloger.CheckRequestRate(o.Context(), repository)
+	if errors.Is(err, catalog.ErrRequestRateExceeded) {
+		o.EncodeError(gatewayerrors.Codes.ToAPIErr(gatewayerrors.ErrSlowDown))
+		return false
+	}
+	if err != nil {
+		o.Log().WithError(err).WithField("repository", repository).Warn("could not check request rate")
+	}
+	return true
+}
@@ -89,6 +89,39 @@ type ListBucketResult struct {
 	Contents       []Contents       `xml:"Contents"`
 }
 
+type Version struct {
+	Key          string `xml:"Key"`
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool   `xml:"IsLatest"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type DeleteMarker struct {
+	Key          string `xml:"Key"`
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool   `xml:"IsLatest"`
+	LastModified string `xml:"LastModified"`
+}
+
+type ListVersionsResult struct {
+	XMLName             xml.Name         `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResult"`
+	Name                string           `xml:"Name"`
+	Prefix              string           `xml:"Prefix"`
+	Delimiter           string           `xml:"Delimiter,omitempty"`
+	KeyMarker           string           `xml:"KeyMarker"`
+	VersionIDMarker     string           `xml:"VersionIdMarker"`
+	NextKeyMarker       string           `xml:"NextKeyMarker,omitempty"`
+	NextVersionIDMarker string           `xml:"NextVersionIdMarker,omitempty"`
+	MaxKeys             int              `xml:"MaxKeys"`
+	IsTruncated         bool             `xml:"IsTruncated"`
+	Versions            []Version        `xml:"Version"`
+	DeleteMarkers       []DeleteMarker   `xml:"DeleteMarker"`
+	CommonPrefixes      []CommonPrefixes `xml:"CommonPrefixes"`
+}
+
 type Object struct {
 	Key       string `xml:"Key"`
 	VersionID string `xml:"VersionId,omitempty"`
@@ -124,6 +157,11 @@ type CopyObjectResult struct {
 	ETag         string `xml:"ETag"`
 }
 
+type CopyPartResult struct {
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+}
+
 type InitiateMultipartUploadResult struct {
 	Bucket   string `xml:"Bucket"`
 	Key      string `xml:"Key"`
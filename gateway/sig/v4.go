@@ -31,6 +31,17 @@ const (
 	v4timeFormat            = "20060102T150405Z"
 	v4shortTimeFormat       = "20060102"
 	v4SignatureHeader       = "X-Amz-Signature"
+	v4AlgorithmParam        = "X-Amz-Algorithm"
+	v4CredentialParam       = "X-Amz-Credential"
+	v4DateParam             = "X-Amz-Date"
+	v4ExpiresParam          = "X-Amz-Expires"
+	v4SignedHeadersParam    = "X-Amz-SignedHeaders"
+	v4Service               = "s3"
+
+	// V4MaxExpires is the longest time a pre-signed request remains valid
+	V4MaxExpires = 7 * 24 * time.Hour
+	// v4MaxClockSkew is how far in the future a pre-signed request may be dated
+	v4MaxClockSkew = 15 * time.Minute
 )
 
 var (
@@ -46,6 +57,10 @@ type V4Auth struct {
 	SignedHeaders       []string
 	SignedHeadersString string
 	Signature           string
+	// Presigned is set when the signature is passed in the query string.  Expires then
+	// holds the raw X-Amz-Expires parameter.
+	Presigned bool
+	Expires   string
 }
 
 func (a V4Auth) GetAccessKeyID() string {
@@ -89,11 +104,11 @@ func ParseV4AuthContext(r *http.Request) (V4Auth, error) {
 
 	// otherwise, see if we have all the required query parameters
 	query := r.URL.Query()
-	algorithm := query.Get("X-Amz-Algorithm")
+	algorithm := query.Get(v4AlgorithmParam)
 	if len(algorithm) == 0 || !strings.EqualFold(algorithm, v4authHeaderPrefix) {
 		return ctx, errors.ErrInvalidQuerySignatureAlgo
 	}
-	credentialScope := query.Get("X-Amz-Credential")
+	credentialScope := query.Get(v4CredentialParam)
 	if len(credentialScope) == 0 {
 		return ctx, errors.ErrMissingCredTag
 	}
@@ -112,13 +127,88 @@ func ParseV4AuthContext(r *http.Request) (V4Auth, error) {
 	ctx.Region = credsResult["Region"]
 	ctx.Service = credsResult["Service"]
 
-	ctx.SignedHeadersString = query.Get("X-Amz-SignedHeaders")
+	ctx.SignedHeadersString = query.Get(v4SignedHeadersParam)
 	headers := splitHeaders(ctx.SignedHeadersString)
 	ctx.SignedHeaders = headers
 	ctx.Signature = query.Get(v4SignatureHeader)
+	ctx.Presigned = true
+	ctx.Expires = query.Get(v4ExpiresParam)
 	return ctx, nil
 }
 
+// verifyExpiration checks that a pre-signed request dated amzDate that expires after the
+// X-Amz-Expires parameter is valid at now
+func verifyExpiration(auth V4Auth, amzDate string, now time.Time) error {
+	if auth.Expires == "" {
+		return errors.ErrInvalidQueryParams
+	}
+	expiresSeconds, err := strconv.ParseInt(auth.Expires, 10, 64)
+	if err != nil {
+		return errors.ErrMalformedExpires
+	}
+	if expiresSeconds < 0 {
+		return errors.ErrNegativeExpires
+	}
+	expires := time.Duration(expiresSeconds) * time.Second
+	if expires > V4MaxExpires {
+		return errors.ErrMaximumExpires
+	}
+	signedAt, err := time.Parse(v4timeFormat, amzDate)
+	if err != nil {
+		return errors.ErrMalformedDate
+	}
+	if signedAt.After(now.Add(v4MaxClockSkew)) {
+		return errors.ErrRequestNotReadyYet
+	}
+	if now.After(signedAt.Add(expires)) {
+		return errors.ErrExpiredPresignRequest
+	}
+	return nil
+}
+
+// V4Presign returns u with a query string signature of a request with method, made with creds
+// to region, that remains valid for expires after now.  Only the host header is signed, and
+// the payload is unsigned, so any client holding the URL may issue the request.
+func V4Presign(creds *model.Credential, method string, u *url.URL, region string, expires time.Duration, now time.Time) (*url.URL, error) {
+	if expires <= 0 {
+		return nil, errors.ErrNegativeExpires
+	}
+	if expires > V4MaxExpires {
+		return nil, errors.ErrMaximumExpires
+	}
+	now = now.UTC()
+	auth := V4Auth{
+		AccessKeyID:         creds.AccessKeyID,
+		Date:                now.Format(v4shortTimeFormat),
+		Region:              region,
+		Service:             v4Service,
+		SignedHeaders:       []string{"host"},
+		SignedHeadersString: "host",
+	}
+	presigned := *u
+	query := presigned.Query()
+	query.Set(v4AlgorithmParam, v4authHeaderPrefix)
+	query.Set(v4CredentialParam, strings.Join([]string{auth.AccessKeyID, auth.Date, auth.Region, auth.Service, v4scopeTerminator}, "/"))
+	query.Set(v4DateParam, now.Format(v4timeFormat))
+	query.Set(v4ExpiresParam, strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set(v4SignedHeadersParam, auth.SignedHeadersString)
+	presigned.RawQuery = query.Encode()
+
+	ctx := &verificationCtx{
+		Request:   &http.Request{Method: method, URL: &presigned, Host: presigned.Host, Header: http.Header{}},
+		Query:     query,
+		AuthValue: auth,
+	}
+	stringToSign, err := ctx.buildSignedString(ctx.buildCanonicalRequest())
+	if err != nil {
+		return nil, err
+	}
+	signingKey := createSignature(creds.AccessSecretKey, auth.Date, auth.Region, auth.Service)
+	query.Set(v4SignatureHeader, hex.EncodeToString(sign(signingKey, stringToSign)))
+	presigned.RawQuery = query.Encode()
+	return &presigned, nil
+}
+
 func V4Verify(auth V4Auth, credentials *model.Credential, r *http.Request) error {
 	ctx := &verificationCtx{
 		Request:   r,
@@ -140,6 +230,16 @@ func V4Verify(auth V4Auth, credentials *model.Credential, r *http.Request) error
 		return errors.ErrSignatureDoesNotMatch
 	}
 
+	if auth.Presigned {
+		amzDate, err := ctx.getAmzDate()
+		if err != nil {
+			return err
+		}
+		if err := verifyExpiration(auth, amzDate, time.Now()); err != nil {
+			return err
+		}
+	}
+
 	// wrap body with verifier
 	reader, err := ctx.reader(r.Body, credentials)
 	if err != nil {
@@ -257,7 +357,7 @@ func (ctx *verificationCtx) buildCanonicalRequest() string {
 
 func (ctx *verificationCtx) getAmzDate() (string, error) {
 	// https://docs.aws.amazon.com/general/latest/gr/sigv4-date-handling.html
-	amzDate := ctx.Request.URL.Query().Get("X-Amz-Date")
+	amzDate := ctx.Request.URL.Query().Get(v4DateParam)
 	if len(amzDate) == 0 {
 		amzDate = ctx.Request.Header.Get("x-amz-date")
 		if len(amzDate) == 0 {
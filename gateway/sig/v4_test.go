@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -340,3 +341,80 @@ func TestUnsignedPayload(t *testing.T) {
 		t.Errorf("expect not no error, got %v", err)
 	}
 }
+
+func TestV4Presign(t *testing.T) {
+	endpoint, err := url.Parse("http://s3.local.lakefs.io:8000/repo1/master/some dir/file.txt?partNumber=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	cases := []struct {
+		Name          string
+		Method        string
+		SignedAt      time.Time
+		Expires       time.Duration
+		Creds         *model.Credential
+		ExpectedError error
+	}{
+		{Name: "get", Method: http.MethodGet, SignedAt: now, Expires: time.Minute, Creds: mockCreds},
+		{Name: "put", Method: http.MethodPut, SignedAt: now, Expires: time.Minute, Creds: mockCreds},
+		{Name: "expired", Method: http.MethodGet, SignedAt: now.Add(-2 * time.Minute), Expires: time.Minute, Creds: mockCreds, ExpectedError: errors.ErrExpiredPresignRequest},
+		{Name: "not ready", Method: http.MethodGet, SignedAt: now.Add(time.Hour), Expires: time.Minute, Creds: mockCreds, ExpectedError: errors.ErrRequestNotReadyYet},
+		{Name: "other secret", Method: http.MethodGet, SignedAt: now, Expires: time.Minute, Creds: &model.Credential{
+			AccessKeyID:     mockCreds.AccessKeyID,
+			AccessSecretKey: "wJalrXUtnFEMI/K7MDENG/bPxRfiCYOTHERKEY",
+		}, ExpectedError: errors.ErrSignatureDoesNotMatch},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			presigned, err := sig.V4Presign(tc.Creds, tc.Method, endpoint, "us-east-1", tc.Expires, tc.SignedAt)
+			if err != nil {
+				t.Fatalf("presign: %s", err)
+			}
+			req, err := http.NewRequest(tc.Method, presigned.String(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			authenticator := sig.NewV4Authenticator(req)
+			if _, err := authenticator.Parse(); err != nil {
+				t.Fatalf("parse: %s", err)
+			}
+			err = authenticator.Verify(mockCreds, "")
+			if err != tc.ExpectedError {
+				t.Errorf("verify: expected %v, got %v", tc.ExpectedError, err)
+			}
+		})
+	}
+}
+
+func TestV4PresignExpires(t *testing.T) {
+	endpoint := &url.URL{Scheme: "http", Host: "s3.local.lakefs.io", Path: "/repo1/master/file"}
+	for _, expires := range []time.Duration{0, -time.Second, sig.V4MaxExpires + time.Second} {
+		if _, err := sig.V4Presign(mockCreds, http.MethodGet, endpoint, "us-east-1", expires, time.Now()); err == nil {
+			t.Errorf("presign expiring after %s: expected an error", expires)
+		}
+	}
+}
+
+func TestPresignedByAWSSDK(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://s3.local.lakefs.io:8000/repo1/master/file.txt", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := v4.NewSigner(credentials.NewStaticCredentials(mockCreds.AccessKeyID, mockCreds.AccessSecretKey, ""))
+	_, err = signer.Presign(req, nil, "s3", "us-east-1", time.Minute, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err = http.NewRequest(http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator := sig.NewV4Authenticator(req)
+	if _, err := authenticator.Parse(); err != nil {
+		t.Fatalf("parse: %s", err)
+	}
+	if err := authenticator.Verify(mockCreds, ""); err != nil {
+		t.Errorf("verify: %s", err)
+	}
+}
@@ -0,0 +1,106 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ActionsPrefix is the path prefix of the action files of a repository
+const ActionsPrefix = "_lakefs_actions/"
+
+var (
+	ErrInvalidAction = errors.New("invalid action")
+
+	hookIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
+// Action runs its hooks, in order, on the events it is on
+type Action struct {
+	Name  string                  `yaml:"name"`
+	On    map[EventType]*ActionOn `yaml:"on"`
+	Hooks []ActionHook            `yaml:"hooks"`
+}
+
+// ActionOn selects the branches an event runs an action on, all branches when empty
+type ActionOn struct {
+	Branches []string `yaml:"branches"`
+}
+
+type ActionHook struct {
+	ID          string                 `yaml:"id"`
+	Type        string                 `yaml:"type"`
+	Description string                 `yaml:"description"`
+	Properties  map[string]interface{} `yaml:"properties"`
+}
+
+// ParseAction parses and validates the YAML action in data
+func ParseAction(data []byte) (*Action, error) {
+	var action Action
+	if err := yaml.UnmarshalStrict(data, &action); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAction, err)
+	}
+	if err := action.validate(); err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+func (a *Action) validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("%w: missing name", ErrInvalidAction)
+	}
+	if len(a.On) == 0 {
+		return fmt.Errorf("%w: %s: missing 'on' events", ErrInvalidAction, a.Name)
+	}
+	for eventType, on := range a.On {
+		if _, ok := eventTypes[eventType]; !ok {
+			return fmt.Errorf("%w: %s: unknown event '%s'", ErrInvalidAction, a.Name, eventType)
+		}
+		if on == nil {
+			continue
+		}
+		for _, branch := range on.Branches {
+			if _, err := path.Match(branch, ""); err != nil {
+				return fmt.Errorf("%w: %s: branch pattern '%s': %s", ErrInvalidAction, a.Name, branch, err)
+			}
+		}
+	}
+	if len(a.Hooks) == 0 {
+		return fmt.Errorf("%w: %s: missing hooks", ErrInvalidAction, a.Name)
+	}
+	ids := make(map[string]struct{}, len(a.Hooks))
+	for _, h := range a.Hooks {
+		if !hookIDRegexp.MatchString(h.ID) {
+			return fmt.Errorf("%w: %s: invalid hook id '%s'", ErrInvalidAction, a.Name, h.ID)
+		}
+		if _, ok := ids[h.ID]; ok {
+			return fmt.Errorf("%w: %s: duplicate hook id '%s'", ErrInvalidAction, a.Name, h.ID)
+		}
+		ids[h.ID] = struct{}{}
+		if _, err := NewHook(h); err != nil {
+			return fmt.Errorf("%w: %s: hook '%s': %s", ErrInvalidAction, a.Name, h.ID, err)
+		}
+	}
+	return nil
+}
+
+// Match returns true if the action runs on event
+func (a *Action) Match(event *Event) bool {
+	on, ok := a.On[event.Type]
+	if !ok {
+		return false
+	}
+	if on == nil || len(on.Branches) == 0 {
+		return true
+	}
+	for _, pattern := range on.Branches {
+		if matched, _ := path.Match(pattern, event.Branch); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package hooks
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			data: `
+name: check
+on:
+  pre-commit:
+  pre-merge:
+    branches: [main, release-*]
+hooks:
+  - id: lua_check
+    type: lua
+    properties:
+      script: "assert(action.branch ~= '')"
+`,
+		},
+		{name: "invalid yaml", data: "name: [", wantErr: true},
+		{name: "unknown field", data: "name: a\nwhen: pre-commit\n", wantErr: true},
+		{name: "no name", data: "on:\n  pre-commit:\nhooks:\n  - id: a\n    type: lua\n    properties:\n      script: x = 1\n", wantErr: true},
+		{name: "no events", data: "name: a\nhooks:\n  - id: a\n    type: lua\n    properties:\n      script: x = 1\n", wantErr: true},
+		{name: "unknown event", data: "name: a\non:\n  post-tag:\nhooks:\n  - id: a\n    type: lua\n    properties:\n      script: x = 1\n", wantErr: true},
+		{name: "bad branch pattern", data: "name: a\non:\n  pre-commit:\n    branches: ['[']\nhooks:\n  - id: a\n    type: lua\n    properties:\n      script: x = 1\n", wantErr: true},
+		{name: "no hooks", data: "name: a\non:\n  pre-commit:\n", wantErr: true},
+		{name: "duplicate hook id", data: "name: a\non:\n  pre-commit:\nhooks:\n  - id: a\n    type: lua\n    properties:\n      script: x = 1\n  - id: a\n    type: lua\n    properties:\n      script: x = 1\n", wantErr: true},
+		{name: "unknown hook type", data: "name: a\non:\n  pre-commit:\nhooks:\n  - id: a\n    type: webhook\n", wantErr: true},
+		{name: "lua syntax error", data: "name: a\non:\n  pre-commit:\nhooks:\n  - id: a\n    type: lua\n    properties:\n      script: x = \n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAction([]byte(tt.data))
+			if tt.wantErr != errors.Is(err, ErrInvalidAction) {
+				t.Fatalf("ParseAction() err=%v, expected error=%t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAction_Match(t *testing.T) {
+	action := &Action{
+		Name: "check",
+		On: map[EventType]*ActionOn{
+			EventTypePreCommit: nil,
+			EventTypePreMerge:  {Branches: []string{"main", "release-*"}},
+		},
+	}
+	tests := []struct {
+		name   string
+		event  Event
+		wanted bool
+	}{
+		{name: "commit any branch", event: Event{Type: EventTypePreCommit, Branch: "feature"}, wanted: true},
+		{name: "merge main", event: Event{Type: EventTypePreMerge, Branch: "main"}, wanted: true},
+		{name: "merge release", event: Event{Type: EventTypePreMerge, Branch: "release-1"}, wanted: true},
+		{name: "merge other", event: Event{Type: EventTypePreMerge, Branch: "feature"}},
+		{name: "other event", event: Event{Type: "post-commit", Branch: "main"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := action.Match(&tt.event); got != tt.wanted {
+				t.Fatalf("Match() = %t, expected %t", got, tt.wanted)
+			}
+		})
+	}
+}
+
+func TestActionsRef(t *testing.T) {
+	tests := []struct {
+		event  Event
+		wanted string
+	}{
+		{event: Event{Type: EventTypePreCommit, Branch: "main", SourceRef: "main"}, wanted: "main"},
+		{event: Event{Type: EventTypePreMerge, Branch: "main", SourceRef: "feature"}, wanted: "main:HEAD"},
+		{event: Event{Type: EventTypePostMerge, Branch: "main", SourceRef: "~abc"}, wanted: "~abc"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.event.Type), func(t *testing.T) {
+			if got := ActionsRef(&tt.event); got != tt.wanted {
+				t.Fatalf("ActionsRef() = %s, expected %s", got, tt.wanted)
+			}
+		})
+	}
+}
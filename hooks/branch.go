@@ -0,0 +1,125 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+const BranchHookType = "branch"
+
+// Modes of BranchHook
+const (
+	// BranchModeCreate creates the branch from the event branch, unless it exists
+	BranchModeCreate = "create"
+	// BranchModeMerge merges the event branch into the branch, creating it when missing
+	BranchModeMerge = "merge"
+	// BranchModeReset recreates the branch from the event branch, discarding its changes
+	BranchModeReset = "reset"
+)
+
+var ErrPostEventOnly = errors.New("hook runs only on post-commit and post-merge events")
+
+// BranchHook updates a downstream branch after a commit or a merge: it creates a branch named
+// by a template, like a snapshot of a release, or refreshes a branch from the event branch.
+type BranchHook struct {
+	id     string
+	branch *template.Template
+	mode   string
+}
+
+// BranchTemplateData is the data of the branch and tag name templates
+type BranchTemplateData struct {
+	Repository string
+	// Branch is the branch the event wrote to
+	Branch string
+	// CommitID is the commit created by the event
+	CommitID string
+	// Date is the date of the event, formatted as 20060102
+	Date string
+	// Time is the time of the event
+	Time time.Time
+}
+
+// nameTemplateProperty parses the required name template of property
+func nameTemplateProperty(h ActionHook, property string) (*template.Template, error) {
+	name, err := stringProperty(h, property)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%w: %s is required", ErrInvalidProperty, property)
+	}
+	tmpl, err := template.New(h.ID).Option("missingkey=error").Parse(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidProperty, property, err)
+	}
+	return tmpl, nil
+}
+
+// executeNameTemplate returns the name tmpl renders for event
+func executeNameTemplate(tmpl *template.Template, event *Event) (string, error) {
+	now := time.Now().UTC()
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, &BranchTemplateData{
+		Repository: event.Repository,
+		Branch:     event.Branch,
+		CommitID:   event.SourceRef,
+		Date:       now.Format("20060102"),
+		Time:       now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func NewBranchHook(h ActionHook) (Hook, error) {
+	tmpl, err := nameTemplateProperty(h, "branch")
+	if err != nil {
+		return nil, err
+	}
+	mode, err := stringProperty(h, "mode")
+	if err != nil {
+		return nil, err
+	}
+	switch mode {
+	case "":
+		mode = BranchModeMerge
+	case BranchModeCreate, BranchModeMerge, BranchModeReset:
+	default:
+		return nil, fmt.Errorf("%w: unknown mode '%s'", ErrInvalidProperty, mode)
+	}
+	return &BranchHook{id: h.ID, branch: tmpl, mode: mode}, nil
+}
+
+func (h *BranchHook) Run(ctx context.Context, event *Event, env Env) error {
+	if !event.IsPost() {
+		return ErrPostEventOnly
+	}
+	branch, err := executeNameTemplate(h.branch, event)
+	if err != nil {
+		return fmt.Errorf("branch name: %w", err)
+	}
+	if branch == event.Branch {
+		return fmt.Errorf("%w: branch %s is the event branch", ErrInvalidProperty, branch)
+	}
+	exists, err := env.BranchExists(ctx, branch)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !exists:
+		return env.CreateBranch(ctx, branch, event.Branch)
+	case h.mode == BranchModeCreate:
+		return nil
+	case h.mode == BranchModeReset:
+		return env.RecreateBranch(ctx, branch, event.Branch)
+	default:
+		message := fmt.Sprintf("Merge %s into %s by hook %s", event.Branch, branch, h.id)
+		return env.Merge(ctx, event.Branch, branch, message)
+	}
+}
@@ -0,0 +1,145 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/auth"
+	"github.com/treeverse/lakefs/permissions"
+)
+
+func TestBranchHook_Run(t *testing.T) {
+	event := &Event{
+		Type:       EventTypePostMerge,
+		Repository: "repo",
+		Branch:     "main",
+		SourceRef:  "~abc",
+		ParentRef:  "~abb",
+	}
+	date := time.Now().UTC().Format("20060102")
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		branches   map[string]bool
+		event      *Event
+		wantOps    []string
+		wantErr    error
+	}{
+		{
+			name:       "merge into existing branch",
+			properties: map[string]interface{}{"branch": "staging"},
+			branches:   map[string]bool{"main": true, "staging": true},
+			wantOps:    []string{"merge main into staging"},
+		},
+		{
+			name:       "merge creates missing branch",
+			properties: map[string]interface{}{"branch": "staging"},
+			branches:   map[string]bool{"main": true},
+			wantOps:    []string{"create staging from main"},
+		},
+		{
+			name:       "reset existing branch",
+			properties: map[string]interface{}{"branch": "staging", "mode": "reset"},
+			branches:   map[string]bool{"main": true, "staging": true},
+			wantOps:    []string{"recreate staging from main"},
+		},
+		{
+			name:       "create templated branch",
+			properties: map[string]interface{}{"branch": "release-{{.Date}}", "mode": "create"},
+			branches:   map[string]bool{"main": true},
+			wantOps:    []string{"create release-" + date + " from main"},
+		},
+		{
+			name:       "create keeps existing branch",
+			properties: map[string]interface{}{"branch": "release-{{.Date}}", "mode": "create"},
+			branches:   map[string]bool{"main": true, "release-" + date: true},
+		},
+		{
+			name:       "event branch",
+			properties: map[string]interface{}{"branch": "{{.Branch}}"},
+			branches:   map[string]bool{"main": true},
+			wantErr:    ErrInvalidProperty,
+		},
+		{
+			name:       "pre event",
+			properties: map[string]interface{}{"branch": "staging"},
+			event:      &Event{Type: EventTypePreMerge, Repository: "repo", Branch: "main", SourceRef: "feature"},
+			wantErr:    ErrPostEventOnly,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := NewBranchHook(ActionHook{ID: "fan_out", Type: BranchHookType, Properties: tt.properties})
+			if err != nil {
+				t.Fatal(err)
+			}
+			env := &fakeEnv{branches: tt.branches}
+			e := event
+			if tt.event != nil {
+				e = tt.event
+			}
+			err = hook.Run(context.Background(), e, env)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run() err=%v, expected %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(env.ops, tt.wantOps); diff != nil {
+				t.Fatalf("Run() operations %s", diff)
+			}
+		})
+	}
+}
+
+func TestNewBranchHook_InvalidProperties(t *testing.T) {
+	for _, properties := range []map[string]interface{}{
+		nil,
+		{"branch": "release-{{.Date"},
+		{"branch": "staging", "mode": "rebase"},
+	} {
+		_, err := NewBranchHook(ActionHook{ID: "fan_out", Type: BranchHookType, Properties: properties})
+		if !errors.Is(err, ErrInvalidProperty) {
+			t.Fatalf("NewBranchHook(%v) err=%v, expected %v", properties, err, ErrInvalidProperty)
+		}
+	}
+}
+
+type fakeAuthorizer struct {
+	allowed map[string]bool
+}
+
+func (a *fakeAuthorizer) Authorize(req *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error) {
+	for _, p := range req.RequiredPermissions {
+		if !a.allowed[req.Username+" "+p.Action+" "+p.Resource] {
+			return &auth.AuthorizationResponse{Allowed: false}, nil
+		}
+	}
+	return &auth.AuthorizationResponse{Allowed: true}, nil
+}
+
+func TestCatalogEnv_Unauthorized(t *testing.T) {
+	ctx := context.Background()
+	event := &Event{Type: EventTypePostMerge, Repository: "repo", Branch: "main", SourceRef: "~abc", Committer: "alice"}
+	// alice may only create staging, writes beyond it fail before reaching the catalog
+	authorizer := &fakeAuthorizer{allowed: map[string]bool{
+		"alice " + permissions.CreateBranchAction + " " + permissions.BranchArn("repo", "staging"): true,
+	}}
+	env := &catalogEnv{service: NewService(nil, nil, nil, nil, nil, authorizer), event: event}
+	ops := map[string]func() error{
+		"recreate": func() error { return env.RecreateBranch(ctx, "staging", "main") },
+		"merge":    func() error { return env.Merge(ctx, "main", "staging", "merge") },
+		"tag":      func() error { return env.CreateTag(ctx, "v1", "~abc") },
+		"create":   func() error { return env.CreateBranch(ctx, "production", "main") },
+	}
+	for name, op := range ops {
+		if err := op(); !errors.Is(err, ErrNotAuthorized) {
+			t.Errorf("%s err=%v, expected %v", name, err, ErrNotAuthorized)
+		}
+	}
+
+	noAuthEnv := &catalogEnv{service: NewService(nil, nil, nil, nil, nil, nil), event: event}
+	if err := noAuthEnv.CreateBranch(ctx, "staging", "main"); !errors.Is(err, ErrNotAuthorized) {
+		t.Errorf("create without authorizer err=%v, expected %v", err, ErrNotAuthorized)
+	}
+}
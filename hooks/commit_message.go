@@ -0,0 +1,59 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const CommitMessageHookType = "commit_message"
+
+// commitMessageTemplates are the patterns of well known commit message conventions
+var commitMessageTemplates = map[string]string{
+	// conventional commits: "type(scope)!: description"
+	"conventional": `^(build|chore|ci|docs|feat|fix|perf|refactor|revert|style|test)(\([\w./-]+\))?!?: \S`,
+	// a ticket reference prefix: "PROJ-123 description" or "[PROJ-123] description"
+	"ticket": `^\[?[A-Z][A-Z0-9]+-[0-9]+\]?[: ]`,
+}
+
+var ErrInvalidCommitMessage = errors.New("invalid commit message")
+
+// CommitMessageHook fails operations whose commit message doesn't match a pattern, given as a
+// regular expression or as the name of a template
+type CommitMessageHook struct {
+	pattern *regexp.Regexp
+}
+
+func NewCommitMessageHook(h ActionHook) (Hook, error) {
+	pattern, err := stringProperty(h, "pattern")
+	if err != nil {
+		return nil, err
+	}
+	template, err := stringProperty(h, "template")
+	if err != nil {
+		return nil, err
+	}
+	if (pattern == "") == (template == "") {
+		return nil, fmt.Errorf("%w: exactly one of pattern or template is required", ErrInvalidProperty)
+	}
+	if template != "" {
+		var ok bool
+		pattern, ok = commitMessageTemplates[template]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown template '%s'", ErrInvalidProperty, template)
+		}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: pattern: %s", ErrInvalidProperty, err)
+	}
+	return &CommitMessageHook{pattern: re}, nil
+}
+
+func (h *CommitMessageHook) Run(_ context.Context, event *Event, _ Env) error {
+	if !h.pattern.MatchString(event.CommitMessage) {
+		return fmt.Errorf("%w: '%s' doesn't match '%s'", ErrInvalidCommitMessage, event.CommitMessage, h.pattern)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCommitMessageHook_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		message    string
+		wantErr    error
+	}{
+		{name: "pattern match", properties: map[string]interface{}{"pattern": "^data: "}, message: "data: add table"},
+		{name: "pattern mismatch", properties: map[string]interface{}{"pattern": "^data: "}, message: "add table", wantErr: ErrInvalidCommitMessage},
+		{name: "conventional", properties: map[string]interface{}{"template": "conventional"}, message: "feat(tables): add table a"},
+		{name: "conventional breaking", properties: map[string]interface{}{"template": "conventional"}, message: "fix!: drop column b"},
+		{name: "not conventional", properties: map[string]interface{}{"template": "conventional"}, message: "added table a", wantErr: ErrInvalidCommitMessage},
+		{name: "ticket", properties: map[string]interface{}{"template": "ticket"}, message: "DATA-12 add table a"},
+		{name: "ticket brackets", properties: map[string]interface{}{"template": "ticket"}, message: "[DATA-12] add table a"},
+		{name: "no ticket", properties: map[string]interface{}{"template": "ticket"}, message: "add table a", wantErr: ErrInvalidCommitMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := NewCommitMessageHook(ActionHook{ID: "message", Type: CommitMessageHookType, Properties: tt.properties})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = hook.Run(context.Background(), &Event{Type: EventTypePreCommit, CommitMessage: tt.message}, &fakeEnv{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run() err=%v, expected %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewCommitMessageHook_InvalidProperties(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+	}{
+		{name: "none", properties: nil},
+		{name: "both", properties: map[string]interface{}{"pattern": "^a", "template": "ticket"}},
+		{name: "unknown template", properties: map[string]interface{}{"template": "jira"}},
+		{name: "invalid pattern", properties: map[string]interface{}{"pattern": "(a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCommitMessageHook(ActionHook{ID: "message", Type: CommitMessageHookType, Properties: tt.properties})
+			if !errors.Is(err, ErrInvalidProperty) {
+				t.Fatalf("NewCommitMessageHook() err=%v, expected %v", err, ErrInvalidProperty)
+			}
+		})
+	}
+}
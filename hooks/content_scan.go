@@ -0,0 +1,203 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/logging"
+)
+
+const (
+	ContentScanHookType = "content_scan"
+
+	// ContentScanFindingsMetadataKey is the commit metadata key of the findings of flagging
+	// content scan hooks
+	ContentScanFindingsMetadataKey = "lakefs_scan_findings"
+
+	ContentScanActionBlock = "block"
+	ContentScanActionFlag  = "flag"
+
+	contentScanTimeout = 5 * time.Minute
+	// contentScanMaxResponseBytes limits the size of scanner responses read into memory
+	contentScanMaxResponseBytes = 1024 * 1024
+)
+
+var (
+	ErrSensitiveData  = errors.New("sensitive data found")
+	ErrScannerFailure = errors.New("scanner failed")
+	ErrUnknownScanner = errors.New("unknown content scanner")
+)
+
+// ContentScanHook streams the objects added or changed by an operation to a scanning
+// endpoint, and blocks the operation when the scanner finds sensitive data in them, or flags
+// it by adding the findings to its commit metadata.  Actions name a scanner configured on the
+// server, they cannot send objects to other endpoints.
+//
+// Each object is sent in the body of a POST request to the endpoint, with its repository,
+// reference and path in the X-LakeFS-Repository, X-LakeFS-Ref and X-LakeFS-Path headers.  The
+// scanner responds with a JSON object: {"findings": [{"type": "...", "description": "..."}]}.
+type ContentScanHook struct {
+	scanner  string
+	action   string
+	prefixes []string
+	client   *http.Client
+}
+
+// ContentScanFinding is sensitive data found in the object at Path
+type ContentScanFinding struct {
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+type contentScanResponse struct {
+	Findings []ContentScanFinding `json:"findings"`
+}
+
+func NewContentScanHook(h ActionHook) (Hook, error) {
+	if _, ok := h.Properties["endpoint"]; ok {
+		return nil, fmt.Errorf("%w: endpoint is configured on the server, name its scanner instead", ErrInvalidProperty)
+	}
+	scanner, err := stringProperty(h, "scanner")
+	if err != nil {
+		return nil, err
+	}
+	if scanner == "" {
+		return nil, fmt.Errorf("%w: scanner is required", ErrInvalidProperty)
+	}
+	action, err := stringProperty(h, "action")
+	if err != nil {
+		return nil, err
+	}
+	switch action {
+	case "":
+		action = ContentScanActionBlock
+	case ContentScanActionBlock, ContentScanActionFlag:
+	default:
+		return nil, fmt.Errorf("%w: unknown action '%s'", ErrInvalidProperty, action)
+	}
+	prefixes, err := stringsProperty(h, "prefixes")
+	if err != nil {
+		return nil, err
+	}
+	return &ContentScanHook{
+		scanner:  scanner,
+		action:   action,
+		prefixes: prefixes,
+		client:   &http.Client{Timeout: contentScanTimeout},
+	}, nil
+}
+
+func (h *ContentScanHook) Run(ctx context.Context, event *Event, env Env) error {
+	endpoint, err := env.ContentScanner(h.scanner)
+	if err != nil {
+		return err
+	}
+	changes, err := env.Changes(ctx)
+	if err != nil {
+		return err
+	}
+	var findings []ContentScanFinding
+	for _, change := range changes {
+		if change.Type == ChangeTypeRemoved || !h.match(change.Path) {
+			continue
+		}
+		found, err := h.scan(ctx, event, env, endpoint, change.Path)
+		if err != nil {
+			return fmt.Errorf("scan %s: %w", change.Path, err)
+		}
+		findings = append(findings, found...)
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	// the commit of a post event was already created, its findings fail the run
+	if h.action == ContentScanActionBlock || event.IsPost() {
+		descriptions := make([]string, len(findings))
+		for i, f := range findings {
+			descriptions[i] = f.Path + ": " + f.Type
+		}
+		return fmt.Errorf("%w: %s", ErrSensitiveData, strings.Join(descriptions, ", "))
+	}
+	return flagFindings(event, findings)
+}
+
+func (h *ContentScanHook) match(p string) bool {
+	if len(h.prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range h.prefixes {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// scan sends the object at path to the scanner at endpoint.  Failures returned name only the
+// scanner, as hook errors are shown to users, and the details are logged.
+func (h *ContentScanHook) scan(ctx context.Context, event *Event, env Env, endpoint, path string) ([]ContentScanFinding, error) {
+	reader, err := env.ReadObject(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-LakeFS-Repository", event.Repository)
+	req.Header.Set("X-LakeFS-Ref", event.SourceRef)
+	req.Header.Set("X-LakeFS-Path", path)
+	log := logging.FromContext(ctx).WithFields(logging.Fields{"scanner": h.scanner, "path": path})
+	resp, err := h.client.Do(req)
+	if err != nil {
+		log.WithError(err).Warn("content scanner request failed")
+		return nil, fmt.Errorf("%w: %s unreachable", ErrScannerFailure, h.scanner)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, contentScanMaxResponseBytes))
+	if err != nil {
+		log.WithError(err).Warn("content scanner response failed")
+		return nil, fmt.Errorf("%w: %s response failed", ErrScannerFailure, h.scanner)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.WithFields(logging.Fields{"status": resp.Status, "body": string(body)}).Warn("content scanner error response")
+		return nil, fmt.Errorf("%w: %s responded %s", ErrScannerFailure, h.scanner, resp.Status)
+	}
+	var result contentScanResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		log.WithError(err).WithField("body", string(body)).Warn("invalid content scanner response")
+		return nil, fmt.Errorf("%w: %s invalid response", ErrScannerFailure, h.scanner)
+	}
+	for i := range result.Findings {
+		result.Findings[i].Path = path
+	}
+	return result.Findings, nil
+}
+
+// flagFindings adds findings to the findings in the commit metadata of event
+func flagFindings(event *Event, findings []ContentScanFinding) error {
+	if event.Metadata == nil {
+		event.Metadata = make(map[string]string)
+	}
+	var all []ContentScanFinding
+	if existing, ok := event.Metadata[ContentScanFindingsMetadataKey]; ok {
+		_ = json.Unmarshal([]byte(existing), &all)
+	}
+	all = append(all, findings...)
+	data, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	event.Metadata[ContentScanFindingsMetadataKey] = string(data)
+	return nil
+}
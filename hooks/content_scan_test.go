@@ -0,0 +1,142 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+// fakeScanner finds an email in objects containing '@'
+func fakeScanner(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-LakeFS-Repository") != "repo" || r.Header.Get("X-LakeFS-Path") == "" {
+			http.Error(w, "missing headers", http.StatusBadRequest)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.Contains(string(body), "fail") {
+			http.Error(w, "scanner down: internal details", http.StatusInternalServerError)
+			return
+		}
+		var resp contentScanResponse
+		if strings.Contains(string(body), "@") {
+			resp.Findings = append(resp.Findings, ContentScanFinding{Type: "email", Description: "email address"})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestContentScanHook_Run(t *testing.T) {
+	srv := fakeScanner(t)
+	tests := []struct {
+		name         string
+		properties   map[string]interface{}
+		objects      map[string]string
+		eventType    EventType
+		wantErr      error
+		wantMetadata map[string]string
+	}{
+		{
+			name:    "clean",
+			objects: map[string]string{"users/a.csv": "id,name\n1,alice\n"},
+		},
+		{
+			name:    "block",
+			objects: map[string]string{"users/a.csv": "id,email\n1,alice@example.com\n"},
+			wantErr: ErrSensitiveData,
+		},
+		{
+			name:       "outside prefixes",
+			properties: map[string]interface{}{"prefixes": []interface{}{"public/"}},
+			objects:    map[string]string{"users/a.csv": "id,email\n1,alice@example.com\n"},
+		},
+		{
+			name:       "flag",
+			properties: map[string]interface{}{"action": "flag"},
+			objects:    map[string]string{"users/a.csv": "id,email\n1,alice@example.com\n"},
+			wantMetadata: map[string]string{
+				"team":                         "data",
+				ContentScanFindingsMetadataKey: `[{"path":"users/a.csv","type":"email","description":"email address"}]`,
+			},
+		},
+		{
+			name:       "flag post event",
+			properties: map[string]interface{}{"action": "flag"},
+			objects:    map[string]string{"users/a.csv": "id,email\n1,alice@example.com\n"},
+			eventType:  EventTypePostCommit,
+			wantErr:    ErrSensitiveData,
+		},
+		{
+			name:    "scanner failure",
+			objects: map[string]string{"users/a.csv": "fail"},
+			wantErr: ErrScannerFailure,
+		},
+		{
+			name:       "unknown scanner",
+			properties: map[string]interface{}{"scanner": "other"},
+			objects:    map[string]string{"users/a.csv": "id,name\n1,alice\n"},
+			wantErr:    ErrUnknownScanner,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			properties := map[string]interface{}{"scanner": "dlp"}
+			for k, v := range tt.properties {
+				properties[k] = v
+			}
+			hook, err := NewContentScanHook(ActionHook{ID: "dlp", Type: ContentScanHookType, Properties: properties})
+			if err != nil {
+				t.Fatal(err)
+			}
+			env := &fakeEnv{objects: tt.objects, scanners: map[string]string{"dlp": srv.URL}}
+			for p := range tt.objects {
+				env.changes = append(env.changes, Change{Path: p, Type: ChangeTypeAdded})
+			}
+			eventType := tt.eventType
+			if eventType == "" {
+				eventType = EventTypePreCommit
+			}
+			event := &Event{Type: eventType, Repository: "repo", Branch: "main", SourceRef: "main", Metadata: map[string]string{"team": "data"}}
+			err = hook.Run(context.Background(), event, env)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run() err=%v, expected %v", err, tt.wantErr)
+			}
+			// scanner responses are logged, not shown to users
+			if err != nil && strings.Contains(err.Error(), "internal details") {
+				t.Fatalf("Run() err=%v includes the scanner response", err)
+			}
+			wantMetadata := tt.wantMetadata
+			if wantMetadata == nil {
+				wantMetadata = map[string]string{"team": "data"}
+			}
+			if diff := deep.Equal(event.Metadata, wantMetadata); diff != nil {
+				t.Fatalf("Run() metadata %s", diff)
+			}
+		})
+	}
+}
+
+func TestNewContentScanHook_InvalidProperties(t *testing.T) {
+	for _, properties := range []map[string]interface{}{
+		nil,
+		{"endpoint": "http://scanner:8080"},
+		{"scanner": "dlp", "endpoint": "http://scanner:8080"},
+		{"scanner": "dlp", "action": "quarantine"},
+		{"scanner": "dlp", "prefixes": "users/"},
+	} {
+		_, err := NewContentScanHook(ActionHook{ID: "dlp", Type: ContentScanHookType, Properties: properties})
+		if !errors.Is(err, ErrInvalidProperty) {
+			t.Fatalf("NewContentScanHook(%v) err=%v, expected %v", properties, err, ErrInvalidProperty)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package hooks
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+type EventType string
+
+const (
+	EventTypePreCommit  EventType = "pre-commit"
+	EventTypePreMerge   EventType = "pre-merge"
+	EventTypePostCommit EventType = "post-commit"
+	EventTypePostMerge  EventType = "post-merge"
+)
+
+var eventTypes = map[EventType]struct{}{
+	EventTypePreCommit:  {},
+	EventTypePreMerge:   {},
+	EventTypePostCommit: {},
+	EventTypePostMerge:  {},
+}
+
+var ErrInvalidEventSrcFormat = errors.New("invalid event source format")
+
+// Event describes a repository operation that runs hooks.  Pre events run before the operation
+// is performed and fail it when a hook fails, post events run after it was performed.
+type Event struct {
+	// ID identifies the runs of the event hooks, set when hooks run
+	ID         string    `json:"id"`
+	Type       EventType `json:"event_type"`
+	Repository string    `json:"repository"`
+	// Branch is the branch the operation writes to
+	Branch string `json:"branch"`
+	// SourceRef holds the changes of the operation: Branch for pre-commit, the merged reference
+	// for pre-merge, and the commit created by the operation for post events.  Objects are read
+	// from it, and so are actions except for pre-merge events.
+	SourceRef string `json:"source_ref"`
+	// ParentRef is the commit of Branch before the operation, set for post events
+	ParentRef     string            `json:"parent_ref,omitempty"`
+	CommitMessage string            `json:"commit_message"`
+	Committer     string            `json:"committer"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	// DryRun is set when hooks run without performing the operation, hooks should not write
+	// anything
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// IsPost returns true for events of operations that were already performed
+func (e *Event) IsPost() bool {
+	return e.Type == EventTypePostCommit || e.Type == EventTypePostMerge
+}
+
+func (e Event) Value() (driver.Value, error) {
+	return json.Marshal(e)
+}
+
+func (e *Event) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	data, ok := src.([]byte)
+	if !ok {
+		return ErrInvalidEventSrcFormat
+	}
+	return json.Unmarshal(data, e)
+}
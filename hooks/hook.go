@@ -0,0 +1,133 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/treeverse/lakefs/preview"
+)
+
+var (
+	ErrUnknownHookType = errors.New("unknown hook type")
+	ErrInvalidProperty = errors.New("invalid hook property")
+)
+
+// Hook checks an event, an error fails the operation
+type Hook interface {
+	Run(ctx context.Context, event *Event, env Env) error
+}
+
+type NewHookFunc func(h ActionHook) (Hook, error)
+
+var hookTypes = map[string]NewHookFunc{
+	LuaHookType:           NewLuaHook,
+	ParquetSchemaHookType: NewParquetSchemaHook,
+	CommitMessageHookType: NewCommitMessageHook,
+	ContentScanHookType:   NewContentScanHook,
+	PluginHookType:        NewPluginHook,
+	BranchHookType:        NewBranchHook,
+	TagHookType:           NewTagHook,
+}
+
+func NewHook(h ActionHook) (Hook, error) {
+	newHook, ok := hookTypes[h.Type]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", h.Type, ErrUnknownHookType)
+	}
+	return newHook(h)
+}
+
+type ChangeType string
+
+const (
+	ChangeTypeAdded   ChangeType = "added"
+	ChangeTypeRemoved ChangeType = "removed"
+	ChangeTypeChanged ChangeType = "changed"
+)
+
+// Change is a path changed by the operation of an event
+type Change struct {
+	Path string
+	Type ChangeType
+}
+
+// Version selects the version of the repository objects are read from
+type Version int
+
+const (
+	// VersionSource is the event source reference, holding the changes of the operation
+	VersionSource Version = iota
+	// VersionDestination is the branch without the changes of the operation: the last commit
+	// of a committed branch, or the destination branch of a merge
+	VersionDestination
+	// VersionActions is the reference the actions of the event were loaded from
+	VersionActions
+)
+
+// Env gives hooks access to the changes of the operation they check
+type Env interface {
+	// Changes returns the paths the operation changes, ordered by path
+	Changes(ctx context.Context) ([]Change, error)
+	// ReadObject reads the object at path on the event source reference
+	ReadObject(ctx context.Context, path string) (io.ReadCloser, error)
+	// OpenObject opens the object at path on version for reads at arbitrary offsets, failing
+	// with db.ErrNotFound when it doesn't exist
+	OpenObject(ctx context.Context, version Version, path string) (preview.Source, error)
+	// ListObjects returns up to limit paths of the objects directly under prefix on version,
+	// ordered by path
+	ListObjects(ctx context.Context, version Version, prefix string, limit int) ([]string, error)
+	// Plugin returns the runner of the plugin configured as name
+	Plugin(ctx context.Context, name string) (PluginRunner, error)
+	// ContentScanner returns the endpoint of the content scanner configured as name, failing
+	// with ErrUnknownScanner when there is none
+	ContentScanner(name string) (string, error)
+
+	// The writes below are authorized by the permissions of the event committer, failing
+	// with ErrNotAuthorized when it lacks them.
+
+	// BranchExists returns true if branch exists in the event repository
+	BranchExists(ctx context.Context, branch string) (bool, error)
+	// CreateBranch creates branch from the source branch
+	CreateBranch(ctx context.Context, branch, source string) error
+	// RecreateBranch replaces branch with a new branch from the source branch, atomically
+	RecreateBranch(ctx context.Context, branch, source string) error
+	// Merge merges the source branch into the destination branch, by the event committer.
+	// Merging no differences succeeds.
+	Merge(ctx context.Context, source, destination, message string) error
+	// CreateTag creates tag on the commit of reference
+	CreateTag(ctx context.Context, tag, reference string) error
+}
+
+func stringProperty(h ActionHook, name string) (string, error) {
+	v, ok := h.Properties[name]
+	if !ok {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s should be a string", ErrInvalidProperty, name)
+	}
+	return s, nil
+}
+
+func stringsProperty(h ActionHook, name string) ([]string, error) {
+	v, ok := h.Properties[name]
+	if !ok {
+		return nil, nil
+	}
+	values, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s should be a list of strings", ErrInvalidProperty, name)
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s should be a list of strings", ErrInvalidProperty, name)
+		}
+		result[i] = s
+	}
+	return result, nil
+}
@@ -0,0 +1,289 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/treeverse/lakefs/logging"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	LuaHookType = "lua"
+
+	luaTimeout = 30 * time.Second
+	// luaMaxReadBytes limits the size of objects scripts read into memory
+	luaMaxReadBytes = 10 * 1024 * 1024
+	// luaMaxStringBytes limits the size of a string built by a single library call
+	luaMaxStringBytes = 10 * 1024 * 1024
+	// luaMaxMemoryBytes limits the growth of the heap while a script runs
+	luaMaxMemoryBytes = 256 * 1024 * 1024
+	// luaMemoryCheckInterval is how often the heap is sampled while a script runs
+	luaMemoryCheckInterval = 10 * time.Millisecond
+	// luaCallStackSize and luaRegistryMaxSize bound the call depth and the value stack
+	luaCallStackSize   = 200
+	luaRegistrySize    = 256 * 20
+	luaRegistryMaxSize = 1024 * 80
+)
+
+var (
+	ErrObjectTooLarge    = errors.New("object too large")
+	ErrLuaMemoryExceeded = errors.New("lua script exceeded its memory limit")
+)
+
+// luaFormatWidth matches the width and precision of a string.format directive
+var luaFormatWidth = regexp.MustCompile(`%[-+ #0]*(\d*)(?:\.(\d+))?`)
+
+// LuaHook runs a Lua script, failing when the script raises an error.  The script runs in a
+// sandbox without access to the file system or the operating system, and is stopped when it
+// runs out of time or memory.  It sees the event in
+// the 'action' table, and reads the changes of the operation through the 'lakefs' table:
+//
+//	lakefs.changes() returns an array of {path=..., type="added"|"removed"|"changed"}
+//	lakefs.read(path) returns the content of the object at path on the event source reference
+//
+// A script_path is read from the reference the action was loaded from.
+type LuaHook struct {
+	script     string
+	scriptPath string
+}
+
+func NewLuaHook(h ActionHook) (Hook, error) {
+	script, err := stringProperty(h, "script")
+	if err != nil {
+		return nil, err
+	}
+	scriptPath, err := stringProperty(h, "script_path")
+	if err != nil {
+		return nil, err
+	}
+	if (script == "") == (scriptPath == "") {
+		return nil, fmt.Errorf("%w: exactly one of script or script_path is required", ErrInvalidProperty)
+	}
+	if script != "" {
+		// fail on syntax errors when the action is loaded
+		L := newLuaState()
+		defer L.Close()
+		if _, err := L.LoadString(script); err != nil {
+			return nil, fmt.Errorf("%w: script: %s", ErrInvalidProperty, err)
+		}
+	}
+	return &LuaHook{script: script, scriptPath: scriptPath}, nil
+}
+
+func (h *LuaHook) Run(ctx context.Context, event *Event, env Env) error {
+	script := h.script
+	if h.scriptPath != "" {
+		data, err := readScript(ctx, env, h.scriptPath)
+		if err != nil {
+			return fmt.Errorf("read script %s: %w", h.scriptPath, err)
+		}
+		script = string(data)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, luaTimeout)
+	defer cancel()
+	L := newLuaState()
+	defer L.Close()
+	var memoryExceeded int32
+	go watchLuaMemory(ctx, cancel, &memoryExceeded)
+	L.SetContext(ctx)
+	openLuaLibs(L)
+	L.SetGlobal("print", L.NewFunction(func(L *lua.LState) int {
+		logging.FromContext(ctx).
+			WithFields(logging.Fields{"repository": event.Repository, "branch": event.Branch}).
+			Info("lua hook: " + L.ToString(1))
+		return 0
+	}))
+	L.SetGlobal("action", luaEventTable(L, event))
+	L.SetGlobal("lakefs", luaLakeFSTable(ctx, L, env))
+
+	err := L.DoString(script)
+	if atomic.LoadInt32(&memoryExceeded) != 0 {
+		return ErrLuaMemoryExceeded
+	}
+	var apiErr *lua.ApiError
+	if errors.As(err, &apiErr) && apiErr.Type == lua.ApiErrorRun {
+		return errors.New(apiErr.Object.String())
+	}
+	return err
+}
+
+func newLuaState() *lua.LState {
+	return lua.NewState(lua.Options{
+		SkipOpenLibs:        true,
+		CallStackSize:       luaCallStackSize,
+		RegistrySize:        luaRegistrySize,
+		RegistryMaxSize:     luaRegistryMaxSize,
+		MinimizeStackMemory: true,
+	})
+}
+
+// watchLuaMemory samples the heap until ctx is done, and cancels the script when the heap grew
+// by more than luaMaxMemoryBytes since the script started.  Go does not account allocations
+// per goroutine, so the heap of the whole process is sampled.  Scripts check their context
+// before every instruction, so a script is stopped soon after it crosses the limit.
+func watchLuaMemory(ctx context.Context, cancel context.CancelFunc, exceeded *int32) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	limit := stats.HeapAlloc + luaMaxMemoryBytes
+	ticker := time.NewTicker(luaMemoryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runtime.ReadMemStats(&stats)
+			if stats.HeapAlloc > limit {
+				atomic.StoreInt32(exceeded, 1)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// openLuaLibs opens the libraries safe to run on the server
+func openLuaLibs(L *lua.LState) {
+	for _, lib := range []struct {
+		name string
+		fn   lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.fn))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	for _, name := range []string{"dofile", "loadfile", "load", "loadstring", "module", "require"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+	// functions building strings much larger than their arguments check the size first, the
+	// string table is also the index of string values
+	str := L.GetGlobal(lua.StringLibName).(*lua.LTable)
+	str.RawSetString("rep", L.NewFunction(luaStringRep))
+	str.RawSetString("format", L.NewFunction(luaStringFormat(str.RawGetString("format"))))
+}
+
+func luaStringRep(L *lua.LState) int {
+	s := L.CheckString(1)
+	n := L.CheckInt(2)
+	if n <= 0 || s == "" {
+		L.Push(lua.LString(""))
+		return 1
+	}
+	if n > luaMaxStringBytes/len(s) {
+		L.RaiseError("string.rep: result exceeds %d bytes", luaMaxStringBytes)
+		return 0
+	}
+	L.Push(lua.LString(strings.Repeat(s, n)))
+	return 1
+}
+
+// luaStringFormat wraps the string.format function format, failing on field widths and
+// precisions that exceed luaMaxStringBytes
+func luaStringFormat(format lua.LValue) lua.LGFunction {
+	return func(L *lua.LState) int {
+		for _, m := range luaFormatWidth.FindAllStringSubmatch(L.CheckString(1), -1) {
+			for _, size := range m[1:] {
+				if n, err := strconv.Atoi(size); size != "" && (err != nil || n > luaMaxStringBytes) {
+					L.RaiseError("string.format: field exceeds %d bytes", luaMaxStringBytes)
+					return 0
+				}
+			}
+		}
+		top := L.GetTop()
+		L.Insert(format, 1)
+		L.Call(top, lua.MultRet)
+		return L.GetTop()
+	}
+}
+
+func luaEventTable(L *lua.LState, event *Event) *lua.LTable {
+	t := L.NewTable()
+	t.RawSetString("event_type", lua.LString(event.Type))
+	t.RawSetString("repository", lua.LString(event.Repository))
+	t.RawSetString("branch", lua.LString(event.Branch))
+	t.RawSetString("source_ref", lua.LString(event.SourceRef))
+	t.RawSetString("commit_message", lua.LString(event.CommitMessage))
+	t.RawSetString("committer", lua.LString(event.Committer))
+	metadata := L.NewTable()
+	for k, v := range event.Metadata {
+		metadata.RawSetString(k, lua.LString(v))
+	}
+	t.RawSetString("metadata", metadata)
+	return t
+}
+
+func luaLakeFSTable(ctx context.Context, L *lua.LState, env Env) *lua.LTable {
+	t := L.NewTable()
+	t.RawSetString("changes", L.NewFunction(func(L *lua.LState) int {
+		changes, err := env.Changes(ctx)
+		if err != nil {
+			L.RaiseError("changes: %s", err)
+			return 0
+		}
+		result := L.CreateTable(len(changes), 0)
+		for _, change := range changes {
+			c := L.CreateTable(0, 2)
+			c.RawSetString("path", lua.LString(change.Path))
+			c.RawSetString("type", lua.LString(change.Type))
+			result.Append(c)
+		}
+		L.Push(result)
+		return 1
+	}))
+	t.RawSetString("read", L.NewFunction(func(L *lua.LState) int {
+		path := L.CheckString(1)
+		data, err := readAll(ctx, env, path)
+		if err != nil {
+			L.RaiseError("read %s: %s", path, err)
+			return 0
+		}
+		L.Push(lua.LString(data))
+		return 1
+	}))
+	return t
+}
+
+// readScript reads the script at path from the reference the action was loaded from, so the
+// changes checked by a hook cannot replace its script
+func readScript(ctx context.Context, env Env, path string) ([]byte, error) {
+	source, err := env.OpenObject(ctx, VersionActions, path)
+	if err != nil {
+		return nil, err
+	}
+	if source.Size() > luaMaxReadBytes {
+		return nil, ErrObjectTooLarge
+	}
+	return ioutil.ReadAll(io.NewSectionReader(source, 0, source.Size()))
+}
+
+func readAll(ctx context.Context, env Env, path string) ([]byte, error) {
+	reader, err := env.ReadObject(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := ioutil.ReadAll(io.LimitReader(reader, luaMaxReadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > luaMaxReadBytes {
+		return nil, ErrObjectTooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,221 @@
+package hooks
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/hooks/plugin"
+	"github.com/treeverse/lakefs/preview"
+)
+
+type fakeEnv struct {
+	changes []Change
+	objects map[string]string
+	// destination holds the objects of VersionDestination
+	destination map[string]string
+	plugins     map[string]PluginRunner
+	scanners    map[string]string
+	// branches holds the existing branches, and ops the branch and tag operations performed
+	branches map[string]bool
+	ops      []string
+}
+
+func (e *fakeEnv) versionObjects(version Version) map[string]string {
+	if version == VersionDestination {
+		return e.destination
+	}
+	return e.objects
+}
+
+func (e *fakeEnv) Changes(_ context.Context) ([]Change, error) {
+	return e.changes, nil
+}
+
+func (e *fakeEnv) ReadObject(_ context.Context, path string) (io.ReadCloser, error) {
+	data, ok := e.objects[path]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return ioutil.NopCloser(strings.NewReader(data)), nil
+}
+
+func (e *fakeEnv) OpenObject(_ context.Context, version Version, path string) (preview.Source, error) {
+	data, ok := e.versionObjects(version)[path]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return strings.NewReader(data), nil
+}
+
+func (e *fakeEnv) ListObjects(_ context.Context, version Version, prefix string, limit int) ([]string, error) {
+	var paths []string
+	for p := range e.versionObjects(version) {
+		if strings.HasPrefix(p, prefix) && !strings.Contains(p[len(prefix):], "/") {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	if len(paths) > limit {
+		paths = paths[:limit]
+	}
+	return paths, nil
+}
+
+func (e *fakeEnv) Plugin(_ context.Context, name string) (PluginRunner, error) {
+	runner, ok := e.plugins[name]
+	if !ok {
+		return nil, plugin.ErrUnknownPlugin
+	}
+	return runner, nil
+}
+
+func (e *fakeEnv) ContentScanner(name string) (string, error) {
+	endpoint, ok := e.scanners[name]
+	if !ok {
+		return "", ErrUnknownScanner
+	}
+	return endpoint, nil
+}
+
+func (e *fakeEnv) BranchExists(_ context.Context, branch string) (bool, error) {
+	return e.branches[branch], nil
+}
+
+func (e *fakeEnv) CreateBranch(_ context.Context, branch, source string) error {
+	if e.branches == nil {
+		e.branches = make(map[string]bool)
+	}
+	e.branches[branch] = true
+	e.ops = append(e.ops, "create "+branch+" from "+source)
+	return nil
+}
+
+func (e *fakeEnv) RecreateBranch(_ context.Context, branch, source string) error {
+	e.ops = append(e.ops, "recreate "+branch+" from "+source)
+	return nil
+}
+
+func (e *fakeEnv) Merge(_ context.Context, source, destination, _ string) error {
+	e.ops = append(e.ops, "merge "+source+" into "+destination)
+	return nil
+}
+
+func (e *fakeEnv) CreateTag(_ context.Context, tag, reference string) error {
+	e.ops = append(e.ops, "tag "+reference+" as "+tag)
+	return nil
+}
+
+func TestLuaHook_Run(t *testing.T) {
+	env := &fakeEnv{
+		changes: []Change{
+			{Path: "tables/a/part-0.parquet", Type: ChangeTypeAdded},
+			{Path: "tables/b/_SUCCESS", Type: ChangeTypeRemoved},
+		},
+		objects: map[string]string{
+			"tables/a/part-0.parquet": "PAR1",
+			"scripts/check.lua":       "if action.committer ~= 'alice' then error('only alice commits') end",
+		},
+	}
+	event := &Event{
+		Type:          EventTypePreCommit,
+		Repository:    "repo",
+		Branch:        "main",
+		SourceRef:     "main",
+		CommitMessage: "add table a",
+		Committer:     "alice",
+		Metadata:      map[string]string{"ticket": "DATA-1"},
+	}
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name:       "event",
+			properties: map[string]interface{}{"script": `assert(action.event_type == "pre-commit" and action.repository == "repo" and action.branch == "main" and action.metadata.ticket == "DATA-1")`},
+		},
+		{
+			name: "changes",
+			properties: map[string]interface{}{"script": `
+for _, c in ipairs(lakefs.changes()) do
+  if c.type == "removed" and string.find(c.path, "_SUCCESS") then
+    error("removing " .. c.path)
+  end
+end`},
+			wantErr: "removing tables/b/_SUCCESS",
+		},
+		{
+			name:       "read",
+			properties: map[string]interface{}{"script": `assert(lakefs.read("tables/a/part-0.parquet") == "PAR1")`},
+		},
+		{
+			name:       "read missing",
+			properties: map[string]interface{}{"script": `lakefs.read("missing")`},
+			wantErr:    "read missing",
+		},
+		{
+			name:       "script path",
+			properties: map[string]interface{}{"script_path": "scripts/check.lua"},
+		},
+		{
+			name:       "no os access",
+			properties: map[string]interface{}{"script": `os.exit(1)`},
+			wantErr:    "attempt to index a non-table object",
+		},
+		{
+			name:       "no file access",
+			properties: map[string]interface{}{"script": `dofile("/etc/passwd")`},
+			wantErr:    "attempt to call a non-function object",
+		},
+		{
+			name:       "string rep",
+			properties: map[string]interface{}{"script": `assert(string.rep("ab", 3) == "ababab" and ("x"):rep(0) == "")`},
+		},
+		{
+			name:       "string rep too large",
+			properties: map[string]interface{}{"script": `local s = ("x"):rep(1e9)`},
+			wantErr:    "string.rep: result exceeds",
+		},
+		{
+			name:       "string format",
+			properties: map[string]interface{}{"script": `assert(string.format("%05d %s %%", 7, "a") == "00007 a %")`},
+		},
+		{
+			name:       "string format too wide",
+			properties: map[string]interface{}{"script": `local s = string.format("%999999999d", 1)`},
+			wantErr:    "string.format: field exceeds",
+		},
+		{
+			name:       "call stack",
+			properties: map[string]interface{}{"script": `local function f() return 1 + f() end f()`},
+			wantErr:    "stack overflow",
+		},
+		{
+			name: "memory",
+			properties: map[string]interface{}{"script": `
+local s, t = string.rep("x", 1000000), {}
+for i = 1, 10000 do t[i] = s .. i end`},
+			wantErr: ErrLuaMemoryExceeded.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := NewLuaHook(ActionHook{ID: "check", Type: LuaHookType, Properties: tt.properties})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = hook.Run(context.Background(), event, env)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Run() err=%v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Run() err=%v, expected error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
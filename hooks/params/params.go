@@ -0,0 +1,18 @@
+package params
+
+// Plugin configures a hook plugin: an executable serving hooks over gRPC
+type Plugin struct {
+	// Name identifies the plugin in the 'plugin' property of hooks
+	Name string
+	Path string
+	Args []string
+	// Env holds KEY=value variables added to the environment of the plugin process
+	Env []string
+}
+
+// ContentScanner is a scanning endpoint content scan hooks send objects to
+type ContentScanner struct {
+	// Name identifies the scanner in the 'scanner' property of hooks
+	Name     string
+	Endpoint string
+}
@@ -0,0 +1,141 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/preview"
+)
+
+const (
+	ParquetSchemaHookType = "parquet_schema"
+
+	parquetExtension = ".parquet"
+	// parquetTableListLimit limits the objects listed to find the destination schema of a
+	// table directory with no other parquet files
+	parquetTableListLimit = 100
+)
+
+var ErrIncompatibleSchema = errors.New("incompatible schema")
+
+// ParquetSchemaHook fails operations that change Parquet files under its prefixes into a
+// schema incompatible with the destination branch: one that changes the type of a column,
+// or drops a required column.  A changed file is compared with its destination version, an
+// added file with the first Parquet file of its directory on the destination.
+type ParquetSchemaHook struct {
+	prefixes []string
+}
+
+func NewParquetSchemaHook(h ActionHook) (Hook, error) {
+	prefixes, err := stringsProperty(h, "prefixes")
+	if err != nil {
+		return nil, err
+	}
+	return &ParquetSchemaHook{prefixes: prefixes}, nil
+}
+
+func (h *ParquetSchemaHook) Run(ctx context.Context, _ *Event, env Env) error {
+	changes, err := env.Changes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if change.Type == ChangeTypeRemoved || !h.match(change.Path) {
+			continue
+		}
+		destination, err := destinationSchema(ctx, env, change)
+		if err != nil {
+			return err
+		}
+		if destination == nil {
+			// a new table
+			continue
+		}
+		source, err := readSchema(ctx, env, VersionSource, change.Path)
+		if err != nil {
+			return err
+		}
+		if err := checkSchemaCompatible(destination, source); err != nil {
+			return fmt.Errorf("%s: %w", change.Path, err)
+		}
+	}
+	return nil
+}
+
+func (h *ParquetSchemaHook) match(p string) bool {
+	if !strings.HasSuffix(strings.ToLower(p), parquetExtension) {
+		return false
+	}
+	if len(h.prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range h.prefixes {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// destinationSchema returns the schema change is compared with, nil when the destination has
+// no parquet file to compare with
+func destinationSchema(ctx context.Context, env Env, change Change) (*preview.Table, error) {
+	if change.Type == ChangeTypeChanged {
+		return readSchema(ctx, env, VersionDestination, change.Path)
+	}
+	dir := path.Dir(change.Path) + "/"
+	if dir == "./" {
+		dir = ""
+	}
+	paths, err := env.ListObjects(ctx, VersionDestination, dir, parquetTableListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", dir, err)
+	}
+	for _, p := range paths {
+		if strings.HasSuffix(strings.ToLower(p), parquetExtension) {
+			return readSchema(ctx, env, VersionDestination, p)
+		}
+	}
+	return nil, nil
+}
+
+func readSchema(ctx context.Context, env Env, version Version, p string) (*preview.Table, error) {
+	src, err := env.OpenObject(ctx, version, p)
+	if errors.Is(err, db.ErrNotFound) && version == VersionDestination {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", p, err)
+	}
+	table, err := preview.ReadTable(p, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("read schema %s: %w", p, err)
+	}
+	return table, nil
+}
+
+// checkSchemaCompatible returns an error when schema changes a column type of base or drops
+// one of its required columns
+func checkSchemaCompatible(base, schema *preview.Table) error {
+	columns := make(map[string]preview.Column, len(schema.Columns))
+	for _, c := range schema.Columns {
+		columns[c.Name] = c
+	}
+	for _, baseColumn := range base.Columns {
+		column, ok := columns[baseColumn.Name]
+		if !ok {
+			if baseColumn.Required {
+				return fmt.Errorf("%w: drops required column %s", ErrIncompatibleSchema, baseColumn.Name)
+			}
+			continue
+		}
+		if column.Type != baseColumn.Type {
+			return fmt.Errorf("%w: changes column %s type from %s to %s", ErrIncompatibleSchema, baseColumn.Name, baseColumn.Type, column.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+type baseRecord struct {
+	ID    int64   `parquet:"name=id, type=INT64"`
+	Name  string  `parquet:"name=name, type=UTF8"`
+	Score *string `parquet:"name=score, type=UTF8"`
+}
+
+type droppedOptionalRecord struct {
+	ID   int64  `parquet:"name=id, type=INT64"`
+	Name string `parquet:"name=name, type=UTF8"`
+}
+
+type droppedRequiredRecord struct {
+	ID    int64   `parquet:"name=id, type=INT64"`
+	Score *string `parquet:"name=score, type=UTF8"`
+}
+
+type changedTypeRecord struct {
+	ID    int32   `parquet:"name=id, type=INT32"`
+	Name  string  `parquet:"name=name, type=UTF8"`
+	Score *string `parquet:"name=score, type=UTF8"`
+}
+
+func generateParquet(t *testing.T, record interface{}) string {
+	t.Helper()
+	fw, err := buffer.NewBufferFile(nil)
+	if err != nil {
+		t.Fatalf("failed to create parquet buffer: %s", err)
+	}
+	pw, err := writer.NewParquetWriter(fw, record, 1)
+	if err != nil {
+		t.Fatalf("failed to create parquet writer: %s", err)
+	}
+	if err := pw.Write(record); err != nil {
+		t.Fatalf("failed to write parquet record: %s", err)
+	}
+	if err := pw.WriteStop(); err != nil {
+		t.Fatalf("failed to stop parquet writer: %s", err)
+	}
+	return string(fw.(buffer.BufferFile).Bytes())
+}
+
+func TestParquetSchemaHook_Run(t *testing.T) {
+	base := generateParquet(t, &baseRecord{ID: 1, Name: "one"})
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		change     Change
+		source     string
+		wantErr    error
+	}{
+		{
+			name:   "same schema",
+			change: Change{Path: "tables/a/part-0.parquet", Type: ChangeTypeChanged},
+			source: base,
+		},
+		{
+			name:   "drop optional column",
+			change: Change{Path: "tables/a/part-0.parquet", Type: ChangeTypeChanged},
+			source: generateParquet(t, &droppedOptionalRecord{ID: 1, Name: "one"}),
+		},
+		{
+			name:    "drop required column",
+			change:  Change{Path: "tables/a/part-0.parquet", Type: ChangeTypeChanged},
+			source:  generateParquet(t, &droppedRequiredRecord{ID: 1}),
+			wantErr: ErrIncompatibleSchema,
+		},
+		{
+			name:    "change column type",
+			change:  Change{Path: "tables/a/part-0.parquet", Type: ChangeTypeChanged},
+			source:  generateParquet(t, &changedTypeRecord{ID: 1, Name: "one"}),
+			wantErr: ErrIncompatibleSchema,
+		},
+		{
+			name:    "added file compared with table",
+			change:  Change{Path: "tables/a/part-1.parquet", Type: ChangeTypeAdded},
+			source:  generateParquet(t, &changedTypeRecord{ID: 1, Name: "one"}),
+			wantErr: ErrIncompatibleSchema,
+		},
+		{
+			name:   "new table",
+			change: Change{Path: "tables/b/part-0.parquet", Type: ChangeTypeAdded},
+			source: generateParquet(t, &changedTypeRecord{ID: 1, Name: "one"}),
+		},
+		{
+			name:       "outside prefixes",
+			properties: map[string]interface{}{"prefixes": []interface{}{"tables/b/"}},
+			change:     Change{Path: "tables/a/part-0.parquet", Type: ChangeTypeChanged},
+			source:     generateParquet(t, &changedTypeRecord{ID: 1, Name: "one"}),
+		},
+		{
+			name:       "inside prefixes",
+			properties: map[string]interface{}{"prefixes": []interface{}{"tables/a/"}},
+			change:     Change{Path: "tables/a/part-0.parquet", Type: ChangeTypeChanged},
+			source:     generateParquet(t, &changedTypeRecord{ID: 1, Name: "one"}),
+			wantErr:    ErrIncompatibleSchema,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := NewParquetSchemaHook(ActionHook{ID: "schema", Type: ParquetSchemaHookType, Properties: tt.properties})
+			if err != nil {
+				t.Fatal(err)
+			}
+			env := &fakeEnv{
+				changes:     []Change{tt.change},
+				objects:     map[string]string{tt.change.Path: tt.source},
+				destination: map[string]string{"tables/a/part-0.parquet": base},
+			}
+			err = hook.Run(context.Background(), &Event{Type: EventTypePreMerge}, env)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run() err=%v, expected %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewParquetSchemaHook_InvalidPrefixes(t *testing.T) {
+	_, err := NewParquetSchemaHook(ActionHook{ID: "schema", Type: ParquetSchemaHookType, Properties: map[string]interface{}{"prefixes": "tables/"}})
+	if !errors.Is(err, ErrInvalidProperty) {
+		t.Fatalf("NewParquetSchemaHook() err=%v, expected %v", err, ErrInvalidProperty)
+	}
+}
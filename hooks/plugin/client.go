@@ -0,0 +1,106 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+)
+
+var ErrNoResult = errors.New("plugin ended the run without a result")
+
+// Host serves plugins the changes and objects of the event they run on
+type Host interface {
+	// Changes returns the paths the operation changes, ordered by path
+	Changes(ctx context.Context) ([]Change, error)
+	// ReadObject reads the object at path on the event source reference
+	ReadObject(ctx context.Context, path string) (io.ReadCloser, error)
+}
+
+// Client runs hooks on a plugin
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+func NewClient(conn *grpc.ClientConn) *Client {
+	return &Client{conn: conn}
+}
+
+// Run runs a hook on the plugin, returning the error of a failed run
+func (c *Client) Run(ctx context.Context, start *Start, host Host) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.conn.NewStream(ctx, &runStreamDesc, runMethod, grpc.CallContentSubtype(codecName))
+	if err != nil {
+		return fmt.Errorf("start plugin run: %w", err)
+	}
+	if err := stream.SendMsg(&RunRequest{Start: start}); err != nil {
+		return fmt.Errorf("send start: %w", err)
+	}
+	changes, err := host.Changes(ctx)
+	if err != nil {
+		return err
+	}
+	for len(changes) > 0 {
+		n := changesBatchSize
+		if n > len(changes) {
+			n = len(changes)
+		}
+		if err := stream.SendMsg(&RunRequest{Changes: changes[:n]}); err != nil {
+			return fmt.Errorf("send changes: %w", err)
+		}
+		changes = changes[n:]
+	}
+	if err := stream.SendMsg(&RunRequest{ChangesDone: true}); err != nil {
+		return fmt.Errorf("send changes: %w", err)
+	}
+
+	for {
+		var resp RunResponse
+		err := stream.RecvMsg(&resp)
+		if errors.Is(err, io.EOF) {
+			return ErrNoResult
+		}
+		if err != nil {
+			return fmt.Errorf("receive from plugin: %w", err)
+		}
+		switch {
+		case resp.Result != nil:
+			_ = stream.CloseSend()
+			if resp.Result.Error != "" {
+				return errors.New(resp.Result.Error)
+			}
+			return nil
+		case resp.Read != nil:
+			if err := sendObject(ctx, stream, host, resp.Read.Path); err != nil {
+				return fmt.Errorf("send object %s: %w", resp.Read.Path, err)
+			}
+		}
+	}
+}
+
+// sendObject streams the object at path to the plugin, an object that can't be read is sent
+// as a chunk with its error
+func sendObject(ctx context.Context, stream grpc.ClientStream, host Host, path string) error {
+	reader, err := host.ReadObject(ctx, path)
+	if err != nil {
+		return stream.SendMsg(&RunRequest{Object: &ObjectChunk{Path: path, Error: err.Error()}})
+	}
+	defer func() { _ = reader.Close() }()
+	buf := make([]byte, objectChunkSize)
+	for {
+		n, err := io.ReadFull(reader, buf)
+		eof := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !eof {
+			return stream.SendMsg(&RunRequest{Object: &ObjectChunk{Path: path, Error: err.Error()}})
+		}
+		if err := stream.SendMsg(&RunRequest{Object: &ObjectChunk{Path: path, Data: buf[:n], EOF: eof}}); err != nil {
+			return err
+		}
+		if eof {
+			return nil
+		}
+	}
+}
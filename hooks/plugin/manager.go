@@ -0,0 +1,213 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/hooks/params"
+	"github.com/treeverse/lakefs/logging"
+	"google.golang.org/grpc"
+)
+
+const (
+	startTimeout         = 30 * time.Second
+	handshakeFieldsCount = 5
+)
+
+var (
+	ErrUnknownPlugin    = errors.New("unknown plugin")
+	ErrInvalidPlugin    = errors.New("invalid plugin configuration")
+	ErrInvalidHandshake = errors.New("invalid plugin handshake")
+	ErrStartTimeout     = errors.New("timed out waiting for plugin handshake")
+	ErrManagerClosed    = errors.New("plugin manager closed")
+)
+
+// Manager starts the configured plugins when their hooks first run, and restarts plugins
+// that exited
+type Manager struct {
+	plugins map[string]params.Plugin
+	log     logging.Logger
+
+	mu        sync.Mutex
+	processes map[string]*process
+	closed    bool
+}
+
+type process struct {
+	cmd    *exec.Cmd
+	conn   *grpc.ClientConn
+	client *Client
+	exited chan struct{}
+}
+
+func NewManager(plugins []params.Plugin, log logging.Logger) (*Manager, error) {
+	m := &Manager{
+		plugins:   make(map[string]params.Plugin, len(plugins)),
+		log:       log,
+		processes: make(map[string]*process),
+	}
+	for _, p := range plugins {
+		if p.Name == "" || p.Path == "" {
+			return nil, fmt.Errorf("%w: name and path are required", ErrInvalidPlugin)
+		}
+		if _, ok := m.plugins[p.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicate plugin %s", ErrInvalidPlugin, p.Name)
+		}
+		m.plugins[p.Name] = p
+	}
+	return m, nil
+}
+
+// Client returns a client of the plugin name, starting it if it isn't running
+func (m *Manager) Client(ctx context.Context, name string) (*Client, error) {
+	if m == nil {
+		return nil, fmt.Errorf("%s: %w", name, ErrUnknownPlugin)
+	}
+	p, ok := m.plugins[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ErrUnknownPlugin)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+	if proc, ok := m.processes[name]; ok {
+		select {
+		case <-proc.exited:
+			_ = proc.conn.Close()
+			delete(m.processes, name)
+		default:
+			return proc.client, nil
+		}
+	}
+	proc, err := m.start(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("start plugin %s: %w", name, err)
+	}
+	m.processes[name] = proc
+	return proc.client, nil
+}
+
+func (m *Manager) start(ctx context.Context, p params.Plugin) (*process, error) {
+	log := m.log.WithField("plugin", p.Name)
+	//nolint:gosec
+	cmd := exec.Command(p.Path, p.Args...)
+	cmd.Env = append(os.Environ(), p.Env...)
+	cmd.Env = append(cmd.Env,
+		MagicCookieKey+"="+MagicCookieValue,
+		ProtocolVersionsKey+"="+strconv.Itoa(ProtocolVersion))
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	proc := &process{cmd: cmd, exited: make(chan struct{})}
+	stderrDone := make(chan struct{})
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			log.Info(scanner.Text())
+		}
+		close(stderrDone)
+	}()
+
+	handshake := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		if scanner.Scan() {
+			handshake <- scanner.Text()
+		}
+		close(handshake)
+		// drain the output of the plugin so it never blocks writing it
+		for scanner.Scan() {
+			log.Debug(scanner.Text())
+		}
+		<-stderrDone
+		_ = cmd.Wait()
+		log.Info("plugin exited")
+		close(proc.exited)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+	var line string
+	select {
+	case line = <-handshake:
+	case <-ctx.Done():
+		_ = cmd.Process.Kill()
+		return nil, ErrStartTimeout
+	}
+	network, address, err := ParseHandshake(line)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		return nil, err
+	}
+	conn, err := grpc.DialContext(ctx, address,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}))
+	if err != nil {
+		_ = cmd.Process.Kill()
+		return nil, fmt.Errorf("connect %s %s: %w", network, address, err)
+	}
+	proc.conn = conn
+	proc.client = NewClient(conn)
+	log.WithField("address", address).Info("plugin started")
+	return proc, nil
+}
+
+// ParseHandshake parses the handshake line written by a plugin, returning the network and
+// address it serves on
+func ParseHandshake(line string) (string, string, error) {
+	fields := strings.Split(strings.TrimSpace(line), "|")
+	if len(fields) != handshakeFieldsCount {
+		return "", "", fmt.Errorf("%w: '%s'", ErrInvalidHandshake, line)
+	}
+	if fields[0] != strconv.Itoa(CoreProtocolVersion) {
+		return "", "", fmt.Errorf("%w: unsupported core protocol version %s", ErrInvalidHandshake, fields[0])
+	}
+	if fields[1] != strconv.Itoa(ProtocolVersion) {
+		return "", "", fmt.Errorf("%w: unsupported protocol version %s", ErrInvalidHandshake, fields[1])
+	}
+	if fields[2] != "tcp" && fields[2] != "unix" {
+		return "", "", fmt.Errorf("%w: unsupported network %s", ErrInvalidHandshake, fields[2])
+	}
+	if fields[4] != "grpc" {
+		return "", "", fmt.Errorf("%w: unsupported protocol %s", ErrInvalidHandshake, fields[4])
+	}
+	return fields[2], fields[3], nil
+}
+
+// Close stops all plugins
+func (m *Manager) Close() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	for name, proc := range m.processes {
+		_ = proc.conn.Close()
+		_ = proc.cmd.Process.Kill()
+		delete(m.processes, name)
+	}
+}
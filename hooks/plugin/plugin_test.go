@@ -0,0 +1,229 @@
+package plugin_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/hooks/params"
+	"github.com/treeverse/lakefs/hooks/plugin"
+	"github.com/treeverse/lakefs/logging"
+	"google.golang.org/grpc"
+)
+
+type fakeHost struct {
+	changes []plugin.Change
+	objects map[string]string
+}
+
+func (h *fakeHost) Changes(_ context.Context) ([]plugin.Change, error) {
+	return h.changes, nil
+}
+
+func (h *fakeHost) ReadObject(_ context.Context, path string) (io.ReadCloser, error) {
+	data, ok := h.objects[path]
+	if !ok {
+		return nil, fmt.Errorf("%s: not found", path)
+	}
+	return ioutil.NopCloser(strings.NewReader(data)), nil
+}
+
+// readmeHook fails when the objects it reads from the 'required' property are missing or empty
+type readmeHook struct{}
+
+func (readmeHook) Run(_ context.Context, req *plugin.Request) error {
+	if req.Event.Type != "pre-commit" || req.HookID != "readme" {
+		return fmt.Errorf("unexpected run %s of %s", req.HookID, req.Event.Type)
+	}
+	required, _ := req.Properties["required"].([]interface{})
+	for _, r := range required {
+		data, err := req.ReadObject(r.(string))
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("%s is empty", r)
+		}
+	}
+	if len(req.Changes) == 0 {
+		return errors.New("no changes")
+	}
+	return nil
+}
+
+func startPlugin(t *testing.T, hook plugin.Hook) *plugin.Client {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := plugin.NewServer(hook)
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return plugin.NewClient(conn)
+}
+
+func TestClient_Run(t *testing.T) {
+	client := startPlugin(t, readmeHook{})
+	largeChanges := make([]plugin.Change, 2500)
+	for i := range largeChanges {
+		largeChanges[i] = plugin.Change{Path: fmt.Sprintf("data/%05d", i), Type: "added"}
+	}
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		host       *fakeHost
+		wantErr    string
+	}{
+		{
+			name:       "pass",
+			properties: map[string]interface{}{"required": []interface{}{"README.md"}},
+			host: &fakeHost{
+				changes: []plugin.Change{{Path: "README.md", Type: "added"}},
+				objects: map[string]string{"README.md": strings.Repeat("x", 3*1024*1024+5)},
+			},
+		},
+		{
+			name: "many changes",
+			host: &fakeHost{changes: largeChanges},
+		},
+		{
+			name:       "empty object",
+			properties: map[string]interface{}{"required": []interface{}{"README.md"}},
+			host: &fakeHost{
+				changes: []plugin.Change{{Path: "README.md", Type: "added"}},
+				objects: map[string]string{"README.md": ""},
+			},
+			wantErr: "README.md is empty",
+		},
+		{
+			name:       "missing object",
+			properties: map[string]interface{}{"required": []interface{}{"README.md"}},
+			host:       &fakeHost{changes: []plugin.Change{{Path: "data/a", Type: "added"}}},
+			wantErr:    "README.md: not found",
+		},
+		{
+			name:    "no changes",
+			host:    &fakeHost{},
+			wantErr: "no changes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.Run(context.Background(), &plugin.Start{
+				HookID:     "readme",
+				Event:      plugin.Event{Type: "pre-commit", Repository: "repo", Branch: "main", SourceRef: "main"},
+				Properties: tt.properties,
+			}, tt.host)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Run() unexpected error: %s", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("Run() err=%v, expected %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseHandshake(t *testing.T) {
+	tests := []struct {
+		line        string
+		wantNetwork string
+		wantAddress string
+		wantErr     bool
+	}{
+		{line: "1|1|tcp|127.0.0.1:1234|grpc\n", wantNetwork: "tcp", wantAddress: "127.0.0.1:1234"},
+		{line: "1|1|unix|/tmp/plugin.sock|grpc", wantNetwork: "unix", wantAddress: "/tmp/plugin.sock"},
+		{line: "2|1|tcp|127.0.0.1:1234|grpc", wantErr: true},
+		{line: "1|2|tcp|127.0.0.1:1234|grpc", wantErr: true},
+		{line: "1|1|udp|127.0.0.1:1234|grpc", wantErr: true},
+		{line: "1|1|tcp|127.0.0.1:1234|netrpc", wantErr: true},
+		{line: "listening on 1234", wantErr: true},
+		{line: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			network, address, err := plugin.ParseHandshake(tt.line)
+			if tt.wantErr {
+				if !errors.Is(err, plugin.ErrInvalidHandshake) {
+					t.Fatalf("ParseHandshake() err=%v, expected %v", err, plugin.ErrInvalidHandshake)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseHandshake() unexpected error: %s", err)
+			}
+			if network != tt.wantNetwork || address != tt.wantAddress {
+				t.Fatalf("ParseHandshake() got %s %s, expected %s %s", network, address, tt.wantNetwork, tt.wantAddress)
+			}
+		})
+	}
+}
+
+func TestNewManager(t *testing.T) {
+	_, err := plugin.NewManager(nil, nil)
+	if err != nil {
+		t.Fatalf("NewManager() unexpected error: %s", err)
+	}
+	_, err = plugin.NewManager([]params.Plugin{{Name: "a", Path: "/bin/a"}, {Name: "a", Path: "/bin/b"}}, nil)
+	if !errors.Is(err, plugin.ErrInvalidPlugin) {
+		t.Fatalf("NewManager() err=%v, expected %v", err, plugin.ErrInvalidPlugin)
+	}
+}
+
+// TestHelperPlugin serves readmeHook when run as a plugin by TestManager_Client
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("LAKEFS_TEST_HELPER_PLUGIN") != "1" {
+		t.Skip("run as a plugin by TestManager_Client")
+	}
+	if err := plugin.Serve(readmeHook{}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func TestManager_Client(t *testing.T) {
+	m, err := plugin.NewManager([]params.Plugin{{
+		Name: "readme",
+		Path: os.Args[0],
+		Args: []string{"-test.run=TestHelperPlugin"},
+		Env:  []string{"LAKEFS_TEST_HELPER_PLUGIN=1"},
+	}}, logging.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	ctx := context.Background()
+	if _, err := m.Client(ctx, "missing"); !errors.Is(err, plugin.ErrUnknownPlugin) {
+		t.Fatalf("Client() err=%v, expected %v", err, plugin.ErrUnknownPlugin)
+	}
+	client, err := m.Client(ctx, "readme")
+	if err != nil {
+		t.Fatalf("Client() unexpected error: %s", err)
+	}
+	err = client.Run(ctx, &plugin.Start{
+		HookID: "readme",
+		Event:  plugin.Event{Type: "pre-commit"},
+	}, &fakeHost{})
+	if err == nil || err.Error() != "no changes" {
+		t.Fatalf("Run() err=%v, expected no changes", err)
+	}
+	again, err := m.Client(ctx, "readme")
+	if err != nil {
+		t.Fatalf("Client() unexpected error: %s", err)
+	}
+	if again != client {
+		t.Fatal("Client() started the running plugin again")
+	}
+}
@@ -0,0 +1,143 @@
+// Package plugin runs hooks out of process.  A hook plugin is an executable, written in any
+// language, serving the HookPlugin gRPC service.  lakeFS starts configured plugins the way
+// hashicorp/go-plugin does:
+//
+//  1. It runs the plugin executable with MagicCookieKey=MagicCookieValue and
+//     ProtocolVersionsKey=ProtocolVersion in its environment.
+//  2. The plugin listens on a local address and writes a single handshake line to its
+//     standard output:
+//     CORE-PROTOCOL-VERSION|APP-PROTOCOL-VERSION|NETWORK|ADDRESS|grpc
+//     for example "1|1|tcp|127.0.0.1:1234|grpc".
+//  3. lakeFS connects to the address, and logs the standard error of the plugin.
+//
+// Messages are encoded as JSON (content subtype "json", content type application/grpc+json).
+// Each hook run is a single bidirectional Run stream: lakeFS sends a RunRequest with Start,
+// RunRequests with batches of Changes and a RunRequest with ChangesDone.  The plugin may then
+// send RunResponses with Read, answered by RunRequests with the ObjectChunks of the object,
+// and ends the run with a RunResponse with its Result.
+package plugin
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+const (
+	CoreProtocolVersion = 1
+	ProtocolVersion     = 1
+
+	// MagicCookieKey and MagicCookieValue are set in the environment of plugins, so
+	// executables not started as a plugin can tell the user
+	MagicCookieKey      = "LAKEFS_HOOK_PLUGIN"
+	MagicCookieValue    = "b7e5e0c6a5c34b3f9d6f0f3e7c1a2d48"
+	ProtocolVersionsKey = "LAKEFS_HOOK_PLUGIN_PROTOCOL_VERSIONS"
+
+	ServiceName = "lakefs.hooks.v1.HookPlugin"
+	runMethod   = "/" + ServiceName + "/Run"
+	codecName   = "json"
+
+	changesBatchSize = 1000
+	objectChunkSize  = 1024 * 1024
+)
+
+// Event describes the repository operation a hook runs on
+type Event struct {
+	Type          string            `json:"event_type"`
+	Repository    string            `json:"repository"`
+	Branch        string            `json:"branch"`
+	SourceRef     string            `json:"source_ref"`
+	ParentRef     string            `json:"parent_ref,omitempty"`
+	CommitMessage string            `json:"commit_message"`
+	Committer     string            `json:"committer"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	// DryRun is set when the hook runs without performing the operation
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// Start starts a hook run
+type Start struct {
+	HookID     string                 `json:"hook_id"`
+	Event      Event                  `json:"event"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Change is a path changed by the operation, Type is one of added, removed or changed
+type Change struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+// ObjectChunk holds the next bytes of an object read by the plugin.  The last chunk has EOF
+// set, or Error when the object couldn't be read.
+type ObjectChunk struct {
+	Path  string `json:"path"`
+	Data  []byte `json:"data,omitempty"`
+	EOF   bool   `json:"eof,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// RunRequest is sent by lakeFS, exactly one of its fields is set
+type RunRequest struct {
+	Start       *Start       `json:"start,omitempty"`
+	Changes     []Change     `json:"changes,omitempty"`
+	ChangesDone bool         `json:"changes_done,omitempty"`
+	Object      *ObjectChunk `json:"object,omitempty"`
+}
+
+// Read requests the content of the object at Path on the event source reference
+type Read struct {
+	Path string `json:"path"`
+}
+
+// Result ends a hook run, an Error fails it
+type Result struct {
+	Error string `json:"error,omitempty"`
+}
+
+// RunResponse is sent by the plugin, exactly one of its fields is set
+type RunResponse struct {
+	Read   *Read   `json:"read,omitempty"`
+	Result *Result `json:"result,omitempty"`
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+//nolint:gochecknoinits
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// runServer handles Run streams of plugins
+type runServer interface {
+	run(stream grpc.ServerStream) error
+}
+
+var runStreamDesc = grpc.StreamDesc{
+	StreamName:    "Run",
+	ServerStreams: true,
+	ClientStreams: true,
+	Handler: func(srv interface{}, stream grpc.ServerStream) error {
+		return srv.(runServer).run(stream)
+	},
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*runServer)(nil),
+	Streams:     []grpc.StreamDesc{runStreamDesc},
+	Metadata:    "lakefs/hooks/plugin",
+}
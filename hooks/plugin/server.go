@@ -0,0 +1,116 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+)
+
+var (
+	ErrNotStartedByLakeFS = errors.New("plugin was not started by lakeFS")
+	ErrUnexpectedMessage  = errors.New("unexpected message")
+)
+
+// Hook is implemented by hook plugins written in Go
+type Hook interface {
+	// Run checks the event of req, an error fails the operation
+	Run(ctx context.Context, req *Request) error
+}
+
+// Request is a hook run on a plugin
+type Request struct {
+	HookID     string
+	Event      Event
+	Properties map[string]interface{}
+	Changes    []Change
+	stream     grpc.ServerStream
+}
+
+// ReadObject reads the object at path on the event source reference
+func (r *Request) ReadObject(path string) ([]byte, error) {
+	if err := r.stream.SendMsg(&RunResponse{Read: &Read{Path: path}}); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	for {
+		var req RunRequest
+		if err := r.stream.RecvMsg(&req); err != nil {
+			return nil, err
+		}
+		if req.Object == nil || req.Object.Path != path {
+			return nil, fmt.Errorf("%w: expected object %s", ErrUnexpectedMessage, path)
+		}
+		if req.Object.Error != "" {
+			return nil, errors.New(req.Object.Error)
+		}
+		buf.Write(req.Object.Data)
+		if req.Object.EOF {
+			return buf.Bytes(), nil
+		}
+	}
+}
+
+type server struct {
+	hook Hook
+}
+
+func (s *server) run(stream grpc.ServerStream) error {
+	var start RunRequest
+	if err := stream.RecvMsg(&start); err != nil {
+		return err
+	}
+	if start.Start == nil {
+		return fmt.Errorf("%w: expected start", ErrUnexpectedMessage)
+	}
+	req := &Request{
+		HookID:     start.Start.HookID,
+		Event:      start.Start.Event,
+		Properties: start.Start.Properties,
+		stream:     stream,
+	}
+	for {
+		var msg RunRequest
+		if err := stream.RecvMsg(&msg); err != nil {
+			return err
+		}
+		if msg.ChangesDone {
+			break
+		}
+		req.Changes = append(req.Changes, msg.Changes...)
+	}
+	result := &Result{}
+	if err := s.hook.Run(stream.Context(), req); err != nil {
+		result.Error = err.Error()
+	}
+	return stream.SendMsg(&RunResponse{Result: result})
+}
+
+// NewServer returns a gRPC server running hook
+func NewServer(hook Hook, opts ...grpc.ServerOption) *grpc.Server {
+	srv := grpc.NewServer(opts...)
+	srv.RegisterService(&serviceDesc, &server{hook: hook})
+	return srv
+}
+
+// Serve serves hook as a plugin of the lakeFS process that started it, until the process
+// closes the connection or is killed
+func Serve(hook Hook) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return ErrNotStartedByLakeFS
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	srv := NewServer(hook)
+	_, err = fmt.Printf("%d|%d|%s|%s|grpc\n", CoreProtocolVersion, ProtocolVersion, listener.Addr().Network(), listener.Addr().String())
+	if err != nil {
+		return err
+	}
+	return srv.Serve(listener)
+}
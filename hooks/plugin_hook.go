@@ -0,0 +1,112 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/treeverse/lakefs/hooks/plugin"
+)
+
+const PluginHookType = "plugin"
+
+// PluginRunner runs hooks on a plugin
+type PluginRunner interface {
+	Run(ctx context.Context, start *plugin.Start, host plugin.Host) error
+}
+
+// PluginHook runs a hook on a plugin configured in lakeFS: an executable serving the plugin
+// gRPC protocol, streamed the changes of the operation and the objects it reads
+type PluginHook struct {
+	id         string
+	plugin     string
+	properties map[string]interface{}
+}
+
+func NewPluginHook(h ActionHook) (Hook, error) {
+	name, err := stringProperty(h, "plugin")
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%w: plugin is required", ErrInvalidProperty)
+	}
+	var properties map[string]interface{}
+	if v, ok := h.Properties["properties"]; ok {
+		properties, ok = jsonValue(v).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: properties should be a map", ErrInvalidProperty)
+		}
+	}
+	return &PluginHook{id: h.ID, plugin: name, properties: properties}, nil
+}
+
+func (h *PluginHook) Run(ctx context.Context, event *Event, env Env) error {
+	runner, err := env.Plugin(ctx, h.plugin)
+	if err != nil {
+		return err
+	}
+	return runner.Run(ctx, &plugin.Start{
+		HookID: h.id,
+		Event: plugin.Event{
+			Type:          string(event.Type),
+			Repository:    event.Repository,
+			Branch:        event.Branch,
+			SourceRef:     event.SourceRef,
+			ParentRef:     event.ParentRef,
+			CommitMessage: event.CommitMessage,
+			Committer:     event.Committer,
+			Metadata:      event.Metadata,
+			DryRun:        event.DryRun,
+		},
+		Properties: h.properties,
+	}, &pluginHost{env: env})
+}
+
+// jsonValue converts the maps decoded from YAML, keyed by interface{}, to maps keyed by
+// string that encode to JSON
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			m[fmt.Sprint(k)] = jsonValue(value)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			m[k] = jsonValue(value)
+		}
+		return m
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			values[i] = jsonValue(value)
+		}
+		return values
+	default:
+		return v
+	}
+}
+
+// pluginHost serves plugins the changes and objects of env
+type pluginHost struct {
+	env Env
+}
+
+func (p *pluginHost) Changes(ctx context.Context) ([]plugin.Change, error) {
+	changes, err := p.env.Changes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]plugin.Change, len(changes))
+	for i, change := range changes {
+		result[i] = plugin.Change{Path: change.Path, Type: string(change.Type)}
+	}
+	return result, nil
+}
+
+func (p *pluginHost) ReadObject(ctx context.Context, path string) (io.ReadCloser, error) {
+	return p.env.ReadObject(ctx, path)
+}
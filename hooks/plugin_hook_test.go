@@ -0,0 +1,100 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/hooks/plugin"
+)
+
+type fakePluginRunner struct {
+	start   *plugin.Start
+	changes []plugin.Change
+	err     error
+}
+
+func (r *fakePluginRunner) Run(ctx context.Context, start *plugin.Start, host plugin.Host) error {
+	r.start = start
+	changes, err := host.Changes(ctx)
+	if err != nil {
+		return err
+	}
+	r.changes = changes
+	return r.err
+}
+
+func TestPluginHook_Run(t *testing.T) {
+	runner := &fakePluginRunner{}
+	env := &fakeEnv{
+		changes: []Change{{Path: "tables/a/part-0.parquet", Type: ChangeTypeAdded}},
+		plugins: map[string]PluginRunner{"scanner": runner},
+	}
+	hook, err := NewPluginHook(ActionHook{
+		ID:   "scan",
+		Type: PluginHookType,
+		Properties: map[string]interface{}{
+			"plugin": "scanner",
+			"properties": map[interface{}]interface{}{
+				"rules": []interface{}{map[interface{}]interface{}{"name": "pii"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &Event{Type: EventTypePreCommit, Repository: "repo", Branch: "main", SourceRef: "main", Committer: "alice"}
+	if err := hook.Run(context.Background(), event, env); err != nil {
+		t.Fatalf("Run() unexpected error: %s", err)
+	}
+	expectedStart := &plugin.Start{
+		HookID: "scan",
+		Event: plugin.Event{
+			Type:       "pre-commit",
+			Repository: "repo",
+			Branch:     "main",
+			SourceRef:  "main",
+			Committer:  "alice",
+		},
+		Properties: map[string]interface{}{
+			"rules": []interface{}{map[string]interface{}{"name": "pii"}},
+		},
+	}
+	if diff := deep.Equal(runner.start, expectedStart); diff != nil {
+		t.Fatalf("Run() started %s", diff)
+	}
+	if diff := deep.Equal(runner.changes, []plugin.Change{{Path: "tables/a/part-0.parquet", Type: "added"}}); diff != nil {
+		t.Fatalf("Run() sent changes %s", diff)
+	}
+
+	errRejected := errors.New("rejected")
+	runner.err = errRejected
+	if err := hook.Run(context.Background(), event, env); !errors.Is(err, errRejected) {
+		t.Fatalf("Run() err=%v, expected %v", err, errRejected)
+	}
+}
+
+func TestPluginHook_UnknownPlugin(t *testing.T) {
+	hook, err := NewPluginHook(ActionHook{ID: "scan", Type: PluginHookType, Properties: map[string]interface{}{"plugin": "missing"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = hook.Run(context.Background(), &Event{Type: EventTypePreCommit}, &fakeEnv{})
+	if !errors.Is(err, plugin.ErrUnknownPlugin) {
+		t.Fatalf("Run() err=%v, expected %v", err, plugin.ErrUnknownPlugin)
+	}
+}
+
+func TestNewPluginHook_InvalidProperties(t *testing.T) {
+	for _, properties := range []map[string]interface{}{
+		nil,
+		{"plugin": 1},
+		{"plugin": "scanner", "properties": "rules"},
+	} {
+		_, err := NewPluginHook(ActionHook{ID: "scan", Type: PluginHookType, Properties: properties})
+		if !errors.Is(err, ErrInvalidProperty) {
+			t.Fatalf("NewPluginHook(%v) err=%v, expected %v", properties, err, ErrInvalidProperty)
+		}
+	}
+}
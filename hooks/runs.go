@@ -0,0 +1,183 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/treeverse/lakefs/db"
+)
+
+const (
+	MaxListRunsAmount     = 1000
+	DefaultListRunsAmount = 100
+)
+
+var (
+	ErrInvalidAfter  = errors.New("invalid after run ID")
+	ErrInvalidStatus = errors.New("invalid run status")
+)
+
+type RunStatus string
+
+const (
+	RunStatusCompleted RunStatus = "completed"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+// Run is a single invocation of a hook
+type Run struct {
+	ID         int64     `db:"id"`
+	Repository string    `db:"repository"`
+	EventID    string    `db:"event_id"`
+	EventType  EventType `db:"event_type"`
+	Branch     string    `db:"branch"`
+	// CommitID is the commit created by the operation of the event, empty until it is created
+	CommitID string `db:"commit_id"`
+	Action   string `db:"action"`
+	HookID   string `db:"hook_id"`
+	HookType string `db:"hook_type"`
+	// Event is the input of the hook
+	Event  Event     `db:"event"`
+	Status RunStatus `db:"status"`
+	// Output holds the error of a failed run
+	Output     string    `db:"output"`
+	Attempt    int       `db:"attempt"`
+	StartTime  time.Time `db:"start_time"`
+	DurationMs int64     `db:"duration_ms"`
+}
+
+// ListRunsParams filters and paginates the runs listed, empty fields match all runs
+type ListRunsParams struct {
+	CommitID string
+	Branch   string
+	Status   RunStatus
+	After    string
+	Amount   int
+}
+
+type RunStore interface {
+	// Record adds run, setting its ID
+	Record(ctx context.Context, run *Run) error
+	// SetCommitID sets the commit created by the operation of the event eventID on its runs
+	SetCommitID(ctx context.Context, repository, eventID, commitID string) error
+	// Get returns the run id of repository
+	Get(ctx context.Context, repository string, id int64) (*Run, error)
+	// List returns the repository runs matching params, newest first
+	List(ctx context.Context, repository string, params ListRunsParams) ([]*Run, bool, error)
+}
+
+type DBRunStore struct {
+	db db.Database
+}
+
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+func NewDBRunStore(db db.Database) *DBRunStore {
+	return &DBRunStore{db: db}
+}
+
+func (s *DBRunStore) Record(ctx context.Context, run *Run) error {
+	res, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var id int64
+		err := tx.GetPrimitive(&id, `INSERT INTO hook_runs (repository_id, event_id, event_type, branch, commit_id,
+				action, hook_id, hook_type, event, status, output, attempt, start_time, duration_ms)
+			SELECT id, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14 FROM catalog_repositories WHERE name = $1
+			RETURNING id`,
+			run.Repository, run.EventID, run.EventType, run.Branch, run.CommitID,
+			run.Action, run.HookID, run.HookType, run.Event, run.Status, run.Output, run.Attempt, run.StartTime, run.DurationMs)
+		return id, err
+	}, db.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	run.ID = res.(int64)
+	return nil
+}
+
+func (s *DBRunStore) SetCommitID(ctx context.Context, repository, eventID, commitID string) error {
+	_, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		return tx.Exec(`UPDATE hook_runs SET commit_id = $3
+			WHERE event_id = $2 AND repository_id = (SELECT id FROM catalog_repositories WHERE name = $1)`,
+			repository, eventID, commitID)
+	}, db.WithContext(ctx))
+	return err
+}
+
+func selectRuns() sq.SelectBuilder {
+	return psql.Select("h.id", "r.name AS repository", "h.event_id", "h.event_type", "h.branch", "h.commit_id",
+		"h.action", "h.hook_id", "h.hook_type", "h.event", "h.status", "h.output", "h.attempt", "h.start_time", "h.duration_ms").
+		From("hook_runs h").
+		Join("catalog_repositories r ON r.id = h.repository_id")
+}
+
+func (s *DBRunStore) Get(ctx context.Context, repository string, id int64) (*Run, error) {
+	query, args, err := selectRuns().Where(sq.Eq{"r.name": repository, "h.id": id}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql: %w", err)
+	}
+	res, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var run Run
+		if err := tx.Get(&run, query, args...); err != nil {
+			return nil, err
+		}
+		return &run, nil
+	}, db.WithContext(ctx), db.ReadOnly())
+	if err != nil {
+		return nil, err
+	}
+	return res.(*Run), nil
+}
+
+func (s *DBRunStore) List(ctx context.Context, repository string, params ListRunsParams) ([]*Run, bool, error) {
+	amount := params.Amount
+	if amount <= 0 || amount > MaxListRunsAmount {
+		amount = DefaultListRunsAmount
+	}
+	q := selectRuns().
+		Where(sq.Eq{"r.name": repository}).
+		OrderBy("h.id DESC").
+		Limit(uint64(amount) + 1)
+	if params.After != "" {
+		afterID, err := strconv.ParseInt(params.After, 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", params.After, ErrInvalidAfter)
+		}
+		q = q.Where(sq.Lt{"h.id": afterID})
+	}
+	if params.CommitID != "" {
+		q = q.Where(sq.Eq{"h.commit_id": params.CommitID})
+	}
+	if params.Branch != "" {
+		q = q.Where(sq.Eq{"h.branch": params.Branch})
+	}
+	if params.Status != "" {
+		if params.Status != RunStatusCompleted && params.Status != RunStatusFailed {
+			return nil, false, fmt.Errorf("%s: %w", params.Status, ErrInvalidStatus)
+		}
+		q = q.Where(sq.Eq{"h.status": params.Status})
+	}
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("build sql: %w", err)
+	}
+	res, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var runs []*Run
+		if err := tx.Select(&runs, query, args...); err != nil {
+			return nil, err
+		}
+		return runs, nil
+	}, db.WithContext(ctx), db.ReadOnly())
+	if err != nil {
+		return nil, false, err
+	}
+	runs := res.([]*Run)
+	hasMore := len(runs) > amount
+	if hasMore {
+		runs = runs[:amount]
+	}
+	return runs, hasMore, nil
+}
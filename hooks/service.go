@@ -0,0 +1,521 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/treeverse/lakefs/auth"
+	"github.com/treeverse/lakefs/block"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/catalog/mvcc"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/hooks/params"
+	"github.com/treeverse/lakefs/hooks/plugin"
+	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/permissions"
+	"github.com/treeverse/lakefs/preview"
+)
+
+const listBatchSize = 1000
+
+var (
+	// ErrHookFailed is returned when a hook fails the operation of an event
+	ErrHookFailed = errors.New("hook failed")
+	// ErrRetryNotAllowed is returned when retrying a run other than a failed run of a post event
+	ErrRetryNotAllowed = errors.New("only failed runs of post events can be retried")
+	ErrHookNotFound    = errors.New("hook not found")
+	// ErrNotAuthorized is returned when a hook writes what the event committer may not write
+	ErrNotAuthorized = errors.New("committer not authorized")
+)
+
+// Authorizer checks the permissions of the users that hooks write on behalf of
+type Authorizer interface {
+	Authorize(req *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error)
+}
+
+// Service runs the hooks of the actions configured in a repository.  Actions are YAML files
+// under ActionsPrefix, loaded from the reference returned by ActionsRef for an event.
+type Service struct {
+	cataloger catalog.Cataloger
+	adapter   block.Adapter
+	runs      RunStore
+	plugins   *plugin.Manager
+	scanners  map[string]string
+	auth      Authorizer
+}
+
+// NewService returns a Service recording the runs of hooks on runs, unless it is nil, running
+// plugin hooks on the plugins of plugins, and content scan hooks on scanners.  Hooks write
+// branches and tags only when authorizer allows the event committer to.
+func NewService(cataloger catalog.Cataloger, adapter block.Adapter, runs RunStore, plugins *plugin.Manager, scanners []params.ContentScanner, authorizer Authorizer) *Service {
+	endpoints := make(map[string]string, len(scanners))
+	for _, scanner := range scanners {
+		endpoints[scanner.Name] = scanner.Endpoint
+	}
+	return &Service{
+		cataloger: cataloger,
+		adapter:   adapter,
+		runs:      runs,
+		plugins:   plugins,
+		scanners:  endpoints,
+		auth:      authorizer,
+	}
+}
+
+// Run runs the hooks of all actions matching event, ordered by action path, and returns an
+// error wrapping ErrHookFailed on the first hook that fails.  Pre events stop on the first
+// failure, all hooks of post events run.  A nil Service runs no hooks.
+func (s *Service) Run(ctx context.Context, event *Event) error {
+	if s == nil {
+		return nil
+	}
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	actions, err := s.LoadActions(ctx, event.Repository, ActionsRef(event))
+	if err != nil {
+		return err
+	}
+	env := &catalogEnv{service: s, event: event}
+	var runErr error
+	for _, action := range actions {
+		if !action.Match(event) {
+			continue
+		}
+		for _, h := range action.Hooks {
+			run := s.runHook(ctx, event, env, action, h, 1)
+			if run.Status == RunStatusCompleted || runErr != nil {
+				continue
+			}
+			runErr = fmt.Errorf("%w: action %s hook %s: %s", ErrHookFailed, action.Name, h.ID, run.Output)
+			if !event.IsPost() {
+				return runErr
+			}
+		}
+	}
+	return runErr
+}
+
+// DryRun runs the hooks of all actions matching event, without recording their runs or
+// letting them write to the repository, and returns the runs of all hooks.  A nil Service runs
+// no hooks.
+func (s *Service) DryRun(ctx context.Context, event *Event) ([]*Run, error) {
+	runs := make([]*Run, 0)
+	if s == nil {
+		return runs, nil
+	}
+	event.DryRun = true
+	actions, err := s.LoadActions(ctx, event.Repository, ActionsRef(event))
+	if err != nil {
+		return nil, err
+	}
+	env := &dryRunEnv{catalogEnv: &catalogEnv{service: s, event: event}}
+	for _, action := range actions {
+		if !action.Match(event) {
+			continue
+		}
+		for _, h := range action.Hooks {
+			runs = append(runs, execHook(ctx, event, env, action, h, 1))
+		}
+	}
+	return runs, nil
+}
+
+// runHook runs hook h of action and records its run
+func (s *Service) runHook(ctx context.Context, event *Event, env Env, action *Action, h ActionHook, attempt int) *Run {
+	run := execHook(ctx, event, env, action, h, attempt)
+	if s.runs != nil {
+		if err := s.runs.Record(ctx, run); err != nil {
+			logging.FromContext(ctx).
+				WithFields(logging.Fields{"action": action.Name, "hook": h.ID, "event_type": event.Type}).
+				WithError(err).
+				Warn("failed to record hook run")
+		}
+	}
+	return run
+}
+
+// execHook runs hook h of action
+func execHook(ctx context.Context, event *Event, env Env, action *Action, h ActionHook, attempt int) *Run {
+	logging.FromContext(ctx).
+		WithFields(logging.Fields{"action": action.Name, "hook": h.ID, "event_type": event.Type, "attempt": attempt}).
+		Debug("run hook")
+	run := &Run{
+		Repository: event.Repository,
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Branch:     event.Branch,
+		Action:     action.Name,
+		HookID:     h.ID,
+		HookType:   h.Type,
+		Event:      *event,
+		Status:     RunStatusCompleted,
+		Attempt:    attempt,
+		StartTime:  time.Now(),
+	}
+	if event.IsPost() {
+		run.CommitID = event.SourceRef
+	}
+	hook, err := NewHook(h)
+	if err == nil {
+		err = hook.Run(ctx, event, env)
+	}
+	run.DurationMs = time.Since(run.StartTime).Milliseconds()
+	if err != nil {
+		run.Status = RunStatusFailed
+		run.Output = err.Error()
+	}
+	return run
+}
+
+// SetCommitID associates the runs of a pre event with the commit created by its operation
+func (s *Service) SetCommitID(ctx context.Context, event *Event, commitID string) error {
+	if s == nil || s.runs == nil || event.ID == "" {
+		return nil
+	}
+	return s.runs.SetCommitID(ctx, event.Repository, event.ID, commitID)
+}
+
+// GetRun returns the hook run id of repository
+func (s *Service) GetRun(ctx context.Context, repository string, id int64) (*Run, error) {
+	if s == nil || s.runs == nil {
+		return nil, db.ErrNotFound
+	}
+	return s.runs.Get(ctx, repository, id)
+}
+
+// ListRuns returns the hook runs of repository matching params, newest first
+func (s *Service) ListRuns(ctx context.Context, repository string, params ListRunsParams) ([]*Run, bool, error) {
+	if s == nil || s.runs == nil {
+		return nil, false, nil
+	}
+	return s.runs.List(ctx, repository, params)
+}
+
+// Retry runs the hook of failed post event run id again with the same event, and returns the
+// new run.  The action is read from the commit of the event, as it was when the run failed.
+func (s *Service) Retry(ctx context.Context, repository string, id int64) (*Run, error) {
+	prev, err := s.GetRun(ctx, repository, id)
+	if err != nil {
+		return nil, err
+	}
+	event := prev.Event
+	if prev.Status != RunStatusFailed || !event.IsPost() {
+		return nil, ErrRetryNotAllowed
+	}
+	actions, err := s.LoadActions(ctx, event.Repository, ActionsRef(&event))
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		if action.Name != prev.Action {
+			continue
+		}
+		for _, h := range action.Hooks {
+			if h.ID == prev.HookID {
+				env := &catalogEnv{service: s, event: &event}
+				return s.runHook(ctx, &event, env, action, h, prev.Attempt+1), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("action %s hook %s: %w", prev.Action, prev.HookID, ErrHookNotFound)
+}
+
+// ActionsRef returns the reference the actions of event are loaded from: the source reference,
+// except for pre-merge events.  Pre-merge actions are loaded from the committed destination
+// branch, so a merge cannot replace the checks it is subject to.
+func ActionsRef(event *Event) string {
+	if event.Type == EventTypePreMerge {
+		return event.Branch + mvcc.CommittedSuffix
+	}
+	return event.SourceRef
+}
+
+// LoadActions returns the actions configured on repository reference, ordered by path.  An
+// invalid action fails with ErrInvalidAction, so no operation runs without its checks.
+func (s *Service) LoadActions(ctx context.Context, repository, reference string) ([]*Action, error) {
+	var actions []*Action
+	after := ""
+	for {
+		entries, hasMore, err := s.cataloger.ListEntries(ctx, repository, reference, ActionsPrefix, after, "", listBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("list actions: %w", err)
+		}
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Path, ".yaml") && !strings.HasSuffix(entry.Path, ".yml") {
+				continue
+			}
+			data, err := s.readEntry(ctx, repository, entry)
+			if err != nil {
+				return nil, fmt.Errorf("read action %s: %w", entry.Path, err)
+			}
+			action, err := ParseAction(data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", entry.Path, err)
+			}
+			actions = append(actions, action)
+		}
+		if !hasMore || len(entries) == 0 {
+			break
+		}
+		after = entries[len(entries)-1].Path
+	}
+	return actions, nil
+}
+
+func (s *Service) readEntry(ctx context.Context, repository string, entry *catalog.Entry) ([]byte, error) {
+	reader, err := s.getEntryReader(ctx, repository, entry)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return ioutil.ReadAll(reader)
+}
+
+func (s *Service) getEntryReader(ctx context.Context, repository string, entry *catalog.Entry) (io.ReadCloser, error) {
+	pointer, err := s.entryPointer(ctx, repository, entry)
+	if err != nil {
+		return nil, err
+	}
+	return s.adapter.WithContext(ctx).Get(pointer, entry.Size)
+}
+
+func (s *Service) entryPointer(ctx context.Context, repository string, entry *catalog.Entry) (block.ObjectPointer, error) {
+	repo, err := s.cataloger.GetRepository(ctx, repository)
+	if err != nil {
+		return block.ObjectPointer{}, err
+	}
+	return block.ObjectPointer{
+		StorageNamespace: repo.StorageNamespace,
+		Identifier:       entry.PhysicalAddress,
+	}, nil
+}
+
+// catalogEnv reads the changes of an event from the catalog
+type catalogEnv struct {
+	service *Service
+	event   *Event
+	changes []Change
+}
+
+func (e *catalogEnv) Changes(ctx context.Context) ([]Change, error) {
+	if e.changes != nil {
+		return e.changes, nil
+	}
+	changes := make([]Change, 0)
+	after := ""
+	for {
+		var differences catalog.Differences
+		var hasMore bool
+		var err error
+		cataloger := e.service.cataloger
+		if e.event.Type == EventTypePreMerge {
+			differences, hasMore, err = cataloger.Diff(ctx, e.event.Repository, e.event.SourceRef, e.event.Branch, catalog.DiffParams{
+				Limit: listBatchSize,
+				After: after,
+			})
+		} else {
+			differences, hasMore, err = cataloger.DiffUncommitted(ctx, e.event.Repository, e.event.Branch, "", listBatchSize, after)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("diff: %w", err)
+		}
+		for _, d := range differences {
+			changes = append(changes, Change{Path: d.Path, Type: changeType(d.Type)})
+		}
+		if !hasMore || len(differences) == 0 {
+			break
+		}
+		after = differences[len(differences)-1].Path
+	}
+	e.changes = changes
+	return changes, nil
+}
+
+func changeType(t catalog.DifferenceType) ChangeType {
+	switch t {
+	case catalog.DifferenceTypeAdded:
+		return ChangeTypeAdded
+	case catalog.DifferenceTypeRemoved:
+		return ChangeTypeRemoved
+	default:
+		return ChangeTypeChanged
+	}
+}
+
+func (e *catalogEnv) ReadObject(ctx context.Context, path string) (io.ReadCloser, error) {
+	entry, err := e.service.cataloger.GetEntry(ctx, e.event.Repository, e.event.SourceRef, path, catalog.GetEntryParams{})
+	if err != nil {
+		return nil, err
+	}
+	return e.service.getEntryReader(ctx, e.event.Repository, entry)
+}
+
+func (e *catalogEnv) ref(version Version) string {
+	switch {
+	case version == VersionSource:
+		return e.event.SourceRef
+	case version == VersionActions:
+		return ActionsRef(e.event)
+	case e.event.IsPost():
+		return e.event.ParentRef
+	case e.event.Type == EventTypePreMerge:
+		return e.event.Branch
+	default:
+		return e.event.Branch + mvcc.CommittedSuffix
+	}
+}
+
+func (e *catalogEnv) OpenObject(ctx context.Context, version Version, path string) (preview.Source, error) {
+	entry, err := e.service.cataloger.GetEntry(ctx, e.event.Repository, e.ref(version), path, catalog.GetEntryParams{})
+	if err != nil {
+		return nil, err
+	}
+	pointer, err := e.service.entryPointer(ctx, e.event.Repository, entry)
+	if err != nil {
+		return nil, err
+	}
+	return block.NewRangeReaderAt(e.service.adapter.WithContext(ctx), pointer, entry.Size), nil
+}
+
+func (e *catalogEnv) ListObjects(ctx context.Context, version Version, prefix string, limit int) ([]string, error) {
+	entries, _, err := e.service.cataloger.ListEntries(ctx, e.event.Repository, e.ref(version), prefix, "", catalog.DefaultPathDelimiter, limit)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.CommonLevel {
+			continue
+		}
+		paths = append(paths, entry.Path)
+	}
+	return paths, nil
+}
+
+func (e *catalogEnv) Plugin(ctx context.Context, name string) (PluginRunner, error) {
+	client, err := e.service.plugins.Client(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (e *catalogEnv) ContentScanner(name string) (string, error) {
+	endpoint, ok := e.service.scanners[name]
+	if !ok {
+		return "", fmt.Errorf("%s: %w", name, ErrUnknownScanner)
+	}
+	return endpoint, nil
+}
+
+func (e *catalogEnv) BranchExists(ctx context.Context, branch string) (bool, error) {
+	return e.service.cataloger.BranchExists(ctx, e.event.Repository, branch)
+}
+
+// authorize returns an error unless the event committer has all of perms
+func (e *catalogEnv) authorize(perms ...permissions.Permission) error {
+	if e.service.auth == nil {
+		return fmt.Errorf("%w: no authorizer", ErrNotAuthorized)
+	}
+	resp, err := e.service.auth.Authorize(&auth.AuthorizationRequest{
+		Username:            e.event.Committer,
+		RequiredPermissions: perms,
+	})
+	if err != nil {
+		return fmt.Errorf("authorize %s: %w", e.event.Committer, err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%w: %s: %s", ErrNotAuthorized, e.event.Committer, resp.Error)
+	}
+	if !resp.Allowed {
+		return fmt.Errorf("%w: %s", ErrNotAuthorized, e.event.Committer)
+	}
+	return nil
+}
+
+func (e *catalogEnv) CreateBranch(ctx context.Context, branch, source string) error {
+	err := e.authorize(permissions.Permission{
+		Action:   permissions.CreateBranchAction,
+		Resource: permissions.BranchArn(e.event.Repository, branch),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = e.service.cataloger.CreateBranch(ctx, e.event.Repository, branch, source)
+	return err
+}
+
+func (e *catalogEnv) RecreateBranch(ctx context.Context, branch, source string) error {
+	err := e.authorize(permissions.Permission{
+		Action:   permissions.DeleteBranchAction,
+		Resource: permissions.BranchArn(e.event.Repository, branch),
+	}, permissions.Permission{
+		Action:   permissions.CreateBranchAction,
+		Resource: permissions.BranchArn(e.event.Repository, branch),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = e.service.cataloger.RecreateBranch(ctx, e.event.Repository, branch, source)
+	return err
+}
+
+func (e *catalogEnv) Merge(ctx context.Context, source, destination, message string) error {
+	err := e.authorize(permissions.Permission{
+		Action:   permissions.CreateCommitAction,
+		Resource: permissions.BranchArn(e.event.Repository, destination),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = e.service.cataloger.Merge(ctx, e.event.Repository, source, destination, e.event.Committer, message, nil, catalog.MergeParams{})
+	if errors.Is(err, catalog.ErrNoDifferenceWasFound) {
+		return nil
+	}
+	return err
+}
+
+// CreateTag is authorized like creating a branch, tags share the namespace of branches
+func (e *catalogEnv) CreateTag(ctx context.Context, tag, reference string) error {
+	err := e.authorize(permissions.Permission{
+		Action:   permissions.CreateBranchAction,
+		Resource: permissions.BranchArn(e.event.Repository, tag),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = e.service.cataloger.CreateTag(ctx, e.event.Repository, tag, reference)
+	return err
+}
+
+// dryRunEnv is a catalogEnv that doesn't write to the repository
+type dryRunEnv struct {
+	*catalogEnv
+}
+
+func (e *dryRunEnv) CreateBranch(ctx context.Context, branch, source string) error {
+	logging.FromContext(ctx).WithFields(logging.Fields{"branch": branch, "source": source}).Debug("dry run: create branch")
+	return nil
+}
+
+func (e *dryRunEnv) RecreateBranch(ctx context.Context, branch, source string) error {
+	logging.FromContext(ctx).WithFields(logging.Fields{"branch": branch, "source": source}).Debug("dry run: recreate branch")
+	return nil
+}
+
+func (e *dryRunEnv) Merge(ctx context.Context, source, destination, _ string) error {
+	logging.FromContext(ctx).WithFields(logging.Fields{"source": source, "destination": destination}).Debug("dry run: merge")
+	return nil
+}
+
+func (e *dryRunEnv) CreateTag(ctx context.Context, tag, reference string) error {
+	logging.FromContext(ctx).WithFields(logging.Fields{"tag": tag, "reference": reference}).Debug("dry run: create tag")
+	return nil
+}
@@ -0,0 +1,34 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"text/template"
+)
+
+const TagHookType = "tag"
+
+// TagHook tags the commit created by a commit or a merge with a name rendered from a
+// template, like a release tag of every merge into main
+type TagHook struct {
+	tag *template.Template
+}
+
+func NewTagHook(h ActionHook) (Hook, error) {
+	tmpl, err := nameTemplateProperty(h, "tag")
+	if err != nil {
+		return nil, err
+	}
+	return &TagHook{tag: tmpl}, nil
+}
+
+func (h *TagHook) Run(ctx context.Context, event *Event, env Env) error {
+	if !event.IsPost() {
+		return ErrPostEventOnly
+	}
+	tag, err := executeNameTemplate(h.tag, event)
+	if err != nil {
+		return fmt.Errorf("tag name: %w", err)
+	}
+	return env.CreateTag(ctx, tag, event.SourceRef)
+}
@@ -0,0 +1,79 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestTagHook_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		event      *Event
+		wantOps    []string
+		wantErr    error
+	}{
+		{
+			name:       "tag merge commit",
+			properties: map[string]interface{}{"tag": "{{.Branch}}-{{.CommitID}}"},
+			event:      &Event{Type: EventTypePostMerge, Repository: "repo", Branch: "main", SourceRef: "~abc"},
+			wantOps:    []string{"tag ~abc as main-~abc"},
+		},
+		{
+			name:       "tag commit",
+			properties: map[string]interface{}{"tag": "{{.Repository}}-release"},
+			event:      &Event{Type: EventTypePostCommit, Repository: "repo", Branch: "main", SourceRef: "~abd"},
+			wantOps:    []string{"tag ~abd as repo-release"},
+		},
+		{
+			name:       "pre event",
+			properties: map[string]interface{}{"tag": "release"},
+			event:      &Event{Type: EventTypePreMerge, Repository: "repo", Branch: "main", SourceRef: "feature"},
+			wantErr:    ErrPostEventOnly,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := NewTagHook(ActionHook{ID: "release_tag", Type: TagHookType, Properties: tt.properties})
+			if err != nil {
+				t.Fatal(err)
+			}
+			env := &fakeEnv{}
+			err = hook.Run(context.Background(), tt.event, env)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run() err=%v, expected %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(env.ops, tt.wantOps); diff != nil {
+				t.Fatalf("Run() operations %s", diff)
+			}
+		})
+	}
+}
+
+func TestTagHook_RunMissingTemplateKey(t *testing.T) {
+	hook, err := NewTagHook(ActionHook{ID: "release_tag", Type: TagHookType, Properties: map[string]interface{}{"tag": "{{.Version}}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &fakeEnv{}
+	err = hook.Run(context.Background(), &Event{Type: EventTypePostMerge, Repository: "repo", Branch: "main", SourceRef: "~abc"}, env)
+	if err == nil || env.ops != nil {
+		t.Fatalf("Run() err=%v, operations %v, expected an error and no operations", err, env.ops)
+	}
+}
+
+func TestNewTagHook_InvalidProperties(t *testing.T) {
+	for _, properties := range []map[string]interface{}{
+		nil,
+		{"tag": "v-{{.Date"},
+		{"tag": 3},
+	} {
+		_, err := NewTagHook(ActionHook{ID: "release_tag", Type: TagHookType, Properties: properties})
+		if !errors.Is(err, ErrInvalidProperty) {
+			t.Fatalf("NewTagHook(%v) err=%v, expected %v", properties, err, ErrInvalidProperty)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package httputil
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -17,3 +18,22 @@ func HeaderTimestamp(ts time.Time) string {
 func ETag(cksum string) string {
 	return fmt.Sprintf("\"%s\"", cksum)
 }
+
+// ETagMatch returns true if the If-None-Match header value ifNoneMatch matches etag.  Entity
+// tags are compared weakly, as RFC 7232 requires for If-None-Match.
+func ETagMatch(ifNoneMatch string, etag string) bool {
+	ifNoneMatch = strings.TrimSpace(ifNoneMatch)
+	if ifNoneMatch == "" {
+		return false
+	}
+	if ifNoneMatch == "*" {
+		return true
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package httputil
+
+import (
+	"testing"
+)
+
+func TestETagMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		etag        string
+		want        bool
+	}{
+		{name: "empty", ifNoneMatch: "", etag: `"abc"`, want: false},
+		{name: "any", ifNoneMatch: "*", etag: `"abc"`, want: true},
+		{name: "match", ifNoneMatch: `"abc"`, etag: `"abc"`, want: true},
+		{name: "no match", ifNoneMatch: `"abd"`, etag: `"abc"`, want: false},
+		{name: "list", ifNoneMatch: `"xyz", "abc"`, etag: `"abc"`, want: true},
+		{name: "weak", ifNoneMatch: `W/"abc"`, etag: `"abc"`, want: true},
+		{name: "unquoted", ifNoneMatch: `abc`, etag: `"abc"`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ETagMatch(tt.ifNoneMatch, tt.etag); got != tt.want {
+				t.Errorf("ETagMatch() '%s' with '%s' got = %t, want = %t", tt.ifNoneMatch, tt.etag, got, tt.want)
+			}
+		})
+	}
+}
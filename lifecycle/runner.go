@@ -0,0 +1,81 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/logging"
+)
+
+// Runner periodically expires the entries of repositories by their lifecycle rules.  Several
+// lakeFS instances may run it: expiration locks the lifecycle branch, so each expired entry is
+// removed once.
+type Runner struct {
+	cataloger catalog.Cataloger
+	interval  time.Duration
+	dryRun    bool
+	log       logging.Logger
+}
+
+func NewRunner(cataloger catalog.Cataloger, interval time.Duration, dryRun bool, log logging.Logger) *Runner {
+	return &Runner{
+		cataloger: cataloger,
+		interval:  interval,
+		dryRun:    dryRun,
+		log:       log,
+	}
+}
+
+// Run expires entries every interval until ctx is done
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.RunOnce(ctx); err != nil {
+				r.log.WithError(err).Error("lifecycle expiration failed")
+			}
+		}
+	}
+}
+
+// RunOnce expires the entries of every repository with lifecycle rules.  Failing to expire a
+// repository does not stop expiring other repositories.
+func (r *Runner) RunOnce(ctx context.Context) error {
+	lifecycles, err := r.cataloger.ListRepositoryLifecycles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, lifecycle := range lifecycles {
+		log := r.log.WithFields(logging.Fields{
+			"repository": lifecycle.Repository,
+			"branch":     lifecycle.Branch,
+			"dry_run":    r.dryRun,
+		})
+		report, err := r.cataloger.ExpireLifecycle(ctx, lifecycle.Repository, r.dryRun)
+		if err != nil {
+			log.WithError(err).Warn("lifecycle expiration of repository failed")
+			continue
+		}
+		if len(report.Expired) == 0 && len(report.Skipped) == 0 {
+			continue
+		}
+		for _, expiration := range report.Expired {
+			log.WithFields(logging.Fields{
+				"path":          expiration.Path,
+				"creation_date": expiration.CreationDate,
+				"rule_prefix":   expiration.Rule.Prefix,
+			}).Debug("entry expired")
+		}
+		log.WithFields(logging.Fields{
+			"reference": report.Reference,
+			"expired":   len(report.Expired),
+			"skipped":   len(report.Skipped),
+		}).Info("lifecycle expiration done")
+	}
+	return nil
+}
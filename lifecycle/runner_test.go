@@ -0,0 +1,56 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/catalog"
+	"github.com/treeverse/lakefs/logging"
+)
+
+var errExpire = errors.New("expire failed")
+
+// expireCataloger records the repositories it expires, failing those in fail
+type expireCataloger struct {
+	catalog.Cataloger
+	lifecycles []*catalog.RepositoryLifecycle
+	fail       map[string]bool
+	expired    []string
+	dryRuns    []bool
+}
+
+func (c *expireCataloger) ListRepositoryLifecycles(_ context.Context) ([]*catalog.RepositoryLifecycle, error) {
+	return c.lifecycles, nil
+}
+
+func (c *expireCataloger) ExpireLifecycle(_ context.Context, repository string, dryRun bool) (*catalog.LifecycleReport, error) {
+	c.dryRuns = append(c.dryRuns, dryRun)
+	if c.fail[repository] {
+		return nil, errExpire
+	}
+	c.expired = append(c.expired, repository)
+	return &catalog.LifecycleReport{Repository: repository}, nil
+}
+
+func TestRunner_RunOnce(t *testing.T) {
+	c := &expireCataloger{
+		lifecycles: []*catalog.RepositoryLifecycle{
+			{Repository: "repo1", Branch: "master"},
+			{Repository: "repo2", Branch: "master"},
+			{Repository: "repo3", Branch: "main"},
+		},
+		fail: map[string]bool{"repo2": true},
+	}
+	r := NewRunner(c, 0, true, logging.Default())
+	if err := r.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %s", err)
+	}
+	if diff := deep.Equal(c.expired, []string{"repo1", "repo3"}); diff != nil {
+		t.Error("expired repositories", diff)
+	}
+	if diff := deep.Equal(c.dryRuns, []bool{true, true, true}); diff != nil {
+		t.Error("dry runs", diff)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ory/dockertest/v3"
+	"github.com/treeverse/lakefs/activity"
 	"github.com/treeverse/lakefs/api"
 	"github.com/treeverse/lakefs/auth"
 	"github.com/treeverse/lakefs/auth/crypt"
@@ -19,7 +20,9 @@ import (
 	"github.com/treeverse/lakefs/db"
 	dbparams "github.com/treeverse/lakefs/db/params"
 	"github.com/treeverse/lakefs/dedup"
+	"github.com/treeverse/lakefs/hooks"
 	"github.com/treeverse/lakefs/logging"
+	"github.com/treeverse/lakefs/notifications"
 	"github.com/treeverse/lakefs/retention"
 	"github.com/treeverse/lakefs/stats"
 	"github.com/treeverse/lakefs/testutil"
@@ -77,7 +80,14 @@ func TestLocalLoad(t *testing.T) {
 		retentionService,
 		migrator,
 		nil,
+		nil,
 		dedupCleaner,
+		activity.NewDBService(conn),
+		nil,
+		notifications.NewDBSubscriptionService(conn, authService),
+		hooks.NewService(cataloger, blockAdapter, hooks.NewDBRunStore(conn), nil, nil, authService),
+		api.S3GatewayParams{},
+		false,
 		logging.Default(),
 	)
 
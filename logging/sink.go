@@ -0,0 +1,228 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+	FormatText   = "text"
+	// FormatHuman is an alias of FormatText
+	FormatHuman = "human"
+
+	SinkStdout = "stdout"
+	SinkStderr = "stderr"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+)
+
+var (
+	ErrInvalidLevel    = errors.New("invalid logging level")
+	ErrInvalidFormat   = errors.New("invalid logging format")
+	ErrInvalidSinkType = errors.New("invalid logging sink type")
+)
+
+// SinkParams configures a destination of log entries
+type SinkParams struct {
+	// Type is one of stdout, stderr, file or syslog
+	Type string
+	// Level is the least severe level written to the sink, empty writes all the entries
+	// allowed by the logger level
+	Level string
+	// Format is one of json, logfmt or text (human)
+	Format string
+
+	// Path is the file of a file sink
+	Path string
+	// MaxSizeMB rotates the file of a file sink once it grows beyond this size, 0 never
+	// rotates it
+	MaxSizeMB int `mapstructure:"max_size_mb"`
+	// MaxBackups is how many rotated files of a file sink to keep
+	MaxBackups int `mapstructure:"max_backups"`
+
+	// Network and Address of the syslog server of a syslog sink, empty for the local server
+	Network string
+	Address string
+	// Tag of syslog messages
+	Tag string
+
+	// CallerPrettyfier formats the function and file of the code logging an entry
+	CallerPrettyfier func(*runtime.Frame) (function string, file string)
+}
+
+// sinkWriter writes a formatted entry of level
+type sinkWriter interface {
+	WriteEntry(level logrus.Level, p []byte) error
+	Close() error
+}
+
+// Sink writes log entries at or above its level to an output in a format
+type Sink struct {
+	level     logrus.Level
+	formatter logrus.Formatter
+	mu        sync.Mutex
+	w         sinkWriter
+}
+
+// NewSink opens the output of the sink described by params
+func NewSink(params SinkParams) (*Sink, error) {
+	level := logrus.TraceLevel
+	if params.Level != "" {
+		var err error
+		level, err = parseLevel(params.Level)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var w sinkWriter
+	var err error
+	var colors bool
+	switch strings.ToLower(params.Type) {
+	case SinkStdout, "", "-":
+		w = writerSink{os.Stdout}
+		colors = terminal.IsTerminal(int(os.Stdout.Fd()))
+	case SinkStderr:
+		w = writerSink{os.Stderr}
+		colors = terminal.IsTerminal(int(os.Stderr.Fd()))
+	case SinkFile:
+		w, err = newRotatingFile(params.Path, params.MaxSizeMB, params.MaxBackups)
+	case SinkSyslog:
+		w, err = newSyslogSink(params.Network, params.Address, params.Tag)
+	default:
+		err = fmt.Errorf("%w: %s", ErrInvalidSinkType, params.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	formatter, err := newFormatter(params.Format, colors, params.CallerPrettyfier)
+	if err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	return &Sink{level: level, formatter: formatter, w: w}, nil
+}
+
+// Reopen reopens the file of a file sink, so files moved by an external log rotation are
+// replaced.  Other sinks are unaffected.
+func (s *Sink) Reopen() error {
+	f, ok := s.w.(*rotatingFile)
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return f.Reopen()
+}
+
+func (s *Sink) Close() error {
+	return s.w.Close()
+}
+
+func (s *Sink) write(e *logrus.Entry) error {
+	if e.Level > s.level {
+		return nil
+	}
+	p, err := s.formatter.Format(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.WriteEntry(e.Level, p)
+}
+
+// sinksFormatter writes each entry to all the sinks, the logger itself writes nothing
+type sinksFormatter []*Sink
+
+func (sf sinksFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	// formatters append to the buffer of the entry, each sink formats into its own
+	e.Buffer = nil
+	for _, s := range sf {
+		if err := s.write(e); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write to log sink, %v\n", err)
+		}
+	}
+	return nil, nil
+}
+
+// SetSinks writes the entries of the default logger to sinks, replacing its output and the
+// previous sinks.  No sinks discard all entries.
+func SetSinks(sinks ...*Sink) {
+	formatterInitOnce.Do(func() {
+		defaultLogger.SetNoLock()
+	})
+	defaultLogger.Formatter = logrusCallerFormatter{sinksFormatter(sinks)}
+	defaultLogger.Out = ioutil.Discard
+}
+
+// SetLevel changes the least severe level logged by the default logger, sinks still skip
+// entries below their own level
+func SetLevel(level string) error {
+	l, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	defaultLogger.SetLevel(l)
+	return nil
+}
+
+func parseLevel(level string) (logrus.Level, error) {
+	l, err := logrus.ParseLevel(level)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidLevel, level)
+	}
+	return l, nil
+}
+
+// newFormatter returns the formatter of format, colors colors the levels of the text format
+func newFormatter(format string, colors bool, callerPrettyfier func(*runtime.Frame) (string, string)) (logrus.Formatter, error) {
+	switch strings.ToLower(format) {
+	case FormatText, FormatHuman, "":
+		return &logrus.TextFormatter{
+			ForceColors:            colors,
+			FullTimestamp:          true,
+			DisableLevelTruncation: true,
+			PadLevelText:           true,
+			QuoteEmptyFields:       true,
+			CallerPrettyfier:       callerPrettyfier,
+		}, nil
+	case FormatLogfmt:
+		return &logrus.TextFormatter{
+			DisableColors:    true,
+			FullTimestamp:    true,
+			QuoteEmptyFields: true,
+			CallerPrettyfier: callerPrettyfier,
+		}, nil
+	case FormatJSON:
+		return &logrus.JSONFormatter{
+			CallerPrettyfier: callerPrettyfier,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, format)
+	}
+}
+
+// writerSink writes entries to a writer that is never closed, such as stdout
+type writerSink struct {
+	w io.Writer
+}
+
+func (ws writerSink) WriteEntry(_ logrus.Level, p []byte) error {
+	_, err := ws.w.Write(p)
+	return err
+}
+
+func (ws writerSink) Close() error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSink_LevelAndFormat(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lakefs.log")
+	sink, err := NewSink(SinkParams{Type: SinkFile, Path: path, Level: "warn", Format: FormatJSON})
+	if err != nil {
+		t.Fatalf("NewSink() unexpected error: %s", err)
+	}
+	defer func() { _ = sink.Close() }()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.TraceLevel)
+	logger.Out = ioutil.Discard
+	logger.Formatter = sinksFormatter{sink}
+	logger.Info("skipped")
+	logger.WithField("key", "value").Error("written")
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines %q, expected only the error", len(lines), lines)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("log line %q is not JSON: %s", lines[0], err)
+	}
+	if entry["msg"] != "written" || entry["key"] != "value" || entry["level"] != "error" {
+		t.Fatalf("unexpected log entry %v", entry)
+	}
+}
+
+func TestSink_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params SinkParams
+	}{
+		{name: "level", params: SinkParams{Type: SinkStdout, Level: "loud"}},
+		{name: "format", params: SinkParams{Type: SinkStdout, Format: "xml"}},
+		{name: "type", params: SinkParams{Type: "kafka"}},
+		{name: "file without path", params: SinkParams{Type: SinkFile}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSink(tt.params); err == nil {
+				t.Fatalf("NewSink(%+v) expected an error", tt.params)
+			}
+		})
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lakefs.log")
+	r, err := newRotatingFile(path, 1, 2)
+	if err != nil {
+		t.Fatalf("newRotatingFile() unexpected error: %s", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	line := []byte(strings.Repeat("x", bytesInMB/2) + "\n")
+	const writes = 7
+	for i := 0; i < writes; i++ {
+		if err := r.WriteEntry(logrus.InfoLevel, line); err != nil {
+			t.Fatalf("WriteEntry() unexpected error: %s", err)
+		}
+	}
+	// each file holds a single line, as two lines exceed a MB
+	for _, name := range []string{"lakefs.log", "lakefs.log.1", "lakefs.log.2"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("missing log file %s: %s", name, err)
+		}
+		if info.Size() != int64(len(line)) {
+			t.Fatalf("log file %s size %d, expected %d", name, info.Size(), len(line))
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "lakefs.log.3")); !os.IsNotExist(err) {
+		t.Fatalf("expected only 2 backups, stat lakefs.log.3 err=%v", err)
+	}
+}
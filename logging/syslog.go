@@ -0,0 +1,43 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logging
+
+import (
+	"log/syslog"
+
+	"github.com/sirupsen/logrus"
+)
+
+// syslogSink sends entries to syslog with the severity of their level
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogSink(network, address, tag string) (sinkWriter, error) {
+	w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) WriteEntry(level logrus.Level, p []byte) error {
+	msg := string(p)
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return s.w.Crit(msg)
+	case logrus.ErrorLevel:
+		return s.w.Err(msg)
+	case logrus.WarnLevel:
+		return s.w.Warning(msg)
+	case logrus.InfoLevel:
+		return s.w.Info(msg)
+	default:
+		return s.w.Debug(msg)
+	}
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
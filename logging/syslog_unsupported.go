@@ -0,0 +1,14 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package logging
+
+import (
+	"errors"
+)
+
+var ErrSyslogNotSupported = errors.New("syslog is not supported on this platform")
+
+func newSyslogSink(_, _, _ string) (sinkWriter, error) {
+	return nil, ErrSyslogNotSupported
+}
@@ -0,0 +1,190 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/treeverse/lakefs/notifications/params"
+)
+
+const EmailChannelName = "email"
+
+var ErrInvalidEmailConfig = errors.New("invalid email notifications configuration")
+
+// DefaultEmailTimeout bounds sending an email when no timeout is configured
+const DefaultEmailTimeout = 30 * time.Second
+
+type sendMailFunc func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// EmailChannel sends notifications by SMTP
+type EmailChannel struct {
+	addr     string
+	auth     smtp.Auth
+	sender   string
+	timeout  time.Duration
+	sendMail sendMailFunc
+	now      func() time.Time
+}
+
+func NewEmailChannel(p params.Email) (*EmailChannel, error) {
+	if p.SMTPHost == "" || p.Sender == "" {
+		return nil, fmt.Errorf("%w: SMTP host and sender are required", ErrInvalidEmailConfig)
+	}
+	var auth smtp.Auth
+	if p.Username != "" {
+		auth = smtp.PlainAuth("", p.Username, p.Password, p.SMTPHost)
+	}
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultEmailTimeout
+	}
+	return &EmailChannel{
+		addr:     net.JoinHostPort(p.SMTPHost, strconv.Itoa(p.SMTPPort)),
+		auth:     auth,
+		sender:   p.Sender,
+		timeout:  timeout,
+		sendMail: sendMail,
+		now:      time.Now,
+	}, nil
+}
+
+// NewEmailStaticSubscribers returns the configured recipients of all email notifications of the
+// configured event types
+func NewEmailStaticSubscribers(p params.Email) (*StaticSubscribers, error) {
+	eventTypes := make([]EventType, len(p.Events))
+	for i, e := range p.Events {
+		t, err := ParseEventType(e)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidEmailConfig, err)
+		}
+		eventTypes[i] = t
+	}
+	return &StaticSubscribers{
+		Channel:    EmailChannelName,
+		Addresses:  p.Recipients,
+		EventTypes: eventTypes,
+	}, nil
+}
+
+func (c *EmailChannel) Name() string {
+	return EmailChannelName
+}
+
+// Send sends notification as a single email to all recipients, recipients see only their own
+// address as the email is addressed to the sender.  Sending stops when ctx is done or after the
+// timeout of the channel.
+func (c *EmailChannel) Send(ctx context.Context, notification *Notification, recipients []string) error {
+	msg := c.formatMessage(notification)
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.sendMail(ctx, c.addr, c.auth, c.sender, recipients, msg)
+}
+
+// sendMail is smtp.SendMail over a connection that is closed when ctx is done, so a stuck SMTP
+// server cannot block it
+func sendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+			return err
+		}
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-stop:
+		}
+	}()
+
+	host, _, _ := net.SplitHostPort(addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return contextError(ctx, err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	if err := sendMessage(client, host, a, from, to, msg); err != nil {
+		return contextError(ctx, err)
+	}
+	return client.Quit()
+}
+
+// sendMessage sends msg through client as smtp.SendMail does
+func sendMessage(client *smtp.Client, host string, a smtp.Auth, from string, to []string, msg []byte) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if err := client.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := client.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// contextError returns the error of ctx when err was caused by closing the connection on it
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %s", ctxErr, err)
+	}
+	return err
+}
+
+func (c *EmailChannel) formatMessage(notification *Notification) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", c.sender)
+	fmt.Fprintf(&b, "To: %s\r\n", c.sender)
+	fmt.Fprintf(&b, "Subject: [lakeFS] %s\r\n", headerValue(notification.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", c.now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	fmt.Fprintf(&b, "Repository: %s\r\n", notification.Repository)
+	if notification.Branch != "" {
+		fmt.Fprintf(&b, "Branch: %s\r\n", notification.Branch)
+	}
+	fmt.Fprintf(&b, "Event: %s\r\n\r\n", notification.Type)
+	b.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+// headerValue keeps values from breaking out of their header line
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
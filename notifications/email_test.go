@@ -0,0 +1,179 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/treeverse/lakefs/notifications/params"
+)
+
+func TestNewEmailChannel(t *testing.T) {
+	valid := params.Email{
+		SMTPHost:   "smtp.example.com",
+		SMTPPort:   587,
+		Sender:     "lakefs@example.com",
+		Recipients: []string{"data@example.com"},
+		Events:     []string{"export_failed"},
+	}
+	tests := []struct {
+		name    string
+		modify  func(p *params.Email)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(p *params.Email) {}},
+		{name: "no host", modify: func(p *params.Email) { p.SMTPHost = "" }, wantErr: true},
+		{name: "no sender", modify: func(p *params.Email) { p.Sender = "" }, wantErr: true},
+		{name: "no recipients", modify: func(p *params.Email) { p.Recipients = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+			_, err := NewEmailChannel(p)
+			if tt.wantErr != errors.Is(err, ErrInvalidEmailConfig) {
+				t.Fatalf("NewEmailChannel() err=%v, expected error=%t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewEmailStaticSubscribers(t *testing.T) {
+	subscribers, err := NewEmailStaticSubscribers(params.Email{
+		Recipients: []string{"data@example.com"},
+		Events:     []string{"export_failed"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		channel string
+		event   EventType
+		want    int
+	}{
+		{name: "subscribed", channel: EmailChannelName, event: EventExportFailed, want: 1},
+		{name: "other event", channel: EmailChannelName, event: EventProtectedBranchMerge},
+		{name: "other channel", channel: "slack", event: EventExportFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipients, err := subscribers.Recipients(ctx, tt.channel, &Notification{Type: tt.event, Repository: "repo"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(recipients) != tt.want {
+				t.Fatalf("Recipients() %v, expected %d recipients", recipients, tt.want)
+			}
+		})
+	}
+
+	_, err = NewEmailStaticSubscribers(params.Email{Events: []string{"export_started"}})
+	if !errors.Is(err, ErrInvalidEmailConfig) {
+		t.Fatalf("NewEmailStaticSubscribers() unknown event err=%v, expected=%v", err, ErrInvalidEmailConfig)
+	}
+}
+
+func TestEmailChannel_Send(t *testing.T) {
+	channel, err := NewEmailChannel(params.Email{
+		SMTPHost: "smtp.example.com",
+		SMTPPort: 2525,
+		Username: "user",
+		Password: "pass",
+		Sender:   "lakefs@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel.now = func() time.Time { return time.Date(2020, 11, 1, 10, 0, 0, 0, time.UTC) }
+	var sent []string
+	channel.sendMail = func(_ context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		if addr != "smtp.example.com:2525" || a == nil || from != "lakefs@example.com" || len(to) != 2 {
+			t.Errorf("sendMail(%s, %v, %s, %v) unexpected arguments", addr, a, from, to)
+		}
+		sent = append(sent, string(msg))
+		return nil
+	}
+
+	ctx := context.Background()
+	err = channel.Send(ctx, &Notification{
+		Type:       EventExportFailed,
+		Repository: "repo",
+		Branch:     "master",
+		Subject:    "export of repo@master failed\r\nBcc: evil@example.com",
+		Body:       "2 tasks failed",
+	}, []string{"data@example.com", "ops@example.com"})
+	if err != nil {
+		t.Fatalf("Send() err=%v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("Send() sent %d emails, expected 1", len(sent))
+	}
+	expected := "From: lakefs@example.com\r\n" +
+		"To: lakefs@example.com\r\n" +
+		"Subject: [lakeFS] export of repo@master failed  Bcc: evil@example.com\r\n" +
+		"Date: Sun, 01 Nov 2020 10:00:00 +0000\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		"Repository: repo\r\n" +
+		"Branch: master\r\n" +
+		"Event: export_failed\r\n" +
+		"\r\n" +
+		"2 tasks failed\r\n"
+	if sent[0] != expected {
+		t.Fatalf("Send() message:\n%s\nexpected:\n%s", strings.ReplaceAll(sent[0], "\r", ""), strings.ReplaceAll(expected, "\r", ""))
+	}
+}
+
+func TestEmailChannel_SendTimeout(t *testing.T) {
+	// an SMTP server that accepts connections and never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = listener.Close()
+	}()
+	go func() {
+		var conns []net.Conn
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				break
+			}
+			conns = append(conns, conn)
+		}
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	smtpPort, _ := strconv.Atoi(port)
+	channel, err := NewEmailChannel(params.Email{
+		SMTPHost: host,
+		SMTPPort: smtpPort,
+		Sender:   "lakefs@example.com",
+		Timeout:  time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = channel.Send(ctx, &Notification{Type: EventExportFailed, Repository: "repo"}, []string{"data@example.com"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Send() err=%v, expected %s", err, context.DeadlineExceeded)
+	}
+	if took := time.Since(start); took > 10*time.Second {
+		t.Errorf("Send() took %s on a stuck server", took)
+	}
+}
@@ -0,0 +1,146 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/treeverse/lakefs/logging"
+)
+
+const sendTimeout = 30 * time.Second
+
+var ErrUnknownEventType = errors.New("unknown notification event type")
+
+type EventType string
+
+const (
+	EventExportFailed EventType = "export_failed"
+	// EventHookFailed is sent when a hook run on a repository operation fails
+	EventHookFailed           EventType = "hook_failed"
+	EventProtectedBranchMerge EventType = "protected_branch_merge"
+)
+
+var EventTypes = []EventType{EventExportFailed, EventHookFailed, EventProtectedBranchMerge}
+
+func ParseEventType(s string) (EventType, error) {
+	for _, t := range EventTypes {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("%s: %w", s, ErrUnknownEventType)
+}
+
+// Notification describes an event on a repository branch for its subscribers
+type Notification struct {
+	Type       EventType
+	Repository string
+	Branch     string
+	Subject    string
+	Body       string
+}
+
+// Channel delivers notifications to recipient addresses
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, notification *Notification, recipients []string) error
+}
+
+// Subscribers returns the addresses subscribed to receive notification through channel
+type Subscribers interface {
+	Recipients(ctx context.Context, channel string, notification *Notification) ([]string, error)
+}
+
+// StaticSubscribers subscribes fixed addresses of a channel to events on all repositories
+type StaticSubscribers struct {
+	Channel    string
+	Addresses  []string
+	EventTypes []EventType
+}
+
+func (s *StaticSubscribers) Recipients(_ context.Context, channel string, notification *Notification) ([]string, error) {
+	if channel != s.Channel {
+		return nil, nil
+	}
+	for _, t := range s.EventTypes {
+		if t == notification.Type {
+			return s.Addresses, nil
+		}
+	}
+	return nil, nil
+}
+
+// Notifier sends notifications through all channels to their subscribers in the background.
+// A nil Notifier drops all notifications.
+type Notifier struct {
+	channels    []Channel
+	subscribers []Subscribers
+	logger      logging.Logger
+	wg          sync.WaitGroup
+}
+
+func NewNotifier(logger logging.Logger, channels []Channel, subscribers ...Subscribers) *Notifier {
+	return &Notifier{
+		channels:    channels,
+		subscribers: subscribers,
+		logger:      logger,
+	}
+}
+
+// Notify sends notification without waiting for delivery, failures are logged
+func (n *Notifier) Notify(notification *Notification) {
+	if n == nil {
+		return
+	}
+	for _, channel := range n.channels {
+		n.wg.Add(1)
+		go func(channel Channel) {
+			defer n.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			defer cancel()
+			if err := n.send(ctx, channel, notification); err != nil {
+				n.logger.WithError(err).
+					WithFields(logging.Fields{
+						"channel":    channel.Name(),
+						"event_type": notification.Type,
+						"repository": notification.Repository,
+						"branch":     notification.Branch,
+					}).
+					Warn("failed to send notification")
+			}
+		}(channel)
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, channel Channel, notification *Notification) error {
+	seen := make(map[string]struct{})
+	var recipients []string
+	for _, s := range n.subscribers {
+		addresses, err := s.Recipients(ctx, channel.Name(), notification)
+		if err != nil {
+			return fmt.Errorf("get recipients: %w", err)
+		}
+		for _, address := range addresses {
+			if _, ok := seen[address]; ok {
+				continue
+			}
+			seen[address] = struct{}{}
+			recipients = append(recipients, address)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+	return channel.Send(ctx, notification, recipients)
+}
+
+// Close waits for notifications being sent
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	n.wg.Wait()
+}
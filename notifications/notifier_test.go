@@ -0,0 +1,65 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/treeverse/lakefs/logging"
+)
+
+type fakeChannel struct {
+	mu         sync.Mutex
+	name       string
+	sent       []*Notification
+	recipients [][]string
+	err        error
+}
+
+func (c *fakeChannel) Name() string {
+	return c.name
+}
+
+func (c *fakeChannel) Send(_ context.Context, notification *Notification, recipients []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, notification)
+	c.recipients = append(c.recipients, recipients)
+	return c.err
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	ok := &fakeChannel{name: "ok"}
+	failing := &fakeChannel{name: "failing", err: errors.New("unreachable")}
+	unsubscribed := &fakeChannel{name: "unsubscribed"}
+	subscribers := []Subscribers{
+		&StaticSubscribers{Channel: "ok", Addresses: []string{"a", "b"}, EventTypes: []EventType{EventExportFailed}},
+		&StaticSubscribers{Channel: "ok", Addresses: []string{"b", "c"}, EventTypes: EventTypes},
+		&StaticSubscribers{Channel: "failing", Addresses: []string{"a"}, EventTypes: []EventType{EventExportFailed}},
+		&StaticSubscribers{Channel: "unsubscribed", Addresses: []string{"a"}, EventTypes: []EventType{EventHookFailed}},
+	}
+	n := NewNotifier(logging.Default(), []Channel{ok, failing, unsubscribed}, subscribers...)
+	notification := &Notification{Type: EventExportFailed, Repository: "repo", Branch: "master"}
+	n.Notify(notification)
+	n.Close()
+	for _, c := range []*fakeChannel{ok, failing} {
+		if len(c.sent) != 1 || c.sent[0] != notification {
+			t.Fatalf("channel %s sent=%v, expected the notification once", c.name, c.sent)
+		}
+	}
+	if len(unsubscribed.sent) != 0 {
+		t.Fatalf("channel without subscribers sent=%v, expected none", unsubscribed.sent)
+	}
+	recipients := ok.recipients[0]
+	sort.Strings(recipients)
+	if len(recipients) != 3 || recipients[0] != "a" || recipients[1] != "b" || recipients[2] != "c" {
+		t.Fatalf("channel recipients=%v, expected each subscriber once", recipients)
+	}
+
+	// a nil notifier drops notifications
+	var nilNotifier *Notifier
+	nilNotifier.Notify(notification)
+	nilNotifier.Close()
+}
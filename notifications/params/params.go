@@ -0,0 +1,17 @@
+package params
+
+import "time"
+
+type Email struct {
+	SMTPHost string
+	SMTPPort int
+	// Timeout bounds sending each email, from connecting to the SMTP server until it accepts
+	// the message
+	Timeout    time.Duration
+	Username   string
+	Password   string
+	Sender     string
+	Recipients []string
+	// Events are the notification event types sent by email
+	Events []string
+}
@@ -0,0 +1,221 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/treeverse/lakefs/auth"
+	"github.com/treeverse/lakefs/db"
+	"github.com/treeverse/lakefs/permissions"
+)
+
+var (
+	ErrInvalidChannel = errors.New("invalid notification channel")
+	ErrInvalidAddress = errors.New("invalid notification address")
+	ErrInvalidBranch  = errors.New("branch subscription requires a repository")
+)
+
+// ChannelNames are the channels users may subscribe to
+var ChannelNames = []string{EmailChannelName}
+
+// Subscription subscribes a user to notifications of EventType on Repository and Branch,
+// delivered through Channel to Address. An empty Repository or Branch matches all.
+type Subscription struct {
+	ID           int64     `db:"id"`
+	Username     string    `db:"username"`
+	Repository   string    `db:"repository"`
+	Branch       string    `db:"branch"`
+	EventType    EventType `db:"event_type"`
+	Channel      string    `db:"channel"`
+	Address      string    `db:"address"`
+	CreationDate time.Time `db:"created_at"`
+}
+
+type SubscriptionService interface {
+	Subscribers
+	// CreateSubscription adds subscription, an existing identical subscription is kept
+	CreateSubscription(ctx context.Context, subscription *Subscription) error
+	ListSubscriptions(ctx context.Context, username string) ([]*Subscription, error)
+	DeleteSubscription(ctx context.Context, username string, id int64) error
+}
+
+// Authorizer checks the permissions of subscribers
+type Authorizer interface {
+	Authorize(req *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error)
+}
+
+type DBSubscriptionService struct {
+	db   db.Database
+	auth Authorizer
+}
+
+// NewDBSubscriptionService returns a SubscriptionService delivering notifications of a
+// repository only to the subscribers authorizer allows to read it
+func NewDBSubscriptionService(db db.Database, authorizer Authorizer) *DBSubscriptionService {
+	return &DBSubscriptionService{db: db, auth: authorizer}
+}
+
+// subscriber is the address a user subscribed to a notification
+type subscriber struct {
+	Username string `db:"username"`
+	Address  string `db:"address"`
+}
+
+func validateSubscription(subscription *Subscription) error {
+	if _, err := ParseEventType(string(subscription.EventType)); err != nil {
+		return err
+	}
+	validChannel := false
+	for _, name := range ChannelNames {
+		if subscription.Channel == name {
+			validChannel = true
+			break
+		}
+	}
+	if !validChannel {
+		return fmt.Errorf("%s: %w", subscription.Channel, ErrInvalidChannel)
+	}
+	if subscription.Channel == EmailChannelName {
+		address, err := mail.ParseAddress(subscription.Address)
+		if err != nil || address.Address != subscription.Address {
+			return fmt.Errorf("%s: %w", subscription.Address, ErrInvalidAddress)
+		}
+	}
+	if subscription.Branch != "" && subscription.Repository == "" {
+		return ErrInvalidBranch
+	}
+	return nil
+}
+
+func (s *DBSubscriptionService) CreateSubscription(ctx context.Context, subscription *Subscription) error {
+	if err := validateSubscription(subscription); err != nil {
+		return err
+	}
+	res, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var created Subscription
+		err := tx.Get(&created, `INSERT INTO notification_subscriptions (user_id, repository, branch, event_type, channel, address)
+			SELECT id, $2, $3, $4, $5, $6 FROM auth_users WHERE display_name = $1
+			ON CONFLICT ON CONSTRAINT notification_subscriptions_unique DO UPDATE SET address = EXCLUDED.address
+			RETURNING id, created_at`,
+			subscription.Username, subscription.Repository, subscription.Branch,
+			subscription.EventType, subscription.Channel, subscription.Address)
+		if err != nil {
+			return nil, err
+		}
+		return &created, nil
+	}, db.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	created := res.(*Subscription)
+	subscription.ID = created.ID
+	subscription.CreationDate = created.CreationDate
+	return nil
+}
+
+func (s *DBSubscriptionService) ListSubscriptions(ctx context.Context, username string) ([]*Subscription, error) {
+	res, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var subscriptions []*Subscription
+		err := tx.Select(&subscriptions, `SELECT s.id, u.display_name AS username, s.repository, s.branch, s.event_type, s.channel, s.address, s.created_at
+			FROM notification_subscriptions s JOIN auth_users u ON u.id = s.user_id
+			WHERE u.display_name = $1
+			ORDER BY s.id`, username)
+		if err != nil {
+			return nil, err
+		}
+		return subscriptions, nil
+	}, db.WithContext(ctx), db.ReadOnly())
+	if err != nil {
+		return nil, err
+	}
+	return res.([]*Subscription), nil
+}
+
+func (s *DBSubscriptionService) DeleteSubscription(ctx context.Context, username string, id int64) error {
+	_, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		res, err := tx.Exec(`DELETE FROM notification_subscriptions
+			WHERE id = $2 AND user_id = (SELECT id FROM auth_users WHERE display_name = $1)`, username, id)
+		if err != nil {
+			return nil, err
+		}
+		if res.RowsAffected() == 0 {
+			return nil, db.ErrNotFound
+		}
+		return nil, nil
+	}, db.WithContext(ctx))
+	return err
+}
+
+// Recipients returns the addresses of all users subscribed to notification through channel
+// that may read its repository
+func (s *DBSubscriptionService) Recipients(ctx context.Context, channel string, notification *Notification) ([]string, error) {
+	res, err := s.db.Transact(func(tx db.Tx) (interface{}, error) {
+		var subscribers []subscriber
+		err := tx.Select(&subscribers, `SELECT DISTINCT u.display_name AS username, s.address
+			FROM notification_subscriptions s JOIN auth_users u ON u.id = s.user_id
+			WHERE s.channel = $1 AND s.event_type = $2
+				AND (s.repository = '' OR s.repository = $3)
+				AND (s.branch = '' OR s.branch = $4)
+			ORDER BY username, s.address`,
+			channel, notification.Type, notification.Repository, notification.Branch)
+		if err != nil {
+			return nil, err
+		}
+		return subscribers, nil
+	}, db.WithContext(ctx), db.ReadOnly())
+	if err != nil {
+		return nil, err
+	}
+	return s.authorizedAddresses(res.([]subscriber), notification.Repository)
+}
+
+// authorizedAddresses returns the distinct addresses of subscribers allowed to read repository.
+// Subscriptions to all repositories are checked like subscriptions to repository, and so are
+// subscriptions of users whose permissions changed after subscribing.
+func (s *DBSubscriptionService) authorizedAddresses(subscribers []subscriber, repository string) ([]string, error) {
+	allowed := make(map[string]bool)
+	seen := make(map[string]struct{})
+	var addresses []string
+	for _, sub := range subscribers {
+		ok, checked := allowed[sub.Username]
+		if !checked {
+			var err error
+			ok, err = s.canRead(sub.Username, repository)
+			if err != nil {
+				return nil, err
+			}
+			allowed[sub.Username] = ok
+		}
+		if !ok {
+			continue
+		}
+		if _, ok := seen[sub.Address]; ok {
+			continue
+		}
+		seen[sub.Address] = struct{}{}
+		addresses = append(addresses, sub.Address)
+	}
+	return addresses, nil
+}
+
+func (s *DBSubscriptionService) canRead(username, repository string) (bool, error) {
+	if s.auth == nil {
+		return false, nil
+	}
+	resp, err := s.auth.Authorize(&auth.AuthorizationRequest{
+		Username: username,
+		RequiredPermissions: []permissions.Permission{
+			{
+				Action:   permissions.ReadRepositoryAction,
+				Resource: permissions.RepoArn(repository),
+			},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("authorize %s: %w", username, err)
+	}
+	return resp.Error == nil && resp.Allowed, nil
+}
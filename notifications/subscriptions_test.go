@@ -0,0 +1,97 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/auth"
+	"github.com/treeverse/lakefs/permissions"
+)
+
+func TestValidateSubscription(t *testing.T) {
+	tests := []struct {
+		name         string
+		subscription Subscription
+		wantErr      error
+	}{
+		{
+			name:         "all repositories",
+			subscription: Subscription{EventType: EventExportFailed, Channel: EmailChannelName, Address: "data@example.com"},
+		},
+		{
+			name:         "branch",
+			subscription: Subscription{Repository: "repo", Branch: "master", EventType: EventProtectedBranchMerge, Channel: EmailChannelName, Address: "data@example.com"},
+		},
+		{
+			name:         "unknown event",
+			subscription: Subscription{EventType: "export_started", Channel: EmailChannelName, Address: "data@example.com"},
+			wantErr:      ErrUnknownEventType,
+		},
+		{
+			name:         "unknown channel",
+			subscription: Subscription{EventType: EventExportFailed, Channel: "pager", Address: "data@example.com"},
+			wantErr:      ErrInvalidChannel,
+		},
+		{
+			name:         "invalid address",
+			subscription: Subscription{EventType: EventExportFailed, Channel: EmailChannelName, Address: "Data <data@example.com>"},
+			wantErr:      ErrInvalidAddress,
+		},
+		{
+			name:         "branch without repository",
+			subscription: Subscription{Branch: "master", EventType: EventExportFailed, Channel: EmailChannelName, Address: "data@example.com"},
+			wantErr:      ErrInvalidBranch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSubscription(&tt.subscription)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateSubscription() err=%v, expected=%v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type readersAuthorizer struct {
+	readers map[string]bool
+	checks  int
+}
+
+func (a *readersAuthorizer) Authorize(req *auth.AuthorizationRequest) (*auth.AuthorizationResponse, error) {
+	a.checks++
+	for _, p := range req.RequiredPermissions {
+		if p.Action != permissions.ReadRepositoryAction || p.Resource != permissions.RepoArn("repo") {
+			return &auth.AuthorizationResponse{Allowed: false}, nil
+		}
+	}
+	return &auth.AuthorizationResponse{Allowed: a.readers[req.Username]}, nil
+}
+
+func TestDBSubscriptionService_AuthorizedAddresses(t *testing.T) {
+	authorizer := &readersAuthorizer{readers: map[string]bool{"alice": true}}
+	s := NewDBSubscriptionService(nil, authorizer)
+	addresses, err := s.authorizedAddresses([]subscriber{
+		{Username: "alice", Address: "alice@example.com"},
+		{Username: "alice", Address: "data@example.com"},
+		{Username: "bob", Address: "bob@example.com"},
+		{Username: "bob", Address: "data@example.com"},
+	}, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(addresses, []string{"alice@example.com", "data@example.com"}); diff != nil {
+		t.Fatalf("authorizedAddresses() %s", diff)
+	}
+	if authorizer.checks != 2 {
+		t.Fatalf("authorized %d times, expected once per user", authorizer.checks)
+	}
+
+	addresses, err = NewDBSubscriptionService(nil, nil).authorizedAddresses([]subscriber{
+		{Username: "alice", Address: "alice@example.com"},
+	}, "repo")
+	if err != nil || len(addresses) != 0 {
+		t.Fatalf("authorizedAddresses() without authorizer = %v, %v, expected no addresses", addresses, err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/treeverse/lakefs/block"
 	"github.com/treeverse/lakefs/catalog"
 	"github.com/treeverse/lakefs/cmdutils"
 	"github.com/treeverse/lakefs/db"
@@ -22,6 +23,8 @@ type RepoActions interface {
 	cmdutils.ProgressReporter
 	ApplyImport(ctx context.Context, it Iterator, dryRun bool) (*Stats, error)
 	GetPreviousCommit(ctx context.Context) (commit *catalog.CommitLog, err error)
+	// CommittedObjects iterates over the objects of commit in key order
+	CommittedObjects(ctx context.Context, commit *catalog.CommitLog) (block.InventoryIterator, error)
 	Commit(ctx context.Context, commitMsg string, metadata catalog.Metadata) (*catalog.CommitLog, error)
 }
 
@@ -64,8 +67,11 @@ func worker(wg *sync.WaitGroup, tasks <-chan *task) {
 	wg.Done()
 }
 
+// ApplyImport writes the differences of it to the import branch.  Writes are checked against
+// the repository quota, which a dry run checks for all added or changed objects.
 func (c *CatalogRepoActions) ApplyImport(ctx context.Context, it Iterator, dryRun bool) (*Stats, error) {
 	var stats Stats
+	var addedBytes int64
 	var wg sync.WaitGroup
 	batchSize := DefaultWriteBatchSize
 	if c.WriteBatchSize > 0 {
@@ -101,6 +107,7 @@ func (c *CatalogRepoActions) ApplyImport(ctx context.Context, it Iterator, dryRu
 		}
 		currentBatch = append(currentBatch, entry)
 		stats.AddedOrChanged += 1
+		addedBytes += obj.Size
 		if len(currentBatch) >= batchSize {
 			previousBatch := currentBatch
 			currentBatch = make([]catalog.Entry, 0, batchSize)
@@ -132,6 +139,13 @@ func (c *CatalogRepoActions) ApplyImport(ctx context.Context, it Iterator, dryRu
 			return nil, *err
 		}
 	}
+	if dryRun && stats.AddedOrChanged > 0 {
+		// changed objects are counted as new, as are the objects they replace until expired
+		err := c.cataloger.CheckRepositoryQuota(ctx, c.repository, int64(stats.AddedOrChanged), addedBytes)
+		if err != nil {
+			return nil, fmt.Errorf("import of %d objects (%d bytes): %w", stats.AddedOrChanged, addedBytes, err)
+		}
+	}
 	if len(currentBatch) > 0 && !dryRun {
 		err := c.cataloger.CreateEntries(ctx, c.repository, catalog.DefaultImportBranchName, currentBatch)
 		if err != nil {
@@ -162,12 +176,64 @@ func (c *CatalogRepoActions) GetPreviousCommit(ctx context.Context) (commit *cat
 	return commit, nil
 }
 
+func (c *CatalogRepoActions) CommittedObjects(ctx context.Context, commit *catalog.CommitLog) (block.InventoryIterator, error) {
+	it, err := c.cataloger.ListEntriesIterator(ctx, c.repository, commit.Reference, "")
+	if err != nil {
+		return nil, err
+	}
+	return &entryInventoryIterator{
+		it:       it,
+		progress: cmdutils.NewActiveProgress("Previously Imported Objects Read", cmdutils.Spinner),
+	}, nil
+}
+
+// entryInventoryIterator reads catalog entries as inventory objects
+type entryInventoryIterator struct {
+	it       catalog.EntryIterator
+	value    *block.InventoryObject
+	progress *cmdutils.Progress
+}
+
+func (e *entryInventoryIterator) Next() bool {
+	if !e.it.Next() {
+		e.value = nil
+		e.progress.SetCompleted(true)
+		return false
+	}
+	entry := e.it.Value()
+	creationDate := entry.CreationDate
+	e.value = &block.InventoryObject{
+		Key:             entry.Path,
+		Size:            entry.Size,
+		LastModified:    &creationDate,
+		Checksum:        entry.Checksum,
+		PhysicalAddress: entry.PhysicalAddress,
+	}
+	e.progress.Incr()
+	return true
+}
+
+func (e *entryInventoryIterator) Err() error {
+	return e.it.Err()
+}
+
+func (e *entryInventoryIterator) Get() *block.InventoryObject {
+	return e.value
+}
+
+func (e *entryInventoryIterator) Progress() []*cmdutils.Progress {
+	return []*cmdutils.Progress{e.progress}
+}
+
 func (c *CatalogRepoActions) Commit(ctx context.Context, commitMsg string, metadata catalog.Metadata) (*catalog.CommitLog, error) {
 	c.commitProgress.Activate()
+	// an import commits the whole inventory, it is not limited like other commits
+	ctx = catalog.WithCommitLimitsExempt(ctx)
 	res, err := c.cataloger.Commit(ctx, c.repository, catalog.DefaultImportBranchName,
 		commitMsg,
 		c.committer,
-		metadata)
+		metadata,
+		catalog.CommitParams{})
 	if err == nil {
 		c.commitProgress.SetCompleted(true)
 	}
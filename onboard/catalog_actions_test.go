@@ -2,6 +2,7 @@ package onboard_test
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -15,6 +16,7 @@ import (
 
 type mockCataloger struct {
 	catalog.Cataloger
+	maxObjects int64
 }
 
 var catalogCallData = struct {
@@ -33,6 +35,12 @@ func (m mockCataloger) CreateEntries(_ context.Context, _, _ string, entries []c
 	atomic.AddInt32(catalogCallData.callLog["CreateEntries"], 1)
 	return nil
 }
+func (m mockCataloger) CheckRepositoryQuota(_ context.Context, _ string, objects int64, _ int64) error {
+	if m.maxObjects > 0 && objects > m.maxObjects {
+		return catalog.ErrQuotaExceeded
+	}
+	return nil
+}
 func (m mockCataloger) DeleteEntry(_ context.Context, _, _ string, path string) error {
 	catalogCallData.mux.Lock()
 	defer catalogCallData.mux.Unlock()
@@ -160,3 +168,29 @@ func TestCreateAndDeleteRows(t *testing.T) {
 		}
 	}
 }
+
+func TestDryRunQuota(t *testing.T) {
+	catalogActions := onboard.NewCatalogActions(mockCataloger{maxObjects: 2}, "example-repo", "committer", logging.Default())
+	catalogActions.WriteBatchSize = 2
+	now := time.Now()
+	lastModified := []time.Time{now}
+	cases := []struct {
+		name        string
+		addedRows   []string
+		expectedErr error
+	}{
+		{name: "within", addedRows: []string{"a1", "b2"}},
+		{name: "over", addedRows: []string{"a1", "b2", "c3"}, expectedErr: catalog.ErrQuotaExceeded},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			leftInv := &mockInventory{lastModified: lastModified}
+			rightInv := &mockInventory{keys: tt.addedRows, lastModified: lastModified}
+			_, err := catalogActions.ApplyImport(context.Background(),
+				onboard.NewDiffIterator(leftInv.Iterator(), rightInv.Iterator()), true)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ApplyImport() err=%v, expected=%v", err, tt.expectedErr)
+			}
+		})
+	}
+}
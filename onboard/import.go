@@ -15,13 +15,11 @@ import (
 const CommitMsgTemplate = "Import from %s"
 
 type Importer struct {
-	repository         string
-	inventoryGenerator block.InventoryGenerator
-	inventory          block.Inventory
-	CatalogActions     RepoActions
-	logger             logging.Logger
-	previousCommit     *catalog.CommitLog
-	progress           []*cmdutils.Progress
+	repository     string
+	inventory      block.Inventory
+	CatalogActions RepoActions
+	previousCommit *catalog.CommitLog
+	progress       []*cmdutils.Progress
 }
 
 type Config struct {
@@ -42,17 +40,12 @@ type Stats struct {
 	PreviousImportDate   time.Time
 }
 
-var (
-	ErrNoInventoryURL           = errors.New("no inventory_url in commit Metadata")
-	ErrInventoryAlreadyImported = errors.New("given inventory was already imported")
-)
+var ErrInventoryAlreadyImported = errors.New("given inventory was already imported")
 
 func CreateImporter(ctx context.Context, logger logging.Logger, config *Config) (importer *Importer, err error) {
 	res := &Importer{
-		repository:         config.Repository,
-		inventoryGenerator: config.InventoryGenerator,
-		logger:             logger,
-		CatalogActions:     config.CatalogActions,
+		repository:     config.Repository,
+		CatalogActions: config.CatalogActions,
 	}
 	if res.CatalogActions == nil {
 		res.CatalogActions = NewCatalogActions(config.Cataloger, config.Repository, config.CommitUsername, logger)
@@ -69,19 +62,17 @@ func CreateImporter(ctx context.Context, logger logging.Logger, config *Config)
 	return res, nil
 }
 
+// diffIterator iterates over the objects to add to and delete from the objects of commit, the
+// previous import, to match the inventory.  The previous inventory is not read, so it may
+// have expired.
 func (s *Importer) diffIterator(ctx context.Context, commit catalog.CommitLog) (Iterator, error) {
-	previousInventoryURL := ExtractInventoryURL(commit.Metadata)
-	if previousInventoryURL == "" {
-		return nil, fmt.Errorf("%w. commit_ref=%s", ErrNoInventoryURL, commit.Reference)
-	}
-	if previousInventoryURL == s.inventory.InventoryURL() {
+	if ExtractInventoryURL(commit.Metadata) == s.inventory.InventoryURL() {
 		return nil, fmt.Errorf("%w. commit_ref=%s", ErrInventoryAlreadyImported, commit.Reference)
 	}
-	previousInv, err := s.inventoryGenerator.GenerateInventory(ctx, s.logger, previousInventoryURL, true)
+	previousObjs, err := s.CatalogActions.CommittedObjects(ctx, &commit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create inventory for previous state: %w", err)
+		return nil, fmt.Errorf("failed to read objects of previous import: %w", err)
 	}
-	previousObjs := previousInv.Iterator()
 	currentObjs := s.inventory.Iterator()
 	return NewDiffIterator(previousObjs, currentObjs), nil
 }
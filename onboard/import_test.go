@@ -2,6 +2,7 @@ package onboard_test
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"strconv"
 	"testing"
@@ -76,6 +77,7 @@ func TestImport(t *testing.T) {
 			if len(test.PreviousInventory) > 0 {
 				catalogActionsMock = mockCatalogActions{
 					previousCommitInventory: previousInventoryURL,
+					previousCommitObjects:   test.PreviousInventory,
 				}
 			}
 			inventoryGenerator := &mockInventoryGenerator{
@@ -149,3 +151,44 @@ func TestImport(t *testing.T) {
 		}
 	}
 }
+
+func TestImportPreviousInventoryExpired(t *testing.T) {
+	// the previous inventory is gone, the import diffs the objects of the previous commit
+	catalogActionsMock := &mockCatalogActions{
+		previousCommitInventory: "s3://example-bucket/expired/manifest.json",
+		previousCommitObjects:   []string{"f1", "f2"},
+	}
+	config := &onboard.Config{
+		CommitUsername: "committer",
+		InventoryURL:   NewInventoryURL,
+		Repository:     "example-repo",
+		InventoryGenerator: &mockInventoryGenerator{
+			newInventoryURL: NewInventoryURL,
+			newInventory:    []string{"f2", "f3"},
+			sourceBucket:    "example-repo",
+		},
+		CatalogActions: catalogActionsMock,
+	}
+	importer, err := onboard.CreateImporter(context.Background(), logging.Default(), config)
+	if err != nil {
+		t.Fatalf("failed to create importer: %v", err)
+	}
+	if _, err := importer.Import(context.Background(), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(catalogActionsMock.objectActions.Added, []string{"f3"}) {
+		t.Errorf("objects added to catalog %v, expected [f3]", catalogActionsMock.objectActions.Added)
+	}
+	if !reflect.DeepEqual(catalogActionsMock.objectActions.Deleted, []string{"f1"}) {
+		t.Errorf("objects deleted from catalog %v, expected [f1]", catalogActionsMock.objectActions.Deleted)
+	}
+
+	config.CatalogActions = &mockCatalogActions{previousCommitInventory: NewInventoryURL}
+	importer, err = onboard.CreateImporter(context.Background(), logging.Default(), config)
+	if err != nil {
+		t.Fatalf("failed to create importer: %v", err)
+	}
+	if _, err := importer.Import(context.Background(), false); !errors.Is(err, onboard.ErrInventoryAlreadyImported) {
+		t.Errorf("import of imported inventory err=%v, expected %s", err, onboard.ErrInventoryAlreadyImported)
+	}
+}
@@ -35,6 +35,7 @@ type objectActions struct {
 
 type mockCatalogActions struct {
 	previousCommitInventory string
+	previousCommitObjects   []string
 	objectActions           objectActions
 	lastCommitMetadata      catalog.Metadata
 }
@@ -101,6 +102,11 @@ func (m *mockCatalogActions) GetPreviousCommit(_ context.Context) (commit *catal
 	return nil, nil
 }
 
+func (m *mockCatalogActions) CommittedObjects(_ context.Context, _ *catalog.CommitLog) (block.InventoryIterator, error) {
+	committed := &mockInventory{keys: m.previousCommitObjects, shouldSort: true}
+	return committed.Iterator(), nil
+}
+
 func (m *mockCatalogActions) Commit(_ context.Context, _ string, metadata catalog.Metadata) (*catalog.CommitLog, error) {
 	m.lastCommitMetadata = metadata
 	return &catalog.CommitLog{}, nil
@@ -127,6 +127,10 @@ type TaskData struct {
 	PerformanceToken   *PerformanceToken   `db:"performance_token"`
 	ToSignalAfter      []TaskID            `db:"to_signal_after"`
 	NotifyChannelAfter *string             `db:"notify_channel_after"`
+	// RetryBaseDelay (if non-nil) delays retrying a failed task with exponential backoff,
+	// starting at RetryBaseDelay and doubling on every try up to RetryMaxDelay.
+	RetryBaseDelay *time.Duration `db:"retry_base_delay"`
+	RetryMaxDelay  *time.Duration `db:"retry_max_delay"`
 }
 
 // TaskDataIterator implements the pgx.CopyFromSource interface and allows using CopyFrom to insert
@@ -180,6 +184,8 @@ func (td *TaskDataIterator) Values() ([]interface{}, error) {
 		value.PerformanceToken,
 		toSignalAfter,
 		value.NotifyChannelAfter,
+		value.RetryBaseDelay,
+		value.RetryMaxDelay,
 	}, nil
 }
 
@@ -189,6 +195,7 @@ var TaskDataColumnNames = []string{
 	"num_signals", "total_dependencies",
 	"actor_id", "action_deadline", "performance_token",
 	"to_signal_after", "notify_channel_after",
+	"retry_base_delay", "retry_max_delay",
 }
 
 var tasksTable = pgx.Identifier{"tasks"}
@@ -225,6 +232,22 @@ func OwnTasks(conn pgxscan.Querier, actor ActorID, maxTasks int, actions []strin
 	return tasks, err
 }
 
+// ListAbortedTasks returns the aborted tasks whose IDs start with idPrefix, ordered by ID.
+// Returned tasks hold only their ID, action, body, status and retry policy.
+func ListAbortedTasks(ctx context.Context, conn pgxscan.Querier, idPrefix string) ([]TaskData, error) {
+	rows, err := conn.Query(ctx, `
+		SELECT id, action, body, status, status_code, max_tries, retry_base_delay, retry_max_delay
+		FROM tasks
+		WHERE left(id, length($1)) = $1 AND status_code = 'aborted'
+		ORDER BY id`, idPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("list aborted tasks: %w", err)
+	}
+	tasks := make([]TaskData, 0)
+	err = pgxscan.ScanAll(&tasks, rows)
+	return tasks, err
+}
+
 // ExtendTaskDeadline extends the deadline for completing taskID which was acquired with the
 // specified token, for maxDuration longer.  It returns nil if the task is still owned and its
 // deadline was extended, or an SQL error, or ErrInvalidToken.
@@ -48,6 +48,10 @@ type Parade interface {
 	// table on tx, so ideally close the transaction shortly after.  The effect is easiest
 	// to analyze when all deleted tasks have been either completed or been aborted.
 	DeleteTasks(ctx context.Context, taskIDs []TaskID) error
+
+	// ListAbortedTasks returns the aborted tasks whose IDs start with idPrefix, ordered by
+	// ID, to retry them as new tasks.
+	ListAbortedTasks(ctx context.Context, idPrefix string) ([]TaskData, error)
 }
 
 type Waiter interface {
@@ -158,6 +162,16 @@ func (p *ParadeDB) DeleteTasks(ctx context.Context, taskIDs []TaskID) error {
 	return nil
 }
 
+func (p *ParadeDB) ListAbortedTasks(ctx context.Context, idPrefix string) ([]TaskData, error) {
+	conn, err := p.PgxPool().Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire conn: %w", err)
+	}
+	defer conn.Release()
+
+	return ListAbortedTasks(ctx, conn, idPrefix)
+}
+
 // ParadePrefix wraps a Parade and adds a prefix to all TaskIDs, action names, and ActorIDs.
 type ParadePrefix struct {
 	Base   Parade
@@ -249,4 +263,13 @@ func (pp *ParadePrefix) NewWaiter(ctx context.Context, taskID TaskID) (Waiter, e
 	return pp.Base.NewWaiter(ctx, pp.AddPrefixTask(taskID))
 }
 
+func (pp *ParadePrefix) ListAbortedTasks(ctx context.Context, idPrefix string) ([]TaskData, error) {
+	tasks, err := pp.Base.ListAbortedTasks(ctx, pp.AddPrefix(idPrefix))
+	for i := range tasks {
+		tasks[i].ID = pp.StripPrefixTask(tasks[i].ID)
+		tasks[i].Action = pp.StripPrefix(tasks[i].Action)
+	}
+	return tasks, err
+}
+
 var _ Parade = &ParadePrefix{}
@@ -121,6 +121,7 @@ func TestTaskDataIterator_Empty(t *testing.T) {
 
 func TestTaskDataIterator_Values(t *testing.T) {
 	now := time.Now()
+	baseDelay, maxDelay := time.Second, time.Minute
 	tasks := []parade.TaskData{
 		{ID: "000", Action: "zero", StatusCode: "enum values enforced on DB"},
 		{ID: "111", Action: "frob", Body: stringAddr("1"), Status: stringAddr("state"),
@@ -131,6 +132,7 @@ func TestTaskDataIterator_Values(t *testing.T) {
 			ActorID:       parade.ActorID("actor"), ActionDeadline: &now,
 			PerformanceToken:   performanceTokenAddr(parade.PerformanceToken{}),
 			NotifyChannelAfter: stringAddr("done"),
+			RetryBaseDelay:     &baseDelay, RetryMaxDelay: &maxDelay,
 		},
 	}
 	it := parade.TaskDataIterator{Data: tasks}
@@ -157,6 +159,7 @@ func TestTaskDataIterator_Values(t *testing.T) {
 				task.NumSignals, task.TotalDependencies,
 				task.ActorID, task.ActionDeadline,
 				task.PerformanceToken, toSignalAfter, task.NotifyChannelAfter,
+				task.RetryBaseDelay, task.RetryMaxDelay,
 			}, values); diffs != nil {
 			t.Errorf("got other values at index %d than expected: %s", index, diffs)
 		}
@@ -465,6 +468,42 @@ func TestReturnTask_RetryUntilFailed(t *testing.T) {
 	}
 }
 
+func TestReturnTask_RetryBackoff(t *testing.T) {
+	ctx := context.Background()
+	pp := makeParadePrefix(t)
+
+	maxTries := 3
+	baseDelay, maxDelay := 300*time.Millisecond, 400*time.Millisecond
+	tasks := []parade.TaskData{
+		{ID: "backoff", Action: "frob", MaxTries: &maxTries, RetryBaseDelay: &baseDelay, RetryMaxDelay: &maxDelay},
+	}
+	testutil.MustDo(t, "InsertTasks", pp.InsertTasks(ctx, tasks))
+	defer makeCleanup(t, ctx, pp, tasks)()
+
+	for i := 0; i < maxTries-1; i++ {
+		ownedTasks, err := pp.OwnTasks(parade.ActorID("foo"), 1, []string{"frob"}, nil)
+		testutil.MustDo(t, "OwnTasks", err)
+		if len(ownedTasks) != 1 {
+			t.Fatalf("expected to own single task after %d/%d tries but got %+v", i, maxTries, ownedTasks)
+		}
+		testutil.MustDo(t, "ReturnTask to retry",
+			pp.ReturnTask(ownedTasks[0].ID, ownedTasks[0].Token, "retry", parade.TaskPending))
+
+		ownedTasks, err = pp.OwnTasks(parade.ActorID("foo"), 1, []string{"frob"}, nil)
+		testutil.MustDo(t, "OwnTasks during backoff", err)
+		if len(ownedTasks) != 0 {
+			t.Fatalf("expected not to own task during backoff after %d/%d tries but got %+v", i, maxTries, ownedTasks)
+		}
+		// delays are 300ms then 400ms (bounded from 600ms)
+		time.Sleep(maxDelay + 50*time.Millisecond)
+	}
+	ownedTasks, err := pp.OwnTasks(parade.ActorID("foo"), 1, []string{"frob"}, nil)
+	testutil.MustDo(t, "OwnTasks after backoff", err)
+	if len(ownedTasks) != 1 {
+		t.Errorf("expected to own task after backoff but got %+v", ownedTasks)
+	}
+}
+
 func TestReturnTask_RetryMulti(t *testing.T) {
 	ctx := context.Background()
 	pp := makeParadePrefix(t)
@@ -731,6 +770,34 @@ func TestDeleteTasks(t *testing.T) {
 	}
 }
 
+func TestListAbortedTasks(t *testing.T) {
+	ctx := context.Background()
+	pp := makeParadePrefix(t)
+
+	tasks := []parade.TaskData{
+		{ID: "export1:copy:a", Action: "copy", Body: stringAddr("a"), StatusCode: parade.TaskAborted, MaxTries: intAddr(3)},
+		{ID: "export1:copy:b", Action: "copy", Body: stringAddr("b"), StatusCode: parade.TaskCompleted},
+		{ID: "export1:delete:c", Action: "delete", Body: stringAddr("c"), StatusCode: parade.TaskAborted},
+		{ID: "export1:finish", Action: "finish", StatusCode: parade.TaskPending},
+		{ID: "export10:copy:a", Action: "copy", StatusCode: parade.TaskAborted},
+	}
+	testutil.MustDo(t, "InsertTasks", pp.InsertTasks(ctx, tasks))
+	defer makeCleanup(t, ctx, pp, tasks)()
+
+	got, err := pp.ListAbortedTasks(ctx, "export1:")
+	testutil.MustDo(t, "ListAbortedTasks", err)
+	var gotIDs []parade.TaskID
+	for _, task := range got {
+		gotIDs = append(gotIDs, task.ID)
+	}
+	if diffs := deep.Equal([]parade.TaskID{"export1:copy:a", "export1:delete:c"}, gotIDs); diffs != nil {
+		t.Fatalf("listed other aborted tasks than expected: %s", diffs)
+	}
+	if got[0].Action != "copy" || got[0].Body == nil || *got[0].Body != "a" || got[0].MaxTries == nil || *got[0].MaxTries != 3 {
+		t.Errorf("got aborted task %+v, expected the inserted copy task", got[0])
+	}
+}
+
 func TestNotification(t *testing.T) {
 	type testCase struct {
 		title      string
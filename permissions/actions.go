@@ -12,48 +12,64 @@ var (
 )
 
 const (
-	ReadRepositoryAction   = "fs:ReadRepository"
-	CreateRepositoryAction = "fs:CreateRepository"
-	DeleteRepositoryAction = "fs:DeleteRepository"
-	ListRepositoriesAction = "fs:ListRepositories"
-	ReadObjectAction       = "fs:ReadObject"
-	WriteObjectAction      = "fs:WriteObject"
-	DeleteObjectAction     = "fs:DeleteObject"
-	ListObjectsAction      = "fs:ListObjects"
-	CreateCommitAction     = "fs:CreateCommit"
-	ReadCommitAction       = "fs:ReadCommit"
-	CreateBranchAction     = "fs:CreateBranch"
-	DeleteBranchAction     = "fs:DeleteBranch"
-	ReadBranchAction       = "fs:ReadBranch"
-	RevertBranchAction     = "fs:RevertBranch"
-	ListBranchesAction     = "fs:ListBranches"
-	ExportConfigAction     = "fs:ExportConfig"
+	ReadRepositoryAction       = "fs:ReadRepository"
+	CreateRepositoryAction     = "fs:CreateRepository"
+	DeleteRepositoryAction     = "fs:DeleteRepository"
+	ListRepositoriesAction     = "fs:ListRepositories"
+	ReadObjectAction           = "fs:ReadObject"
+	WriteObjectAction          = "fs:WriteObject"
+	DeleteObjectAction         = "fs:DeleteObject"
+	ListObjectsAction          = "fs:ListObjects"
+	CreateCommitAction         = "fs:CreateCommit"
+	ReadCommitAction           = "fs:ReadCommit"
+	CreateBranchAction         = "fs:CreateBranch"
+	DeleteBranchAction         = "fs:DeleteBranch"
+	RenameBranchAction         = "fs:RenameBranch"
+	ReadBranchAction           = "fs:ReadBranch"
+	RevertBranchAction         = "fs:RevertBranch"
+	ResetBranchAction          = "fs:ResetBranch"
+	ListBranchesAction         = "fs:ListBranches"
+	ExportConfigAction         = "fs:ExportConfig"
+	SetRepositoryQuotaAction   = "fs:SetRepositoryQuota"
+	ReadOrganizationAction     = "fs:ReadOrganization"
+	SetOrganizationQuotaAction = "fs:SetOrganizationQuota"
+	SetDefaultBranchAction     = "fs:SetDefaultBranch"
+	SetReadOnlyAction          = "fs:SetReadOnly"
+	ArchiveRepositoryAction    = "fs:ArchiveRepository"
+	SetCommitLimitsAction      = "fs:SetCommitLimits"
+	ExemptCommitLimitsAction   = "fs:ExemptCommitLimits"
+	SetMetadataSchemaAction    = "fs:SetMetadataSchema"
+	RetryHookRunAction         = "fs:RetryHookRun"
 
 	RetentionReadPolicyAction  = "retention:GetPolicy"
 	RetentionWritePolicyAction = "retention:WritePolicy"
 
-	ReadUserAction          = "auth:ReadUser"
-	CreateUserAction        = "auth:CreateUser"
-	DeleteUserAction        = "auth:DeleteUser"
-	ListUsersAction         = "auth:ListUsers"
-	ReadGroupAction         = "auth:ReadGroup"
-	CreateGroupAction       = "auth:CreateGroup"
-	DeleteGroupAction       = "auth:DeleteGroup"
-	ListGroupsAction        = "auth:ListGroups"
-	AddGroupMemberAction    = "auth:AddGroupMember"
-	RemoveGroupMemberAction = "auth:RemoveGroupMember"
-	ReadPolicyAction        = "auth:ReadPolicy"
-	CreatePolicyAction      = "auth:CreatePolicy"
-	UpdatePolicyAction      = "auth:UpdatePolicy"
-	DeletePolicyAction      = "auth:DeletePolicy"
-	ListPoliciesAction      = "auth:ListPolicies"
-	AttachPolicyAction      = "auth:AttachPolicy"
-	DetachPolicyAction      = "auth:DetachPolicy"
-	ReadCredentialsAction   = "auth:ReadCredentials"
-	CreateCredentialsAction = "auth:CreateCredentials"
-	DeleteCredentialsAction = "auth:DeleteCredentials"
-	ListCredentialsAction   = "auth:ListCredentials"
-	ReadConfigAction        = "auth:ReadConfig"
+	ReadUserAction           = "auth:ReadUser"
+	CreateUserAction         = "auth:CreateUser"
+	DeleteUserAction         = "auth:DeleteUser"
+	ListUsersAction          = "auth:ListUsers"
+	ReadGroupAction          = "auth:ReadGroup"
+	CreateGroupAction        = "auth:CreateGroup"
+	DeleteGroupAction        = "auth:DeleteGroup"
+	ListGroupsAction         = "auth:ListGroups"
+	AddGroupMemberAction     = "auth:AddGroupMember"
+	RemoveGroupMemberAction  = "auth:RemoveGroupMember"
+	ReadPolicyAction         = "auth:ReadPolicy"
+	CreatePolicyAction       = "auth:CreatePolicy"
+	UpdatePolicyAction       = "auth:UpdatePolicy"
+	DeletePolicyAction       = "auth:DeletePolicy"
+	ListPoliciesAction       = "auth:ListPolicies"
+	AttachPolicyAction       = "auth:AttachPolicy"
+	DetachPolicyAction       = "auth:DetachPolicy"
+	ReadCredentialsAction    = "auth:ReadCredentials"
+	CreateCredentialsAction  = "auth:CreateCredentials"
+	DeleteCredentialsAction  = "auth:DeleteCredentials"
+	ListCredentialsAction    = "auth:ListCredentials"
+	ListSubscriptionsAction  = "auth:ListSubscriptions"
+	CreateSubscriptionAction = "auth:CreateSubscription"
+	DeleteSubscriptionAction = "auth:DeleteSubscription"
+	ReadConfigAction         = "auth:ReadConfig"
+	WriteConfigAction        = "auth:WriteConfig"
 )
 
 var serviceSet = map[string]struct{}{
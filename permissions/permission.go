@@ -24,6 +24,10 @@ func BranchArn(repoID, branchID string) string {
 	return fSArnPrefix + "repository/" + repoID + "/branch/" + branchID
 }
 
+func OrganizationArn(organization string) string {
+	return fSArnPrefix + "organization/" + organization
+}
+
 func UserArn(userID string) string {
 	return authArnPrefix + "user/" + userID
 }
@@ -0,0 +1,168 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DefaultMaxBytes = 4 * 1024
+	MaxBytesLimit   = 1024 * 1024
+	DefaultMaxRows  = 20
+	MaxRowsLimit    = 1000
+)
+
+type Format string
+
+const (
+	FormatText      Format = "text"
+	FormatCSV       Format = "csv"
+	FormatJSONLines Format = "jsonl"
+	FormatBinary    Format = "binary"
+)
+
+// Preview is a bounded view of an object's content.  Depending on Format, one of Text, Rows or
+// Records holds the content.
+type Preview struct {
+	ContentType string
+	Format      Format
+	// Truncated is true when the preview doesn't cover the entire object
+	Truncated bool
+	Text      string
+	Rows      [][]string
+	Records   []interface{}
+}
+
+var extensionFormats = map[string]struct {
+	contentType string
+	format      Format
+	comma       rune
+}{
+	".csv":    {contentType: "text/csv", format: FormatCSV, comma: ','},
+	".tsv":    {contentType: "text/tab-separated-values", format: FormatCSV, comma: '\t'},
+	".jsonl":  {contentType: "application/x-ndjson", format: FormatJSONLines},
+	".ndjson": {contentType: "application/x-ndjson", format: FormatJSONLines},
+	".json":   {contentType: "application/json", format: FormatText},
+}
+
+// Detect returns the content type and preview format of an object by its path extension, or
+// by sniffing the head of its content.
+func Detect(path string, head []byte) (string, Format) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if f, ok := extensionFormats[ext]; ok {
+		return f.contentType, f.format
+	}
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = http.DetectContentType(head)
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || mediaType == "application/xml" {
+		return contentType, FormatText
+	}
+	return contentType, FormatBinary
+}
+
+// Read builds a preview of the object at path from the head of its content.  truncated
+// specifies that head doesn't hold the entire content.  At most maxRows rows are decoded for
+// tabular formats.
+func Read(path string, head []byte, truncated bool, maxRows int) *Preview {
+	contentType, format := Detect(path, head)
+	p := &Preview{
+		ContentType: contentType,
+		Format:      format,
+		Truncated:   truncated,
+	}
+	switch format {
+	case FormatCSV:
+		comma := extensionFormats[strings.ToLower(filepath.Ext(path))].comma
+		if rows, more, err := readCSV(completeLines(head, truncated), comma, maxRows); err == nil {
+			p.Rows = rows
+			p.Truncated = p.Truncated || more
+			return p
+		}
+	case FormatJSONLines:
+		if records, more, err := readJSONLines(completeLines(head, truncated), maxRows); err == nil {
+			p.Records = records
+			p.Truncated = p.Truncated || more
+			return p
+		}
+	case FormatBinary:
+		return p
+	}
+	// text, or tabular content that failed to decode
+	p.Format = FormatText
+	p.Text = validUTF8Prefix(head)
+	return p
+}
+
+// completeLines drops the last, partial, line of truncated content
+func completeLines(data []byte, truncated bool) []byte {
+	if !truncated {
+		return data
+	}
+	idx := bytes.LastIndexByte(data, '\n')
+	if idx < 0 {
+		return nil
+	}
+	return data[:idx+1]
+}
+
+func readCSV(data []byte, comma rune, maxRows int) ([][]string, bool, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	rows := make([][]string, 0)
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if len(rows) == maxRows {
+			return rows, true, nil
+		}
+		rows = append(rows, row)
+	}
+}
+
+func readJSONLines(data []byte, maxRows int) ([]interface{}, bool, error) {
+	records := make([]interface{}, 0)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if len(records) == maxRows {
+			return records, true, nil
+		}
+		var record interface{}
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, false, err
+		}
+		records = append(records, record)
+	}
+	return records, false, nil
+}
+
+// validUTF8Prefix returns data as a string, without a trailing partial UTF-8 sequence
+func validUTF8Prefix(data []byte) string {
+	for i := 0; i < utf8.UTFMax && len(data) > 0; i++ {
+		if utf8.Valid(data) {
+			break
+		}
+		data = data[:len(data)-1]
+	}
+	return string(data)
+}
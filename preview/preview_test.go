@@ -0,0 +1,106 @@
+package preview_test
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/treeverse/lakefs/preview"
+)
+
+func TestRead(t *testing.T) {
+	cases := []struct {
+		name      string
+		path      string
+		head      string
+		truncated bool
+		maxRows   int
+		expected  *preview.Preview
+	}{
+		{
+			name: "text",
+			path: "readme.txt",
+			head: "hello world",
+			expected: &preview.Preview{
+				ContentType: "text/plain; charset=utf-8",
+				Format:      preview.FormatText,
+				Text:        "hello world",
+			},
+		},
+		{
+			name:      "text truncated in rune",
+			path:      "readme",
+			head:      "hello w\xc3",
+			truncated: true,
+			expected: &preview.Preview{
+				ContentType: "text/plain; charset=utf-8",
+				Format:      preview.FormatText,
+				Truncated:   true,
+				Text:        "hello w",
+			},
+		},
+		{
+			name:    "csv",
+			path:    "data/table.csv",
+			head:    "a,b\n1,2\n3,4\n",
+			maxRows: 10,
+			expected: &preview.Preview{
+				ContentType: "text/csv",
+				Format:      preview.FormatCSV,
+				Rows:        [][]string{{"a", "b"}, {"1", "2"}, {"3", "4"}},
+			},
+		},
+		{
+			name:      "csv truncated",
+			path:      "data/table.csv",
+			head:      "a,b\n1,2\n3,",
+			truncated: true,
+			maxRows:   10,
+			expected: &preview.Preview{
+				ContentType: "text/csv",
+				Format:      preview.FormatCSV,
+				Truncated:   true,
+				Rows:        [][]string{{"a", "b"}, {"1", "2"}},
+			},
+		},
+		{
+			name:    "jsonl max rows",
+			path:    "events.jsonl",
+			head:    "{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n",
+			maxRows: 2,
+			expected: &preview.Preview{
+				ContentType: "application/x-ndjson",
+				Format:      preview.FormatJSONLines,
+				Truncated:   true,
+				Records:     []interface{}{map[string]interface{}{"a": 1.0}, map[string]interface{}{"a": 2.0}},
+			},
+		},
+		{
+			name:    "invalid jsonl as text",
+			path:    "events.jsonl",
+			head:    "not json\n",
+			maxRows: 2,
+			expected: &preview.Preview{
+				ContentType: "application/x-ndjson",
+				Format:      preview.FormatText,
+				Text:        "not json\n",
+			},
+		},
+		{
+			name: "binary",
+			path: "data/part-0000",
+			head: "\x00\x01\x02PAR1",
+			expected: &preview.Preview{
+				ContentType: "application/octet-stream",
+				Format:      preview.FormatBinary,
+			},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := preview.Read(tt.path, []byte(tt.head), tt.truncated, tt.maxRows)
+			if diff := deep.Equal(got, tt.expected); diff != nil {
+				t.Fatal("Read() unexpected preview", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package preview
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/scritchley/orc"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+type TableFormat string
+
+const (
+	TableFormatParquet TableFormat = "parquet"
+	TableFormatORC     TableFormat = "orc"
+	TableFormatCSV     TableFormat = "csv"
+)
+
+const (
+	parquetMagic = "PAR1"
+	orcMagic     = "ORC"
+)
+
+var (
+	ErrUnsupportedTableFormat = errors.New("unsupported table format. supported formats: parquet, orc, csv")
+	ErrReadOnlySource         = errors.New("source is read only")
+)
+
+// Source reads an object's content at arbitrary offsets
+type Source interface {
+	io.ReaderAt
+	Size() int64
+}
+
+type Column struct {
+	Name string
+	Type string
+	// Required is set for parquet columns that cannot hold nulls
+	Required bool
+}
+
+// Table describes the schema of a table file, with a sample of its first rows
+type Table struct {
+	Format  TableFormat
+	Columns []Column
+	// RowCount is the number of rows in the object, nil when unknown
+	RowCount *int64
+	// Sample holds the first rows, values ordered by Columns
+	Sample [][]interface{}
+}
+
+// DetectTable returns the table format of an object by its path extension, or by the magic
+// bytes at the head of its content.
+func DetectTable(path string, src Source) (TableFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".parquet":
+		return TableFormatParquet, nil
+	case ".orc":
+		return TableFormatORC, nil
+	case ".csv", ".tsv":
+		return TableFormatCSV, nil
+	}
+	magic := make([]byte, len(parquetMagic))
+	n, err := src.ReadAt(magic, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	magic = magic[:n]
+	switch {
+	case bytes.Equal(magic, []byte(parquetMagic)):
+		return TableFormatParquet, nil
+	case bytes.HasPrefix(magic, []byte(orcMagic)):
+		return TableFormatORC, nil
+	}
+	return "", ErrUnsupportedTableFormat
+}
+
+// ReadTable reads the schema and up to maxRows sample rows of the table object at path.
+// Parquet and ORC objects are read from their footer, CSV objects from their head.
+func ReadTable(path string, src Source, maxRows int) (*Table, error) {
+	format, err := DetectTable(path, src)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case TableFormatParquet:
+		return readParquetTable(src, maxRows)
+	case TableFormatORC:
+		return readORCTable(src, maxRows)
+	default:
+		return readCSVTable(path, src, maxRows)
+	}
+}
+
+// parquetFile adapts a Source to the file interface used by the parquet reader
+type parquetFile struct {
+	*io.SectionReader
+	src Source
+}
+
+func newParquetFile(src Source) *parquetFile {
+	return &parquetFile{
+		SectionReader: io.NewSectionReader(src, 0, src.Size()),
+		src:           src,
+	}
+}
+
+func (f *parquetFile) Open(_ string) (source.ParquetFile, error) {
+	return newParquetFile(f.src), nil
+}
+
+func (f *parquetFile) Create(_ string) (source.ParquetFile, error) {
+	return nil, ErrReadOnlySource
+}
+
+func (f *parquetFile) Write(_ []byte) (int, error) {
+	return 0, ErrReadOnlySource
+}
+
+func (f *parquetFile) Close() error {
+	return nil
+}
+
+func readParquetTable(src Source, maxRows int) (*Table, error) {
+	pr, err := reader.NewParquetColumnReader(newParquetFile(src), 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	defer pr.ReadStop()
+
+	numRows := pr.GetNumRows()
+	sampleRows := int64(maxRows)
+	if sampleRows > numRows {
+		sampleRows = numRows
+	}
+	schemaHandler := pr.SchemaHandler
+	table := &Table{
+		Format:   TableFormatParquet,
+		Columns:  make([]Column, 0, len(schemaHandler.ValueColumns)),
+		RowCount: &numRows,
+		Sample:   make([][]interface{}, sampleRows),
+	}
+	for i := range table.Sample {
+		table.Sample[i] = make([]interface{}, len(schemaHandler.ValueColumns))
+	}
+	for i, inPath := range schemaHandler.ValueColumns {
+		element := schemaHandler.SchemaElements[schemaHandler.MapIndex[inPath]]
+		columnType := element.GetType().String()
+		if element.IsSetConvertedType() {
+			columnType = element.GetConvertedType().String()
+		}
+		// drop the root element from the column path
+		exPath := common.StrToPath(schemaHandler.InPathToExPath[inPath])
+		table.Columns = append(table.Columns, Column{
+			Name:     strings.Join(exPath[1:], "."),
+			Type:     columnType,
+			Required: element.GetRepetitionType() == parquet.FieldRepetitionType_REQUIRED,
+		})
+
+		// values of repeated columns don't map one to one to rows, leave them out of the sample
+		path := common.StrToPath(inPath)
+		maxRepetition, err := schemaHandler.MaxRepetitionLevel(path)
+		if err != nil {
+			return nil, err
+		}
+		if maxRepetition > 0 || sampleRows == 0 {
+			continue
+		}
+		maxDefinition, err := schemaHandler.MaxDefinitionLevel(path)
+		if err != nil {
+			return nil, err
+		}
+		values, _, dls, err := pr.ReadColumnByPath(inPath, sampleRows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read parquet column %s: %w", table.Columns[i].Name, err)
+		}
+		for row := 0; row < len(values) && row < len(table.Sample); row++ {
+			if dls[row] < maxDefinition {
+				// null value
+				continue
+			}
+			table.Sample[row][i] = values[row]
+		}
+	}
+	return table, nil
+}
+
+func readORCTable(src Source, maxRows int) (*Table, error) {
+	orcReader, err := orc.NewReader(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create orc reader: %w", err)
+	}
+	defer func() {
+		_ = orcReader.Close()
+	}()
+
+	schema := orcReader.Schema()
+	columnNames := schema.Columns()
+	numRows := int64(orcReader.NumRows())
+	table := &Table{
+		Format:   TableFormatORC,
+		Columns:  make([]Column, 0, len(columnNames)),
+		RowCount: &numRows,
+		Sample:   make([][]interface{}, 0),
+	}
+	for _, name := range columnNames {
+		field, err := schema.GetField(name)
+		if err != nil {
+			return nil, err
+		}
+		table.Columns = append(table.Columns, Column{
+			Name: name,
+			Type: field.String(),
+		})
+	}
+	if maxRows == 0 {
+		return table, nil
+	}
+
+	cursor := orcReader.Select(columnNames...)
+	defer func() {
+		_ = cursor.Close()
+	}()
+	for len(table.Sample) < maxRows && cursor.Stripes() {
+		for len(table.Sample) < maxRows && cursor.Next() {
+			row := make([]interface{}, len(columnNames))
+			copy(row, cursor.Row())
+			table.Sample = append(table.Sample, row)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read orc rows: %w", err)
+	}
+	return table, nil
+}
+
+// readCSVTable reads the columns from the header row of the object, and a sample from the rows
+// that follow it.  Column types are not part of the format and are reported as strings.  Only
+// the head of the object is read, so the row count is known only for small objects.
+func readCSVTable(path string, src Source, maxRows int) (*Table, error) {
+	size := src.Size()
+	truncated := size > MaxBytesLimit
+	if truncated {
+		size = MaxBytesLimit
+	}
+	head := make([]byte, size)
+	n, err := src.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	comma := ','
+	if strings.ToLower(filepath.Ext(path)) == ".tsv" {
+		comma = '\t'
+	}
+	limit := maxRows + 1 // header row
+	if !truncated {
+		// read all rows to count them
+		limit = -1
+	}
+	rows, more, err := readCSV(completeLines(head, truncated), comma, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv rows: %w", err)
+	}
+	table := &Table{
+		Format:  TableFormatCSV,
+		Columns: make([]Column, 0),
+		Sample:  make([][]interface{}, 0),
+	}
+	if len(rows) == 0 {
+		if !truncated {
+			table.RowCount = new(int64)
+		}
+		return table, nil
+	}
+	for _, name := range rows[0] {
+		table.Columns = append(table.Columns, Column{Name: name, Type: "string"})
+	}
+	rows = rows[1:]
+	if !truncated && !more {
+		rowCount := int64(len(rows))
+		table.RowCount = &rowCount
+	}
+	for i := 0; i < len(rows) && i < maxRows; i++ {
+		row := make([]interface{}, len(rows[i]))
+		for j := range rows[i] {
+			row[j] = rows[i][j]
+		}
+		table.Sample = append(table.Sample, row)
+	}
+	return table, nil
+}
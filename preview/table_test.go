@@ -0,0 +1,163 @@
+package preview_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/go-openapi/swag"
+	"github.com/scritchley/orc"
+	"github.com/treeverse/lakefs/preview"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+type bytesSource struct {
+	*bytes.Reader
+}
+
+func newBytesSource(data []byte) *bytesSource {
+	return &bytesSource{Reader: bytes.NewReader(data)}
+}
+
+type testRecord struct {
+	ID    int64   `parquet:"name=id, type=INT64"`
+	Name  string  `parquet:"name=name, type=UTF8"`
+	Score *string `parquet:"name=score, type=UTF8"`
+}
+
+func generateParquet(t *testing.T, records []*testRecord) []byte {
+	t.Helper()
+	fw, err := buffer.NewBufferFile(nil)
+	if err != nil {
+		t.Fatalf("failed to create parquet buffer: %s", err)
+	}
+	pw, err := writer.NewParquetWriter(fw, new(testRecord), 1)
+	if err != nil {
+		t.Fatalf("failed to create parquet writer: %s", err)
+	}
+	for _, r := range records {
+		if err := pw.Write(r); err != nil {
+			t.Fatalf("failed to write parquet record: %s", err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		t.Fatalf("failed to stop parquet writer: %s", err)
+	}
+	return fw.(buffer.BufferFile).Bytes()
+}
+
+func generateORC(t *testing.T, rows [][]interface{}) []byte {
+	t.Helper()
+	schema, err := orc.ParseSchema("struct<id:bigint,name:string>")
+	if err != nil {
+		t.Fatalf("failed to parse orc schema: %s", err)
+	}
+	var buf bytes.Buffer
+	w, err := orc.NewWriter(&buf, orc.SetSchema(schema))
+	if err != nil {
+		t.Fatalf("failed to create orc writer: %s", err)
+	}
+	for _, row := range rows {
+		if err := w.Write(row...); err != nil {
+			t.Fatalf("failed to write orc row: %s", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close orc writer: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func columnNames(table *preview.Table) []string {
+	names := make([]string, len(table.Columns))
+	for i, c := range table.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func TestReadTable_Parquet(t *testing.T) {
+	data := generateParquet(t, []*testRecord{
+		{ID: 1, Name: "one", Score: swag.String("high")},
+		{ID: 2, Name: "two"},
+		{ID: 3, Name: "three", Score: swag.String("low")},
+	})
+	// no extension - detected by magic
+	table, err := preview.ReadTable("data/part-0000", newBytesSource(data), 2)
+	if err != nil {
+		t.Fatalf("ReadTable failed: %s", err)
+	}
+	if table.Format != preview.TableFormatParquet {
+		t.Fatalf("Format=%s, expected %s", table.Format, preview.TableFormatParquet)
+	}
+	if names := columnNames(table); len(names) != 3 || names[0] != "id" || names[1] != "name" || names[2] != "score" {
+		t.Fatalf("unexpected columns %v", table.Columns)
+	}
+	if table.Columns[1].Type != "UTF8" {
+		t.Errorf("name column type=%s, expected UTF8", table.Columns[1].Type)
+	}
+	if swag.Int64Value(table.RowCount) != 3 {
+		t.Errorf("RowCount=%d, expected 3", swag.Int64Value(table.RowCount))
+	}
+	if len(table.Sample) != 2 {
+		t.Fatalf("got %d sample rows, expected 2", len(table.Sample))
+	}
+	if table.Sample[0][0] != int64(1) || table.Sample[0][1] != "one" || table.Sample[0][2] != "high" {
+		t.Errorf("unexpected first sample row %v", table.Sample[0])
+	}
+	if table.Sample[1][2] != nil {
+		t.Errorf("expected null score in second sample row, got %v", table.Sample[1][2])
+	}
+}
+
+func TestReadTable_ORC(t *testing.T) {
+	data := generateORC(t, [][]interface{}{
+		{int64(1), "one"},
+		{int64(2), "two"},
+		{int64(3), "three"},
+	})
+	table, err := preview.ReadTable("data/table.orc", newBytesSource(data), 5)
+	if err != nil {
+		t.Fatalf("ReadTable failed: %s", err)
+	}
+	if table.Format != preview.TableFormatORC {
+		t.Fatalf("Format=%s, expected %s", table.Format, preview.TableFormatORC)
+	}
+	if names := columnNames(table); len(names) != 2 || names[0] != "id" || names[1] != "name" {
+		t.Fatalf("unexpected columns %v", table.Columns)
+	}
+	if swag.Int64Value(table.RowCount) != 3 {
+		t.Errorf("RowCount=%d, expected 3", swag.Int64Value(table.RowCount))
+	}
+	if len(table.Sample) != 3 || table.Sample[2][1] != "three" {
+		t.Errorf("unexpected sample %v", table.Sample)
+	}
+}
+
+func TestReadTable_CSV(t *testing.T) {
+	data := []byte("id,name\n1,one\n2,two\n3,three\n")
+	table, err := preview.ReadTable("data/table.csv", newBytesSource(data), 2)
+	if err != nil {
+		t.Fatalf("ReadTable failed: %s", err)
+	}
+	if table.Format != preview.TableFormatCSV {
+		t.Fatalf("Format=%s, expected %s", table.Format, preview.TableFormatCSV)
+	}
+	if names := columnNames(table); len(names) != 2 || names[0] != "id" || names[1] != "name" {
+		t.Fatalf("unexpected columns %v", table.Columns)
+	}
+	if swag.Int64Value(table.RowCount) != 3 {
+		t.Errorf("RowCount=%d, expected 3", swag.Int64Value(table.RowCount))
+	}
+	if len(table.Sample) != 2 || table.Sample[1][1] != "two" {
+		t.Errorf("unexpected sample %v", table.Sample)
+	}
+}
+
+func TestReadTable_Unsupported(t *testing.T) {
+	_, err := preview.ReadTable("data/notes.txt", newBytesSource([]byte("hello world")), 2)
+	if !errors.Is(err, preview.ErrUnsupportedTableFormat) {
+		t.Fatalf("expected ErrUnsupportedTableFormat, got %v", err)
+	}
+}
@@ -10,8 +10,6 @@ import (
 	"github.com/treeverse/lakefs/db"
 )
 
-const dbConfigKey = "retentionPolicy"
-
 var ErrPolicyNotFound = errors.New("policy not found")
 
 type DBRetentionService struct {
@@ -29,7 +27,7 @@ func (s *DBRetentionService) GetPolicy(repositoryName string) (*catalog.PolicyWi
 			&policy,
 			`SELECT description, (value::json)->'Rules' as rules, created_at FROM catalog_repositories_config WHERE repository_id IN (SELECT id FROM catalog_repositories WHERE name = $1) AND key = $2`,
 			repositoryName,
-			dbConfigKey,
+			catalog.RetentionPolicyConfigKey,
 		)
 		if errors.Is(err, db.ErrNotFound) {
 			return nil, nil
@@ -51,7 +49,7 @@ func (s *DBRetentionService) SetPolicy(repositoryName string, policy *catalog.Po
                          FROM catalog_repositories WHERE name=$1
                          ON CONFLICT (repository_id, key)
                          DO UPDATE SET (value, description, created_at) = (EXCLUDED.value, EXCLUDED.description, EXCLUDED.created_at)`,
-			repositoryName, dbConfigKey, &catalog.RulesHolder{Rules: policy.Rules}, policy.Description, creationDate,
+			repositoryName, catalog.RetentionPolicyConfigKey, &catalog.RulesHolder{Rules: policy.Rules}, policy.Description, creationDate,
 		)
 	})
 	return err
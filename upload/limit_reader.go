@@ -0,0 +1,38 @@
+package upload
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrEntityTooLarge = errors.New("entity too large")
+
+type limitReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+// LimitReader returns a reader of r that fails with ErrEntityTooLarge when r holds more than
+// n bytes, a limit of 0 doesn't limit r
+func LimitReader(r io.Reader, n int64) io.Reader {
+	if n <= 0 {
+		return r
+	}
+	return &limitReader{r: r, remaining: n}
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrEntityTooLarge
+	}
+	// read one byte past the limit to tell a reader of exactly the limit from a larger one
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrEntityTooLarge
+	}
+	return n, err
+}
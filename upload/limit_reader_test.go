@@ -0,0 +1,41 @@
+package upload_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/treeverse/lakefs/upload"
+)
+
+func TestLimitReader(t *testing.T) {
+	cases := []struct {
+		name        string
+		data        string
+		limit       int64
+		expectedErr error
+	}{
+		{name: "under limit", data: "abc", limit: 4},
+		{name: "at limit", data: "abcd", limit: 4},
+		{name: "over limit", data: "abcde", limit: 4, expectedErr: upload.ErrEntityTooLarge},
+		{name: "unbounded", data: "abcde", limit: 0},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ioutil.ReadAll(upload.LimitReader(strings.NewReader(tt.data), tt.limit))
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("LimitReader() err=%v, expected %v", err, tt.expectedErr)
+			}
+			if tt.expectedErr != nil {
+				if int64(len(data)) > tt.limit {
+					t.Fatalf("LimitReader() read %d bytes, over the limit of %d", len(data), tt.limit)
+				}
+				return
+			}
+			if string(data) != tt.data {
+				t.Fatalf("LimitReader() read %s, expected %s", data, tt.data)
+			}
+		})
+	}
+}
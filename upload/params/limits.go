@@ -0,0 +1,16 @@
+package params
+
+// Limits bound the size of uploads and API requests, a limit of 0 is unbounded
+type Limits struct {
+	// MaxObjectSize is the maximum size of an object uploaded in a single request
+	MaxObjectSize int64
+	// MaxParts is the maximum number of parts of a multipart upload
+	MaxParts int64
+	// MinPartSize is the minimum size of the parts of a multipart upload, except its last
+	// part
+	MinPartSize int64
+	// MaxPartSize is the maximum size of a part of a multipart upload
+	MaxPartSize int64
+	// MaxRequestBodySize is the maximum size of the body of API requests other than uploads
+	MaxRequestBodySize int64
+}
@@ -1,8 +1,10 @@
 package upload
 
 import (
+	"crypto/md5" //nolint:gosec
 	"encoding/hex"
 	"io"
+	"net/url"
 
 	"github.com/google/uuid"
 	"github.com/treeverse/lakefs/block"
@@ -36,3 +38,70 @@ func WriteBlob(adapter block.Adapter, bucketName string, body io.Reader, content
 		Size:            hashReader.CopiedSize,
 	}, nil
 }
+
+// CopyBlob copies the object at sourceAddress of sourceNamespace to a new physical address in
+// destinationNamespace, and returns that address
+func CopyBlob(adapter block.Adapter, sourceNamespace, sourceAddress, destinationNamespace string) (string, error) {
+	uid := uuid.New()
+	address := hex.EncodeToString(uid[:])
+	err := adapter.Copy(block.ObjectPointer{
+		StorageNamespace: sourceNamespace,
+		Identifier:       sourceAddress,
+	}, block.ObjectPointer{
+		StorageNamespace: destinationNamespace,
+		Identifier:       address,
+	}, block.CopyOpts{})
+	if err != nil {
+		return "", err
+	}
+	return address, nil
+}
+
+// MigrateBlob copies the object at sourceAddress of sourceNamespace to destinationNamespace,
+// and returns its physical address there.  Relative addresses are kept, objects at fully
+// qualified addresses are copied to a new address.
+func MigrateBlob(adapter block.Adapter, sourceNamespace, sourceAddress, destinationNamespace string) (string, error) {
+	if _, err := url.ParseRequestURI(sourceAddress); err == nil {
+		return CopyBlob(adapter, sourceNamespace, sourceAddress, destinationNamespace)
+	}
+	err := adapter.Copy(block.ObjectPointer{
+		StorageNamespace: sourceNamespace,
+		Identifier:       sourceAddress,
+	}, block.ObjectPointer{
+		StorageNamespace: destinationNamespace,
+		Identifier:       sourceAddress,
+	}, block.CopyOpts{})
+	if err != nil {
+		return "", err
+	}
+	return sourceAddress, nil
+}
+
+// TransitionBlob moves the object at address of namespace to storageClass.  Objects at fully
+// qualified addresses were imported from outside the storage namespace, and are left as is.
+func TransitionBlob(adapter block.Adapter, namespace, address, storageClass string) error {
+	if _, err := url.ParseRequestURI(address); err == nil {
+		return nil
+	}
+	return adapter.SetStorageClass(block.ObjectPointer{
+		StorageNamespace: namespace,
+		Identifier:       address,
+	}, storageClass)
+}
+
+// ChecksumBlob returns the checksum of the object of size at address of namespace, as
+// WriteBlob computes it
+func ChecksumBlob(adapter block.Adapter, namespace, address string, size int64) (string, error) {
+	reader, err := adapter.Get(block.ObjectPointer{StorageNamespace: namespace, Identifier: address}, size)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	hash := md5.New() //nolint:gosec
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -414,8 +414,8 @@ class Branches {
         }
     }
 
-    async list(repoId, after, amount = DEFAULT_LISTING_AMOUNT) {
-        const query = qs({after, amount});
+    async list(repoId, after, amount = DEFAULT_LISTING_AMOUNT, sortBy = 'name') {
+        const query = qs({after, amount, sort_by: sortBy});
         const response = await apiRequest(`/repositories/${repoId}/branches?${query}`);
         if (response.status !== 200) {
             throw new Error(`could not list branches: ${await extractError(response)}`)